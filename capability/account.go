@@ -2,20 +2,46 @@ package capability
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/blockberries/punnet-sdk/store"
 	"github.com/blockberries/punnet-sdk/types"
 )
 
-// AccountCapability provides controlled access to account operations
-type AccountCapability interface {
+// ReadOnlyAccountCapability provides read access to account state. Grant it
+// to query servers and indexers that must never mutate state.
+type ReadOnlyAccountCapability interface {
 	// ModuleName returns the module this capability is scoped to
 	ModuleName() string
 
 	// GetAccount retrieves an account by name
 	GetAccount(ctx context.Context, name types.AccountName) (*types.Account, error)
 
+	// HasAccount checks if an account exists
+	HasAccount(ctx context.Context, name types.AccountName) (bool, error)
+
+	// VerifyAuthorization verifies that an authorization meets the account's authority threshold
+	// This enables hierarchical authorization with cycle detection
+	VerifyAuthorization(ctx context.Context, account *types.Account, auth *types.Authorization, message []byte) error
+
+	// GetNonce retrieves an account's current nonce
+	GetNonce(ctx context.Context, name types.AccountName) (uint64, error)
+
+	// IterateAccounts iterates over all accounts
+	IterateAccounts(ctx context.Context, callback func(*types.Account) error) error
+
+	// GetExtension decodes the account's extension named ext.ExtensionName()
+	// into ext. Returns types.ErrNotFound if the account has no such extension
+	// and types.ErrExtensionSchemaMismatch if it was stored with a schema
+	// version ext cannot read.
+	GetExtension(ctx context.Context, name types.AccountName, ext types.AccountExtension) error
+}
+
+// AccountCapability provides controlled access to account operations
+type AccountCapability interface {
+	ReadOnlyAccountCapability
+
 	// CreateAccount creates a new account with the given name and public key
 	// Returns error if the account already exists
 	CreateAccount(ctx context.Context, name types.AccountName, pubKey []byte) (*types.Account, error)
@@ -27,27 +53,47 @@ type AccountCapability interface {
 	// DeleteAccount removes an account
 	DeleteAccount(ctx context.Context, name types.AccountName) error
 
-	// HasAccount checks if an account exists
-	HasAccount(ctx context.Context, name types.AccountName) (bool, error)
-
-	// VerifyAuthorization verifies that an authorization meets the account's authority threshold
-	// This enables hierarchical authorization with cycle detection
-	VerifyAuthorization(ctx context.Context, account *types.Account, auth *types.Authorization, message []byte) error
-
 	// IncrementNonce increments an account's nonce (for replay protection)
 	IncrementNonce(ctx context.Context, name types.AccountName) error
 
-	// GetNonce retrieves an account's current nonce
-	GetNonce(ctx context.Context, name types.AccountName) (uint64, error)
+	// SetExtension attaches ext to an existing account, replacing any stored
+	// extension with the same name
+	SetExtension(ctx context.Context, name types.AccountName, ext types.AccountExtension) error
 
-	// IterateAccounts iterates over all accounts
-	IterateAccounts(ctx context.Context, callback func(*types.Account) error) error
+	// DeleteExtension removes an account's extension; removing a missing
+	// extension is not an error
+	DeleteExtension(ctx context.Context, name types.AccountName, extName string) error
 }
 
 // accountCapability is the implementation of AccountCapability
 type accountCapability struct {
 	moduleName string
 	store      *store.AccountStore
+
+	// extensions holds account extensions keyed by types.AccountExtensionKey
+	extensions store.BackingStore
+
+	// grant is revoked when the module is unregistered (nil: never revoked)
+	grant *moduleGrant
+}
+
+// NewAccountCapability returns an account capability of moduleName over
+// accounts, keeping account extensions in the full state store s. Unlike a
+// granted capability it shares its account cache with whoever built
+// accounts, e.g. an application that authorizes transactions against them.
+func NewAccountCapability(accounts *store.AccountStore, s store.BackingStore, moduleName string) (AccountCapability, error) {
+	if accounts == nil || s == nil {
+		return nil, ErrStoreNil
+	}
+	if moduleName == "" {
+		return nil, fmt.Errorf("module name cannot be empty")
+	}
+
+	return &accountCapability{
+		moduleName: moduleName,
+		store:      accounts,
+		extensions: AccountExtensionStore(s, moduleName),
+	}, nil
 }
 
 // ModuleName returns the module this capability is scoped to
@@ -63,6 +109,9 @@ func (ac *accountCapability) GetAccount(ctx context.Context, name types.AccountN
 	if ac == nil || ac.store == nil {
 		return nil, ErrCapabilityNil
 	}
+	if err := ac.grant.check(ac.moduleName); err != nil {
+		return nil, err
+	}
 
 	if !name.IsValid() {
 		return nil, fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
@@ -76,6 +125,9 @@ func (ac *accountCapability) CreateAccount(ctx context.Context, name types.Accou
 	if ac == nil || ac.store == nil {
 		return nil, ErrCapabilityNil
 	}
+	if err := ac.grant.check(ac.moduleName); err != nil {
+		return nil, err
+	}
 
 	if !name.IsValid() {
 		return nil, fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
@@ -114,6 +166,9 @@ func (ac *accountCapability) UpdateAccount(ctx context.Context, account *types.A
 	if ac == nil || ac.store == nil {
 		return ErrCapabilityNil
 	}
+	if err := ac.grant.check(ac.moduleName); err != nil {
+		return err
+	}
 
 	if account == nil {
 		return fmt.Errorf("account cannot be nil")
@@ -145,11 +200,18 @@ func (ac *accountCapability) DeleteAccount(ctx context.Context, name types.Accou
 	if ac == nil || ac.store == nil {
 		return ErrCapabilityNil
 	}
+	if err := ac.grant.check(ac.moduleName); err != nil {
+		return err
+	}
 
 	if !name.IsValid() {
 		return fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
 	}
 
+	if err := ac.deleteExtensions(name); err != nil {
+		return err
+	}
+
 	return ac.store.Delete(ctx, name)
 }
 
@@ -158,6 +220,9 @@ func (ac *accountCapability) HasAccount(ctx context.Context, name types.AccountN
 	if ac == nil || ac.store == nil {
 		return false, ErrCapabilityNil
 	}
+	if err := ac.grant.check(ac.moduleName); err != nil {
+		return false, err
+	}
 
 	if !name.IsValid() {
 		return false, fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
@@ -171,6 +236,9 @@ func (ac *accountCapability) VerifyAuthorization(ctx context.Context, account *t
 	if ac == nil || ac.store == nil {
 		return ErrCapabilityNil
 	}
+	if err := ac.grant.check(ac.moduleName); err != nil {
+		return err
+	}
 
 	if account == nil {
 		return fmt.Errorf("account cannot be nil")
@@ -196,6 +264,9 @@ func (ac *accountCapability) IncrementNonce(ctx context.Context, name types.Acco
 	if ac == nil || ac.store == nil {
 		return ErrCapabilityNil
 	}
+	if err := ac.grant.check(ac.moduleName); err != nil {
+		return err
+	}
 
 	if !name.IsValid() {
 		return fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
@@ -223,6 +294,9 @@ func (ac *accountCapability) GetNonce(ctx context.Context, name types.AccountNam
 	if ac == nil || ac.store == nil {
 		return 0, ErrCapabilityNil
 	}
+	if err := ac.grant.check(ac.moduleName); err != nil {
+		return 0, err
+	}
 
 	if !name.IsValid() {
 		return 0, fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
@@ -241,6 +315,9 @@ func (ac *accountCapability) IterateAccounts(ctx context.Context, callback func(
 	if ac == nil || ac.store == nil {
 		return ErrCapabilityNil
 	}
+	if err := ac.grant.check(ac.moduleName); err != nil {
+		return err
+	}
 
 	if callback == nil {
 		return fmt.Errorf("callback cannot be nil")
@@ -274,11 +351,128 @@ func (ac *accountCapability) IterateAccounts(ctx context.Context, callback func(
 	return nil
 }
 
+// SetExtension attaches ext to an existing account
+func (ac *accountCapability) SetExtension(ctx context.Context, name types.AccountName, ext types.AccountExtension) error {
+	if ac == nil || ac.store == nil || ac.extensions == nil {
+		return ErrCapabilityNil
+	}
+	if err := ac.grant.check(ac.moduleName); err != nil {
+		return err
+	}
+
+	if !name.IsValid() {
+		return fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
+	}
+
+	data, err := types.EncodeAccountExtension(ext)
+	if err != nil {
+		return err
+	}
+
+	exists, err := ac.store.Has(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to check account existence: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("%w: account %s not found", types.ErrNotFound, name)
+	}
+
+	if err := ac.extensions.Set(types.AccountExtensionKey(name, ext.ExtensionName()), data); err != nil {
+		return fmt.Errorf("failed to store extension %s of account %s: %w", ext.ExtensionName(), name, err)
+	}
+	return nil
+}
+
+// GetExtension decodes an account's extension into ext
+func (ac *accountCapability) GetExtension(ctx context.Context, name types.AccountName, ext types.AccountExtension) error {
+	if ac == nil || ac.extensions == nil {
+		return ErrCapabilityNil
+	}
+	if err := ac.grant.check(ac.moduleName); err != nil {
+		return err
+	}
+
+	if !name.IsValid() {
+		return fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
+	}
+	if ext == nil {
+		return fmt.Errorf("account extension cannot be nil")
+	}
+	if err := types.ValidateExtensionName(ext.ExtensionName()); err != nil {
+		return err
+	}
+
+	data, err := ac.extensions.Get(types.AccountExtensionKey(name, ext.ExtensionName()))
+	if errors.Is(err, store.ErrNotFound) || (err == nil && data == nil) {
+		return fmt.Errorf("%w: extension %s of account %s", types.ErrNotFound, ext.ExtensionName(), name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read extension %s of account %s: %w", ext.ExtensionName(), name, err)
+	}
+
+	return types.DecodeAccountExtension(data, ext)
+}
+
+// DeleteExtension removes an account's extension
+func (ac *accountCapability) DeleteExtension(ctx context.Context, name types.AccountName, extName string) error {
+	if ac == nil || ac.extensions == nil {
+		return ErrCapabilityNil
+	}
+	if err := ac.grant.check(ac.moduleName); err != nil {
+		return err
+	}
+
+	if !name.IsValid() {
+		return fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
+	}
+	if err := types.ValidateExtensionName(extName); err != nil {
+		return err
+	}
+
+	return ac.extensions.Delete(types.AccountExtensionKey(name, extName))
+}
+
+// deleteExtensions removes all extensions of an account
+func (ac *accountCapability) deleteExtensions(name types.AccountName) (err error) {
+	if ac.extensions == nil {
+		return nil
+	}
+
+	accountExts := store.NewPrefixStore(ac.extensions, types.AccountExtensionPrefix(name))
+	iter, iterErr := accountExts.Iterator(nil, nil)
+	if iterErr != nil {
+		return fmt.Errorf("failed to iterate extensions of account %s: %w", name, iterErr)
+	}
+
+	// Collect keys first; deleting while iterating is not supported by all stores
+	var keys [][]byte
+	for ; iter.Valid(); iter.Next() {
+		keys = append(keys, append([]byte(nil), iter.Key()...))
+	}
+	if iterErr := iter.Error(); iterErr != nil {
+		_ = iter.Close()
+		return fmt.Errorf("failed to iterate extensions of account %s: %w", name, iterErr)
+	}
+	if closeErr := iter.Close(); closeErr != nil {
+		return fmt.Errorf("failed to close iterator: %w", closeErr)
+	}
+
+	for _, key := range keys {
+		if err := accountExts.Delete(key); err != nil {
+			return fmt.Errorf("failed to delete extension of account %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
 // Flush flushes pending changes to backing store
 func (ac *accountCapability) Flush(ctx context.Context) error {
 	if ac == nil || ac.store == nil {
 		return ErrCapabilityNil
 	}
+	if err := ac.grant.check(ac.moduleName); err != nil {
+		return err
+	}
 
 	return ac.store.Flush(ctx)
 }
@@ -3,6 +3,7 @@ package capability
 import (
 	"context"
 	"crypto/ed25519"
+	"errors"
 	"sync"
 	"testing"
 
@@ -582,3 +583,168 @@ func XTestAccountCapability_ConcurrentOperations_SKIPPED(t *testing.T) {
 
 	wg.Wait()
 }
+
+// testVesting is an account extension used by the capability tests
+type testVesting struct {
+	Amount uint64 `json:"amount"`
+}
+
+func (v *testVesting) ExtensionName() string { return "vesting" }
+func (v *testVesting) SchemaVersion() uint32 { return 1 }
+
+func TestAccountCapability_Extensions(t *testing.T) {
+	cap := setupAccountCapability(t)
+	ctx := context.Background()
+
+	pubKey, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	// Extensions require an existing account
+	if err := cap.SetExtension(ctx, "alice", &testVesting{Amount: 10}); !errors.Is(err, types.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	if _, err := cap.CreateAccount(ctx, "alice", pubKey); err != nil {
+		t.Fatalf("CreateAccount failed: %v", err)
+	}
+	if err := cap.SetExtension(ctx, "alice", &testVesting{Amount: 10}); err != nil {
+		t.Fatalf("SetExtension failed: %v", err)
+	}
+
+	var got testVesting
+	if err := cap.GetExtension(ctx, "alice", &got); err != nil {
+		t.Fatalf("GetExtension failed: %v", err)
+	}
+	if got.Amount != 10 {
+		t.Fatalf("expected amount 10, got %d", got.Amount)
+	}
+
+	// Extensions are not accounts
+	if flushable, ok := cap.(interface{ Flush(context.Context) error }); ok {
+		if err := flushable.Flush(ctx); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+	count := 0
+	if err := cap.IterateAccounts(ctx, func(*types.Account) error {
+		count++
+		return nil
+	}); err != nil {
+		t.Fatalf("IterateAccounts failed: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 account, got %d", count)
+	}
+
+	if err := cap.DeleteExtension(ctx, "alice", "vesting"); err != nil {
+		t.Fatalf("DeleteExtension failed: %v", err)
+	}
+	if err := cap.GetExtension(ctx, "alice", &got); !errors.Is(err, types.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestAccountCapability_DeleteAccountRemovesExtensions(t *testing.T) {
+	cap := setupAccountCapability(t)
+	ctx := context.Background()
+
+	pubKey, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	for _, name := range []types.AccountName{"alice", "alice.bob"} {
+		if _, err := cap.CreateAccount(ctx, name, pubKey); err != nil {
+			t.Fatalf("CreateAccount failed: %v", err)
+		}
+		if err := cap.SetExtension(ctx, name, &testVesting{Amount: 1}); err != nil {
+			t.Fatalf("SetExtension failed: %v", err)
+		}
+	}
+
+	if err := cap.DeleteAccount(ctx, "alice"); err != nil {
+		t.Fatalf("DeleteAccount failed: %v", err)
+	}
+	if _, err := cap.CreateAccount(ctx, "alice", pubKey); err != nil {
+		t.Fatalf("CreateAccount failed: %v", err)
+	}
+
+	// A recreated account does not inherit the old extensions
+	if err := cap.GetExtension(ctx, "alice", &testVesting{}); !errors.Is(err, types.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	// Other accounts are untouched
+	if err := cap.GetExtension(ctx, "alice.bob", &testVesting{}); err != nil {
+		t.Fatalf("GetExtension failed: %v", err)
+	}
+}
+
+func TestAccountCapability_ExtensionsIsolatedByModule(t *testing.T) {
+	backing := store.NewMemoryStore()
+	cm := NewCapabilityManager(backing)
+	ctx := context.Background()
+
+	pubKey, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	caps := make(map[string]AccountCapability)
+	for _, module := range []string{"auth", "vesting"} {
+		if err := cm.RegisterModule(module); err != nil {
+			t.Fatalf("failed to register module: %v", err)
+		}
+		caps[module], err = cm.GrantAccountCapability(module)
+		if err != nil {
+			t.Fatalf("failed to grant account capability: %v", err)
+		}
+		if _, err := caps[module].CreateAccount(ctx, "alice", pubKey); err != nil {
+			t.Fatalf("CreateAccount failed: %v", err)
+		}
+	}
+
+	if err := caps["vesting"].SetExtension(ctx, "alice", &testVesting{Amount: 5}); err != nil {
+		t.Fatalf("SetExtension failed: %v", err)
+	}
+	if err := caps["auth"].GetExtension(ctx, "alice", &testVesting{}); !errors.Is(err, types.ErrNotFound) {
+		t.Fatalf("expected extension to be invisible to other modules, got %v", err)
+	}
+}
+
+func TestNewAccountCapability(t *testing.T) {
+	ctx := context.Background()
+	backing := store.NewMemoryStore()
+	accounts := store.NewCachedObjectStore[*types.Account](backing, store.NewJSONSerializer[*types.Account](), 10, 10)
+
+	cap, err := NewAccountCapability(store.NewAccountStoreOf(accounts), backing, "auth")
+	if err != nil {
+		t.Fatalf("NewAccountCapability failed: %v", err)
+	}
+	if _, err := cap.CreateAccount(ctx, "alice", []byte("alice-pubkey")); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	if err := cap.IncrementNonce(ctx, "alice"); err != nil {
+		t.Fatalf("failed to increment nonce: %v", err)
+	}
+
+	// The capability shares the object store's cache
+	account, err := accounts.Get(ctx, []byte("alice"))
+	if err != nil {
+		t.Fatalf("failed to get account: %v", err)
+	}
+	if account.Nonce != 1 {
+		t.Fatalf("expected nonce 1, got %d", account.Nonce)
+	}
+
+	if _, err := NewAccountCapability(nil, backing, "auth"); err == nil {
+		t.Fatal("expected error for nil account store")
+	}
+	if _, err := NewAccountCapability(store.NewAccountStoreOf(accounts), nil, "auth"); err == nil {
+		t.Fatal("expected error for nil state store")
+	}
+	if _, err := NewAccountCapability(store.NewAccountStoreOf(accounts), backing, ""); err == nil {
+		t.Fatal("expected error for empty module name")
+	}
+}
@@ -8,8 +8,9 @@ import (
 	"github.com/blockberries/punnet-sdk/types"
 )
 
-// BalanceCapability provides controlled access to balance operations
-type BalanceCapability interface {
+// ReadOnlyBalanceCapability provides read access to balance state. Grant it
+// to query servers and indexers that must never mutate state.
+type ReadOnlyBalanceCapability interface {
 	// ModuleName returns the module this capability is scoped to
 	ModuleName() string
 
@@ -17,6 +18,23 @@ type BalanceCapability interface {
 	// Returns zero balance if not found
 	GetBalance(ctx context.Context, account types.AccountName, denom string) (uint64, error)
 
+	// GetAccountBalances retrieves all balances for an account
+	GetAccountBalances(ctx context.Context, account types.AccountName) (types.Coins, error)
+
+	// HasBalance checks if a balance exists for an account and denomination
+	HasBalance(ctx context.Context, account types.AccountName, denom string) (bool, error)
+
+	// IterateBalances iterates over all balances
+	IterateBalances(ctx context.Context, callback func(store.Balance) error) error
+
+	// IterateAccountBalances iterates over all balances for a specific account
+	IterateAccountBalances(ctx context.Context, account types.AccountName, callback func(store.Balance) error) error
+}
+
+// BalanceCapability provides controlled access to balance operations
+type BalanceCapability interface {
+	ReadOnlyBalanceCapability
+
 	// SetBalance sets a balance for an account and denomination
 	SetBalance(ctx context.Context, account types.AccountName, denom string, amount uint64) error
 
@@ -31,24 +49,33 @@ type BalanceCapability interface {
 	// Transfer transfers tokens from one account to another
 	// This operation is atomic with automatic rollback on error
 	Transfer(ctx context.Context, from, to types.AccountName, denom string, amount uint64) error
-
-	// GetAccountBalances retrieves all balances for an account
-	GetAccountBalances(ctx context.Context, account types.AccountName) (types.Coins, error)
-
-	// HasBalance checks if a balance exists for an account and denomination
-	HasBalance(ctx context.Context, account types.AccountName, denom string) (bool, error)
-
-	// IterateBalances iterates over all balances
-	IterateBalances(ctx context.Context, callback func(store.Balance) error) error
-
-	// IterateAccountBalances iterates over all balances for a specific account
-	IterateAccountBalances(ctx context.Context, account types.AccountName, callback func(store.Balance) error) error
 }
 
 // balanceCapability is the implementation of BalanceCapability
 type balanceCapability struct {
 	moduleName string
 	store      *store.BalanceStore
+
+	// grant is revoked when the module is unregistered (nil: never revoked)
+	grant *moduleGrant
+}
+
+// NewBalanceCapability returns a balance capability of moduleName over
+// balances. Unlike a granted capability it shares its balance cache with
+// whoever built balances, e.g. an application whose transfer effects move
+// them.
+func NewBalanceCapability(balances *store.BalanceStore, moduleName string) (BalanceCapability, error) {
+	if balances == nil {
+		return nil, ErrStoreNil
+	}
+	if moduleName == "" {
+		return nil, fmt.Errorf("module name cannot be empty")
+	}
+
+	return &balanceCapability{
+		moduleName: moduleName,
+		store:      balances,
+	}, nil
 }
 
 // ModuleName returns the module this capability is scoped to
@@ -64,6 +91,9 @@ func (bc *balanceCapability) GetBalance(ctx context.Context, account types.Accou
 	if bc == nil || bc.store == nil {
 		return 0, ErrCapabilityNil
 	}
+	if err := bc.grant.check(bc.moduleName); err != nil {
+		return 0, err
+	}
 
 	if !account.IsValid() {
 		return 0, fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
@@ -86,6 +116,9 @@ func (bc *balanceCapability) SetBalance(ctx context.Context, account types.Accou
 	if bc == nil || bc.store == nil {
 		return ErrCapabilityNil
 	}
+	if err := bc.grant.check(bc.moduleName); err != nil {
+		return err
+	}
 
 	if !account.IsValid() {
 		return fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
@@ -108,6 +141,9 @@ func (bc *balanceCapability) AddBalance(ctx context.Context, account types.Accou
 	if bc == nil || bc.store == nil {
 		return ErrCapabilityNil
 	}
+	if err := bc.grant.check(bc.moduleName); err != nil {
+		return err
+	}
 
 	if !account.IsValid() {
 		return fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
@@ -133,6 +169,9 @@ func (bc *balanceCapability) SubBalance(ctx context.Context, account types.Accou
 	if bc == nil || bc.store == nil {
 		return ErrCapabilityNil
 	}
+	if err := bc.grant.check(bc.moduleName); err != nil {
+		return err
+	}
 
 	if !account.IsValid() {
 		return fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
@@ -158,6 +197,9 @@ func (bc *balanceCapability) Transfer(ctx context.Context, from, to types.Accoun
 	if bc == nil || bc.store == nil {
 		return ErrCapabilityNil
 	}
+	if err := bc.grant.check(bc.moduleName); err != nil {
+		return err
+	}
 
 	if !from.IsValid() {
 		return fmt.Errorf("%w: invalid sender account name", types.ErrInvalidAccount)
@@ -191,6 +233,9 @@ func (bc *balanceCapability) GetAccountBalances(ctx context.Context, account typ
 	if bc == nil || bc.store == nil {
 		return nil, ErrCapabilityNil
 	}
+	if err := bc.grant.check(bc.moduleName); err != nil {
+		return nil, err
+	}
 
 	if !account.IsValid() {
 		return nil, fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
@@ -212,6 +257,9 @@ func (bc *balanceCapability) HasBalance(ctx context.Context, account types.Accou
 	if bc == nil || bc.store == nil {
 		return false, ErrCapabilityNil
 	}
+	if err := bc.grant.check(bc.moduleName); err != nil {
+		return false, err
+	}
 
 	if !account.IsValid() {
 		return false, fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
@@ -229,6 +277,9 @@ func (bc *balanceCapability) IterateBalances(ctx context.Context, callback func(
 	if bc == nil || bc.store == nil {
 		return ErrCapabilityNil
 	}
+	if err := bc.grant.check(bc.moduleName); err != nil {
+		return err
+	}
 
 	if callback == nil {
 		return fmt.Errorf("callback cannot be nil")
@@ -267,6 +318,9 @@ func (bc *balanceCapability) IterateAccountBalances(ctx context.Context, account
 	if bc == nil || bc.store == nil {
 		return ErrCapabilityNil
 	}
+	if err := bc.grant.check(bc.moduleName); err != nil {
+		return err
+	}
 
 	if !account.IsValid() {
 		return fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
@@ -309,6 +363,9 @@ func (bc *balanceCapability) Flush(ctx context.Context) error {
 	if bc == nil || bc.store == nil {
 		return ErrCapabilityNil
 	}
+	if err := bc.grant.check(bc.moduleName); err != nil {
+		return err
+	}
 
 	return bc.store.Flush(ctx)
 }
@@ -688,3 +688,33 @@ func XTestBalanceCapability_ConcurrentOperations_SKIPPED(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestNewBalanceCapability(t *testing.T) {
+	ctx := context.Background()
+	balances := store.NewBalanceStore(store.NewMemoryStore())
+
+	cap, err := NewBalanceCapability(balances, "bank")
+	if err != nil {
+		t.Fatalf("NewBalanceCapability failed: %v", err)
+	}
+	if err := cap.AddBalance(ctx, "alice", "uatom", 100); err != nil {
+		t.Fatalf("failed to add balance: %v", err)
+	}
+
+	// The capability shares the store's cache, so writes are visible
+	// before any flush
+	balance, err := balances.Get(ctx, "alice", "uatom")
+	if err != nil {
+		t.Fatalf("failed to get balance: %v", err)
+	}
+	if balance.Amount != 100 {
+		t.Fatalf("expected balance 100, got %d", balance.Amount)
+	}
+
+	if _, err := NewBalanceCapability(nil, "bank"); err == nil {
+		t.Fatal("expected error for nil store")
+	}
+	if _, err := NewBalanceCapability(balances, ""); err == nil {
+		t.Fatal("expected error for empty module name")
+	}
+}
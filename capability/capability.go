@@ -1,10 +1,13 @@
 package capability
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/blockberries/punnet-sdk/store"
 )
@@ -21,6 +24,21 @@ var (
 
 	// ErrStoreNil is returned when a store is nil
 	ErrStoreNil = errors.New("store is nil")
+
+	// ErrDuplicateIndex is returned when a module registers an index name twice
+	ErrDuplicateIndex = errors.New("index already registered")
+
+	// ErrIndexAfterGrant is returned when registering an index for a module
+	// that was already granted a capability
+	ErrIndexAfterGrant = errors.New("index registered after capability grant")
+
+	// ErrCapabilityRevoked is returned by the capabilities of a module that
+	// was unregistered after they were granted
+	ErrCapabilityRevoked = errors.New("capability revoked")
+
+	// ErrModuleSealed is returned when registering a module whose state
+	// namespace was sealed by UnregisterModule
+	ErrModuleSealed = errors.New("module namespace sealed")
 )
 
 // Capability represents controlled access to state operations
@@ -50,7 +68,27 @@ type Capability[T any] interface {
 type CapabilityManager struct {
 	mu      sync.RWMutex
 	modules map[string]bool // tracks registered modules
+	granted map[string]bool // tracks modules granted a capability
+	indexes map[string][]*store.Index
+	grants  map[string]*moduleGrant // revocation token of each registered module
+	sealed  map[string]bool         // tracks unregistered modules
 	backing store.BackingStore
+	usage   *UsageTracker // counts capability writes (may be nil)
+}
+
+// moduleGrant is shared by every capability granted to one registration of
+// a module; unregistering the module revokes it
+type moduleGrant struct {
+	revoked atomic.Bool
+}
+
+// check returns ErrCapabilityRevoked once the grant is revoked. A nil grant
+// (a capability built outside a manager) is never revoked.
+func (g *moduleGrant) check(moduleName string) error {
+	if g != nil && g.revoked.Load() {
+		return fmt.Errorf("%w: module %s", ErrCapabilityRevoked, moduleName)
+	}
+	return nil
 }
 
 // NewCapabilityManager creates a new capability manager
@@ -61,6 +99,10 @@ func NewCapabilityManager(backing store.BackingStore) *CapabilityManager {
 
 	return &CapabilityManager{
 		modules: make(map[string]bool),
+		granted: make(map[string]bool),
+		indexes: make(map[string][]*store.Index),
+		grants:  make(map[string]*moduleGrant),
+		sealed:  make(map[string]bool),
 		backing: backing,
 	}
 }
@@ -82,11 +124,101 @@ func (cm *CapabilityManager) RegisterModule(moduleName string) error {
 	if cm.modules[moduleName] {
 		return fmt.Errorf("%w: %s", ErrDuplicateModule, moduleName)
 	}
+	if cm.sealed[moduleName] {
+		return fmt.Errorf("%w: %s (use ReloadModule)", ErrModuleSealed, moduleName)
+	}
 
+	cm.registerLocked(moduleName)
+	return nil
+}
+
+// SetUsageTracker counts the writes of capabilities granted from now on, and
+// of indexes registered from now on, with tracker (see UsageTracker)
+//
+// PRECONDITION: Called before any capability is granted or index registered,
+// so no write escapes the tracker.
+func (cm *CapabilityManager) SetUsageTracker(tracker *UsageTracker) {
+	if cm == nil {
+		return
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.usage = tracker
+}
+
+// registerLocked registers a module with a fresh grant.
+// PRECONDITION: cm.mu is held for writing.
+func (cm *CapabilityManager) registerLocked(moduleName string) {
 	cm.modules[moduleName] = true
+	cm.grants[moduleName] = &moduleGrant{}
+}
+
+// UnregisterModule unloads a module: every capability granted to it
+// (including read-only ones) fails with ErrCapabilityRevoked from then on,
+// its indexes are dropped, and its state namespace is sealed. Its state is
+// kept, but RegisterModule refuses the name so an unrelated module cannot
+// inherit it; ReloadModule re-registers it explicitly.
+//
+// PRECONDITION: The module's capabilities are not in use by a block being
+// executed; unload between blocks so no block sees a partial module.
+func (cm *CapabilityManager) UnregisterModule(moduleName string) error {
+	if cm == nil {
+		return ErrCapabilityNil
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if !cm.modules[moduleName] {
+		return fmt.Errorf("%w: %s", ErrModuleNotFound, moduleName)
+	}
+
+	cm.grants[moduleName].revoked.Store(true)
+	delete(cm.grants, moduleName)
+	delete(cm.modules, moduleName)
+	delete(cm.granted, moduleName)
+	delete(cm.indexes, moduleName)
+	cm.sealed[moduleName] = true
+	return nil
+}
+
+// ReloadModule re-registers a module unregistered by UnregisterModule,
+// resuming its sealed state. Capabilities granted before the unload stay
+// revoked; grant new ones. Indexes must be registered again, and rebuilt
+// (see RebuildIndexes) if their definitions changed.
+func (cm *CapabilityManager) ReloadModule(moduleName string) error {
+	if cm == nil {
+		return ErrCapabilityNil
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if cm.modules[moduleName] {
+		return fmt.Errorf("%w: %s", ErrDuplicateModule, moduleName)
+	}
+	if !cm.sealed[moduleName] {
+		return fmt.Errorf("%w: %s was never unregistered", ErrModuleNotFound, moduleName)
+	}
+
+	delete(cm.sealed, moduleName)
+	cm.registerLocked(moduleName)
 	return nil
 }
 
+// IsModuleSealed checks if a module was unregistered and not reloaded
+func (cm *CapabilityManager) IsModuleSealed(moduleName string) bool {
+	if cm == nil {
+		return false
+	}
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	return cm.sealed[moduleName]
+}
+
 // IsModuleRegistered checks if a module is registered
 func (cm *CapabilityManager) IsModuleRegistered(moduleName string) bool {
 	if cm == nil {
@@ -100,19 +232,190 @@ func (cm *CapabilityManager) IsModuleRegistered(moduleName string) bool {
 }
 
 // createPrefixedStore creates a store with a module-specific prefix
-// This provides namespace isolation for modules
-func (cm *CapabilityManager) createPrefixedStore(moduleName string) (store.BackingStore, error) {
+// This provides namespace isolation for modules. If the module registered
+// indexes, writes through the store keep them in sync. It also returns the
+// module's grant, which the capability checks on every call.
+func (cm *CapabilityManager) createPrefixedStore(moduleName string) (store.BackingStore, *moduleGrant, error) {
+	if cm == nil {
+		return nil, nil, ErrCapabilityNil
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if !cm.modules[moduleName] {
+		return nil, nil, fmt.Errorf("%w: %s", ErrModuleNotFound, moduleName)
+	}
+	cm.granted[moduleName] = true
+	grant := cm.grants[moduleName]
+
+	prefixed := ModuleStore(NewTrackedStore(cm.backing, cm.usage), moduleName)
+	if len(cm.indexes[moduleName]) == 0 {
+		return prefixed, grant, nil
+	}
+	indexed, err := store.NewIndexedStore(prefixed, cm.indexes[moduleName]...)
+	return indexed, grant, err
+}
+
+// moduleGrantOf returns the grant of a registered module
+func (cm *CapabilityManager) moduleGrantOf(moduleName string) (*moduleGrant, error) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	if !cm.modules[moduleName] {
+		return nil, fmt.Errorf("%w: %s", ErrModuleNotFound, moduleName)
+	}
+	return cm.grants[moduleName], nil
+}
+
+// ModuleStore returns the view of s that capabilities granted to moduleName
+// use (keys prefixed with "module/<moduleName>/"). Use it to read a module's
+// state from another view of the same store, e.g. a historical version.
+func ModuleStore(s store.BackingStore, moduleName string) store.BackingStore {
+	return store.NewPrefixStore(s, []byte(fmt.Sprintf("module/%s/", moduleName)))
+}
+
+// AccountExtensionStore returns the view of s holding the account extensions
+// of moduleName (keys prefixed with "account_ext/<moduleName>/"). Extensions
+// live outside ModuleStore so iterating a module's accounts never sees them.
+func AccountExtensionStore(s store.BackingStore, moduleName string) store.BackingStore {
+	return store.NewPrefixStore(s, []byte(fmt.Sprintf("account_ext/%s/", moduleName)))
+}
+
+// IndexStore returns the view of s holding the entries of a module's index
+// (keys prefixed with "index/<moduleName>/<indexName>/"). Indexes live
+// outside ModuleStore so iterating a module's state never sees them.
+func IndexStore(s store.BackingStore, moduleName, indexName string) store.BackingStore {
+	return store.NewPrefixStore(s, []byte(fmt.Sprintf("index/%s/%s/", moduleName, indexName)))
+}
+
+// RegisterIndex registers a secondary index of a module's state, derived by
+// fn from each entry of ModuleStore. Every capability later granted to the
+// module keeps the index in sync with its writes. The returned index serves
+// lookups.
+//
+// PRECONDITION: Called before any capability is granted to the module, so no
+// write escapes the index. Existing state is not indexed; call RebuildIndexes
+// when adding an index to a chain with state.
+//
+// SECURITY: State effects reach the index through IndexedStoreOf. Other
+// writes that bypass the module's capabilities do not update the index;
+// CheckIndexes detects them.
+func (cm *CapabilityManager) RegisterIndex(moduleName, indexName string, fn store.IndexFunc) (*store.Index, error) {
+	if cm == nil {
+		return nil, ErrCapabilityNil
+	}
+
+	// '/' would let one index's key space contain another's
+	if indexName == "" || strings.Contains(indexName, "/") {
+		return nil, fmt.Errorf("invalid index name %q", indexName)
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if !cm.modules[moduleName] {
+		return nil, fmt.Errorf("%w: %s", ErrModuleNotFound, moduleName)
+	}
+	if cm.granted[moduleName] {
+		return nil, fmt.Errorf("%w: %s", ErrIndexAfterGrant, moduleName)
+	}
+	for _, idx := range cm.indexes[moduleName] {
+		if idx.Name() == indexName {
+			return nil, fmt.Errorf("%w: %s/%s", ErrDuplicateIndex, moduleName, indexName)
+		}
+	}
+
+	idx, err := store.NewIndex(indexName, IndexStore(NewTrackedStore(cm.backing, cm.usage), moduleName, indexName), fn)
+	if err != nil {
+		return nil, err
+	}
+	cm.indexes[moduleName] = append(cm.indexes[moduleName], idx)
+	return idx, nil
+}
+
+// IndexedStoreOf returns the store through which a write to key of the full
+// state store keeps the indexes of the module owning it in sync, and key
+// within that store. It returns a nil store if key is outside a module's
+// ModuleStore or the module registered no indexes; write key directly then.
+//
+// The effect executor writes through it, so a StateWriteEffect into an
+// indexed module's state updates its indexes like a capability write.
+// The returned store does not record usage of the primary write; the caller
+// records it as for any other effect write.
+func (cm *CapabilityManager) IndexedStoreOf(key []byte) (store.BackingStore, []byte, error) {
+	if cm == nil {
+		return nil, nil, ErrCapabilityNil
+	}
+
+	rest, ok := bytes.CutPrefix(key, []byte("module/"))
+	if !ok {
+		return nil, nil, nil
+	}
+	end := bytes.IndexByte(rest, '/')
+	if end <= 0 {
+		return nil, nil, nil
+	}
+	moduleName := string(rest[:end])
+
+	cm.mu.RLock()
+	indexes := cm.indexes[moduleName]
+	cm.mu.RUnlock()
+	if len(indexes) == 0 {
+		return nil, nil, nil
+	}
+
+	indexed, err := store.NewIndexedStore(ModuleStore(cm.backing, moduleName), indexes...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return indexed, rest[end+1:], nil
+}
+
+// moduleIndexes returns the indexes registered for a module
+func (cm *CapabilityManager) moduleIndexes(moduleName string) ([]*store.Index, error) {
 	if cm == nil {
 		return nil, ErrCapabilityNil
 	}
 
-	if !cm.IsModuleRegistered(moduleName) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	if !cm.modules[moduleName] {
 		return nil, fmt.Errorf("%w: %s", ErrModuleNotFound, moduleName)
 	}
+	return append([]*store.Index(nil), cm.indexes[moduleName]...), nil
+}
+
+// RebuildIndexes re-derives every index of a module from its state
+func (cm *CapabilityManager) RebuildIndexes(moduleName string) error {
+	indexes, err := cm.moduleIndexes(moduleName)
+	if err != nil {
+		return err
+	}
 
-	// Create prefix: "module/<moduleName>/"
-	prefix := []byte(fmt.Sprintf("module/%s/", moduleName))
-	return store.NewPrefixStore(cm.backing, prefix), nil
+	for _, idx := range indexes {
+		if err := store.RebuildIndex(ModuleStore(cm.backing, moduleName), idx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckIndexes verifies every index of a module against its state, returning
+// store.ErrIndexInconsistent for the first index that does not match
+func (cm *CapabilityManager) CheckIndexes(moduleName string) error {
+	indexes, err := cm.moduleIndexes(moduleName)
+	if err != nil {
+		return err
+	}
+
+	for _, idx := range indexes {
+		if err := store.CheckIndex(ModuleStore(cm.backing, moduleName), idx); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // GrantAccountCapability grants account access capability to a module
@@ -121,7 +424,7 @@ func (cm *CapabilityManager) GrantAccountCapability(moduleName string) (AccountC
 		return nil, ErrCapabilityNil
 	}
 
-	prefixedStore, err := cm.createPrefixedStore(moduleName)
+	prefixedStore, grant, err := cm.createPrefixedStore(moduleName)
 	if err != nil {
 		return nil, err
 	}
@@ -129,9 +432,15 @@ func (cm *CapabilityManager) GrantAccountCapability(moduleName string) (AccountC
 	// Create account store with the prefixed backing store
 	accountStore := store.NewAccountStore(prefixedStore)
 
+	cm.mu.RLock()
+	extensions := AccountExtensionStore(NewTrackedStore(cm.backing, cm.usage), moduleName)
+	cm.mu.RUnlock()
+
 	return &accountCapability{
 		moduleName: moduleName,
 		store:      accountStore,
+		extensions: extensions,
+		grant:      grant,
 	}, nil
 }
 
@@ -141,7 +450,7 @@ func (cm *CapabilityManager) GrantBalanceCapability(moduleName string) (BalanceC
 		return nil, ErrCapabilityNil
 	}
 
-	prefixedStore, err := cm.createPrefixedStore(moduleName)
+	prefixedStore, grant, err := cm.createPrefixedStore(moduleName)
 	if err != nil {
 		return nil, err
 	}
@@ -152,6 +461,7 @@ func (cm *CapabilityManager) GrantBalanceCapability(moduleName string) (BalanceC
 	return &balanceCapability{
 		moduleName: moduleName,
 		store:      balanceStore,
+		grant:      grant,
 	}, nil
 }
 
@@ -161,7 +471,7 @@ func (cm *CapabilityManager) GrantValidatorCapability(moduleName string) (Valida
 		return nil, ErrCapabilityNil
 	}
 
-	prefixedStore, err := cm.createPrefixedStore(moduleName)
+	prefixedStore, grant, err := cm.createPrefixedStore(moduleName)
 	if err != nil {
 		return nil, err
 	}
@@ -174,9 +484,39 @@ func (cm *CapabilityManager) GrantValidatorCapability(moduleName string) (Valida
 		moduleName:      moduleName,
 		validatorStore:  validatorStore,
 		delegationStore: delegationStore,
+		grant:           grant,
 	}, nil
 }
 
+// GrantReadOnlyAccountCapability grants read-only account access to a
+// module's state, for query servers and indexers. Unlike write grants, it
+// does not prevent the module from registering indexes later.
+func (cm *CapabilityManager) GrantReadOnlyAccountCapability(moduleName string) (ReadOnlyAccountCapability, error) {
+	if cm == nil {
+		return nil, ErrCapabilityNil
+	}
+	grant, err := cm.moduleGrantOf(moduleName)
+	if err != nil {
+		return nil, err
+	}
+
+	return newReadOnlyAccountCapability(cm.backing, moduleName, grant), nil
+}
+
+// GrantReadOnlyBalanceCapability grants read-only balance access to a
+// module's state, for query servers and indexers
+func (cm *CapabilityManager) GrantReadOnlyBalanceCapability(moduleName string) (ReadOnlyBalanceCapability, error) {
+	if cm == nil {
+		return nil, ErrCapabilityNil
+	}
+	grant, err := cm.moduleGrantOf(moduleName)
+	if err != nil {
+		return nil, err
+	}
+
+	return newReadOnlyBalanceCapability(cm.backing, moduleName, grant), nil
+}
+
 // Flush flushes all pending changes to the underlying storage
 func (cm *CapabilityManager) Flush(ctx context.Context) error {
 	if cm == nil {
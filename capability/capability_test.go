@@ -403,3 +403,300 @@ func TestConcurrentGrantCapabilities(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestRegisterIndex_BalancesByDenom(t *testing.T) {
+	backing := store.NewMemoryStore()
+	cm := NewCapabilityManager(backing)
+	ctx := context.Background()
+
+	if err := cm.RegisterModule("bank"); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	byDenom, err := cm.RegisterIndex("bank", "by_denom", store.BalanceDenomIndex)
+	if err != nil {
+		t.Fatalf("failed to register index: %v", err)
+	}
+
+	cap, err := cm.GrantBalanceCapability("bank")
+	if err != nil {
+		t.Fatalf("failed to grant balance capability: %v", err)
+	}
+	for _, b := range []store.Balance{{Account: "alice", Denom: "uatom", Amount: 1}, {Account: "bob", Denom: "uatom", Amount: 2}, {Account: "bob", Denom: "uosmo", Amount: 3}} {
+		if err := cap.SetBalance(ctx, b.Account, b.Denom, b.Amount); err != nil {
+			t.Fatalf("failed to set balance: %v", err)
+		}
+	}
+	if flushable, ok := cap.(interface{ Flush(context.Context) error }); ok {
+		if err := flushable.Flush(ctx); err != nil {
+			t.Fatalf("failed to flush: %v", err)
+		}
+	}
+
+	holders, err := byDenom.Lookup([]byte("uatom"))
+	if err != nil {
+		t.Fatalf("failed to look up index: %v", err)
+	}
+	if len(holders) != 2 || string(holders[0]) != "alice/uatom" || string(holders[1]) != "bob/uatom" {
+		t.Fatalf("uatom holders = %q, want [alice/uatom bob/uatom]", holders)
+	}
+	if err := cm.CheckIndexes("bank"); err != nil {
+		t.Fatalf("index inconsistent: %v", err)
+	}
+
+	// Index entries are outside the module namespace
+	count := 0
+	if err := cap.IterateBalances(ctx, func(store.Balance) error {
+		count++
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to iterate balances: %v", err)
+	}
+	if count != 3 {
+		t.Fatalf("iterated %d balances, want 3", count)
+	}
+
+	// A write bypassing the capability is detected and repaired
+	if err := ModuleStore(backing, "bank").Set(store.BalanceKey("carol", "uatom"), []byte(`{"account":"carol","denom":"uatom","amount":4}`)); err != nil {
+		t.Fatalf("failed to write balance: %v", err)
+	}
+	if err := cm.CheckIndexes("bank"); !errors.Is(err, store.ErrIndexInconsistent) {
+		t.Fatalf("CheckIndexes error = %v, want ErrIndexInconsistent", err)
+	}
+	if err := cm.RebuildIndexes("bank"); err != nil {
+		t.Fatalf("failed to rebuild indexes: %v", err)
+	}
+	if err := cm.CheckIndexes("bank"); err != nil {
+		t.Fatalf("index inconsistent after rebuild: %v", err)
+	}
+}
+
+func TestIndexedStoreOf(t *testing.T) {
+	backing := store.NewMemoryStore()
+	cm := NewCapabilityManager(backing)
+
+	for _, module := range []string{"bank", "staking"} {
+		if err := cm.RegisterModule(module); err != nil {
+			t.Fatalf("failed to register module: %v", err)
+		}
+	}
+	byDenom, err := cm.RegisterIndex("bank", "by_denom", store.BalanceDenomIndex)
+	if err != nil {
+		t.Fatalf("failed to register index: %v", err)
+	}
+
+	// Keys outside an indexed module's state are written directly
+	for _, key := range []string{"module/staking/key", "account_ext/bank/key", "index/bank/by_denom/key", "module/bank", "other"} {
+		indexed, _, err := cm.IndexedStoreOf([]byte(key))
+		if err != nil {
+			t.Fatalf("IndexedStoreOf(%q) failed: %v", key, err)
+		}
+		if indexed != nil {
+			t.Fatalf("IndexedStoreOf(%q) returned a store, want nil", key)
+		}
+	}
+
+	key := append([]byte("module/bank/"), store.BalanceKey("carol", "uatom")...)
+	indexed, moduleKey, err := cm.IndexedStoreOf(key)
+	if err != nil {
+		t.Fatalf("IndexedStoreOf failed: %v", err)
+	}
+	if indexed == nil || string(moduleKey) != string(store.BalanceKey("carol", "uatom")) {
+		t.Fatalf("IndexedStoreOf = (%v, %q), want the bank store and the balance key", indexed, moduleKey)
+	}
+	if err := indexed.Set(moduleKey, []byte(`{"account":"carol","denom":"uatom","amount":4}`)); err != nil {
+		t.Fatalf("failed to write balance: %v", err)
+	}
+
+	holders, err := byDenom.Lookup([]byte("uatom"))
+	if err != nil {
+		t.Fatalf("failed to look up index: %v", err)
+	}
+	if len(holders) != 1 || string(holders[0]) != "carol/uatom" {
+		t.Fatalf("uatom holders = %q, want [carol/uatom]", holders)
+	}
+	if err := cm.CheckIndexes("bank"); err != nil {
+		t.Fatalf("index inconsistent: %v", err)
+	}
+
+	if err := indexed.Delete(moduleKey); err != nil {
+		t.Fatalf("failed to delete balance: %v", err)
+	}
+	if err := cm.CheckIndexes("bank"); err != nil {
+		t.Fatalf("index inconsistent after delete: %v", err)
+	}
+}
+
+func TestRegisterIndex_Errors(t *testing.T) {
+	backing := store.NewMemoryStore()
+	cm := NewCapabilityManager(backing)
+
+	if _, err := cm.RegisterIndex("bank", "by_denom", store.BalanceDenomIndex); !errors.Is(err, ErrModuleNotFound) {
+		t.Fatalf("expected ErrModuleNotFound, got %v", err)
+	}
+
+	if err := cm.RegisterModule("bank"); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	for _, name := range []string{"", "by/denom"} {
+		if _, err := cm.RegisterIndex("bank", name, store.BalanceDenomIndex); err == nil {
+			t.Fatalf("expected error with index name %q", name)
+		}
+	}
+	if _, err := cm.RegisterIndex("bank", "by_denom", store.BalanceDenomIndex); err != nil {
+		t.Fatalf("failed to register index: %v", err)
+	}
+	if _, err := cm.RegisterIndex("bank", "by_denom", store.BalanceDenomIndex); !errors.Is(err, ErrDuplicateIndex) {
+		t.Fatalf("expected ErrDuplicateIndex, got %v", err)
+	}
+
+	if _, err := cm.GrantBalanceCapability("bank"); err != nil {
+		t.Fatalf("failed to grant balance capability: %v", err)
+	}
+	if _, err := cm.RegisterIndex("bank", "late", store.BalanceDenomIndex); !errors.Is(err, ErrIndexAfterGrant) {
+		t.Fatalf("expected ErrIndexAfterGrant, got %v", err)
+	}
+}
+
+func TestUnregisterModule_RevokesCapabilities(t *testing.T) {
+	backing := store.NewMemoryStore()
+	cm := NewCapabilityManager(backing)
+	ctx := context.Background()
+
+	if err := cm.RegisterModule("bank"); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	balances, err := cm.GrantBalanceCapability("bank")
+	if err != nil {
+		t.Fatalf("failed to grant balance capability: %v", err)
+	}
+	accounts, err := cm.GrantAccountCapability("bank")
+	if err != nil {
+		t.Fatalf("failed to grant account capability: %v", err)
+	}
+	validators, err := cm.GrantValidatorCapability("bank")
+	if err != nil {
+		t.Fatalf("failed to grant validator capability: %v", err)
+	}
+	readOnly, err := cm.GrantReadOnlyBalanceCapability("bank")
+	if err != nil {
+		t.Fatalf("failed to grant read-only balance capability: %v", err)
+	}
+
+	if err := balances.SetBalance(ctx, "alice", "stake", 100); err != nil {
+		t.Fatalf("failed to set balance: %v", err)
+	}
+	flush(t, balances)
+	// Cached but unflushed writes must not reach state after the unload
+	if err := balances.SetBalance(ctx, "alice", "stake", 999); err != nil {
+		t.Fatalf("failed to set balance: %v", err)
+	}
+
+	if err := cm.UnregisterModule("bank"); err != nil {
+		t.Fatalf("failed to unregister module: %v", err)
+	}
+	if cm.IsModuleRegistered("bank") || !cm.IsModuleSealed("bank") {
+		t.Fatal("expected bank to be unregistered and sealed")
+	}
+
+	checks := map[string]error{
+		"GetBalance":    func() error { _, err := balances.GetBalance(ctx, "alice", "stake"); return err }(),
+		"Flush":         balances.(interface{ Flush(context.Context) error }).Flush(ctx),
+		"GetAccount":    func() error { _, err := accounts.GetAccount(ctx, "alice"); return err }(),
+		"GetValidator":  func() error { _, err := validators.GetValidator(ctx, []byte("pk")); return err }(),
+		"ReadOnly":      func() error { _, err := readOnly.GetBalance(ctx, "alice", "stake"); return err }(),
+		"GrantBalance":  func() error { _, err := cm.GrantBalanceCapability("bank"); return err }(),
+		"GrantReadOnly": func() error { _, err := cm.GrantReadOnlyAccountCapability("bank"); return err }(),
+	}
+	for name, err := range checks {
+		want := ErrCapabilityRevoked
+		if name == "GrantBalance" || name == "GrantReadOnly" {
+			want = ErrModuleNotFound
+		}
+		if !errors.Is(err, want) {
+			t.Errorf("%s: expected %v, got %v", name, want, err)
+		}
+	}
+
+	// Sealed state is kept as of the last flush
+	reader, err := NewReadOnlyBalanceCapability(backing, "bank")
+	if err != nil {
+		t.Fatalf("failed to create read-only capability: %v", err)
+	}
+	amount, err := reader.GetBalance(ctx, "alice", "stake")
+	if err != nil {
+		t.Fatalf("failed to get balance: %v", err)
+	}
+	if amount != 100 {
+		t.Fatalf("expected sealed balance 100, got %d", amount)
+	}
+}
+
+func TestReloadModule(t *testing.T) {
+	cm := NewCapabilityManager(store.NewMemoryStore())
+	ctx := context.Background()
+
+	if err := cm.RegisterModule("feature"); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	old, err := cm.GrantBalanceCapability("feature")
+	if err != nil {
+		t.Fatalf("failed to grant balance capability: %v", err)
+	}
+	if err := old.SetBalance(ctx, "alice", "stake", 7); err != nil {
+		t.Fatalf("failed to set balance: %v", err)
+	}
+	flush(t, old)
+
+	if err := cm.UnregisterModule("feature"); err != nil {
+		t.Fatalf("failed to unregister module: %v", err)
+	}
+	if err := cm.RegisterModule("feature"); !errors.Is(err, ErrModuleSealed) {
+		t.Fatalf("expected ErrModuleSealed, got %v", err)
+	}
+	if err := cm.ReloadModule("feature"); err != nil {
+		t.Fatalf("failed to reload module: %v", err)
+	}
+	if err := cm.ReloadModule("feature"); !errors.Is(err, ErrDuplicateModule) {
+		t.Fatalf("expected ErrDuplicateModule, got %v", err)
+	}
+
+	// Indexes can be registered again before the first new grant
+	if _, err := cm.RegisterIndex("feature", "by_denom", store.BalanceDenomIndex); err != nil {
+		t.Fatalf("failed to register index after reload: %v", err)
+	}
+
+	reloaded, err := cm.GrantBalanceCapability("feature")
+	if err != nil {
+		t.Fatalf("failed to grant balance capability: %v", err)
+	}
+	amount, err := reloaded.GetBalance(ctx, "alice", "stake")
+	if err != nil {
+		t.Fatalf("failed to get balance: %v", err)
+	}
+	if amount != 7 {
+		t.Fatalf("expected resumed balance 7, got %d", amount)
+	}
+
+	// Capabilities from before the unload stay revoked
+	if _, err := old.GetBalance(ctx, "alice", "stake"); !errors.Is(err, ErrCapabilityRevoked) {
+		t.Fatalf("expected ErrCapabilityRevoked, got %v", err)
+	}
+}
+
+func TestUnregisterModule_Errors(t *testing.T) {
+	cm := NewCapabilityManager(store.NewMemoryStore())
+	if err := cm.UnregisterModule("missing"); !errors.Is(err, ErrModuleNotFound) {
+		t.Fatalf("expected ErrModuleNotFound, got %v", err)
+	}
+	if err := cm.ReloadModule("missing"); !errors.Is(err, ErrModuleNotFound) {
+		t.Fatalf("expected ErrModuleNotFound, got %v", err)
+	}
+
+	var nilManager *CapabilityManager
+	if err := nilManager.UnregisterModule("bank"); !errors.Is(err, ErrCapabilityNil) {
+		t.Fatalf("expected ErrCapabilityNil, got %v", err)
+	}
+	if nilManager.IsModuleSealed("bank") {
+		t.Fatal("expected false for nil manager")
+	}
+}
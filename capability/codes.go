@@ -0,0 +1,11 @@
+package capability
+
+import sdkerrors "github.com/blockberries/punnet-sdk/errors"
+
+// CodespaceCapability is the result codespace of capability errors
+const CodespaceCapability = "capability"
+
+// Capability error codes.
+func init() {
+	sdkerrors.MustRegister(CodespaceCapability, 2, ErrStorageLimitExceeded)
+}
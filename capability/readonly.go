@@ -0,0 +1,177 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// readOnlyAccountCapability is the implementation of ReadOnlyAccountCapability.
+//
+// SECURITY: It exposes no write methods and reads through store.ReadOnlyStore
+// views, so neither a type assertion nor a bug in the account store can
+// mutate state through it.
+type readOnlyAccountCapability struct {
+	moduleName string
+	store      store.BackingStore
+	extensions store.BackingStore
+	grant      *moduleGrant
+}
+
+// NewReadOnlyAccountCapability returns a read-only account capability of
+// moduleName over the full state store s, e.g. a query context's pinned
+// store or a saved IAVL version
+func NewReadOnlyAccountCapability(s store.BackingStore, moduleName string) (ReadOnlyAccountCapability, error) {
+	if s == nil {
+		return nil, ErrStoreNil
+	}
+	if moduleName == "" {
+		return nil, fmt.Errorf("module name cannot be empty")
+	}
+
+	return newReadOnlyAccountCapability(s, moduleName, nil), nil
+}
+
+// newReadOnlyAccountCapability builds the capability; grant is nil outside a
+// manager
+func newReadOnlyAccountCapability(s store.BackingStore, moduleName string, grant *moduleGrant) *readOnlyAccountCapability {
+	return &readOnlyAccountCapability{
+		moduleName: moduleName,
+		store:      store.NewReadOnlyStore(ModuleStore(s, moduleName)),
+		extensions: store.NewReadOnlyStore(AccountExtensionStore(s, moduleName)),
+		grant:      grant,
+	}
+}
+
+// accounts returns an account capability over the read-only views. A fresh
+// account store per call keeps its cache from serving values that writable
+// capabilities have since replaced.
+func (rc *readOnlyAccountCapability) accounts() *accountCapability {
+	if rc == nil || rc.store == nil {
+		return nil
+	}
+	return &accountCapability{
+		moduleName: rc.moduleName,
+		store:      store.NewAccountStore(rc.store),
+		extensions: rc.extensions,
+		grant:      rc.grant,
+	}
+}
+
+// ModuleName returns the module this capability is scoped to
+func (rc *readOnlyAccountCapability) ModuleName() string {
+	if rc == nil {
+		return ""
+	}
+	return rc.moduleName
+}
+
+// GetAccount retrieves an account by name
+func (rc *readOnlyAccountCapability) GetAccount(ctx context.Context, name types.AccountName) (*types.Account, error) {
+	return rc.accounts().GetAccount(ctx, name)
+}
+
+// HasAccount checks if an account exists
+func (rc *readOnlyAccountCapability) HasAccount(ctx context.Context, name types.AccountName) (bool, error) {
+	return rc.accounts().HasAccount(ctx, name)
+}
+
+// VerifyAuthorization verifies that an authorization meets the account's authority threshold
+func (rc *readOnlyAccountCapability) VerifyAuthorization(ctx context.Context, account *types.Account, auth *types.Authorization, message []byte) error {
+	return rc.accounts().VerifyAuthorization(ctx, account, auth, message)
+}
+
+// GetNonce retrieves an account's current nonce
+func (rc *readOnlyAccountCapability) GetNonce(ctx context.Context, name types.AccountName) (uint64, error) {
+	return rc.accounts().GetNonce(ctx, name)
+}
+
+// IterateAccounts iterates over all accounts
+func (rc *readOnlyAccountCapability) IterateAccounts(ctx context.Context, callback func(*types.Account) error) error {
+	return rc.accounts().IterateAccounts(ctx, callback)
+}
+
+// GetExtension decodes an account's extension into ext
+func (rc *readOnlyAccountCapability) GetExtension(ctx context.Context, name types.AccountName, ext types.AccountExtension) error {
+	return rc.accounts().GetExtension(ctx, name, ext)
+}
+
+// readOnlyBalanceCapability is the implementation of ReadOnlyBalanceCapability.
+//
+// SECURITY: See readOnlyAccountCapability.
+type readOnlyBalanceCapability struct {
+	moduleName string
+	store      store.BackingStore
+	grant      *moduleGrant
+}
+
+// NewReadOnlyBalanceCapability returns a read-only balance capability of
+// moduleName over the full state store s (see NewReadOnlyAccountCapability)
+func NewReadOnlyBalanceCapability(s store.BackingStore, moduleName string) (ReadOnlyBalanceCapability, error) {
+	if s == nil {
+		return nil, ErrStoreNil
+	}
+	if moduleName == "" {
+		return nil, fmt.Errorf("module name cannot be empty")
+	}
+
+	return newReadOnlyBalanceCapability(s, moduleName, nil), nil
+}
+
+// newReadOnlyBalanceCapability builds the capability; grant is nil outside a
+// manager
+func newReadOnlyBalanceCapability(s store.BackingStore, moduleName string, grant *moduleGrant) *readOnlyBalanceCapability {
+	return &readOnlyBalanceCapability{
+		moduleName: moduleName,
+		store:      store.NewReadOnlyStore(ModuleStore(s, moduleName)),
+		grant:      grant,
+	}
+}
+
+// balances returns a balance capability over the read-only view, with a
+// fresh balance store per call (see readOnlyAccountCapability.accounts)
+func (rc *readOnlyBalanceCapability) balances() *balanceCapability {
+	if rc == nil || rc.store == nil {
+		return nil
+	}
+	return &balanceCapability{
+		moduleName: rc.moduleName,
+		store:      store.NewBalanceStore(rc.store),
+		grant:      rc.grant,
+	}
+}
+
+// ModuleName returns the module this capability is scoped to
+func (rc *readOnlyBalanceCapability) ModuleName() string {
+	if rc == nil {
+		return ""
+	}
+	return rc.moduleName
+}
+
+// GetBalance retrieves a balance by account and denomination
+func (rc *readOnlyBalanceCapability) GetBalance(ctx context.Context, account types.AccountName, denom string) (uint64, error) {
+	return rc.balances().GetBalance(ctx, account, denom)
+}
+
+// GetAccountBalances retrieves all balances for an account
+func (rc *readOnlyBalanceCapability) GetAccountBalances(ctx context.Context, account types.AccountName) (types.Coins, error) {
+	return rc.balances().GetAccountBalances(ctx, account)
+}
+
+// HasBalance checks if a balance exists for an account and denomination
+func (rc *readOnlyBalanceCapability) HasBalance(ctx context.Context, account types.AccountName, denom string) (bool, error) {
+	return rc.balances().HasBalance(ctx, account, denom)
+}
+
+// IterateBalances iterates over all balances
+func (rc *readOnlyBalanceCapability) IterateBalances(ctx context.Context, callback func(store.Balance) error) error {
+	return rc.balances().IterateBalances(ctx, callback)
+}
+
+// IterateAccountBalances iterates over all balances for a specific account
+func (rc *readOnlyBalanceCapability) IterateAccountBalances(ctx context.Context, account types.AccountName, callback func(store.Balance) error) error {
+	return rc.balances().IterateAccountBalances(ctx, account, callback)
+}
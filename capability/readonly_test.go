@@ -0,0 +1,173 @@
+package capability
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// flush flushes a writable capability's cached writes to the backing store
+func flush(t *testing.T, cap any) {
+	t.Helper()
+
+	if flushable, ok := cap.(interface{ Flush(context.Context) error }); ok {
+		if err := flushable.Flush(context.Background()); err != nil {
+			t.Fatalf("failed to flush: %v", err)
+		}
+	}
+}
+
+func TestGrantReadOnlyAccountCapability(t *testing.T) {
+	cm := NewCapabilityManager(store.NewMemoryStore())
+	ctx := context.Background()
+
+	if err := cm.RegisterModule("auth"); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	writable, err := cm.GrantAccountCapability("auth")
+	if err != nil {
+		t.Fatalf("failed to grant account capability: %v", err)
+	}
+	readOnly, err := cm.GrantReadOnlyAccountCapability("auth")
+	if err != nil {
+		t.Fatalf("failed to grant read-only account capability: %v", err)
+	}
+
+	if _, ok := readOnly.(AccountCapability); ok {
+		t.Fatal("read-only capability asserts to AccountCapability")
+	}
+	if readOnly.ModuleName() != "auth" {
+		t.Fatalf("expected module name 'auth', got %s", readOnly.ModuleName())
+	}
+
+	if _, err := writable.CreateAccount(ctx, "alice", []byte("pubkey")); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	flush(t, writable)
+
+	account, err := readOnly.GetAccount(ctx, "alice")
+	if err != nil {
+		t.Fatalf("failed to get account: %v", err)
+	}
+	if account.Nonce != 0 {
+		t.Fatalf("expected nonce 0, got %d", account.Nonce)
+	}
+
+	// Later writes are visible: the read-only capability holds no cache
+	if err := writable.IncrementNonce(ctx, "alice"); err != nil {
+		t.Fatalf("failed to increment nonce: %v", err)
+	}
+	flush(t, writable)
+	nonce, err := readOnly.GetNonce(ctx, "alice")
+	if err != nil {
+		t.Fatalf("failed to get nonce: %v", err)
+	}
+	if nonce != 1 {
+		t.Fatalf("expected nonce 1, got %d", nonce)
+	}
+
+	var names []types.AccountName
+	if err := readOnly.IterateAccounts(ctx, func(a *types.Account) error {
+		names = append(names, a.Name)
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to iterate accounts: %v", err)
+	}
+	if len(names) != 1 || names[0] != "alice" {
+		t.Fatalf("expected [alice], got %v", names)
+	}
+
+	if has, err := readOnly.HasAccount(ctx, "bob"); err != nil || has {
+		t.Fatalf("HasAccount(bob) = %v, %v, want false", has, err)
+	}
+}
+
+func TestGrantReadOnlyBalanceCapability(t *testing.T) {
+	cm := NewCapabilityManager(store.NewMemoryStore())
+	ctx := context.Background()
+
+	if err := cm.RegisterModule("bank"); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	readOnly, err := cm.GrantReadOnlyBalanceCapability("bank")
+	if err != nil {
+		t.Fatalf("failed to grant read-only balance capability: %v", err)
+	}
+	if _, ok := readOnly.(BalanceCapability); ok {
+		t.Fatal("read-only capability asserts to BalanceCapability")
+	}
+
+	// A read-only grant does not count as a grant for index registration
+	if _, err := cm.RegisterIndex("bank", "by_denom", store.BalanceDenomIndex); err != nil {
+		t.Fatalf("failed to register index after read-only grant: %v", err)
+	}
+
+	writable, err := cm.GrantBalanceCapability("bank")
+	if err != nil {
+		t.Fatalf("failed to grant balance capability: %v", err)
+	}
+	if err := writable.SetBalance(ctx, "alice", "stake", 100); err != nil {
+		t.Fatalf("failed to set balance: %v", err)
+	}
+	flush(t, writable)
+
+	amount, err := readOnly.GetBalance(ctx, "alice", "stake")
+	if err != nil {
+		t.Fatalf("failed to get balance: %v", err)
+	}
+	if amount != 100 {
+		t.Fatalf("expected balance 100, got %d", amount)
+	}
+	coins, err := readOnly.GetAccountBalances(ctx, "alice")
+	if err != nil {
+		t.Fatalf("failed to get account balances: %v", err)
+	}
+	if len(coins) != 1 || coins[0].Amount != 100 {
+		t.Fatalf("expected 100stake, got %v", coins)
+	}
+}
+
+func TestGrantReadOnlyCapability_Errors(t *testing.T) {
+	cm := NewCapabilityManager(store.NewMemoryStore())
+	if _, err := cm.GrantReadOnlyAccountCapability("missing"); !errors.Is(err, ErrModuleNotFound) {
+		t.Fatalf("expected ErrModuleNotFound, got %v", err)
+	}
+	if _, err := cm.GrantReadOnlyBalanceCapability("missing"); !errors.Is(err, ErrModuleNotFound) {
+		t.Fatalf("expected ErrModuleNotFound, got %v", err)
+	}
+
+	var nilManager *CapabilityManager
+	if _, err := nilManager.GrantReadOnlyAccountCapability("auth"); !errors.Is(err, ErrCapabilityNil) {
+		t.Fatalf("expected ErrCapabilityNil, got %v", err)
+	}
+	if _, err := NewReadOnlyBalanceCapability(nil, "bank"); !errors.Is(err, ErrStoreNil) {
+		t.Fatalf("expected ErrStoreNil, got %v", err)
+	}
+	if _, err := NewReadOnlyAccountCapability(store.NewMemoryStore(), ""); err == nil {
+		t.Fatal("expected error with empty module name")
+	}
+}
+
+func TestReadOnlyCapability_RejectsWrites(t *testing.T) {
+	backing := store.NewMemoryStore()
+	readOnly, err := NewReadOnlyAccountCapability(backing, "auth")
+	if err != nil {
+		t.Fatalf("failed to create read-only capability: %v", err)
+	}
+
+	// Reach the store through the implementation to confirm the view itself
+	// rejects writes
+	impl := readOnly.(*readOnlyAccountCapability)
+	if err := impl.store.Set([]byte("alice"), []byte("{}")); !errors.Is(err, store.ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+	if err := impl.extensions.Delete([]byte("alice/ext")); !errors.Is(err, store.ErrReadOnly) {
+		t.Fatalf("expected ErrReadOnly, got %v", err)
+	}
+	if has, _ := backing.Has([]byte("module/auth/alice")); has {
+		t.Fatal("write reached the backing store")
+	}
+}
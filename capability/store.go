@@ -0,0 +1,133 @@
+package capability
+
+import (
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/store"
+)
+
+// StoreCapability provides a module's raw key-value state: the view of the
+// state store under ModuleStore(ModuleName()). Modules read their state
+// through it and change it with state effects, which the runtime applies
+// under the same prefix.
+//
+// Flush and Close do nothing: the state store belongs to the application.
+type StoreCapability interface {
+	store.BackingStore
+
+	// ModuleName returns the module this capability is scoped to
+	ModuleName() string
+}
+
+// storeCapability is the implementation of StoreCapability
+type storeCapability struct {
+	moduleName string
+	store      store.BackingStore
+
+	// grant is revoked when the module is unregistered (nil: never revoked)
+	grant *moduleGrant
+}
+
+// GrantStoreCapability grants a module access to its own state namespace
+func (cm *CapabilityManager) GrantStoreCapability(moduleName string) (StoreCapability, error) {
+	if cm == nil {
+		return nil, ErrCapabilityNil
+	}
+
+	prefixedStore, grant, err := cm.createPrefixedStore(moduleName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storeCapability{
+		moduleName: moduleName,
+		store:      prefixedStore,
+		grant:      grant,
+	}, nil
+}
+
+// ModuleName returns the module this capability is scoped to
+func (sc *storeCapability) ModuleName() string {
+	if sc == nil {
+		return ""
+	}
+	return sc.moduleName
+}
+
+// check returns an error unless the capability may be used
+func (sc *storeCapability) check() error {
+	if sc == nil || sc.store == nil {
+		return ErrCapabilityNil
+	}
+	return sc.grant.check(sc.moduleName)
+}
+
+// Get retrieves raw bytes by key
+func (sc *storeCapability) Get(key []byte) ([]byte, error) {
+	if err := sc.check(); err != nil {
+		return nil, err
+	}
+	return sc.store.Get(key)
+}
+
+// Set stores raw bytes with the given key
+func (sc *storeCapability) Set(key []byte, value []byte) error {
+	if err := sc.check(); err != nil {
+		return err
+	}
+	return sc.store.Set(key, value)
+}
+
+// Delete removes a key
+func (sc *storeCapability) Delete(key []byte) error {
+	if err := sc.check(); err != nil {
+		return err
+	}
+	return sc.store.Delete(key)
+}
+
+// Has checks if a key exists
+func (sc *storeCapability) Has(key []byte) (bool, error) {
+	if err := sc.check(); err != nil {
+		return false, err
+	}
+	return sc.store.Has(key)
+}
+
+// Iterator returns an iterator over a range of keys
+func (sc *storeCapability) Iterator(start, end []byte) (store.RawIterator, error) {
+	if err := sc.check(); err != nil {
+		return nil, err
+	}
+	return sc.store.Iterator(start, end)
+}
+
+// ReverseIterator returns a reverse iterator over a range of keys
+func (sc *storeCapability) ReverseIterator(start, end []byte) (store.RawIterator, error) {
+	if err := sc.check(); err != nil {
+		return nil, err
+	}
+	return sc.store.ReverseIterator(start, end)
+}
+
+// Flush does nothing; the application flushes the state store
+func (sc *storeCapability) Flush() error {
+	return sc.check()
+}
+
+// Close does nothing; the application closes the state store
+func (sc *storeCapability) Close() error {
+	return sc.check()
+}
+
+// CheckStoreCapability returns an error unless storeCap was granted to
+// moduleName, for module constructors taking one
+func CheckStoreCapability(storeCap StoreCapability, moduleName string) error {
+	if storeCap == nil {
+		return fmt.Errorf("store capability cannot be nil")
+	}
+	if storeCap.ModuleName() != moduleName {
+		return fmt.Errorf("store capability granted to %s, not %s", storeCap.ModuleName(), moduleName)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package capability
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/blockberries/punnet-sdk/store"
+)
+
+func TestGrantStoreCapability(t *testing.T) {
+	backing := store.NewMemoryStore()
+	cm := NewCapabilityManager(backing)
+	if err := cm.RegisterModule("nft"); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+
+	storeCap, err := cm.GrantStoreCapability("nft")
+	if err != nil {
+		t.Fatalf("failed to grant store capability: %v", err)
+	}
+	if storeCap.ModuleName() != "nft" {
+		t.Fatalf("expected module name 'nft', got %s", storeCap.ModuleName())
+	}
+
+	// Writes land in the module's namespace, where state effects and
+	// ModuleStore read them
+	if err := storeCap.Set([]byte("k"), []byte("v")); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+	value, err := backing.Get([]byte("module/nft/k"))
+	if err != nil || string(value) != "v" {
+		t.Fatalf("expected v under module/nft/k, got %q, %v", value, err)
+	}
+	if err := backing.Set([]byte("module/nft/other"), []byte("w")); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+	if has, err := storeCap.Has([]byte("other")); err != nil || !has {
+		t.Fatalf("expected other to be visible, got %v, %v", has, err)
+	}
+
+	// Nothing outside the namespace is visible
+	if err := backing.Set([]byte("module/bank/k"), []byte("x")); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+	iter, err := storeCap.Iterator(nil, nil)
+	if err != nil {
+		t.Fatalf("failed to iterate: %v", err)
+	}
+	defer iter.Close()
+	count := 0
+	for ; iter.Valid(); iter.Next() {
+		count++
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 keys, got %d", count)
+	}
+
+	// Flush and Close leave the application's store alone
+	if err := storeCap.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := storeCap.Get([]byte("k")); err != nil {
+		t.Fatalf("expected the store to stay open, got %v", err)
+	}
+}
+
+func TestGrantStoreCapability_Errors(t *testing.T) {
+	cm := NewCapabilityManager(store.NewMemoryStore())
+	if _, err := cm.GrantStoreCapability("nft"); !errors.Is(err, ErrModuleNotFound) {
+		t.Fatalf("expected ErrModuleNotFound, got %v", err)
+	}
+
+	var nilManager *CapabilityManager
+	if _, err := nilManager.GrantStoreCapability("nft"); !errors.Is(err, ErrCapabilityNil) {
+		t.Fatalf("expected ErrCapabilityNil, got %v", err)
+	}
+}
+
+func TestStoreCapability_Revoked(t *testing.T) {
+	cm := NewCapabilityManager(store.NewMemoryStore())
+	if err := cm.RegisterModule("nft"); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	storeCap, err := cm.GrantStoreCapability("nft")
+	if err != nil {
+		t.Fatalf("failed to grant store capability: %v", err)
+	}
+
+	if err := cm.UnregisterModule("nft"); err != nil {
+		t.Fatalf("failed to unregister module: %v", err)
+	}
+	if _, err := storeCap.Get([]byte("k")); !errors.Is(err, ErrCapabilityRevoked) {
+		t.Fatalf("expected ErrCapabilityRevoked, got %v", err)
+	}
+	if err := storeCap.Set([]byte("k"), []byte("v")); !errors.Is(err, ErrCapabilityRevoked) {
+		t.Fatalf("expected ErrCapabilityRevoked, got %v", err)
+	}
+}
+
+func TestCheckStoreCapability(t *testing.T) {
+	cm := NewCapabilityManager(store.NewMemoryStore())
+	if err := cm.RegisterModule("nft"); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	storeCap, err := cm.GrantStoreCapability("nft")
+	if err != nil {
+		t.Fatalf("failed to grant store capability: %v", err)
+	}
+
+	if err := CheckStoreCapability(storeCap, "nft"); err != nil {
+		t.Fatalf("expected capability of nft to pass, got %v", err)
+	}
+	if err := CheckStoreCapability(storeCap, "escrow"); err == nil {
+		t.Fatal("expected capability of nft to fail for escrow")
+	}
+	if err := CheckStoreCapability(nil, "nft"); err == nil {
+		t.Fatal("expected nil capability to fail")
+	}
+}
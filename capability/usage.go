@@ -0,0 +1,246 @@
+package capability
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/blockberries/punnet-sdk/store"
+)
+
+// ErrStorageLimitExceeded is returned when a write would take a module past
+// its per-block storage limit
+var ErrStorageLimitExceeded = errors.New("module storage limit exceeded")
+
+// Key prefixes of the per-module namespaces (see ModuleStore,
+// AccountExtensionStore and IndexStore)
+var modulePrefixes = [][]byte{
+	[]byte("module/"),
+	[]byte("account_ext/"),
+	[]byte("index/"),
+}
+
+// ModuleOfKey returns the module owning key of the full state store, if key
+// lies in a module's ModuleStore, AccountExtensionStore or IndexStore
+// namespace
+func ModuleOfKey(key []byte) (string, bool) {
+	for _, prefix := range modulePrefixes {
+		if !bytes.HasPrefix(key, prefix) {
+			continue
+		}
+		rest := key[len(prefix):]
+		end := bytes.IndexByte(rest, '/')
+		if end <= 0 {
+			return "", false
+		}
+		return string(rest[:end]), true
+	}
+	return "", false
+}
+
+// StorageUsage is the storage a module wrote in one block
+type StorageUsage struct {
+	// Module is the module name
+	Module string `json:"module"`
+
+	// BytesWritten is the key and value bytes of the module's writes.
+	// Deletes are not counted; they do not grow state.
+	BytesWritten uint64 `json:"bytes_written"`
+
+	// Writes is the number of writes
+	Writes uint64 `json:"writes"`
+}
+
+// StorageLimits caps the bytes each module may write per block. Zero means
+// no cap.
+type StorageLimits struct {
+	// Default applies to modules without a PerModule entry
+	Default uint64 `json:"default"`
+
+	// PerModule overrides Default for individual modules
+	PerModule map[string]uint64 `json:"per_module,omitempty"`
+}
+
+// limit returns the cap of module (0 for none)
+func (l StorageLimits) limit(module string) uint64 {
+	if limit, ok := l.PerModule[module]; ok {
+		return limit
+	}
+	return l.Default
+}
+
+// StorageReporter receives each block's storage usage, sorted by module
+// name, e.g. to export it as metrics. It is called synchronously and must
+// not block for long.
+type StorageReporter func(height uint64, usage []StorageUsage)
+
+// UsageTracker counts the bytes each module writes per block and enforces
+// StorageLimits, so operators can spot and stop runaway state growth.
+//
+// Writes are counted when attempted, including writes that a failed
+// transaction later rolls back; the counts are an upper bound on growth.
+//
+// INVARIANT: Every node attempts the same writes in the same order, so a
+// capped write fails on every node alike.
+type UsageTracker struct {
+	mu       sync.Mutex
+	limits   StorageLimits
+	reporter StorageReporter
+	usage    map[string]*StorageUsage
+}
+
+// NewUsageTracker creates a tracker enforcing limits and reporting each
+// block's usage to reporter (which may be nil)
+func NewUsageTracker(limits StorageLimits, reporter StorageReporter) *UsageTracker {
+	perModule := make(map[string]uint64, len(limits.PerModule))
+	for module, limit := range limits.PerModule {
+		perModule[module] = limit
+	}
+	limits.PerModule = perModule
+
+	return &UsageTracker{
+		limits:   limits,
+		reporter: reporter,
+		usage:    make(map[string]*StorageUsage),
+	}
+}
+
+// RecordWrite counts a write of n bytes by module. It returns
+// ErrStorageLimitExceeded, counting nothing, if the write would take the
+// module past its limit for the block.
+func (t *UsageTracker) RecordWrite(module string, n int) error {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	u := t.usage[module]
+	if u == nil {
+		u = &StorageUsage{Module: module}
+		t.usage[module] = u
+	}
+
+	written := u.BytesWritten + uint64(n)
+	if limit := t.limits.limit(module); limit > 0 && (written > limit || written < u.BytesWritten) {
+		return fmt.Errorf("%w: module %s wrote %d of %d bytes this block, wanted %d more",
+			ErrStorageLimitExceeded, module, u.BytesWritten, limit, n)
+	}
+	u.BytesWritten = written
+	u.Writes++
+	return nil
+}
+
+// recordKey counts a write of key and value if key lies in a module
+// namespace; other keys (accounts, balances, ...) are not tracked
+func (t *UsageTracker) recordKey(key, value []byte) error {
+	if t == nil {
+		return nil
+	}
+	module, ok := ModuleOfKey(key)
+	if !ok {
+		return nil
+	}
+	return t.RecordWrite(module, len(key)+len(value))
+}
+
+// Usage returns the usage of module in the current block
+func (t *UsageTracker) Usage(module string) StorageUsage {
+	if t == nil {
+		return StorageUsage{Module: module}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if u := t.usage[module]; u != nil {
+		return *u
+	}
+	return StorageUsage{Module: module}
+}
+
+// Snapshot returns the usage of every module that wrote in the current
+// block, sorted by module name
+func (t *UsageTracker) Snapshot() []StorageUsage {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.snapshotLocked()
+}
+
+// snapshotLocked implements Snapshot.
+// PRECONDITION: t.mu is held.
+func (t *UsageTracker) snapshotLocked() []StorageUsage {
+	usage := make([]StorageUsage, 0, len(t.usage))
+	for _, u := range t.usage {
+		usage = append(usage, *u)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Module < usage[j].Module })
+	return usage
+}
+
+// EndBlock reports the block's usage to the reporter and resets the counts
+// for the next block
+func (t *UsageTracker) EndBlock(height uint64) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	usage := t.snapshotLocked()
+	t.usage = make(map[string]*StorageUsage)
+	reporter := t.reporter
+	t.mu.Unlock()
+
+	if reporter != nil {
+		reporter(height, usage)
+	}
+}
+
+// Reset discards the counts of the current block without reporting them,
+// e.g. when a block is abandoned and executed again
+func (t *UsageTracker) Reset() {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.usage = make(map[string]*StorageUsage)
+}
+
+// trackedStore counts the writes to a store whose keys are full state keys
+type trackedStore struct {
+	store.BackingStore
+	tracker *UsageTracker
+}
+
+// NewTrackedStore returns a view of the full state store s that counts every
+// write into a module namespace with tracker, rejecting writes past a
+// module's limit before they reach s
+func NewTrackedStore(s store.BackingStore, tracker *UsageTracker) store.BackingStore {
+	if tracker == nil {
+		return s
+	}
+	return &trackedStore{BackingStore: s, tracker: tracker}
+}
+
+// Set stores raw bytes with the given key, counting them first
+func (s *trackedStore) Set(key []byte, value []byte) error {
+	if err := s.tracker.recordKey(key, value); err != nil {
+		return err
+	}
+	return s.BackingStore.Set(key, value)
+}
+
+// Close is a no-op; the parent store is owned by the caller
+func (s *trackedStore) Close() error {
+	return nil
+}
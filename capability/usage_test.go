@@ -0,0 +1,174 @@
+package capability
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/blockberries/punnet-sdk/store"
+)
+
+func TestModuleOfKey(t *testing.T) {
+	tests := []struct {
+		key    string
+		module string
+		ok     bool
+	}{
+		{"module/bank/balance", "bank", true},
+		{"account_ext/auth/alice", "auth", true},
+		{"index/staking/by_power/x", "staking", true},
+		{"module/bank", "", false},
+		{"module//x", "", false},
+		{"balance/alice/stake", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		module, ok := ModuleOfKey([]byte(tt.key))
+		if module != tt.module || ok != tt.ok {
+			t.Errorf("ModuleOfKey(%q) = %q, %v; want %q, %v", tt.key, module, ok, tt.module, tt.ok)
+		}
+	}
+}
+
+func TestUsageTracker_Limits(t *testing.T) {
+	tracker := NewUsageTracker(StorageLimits{Default: 10, PerModule: map[string]uint64{"big": 0, "small": 3}}, nil)
+
+	if err := tracker.RecordWrite("a", 10); err != nil {
+		t.Fatalf("write up to the default limit failed: %v", err)
+	}
+	if err := tracker.RecordWrite("a", 1); !errors.Is(err, ErrStorageLimitExceeded) {
+		t.Fatalf("expected ErrStorageLimitExceeded, got %v", err)
+	}
+	if got := tracker.Usage("a"); got.BytesWritten != 10 || got.Writes != 1 {
+		t.Fatalf("rejected write was counted: %+v", got)
+	}
+
+	if err := tracker.RecordWrite("small", 4); !errors.Is(err, ErrStorageLimitExceeded) {
+		t.Fatalf("expected per-module limit to apply, got %v", err)
+	}
+
+	// A zero per-module limit lifts the default
+	if err := tracker.RecordWrite("big", 1<<20); err != nil {
+		t.Fatalf("uncapped write failed: %v", err)
+	}
+
+	if got := tracker.Usage("unknown"); got != (StorageUsage{Module: "unknown"}) {
+		t.Fatalf("expected no usage, got %+v", got)
+	}
+}
+
+func TestUsageTracker_EndBlock(t *testing.T) {
+	var reportedHeight uint64
+	var reported []StorageUsage
+	tracker := NewUsageTracker(StorageLimits{Default: 100}, func(height uint64, usage []StorageUsage) {
+		reportedHeight, reported = height, usage
+	})
+
+	for _, w := range []struct {
+		module string
+		n      int
+	}{{"b", 5}, {"a", 3}, {"b", 7}} {
+		if err := tracker.RecordWrite(w.module, w.n); err != nil {
+			t.Fatalf("RecordWrite failed: %v", err)
+		}
+	}
+
+	want := []StorageUsage{
+		{Module: "a", BytesWritten: 3, Writes: 1},
+		{Module: "b", BytesWritten: 12, Writes: 2},
+	}
+	if got := tracker.Snapshot(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Snapshot() = %+v, want %+v", got, want)
+	}
+
+	tracker.EndBlock(7)
+	if reportedHeight != 7 || !reflect.DeepEqual(reported, want) {
+		t.Fatalf("reported %d: %+v, want 7: %+v", reportedHeight, reported, want)
+	}
+
+	// Limits apply per block
+	if len(tracker.Snapshot()) != 0 {
+		t.Fatalf("expected counts to reset, got %+v", tracker.Snapshot())
+	}
+	if err := tracker.RecordWrite("b", 100); err != nil {
+		t.Fatalf("write in the next block failed: %v", err)
+	}
+
+	tracker.Reset()
+	if len(tracker.Snapshot()) != 0 {
+		t.Fatalf("expected Reset to discard counts, got %+v", tracker.Snapshot())
+	}
+}
+
+func TestUsageTracker_Nil(t *testing.T) {
+	var tracker *UsageTracker
+	if err := tracker.RecordWrite("a", 1); err != nil {
+		t.Fatalf("nil tracker should accept writes, got %v", err)
+	}
+	tracker.EndBlock(1)
+	tracker.Reset()
+	if tracker.Snapshot() != nil {
+		t.Fatal("expected nil snapshot")
+	}
+
+	backing := store.NewMemoryStore()
+	if NewTrackedStore(backing, nil) != store.BackingStore(backing) {
+		t.Fatal("expected untracked store to be returned as is")
+	}
+}
+
+func TestTrackedStore(t *testing.T) {
+	backing := store.NewMemoryStore()
+	tracker := NewUsageTracker(StorageLimits{Default: 20}, nil)
+	s := NewTrackedStore(backing, tracker)
+
+	// "module/m/k" + "value" is 15 bytes
+	if err := s.Set([]byte("module/m/k"), []byte("value")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if got := tracker.Usage("m"); got.BytesWritten != 15 || got.Writes != 1 {
+		t.Fatalf("unexpected usage %+v", got)
+	}
+
+	// A write past the limit does not reach the backing store
+	if err := s.Set([]byte("module/m/j"), []byte("value")); !errors.Is(err, ErrStorageLimitExceeded) {
+		t.Fatalf("expected ErrStorageLimitExceeded, got %v", err)
+	}
+	if has, _ := backing.Has([]byte("module/m/j")); has {
+		t.Fatal("rejected write reached the backing store")
+	}
+
+	// Deletes and keys outside module namespaces are not counted
+	if err := s.Delete([]byte("module/m/k")); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := s.Set([]byte("balance/alice"), []byte("1000000000000000000000000")); err != nil {
+		t.Fatalf("Set outside module namespaces failed: %v", err)
+	}
+	if got := tracker.Usage("m"); got.BytesWritten != 15 || got.Writes != 1 {
+		t.Fatalf("unexpected usage %+v", got)
+	}
+}
+
+func TestCapabilityManager_UsageTracker(t *testing.T) {
+	backing := store.NewMemoryStore()
+	cm := NewCapabilityManager(backing)
+	tracker := NewUsageTracker(StorageLimits{}, nil)
+	cm.SetUsageTracker(tracker)
+
+	if err := cm.RegisterModule("m"); err != nil {
+		t.Fatalf("RegisterModule failed: %v", err)
+	}
+	prefixed, _, err := cm.createPrefixedStore("m")
+	if err != nil {
+		t.Fatalf("createPrefixedStore failed: %v", err)
+	}
+
+	// "module/m/" + "k" + "v" is 11 bytes
+	if err := prefixed.Set([]byte("k"), []byte("v")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if got := tracker.Usage("m"); got.BytesWritten != 11 || got.Writes != 1 {
+		t.Fatalf("expected capability writes to be counted, got %+v", got)
+	}
+}
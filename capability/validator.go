@@ -61,6 +61,9 @@ type validatorCapability struct {
 	moduleName      string
 	validatorStore  *store.ValidatorStore
 	delegationStore *store.DelegationStore
+
+	// grant is revoked when the module is unregistered (nil: never revoked)
+	grant *moduleGrant
 }
 
 // ModuleName returns the module this capability is scoped to
@@ -78,6 +81,9 @@ func (vc *validatorCapability) GetValidator(ctx context.Context, pubKey []byte)
 	if vc == nil || vc.validatorStore == nil {
 		return zero, ErrCapabilityNil
 	}
+	if err := vc.grant.check(vc.moduleName); err != nil {
+		return zero, err
+	}
 
 	if len(pubKey) == 0 {
 		return zero, fmt.Errorf("public key cannot be empty")
@@ -96,6 +102,9 @@ func (vc *validatorCapability) SetValidator(ctx context.Context, validator store
 	if vc == nil || vc.validatorStore == nil {
 		return ErrCapabilityNil
 	}
+	if err := vc.grant.check(vc.moduleName); err != nil {
+		return err
+	}
 
 	if !validator.IsValid() {
 		return fmt.Errorf("invalid validator")
@@ -113,6 +122,9 @@ func (vc *validatorCapability) DeleteValidator(ctx context.Context, pubKey []byt
 	if vc == nil || vc.validatorStore == nil {
 		return ErrCapabilityNil
 	}
+	if err := vc.grant.check(vc.moduleName); err != nil {
+		return err
+	}
 
 	if len(pubKey) == 0 {
 		return fmt.Errorf("public key cannot be empty")
@@ -130,6 +142,9 @@ func (vc *validatorCapability) HasValidator(ctx context.Context, pubKey []byte)
 	if vc == nil || vc.validatorStore == nil {
 		return false, ErrCapabilityNil
 	}
+	if err := vc.grant.check(vc.moduleName); err != nil {
+		return false, err
+	}
 
 	if len(pubKey) == 0 {
 		return false, fmt.Errorf("public key cannot be empty")
@@ -143,6 +158,9 @@ func (vc *validatorCapability) GetActiveValidators(ctx context.Context) ([]store
 	if vc == nil || vc.validatorStore == nil {
 		return nil, ErrCapabilityNil
 	}
+	if err := vc.grant.check(vc.moduleName); err != nil {
+		return nil, err
+	}
 
 	validators, err := vc.validatorStore.GetActiveValidators(ctx)
 	if err != nil {
@@ -160,6 +178,9 @@ func (vc *validatorCapability) GetValidatorSet(ctx context.Context) ([]types.Val
 	if vc == nil || vc.validatorStore == nil {
 		return nil, ErrCapabilityNil
 	}
+	if err := vc.grant.check(vc.moduleName); err != nil {
+		return nil, err
+	}
 
 	updates, err := vc.validatorStore.GetValidatorUpdates(ctx)
 	if err != nil {
@@ -177,6 +198,9 @@ func (vc *validatorCapability) SetValidatorPower(ctx context.Context, pubKey []b
 	if vc == nil || vc.validatorStore == nil {
 		return ErrCapabilityNil
 	}
+	if err := vc.grant.check(vc.moduleName); err != nil {
+		return err
+	}
 
 	if len(pubKey) == 0 {
 		return fmt.Errorf("public key cannot be empty")
@@ -194,6 +218,9 @@ func (vc *validatorCapability) SetValidatorActive(ctx context.Context, pubKey []
 	if vc == nil || vc.validatorStore == nil {
 		return ErrCapabilityNil
 	}
+	if err := vc.grant.check(vc.moduleName); err != nil {
+		return err
+	}
 
 	if len(pubKey) == 0 {
 		return fmt.Errorf("public key cannot be empty")
@@ -211,6 +238,9 @@ func (vc *validatorCapability) IterateValidators(ctx context.Context, callback f
 	if vc == nil || vc.validatorStore == nil {
 		return ErrCapabilityNil
 	}
+	if err := vc.grant.check(vc.moduleName); err != nil {
+		return err
+	}
 
 	if callback == nil {
 		return fmt.Errorf("callback cannot be nil")
@@ -247,6 +277,9 @@ func (vc *validatorCapability) GetDelegation(ctx context.Context, delegator type
 	if vc == nil || vc.delegationStore == nil {
 		return zero, ErrCapabilityNil
 	}
+	if err := vc.grant.check(vc.moduleName); err != nil {
+		return zero, err
+	}
 
 	if !delegator.IsValid() {
 		return zero, fmt.Errorf("%w: invalid delegator account name", types.ErrInvalidAccount)
@@ -269,6 +302,9 @@ func (vc *validatorCapability) SetDelegation(ctx context.Context, delegation sto
 	if vc == nil || vc.delegationStore == nil {
 		return ErrCapabilityNil
 	}
+	if err := vc.grant.check(vc.moduleName); err != nil {
+		return err
+	}
 
 	if !delegation.IsValid() {
 		return fmt.Errorf("invalid delegation")
@@ -286,6 +322,9 @@ func (vc *validatorCapability) DeleteDelegation(ctx context.Context, delegator t
 	if vc == nil || vc.delegationStore == nil {
 		return ErrCapabilityNil
 	}
+	if err := vc.grant.check(vc.moduleName); err != nil {
+		return err
+	}
 
 	if !delegator.IsValid() {
 		return fmt.Errorf("%w: invalid delegator account name", types.ErrInvalidAccount)
@@ -307,6 +346,9 @@ func (vc *validatorCapability) HasDelegation(ctx context.Context, delegator type
 	if vc == nil || vc.delegationStore == nil {
 		return false, ErrCapabilityNil
 	}
+	if err := vc.grant.check(vc.moduleName); err != nil {
+		return false, err
+	}
 
 	if !delegator.IsValid() {
 		return false, fmt.Errorf("%w: invalid delegator account name", types.ErrInvalidAccount)
@@ -324,6 +366,9 @@ func (vc *validatorCapability) IterateDelegations(ctx context.Context, callback
 	if vc == nil || vc.delegationStore == nil {
 		return ErrCapabilityNil
 	}
+	if err := vc.grant.check(vc.moduleName); err != nil {
+		return err
+	}
 
 	if callback == nil {
 		return fmt.Errorf("callback cannot be nil")
@@ -358,6 +403,9 @@ func (vc *validatorCapability) Flush(ctx context.Context) error {
 	if vc == nil || vc.validatorStore == nil || vc.delegationStore == nil {
 		return ErrCapabilityNil
 	}
+	if err := vc.grant.check(vc.moduleName); err != nil {
+		return err
+	}
 
 	// Flush both validator and delegation stores
 	if err := vc.validatorStore.Flush(ctx); err != nil {
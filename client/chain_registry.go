@@ -0,0 +1,511 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+var (
+	// ErrUnknownChain is returned when a chain ID is not in the registry
+	ErrUnknownChain = errors.New("unknown chain")
+
+	// ErrChainExists is returned when registering a chain ID twice
+	ErrChainExists = errors.New("chain already registered")
+
+	// ErrInvalidChainConfig is returned for a malformed chain configuration
+	ErrInvalidChainConfig = errors.New("invalid chain config")
+)
+
+// ChainRegistryVersion is the version of the registry JSON format.
+const ChainRegistryVersion = "1"
+
+// maxGasPriceDecimals bounds the fractional digits of a gas price, keeping
+// its denominator (10^decimals) within uint64
+const maxGasPriceDecimals = 18
+
+// ChainConfig is the client-side configuration of one Punnet chain: what a
+// transaction builder or CLI needs to target it without per-command flags.
+type ChainConfig struct {
+	// ChainID is bound into every SignDoc for this chain
+	ChainID string `json:"chain_id"`
+
+	// Bech32Prefix is the human-readable part of bech32 strings shown for
+	// this chain. Empty means crypto.PubKeyBech32HRP.
+	Bech32Prefix string `json:"bech32_prefix,omitempty"`
+
+	// Denoms describes the chain's denominations for display
+	Denoms []types.DenomMetadata `json:"denoms,omitempty"`
+
+	// FeeDenom is the denomination fees are paid in; it must have a gas price
+	FeeDenom string `json:"fee_denom"`
+
+	// GasPrices are the minimum prices per unit of gas, one per denomination
+	GasPrices []GasPrice `json:"gas_prices"`
+
+	// DefaultGasLimit is used when a transaction does not set a gas limit
+	DefaultGasLimit uint64 `json:"default_gas_limit"`
+
+	// FeeSlippage is the default fee conversion slippage tolerance. The zero
+	// value means no slippage (0/1).
+	FeeSlippage types.Ratio `json:"fee_slippage"`
+
+	// Endpoints are the chain's node URLs, in order of preference
+	Endpoints []string `json:"endpoints,omitempty"`
+}
+
+// GasPrice is the price of one unit of gas in Denom, as an exact decimal
+// fraction (Price.Denominator is a power of ten).
+//
+// It is encoded in JSON as a string of a decimal amount followed by the
+// denomination, e.g. "0.025ustake" (see ParseGasPrice).
+type GasPrice struct {
+	Denom string
+	Price types.Ratio
+}
+
+// ParseGasPrice parses a gas price such as "0.025ustake" or "1stake": a
+// non-negative decimal with at most 18 fractional digits, immediately
+// followed by a denomination starting with a letter.
+func ParseGasPrice(s string) (GasPrice, error) {
+	split := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if split <= 0 {
+		return GasPrice{}, fmt.Errorf("%w: gas price %q must be an amount followed by a denomination", ErrInvalidChainConfig, s)
+	}
+	amount, denom := s[:split], s[split:]
+	if c := denom[0]; (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+		return GasPrice{}, fmt.Errorf("%w: gas price %q: denomination must start with a letter", ErrInvalidChainConfig, s)
+	}
+
+	whole, frac, _ := strings.Cut(amount, ".")
+	if whole == "" || strings.Contains(frac, ".") || len(frac) > maxGasPriceDecimals {
+		return GasPrice{}, fmt.Errorf("%w: gas price %q: invalid amount", ErrInvalidChainConfig, s)
+	}
+	numerator, err := strconv.ParseUint(whole+frac, 10, 64)
+	if err != nil {
+		return GasPrice{}, fmt.Errorf("%w: gas price %q: %v", ErrInvalidChainConfig, s, err)
+	}
+	denominator := uint64(1)
+	for range len(frac) {
+		denominator *= 10
+	}
+
+	price := GasPrice{Denom: denom, Price: types.Ratio{Numerator: numerator, Denominator: denominator}}
+	if err := price.ValidateBasic(); err != nil {
+		return GasPrice{}, err
+	}
+	return price, nil
+}
+
+// String formats the price as ParseGasPrice accepts it
+func (p GasPrice) String() string {
+	decimals := decimalPlaces(p.Price.Denominator)
+	if decimals < 0 {
+		return fmt.Sprintf("%d/%d%s", p.Price.Numerator, p.Price.Denominator, p.Denom)
+	}
+
+	whole := strconv.FormatUint(p.Price.Numerator/p.Price.Denominator, 10)
+	rem := p.Price.Numerator % p.Price.Denominator
+	if rem == 0 {
+		return whole + p.Denom
+	}
+	frac := strconv.FormatUint(rem, 10)
+	frac = strings.TrimRight(strings.Repeat("0", decimals-len(frac))+frac, "0")
+	return whole + "." + frac + p.Denom
+}
+
+// ValidateBasic checks the denomination and that the price is a decimal
+// fraction: its denominator is a power of ten up to 10^18
+func (p GasPrice) ValidateBasic() error {
+	if !(types.Coin{Denom: p.Denom}).IsValid() {
+		return fmt.Errorf("%w: gas price denomination %q", ErrInvalidChainConfig, p.Denom)
+	}
+	if decimalPlaces(p.Price.Denominator) < 0 {
+		return fmt.Errorf("%w: gas price %s: denominator %d is not a power of ten up to 10^%d",
+			ErrInvalidChainConfig, p.Denom, p.Price.Denominator, maxGasPriceDecimals)
+	}
+	return nil
+}
+
+// decimalPlaces returns k if denominator is 10^k with k <= 18, and -1
+// otherwise
+func decimalPlaces(denominator uint64) int {
+	for k, pow := 0, uint64(1); k <= maxGasPriceDecimals; k, pow = k+1, pow*10 {
+		if denominator == pow {
+			return k
+		}
+	}
+	return -1
+}
+
+// Fee returns the fee for gasLimit units of gas: gasLimit * price, rounded
+// up so the fee never falls below the minimum price.
+func (p GasPrice) Fee(gasLimit uint64) (types.Coin, error) {
+	if err := p.ValidateBasic(); err != nil {
+		return types.Coin{}, err
+	}
+
+	hi, lo := bits.Mul64(gasLimit, p.Price.Numerator)
+	if hi >= p.Price.Denominator {
+		return types.Coin{}, fmt.Errorf("fee for %d gas at %s overflows", gasLimit, p)
+	}
+	amount, rem := bits.Div64(hi, lo, p.Price.Denominator)
+	if rem != 0 {
+		if amount == ^uint64(0) {
+			return types.Coin{}, fmt.Errorf("fee for %d gas at %s overflows", gasLimit, p)
+		}
+		amount++
+	}
+	return types.NewCoin(p.Denom, amount), nil
+}
+
+// MarshalJSON encodes the price as a string, e.g. "0.025ustake"
+func (p GasPrice) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.String())
+}
+
+// UnmarshalJSON decodes a string accepted by ParseGasPrice
+func (p *GasPrice) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%w: gas price must be a string: %v", ErrInvalidChainConfig, err)
+	}
+	price, err := ParseGasPrice(s)
+	if err != nil {
+		return err
+	}
+	*p = price
+	return nil
+}
+
+// ValidateBasic checks the configuration
+func (c ChainConfig) ValidateBasic() error {
+	if err := types.ValidateChainID(c.ChainID); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidChainConfig, err)
+	}
+	if c.Bech32Prefix != "" && !isValidBech32Prefix(c.Bech32Prefix) {
+		return fmt.Errorf("%w: chain %s: invalid bech32 prefix %q", ErrInvalidChainConfig, c.ChainID, c.Bech32Prefix)
+	}
+
+	seenDenoms := make(map[string]bool, len(c.Denoms))
+	for _, d := range c.Denoms {
+		if err := d.ValidateBasic(); err != nil {
+			return fmt.Errorf("%w: chain %s: %w", ErrInvalidChainConfig, c.ChainID, err)
+		}
+		if seenDenoms[d.Base] {
+			return fmt.Errorf("%w: chain %s: duplicate denomination %s", ErrInvalidChainConfig, c.ChainID, d.Base)
+		}
+		seenDenoms[d.Base] = true
+	}
+
+	seenPrices := make(map[string]bool, len(c.GasPrices))
+	for _, p := range c.GasPrices {
+		if err := p.ValidateBasic(); err != nil {
+			return fmt.Errorf("chain %s: %w", c.ChainID, err)
+		}
+		if seenPrices[p.Denom] {
+			return fmt.Errorf("%w: chain %s: duplicate gas price for %s", ErrInvalidChainConfig, c.ChainID, p.Denom)
+		}
+		seenPrices[p.Denom] = true
+	}
+	if !seenPrices[c.FeeDenom] {
+		return fmt.Errorf("%w: chain %s: fee denomination %q has no gas price", ErrInvalidChainConfig, c.ChainID, c.FeeDenom)
+	}
+	if c.DefaultGasLimit == 0 {
+		return fmt.Errorf("%w: chain %s: default gas limit cannot be zero", ErrInvalidChainConfig, c.ChainID)
+	}
+	if c.FeeSlippage != (types.Ratio{}) {
+		if err := c.FeeSlippage.ValidateBasic(); err != nil {
+			return fmt.Errorf("%w: chain %s: fee slippage: %v", ErrInvalidChainConfig, c.ChainID, err)
+		}
+	}
+
+	for _, endpoint := range c.Endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("%w: chain %s: invalid endpoint %q", ErrInvalidChainConfig, c.ChainID, endpoint)
+		}
+	}
+	return nil
+}
+
+// Bech32HRP returns the chain's bech32 human-readable part
+func (c ChainConfig) Bech32HRP() string {
+	if c.Bech32Prefix == "" {
+		return crypto.PubKeyBech32HRP
+	}
+	return c.Bech32Prefix
+}
+
+// GasPrice returns the chain's gas price in denom
+func (c ChainConfig) GasPrice(denom string) (GasPrice, bool) {
+	for _, p := range c.GasPrices {
+		if p.Denom == denom {
+			return p, true
+		}
+	}
+	return GasPrice{}, false
+}
+
+// Denom returns the display metadata of a base denomination
+func (c ChainConfig) Denom(base string) (types.DenomMetadata, bool) {
+	for _, d := range c.Denoms {
+		if d.Base == base {
+			return d, true
+		}
+	}
+	return types.DenomMetadata{}, false
+}
+
+// FormatCoins renders coins in the chain's display units for output,
+// e.g. "1.5 PUN"; denominations without metadata are shown in base units
+func (c ChainConfig) FormatCoins(coins types.Coins) string {
+	return types.FormatCoins(coins, c.Denom)
+}
+
+// Fee returns the fee for gasLimit at the fee denomination's gas price.
+// A zero gasLimit means DefaultGasLimit.
+func (c ChainConfig) Fee(gasLimit uint64) (types.Fee, error) {
+	if gasLimit == 0 {
+		gasLimit = c.DefaultGasLimit
+	}
+	price, ok := c.GasPrice(c.FeeDenom)
+	if !ok {
+		return types.Fee{}, fmt.Errorf("%w: chain %s: fee denomination %q has no gas price", ErrInvalidChainConfig, c.ChainID, c.FeeDenom)
+	}
+	coin, err := price.Fee(gasLimit)
+	if err != nil {
+		return types.Fee{}, err
+	}
+	return types.Fee{Amount: types.NewCoins(coin), GasLimit: gasLimit}, nil
+}
+
+// NewTransaction returns an unsigned transaction for this chain with the
+// default fee (DefaultGasLimit at the fee denomination's price) and fee
+// slippage. Sign it with the SignDoc from tx.ToSignDoc(c.ChainID, nonce).
+func (c ChainConfig) NewTransaction(account types.AccountName, nonce uint64, msgs []types.Message) (*types.Transaction, error) {
+	fee, err := c.Fee(0)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := types.NewTransaction(account, nonce, msgs, types.NewAuthorization())
+	tx.Fee = fee
+	tx.FeeSlippage = c.FeeSlippage
+	if tx.FeeSlippage == (types.Ratio{}) {
+		tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+	}
+	return tx, nil
+}
+
+// isValidBech32Prefix reports whether hrp is a valid lowercase BIP-173
+// human-readable part
+func isValidBech32Prefix(hrp string) bool {
+	if len(hrp) == 0 || len(hrp) > 83 {
+		return false
+	}
+	for i := 0; i < len(hrp); i++ {
+		c := hrp[i]
+		if c < 33 || c > 126 || (c >= 'A' && c <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// ChainRegistry holds the configurations of the chains a client targets,
+// keyed by chain ID, and an optional default chain.
+//
+// CONCURRENCY: Safe for concurrent use.
+type ChainRegistry struct {
+	mu           sync.RWMutex
+	chains       map[string]ChainConfig
+	defaultChain string
+}
+
+// chainRegistryFile is the JSON form of a ChainRegistry
+type chainRegistryFile struct {
+	Version      string        `json:"version"`
+	DefaultChain string        `json:"default_chain,omitempty"`
+	Chains       []ChainConfig `json:"chains"`
+}
+
+// NewChainRegistry creates an empty chain registry
+func NewChainRegistry() *ChainRegistry {
+	return &ChainRegistry{chains: make(map[string]ChainConfig)}
+}
+
+// LoadChainRegistry decodes a registry from JSON:
+//
+//	{
+//	  "version": "1",
+//	  "default_chain": "punnet-1",
+//	  "chains": [{
+//	    "chain_id": "punnet-1",
+//	    "denoms": [{"base": "ustake", "display": "STAKE", "exponent": 6}],
+//	    "fee_denom": "ustake",
+//	    "gas_prices": ["0.025ustake"],
+//	    "default_gas_limit": 200000,
+//	    "fee_slippage": {"numerator": 1, "denominator": 100},
+//	    "endpoints": ["https://rpc.punnet.example"]
+//	  }]
+//	}
+//
+// Unknown fields are rejected so a misspelled setting is not silently
+// ignored. Every chain is validated.
+func LoadChainRegistry(r io.Reader) (*ChainRegistry, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var file chainRegistryFile
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to decode chain registry: %w", err)
+	}
+	if file.Version != ChainRegistryVersion {
+		return nil, fmt.Errorf("unsupported chain registry version %q", file.Version)
+	}
+
+	registry := NewChainRegistry()
+	for _, cfg := range file.Chains {
+		if err := registry.Register(cfg); err != nil {
+			return nil, err
+		}
+	}
+	if file.DefaultChain != "" {
+		if err := registry.SetDefault(file.DefaultChain); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// LoadChainRegistryFile reads a registry from a JSON file
+func LoadChainRegistryFile(path string) (*ChainRegistry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chain registry: %w", err)
+	}
+	return LoadChainRegistry(bytes.NewReader(data))
+}
+
+// WriteJSON encodes the registry in the format read by LoadChainRegistry,
+// with chains sorted by ID
+func (r *ChainRegistry) WriteJSON(w io.Writer) error {
+	r.mu.RLock()
+	file := chainRegistryFile{
+		Version:      ChainRegistryVersion,
+		DefaultChain: r.defaultChain,
+		Chains:       make([]ChainConfig, 0, len(r.chains)),
+	}
+	for _, id := range r.chainIDsLocked() {
+		file.Chains = append(file.Chains, r.chains[id])
+	}
+	r.mu.RUnlock()
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// Register validates and adds a chain. Returns ErrChainExists if the chain
+// ID is already registered.
+func (r *ChainRegistry) Register(cfg ChainConfig) error {
+	if err := cfg.ValidateBasic(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.chains[cfg.ChainID]; exists {
+		return fmt.Errorf("%w: %s", ErrChainExists, cfg.ChainID)
+	}
+	r.chains[cfg.ChainID] = cloneChainConfig(cfg)
+	return nil
+}
+
+// Get returns the configuration of chainID, or ErrUnknownChain
+func (r *ChainRegistry) Get(chainID string) (ChainConfig, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cfg, ok := r.chains[chainID]
+	if !ok {
+		return ChainConfig{}, fmt.Errorf("%w: %s", ErrUnknownChain, chainID)
+	}
+	return cloneChainConfig(cfg), nil
+}
+
+// Resolve returns the configuration of chainID, or of the default chain if
+// chainID is empty. It is the lookup behind a CLI's optional --chain-id flag.
+func (r *ChainRegistry) Resolve(chainID string) (ChainConfig, error) {
+	if chainID != "" {
+		return r.Get(chainID)
+	}
+
+	r.mu.RLock()
+	defaultChain := r.defaultChain
+	r.mu.RUnlock()
+	if defaultChain == "" {
+		return ChainConfig{}, fmt.Errorf("%w: no chain ID given and no default chain set", ErrUnknownChain)
+	}
+	return r.Get(defaultChain)
+}
+
+// SetDefault makes chainID the chain used by Resolve when none is given
+func (r *ChainRegistry) SetDefault(chainID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.chains[chainID]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownChain, chainID)
+	}
+	r.defaultChain = chainID
+	return nil
+}
+
+// ChainIDs returns the registered chain IDs in sorted order
+func (r *ChainRegistry) ChainIDs() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.chainIDsLocked()
+}
+
+// chainIDsLocked returns the sorted chain IDs
+//
+// PRECONDITION: r.mu is held
+func (r *ChainRegistry) chainIDsLocked() []string {
+	ids := make([]string, 0, len(r.chains))
+	for id := range r.chains {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// cloneChainConfig copies cfg's slices so callers cannot change the
+// registry's copy
+func cloneChainConfig(cfg ChainConfig) ChainConfig {
+	cfg.Denoms = append([]types.DenomMetadata(nil), cfg.Denoms...)
+	cfg.GasPrices = append([]GasPrice(nil), cfg.GasPrices...)
+	cfg.Endpoints = append([]string(nil), cfg.Endpoints...)
+	return cfg
+}
@@ -0,0 +1,212 @@
+package client
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+const testRegistryJSON = `{
+  "version": "1",
+  "default_chain": "punnet-1",
+  "chains": [
+    {
+      "chain_id": "punnet-1",
+      "bech32_prefix": "punnet",
+      "denoms": [{"base": "ustake", "display": "STAKE", "exponent": 6}],
+      "fee_denom": "ustake",
+      "gas_prices": ["0.025ustake", "1uatom"],
+      "default_gas_limit": 200000,
+      "fee_slippage": {"numerator": 1, "denominator": 100},
+      "endpoints": ["https://rpc.punnet.example"]
+    },
+    {
+      "chain_id": "punnet-testnet",
+      "fee_denom": "stake",
+      "gas_prices": ["0stake"],
+      "default_gas_limit": 100000,
+      "fee_slippage": {"numerator": 0, "denominator": 0}
+    }
+  ]
+}`
+
+func TestParseGasPrice(t *testing.T) {
+	tests := []struct {
+		in          string
+		numerator   uint64
+		denominator uint64
+		denom       string
+		str         string
+	}{
+		{"0.025ustake", 25, 1000, "ustake", "0.025ustake"},
+		{"1stake", 1, 1, "stake", "1stake"},
+		{"10.50stake", 1050, 100, "stake", "10.5stake"},
+		{"0.000000000000000001a", 1, 1_000_000_000_000_000_000, "a", "0.000000000000000001a"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			price, err := ParseGasPrice(tc.in)
+			require.NoError(t, err)
+			require.Equal(t, tc.denom, price.Denom)
+			require.Equal(t, types.Ratio{Numerator: tc.numerator, Denominator: tc.denominator}, price.Price)
+			require.Equal(t, tc.str, price.String())
+		})
+	}
+
+	for _, bad := range []string{"", "stake", "0.025", ".5stake", "1.2.3stake", "1.0000000000000000001stake", "99999999999999999999stake", "1-stake"} {
+		_, err := ParseGasPrice(bad)
+		require.ErrorIs(t, err, ErrInvalidChainConfig, bad)
+	}
+}
+
+func TestGasPrice_Fee(t *testing.T) {
+	price, err := ParseGasPrice("0.025ustake")
+	require.NoError(t, err)
+
+	fee, err := price.Fee(200000)
+	require.NoError(t, err)
+	require.Equal(t, types.NewCoin("ustake", 5000), fee)
+
+	// Fractional fees round up
+	fee, err = price.Fee(1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), fee.Amount)
+
+	big, err := ParseGasPrice("2stake")
+	require.NoError(t, err)
+	_, err = big.Fee(^uint64(0))
+	require.Error(t, err)
+}
+
+func TestLoadChainRegistry(t *testing.T) {
+	registry, err := LoadChainRegistry(strings.NewReader(testRegistryJSON))
+	require.NoError(t, err)
+	require.Equal(t, []string{"punnet-1", "punnet-testnet"}, registry.ChainIDs())
+
+	cfg, err := registry.Resolve("")
+	require.NoError(t, err)
+	require.Equal(t, "punnet-1", cfg.ChainID)
+	require.Equal(t, "punnet", cfg.Bech32HRP())
+	denom, ok := cfg.Denom("ustake")
+	require.True(t, ok)
+	require.Equal(t, uint32(6), denom.Exponent)
+	require.Equal(t, "1.5 STAKE, 3uatom", cfg.FormatCoins(types.NewCoins(types.NewCoin("ustake", 1500000), types.NewCoin("uatom", 3))))
+
+	testnet, err := registry.Resolve("punnet-testnet")
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubKeyBech32HRP, testnet.Bech32HRP())
+
+	_, err = registry.Resolve("other")
+	require.ErrorIs(t, err, ErrUnknownChain)
+
+	// The registry round-trips through its JSON form
+	var buf bytes.Buffer
+	require.NoError(t, registry.WriteJSON(&buf))
+	path := filepath.Join(t.TempDir(), "chains.json")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+	reloaded, err := LoadChainRegistryFile(path)
+	require.NoError(t, err)
+	reloadedCfg, err := reloaded.Resolve("")
+	require.NoError(t, err)
+	require.Equal(t, cfg, reloadedCfg)
+}
+
+func TestLoadChainRegistry_Rejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		replace [2]string
+	}{
+		{"unknown field", [2]string{`"bech32_prefix"`, `"bech32_prefx"`}},
+		{"version", [2]string{`"version": "1"`, `"version": "2"`}},
+		{"unknown default", [2]string{`"default_chain": "punnet-1"`, `"default_chain": "other"`}},
+		{"duplicate chain", [2]string{`"punnet-testnet"`, `"punnet-1"`}},
+		{"fee denom without price", [2]string{`"fee_denom": "ustake"`, `"fee_denom": "uother"`}},
+		{"zero gas limit", [2]string{`"default_gas_limit": 200000`, `"default_gas_limit": 0`}},
+		{"bad endpoint", [2]string{`https://rpc.punnet.example`, `rpc.punnet.example`}},
+		{"bad prefix", [2]string{`"bech32_prefix": "punnet"`, `"bech32_prefix": "Punnet"`}},
+		{"duplicate gas price", [2]string{`"1uatom"`, `"1ustake"`}},
+		{"bad denom exponent", [2]string{`"exponent": 6`, `"exponent": 20`}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			data := strings.Replace(testRegistryJSON, tc.replace[0], tc.replace[1], 1)
+			require.NotEqual(t, testRegistryJSON, data)
+			_, err := LoadChainRegistry(strings.NewReader(data))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestChainRegistry_Register(t *testing.T) {
+	registry := NewChainRegistry()
+	cfg := ChainConfig{
+		ChainID:         "punnet-1",
+		FeeDenom:        "stake",
+		GasPrices:       []GasPrice{{Denom: "stake", Price: types.Ratio{Numerator: 1, Denominator: 10}}},
+		DefaultGasLimit: 1000,
+	}
+	require.NoError(t, registry.Register(cfg))
+	require.ErrorIs(t, registry.Register(cfg), ErrChainExists)
+
+	_, err := registry.Resolve("")
+	require.ErrorIs(t, err, ErrUnknownChain, "no default chain")
+	require.ErrorIs(t, registry.SetDefault("other"), ErrUnknownChain)
+
+	// Returned configs are copies
+	got, err := registry.Get("punnet-1")
+	require.NoError(t, err)
+	got.GasPrices[0].Denom = "changed"
+	got, err = registry.Get("punnet-1")
+	require.NoError(t, err)
+	require.Equal(t, "stake", got.GasPrices[0].Denom)
+
+	cfg.ChainID = "punnet-2"
+	cfg.GasPrices[0].Price.Denominator = 3
+	require.ErrorIs(t, registry.Register(cfg), ErrInvalidChainConfig, "non-decimal gas price")
+}
+
+func TestChainConfig_NewTransaction(t *testing.T) {
+	registry, err := LoadChainRegistry(strings.NewReader(testRegistryJSON))
+	require.NoError(t, err)
+	cfg, err := registry.Resolve("")
+	require.NoError(t, err)
+
+	msg := &testSendMessage{From: "alice"}
+	tx, err := cfg.NewTransaction("alice", 4, []types.Message{msg})
+	require.NoError(t, err)
+	require.Equal(t, types.Fee{Amount: types.NewCoins(types.NewCoin("ustake", 5000)), GasLimit: 200000}, tx.Fee)
+	require.Equal(t, types.Ratio{Numerator: 1, Denominator: 100}, tx.FeeSlippage)
+
+	signDoc, err := tx.ToSignDoc(cfg.ChainID, tx.Nonce)
+	require.NoError(t, err)
+	require.Equal(t, "punnet-1", signDoc.ChainID)
+	require.Equal(t, "5000", signDoc.Fee.Amount[0].Amount)
+
+	fee, err := cfg.Fee(400000)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10000), fee.Amount[0].Amount)
+
+	// A chain without slippage gets an explicit 0/1
+	testnet, err := registry.Get("punnet-testnet")
+	require.NoError(t, err)
+	tx, err = testnet.NewTransaction("alice", 0, []types.Message{msg})
+	require.NoError(t, err)
+	require.Equal(t, types.Ratio{Numerator: 0, Denominator: 1}, tx.FeeSlippage)
+	require.NoError(t, tx.ValidateBasic())
+}
+
+// testSendMessage is a minimal types.Message
+type testSendMessage struct {
+	From types.AccountName `json:"from"`
+}
+
+func (m *testSendMessage) Type() string                    { return "/test.send" }
+func (m *testSendMessage) ValidateBasic() error            { return nil }
+func (m *testSendMessage) GetSigners() []types.AccountName { return []types.AccountName{m.From} }
@@ -0,0 +1,388 @@
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// DefaultStuckAfter is how long the next transaction the chain expects from
+// an account may stay uncommitted before PendingTracker reports it stuck
+const DefaultStuckAfter = time.Minute
+
+var (
+	// ErrUnknownTx is returned for a transaction hash the tracker does not hold
+	ErrUnknownTx = errors.New("unknown pending transaction")
+
+	// ErrFeeNotHigher is returned when a replacement fee does not exceed the
+	// fee of the transaction it replaces
+	ErrFeeNotHigher = errors.New("replacement fee not higher")
+)
+
+// Broadcaster submits a signed transaction to the chain's mempool
+type Broadcaster interface {
+	BroadcastTx(ctx context.Context, tx *types.Transaction) error
+}
+
+// TxResigner signs a copy of a transaction with a new fee at the same nonces.
+// *TxBuilder implements it.
+type TxResigner interface {
+	Resign(tx *types.Transaction, fee types.Fee) (*types.Transaction, error)
+}
+
+// PendingStatus is the state of a tracked transaction
+type PendingStatus string
+
+// Pending statuses
+const (
+	// PendingStatusPending transactions are broadcast and not yet committed
+	PendingStatusPending PendingStatus = "pending"
+
+	// PendingStatusReplaced transactions were replaced by a higher fee one
+	// at the same nonce
+	PendingStatusReplaced PendingStatus = "replaced"
+
+	// PendingStatusCommitted transactions hold a nonce the chain has passed
+	PendingStatusCommitted PendingStatus = "committed"
+)
+
+// PendingTx is a transaction tracked by a PendingTracker
+type PendingTx struct {
+	// Hash is the hex encoded TxHash of the transaction
+	Hash string `json:"hash"`
+
+	// Account is the transaction's account
+	Account types.AccountName `json:"account"`
+
+	// Nonce is the account's nonce in the transaction
+	Nonce uint64 `json:"nonce"`
+
+	// Fee is the transaction's fee
+	Fee types.Fee `json:"fee"`
+
+	// BroadcastAt is when the transaction was tracked
+	BroadcastAt time.Time `json:"broadcast_at"`
+
+	// Status is the transaction's state
+	Status PendingStatus `json:"status"`
+
+	// ReplacedBy is the hash of the replacing transaction, if Status is
+	// PendingStatusReplaced
+	ReplacedBy string `json:"replaced_by,omitempty"`
+
+	// Tx is the transaction
+	Tx *types.Transaction `json:"-"`
+}
+
+// NonceReport is the result of PendingTracker.Sync for one account
+type NonceReport struct {
+	// Account is the reported account
+	Account types.AccountName `json:"account"`
+
+	// ChainNonce is the next nonce the chain expects from Account
+	ChainNonce uint64 `json:"chain_nonce"`
+
+	// Committed are the transactions whose nonce the chain has passed,
+	// sorted by nonce. With replacements, the chain may have included the
+	// replaced transaction instead; only the latest one is listed.
+	Committed []PendingTx `json:"committed,omitempty"`
+
+	// Pending are the uncommitted transactions, sorted by nonce
+	Pending []PendingTx `json:"pending,omitempty"`
+
+	// Gaps are the nonces from ChainNonce up to the highest pending nonce
+	// that no pending transaction holds. Transactions above a gap cannot
+	// execute until it is filled.
+	Gaps []uint64 `json:"gaps,omitempty"`
+
+	// Stuck are the pending transactions that cannot make progress: the one
+	// at ChainNonce once it is older than the tracker's stuck timeout, and
+	// every one above a gap
+	Stuck []PendingTx `json:"stuck,omitempty"`
+}
+
+// PendingTracker follows broadcast transactions by hash until the chain
+// commits their nonces, the way nonce managers of Ethereum wallets do. It
+// reports nonce gaps and stuck transactions, and replaces a pending
+// transaction with a higher fee one at the same nonce.
+//
+// Only the transaction's Account nonce is tracked; co-signer nonces are not.
+//
+// Safe for concurrent use.
+type PendingTracker struct {
+	mu          sync.Mutex
+	sequences   SequenceSource
+	broadcaster Broadcaster
+	resigner    TxResigner
+	stuckAfter  time.Duration
+
+	// now returns the current time; replaced in tests
+	now func() time.Time
+
+	// txs holds the tracked transactions by hash
+	txs map[string]*PendingTx
+
+	// active maps each account's nonces to the hash of the transaction
+	// currently pending at the nonce
+	active map[types.AccountName]map[uint64]string
+}
+
+// NewPendingTracker creates a pending tracker reading chain nonces from
+// sequences (e.g. a *QueryClient), broadcasting with broadcaster and
+// re-signing replacements with resigner (e.g. a *TxBuilder; nil disables
+// ReplaceWithHigherFee). stuckAfter defaults to DefaultStuckAfter.
+func NewPendingTracker(sequences SequenceSource, broadcaster Broadcaster, resigner TxResigner, stuckAfter time.Duration) (*PendingTracker, error) {
+	if sequences == nil {
+		return nil, fmt.Errorf("sequence source cannot be nil")
+	}
+	if broadcaster == nil {
+		return nil, fmt.Errorf("broadcaster cannot be nil")
+	}
+	if stuckAfter < 0 {
+		return nil, fmt.Errorf("stuck timeout cannot be negative")
+	}
+	if stuckAfter == 0 {
+		stuckAfter = DefaultStuckAfter
+	}
+
+	return &PendingTracker{
+		sequences:   sequences,
+		broadcaster: broadcaster,
+		resigner:    resigner,
+		stuckAfter:  stuckAfter,
+		now:         time.Now,
+		txs:         make(map[string]*PendingTx),
+		active:      make(map[types.AccountName]map[uint64]string),
+	}, nil
+}
+
+// Broadcast broadcasts tx and tracks it, returning its hash. A transaction
+// the broadcaster rejects is not tracked.
+func (t *PendingTracker) Broadcast(ctx context.Context, tx *types.Transaction) (string, error) {
+	if t == nil {
+		return "", fmt.Errorf("pending tracker is nil")
+	}
+
+	hash, err := pendingHash(tx)
+	if err != nil {
+		return "", err
+	}
+	if err := t.broadcaster.BroadcastTx(ctx, tx); err != nil {
+		return "", fmt.Errorf("failed to broadcast %s: %w", hash, err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.trackLocked(hash, tx)
+	return hash, nil
+}
+
+// Track tracks tx, broadcast by other means, and returns its hash. A
+// transaction already pending at the same nonce is marked replaced by it.
+func (t *PendingTracker) Track(tx *types.Transaction) (string, error) {
+	if t == nil {
+		return "", fmt.Errorf("pending tracker is nil")
+	}
+
+	hash, err := pendingHash(tx)
+	if err != nil {
+		return "", err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.trackLocked(hash, tx)
+	return hash, nil
+}
+
+// Get returns the tracked transaction with the given hash
+func (t *PendingTracker) Get(hash string) (PendingTx, bool) {
+	if t == nil {
+		return PendingTx{}, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ptx, ok := t.txs[hash]
+	if !ok {
+		return PendingTx{}, false
+	}
+	return *ptx, true
+}
+
+// Pending returns the pending transactions of account, sorted by nonce
+func (t *PendingTracker) Pending(account types.AccountName) []PendingTx {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.pendingLocked(account)
+}
+
+// Sync reads the chain nonce of account, stops tracking the transactions
+// below it and reports the account's committed, pending, gapped and stuck
+// transactions.
+func (t *PendingTracker) Sync(ctx context.Context, account types.AccountName) (*NonceReport, error) {
+	if t == nil {
+		return nil, fmt.Errorf("pending tracker is nil")
+	}
+
+	chainNonce, err := t.sequences.GetSequence(ctx, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sequence of %s: %w", account, err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	report := &NonceReport{Account: account, ChainNonce: chainNonce}
+
+	// Drop every transaction below the chain nonce, replaced ones included
+	for hash, ptx := range t.txs {
+		if ptx.Account != account || ptx.Nonce >= chainNonce {
+			continue
+		}
+		if ptx.Status == PendingStatusPending {
+			ptx.Status = PendingStatusCommitted
+			report.Committed = append(report.Committed, *ptx)
+			delete(t.active[account], ptx.Nonce)
+		}
+		delete(t.txs, hash)
+	}
+	if len(t.active[account]) == 0 {
+		delete(t.active, account)
+	}
+	sort.Slice(report.Committed, func(i, j int) bool { return report.Committed[i].Nonce < report.Committed[j].Nonce })
+
+	report.Pending = t.pendingLocked(account)
+	next := chainNonce
+	gapped := false
+	now := t.now()
+	for _, ptx := range report.Pending {
+		for ; next < ptx.Nonce; next++ {
+			report.Gaps = append(report.Gaps, next)
+			gapped = true
+		}
+		next = ptx.Nonce + 1
+
+		head := ptx.Nonce == chainNonce && now.Sub(ptx.BroadcastAt) >= t.stuckAfter
+		if gapped || head {
+			report.Stuck = append(report.Stuck, ptx)
+		}
+	}
+	return report, nil
+}
+
+// ReplaceWithHigherFee re-signs the pending transaction hash with fee at the
+// same nonce, broadcasts the replacement and tracks it in its place,
+// returning it. fee.Amount must not lower any coin of the original fee and
+// must raise at least one (ErrFeeNotHigher otherwise); the gas limit may
+// change freely.
+//
+// Whether the mempool accepts the replacement is up to the node; if the
+// broadcaster rejects it, the original stays pending.
+func (t *PendingTracker) ReplaceWithHigherFee(ctx context.Context, hash string, fee types.Fee) (PendingTx, error) {
+	if t == nil {
+		return PendingTx{}, fmt.Errorf("pending tracker is nil")
+	}
+	if t.resigner == nil {
+		return PendingTx{}, fmt.Errorf("pending tracker has no resigner")
+	}
+
+	t.mu.Lock()
+	original, ok := t.txs[hash]
+	var tx *types.Transaction
+	var oldFee types.Fee
+	if ok {
+		tx, oldFee = original.Tx, original.Fee
+		ok = original.Status == PendingStatusPending
+	}
+	t.mu.Unlock()
+	if !ok {
+		return PendingTx{}, fmt.Errorf("%w: %s", ErrUnknownTx, hash)
+	}
+
+	if !feeHigher(fee.Amount, oldFee.Amount) {
+		return PendingTx{}, fmt.Errorf("%w: %s does not exceed %s", ErrFeeNotHigher, fee.Amount, oldFee.Amount)
+	}
+
+	replacement, err := t.resigner.Resign(tx, fee)
+	if err != nil {
+		return PendingTx{}, fmt.Errorf("failed to re-sign %s: %w", hash, err)
+	}
+	newHash, err := t.Broadcast(ctx, replacement)
+	if err != nil {
+		return PendingTx{}, err
+	}
+
+	replaced, _ := t.Get(newHash)
+	return replaced, nil
+}
+
+// trackLocked records tx under hash as the pending transaction at its
+// nonce, marking the one it displaces replaced.
+// PRECONDITION: t.mu is held.
+func (t *PendingTracker) trackLocked(hash string, tx *types.Transaction) {
+	nonces := t.active[tx.Account]
+	if nonces == nil {
+		nonces = make(map[uint64]string)
+		t.active[tx.Account] = nonces
+	}
+	if previous, ok := nonces[tx.Nonce]; ok && previous != hash {
+		if ptx := t.txs[previous]; ptx != nil {
+			ptx.Status = PendingStatusReplaced
+			ptx.ReplacedBy = hash
+		}
+	}
+	nonces[tx.Nonce] = hash
+
+	t.txs[hash] = &PendingTx{
+		Hash:        hash,
+		Account:     tx.Account,
+		Nonce:       tx.Nonce,
+		Fee:         tx.Fee,
+		BroadcastAt: t.now(),
+		Status:      PendingStatusPending,
+		Tx:          tx,
+	}
+}
+
+// pendingLocked returns the pending transactions of account, sorted by nonce.
+// PRECONDITION: t.mu is held.
+func (t *PendingTracker) pendingLocked(account types.AccountName) []PendingTx {
+	var pending []PendingTx
+	for _, hash := range t.active[account] {
+		pending = append(pending, *t.txs[hash])
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Nonce < pending[j].Nonce })
+	return pending
+}
+
+// pendingHash returns the hex encoded TxHash of tx
+func pendingHash(tx *types.Transaction) (string, error) {
+	if tx == nil {
+		return "", fmt.Errorf("%w: transaction is nil", types.ErrInvalidTransaction)
+	}
+	hash, err := tx.TxHash()
+	if err != nil {
+		return "", fmt.Errorf("failed to hash transaction: %w", err)
+	}
+	return hex.EncodeToString(hash), nil
+}
+
+// feeHigher reports whether fee lowers no coin of old and raises at least one
+func feeHigher(fee, old types.Coins) bool {
+	return fee.IsAllGTE(old) && !old.IsAllGTE(fee)
+}
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// recordingBroadcaster records broadcast transactions and fails with err
+type recordingBroadcaster struct {
+	txs []*types.Transaction
+	err error
+}
+
+func (b *recordingBroadcaster) BroadcastTx(ctx context.Context, tx *types.Transaction) error {
+	if b.err != nil {
+		return b.err
+	}
+	b.txs = append(b.txs, tx)
+	return nil
+}
+
+// newPendingTestTracker returns a tracker signing for alice and bob, whose
+// clock is advanced through the returned pointer
+func newPendingTestTracker(t *testing.T, sequences chainSequences) (*PendingTracker, *TxBuilder, *recordingBroadcaster, *time.Time, accountMap) {
+	t.Helper()
+	kr, accounts := newBuilderKeyring(t, sequences)
+	builder, err := NewTxBuilder("punnet-1", sequences, kr, nil)
+	require.NoError(t, err)
+
+	broadcaster := &recordingBroadcaster{}
+	tracker, err := NewPendingTracker(sequences, broadcaster, builder, 10*time.Second)
+	require.NoError(t, err)
+	now := time.Unix(1_700_000_000, 0)
+	tracker.now = func() time.Time { return now }
+	return tracker, builder, broadcaster, &now, accounts
+}
+
+// buildAt builds a transfer from alice at nonce
+func buildAt(t *testing.T, builder *TxBuilder, nonce uint64, fee uint64) *types.Transaction {
+	t.Helper()
+	tx := types.NewTransaction("alice", nonce, []types.Message{send("alice", "bob")}, nil)
+	tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+	resigned, err := builder.Resign(tx, types.Fee{Amount: types.NewCoins(types.NewCoin("stake", fee)), GasLimit: 100_000})
+	require.NoError(t, err)
+	return resigned
+}
+
+func TestNewPendingTracker(t *testing.T) {
+	_, err := NewPendingTracker(nil, &recordingBroadcaster{}, nil, 0)
+	require.Error(t, err)
+	_, err = NewPendingTracker(chainSequences{}, nil, nil, 0)
+	require.Error(t, err)
+	_, err = NewPendingTracker(chainSequences{}, &recordingBroadcaster{}, nil, -time.Second)
+	require.Error(t, err)
+
+	tracker, err := NewPendingTracker(chainSequences{}, &recordingBroadcaster{}, nil, 0)
+	require.NoError(t, err)
+	require.Equal(t, DefaultStuckAfter, tracker.stuckAfter)
+}
+
+func TestPendingTracker_Sync(t *testing.T) {
+	sequences := chainSequences{"alice": 5}
+	tracker, builder, broadcaster, now, _ := newPendingTestTracker(t, sequences)
+	ctx := context.Background()
+
+	// Nonces 5, 6 and 8 are broadcast; 7 is missing
+	var hashes []string
+	for _, nonce := range []uint64{5, 6, 8} {
+		hash, err := tracker.Broadcast(ctx, buildAt(t, builder, nonce, 10))
+		require.NoError(t, err)
+		hashes = append(hashes, hash)
+	}
+	require.Len(t, broadcaster.txs, 3)
+
+	report, err := tracker.Sync(ctx, "alice")
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), report.ChainNonce)
+	require.Empty(t, report.Committed)
+	require.Len(t, report.Pending, 3)
+	require.Equal(t, []uint64{7}, report.Gaps)
+	require.Len(t, report.Stuck, 1)
+	require.Equal(t, hashes[2], report.Stuck[0].Hash)
+
+	// The chain commits 5; 6 now heads the queue and times out
+	sequences["alice"] = 6
+	*now = now.Add(10 * time.Second)
+	report, err = tracker.Sync(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, report.Committed, 1)
+	require.Equal(t, hashes[0], report.Committed[0].Hash)
+	require.Equal(t, PendingStatusCommitted, report.Committed[0].Status)
+	require.Equal(t, []uint64{7}, report.Gaps)
+	require.Len(t, report.Stuck, 2)
+	require.Equal(t, []uint64{6, 8}, []uint64{report.Stuck[0].Nonce, report.Stuck[1].Nonce})
+
+	_, ok := tracker.Get(hashes[0])
+	require.False(t, ok, "committed transactions are no longer tracked")
+
+	// Filling the gap clears it
+	_, err = tracker.Broadcast(ctx, buildAt(t, builder, 7, 10))
+	require.NoError(t, err)
+	report, err = tracker.Sync(ctx, "alice")
+	require.NoError(t, err)
+	require.Empty(t, report.Gaps)
+	require.Len(t, report.Stuck, 1)
+	require.Equal(t, uint64(6), report.Stuck[0].Nonce)
+
+	sequences["alice"] = 9
+	report, err = tracker.Sync(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, report.Committed, 3)
+	require.Empty(t, report.Pending)
+	require.Empty(t, tracker.Pending("alice"))
+}
+
+func TestPendingTracker_ReplaceWithHigherFee(t *testing.T) {
+	sequences := chainSequences{"alice": 5}
+	tracker, builder, broadcaster, _, accounts := newPendingTestTracker(t, sequences)
+	ctx := context.Background()
+
+	hash, err := tracker.Broadcast(ctx, buildAt(t, builder, 5, 10))
+	require.NoError(t, err)
+
+	sameFee := types.Fee{Amount: types.NewCoins(types.NewCoin("stake", 10)), GasLimit: 200_000}
+	_, err = tracker.ReplaceWithHigherFee(ctx, hash, sameFee)
+	require.ErrorIs(t, err, ErrFeeNotHigher)
+
+	_, err = tracker.ReplaceWithHigherFee(ctx, "unknown", sameFee)
+	require.ErrorIs(t, err, ErrUnknownTx)
+
+	// A rejected replacement leaves the original pending
+	broadcaster.err = errors.New("mempool full")
+	higher := types.Fee{Amount: types.NewCoins(types.NewCoin("stake", 20)), GasLimit: 100_000}
+	_, err = tracker.ReplaceWithHigherFee(ctx, hash, higher)
+	require.Error(t, err)
+	original, ok := tracker.Get(hash)
+	require.True(t, ok)
+	require.Equal(t, PendingStatusPending, original.Status)
+
+	broadcaster.err = nil
+	replacement, err := tracker.ReplaceWithHigherFee(ctx, hash, higher)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), replacement.Nonce)
+	require.Equal(t, higher, replacement.Fee)
+	require.NoError(t, replacement.Tx.VerifyAuthorization("punnet-1", accounts["alice"], accounts))
+
+	original, ok = tracker.Get(hash)
+	require.True(t, ok)
+	require.Equal(t, PendingStatusReplaced, original.Status)
+	require.Equal(t, replacement.Hash, original.ReplacedBy)
+
+	pending := tracker.Pending("alice")
+	require.Len(t, pending, 1)
+	require.Equal(t, replacement.Hash, pending[0].Hash)
+
+	// Replaced transactions cannot be replaced again
+	_, err = tracker.ReplaceWithHigherFee(ctx, hash, types.Fee{Amount: types.NewCoins(types.NewCoin("stake", 30))})
+	require.ErrorIs(t, err, ErrUnknownTx)
+
+	// Committing the nonce drops both
+	sequences["alice"] = 6
+	report, err := tracker.Sync(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, report.Committed, 1)
+	require.Equal(t, replacement.Hash, report.Committed[0].Hash)
+	_, ok = tracker.Get(hash)
+	require.False(t, ok)
+}
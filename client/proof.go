@@ -0,0 +1,221 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/modules/bank"
+	"github.com/blockberries/punnet-sdk/query"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// ErrProofRequired is returned when a query client requiring proofs is asked
+// for a result it cannot verify
+var ErrProofRequired = errors.New("query result cannot be proven")
+
+// ProofVerifier verifies the result of a proof query for key at
+// query.StoreKeyPath against a trusted header. *light.Client implements it.
+type ProofVerifier interface {
+	VerifyQueryResult(ctx context.Context, key []byte, result *types.QueryResult) error
+}
+
+// QueryClientOption configures a QueryClient
+type QueryClientOption func(*QueryClient)
+
+// WithProofVerifier makes the client request a Merkle proof for every state
+// read it can express as store key reads (GetStoreKey, GetBalance,
+// GetSequence and GetDenomMetadata) and verify it with verifier, e.g. a
+// *light.Client following the chain.
+func WithProofVerifier(verifier ProofVerifier) QueryClientOption {
+	return func(c *QueryClient) {
+		c.verifier = verifier
+	}
+}
+
+// WithRequireProofs makes the client refuse, with ErrProofRequired, every
+// query whose result it cannot verify: handler queries, batches and store
+// key reads without a proof. Requires WithProofVerifier.
+//
+// SECURITY: Without it, results of handler queries are taken on the word of
+// the node. Integrations that move funds based on query results (exchanges,
+// bridges) should require proofs so a malicious RPC endpoint cannot fool
+// them.
+func WithRequireProofs() QueryClientOption {
+	return func(c *QueryClient) {
+		c.requireProofs = true
+	}
+}
+
+// GetStoreKey returns the raw value of key in the state store at height (0
+// for the latest height), or nil if key is absent, together with the height
+// it was read at. With a proof verifier, the value (or its absence) is
+// proven against the verified header at that height.
+func (c *QueryClient) GetStoreKey(ctx context.Context, key []byte, height int64) ([]byte, uint64, error) {
+	if c == nil {
+		return nil, 0, fmt.Errorf("query client is nil")
+	}
+	if len(key) == 0 {
+		return nil, 0, fmt.Errorf("store key cannot be empty")
+	}
+
+	result, err := c.Query(ctx, query.Request{Path: query.StoreKeyPath, Data: key, Height: height, Prove: c.verifier != nil})
+	if err != nil {
+		return nil, 0, err
+	}
+	return result.Data, result.Height, nil
+}
+
+// checkProvable returns ErrProofRequired if the client requires proofs and
+// the result of req cannot be verified
+func (c *QueryClient) checkProvable(req query.Request) error {
+	if !c.requireProofs || (req.Path == query.StoreKeyPath && req.Prove) {
+		return nil
+	}
+	return fmt.Errorf("%w: path %s", ErrProofRequired, req.Path)
+}
+
+// verify checks the proof of the result of req, if req asked for one
+func (c *QueryClient) verify(ctx context.Context, req query.Request, result *types.QueryResult) error {
+	if c.verifier == nil || req.Path != query.StoreKeyPath || !req.Prove {
+		return nil
+	}
+	if len(result.Proof) == 0 {
+		return fmt.Errorf("%w: node returned no proof for key %x", ErrProofRequired, req.Data)
+	}
+	if err := c.verifier.VerifyQueryResult(ctx, req.Data, result); err != nil {
+		return fmt.Errorf("failed to verify proof of key %x: %w", req.Data, err)
+	}
+	return nil
+}
+
+// provenBalance implements GetBalance with proven reads
+func (c *QueryClient) provenBalance(ctx context.Context, account types.AccountName, denom string, height int64) (types.Coin, uint64, error) {
+	if !account.IsValid() {
+		return types.Coin{}, 0, fmt.Errorf("%w: %s", types.ErrInvalidAccount, account)
+	}
+
+	// Read the raw value: store.BalanceStore reports every read error,
+	// failed proofs included, as a zero balance
+	view := c.newProvenStore(ctx, height)
+	raw, err := capability.ModuleStore(view, bank.ModuleName).Get(store.BalanceKey(account, denom))
+	if errors.Is(err, store.ErrNotFound) {
+		return types.NewCoin(denom, 0), uint64(view.height), nil
+	}
+	if err != nil {
+		return types.Coin{}, 0, err
+	}
+
+	balance, err := store.NewJSONSerializer[store.Balance]().Unmarshal(raw)
+	if err != nil {
+		return types.Coin{}, 0, fmt.Errorf("failed to decode balance: %w", err)
+	}
+	return types.NewCoin(denom, balance.Amount), uint64(view.height), nil
+}
+
+// provenSequence implements GetSequence with proven reads
+func (c *QueryClient) provenSequence(ctx context.Context, account types.AccountName) (uint64, error) {
+	if !account.IsValid() {
+		return 0, fmt.Errorf("%w: %s", types.ErrInvalidAccount, account)
+	}
+
+	// Accounts are stored under their raw name (see runtime.QueryPathAccountNonce)
+	raw, err := c.newProvenStore(ctx, 0).Get([]byte(account))
+	if errors.Is(err, store.ErrNotFound) {
+		return 0, fmt.Errorf("%w: account %s", types.ErrNotFound, account)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	acc, err := store.NewJSONSerializer[*types.Account]().Unmarshal(raw)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode account: %w", err)
+	}
+	return acc.Nonce, nil
+}
+
+// provenDenomMetadata implements GetDenomMetadata with proven reads
+func (c *QueryClient) provenDenomMetadata(ctx context.Context, denom string, height int64) (types.DenomMetadata, error) {
+	metadata, ok, err := bank.GetDenomMetadata(c.newProvenStore(ctx, height), denom)
+	if err != nil {
+		return types.DenomMetadata{}, err
+	}
+	if !ok {
+		return types.DenomMetadata{}, fmt.Errorf("%w: no metadata for denom %s", types.ErrNotFound, denom)
+	}
+	return metadata, nil
+}
+
+// provenStore is a read-only view of the state store at one height whose
+// reads are proven store key queries, so state readers such as
+// bank.GetDenomMetadata can run over verified data. Every read error,
+// including a failed proof, is returned; readers must not mistake it for
+// absence.
+//
+// The first read pins the height when it is 0 (latest). Iteration cannot be
+// proven and fails with ErrProofRequired.
+type provenStore struct {
+	ctx    context.Context
+	client *QueryClient
+	height int64
+}
+
+// newProvenStore returns a proven view of the state store at height
+func (c *QueryClient) newProvenStore(ctx context.Context, height int64) *provenStore {
+	return &provenStore{ctx: ctx, client: c, height: height}
+}
+
+// Get retrieves the proven value of key
+func (s *provenStore) Get(key []byte) ([]byte, error) {
+	value, height, err := s.client.GetStoreKey(s.ctx, key, s.height)
+	if err != nil {
+		return nil, err
+	}
+	s.height = int64(height)
+	if value == nil {
+		return nil, store.ErrNotFound
+	}
+	return value, nil
+}
+
+// Has checks if key exists
+func (s *provenStore) Has(key []byte) (bool, error) {
+	_, err := s.Get(key)
+	if errors.Is(err, store.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Set is not supported
+func (s *provenStore) Set(key []byte, value []byte) error {
+	return store.ErrReadOnly
+}
+
+// Delete is not supported
+func (s *provenStore) Delete(key []byte) error {
+	return store.ErrReadOnly
+}
+
+// Iterator is not supported
+func (s *provenStore) Iterator(start, end []byte) (store.RawIterator, error) {
+	return nil, fmt.Errorf("%w: iteration", ErrProofRequired)
+}
+
+// ReverseIterator is not supported
+func (s *provenStore) ReverseIterator(start, end []byte) (store.RawIterator, error) {
+	return nil, fmt.Errorf("%w: iteration", ErrProofRequired)
+}
+
+// Flush is a no-op
+func (s *provenStore) Flush() error {
+	return nil
+}
+
+// Close is a no-op
+func (s *provenStore) Close() error {
+	return nil
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/light"
+	"github.com/blockberries/punnet-sdk/modules/bank"
+	"github.com/blockberries/punnet-sdk/query"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+var _ ProofVerifier = (*light.Client)(nil)
+
+// rootVerifier verifies proofs against the store roots trusted at each
+// height, standing in for a light client
+type rootVerifier map[uint64][]byte
+
+func (v rootVerifier) VerifyQueryResult(ctx context.Context, key []byte, result *types.QueryResult) error {
+	root, ok := v[result.Height]
+	if !ok {
+		return light.ErrHeaderMismatch
+	}
+	if result.Data == nil {
+		return light.VerifyNonMembership(root, result.Proof, key)
+	}
+	return light.VerifyMembership(root, result.Proof, key, result.Data)
+}
+
+// tamperingQuerier rewrites the data of store key results
+type tamperingQuerier struct {
+	Querier
+	data []byte
+}
+
+func (q *tamperingQuerier) QueryRequest(ctx context.Context, req query.Request) (*types.QueryResult, error) {
+	result, err := q.Querier.QueryRequest(ctx, req)
+	if err == nil && req.Path == query.StoreKeyPath {
+		result.Data = q.data
+	}
+	return result, err
+}
+
+func TestQueryClient_ProofVerification(t *testing.T) {
+	unverified, s := setupClient(t, 0)
+	ctx := context.Background()
+
+	roots := rootVerifier{}
+	commitBalance(t, s, "alice", 100)
+	roots[uint64(s.Version())] = s.Hash()
+	commitBalance(t, s, "alice", 250)
+	roots[uint64(s.Version())] = s.Hash()
+
+	client, err := NewQueryClient(unverified.querier, WithProofVerifier(roots), WithRequireProofs())
+	require.NoError(t, err)
+
+	t.Run("proven balances", func(t *testing.T) {
+		coin, height, err := client.GetBalance(ctx, "alice", "uatom", 0)
+		require.NoError(t, err)
+		require.Equal(t, types.NewCoin("uatom", 250), coin)
+		require.Equal(t, uint64(2), height)
+
+		coin, height, err = client.GetBalance(ctx, "alice", "uatom", 1)
+		require.NoError(t, err)
+		require.Equal(t, uint64(100), coin.Amount)
+		require.Equal(t, uint64(1), height)
+
+		// Absence is proven too
+		coin, _, err = client.GetBalance(ctx, "bob", "uatom", 0)
+		require.NoError(t, err)
+		require.True(t, coin.IsZero())
+	})
+
+	t.Run("proven denom metadata", func(t *testing.T) {
+		_, err := client.GetDenomMetadata(ctx, "uatom", 0)
+		require.ErrorIs(t, err, types.ErrNotFound)
+	})
+
+	t.Run("unprovable queries are refused", func(t *testing.T) {
+		_, err := client.GetDenomMetadataLookup(ctx, 0)
+		require.ErrorIs(t, err, ErrProofRequired)
+
+		_, err = client.Query(ctx, query.Request{Path: bank.QueryServiceBalance})
+		require.ErrorIs(t, err, ErrProofRequired)
+
+		_, _, err = client.Batch(0).BankParams(&bank.Params{}).Execute(ctx)
+		require.ErrorIs(t, err, ErrProofRequired)
+	})
+
+	t.Run("tampered results are rejected", func(t *testing.T) {
+		balance := []byte(`{"account":"alice","denom":"uatom","amount":1000000}`)
+		for _, tt := range []struct {
+			name string
+			data []byte
+		}{
+			{"forged value", balance},
+			{"hidden value", nil},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				malicious, err := NewQueryClient(&tamperingQuerier{Querier: unverified.querier, data: tt.data}, WithProofVerifier(roots))
+				require.NoError(t, err)
+				_, _, err = malicious.GetBalance(ctx, "alice", "uatom", 0)
+				require.ErrorIs(t, err, light.ErrInvalidProof)
+			})
+		}
+	})
+
+	t.Run("untrusted height", func(t *testing.T) {
+		commitBalance(t, s, "alice", 1)
+		_, _, err := client.GetBalance(ctx, "alice", "uatom", 0)
+		require.ErrorIs(t, err, light.ErrHeaderMismatch)
+	})
+}
+
+func TestNewQueryClient_RequireProofsWithoutVerifier(t *testing.T) {
+	_, err := NewQueryClient(&tamperingQuerier{}, WithRequireProofs())
+	require.Error(t, err)
+
+	// Without a verifier, store key reads are served unproven
+	client, s := setupClient(t, 0)
+	commitBalance(t, s, "alice", 5)
+	value, height, err := client.GetStoreKey(context.Background(), []byte("module/bank/"+string(store.BalanceKey("alice", "uatom"))), 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), height)
+	require.Contains(t, string(value), `"amount":5`)
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/modules/bank"
+	"github.com/blockberries/punnet-sdk/query"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// QueryBatch collects queries to send in one round trip (see
+// runtime.QueryPathBatch), e.g. to populate a wallet dashboard. Each query
+// decodes its result into the destination it was added with.
+//
+// Not safe for concurrent use.
+type QueryBatch struct {
+	client   *QueryClient
+	height   int64
+	requests []query.Request
+	decoders []func(*types.QueryResult) error
+	err      error
+}
+
+// Batch starts a query batch read at height (0 for the latest height). All
+// queries of the batch read the same height.
+func (c *QueryClient) Batch(height int64) *QueryBatch {
+	return &QueryBatch{client: c, height: height}
+}
+
+// Len returns the number of queries in the batch
+func (b *QueryBatch) Len() int {
+	return len(b.requests)
+}
+
+// Query adds req, whose successful result is passed to decode (which may be
+// nil). A request at height 0 reads the batch height.
+func (b *QueryBatch) Query(req query.Request, decode func(*types.QueryResult) error) *QueryBatch {
+	b.requests = append(b.requests, req)
+	b.decoders = append(b.decoders, decode)
+	return b
+}
+
+// Balance adds a query of the balance of account in denom into dst
+func (b *QueryBatch) Balance(account types.AccountName, denom string, dst *types.Coin) *QueryBatch {
+	req, err := balanceRequest(account, denom, 0)
+	if err != nil {
+		b.fail(err)
+		return b
+	}
+	return b.Query(req, func(result *types.QueryResult) error {
+		coin, err := decodeBalance(result, denom)
+		if err == nil {
+			*dst = coin
+		}
+		return err
+	})
+}
+
+// Sequence adds a query of the next nonce the chain expects from account
+// into dst
+func (b *QueryBatch) Sequence(account types.AccountName, dst *uint64) *QueryBatch {
+	return b.Query(sequenceRequest(account, 0), func(result *types.QueryResult) error {
+		seq, err := decodeSequence(result)
+		if err == nil {
+			*dst = seq
+		}
+		return err
+	})
+}
+
+// DenomMetadata adds a query of the display metadata of denom into dst
+func (b *QueryBatch) DenomMetadata(denom string, dst *types.DenomMetadata) *QueryBatch {
+	req, err := denomMetadataRequest(denom, 0)
+	if err != nil {
+		b.fail(err)
+		return b
+	}
+	return b.Query(req, func(result *types.QueryResult) error {
+		metadata, err := decodeDenomMetadata(result)
+		if err == nil {
+			*dst = metadata
+		}
+		return err
+	})
+}
+
+// BankParams adds a query of the bank params into dst
+func (b *QueryBatch) BankParams(dst *bank.Params) *QueryBatch {
+	return b.Query(query.Request{Path: bank.QueryServiceParams}, func(result *types.QueryResult) error {
+		var resp bank.QueryParamsResponse
+		if err := json.Unmarshal(result.Data, &resp); err != nil {
+			return fmt.Errorf("failed to decode params response: %w", err)
+		}
+		*dst = resp.Params
+		return nil
+	})
+}
+
+// Execute sends the batch and decodes each result. It returns the height
+// the batch was served at and one error per query in the order they were
+// added: nil if the query succeeded, or an error wrapping ErrQueryFailed
+// and its decoded *ResultError. err is set only if the batch as a whole
+// failed.
+func (b *QueryBatch) Execute(ctx context.Context) (height uint64, errs []error, err error) {
+	if b.client == nil {
+		return 0, nil, fmt.Errorf("query client is nil")
+	}
+	if b.err != nil {
+		return 0, nil, b.err
+	}
+	if len(b.requests) > runtime.MaxQueryBatchSize {
+		return 0, nil, fmt.Errorf("%d queries exceed the batch limit of %d", len(b.requests), runtime.MaxQueryBatchSize)
+	}
+
+	data, err := json.Marshal(runtime.QueryBatchRequest{Requests: b.requests})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to encode batch query: %w", err)
+	}
+	result, err := b.client.Query(ctx, query.Request{Path: runtime.QueryPathBatch, Data: data, Height: b.height})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var resp runtime.QueryBatchResponse
+	if err := json.Unmarshal(result.Data, &resp); err != nil {
+		return 0, nil, fmt.Errorf("failed to decode batch response: %w", err)
+	}
+	if len(resp.Results) != len(b.requests) {
+		return 0, nil, fmt.Errorf("batch returned %d results for %d queries", len(resp.Results), len(b.requests))
+	}
+
+	errs = make([]error, len(resp.Results))
+	for i, r := range resp.Results {
+		if r == nil {
+			errs[i] = fmt.Errorf("batch returned no result for query %d", i)
+			continue
+		}
+		if err := DecodeQueryResult(r); err != nil {
+			errs[i] = fmt.Errorf("%w: %w", ErrQueryFailed, err)
+			continue
+		}
+		if decode := b.decoders[i]; decode != nil {
+			errs[i] = decode(r)
+		}
+	}
+	return result.Height, errs, nil
+}
+
+// fail records the first error adding a query, returned by Execute
+func (b *QueryBatch) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
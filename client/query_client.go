@@ -0,0 +1,202 @@
+// Package client provides typed clients for application queries.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/modules/bank"
+	"github.com/blockberries/punnet-sdk/query"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// ErrQueryFailed is returned when the application rejects a query
+var ErrQueryFailed = errors.New("query failed")
+
+// Querier executes query requests; *runtime.Application implements it
+type Querier interface {
+	QueryRequest(ctx context.Context, req query.Request) (*types.QueryResult, error)
+}
+
+// QueryClient issues typed queries.
+//
+// Heights are committed block heights; 0 means the latest height. Reads at
+// older heights succeed only while the height is inside the state store's
+// retention window (see store.WithKeepRecent).
+//
+// With a proof verifier (see WithProofVerifier), typed reads of state are
+// served by proven store key reads and checked against a trusted header.
+type QueryClient struct {
+	querier       Querier
+	verifier      ProofVerifier
+	requireProofs bool
+}
+
+// NewQueryClient creates a query client
+func NewQueryClient(querier Querier, opts ...QueryClientOption) (*QueryClient, error) {
+	if querier == nil {
+		return nil, fmt.Errorf("querier cannot be nil")
+	}
+
+	c := &QueryClient{querier: querier}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.requireProofs && c.verifier == nil {
+		return nil, fmt.Errorf("requiring proofs needs a proof verifier")
+	}
+	return c, nil
+}
+
+// Query executes req. A failed result is returned as an error wrapping both
+// ErrQueryFailed and its decoded *ResultError (see DecodeQueryResult).
+//
+// With a proof verifier, the proof of a query.StoreKeyPath request with
+// Prove set is verified. If the client requires proofs, any other request
+// fails with ErrProofRequired before it is sent.
+func (c *QueryClient) Query(ctx context.Context, req query.Request) (*types.QueryResult, error) {
+	if c == nil {
+		return nil, fmt.Errorf("query client is nil")
+	}
+	if err := c.checkProvable(req); err != nil {
+		return nil, err
+	}
+
+	result, err := c.querier.QueryRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := DecodeQueryResult(result); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrQueryFailed, err)
+	}
+	if err := c.verify(ctx, req, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetBalance returns the balance of account in denom at height (0 for the
+// latest height) together with the height the balance was read at.
+func (c *QueryClient) GetBalance(ctx context.Context, account types.AccountName, denom string, height int64) (types.Coin, uint64, error) {
+	if c != nil && c.verifier != nil {
+		return c.provenBalance(ctx, account, denom, height)
+	}
+
+	req, err := balanceRequest(account, denom, height)
+	if err != nil {
+		return types.Coin{}, 0, err
+	}
+
+	result, err := c.Query(ctx, req)
+	if err != nil {
+		return types.Coin{}, 0, err
+	}
+
+	coin, err := decodeBalance(result, denom)
+	if err != nil {
+		return types.Coin{}, 0, err
+	}
+	return coin, result.Height, nil
+}
+
+// GetSequence returns the next transaction nonce the chain expects from account
+// at the latest height
+func (c *QueryClient) GetSequence(ctx context.Context, account types.AccountName) (uint64, error) {
+	if c != nil && c.verifier != nil {
+		return c.provenSequence(ctx, account)
+	}
+
+	result, err := c.Query(ctx, sequenceRequest(account, 0))
+	if err != nil {
+		return 0, err
+	}
+	return decodeSequence(result)
+}
+
+// GetDenomMetadata returns the display metadata of denom at height (0 for
+// the latest height). A denom without metadata fails with types.ErrNotFound.
+func (c *QueryClient) GetDenomMetadata(ctx context.Context, denom string, height int64) (types.DenomMetadata, error) {
+	if c != nil && c.verifier != nil {
+		return c.provenDenomMetadata(ctx, denom, height)
+	}
+
+	req, err := denomMetadataRequest(denom, height)
+	if err != nil {
+		return types.DenomMetadata{}, err
+	}
+
+	result, err := c.Query(ctx, req)
+	if err != nil {
+		return types.DenomMetadata{}, err
+	}
+	return decodeDenomMetadata(result)
+}
+
+// GetDenomMetadataLookup returns a lookup over all denom metadata at height
+// (0 for the latest height), for types.FormatCoins and
+// types.FormatSignDocCoins
+func (c *QueryClient) GetDenomMetadataLookup(ctx context.Context, height int64) (types.DenomMetadataLookup, error) {
+	result, err := c.Query(ctx, query.Request{Path: bank.QueryServiceAllDenomMetadata, Height: height})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp bank.QueryAllDenomMetadataResponse
+	if err := json.Unmarshal(result.Data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode denom metadata response: %w", err)
+	}
+	return types.NewDenomMetadataLookup(resp.Metadata...), nil
+}
+
+// balanceRequest returns the request of GetBalance
+func balanceRequest(account types.AccountName, denom string, height int64) (query.Request, error) {
+	data, err := json.Marshal(bank.QueryBalanceRequest{Account: account, Denom: denom})
+	if err != nil {
+		return query.Request{}, fmt.Errorf("failed to encode balance query: %w", err)
+	}
+	return query.Request{Path: bank.QueryServiceBalance, Data: data, Height: height}, nil
+}
+
+// decodeBalance decodes the result of a balanceRequest
+func decodeBalance(result *types.QueryResult, denom string) (types.Coin, error) {
+	var resp bank.QueryBalanceResponse
+	if err := json.Unmarshal(result.Data, &resp); err != nil {
+		return types.Coin{}, fmt.Errorf("failed to decode balance response: %w", err)
+	}
+	return types.NewCoin(denom, resp.Balance), nil
+}
+
+// sequenceRequest returns the request of GetSequence
+func sequenceRequest(account types.AccountName, height int64) query.Request {
+	return query.Request{Path: runtime.QueryPathAccountNonce, Data: []byte(account), Height: height}
+}
+
+// decodeSequence decodes the result of a sequenceRequest
+func decodeSequence(result *types.QueryResult) (uint64, error) {
+	var resp runtime.AccountNonceResponse
+	if err := json.Unmarshal(result.Data, &resp); err != nil {
+		return 0, fmt.Errorf("failed to decode nonce response: %w", err)
+	}
+	return resp.Nonce, nil
+}
+
+// denomMetadataRequest returns the request of GetDenomMetadata
+func denomMetadataRequest(denom string, height int64) (query.Request, error) {
+	data, err := json.Marshal(bank.QueryDenomMetadataRequest{Denom: denom})
+	if err != nil {
+		return query.Request{}, fmt.Errorf("failed to encode denom metadata query: %w", err)
+	}
+	return query.Request{Path: bank.QueryServiceDenomMetadata, Data: data, Height: height}, nil
+}
+
+// decodeDenomMetadata decodes the result of a denomMetadataRequest
+func decodeDenomMetadata(result *types.QueryResult) (types.DenomMetadata, error) {
+	var resp bank.QueryDenomMetadataResponse
+	if err := json.Unmarshal(result.Data, &resp); err != nil {
+		return types.DenomMetadata{}, fmt.Errorf("failed to decode denom metadata response: %w", err)
+	}
+	return resp.Metadata, nil
+}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/modules/bank"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// setupClient returns a client for an application with the bank module over
+// an IAVL store retaining keepRecent versions
+func setupClient(t *testing.T, keepRecent int64) (*QueryClient, *store.IAVLStore) {
+	t.Helper()
+
+	iavlStore, err := store.NewIAVLStore(store.NewMemDB(), 0, store.WithKeepRecent(keepRecent))
+	require.NoError(t, err)
+
+	capMgr := capability.NewCapabilityManager(iavlStore)
+	require.NoError(t, capMgr.RegisterModule(bank.ModuleName))
+	balanceCap, err := capMgr.GrantBalanceCapability(bank.ModuleName)
+	require.NoError(t, err)
+	bankMod, err := bank.CreateModule(balanceCap)
+	require.NoError(t, err)
+
+	app, err := runtime.NewApplication(runtime.ApplicationConfig{
+		ChainID:    "test-chain",
+		StateStore: iavlStore,
+		Modules:    []runtime.Module{bankMod},
+	})
+	require.NoError(t, err)
+
+	client, err := NewQueryClient(app)
+	require.NoError(t, err)
+	return client, iavlStore
+}
+
+// commitBalance sets a bank balance and saves a new store version
+func commitBalance(t *testing.T, s *store.IAVLStore, account types.AccountName, amount uint64) {
+	t.Helper()
+
+	balances := store.NewBalanceStore(capability.ModuleStore(s, bank.ModuleName))
+	require.NoError(t, balances.Set(context.Background(), store.NewBalance(account, "uatom", amount)))
+	require.NoError(t, balances.Flush(context.Background()))
+}
+
+func TestQueryClient_GetBalanceAtHeight(t *testing.T) {
+	client, s := setupClient(t, 0)
+	ctx := context.Background()
+
+	commitBalance(t, s, "alice", 100)
+	commitBalance(t, s, "alice", 250)
+	require.Equal(t, int64(2), s.Version())
+
+	tests := []struct {
+		name       string
+		height     int64
+		wantAmount uint64
+		wantHeight uint64
+	}{
+		{"latest", 0, 250, 2},
+		{"height 2", 2, 250, 2},
+		{"height 1", 1, 100, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coin, height, err := client.GetBalance(ctx, "alice", "uatom", tt.height)
+			require.NoError(t, err)
+			require.Equal(t, types.NewCoin("uatom", tt.wantAmount), coin)
+			require.Equal(t, tt.wantHeight, height)
+		})
+	}
+
+	// Unknown accounts have a zero balance
+	coin, _, err := client.GetBalance(ctx, "bob", "uatom", 1)
+	require.NoError(t, err)
+	require.True(t, coin.IsZero())
+
+	_, _, err = client.GetBalance(ctx, "alice", "uatom", 3)
+	require.ErrorIs(t, err, ErrQueryFailed)
+}
+
+func TestQueryClient_RetentionWindow(t *testing.T) {
+	client, s := setupClient(t, 2)
+	ctx := context.Background()
+
+	commitBalance(t, s, "alice", 1)
+	commitBalance(t, s, "alice", 2)
+	commitBalance(t, s, "alice", 3)
+	require.Equal(t, int64(2), s.EarliestVersion())
+
+	_, _, err := client.GetBalance(ctx, "alice", "uatom", 1)
+	require.ErrorIs(t, err, ErrQueryFailed)
+	require.ErrorContains(t, err, "version not available")
+
+	coin, _, err := client.GetBalance(ctx, "alice", "uatom", 2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), coin.Amount)
+}
+
+func TestQueryClient_GetDenomMetadata(t *testing.T) {
+	client, s := setupClient(t, 0)
+	ctx := context.Background()
+
+	pun := types.DenomMetadata{Base: "upun", Display: "PUN", Exponent: 6}
+	data, err := json.Marshal(pun)
+	require.NoError(t, err)
+	require.NoError(t, bank.DenomMetadataStore(s).Set([]byte(pun.Base), data))
+	require.NoError(t, s.Flush())
+
+	got, err := client.GetDenomMetadata(ctx, "upun", 0)
+	require.NoError(t, err)
+	require.Equal(t, pun, got)
+
+	_, err = client.GetDenomMetadata(ctx, "uatom", 0)
+	require.ErrorIs(t, err, types.ErrNotFound)
+
+	lookup, err := client.GetDenomMetadataLookup(ctx, 0)
+	require.NoError(t, err)
+	require.Equal(t, "1.5 PUN, 7uatom", types.FormatCoins(types.NewCoins(types.NewCoin("upun", 1500000), types.NewCoin("uatom", 7)), lookup))
+}
+
+func TestNewQueryClient_Nil(t *testing.T) {
+	_, err := NewQueryClient(nil)
+	require.Error(t, err)
+}
+
+func TestQueryClient_Batch(t *testing.T) {
+	client, s := setupClient(t, 0)
+	ctx := context.Background()
+
+	commitBalance(t, s, "alice", 100)
+	commitBalance(t, s, "alice", 250)
+
+	var alice, bob types.Coin
+	var metadata types.DenomMetadata
+	var params bank.Params
+	batch := client.Batch(1).
+		Balance("alice", "uatom", &alice).
+		Balance("bob", "uatom", &bob).
+		DenomMetadata("uatom", &metadata).
+		BankParams(&params)
+	require.Equal(t, 4, batch.Len())
+
+	height, errs, err := batch.Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), height)
+	require.Len(t, errs, 4)
+	require.NoError(t, errs[0])
+	require.Equal(t, types.NewCoin("uatom", 100), alice)
+	require.NoError(t, errs[1])
+	require.True(t, bob.IsZero())
+	require.ErrorIs(t, errs[2], ErrQueryFailed)
+	require.ErrorIs(t, errs[2], types.ErrNotFound)
+	require.NoError(t, errs[3])
+	require.Equal(t, bank.DefaultParams(), params)
+
+	// The latest height is read by default
+	height, errs, err = client.Batch(0).Balance("alice", "uatom", &alice).Execute(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), height)
+	require.NoError(t, errs[0])
+	require.Equal(t, uint64(250), alice.Amount)
+
+	// Oversized batches fail before a round trip
+	oversized := client.Batch(0)
+	for i := 0; i <= runtime.MaxQueryBatchSize; i++ {
+		oversized.Balance("alice", "uatom", &alice)
+	}
+	_, _, err = oversized.Execute(ctx)
+	require.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"fmt"
+
+	sdkerrors "github.com/blockberries/punnet-sdk/errors"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// ResultError is a failed transaction or query result decoded into a Go error.
+//
+// It unwraps to the error registered for its codespace and code (see
+// sdkerrors.Register), so callers branch with errors.Is:
+//
+//	if errors.Is(client.DecodeTxResult(result), types.ErrSequenceMismatch) {
+//		// resync the account sequence and retry
+//	}
+type ResultError struct {
+	// Codespace and Code identify the error
+	Codespace string
+	Code      uint32
+
+	// Log is the result log
+	Log string
+
+	// err is the registered error, or nil if the code is unknown to this client
+	err error
+}
+
+// Error implements error
+func (e *ResultError) Error() string {
+	return fmt.Sprintf("%s (codespace %s, code %d)", e.Log, e.Codespace, e.Code)
+}
+
+// Unwrap returns the registered error for the result code, if any
+func (e *ResultError) Unwrap() error {
+	return e.err
+}
+
+// DecodeTxResult returns nil if result succeeded, otherwise a *ResultError
+func DecodeTxResult(result *types.TxResult) error {
+	if result == nil {
+		return fmt.Errorf("transaction result is nil")
+	}
+	return decodeResult(result.Codespace, result.Code, result.Log)
+}
+
+// DecodeQueryResult returns nil if result succeeded, otherwise a *ResultError
+func DecodeQueryResult(result *types.QueryResult) error {
+	if result == nil {
+		return fmt.Errorf("query result is nil")
+	}
+	return decodeResult(result.Codespace, result.Code, result.Log)
+}
+
+// decodeResult maps a result code to a *ResultError
+func decodeResult(codespace string, code uint32, log string) error {
+	if code == sdkerrors.CodeOK {
+		return nil
+	}
+
+	// Results from before codespaces were introduced carry none
+	if codespace == "" {
+		codespace = sdkerrors.CodespaceSDK
+	}
+
+	return &ResultError{
+		Codespace: codespace,
+		Code:      code,
+		Log:       log,
+		err:       sdkerrors.FromCode(codespace, code),
+	}
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdkerrors "github.com/blockberries/punnet-sdk/errors"
+	"github.com/blockberries/punnet-sdk/query"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+func TestDecodeTxResult(t *testing.T) {
+	require.NoError(t, DecodeTxResult(&types.TxResult{Code: sdkerrors.CodeOK}))
+	require.Error(t, DecodeTxResult(nil))
+
+	codespace, code := sdkerrors.ABCICode(types.ErrSequenceMismatch)
+	err := DecodeTxResult(&types.TxResult{Codespace: codespace, Code: code, Log: "expected nonce 5, got 4"})
+	require.ErrorIs(t, err, types.ErrSequenceMismatch)
+	require.NotErrorIs(t, err, types.ErrInsufficientFunds)
+	require.ErrorContains(t, err, "expected nonce 5, got 4")
+
+	var resultErr *ResultError
+	require.True(t, errors.As(err, &resultErr))
+	require.Equal(t, codespace, resultErr.Codespace)
+	require.Equal(t, code, resultErr.Code)
+
+	// Unknown codes still decode, without a typed cause
+	err = DecodeTxResult(&types.TxResult{Codespace: "unknown", Code: 42, Log: "mystery"})
+	require.True(t, errors.As(err, &resultErr))
+	require.Nil(t, resultErr.Unwrap())
+
+	// Results without a codespace are read as SDK codes
+	_, code = sdkerrors.ABCICode(types.ErrInsufficientFunds)
+	require.ErrorIs(t, DecodeTxResult(&types.TxResult{Code: code}), types.ErrInsufficientFunds)
+}
+
+func TestQueryClient_TypedErrors(t *testing.T) {
+	client, _ := setupClient(t, 0)
+
+	_, err := client.GetSequence(t.Context(), "nobody")
+	require.ErrorIs(t, err, ErrQueryFailed)
+	require.ErrorIs(t, err, types.ErrNotFound)
+
+	_, _, err = client.GetBalance(t.Context(), "alice", "uatom", 10)
+	require.ErrorIs(t, err, ErrQueryFailed)
+	require.ErrorIs(t, err, query.ErrHeightNotAvailable)
+}
@@ -0,0 +1,271 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// ErrUnknownReservation is returned when confirming or failing a sequence that
+// was not reserved (or was discarded by a resync)
+var ErrUnknownReservation = errors.New("unknown sequence reservation")
+
+// SequenceSource returns the next nonce the chain expects from an account.
+// *QueryClient implements it.
+type SequenceSource interface {
+	GetSequence(ctx context.Context, account types.AccountName) (uint64, error)
+}
+
+// SequenceManager hands out transaction nonces to concurrent transaction
+// builders signing for the same accounts.
+//
+// Sequences are reserved optimistically: an account's first Reserve fetches
+// the chain sequence, later ones increment locally without a query. After
+// broadcasting, the builder reports the outcome:
+//
+//   - Confirm: the transaction was accepted; its sequence is consumed.
+//   - Fail: the transaction was rejected and its sequence is free again.
+//     The next Reserve reuses the lowest free sequence so no gap is left.
+//     If the rejection was types.ErrSequenceMismatch the local view is stale
+//     and the account is resynced from the chain on its next Reserve.
+//
+// Safe for concurrent use.
+type SequenceManager struct {
+	mu       sync.Mutex
+	source   SequenceSource
+	accounts map[types.AccountName]*accountSequences
+
+	// syncing holds the accounts whose chain sequence a Reserve is
+	// fetching; the channel is closed when the fetch completes
+	syncing map[types.AccountName]chan struct{}
+}
+
+// accountSequences tracks one account's sequences
+type accountSequences struct {
+	// next is the lowest sequence never handed out
+	next uint64
+
+	// free holds released sequences below next, in ascending order
+	free []uint64
+
+	// pending holds reserved sequences awaiting Confirm or Fail
+	pending map[uint64]struct{}
+
+	// stale forces a resync on the next Reserve
+	stale bool
+}
+
+// NewSequenceManager creates a sequence manager that syncs from source
+func NewSequenceManager(source SequenceSource) (*SequenceManager, error) {
+	if source == nil {
+		return nil, fmt.Errorf("sequence source cannot be nil")
+	}
+
+	return &SequenceManager{
+		source:   source,
+		accounts: make(map[types.AccountName]*accountSequences),
+		syncing:  make(map[types.AccountName]chan struct{}),
+	}, nil
+}
+
+// Reserve returns a sequence for the next transaction of account. The caller
+// must report the broadcast outcome with Confirm or Fail.
+//
+// The chain sequence is fetched without holding the manager's lock, so a slow
+// query for one account does not block the others. Concurrent Reserves of an
+// account wait for a single fetch.
+func (m *SequenceManager) Reserve(ctx context.Context, account types.AccountName) (uint64, error) {
+	if m == nil {
+		return 0, fmt.Errorf("sequence manager is nil")
+	}
+	if !account.IsValid() {
+		return 0, fmt.Errorf("%w: %s", types.ErrInvalidAccount, account)
+	}
+
+	for {
+		m.mu.Lock()
+		if seqs := m.accounts[account]; seqs != nil && !seqs.stale {
+			seq := seqs.reserve()
+			m.mu.Unlock()
+			return seq, nil
+		}
+		if done, ok := m.syncing[account]; ok {
+			m.mu.Unlock()
+			select {
+			case <-done:
+				continue
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+		}
+		done := make(chan struct{})
+		m.syncing[account] = done
+		m.mu.Unlock()
+
+		next, err := m.fetch(ctx, account)
+
+		m.mu.Lock()
+		delete(m.syncing, account)
+		close(done)
+		if err != nil {
+			m.mu.Unlock()
+			return 0, err
+		}
+
+		// A Resync may have synced the account during the fetch; its
+		// state is kept rather than reset
+		seqs := m.accounts[account]
+		if seqs == nil || seqs.stale {
+			seqs = m.installLocked(account, next)
+		}
+		seq := seqs.reserve()
+		m.mu.Unlock()
+		return seq, nil
+	}
+}
+
+// reserve hands out the lowest free sequence
+func (s *accountSequences) reserve() uint64 {
+	var seq uint64
+	if len(s.free) > 0 {
+		seq = s.free[0]
+		s.free = s.free[1:]
+	} else {
+		seq = s.next
+		s.next++
+	}
+	s.pending[seq] = struct{}{}
+	return seq
+}
+
+// Confirm records that the transaction using seq was accepted
+func (m *SequenceManager) Confirm(account types.AccountName, seq uint64) error {
+	if m == nil {
+		return fmt.Errorf("sequence manager is nil")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seqs, err := m.pendingLocked(account, seq)
+	if err != nil {
+		return err
+	}
+	delete(seqs.pending, seq)
+	return nil
+}
+
+// Fail records that the transaction using seq was not accepted, freeing seq.
+// broadcastErr is the broadcast error (e.g. from DecodeTxResult); if it wraps
+// types.ErrSequenceMismatch the account is resynced from the chain on its next
+// Reserve.
+func (m *SequenceManager) Fail(account types.AccountName, seq uint64, broadcastErr error) error {
+	if m == nil {
+		return fmt.Errorf("sequence manager is nil")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seqs, err := m.pendingLocked(account, seq)
+	if err != nil {
+		return err
+	}
+	delete(seqs.pending, seq)
+
+	if errors.Is(broadcastErr, types.ErrSequenceMismatch) {
+		seqs.stale = true
+		return nil
+	}
+
+	if seq == seqs.next-1 {
+		// Last sequence handed out: roll back, along with any free
+		// sequences now at the top
+		seqs.next--
+		for len(seqs.free) > 0 && seqs.free[len(seqs.free)-1] == seqs.next-1 {
+			seqs.free = seqs.free[:len(seqs.free)-1]
+			seqs.next--
+		}
+		return nil
+	}
+
+	i := sort.Search(len(seqs.free), func(i int) bool { return seqs.free[i] >= seq })
+	seqs.free = append(seqs.free, 0)
+	copy(seqs.free[i+1:], seqs.free[i:])
+	seqs.free[i] = seq
+	return nil
+}
+
+// Resync replaces the local view of account with the chain sequence.
+// Pending reservations are discarded; confirming or failing them returns
+// ErrUnknownReservation.
+func (m *SequenceManager) Resync(ctx context.Context, account types.AccountName) error {
+	if m == nil {
+		return fmt.Errorf("sequence manager is nil")
+	}
+
+	next, err := m.fetch(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.installLocked(account, next)
+	return nil
+}
+
+// Pending returns the number of reservations of account awaiting Confirm or Fail
+func (m *SequenceManager) Pending(account types.AccountName) int {
+	if m == nil {
+		return 0
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seqs := m.accounts[account]
+	if seqs == nil {
+		return 0
+	}
+	return len(seqs.pending)
+}
+
+// fetch returns the chain sequence of account.
+// PRECONDITION: m.mu is not held, so the query does not block other accounts.
+func (m *SequenceManager) fetch(ctx context.Context, account types.AccountName) (uint64, error) {
+	next, err := m.source.GetSequence(ctx, account)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sync sequence of %s: %w", account, err)
+	}
+	return next, nil
+}
+
+// installLocked resets the state of account to the chain sequence next.
+// PRECONDITION: m.mu is held.
+func (m *SequenceManager) installLocked(account types.AccountName, next uint64) *accountSequences {
+	seqs := &accountSequences{
+		next:    next,
+		pending: make(map[uint64]struct{}),
+	}
+	m.accounts[account] = seqs
+	return seqs
+}
+
+// pendingLocked returns the state of account if seq is reserved.
+// PRECONDITION: m.mu is held.
+func (m *SequenceManager) pendingLocked(account types.AccountName, seq uint64) (*accountSequences, error) {
+	seqs := m.accounts[account]
+	if seqs == nil {
+		return nil, fmt.Errorf("%w: %s sequence %d", ErrUnknownReservation, account, seq)
+	}
+	if _, ok := seqs.pending[seq]; !ok {
+		return nil, fmt.Errorf("%w: %s sequence %d", ErrUnknownReservation, account, seq)
+	}
+	return seqs, nil
+}
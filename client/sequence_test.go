@@ -0,0 +1,221 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// fakeSource returns a configurable chain sequence and counts queries
+type fakeSource struct {
+	mu      sync.Mutex
+	seq     uint64
+	queries int
+	err     error
+}
+
+func (f *fakeSource) GetSequence(ctx context.Context, account types.AccountName) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queries++
+	return f.seq, f.err
+}
+
+func TestSequenceManager_ConcurrentReserve(t *testing.T) {
+	source := &fakeSource{seq: 7}
+	m, err := NewSequenceManager(source)
+	require.NoError(t, err)
+
+	const n = 50
+	seqs := make(chan uint64, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			seq, err := m.Reserve(context.Background(), "alice")
+			require.NoError(t, err)
+			seqs <- seq
+		}()
+	}
+	wg.Wait()
+	close(seqs)
+
+	seen := make(map[uint64]bool)
+	for seq := range seqs {
+		require.False(t, seen[seq], "sequence %d reserved twice", seq)
+		seen[seq] = true
+	}
+	for seq := uint64(7); seq < 7+n; seq++ {
+		require.True(t, seen[seq], "sequence %d not reserved", seq)
+	}
+	require.Equal(t, 1, source.queries)
+	require.Equal(t, n, m.Pending("alice"))
+}
+
+func TestSequenceManager_FailReusesSequence(t *testing.T) {
+	m, err := NewSequenceManager(&fakeSource{seq: 0})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for want := uint64(0); want < 4; want++ {
+		seq, err := m.Reserve(ctx, "alice")
+		require.NoError(t, err)
+		require.Equal(t, want, seq)
+	}
+
+	require.NoError(t, m.Confirm("alice", 0))
+	require.NoError(t, m.Fail("alice", 2, errors.New("connection reset")))
+	require.NoError(t, m.Fail("alice", 1, errors.New("connection reset")))
+
+	// Gaps are refilled lowest first, then fresh sequences resume
+	for _, want := range []uint64{1, 2, 4} {
+		seq, err := m.Reserve(ctx, "alice")
+		require.NoError(t, err)
+		require.Equal(t, want, seq)
+	}
+
+	require.ErrorIs(t, m.Confirm("alice", 0), ErrUnknownReservation)
+	require.ErrorIs(t, m.Confirm("bob", 0), ErrUnknownReservation)
+}
+
+func TestSequenceManager_FailLastRollsBack(t *testing.T) {
+	m, err := NewSequenceManager(&fakeSource{seq: 10})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := m.Reserve(ctx, "alice")
+		require.NoError(t, err)
+	}
+
+	// Freeing 11 then 12 rolls next back to 11
+	require.NoError(t, m.Fail("alice", 11, errors.New("rejected")))
+	require.NoError(t, m.Fail("alice", 12, errors.New("rejected")))
+
+	seq, err := m.Reserve(ctx, "alice")
+	require.NoError(t, err)
+	require.Equal(t, uint64(11), seq)
+	seq, err = m.Reserve(ctx, "alice")
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), seq)
+}
+
+func TestSequenceManager_MismatchResyncs(t *testing.T) {
+	source := &fakeSource{seq: 3}
+	m, err := NewSequenceManager(source)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	seq, err := m.Reserve(ctx, "alice")
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), seq)
+
+	// Another client used sequences 3..5
+	source.seq = 6
+	mismatch := fmt.Errorf("broadcast: %w", types.ErrSequenceMismatch)
+	require.NoError(t, m.Fail("alice", seq, mismatch))
+
+	seq, err = m.Reserve(ctx, "alice")
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), seq)
+	require.Equal(t, 2, source.queries)
+
+	// Explicit resync discards pending reservations
+	source.seq = 9
+	require.NoError(t, m.Resync(ctx, "alice"))
+	require.ErrorIs(t, m.Confirm("alice", 6), ErrUnknownReservation)
+	seq, err = m.Reserve(ctx, "alice")
+	require.NoError(t, err)
+	require.Equal(t, uint64(9), seq)
+}
+
+// blockingSource blocks the sequence queries of one account until released
+type blockingSource struct {
+	blocked types.AccountName
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingSource) GetSequence(ctx context.Context, account types.AccountName) (uint64, error) {
+	if account == b.blocked {
+		close(b.started)
+		<-b.release
+		return 5, nil
+	}
+	return 1, nil
+}
+
+func TestSequenceManager_FetchDoesNotBlockOtherAccounts(t *testing.T) {
+	source := &blockingSource{blocked: "alice", started: make(chan struct{}), release: make(chan struct{})}
+	m, err := NewSequenceManager(source)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	aliceSeq := make(chan uint64, 1)
+	go func() {
+		seq, err := m.Reserve(ctx, "alice")
+		require.NoError(t, err)
+		aliceSeq <- seq
+	}()
+	<-source.started
+
+	// bob is served while alice's query is in flight
+	seq, err := m.Reserve(ctx, "bob")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), seq)
+
+	// A canceled Reserve of alice stops waiting for the fetch
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = m.Reserve(canceled, "alice")
+	require.ErrorIs(t, err, context.Canceled)
+
+	close(source.release)
+	require.Equal(t, uint64(5), <-aliceSeq)
+	require.Equal(t, 1, m.Pending("alice"))
+}
+
+func TestSequenceManager_Errors(t *testing.T) {
+	_, err := NewSequenceManager(nil)
+	require.Error(t, err)
+
+	source := &fakeSource{err: errors.New("node unavailable")}
+	m, err := NewSequenceManager(source)
+	require.NoError(t, err)
+
+	_, err = m.Reserve(context.Background(), "alice")
+	require.ErrorContains(t, err, "node unavailable")
+
+	_, err = m.Reserve(context.Background(), "")
+	require.ErrorIs(t, err, types.ErrInvalidAccount)
+}
+
+func TestSequenceManager_QueryClientSource(t *testing.T) {
+	client, s := setupClient(t, 0)
+
+	account := types.NewAccount("alice", []byte("pubkey"))
+	account.Nonce = 42
+	data, err := json.Marshal(account)
+	require.NoError(t, err)
+	require.NoError(t, s.Set([]byte("alice"), data))
+	_, _, err = s.SaveVersion()
+	require.NoError(t, err)
+
+	m, err := NewSequenceManager(client)
+	require.NoError(t, err)
+
+	seq, err := m.Reserve(context.Background(), "alice")
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), seq)
+
+	_, err = m.Reserve(context.Background(), "bob")
+	require.ErrorIs(t, err, ErrQueryFailed)
+}
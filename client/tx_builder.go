@@ -0,0 +1,305 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// ErrNoSigningKey is returned when no keyring key signs for an account
+var ErrNoSigningKey = errors.New("no signing key for account")
+
+// KeyResolver returns the name of the keyring key that signs for account
+type KeyResolver func(account types.AccountName) (string, error)
+
+// KeyringAccountResolver resolves an account to the keyring key whose
+// metadata names it (see crypto.KeyMetadata.Account). Multisig entries are
+// skipped. Resolving fails with ErrNoSigningKey if no key names the
+// account, and if several do, since picking one would be arbitrary.
+func KeyringAccountResolver(kr crypto.Keyring) KeyResolver {
+	return func(account types.AccountName) (string, error) {
+		infos, err := kr.ListKeyInfo()
+		if err != nil {
+			return "", fmt.Errorf("failed to list keys: %w", err)
+		}
+
+		var match string
+		for _, info := range infos {
+			if info.Multisig != nil || info.Metadata == nil || info.Metadata.Account != string(account) {
+				continue
+			}
+			if match != "" {
+				return "", fmt.Errorf("%w: %s has several keys (%s, %s)", ErrNoSigningKey, account, match, info.Name)
+			}
+			match = info.Name
+		}
+		if match == "" {
+			return "", fmt.Errorf("%w: %s", ErrNoSigningKey, account)
+		}
+		return match, nil
+	}
+}
+
+// TxOptions are the optional fields of a built transaction
+type TxOptions struct {
+	Memo string
+	Fee  types.Fee
+
+	// FeeSlippage defaults to no slippage (0/1) if its denominator is zero
+	FeeSlippage types.Ratio
+
+	Tip        *types.Coin
+	FeeGranter types.AccountName
+}
+
+// TxBuilder builds signed transactions, resolving their signers from the
+// messages (see ResolveSigners) and signing for each with the keyring.
+//
+// Safe for concurrent use if its SequenceSource, Keyring and KeyResolver are.
+type TxBuilder struct {
+	chainID   string
+	sequences SequenceSource
+	keyring   crypto.Keyring
+	keyFor    KeyResolver
+}
+
+// NewTxBuilder creates a transaction builder for chainID. Sequences are
+// fetched from sequences (e.g. a *QueryClient); keyFor maps accounts to
+// keyring keys and defaults to KeyringAccountResolver(kr).
+func NewTxBuilder(chainID string, sequences SequenceSource, kr crypto.Keyring, keyFor KeyResolver) (*TxBuilder, error) {
+	if err := types.ValidateChainID(chainID); err != nil {
+		return nil, err
+	}
+	if sequences == nil {
+		return nil, fmt.Errorf("sequence source cannot be nil")
+	}
+	if kr == nil {
+		return nil, fmt.Errorf("keyring cannot be nil")
+	}
+	if keyFor == nil {
+		keyFor = KeyringAccountResolver(kr)
+	}
+
+	return &TxBuilder{chainID: chainID, sequences: sequences, keyring: kr, keyFor: keyFor}, nil
+}
+
+// ResolveSigners returns the accounts that must sign a transaction of msgs,
+// primary signer first: the first signer of the first message, then, for
+// each message none of whose signers is already listed, its first signer.
+// A message listing several signers is thus covered by one of them, as
+// types.Transaction.MsgSigner expects.
+//
+// Returns an error if a message has no signers or more than
+// types.MaxSigners accounts are needed.
+func ResolveSigners(msgs []types.Message) ([]types.AccountName, error) {
+	if len(msgs) == 0 {
+		return nil, fmt.Errorf("%w: transaction must have at least one message", types.ErrInvalidTransaction)
+	}
+
+	var signers []types.AccountName
+	seen := make(map[types.AccountName]struct{})
+	for i, msg := range msgs {
+		msgSigners := msg.GetSigners()
+		if len(msgSigners) == 0 {
+			return nil, fmt.Errorf("%w: message %d has no signers", types.ErrInvalidTransaction, i)
+		}
+
+		covered := false
+		for _, signer := range msgSigners {
+			if _, ok := seen[signer]; ok {
+				covered = true
+				break
+			}
+		}
+		if covered {
+			continue
+		}
+		seen[msgSigners[0]] = struct{}{}
+		signers = append(signers, msgSigners[0])
+	}
+
+	if len(signers) > types.MaxSigners {
+		return nil, fmt.Errorf("%w: %d signers exceed %d", types.ErrInvalidTransaction, len(signers), types.MaxSigners)
+	}
+	return signers, nil
+}
+
+// Build returns a transaction of msgs signed by every account ResolveSigners
+// finds: the first is the transaction's account, the others its co-signers,
+// each at its current chain sequence and signing its own SignDoc with
+// keyring.SignSignDoc (so key signing policies apply).
+//
+// POSTCONDITION: The transaction passes ValidateBasic.
+//
+// Sequences are read from the chain, so a second transaction from the same
+// accounts must not be built before the first is committed.
+func (b *TxBuilder) Build(ctx context.Context, msgs []types.Message, opts TxOptions) (*types.Transaction, error) {
+	return b.build(ctx, msgs, opts, b.sign)
+}
+
+// BuildForSimulation returns the transaction Build would, authorized by
+// placeholder signatures (see types.NewPlaceholderSignature) over each
+// signer's public key instead of real ones. Only key information is read
+// from the keyring (see crypto.Keyring.ListKeyInfo): no key signs, so no
+// password, signing policy or confirmation hook is involved.
+//
+// The transaction has the size and authorization layout of the signed one,
+// so gas simulated with it matches execution, but it fails signature
+// verification and must not be broadcast.
+func (b *TxBuilder) BuildForSimulation(ctx context.Context, msgs []types.Message, opts TxOptions) (*types.Transaction, error) {
+	return b.build(ctx, msgs, opts, b.placeholder)
+}
+
+// Resign returns a copy of tx, with fee, signed anew by its account and
+// co-signers at their nonces in tx. It replaces a pending transaction with
+// one the mempool ranks higher (see PendingTracker.ReplaceWithHigherFee).
+//
+// Transactions with a FeePayer are rejected: the builder does not sign for
+// fee payers.
+func (b *TxBuilder) Resign(tx *types.Transaction, fee types.Fee) (*types.Transaction, error) {
+	if b == nil {
+		return nil, fmt.Errorf("tx builder is nil")
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("%w: transaction is nil", types.ErrInvalidTransaction)
+	}
+	if tx.FeePayer != "" {
+		return nil, fmt.Errorf("%w: cannot re-sign for fee payer %s", types.ErrInvalidTransaction, tx.FeePayer)
+	}
+
+	resigned := types.NewTransaction(tx.Account, tx.Nonce, tx.Messages, nil)
+	resigned.Memo = tx.Memo
+	resigned.Fee = fee
+	resigned.FeeSlippage = tx.FeeSlippage
+	resigned.Tip = tx.Tip
+	resigned.FeeGranter = tx.FeeGranter
+	for _, cs := range tx.CoSigners {
+		resigned.CoSigners = append(resigned.CoSigners, types.CoSigner{Account: cs.Account, Nonce: cs.Nonce})
+	}
+
+	auth, err := b.sign(resigned, resigned.Account, resigned.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	resigned.Authorization = auth
+	for i := range resigned.CoSigners {
+		cs := &resigned.CoSigners[i]
+		if cs.Authorization, err = b.sign(resigned, cs.Account, cs.Nonce); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := resigned.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return resigned, nil
+}
+
+// build lays out the transaction of msgs and authorizes it with authorize
+func (b *TxBuilder) build(
+	ctx context.Context,
+	msgs []types.Message,
+	opts TxOptions,
+	authorize func(tx *types.Transaction, signer types.AccountName, sequence uint64) (*types.Authorization, error),
+) (*types.Transaction, error) {
+	if b == nil {
+		return nil, fmt.Errorf("tx builder is nil")
+	}
+
+	signers, err := ResolveSigners(msgs)
+	if err != nil {
+		return nil, err
+	}
+	sequences := make([]uint64, len(signers))
+	for i, signer := range signers {
+		if sequences[i], err = b.sequences.GetSequence(ctx, signer); err != nil {
+			return nil, fmt.Errorf("failed to get sequence of %s: %w", signer, err)
+		}
+	}
+
+	tx := types.NewTransaction(signers[0], sequences[0], msgs, nil)
+	tx.Memo = opts.Memo
+	tx.Fee = opts.Fee
+	tx.FeeSlippage = opts.FeeSlippage
+	if tx.FeeSlippage.Denominator == 0 {
+		tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+	}
+	tx.Tip = opts.Tip
+	tx.FeeGranter = opts.FeeGranter
+	for i, signer := range signers[1:] {
+		tx.CoSigners = append(tx.CoSigners, types.CoSigner{Account: signer, Nonce: sequences[i+1]})
+	}
+
+	// Every SignDoc carries the full signer list, so signatures are made
+	// only once the layout is final
+	for i, signer := range signers {
+		auth, err := authorize(tx, signer, sequences[i])
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			tx.Authorization = auth
+		} else {
+			tx.CoSigners[i-1].Authorization = auth
+		}
+	}
+
+	if err := tx.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// sign returns signer's authorization of its SignDoc of tx at sequence
+func (b *TxBuilder) sign(tx *types.Transaction, signer types.AccountName, sequence uint64) (*types.Authorization, error) {
+	keyName, err := b.keyFor(signer)
+	if err != nil {
+		return nil, err
+	}
+	key, err := b.keyring.GetKey(keyName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key of %s: %w", signer, err)
+	}
+
+	signDoc, err := tx.ToSignerSignDoc(b.chainID, signer, sequence)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := b.keyring.SignSignDoc(keyName, signDoc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign for %s: %w", signer, err)
+	}
+
+	return types.NewAuthorization(types.Signature{
+		Algorithm: key.Algorithm(),
+		PubKey:    key.PublicKey().Bytes(),
+		Signature: signature,
+	}), nil
+}
+
+// placeholder returns a placeholder authorization for signer's key
+func (b *TxBuilder) placeholder(tx *types.Transaction, signer types.AccountName, sequence uint64) (*types.Authorization, error) {
+	keyName, err := b.keyFor(signer)
+	if err != nil {
+		return nil, err
+	}
+	infos, err := b.keyring.ListKeyInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keys: %w", err)
+	}
+
+	for _, info := range infos {
+		if info.Name != keyName {
+			continue
+		}
+		signature, err := types.NewPlaceholderSignature(info.Algorithm, info.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to simulate signature of %s: %w", signer, err)
+		}
+		return types.NewAuthorization(signature), nil
+	}
+	return nil, fmt.Errorf("failed to get key of %s: %w", signer, crypto.ErrKeyNotFound)
+}
@@ -0,0 +1,207 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// transferMsg is a message signed by its sender
+type transferMsg struct {
+	From types.AccountName `json:"from"`
+	To   types.AccountName `json:"to"`
+}
+
+func (m *transferMsg) Type() string                          { return "/test.v1.MsgTransfer" }
+func (m *transferMsg) ValidateBasic() error                  { return nil }
+func (m *transferMsg) GetSigners() []types.AccountName       { return []types.AccountName{m.From} }
+func (m *transferMsg) SignDocData() (json.RawMessage, error) { return json.Marshal(m) }
+
+// jointMsg is a message any of its signers may authorize
+type jointMsg struct {
+	Signers []types.AccountName `json:"signers"`
+}
+
+func (m *jointMsg) Type() string                          { return "/test.v1.MsgJoint" }
+func (m *jointMsg) ValidateBasic() error                  { return nil }
+func (m *jointMsg) GetSigners() []types.AccountName       { return m.Signers }
+func (m *jointMsg) SignDocData() (json.RawMessage, error) { return json.Marshal(m) }
+
+// chainSequences returns per-account chain sequences
+type chainSequences map[types.AccountName]uint64
+
+func (s chainSequences) GetSequence(ctx context.Context, account types.AccountName) (uint64, error) {
+	seq, ok := s[account]
+	if !ok {
+		return 0, errors.New("account not found")
+	}
+	return seq, nil
+}
+
+// accountMap is an in-memory types.AccountGetter
+type accountMap map[types.AccountName]*types.Account
+
+func (m accountMap) GetAccount(name types.AccountName) (*types.Account, error) {
+	account, ok := m[name]
+	if !ok {
+		return nil, types.ErrNotFound
+	}
+	return account, nil
+}
+
+func send(from, to types.AccountName) *transferMsg {
+	return &transferMsg{From: from, To: to}
+}
+
+func TestResolveSigners(t *testing.T) {
+	tests := []struct {
+		name    string
+		msgs    []types.Message
+		want    []types.AccountName
+		wantErr bool
+	}{
+		{name: "single signer", msgs: []types.Message{send("alice", "bob"), send("alice", "carol")}, want: []types.AccountName{"alice"}},
+		{name: "in message order", msgs: []types.Message{send("bob", "alice"), send("alice", "bob")}, want: []types.AccountName{"bob", "alice"}},
+		{name: "deduplicated", msgs: []types.Message{send("alice", "bob"), send("bob", "alice"), send("alice", "carol")}, want: []types.AccountName{"alice", "bob"}},
+		{name: "joint message covered by a listed signer", msgs: []types.Message{
+			send("bob", "alice"), &jointMsg{Signers: []types.AccountName{"alice", "bob"}},
+		}, want: []types.AccountName{"bob"}},
+		{name: "joint message adds its first signer", msgs: []types.Message{
+			&jointMsg{Signers: []types.AccountName{"carol", "dave"}}, send("dave", "alice"),
+		}, want: []types.AccountName{"carol", "dave"}},
+		{name: "no messages", wantErr: true},
+		{name: "message without signers", msgs: []types.Message{&jointMsg{}}, wantErr: true},
+		{name: "too many signers", msgs: []types.Message{
+			send("a", "z"), send("b", "z"), send("c", "z"), send("d", "z"), send("e", "z"),
+			send("f", "z"), send("g", "z"), send("h", "z"), send("i", "z"),
+		}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signers, err := ResolveSigners(tt.msgs)
+			if tt.wantErr {
+				require.ErrorIs(t, err, types.ErrInvalidTransaction)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, signers)
+		})
+	}
+}
+
+// newBuilderKeyring returns a keyring with a key for each account, named
+// after it, and the accounts at the given sequences
+func newBuilderKeyring(t *testing.T, sequences chainSequences) (crypto.Keyring, accountMap) {
+	t.Helper()
+	kr := crypto.NewKeyring(crypto.NewMemoryStore())
+	accounts := make(accountMap)
+	for name, seq := range sequences {
+		signer, err := kr.NewKey("key-"+string(name), crypto.AlgorithmEd25519)
+		require.NoError(t, err)
+		require.NoError(t, kr.SetMetadata("key-"+string(name), &crypto.KeyMetadata{Account: string(name)}))
+
+		account := types.NewAccount(name, signer.PublicKey().Bytes())
+		account.Nonce = seq
+		accounts[name] = account
+	}
+	return kr, accounts
+}
+
+func TestTxBuilder_Build(t *testing.T) {
+	sequences := chainSequences{"alice": 7, "bob": 3}
+	kr, accounts := newBuilderKeyring(t, sequences)
+	builder, err := NewTxBuilder("punnet-1", sequences, kr, nil)
+	require.NoError(t, err)
+
+	t.Run("single signer", func(t *testing.T) {
+		tx, err := builder.Build(context.Background(), []types.Message{send("alice", "bob")}, TxOptions{Memo: "hi"})
+		require.NoError(t, err)
+		require.Equal(t, types.AccountName("alice"), tx.Account)
+		require.Equal(t, uint64(7), tx.Nonce)
+		require.Empty(t, tx.CoSigners)
+		require.Equal(t, "hi", tx.Memo)
+		require.NoError(t, tx.VerifyAuthorization("punnet-1", accounts["alice"], accounts))
+	})
+
+	t.Run("co-signed", func(t *testing.T) {
+		msgs := []types.Message{send("alice", "bob"), send("bob", "alice"), send("alice", "bob")}
+		tx, err := builder.Build(context.Background(), msgs, TxOptions{})
+		require.NoError(t, err)
+		require.Equal(t, []types.AccountName{"alice", "bob"}, tx.Signers())
+		require.Equal(t, uint64(3), tx.CoSigners[0].Nonce)
+		require.NoError(t, tx.VerifyAuthorization("punnet-1", accounts["alice"], accounts))
+		require.NoError(t, tx.VerifyCoSignerAuthorization("punnet-1", accounts["bob"], accounts))
+	})
+
+	t.Run("no key for a signer", func(t *testing.T) {
+		withCarol, err := NewTxBuilder("punnet-1", chainSequences{"alice": 7, "carol": 1}, kr, nil)
+		require.NoError(t, err)
+		_, err = withCarol.Build(context.Background(), []types.Message{send("alice", "bob"), send("carol", "bob")}, TxOptions{})
+		require.ErrorIs(t, err, ErrNoSigningKey)
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		_, err := builder.Build(context.Background(), []types.Message{send("carol", "bob")}, TxOptions{})
+		require.Error(t, err)
+	})
+}
+
+func TestTxBuilder_BuildForSimulation(t *testing.T) {
+	sequences := chainSequences{"alice": 7, "bob": 3}
+	kr, accounts := newBuilderKeyring(t, sequences)
+	builder, err := NewTxBuilder("punnet-1", sequences, kr, nil)
+	require.NoError(t, err)
+	msgs := []types.Message{send("alice", "bob"), send("bob", "alice")}
+
+	simulated, err := builder.BuildForSimulation(context.Background(), msgs, TxOptions{Memo: "hi"})
+	require.NoError(t, err)
+	signed, err := builder.Build(context.Background(), msgs, TxOptions{Memo: "hi"})
+	require.NoError(t, err)
+
+	require.Equal(t, signed.Signers(), simulated.Signers())
+	require.True(t, simulated.Authorization.Signatures[0].IsPlaceholder())
+	require.True(t, simulated.CoSigners[0].Authorization.Signatures[0].IsPlaceholder())
+	require.Equal(t, signed.Authorization.Signatures[0].PubKey, simulated.Authorization.Signatures[0].PubKey)
+
+	simulatedBytes, err := simulated.Encode()
+	require.NoError(t, err)
+	signedBytes, err := signed.Encode()
+	require.NoError(t, err)
+	require.Len(t, simulatedBytes, len(signedBytes), "simulated transactions must have the signed size")
+
+	err = simulated.VerifyAuthorization("punnet-1", accounts["alice"], accounts)
+	require.ErrorIs(t, err, types.ErrInvalidSignature)
+}
+
+func TestTxBuilder_Resign(t *testing.T) {
+	sequences := chainSequences{"alice": 7, "bob": 3}
+	kr, accounts := newBuilderKeyring(t, sequences)
+	builder, err := NewTxBuilder("punnet-1", sequences, kr, nil)
+	require.NoError(t, err)
+
+	tx, err := builder.Build(context.Background(), []types.Message{send("alice", "bob"), send("bob", "alice")}, TxOptions{Memo: "hi"})
+	require.NoError(t, err)
+
+	// The chain has moved on; the re-signed transaction keeps tx's nonces
+	sequences["alice"], sequences["bob"] = 9, 4
+	fee := types.Fee{Amount: types.NewCoins(types.NewCoin("stake", 5)), GasLimit: 1}
+	resigned, err := builder.Resign(tx, fee)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), resigned.Nonce)
+	require.Equal(t, uint64(3), resigned.CoSigners[0].Nonce)
+	require.Equal(t, fee, resigned.Fee)
+	require.Equal(t, "hi", resigned.Memo)
+	require.NoError(t, resigned.VerifyAuthorization("punnet-1", accounts["alice"], accounts))
+	require.NoError(t, resigned.VerifyCoSignerAuthorization("punnet-1", accounts["bob"], accounts))
+
+	tx.FeePayer = "bob"
+	_, err = builder.Resign(tx, fee)
+	require.ErrorIs(t, err, types.ErrInvalidTransaction)
+}
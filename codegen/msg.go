@@ -0,0 +1,399 @@
+// Package codegen generates boilerplate for Punnet SDK modules.
+//
+// GenerateMsg emits the SignDocSerializable implementation of message structs
+// (see cmd/punnet-gen). The generated SignDocData encodes fields as a JSON
+// object with keys in sorted order, numbers as decimal strings and strings
+// NFC-normalized, matching the canonical SignDoc encoding.
+package codegen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TypesImportPath is the import path of the Punnet SDK types package
+const TypesImportPath = "github.com/blockberries/punnet-sdk/types"
+
+var (
+	// ErrUnsupportedType is returned for message fields the generator cannot encode
+	ErrUnsupportedType = errors.New("unsupported field type")
+
+	// ErrInvalidTag is returned for malformed punnet field tags
+	ErrInvalidTag = errors.New("invalid field tag")
+
+	// ErrMessageNotFound is returned when a requested type is not a struct in the source
+	ErrMessageNotFound = errors.New("message type not found")
+)
+
+// MsgOptions configures GenerateMsg
+type MsgOptions struct {
+	// Types lists the message struct names to generate for
+	Types []string
+
+	// TypePrefix is prepended to a message name to form its Type() URL,
+	// e.g. "/punnet.bank.v1."
+	TypePrefix string
+
+	// SkipValidateBasic omits ValidateBasic, for messages that implement it
+	// by hand (typically calling the generated validateFields)
+	SkipValidateBasic bool
+}
+
+// MsgOutput is the generated code for one source file
+type MsgOutput struct {
+	// Code is the gofmt-formatted message implementation
+	Code []byte
+
+	// Tests is the gofmt-formatted determinism test file
+	Tests []byte
+}
+
+// fieldKind is the encoding class of a message field
+type fieldKind int
+
+const (
+	kindString fieldKind = iota
+	kindAccount
+	kindInt
+	kindUint
+	kindBool
+	kindBytes
+	kindStrings
+	kindAccounts
+	kindCoin
+	kindCoins
+)
+
+// builtinKinds maps predeclared type names to their encoding class
+var builtinKinds = map[string]fieldKind{
+	"string": kindString,
+	"bool":   kindBool,
+	"int":    kindInt,
+	"int8":   kindInt,
+	"int16":  kindInt,
+	"int32":  kindInt,
+	"int64":  kindInt,
+	"uint":   kindUint,
+	"uint8":  kindUint,
+	"uint16": kindUint,
+	"uint32": kindUint,
+	"uint64": kindUint,
+}
+
+// msgField is a message field included in SignDocData
+type msgField struct {
+	name     string
+	key      string
+	kind     fieldKind
+	typeExpr string
+	signer   bool
+	required bool
+	optional bool
+}
+
+// msgType is a parsed message struct
+type msgType struct {
+	name string
+
+	// fields are sorted by JSON key
+	fields []*msgField
+}
+
+// msgFile is the parse result of one source file
+type msgFile struct {
+	pkg        string
+	source     string
+	typesAlias string
+	messages   []*msgType
+}
+
+// GenerateMsg parses the Go source file filename (src is its content) and
+// generates Type, SignDocData, GetSigners and ValidateBasic for each
+// requested message struct, along with their tests.
+//
+// Fields are controlled by struct tags:
+//   - json:"name" sets the SignDoc key (the Go field name if absent); json:"-"
+//     and unexported fields are skipped
+//   - punnet:"signer" makes a types.AccountName or []types.AccountName field a
+//     signer; GetSigners is generated only if a field is tagged
+//   - punnet:"required" rejects zero values in ValidateBasic
+//   - punnet:"optional" validates an account field only when it is set
+//   - punnet:"-" excludes the field from SignDocData and validation
+//
+// SECURITY: A field excluded with punnet:"-" is not bound by signatures. Only
+// exclude fields that are derived from signed fields or do not affect
+// execution.
+//
+// Supported field types are strings, integers, bools, []byte, string slices,
+// types.AccountName, types.Coin, types.Coins, and types declared in the same
+// file whose underlying type is one of these.
+func GenerateMsg(filename string, src []byte, opts MsgOptions) (*MsgOutput, error) {
+	if len(opts.Types) == 0 {
+		return nil, fmt.Errorf("no message types given")
+	}
+	if opts.TypePrefix == "" {
+		return nil, fmt.Errorf("type prefix cannot be empty")
+	}
+
+	file, err := parseMsgFile(filename, src, opts.Types)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := formatSource(file.source, renderMsgCode(file, opts))
+	if err != nil {
+		return nil, err
+	}
+	tests, err := formatSource(file.source, renderMsgTests(file, opts))
+	if err != nil {
+		return nil, err
+	}
+
+	return &MsgOutput{Code: code, Tests: tests}, nil
+}
+
+// parseMsgFile parses the requested message structs of a source file
+func parseMsgFile(filename string, src []byte, names []string) (*msgFile, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &msgFile{
+		pkg:        f.Name.Name,
+		source:     filepath.Base(filename),
+		typesAlias: "types",
+	}
+	for _, imp := range f.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		if path == TypesImportPath && imp.Name != nil {
+			file.typesAlias = imp.Name.Name
+		}
+	}
+
+	decls := make(map[string]ast.Expr)
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			decls[ts.Name.Name] = ts.Type
+		}
+	}
+
+	r := &resolver{fset: fset, decls: decls, typesAlias: file.typesAlias}
+	for _, name := range names {
+		st, ok := decls[name].(*ast.StructType)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not a struct in %s", ErrMessageNotFound, name, file.source)
+		}
+		msg, err := r.message(name, st)
+		if err != nil {
+			return nil, err
+		}
+		file.messages = append(file.messages, msg)
+	}
+	return file, nil
+}
+
+// resolver classifies field types of one source file
+type resolver struct {
+	fset       *token.FileSet
+	decls      map[string]ast.Expr
+	typesAlias string
+}
+
+// message parses the fields of a message struct
+func (r *resolver) message(name string, st *ast.StructType) (*msgType, error) {
+	msg := &msgType{name: name}
+	keys := make(map[string]string)
+
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 {
+			return nil, fmt.Errorf("%s: %w: embedded field %s", name, ErrUnsupportedType, r.exprString(field.Type))
+		}
+
+		tag := reflect.StructTag("")
+		if field.Tag != nil {
+			raw, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w: %v", name, ErrInvalidTag, err)
+			}
+			tag = reflect.StructTag(raw)
+		}
+
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			f, err := r.field(ident.Name, field.Type, tag)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", name, ident.Name, err)
+			}
+			if f == nil {
+				continue
+			}
+			if prev, ok := keys[f.key]; ok {
+				return nil, fmt.Errorf("%s.%s: %w: key %q already used by %s", name, ident.Name, ErrInvalidTag, f.key, prev)
+			}
+			keys[f.key] = ident.Name
+			msg.fields = append(msg.fields, f)
+		}
+	}
+
+	sort.Slice(msg.fields, func(i, j int) bool {
+		return msg.fields[i].key < msg.fields[j].key
+	})
+	return msg, nil
+}
+
+// field parses one field, returning nil if it is excluded from SignDocData
+func (r *resolver) field(name string, typ ast.Expr, tag reflect.StructTag) (*msgField, error) {
+	key := name
+	if jsonTag, ok := tag.Lookup("json"); ok {
+		jsonName, _, _ := strings.Cut(jsonTag, ",")
+		if jsonName == "-" {
+			return nil, nil
+		}
+		if jsonName != "" {
+			key = jsonName
+		}
+	}
+
+	f := &msgField{name: name, key: key, typeExpr: r.exprString(typ)}
+	if punnetTag, ok := tag.Lookup("punnet"); ok {
+		if punnetTag == "-" {
+			return nil, nil
+		}
+		for _, opt := range strings.Split(punnetTag, ",") {
+			switch opt {
+			case "signer":
+				f.signer = true
+			case "required":
+				f.required = true
+			case "optional":
+				f.optional = true
+			default:
+				return nil, fmt.Errorf("%w: unknown punnet option %q", ErrInvalidTag, opt)
+			}
+		}
+	}
+
+	kind, err := r.kind(typ, 0)
+	if err != nil {
+		return nil, err
+	}
+	f.kind = kind
+
+	switch {
+	case f.signer && kind != kindAccount && kind != kindAccounts:
+		return nil, fmt.Errorf("%w: signer field must be an account name or account name slice", ErrInvalidTag)
+	case f.required && f.optional:
+		return nil, fmt.Errorf("%w: field cannot be both required and optional", ErrInvalidTag)
+	case f.required && kind == kindBool:
+		return nil, fmt.Errorf("%w: bool field cannot be required", ErrInvalidTag)
+	case f.optional && kind != kindAccount:
+		return nil, fmt.Errorf("%w: only account name fields can be optional", ErrInvalidTag)
+	}
+	return f, nil
+}
+
+// maxTypeDepth bounds the resolution of named types declared in the file
+const maxTypeDepth = 8
+
+// kind classifies a field type
+func (r *resolver) kind(typ ast.Expr, depth int) (fieldKind, error) {
+	if depth > maxTypeDepth {
+		return 0, fmt.Errorf("%w: type nesting too deep", ErrUnsupportedType)
+	}
+
+	switch t := typ.(type) {
+	case *ast.Ident:
+		if kind, ok := builtinKinds[t.Name]; ok {
+			return kind, nil
+		}
+		if decl, ok := r.decls[t.Name]; ok {
+			return r.kind(decl, depth+1)
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok && pkg.Name == r.typesAlias {
+			switch t.Sel.Name {
+			case "AccountName":
+				return kindAccount, nil
+			case "Coin":
+				return kindCoin, nil
+			case "Coins":
+				return kindCoins, nil
+			}
+		}
+	case *ast.ArrayType:
+		if t.Len != nil {
+			break
+		}
+		if elt, ok := t.Elt.(*ast.Ident); ok && (elt.Name == "byte" || elt.Name == "uint8") {
+			return kindBytes, nil
+		}
+		elt, err := r.kind(t.Elt, depth+1)
+		if err != nil {
+			break
+		}
+		switch elt {
+		case kindString:
+			return kindStrings, nil
+		case kindAccount:
+			return kindAccounts, nil
+		case kindCoin:
+			return kindCoins, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", ErrUnsupportedType, r.exprString(typ))
+}
+
+// exprString renders a type expression as source
+func (r *resolver) exprString(expr ast.Expr) string {
+	var buf bytes.Buffer
+	_ = printer.Fprint(&buf, r.fset, expr)
+	return buf.String()
+}
+
+// formatSource gofmts generated source, reporting the source file on failure
+func formatSource(source string, src []byte) ([]byte, error) {
+	out, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("formatting code generated from %s: %w", source, err)
+	}
+	return out, nil
+}
+
+// WriteFile writes data to path unless the file already has that content, and
+// reports whether it was written. Skipping unchanged files keeps go:generate
+// from touching modification times.
+func WriteFile(path string, data []byte) (bool, error) {
+	existing, err := os.ReadFile(path)
+	if err == nil && bytes.Equal(existing, data) {
+		return false, nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return true, nil
+}
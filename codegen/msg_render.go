@@ -0,0 +1,364 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// generatedHeader marks generated files (see https://go.dev/s/generatedcode)
+const generatedHeader = "// Code generated by punnet-gen msg from %s. DO NOT EDIT.\n\n"
+
+// sampleAccounts are the account names used in generated test messages
+var sampleAccounts = []string{"alice", "bob", "carol", "dave", "erin"}
+
+// renderMsgCode renders the unformatted message implementation
+func renderMsgCode(file *msgFile, opts MsgOptions) []byte {
+	var body bytes.Buffer
+	needBase64, needStrconv := false, false
+	t := file.typesAlias
+
+	fmt.Fprintf(&body, "const (\n")
+	for _, msg := range file.messages {
+		fmt.Fprintf(&body, "// Type%s is the type URL of %s\n", msg.name, msg.name)
+		fmt.Fprintf(&body, "Type%s = %s\n", msg.name, strconv.Quote(opts.TypePrefix+msg.name))
+	}
+	fmt.Fprintf(&body, ")\n\n")
+
+	fmt.Fprintf(&body, "var (\n")
+	for _, msg := range file.messages {
+		fmt.Fprintf(&body, "_ %s.SignDocSerializable = (*%s)(nil)\n", t, msg.name)
+	}
+	fmt.Fprintf(&body, ")\n")
+
+	for _, msg := range file.messages {
+		m := msg.name
+
+		fmt.Fprintf(&body, "\n// Type returns the message type identifier\n")
+		fmt.Fprintf(&body, "func (m *%s) Type() string {\nreturn Type%s\n}\n", m, m)
+
+		fmt.Fprintf(&body, "\n// SignDocData returns the canonical JSON representation of the message for\n")
+		fmt.Fprintf(&body, "// SignDoc: keys sorted, numbers as decimal strings, strings NFC-normalized\n")
+		fmt.Fprintf(&body, "func (m *%s) SignDocData() (json.RawMessage, error) {\n", m)
+		fmt.Fprintf(&body, "if m == nil {\nreturn nil, fmt.Errorf(\"message is nil\")\n}\n\n")
+		fmt.Fprintf(&body, "data := struct {\n")
+		for _, f := range msg.fields {
+			fmt.Fprintf(&body, "%s %s `json:%s`\n", f.name, signDocType(f, t), strconv.Quote(f.key))
+		}
+		fmt.Fprintf(&body, "}{\n")
+		for _, f := range msg.fields {
+			switch f.kind {
+			case kindBytes:
+				needBase64 = true
+			case kindInt, kindUint:
+				needStrconv = true
+			}
+			fmt.Fprintf(&body, "%s: %s,\n", f.name, signDocValue(f, t))
+		}
+		fmt.Fprintf(&body, "}\nreturn json.Marshal(data)\n}\n")
+
+		renderGetSigners(&body, msg, t)
+
+		if !opts.SkipValidateBasic {
+			fmt.Fprintf(&body, "\n// ValidateBasic performs stateless validation\n")
+			fmt.Fprintf(&body, "func (m *%s) ValidateBasic() error {\nreturn m.validateFields()\n}\n", m)
+		}
+		renderValidateFields(&body, msg, t)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, generatedHeader, file.source)
+	fmt.Fprintf(&out, "package %s\n\nimport (\n", file.pkg)
+	if needBase64 {
+		fmt.Fprintf(&out, "\"encoding/base64\"\n")
+	}
+	fmt.Fprintf(&out, "\"encoding/json\"\n\"fmt\"\n")
+	if needStrconv {
+		fmt.Fprintf(&out, "\"strconv\"\n")
+	}
+	fmt.Fprintf(&out, "\n%s\n)\n\n", typesImport(t))
+	out.Write(body.Bytes())
+	return out.Bytes()
+}
+
+// signDocType returns the type of a field in the SignDocData struct
+func signDocType(f *msgField, t string) string {
+	switch f.kind {
+	case kindBool:
+		return "bool"
+	case kindStrings, kindAccounts:
+		return "[]string"
+	case kindCoin:
+		return t + ".SignDocCoin"
+	case kindCoins:
+		return "[]" + t + ".SignDocCoin"
+	default:
+		return "string"
+	}
+}
+
+// signDocValue returns the canonical encoding expression of a field
+func signDocValue(f *msgField, t string) string {
+	v := "m." + f.name
+	switch f.kind {
+	case kindString, kindAccount:
+		return fmt.Sprintf("%s.SignDocString(%s)", t, v)
+	case kindInt:
+		if f.typeExpr != "int64" {
+			v = "int64(" + v + ")"
+		}
+		return fmt.Sprintf("strconv.FormatInt(%s, 10)", v)
+	case kindUint:
+		if f.typeExpr != "uint64" {
+			v = "uint64(" + v + ")"
+		}
+		return fmt.Sprintf("strconv.FormatUint(%s, 10)", v)
+	case kindBytes:
+		return fmt.Sprintf("base64.StdEncoding.EncodeToString(%s)", v)
+	case kindStrings, kindAccounts:
+		return fmt.Sprintf("%s.SignDocStrings(%s)", t, v)
+	case kindCoin:
+		return fmt.Sprintf("%s.NewSignDocCoin(%s)", t, convert(f, t, "Coin", v))
+	case kindCoins:
+		return fmt.Sprintf("%s.NewSignDocCoins(%s)", t, convert(f, t, "Coins", v))
+	default:
+		return v
+	}
+}
+
+// renderGetSigners renders GetSigners if any field is tagged as a signer
+func renderGetSigners(body *bytes.Buffer, msg *msgType, t string) {
+	var signers []*msgField
+	hasSlice := false
+	for _, f := range msg.fields {
+		if !f.signer {
+			continue
+		}
+		if f.kind == kindAccounts {
+			hasSlice = true
+		}
+		signers = append(signers, f)
+	}
+	if len(signers) == 0 {
+		return
+	}
+
+	fmt.Fprintf(body, "\n// GetSigners returns the accounts that must authorize this message\n")
+	fmt.Fprintf(body, "func (m *%s) GetSigners() []%s.AccountName {\n", msg.name, t)
+	fmt.Fprintf(body, "if m == nil {\nreturn nil\n}\n")
+
+	if !hasSlice {
+		values := make([]string, len(signers))
+		for i, f := range signers {
+			values[i] = convert(f, t, "AccountName", "m."+f.name)
+		}
+		fmt.Fprintf(body, "return []%s.AccountName{%s}\n}\n", t, strings.Join(values, ", "))
+		return
+	}
+
+	fmt.Fprintf(body, "var signers []%s.AccountName\n", t)
+	for _, f := range msg.fields {
+		switch {
+		case !f.signer:
+		case f.kind == kindAccounts:
+			if f.typeExpr == "[]"+t+".AccountName" {
+				fmt.Fprintf(body, "signers = append(signers, m.%s...)\n", f.name)
+			} else {
+				fmt.Fprintf(body, "for _, signer := range m.%s {\nsigners = append(signers, %s.AccountName(signer))\n}\n", f.name, t)
+			}
+		default:
+			fmt.Fprintf(body, "signers = append(signers, %s)\n", convert(f, t, "AccountName", "m."+f.name))
+		}
+	}
+	fmt.Fprintf(body, "return signers\n}\n")
+}
+
+// renderValidateFields renders the checks implied by field types and tags
+func renderValidateFields(body *bytes.Buffer, msg *msgType, t string) {
+	fmt.Fprintf(body, "\n// validateFields checks the account names, coins and required fields of the\n")
+	fmt.Fprintf(body, "// message\n")
+	fmt.Fprintf(body, "func (m *%s) validateFields() error {\n", msg.name)
+	fmt.Fprintf(body, "if m == nil {\nreturn fmt.Errorf(\"message is nil\")\n}\n")
+
+	for _, f := range msg.fields {
+		v := "m." + f.name
+		switch f.kind {
+		case kindAccount:
+			cond := fmt.Sprintf("!%s.IsValid()", convert(f, t, "AccountName", v))
+			if f.optional {
+				cond = fmt.Sprintf("%s != \"\" && %s", v, cond)
+			}
+			fmt.Fprintf(body, "\nif %s {\nreturn fmt.Errorf(\"%%w: invalid %s %%s\", %s.ErrInvalidAccount, %s)\n}\n", cond, f.key, t, v)
+		case kindAccounts:
+			if f.required {
+				renderEmptyCheck(body, f)
+			}
+			elem := "account"
+			if f.typeExpr != "[]"+t+".AccountName" {
+				elem = t + ".AccountName(account)"
+			}
+			fmt.Fprintf(body, "\nfor i, account := range %s {\nif !%s.IsValid() {\n", v, elem)
+			fmt.Fprintf(body, "return fmt.Errorf(\"%%w: invalid %s[%%d] %%s\", %s.ErrInvalidAccount, i, account)\n}\n}\n", f.key, t)
+		case kindCoin:
+			fmt.Fprintf(body, "\nif !%s.IsValid() {\nreturn fmt.Errorf(\"invalid %s\")\n}\n", convert(f, t, "Coin", v), f.key)
+			if f.required {
+				fmt.Fprintf(body, "\nif !%s.IsPositive() {\nreturn fmt.Errorf(\"%s must be positive\")\n}\n", convert(f, t, "Coin", v), f.key)
+			}
+		case kindCoins:
+			fmt.Fprintf(body, "\nif !%s.IsValid() {\nreturn fmt.Errorf(\"invalid %s\")\n}\n", convert(f, t, "Coins", v), f.key)
+			if f.required {
+				fmt.Fprintf(body, "\nif !%s.IsAllPositive() {\nreturn fmt.Errorf(\"%s must be positive\")\n}\n", convert(f, t, "Coins", v), f.key)
+			}
+		case kindString:
+			if f.required {
+				fmt.Fprintf(body, "\nif %s == \"\" {\nreturn fmt.Errorf(\"%s cannot be empty\")\n}\n", v, f.key)
+			}
+		case kindInt, kindUint:
+			if f.required {
+				fmt.Fprintf(body, "\nif %s == 0 {\nreturn fmt.Errorf(\"%s cannot be zero\")\n}\n", v, f.key)
+			}
+		case kindBytes, kindStrings:
+			if f.required {
+				renderEmptyCheck(body, f)
+			}
+		}
+	}
+	fmt.Fprintf(body, "\nreturn nil\n}\n")
+}
+
+// convert returns the expression v, of field f, as the types package type
+// name, converting only if the field has a different declared type
+func convert(f *msgField, t, name, v string) string {
+	if f.typeExpr == t+"."+name {
+		return v
+	}
+	return fmt.Sprintf("%s.%s(%s)", t, name, v)
+}
+
+// renderEmptyCheck renders the required check of a slice field
+func renderEmptyCheck(body *bytes.Buffer, f *msgField) {
+	fmt.Fprintf(body, "\nif len(m.%s) == 0 {\nreturn fmt.Errorf(\"%s cannot be empty\")\n}\n", f.name, f.key)
+}
+
+// renderMsgTests renders the unformatted test file
+func renderMsgTests(file *msgFile, opts MsgOptions) []byte {
+	var body bytes.Buffer
+	needBytes, needTypes := false, false
+
+	validate := "ValidateBasic"
+	if opts.SkipValidateBasic {
+		validate = "validateFields"
+	}
+
+	for _, msg := range file.messages {
+		m := msg.name
+
+		fmt.Fprintf(&body, "\n// newTest%s returns a %s with every field set\n", m, m)
+		fmt.Fprintf(&body, "func newTest%s() *%s {\nreturn &%s{\n", m, m, m)
+		accounts, number := 0, 0
+		for _, f := range msg.fields {
+			value, usesTypes := sampleValue(f, file.typesAlias, &accounts, &number)
+			needTypes = needTypes || usesTypes
+			fmt.Fprintf(&body, "%s: %s,\n", f.name, value)
+		}
+		fmt.Fprintf(&body, "}\n}\n")
+
+		fmt.Fprintf(&body, "\nfunc Test%s_SignDocDataDeterminism(t *testing.T) {\n", m)
+		fmt.Fprintf(&body, "msg := newTest%s()\n", m)
+		fmt.Fprintf(&body, "punnettesting.AssertSignDocDataValid(t, msg)\n")
+		fmt.Fprintf(&body, "punnettesting.AssertSignDocDataDeterminism(t, msg, 100)\n")
+		fmt.Fprintf(&body, "punnettesting.AssertSignDocDataDeterminismConcurrent(t, msg, 10, 100)\n\n")
+		fmt.Fprintf(&body, "// The zero message is deterministic too\n")
+		fmt.Fprintf(&body, "punnettesting.AssertSignDocDataDeterminism(t, &%s{}, 10)\n}\n", m)
+
+		var stringFields []string
+		for _, f := range msg.fields {
+			if f.kind == kindString || f.kind == kindAccount {
+				stringFields = append(stringFields, f.name)
+			}
+		}
+		if len(stringFields) > 0 {
+			needBytes = true
+			fmt.Fprintf(&body, "\nfunc Test%s_SignDocDataNFC(t *testing.T) {\n", m)
+			fmt.Fprintf(&body, "composed, decomposed := newTest%s(), newTest%s()\n", m, m)
+			for _, name := range stringFields {
+				fmt.Fprintf(&body, "composed.%s, decomposed.%s = \"caf\\u00e9\", \"cafe\\u0301\"\n", name, name)
+			}
+			fmt.Fprintf(&body, "\nwant, err := composed.SignDocData()\n")
+			fmt.Fprintf(&body, "if err != nil {\nt.Fatalf(\"SignDocData failed: %%v\", err)\n}\n")
+			fmt.Fprintf(&body, "got, err := decomposed.SignDocData()\n")
+			fmt.Fprintf(&body, "if err != nil {\nt.Fatalf(\"SignDocData failed: %%v\", err)\n}\n")
+			fmt.Fprintf(&body, "if !bytes.Equal(got, want) {\n")
+			fmt.Fprintf(&body, "t.Fatalf(\"SignDocData depends on normalization form:\\ngot:  %%s\\nwant: %%s\", got, want)\n}\n}\n")
+		}
+
+		fmt.Fprintf(&body, "\nfunc Test%s_%s(t *testing.T) {\n", m, strings.ToUpper(validate[:1])+validate[1:])
+		fmt.Fprintf(&body, "if err := newTest%s().%s(); err != nil {\n", m, validate)
+		fmt.Fprintf(&body, "t.Fatalf(\"expected valid message, got %%v\", err)\n}\n\n")
+		fmt.Fprintf(&body, "var nilMsg *%s\n", m)
+		fmt.Fprintf(&body, "if err := nilMsg.%s(); err == nil {\n", validate)
+		fmt.Fprintf(&body, "t.Fatal(\"expected error for nil message\")\n}\n}\n")
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, generatedHeader, file.source)
+	fmt.Fprintf(&out, "package %s\n\nimport (\n", file.pkg)
+	if needBytes {
+		fmt.Fprintf(&out, "\"bytes\"\n")
+	}
+	fmt.Fprintf(&out, "\"testing\"\n\n")
+	fmt.Fprintf(&out, "punnettesting \"github.com/blockberries/punnet-sdk/testing\"\n")
+	if needTypes {
+		fmt.Fprintf(&out, "%s\n", typesImport(file.typesAlias))
+	}
+	fmt.Fprintf(&out, ")\n")
+	out.Write(body.Bytes())
+	return out.Bytes()
+}
+
+// sampleValue returns a valid, non-zero value for a field and whether it
+// refers to the types package
+func sampleValue(f *msgField, t string, accounts, number *int) (string, bool) {
+	nextAccount := func() string {
+		name := sampleAccounts[*accounts%len(sampleAccounts)]
+		if *accounts >= len(sampleAccounts) {
+			name += strconv.Itoa(*accounts / len(sampleAccounts))
+		}
+		*accounts++
+		return strconv.Quote(name)
+	}
+	usesTypes := strings.HasPrefix(f.typeExpr, t+".") || strings.Contains(f.typeExpr, "]"+t+".")
+
+	switch f.kind {
+	case kindString:
+		return strconv.Quote(f.key + " value"), false
+	case kindAccount:
+		return nextAccount(), false
+	case kindInt, kindUint:
+		*number++
+		return strconv.Itoa(*number), false
+	case kindBool:
+		return "true", false
+	case kindBytes:
+		return fmt.Sprintf("[]byte(%s)", strconv.Quote(f.key)), false
+	case kindStrings:
+		return fmt.Sprintf("%s{%s}", f.typeExpr, strconv.Quote(f.key+" value")), usesTypes
+	case kindAccounts:
+		return fmt.Sprintf("%s{%s, %s}", f.typeExpr, nextAccount(), nextAccount()), usesTypes
+	case kindCoin:
+		return fmt.Sprintf("%s{Denom: \"stake\", Amount: 100}", f.typeExpr), usesTypes
+	case kindCoins:
+		return fmt.Sprintf("%s{{Denom: \"stake\", Amount: 100}}", f.typeExpr), usesTypes
+	default:
+		return "", false
+	}
+}
+
+// typesImport returns the import spec of the types package under alias t
+func typesImport(t string) string {
+	if t == "types" {
+		return strconv.Quote(TypesImportPath)
+	}
+	return t + " " + strconv.Quote(TypesImportPath)
+}
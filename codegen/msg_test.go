@@ -0,0 +1,188 @@
+package codegen
+
+import (
+	"bytes"
+	"errors"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleSource = `package sample
+
+import ptypes "github.com/blockberries/punnet-sdk/types"
+
+type Label string
+
+type MsgSample struct {
+	Zeta     string             ` + "`json:\"zeta\"`" + `
+	Owner    ptypes.AccountName ` + "`json:\"owner\" punnet:\"signer\"`" + `
+	Amount   ptypes.Coin        ` + "`json:\"amount\" punnet:\"required\"`" + `
+	Count    int                ` + "`json:\"count\"`" + `
+	Labels   []Label            ` + "`json:\"labels\"`" + `
+	Internal string             ` + "`json:\"-\"`" + `
+	Derived  string             ` + "`json:\"derived\" punnet:\"-\"`" + `
+	NoTag    bool
+	hidden   string
+}
+`
+
+func generateSample(t *testing.T, opts MsgOptions) *MsgOutput {
+	t.Helper()
+
+	if opts.Types == nil {
+		opts.Types = []string{"MsgSample"}
+	}
+	if opts.TypePrefix == "" {
+		opts.TypePrefix = "/punnet.sample.v1."
+	}
+	out, err := GenerateMsg("sample.go", []byte(sampleSource), opts)
+	if err != nil {
+		t.Fatalf("GenerateMsg failed: %v", err)
+	}
+	for name, src := range map[string][]byte{"code": out.Code, "tests": out.Tests} {
+		if _, err := parser.ParseFile(token.NewFileSet(), name+".go", src, 0); err != nil {
+			t.Fatalf("generated %s does not parse: %v\n%s", name, err, src)
+		}
+	}
+	return out
+}
+
+func TestGenerateMsg_SortedKeys(t *testing.T) {
+	code := string(generateSample(t, MsgOptions{}).Code)
+
+	keys := []string{"NoTag", "amount", "count", "labels", "owner", "zeta"}
+	last := -1
+	for _, key := range keys {
+		i := strings.Index(code, "`json:\""+key+"\"`")
+		if i < 0 {
+			t.Fatalf("key %q missing from generated code:\n%s", key, code)
+		}
+		if i < last {
+			t.Fatalf("key %q out of order in generated code:\n%s", key, code)
+		}
+		last = i
+	}
+
+	for _, excluded := range []string{"Internal", "Derived", "hidden"} {
+		if strings.Contains(code, "m."+excluded) {
+			t.Errorf("excluded field %s appears in generated code", excluded)
+		}
+	}
+}
+
+func TestGenerateMsg_Encoding(t *testing.T) {
+	code := string(generateSample(t, MsgOptions{}).Code)
+
+	for _, want := range []string{
+		`TypeMsgSample = "/punnet.sample.v1.MsgSample"`,
+		"ptypes.NewSignDocCoin(m.Amount)",
+		"strconv.FormatInt(int64(m.Count), 10)",
+		"ptypes.SignDocStrings(m.Labels)",
+		"ptypes.SignDocString(m.Owner)",
+		"return []ptypes.AccountName{m.Owner}",
+		"func (m *MsgSample) ValidateBasic() error",
+		"m.Amount.IsPositive()",
+		`ptypes "github.com/blockberries/punnet-sdk/types"`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code lacks %q:\n%s", want, code)
+		}
+	}
+}
+
+func TestGenerateMsg_SkipValidateBasic(t *testing.T) {
+	out := generateSample(t, MsgOptions{SkipValidateBasic: true})
+
+	if bytes.Contains(out.Code, []byte("ValidateBasic")) {
+		t.Errorf("generated code contains ValidateBasic:\n%s", out.Code)
+	}
+	if !bytes.Contains(out.Code, []byte("func (m *MsgSample) validateFields() error")) {
+		t.Errorf("generated code lacks validateFields:\n%s", out.Code)
+	}
+	if !bytes.Contains(out.Tests, []byte("newTestMsgSample().validateFields()")) {
+		t.Errorf("generated tests do not call validateFields:\n%s", out.Tests)
+	}
+}
+
+func TestGenerateMsg_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		want  error
+	}{
+		{"map", "F map[string]string", ErrUnsupportedType},
+		{"float", "F float64", ErrUnsupportedType},
+		{"array", "F [4]byte", ErrUnsupportedType},
+		{"embedded", "types.Coin", ErrUnsupportedType},
+		{"unknown option", "F string `punnet:\"signed\"`", ErrInvalidTag},
+		{"string signer", "F string `punnet:\"signer\"`", ErrInvalidTag},
+		{"required bool", "F bool `punnet:\"required\"`", ErrInvalidTag},
+		{"optional string", "F string `punnet:\"optional\"`", ErrInvalidTag},
+		{"duplicate key", "F string `json:\"x\"`\nG string `json:\"x\"`", ErrInvalidTag},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := "package p\n\nimport \"github.com/blockberries/punnet-sdk/types\"\n\ntype Msg struct {\n" + tt.field + "\n}\n"
+			_, err := GenerateMsg("p.go", []byte(src), MsgOptions{Types: []string{"Msg"}, TypePrefix: "/p."})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if _, err := GenerateMsg("sample.go", []byte(sampleSource), MsgOptions{Types: []string{"Label"}, TypePrefix: "/p."}); !errors.Is(err, ErrMessageNotFound) {
+		t.Errorf("expected ErrMessageNotFound, got %v", err)
+	}
+	if _, err := GenerateMsg("sample.go", []byte(sampleSource), MsgOptions{Types: []string{"MsgSample"}}); err == nil {
+		t.Error("expected error with empty type prefix")
+	}
+}
+
+// TestGenerateMsg_ExampleUpToDate checks that the committed example output
+// matches the generator, so generator changes are exercised by its tests
+func TestGenerateMsg_ExampleUpToDate(t *testing.T) {
+	dir := filepath.Join("..", "examples", "msggen")
+	src, err := os.ReadFile(filepath.Join(dir, "messages.go"))
+	if err != nil {
+		t.Fatalf("failed to read example: %v", err)
+	}
+
+	out, err := GenerateMsg("messages.go", src, MsgOptions{
+		Types:      []string{"MsgTransfer", "MsgSetProfile"},
+		TypePrefix: "/punnet.example.v1.",
+	})
+	if err != nil {
+		t.Fatalf("GenerateMsg failed: %v", err)
+	}
+
+	for name, want := range map[string][]byte{
+		"messages_msggen.go":      out.Code,
+		"messages_msggen_test.go": out.Tests,
+	} {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is stale; run go generate ./examples/msggen", name)
+		}
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.go")
+
+	if changed, err := WriteFile(path, []byte("package a\n")); err != nil || !changed {
+		t.Fatalf("first write = %v, %v, want changed", changed, err)
+	}
+	if changed, err := WriteFile(path, []byte("package a\n")); err != nil || changed {
+		t.Fatalf("identical write = %v, %v, want unchanged", changed, err)
+	}
+	if changed, err := WriteFile(path, []byte("package b\n")); err != nil || !changed {
+		t.Fatalf("modified write = %v, %v, want changed", changed, err)
+	}
+}
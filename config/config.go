@@ -0,0 +1,319 @@
+// Package config loads the node configuration of applications built on the
+// SDK: the state store backend and pruning, mempool limits, keyring backend
+// and telemetry endpoints.
+//
+// Configuration is layered: Default() values, then each config file in order
+// (TOML, YAML or JSON, chosen by extension), then environment variables.
+// Each layer overrides only the settings it names. See Load.
+//
+// Keys are snake_case in every format, e.g. in TOML:
+//
+//	[store]
+//	backend = "goleveldb"
+//
+//	[store.pruning]
+//	strategy = "keep-recent"
+//	keep_recent = 100
+//
+// and PUNNET_STORE_PRUNING_KEEP_RECENT=100 in the environment.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+
+	dbm "github.com/cosmos/cosmos-db"
+
+	"github.com/blockberries/punnet-sdk/mempool"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// ErrInvalidConfig is returned for configuration that fails to parse or validate
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Store backends
+const (
+	BackendGoLevelDB = string(dbm.GoLevelDBBackend)
+	BackendPebbleDB  = string(dbm.PebbleDBBackend)
+	BackendRocksDB   = string(dbm.RocksDBBackend)
+	BackendMemDB     = string(dbm.MemDBBackend)
+)
+
+// Keyring backends
+const (
+	// KeyringFile stores encrypted keys in files (see crypto.NewFileKeyStore)
+	KeyringFile = "file"
+
+	// KeyringOS stores keys in the OS keychain (see crypto.NewKeychainStore)
+	KeyringOS = "os"
+
+	// KeyringMemory keeps keys in memory only, for tests and development
+	KeyringMemory = "memory"
+)
+
+// Config is the node configuration
+type Config struct {
+	// ChainID is the chain the node serves; empty to take it from genesis
+	ChainID string `json:"chain_id"`
+
+	Store     StoreConfig     `json:"store"`
+	Mempool   MempoolConfig   `json:"mempool"`
+	Keyring   KeyringConfig   `json:"keyring"`
+	Telemetry TelemetryConfig `json:"telemetry"`
+}
+
+// StoreConfig configures the state store
+type StoreConfig struct {
+	// Backend is the database backend: goleveldb, pebbledb, rocksdb or
+	// memdb. pebbledb and rocksdb require building with their build tags.
+	Backend string `json:"backend"`
+
+	// Dir is the database directory, relative to the node's home directory
+	// if not absolute (unused by memdb)
+	Dir string `json:"dir"`
+
+	// CacheSize is the IAVL tree cache size in nodes (0 disables the cache)
+	CacheSize int `json:"cache_size"`
+
+	Pruning PruningConfig `json:"pruning"`
+}
+
+// PruningConfig configures state version pruning (see store.PruningOptions)
+type PruningConfig struct {
+	// Strategy is keep-all, keep-recent or keep-every
+	Strategy string `json:"strategy"`
+
+	KeepRecent int64 `json:"keep_recent"`
+	KeepEvery  int64 `json:"keep_every"`
+	Background bool  `json:"background"`
+	BatchSize  int64 `json:"batch_size"`
+}
+
+// MempoolConfig configures the mempool's default lane
+type MempoolConfig struct {
+	// MaxTxs is the maximum number of pending transactions
+	MaxTxs int `json:"max_txs"`
+
+	// MaxBytes is the maximum total size of pending transactions
+	MaxBytes int64 `json:"max_bytes"`
+
+	// MaxBlockTxs limits how many pending transactions a block includes
+	// (0 means no limit)
+	MaxBlockTxs int `json:"max_block_txs"`
+}
+
+// KeyringConfig configures the node's keyring
+type KeyringConfig struct {
+	// Backend is file, os or memory
+	Backend string `json:"backend"`
+
+	// Dir is the key directory of the file backend, relative to the node's
+	// home directory if not absolute
+	Dir string `json:"dir"`
+
+	// ServiceName identifies the node's keys in the OS keychain
+	ServiceName string `json:"service_name"`
+}
+
+// TelemetryConfig configures metrics export
+type TelemetryConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// ServiceName labels the exported metrics
+	ServiceName string `json:"service_name"`
+
+	// Endpoints are the collector URLs metrics are sent to
+	Endpoints []string `json:"endpoints"`
+}
+
+// Default returns the default configuration: goleveldb state keeping the
+// last 100,000 versions, a 5,000 transaction / 64 MiB mempool, the file
+// keyring and telemetry disabled
+func Default() Config {
+	return Config{
+		Store: StoreConfig{
+			Backend:   BackendGoLevelDB,
+			Dir:       "data",
+			CacheSize: 10000,
+			Pruning: PruningConfig{
+				Strategy:   store.PruneKeepRecent.String(),
+				KeepRecent: 100000,
+				BatchSize:  store.DefaultPruneBatchSize,
+			},
+		},
+		Mempool: MempoolConfig{
+			MaxTxs:   5000,
+			MaxBytes: 64 << 20,
+		},
+		Keyring: KeyringConfig{
+			Backend:     KeyringFile,
+			Dir:         "keyring",
+			ServiceName: "punnet",
+		},
+		Telemetry: TelemetryConfig{
+			ServiceName: "punnet",
+		},
+	}
+}
+
+// ValidateBasic performs stateless validation
+func (c Config) ValidateBasic() error {
+	if c.ChainID != "" {
+		if err := types.ValidateChainID(c.ChainID); err != nil {
+			return fmt.Errorf("%w: chain_id: %v", ErrInvalidConfig, err)
+		}
+	}
+	if err := c.Store.ValidateBasic(); err != nil {
+		return fmt.Errorf("%w: store: %v", ErrInvalidConfig, err)
+	}
+	if err := c.Mempool.ValidateBasic(); err != nil {
+		return fmt.Errorf("%w: mempool: %v", ErrInvalidConfig, err)
+	}
+	if err := c.Keyring.ValidateBasic(); err != nil {
+		return fmt.Errorf("%w: keyring: %v", ErrInvalidConfig, err)
+	}
+	if err := c.Telemetry.ValidateBasic(); err != nil {
+		return fmt.Errorf("%w: telemetry: %v", ErrInvalidConfig, err)
+	}
+	return nil
+}
+
+// ValidateBasic performs stateless validation
+func (c StoreConfig) ValidateBasic() error {
+	switch c.Backend {
+	case BackendGoLevelDB, BackendPebbleDB, BackendRocksDB:
+		if c.Dir == "" {
+			return fmt.Errorf("dir is required for backend %s", c.Backend)
+		}
+	case BackendMemDB:
+	default:
+		return fmt.Errorf("unknown backend %q", c.Backend)
+	}
+	if c.CacheSize < 0 {
+		return fmt.Errorf("cache_size cannot be negative, got %d", c.CacheSize)
+	}
+
+	opts, err := c.Pruning.Options()
+	if err != nil {
+		return err
+	}
+	return opts.Validate()
+}
+
+// Options returns the pruning options
+func (c PruningConfig) Options() (store.PruningOptions, error) {
+	opts := store.PruningOptions{
+		KeepRecent: c.KeepRecent,
+		KeepEvery:  c.KeepEvery,
+		Background: c.Background,
+		BatchSize:  c.BatchSize,
+	}
+	for _, strategy := range []store.PruningStrategy{store.PruneKeepAll, store.PruneKeepRecent, store.PruneKeepEvery} {
+		if c.Strategy == strategy.String() {
+			opts.Strategy = strategy
+			return opts, nil
+		}
+	}
+	return store.PruningOptions{}, fmt.Errorf("unknown pruning strategy %q", c.Strategy)
+}
+
+// OpenDB opens the store database called name, resolving a relative Dir
+// against home.
+//
+// PRECONDITION: c passed ValidateBasic.
+func (c StoreConfig) OpenDB(name, home string) (dbm.DB, error) {
+	return dbm.NewDB(name, dbm.BackendType(c.Backend), resolve(home, c.Dir))
+}
+
+// OpenIAVLStore opens the IAVL state store in the database called name with
+// the configured cache size and pruning, resolving a relative Dir against
+// home.
+//
+// PRECONDITION: c passed ValidateBasic.
+func (c StoreConfig) OpenIAVLStore(name, home string) (*store.IAVLStore, error) {
+	opts, err := c.Pruning.Options()
+	if err != nil {
+		return nil, err
+	}
+	db, err := c.OpenDB(name, home)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := store.NewIAVLStore(db, c.CacheSize, store.WithPruning(opts))
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// ValidateBasic performs stateless validation
+func (c MempoolConfig) ValidateBasic() error {
+	return c.Lane().ValidateBasic()
+}
+
+// Lane returns the mempool's default lane: FIFO ordered, holding every
+// transaction. Applications with more lanes add them to the mempool.Config
+// alongside it.
+func (c MempoolConfig) Lane() mempool.Lane {
+	return mempool.Lane{
+		Name:        "default",
+		MaxTxs:      c.MaxTxs,
+		MaxBytes:    c.MaxBytes,
+		MaxBlockTxs: c.MaxBlockTxs,
+		Ordering:    mempool.OrderFIFO,
+	}
+}
+
+// ValidateBasic performs stateless validation
+func (c KeyringConfig) ValidateBasic() error {
+	switch c.Backend {
+	case KeyringFile:
+		if c.Dir == "" {
+			return fmt.Errorf("dir is required for backend %s", c.Backend)
+		}
+	case KeyringOS:
+		if c.ServiceName == "" {
+			return fmt.Errorf("service_name is required for backend %s", c.Backend)
+		}
+	case KeyringMemory:
+	default:
+		return fmt.Errorf("unknown backend %q", c.Backend)
+	}
+	return nil
+}
+
+// KeyDir returns the key directory of the file backend, resolving a
+// relative Dir against home
+func (c KeyringConfig) KeyDir(home string) string {
+	return resolve(home, c.Dir)
+}
+
+// ValidateBasic performs stateless validation
+func (c TelemetryConfig) ValidateBasic() error {
+	if c.Enabled && len(c.Endpoints) == 0 {
+		return fmt.Errorf("at least one endpoint is required when enabled")
+	}
+	for _, endpoint := range c.Endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return fmt.Errorf("invalid endpoint %q: %v", endpoint, err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid endpoint %q: scheme and host are required", endpoint)
+		}
+	}
+	return nil
+}
+
+// resolve returns dir, joined to home if relative
+func resolve(home, dir string) string {
+	if filepath.IsAbs(dir) || home == "" {
+		return dir
+	}
+	return filepath.Join(home, dir)
+}
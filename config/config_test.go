@@ -0,0 +1,155 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/mempool"
+	"github.com/blockberries/punnet-sdk/store"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestDefault(t *testing.T) {
+	cfg := Default()
+	require.NoError(t, cfg.ValidateBasic())
+
+	opts, err := cfg.Store.Pruning.Options()
+	require.NoError(t, err)
+	assert.Equal(t, store.PruneKeepRecent, opts.Strategy)
+	assert.Equal(t, mempool.OrderFIFO, cfg.Mempool.Lane().Ordering)
+}
+
+func TestConfig_ValidateBasic(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+	}{
+		{name: "invalid chain ID", modify: func(c *Config) { c.ChainID = "bad chain" }},
+		{name: "unknown store backend", modify: func(c *Config) { c.Store.Backend = "sqlite" }},
+		{name: "store without dir", modify: func(c *Config) { c.Store.Dir = "" }},
+		{name: "negative cache size", modify: func(c *Config) { c.Store.CacheSize = -1 }},
+		{name: "unknown pruning strategy", modify: func(c *Config) { c.Store.Pruning.Strategy = "nothing" }},
+		{name: "keep-every without interval", modify: func(c *Config) { c.Store.Pruning.Strategy = "keep-every" }},
+		{name: "empty mempool", modify: func(c *Config) { c.Mempool.MaxTxs = 0 }},
+		{name: "unknown keyring backend", modify: func(c *Config) { c.Keyring.Backend = "test" }},
+		{name: "os keyring without service", modify: func(c *Config) { c.Keyring.Backend, c.Keyring.ServiceName = KeyringOS, "" }},
+		{name: "telemetry without endpoints", modify: func(c *Config) { c.Telemetry.Enabled = true }},
+		{name: "invalid endpoint", modify: func(c *Config) { c.Telemetry.Endpoints = []string{"localhost"} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.modify(&cfg)
+			require.ErrorIs(t, cfg.ValidateBasic(), ErrInvalidConfig)
+		})
+	}
+
+	t.Run("memdb needs no dir", func(t *testing.T) {
+		cfg := Default()
+		cfg.Store.Backend, cfg.Store.Dir = BackendMemDB, ""
+		require.NoError(t, cfg.ValidateBasic())
+	})
+}
+
+func TestLoad_Layering(t *testing.T) {
+	base := writeFile(t, "base.toml", `
+chain_id = "punnet-1"
+
+[store]
+backend = "memdb"
+cache_size = 0
+
+[store.pruning]
+strategy = "keep-every"
+keep_recent = 100
+keep_every = 1_000
+
+[telemetry]
+enabled = true
+endpoints = [
+  "http://localhost:4318", # collector
+]
+`)
+	override := writeFile(t, "override.yaml", `
+store:
+  pruning:
+    keep_recent: 50
+mempool:
+  max_txs: 10
+`)
+	t.Setenv("PUNNETTEST_MEMPOOL_MAX_BYTES", "4096")
+	t.Setenv("PUNNETTEST_TELEMETRY_ENDPOINTS", "http://a:1, http://b:2")
+
+	cfg, err := Load("PUNNETTEST", base, override)
+	require.NoError(t, err)
+
+	assert.Equal(t, "punnet-1", cfg.ChainID)
+	assert.Equal(t, BackendMemDB, cfg.Store.Backend)
+	assert.Equal(t, "keep-every", cfg.Store.Pruning.Strategy)
+	assert.Equal(t, int64(50), cfg.Store.Pruning.KeepRecent, "later files override earlier ones")
+	assert.Equal(t, int64(1000), cfg.Store.Pruning.KeepEvery, "settings a file does not name are kept")
+	assert.Equal(t, int64(store.DefaultPruneBatchSize), cfg.Store.Pruning.BatchSize, "defaults are kept")
+	assert.Equal(t, 10, cfg.Mempool.MaxTxs)
+	assert.Equal(t, int64(4096), cfg.Mempool.MaxBytes, "environment overrides files")
+	assert.Equal(t, []string{"http://a:1", "http://b:2"}, cfg.Telemetry.Endpoints)
+	assert.Equal(t, KeyringFile, cfg.Keyring.Backend)
+
+	s, err := cfg.Store.OpenIAVLStore("state", t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), s.Pruning().KeepEvery)
+	require.NoError(t, s.Close())
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{name: "unknown key", file: "c.toml", content: "[store]\nbackend = \"memdb\"\ncache = 1\n"},
+		{name: "unknown table", file: "c.json", content: `{"stores":{}}`},
+		{name: "wrong type", file: "c.yaml", content: "mempool:\n  max_txs: many\n"},
+		{name: "invalid value", file: "c.toml", content: "[keyring]\nbackend = \"ledger\"\n"},
+		{name: "unsupported format", file: "c.ini", content: "backend=memdb"},
+		{name: "malformed toml", file: "c.toml", content: "[store\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load("PUNNETTEST", writeFile(t, tt.file, tt.content))
+			require.ErrorIs(t, err, ErrInvalidConfig)
+		})
+	}
+
+	_, err := Load("PUNNETTEST", filepath.Join(t.TempDir(), "missing.toml"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestConfig_ApplyEnv(t *testing.T) {
+	cfg := Default()
+	require.NoError(t, cfg.ApplyEnv("app", []string{
+		"APP_STORE_PRUNING_BACKGROUND=true",
+		"APP_KEYRING_BACKEND=memory",
+		"OTHER_STORE_BACKEND=memdb",
+		"APP",
+	}))
+	assert.True(t, cfg.Store.Pruning.Background)
+	assert.Equal(t, KeyringMemory, cfg.Keyring.Backend)
+	assert.Equal(t, BackendGoLevelDB, cfg.Store.Backend)
+
+	for _, env := range []string{"APP_STORE_BACKND=memdb", "APP_STORE_CACHE_SIZE=big", "APP_TELEMETRY_ENABLED=maybe", "APP_STORE_PRUNING=x"} {
+		cfg := Default()
+		require.ErrorIs(t, cfg.ApplyEnv("APP", []string{env}), ErrInvalidConfig, env)
+	}
+}
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultEnvPrefix is the environment variable prefix Load uses
+const DefaultEnvPrefix = "PUNNET"
+
+// MaxFileSize bounds the size of a config file
+const MaxFileSize = 1 << 20
+
+// Load returns Default() overridden by each file in paths in order, then by
+// the process environment variables with prefix (see ApplyEnv; applications
+// without their own use DefaultEnvPrefix), validated.
+func Load(prefix string, paths ...string) (Config, error) {
+	cfg := Default()
+	for _, path := range paths {
+		if err := cfg.LoadFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+	if err := cfg.ApplyEnv(prefix, os.Environ()); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.ValidateBasic(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// LoadFile overrides the settings the file at path names. Its format is
+// chosen by extension: .toml, .yaml or .yml, or .json.
+//
+// SECURITY: Unknown keys are rejected, so a misspelled setting fails loudly
+// instead of silently keeping its default.
+func (c *Config) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config: %w", err)
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(io.LimitReader(f, MaxFileSize+1)); err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	if buf.Len() > MaxFileSize {
+		return fmt.Errorf("%w: %s exceeds %d bytes", ErrInvalidConfig, path, MaxFileSize)
+	}
+
+	var tree map[string]any
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".toml":
+		tree, err = parseTOML(buf.Bytes())
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(buf.Bytes(), &tree)
+	case ".json":
+		err = json.Unmarshal(buf.Bytes(), &tree)
+	default:
+		return fmt.Errorf("%w: %s: unsupported format %q", ErrInvalidConfig, path, ext)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidConfig, path, err)
+	}
+
+	if err := c.merge(tree); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidConfig, path, err)
+	}
+	return nil
+}
+
+// merge overrides the settings tree names
+func (c *Config) merge(tree map[string]any) error {
+	if tree == nil {
+		return nil
+	}
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return err
+	}
+
+	// Decoding into c keeps the settings the tree does not name
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(c)
+}
+
+// ApplyEnv overrides settings from environ ("KEY=value" entries, as from
+// os.Environ): a setting's variable is prefix, "_", and its key path upper
+// case, joined by "_", e.g. PUNNET_STORE_PRUNING_KEEP_RECENT. List settings
+// are comma separated.
+//
+// SECURITY: A variable with the prefix naming no setting is rejected, as are
+// unknown file keys.
+func (c *Config) ApplyEnv(prefix string, environ []string) error {
+	if prefix == "" {
+		return fmt.Errorf("%w: environment prefix cannot be empty", ErrInvalidConfig)
+	}
+	settings := make(map[string]reflect.Value)
+	collectSettings(reflect.ValueOf(c).Elem(), strings.ToUpper(prefix), settings)
+
+	var names []string
+	values := make(map[string]string)
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(name, strings.ToUpper(prefix)+"_") {
+			continue
+		}
+		names = append(names, name)
+		values[name] = value
+	}
+	// Deterministic error reporting
+	sort.Strings(names)
+
+	for _, name := range names {
+		field, ok := settings[name]
+		if !ok {
+			return fmt.Errorf("%w: unknown environment variable %s", ErrInvalidConfig, name)
+		}
+		if err := setField(field, values[name]); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidConfig, name, err)
+		}
+	}
+	return nil
+}
+
+// collectSettings maps the environment variable name of each setting in v,
+// a struct, to its field
+func collectSettings(v reflect.Value, name string, settings map[string]reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		fieldName := name + "_" + strings.ToUpper(key)
+		if field := v.Field(i); field.Kind() == reflect.Struct {
+			collectSettings(field, fieldName, settings)
+		} else {
+			settings[fieldName] = field
+		}
+	}
+}
+
+// setField parses value into field
+func setField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Slice:
+		var list []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		field.Set(reflect.ValueOf(list))
+	default:
+		return fmt.Errorf("unsupported setting type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,407 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// parseTOML parses the subset of TOML configuration needs: tables, dotted
+// keys, basic and literal strings, decimal integers, booleans and arrays
+// (which may span lines). Floats, dates, multi-line strings, inline tables
+// and arrays of tables are rejected rather than misread.
+//
+// INVARIANT: Values are string, int64, bool, []any or map[string]any.
+//
+// Complexity: O(n) for n bytes of input.
+func parseTOML(data []byte) (map[string]any, error) {
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("toml: invalid UTF-8")
+	}
+
+	p := &tomlParser{data: data, root: make(map[string]any), headers: make(map[string]struct{})}
+	p.current = p.root
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return p.root, nil
+		}
+		var err error
+		if p.peek() == '[' {
+			err = p.parseHeader()
+		} else {
+			err = p.parseKeyValue()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("toml: line %d: %v", p.line(), err)
+		}
+	}
+}
+
+// tomlParser holds the state of parseTOML
+type tomlParser struct {
+	data []byte
+	pos  int
+
+	root    map[string]any
+	current map[string]any
+
+	// headers are the tables defined by a [header]
+	headers map[string]struct{}
+}
+
+func (p *tomlParser) eof() bool { return p.pos >= len(p.data) }
+
+func (p *tomlParser) peek() byte { return p.data[p.pos] }
+
+// line returns the 1-based line number of the current position
+func (p *tomlParser) line() int {
+	return bytes.Count(p.data[:min(p.pos, len(p.data))], []byte{'\n'}) + 1
+}
+
+// skipSpace skips spaces and tabs
+func (p *tomlParser) skipSpace() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+// skipBlank skips whitespace, newlines and comments
+func (p *tomlParser) skipBlank() {
+	for !p.eof() {
+		switch p.peek() {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		case '#':
+			p.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) skipComment() {
+	for !p.eof() && p.peek() != '\n' {
+		p.pos++
+	}
+}
+
+// endLine consumes the rest of the line, which may hold only a comment
+func (p *tomlParser) endLine() error {
+	p.skipSpace()
+	if !p.eof() && p.peek() == '#' {
+		p.skipComment()
+	}
+	if p.eof() {
+		return nil
+	}
+	if p.peek() == '\r' {
+		p.pos++
+	}
+	if p.eof() || p.peek() != '\n' {
+		return fmt.Errorf("unexpected %q after value", p.rest())
+	}
+	p.pos++
+	return nil
+}
+
+// rest returns the remainder of the current line, for error messages
+func (p *tomlParser) rest() string {
+	end := bytes.IndexByte(p.data[p.pos:], '\n')
+	if end < 0 {
+		return string(p.data[p.pos:])
+	}
+	return strings.TrimRight(string(p.data[p.pos:p.pos+end]), "\r")
+}
+
+// parseHeader parses a [table] header and makes it the current table
+func (p *tomlParser) parseHeader() error {
+	p.pos++ // '['
+	if !p.eof() && p.peek() == '[' {
+		return fmt.Errorf("arrays of tables are not supported")
+	}
+	p.skipSpace()
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	p.skipSpace()
+	if p.eof() || p.peek() != ']' {
+		return fmt.Errorf("expected ] after table name")
+	}
+	p.pos++
+
+	path := strings.Join(keys, ".")
+	if _, dup := p.headers[path]; dup {
+		return fmt.Errorf("table %s defined twice", path)
+	}
+	p.headers[path] = struct{}{}
+
+	table, err := p.table(p.root, keys)
+	if err != nil {
+		return err
+	}
+	p.current = table
+	return p.endLine()
+}
+
+// parseKeyValue parses a key = value line into the current table
+func (p *tomlParser) parseKeyValue() error {
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	p.skipSpace()
+	if p.eof() || p.peek() != '=' {
+		return fmt.Errorf("expected = after key %s", strings.Join(keys, "."))
+	}
+	p.pos++
+	p.skipSpace()
+
+	value, err := p.parseValue()
+	if err != nil {
+		return fmt.Errorf("key %s: %v", strings.Join(keys, "."), err)
+	}
+
+	table, err := p.table(p.current, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	last := keys[len(keys)-1]
+	if _, dup := table[last]; dup {
+		return fmt.Errorf("key %s defined twice", strings.Join(keys, "."))
+	}
+	table[last] = value
+	return p.endLine()
+}
+
+// table returns the table at keys below parent, creating missing tables
+func (p *tomlParser) table(parent map[string]any, keys []string) (map[string]any, error) {
+	for _, key := range keys {
+		switch v := parent[key].(type) {
+		case nil:
+			child := make(map[string]any)
+			parent[key] = child
+			parent = child
+		case map[string]any:
+			parent = v
+		default:
+			return nil, fmt.Errorf("key %s is not a table", key)
+		}
+	}
+	return parent, nil
+}
+
+// parseKey parses a possibly dotted key
+func (p *tomlParser) parseKey() ([]string, error) {
+	var keys []string
+	for {
+		key, err := p.parseSimpleKey()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+
+		p.skipSpace()
+		if p.eof() || p.peek() != '.' {
+			return keys, nil
+		}
+		p.pos++
+		p.skipSpace()
+	}
+}
+
+// parseSimpleKey parses a bare or quoted key
+func (p *tomlParser) parseSimpleKey() (string, error) {
+	if p.eof() {
+		return "", fmt.Errorf("expected key")
+	}
+	switch p.peek() {
+	case '"':
+		return p.parseBasicString()
+	case '\'':
+		return p.parseLiteralString()
+	}
+
+	start := p.pos
+	for !p.eof() && isBareKeyChar(p.peek()) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", fmt.Errorf("invalid key %q", p.rest())
+	}
+	return string(p.data[start:p.pos]), nil
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// parseValue parses a value
+func (p *tomlParser) parseValue() (any, error) {
+	if p.eof() {
+		return nil, fmt.Errorf("expected value")
+	}
+	switch c := p.peek(); {
+	case c == '"':
+		return p.parseBasicString()
+	case c == '\'':
+		return p.parseLiteralString()
+	case c == '[':
+		return p.parseArray()
+	case c == '{':
+		return nil, fmt.Errorf("inline tables are not supported")
+	}
+
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune(" \t\r\n,]#", rune(p.peek())) {
+		p.pos++
+	}
+	word := string(p.data[start:p.pos])
+	switch word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return parseTOMLInteger(word)
+}
+
+// parseTOMLInteger parses a decimal integer, allowing underscores between
+// digits
+func parseTOMLInteger(word string) (int64, error) {
+	digits := strings.TrimLeft(word, "+-")
+	if digits == "" || len(word)-len(digits) > 1 || strings.HasPrefix(digits, "_") || strings.HasSuffix(digits, "_") ||
+		strings.Contains(digits, "__") || len(digits) > 1 && digits[0] == '0' {
+		return 0, fmt.Errorf("unsupported value %q", word)
+	}
+	n, err := strconv.ParseInt(strings.ReplaceAll(word, "_", ""), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unsupported value %q (only strings, integers, booleans and arrays)", word)
+	}
+	return n, nil
+}
+
+// parseArray parses an array, which may span lines
+func (p *tomlParser) parseArray() ([]any, error) {
+	p.pos++ // '['
+	values := []any{}
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return nil, fmt.Errorf("unterminated array")
+		}
+		if p.peek() == ']' {
+			p.pos++
+			return values, nil
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		p.skipBlank()
+		if p.eof() {
+			return nil, fmt.Errorf("unterminated array")
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			return nil, fmt.Errorf("expected , or ] in array")
+		}
+	}
+}
+
+// parseLiteralString parses a 'literal string', which has no escapes
+func (p *tomlParser) parseLiteralString() (string, error) {
+	if bytes.HasPrefix(p.data[p.pos:], []byte("'''")) {
+		return "", fmt.Errorf("multi-line strings are not supported")
+	}
+	p.pos++
+	start := p.pos
+	for !p.eof() && p.peek() != '\'' {
+		if p.peek() == '\n' {
+			return "", fmt.Errorf("unterminated string")
+		}
+		p.pos++
+	}
+	if p.eof() {
+		return "", fmt.Errorf("unterminated string")
+	}
+	s := string(p.data[start:p.pos])
+	p.pos++
+	return s, nil
+}
+
+// parseBasicString parses a "basic string" with escapes
+func (p *tomlParser) parseBasicString() (string, error) {
+	if bytes.HasPrefix(p.data[p.pos:], []byte(`"""`)) {
+		return "", fmt.Errorf("multi-line strings are not supported")
+	}
+	p.pos++
+
+	var sb strings.Builder
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", fmt.Errorf("unterminated string")
+		}
+		c := p.peek()
+		p.pos++
+		switch c {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+}
+
+// parseEscape parses the escape sequence after a backslash
+func (p *tomlParser) parseEscape(sb *strings.Builder) error {
+	if p.eof() {
+		return fmt.Errorf("unterminated string")
+	}
+	c := p.peek()
+	p.pos++
+	switch c {
+	case 'b':
+		sb.WriteByte('\b')
+	case 't':
+		sb.WriteByte('\t')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'r':
+		sb.WriteByte('\r')
+	case '"', '\\':
+		sb.WriteByte(c)
+	case 'u', 'U':
+		size := 4
+		if c == 'U' {
+			size = 8
+		}
+		if p.pos+size > len(p.data) {
+			return fmt.Errorf("invalid unicode escape")
+		}
+		code, err := strconv.ParseUint(string(p.data[p.pos:p.pos+size]), 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return fmt.Errorf("invalid unicode escape")
+		}
+		p.pos += size
+		sb.WriteRune(rune(code))
+	default:
+		return fmt.Errorf("invalid escape \\%c", c)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTOML(t *testing.T) {
+	tree, err := parseTOML([]byte(`# node config
+name = "node \"one\"\u00e9" # trailing comment
+path = 'C:\keys'
+"quoted key" = -42
+a.b = true
+
+[store.pruning]
+keep_recent = 1_000
+list = [1, "two", [false],
+  # comment inside an array
+]
+
+[mempool]
+empty = []
+`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"name":       "node \"one\"\u00e9",
+		"path":       `C:\keys`,
+		"quoted key": int64(-42),
+		"a":          map[string]any{"b": true},
+		"store": map[string]any{"pruning": map[string]any{
+			"keep_recent": int64(1000),
+			"list":        []any{int64(1), "two", []any{false}},
+		}},
+		"mempool": map[string]any{"empty": []any{}},
+	}, tree)
+}
+
+func TestParseTOML_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "float", input: "a = 1.5", wantErr: "unsupported value"},
+		{name: "date", input: "a = 2026-01-01", wantErr: "unsupported value"},
+		{name: "leading zero", input: "a = 01", wantErr: "unsupported value"},
+		{name: "misplaced underscore", input: "a = 1__0", wantErr: "unsupported value"},
+		{name: "overflow", input: "a = 9223372036854775808", wantErr: "unsupported value"},
+		{name: "inline table", input: "a = {b = 1}", wantErr: "inline tables"},
+		{name: "array of tables", input: "[[a]]", wantErr: "arrays of tables"},
+		{name: "multi-line string", input: `a = """x"""`, wantErr: "multi-line strings"},
+		{name: "duplicate key", input: "a = 1\na = 2", wantErr: "line 2: key a defined twice"},
+		{name: "duplicate table", input: "[a]\n[a]", wantErr: "table a defined twice"},
+		{name: "key is not a table", input: "a = 1\n[a.b]", wantErr: "key a is not a table"},
+		{name: "missing value", input: "a =", wantErr: "expected value"},
+		{name: "missing equals", input: "a 1", wantErr: "expected ="},
+		{name: "trailing data", input: `a = "x" y`, wantErr: "unexpected"},
+		{name: "unterminated string", input: "a = \"x\nb = 1", wantErr: "unterminated string"},
+		{name: "unterminated array", input: "a = [1,", wantErr: "unterminated array"},
+		{name: "invalid escape", input: `a = "\q"`, wantErr: "invalid escape"},
+		{name: "surrogate escape", input: `a = "\ud800"`, wantErr: "invalid unicode escape"},
+		{name: "invalid UTF-8", input: "a = \"\xff\"", wantErr: "invalid UTF-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseTOML([]byte(tt.input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -25,6 +25,13 @@ const (
 	// Key size: 33 bytes (compressed), Signature size: 64 bytes.
 	// Used for hardware security module compatibility.
 	AlgorithmSecp256r1 Algorithm = "secp256r1"
+
+	// AlgorithmWebAuthn is a P-256 ECDSA assertion produced by a WebAuthn
+	// authenticator (passkey).
+	// Key size: 33 bytes (compressed), Signature size: 64 bytes (R || S).
+	// The private key never leaves the authenticator, so the SDK cannot
+	// generate or hold keys for this algorithm; it only verifies assertions.
+	AlgorithmWebAuthn Algorithm = "webauthn"
 )
 
 // String returns the string representation of the algorithm.
@@ -35,7 +42,7 @@ func (a Algorithm) String() string {
 // IsValid returns true if the algorithm is a recognized type.
 func (a Algorithm) IsValid() bool {
 	switch a {
-	case AlgorithmEd25519, AlgorithmSecp256k1, AlgorithmSecp256r1:
+	case AlgorithmEd25519, AlgorithmSecp256k1, AlgorithmSecp256r1, AlgorithmWebAuthn:
 		return true
 	default:
 		return false
@@ -54,7 +61,7 @@ func (a Algorithm) PublicKeySize() int {
 	switch a {
 	case AlgorithmEd25519:
 		return 32
-	case AlgorithmSecp256k1, AlgorithmSecp256r1:
+	case AlgorithmSecp256k1, AlgorithmSecp256r1, AlgorithmWebAuthn:
 		return 33 // Compressed form
 	default:
 		return 0
@@ -78,7 +85,7 @@ func (a Algorithm) PrivateKeySize() int {
 // Complexity: O(1)
 func (a Algorithm) SignatureSize() int {
 	switch a {
-	case AlgorithmEd25519, AlgorithmSecp256k1, AlgorithmSecp256r1:
+	case AlgorithmEd25519, AlgorithmSecp256k1, AlgorithmSecp256r1, AlgorithmWebAuthn:
 		return 64
 	default:
 		return 0
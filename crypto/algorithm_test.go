@@ -16,6 +16,7 @@ func TestAlgorithm_String(t *testing.T) {
 		{AlgorithmEd25519, "ed25519"},
 		{AlgorithmSecp256k1, "secp256k1"},
 		{AlgorithmSecp256r1, "secp256r1"},
+		{AlgorithmWebAuthn, "webauthn"},
 	}
 
 	for _, tt := range tests {
@@ -33,6 +34,7 @@ func TestAlgorithm_IsValid(t *testing.T) {
 		{AlgorithmEd25519, true},
 		{AlgorithmSecp256k1, true},
 		{AlgorithmSecp256r1, true},
+		{AlgorithmWebAuthn, true},
 		{Algorithm("unknown"), false},
 		{Algorithm(""), false},
 		{Algorithm("ED25519"), false}, // case sensitive
@@ -78,6 +80,8 @@ func TestAlgorithm_KeySize(t *testing.T) {
 	assert.Equal(t, 32, AlgorithmEd25519.KeySize())
 	assert.Equal(t, 33, AlgorithmSecp256k1.KeySize())
 	assert.Equal(t, 33, AlgorithmSecp256r1.KeySize())
+	assert.Equal(t, 33, AlgorithmWebAuthn.KeySize())
+	assert.Equal(t, 0, AlgorithmWebAuthn.PrivateKeySize())
 	assert.Equal(t, 0, Algorithm("unknown").KeySize())
 }
 
@@ -85,5 +89,6 @@ func TestAlgorithm_SignatureSize(t *testing.T) {
 	assert.Equal(t, 64, AlgorithmEd25519.SignatureSize())
 	assert.Equal(t, 64, AlgorithmSecp256k1.SignatureSize())
 	assert.Equal(t, 64, AlgorithmSecp256r1.SignatureSize())
+	assert.Equal(t, 64, AlgorithmWebAuthn.SignatureSize())
 	assert.Equal(t, 0, Algorithm("unknown").SignatureSize())
 }
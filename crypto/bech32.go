@@ -0,0 +1,151 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Minimal BIP-173 bech32 implementation used for public key strings.
+//
+// Only the original bech32 checksum is supported (not bech32m). Encoded
+// strings are always lowercase; mixed-case input is rejected as required by
+// BIP-173.
+
+const (
+	bech32Charset   = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	bech32MaxLength = 90
+)
+
+var bech32Generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+var errBech32 = errors.New("invalid bech32 string")
+
+// bech32Polymod computes the BCH checksum over values.
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= bech32Generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// bech32HRPExpand expands the human-readable part for checksum computation.
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// bech32Checksum returns the 6 checksum values for hrp and 5-bit data.
+func bech32Checksum(hrp string, data []byte) []byte {
+	values := append(bech32HRPExpand(hrp), data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+	mod := bech32Polymod(values) ^ 1
+	out := make([]byte, 6)
+	for i := range out {
+		out[i] = byte((mod >> uint(5*(5-i))) & 31)
+	}
+	return out
+}
+
+// convertBits regroups data from fromBits-wide to toBits-wide groups.
+// When pad is false, leftover bits must be zero and fewer than fromBits.
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var acc uint32
+	var bits uint
+	maxv := uint32(1)<<toBits - 1
+	out := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for _, b := range data {
+		if uint32(b)>>fromBits != 0 {
+			return nil, fmt.Errorf("%w: value out of range", errBech32)
+		}
+		acc = acc<<fromBits | uint32(b)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte((acc>>bits)&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte((acc<<(toBits-bits))&maxv))
+		}
+	} else if bits >= fromBits || (acc<<(toBits-bits))&maxv != 0 {
+		return nil, fmt.Errorf("%w: non-zero padding", errBech32)
+	}
+	return out, nil
+}
+
+// bech32Encode encodes data under hrp.
+func bech32Encode(hrp string, data []byte) (string, error) {
+	conv, err := convertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	combined := append(conv, bech32Checksum(hrp, conv)...)
+
+	var sb strings.Builder
+	sb.Grow(len(hrp) + 1 + len(combined))
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range combined {
+		sb.WriteByte(bech32Charset[v])
+	}
+	if sb.Len() > bech32MaxLength {
+		return "", fmt.Errorf("%w: encoded length %d exceeds %d", errBech32, sb.Len(), bech32MaxLength)
+	}
+	return sb.String(), nil
+}
+
+// bech32Decode decodes s, returning its hrp and data bytes.
+func bech32Decode(s string) (string, []byte, error) {
+	if len(s) > bech32MaxLength {
+		return "", nil, fmt.Errorf("%w: length %d exceeds %d", errBech32, len(s), bech32MaxLength)
+	}
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, fmt.Errorf("%w: mixed case", errBech32)
+	}
+	s = strings.ToLower(s)
+
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || sep+7 > len(s) {
+		return "", nil, fmt.Errorf("%w: invalid separator position", errBech32)
+	}
+	hrp := s[:sep]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, fmt.Errorf("%w: invalid human-readable part", errBech32)
+		}
+	}
+
+	values := make([]byte, 0, len(s)-sep-1)
+	for i := sep + 1; i < len(s); i++ {
+		idx := strings.IndexByte(bech32Charset, s[i])
+		if idx < 0 {
+			return "", nil, fmt.Errorf("%w: invalid character %q", errBech32, s[i])
+		}
+		values = append(values, byte(idx))
+	}
+	if bech32Polymod(append(bech32HRPExpand(hrp), values...)) != 1 {
+		return "", nil, fmt.Errorf("%w: checksum mismatch", errBech32)
+	}
+
+	data, err := convertBits(values[:len(values)-6], 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+	return hrp, data, nil
+}
@@ -0,0 +1,44 @@
+package crypto
+
+import "crypto/subtle"
+
+// ConstantTimeEqual reports whether a and b are equal, in time that depends
+// only on their lengths, not their contents.
+//
+// Use it instead of bytes.Equal whenever either side is secret or
+// attacker-influenced and derived from a secret: MACs, password hashes,
+// private key material, authentication tokens. bytes.Equal returns at the
+// first differing byte, so its timing reveals how long a matching prefix an
+// attacker has guessed.
+//
+// SECURITY: The lengths are not hidden; compare fixed-size values (hashes,
+// MACs) where the length of a secret matters.
+//
+// Complexity: O(n) where n is the length of the inputs.
+func ConstantTimeEqual(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// PublicKeyBytesEqual reports whether two public keys given as algorithm and
+// encoded bytes are the same key, comparing the bytes in constant time.
+//
+// Complexity: O(n) where n is the key length.
+func PublicKeyBytesEqual(algoA Algorithm, keyA []byte, algoB Algorithm, keyB []byte) bool {
+	// Evaluate both comparisons before combining so the timing does not reveal
+	// which one failed
+	sameAlgo := subtle.ConstantTimeCompare([]byte(algoA), []byte(algoB))
+	sameKey := subtle.ConstantTimeCompare(keyA, keyB)
+	return sameAlgo&sameKey == 1
+}
+
+// SignaturesEqual reports whether two encoded signatures are byte-identical,
+// comparing them in constant time.
+//
+// NOTE: ECDSA signatures are malleable; two different encodings can both be
+// valid for the same message and key. Normalize them first (see
+// NormalizeSignature) when comparing for semantic equality.
+//
+// Complexity: O(n) where n is the signature length.
+func SignaturesEqual(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
@@ -0,0 +1,42 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstantTimeEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []byte
+		want bool
+	}{
+		{"both nil", nil, nil, true},
+		{"nil and empty", nil, []byte{}, true},
+		{"equal", []byte("secret"), []byte("secret"), true},
+		{"differ in last byte", []byte("secret"), []byte("secreT"), false},
+		{"differ in first byte", []byte("secret"), []byte("Secret"), false},
+		{"prefix", []byte("secret"), []byte("sec"), false},
+		{"empty and non-empty", []byte{}, []byte{0}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ConstantTimeEqual(tt.a, tt.b))
+			assert.Equal(t, tt.want, ConstantTimeEqual(tt.b, tt.a))
+			assert.Equal(t, tt.want, SignaturesEqual(tt.a, tt.b))
+		})
+	}
+}
+
+func TestPublicKeyBytesEqual(t *testing.T) {
+	key := []byte{0x02, 0x01, 0x02, 0x03}
+	other := []byte{0x02, 0x01, 0x02, 0x04}
+
+	assert.True(t, PublicKeyBytesEqual(AlgorithmSecp256k1, key, AlgorithmSecp256k1, key))
+	assert.False(t, PublicKeyBytesEqual(AlgorithmSecp256k1, key, AlgorithmSecp256k1, other))
+	// The same bytes under another algorithm are a different key
+	assert.False(t, PublicKeyBytesEqual(AlgorithmSecp256k1, key, AlgorithmSecp256r1, key))
+	assert.False(t, PublicKeyBytesEqual(AlgorithmSecp256k1, key, AlgorithmSecp256r1, other))
+}
@@ -151,6 +151,18 @@ func (k *secp256r1PublicKey) Verify(data, signature []byte) bool {
 	return ecdsa.Verify(k.key, hash[:], r, s)
 }
 
+// VerifyDigest verifies a signature (64 bytes: r||s in big-endian) over a
+// precomputed digest. Unlike Verify, the digest is not hashed again.
+func (k *secp256r1PublicKey) VerifyDigest(digest, signature []byte) bool {
+	if len(signature) != 64 {
+		return false
+	}
+
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:])
+	return ecdsa.Verify(k.key, digest, r, s)
+}
+
 // Equals checks equality using constant-time comparison.
 func (k *secp256r1PublicKey) Equals(other PublicKey) bool {
 	if other == nil || other.Algorithm() != AlgorithmSecp256r1 {
@@ -424,3 +436,6 @@ func IsLowS(signature []byte, n *big.Int) bool {
 	halfN := new(big.Int).Rsh(n, 1)
 	return s.Cmp(halfN) <= 0
 }
+
+// Verify the secp256r1 public key supports digest verification.
+var _ DigestVerifier = (*secp256r1PublicKey)(nil)
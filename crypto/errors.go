@@ -22,6 +22,9 @@ var (
 	// ErrInvalidAlgorithm is returned when an algorithm is not recognized.
 	ErrInvalidAlgorithm = errors.New("invalid algorithm")
 
+	// ErrInvalidPubKeyString is returned when a public key string cannot be decoded.
+	ErrInvalidPubKeyString = errors.New("invalid public key string")
+
 	// ErrKeyNameMismatch is returned when the name parameter differs from EncryptedKey.Name.
 	ErrKeyNameMismatch = errors.New("key name parameter does not match EncryptedKey.Name")
 
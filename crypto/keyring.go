@@ -3,12 +3,10 @@ package crypto
 import (
 	"crypto/aes"
 	"crypto/cipher"
-	"crypto/sha256"
 	"errors"
 	"fmt"
 	"sync"
-
-	"golang.org/x/crypto/pbkdf2"
+	"time"
 )
 
 // Key name constraints.
@@ -73,18 +71,74 @@ type Keyring interface {
 	// ExportKey exports a private key (may require password).
 	// Returns ErrKeyNotFound if key doesn't exist.
 	// Returns ErrInvalidPassword if password is incorrect (for encrypted keys).
+	// Returns ErrPasswordRateLimited or ErrKeyLockedOut if failed attempts
+	// are throttled (see WithPasswordAttemptLimit).
 	// Complexity: O(store.Get) + O(decryption if encrypted).
 	ExportKey(name string, password string) ([]byte, error)
 
+	// ExportArmored exports a private key as an ASCII-armored bundle encrypted
+	// with passphrase (Argon2id + XChaCha20-Poly1305). See keyring_armor.go.
+	// For encrypted store entries, passphrase must also unlock the entry, and
+	// failed unlocks count towards WithPasswordAttemptLimit.
+	// Returns ErrKeyNotFound if key doesn't exist.
+	// Returns ErrEmptyPassphrase if passphrase is empty.
+	// Complexity: O(ExportKey) + O(Argon2id).
+	ExportArmored(name string, passphrase string) ([]byte, error)
+
+	// ImportArmored imports a private key from a bundle produced by ExportArmored.
+	// Returns ErrKeyExists if a key with this name already exists.
+	// Returns ErrInvalidPassword if the passphrase is wrong or the bundle was modified.
+	// Returns ErrUnsupportedArmorVersion for bundles from an incompatible format version.
+	// Complexity: O(Argon2id) + O(ImportKey).
+	ImportArmored(name string, armored []byte, passphrase string) (Signer, error)
+
+	// ResetPasswordAttempts clears a key's failed password attempts, lifting
+	// any backoff or lockout imposed by WithPasswordAttemptLimit.
+	// Complexity: O(1).
+	ResetPasswordAttempts(name string) error
+
 	// GetKey retrieves a signer by name.
 	// Returns ErrKeyNotFound if key doesn't exist.
 	// Complexity: O(store.Get) or O(1) if cached.
 	GetKey(name string) (Signer, error)
 
 	// ListKeys returns all key names.
+	// Use ListKeyInfo for public keys and metadata.
 	// Complexity: O(n) where n is number of keys.
 	ListKeys() ([]string, error)
 
+	// ListKeyInfo returns the public key, algorithm and metadata of every
+	// key, sorted by name. See keyring_metadata.go.
+	// Complexity: O(n * store.Get) where n is number of keys.
+	ListKeyInfo() ([]KeyInfo, error)
+
+	// SetMetadata replaces a key's label, account and algorithm parameters.
+	// Returns ErrKeyNotFound if key doesn't exist.
+	// Returns ErrInvalidKeyMetadata if the metadata fails validation.
+	// Complexity: O(store.Get) + O(store.Put).
+	SetMetadata(name string, meta *KeyMetadata) error
+
+	// GetMetadata returns a copy of a key's metadata, including its creation
+	// and last-used times.
+	// Returns ErrKeyNotFound if key doesn't exist.
+	// Complexity: O(store.Get).
+	GetMetadata(name string) (*KeyMetadata, error)
+
+	// AddMultisig stores a multisig descriptor (threshold and member public
+	// keys) under name. See keyring_multisig.go.
+	// Multisig entries cannot sign or be exported; private-key operations on
+	// them return ErrMultisigKey.
+	// Returns ErrKeyExists if a key with this name already exists.
+	// Returns ErrInvalidMultisig if the descriptor fails validation.
+	// Complexity: O(n) where n is number of members + O(store.Put).
+	AddMultisig(name string, desc *MultisigDescriptor) error
+
+	// GetMultisig returns a copy of the multisig descriptor stored under name.
+	// Returns ErrKeyNotFound if key doesn't exist.
+	// Returns ErrNotMultisig if the key is a single signing key.
+	// Complexity: O(store.Get).
+	GetMultisig(name string) (*MultisigDescriptor, error)
+
 	// DeleteKey removes a key.
 	// Returns ErrKeyNotFound if key doesn't exist.
 	// Complexity: O(store.Delete).
@@ -92,9 +146,34 @@ type Keyring interface {
 
 	// Sign signs data with the named key.
 	// Returns ErrKeyNotFound if key doesn't exist.
+	// Returns ErrSigningDenied or ErrConfirmTimeout if a confirmation hook
+	// (WithConfirmHook) rejects or does not answer.
+	// Returns a *PolicyViolationError if the key has a SigningPolicy, since a
+	// policy cannot be evaluated against raw data; use SignSignDoc instead.
 	// Complexity: O(GetKey) + O(n) where n is data length.
 	Sign(name string, data []byte) ([]byte, error)
 
+	// SignSignDoc evaluates the key's SigningPolicy (if any) against doc and
+	// signs doc's sign bytes. See keyring_policy.go.
+	// Returns ErrKeyNotFound if key doesn't exist.
+	// Returns a *PolicyViolationError (matching ErrPolicyViolation) if the
+	// policy rejects doc.
+	// Returns ErrSigningDenied or ErrConfirmTimeout if a confirmation hook
+	// (WithConfirmHook) rejects or does not answer.
+	// Complexity: O(GetKey) + O(n) where n is the SignDoc JSON size.
+	SignSignDoc(name string, doc PolicySignDoc) ([]byte, error)
+
+	// SetPolicy attaches a SigningPolicy to a key, persisting it in the key's
+	// KeyEntry. A nil policy removes any existing policy.
+	// Returns ErrKeyNotFound if key doesn't exist.
+	// Complexity: O(store.Get) + O(store.Put).
+	SetPolicy(name string, policy *SigningPolicy) error
+
+	// GetPolicy returns a copy of the key's SigningPolicy, or nil if none is set.
+	// Returns ErrKeyNotFound if key doesn't exist.
+	// Complexity: O(store.Get).
+	GetPolicy(name string) (*SigningPolicy, error)
+
 	// Close releases all resources and zeroizes all cached private keys.
 	// After Close is called, all other methods will return ErrKeyringClosed.
 	//
@@ -136,6 +215,35 @@ type defaultKeyring struct {
 	maxCacheSize int
 	// closed indicates if the keyring has been closed
 	closed bool
+	// policyGen is incremented by SetPolicy; GetKey skips caching signers
+	// loaded under an older generation
+	policyGen uint64
+
+	// spend tracks daily spend for keys with a DailySpendLimit
+	spend *spendTracker
+	// usage tracks when each key last signed (see KeyMetadata.LastUsedAt)
+	usage *usageTracker
+	// spendExtractor computes message spend for DailySpendLimit
+	spendExtractor SpendExtractor
+	// now is the time source for daily spend windows
+	now func() time.Time
+
+	// confirm is invoked before signing (nil disables confirmation)
+	confirm ConfirmHook
+	// confirmTimeout bounds confirm (zero means no timeout)
+	confirmTimeout time.Duration
+
+	// kdfParams are the Argon2id parameters encrypted entries are upgraded
+	// to on unlock (see rekeyEntry)
+	kdfParams KDFParams
+
+	// secureMemory keeps cached private keys in locked memory
+	// (see WithSecureMemory)
+	secureMemory bool
+
+	// attempts throttles failed password attempts (nil means unlimited;
+	// see WithPasswordAttemptLimit)
+	attempts *passwordAttempts
 }
 
 // KeyringOption configures a Keyring.
@@ -157,6 +265,13 @@ func NewKeyring(store SimpleKeyStore, opts ...KeyringOption) Keyring {
 		cache:        make(map[string]Signer),
 		cacheOrder:   make([]string, 0, 100),
 		maxCacheSize: 100,
+
+		spend:          newSpendTracker(),
+		usage:          newUsageTracker(),
+		spendExtractor: DefaultSpendExtractor,
+		now:            time.Now,
+
+		kdfParams: DefaultKDFParams(),
 	}
 	for _, opt := range opts {
 		opt(kr)
@@ -200,6 +315,7 @@ func (kr *defaultKeyring) NewKey(name string, algo Algorithm) (Signer, error) {
 		PrivateKey: privKey.Bytes(),
 		PublicKey:  privKey.PublicKey().Bytes(),
 		Encrypted:  false,
+		Metadata:   &KeyMetadata{CreatedAt: kr.now()},
 	}
 
 	// Store
@@ -208,7 +324,12 @@ func (kr *defaultKeyring) NewKey(name string, algo Algorithm) (Signer, error) {
 	}
 
 	// Create signer and cache
-	signer := NewSigner(privKey)
+	signer, err := kr.newSigner(privKey)
+	if err != nil {
+		// Don't leave behind a key the caller never received
+		_ = kr.store.Delete(name)
+		return nil, err
+	}
 	kr.addToCache(name, signer)
 
 	return signer, nil
@@ -255,6 +376,7 @@ func (kr *defaultKeyring) ImportKey(name string, privKeyBytes []byte, algo Algor
 		PrivateKey: privKey.Bytes(),
 		PublicKey:  privKey.PublicKey().Bytes(),
 		Encrypted:  false,
+		Metadata:   &KeyMetadata{CreatedAt: kr.now()},
 	}
 
 	// Store
@@ -263,23 +385,35 @@ func (kr *defaultKeyring) ImportKey(name string, privKeyBytes []byte, algo Algor
 	}
 
 	// Create signer and cache
-	signer := NewSigner(privKey)
+	signer, err := kr.newSigner(privKey)
+	if err != nil {
+		// Don't leave behind a key the caller never received
+		_ = kr.store.Delete(name)
+		return nil, err
+	}
 	kr.addToCache(name, signer)
 
 	return signer, nil
 }
 
-// PBKDF2 parameters for key derivation (must match file_keystore.go).
+// PBKDF2 parameters for legacy encrypted entries (must match file_keystore.go).
 const (
 	exportPBKDF2Iterations = 100_000
 	exportPBKDF2KeyLen     = 32 // AES-256 requires 32-byte key
 )
 
 // ExportKey exports a private key.
-// For encrypted keys, password is used to derive the decryption key via PBKDF2.
-// Returns ErrInvalidPassword if the password is incorrect for encrypted keys.
+// For encrypted keys, password is used to derive the decryption key with the
+// entry's KDF. Entries still using PBKDF2 (or weaker Argon2id parameters than
+// the keyring's WithKDFParams) are transparently re-encrypted with Argon2id
+// once unlocked.
+// Returns ErrInvalidPassword if the password is incorrect for encrypted keys,
+// or if the entry was tampered with (see decryptExportKey).
+// Returns ErrPasswordRateLimited or ErrKeyLockedOut if failed attempts are
+// throttled (see WithPasswordAttemptLimit).
 // Note: Caller should zero the returned bytes when done with them.
-// Complexity: O(store.Get) + O(PBKDF2) for encrypted keys.
+// Complexity: O(store.Get) + O(KDF) for encrypted keys, plus O(Argon2id)
+// when the entry is upgraded.
 func (kr *defaultKeyring) ExportKey(name string, password string) ([]byte, error) {
 	kr.mu.RLock()
 	if err := kr.checkClosed(); err != nil {
@@ -297,8 +431,18 @@ func (kr *defaultKeyring) ExportKey(name string, password string) ([]byte, error
 	// Also zero Salt if present (not strictly sensitive but good hygiene)
 	defer Zeroize(entry.Salt)
 
+	if entry.Multisig != nil {
+		return nil, ErrMultisigKey
+	}
+
 	if entry.Encrypted {
-		return kr.decryptExportKey(entry, password, name)
+		finish, err := kr.attempts.begin(name, kr.now())
+		if err != nil {
+			return nil, err
+		}
+		key, err := kr.decryptExportKey(entry, password, name)
+		finish(err, kr.now())
+		return key, err
 	}
 
 	// Return a copy to prevent external mutation
@@ -307,9 +451,10 @@ func (kr *defaultKeyring) ExportKey(name string, password string) ([]byte, error
 	return result, nil
 }
 
-// decryptExportKey decrypts an encrypted key entry using the provided password.
-// Uses PBKDF2 for key derivation and AES-GCM for decryption.
-// Complexity: O(PBKDF2 iterations) + O(ciphertext length).
+// decryptExportKey decrypts an encrypted key entry using the provided password
+// and upgrades its encryption if needed (see rekeyEntry).
+// Uses the entry's KDF for key derivation and AES-GCM for decryption.
+// Complexity: O(KDF) + O(ciphertext length).
 func (kr *defaultKeyring) decryptExportKey(entry *KeyEntry, password string, name string) ([]byte, error) {
 	// Validate encryption parameters
 	if len(entry.Salt) < MinSaltLength {
@@ -324,19 +469,45 @@ func (kr *defaultKeyring) decryptExportKey(entry *KeyEntry, password string, nam
 	defer Zeroize(passwordBytes)
 
 	// Derive decryption key from password and stored salt
-	derivedKey := pbkdf2.Key(passwordBytes, entry.Salt, exportPBKDF2Iterations, exportPBKDF2KeyLen, sha256.New)
+	derivedKey, err := deriveEntryKey(entry, passwordBytes)
+	if err != nil {
+		return nil, err
+	}
 	defer Zeroize(derivedKey) // Zero derived key after use
 
 	// Decrypt private key data using AES-GCM
 	plaintext, err := decryptAESGCMKeyring(derivedKey, entry.Nonce, entry.PrivateKey, []byte(name))
-	if err != nil {
-		// Authentication failure means wrong password or tampered data
+
+	// SECURITY: Every authentication failure - wrong password, tampered
+	// ciphertext, or a stored public key that does not belong to the
+	// decrypted private key - returns the same ErrInvalidPassword after the
+	// same KDF and decryption work, so neither the error nor its timing tells
+	// an attacker which check failed.
+	if err != nil || !entryPublicKeyMatches(entry, plaintext) {
+		Zeroize(plaintext)
 		return nil, ErrInvalidPassword
 	}
 
+	kr.rekeyEntry(entry, plaintext, password)
 	return plaintext, nil
 }
 
+// entryPublicKeyMatches reports whether privKeyBytes is the private key of
+// the entry's stored public key, comparing in constant time. Entries without
+// a stored public key match any key.
+// Complexity: O(1) for supported algorithms (one public key derivation).
+func entryPublicKeyMatches(entry *KeyEntry, privKeyBytes []byte) bool {
+	if len(entry.PublicKey) == 0 {
+		return true
+	}
+	privKey, err := PrivateKeyFromBytes(entry.Algorithm, privKeyBytes)
+	if err != nil {
+		return false
+	}
+	defer privKey.Zeroize()
+	return ConstantTimeEqual(privKey.PublicKey().Bytes(), entry.PublicKey)
+}
+
 // decryptAESGCMKeyring decrypts ciphertext using AES-256-GCM.
 // Returns error if authentication fails (wrong password or tampered data).
 // Complexity: O(n) where n is ciphertext length.
@@ -376,6 +547,7 @@ func (kr *defaultKeyring) GetKey(name string) (Signer, error) {
 		kr.mu.RUnlock()
 		return signer, nil
 	}
+	gen := kr.policyGen
 	kr.mu.RUnlock()
 
 	// Load from store (potential duplicate work if racing, but correctness preserved)
@@ -386,14 +558,26 @@ func (kr *defaultKeyring) GetKey(name string) (Signer, error) {
 	// Zero the entry's private key bytes after we're done with them
 	defer Zeroize(entry.PrivateKey)
 
+	if entry.Multisig != nil {
+		return nil, ErrMultisigKey
+	}
+
 	// Reconstruct signer
 	privKey, err := PrivateKeyFromBytes(entry.Algorithm, entry.PrivateKey)
 	if err != nil {
 		return nil, ErrInvalidKey
 	}
 
-	signer := NewSigner(privKey)
-	kr.addToCache(name, signer)
+	signer, err := kr.newSigner(privKey)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Policy != nil {
+		// SECURITY: Policy-bound keys are cached wrapped so that neither
+		// Keyring.Sign nor the returned Signer can sign raw data.
+		signer = &policySigner{Signer: signer, name: name, policy: entry.Policy}
+	}
+	kr.addToCacheGen(name, signer, gen)
 
 	return signer, nil
 }
@@ -427,21 +611,28 @@ func (kr *defaultKeyring) DeleteKey(name string) error {
 		return err
 	}
 
-	// Remove from cache and zeroize if present
+	kr.removeFromCacheLocked(name)
+	kr.mu.Unlock()
+
+	kr.spend.clear(name)
+	kr.usage.clear(name)
+	kr.attempts.clear(name)
+	return kr.store.Delete(name)
+}
+
+// removeFromCacheLocked removes and zeroizes a cached signer.
+// Must be called with the write lock held.
+func (kr *defaultKeyring) removeFromCacheLocked(name string) {
 	if signer, ok := kr.cache[name]; ok {
 		zeroizeSigner(signer)
 		delete(kr.cache, name)
 	}
-	// Remove from order slice
 	for i, n := range kr.cacheOrder {
 		if n == name {
 			kr.cacheOrder = append(kr.cacheOrder[:i], kr.cacheOrder[i+1:]...)
 			break
 		}
 	}
-	kr.mu.Unlock()
-
-	return kr.store.Delete(name)
 }
 
 // Sign signs data with the named key.
@@ -457,6 +648,11 @@ func (kr *defaultKeyring) Sign(name string, data []byte) ([]byte, error) {
 		return nil, ErrDataTooLarge
 	}
 
+	// Confirm before taking the lock: the hook may wait on a user.
+	if err := kr.confirmSigning(ConfirmRequest{Key: name, Data: data}); err != nil {
+		return nil, err
+	}
+
 	kr.mu.RLock()
 	defer kr.mu.RUnlock()
 
@@ -466,7 +662,11 @@ func (kr *defaultKeyring) Sign(name string, data []byte) ([]byte, error) {
 
 	// Check cache first (hot path, already holding lock)
 	if signer, ok := kr.cache[name]; ok {
-		return signer.Sign(data)
+		sig, err := signer.Sign(data)
+		if err == nil {
+			kr.usage.record(name, kr.now())
+		}
+		return sig, err
 	}
 
 	// Not in cache - need to load from store.
@@ -478,6 +678,14 @@ func (kr *defaultKeyring) Sign(name string, data []byte) ([]byte, error) {
 	}
 	defer Zeroize(entry.PrivateKey)
 
+	if entry.Multisig != nil {
+		return nil, ErrMultisigKey
+	}
+	if entry.Policy != nil {
+		return nil, &PolicyViolationError{Key: name, Rule: PolicyRuleRawSign,
+			Reason: "key has a signing policy; use Keyring.SignSignDoc"}
+	}
+
 	privKey, err := PrivateKeyFromBytes(entry.Algorithm, entry.PrivateKey)
 	if err != nil {
 		return nil, ErrInvalidKey
@@ -489,6 +697,9 @@ func (kr *defaultKeyring) Sign(name string, data []byte) ([]byte, error) {
 	// Zeroize the temporary signer's key since we can't cache it
 	zeroizeSigner(signer)
 
+	if err == nil {
+		kr.usage.record(name, kr.now())
+	}
 	return sig, err
 }
 
@@ -501,6 +712,30 @@ func (kr *defaultKeyring) addToCache(name string, signer Signer) {
 
 	kr.mu.Lock()
 	defer kr.mu.Unlock()
+	kr.addToCacheLocked(name, signer)
+}
+
+// addToCacheGen is addToCache, skipped if SetPolicy has run since gen was
+// read, so a signer loaded under a stale policy is never cached.
+func (kr *defaultKeyring) addToCacheGen(name string, signer Signer, gen uint64) {
+	if kr.maxCacheSize <= 0 {
+		return
+	}
+
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	if kr.policyGen != gen {
+		return
+	}
+	kr.addToCacheLocked(name, signer)
+}
+
+// addToCacheLocked adds a signer to the cache.
+// Must be called with the write lock held.
+func (kr *defaultKeyring) addToCacheLocked(name string, signer Signer) {
+	if kr.closed {
+		return
+	}
 
 	// Already in cache? Move to front
 	if _, ok := kr.cache[name]; ok {
@@ -602,8 +837,16 @@ func (kr *defaultKeyring) Close() error {
 }
 
 // zeroizeSigner attempts to zeroize the private key within a signer.
-// Works with BasicSigner which wraps a PrivateKey.
+// Works with BasicSigner which wraps a PrivateKey, and with the lockedSigner
+// used by WithSecureMemory.
 func zeroizeSigner(s Signer) {
+	if ps, ok := s.(*policySigner); ok {
+		s = ps.Signer
+	}
+	if ls, ok := s.(*lockedSigner); ok {
+		ls.zeroize()
+		return
+	}
 	// Type assert to access the underlying PrivateKey
 	if bs, ok := s.(*BasicSigner); ok {
 		if bs.privateKey != nil {
@@ -0,0 +1,234 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testArmorPassphrase = "correct horse battery staple"
+
+// exportTestArmor creates a key in a fresh keyring and exports it armored.
+func exportTestArmor(t *testing.T) (Signer, []byte) {
+	t.Helper()
+	kr := NewKeyring(NewMemoryStore())
+	signer, err := kr.NewKey("backup", AlgorithmEd25519)
+	if err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+	armored, err := kr.ExportArmored("backup", testArmorPassphrase)
+	if err != nil {
+		t.Fatalf("ExportArmored failed: %v", err)
+	}
+	return signer, armored
+}
+
+func TestKeyringArmoredRoundTrip(t *testing.T) {
+	original, armored := exportTestArmor(t)
+
+	if !bytes.HasPrefix(armored, []byte(armorBeginLine+"\n")) {
+		t.Errorf("armored output missing BEGIN line:\n%s", armored)
+	}
+	if !bytes.HasSuffix(armored, []byte(armorEndLine+"\n")) {
+		t.Errorf("armored output missing END line:\n%s", armored)
+	}
+	for _, line := range strings.Split(string(armored), "\n") {
+		if len(line) > armorLineLength && !strings.HasPrefix(line, "-----") {
+			t.Errorf("line exceeds %d columns: %q", armorLineLength, line)
+		}
+	}
+
+	// Import into a different keyring, as when moving between machines.
+	kr := NewKeyring(NewMemoryStore())
+	imported, err := kr.ImportArmored("restored", armored, testArmorPassphrase)
+	if err != nil {
+		t.Fatalf("ImportArmored failed: %v", err)
+	}
+	if !imported.PublicKey().Equals(original.PublicKey()) {
+		t.Error("imported public key does not match original")
+	}
+
+	data := []byte("message")
+	sig1, _ := original.Sign(data)
+	sig2, _ := imported.Sign(data)
+	if !bytes.Equal(sig1, sig2) {
+		t.Error("imported key produces different signatures")
+	}
+}
+
+func TestKeyringArmoredNoPlaintext(t *testing.T) {
+	kr := NewKeyring(NewMemoryStore())
+	if _, err := kr.NewKey("secret", AlgorithmEd25519); err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+	privKey, err := kr.ExportKey("secret", "")
+	if err != nil {
+		t.Fatalf("ExportKey failed: %v", err)
+	}
+	defer Zeroize(privKey)
+
+	armored, err := kr.ExportArmored("secret", testArmorPassphrase)
+	if err != nil {
+		t.Fatalf("ExportArmored failed: %v", err)
+	}
+	if bytes.Contains(armored, []byte(base64.StdEncoding.EncodeToString(privKey[:32]))) {
+		t.Error("armored output contains plaintext key material")
+	}
+
+	// Each export uses a fresh salt and nonce.
+	armored2, err := kr.ExportArmored("secret", testArmorPassphrase)
+	if err != nil {
+		t.Fatalf("ExportArmored failed: %v", err)
+	}
+	if bytes.Equal(armored, armored2) {
+		t.Error("two exports produced identical output")
+	}
+}
+
+func TestKeyringArmoredErrors(t *testing.T) {
+	_, armored := exportTestArmor(t)
+
+	replaceHeader := func(header, value string) []byte {
+		lines := strings.Split(string(armored), "\n")
+		for i, line := range lines {
+			if strings.HasPrefix(line, header+": ") {
+				lines[i] = header + ": " + value
+			}
+		}
+		return []byte(strings.Join(lines, "\n"))
+	}
+	insertHeader := func(line string) []byte {
+		return bytes.Replace(armored, []byte("Version: 1\n"), []byte("Version: 1\n"+line+"\n"), 1)
+	}
+
+	tests := []struct {
+		name       string
+		armored    []byte
+		passphrase string
+		wantErr    error
+	}{
+		{"wrong passphrase", armored, "wrong", ErrInvalidPassword},
+		{"empty passphrase", armored, "", ErrEmptyPassphrase},
+		{"unsupported version", replaceHeader("Version", "2"), testArmorPassphrase, ErrUnsupportedArmorVersion},
+		{"tampered kdf params", replaceHeader("KDF-Params", "t=2,m=65536,p=4"), testArmorPassphrase, ErrInvalidPassword},
+		{"tampered algorithm", replaceHeader("Algorithm", "secp256k1"), testArmorPassphrase, ErrInvalidPassword},
+		{"unknown algorithm", replaceHeader("Algorithm", "rsa"), testArmorPassphrase, ErrInvalidArmor},
+		{"excessive memory", replaceHeader("KDF-Params", "t=3,m=4194304,p=4"), testArmorPassphrase, ErrInvalidArmor},
+		{"weak kdf", replaceHeader("KDF-Params", "t=1,m=1024,p=1"), testArmorPassphrase, ErrInvalidArmor},
+		{"unsupported cipher", replaceHeader("Cipher", "aes-256-gcm"), testArmorPassphrase, ErrInvalidArmor},
+		{"unknown header", insertHeader("Extra: 1"), testArmorPassphrase, ErrInvalidArmor},
+		{"duplicate header", insertHeader("Version: 1"), testArmorPassphrase, ErrInvalidArmor},
+		{"missing end line", bytes.TrimSuffix(armored, []byte(armorEndLine+"\n")), testArmorPassphrase, ErrInvalidArmor},
+		{"not armor", []byte("hello"), testArmorPassphrase, ErrInvalidArmor},
+		{"oversized", bytes.Repeat([]byte("A"), maxArmorSize+1), testArmorPassphrase, ErrInvalidArmor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kr := NewKeyring(NewMemoryStore())
+			_, err := kr.ImportArmored("restored", tt.armored, tt.passphrase)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestKeyringArmoredTolerance(t *testing.T) {
+	original, armored := exportTestArmor(t)
+
+	// Comment headers, CRLF line endings and surrounding whitespace survive
+	// email and copy-paste.
+	withComment := bytes.Replace(armored, []byte("Version: 1\n"), []byte("Version: 1\nComment: laptop backup\n"), 1)
+	crlf := bytes.ReplaceAll(withComment, []byte("\n"), []byte("\r\n"))
+	padded := append(append([]byte("\n  "), crlf...), []byte("\n\n")...)
+
+	kr := NewKeyring(NewMemoryStore())
+	imported, err := kr.ImportArmored("restored", padded, testArmorPassphrase)
+	if err != nil {
+		t.Fatalf("ImportArmored failed: %v", err)
+	}
+	if !imported.PublicKey().Equals(original.PublicKey()) {
+		t.Error("imported public key does not match original")
+	}
+}
+
+func TestKeyringArmoredExistingKey(t *testing.T) {
+	_, armored := exportTestArmor(t)
+
+	kr := NewKeyring(NewMemoryStore())
+	if _, err := kr.NewKey("taken", AlgorithmEd25519); err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+	if _, err := kr.ImportArmored("taken", armored, testArmorPassphrase); !errors.Is(err, ErrKeyExists) {
+		t.Errorf("expected ErrKeyExists, got %v", err)
+	}
+	if _, err := kr.ImportArmored("bad/name", armored, testArmorPassphrase); !errors.Is(err, ErrInvalidKeyName) {
+		t.Errorf("expected ErrInvalidKeyName, got %v", err)
+	}
+}
+
+func TestKeyringArmoredExportErrors(t *testing.T) {
+	kr := NewKeyring(NewMemoryStore())
+	if _, err := kr.NewKey("key", AlgorithmEd25519); err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+
+	if _, err := kr.ExportArmored("key", ""); !errors.Is(err, ErrEmptyPassphrase) {
+		t.Errorf("expected ErrEmptyPassphrase, got %v", err)
+	}
+	if _, err := kr.ExportArmored("missing", testArmorPassphrase); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+
+	if err := kr.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := kr.ExportArmored("key", testArmorPassphrase); !errors.Is(err, ErrKeyringClosed) {
+		t.Errorf("expected ErrKeyringClosed, got %v", err)
+	}
+	if _, err := kr.ImportArmored("key", nil, testArmorPassphrase); !errors.Is(err, ErrKeyringClosed) {
+		t.Errorf("expected ErrKeyringClosed, got %v", err)
+	}
+}
+
+func TestKeyringArmoredEncryptedEntry(t *testing.T) {
+	store := NewMemoryStore()
+	kr := NewKeyring(store)
+	original, err := kr.NewKey("enc", AlgorithmEd25519)
+	if err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+	privKey, err := kr.ExportKey("enc", "")
+	if err != nil {
+		t.Fatalf("ExportKey failed: %v", err)
+	}
+
+	// Replace the entry with one encrypted under the passphrase.
+	entry, err := createEncryptedKeyEntry("enc", privKey, testArmorPassphrase)
+	if err != nil {
+		t.Fatalf("createEncryptedKeyEntry failed: %v", err)
+	}
+	if err := store.Put(entry, true); err != nil {
+		t.Fatalf("store.Put failed: %v", err)
+	}
+
+	kr2 := NewKeyring(store)
+	if _, err := kr2.ExportArmored("enc", "wrong"); !errors.Is(err, ErrInvalidPassword) {
+		t.Errorf("expected ErrInvalidPassword, got %v", err)
+	}
+	armored, err := kr2.ExportArmored("enc", testArmorPassphrase)
+	if err != nil {
+		t.Fatalf("ExportArmored failed: %v", err)
+	}
+
+	imported, err := NewKeyring(NewMemoryStore()).ImportArmored("enc", armored, testArmorPassphrase)
+	if err != nil {
+		t.Fatalf("ImportArmored failed: %v", err)
+	}
+	if !imported.PublicKey().Equals(original.PublicKey()) {
+		t.Error("imported public key does not match original")
+	}
+}
@@ -0,0 +1,105 @@
+package crypto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Confirmation errors.
+var (
+	// ErrSigningDenied is returned when a confirmation hook rejects a request.
+	// Errors returned by a hook are wrapped so errors.Is matches both this
+	// sentinel and the hook's own error.
+	ErrSigningDenied = errors.New("signing denied")
+
+	// ErrConfirmTimeout is returned when a confirmation hook does not answer
+	// within the configured timeout. Timeouts are treated as denials.
+	ErrConfirmTimeout = errors.New("signing confirmation timed out")
+)
+
+// ConfirmRequest describes a signing request awaiting confirmation.
+type ConfirmRequest struct {
+	// Key is the name of the key that will sign.
+	Key string
+
+	// SignDoc is the document being signed, or nil for raw signing via
+	// Keyring.Sign. For *types.SignDoc callers it is the *types.SignDoc itself.
+	SignDoc PolicySignDoc
+
+	// Data is the exact bytes that will be signed.
+	Data []byte
+}
+
+// ConfirmHook is invoked by the Keyring before every signature it produces.
+// Return nil to approve; any error denies the request.
+//
+// The hook runs without Keyring locks held, so it may block on user input.
+// ctx is cancelled when the confirmation timeout expires; hooks should
+// abandon their prompt when it is done.
+//
+// See types.KeyringConfirmHook for an adapter that hands the hook a parsed
+// *types.SignDoc.
+type ConfirmHook func(ctx context.Context, req ConfirmRequest) error
+
+// WithConfirmHook installs a confirmation hook for Keyring.Sign and
+// Keyring.SignSignDoc. timeout bounds how long the hook may take; zero
+// means no timeout.
+//
+// SECURITY: Signers returned by GetKey sign directly and are not
+// intercepted. Applications relying on confirmation should sign only through
+// the Keyring.
+func WithConfirmHook(hook ConfirmHook, timeout time.Duration) KeyringOption {
+	return func(k *defaultKeyring) {
+		k.confirm = hook
+		k.confirmTimeout = timeout
+	}
+}
+
+// confirmSigning runs the keyring's confirmation hook, if any.
+func (kr *defaultKeyring) confirmSigning(req ConfirmRequest) error {
+	if kr.confirm == nil {
+		return nil
+	}
+	return RunConfirm(context.Background(), kr.confirmTimeout, func(ctx context.Context) error {
+		return kr.confirm(ctx, req)
+	})
+}
+
+// RunConfirm calls confirm, enforcing timeout (zero means none), and
+// normalizes the outcome:
+//   - nil if confirm approves;
+//   - an error matching ErrConfirmTimeout if the timeout or ctx expires first;
+//   - an error matching ErrSigningDenied (and confirm's own error) otherwise.
+//
+// confirm runs in its own goroutine so an unresponsive hook cannot stall the
+// caller past the timeout; it receives a context cancelled at that point.
+func RunConfirm(ctx context.Context, timeout time.Duration, confirm func(ctx context.Context) error) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- confirm(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		switch {
+		case err == nil:
+			return nil
+		case errors.Is(err, ErrSigningDenied):
+			return err
+		case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+			return fmt.Errorf("%w: %w", ErrConfirmTimeout, err)
+		default:
+			return fmt.Errorf("%w: %w", ErrSigningDenied, err)
+		}
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrConfirmTimeout, ctx.Err())
+	}
+}
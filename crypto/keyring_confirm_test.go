@@ -0,0 +1,129 @@
+package crypto
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestKeyringConfirmHook(t *testing.T) {
+	var calls atomic.Int32
+	var last ConfirmRequest
+	approve := true
+	hook := func(_ context.Context, req ConfirmRequest) error {
+		calls.Add(1)
+		last = req
+		if !approve {
+			return ErrSigningDenied
+		}
+		return nil
+	}
+
+	kr := NewKeyring(NewMemoryStore(), WithConfirmHook(hook, time.Second))
+	signer, err := kr.NewKey("k", AlgorithmEd25519)
+	if err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+
+	doc := newTestPolicyDoc(t, "punnet-1", "1", 5)
+	sig, err := kr.SignSignDoc("k", doc)
+	if err != nil {
+		t.Fatalf("SignSignDoc failed: %v", err)
+	}
+	signBytes, _ := doc.GetSignBytes()
+	if !signer.PublicKey().Verify(signBytes, sig) {
+		t.Error("signature does not verify")
+	}
+	if last.Key != "k" || last.SignDoc == nil || string(last.Data) != string(signBytes) {
+		t.Errorf("unexpected confirm request: %+v", last)
+	}
+
+	if _, err := kr.Sign("k", []byte("raw")); err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	if last.SignDoc != nil || string(last.Data) != "raw" {
+		t.Errorf("raw request should carry data and no SignDoc: %+v", last)
+	}
+
+	approve = false
+	if _, err := kr.SignSignDoc("k", doc); !errors.Is(err, ErrSigningDenied) {
+		t.Errorf("expected ErrSigningDenied, got %v", err)
+	}
+	if _, err := kr.Sign("k", []byte("raw")); !errors.Is(err, ErrSigningDenied) {
+		t.Errorf("expected ErrSigningDenied, got %v", err)
+	}
+	if got := calls.Load(); got != 4 {
+		t.Errorf("expected 4 confirm calls, got %d", got)
+	}
+}
+
+func TestKeyringConfirmSkippedOnPolicyViolation(t *testing.T) {
+	var calls atomic.Int32
+	kr := NewKeyring(NewMemoryStore(), WithConfirmHook(func(context.Context, ConfirmRequest) error {
+		calls.Add(1)
+		return nil
+	}, 0))
+	if _, err := kr.NewKey("k", AlgorithmEd25519); err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+	if err := kr.SetPolicy("k", &SigningPolicy{AllowedChainIDs: []string{"punnet-1"}}); err != nil {
+		t.Fatalf("SetPolicy failed: %v", err)
+	}
+
+	if _, err := kr.SignSignDoc("k", newTestPolicyDoc(t, "punnet-2", "1")); !errors.Is(err, ErrPolicyViolation) {
+		t.Fatalf("expected ErrPolicyViolation, got %v", err)
+	}
+	if calls.Load() != 0 {
+		t.Error("user was asked to confirm a request the policy rejects")
+	}
+	if _, err := kr.SignSignDoc("k", newTestPolicyDoc(t, "punnet-1", "1")); err != nil {
+		t.Fatalf("SignSignDoc failed: %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 confirm call, got %d", calls.Load())
+	}
+}
+
+func TestRunConfirm(t *testing.T) {
+	custom := errors.New("user closed dialog")
+
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		confirm func(ctx context.Context) error
+		wantErr []error
+	}{
+		{"approve", 0, func(context.Context) error { return nil }, nil},
+		{"deny", 0, func(context.Context) error { return ErrSigningDenied }, []error{ErrSigningDenied}},
+		{"custom error", 0, func(context.Context) error { return custom }, []error{ErrSigningDenied, custom}},
+		{"hook ignores timeout", 10 * time.Millisecond, func(context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		}, []error{ErrConfirmTimeout}},
+		{"hook honors timeout", 10 * time.Millisecond, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, []error{ErrConfirmTimeout}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RunConfirm(context.Background(), tt.timeout, tt.confirm)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("expected approval, got %v", err)
+				}
+				return
+			}
+			for _, want := range tt.wantErr {
+				if !errors.Is(err, want) {
+					t.Errorf("expected %v in chain, got %v", want, err)
+				}
+			}
+			if errors.Is(err, ErrConfirmTimeout) && errors.Is(err, ErrSigningDenied) {
+				t.Errorf("timeout and denial should be distinguishable: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// KDF identifies the password-based key derivation function protecting an
+// encrypted KeyEntry.
+//
+// COMPATIBILITY: Entries written before the KDF field existed have an empty
+// KDF and are treated as KDFPBKDF2SHA256 with exportPBKDF2Iterations.
+type KDF string
+
+const (
+	// KDFPBKDF2SHA256 is PBKDF2-HMAC-SHA256 with exportPBKDF2Iterations
+	// iterations (the legacy scheme).
+	KDFPBKDF2SHA256 KDF = "pbkdf2-sha256"
+
+	// KDFArgon2id is Argon2id with the entry's KDFParams.
+	KDFArgon2id KDF = "argon2id"
+)
+
+// ErrUnsupportedKDF is returned when an encrypted KeyEntry names an unknown KDF.
+var ErrUnsupportedKDF = errors.New("unsupported key derivation function")
+
+// KDFParams holds the Argon2id cost parameters of an encrypted KeyEntry.
+type KDFParams struct {
+	// Time is the number of passes over memory.
+	Time uint32 `json:"time"`
+
+	// Memory is the memory cost in KiB.
+	Memory uint32 `json:"memory"`
+
+	// Threads is the degree of parallelism.
+	Threads uint8 `json:"threads"`
+}
+
+// DefaultKDFParams returns the Argon2id parameters used for new and
+// re-encrypted entries (the same defaults as ExportArmored).
+func DefaultKDFParams() KDFParams {
+	return KDFParams{Time: ArmorArgon2Time, Memory: ArmorArgon2Memory, Threads: ArmorArgon2Threads}
+}
+
+// Validate checks the parameters against the bounds accepted by ImportArmored.
+//
+// SECURITY: Stored parameters are validated before deriving a key, so a
+// tampered entry cannot force unbounded memory use or a trivially weak KDF.
+func (p KDFParams) Validate() error {
+	if p.Time < armorMinArgon2Time || p.Time > armorMaxArgon2Time {
+		return fmt.Errorf("%w: argon2 time %d out of range [%d, %d]",
+			ErrInvalidEncryptionParams, p.Time, armorMinArgon2Time, armorMaxArgon2Time)
+	}
+	if p.Memory < armorMinArgon2Memory || p.Memory > armorMaxArgon2Memory {
+		return fmt.Errorf("%w: argon2 memory %d KiB out of range [%d, %d]",
+			ErrInvalidEncryptionParams, p.Memory, armorMinArgon2Memory, armorMaxArgon2Memory)
+	}
+	if p.Threads == 0 {
+		return fmt.Errorf("%w: argon2 parallelism must be positive", ErrInvalidEncryptionParams)
+	}
+	return nil
+}
+
+// weakerThan reports whether p costs less than target in time or memory.
+func (p KDFParams) weakerThan(target KDFParams) bool {
+	return p.Time < target.Time || p.Memory < target.Memory
+}
+
+// EncryptKeyEntry creates an encrypted KeyEntry protecting privKey with
+// AES-256-GCM under an Argon2id key derived from password. The key name is
+// bound as AEAD additional data, so an entry cannot be replayed under another
+// name.
+//
+// Returns ErrEmptyPassphrase if password is empty.
+// Returns ErrInvalidEncryptionParams if params fail Validate.
+// Complexity: O(Argon2id) + O(n) where n is key length.
+func EncryptKeyEntry(name string, algo Algorithm, privKey, pubKey []byte, password string, params KDFParams) (*KeyEntry, error) {
+	if err := validateKeyNameSimple(name); err != nil {
+		return nil, err
+	}
+	if password == "" {
+		return nil, ErrEmptyPassphrase
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, MinSaltLength)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	nonce := make([]byte, AESGCMNonceLength)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	passwordBytes := []byte(password)
+	defer Zeroize(passwordBytes)
+	derivedKey := argon2.IDKey(passwordBytes, salt, params.Time, params.Memory, params.Threads, exportPBKDF2KeyLen)
+	defer Zeroize(derivedKey)
+
+	ciphertext, err := encryptAESGCM(derivedKey, nonce, privKey, []byte(name))
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &KeyEntry{
+		Name:       name,
+		Algorithm:  algo,
+		PrivateKey: ciphertext,
+		Encrypted:  true,
+		Salt:       salt,
+		Nonce:      nonce,
+		KDF:        KDFArgon2id,
+		KDFParams:  &params,
+	}
+	if pubKey != nil {
+		entry.PublicKey = make([]byte, len(pubKey))
+		copy(entry.PublicKey, pubKey)
+	}
+	return entry, nil
+}
+
+// deriveEntryKey derives the AES-256-GCM key of an encrypted entry using the
+// entry's KDF.
+// Returns ErrUnsupportedKDF for unknown KDFs and ErrInvalidEncryptionParams
+// for missing or out-of-bounds Argon2id parameters.
+func deriveEntryKey(entry *KeyEntry, password []byte) ([]byte, error) {
+	switch entry.KDF {
+	case "", KDFPBKDF2SHA256:
+		return pbkdf2.Key(password, entry.Salt, exportPBKDF2Iterations, exportPBKDF2KeyLen, sha256.New), nil
+	case KDFArgon2id:
+		if entry.KDFParams == nil {
+			return nil, fmt.Errorf("%w: missing argon2 parameters", ErrInvalidEncryptionParams)
+		}
+		p := *entry.KDFParams
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+		return argon2.IDKey(password, entry.Salt, p.Time, p.Memory, p.Threads, exportPBKDF2KeyLen), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedKDF, entry.KDF)
+	}
+}
+
+// entryNeedsRekey reports whether an encrypted entry should be re-encrypted
+// under target: it uses a legacy KDF or weaker Argon2id parameters.
+// Entries that are stronger than target are left alone.
+func entryNeedsRekey(entry *KeyEntry, target KDFParams) bool {
+	if entry.KDF != KDFArgon2id || entry.KDFParams == nil {
+		return true
+	}
+	return entry.KDFParams.weakerThan(target)
+}
+
+// WithKDFParams sets the Argon2id parameters encrypted entries are upgraded
+// to when ExportKey unlocks them. Default is DefaultKDFParams().
+// Parameters that fail KDFParams.Validate (including the zero value) disable
+// re-encryption.
+func WithKDFParams(params KDFParams) KeyringOption {
+	return func(k *defaultKeyring) {
+		k.kdfParams = params
+	}
+}
+
+// rekeyEntry transparently re-encrypts an entry that was just unlocked with
+// password, if it uses a legacy KDF or weaker parameters than the keyring's
+// target. The upgrade is best effort: on any failure the old entry is kept
+// and the upgrade is retried on the next unlock.
+//
+// CONCURRENCY: The Argon2id derivation runs without holding kr.mu. The new
+// entry is written under the write lock only if the stored ciphertext is
+// unchanged, so a concurrent SetPolicy, DeleteKey or re-encryption is never
+// overwritten.
+//
+// Complexity: O(Argon2id) + O(store.Get) + O(store.Put) when an upgrade runs.
+func (kr *defaultKeyring) rekeyEntry(entry *KeyEntry, plaintext []byte, password string) {
+	target := kr.kdfParams
+	if target.Validate() != nil || !entryNeedsRekey(entry, target) {
+		return
+	}
+
+	upgraded, err := EncryptKeyEntry(entry.Name, entry.Algorithm, plaintext, entry.PublicKey, password, target)
+	if err != nil {
+		return
+	}
+
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	if kr.checkClosed() != nil {
+		return
+	}
+
+	current, err := kr.store.Get(entry.Name)
+	if err != nil {
+		return
+	}
+	defer Zeroize(current.PrivateKey)
+	if !ConstantTimeEqual(current.PrivateKey, entry.PrivateKey) || !ConstantTimeEqual(current.Salt, entry.Salt) {
+		return
+	}
+	// Carry over the stored policy and metadata; only the encryption changes
+	upgraded.Policy = current.Policy.Clone()
+	upgraded.Metadata = current.Metadata.Clone()
+	_ = kr.store.Put(upgraded, true)
+}
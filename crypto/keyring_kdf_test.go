@@ -0,0 +1,279 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// testKDFParams are the cheapest Argon2id parameters Validate accepts
+var testKDFParams = KDFParams{Time: 1, Memory: 19 * 1024, Threads: 1}
+
+// newLegacyEncryptedKey stores a PBKDF2-encrypted Ed25519 key and returns its
+// plaintext private key
+func newLegacyEncryptedKey(t *testing.T, store SimpleKeyStore, name, password string) []byte {
+	t.Helper()
+
+	privKey, err := GeneratePrivateKey(AlgorithmEd25519)
+	if err != nil {
+		t.Fatalf("GeneratePrivateKey failed: %v", err)
+	}
+	entry, err := createEncryptedKeyEntry(name, privKey.Bytes(), password)
+	if err != nil {
+		t.Fatalf("createEncryptedKeyEntry failed: %v", err)
+	}
+	if err := store.Put(entry, false); err != nil {
+		t.Fatalf("store.Put failed: %v", err)
+	}
+	return privKey.Bytes()
+}
+
+func TestEncryptKeyEntryRoundTrip(t *testing.T) {
+	privKey, err := GeneratePrivateKey(AlgorithmEd25519)
+	if err != nil {
+		t.Fatalf("GeneratePrivateKey failed: %v", err)
+	}
+
+	entry, err := EncryptKeyEntry("argon", AlgorithmEd25519, privKey.Bytes(), privKey.PublicKey().Bytes(), "pw", testKDFParams)
+	if err != nil {
+		t.Fatalf("EncryptKeyEntry failed: %v", err)
+	}
+	if !entry.Encrypted || entry.KDF != KDFArgon2id || entry.KDFParams == nil || *entry.KDFParams != testKDFParams {
+		t.Fatalf("unexpected entry encryption metadata: %+v", entry)
+	}
+	if bytes.Contains(entry.PrivateKey, privKey.Bytes()) {
+		t.Fatal("entry contains plaintext private key")
+	}
+
+	store := NewMemoryStore()
+	if err := store.Put(entry, false); err != nil {
+		t.Fatalf("store.Put failed: %v", err)
+	}
+	kr := NewKeyring(store, WithKDFParams(testKDFParams))
+
+	exported, err := kr.ExportKey("argon", "pw")
+	if err != nil {
+		t.Fatalf("ExportKey failed: %v", err)
+	}
+	if !bytes.Equal(exported, privKey.Bytes()) {
+		t.Fatal("exported key does not match original")
+	}
+	if _, err := kr.ExportKey("argon", "wrong"); err != ErrInvalidPassword {
+		t.Fatalf("expected ErrInvalidPassword, got %v", err)
+	}
+
+	// Already at the target parameters: the entry is not rewritten
+	stored, err := store.Get("argon")
+	if err != nil {
+		t.Fatalf("store.Get failed: %v", err)
+	}
+	if !bytes.Equal(stored.Salt, entry.Salt) {
+		t.Fatal("entry at target parameters was re-encrypted")
+	}
+}
+
+func TestEncryptKeyEntryValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		keyName  string
+		password string
+		params   KDFParams
+		wantErr  error
+	}{
+		{"empty password", "k", "", testKDFParams, ErrEmptyPassphrase},
+		{"invalid name", "a/b", "pw", testKDFParams, ErrInvalidKeyName},
+		{"zero params", "k", "pw", KDFParams{}, ErrInvalidEncryptionParams},
+		{"memory too low", "k", "pw", KDFParams{Time: 1, Memory: 1024, Threads: 1}, ErrInvalidEncryptionParams},
+		{"memory too high", "k", "pw", KDFParams{Time: 1, Memory: 2 * 1024 * 1024, Threads: 1}, ErrInvalidEncryptionParams},
+		{"time too high", "k", "pw", KDFParams{Time: 100, Memory: 19 * 1024, Threads: 1}, ErrInvalidEncryptionParams},
+		{"no threads", "k", "pw", KDFParams{Time: 1, Memory: 19 * 1024}, ErrInvalidEncryptionParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := EncryptKeyEntry(tt.keyName, AlgorithmEd25519, make([]byte, 64), nil, tt.password, tt.params)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestKeyringExportKeyUpgradesLegacyKDF(t *testing.T) {
+	store := NewMemoryStore()
+	original := newLegacyEncryptedKey(t, store, "legacy", "pw")
+
+	policy := &SigningPolicy{AllowedChainIDs: []string{"punnet-1"}}
+	kr := NewKeyring(store, WithKDFParams(testKDFParams))
+	if err := kr.SetPolicy("legacy", policy); err != nil {
+		t.Fatalf("SetPolicy failed: %v", err)
+	}
+
+	// A wrong password must not trigger an upgrade
+	if _, err := kr.ExportKey("legacy", "wrong"); err != ErrInvalidPassword {
+		t.Fatalf("expected ErrInvalidPassword, got %v", err)
+	}
+	stored, err := store.Get("legacy")
+	if err != nil {
+		t.Fatalf("store.Get failed: %v", err)
+	}
+	if stored.KDF != "" {
+		t.Fatalf("entry upgraded after failed unlock: KDF=%q", stored.KDF)
+	}
+
+	exported, err := kr.ExportKey("legacy", "pw")
+	if err != nil {
+		t.Fatalf("ExportKey failed: %v", err)
+	}
+	if !bytes.Equal(exported, original) {
+		t.Fatal("exported key does not match original")
+	}
+
+	upgraded, err := store.Get("legacy")
+	if err != nil {
+		t.Fatalf("store.Get failed: %v", err)
+	}
+	if upgraded.KDF != KDFArgon2id || upgraded.KDFParams == nil || *upgraded.KDFParams != testKDFParams {
+		t.Fatalf("entry not upgraded to Argon2id: KDF=%q params=%v", upgraded.KDF, upgraded.KDFParams)
+	}
+	if bytes.Equal(upgraded.Salt, stored.Salt) {
+		t.Fatal("upgraded entry reused the legacy salt")
+	}
+	if upgraded.Policy == nil || len(upgraded.Policy.AllowedChainIDs) != 1 {
+		t.Fatal("upgrade dropped the signing policy")
+	}
+
+	// The upgraded entry unlocks with the same password from a fresh keyring
+	kr2 := NewKeyring(store, WithKDFParams(testKDFParams))
+	exported, err = kr2.ExportKey("legacy", "pw")
+	if err != nil {
+		t.Fatalf("ExportKey after upgrade failed: %v", err)
+	}
+	if !bytes.Equal(exported, original) {
+		t.Fatal("exported key after upgrade does not match original")
+	}
+}
+
+func TestKeyringExportKeyUpgradesWeakerParams(t *testing.T) {
+	stronger := KDFParams{Time: 2, Memory: 19 * 1024, Threads: 1}
+
+	tests := []struct {
+		name        string
+		stored      KDFParams
+		target      KDFParams
+		wantUpgrade bool
+	}{
+		{"weaker time", testKDFParams, stronger, true},
+		{"stronger than target", stronger, testKDFParams, false},
+		{"re-encryption disabled", testKDFParams, KDFParams{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privKey, err := GeneratePrivateKey(AlgorithmEd25519)
+			if err != nil {
+				t.Fatalf("GeneratePrivateKey failed: %v", err)
+			}
+			entry, err := EncryptKeyEntry("k", AlgorithmEd25519, privKey.Bytes(), nil, "pw", tt.stored)
+			if err != nil {
+				t.Fatalf("EncryptKeyEntry failed: %v", err)
+			}
+			store := NewMemoryStore()
+			if err := store.Put(entry, false); err != nil {
+				t.Fatalf("store.Put failed: %v", err)
+			}
+
+			kr := NewKeyring(store, WithKDFParams(tt.target))
+			if _, err := kr.ExportKey("k", "pw"); err != nil {
+				t.Fatalf("ExportKey failed: %v", err)
+			}
+
+			got, err := store.Get("k")
+			if err != nil {
+				t.Fatalf("store.Get failed: %v", err)
+			}
+			upgraded := !bytes.Equal(got.Salt, entry.Salt)
+			if upgraded != tt.wantUpgrade {
+				t.Fatalf("expected upgrade=%v, got %v (params %v)", tt.wantUpgrade, upgraded, *got.KDFParams)
+			}
+			if upgraded && *got.KDFParams != tt.target {
+				t.Fatalf("expected params %v, got %v", tt.target, *got.KDFParams)
+			}
+		})
+	}
+}
+
+func TestKeyringExportKeyRejectsBadKDF(t *testing.T) {
+	huge := KDFParams{Time: 1, Memory: 4 * 1024 * 1024, Threads: 1}
+
+	tests := []struct {
+		name    string
+		kdf     KDF
+		params  *KDFParams
+		wantErr error
+	}{
+		{"unknown kdf", "scrypt", nil, ErrUnsupportedKDF},
+		{"missing params", KDFArgon2id, nil, ErrInvalidEncryptionParams},
+		{"unbounded memory", KDFArgon2id, &huge, ErrInvalidEncryptionParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryStore()
+			entry := &KeyEntry{
+				Name:       "bad",
+				Algorithm:  AlgorithmEd25519,
+				PrivateKey: []byte("some-ciphertext"),
+				Encrypted:  true,
+				Salt:       make([]byte, MinSaltLength),
+				Nonce:      make([]byte, AESGCMNonceLength),
+				KDF:        tt.kdf,
+				KDFParams:  tt.params,
+			}
+			if err := store.Put(entry, false); err != nil {
+				t.Fatalf("store.Put failed: %v", err)
+			}
+
+			kr := NewKeyring(store)
+			if _, err := kr.ExportKey("bad", "pw"); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestKeyEntryKDFCloneAndJSON(t *testing.T) {
+	params := testKDFParams
+	entry := &KeyEntry{Name: "k", Algorithm: AlgorithmEd25519, Encrypted: true, KDF: KDFArgon2id, KDFParams: &params}
+
+	clone := entry.Clone()
+	if clone.KDF != KDFArgon2id || clone.KDFParams == nil || *clone.KDFParams != params {
+		t.Fatalf("clone lost KDF metadata: %+v", clone)
+	}
+	clone.KDFParams.Time = 9
+	if entry.KDFParams.Time != params.Time {
+		t.Fatal("clone shares KDFParams with original")
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded KeyEntry
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.KDF != KDFArgon2id || decoded.KDFParams == nil || *decoded.KDFParams != params {
+		t.Fatalf("JSON round trip lost KDF metadata: %s", data)
+	}
+
+	// Entries written before the KDF field decode as legacy PBKDF2
+	var legacy KeyEntry
+	if err := json.Unmarshal([]byte(`{"name":"old","algorithm":"ed25519","encrypted":true}`), &legacy); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if legacy.KDF != "" || legacy.KDFParams != nil {
+		t.Fatalf("legacy entry has KDF metadata: %+v", legacy)
+	}
+}
@@ -0,0 +1,195 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Password attempt errors.
+var (
+	// ErrPasswordRateLimited is returned when a password attempt is made
+	// during the backoff that follows a failed attempt, or while another
+	// attempt on the same key is in progress.
+	ErrPasswordRateLimited = errors.New("password attempt rate limited")
+
+	// ErrKeyLockedOut is returned for keys locked after too many consecutive
+	// failed password attempts, until ResetPasswordAttempts is called.
+	ErrKeyLockedOut = errors.New("key locked out after too many failed password attempts")
+)
+
+// PasswordAttemptLimit throttles password attempts against encrypted keys
+// (ExportKey and ExportArmored). After each consecutive failure, attempts
+// are refused for a delay starting at BaseDelay and doubling up to MaxDelay;
+// after MaxFailures consecutive failures the key is locked out until
+// Keyring.ResetPasswordAttempts. A successful attempt clears the count.
+//
+// SECURITY: This bounds online guessing through the keyring. State is kept
+// in memory only and resets with the process; offline attacks on a copied
+// key store are bounded by the KDF (see KDFParams) instead.
+type PasswordAttemptLimit struct {
+	// MaxFailures is the number of consecutive failures that locks a key.
+	// Zero disables lockout.
+	MaxFailures int
+
+	// BaseDelay is the backoff after the first failure. Zero disables backoff.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the backoff. Zero means no cap.
+	MaxDelay time.Duration
+}
+
+// DefaultPasswordAttemptLimit returns a limit suitable for interactive use:
+// a 1s backoff doubling up to 5 minutes, and lockout after 10 failures.
+func DefaultPasswordAttemptLimit() PasswordAttemptLimit {
+	return PasswordAttemptLimit{
+		MaxFailures: 10,
+		BaseDelay:   time.Second,
+		MaxDelay:    5 * time.Minute,
+	}
+}
+
+// Validate checks the limit for consistency.
+func (l PasswordAttemptLimit) Validate() error {
+	if l.MaxFailures < 0 {
+		return fmt.Errorf("max failures must not be negative, got %d", l.MaxFailures)
+	}
+	if l.BaseDelay < 0 || l.MaxDelay < 0 {
+		return fmt.Errorf("delays must not be negative")
+	}
+	if l.MaxDelay != 0 && l.MaxDelay < l.BaseDelay {
+		return fmt.Errorf("max delay %s is below base delay %s", l.MaxDelay, l.BaseDelay)
+	}
+	return nil
+}
+
+// WithPasswordAttemptLimit throttles failed password attempts as described
+// by limit. Password attempts are unlimited by default. A limit that fails
+// PasswordAttemptLimit.Validate is replaced by DefaultPasswordAttemptLimit.
+func WithPasswordAttemptLimit(limit PasswordAttemptLimit) KeyringOption {
+	return func(k *defaultKeyring) {
+		if limit.Validate() != nil {
+			// SECURITY: fail closed rather than silently disabling the limit
+			limit = DefaultPasswordAttemptLimit()
+		}
+		k.attempts = newPasswordAttempts(limit)
+	}
+}
+
+// ResetPasswordAttempts clears a key's failed password attempts, lifting any
+// backoff or lockout. Resetting a key without failed attempts is a no-op.
+func (kr *defaultKeyring) ResetPasswordAttempts(name string) error {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	if err := kr.checkClosed(); err != nil {
+		return err
+	}
+	kr.attempts.clear(name)
+	return nil
+}
+
+// passwordAttempts tracks consecutive failed password attempts per key.
+// A nil *passwordAttempts allows every attempt.
+// Thread-safe.
+type passwordAttempts struct {
+	limit PasswordAttemptLimit
+
+	mu   sync.Mutex
+	keys map[string]*attemptState
+}
+
+// attemptState is one key's password attempt history.
+type attemptState struct {
+	failures int
+	retryAt  time.Time
+	inFlight bool
+}
+
+func newPasswordAttempts(limit PasswordAttemptLimit) *passwordAttempts {
+	return &passwordAttempts{limit: limit, keys: make(map[string]*attemptState)}
+}
+
+// begin admits a password attempt on name at now, or returns
+// ErrKeyLockedOut or ErrPasswordRateLimited. The caller must call finish
+// with the attempt's result.
+//
+// INVARIANT: At most one attempt per key is in progress, so concurrent
+// callers cannot all slip in before a failure is recorded.
+func (t *passwordAttempts) begin(name string, now time.Time) (finish func(err error, now time.Time), err error) {
+	if t == nil {
+		return func(error, time.Time) {}, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st, ok := t.keys[name]
+	if !ok {
+		st = &attemptState{}
+		t.keys[name] = st
+	}
+	if t.limit.MaxFailures > 0 && st.failures >= t.limit.MaxFailures {
+		return nil, fmt.Errorf("%w: %s (%d failures)", ErrKeyLockedOut, name, st.failures)
+	}
+	if st.inFlight {
+		return nil, fmt.Errorf("%w: %s: another attempt is in progress", ErrPasswordRateLimited, name)
+	}
+	if now.Before(st.retryAt) {
+		return nil, fmt.Errorf("%w: %s: retry in %s", ErrPasswordRateLimited, name, st.retryAt.Sub(now).Round(time.Millisecond))
+	}
+
+	st.inFlight = true
+	return func(err error, now time.Time) { t.finish(name, st, err, now) }, nil
+}
+
+// finish records the result of an attempt admitted by begin. Only
+// ErrInvalidPassword counts as a failure; success clears the history.
+func (t *passwordAttempts) finish(name string, st *attemptState, err error, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	st.inFlight = false
+	switch {
+	case err == nil:
+		st.failures = 0
+		st.retryAt = time.Time{}
+	case errors.Is(err, ErrInvalidPassword):
+		st.failures++
+		st.retryAt = now.Add(t.backoff(st.failures))
+	}
+
+	// Drop idle state so the map does not grow with every key name tried;
+	// skip if clear replaced it mid-attempt
+	if st.failures == 0 && t.keys[name] == st {
+		delete(t.keys, name)
+	}
+}
+
+// backoff returns the delay after the given number of consecutive failures.
+func (t *passwordAttempts) backoff(failures int) time.Duration {
+	delay := t.limit.BaseDelay
+	for i := 1; i < failures && delay > 0; i++ {
+		if t.limit.MaxDelay != 0 && delay >= t.limit.MaxDelay {
+			break
+		}
+		if delay > time.Duration(1<<62) {
+			break // doubling would overflow
+		}
+		delay *= 2
+	}
+	if t.limit.MaxDelay != 0 && delay > t.limit.MaxDelay {
+		delay = t.limit.MaxDelay
+	}
+	return delay
+}
+
+// clear forgets a key's attempt history.
+func (t *passwordAttempts) clear(name string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.keys, name)
+}
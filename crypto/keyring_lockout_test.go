@@ -0,0 +1,158 @@
+package crypto
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// newLockoutTestKeyring returns a keyring holding an encrypted key "locked"
+// with password "correct", throttled by limit, and a clock to advance.
+func newLockoutTestKeyring(t *testing.T, limit PasswordAttemptLimit) (Keyring, *time.Time) {
+	t.Helper()
+	store := NewMemoryStore()
+
+	signer, err := NewKeyring(store).NewKey("locked", AlgorithmEd25519)
+	if err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+	raw := signer.(*BasicSigner).privateKey.Bytes()
+	entry, err := createEncryptedKeyEntry("locked", raw, "correct")
+	if err != nil {
+		t.Fatalf("createEncryptedKeyEntry failed: %v", err)
+	}
+	entry.Algorithm = AlgorithmEd25519
+	if err := store.Put(entry, true); err != nil {
+		t.Fatalf("store.Put failed: %v", err)
+	}
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	kr := NewKeyring(store,
+		WithPasswordAttemptLimit(limit),
+		WithKeyringClock(func() time.Time { return now }),
+		WithKDFParams(KDFParams{}), // keep the legacy entry as-is
+	)
+	return kr, &now
+}
+
+func TestPasswordAttemptLimit_Backoff(t *testing.T) {
+	kr, now := newLockoutTestKeyring(t, PasswordAttemptLimit{BaseDelay: time.Second, MaxDelay: 3 * time.Second})
+
+	if _, err := kr.ExportKey("locked", "wrong"); !errors.Is(err, ErrInvalidPassword) {
+		t.Fatalf("expected ErrInvalidPassword, got %v", err)
+	}
+	// Even the right password is refused during the backoff
+	if _, err := kr.ExportKey("locked", "correct"); !errors.Is(err, ErrPasswordRateLimited) {
+		t.Fatalf("expected ErrPasswordRateLimited, got %v", err)
+	}
+
+	*now = now.Add(time.Second)
+	if _, err := kr.ExportKey("locked", "wrong"); !errors.Is(err, ErrInvalidPassword) {
+		t.Fatalf("expected ErrInvalidPassword, got %v", err)
+	}
+	// The second failure doubles the delay
+	*now = now.Add(time.Second)
+	if _, err := kr.ExportKey("locked", "correct"); !errors.Is(err, ErrPasswordRateLimited) {
+		t.Fatalf("expected ErrPasswordRateLimited after doubled backoff, got %v", err)
+	}
+
+	*now = now.Add(time.Second)
+	key, err := kr.ExportKey("locked", "correct")
+	if err != nil {
+		t.Fatalf("ExportKey failed after backoff: %v", err)
+	}
+	Zeroize(key)
+
+	// Success clears the history: a new failure starts from BaseDelay
+	if _, err := kr.ExportKey("locked", "wrong"); !errors.Is(err, ErrInvalidPassword) {
+		t.Fatalf("expected ErrInvalidPassword, got %v", err)
+	}
+	*now = now.Add(time.Second)
+	if _, err := kr.ExportKey("locked", "wrong"); !errors.Is(err, ErrInvalidPassword) {
+		t.Fatalf("expected backoff reset after success, got %v", err)
+	}
+}
+
+func TestPasswordAttemptLimit_LockoutAndReset(t *testing.T) {
+	kr, _ := newLockoutTestKeyring(t, PasswordAttemptLimit{MaxFailures: 3})
+
+	for i := 0; i < 3; i++ {
+		if _, err := kr.ExportKey("locked", "wrong"); !errors.Is(err, ErrInvalidPassword) {
+			t.Fatalf("attempt %d: expected ErrInvalidPassword, got %v", i, err)
+		}
+	}
+	if _, err := kr.ExportKey("locked", "correct"); !errors.Is(err, ErrKeyLockedOut) {
+		t.Fatalf("expected ErrKeyLockedOut, got %v", err)
+	}
+	if _, err := kr.ExportArmored("locked", "correct"); !errors.Is(err, ErrKeyLockedOut) {
+		t.Fatalf("expected ExportArmored to be locked out, got %v", err)
+	}
+
+	if err := kr.ResetPasswordAttempts("locked"); err != nil {
+		t.Fatalf("ResetPasswordAttempts failed: %v", err)
+	}
+	key, err := kr.ExportKey("locked", "correct")
+	if err != nil {
+		t.Fatalf("ExportKey failed after reset: %v", err)
+	}
+	Zeroize(key)
+
+	if err := kr.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := kr.ResetPasswordAttempts("locked"); !errors.Is(err, ErrKeyringClosed) {
+		t.Fatalf("expected ErrKeyringClosed, got %v", err)
+	}
+}
+
+func TestPasswordAttemptLimit_Unlimited(t *testing.T) {
+	kr, _ := newLockoutTestKeyring(t, PasswordAttemptLimit{})
+
+	for i := 0; i < 3; i++ {
+		if _, err := kr.ExportKey("locked", "wrong"); !errors.Is(err, ErrInvalidPassword) {
+			t.Fatalf("attempt %d: expected ErrInvalidPassword, got %v", i, err)
+		}
+	}
+	key, err := kr.ExportKey("locked", "correct")
+	if err != nil {
+		t.Fatalf("ExportKey failed: %v", err)
+	}
+	Zeroize(key)
+}
+
+func TestPasswordAttemptLimit_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   PasswordAttemptLimit
+		wantErr bool
+	}{
+		{"zero", PasswordAttemptLimit{}, false},
+		{"default", DefaultPasswordAttemptLimit(), false},
+		{"negative failures", PasswordAttemptLimit{MaxFailures: -1}, true},
+		{"negative delay", PasswordAttemptLimit{BaseDelay: -time.Second}, true},
+		{"max below base", PasswordAttemptLimit{BaseDelay: time.Minute, MaxDelay: time.Second}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.limit.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPasswordAttempts_BackoffCap(t *testing.T) {
+	attempts := newPasswordAttempts(PasswordAttemptLimit{BaseDelay: time.Second, MaxDelay: time.Minute})
+	for failures, want := range map[int]time.Duration{
+		1: time.Second, 2: 2 * time.Second, 4: 8 * time.Second, 7: time.Minute, 1000: time.Minute,
+	} {
+		if got := attempts.backoff(failures); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", failures, got, want)
+		}
+	}
+
+	uncapped := newPasswordAttempts(PasswordAttemptLimit{BaseDelay: time.Second})
+	if got := uncapped.backoff(1000); got <= 0 {
+		t.Errorf("uncapped backoff overflowed: %s", got)
+	}
+}
@@ -0,0 +1,280 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Key metadata limits.
+//
+// SECURITY: Metadata is user-supplied and persisted with every key, so it is
+// bounded to keep entries small and safe to display.
+const (
+	// MaxKeyLabelLength is the maximum length of KeyMetadata.Label in bytes.
+	MaxKeyLabelLength = 128
+
+	// MaxKeyAccountLength is the maximum length of KeyMetadata.Account in bytes.
+	MaxKeyAccountLength = 64
+
+	// MaxKeyAlgorithmParams is the maximum number of KeyMetadata.AlgorithmParams entries.
+	MaxKeyAlgorithmParams = 16
+
+	// MaxKeyAlgorithmParamLength is the maximum length of an AlgorithmParams
+	// key or value in bytes.
+	MaxKeyAlgorithmParamLength = 256
+)
+
+// ErrInvalidKeyMetadata is returned when key metadata fails validation.
+var ErrInvalidKeyMetadata = errors.New("invalid key metadata")
+
+// KeyMetadata is descriptive, non-secret information about a keyring key,
+// intended for wallet UIs. It never affects signing.
+type KeyMetadata struct {
+	// CreatedAt is when the key was generated or imported by a Keyring.
+	// Set by the Keyring; zero for keys created before metadata existed.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	// Label is a human-readable name for display.
+	Label string `json:"label,omitempty"`
+
+	// Account is the on-chain account name the key is associated with.
+	Account string `json:"account,omitempty"`
+
+	// AlgorithmParams holds algorithm-specific details (e.g. a derivation
+	// path or hardware device identifier).
+	AlgorithmParams map[string]string `json:"algorithm_params,omitempty"`
+
+	// LastUsedAt is when the Keyring last signed with the key.
+	// Set by the Keyring.
+	LastUsedAt time.Time `json:"last_used_at,omitempty"`
+}
+
+// ValidateBasic checks the user-settable fields against the metadata limits.
+// Labels, accounts and parameters must be valid UTF-8 without control
+// characters so they can be displayed safely.
+func (m *KeyMetadata) ValidateBasic() error {
+	if len(m.Label) > MaxKeyLabelLength {
+		return fmt.Errorf("%w: label too long (max %d bytes)", ErrInvalidKeyMetadata, MaxKeyLabelLength)
+	}
+	if err := validateMetadataText("label", m.Label); err != nil {
+		return err
+	}
+	if len(m.Account) > MaxKeyAccountLength {
+		return fmt.Errorf("%w: account too long (max %d bytes)", ErrInvalidKeyMetadata, MaxKeyAccountLength)
+	}
+	if err := validateMetadataText("account", m.Account); err != nil {
+		return err
+	}
+	if len(m.AlgorithmParams) > MaxKeyAlgorithmParams {
+		return fmt.Errorf("%w: too many algorithm params (max %d)", ErrInvalidKeyMetadata, MaxKeyAlgorithmParams)
+	}
+	for k, v := range m.AlgorithmParams {
+		if k == "" {
+			return fmt.Errorf("%w: algorithm param name cannot be empty", ErrInvalidKeyMetadata)
+		}
+		if len(k) > MaxKeyAlgorithmParamLength || len(v) > MaxKeyAlgorithmParamLength {
+			return fmt.Errorf("%w: algorithm param %q too long (max %d bytes)",
+				ErrInvalidKeyMetadata, k, MaxKeyAlgorithmParamLength)
+		}
+		if err := validateMetadataText("algorithm param", k); err != nil {
+			return err
+		}
+		if err := validateMetadataText("algorithm param", v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateMetadataText rejects invalid UTF-8 and control characters.
+func validateMetadataText(field, s string) error {
+	if !utf8.ValidString(s) {
+		return fmt.Errorf("%w: %s is not valid UTF-8", ErrInvalidKeyMetadata, field)
+	}
+	for _, r := range s {
+		if r < 32 || r == 127 {
+			return fmt.Errorf("%w: %s contains control characters", ErrInvalidKeyMetadata, field)
+		}
+	}
+	return nil
+}
+
+// Clone returns a deep copy of the metadata.
+func (m *KeyMetadata) Clone() *KeyMetadata {
+	if m == nil {
+		return nil
+	}
+	clone := *m
+	if m.AlgorithmParams != nil {
+		clone.AlgorithmParams = make(map[string]string, len(m.AlgorithmParams))
+		for k, v := range m.AlgorithmParams {
+			clone.AlgorithmParams[k] = v
+		}
+	}
+	return &clone
+}
+
+// KeyInfo describes a keyring key without its private key material.
+type KeyInfo struct {
+	// Name is the key's keyring name.
+	Name string `json:"name"`
+
+	// Algorithm is the key's signing algorithm (empty for multisig keys).
+	Algorithm Algorithm `json:"algorithm,omitempty"`
+
+	// PublicKey is the public key bytes (nil for multisig keys).
+	PublicKey []byte `json:"public_key"`
+
+	// Encrypted indicates whether the key is stored encrypted.
+	Encrypted bool `json:"encrypted"`
+
+	// Metadata is the key's metadata (never nil).
+	Metadata *KeyMetadata `json:"metadata"`
+
+	// Multisig is the descriptor of a multisig key (nil for single keys).
+	Multisig *MultisigDescriptor `json:"multisig,omitempty"`
+}
+
+// usageTracker records when each key last signed.
+//
+// Signing is the keyring's hot path, so usage is tracked in memory and
+// merged into metadata on read rather than written to the store per
+// signature. It is persisted whenever the key's metadata is next written.
+type usageTracker struct {
+	mu       sync.Mutex
+	lastUsed map[string]time.Time
+}
+
+func newUsageTracker() *usageTracker {
+	return &usageTracker{lastUsed: make(map[string]time.Time)}
+}
+
+// record marks name as used at now.
+func (u *usageTracker) record(name string, now time.Time) {
+	u.mu.Lock()
+	u.lastUsed[name] = now
+	u.mu.Unlock()
+}
+
+// get returns when name was last used, or the zero time.
+func (u *usageTracker) get(name string) time.Time {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.lastUsed[name]
+}
+
+// clear forgets name.
+func (u *usageTracker) clear(name string) {
+	u.mu.Lock()
+	delete(u.lastUsed, name)
+	u.mu.Unlock()
+}
+
+// entryMetadata returns a copy of entry's metadata with the tracked
+// last-used time merged in. Never returns nil.
+func (kr *defaultKeyring) entryMetadata(entry *KeyEntry) *KeyMetadata {
+	meta := entry.Metadata.Clone()
+	if meta == nil {
+		meta = &KeyMetadata{}
+	}
+	if used := kr.usage.get(entry.Name); used.After(meta.LastUsedAt) {
+		meta.LastUsedAt = used
+	}
+	return meta
+}
+
+// SetMetadata replaces the user-settable metadata (Label, Account and
+// AlgorithmParams) of a key. CreatedAt and LastUsedAt are maintained by the
+// Keyring and ignored in meta. A nil meta clears the user-settable fields.
+//
+// Returns ErrKeyNotFound if the key doesn't exist.
+// Returns ErrInvalidKeyMetadata if meta fails ValidateBasic.
+// Complexity: O(store.Get) + O(store.Put).
+func (kr *defaultKeyring) SetMetadata(name string, meta *KeyMetadata) error {
+	if meta == nil {
+		meta = &KeyMetadata{}
+	}
+	if err := meta.ValidateBasic(); err != nil {
+		return err
+	}
+
+	// Serialize with SetPolicy and re-encryption, which also rewrite entries
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	if err := kr.checkClosed(); err != nil {
+		return err
+	}
+
+	entry, err := kr.store.Get(name)
+	if err != nil {
+		return err
+	}
+	defer Zeroize(entry.PrivateKey)
+
+	current := kr.entryMetadata(entry)
+	updated := meta.Clone()
+	updated.CreatedAt = current.CreatedAt
+	updated.LastUsedAt = current.LastUsedAt
+	entry.Metadata = updated
+
+	return kr.store.Put(entry, true)
+}
+
+// GetMetadata returns a copy of the key's metadata. Keys without stored
+// metadata return an empty KeyMetadata.
+//
+// Returns ErrKeyNotFound if the key doesn't exist.
+// Complexity: O(store.Get).
+func (kr *defaultKeyring) GetMetadata(name string) (*KeyMetadata, error) {
+	kr.mu.RLock()
+	if err := kr.checkClosed(); err != nil {
+		kr.mu.RUnlock()
+		return nil, err
+	}
+	kr.mu.RUnlock()
+
+	entry, err := kr.store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	defer Zeroize(entry.PrivateKey)
+	return kr.entryMetadata(entry), nil
+}
+
+// ListKeyInfo returns the public information and metadata of all keys,
+// sorted by name. Keys deleted while listing are omitted.
+// Complexity: O(n * store.Get) + O(n log n) where n is number of keys.
+func (kr *defaultKeyring) ListKeyInfo() ([]KeyInfo, error) {
+	names, err := kr.ListKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]KeyInfo, 0, len(names))
+	for _, name := range names {
+		entry, err := kr.store.Get(name)
+		if errors.Is(err, ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		Zeroize(entry.PrivateKey)
+
+		infos = append(infos, KeyInfo{
+			Name:      name,
+			Algorithm: entry.Algorithm,
+			PublicKey: entry.PublicKey,
+			Encrypted: entry.Encrypted,
+			Metadata:  kr.entryMetadata(entry),
+			Multisig:  entry.Multisig.Clone(),
+		})
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
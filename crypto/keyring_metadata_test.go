@@ -0,0 +1,304 @@
+package crypto
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newMetadataKeyring returns a keyring with a settable clock
+func newMetadataKeyring(t *testing.T) (Keyring, *time.Time) {
+	t.Helper()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	kr := NewKeyring(NewMemoryStore(), WithKeyringClock(func() time.Time { return now }))
+	return kr, &now
+}
+
+func TestKeyringMetadataCreatedAt(t *testing.T) {
+	kr, now := newMetadataKeyring(t)
+
+	if _, err := kr.NewKey("generated", AlgorithmEd25519); err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+	created := *now
+
+	*now = now.Add(time.Hour)
+	privKey, err := GeneratePrivateKey(AlgorithmEd25519)
+	if err != nil {
+		t.Fatalf("GeneratePrivateKey failed: %v", err)
+	}
+	if _, err := kr.ImportKey("imported", privKey.Bytes(), AlgorithmEd25519); err != nil {
+		t.Fatalf("ImportKey failed: %v", err)
+	}
+
+	meta, err := kr.GetMetadata("generated")
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if !meta.CreatedAt.Equal(created) {
+		t.Fatalf("expected CreatedAt %v, got %v", created, meta.CreatedAt)
+	}
+	if !meta.LastUsedAt.IsZero() {
+		t.Fatalf("expected zero LastUsedAt, got %v", meta.LastUsedAt)
+	}
+
+	meta, err = kr.GetMetadata("imported")
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if !meta.CreatedAt.Equal(*now) {
+		t.Fatalf("expected CreatedAt %v, got %v", *now, meta.CreatedAt)
+	}
+
+	if _, err := kr.GetMetadata("missing"); err != ErrKeyNotFound {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestKeyringSetMetadata(t *testing.T) {
+	kr, now := newMetadataKeyring(t)
+	if _, err := kr.NewKey("k", AlgorithmEd25519); err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+	created := *now
+
+	*now = now.Add(time.Hour)
+	err := kr.SetMetadata("k", &KeyMetadata{
+		Label:           "Validator hot key",
+		Account:         "alice",
+		AlgorithmParams: map[string]string{"hd_path": "m/44'/118'/0'/0/0"},
+		// Keyring-managed fields are ignored
+		CreatedAt:  now.Add(48 * time.Hour),
+		LastUsedAt: now.Add(48 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("SetMetadata failed: %v", err)
+	}
+
+	meta, err := kr.GetMetadata("k")
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if meta.Label != "Validator hot key" || meta.Account != "alice" || meta.AlgorithmParams["hd_path"] != "m/44'/118'/0'/0/0" {
+		t.Fatalf("metadata not stored: %+v", meta)
+	}
+	if !meta.CreatedAt.Equal(created) || !meta.LastUsedAt.IsZero() {
+		t.Fatalf("keyring-managed times overwritten: %+v", meta)
+	}
+
+	// The returned metadata is a copy
+	meta.AlgorithmParams["hd_path"] = "changed"
+	again, err := kr.GetMetadata("k")
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if again.AlgorithmParams["hd_path"] != "m/44'/118'/0'/0/0" {
+		t.Fatal("GetMetadata returned shared state")
+	}
+
+	// nil clears the user-settable fields but keeps CreatedAt
+	if err := kr.SetMetadata("k", nil); err != nil {
+		t.Fatalf("SetMetadata(nil) failed: %v", err)
+	}
+	cleared, err := kr.GetMetadata("k")
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if cleared.Label != "" || cleared.Account != "" || cleared.AlgorithmParams != nil {
+		t.Fatalf("metadata not cleared: %+v", cleared)
+	}
+	if !cleared.CreatedAt.Equal(created) {
+		t.Fatalf("CreatedAt lost: %v", cleared.CreatedAt)
+	}
+
+	if err := kr.SetMetadata("missing", &KeyMetadata{Label: "x"}); err != ErrKeyNotFound {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+}
+
+func TestKeyMetadataValidateBasic(t *testing.T) {
+	tooManyParams := make(map[string]string)
+	for i := 0; i <= MaxKeyAlgorithmParams; i++ {
+		tooManyParams[string(rune('a'+i))] = "v"
+	}
+
+	tests := []struct {
+		name    string
+		meta    KeyMetadata
+		wantErr bool
+	}{
+		{"empty", KeyMetadata{}, false},
+		{"valid", KeyMetadata{Label: "Cold storage ❄", Account: "alice", AlgorithmParams: map[string]string{"curve": "p256"}}, false},
+		{"label too long", KeyMetadata{Label: strings.Repeat("a", MaxKeyLabelLength+1)}, true},
+		{"label control char", KeyMetadata{Label: "evil\x1b[31m"}, true},
+		{"label invalid utf8", KeyMetadata{Label: "\xff"}, true},
+		{"account too long", KeyMetadata{Account: strings.Repeat("a", MaxKeyAccountLength+1)}, true},
+		{"too many params", KeyMetadata{AlgorithmParams: tooManyParams}, true},
+		{"empty param name", KeyMetadata{AlgorithmParams: map[string]string{"": "v"}}, true},
+		{"param value too long", KeyMetadata{AlgorithmParams: map[string]string{"k": strings.Repeat("v", MaxKeyAlgorithmParamLength+1)}}, true},
+		{"param newline", KeyMetadata{AlgorithmParams: map[string]string{"k": "a\nb"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.meta.ValidateBasic()
+			if tt.wantErr && !errors.Is(err, ErrInvalidKeyMetadata) {
+				t.Fatalf("expected ErrInvalidKeyMetadata, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	kr, _ := newMetadataKeyring(t)
+	if _, err := kr.NewKey("k", AlgorithmEd25519); err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+	if err := kr.SetMetadata("k", &KeyMetadata{Label: "bad\x00"}); !errors.Is(err, ErrInvalidKeyMetadata) {
+		t.Fatalf("expected ErrInvalidKeyMetadata, got %v", err)
+	}
+}
+
+func TestKeyringLastUsedAt(t *testing.T) {
+	kr, now := newMetadataKeyring(t)
+	if _, err := kr.NewKey("k", AlgorithmEd25519); err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+
+	*now = now.Add(time.Minute)
+	if _, err := kr.Sign("k", []byte("data")); err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	signedAt := *now
+
+	meta, err := kr.GetMetadata("k")
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if !meta.LastUsedAt.Equal(signedAt) {
+		t.Fatalf("expected LastUsedAt %v, got %v", signedAt, meta.LastUsedAt)
+	}
+
+	// SignSignDoc also counts as use
+	*now = now.Add(time.Minute)
+	if _, err := kr.SignSignDoc("k", newTestPolicyDoc(t, "punnet-1", "")); err != nil {
+		t.Fatalf("SignSignDoc failed: %v", err)
+	}
+	meta, err = kr.GetMetadata("k")
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if !meta.LastUsedAt.Equal(*now) {
+		t.Fatalf("expected LastUsedAt %v, got %v", *now, meta.LastUsedAt)
+	}
+
+	// Failed signing does not count
+	usedAt := *now
+	*now = now.Add(time.Minute)
+	if _, err := kr.Sign("k", make([]byte, MaxSignDataLength+1)); err != ErrDataTooLarge {
+		t.Fatalf("expected ErrDataTooLarge, got %v", err)
+	}
+	meta, err = kr.GetMetadata("k")
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if !meta.LastUsedAt.Equal(usedAt) {
+		t.Fatalf("failed Sign updated LastUsedAt to %v", meta.LastUsedAt)
+	}
+
+	// Deleting the key forgets its usage
+	if err := kr.DeleteKey("k"); err != nil {
+		t.Fatalf("DeleteKey failed: %v", err)
+	}
+	if _, err := kr.NewKey("k", AlgorithmEd25519); err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+	meta, err = kr.GetMetadata("k")
+	if err != nil {
+		t.Fatalf("GetMetadata failed: %v", err)
+	}
+	if !meta.LastUsedAt.IsZero() {
+		t.Fatalf("recreated key inherited LastUsedAt %v", meta.LastUsedAt)
+	}
+}
+
+func TestKeyringListKeyInfo(t *testing.T) {
+	store := NewMemoryStore()
+	kr := NewKeyring(store)
+
+	for _, name := range []string{"charlie", "alice", "bob"} {
+		if _, err := kr.NewKey(name, AlgorithmEd25519); err != nil {
+			t.Fatalf("NewKey(%s) failed: %v", name, err)
+		}
+	}
+	if err := kr.SetMetadata("bob", &KeyMetadata{Label: "Bob's key", Account: "bob"}); err != nil {
+		t.Fatalf("SetMetadata failed: %v", err)
+	}
+
+	// Entries from before metadata existed are listed with empty metadata
+	legacy := &KeyEntry{Name: "legacy", Algorithm: AlgorithmEd25519, PrivateKey: make([]byte, 64), PublicKey: make([]byte, 32)}
+	if err := store.Put(legacy, false); err != nil {
+		t.Fatalf("store.Put failed: %v", err)
+	}
+
+	infos, err := kr.ListKeyInfo()
+	if err != nil {
+		t.Fatalf("ListKeyInfo failed: %v", err)
+	}
+	wantNames := []string{"alice", "bob", "charlie", "legacy"}
+	if len(infos) != len(wantNames) {
+		t.Fatalf("expected %d keys, got %d", len(wantNames), len(infos))
+	}
+	for i, info := range infos {
+		if info.Name != wantNames[i] {
+			t.Fatalf("key %d: expected %s, got %s", i, wantNames[i], info.Name)
+		}
+		if info.Metadata == nil {
+			t.Fatalf("key %s: nil metadata", info.Name)
+		}
+		if info.Algorithm != AlgorithmEd25519 || len(info.PublicKey) != 32 {
+			t.Fatalf("key %s: unexpected algorithm or public key", info.Name)
+		}
+	}
+	if infos[1].Metadata.Label != "Bob's key" || infos[1].Metadata.Account != "bob" {
+		t.Fatalf("bob's metadata missing: %+v", infos[1].Metadata)
+	}
+	if infos[0].Metadata.CreatedAt.IsZero() {
+		t.Fatal("expected CreatedAt for generated key")
+	}
+	if !infos[3].Metadata.CreatedAt.IsZero() || infos[3].Metadata.Label != "" {
+		t.Fatalf("legacy key has unexpected metadata: %+v", infos[3].Metadata)
+	}
+
+	// KeyInfo carries no private key material
+	data, err := json.Marshal(infos)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if strings.Contains(string(data), "private") {
+		t.Fatalf("KeyInfo JSON mentions private key: %s", data)
+	}
+
+	if err := kr.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := kr.ListKeyInfo(); err != ErrKeyringClosed {
+		t.Fatalf("expected ErrKeyringClosed, got %v", err)
+	}
+}
+
+func TestKeyEntryMetadataClone(t *testing.T) {
+	entry := &KeyEntry{
+		Name:     "k",
+		Metadata: &KeyMetadata{Label: "l", AlgorithmParams: map[string]string{"a": "b"}},
+	}
+	clone := entry.Clone()
+	clone.Metadata.Label = "changed"
+	clone.Metadata.AlgorithmParams["a"] = "changed"
+	if entry.Metadata.Label != "l" || entry.Metadata.AlgorithmParams["a"] != "b" {
+		t.Fatal("clone shares metadata with original")
+	}
+}
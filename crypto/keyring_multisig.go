@@ -0,0 +1,182 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxMultisigMembers bounds the number of members in a MultisigDescriptor.
+const MaxMultisigMembers = 64
+
+// Multisig errors.
+var (
+	// ErrInvalidMultisig is returned when a multisig descriptor is malformed.
+	ErrInvalidMultisig = errors.New("invalid multisig descriptor")
+
+	// ErrMultisigKey is returned when a private-key operation (signing,
+	// export, policies) is attempted on a multisig entry.
+	ErrMultisigKey = errors.New("key is a multisig descriptor")
+
+	// ErrNotMultisig is returned by GetMultisig for single-key entries.
+	ErrNotMultisig = errors.New("key is not a multisig descriptor")
+)
+
+// MultisigMember is one weighted public key of a multisig.
+type MultisigMember struct {
+	// Name is an optional display name (e.g. the member's keyring key name).
+	Name string `json:"name,omitempty"`
+
+	// Algorithm is the member key's signing algorithm.
+	Algorithm Algorithm `json:"algorithm"`
+
+	// PubKey is the member's public key bytes.
+	PubKey []byte `json:"pub_key"`
+
+	// Weight is the member's contribution toward Threshold.
+	Weight uint64 `json:"weight"`
+}
+
+// MultisigDescriptor describes a threshold multisig: a set of weighted
+// member public keys and the weight required to authorize.
+//
+// A descriptor holds no private key material. It is stored in the keyring
+// so the multisig can be referenced by name when building an account's
+// authority (types.NewMultisigAuthority) and when assembling members'
+// partial signatures (types.NewMultisigAuthorization).
+type MultisigDescriptor struct {
+	// Threshold is the minimum total member weight required.
+	Threshold uint64 `json:"threshold"`
+
+	// Members are the weighted member keys, in a stable display order.
+	Members []MultisigMember `json:"members"`
+}
+
+// ValidateBasic checks the descriptor for configuration errors.
+//
+// INVARIANT: A valid descriptor has 1..MaxMultisigMembers members with
+// distinct (algorithm, public key) pairs, positive weights, and a threshold
+// in [1, total weight].
+func (d *MultisigDescriptor) ValidateBasic() error {
+	if d == nil {
+		return fmt.Errorf("%w: descriptor cannot be nil", ErrInvalidMultisig)
+	}
+	if d.Threshold == 0 {
+		return fmt.Errorf("%w: threshold cannot be zero", ErrInvalidMultisig)
+	}
+	if len(d.Members) == 0 {
+		return fmt.Errorf("%w: no members", ErrInvalidMultisig)
+	}
+	if len(d.Members) > MaxMultisigMembers {
+		return fmt.Errorf("%w: too many members (max %d)", ErrInvalidMultisig, MaxMultisigMembers)
+	}
+
+	var total uint64
+	for i, m := range d.Members {
+		if !m.Algorithm.IsValid() {
+			return fmt.Errorf("%w: member %d: unknown algorithm %q", ErrInvalidMultisig, i, m.Algorithm)
+		}
+		if len(m.PubKey) != m.Algorithm.PublicKeySize() {
+			return fmt.Errorf("%w: member %d: %s key must be %d bytes, got %d",
+				ErrInvalidMultisig, i, m.Algorithm, m.Algorithm.PublicKeySize(), len(m.PubKey))
+		}
+		if m.Weight == 0 {
+			return fmt.Errorf("%w: member %d: weight cannot be zero", ErrInvalidMultisig, i)
+		}
+		if len(m.Name) > MaxKeyNameLength {
+			return fmt.Errorf("%w: member %d: name too long", ErrInvalidMultisig, i)
+		}
+		if j := d.MemberIndex(m.Algorithm, m.PubKey); j != i {
+			return fmt.Errorf("%w: members %d and %d have the same key", ErrInvalidMultisig, j, i)
+		}
+		if total > ^uint64(0)-m.Weight {
+			return fmt.Errorf("%w: total weight overflow", ErrInvalidMultisig)
+		}
+		total += m.Weight
+	}
+
+	if total < d.Threshold {
+		return fmt.Errorf("%w: threshold %d exceeds total weight %d", ErrInvalidMultisig, d.Threshold, total)
+	}
+	return nil
+}
+
+// MemberIndex returns the index of the member with the given key, or -1.
+// Complexity: O(n) where n is the number of members.
+func (d *MultisigDescriptor) MemberIndex(algo Algorithm, pubKey []byte) int {
+	for i, m := range d.Members {
+		if PublicKeyBytesEqual(m.Algorithm, m.PubKey, algo, pubKey) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Clone returns a deep copy of the descriptor.
+func (d *MultisigDescriptor) Clone() *MultisigDescriptor {
+	if d == nil {
+		return nil
+	}
+	clone := &MultisigDescriptor{
+		Threshold: d.Threshold,
+		Members:   make([]MultisigMember, len(d.Members)),
+	}
+	for i, m := range d.Members {
+		clone.Members[i] = m
+		clone.Members[i].PubKey = append([]byte(nil), m.PubKey...)
+	}
+	return clone
+}
+
+// AddMultisig stores a multisig descriptor under name.
+//
+// Returns ErrKeyExists if a key with this name already exists.
+// Returns ErrInvalidMultisig if desc fails ValidateBasic.
+// Complexity: O(n) for validation + O(store.Put).
+func (kr *defaultKeyring) AddMultisig(name string, desc *MultisigDescriptor) error {
+	kr.mu.RLock()
+	if err := kr.checkClosed(); err != nil {
+		kr.mu.RUnlock()
+		return err
+	}
+	kr.mu.RUnlock()
+
+	if err := validateKeyNameSimple(name); err != nil {
+		return err
+	}
+	if err := desc.ValidateBasic(); err != nil {
+		return err
+	}
+
+	entry := &KeyEntry{
+		Name:     name,
+		Multisig: desc.Clone(),
+		Metadata: &KeyMetadata{CreatedAt: kr.now()},
+	}
+	// Put with overwrite=false is the linearization point for creation
+	return kr.store.Put(entry, false)
+}
+
+// GetMultisig returns a copy of the multisig descriptor stored under name.
+//
+// Returns ErrKeyNotFound if the key doesn't exist.
+// Returns ErrNotMultisig if the key is a single signing key.
+// Complexity: O(store.Get).
+func (kr *defaultKeyring) GetMultisig(name string) (*MultisigDescriptor, error) {
+	kr.mu.RLock()
+	if err := kr.checkClosed(); err != nil {
+		kr.mu.RUnlock()
+		return nil, err
+	}
+	kr.mu.RUnlock()
+
+	entry, err := kr.store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	defer Zeroize(entry.PrivateKey)
+
+	if entry.Multisig == nil {
+		return nil, ErrNotMultisig
+	}
+	return entry.Multisig.Clone(), nil
+}
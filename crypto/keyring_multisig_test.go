@@ -0,0 +1,173 @@
+package crypto
+
+import (
+	"errors"
+	"testing"
+)
+
+// newTestMultisig returns a 2-of-3 descriptor over fresh Ed25519 keys
+func newTestMultisig(t *testing.T) *MultisigDescriptor {
+	t.Helper()
+	desc := &MultisigDescriptor{Threshold: 2}
+	for _, name := range []string{"alice", "bob", "carol"} {
+		priv, err := GeneratePrivateKey(AlgorithmEd25519)
+		if err != nil {
+			t.Fatalf("GeneratePrivateKey failed: %v", err)
+		}
+		desc.Members = append(desc.Members, MultisigMember{
+			Name:      name,
+			Algorithm: AlgorithmEd25519,
+			PubKey:    priv.PublicKey().Bytes(),
+			Weight:    1,
+		})
+	}
+	return desc
+}
+
+func TestMultisigDescriptorValidateBasic(t *testing.T) {
+	valid := newTestMultisig(t)
+	if err := valid.ValidateBasic(); err != nil {
+		t.Fatalf("valid descriptor rejected: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(d *MultisigDescriptor)
+	}{
+		{"zero threshold", func(d *MultisigDescriptor) { d.Threshold = 0 }},
+		{"threshold above total weight", func(d *MultisigDescriptor) { d.Threshold = 4 }},
+		{"no members", func(d *MultisigDescriptor) { d.Members = nil }},
+		{"unknown algorithm", func(d *MultisigDescriptor) { d.Members[0].Algorithm = "rsa" }},
+		{"wrong key size", func(d *MultisigDescriptor) { d.Members[0].PubKey = d.Members[0].PubKey[:31] }},
+		{"zero weight", func(d *MultisigDescriptor) { d.Members[1].Weight = 0 }},
+		{"duplicate member", func(d *MultisigDescriptor) { d.Members[2].PubKey = d.Members[0].PubKey }},
+		{"weight overflow", func(d *MultisigDescriptor) {
+			d.Members[0].Weight = ^uint64(0)
+			d.Members[1].Weight = 1
+		}},
+		{"too many members", func(d *MultisigDescriptor) {
+			for len(d.Members) <= MaxMultisigMembers {
+				pub := make([]byte, 32)
+				pub[0], pub[1] = byte(len(d.Members)), 0xFF
+				d.Members = append(d.Members, MultisigMember{Algorithm: AlgorithmEd25519, PubKey: pub, Weight: 1})
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := valid.Clone()
+			tt.mutate(d)
+			if err := d.ValidateBasic(); !errors.Is(err, ErrInvalidMultisig) {
+				t.Fatalf("expected ErrInvalidMultisig, got %v", err)
+			}
+		})
+	}
+
+	var nilDesc *MultisigDescriptor
+	if err := nilDesc.ValidateBasic(); !errors.Is(err, ErrInvalidMultisig) {
+		t.Fatalf("expected ErrInvalidMultisig for nil descriptor, got %v", err)
+	}
+}
+
+func TestMultisigDescriptorClone(t *testing.T) {
+	desc := newTestMultisig(t)
+	clone := desc.Clone()
+	clone.Members[0].PubKey[0] ^= 0xFF
+	clone.Members[1].Weight = 7
+	if desc.Members[0].PubKey[0] == clone.Members[0].PubKey[0] || desc.Members[1].Weight != 1 {
+		t.Fatal("clone shares state with original")
+	}
+	if desc.MemberIndex(AlgorithmEd25519, desc.Members[2].PubKey) != 2 {
+		t.Fatal("MemberIndex did not find member")
+	}
+	if desc.MemberIndex(AlgorithmSecp256r1, desc.Members[2].PubKey) != -1 {
+		t.Fatal("MemberIndex matched a different algorithm")
+	}
+}
+
+func TestKeyringMultisig(t *testing.T) {
+	kr := NewKeyring(NewMemoryStore())
+	desc := newTestMultisig(t)
+
+	if err := kr.AddMultisig("treasury", desc); err != nil {
+		t.Fatalf("AddMultisig failed: %v", err)
+	}
+	if err := kr.AddMultisig("treasury", desc); err != ErrKeyExists {
+		t.Fatalf("expected ErrKeyExists, got %v", err)
+	}
+	if err := kr.AddMultisig("bad", &MultisigDescriptor{Threshold: 1}); !errors.Is(err, ErrInvalidMultisig) {
+		t.Fatalf("expected ErrInvalidMultisig, got %v", err)
+	}
+	if err := kr.AddMultisig("a/b", desc); !errors.Is(err, ErrInvalidKeyName) {
+		t.Fatalf("expected ErrInvalidKeyName, got %v", err)
+	}
+
+	// Mutating the caller's descriptor does not affect the stored one
+	desc.Threshold = 3
+
+	got, err := kr.GetMultisig("treasury")
+	if err != nil {
+		t.Fatalf("GetMultisig failed: %v", err)
+	}
+	if got.Threshold != 2 || len(got.Members) != 3 || got.Members[1].Name != "bob" {
+		t.Fatalf("unexpected descriptor: %+v", got)
+	}
+
+	if _, err := kr.NewKey("single", AlgorithmEd25519); err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+	if _, err := kr.GetMultisig("single"); err != ErrNotMultisig {
+		t.Fatalf("expected ErrNotMultisig, got %v", err)
+	}
+	if _, err := kr.GetMultisig("missing"); err != ErrKeyNotFound {
+		t.Fatalf("expected ErrKeyNotFound, got %v", err)
+	}
+
+	infos, err := kr.ListKeyInfo()
+	if err != nil {
+		t.Fatalf("ListKeyInfo failed: %v", err)
+	}
+	if len(infos) != 2 || infos[1].Name != "treasury" || infos[1].Multisig == nil || infos[0].Multisig != nil {
+		t.Fatalf("unexpected key list: %+v", infos)
+	}
+	if infos[1].Metadata.CreatedAt.IsZero() {
+		t.Fatal("multisig entry has no CreatedAt")
+	}
+	if err := kr.SetMetadata("treasury", &KeyMetadata{Label: "DAO treasury"}); err != nil {
+		t.Fatalf("SetMetadata failed: %v", err)
+	}
+}
+
+func TestKeyringMultisigRejectsKeyOperations(t *testing.T) {
+	kr := NewKeyring(NewMemoryStore())
+	if err := kr.AddMultisig("ms", newTestMultisig(t)); err != nil {
+		t.Fatalf("AddMultisig failed: %v", err)
+	}
+
+	if _, err := kr.GetKey("ms"); err != ErrMultisigKey {
+		t.Fatalf("GetKey: expected ErrMultisigKey, got %v", err)
+	}
+	if _, err := kr.Sign("ms", []byte("data")); err != ErrMultisigKey {
+		t.Fatalf("Sign: expected ErrMultisigKey, got %v", err)
+	}
+	if _, err := kr.SignSignDoc("ms", newTestPolicyDoc(t, "punnet-1", "")); err != ErrMultisigKey {
+		t.Fatalf("SignSignDoc: expected ErrMultisigKey, got %v", err)
+	}
+	if _, err := kr.ExportKey("ms", ""); err != ErrMultisigKey {
+		t.Fatalf("ExportKey: expected ErrMultisigKey, got %v", err)
+	}
+	if _, err := kr.ExportArmored("ms", "passphrase"); err != ErrMultisigKey {
+		t.Fatalf("ExportArmored: expected ErrMultisigKey, got %v", err)
+	}
+	if err := kr.SetPolicy("ms", &SigningPolicy{AllowedChainIDs: []string{"punnet-1"}}); err != ErrMultisigKey {
+		t.Fatalf("SetPolicy: expected ErrMultisigKey, got %v", err)
+	}
+
+	if err := kr.DeleteKey("ms"); err != nil {
+		t.Fatalf("DeleteKey failed: %v", err)
+	}
+	if _, err := kr.GetMultisig("ms"); err != ErrKeyNotFound {
+		t.Fatalf("expected ErrKeyNotFound after delete, got %v", err)
+	}
+}
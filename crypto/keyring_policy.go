@@ -0,0 +1,571 @@
+package crypto
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrPolicyViolation is matched (via errors.Is) by every PolicyViolationError.
+var ErrPolicyViolation = errors.New("signing policy violation")
+
+// Policy rules reported in PolicyViolationError.Rule.
+const (
+	PolicyRuleChainID     = "chain_id"
+	PolicyRuleMessageType = "message_type"
+	PolicyRuleMaxFee      = "max_fee"
+	PolicyRuleDailySpend  = "daily_spend"
+	PolicyRuleRawSign     = "raw_sign"
+	PolicyRuleMalformed   = "malformed_sign_doc"
+)
+
+// PolicyViolationError reports why a Keyring refused to sign.
+type PolicyViolationError struct {
+	// Key is the name of the key whose policy rejected the request.
+	Key string
+
+	// Rule is the PolicyRule* constant that was violated.
+	Rule string
+
+	// Reason is a human-readable explanation.
+	Reason string
+}
+
+// Error implements error.
+func (e *PolicyViolationError) Error() string {
+	return fmt.Sprintf("%s: key %s: %s: %s", ErrPolicyViolation, e.Key, e.Rule, e.Reason)
+}
+
+// Is reports whether target is ErrPolicyViolation.
+func (e *PolicyViolationError) Is(target error) bool {
+	return target == ErrPolicyViolation
+}
+
+// PolicyCoin is a denomination and amount used in policy limits.
+type PolicyCoin struct {
+	Denom  string `json:"denom"`
+	Amount uint64 `json:"amount"`
+}
+
+// SigningPolicy restricts what a Keyring key may sign.
+//
+// Policies are stored in the key's KeyEntry and evaluated against SignDoc
+// content by Keyring.SignSignDoc. An empty field places no restriction.
+//
+// SECURITY: A key with a policy can only sign SignDocs. Keyring.Sign and the
+// Sign method of Signers returned by GetKey refuse raw data, because the
+// policy cannot be evaluated against opaque bytes. ExportKey is unaffected:
+// policies constrain signing, not custody.
+type SigningPolicy struct {
+	// AllowedChainIDs lists chain IDs the key may sign for.
+	AllowedChainIDs []string `json:"allowed_chain_ids,omitempty"`
+
+	// AllowedMessageTypes lists message types a SignDoc may contain.
+	AllowedMessageTypes []string `json:"allowed_message_types,omitempty"`
+
+	// MaxFee caps the fee of a single SignDoc per denomination. Fee
+	// denominations not listed are rejected.
+	MaxFee []PolicyCoin `json:"max_fee,omitempty"`
+
+	// DailySpendLimit caps the total spend (fees plus message amounts, see
+	// SpendExtractor) signed per UTC day per denomination. Spent
+	// denominations not listed are rejected.
+	//
+	// Spend is tracked in memory by the Keyring and resets when the process
+	// restarts.
+	DailySpendLimit []PolicyCoin `json:"daily_spend_limit,omitempty"`
+}
+
+// ValidateBasic checks the policy for configuration errors.
+func (p *SigningPolicy) ValidateBasic() error {
+	for _, id := range p.AllowedChainIDs {
+		if id == "" {
+			return fmt.Errorf("allowed chain ID cannot be empty")
+		}
+	}
+	for _, t := range p.AllowedMessageTypes {
+		if t == "" {
+			return fmt.Errorf("allowed message type cannot be empty")
+		}
+	}
+	if err := validatePolicyCoins("max fee", p.MaxFee); err != nil {
+		return err
+	}
+	return validatePolicyCoins("daily spend limit", p.DailySpendLimit)
+}
+
+func validatePolicyCoins(field string, coins []PolicyCoin) error {
+	seen := make(map[string]bool, len(coins))
+	for _, c := range coins {
+		if c.Denom == "" {
+			return fmt.Errorf("%s: denom cannot be empty", field)
+		}
+		if seen[c.Denom] {
+			return fmt.Errorf("%s: duplicate denom %s", field, c.Denom)
+		}
+		seen[c.Denom] = true
+	}
+	return nil
+}
+
+// Clone returns a deep copy of the policy.
+func (p *SigningPolicy) Clone() *SigningPolicy {
+	if p == nil {
+		return nil
+	}
+	return &SigningPolicy{
+		AllowedChainIDs:     append([]string(nil), p.AllowedChainIDs...),
+		AllowedMessageTypes: append([]string(nil), p.AllowedMessageTypes...),
+		MaxFee:              append([]PolicyCoin(nil), p.MaxFee...),
+		DailySpendLimit:     append([]PolicyCoin(nil), p.DailySpendLimit...),
+	}
+}
+
+// PolicySignDoc is a sign document the Keyring can evaluate a policy against.
+// Implemented by *types.SignDoc.
+//
+// ToJSON must return the canonical SignDoc JSON and GetSignBytes the bytes
+// derived from it, so the policy is evaluated on exactly what is signed.
+type PolicySignDoc interface {
+	SignBytesProvider
+	ToJSON() ([]byte, error)
+}
+
+// SpendExtractor returns the amounts a message spends from the signing
+// account. Fees are accounted separately and must not be included.
+type SpendExtractor func(msgType string, data json.RawMessage) ([]PolicyCoin, error)
+
+// WithSpendExtractor overrides how message spend is computed for
+// DailySpendLimit. The default is DefaultSpendExtractor.
+func WithSpendExtractor(extractor SpendExtractor) KeyringOption {
+	return func(k *defaultKeyring) {
+		k.spendExtractor = extractor
+	}
+}
+
+// WithKeyringClock overrides the time source used for daily spend windows
+// and password attempt backoff. Intended for tests.
+func WithKeyringClock(now func() time.Time) KeyringOption {
+	return func(k *defaultKeyring) {
+		k.now = now
+	}
+}
+
+// DefaultSpendExtractor reads top-level "amount" and "coins" fields holding
+// a coin ({"denom","amount"}) or a list of coins. Amounts may be JSON
+// numbers or decimal strings. Messages without these fields spend nothing.
+//
+// SECURITY: Message types that move funds through other fields (e.g. nested
+// inputs) are not counted. Combine DailySpendLimit with AllowedMessageTypes,
+// or install a SpendExtractor that understands every allowed type.
+func DefaultSpendExtractor(_ string, data json.RawMessage) ([]PolicyCoin, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		// Non-object message data carries no recognizable amounts.
+		return nil, nil
+	}
+
+	var spent []PolicyCoin
+	for _, field := range []string{"amount", "coins"} {
+		raw, ok := fields[field]
+		if !ok {
+			continue
+		}
+		coins, err := parseSpendCoins(raw)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field, err)
+		}
+		spent = append(spent, coins...)
+	}
+	return spent, nil
+}
+
+// parseSpendCoins parses a coin or list of coins.
+func parseSpendCoins(raw json.RawMessage) ([]PolicyCoin, error) {
+	type jsonCoin struct {
+		Denom  string      `json:"denom"`
+		Amount json.Number `json:"amount"`
+	}
+
+	var list []jsonCoin
+	if err := json.Unmarshal(raw, &list); err != nil {
+		var single jsonCoin
+		if err := json.Unmarshal(raw, &single); err != nil {
+			return nil, fmt.Errorf("not a coin or coin list")
+		}
+		list = []jsonCoin{single}
+	}
+
+	coins := make([]PolicyCoin, len(list))
+	for i, c := range list {
+		if c.Denom == "" {
+			return nil, fmt.Errorf("coin denom cannot be empty")
+		}
+		amount, err := strconv.ParseUint(c.Amount.String(), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount %q for %s", c.Amount, c.Denom)
+		}
+		coins[i] = PolicyCoin{Denom: c.Denom, Amount: amount}
+	}
+	return coins, nil
+}
+
+// policySignDocView is the subset of the SignDoc JSON a policy inspects.
+// Defined here because crypto cannot import types.
+type policySignDocView struct {
+	ChainID  string `json:"chain_id"`
+	Messages []struct {
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	} `json:"messages"`
+	Fee struct {
+		Amount []struct {
+			Denom  string `json:"denom"`
+			Amount string `json:"amount"`
+		} `json:"amount"`
+	} `json:"fee"`
+}
+
+// policyRequest is a SignDoc reduced to what a policy evaluates.
+type policyRequest struct {
+	chainID  string
+	msgTypes []string
+	fee      map[string]uint64
+	spend    map[string]uint64
+}
+
+// newPolicyRequest parses signDocJSON and computes fee and spend totals.
+func newPolicyRequest(signDocJSON []byte, extractor SpendExtractor) (*policyRequest, error) {
+	var view policySignDocView
+	if err := json.Unmarshal(signDocJSON, &view); err != nil {
+		return nil, fmt.Errorf("invalid sign doc JSON: %v", err)
+	}
+
+	req := &policyRequest{
+		chainID:  view.ChainID,
+		msgTypes: make([]string, len(view.Messages)),
+		fee:      make(map[string]uint64),
+		spend:    make(map[string]uint64),
+	}
+	for _, c := range view.Fee.Amount {
+		amount, err := strconv.ParseUint(c.Amount, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fee amount %q for %s", c.Amount, c.Denom)
+		}
+		if err := addCoin(req.fee, c.Denom, amount); err != nil {
+			return nil, err
+		}
+		if err := addCoin(req.spend, c.Denom, amount); err != nil {
+			return nil, err
+		}
+	}
+	for i, msg := range view.Messages {
+		req.msgTypes[i] = msg.Type
+		coins, err := extractor(msg.Type, msg.Data)
+		if err != nil {
+			return nil, fmt.Errorf("message %d (%s): %v", i, msg.Type, err)
+		}
+		for _, c := range coins {
+			if err := addCoin(req.spend, c.Denom, c.Amount); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return req, nil
+}
+
+// addCoin adds amount to totals[denom], rejecting overflow.
+func addCoin(totals map[string]uint64, denom string, amount uint64) error {
+	if totals[denom] > math.MaxUint64-amount {
+		return fmt.Errorf("amount overflow for %s", denom)
+	}
+	totals[denom] += amount
+	return nil
+}
+
+// evaluate checks the stateless rules of policy against req.
+// The daily spend limit is checked separately by spendTracker.reserve.
+func (p *SigningPolicy) evaluate(name string, req *policyRequest) error {
+	if len(p.AllowedChainIDs) > 0 && !containsString(p.AllowedChainIDs, req.chainID) {
+		return &PolicyViolationError{Key: name, Rule: PolicyRuleChainID,
+			Reason: fmt.Sprintf("chain ID %q not allowed", req.chainID)}
+	}
+	if len(p.AllowedMessageTypes) > 0 {
+		for _, t := range req.msgTypes {
+			if !containsString(p.AllowedMessageTypes, t) {
+				return &PolicyViolationError{Key: name, Rule: PolicyRuleMessageType,
+					Reason: fmt.Sprintf("message type %s not allowed", t)}
+			}
+		}
+	}
+	if len(p.MaxFee) > 0 {
+		limits := policyCoinMap(p.MaxFee)
+		for _, denom := range sortedDenoms(req.fee) {
+			limit, ok := limits[denom]
+			if !ok {
+				return &PolicyViolationError{Key: name, Rule: PolicyRuleMaxFee,
+					Reason: fmt.Sprintf("fee denom %s not allowed", denom)}
+			}
+			if req.fee[denom] > limit {
+				return &PolicyViolationError{Key: name, Rule: PolicyRuleMaxFee,
+					Reason: fmt.Sprintf("fee %d%s exceeds maximum %d%s", req.fee[denom], denom, limit, denom)}
+			}
+		}
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func policyCoinMap(coins []PolicyCoin) map[string]uint64 {
+	m := make(map[string]uint64, len(coins))
+	for _, c := range coins {
+		m[c.Denom] = c.Amount
+	}
+	return m
+}
+
+// sortedDenoms returns the keys of totals in sorted order so violations are
+// reported deterministically.
+func sortedDenoms(totals map[string]uint64) []string {
+	denoms := make([]string, 0, len(totals))
+	for d := range totals {
+		denoms = append(denoms, d)
+	}
+	sort.Strings(denoms)
+	return denoms
+}
+
+// spendTracker accumulates per-key spend for the current UTC day.
+// Thread-safe.
+type spendTracker struct {
+	mu   sync.Mutex
+	keys map[string]*dailySpend
+}
+
+// dailySpend is one key's spend on a given UTC day.
+type dailySpend struct {
+	day    string
+	totals map[string]uint64
+}
+
+func newSpendTracker() *spendTracker {
+	return &spendTracker{keys: make(map[string]*dailySpend)}
+}
+
+// reserve checks spend against limits for the day containing now and, if
+// within limits, records it. The returned release func undoes the
+// reservation (used when signing fails after the check).
+//
+// INVARIANT: For each key and day, recorded totals never exceed limits.
+func (t *spendTracker) reserve(name string, limits []PolicyCoin, spend map[string]uint64, now time.Time) (release func(), err error) {
+	if len(limits) == 0 {
+		return func() {}, nil
+	}
+	limitMap := policyCoinMap(limits)
+	day := now.UTC().Format(time.DateOnly)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ds, ok := t.keys[name]
+	if !ok || ds.day != day {
+		ds = &dailySpend{day: day, totals: make(map[string]uint64)}
+		t.keys[name] = ds
+	}
+
+	for _, denom := range sortedDenoms(spend) {
+		limit, ok := limitMap[denom]
+		if !ok {
+			return nil, &PolicyViolationError{Key: name, Rule: PolicyRuleDailySpend,
+				Reason: fmt.Sprintf("spend denom %s not allowed", denom)}
+		}
+		if ds.totals[denom] > limit || spend[denom] > limit-ds.totals[denom] {
+			return nil, &PolicyViolationError{Key: name, Rule: PolicyRuleDailySpend,
+				Reason: fmt.Sprintf("spending %d%s would exceed daily limit %d%s (%d%s already spent)",
+					spend[denom], denom, limit, denom, ds.totals[denom], denom)}
+		}
+	}
+	for denom, amount := range spend {
+		ds.totals[denom] += amount
+	}
+
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if cur, ok := t.keys[name]; ok && cur == ds {
+			for denom, amount := range spend {
+				ds.totals[denom] -= amount
+			}
+		}
+	}, nil
+}
+
+// clear discards tracked spend for name.
+func (t *spendTracker) clear(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.keys, name)
+}
+
+// policySigner wraps the Signer of a policy-bound key. Its Sign method
+// refuses raw data so a Signer obtained from GetKey cannot bypass the policy.
+type policySigner struct {
+	Signer
+	name   string
+	policy *SigningPolicy
+}
+
+// Sign implements Signer by rejecting raw data.
+func (s *policySigner) Sign([]byte) ([]byte, error) {
+	return nil, &PolicyViolationError{Key: s.name, Rule: PolicyRuleRawSign,
+		Reason: "key has a signing policy; use Keyring.SignSignDoc"}
+}
+
+// SetPolicy attaches policy to the named key, replacing any existing policy.
+// A nil policy removes it. The policy is persisted in the key's KeyEntry.
+//
+// SECURITY: The key's cached signer is zeroized, so Signers previously
+// returned by GetKey or NewKey for this key stop working rather than
+// continuing to sign outside the new policy.
+func (kr *defaultKeyring) SetPolicy(name string, policy *SigningPolicy) error {
+	if policy != nil {
+		if err := policy.ValidateBasic(); err != nil {
+			return fmt.Errorf("invalid signing policy: %w", err)
+		}
+	}
+
+	// Hold the write lock so no signer loaded under the old policy can be
+	// cached after the update (see GetKey's policyGen check).
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	if err := kr.checkClosed(); err != nil {
+		return err
+	}
+
+	entry, err := kr.store.Get(name)
+	if err != nil {
+		return err
+	}
+	defer Zeroize(entry.PrivateKey)
+
+	if entry.Multisig != nil {
+		return ErrMultisigKey
+	}
+	entry.Policy = policy.Clone()
+	if err := kr.store.Put(entry, true); err != nil {
+		return err
+	}
+
+	kr.policyGen++
+	kr.removeFromCacheLocked(name)
+	kr.spend.clear(name)
+	return nil
+}
+
+// GetPolicy returns a copy of the named key's policy, or nil if it has none.
+func (kr *defaultKeyring) GetPolicy(name string) (*SigningPolicy, error) {
+	kr.mu.RLock()
+	if err := kr.checkClosed(); err != nil {
+		kr.mu.RUnlock()
+		return nil, err
+	}
+	kr.mu.RUnlock()
+
+	entry, err := kr.store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	defer Zeroize(entry.PrivateKey)
+	return entry.Policy.Clone(), nil
+}
+
+// SignSignDoc evaluates the named key's policy against doc and, if allowed,
+// signs doc's sign bytes.
+//
+// Keys without a policy sign unconditionally.
+// Returns a *PolicyViolationError (matching ErrPolicyViolation) on rejection.
+// Complexity: O(GetKey) + O(n) where n is the SignDoc JSON size.
+func (kr *defaultKeyring) SignSignDoc(name string, doc PolicySignDoc) ([]byte, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("sign doc cannot be nil")
+	}
+	signer, err := kr.GetKey(name)
+	if err != nil {
+		return nil, err
+	}
+
+	signBytes, err := doc.GetSignBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	ps, ok := signer.(*policySigner)
+	if !ok {
+		if err := kr.confirmSigning(ConfirmRequest{Key: name, SignDoc: doc, Data: signBytes}); err != nil {
+			return nil, err
+		}
+		sig, err := kr.signWith(signer, signBytes)
+		if err != nil {
+			return nil, err
+		}
+		kr.usage.record(name, kr.now())
+		return sig, nil
+	}
+
+	docJSON, err := doc.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+	req, err := newPolicyRequest(docJSON, kr.spendExtractor)
+	if err != nil {
+		return nil, &PolicyViolationError{Key: name, Rule: PolicyRuleMalformed, Reason: err.Error()}
+	}
+	if err := ps.policy.evaluate(name, req); err != nil {
+		return nil, err
+	}
+
+	// Confirm after policy evaluation so users are never asked to approve a
+	// request the policy would reject.
+	if err := kr.confirmSigning(ConfirmRequest{Key: name, SignDoc: doc, Data: signBytes}); err != nil {
+		return nil, err
+	}
+
+	release, err := kr.spend.reserve(name, ps.policy.DailySpendLimit, req.spend, kr.now())
+	if err != nil {
+		return nil, err
+	}
+	sig, err := kr.signWith(ps.Signer, signBytes)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	kr.usage.record(name, kr.now())
+	return sig, nil
+}
+
+// signWith signs data while holding the read lock so Close cannot zeroize
+// the signer mid-operation.
+func (kr *defaultKeyring) signWith(signer Signer, data []byte) ([]byte, error) {
+	if len(data) > MaxSignDataLength {
+		return nil, ErrDataTooLarge
+	}
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	if err := kr.checkClosed(); err != nil {
+		return nil, err
+	}
+	return signer.Sign(data)
+}
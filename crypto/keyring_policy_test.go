@@ -0,0 +1,306 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// testPolicyDoc is a minimal PolicySignDoc; crypto cannot import types.SignDoc.
+type testPolicyDoc struct {
+	json []byte
+}
+
+func (d testPolicyDoc) ToJSON() ([]byte, error) { return d.json, nil }
+
+func (d testPolicyDoc) GetSignBytes() ([]byte, error) {
+	hash := sha256.Sum256(d.json)
+	return hash[:], nil
+}
+
+// newTestPolicyDoc builds a SignDoc-shaped document with one MsgSend per amount.
+func newTestPolicyDoc(t *testing.T, chainID string, fee string, sends ...uint64) testPolicyDoc {
+	t.Helper()
+	type coin struct {
+		Denom  string `json:"denom"`
+		Amount string `json:"amount"`
+	}
+	type msg struct {
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	doc := struct {
+		ChainID  string `json:"chain_id"`
+		Messages []msg  `json:"messages"`
+		Fee      struct {
+			Amount []coin `json:"amount"`
+		} `json:"fee"`
+	}{ChainID: chainID, Messages: []msg{}}
+	if fee != "" {
+		doc.Fee.Amount = []coin{{Denom: "stake", Amount: fee}}
+	}
+	for _, amount := range sends {
+		data, err := json.Marshal(map[string]any{"amount": map[string]any{"denom": "stake", "amount": amount}})
+		if err != nil {
+			t.Fatalf("marshal failed: %v", err)
+		}
+		doc.Messages = append(doc.Messages, msg{Type: "/punnet.bank.v1.MsgSend", Data: data})
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	return testPolicyDoc{json: data}
+}
+
+// newPolicyKeyring returns a keyring with key "k" bound to policy, the key's
+// public key and a settable clock.
+func newPolicyKeyring(t *testing.T, policy *SigningPolicy) (Keyring, PublicKey, *time.Time) {
+	t.Helper()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	kr := NewKeyring(NewMemoryStore(), WithKeyringClock(func() time.Time { return now }))
+	signer, err := kr.NewKey("k", AlgorithmEd25519)
+	if err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+	// Copy the public key: SetPolicy zeroizes previously returned signers.
+	pubKey, err := PublicKeyFromBytes(signer.Algorithm(), signer.PublicKey().Bytes())
+	if err != nil {
+		t.Fatalf("PublicKeyFromBytes failed: %v", err)
+	}
+	if err := kr.SetPolicy("k", policy); err != nil {
+		t.Fatalf("SetPolicy failed: %v", err)
+	}
+	return kr, pubKey, &now
+}
+
+func expectViolation(t *testing.T, err error, rule string) {
+	t.Helper()
+	var pve *PolicyViolationError
+	if !errors.As(err, &pve) {
+		t.Fatalf("expected PolicyViolationError, got %v", err)
+	}
+	if pve.Rule != rule {
+		t.Errorf("expected rule %s, got %s (%v)", rule, pve.Rule, err)
+	}
+	if !errors.Is(err, ErrPolicyViolation) {
+		t.Error("expected errors.Is(err, ErrPolicyViolation)")
+	}
+}
+
+func TestKeyringSignSignDocWithoutPolicy(t *testing.T) {
+	kr := NewKeyring(NewMemoryStore())
+	signer, err := kr.NewKey("k", AlgorithmEd25519)
+	if err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+
+	doc := newTestPolicyDoc(t, "any-chain", "1000000", 1<<40)
+	sig, err := kr.SignSignDoc("k", doc)
+	if err != nil {
+		t.Fatalf("SignSignDoc failed: %v", err)
+	}
+	signBytes, _ := doc.GetSignBytes()
+	if !signer.PublicKey().Verify(signBytes, sig) {
+		t.Error("signature does not verify")
+	}
+
+	policy, err := kr.GetPolicy("k")
+	if err != nil {
+		t.Fatalf("GetPolicy failed: %v", err)
+	}
+	if policy != nil {
+		t.Errorf("expected nil policy, got %+v", policy)
+	}
+}
+
+func TestKeyringPolicyRules(t *testing.T) {
+	policy := &SigningPolicy{
+		AllowedChainIDs:     []string{"punnet-1"},
+		AllowedMessageTypes: []string{"/punnet.bank.v1.MsgSend"},
+		MaxFee:              []PolicyCoin{{Denom: "stake", Amount: 500}},
+	}
+	kr, pubKey, _ := newPolicyKeyring(t, policy)
+
+	allowed := newTestPolicyDoc(t, "punnet-1", "500", 10)
+	sig, err := kr.SignSignDoc("k", allowed)
+	if err != nil {
+		t.Fatalf("SignSignDoc failed: %v", err)
+	}
+	signBytes, _ := allowed.GetSignBytes()
+	if !pubKey.Verify(signBytes, sig) {
+		t.Error("signature does not verify")
+	}
+
+	otherType := testPolicyDoc{json: []byte(`{"chain_id":"punnet-1","messages":[{"type":"/punnet.staking.v1.MsgDelegate","data":{}}],"fee":{"amount":[]}}`)}
+	otherDenom := testPolicyDoc{json: []byte(`{"chain_id":"punnet-1","messages":[],"fee":{"amount":[{"denom":"atom","amount":"1"}]}}`)}
+
+	tests := []struct {
+		name string
+		doc  PolicySignDoc
+		rule string
+	}{
+		{"wrong chain", newTestPolicyDoc(t, "punnet-2", "1", 10), PolicyRuleChainID},
+		{"disallowed message type", otherType, PolicyRuleMessageType},
+		{"fee too high", newTestPolicyDoc(t, "punnet-1", "501", 10), PolicyRuleMaxFee},
+		{"fee denom not allowed", otherDenom, PolicyRuleMaxFee},
+		{"malformed fee", newTestPolicyDoc(t, "punnet-1", "-1", 10), PolicyRuleMalformed},
+		{"not JSON", testPolicyDoc{json: []byte("not json")}, PolicyRuleMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := kr.SignSignDoc("k", tt.doc)
+			expectViolation(t, err, tt.rule)
+		})
+	}
+}
+
+func TestKeyringPolicyDailySpend(t *testing.T) {
+	kr, _, now := newPolicyKeyring(t, &SigningPolicy{
+		DailySpendLimit: []PolicyCoin{{Denom: "stake", Amount: 1000}},
+	})
+
+	// Fee and message amounts both count.
+	if _, err := kr.SignSignDoc("k", newTestPolicyDoc(t, "punnet-1", "100", 300, 200)); err != nil {
+		t.Fatalf("first sign failed: %v", err)
+	}
+	if _, err := kr.SignSignDoc("k", newTestPolicyDoc(t, "punnet-1", "0", 400)); err != nil {
+		t.Fatalf("second sign failed: %v", err)
+	}
+
+	// 1000 already spent; a rejected request does not consume the budget.
+	_, err := kr.SignSignDoc("k", newTestPolicyDoc(t, "punnet-1", "", 1))
+	expectViolation(t, err, PolicyRuleDailySpend)
+
+	// The window resets at the next UTC day.
+	*now = now.Add(12 * time.Hour)
+	if _, err := kr.SignSignDoc("k", newTestPolicyDoc(t, "punnet-1", "", 1000)); err != nil {
+		t.Fatalf("sign after day rollover failed: %v", err)
+	}
+
+	// Spend in a denomination without a limit is rejected.
+	other := testPolicyDoc{json: []byte(`{"chain_id":"punnet-1","messages":[{"type":"t","data":{"coins":[{"denom":"atom","amount":"1"}]}}],"fee":{"amount":[]}}`)}
+	_, err = kr.SignSignDoc("k", other)
+	expectViolation(t, err, PolicyRuleDailySpend)
+}
+
+func TestKeyringPolicyBlocksRawSigning(t *testing.T) {
+	kr, _, _ := newPolicyKeyring(t, &SigningPolicy{AllowedChainIDs: []string{"punnet-1"}})
+
+	_, err := kr.Sign("k", []byte("raw"))
+	expectViolation(t, err, PolicyRuleRawSign)
+
+	signer, err := kr.GetKey("k")
+	if err != nil {
+		t.Fatalf("GetKey failed: %v", err)
+	}
+	_, err = signer.Sign([]byte("raw"))
+	expectViolation(t, err, PolicyRuleRawSign)
+
+	if err := kr.SetPolicy("k", nil); err != nil {
+		t.Fatalf("SetPolicy(nil) failed: %v", err)
+	}
+	if _, err := kr.Sign("k", []byte("raw")); err != nil {
+		t.Errorf("raw sign after removing policy failed: %v", err)
+	}
+}
+
+func TestKeyringPolicyPersisted(t *testing.T) {
+	store := NewMemoryStore()
+	kr := NewKeyring(store, WithCacheSize(0))
+	if _, err := kr.NewKey("k", AlgorithmEd25519); err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+	policy := &SigningPolicy{
+		AllowedChainIDs: []string{"punnet-1"},
+		MaxFee:          []PolicyCoin{{Denom: "stake", Amount: 5}},
+	}
+	if err := kr.SetPolicy("k", policy); err != nil {
+		t.Fatalf("SetPolicy failed: %v", err)
+	}
+	policy.AllowedChainIDs[0] = "mutated"
+
+	kr2 := NewKeyring(store)
+	got, err := kr2.GetPolicy("k")
+	if err != nil {
+		t.Fatalf("GetPolicy failed: %v", err)
+	}
+	if got == nil || len(got.AllowedChainIDs) != 1 || got.AllowedChainIDs[0] != "punnet-1" {
+		t.Fatalf("unexpected persisted policy: %+v", got)
+	}
+	if _, err := kr2.Sign("k", []byte("raw")); !errors.Is(err, ErrPolicyViolation) {
+		t.Errorf("expected ErrPolicyViolation from uncached Sign, got %v", err)
+	}
+	if _, err := kr2.SignSignDoc("k", newTestPolicyDoc(t, "punnet-2", "1")); !errors.Is(err, ErrPolicyViolation) {
+		t.Errorf("expected ErrPolicyViolation, got %v", err)
+	}
+}
+
+func TestKeyringSetPolicyErrors(t *testing.T) {
+	kr := NewKeyring(NewMemoryStore())
+	if _, err := kr.NewKey("k", AlgorithmEd25519); err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+
+	invalid := []*SigningPolicy{
+		{AllowedChainIDs: []string{""}},
+		{AllowedMessageTypes: []string{""}},
+		{MaxFee: []PolicyCoin{{Denom: "", Amount: 1}}},
+		{DailySpendLimit: []PolicyCoin{{Denom: "stake"}, {Denom: "stake"}}},
+	}
+	for i, p := range invalid {
+		if err := kr.SetPolicy("k", p); err == nil {
+			t.Errorf("policy %d: expected validation error", i)
+		}
+	}
+
+	if err := kr.SetPolicy("missing", &SigningPolicy{}); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+	if _, err := kr.GetPolicy("missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+
+	if err := kr.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if err := kr.SetPolicy("k", nil); !errors.Is(err, ErrKeyringClosed) {
+		t.Errorf("expected ErrKeyringClosed, got %v", err)
+	}
+}
+
+func TestDefaultSpendExtractor(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []PolicyCoin
+		wantErr bool
+	}{
+		{"single coin numeric", `{"amount":{"denom":"stake","amount":5}}`, []PolicyCoin{{"stake", 5}}, false},
+		{"single coin string", `{"amount":{"denom":"stake","amount":"5"}}`, []PolicyCoin{{"stake", 5}}, false},
+		{"coin list", `{"coins":[{"denom":"a","amount":"1"},{"denom":"b","amount":"2"}]}`, []PolicyCoin{{"a", 1}, {"b", 2}}, false},
+		{"no amounts", `{"validator":"v"}`, nil, false},
+		{"non-object", `"signers"`, nil, false},
+		{"negative", `{"amount":{"denom":"stake","amount":-1}}`, nil, true},
+		{"missing denom", `{"amount":{"amount":"1"}}`, nil, true},
+		{"wrong shape", `{"amount":"100"}`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DefaultSpendExtractor("t", json.RawMessage(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("coin %d: got %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -1478,6 +1478,43 @@ func TestKeyringExportKeyEncryptedEmptyPassword(t *testing.T) {
 	}
 }
 
+func TestKeyringExportKeyEncryptedPublicKeyMismatch(t *testing.T) {
+	store := NewMemoryStore()
+	kr := NewKeyring(store)
+
+	if _, err := kr.NewKey("mismatch-test", AlgorithmEd25519); err != nil {
+		t.Fatalf("NewKey failed: %v", err)
+	}
+	originalKey, err := kr.ExportKey("mismatch-test", "")
+	if err != nil {
+		t.Fatalf("ExportKey (plaintext) failed: %v", err)
+	}
+	defer Zeroize(originalKey)
+
+	password := "correct-password"
+	encryptedEntry, err := createEncryptedKeyEntry("mismatch-test", originalKey, password)
+	if err != nil {
+		t.Fatalf("createEncryptedKeyEntry failed: %v", err)
+	}
+
+	// Swap in another key's public key, as an attacker with write access to
+	// the store might to redirect funds
+	otherKey, err := GeneratePrivateKey(AlgorithmEd25519)
+	if err != nil {
+		t.Fatalf("GeneratePrivateKey failed: %v", err)
+	}
+	encryptedEntry.PublicKey = otherKey.PublicKey().Bytes()
+	if err := store.Put(encryptedEntry, true); err != nil {
+		t.Fatalf("store.Put failed: %v", err)
+	}
+
+	// The correct password must fail exactly as a wrong one does
+	_, err = NewKeyring(store).ExportKey("mismatch-test", password)
+	if err != ErrInvalidPassword {
+		t.Errorf("expected ErrInvalidPassword, got %v", err)
+	}
+}
+
 func TestKeyringExportKeyInvalidEncryptionParams(t *testing.T) {
 	store := NewMemoryStore()
 
@@ -230,6 +230,11 @@ func PublicKeyFromBytes(algo Algorithm, data []byte) (PublicKey, error) {
 // SerializablePublicKey wraps a PublicKey for JSON serialization.
 // JSON format: {"pub_key": "<base64>", "algorithm": "<algo>"}
 //
+// PublicKey is an interface, so encoding/json cannot unmarshal into it
+// directly; use SerializablePublicKey for public key fields instead.
+// UnmarshalJSON also accepts a self-describing string in any encoding
+// accepted by PubKeyFromString, e.g. "ed25519:base64:...".
+//
 // Complexity: O(n) for marshaling/unmarshaling where n is key size.
 // Memory: One allocation for base64 encoding during marshal.
 type SerializablePublicKey struct {
@@ -265,13 +270,27 @@ func (s *SerializablePublicKey) MarshalJSON() ([]byte, error) {
 }
 
 // UnmarshalJSON implements json.Unmarshaler.
-// Decodes the public key from Base64 standard encoding.
+// Decodes the public key from Base64 standard encoding, or from a
+// PubKeyToString string.
 func (s *SerializablePublicKey) UnmarshalJSON(data []byte) error {
 	if string(data) == "null" {
 		s.key = nil
 		return nil
 	}
 
+	if len(data) > 0 && data[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPubKeyString, err)
+		}
+		key, err := PubKeyFromString(str)
+		if err != nil {
+			return err
+		}
+		s.key = key
+		return nil
+	}
+
 	var j serializablePublicKeyJSON
 	if err := json.Unmarshal(data, &j); err != nil {
 		return fmt.Errorf("invalid public key JSON: %w", err)
@@ -72,8 +72,8 @@ type KeyEntry struct {
 	// Name is the unique identifier for this key.
 	Name string `json:"name"`
 
-	// Algorithm is the key's signing algorithm.
-	Algorithm Algorithm `json:"algorithm"`
+	// Algorithm is the key's signing algorithm (empty for multisig entries).
+	Algorithm Algorithm `json:"algorithm,omitempty"`
 
 	// PrivateKey is the encrypted or raw private key bytes.
 	// For encrypted storage, this contains the ciphertext.
@@ -85,13 +85,32 @@ type KeyEntry struct {
 	// Encrypted indicates whether PrivateKey is encrypted.
 	Encrypted bool `json:"encrypted"`
 
-	// Salt is the PBKDF2 salt used for key derivation (only set when Encrypted=true).
+	// Salt is the KDF salt used for key derivation (only set when Encrypted=true).
 	// MUST be at least MinSaltLength (16) bytes when present.
 	Salt []byte `json:"salt,omitempty"`
 
 	// Nonce is the AES-GCM nonce used for encryption (only set when Encrypted=true).
 	// MUST be exactly AESGCMNonceLength (12) bytes when present.
 	Nonce []byte `json:"nonce,omitempty"`
+
+	// KDF identifies the password-based key derivation function (only set
+	// when Encrypted=true). Empty means legacy PBKDF2; see keyring_kdf.go.
+	KDF KDF `json:"kdf,omitempty"`
+
+	// KDFParams holds the Argon2id parameters when KDF is KDFArgon2id.
+	KDFParams *KDFParams `json:"kdf_params,omitempty"`
+
+	// Policy restricts what the key may sign (nil means unrestricted).
+	// See SigningPolicy.
+	Policy *SigningPolicy `json:"policy,omitempty"`
+
+	// Metadata is optional descriptive information (label, account, times).
+	// See KeyMetadata.
+	Metadata *KeyMetadata `json:"metadata,omitempty"`
+
+	// Multisig is set for multisig entries, which describe a threshold of
+	// member public keys and hold no private key. See MultisigDescriptor.
+	Multisig *MultisigDescriptor `json:"multisig,omitempty"`
 }
 
 // Clone creates a deep copy of the KeyEntry.
@@ -106,6 +125,14 @@ func (e *KeyEntry) Clone() *KeyEntry {
 		Name:      e.Name,
 		Algorithm: e.Algorithm,
 		Encrypted: e.Encrypted,
+		KDF:       e.KDF,
+		Policy:    e.Policy.Clone(),
+		Metadata:  e.Metadata.Clone(),
+		Multisig:  e.Multisig.Clone(),
+	}
+	if e.KDFParams != nil {
+		params := *e.KDFParams
+		clone.KDFParams = &params
 	}
 	if e.PrivateKey != nil {
 		clone.PrivateKey = make([]byte, len(e.PrivateKey))
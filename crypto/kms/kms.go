@@ -0,0 +1,262 @@
+// Package kms provides crypto.Signer implementations backed by asymmetric keys
+// held in a cloud key management service (AWS KMS, Google Cloud KMS).
+//
+// Private keys never leave the KMS. The Signer fetches the public key once,
+// hashes each message locally with SHA-256 and asks the KMS to sign the
+// digest. The KMS returns an ASN.1 DER ECDSA signature, which the Signer
+// converts to the 64-byte r||s form used by SignDoc signatures, normalized to
+// low-S and verified against the public key before it is returned.
+//
+// Supported key specs:
+//
+//	Algorithm            AWS KMS KeySpec       GCP CryptoKeyVersionAlgorithm
+//	secp256k1            ECC_SECG_P256K1       EC_SIGN_SECP256K1_SHA256
+//	secp256r1 (P-256)    ECC_NIST_P256         EC_SIGN_P256_SHA256
+//
+// The cloud SDKs are not dependencies of this package. Adapt them with a
+// small Client implementation:
+//
+//	AWS:  GetPublicKey -> GetPublicKeyOutput.PublicKey (DER)
+//	      SignDigest   -> Sign{MessageType: DIGEST, SigningAlgorithm: ECDSA_SHA_256}.Signature
+//	GCP:  GetPublicKey -> GetPublicKey().Pem (PEM is accepted)
+//	      SignDigest   -> AsymmetricSign{Digest: {Sha256: digest}}.Signature
+package kms
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+)
+
+// Errors returned by the KMS signer.
+var (
+	// ErrUnsupportedKey indicates the KMS key is not a supported ECDSA key.
+	ErrUnsupportedKey = errors.New("unsupported KMS key")
+
+	// ErrInvalidPublicKey indicates the KMS returned a malformed public key.
+	ErrInvalidPublicKey = errors.New("invalid KMS public key")
+
+	// ErrInvalidSignature indicates the KMS returned a malformed signature or
+	// one that does not verify against the key's public key.
+	ErrInvalidSignature = errors.New("invalid KMS signature")
+)
+
+// defaultSignTimeout bounds a single Sign call made without a context.
+const defaultSignTimeout = 10 * time.Second
+
+// Client is the subset of a cloud KMS API the Signer needs.
+// Implementations must be safe for concurrent use.
+type Client interface {
+	// GetPublicKey returns the key's public key as a DER- or PEM-encoded
+	// X.509 SubjectPublicKeyInfo.
+	GetPublicKey(ctx context.Context, keyID string) ([]byte, error)
+
+	// SignDigest signs a 32-byte SHA-256 digest and returns an ASN.1 DER
+	// encoded ECDSA signature.
+	SignDigest(ctx context.Context, keyID string, digest []byte) ([]byte, error)
+}
+
+// Signer is a crypto.Signer backed by a KMS key.
+// Thread-safe.
+type Signer struct {
+	client  Client
+	keyID   string
+	pubKey  crypto.PublicKey
+	timeout time.Duration
+}
+
+var _ crypto.Signer = (*Signer)(nil)
+
+// SignerOption configures a Signer.
+type SignerOption func(*Signer)
+
+// WithSignTimeout sets the timeout applied to Sign calls. Defaults to 10s.
+// SignContext uses the caller's context instead.
+func WithSignTimeout(timeout time.Duration) SignerOption {
+	return func(s *Signer) {
+		s.timeout = timeout
+	}
+}
+
+// NewSigner fetches the public key for keyID and returns a Signer for it.
+// The algorithm is taken from the key's curve.
+func NewSigner(ctx context.Context, client Client, keyID string, opts ...SignerOption) (*Signer, error) {
+	if client == nil {
+		return nil, fmt.Errorf("KMS client cannot be nil")
+	}
+	if keyID == "" {
+		return nil, fmt.Errorf("KMS key ID cannot be empty")
+	}
+
+	encoded, err := client.GetPublicKey(ctx, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch public key for %s: %w", keyID, err)
+	}
+	pubKey, err := ParsePublicKey(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Signer{
+		client:  client,
+		keyID:   keyID,
+		pubKey:  pubKey,
+		timeout: defaultSignTimeout,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// KeyID returns the KMS key identifier.
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
+
+// PublicKey implements crypto.Signer.
+func (s *Signer) PublicKey() crypto.PublicKey {
+	return s.pubKey
+}
+
+// Algorithm implements crypto.Signer.
+func (s *Signer) Algorithm() crypto.Algorithm {
+	return s.pubKey.Algorithm()
+}
+
+// Sign implements crypto.Signer.
+func (s *Signer) Sign(data []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	return s.SignContext(ctx, data)
+}
+
+// SignContext signs data with the KMS key.
+//
+// POSTCONDITION: The returned signature is 64 bytes (r||s), low-S, and
+// verifies under PublicKey().Verify(data, sig), matching the output of the
+// local secp256k1/secp256r1 signers.
+func (s *Signer) SignContext(ctx context.Context, data []byte) ([]byte, error) {
+	digest := sha256.Sum256(data)
+
+	der, err := s.client.SignDigest(ctx, s.keyID, digest[:])
+	if err != nil {
+		return nil, fmt.Errorf("KMS sign failed for %s: %w", s.keyID, err)
+	}
+
+	sig, err := DERToRS(der, s.Algorithm())
+	if err != nil {
+		return nil, err
+	}
+
+	// SECURITY: Catch a misconfigured key ID or a KMS returning a signature
+	// from a different key before it reaches a transaction.
+	if !s.pubKey.Verify(data, sig) {
+		return nil, fmt.Errorf("%w: signature does not verify against %s", ErrInvalidSignature, s.keyID)
+	}
+	return sig, nil
+}
+
+// ecdsaSignature is the ASN.1 structure of a DER ECDSA signature.
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// DERToRS converts an ASN.1 DER ECDSA signature to the 64-byte r||s form,
+// normalizing s to the lower half of the curve order.
+//
+// Complexity: O(1).
+func DERToRS(der []byte, algo crypto.Algorithm) ([]byte, error) {
+	n := crypto.CurveOrder(algo)
+	if n == nil {
+		return nil, fmt.Errorf("%w: algorithm %s", ErrUnsupportedKey, algo)
+	}
+
+	var sig ecdsaSignature
+	rest, err := asn1.Unmarshal(der, &sig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: trailing data after DER signature", ErrInvalidSignature)
+	}
+	if sig.R.Sign() <= 0 || sig.R.Cmp(n) >= 0 || sig.S.Sign() <= 0 || sig.S.Cmp(n) >= 0 {
+		return nil, fmt.Errorf("%w: r or s out of range", ErrInvalidSignature)
+	}
+
+	out := make([]byte, 64)
+	sig.R.FillBytes(out[:32])
+	sig.S.FillBytes(out[32:])
+	return crypto.NormalizeSignature(out, algo), nil
+}
+
+// Object identifiers for SubjectPublicKeyInfo parsing.
+var (
+	oidECPublicKey = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidP256        = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	oidSecp256k1   = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// subjectPublicKeyInfo is the ASN.1 structure of an X.509 public key.
+// Parsed directly because crypto/x509 does not support secp256k1.
+type subjectPublicKeyInfo struct {
+	Algorithm struct {
+		Algorithm  asn1.ObjectIdentifier
+		Parameters asn1.RawValue `asn1:"optional"`
+	}
+	PublicKey asn1.BitString
+}
+
+// ParsePublicKey parses a DER- or PEM-encoded SubjectPublicKeyInfo holding a
+// secp256k1 or P-256 key, as returned by AWS KMS and Google Cloud KMS.
+func ParsePublicKey(encoded []byte) (crypto.PublicKey, error) {
+	if block, _ := pem.Decode(encoded); block != nil {
+		if block.Type != "PUBLIC KEY" {
+			return nil, fmt.Errorf("%w: unexpected PEM block %q", ErrInvalidPublicKey, block.Type)
+		}
+		encoded = block.Bytes
+	}
+
+	var spki subjectPublicKeyInfo
+	rest, err := asn1.Unmarshal(encoded, &spki)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: trailing data after public key", ErrInvalidPublicKey)
+	}
+	if !spki.Algorithm.Algorithm.Equal(oidECPublicKey) {
+		return nil, fmt.Errorf("%w: not an EC key (%s)", ErrUnsupportedKey, spki.Algorithm.Algorithm)
+	}
+
+	var curve asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(spki.Algorithm.Parameters.FullBytes, &curve); err != nil {
+		return nil, fmt.Errorf("%w: missing named curve: %v", ErrInvalidPublicKey, err)
+	}
+
+	var algo crypto.Algorithm
+	switch {
+	case curve.Equal(oidSecp256k1):
+		algo = crypto.AlgorithmSecp256k1
+	case curve.Equal(oidP256):
+		algo = crypto.AlgorithmSecp256r1
+	default:
+		return nil, fmt.Errorf("%w: curve %s", ErrUnsupportedKey, curve)
+	}
+
+	if spki.PublicKey.BitLength%8 != 0 {
+		return nil, fmt.Errorf("%w: public key bit string is not byte aligned", ErrInvalidPublicKey)
+	}
+	pubKey, err := crypto.PublicKeyFromBytes(algo, spki.PublicKey.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+	}
+	return pubKey, nil
+}
@@ -0,0 +1,233 @@
+package kms
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	dcrecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+)
+
+// fakeKMS is an in-memory Client holding one key.
+type fakeKMS struct {
+	publicKey []byte
+	sign      func(digest []byte) ([]byte, error)
+	digests   [][]byte
+}
+
+func (f *fakeKMS) GetPublicKey(_ context.Context, keyID string) ([]byte, error) {
+	if keyID != "test-key" {
+		return nil, errors.New("key not found")
+	}
+	return f.publicKey, nil
+}
+
+func (f *fakeKMS) SignDigest(_ context.Context, _ string, digest []byte) ([]byte, error) {
+	f.digests = append(f.digests, append([]byte(nil), digest...))
+	return f.sign(digest)
+}
+
+// marshalSPKI encodes an uncompressed EC point as a SubjectPublicKeyInfo.
+func marshalSPKI(t *testing.T, curve asn1.ObjectIdentifier, point []byte) []byte {
+	t.Helper()
+	params, err := asn1.Marshal(curve)
+	require.NoError(t, err)
+	var spki subjectPublicKeyInfo
+	spki.Algorithm.Algorithm = oidECPublicKey
+	spki.Algorithm.Parameters = asn1.RawValue{FullBytes: params}
+	spki.PublicKey = asn1.BitString{Bytes: point, BitLength: len(point) * 8}
+	der, err := asn1.Marshal(spki)
+	require.NoError(t, err)
+	return der
+}
+
+func newSecp256k1KMS(t *testing.T) (*fakeKMS, *secp256k1.PrivateKey) {
+	t.Helper()
+	priv, err := secp256k1.GeneratePrivateKey()
+	require.NoError(t, err)
+	return &fakeKMS{
+		publicKey: marshalSPKI(t, oidSecp256k1, priv.PubKey().SerializeUncompressed()),
+		sign: func(digest []byte) ([]byte, error) {
+			return dcrecdsa.Sign(priv, digest).Serialize(), nil
+		},
+	}, priv
+}
+
+func newP256KMS(t *testing.T) (*fakeKMS, *ecdsa.PrivateKey) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+	return &fakeKMS{
+		// GCP returns PEM.
+		publicKey: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}),
+		sign: func(digest []byte) ([]byte, error) {
+			return ecdsa.SignASN1(rand.Reader, priv, digest)
+		},
+	}, priv
+}
+
+func TestSigner_Secp256k1(t *testing.T) {
+	kms, priv := newSecp256k1KMS(t)
+	signer, err := NewSigner(context.Background(), kms, "test-key")
+	require.NoError(t, err)
+
+	assert.Equal(t, crypto.AlgorithmSecp256k1, signer.Algorithm())
+	assert.Equal(t, priv.PubKey().SerializeCompressed(), signer.PublicKey().Bytes())
+	assert.Equal(t, "test-key", signer.KeyID())
+
+	data := []byte("sign doc bytes")
+	sig, err := signer.Sign(data)
+	require.NoError(t, err)
+	assert.Len(t, sig, 64)
+	assert.True(t, crypto.IsLowSForAlgorithm(sig, crypto.AlgorithmSecp256k1))
+	assert.True(t, signer.PublicKey().Verify(data, sig))
+	require.Len(t, kms.digests, 1)
+	assert.Len(t, kms.digests[0], 32)
+}
+
+func TestSigner_P256(t *testing.T) {
+	kms, _ := newP256KMS(t)
+	signer, err := NewSigner(context.Background(), kms, "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, crypto.AlgorithmSecp256r1, signer.Algorithm())
+
+	// P-256 KMS signatures are high-S about half the time; sign repeatedly so
+	// normalization is exercised.
+	data := []byte("sign doc bytes")
+	for i := 0; i < 16; i++ {
+		sig, err := signer.Sign(data)
+		require.NoError(t, err)
+		assert.Len(t, sig, 64)
+		assert.True(t, crypto.IsLowSForAlgorithm(sig, crypto.AlgorithmSecp256r1))
+		assert.True(t, signer.PublicKey().Verify(data, sig))
+	}
+}
+
+func TestSigner_RejectsWrongKeySignature(t *testing.T) {
+	kms, _ := newSecp256k1KMS(t)
+	other, _ := newSecp256k1KMS(t)
+	kms.sign = other.sign
+
+	signer, err := NewSigner(context.Background(), kms, "test-key")
+	require.NoError(t, err)
+	_, err = signer.Sign([]byte("data"))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestSigner_KMSErrors(t *testing.T) {
+	kms, _ := newSecp256k1KMS(t)
+
+	_, err := NewSigner(context.Background(), kms, "missing")
+	assert.Error(t, err)
+	_, err = NewSigner(context.Background(), nil, "test-key")
+	assert.Error(t, err)
+	_, err = NewSigner(context.Background(), kms, "")
+	assert.Error(t, err)
+
+	signErr := errors.New("throttled")
+	kms.sign = func([]byte) ([]byte, error) { return nil, signErr }
+	signer, err := NewSigner(context.Background(), kms, "test-key")
+	require.NoError(t, err)
+	_, err = signer.Sign([]byte("data"))
+	assert.ErrorIs(t, err, signErr)
+}
+
+func TestDERToRS(t *testing.T) {
+	n := crypto.CurveOrder(crypto.AlgorithmSecp256k1)
+	halfN := crypto.HalfCurveOrder(crypto.AlgorithmSecp256k1)
+	highS := new(big.Int).Add(halfN, big.NewInt(5))
+
+	mustDER := func(r, s *big.Int) []byte {
+		der, err := asn1.Marshal(ecdsaSignature{R: r, S: s})
+		require.NoError(t, err)
+		return der
+	}
+
+	t.Run("pads short values", func(t *testing.T) {
+		sig, err := DERToRS(mustDER(big.NewInt(1), big.NewInt(2)), crypto.AlgorithmSecp256k1)
+		require.NoError(t, err)
+		want := make([]byte, 64)
+		want[31], want[63] = 1, 2
+		assert.Equal(t, want, sig)
+	})
+
+	t.Run("normalizes high-S", func(t *testing.T) {
+		sig, err := DERToRS(mustDER(big.NewInt(7), highS), crypto.AlgorithmSecp256k1)
+		require.NoError(t, err)
+		s := new(big.Int).SetBytes(sig[32:])
+		assert.Equal(t, 0, s.Cmp(new(big.Int).Sub(n, highS)))
+	})
+
+	tests := []struct {
+		name string
+		der  []byte
+		algo crypto.Algorithm
+		want error
+	}{
+		{"zero r", mustDER(big.NewInt(0), big.NewInt(1)), crypto.AlgorithmSecp256k1, ErrInvalidSignature},
+		{"s equals n", mustDER(big.NewInt(1), n), crypto.AlgorithmSecp256k1, ErrInvalidSignature},
+		{"negative s", mustDER(big.NewInt(1), big.NewInt(-1)), crypto.AlgorithmSecp256k1, ErrInvalidSignature},
+		{"trailing data", append(mustDER(big.NewInt(1), big.NewInt(1)), 0), crypto.AlgorithmSecp256k1, ErrInvalidSignature},
+		{"garbage", []byte{0x01, 0x02}, crypto.AlgorithmSecp256k1, ErrInvalidSignature},
+		{"ed25519", mustDER(big.NewInt(1), big.NewInt(1)), crypto.AlgorithmEd25519, ErrUnsupportedKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DERToRS(tt.der, tt.algo)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	kms, priv := newSecp256k1KMS(t)
+	pubKey, err := ParsePublicKey(kms.publicKey)
+	require.NoError(t, err)
+	assert.Equal(t, priv.PubKey().SerializeCompressed(), pubKey.Bytes())
+
+	p256, _ := newP256KMS(t)
+	block, _ := pem.Decode(p256.publicKey)
+	pubKey, err = ParsePublicKey(block.Bytes)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.AlgorithmSecp256r1, pubKey.Algorithm())
+
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	p384DER, err := x509.MarshalPKIXPublicKey(&p384.PublicKey)
+	require.NoError(t, err)
+
+	offCurve := make([]byte, 65)
+	offCurve[0] = 0x04
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"unsupported curve", p384DER, ErrUnsupportedKey},
+		{"point not on curve", marshalSPKI(t, oidSecp256k1, offCurve), ErrInvalidPublicKey},
+		{"wrong PEM type", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: block.Bytes}), ErrInvalidPublicKey},
+		{"trailing data", append(append([]byte(nil), block.Bytes...), 0), ErrInvalidPublicKey},
+		{"garbage", []byte("not a key"), ErrInvalidPublicKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePublicKey(tt.data)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
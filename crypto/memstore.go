@@ -1,11 +1,18 @@
 package crypto
 
 import (
+	"hash/maphash"
 	"sync"
 )
 
+// memoryStoreShards is the number of independently locked shards of a
+// MemoryStore (a power of two)
+const memoryStoreShards = 16
+
 // MemoryStore implements SimpleKeyStore with in-memory storage.
-// Thread-safe via RWMutex. Optimized for read-heavy workloads.
+// Thread-safe. Keys are spread over memoryStoreShards shards by name hash,
+// each guarded by its own RWMutex, so concurrent access to different keys
+// rarely contends. Optimized for read-heavy workloads.
 //
 // Performance characteristics:
 // - Get: O(1) average, O(n) worst case (hash collision)
@@ -14,26 +21,41 @@ import (
 // - List: O(n) where n is number of keys
 // - Has: O(1) average
 //
-// Memory: ~128 bytes overhead per key + key data size.
+// Memory: ~128 bytes overhead per key + key data size, plus ~1 KB of shards.
 type MemoryStore struct {
+	seed   maphash.Seed
+	shards [memoryStoreShards]keyShard
+}
+
+// keyShard is one lock-protected partition of a MemoryStore
+type keyShard struct {
 	mu   sync.RWMutex
 	keys map[string]*KeyEntry
+
+	// Pad to a cache line so neighbouring shard locks do not false-share
+	_ [32]byte
 }
 
 // NewMemoryStore creates a new in-memory key store.
-// Pre-allocates map for expected capacity to reduce reallocations.
+// Pre-allocates maps for expected capacity to reduce reallocations.
 func NewMemoryStore() *MemoryStore {
-	return &MemoryStore{
-		keys: make(map[string]*KeyEntry, 16), // Pre-allocate for typical use
-	}
+	return NewMemoryStoreWithCapacity(16) // Pre-allocate for typical use
 }
 
 // NewMemoryStoreWithCapacity creates a store with specified initial capacity.
 // Use this when you know the approximate number of keys to avoid rehashing.
 func NewMemoryStoreWithCapacity(capacity int) *MemoryStore {
-	return &MemoryStore{
-		keys: make(map[string]*KeyEntry, capacity),
+	perShard := capacity / memoryStoreShards
+	s := &MemoryStore{seed: maphash.MakeSeed()}
+	for i := range s.shards {
+		s.shards[i].keys = make(map[string]*KeyEntry, perShard)
 	}
+	return s
+}
+
+// shard returns the shard holding name
+func (s *MemoryStore) shard(name string) *keyShard {
+	return &s.shards[maphash.String(s.seed, name)&(memoryStoreShards-1)]
 }
 
 // Get retrieves a key entry by name.
@@ -41,10 +63,11 @@ func NewMemoryStoreWithCapacity(capacity int) *MemoryStore {
 // The read lock is held until Clone() completes to prevent race conditions
 // with concurrent Delete() operations that zeroize private key material.
 func (s *MemoryStore) Get(name string) (*KeyEntry, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	sh := s.shard(name)
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
 
-	entry, ok := s.keys[name]
+	entry, ok := sh.keys[name]
 	if !ok {
 		return nil, ErrKeyNotFound
 	}
@@ -54,46 +77,60 @@ func (s *MemoryStore) Get(name string) (*KeyEntry, error) {
 
 // Put stores a key entry.
 func (s *MemoryStore) Put(entry *KeyEntry, overwrite bool) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	// Store a clone to prevent external mutation; cloning before locking
+	// keeps the critical section short
+	clone := entry.Clone()
+
+	sh := s.shard(entry.Name)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
 
 	if !overwrite {
-		if _, exists := s.keys[entry.Name]; exists {
+		if _, exists := sh.keys[entry.Name]; exists {
+			Zeroize(clone.PrivateKey)
 			return ErrKeyExists
 		}
 	}
 
-	// Store a clone to prevent external mutation
-	s.keys[entry.Name] = entry.Clone()
+	sh.keys[entry.Name] = clone
 	return nil
 }
 
 // Delete removes a key entry.
 // Zeros private key material before removal for security.
 func (s *MemoryStore) Delete(name string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	sh := s.shard(name)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
 
-	entry, exists := s.keys[name]
+	entry, exists := sh.keys[name]
 	if !exists {
 		return ErrKeyNotFound
 	}
 
 	// Zero private key bytes before deletion to minimize memory exposure
 	Zeroize(entry.PrivateKey)
-	delete(s.keys, name)
+	delete(sh.keys, name)
 	return nil
 }
 
 // List returns all key names.
-// Pre-allocates result slice to avoid reallocations.
+// Read-locks every shard (in index order) so the result is a consistent
+// snapshot.
 func (s *MemoryStore) List() ([]string, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	s.rlockAll()
+	defer s.runlockAll()
+
+	n := 0
+	for i := range s.shards {
+		n += len(s.shards[i].keys)
+	}
 
-	names := make([]string, 0, len(s.keys))
-	for name := range s.keys {
-		names = append(names, name)
+	names := make([]string, 0, n)
+	for i := range s.shards {
+		for name := range s.shards[i].keys {
+			names = append(names, name)
+		}
 	}
 	return names, nil
 }
@@ -101,18 +138,23 @@ func (s *MemoryStore) List() ([]string, error) {
 // Has returns true if a key exists.
 // More efficient than Get when you don't need the key data.
 func (s *MemoryStore) Has(name string) (bool, error) {
-	s.mu.RLock()
-	_, exists := s.keys[name]
-	s.mu.RUnlock()
+	sh := s.shard(name)
+	sh.mu.RLock()
+	_, exists := sh.keys[name]
+	sh.mu.RUnlock()
 	return exists, nil
 }
 
 // Len returns the number of keys.
 // Useful for monitoring and testing.
 func (s *MemoryStore) Len() int {
-	s.mu.RLock()
-	n := len(s.keys)
-	s.mu.RUnlock()
+	s.rlockAll()
+	defer s.runlockAll()
+
+	n := 0
+	for i := range s.shards {
+		n += len(s.shards[i].keys)
+	}
 	return n
 }
 
@@ -120,11 +162,30 @@ func (s *MemoryStore) Len() int {
 // Zeros private key material before removal for security.
 // Useful for testing.
 func (s *MemoryStore) Clear() {
-	s.mu.Lock()
-	// Zero all private keys before clearing
-	for _, entry := range s.keys {
-		Zeroize(entry.PrivateKey)
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.Lock()
+		// Zero all private keys before clearing
+		for _, entry := range sh.keys {
+			Zeroize(entry.PrivateKey)
+		}
+		sh.keys = make(map[string]*KeyEntry)
+		sh.mu.Unlock()
+	}
+}
+
+// rlockAll read-locks every shard in index order.
+// INVARIANT: Multi-shard locking always follows index order, so it cannot
+// deadlock with other multi-shard or single-shard lockers.
+func (s *MemoryStore) rlockAll() {
+	for i := range s.shards {
+		s.shards[i].mu.RLock()
+	}
+}
+
+// runlockAll releases the locks taken by rlockAll
+func (s *MemoryStore) runlockAll() {
+	for i := range s.shards {
+		s.shards[i].mu.RUnlock()
 	}
-	s.keys = make(map[string]*KeyEntry, 16)
-	s.mu.Unlock()
 }
@@ -0,0 +1,163 @@
+package crypto
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func newMemStoreEntry(name string) *KeyEntry {
+	return &KeyEntry{
+		Name:       name,
+		Algorithm:  AlgorithmEd25519,
+		PrivateKey: make([]byte, 64),
+		PublicKey:  make([]byte, 32),
+	}
+}
+
+func TestMemoryStoreShardedListAndLen(t *testing.T) {
+	store := NewMemoryStoreWithCapacity(256)
+
+	const n = 200
+	want := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("key-%03d", i)
+		want = append(want, name)
+		if err := store.Put(newMemStoreEntry(name), false); err != nil {
+			t.Fatalf("Put(%s) failed: %v", name, err)
+		}
+	}
+
+	if got := store.Len(); got != n {
+		t.Fatalf("expected Len %d, got %d", n, got)
+	}
+
+	names, err := store.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	sort.Strings(names)
+	if len(names) != n {
+		t.Fatalf("expected %d names, got %d", n, len(names))
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("name %d: expected %s, got %s", i, want[i], names[i])
+		}
+	}
+
+	store.Clear()
+	if got := store.Len(); got != 0 {
+		t.Fatalf("expected empty store after Clear, got %d", got)
+	}
+}
+
+func TestMemoryStorePutExistingZeroizesClone(t *testing.T) {
+	store := NewMemoryStore()
+	if err := store.Put(newMemStoreEntry("dup"), false); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	second := newMemStoreEntry("dup")
+	second.PrivateKey[0] = 0xFF
+	if err := store.Put(second, false); err != ErrKeyExists {
+		t.Fatalf("expected ErrKeyExists, got %v", err)
+	}
+
+	// The caller's entry is left untouched; only the internal clone is wiped
+	if second.PrivateKey[0] != 0xFF {
+		t.Fatal("Put modified the caller's private key")
+	}
+	got, err := store.Get("dup")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.PrivateKey[0] != 0 {
+		t.Fatal("rejected Put overwrote the stored entry")
+	}
+}
+
+func TestMemoryStoreConcurrentAccess(t *testing.T) {
+	store := NewMemoryStore()
+
+	const workers = 8
+	const perWorker = 100
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				name := fmt.Sprintf("w%d-%d", w, i)
+				if err := store.Put(newMemStoreEntry(name), false); err != nil {
+					t.Errorf("Put(%s) failed: %v", name, err)
+					return
+				}
+				if _, err := store.Get(name); err != nil {
+					t.Errorf("Get(%s) failed: %v", name, err)
+					return
+				}
+				if i%2 == 0 {
+					if err := store.Delete(name); err != nil {
+						t.Errorf("Delete(%s) failed: %v", name, err)
+						return
+					}
+				}
+			}
+		}(w)
+	}
+	for r := 0; r < 2; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				if _, err := store.List(); err != nil {
+					t.Errorf("List failed: %v", err)
+					return
+				}
+				_ = store.Len()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got, want := store.Len(), workers*perWorker/2; got != want {
+		t.Fatalf("expected %d keys, got %d", want, got)
+	}
+}
+
+// benchmarkMemoryStoreParallel runs a parallel workload over 256 keys where
+// writePercent of operations are overwriting Puts and the rest Gets
+func benchmarkMemoryStoreParallel(b *testing.B, writePercent int) {
+	store := NewMemoryStoreWithCapacity(256)
+	entries := make([]*KeyEntry, 256)
+	for i := range entries {
+		entries[i] = newMemStoreEntry(fmt.Sprintf("validator-%03d", i))
+		_ = store.Put(entries[i], false)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			entry := entries[(i*131)%len(entries)]
+			if i%100 < writePercent {
+				_ = store.Put(entry, true)
+			} else {
+				_, _ = store.Get(entry.Name)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkMemoryStoreParallel(b *testing.B) {
+	for _, writePercent := range []int{0, 10, 50} {
+		b.Run(fmt.Sprintf("Writes%d%%", writePercent), func(b *testing.B) {
+			benchmarkMemoryStoreParallel(b, writePercent)
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package crypto
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultPublicKeyCacheCapacity is the capacity of the shared cache used by
+// ParsePublicKeyCached
+const DefaultPublicKeyCacheCapacity = 4096
+
+// defaultPublicKeyCache backs ParsePublicKeyCached
+var defaultPublicKeyCache = NewPublicKeyCache(DefaultPublicKeyCacheCapacity)
+
+// ParsePublicKeyCached parses a public key like PublicKeyFromBytes, using a
+// process-wide LRU cache of DefaultPublicKeyCacheCapacity entries.
+//
+// SECURITY: The returned key may be shared with other callers; it must not be
+// modified (including the slice returned by Bytes).
+func ParsePublicKeyCached(algo Algorithm, data []byte) (PublicKey, error) {
+	return defaultPublicKeyCache.Parse(algo, data)
+}
+
+// DigestVerifier is implemented by public keys that can verify a signature
+// over a precomputed digest, skipping the hash applied by PublicKey.Verify.
+// secp256r1 keys implement it for WebAuthn, whose signed digest is computed
+// by the authenticator.
+type DigestVerifier interface {
+	// VerifyDigest verifies a 64-byte r||s signature over digest
+	VerifyDigest(digest, signature []byte) bool
+}
+
+// PublicKeyCache is an LRU cache of parsed public keys, keyed by algorithm
+// and the exact encoded bytes.
+//
+// Parsing is cheap for Ed25519 but requires point decompression and curve
+// checks for secp256k1/secp256r1. Verification repeatedly sees the same
+// account and validator keys, so caching the parsed form removes that work
+// from the block processing hot path.
+//
+// Only successfully parsed keys are cached, so invalid keys cannot evict
+// valid ones without paying the full parse cost.
+//
+// INVARIANT: A cached key is the result of PublicKeyFromBytes for its
+// (algorithm, bytes) entry; different encodings of the same point (compressed
+// and uncompressed) are separate entries.
+//
+// Thread-safe. Complexity: O(1) per Parse plus the parse cost on a miss.
+type PublicKeyCache struct {
+	capacity int
+
+	mu sync.Mutex
+	// entries is keyed by algorithm, then by encoded key bytes; the two
+	// levels let lookups index with string(data) without allocating
+	entries map[Algorithm]map[string]*list.Element
+	size    int
+	lru     *list.List // front = most recently used
+
+	hits   uint64
+	misses uint64
+}
+
+// publicKeyCacheEntry is an LRU list element value
+type publicKeyCacheEntry struct {
+	algo   Algorithm
+	data   string
+	pubKey PublicKey
+}
+
+// NewPublicKeyCache creates a cache holding up to capacity parsed keys.
+// A non-positive capacity uses DefaultPublicKeyCacheCapacity.
+func NewPublicKeyCache(capacity int) *PublicKeyCache {
+	if capacity <= 0 {
+		capacity = DefaultPublicKeyCacheCapacity
+	}
+	return &PublicKeyCache{
+		capacity: capacity,
+		entries:  make(map[Algorithm]map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// Parse returns the parsed public key for algo and data, parsing it with
+// PublicKeyFromBytes on a cache miss. data is not retained.
+//
+// SECURITY: The returned key may be shared with other callers; it must not be
+// modified (including the slice returned by Bytes).
+func (c *PublicKeyCache) Parse(algo Algorithm, data []byte) (PublicKey, error) {
+	if c == nil {
+		return PublicKeyFromBytes(algo, data)
+	}
+
+	c.mu.Lock()
+	if elem, ok := c.entries[algo][string(data)]; ok {
+		c.lru.MoveToFront(elem)
+		c.hits++
+		pubKey := elem.Value.(*publicKeyCacheEntry).pubKey
+		c.mu.Unlock()
+		return pubKey, nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	// Parse outside the lock; concurrent misses for the same key both parse
+	// and the first insert wins
+	pubKey, err := PublicKeyFromBytes(algo, data)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[algo][string(data)]; ok {
+		c.lru.MoveToFront(elem)
+		return elem.Value.(*publicKeyCacheEntry).pubKey, nil
+	}
+	if c.size >= c.capacity {
+		c.evictLRU()
+	}
+
+	byData := c.entries[algo]
+	if byData == nil {
+		byData = make(map[string]*list.Element)
+		c.entries[algo] = byData
+	}
+	entry := &publicKeyCacheEntry{algo: algo, data: string(data), pubKey: pubKey}
+	byData[entry.data] = c.lru.PushFront(entry)
+	c.size++
+	return pubKey, nil
+}
+
+// Stats returns cache hit/miss statistics.
+//
+// Returns (hits, misses, hitRate) where hitRate is in range [0.0, 1.0].
+func (c *PublicKeyCache) Stats() (hits, misses uint64, hitRate float64) {
+	if c == nil {
+		return 0, 0, 0.0
+	}
+
+	c.mu.Lock()
+	hits = c.hits
+	misses = c.misses
+	c.mu.Unlock()
+
+	total := hits + misses
+	if total == 0 {
+		return hits, misses, 0.0
+	}
+	return hits, misses, float64(hits) / float64(total)
+}
+
+// Len returns the current number of cached keys.
+func (c *PublicKeyCache) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// Capacity returns the maximum cache capacity.
+func (c *PublicKeyCache) Capacity() int {
+	if c == nil {
+		return 0
+	}
+	return c.capacity
+}
+
+// Purge removes all cached keys.
+func (c *PublicKeyCache) Purge() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[Algorithm]map[string]*list.Element)
+	c.size = 0
+	c.lru = list.New()
+}
+
+// evictLRU removes the least recently used entry.
+// Must be called with c.mu held.
+func (c *PublicKeyCache) evictLRU() {
+	back := c.lru.Back()
+	if back == nil {
+		return
+	}
+	c.lru.Remove(back)
+	entry := back.Value.(*publicKeyCacheEntry)
+	delete(c.entries[entry.algo], entry.data)
+	c.size--
+}
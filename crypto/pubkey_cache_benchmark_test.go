@@ -0,0 +1,101 @@
+package crypto
+
+import (
+	"testing"
+)
+
+// benchmarkParse compares direct parsing with a warm PublicKeyCache for algo.
+func benchmarkParse(b *testing.B, algo Algorithm) {
+	data := generatePublicKeyBytes(b, algo)
+
+	b.Run("Direct", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := PublicKeyFromBytes(algo, data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Cached", func(b *testing.B) {
+		cache := NewPublicKeyCache(1024)
+		if _, err := cache.Parse(algo, data); err != nil {
+			b.Fatal(err)
+		}
+
+		b.ResetTimer()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := cache.Parse(algo, data); err != nil {
+				b.Fatal(err)
+			}
+		}
+
+		b.StopTimer()
+		_, _, hitRate := cache.Stats()
+		b.ReportMetric(hitRate, "hit_rate")
+	})
+}
+
+func BenchmarkPublicKeyCache_Ed25519(b *testing.B) {
+	benchmarkParse(b, AlgorithmEd25519)
+}
+
+func BenchmarkPublicKeyCache_Secp256k1(b *testing.B) {
+	benchmarkParse(b, AlgorithmSecp256k1)
+}
+
+func BenchmarkPublicKeyCache_Secp256r1(b *testing.B) {
+	benchmarkParse(b, AlgorithmSecp256r1)
+}
+
+// BenchmarkPublicKeyCache_BlockWorkload parses keys of a validator set that
+// is larger than the number of distinct signers per block, modeling repeated
+// verification of the same keys across blocks.
+func BenchmarkPublicKeyCache_BlockWorkload(b *testing.B) {
+	const signers = 256
+
+	keys := make([][]byte, signers)
+	for i := range keys {
+		keys[i] = generatePublicKeyBytes(b, AlgorithmSecp256r1)
+	}
+
+	b.Run("Direct", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := PublicKeyFromBytes(AlgorithmSecp256r1, keys[i%signers]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Cached", func(b *testing.B) {
+		cache := NewPublicKeyCache(signers)
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := cache.Parse(AlgorithmSecp256r1, keys[i%signers]); err != nil {
+				b.Fatal(err)
+			}
+		}
+
+		b.StopTimer()
+		_, _, hitRate := cache.Stats()
+		b.ReportMetric(hitRate, "hit_rate")
+	})
+}
+
+// BenchmarkPublicKeyCache_Parallel measures contention on a shared cache.
+func BenchmarkPublicKeyCache_Parallel(b *testing.B) {
+	data := generatePublicKeyBytes(b, AlgorithmEd25519)
+	cache := NewPublicKeyCache(1024)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := cache.Parse(AlgorithmEd25519, data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
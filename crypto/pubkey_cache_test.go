@@ -0,0 +1,232 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func generatePublicKeyBytes(t testing.TB, algo Algorithm) []byte {
+	t.Helper()
+
+	priv, err := GeneratePrivateKey(algo)
+	if err != nil {
+		t.Fatalf("GeneratePrivateKey(%s) failed: %v", algo, err)
+	}
+	return priv.PublicKey().Bytes()
+}
+
+func TestPublicKeyCache_Parse(t *testing.T) {
+	for _, algo := range []Algorithm{AlgorithmEd25519, AlgorithmSecp256k1, AlgorithmSecp256r1} {
+		t.Run(algo.String(), func(t *testing.T) {
+			cache := NewPublicKeyCache(8)
+			data := generatePublicKeyBytes(t, algo)
+
+			first, err := cache.Parse(algo, data)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			second, err := cache.Parse(algo, data)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if first != second {
+				t.Fatal("expected cached key on second parse")
+			}
+
+			direct, err := PublicKeyFromBytes(algo, data)
+			if err != nil {
+				t.Fatalf("PublicKeyFromBytes failed: %v", err)
+			}
+			if !first.Equals(direct) {
+				t.Fatal("cached key differs from directly parsed key")
+			}
+
+			hits, misses, _ := cache.Stats()
+			if hits != 1 || misses != 1 {
+				t.Fatalf("expected 1 hit and 1 miss, got %d hits and %d misses", hits, misses)
+			}
+		})
+	}
+}
+
+func TestPublicKeyCache_KeyedByAlgorithmAndBytes(t *testing.T) {
+	cache := NewPublicKeyCache(8)
+
+	// The same bytes under another algorithm are a separate entry
+	data := generatePublicKeyBytes(t, AlgorithmSecp256r1)
+	if _, err := cache.Parse(AlgorithmSecp256r1, data); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if _, err := cache.Parse(AlgorithmSecp256k1, data); err == nil {
+		// A P-256 encoding may happen to be a valid secp256k1 point
+		if cache.Len() != 2 {
+			t.Fatalf("expected 2 entries, got %d", cache.Len())
+		}
+	}
+
+	// Mutating the caller's slice does not affect the cached entry
+	key, err := cache.Parse(AlgorithmSecp256r1, data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	data[1] ^= 0xFF
+	again, err := cache.Parse(AlgorithmSecp256r1, append([]byte(nil), key.Bytes()...))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if again != key {
+		t.Fatal("expected cached key for original bytes")
+	}
+}
+
+func TestPublicKeyCache_InvalidKeysNotCached(t *testing.T) {
+	cache := NewPublicKeyCache(8)
+
+	if _, err := cache.Parse(AlgorithmEd25519, []byte("short")); err == nil {
+		t.Fatal("expected error for invalid key")
+	}
+	if _, err := cache.Parse(AlgorithmSecp256r1, make([]byte, 33)); err == nil {
+		t.Fatal("expected error for invalid point")
+	}
+	if cache.Len() != 0 {
+		t.Fatalf("expected no cached entries, got %d", cache.Len())
+	}
+}
+
+func TestPublicKeyCache_LRUEviction(t *testing.T) {
+	cache := NewPublicKeyCache(2)
+
+	keys := make([][]byte, 3)
+	for i := range keys {
+		keys[i] = generatePublicKeyBytes(t, AlgorithmEd25519)
+	}
+
+	mustParse := func(data []byte) PublicKey {
+		t.Helper()
+		key, err := cache.Parse(AlgorithmEd25519, data)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		return key
+	}
+
+	k0 := mustParse(keys[0])
+	mustParse(keys[1])
+	mustParse(keys[0]) // keys[1] is now least recently used
+	mustParse(keys[2]) // evicts keys[1]
+
+	if cache.Len() != 2 {
+		t.Fatalf("expected 2 entries, got %d", cache.Len())
+	}
+	if mustParse(keys[0]) != k0 {
+		t.Fatal("expected keys[0] to remain cached")
+	}
+
+	_, missesBefore, _ := cache.Stats()
+	mustParse(keys[1])
+	_, missesAfter, _ := cache.Stats()
+	if missesAfter != missesBefore+1 {
+		t.Fatal("expected keys[1] to have been evicted")
+	}
+
+	cache.Purge()
+	if cache.Len() != 0 {
+		t.Fatalf("expected empty cache after Purge, got %d", cache.Len())
+	}
+}
+
+func TestPublicKeyCache_Concurrent(t *testing.T) {
+	cache := NewPublicKeyCache(16)
+
+	keys := make([][]byte, 32)
+	for i := range keys {
+		keys[i] = generatePublicKeyBytes(t, AlgorithmEd25519)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				data := keys[(g+i)%len(keys)]
+				key, err := cache.Parse(AlgorithmEd25519, data)
+				if err != nil {
+					t.Errorf("Parse failed: %v", err)
+					return
+				}
+				if string(key.Bytes()) != string(data) {
+					t.Errorf("Parse returned wrong key")
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if cache.Len() > cache.Capacity() {
+		t.Fatalf("cache exceeded capacity: %d > %d", cache.Len(), cache.Capacity())
+	}
+}
+
+func TestPublicKeyCache_NilCacheParsesDirectly(t *testing.T) {
+	var cache *PublicKeyCache
+	data := generatePublicKeyBytes(t, AlgorithmEd25519)
+
+	if _, err := cache.Parse(AlgorithmEd25519, data); err != nil {
+		t.Fatalf("Parse on nil cache failed: %v", err)
+	}
+	if cache.Len() != 0 || cache.Capacity() != 0 {
+		t.Fatal("expected nil cache to report no entries")
+	}
+}
+
+func TestSecp256r1PublicKey_VerifyDigest(t *testing.T) {
+	priv, err := GeneratePrivateKey(AlgorithmSecp256r1)
+	if err != nil {
+		t.Fatalf("GeneratePrivateKey failed: %v", err)
+	}
+	data := []byte("message")
+	sig, err := priv.Sign(data)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	key, err := ParsePublicKeyCached(AlgorithmSecp256r1, priv.PublicKey().Bytes())
+	if err != nil {
+		t.Fatalf("ParsePublicKeyCached failed: %v", err)
+	}
+	verifier, ok := key.(DigestVerifier)
+	if !ok {
+		t.Fatal("secp256r1 key does not implement DigestVerifier")
+	}
+
+	// Sign hashes data with SHA-256, so the signature is over its digest
+	digest := sha256.Sum256(data)
+	if !verifier.VerifyDigest(digest[:], sig) {
+		t.Fatal("VerifyDigest rejected a valid signature")
+	}
+	if verifier.VerifyDigest(data, sig) {
+		t.Fatal("VerifyDigest accepted a signature over a different digest")
+	}
+	if verifier.VerifyDigest(digest[:], sig[:63]) {
+		t.Fatal("VerifyDigest accepted a truncated signature")
+	}
+}
+
+func ExamplePublicKeyCache() {
+	cache := NewPublicKeyCache(1024)
+
+	data := make([]byte, 32)
+	for i := 0; i < 3; i++ {
+		if _, err := cache.Parse(AlgorithmEd25519, data); err != nil {
+			panic(err)
+		}
+	}
+
+	hits, misses, _ := cache.Stats()
+	fmt.Println(hits, misses)
+	// Output: 2 1
+}
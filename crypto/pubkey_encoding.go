@@ -0,0 +1,157 @@
+package crypto
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PubKeyEncoding identifies the byte encoding used in a public key string.
+type PubKeyEncoding string
+
+const (
+	// PubKeyEncodingHex is lowercase hexadecimal.
+	PubKeyEncodingHex PubKeyEncoding = "hex"
+
+	// PubKeyEncodingBase64 is standard padded Base64, matching PublicKey.String().
+	PubKeyEncodingBase64 PubKeyEncoding = "base64"
+
+	// PubKeyEncodingBech32 is BIP-173 bech32 with human-readable part PubKeyBech32HRP.
+	PubKeyEncodingBech32 PubKeyEncoding = "bech32"
+)
+
+// PubKeyBech32HRP is the bech32 human-readable part for public key strings.
+const PubKeyBech32HRP = "punnetpub"
+
+// pubKeyStringSeparator separates the algorithm, encoding and payload.
+const pubKeyStringSeparator = ":"
+
+// IsValid returns true if the encoding is recognized.
+func (e PubKeyEncoding) IsValid() bool {
+	switch e {
+	case PubKeyEncodingHex, PubKeyEncodingBase64, PubKeyEncodingBech32:
+		return true
+	default:
+		return false
+	}
+}
+
+// PubKeyToString encodes a public key in the self-describing string format
+//
+//	<algorithm>:<encoding>:<payload>
+//
+// e.g. "ed25519:hex:3b6a27bc...". The prefix lets PubKeyFromString recover the
+// key without out-of-band knowledge of its algorithm or encoding.
+//
+// Complexity: O(n) where n is key length.
+func PubKeyToString(key PublicKey, enc PubKeyEncoding) (string, error) {
+	if key == nil {
+		return "", fmt.Errorf("%w: public key is nil", ErrInvalidPubKeyString)
+	}
+
+	var payload string
+	switch enc {
+	case PubKeyEncodingHex:
+		payload = hex.EncodeToString(key.Bytes())
+	case PubKeyEncodingBase64:
+		payload = base64.StdEncoding.EncodeToString(key.Bytes())
+	case PubKeyEncodingBech32:
+		var err error
+		payload, err = bech32Encode(PubKeyBech32HRP, key.Bytes())
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidPubKeyString, err)
+		}
+	default:
+		return "", fmt.Errorf("%w: unknown encoding %q", ErrInvalidPubKeyString, enc)
+	}
+
+	return string(key.Algorithm()) + pubKeyStringSeparator + string(enc) + pubKeyStringSeparator + payload, nil
+}
+
+// PubKeyFromString decodes a public key produced by PubKeyToString.
+//
+// SECURITY: The decoded bytes are validated by PublicKeyFromBytes, so the
+// result is a well-formed key for its algorithm (e.g. an on-curve point).
+//
+// Complexity: O(n) where n is string length.
+func PubKeyFromString(s string) (PublicKey, error) {
+	parts := strings.SplitN(s, pubKeyStringSeparator, 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: expected <algorithm>:<encoding>:<payload>", ErrInvalidPubKeyString)
+	}
+
+	algo := Algorithm(parts[0])
+	if !algo.IsValid() {
+		return nil, fmt.Errorf("%w: %w: %q", ErrInvalidPubKeyString, ErrInvalidAlgorithm, parts[0])
+	}
+
+	var keyBytes []byte
+	var err error
+	switch enc := PubKeyEncoding(parts[1]); enc {
+	case PubKeyEncodingHex:
+		keyBytes, err = hex.DecodeString(parts[2])
+	case PubKeyEncodingBase64:
+		keyBytes, err = base64.StdEncoding.Strict().DecodeString(parts[2])
+	case PubKeyEncodingBech32:
+		var hrp string
+		hrp, keyBytes, err = bech32Decode(parts[2])
+		if err == nil && hrp != PubKeyBech32HRP {
+			err = fmt.Errorf("unexpected human-readable part %q", hrp)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown encoding %q", ErrInvalidPubKeyString, parts[1])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPubKeyString, err)
+	}
+
+	key, err := PublicKeyFromBytes(algo, keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPubKeyString, err)
+	}
+	return key, nil
+}
+
+// marshalPublicKeyJSON encodes a public key as a JSON string in the Base64
+// PubKeyToString format. Shared by the MarshalJSON methods of all key types.
+func marshalPublicKeyJSON(key PublicKey) ([]byte, error) {
+	s, err := PubKeyToString(key, PubKeyEncodingBase64)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(s)
+}
+
+// signerJSON is the JSON representation of a Signer.
+type signerJSON struct {
+	Algorithm Algorithm `json:"algorithm"`
+	PubKey    PublicKey `json:"pub_key"`
+}
+
+// MarshalJSON implements json.Marshaler.
+//
+// SECURITY: Only the algorithm and public key are encoded. The private key is
+// never serialized, so logging or persisting a signer cannot leak it.
+func (s *BasicSigner) MarshalJSON() ([]byte, error) {
+	return json.Marshal(signerJSON{
+		Algorithm: s.Algorithm(),
+		PubKey:    s.PublicKey(),
+	})
+}
+
+// MarshalJSON implements json.Marshaler.
+func (k *ed25519PublicKey) MarshalJSON() ([]byte, error) {
+	return marshalPublicKeyJSON(k)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (k *secp256k1PublicKey) MarshalJSON() ([]byte, error) {
+	return marshalPublicKeyJSON(k)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (k *secp256r1PublicKey) MarshalJSON() ([]byte, error) {
+	return marshalPublicKeyJSON(k)
+}
@@ -0,0 +1,208 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPubKeyString_RoundTrip(t *testing.T) {
+	algos := []Algorithm{AlgorithmEd25519, AlgorithmSecp256k1, AlgorithmSecp256r1}
+	encodings := []PubKeyEncoding{PubKeyEncodingHex, PubKeyEncodingBase64, PubKeyEncodingBech32}
+
+	for _, algo := range algos {
+		priv, err := GeneratePrivateKey(algo)
+		require.NoError(t, err)
+		pub := priv.PublicKey()
+
+		for _, enc := range encodings {
+			t.Run(string(algo)+"/"+string(enc), func(t *testing.T) {
+				s, err := PubKeyToString(pub, enc)
+				require.NoError(t, err)
+				assert.True(t, strings.HasPrefix(s, string(algo)+":"+string(enc)+":"), s)
+
+				decoded, err := PubKeyFromString(s)
+				require.NoError(t, err)
+				assert.True(t, pub.Equals(decoded))
+			})
+		}
+	}
+}
+
+func TestPubKeyString_Format(t *testing.T) {
+	data := make([]byte, 32)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	pub, err := PublicKeyFromBytes(AlgorithmEd25519, data)
+	require.NoError(t, err)
+
+	s, err := PubKeyToString(pub, PubKeyEncodingHex)
+	require.NoError(t, err)
+	assert.Equal(t, "ed25519:hex:000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", s)
+
+	s, err = PubKeyToString(pub, PubKeyEncodingBase64)
+	require.NoError(t, err)
+	assert.Equal(t, "ed25519:base64:"+pub.String(), s)
+
+	s, err = PubKeyToString(pub, PubKeyEncodingBech32)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(s, "ed25519:bech32:"+PubKeyBech32HRP+"1"), s)
+}
+
+func TestPubKeyFromString_Invalid(t *testing.T) {
+	priv, err := GeneratePrivateKey(AlgorithmEd25519)
+	require.NoError(t, err)
+	pub := priv.PublicKey()
+	valid, err := PubKeyToString(pub, PubKeyEncodingBech32)
+	require.NoError(t, err)
+
+	// Flip the last checksum character.
+	last := valid[len(valid)-1]
+	corrupted := valid[:len(valid)-1] + string(map[bool]byte{true: 'q', false: 'p'}[last != 'q'])
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty", ""},
+		{"missing parts", "ed25519:hex"},
+		{"unknown algorithm", "rsa:hex:00"},
+		{"unknown encoding", "ed25519:base58:00"},
+		{"bad hex", "ed25519:hex:zz"},
+		{"wrong key size", "ed25519:hex:0001"},
+		{"unpadded base64", "ed25519:base64:" + strings.TrimRight(pub.String(), "=")},
+		// 0xff bytes encode to '_' in URL base64 and '/' in standard base64.
+		{"url base64", "ed25519:base64:" + base64.URLEncoding.EncodeToString(bytes.Repeat([]byte{0xff}, 32))},
+		{"bech32 checksum", corrupted},
+		{"bech32 mixed case", "ed25519:bech32:" + strings.ToUpper(valid[len("ed25519:bech32:"):len("ed25519:bech32:")+3]) + valid[len("ed25519:bech32:")+3:]},
+		{"off-curve secp256r1", "secp256r1:hex:02" + strings.Repeat("ff", 32)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := PubKeyFromString(tt.input)
+			assert.ErrorIs(t, err, ErrInvalidPubKeyString)
+		})
+	}
+}
+
+func TestPubKeyToString_Invalid(t *testing.T) {
+	_, err := PubKeyToString(nil, PubKeyEncodingHex)
+	assert.ErrorIs(t, err, ErrInvalidPubKeyString)
+
+	priv, err := GeneratePrivateKey(AlgorithmEd25519)
+	require.NoError(t, err)
+	_, err = PubKeyToString(priv.PublicKey(), PubKeyEncoding("base58"))
+	assert.ErrorIs(t, err, ErrInvalidPubKeyString)
+}
+
+func TestBech32_BIP173Vectors(t *testing.T) {
+	valid := []string{
+		"A12UEL5L",
+		"a12uel5l",
+		"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs",
+		"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+		"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w",
+	}
+	for _, s := range valid {
+		_, _, err := bech32Decode(s)
+		// Some BIP-173 vectors have non-byte-aligned data; only the checksum
+		// and structure are under test here.
+		if err != nil {
+			assert.Contains(t, err.Error(), "padding", s)
+		}
+	}
+
+	invalid := []string{
+		"pzry9x0s0muk",   // no separator
+		"1pzry9x0s0muk",  // empty hrp
+		"x1b4n0q5v",      // invalid data character
+		"li1dgmt3",       // checksum too short
+		"A1G7SGD8",       // checksum calculated with uppercase hrp
+		"10a06t8",        // empty hrp
+		"1qzzfhee",       // empty hrp
+		"a12UEL5L",       // mixed case
+		"abc1rzg",        // too short
+		"qyrz8wqd2c9m1z", // invalid checksum
+	}
+	for _, s := range invalid {
+		_, _, err := bech32Decode(s)
+		assert.Error(t, err, s)
+	}
+
+	data := []byte{0x00, 0x01, 0xfe, 0xff}
+	encoded, err := bech32Encode("test", data)
+	require.NoError(t, err)
+	hrp, decoded, err := bech32Decode(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, "test", hrp)
+	assert.Equal(t, data, decoded)
+}
+
+func TestSerializablePublicKey_UnmarshalString(t *testing.T) {
+	priv, err := GeneratePrivateKey(AlgorithmSecp256k1)
+	require.NoError(t, err)
+	pub := priv.PublicKey()
+
+	type config struct {
+		Key      SerializablePublicKey  `json:"key"`
+		Optional *SerializablePublicKey `json:"optional"`
+	}
+
+	// Any encoding is accepted on input.
+	for _, encoding := range []PubKeyEncoding{PubKeyEncodingBase64, PubKeyEncodingHex, PubKeyEncodingBech32} {
+		str, err := PubKeyToString(pub, encoding)
+		require.NoError(t, err)
+
+		var decoded config
+		require.NoError(t, json.Unmarshal([]byte(`{"key":"`+str+`","optional":null}`), &decoded))
+		require.NotNil(t, decoded.Key.PublicKey())
+		assert.True(t, pub.Equals(decoded.Key.PublicKey()))
+		assert.Nil(t, decoded.Optional)
+	}
+
+	var bad SerializablePublicKey
+	assert.ErrorIs(t, json.Unmarshal([]byte(`"ed25519:hex:00"`), &bad), ErrInvalidPubKeyString)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`"not a key"`), &bad), ErrInvalidPubKeyString)
+}
+
+func TestPublicKey_MarshalJSON(t *testing.T) {
+	for _, algo := range []Algorithm{AlgorithmEd25519, AlgorithmSecp256k1, AlgorithmSecp256r1} {
+		t.Run(string(algo), func(t *testing.T) {
+			priv, err := GeneratePrivateKey(algo)
+			require.NoError(t, err)
+			pub := priv.PublicKey()
+
+			// Marshaling through the interface uses the string format.
+			data, err := json.Marshal(pub)
+			require.NoError(t, err)
+
+			var wrapped SerializablePublicKey
+			require.NoError(t, json.Unmarshal(data, &wrapped))
+			assert.True(t, pub.Equals(wrapped.PublicKey()))
+		})
+	}
+}
+
+func TestSigner_MarshalJSON(t *testing.T) {
+	priv, err := GeneratePrivateKey(AlgorithmEd25519)
+	require.NoError(t, err)
+	signer := NewSigner(priv)
+
+	data, err := json.Marshal(signer)
+	require.NoError(t, err)
+
+	pubStr, err := PubKeyToString(priv.PublicKey(), PubKeyEncodingBase64)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"algorithm":"ed25519","pub_key":"`+pubStr+`"}`, string(data))
+
+	// SECURITY: private key material must never appear in the output.
+	assert.NotContains(t, string(data), base64.StdEncoding.EncodeToString(priv.Bytes()))
+	assert.NotContains(t, string(data), base64.StdEncoding.EncodeToString(priv.Bytes()[:32]))
+}
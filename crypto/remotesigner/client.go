@@ -0,0 +1,249 @@
+package remotesigner
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// maxResponseBodySize bounds responses read by the client.
+const maxResponseBodySize = 1 << 20
+
+// defaultClientTimeout bounds a single call when the caller's context has
+// no deadline.
+const defaultClientTimeout = 10 * time.Second
+
+// Client talks to a Server.
+// Thread-safe.
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// ClientOption configures a Client.
+type ClientOption func(*clientOptions)
+
+// clientOptions collects the ClientOptions of NewClient
+type clientOptions struct {
+	dialOptions []grpc.DialOption
+}
+
+// WithDialOptions appends options to those the client dials the server
+// with, e.g. keepalive parameters or interceptors.
+func WithDialOptions(opts ...grpc.DialOption) ClientOption {
+	return func(o *clientOptions) {
+		o.dialOptions = append(o.dialOptions, opts...)
+	}
+}
+
+// NewClient creates a client for the server at target, a gRPC target such
+// as "signer:8443". The connection is established lazily; Close releases it.
+//
+// tlsConfig must carry the client certificate and the CA that signed the
+// server certificate; see ClientTLSConfig.
+func NewClient(target string, tlsConfig *tls.Config, opts ...ClientOption) (*Client, error) {
+	if target == "" {
+		return nil, fmt.Errorf("server target cannot be empty")
+	}
+	if tlsConfig == nil {
+		return nil, fmt.Errorf("TLS config cannot be nil")
+	}
+
+	var o clientOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	dialOptions := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
+		grpc.WithDefaultCallOptions(
+			grpc.ForceCodec(jsonCodec{}),
+			grpc.MaxCallRecvMsgSize(maxResponseBodySize),
+		),
+	}, o.dialOptions...)
+
+	conn, err := grpc.Dial(target, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", target, err)
+	}
+	return &Client{conn: conn}, nil
+}
+
+// Close closes the connection to the server.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// ListKeys returns the names of keys this client may use.
+func (c *Client) ListKeys(ctx context.Context) ([]string, error) {
+	var resp listKeysResponse
+	if err := c.invoke(ctx, methodListKeys, &listKeysRequest{}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Keys, nil
+}
+
+// GetKey returns a Signer for the named remote key.
+func (c *Client) GetKey(ctx context.Context, name string) (*Signer, error) {
+	var resp keyResponse
+	if err := c.invoke(ctx, methodGetKey, &getKeyRequest{Name: name}, &resp); err != nil {
+		return nil, err
+	}
+	pubKey, err := crypto.PublicKeyFromBytes(resp.Algorithm, resp.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return &Signer{client: c, name: name, pubKey: pubKey}, nil
+}
+
+// SignSignDoc asks the server to sign signDoc with the named key.
+// The server enforces the key's message type policy on signDoc's content.
+//
+// POSTCONDITION: The returned signature verifies against the key's public key
+// over signDoc.GetSignBytes().
+func (c *Client) SignSignDoc(ctx context.Context, name string, signDoc *types.SignDoc) (*crypto.Signature, error) {
+	if signDoc == nil {
+		return nil, fmt.Errorf("%w: sign doc cannot be nil", ErrInvalidRequest)
+	}
+	docJSON, err := signDoc.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	signBytes, err := signDoc.GetSignBytes()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+
+	var resp signResponse
+	if err := c.invoke(ctx, methodSignDoc, &signDocRequest{Key: name, SignDoc: docJSON}, &resp); err != nil {
+		return nil, err
+	}
+	return verifyResponse(&resp, signBytes)
+}
+
+// SignBytes asks the server to sign data with the named key.
+// Requires AllowRawBytes in the key's policy.
+func (c *Client) SignBytes(ctx context.Context, name string, data []byte) (*crypto.Signature, error) {
+	var resp signResponse
+	if err := c.invoke(ctx, methodSignBytes, &signBytesRequest{Key: name, Data: data}, &resp); err != nil {
+		return nil, err
+	}
+	return verifyResponse(&resp, data)
+}
+
+// verifyResponse checks that the server's signature is valid for data.
+//
+// SECURITY: A compromised or misconfigured server must not be able to hand
+// the node a signature that would be rejected on chain (or that belongs to a
+// different key).
+func verifyResponse(resp *signResponse, data []byte) (*crypto.Signature, error) {
+	pubKey, err := crypto.PublicKeyFromBytes(resp.Algorithm, resp.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if !pubKey.Verify(data, resp.Signature) {
+		return nil, fmt.Errorf("%w: signature does not verify", ErrInvalidResponse)
+	}
+	return &crypto.Signature{
+		PubKey:    resp.PubKey,
+		Signature: resp.Signature,
+		Algorithm: resp.Algorithm,
+	}, nil
+}
+
+// invoke calls method with in and decodes the response into out.
+func (c *Client) invoke(ctx context.Context, method string, in, out any) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultClientTimeout)
+		defer cancel()
+	}
+
+	if err := c.conn.Invoke(ctx, "/"+serviceName+"/"+method, in, out); err != nil {
+		return fromStatus(err)
+	}
+	return nil
+}
+
+// fromStatus maps a gRPC status error back to its sentinel error.
+func fromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrRemote, err)
+	}
+	for _, sc := range statusCodes {
+		if sc.code == st.Code() {
+			// The server's message already includes the sentinel text; avoid repeating it.
+			msg := strings.TrimPrefix(st.Message(), sc.err.Error()+": ")
+			return fmt.Errorf("%w: %s", sc.err, msg)
+		}
+	}
+	// Transport failures (e.g. Unavailable, DeadlineExceeded)
+	return fmt.Errorf("%w: %s: %s", ErrRemote, st.Code(), st.Message())
+}
+
+// Signer is a crypto.Signer backed by a key held on a remote Server.
+// Thread-safe.
+type Signer struct {
+	client *Client
+	name   string
+	pubKey crypto.PublicKey
+}
+
+var _ crypto.Signer = (*Signer)(nil)
+
+// Name returns the remote key name.
+func (s *Signer) Name() string {
+	return s.name
+}
+
+// PublicKey implements crypto.Signer.
+func (s *Signer) PublicKey() crypto.PublicKey {
+	return s.pubKey
+}
+
+// Algorithm implements crypto.Signer.
+func (s *Signer) Algorithm() crypto.Algorithm {
+	return s.pubKey.Algorithm()
+}
+
+// Sign implements crypto.Signer by signing raw bytes remotely.
+// Requires AllowRawBytes in the key's policy; prefer SignSignDoc so the
+// server can enforce message type policy.
+func (s *Signer) Sign(data []byte) ([]byte, error) {
+	return s.SignContext(context.Background(), data)
+}
+
+// SignContext is Sign with a caller-supplied context.
+func (s *Signer) SignContext(ctx context.Context, data []byte) ([]byte, error) {
+	sig, err := s.client.SignBytes(ctx, s.name, data)
+	if err != nil {
+		return nil, err
+	}
+	return s.checkKey(sig)
+}
+
+// SignSignDoc signs signDoc with this key.
+func (s *Signer) SignSignDoc(ctx context.Context, signDoc *types.SignDoc) ([]byte, error) {
+	sig, err := s.client.SignSignDoc(ctx, s.name, signDoc)
+	if err != nil {
+		return nil, err
+	}
+	return s.checkKey(sig)
+}
+
+// checkKey rejects signatures produced by a key other than the one this
+// Signer was created for (e.g. if the server's key was rotated).
+func (s *Signer) checkKey(sig *crypto.Signature) ([]byte, error) {
+	if !crypto.PublicKeyBytesEqual(sig.Algorithm, sig.PubKey, s.pubKey.Algorithm(), s.pubKey.Bytes()) {
+		return nil, fmt.Errorf("%w: server signed with a different key", ErrInvalidResponse)
+	}
+	return sig.Signature, nil
+}
@@ -0,0 +1,124 @@
+package remotesigner
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Policy restricts how a key held by the Server may be used.
+//
+// Keys without a Policy cannot be used at all: an operator must opt each key
+// in explicitly.
+type Policy struct {
+	// AllowedClients lists client certificate common names permitted to use
+	// the key. Empty means any client with a verified certificate.
+	AllowedClients []string `json:"allowed_clients,omitempty"`
+
+	// AllowedMessageTypes lists message type URLs (e.g. "/punnet.bank.v1.MsgSend")
+	// that a SignDoc may contain. Empty means any message type.
+	AllowedMessageTypes []string `json:"allowed_message_types,omitempty"`
+
+	// AllowRawBytes permits signing arbitrary bytes via SignBytes.
+	//
+	// SECURITY: Raw signing bypasses AllowedMessageTypes because the server
+	// cannot see what is being signed. Enable only for keys that must sign
+	// non-SignDoc payloads (e.g. consensus votes).
+	AllowRawBytes bool `json:"allow_raw_bytes,omitempty"`
+
+	// RateLimit is the maximum number of sign requests per RateWindow.
+	// Zero means unlimited.
+	RateLimit int `json:"rate_limit,omitempty"`
+
+	// RateWindow is the rate limit window. Defaults to one minute when
+	// RateLimit is set.
+	RateWindow time.Duration `json:"rate_window,omitempty"`
+}
+
+// ValidateBasic checks the policy for configuration errors.
+func (p Policy) ValidateBasic() error {
+	if p.RateLimit < 0 {
+		return fmt.Errorf("rate limit cannot be negative: %d", p.RateLimit)
+	}
+	if p.RateWindow < 0 {
+		return fmt.Errorf("rate window cannot be negative: %s", p.RateWindow)
+	}
+	return nil
+}
+
+// allowsClient reports whether the client may use the key.
+func (p Policy) allowsClient(client string) bool {
+	if len(p.AllowedClients) == 0 {
+		return true
+	}
+	for _, c := range p.AllowedClients {
+		if c == client {
+			return true
+		}
+	}
+	return false
+}
+
+// checkMessageTypes returns an error naming the first disallowed message type.
+func (p Policy) checkMessageTypes(types []string) error {
+	if len(p.AllowedMessageTypes) == 0 {
+		return nil
+	}
+	allowed := make(map[string]bool, len(p.AllowedMessageTypes))
+	for _, t := range p.AllowedMessageTypes {
+		allowed[t] = true
+	}
+	for _, t := range types {
+		if !allowed[t] {
+			return fmt.Errorf("%w: message type %s not allowed", ErrPolicyDenied, t)
+		}
+	}
+	return nil
+}
+
+// window returns the effective rate window.
+func (p Policy) window() time.Duration {
+	if p.RateWindow > 0 {
+		return p.RateWindow
+	}
+	return time.Minute
+}
+
+// rateLimiter enforces per-key fixed-window rate limits.
+// Thread-safe.
+type rateLimiter struct {
+	mu      sync.Mutex
+	windows map[string]*rateWindow
+}
+
+// rateWindow tracks requests in the current window for one key.
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{windows: make(map[string]*rateWindow)}
+}
+
+// allow records a request for key at now and reports whether it is within limit.
+// Complexity: O(1).
+func (r *rateLimiter) allow(key string, limit int, window time.Duration, now time.Time) bool {
+	if limit <= 0 {
+		return true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w, ok := r.windows[key]
+	if !ok || now.Sub(w.start) >= window || now.Before(w.start) {
+		w = &rateWindow{start: now}
+		r.windows[key] = w
+	}
+	if w.count >= limit {
+		return false
+	}
+	w.count++
+	return true
+}
@@ -0,0 +1,147 @@
+// Package remotesigner isolates signing keys in a separate process.
+//
+// A Server wraps a crypto.Keyring and exposes it as a gRPC service over
+// mutual TLS. Every call is authenticated by client certificate, checked
+// against a per-key Policy (allowed clients, allowed message types, rate
+// limits) and recorded through an AuditLogger. A Client connects to the
+// server and hands out crypto.Signer implementations whose private keys
+// never enter the node process.
+//
+// The service's messages are the plain structs of this file, carried by a
+// JSON codec, so the service needs no generated protobuf code.
+//
+// SECURITY: The server requires a verified client certificate on every
+// call. Plaintext connections and TLS without a client certificate are
+// rejected.
+package remotesigner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+)
+
+// Service and method names.
+const (
+	serviceName     = "punnet.remotesigner.v1.RemoteSigner"
+	methodListKeys  = "ListKeys"
+	methodGetKey    = "GetKey"
+	methodSignDoc   = "SignDoc"
+	methodSignBytes = "SignBytes"
+)
+
+// maxRequestBodySize bounds request messages accepted by the server.
+// Sized for a SignDoc with MaxMessagesPerSignDoc maximum-size messages.
+const maxRequestBodySize = 8 << 20
+
+// Errors returned by the client and server.
+var (
+	// ErrUnauthenticated indicates the request carried no verified client certificate.
+	ErrUnauthenticated = errors.New("client certificate required")
+
+	// ErrPolicyDenied indicates the request was rejected by the key's policy.
+	ErrPolicyDenied = errors.New("signing policy denied request")
+
+	// ErrRateLimited indicates the key's rate limit was exceeded.
+	ErrRateLimited = errors.New("signing rate limit exceeded")
+
+	// ErrInvalidRequest indicates a malformed request.
+	ErrInvalidRequest = errors.New("invalid remote signer request")
+
+	// ErrInvalidResponse indicates the server returned a malformed or
+	// unverifiable response (e.g. a signature that does not verify).
+	ErrInvalidResponse = errors.New("invalid remote signer response")
+
+	// ErrRemote indicates an unclassified server-side failure.
+	ErrRemote = errors.New("remote signer error")
+)
+
+// statusCodes maps the sentinel errors above one-to-one onto gRPC status
+// codes, so clients can use errors.Is across the wire.
+var statusCodes = []struct {
+	err  error
+	code codes.Code
+}{
+	{ErrUnauthenticated, codes.Unauthenticated},
+	{ErrPolicyDenied, codes.PermissionDenied},
+	{ErrRateLimited, codes.ResourceExhausted},
+	{ErrInvalidRequest, codes.InvalidArgument},
+	{crypto.ErrKeyNotFound, codes.NotFound},
+	{ErrRemote, codes.Internal},
+}
+
+// jsonCodec is the gRPC codec of the service's messages.
+type jsonCodec struct{}
+
+// Name implements encoding.Codec.
+func (jsonCodec) Name() string {
+	return "json"
+}
+
+// Marshal implements encoding.Codec.
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal implements encoding.Codec, rejecting unknown fields and trailing data.
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return fmt.Errorf("trailing data after message")
+	}
+	return nil
+}
+
+// listKeysRequest is the request of ListKeys.
+type listKeysRequest struct{}
+
+// getKeyRequest is the request of GetKey.
+type getKeyRequest struct {
+	Name string `json:"name"`
+}
+
+// keyResponse describes a key held by the server; it is the response of GetKey.
+type keyResponse struct {
+	Name      string           `json:"name"`
+	Algorithm crypto.Algorithm `json:"algorithm"`
+	PubKey    []byte           `json:"pub_key"`
+}
+
+// listKeysResponse is the response of ListKeys.
+type listKeysResponse struct {
+	Keys []string `json:"keys"`
+}
+
+// signDocRequest is the request of SignDoc.
+//
+// The server parses the SignDoc, enforces policy on its content and signs
+// its sign bytes, so policy decisions and signatures always cover the same
+// document.
+type signDocRequest struct {
+	Key     string          `json:"key"`
+	SignDoc json.RawMessage `json:"sign_doc"`
+}
+
+// signBytesRequest is the request of SignBytes.
+// Only permitted for keys whose policy sets AllowRawBytes.
+type signBytesRequest struct {
+	Key  string `json:"key"`
+	Data []byte `json:"data"`
+}
+
+// signResponse is the response of SignDoc and SignBytes.
+type signResponse struct {
+	Algorithm crypto.Algorithm `json:"algorithm"`
+	PubKey    []byte           `json:"pub_key"`
+	Signature []byte           `json:"signature"`
+}
@@ -0,0 +1,378 @@
+package remotesigner
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+const (
+	testMsgSend     = "/punnet.bank.v1.MsgSend"
+	testMsgDelegate = "/punnet.staking.v1.MsgDelegate"
+)
+
+// testCA issues certificates for tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &testCA{cert: cert, key: key, pool: pool}
+}
+
+func (ca *testCA) issue(t *testing.T, commonName string, usage x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// testEnv is a running server with a keyring and a CA for client certificates.
+type testEnv struct {
+	ca      *testCA
+	addr    string
+	keyring crypto.Keyring
+	signer  crypto.Signer
+
+	mu      sync.Mutex
+	records []AuditRecord
+	now     time.Time
+}
+
+func newTestEnv(t *testing.T, policies map[string]Policy) *testEnv {
+	t.Helper()
+	env := &testEnv{
+		ca:      newTestCA(t),
+		keyring: crypto.NewKeyring(crypto.NewMemoryStore()),
+		now:     time.Unix(1700000000, 0),
+	}
+	var err error
+	env.signer, err = env.keyring.NewKey("validator", crypto.AlgorithmEd25519)
+	require.NoError(t, err)
+
+	srv, err := NewServer(env.keyring, policies,
+		WithAuditLogger(AuditLoggerFunc(func(r AuditRecord) {
+			env.mu.Lock()
+			defer env.mu.Unlock()
+			env.records = append(env.records, r)
+		})),
+		WithClock(func() time.Time {
+			env.mu.Lock()
+			defer env.mu.Unlock()
+			return env.now
+		}),
+	)
+	require.NoError(t, err)
+
+	tlsConfig, err := ServerTLSConfig(env.ca.issue(t, "signer", x509.ExtKeyUsageServerAuth), env.ca.pool)
+	require.NoError(t, err)
+
+	grpcServer, err := srv.GRPCServer(tlsConfig)
+	require.NoError(t, err)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = grpcServer.Serve(lis) }()
+	t.Cleanup(grpcServer.Stop)
+	env.addr = lis.Addr().String()
+	return env
+}
+
+func (env *testEnv) client(t *testing.T, commonName string) *Client {
+	t.Helper()
+	tlsConfig, err := ClientTLSConfig(env.ca.issue(t, commonName, x509.ExtKeyUsageClientAuth), env.ca.pool, "")
+	require.NoError(t, err)
+	c, err := NewClient(env.addr, tlsConfig)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func (env *testEnv) advance(d time.Duration) {
+	env.mu.Lock()
+	defer env.mu.Unlock()
+	env.now = env.now.Add(d)
+}
+
+func (env *testEnv) auditRecords() []AuditRecord {
+	env.mu.Lock()
+	defer env.mu.Unlock()
+	return append([]AuditRecord(nil), env.records...)
+}
+
+func testSignDoc(t testing.TB, msgTypes ...string) *types.SignDoc {
+	t.Helper()
+
+	sd, err := types.NewSignDoc("punnet-1", 1, "alice", 1, "")
+	require.NoError(t, err)
+	for _, msgType := range msgTypes {
+		sd.AddMessage(msgType, json.RawMessage(`{"amount":"100"}`))
+	}
+	return sd
+}
+
+func TestRemoteSigner_SignSignDoc(t *testing.T) {
+	env := newTestEnv(t, map[string]Policy{
+		"validator": {AllowedClients: []string{"node-1"}, AllowedMessageTypes: []string{testMsgSend}},
+	})
+	ctx := context.Background()
+	c := env.client(t, "node-1")
+
+	keys, err := c.ListKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"validator"}, keys)
+
+	remote, err := c.GetKey(ctx, "validator")
+	require.NoError(t, err)
+	assert.True(t, remote.PublicKey().Equals(env.signer.PublicKey()))
+	assert.Equal(t, crypto.AlgorithmEd25519, remote.Algorithm())
+
+	sd := testSignDoc(t, testMsgSend)
+	sig, err := remote.SignSignDoc(ctx, sd)
+	require.NoError(t, err)
+
+	signBytes, err := sd.GetSignBytes()
+	require.NoError(t, err)
+	assert.True(t, env.signer.PublicKey().Verify(signBytes, sig))
+
+	records := env.auditRecords()
+	require.NotEmpty(t, records)
+	last := records[len(records)-1]
+	assert.Equal(t, OperationSignDoc, last.Operation)
+	assert.Equal(t, "node-1", last.Client)
+	assert.Equal(t, "validator", last.Key)
+	assert.Equal(t, "punnet-1", last.ChainID)
+	assert.Equal(t, []string{testMsgSend}, last.MessageTypes)
+	assert.Len(t, last.DataHash, 32)
+	assert.True(t, last.Allowed)
+}
+
+func TestRemoteSigner_PolicyDenied(t *testing.T) {
+	env := newTestEnv(t, map[string]Policy{
+		"validator": {AllowedClients: []string{"node-1"}, AllowedMessageTypes: []string{testMsgSend}},
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		client  string
+		key     string
+		doc     *types.SignDoc
+		wantErr error
+	}{
+		{"disallowed message type", "node-1", "validator", testSignDoc(t, testMsgSend, testMsgDelegate), ErrPolicyDenied},
+		{"disallowed client", "node-2", "validator", testSignDoc(t, testMsgSend), ErrPolicyDenied},
+		{"unknown key", "node-1", "missing", testSignDoc(t, testMsgSend), crypto.ErrKeyNotFound},
+		{"invalid sign doc", "node-1", "validator", testSignDoc(t), ErrInvalidRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := env.client(t, tt.client).SignSignDoc(ctx, tt.key, tt.doc)
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			records := env.auditRecords()
+			last := records[len(records)-1]
+			assert.False(t, last.Allowed)
+			assert.NotEmpty(t, last.Error)
+		})
+	}
+}
+
+func TestRemoteSigner_KeysWithoutPolicy(t *testing.T) {
+	env := newTestEnv(t, nil)
+	ctx := context.Background()
+	c := env.client(t, "node-1")
+
+	keys, err := c.ListKeys(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	// The key exists in the keyring but is not exposed without a policy.
+	_, err = c.GetKey(ctx, "validator")
+	assert.ErrorIs(t, err, crypto.ErrKeyNotFound)
+	_, err = c.SignSignDoc(ctx, "validator", testSignDoc(t, testMsgSend))
+	assert.ErrorIs(t, err, crypto.ErrKeyNotFound)
+}
+
+func TestRemoteSigner_ListKeysFiltersByClient(t *testing.T) {
+	env := newTestEnv(t, map[string]Policy{
+		"validator": {AllowedClients: []string{"node-1"}},
+	})
+	keys, err := env.client(t, "node-2").ListKeys(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestRemoteSigner_RateLimit(t *testing.T) {
+	env := newTestEnv(t, map[string]Policy{
+		"validator": {RateLimit: 2, RateWindow: time.Minute},
+	})
+	ctx := context.Background()
+	c := env.client(t, "node-1")
+
+	for i := 0; i < 2; i++ {
+		_, err := c.SignSignDoc(ctx, "validator", testSignDoc(t, testMsgSend))
+		require.NoError(t, err)
+	}
+	_, err := c.SignSignDoc(ctx, "validator", testSignDoc(t, testMsgSend))
+	assert.ErrorIs(t, err, ErrRateLimited)
+
+	env.advance(time.Minute)
+	_, err = c.SignSignDoc(ctx, "validator", testSignDoc(t, testMsgSend))
+	assert.NoError(t, err)
+}
+
+func TestRemoteSigner_RawBytes(t *testing.T) {
+	env := newTestEnv(t, map[string]Policy{
+		"validator": {},
+	})
+	ctx := context.Background()
+	remote, err := env.client(t, "node-1").GetKey(ctx, "validator")
+	require.NoError(t, err)
+
+	_, err = remote.Sign([]byte("vote"))
+	assert.ErrorIs(t, err, ErrPolicyDenied)
+
+	env2 := newTestEnv(t, map[string]Policy{
+		"validator": {AllowRawBytes: true},
+	})
+	remote2, err := env2.client(t, "node-1").GetKey(ctx, "validator")
+	require.NoError(t, err)
+
+	var signer crypto.Signer = remote2
+	sig, err := signer.Sign([]byte("vote"))
+	require.NoError(t, err)
+	assert.True(t, env2.signer.PublicKey().Verify([]byte("vote"), sig))
+}
+
+func TestRemoteSigner_RequiresClientCertificate(t *testing.T) {
+	env := newTestEnv(t, map[string]Policy{"validator": {}})
+
+	// A client that trusts the server but presents no certificate fails the
+	// TLS handshake.
+	c, err := NewClient(env.addr, &tls.Config{RootCAs: env.ca.pool, MinVersion: tls.VersionTLS13})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	_, err = c.ListKeys(context.Background())
+	assert.Error(t, err)
+
+	// Calls reaching the handler without a verified chain are rejected.
+	srv, err := NewServer(env.keyring, map[string]Policy{"validator": {}})
+	require.NoError(t, err)
+	handler := serviceDesc.Methods[0].Handler
+	_, err = handler(srv, context.Background(), func(v any) error { return nil }, nil)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.ErrorIs(t, fromStatus(err), ErrUnauthenticated)
+}
+
+func TestRemoteSigner_RejectsNonCanonicalSignDoc(t *testing.T) {
+	env := newTestEnv(t, map[string]Policy{"validator": {}})
+	c := env.client(t, "node-1")
+
+	doc, err := testSignDoc(t, testMsgSend).ToJSON()
+	require.NoError(t, err)
+	// An escaped character parses to the same document but is not the
+	// canonical encoding the signature would commit to.
+	escaped := bytes.Replace(doc, []byte(`"alice"`), []byte(`"\u0061lice"`), 1)
+	require.NotEqual(t, doc, escaped)
+
+	var resp signResponse
+	err = c.invoke(context.Background(), methodSignDoc, &signDocRequest{Key: "validator", SignDoc: escaped}, &resp)
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+}
+
+func TestVerifyResponse(t *testing.T) {
+	privKey, err := crypto.GeneratePrivateKey(crypto.AlgorithmEd25519)
+	require.NoError(t, err)
+	signer := crypto.NewSigner(privKey)
+	sig, err := signer.Sign([]byte("data"))
+	require.NoError(t, err)
+
+	resp := &signResponse{Algorithm: crypto.AlgorithmEd25519, PubKey: signer.PublicKey().Bytes(), Signature: sig}
+	_, err = verifyResponse(resp, []byte("data"))
+	assert.NoError(t, err)
+
+	_, err = verifyResponse(resp, []byte("other"))
+	assert.ErrorIs(t, err, ErrInvalidResponse)
+
+	resp.PubKey = resp.PubKey[:10]
+	_, err = verifyResponse(resp, []byte("data"))
+	assert.ErrorIs(t, err, ErrInvalidResponse)
+}
+
+func TestNewClient_Validation(t *testing.T) {
+	_, err := NewClient("", &tls.Config{})
+	assert.Error(t, err)
+	_, err = NewClient("signer:8443", nil)
+	assert.Error(t, err)
+}
+
+func TestNewServer_Validation(t *testing.T) {
+	kr := crypto.NewKeyring(crypto.NewMemoryStore())
+	_, err := NewServer(nil, nil)
+	assert.Error(t, err)
+	_, err = NewServer(kr, map[string]Policy{"k": {RateLimit: -1}})
+	assert.Error(t, err)
+}
+
+func TestRemoteSigner_EnforcesKeyringPolicy(t *testing.T) {
+	env := newTestEnv(t, map[string]Policy{"validator": {}})
+	require.NoError(t, env.keyring.SetPolicy("validator", &crypto.SigningPolicy{
+		AllowedChainIDs: []string{"punnet-2"},
+	}))
+
+	_, err := env.client(t, "node-1").SignSignDoc(context.Background(), "validator", testSignDoc(t, testMsgSend))
+	assert.ErrorIs(t, err, ErrPolicyDenied)
+}
@@ -0,0 +1,368 @@
+package remotesigner
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Audit operations.
+const (
+	OperationGetKey    = "get_key"
+	OperationListKeys  = "list_keys"
+	OperationSignDoc   = "sign_doc"
+	OperationSignBytes = "sign_bytes"
+)
+
+// AuditRecord describes one call handled by the Server.
+// Records are emitted for denied and failed requests as well as successful ones.
+type AuditRecord struct {
+	// Time is when the request was handled.
+	Time time.Time `json:"time"`
+
+	// Client is the common name of the client certificate ("" if unauthenticated).
+	Client string `json:"client"`
+
+	// Operation is one of the Operation* constants.
+	Operation string `json:"operation"`
+
+	// Key is the key name the request targeted ("" for list requests).
+	Key string `json:"key,omitempty"`
+
+	// ChainID is the SignDoc chain ID (sign_doc only).
+	ChainID string `json:"chain_id,omitempty"`
+
+	// MessageTypes are the SignDoc message types (sign_doc only).
+	MessageTypes []string `json:"message_types,omitempty"`
+
+	// DataHash is SHA-256 of the signed bytes, so signatures can be matched to
+	// audit records without logging transaction content.
+	DataHash []byte `json:"data_hash,omitempty"`
+
+	// Allowed reports whether the request succeeded.
+	Allowed bool `json:"allowed"`
+
+	// Error is the rejection or failure reason when Allowed is false.
+	Error string `json:"error,omitempty"`
+}
+
+// AuditLogger receives an AuditRecord for every request.
+// Implementations must be safe for concurrent use.
+type AuditLogger interface {
+	LogRequest(record AuditRecord)
+}
+
+// AuditLoggerFunc adapts a function to AuditLogger.
+type AuditLoggerFunc func(record AuditRecord)
+
+// LogRequest implements AuditLogger.
+func (f AuditLoggerFunc) LogRequest(record AuditRecord) {
+	f(record)
+}
+
+// Server exposes a Keyring to remote clients.
+// Serve it with the *grpc.Server returned by GRPCServer.
+// Thread-safe.
+type Server struct {
+	keyring  crypto.Keyring
+	policies map[string]Policy
+	audit    AuditLogger
+	limiter  *rateLimiter
+	now      func() time.Time
+}
+
+// ServerOption configures a Server.
+type ServerOption func(*Server)
+
+// WithAuditLogger sets the audit logger. The default discards records.
+func WithAuditLogger(logger AuditLogger) ServerOption {
+	return func(s *Server) {
+		s.audit = logger
+	}
+}
+
+// WithClock overrides the time source used for rate limiting and audit
+// timestamps. Intended for tests.
+func WithClock(now func() time.Time) ServerOption {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// NewServer creates a server for keyring.
+//
+// policies maps key names to their Policy. Keys without a policy are not
+// visible to clients and cannot be used.
+func NewServer(keyring crypto.Keyring, policies map[string]Policy, opts ...ServerOption) (*Server, error) {
+	if keyring == nil {
+		return nil, fmt.Errorf("keyring cannot be nil")
+	}
+	copied := make(map[string]Policy, len(policies))
+	for name, p := range policies {
+		if err := p.ValidateBasic(); err != nil {
+			return nil, fmt.Errorf("policy for key %s: %w", name, err)
+		}
+		copied[name] = p
+	}
+
+	s := &Server{
+		keyring:  keyring,
+		policies: copied,
+		audit:    AuditLoggerFunc(func(AuditRecord) {}),
+		limiter:  newRateLimiter(),
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// GRPCServer returns a gRPC server exposing s over mutual TLS with
+// tlsConfig, which should come from ServerTLSConfig. Start it with Serve on
+// a listener. opts are appended to the server's own options.
+func (s *Server) GRPCServer(tlsConfig *tls.Config, opts ...grpc.ServerOption) (*grpc.Server, error) {
+	if tlsConfig == nil {
+		return nil, fmt.Errorf("TLS config cannot be nil")
+	}
+
+	opts = append([]grpc.ServerOption{
+		grpc.Creds(credentials.NewTLS(tlsConfig)),
+		grpc.ForceServerCodec(jsonCodec{}),
+		grpc.MaxRecvMsgSize(maxRequestBodySize),
+	}, opts...)
+	srv := grpc.NewServer(opts...)
+	srv.RegisterService(&serviceDesc, s)
+	return srv, nil
+}
+
+// serviceDesc describes the signer service to gRPC.
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: methodListKeys, Handler: unaryHandler(methodListKeys, OperationListKeys, (*Server).listKeys)},
+		{MethodName: methodGetKey, Handler: unaryHandler(methodGetKey, OperationGetKey, (*Server).getKey)},
+		{MethodName: methodSignDoc, Handler: unaryHandler(methodSignDoc, OperationSignDoc, (*Server).signDoc)},
+		{MethodName: methodSignBytes, Handler: unaryHandler(methodSignBytes, OperationSignBytes, (*Server).signBytes)},
+	},
+}
+
+// unaryHandler returns the gRPC handler of a method. It decodes the request,
+// authenticates the client, calls handle and audits the call.
+//
+// SECURITY: Calls without a verified client certificate are rejected before
+// handle runs.
+func unaryHandler[Req, Resp any](method, operation string, handle func(*Server, *AuditRecord, *Req) (*Resp, error)) func(any, context.Context, func(any) error, grpc.UnaryServerInterceptor) (any, error) {
+	return func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		s := srv.(*Server)
+		req := new(Req)
+		decodeErr := dec(req)
+
+		call := func(ctx context.Context, _ any) (any, error) {
+			record := AuditRecord{Time: s.now(), Client: clientName(ctx), Operation: operation}
+
+			var resp *Resp
+			var err error
+			switch {
+			case record.Client == "":
+				err = ErrUnauthenticated
+			case decodeErr != nil:
+				err = fmt.Errorf("%w: %v", ErrInvalidRequest, decodeErr)
+			default:
+				resp, err = handle(s, &record, req)
+			}
+
+			if err != nil {
+				record.Error = err.Error()
+				s.audit.LogRequest(record)
+				return nil, toStatus(err)
+			}
+			record.Allowed = true
+			s.audit.LogRequest(record)
+			return resp, nil
+		}
+
+		if interceptor == nil {
+			return call(ctx, req)
+		}
+		info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/" + method}
+		return interceptor(ctx, req, info, call)
+	}
+}
+
+// clientName returns the common name of the verified client certificate.
+func clientName(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return tlsInfo.State.VerifiedChains[0][0].Subject.CommonName
+}
+
+// authorize checks that client may use key and returns its policy.
+func (s *Server) authorize(client, key string) (Policy, error) {
+	policy, ok := s.policies[key]
+	if !ok {
+		// Unconfigured keys are reported as missing so their existence is not leaked.
+		return Policy{}, fmt.Errorf("%w: %s", crypto.ErrKeyNotFound, key)
+	}
+	if !policy.allowsClient(client) {
+		return Policy{}, fmt.Errorf("%w: client %s may not use key %s", ErrPolicyDenied, client, key)
+	}
+	return policy, nil
+}
+
+func (s *Server) listKeys(record *AuditRecord, _ *listKeysRequest) (*listKeysResponse, error) {
+	names := make([]string, 0, len(s.policies))
+	for name, policy := range s.policies {
+		if policy.allowsClient(record.Client) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return &listKeysResponse{Keys: names}, nil
+}
+
+func (s *Server) getKey(record *AuditRecord, req *getKeyRequest) (*keyResponse, error) {
+	record.Key = req.Name
+	if _, err := s.authorize(record.Client, req.Name); err != nil {
+		return nil, err
+	}
+	signer, err := s.keyring.GetKey(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &keyResponse{Name: req.Name, Algorithm: signer.Algorithm(), PubKey: signer.PublicKey().Bytes()}, nil
+}
+
+func (s *Server) signDoc(record *AuditRecord, req *signDocRequest) (*signResponse, error) {
+	record.Key = req.Key
+	policy, err := s.authorize(record.Client, req.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	signDoc, err := types.ParseSignDoc(req.SignDoc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	if err := signDoc.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+
+	record.ChainID = signDoc.ChainID
+	record.MessageTypes = make([]string, len(signDoc.Messages))
+	for i, msg := range signDoc.Messages {
+		record.MessageTypes[i] = msg.Type
+	}
+
+	// SECURITY: Require the canonical encoding so the document the client
+	// displayed, the document policy was evaluated on, and the document
+	// signed are byte-for-byte identical.
+	canonical, err := signDoc.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	if !bytes.Equal(canonical, req.SignDoc) {
+		return nil, fmt.Errorf("%w: sign_doc is not in canonical form", ErrInvalidRequest)
+	}
+
+	if err := policy.checkMessageTypes(record.MessageTypes); err != nil {
+		return nil, err
+	}
+
+	signBytes, err := signDoc.GetSignBytes()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	// Sign through the keyring so any keyring-level SigningPolicy on the
+	// key is enforced as well.
+	return s.sign(record, policy, signBytes, func() ([]byte, error) {
+		return s.keyring.SignSignDoc(record.Key, signDoc)
+	})
+}
+
+func (s *Server) signBytes(record *AuditRecord, req *signBytesRequest) (*signResponse, error) {
+	record.Key = req.Key
+	policy, err := s.authorize(record.Client, req.Key)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.AllowRawBytes {
+		return nil, fmt.Errorf("%w: raw byte signing not allowed for key %s", ErrPolicyDenied, req.Key)
+	}
+	if len(req.Data) == 0 {
+		return nil, fmt.Errorf("%w: data cannot be empty", ErrInvalidRequest)
+	}
+	return s.sign(record, policy, req.Data, func() ([]byte, error) {
+		return s.keyring.Sign(record.Key, req.Data)
+	})
+}
+
+// sign applies the rate limit and calls signFn, which signs data with the
+// record's key.
+func (s *Server) sign(record *AuditRecord, policy Policy, data []byte, signFn func() ([]byte, error)) (*signResponse, error) {
+	hash := sha256.Sum256(data)
+	record.DataHash = hash[:]
+
+	if !s.limiter.allow(record.Key, policy.RateLimit, policy.window(), s.now()) {
+		return nil, fmt.Errorf("%w: %d requests per %s", ErrRateLimited, policy.RateLimit, policy.window())
+	}
+
+	signer, err := s.keyring.GetKey(record.Key)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := signFn()
+	if err != nil {
+		return nil, err
+	}
+	return &signResponse{
+		Algorithm: signer.Algorithm(),
+		PubKey:    signer.PublicKey().Bytes(),
+		Signature: sig,
+	}, nil
+}
+
+// toStatus converts err to the gRPC status carrying its sentinel's code.
+func toStatus(err error) error {
+	code := errorCode(err)
+	msg := err.Error()
+	if code == codes.Internal {
+		// Do not leak internal details (e.g. storage paths) to clients.
+		msg = "internal error"
+	}
+	return status.Error(code, msg)
+}
+
+// errorCode maps an error to its gRPC status code.
+func errorCode(err error) codes.Code {
+	if errors.Is(err, crypto.ErrPolicyViolation) {
+		return codes.PermissionDenied
+	}
+	for _, sc := range statusCodes {
+		if errors.Is(err, sc.err) {
+			return sc.code
+		}
+	}
+	return codes.Internal
+}
@@ -0,0 +1,41 @@
+package remotesigner
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+)
+
+// ServerTLSConfig returns a TLS configuration for the Server that requires
+// and verifies client certificates signed by clientCAs.
+//
+// SECURITY: TLS 1.3 only. Clients are identified by the common name of their
+// certificate, which Policy.AllowedClients matches against.
+func ServerTLSConfig(cert tls.Certificate, clientCAs *x509.CertPool) (*tls.Config, error) {
+	if clientCAs == nil {
+		return nil, fmt.Errorf("client CA pool cannot be nil")
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
+
+// ClientTLSConfig returns a TLS configuration for a Client presenting cert
+// and verifying the server against rootCAs.
+//
+// serverName overrides the name verified in the server certificate; leave
+// empty to use the host from the client's target.
+func ClientTLSConfig(cert tls.Certificate, rootCAs *x509.CertPool, serverName string) (*tls.Config, error) {
+	if rootCAs == nil {
+		return nil, fmt.Errorf("root CA pool cannot be nil")
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      rootCAs,
+		ServerName:   serverName,
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
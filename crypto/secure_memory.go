@@ -0,0 +1,168 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// Secure memory errors.
+var (
+	// ErrSecureMemoryUnsupported is returned when locked memory is not
+	// available on the current platform.
+	ErrSecureMemoryUnsupported = errors.New("secure memory not supported on this platform")
+
+	// ErrSecureMemory is returned when a locked buffer cannot be allocated,
+	// typically because RLIMIT_MEMLOCK is exhausted.
+	ErrSecureMemory = errors.New("secure memory allocation failed")
+
+	// ErrSignerDestroyed is returned when signing with a signer whose key was
+	// zeroized, e.g. after it was evicted from the keyring cache.
+	ErrSignerDestroyed = errors.New("signer key has been destroyed")
+)
+
+// SecureBuffer is a fixed-size byte buffer outside the Go heap, locked into
+// RAM so it is never written to swap, with an inaccessible guard page on
+// each side. The data ends against the trailing guard page, so a buffer
+// overrun faults instead of reading or corrupting neighbouring memory.
+//
+// Supported on Linux, macOS and FreeBSD; NewSecureBuffer returns
+// ErrSecureMemoryUnsupported elsewhere.
+//
+// SECURITY: Locked memory keeps keys out of swap files and (on Linux) core
+// dumps of swapped pages, but not out of reach of a process that can read
+// this one's memory. Transient copies made while signing still live on the
+// Go heap until they are zeroized.
+//
+// Thread-safety: Bytes and Destroy must not race; callers synchronize.
+type SecureBuffer struct {
+	// mapping is the whole allocation including guard pages
+	mapping []byte
+	// data is the usable region, a suffix of the locked pages
+	data []byte
+}
+
+// NewSecureBuffer allocates a zeroed locked buffer of size bytes.
+// Complexity: O(size) plus a few system calls.
+func NewSecureBuffer(size int) (*SecureBuffer, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: invalid size %d", ErrSecureMemory, size)
+	}
+	mapping, data, err := allocLocked(size)
+	if err != nil {
+		return nil, err
+	}
+	buf := &SecureBuffer{mapping: mapping, data: data}
+	// Release the mapping if the buffer is dropped without Destroy
+	runtime.SetFinalizer(buf, (*SecureBuffer).Destroy)
+	return buf, nil
+}
+
+// Bytes returns the buffer's contents. The slice aliases locked memory and
+// is invalid after Destroy.
+func (b *SecureBuffer) Bytes() []byte {
+	return b.data
+}
+
+// Destroy zeroizes the buffer, unlocks and releases its memory.
+// Safe to call multiple times.
+func (b *SecureBuffer) Destroy() error {
+	if b.mapping == nil {
+		return nil
+	}
+	Zeroize(b.data)
+	err := freeLocked(b.mapping)
+	b.mapping, b.data = nil, nil
+	runtime.SetFinalizer(b, nil)
+	return err
+}
+
+// WithSecureMemory keeps the private keys of cached signers in SecureBuffers
+// instead of the Go heap. Loading a key fails with ErrSecureMemory or
+// ErrSecureMemoryUnsupported rather than falling back to heap memory.
+//
+// Intended for validator-grade deployments; raise RLIMIT_MEMLOCK if many keys
+// are cached, as each key locks at least one page plus two guard pages of
+// address space.
+func WithSecureMemory() KeyringOption {
+	return func(k *defaultKeyring) {
+		k.secureMemory = true
+	}
+}
+
+// newSigner wraps privKey in a Signer. With secure memory enabled, the key
+// is moved into a SecureBuffer and privKey is zeroized.
+func (kr *defaultKeyring) newSigner(privKey PrivateKey) (Signer, error) {
+	if !kr.secureMemory {
+		return NewSigner(privKey), nil
+	}
+	defer privKey.Zeroize()
+	return newLockedSigner(privKey)
+}
+
+// lockedSigner is a Signer whose private key lives in a SecureBuffer.
+// Thread-safe: Sign and zeroize are serialized by mu.
+type lockedSigner struct {
+	mu     sync.RWMutex
+	algo   Algorithm
+	pubKey PublicKey
+	key    *SecureBuffer
+}
+
+// newLockedSigner copies privKey into a new SecureBuffer.
+func newLockedSigner(privKey PrivateKey) (*lockedSigner, error) {
+	raw := privKey.Bytes()
+	buf, err := NewSecureBuffer(len(raw))
+	if err != nil {
+		return nil, err
+	}
+	copy(buf.Bytes(), raw)
+	if privKey.Algorithm() != AlgorithmEd25519 {
+		// ECDSA keys serialize to a fresh copy
+		Zeroize(raw)
+	}
+	return &lockedSigner{algo: privKey.Algorithm(), pubKey: privKey.PublicKey(), key: buf}, nil
+}
+
+// Sign signs data with a transient copy of the key that is zeroized after
+// signing.
+//
+// RATIONALE: Signing cannot use the locked bytes in place: crypto/ed25519
+// caches per-key state under a weak pointer, which only heap memory supports.
+func (s *lockedSigner) Sign(data []byte) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.key == nil {
+		return nil, ErrSignerDestroyed
+	}
+
+	privKey, err := PrivateKeyFromBytes(s.algo, s.key.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	defer privKey.Zeroize()
+	return privKey.Sign(data)
+}
+
+// PublicKey returns the signer's public key.
+func (s *lockedSigner) PublicKey() PublicKey {
+	return s.pubKey
+}
+
+// Algorithm returns the signing algorithm.
+func (s *lockedSigner) Algorithm() Algorithm {
+	return s.algo
+}
+
+// zeroize destroys the signer's key; later Sign calls return
+// ErrSignerDestroyed.
+func (s *lockedSigner) zeroize() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.key != nil {
+		_ = s.key.Destroy()
+		s.key = nil
+	}
+}
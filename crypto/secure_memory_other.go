@@ -0,0 +1,13 @@
+//go:build !(linux || darwin || freebsd)
+
+package crypto
+
+// allocLocked is unavailable on this platform.
+func allocLocked(size int) (mapping, data []byte, err error) {
+	return nil, nil, ErrSecureMemoryUnsupported
+}
+
+// freeLocked is unavailable on this platform.
+func freeLocked(mapping []byte) error {
+	return ErrSecureMemoryUnsupported
+}
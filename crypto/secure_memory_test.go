@@ -0,0 +1,101 @@
+package crypto
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSecureBufferOrSkip allocates a SecureBuffer, skipping the test where
+// locked memory is unavailable.
+func newSecureBufferOrSkip(t *testing.T, size int) *SecureBuffer {
+	t.Helper()
+	buf, err := NewSecureBuffer(size)
+	if errors.Is(err, ErrSecureMemoryUnsupported) {
+		t.Skip("secure memory not supported on this platform")
+	}
+	require.NoError(t, err)
+	return buf
+}
+
+func TestSecureBuffer(t *testing.T) {
+	_, err := NewSecureBuffer(0)
+	assert.Error(t, err)
+
+	buf := newSecureBufferOrSkip(t, 64)
+	data := buf.Bytes()
+	require.Len(t, data, 64)
+	assert.Equal(t, make([]byte, 64), data, "new buffer must be zeroed")
+	assert.Equal(t, 64, cap(data), "appending must not reach the guard page")
+
+	for i := range data {
+		data[i] = byte(i)
+	}
+	assert.Equal(t, byte(63), buf.Bytes()[63])
+
+	require.NoError(t, buf.Destroy())
+	assert.Nil(t, buf.Bytes())
+	assert.NoError(t, buf.Destroy(), "Destroy must be idempotent")
+}
+
+func TestSecureBuffer_MultiPage(t *testing.T) {
+	buf := newSecureBufferOrSkip(t, 10000)
+	defer func() { _ = buf.Destroy() }()
+
+	data := buf.Bytes()
+	require.Len(t, data, 10000)
+	data[0], data[len(data)-1] = 1, 2
+	assert.Equal(t, byte(1), buf.Bytes()[0])
+	assert.Equal(t, byte(2), buf.Bytes()[9999])
+}
+
+func TestKeyringWithSecureMemory(t *testing.T) {
+	newSecureBufferOrSkip(t, 1).Destroy()
+
+	for _, algo := range []Algorithm{AlgorithmEd25519, AlgorithmSecp256k1, AlgorithmSecp256r1} {
+		t.Run(algo.String(), func(t *testing.T) {
+			kr := NewKeyring(NewMemoryStore(), WithSecureMemory())
+			defer kr.Close()
+
+			signer, err := kr.NewKey("validator", algo)
+			require.NoError(t, err)
+			require.IsType(t, &lockedSigner{}, signer)
+
+			data := []byte("block proposal")
+			sig, err := signer.Sign(data)
+			require.NoError(t, err)
+			assert.True(t, signer.PublicKey().Verify(data, sig))
+
+			sig, err = kr.Sign("validator", data)
+			require.NoError(t, err)
+			assert.True(t, signer.PublicKey().Verify(data, sig))
+		})
+	}
+}
+
+func TestKeyringWithSecureMemory_GetKeyAndClose(t *testing.T) {
+	newSecureBufferOrSkip(t, 1).Destroy()
+
+	store := NewMemoryStore()
+	created, err := NewKeyring(store).NewKey("validator", AlgorithmEd25519)
+	require.NoError(t, err)
+
+	kr := NewKeyring(store, WithSecureMemory())
+	signer, err := kr.GetKey("validator")
+	require.NoError(t, err)
+	require.IsType(t, &lockedSigner{}, signer)
+	assert.True(t, signer.PublicKey().Equals(created.PublicKey()))
+
+	data := []byte("vote")
+	sig, err := signer.Sign(data)
+	require.NoError(t, err)
+	assert.True(t, created.PublicKey().Verify(data, sig))
+
+	// Close destroys cached keys; signers handed out earlier stop working
+	// instead of touching released memory
+	require.NoError(t, kr.Close())
+	_, err = signer.Sign(data)
+	assert.ErrorIs(t, err, ErrSignerDestroyed)
+}
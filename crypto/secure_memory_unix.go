@@ -0,0 +1,51 @@
+//go:build linux || darwin || freebsd
+
+package crypto
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// allocLocked maps size bytes of locked memory between two PROT_NONE guard
+// pages and returns the whole mapping and the usable region, which ends
+// against the trailing guard page.
+func allocLocked(size int) (mapping, data []byte, err error) {
+	pageSize := os.Getpagesize()
+	dataPages := (size + pageSize - 1) / pageSize
+	total := (dataPages + 2) * pageSize
+
+	mapping, err = syscall.Mmap(-1, 0, total, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_PRIVATE|syscall.MAP_ANON)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: mmap: %v", ErrSecureMemory, err)
+	}
+
+	guardEnd := total - pageSize
+	if err := syscall.Mprotect(mapping[:pageSize], syscall.PROT_NONE); err != nil {
+		_ = syscall.Munmap(mapping)
+		return nil, nil, fmt.Errorf("%w: mprotect: %v", ErrSecureMemory, err)
+	}
+	if err := syscall.Mprotect(mapping[guardEnd:], syscall.PROT_NONE); err != nil {
+		_ = syscall.Munmap(mapping)
+		return nil, nil, fmt.Errorf("%w: mprotect: %v", ErrSecureMemory, err)
+	}
+	if err := syscall.Mlock(mapping[pageSize:guardEnd]); err != nil {
+		_ = syscall.Munmap(mapping)
+		return nil, nil, fmt.Errorf("%w: mlock: %v", ErrSecureMemory, err)
+	}
+
+	return mapping, mapping[guardEnd-size : guardEnd : guardEnd], nil
+}
+
+// freeLocked unlocks and unmaps a mapping returned by allocLocked.
+func freeLocked(mapping []byte) error {
+	pageSize := os.Getpagesize()
+	if err := syscall.Munlock(mapping[pageSize : len(mapping)-pageSize]); err != nil {
+		return fmt.Errorf("munlock: %w", err)
+	}
+	if err := syscall.Munmap(mapping); err != nil {
+		return fmt.Errorf("munmap: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package effects
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Branch is a copy-on-write view of an Executor's stores. Effects executed
+// in a branch see the branch's own writes, but the executor's stores are not
+// touched until Commit replays the writes against them in the order they
+// were made. Dropping a branch without committing discards its writes.
+//
+// Branch lets a batch of effects fail partway without leaving the effects
+// that ran before the failure in the working state.
+//
+// INVARIANT: The parent executor's stores are not modified before Commit.
+//
+// Thread Safety: Branch is safe for concurrent use. The parent's stores must
+// not be written between Branch and Commit, or the checks made in the branch
+// (e.g. sufficient funds) may no longer hold when the writes are replayed.
+type Branch struct {
+	parent *Executor
+
+	mu sync.Mutex
+
+	// state maps key to value; a nil value marks a deletion
+	state map[string][]byte
+
+	// balances maps balanceID(account, denom) to the branch's balance
+	balances map[string]uint64
+
+	// writes replays the branch's writes against the parent, in order
+	writes []func(*Executor) error
+
+	committed bool
+}
+
+// Branch creates a branch of e's stores
+func (e *Executor) Branch() (*Branch, error) {
+	if e == nil {
+		return nil, fmt.Errorf("executor is nil")
+	}
+
+	return &Branch{
+		parent:   e,
+		state:    make(map[string][]byte),
+		balances: make(map[string]uint64),
+	}, nil
+}
+
+// Execute executes effects in order against the branch
+func (b *Branch) Execute(effects []Effect) (*ExecutionResult, error) {
+	if b == nil {
+		return nil, fmt.Errorf("branch is nil")
+	}
+
+	executor := &Executor{store: b, balanceStore: b}
+	if b.parent.accountStore != nil {
+		executor.accountStore = b
+	}
+	return executor.Execute(effects)
+}
+
+// Commit applies the branch's writes to the parent executor's stores.
+// A branch can be committed once.
+//
+// POSTCONDITION: On error, the writes before the failing one have been
+// applied; this only happens if a parent store fails a write the branch
+// accepted
+func (b *Branch) Commit() error {
+	if b == nil {
+		return fmt.Errorf("branch is nil")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.committed {
+		return fmt.Errorf("branch already committed")
+	}
+	b.committed = true
+
+	for i, write := range b.writes {
+		if err := write(b.parent); err != nil {
+			return fmt.Errorf("failed to commit write %d: %w", i, err)
+		}
+	}
+	b.writes = nil
+	return nil
+}
+
+// Get retrieves a value, preferring the branch's writes
+func (b *Branch) Get(key []byte) ([]byte, error) {
+	b.mu.Lock()
+	value, written := b.state[string(key)]
+	b.mu.Unlock()
+
+	if !written {
+		return b.parent.store.Get(key)
+	}
+	if value == nil {
+		return nil, fmt.Errorf("key not found: %x", key)
+	}
+	return append([]byte{}, value...), nil
+}
+
+// Set buffers a write of value under key
+func (b *Branch) Set(key []byte, value []byte) error {
+	keyCopy := append([]byte{}, key...)
+	valueCopy := append([]byte{}, value...)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state[string(keyCopy)] = valueCopy
+	b.writes = append(b.writes, func(e *Executor) error {
+		return e.store.Set(keyCopy, valueCopy)
+	})
+	return nil
+}
+
+// Delete buffers a deletion of key
+func (b *Branch) Delete(key []byte) error {
+	keyCopy := append([]byte{}, key...)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state[string(keyCopy)] = nil
+	b.writes = append(b.writes, func(e *Executor) error {
+		return e.store.Delete(keyCopy)
+	})
+	return nil
+}
+
+// Has checks if key exists, preferring the branch's writes
+func (b *Branch) Has(key []byte) bool {
+	b.mu.Lock()
+	value, written := b.state[string(key)]
+	b.mu.Unlock()
+
+	if !written {
+		return b.parent.store.Has(key)
+	}
+	return value != nil
+}
+
+// GetBalance retrieves a balance, preferring the branch's writes
+func (b *Branch) GetBalance(account types.AccountName, denom string) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.balanceLocked(account, denom)
+}
+
+// SetBalance buffers a write of a balance
+func (b *Branch) SetBalance(account types.AccountName, denom string, amount uint64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.balances[balanceID(account, denom)] = amount
+	b.writes = append(b.writes, func(e *Executor) error {
+		return e.balanceStore.SetBalance(account, denom, amount)
+	})
+	return nil
+}
+
+// SubBalance buffers a subtraction from a balance.
+// Returns types.ErrInsufficientFunds if the balance is less than amount.
+func (b *Branch) SubBalance(account types.AccountName, denom string, amount uint64) error {
+	if amount == 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	balance, err := b.balanceLocked(account, denom)
+	if err != nil {
+		return err
+	}
+	if balance < amount {
+		return types.ErrInsufficientFunds
+	}
+
+	b.balances[balanceID(account, denom)] = balance - amount
+	b.writes = append(b.writes, func(e *Executor) error {
+		return e.balanceStore.SubBalance(account, denom, amount)
+	})
+	return nil
+}
+
+// AddBalance buffers an addition to a balance
+func (b *Branch) AddBalance(account types.AccountName, denom string, amount uint64) error {
+	if amount == 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	balance, err := b.balanceLocked(account, denom)
+	if err != nil {
+		return err
+	}
+	if balance > ^uint64(0)-amount {
+		return fmt.Errorf("balance overflow")
+	}
+
+	b.balances[balanceID(account, denom)] = balance + amount
+	b.writes = append(b.writes, func(e *Executor) error {
+		return e.balanceStore.AddBalance(account, denom, amount)
+	})
+	return nil
+}
+
+// SetAccount buffers a write of account
+func (b *Branch) SetAccount(account *types.Account) error {
+	if account == nil {
+		return fmt.Errorf("account cannot be nil")
+	}
+	accountCopy := *account
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.writes = append(b.writes, func(e *Executor) error {
+		return e.accountStore.SetAccount(&accountCopy)
+	})
+	return nil
+}
+
+// balanceLocked returns the branch's balance of account in denom.
+//
+// PRECONDITION: b.mu is held
+func (b *Branch) balanceLocked(account types.AccountName, denom string) (uint64, error) {
+	if balance, ok := b.balances[balanceID(account, denom)]; ok {
+		return balance, nil
+	}
+	return b.parent.balanceStore.GetBalance(account, denom)
+}
+
+// balanceID identifies the balance of account in denom
+func balanceID(account types.AccountName, denom string) string {
+	return string(account) + "/" + denom
+}
@@ -0,0 +1,91 @@
+package effects
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+func TestBranch(t *testing.T) {
+	s := NewMockStore()
+	balances := NewMockBalanceStore()
+	if err := balances.SetBalance("alice", "stake", 100); err != nil {
+		t.Fatalf("SetBalance failed: %v", err)
+	}
+	executor, err := NewExecutor(s, balances)
+	if err != nil {
+		t.Fatalf("NewExecutor failed: %v", err)
+	}
+
+	branch, err := executor.Branch()
+	if err != nil {
+		t.Fatalf("Branch failed: %v", err)
+	}
+	_, err = branch.Execute([]Effect{
+		NewStateWriteEffect("bank", []byte("key"), []byte("value")),
+		TransferEffect{From: "alice", To: "bob", Amount: types.NewCoins(types.NewCoin("stake", 60))},
+		// The branch sees its own writes
+		TransferEffect{From: "bob", To: "carol", Amount: types.NewCoins(types.NewCoin("stake", 10))},
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	// Nothing reaches the parent before Commit
+	if s.Has([]byte("module/bank/key")) {
+		t.Fatal("branch write reached the parent store before Commit")
+	}
+	if balance, _ := balances.GetBalance("alice", "stake"); balance != 100 {
+		t.Fatalf("expected parent balance 100 before Commit, got %d", balance)
+	}
+
+	if err := branch.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if !s.Has([]byte("module/bank/key")) {
+		t.Fatal("branch write was not committed")
+	}
+	for account, want := range map[types.AccountName]uint64{"alice": 40, "bob": 50, "carol": 10} {
+		if balance, _ := balances.GetBalance(account, "stake"); balance != want {
+			t.Fatalf("expected %s balance %d, got %d", account, want, balance)
+		}
+	}
+
+	if err := branch.Commit(); err == nil {
+		t.Fatal("expected error committing a branch twice")
+	}
+}
+
+func TestBranch_FailureDiscards(t *testing.T) {
+	s := NewMockStore()
+	balances := NewMockBalanceStore()
+	if err := balances.SetBalance("alice", "stake", 100); err != nil {
+		t.Fatalf("SetBalance failed: %v", err)
+	}
+	executor, err := NewExecutor(s, balances)
+	if err != nil {
+		t.Fatalf("NewExecutor failed: %v", err)
+	}
+
+	branch, err := executor.Branch()
+	if err != nil {
+		t.Fatalf("Branch failed: %v", err)
+	}
+	_, err = branch.Execute([]Effect{
+		NewStateWriteEffect("bank", []byte("key"), []byte("value")),
+		TransferEffect{From: "alice", To: "bob", Amount: types.NewCoins(types.NewCoin("stake", 60))},
+		TransferEffect{From: "alice", To: "bob", Amount: types.NewCoins(types.NewCoin("stake", 60))},
+	})
+	if !errors.Is(err, types.ErrInsufficientFunds) {
+		t.Fatalf("expected ErrInsufficientFunds, got %v", err)
+	}
+
+	// The effects before the failure never reach the parent
+	if s.Has([]byte("module/bank/key")) {
+		t.Fatal("write of a failed branch reached the parent store")
+	}
+	if balance, _ := balances.GetBalance("alice", "stake"); balance != 100 {
+		t.Fatalf("expected balance 100, got %d", balance)
+	}
+}
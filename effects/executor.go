@@ -83,6 +83,10 @@ type Executor struct {
 
 	// balanceStore provides balance operations (must be thread-safe)
 	balanceStore BalanceStore
+
+	// accountStore stores the accounts of AccountWriteEffects (may be nil;
+	// must be thread-safe)
+	accountStore AccountStore
 }
 
 // BalanceStore provides balance-specific operations
@@ -100,6 +104,12 @@ type BalanceStore interface {
 	AddBalance(account types.AccountName, denom string, amount uint64) error
 }
 
+// AccountStore provides account operations
+type AccountStore interface {
+	// SetAccount stores account under its name
+	SetAccount(account *types.Account) error
+}
+
 // NewExecutor creates a new effect executor
 func NewExecutor(store Store, balanceStore BalanceStore) (*Executor, error) {
 	if store == nil {
@@ -115,6 +125,18 @@ func NewExecutor(store Store, balanceStore BalanceStore) (*Executor, error) {
 	}, nil
 }
 
+// SetAccountStore routes AccountWriteEffects through accounts, e.g. the
+// runtime's cached account store, instead of writing their encoded value to
+// the state store.
+//
+// PRECONDITION: Called before the executor is used.
+func (e *Executor) SetAccountStore(accounts AccountStore) {
+	if e == nil {
+		return
+	}
+	e.accountStore = accounts
+}
+
 // Execute executes a list of effects in order
 // Effects must be provided in a valid execution order (e.g., from topological sort)
 func (e *Executor) Execute(effects []Effect) (*ExecutionResult, error) {
@@ -188,9 +210,20 @@ func (e *Executor) executeWrite(effect Effect) error {
 		return fmt.Errorf("write effect has empty key")
 	}
 
-	// Note: Actual serialization happens in the capability/store layer
+	if write, ok := effect.(AccountWriteEffect); ok && e.accountStore != nil {
+		return e.accountStore.SetAccount(write.Account)
+	}
+
+	if encoder, ok := effect.(ValueEncoder); ok {
+		value, err := encoder.EncodedValue()
+		if err != nil {
+			return fmt.Errorf("failed to encode value: %w", err)
+		}
+		return e.store.Set(key, value)
+	}
+
+	// Note: Typed write effects are serialized in the capability/store layer
 	// This executor validates the write can occur
-	// In a real implementation, we would serialize the value here
 	return e.store.Set(key, []byte("placeholder"))
 }
 
@@ -230,7 +263,8 @@ func (e *Executor) executeTransfer(effect Effect) error {
 
 		// Add to receiver
 		if err := e.balanceStore.AddBalance(transfer.To, coin.Denom, coin.Amount); err != nil {
-			// Rollback is handled by the transaction layer
+			// The subtraction is not undone: callers needing atomicity
+			// execute in a Branch and discard it on error
 			return fmt.Errorf("failed to add to %s: %w", transfer.To, err)
 		}
 	}
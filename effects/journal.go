@@ -0,0 +1,117 @@
+package effects
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// Journal is the ordered record of the effects a transaction or block hook
+// applied. Replaying a journal against the same starting state reproduces the
+// same state and events.
+type Journal struct {
+	effects []Effect
+}
+
+// NewJournal creates a journal of effects
+func NewJournal(effects []Effect) *Journal {
+	entries := make([]Effect, len(effects))
+	copy(entries, effects)
+	return &Journal{effects: entries}
+}
+
+// Effects returns the recorded effects (defensive copy of the slice)
+func (j *Journal) Effects() []Effect {
+	if j == nil {
+		return nil
+	}
+	result := make([]Effect, len(j.effects))
+	copy(result, j.effects)
+	return result
+}
+
+// Len returns the number of recorded effects
+func (j *Journal) Len() int {
+	if j == nil {
+		return 0
+	}
+	return len(j.effects)
+}
+
+// Fingerprint returns a SHA-256 digest of the recorded effects' canonical
+// encoding. Two runs of a deterministic handler over the same state yield the
+// same fingerprint.
+func (j *Journal) Fingerprint() ([]byte, error) {
+	if j == nil {
+		return nil, fmt.Errorf("journal is nil")
+	}
+	return Fingerprint(j.effects)
+}
+
+// Replay applies the journal's effects with executor
+func Replay(executor *Executor, journal *Journal) (*ExecutionResult, error) {
+	if journal == nil {
+		return nil, fmt.Errorf("journal cannot be nil")
+	}
+	return executor.Execute(journal.Effects())
+}
+
+// Fingerprint returns a SHA-256 digest of the canonical encoding of effects:
+// for each effect its type and key, followed by its encoded value (writes),
+// sorted attributes (events) or parties and amounts (transfers).
+//
+// Complexity: O(total encoded size), plus O(a log a) per event with a attributes
+func Fingerprint(effects []Effect) ([]byte, error) {
+	h := sha256.New()
+	field := func(b []byte) {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(b)))
+		h.Write(length[:])
+		h.Write(b)
+	}
+
+	for i, effect := range effects {
+		if effect == nil {
+			return nil, fmt.Errorf("effect %d is nil", i)
+		}
+		h.Write([]byte{byte(effect.Type())})
+		field(effect.Key())
+
+		switch e := effect.(type) {
+		case ValueEncoder:
+			value, err := e.EncodedValue()
+			if err != nil {
+				return nil, fmt.Errorf("effect %d: failed to encode value: %w", i, err)
+			}
+			field(value)
+		case EventEffect:
+			field([]byte(e.EventType))
+			var count [8]byte
+			binary.BigEndian.PutUint64(count[:], uint64(len(e.Attributes)))
+			h.Write(count[:])
+			keys := make([]string, 0, len(e.Attributes))
+			for k := range e.Attributes {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				field([]byte(k))
+				field(e.Attributes[k])
+			}
+		case TransferEffect:
+			field([]byte(e.From))
+			field([]byte(e.To))
+			var count [8]byte
+			binary.BigEndian.PutUint64(count[:], uint64(len(e.Amount)))
+			h.Write(count[:])
+			for _, coin := range e.Amount {
+				field([]byte(coin.Denom))
+				var amount [8]byte
+				binary.BigEndian.PutUint64(amount[:], coin.Amount)
+				h.Write(amount[:])
+			}
+		}
+	}
+	return h.Sum(nil), nil
+}
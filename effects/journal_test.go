@@ -0,0 +1,112 @@
+package effects
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+func journalEffects() []Effect {
+	return []Effect{
+		NewStateWriteEffect("bank", []byte("a"), []byte("1")),
+		NewEventEffect("transfer", map[string][]byte{"from": []byte("alice"), "to": []byte("bob"), "amount": []byte("5")}),
+		TransferEffect{From: "alice", To: "bob", Amount: types.NewCoins(types.NewCoin("uatom", 5))},
+		NewStateDeleteEffect("bank", []byte("b")),
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	first, err := Fingerprint(journalEffects())
+	if err != nil {
+		t.Fatalf("Fingerprint failed: %v", err)
+	}
+
+	// Event attributes are maps; iteration order must not matter
+	for i := 0; i < 20; i++ {
+		again, err := Fingerprint(journalEffects())
+		if err != nil {
+			t.Fatalf("Fingerprint failed: %v", err)
+		}
+		if !bytes.Equal(first, again) {
+			t.Fatal("fingerprint is not deterministic")
+		}
+	}
+
+	changed := journalEffects()
+	changed[0] = NewStateWriteEffect("bank", []byte("a"), []byte("2"))
+	other, err := Fingerprint(changed)
+	if err != nil {
+		t.Fatalf("Fingerprint failed: %v", err)
+	}
+	if bytes.Equal(first, other) {
+		t.Fatal("different values should change the fingerprint")
+	}
+
+	reordered := journalEffects()
+	reordered[0], reordered[3] = reordered[3], reordered[0]
+	other, err = Fingerprint(reordered)
+	if err != nil {
+		t.Fatalf("Fingerprint failed: %v", err)
+	}
+	if bytes.Equal(first, other) {
+		t.Fatal("effect order should change the fingerprint")
+	}
+
+	if _, err := Fingerprint([]Effect{nil}); err == nil {
+		t.Fatal("expected error for nil effect")
+	}
+}
+
+func TestReplay(t *testing.T) {
+	newExecutor := func() (*Executor, *MockStore, *MockBalanceStore) {
+		store := NewMockStore()
+		_ = store.Set([]byte("module/bank/b"), []byte("old"))
+		balances := NewMockBalanceStore()
+		_ = balances.SetBalance("alice", "uatom", 10)
+		executor, err := NewExecutor(store, balances)
+		if err != nil {
+			t.Fatalf("NewExecutor failed: %v", err)
+		}
+		return executor, store, balances
+	}
+
+	executor, store, balances := newExecutor()
+	journal := NewJournal(journalEffects())
+	result, err := Replay(executor, journal)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+
+	replayExecutor, replayStore, replayBalances := newExecutor()
+	replayResult, err := Replay(replayExecutor, journal)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+
+	if len(store.data) != len(replayStore.data) {
+		t.Fatalf("store sizes differ: %d vs %d", len(store.data), len(replayStore.data))
+	}
+	for key, value := range store.data {
+		if !bytes.Equal(value, replayStore.data[key]) {
+			t.Fatalf("key %q differs after replay", key)
+		}
+	}
+	for _, account := range []types.AccountName{"alice", "bob"} {
+		want, _ := balances.GetBalance(account, "uatom")
+		got, _ := replayBalances.GetBalance(account, "uatom")
+		if want != got {
+			t.Fatalf("balance of %s differs after replay: %d vs %d", account, want, got)
+		}
+	}
+	if len(result.Events) != 1 || len(replayResult.Events) != 1 {
+		t.Fatalf("expected one event per run, got %d and %d", len(result.Events), len(replayResult.Events))
+	}
+
+	if journal.Len() != 4 {
+		t.Fatalf("expected 4 journal entries, got %d", journal.Len())
+	}
+	if _, err := Replay(executor, nil); err == nil {
+		t.Fatal("expected error for nil journal")
+	}
+}
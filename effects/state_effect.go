@@ -0,0 +1,157 @@
+package effects
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// ValueEncoder is implemented by write effects that carry their encoded value.
+// The Executor stores the encoded value; write effects without it are only
+// validated against the store.
+type ValueEncoder interface {
+	// EncodedValue returns the bytes to store under the effect's Key
+	EncodedValue() ([]byte, error)
+}
+
+// StateWriteEffect writes raw bytes to a module's namespace, the same keys the
+// module's capabilities use (see capability.ModuleStore). A nil Value deletes
+// the key.
+type StateWriteEffect struct {
+	// Module is the module whose namespace is written
+	Module string
+
+	// StoreKey is the key within the module namespace
+	StoreKey []byte
+
+	// Value is the encoded value, or nil to delete StoreKey
+	Value []byte
+}
+
+// NewStateWriteEffect creates a write effect with defensive copies of key and value
+func NewStateWriteEffect(module string, key, value []byte) StateWriteEffect {
+	e := StateWriteEffect{
+		Module:   module,
+		StoreKey: append([]byte(nil), key...),
+	}
+	if value != nil {
+		e.Value = append([]byte{}, value...)
+	}
+	return e
+}
+
+// NewStateDeleteEffect creates an effect deleting key from a module namespace
+func NewStateDeleteEffect(module string, key []byte) StateWriteEffect {
+	return StateWriteEffect{
+		Module:   module,
+		StoreKey: append([]byte(nil), key...),
+	}
+}
+
+// Type returns EffectTypeDelete for a nil Value, EffectTypeWrite otherwise
+func (e StateWriteEffect) Type() EffectType {
+	if e.Value == nil {
+		return EffectTypeDelete
+	}
+	return EffectTypeWrite
+}
+
+// Validate performs validation
+func (e StateWriteEffect) Validate() error {
+	if e.Module == "" {
+		return fmt.Errorf("module name cannot be empty")
+	}
+	if len(e.StoreKey) == 0 {
+		return fmt.Errorf("key cannot be empty")
+	}
+	return nil
+}
+
+// Dependencies returns the dependencies
+func (e StateWriteEffect) Dependencies() []Dependency {
+	return []Dependency{
+		{
+			Type:     DependencyTypeGeneric,
+			Key:      e.Key(),
+			ReadOnly: false,
+		},
+	}
+}
+
+// Key returns the full key ("module/<module>/<key>")
+func (e StateWriteEffect) Key() []byte {
+	prefix := []byte(fmt.Sprintf("module/%s/", e.Module))
+	// Create new slice with exact capacity to prevent aliasing
+	result := make([]byte, len(prefix)+len(e.StoreKey))
+	copy(result, prefix)
+	copy(result[len(prefix):], e.StoreKey)
+	return result
+}
+
+// EncodedValue returns a copy of Value
+func (e StateWriteEffect) EncodedValue() ([]byte, error) {
+	return append([]byte{}, e.Value...), nil
+}
+
+// AccountWriteEffect stores Account under its name, where the runtime keeps
+// accounts. The Executor writes it through its AccountStore when one is set
+// (see Executor.SetAccountStore), so cached account reads see the update;
+// otherwise it stores the encoded account under Key.
+type AccountWriteEffect struct {
+	// Account is the account to store
+	Account *types.Account
+}
+
+// NewAccountWriteEffect creates a write effect storing a copy of account
+func NewAccountWriteEffect(account *types.Account) AccountWriteEffect {
+	if account == nil {
+		return AccountWriteEffect{}
+	}
+	accountCopy := *account
+	return AccountWriteEffect{Account: &accountCopy}
+}
+
+// Type returns the effect type
+func (e AccountWriteEffect) Type() EffectType {
+	return EffectTypeWrite
+}
+
+// Validate performs validation, including Account.ValidateBasic
+func (e AccountWriteEffect) Validate() error {
+	if e.Account == nil {
+		return fmt.Errorf("account cannot be nil")
+	}
+	if err := e.Account.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid account: %w", err)
+	}
+	return nil
+}
+
+// Dependencies returns the dependencies
+func (e AccountWriteEffect) Dependencies() []Dependency {
+	return []Dependency{
+		{
+			Type:     DependencyTypeAccount,
+			Key:      e.Key(),
+			ReadOnly: false,
+		},
+	}
+}
+
+// Key returns the account's raw name, the runtime's account key
+func (e AccountWriteEffect) Key() []byte {
+	if e.Account == nil {
+		return nil
+	}
+	return []byte(e.Account.Name)
+}
+
+// EncodedValue returns the JSON encoding of Account, as the runtime's
+// account store encodes accounts
+func (e AccountWriteEffect) EncodedValue() ([]byte, error) {
+	if e.Account == nil {
+		return nil, fmt.Errorf("account cannot be nil")
+	}
+	return json.Marshal(e.Account)
+}
@@ -0,0 +1,139 @@
+package effects
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+func TestStateWriteEffect(t *testing.T) {
+	key := []byte("key")
+	value := []byte("value")
+	e := NewStateWriteEffect("bank", key, value)
+
+	// Defensive copies
+	key[0] = 'x'
+	value[0] = 'x'
+
+	if e.Type() != EffectTypeWrite {
+		t.Fatalf("expected write, got %s", e.Type())
+	}
+	if err := e.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if string(e.Key()) != "module/bank/key" {
+		t.Fatalf("unexpected key %q", e.Key())
+	}
+	encoded, err := e.EncodedValue()
+	if err != nil || string(encoded) != "value" {
+		t.Fatalf("unexpected encoded value %q (%v)", encoded, err)
+	}
+
+	// An empty value is a write, not a delete
+	if NewStateWriteEffect("bank", []byte("k"), []byte{}).Type() != EffectTypeWrite {
+		t.Fatal("empty value should be a write")
+	}
+
+	del := NewStateDeleteEffect("bank", []byte("key"))
+	if del.Type() != EffectTypeDelete {
+		t.Fatalf("expected delete, got %s", del.Type())
+	}
+
+	invalid := []StateWriteEffect{
+		{StoreKey: []byte("k"), Value: []byte("v")},
+		{Module: "bank", Value: []byte("v")},
+	}
+	for i, e := range invalid {
+		if err := e.Validate(); err == nil {
+			t.Errorf("case %d: expected validation error", i)
+		}
+	}
+}
+
+func TestExecutor_StateWriteEffect(t *testing.T) {
+	store := NewMockStore()
+	executor, err := NewExecutor(store, NewMockBalanceStore())
+	if err != nil {
+		t.Fatalf("NewExecutor failed: %v", err)
+	}
+
+	if _, err := executor.Execute([]Effect{NewStateWriteEffect("bank", []byte("k"), []byte("v"))}); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	got, err := store.Get([]byte("module/bank/k"))
+	if err != nil || string(got) != "v" {
+		t.Fatalf("expected stored value v, got %q (%v)", got, err)
+	}
+
+	if _, err := executor.Execute([]Effect{NewStateDeleteEffect("bank", []byte("k"))}); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if store.Has([]byte("module/bank/k")) {
+		t.Fatal("expected key to be deleted")
+	}
+}
+
+// accountSink is an AccountStore recording the accounts it stores
+type accountSink map[types.AccountName]*types.Account
+
+func (s accountSink) SetAccount(account *types.Account) error {
+	s[account.Name] = account
+	return nil
+}
+
+func TestAccountWriteEffect(t *testing.T) {
+	account := types.NewAccount("alice", []byte("pubkey-alice"))
+	e := NewAccountWriteEffect(account)
+
+	// Defensive copy
+	account.Nonce = 7
+
+	if e.Type() != EffectTypeWrite {
+		t.Fatalf("expected write, got %s", e.Type())
+	}
+	if err := e.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if string(e.Key()) != "alice" || e.Account.Nonce != 0 {
+		t.Fatalf("unexpected effect %q %+v", e.Key(), e.Account)
+	}
+	if err := (AccountWriteEffect{}).Validate(); err == nil {
+		t.Fatal("expected validation error for nil account")
+	}
+	if err := NewAccountWriteEffect(&types.Account{Name: "alice"}).Validate(); err == nil {
+		t.Fatal("expected validation error for invalid account")
+	}
+
+	// Without an account store the encoded account is written under its name
+	store := NewMockStore()
+	executor, err := NewExecutor(store, NewMockBalanceStore())
+	if err != nil {
+		t.Fatalf("NewExecutor failed: %v", err)
+	}
+	if _, err := executor.Execute([]Effect{e}); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	encoded, err := e.EncodedValue()
+	if err != nil {
+		t.Fatalf("EncodedValue failed: %v", err)
+	}
+	if got, err := store.Get([]byte("alice")); err != nil || !bytes.Equal(got, encoded) {
+		t.Fatalf("expected encoded account, got %q (%v)", got, err)
+	}
+
+	// With one, the account goes through it and not the state store
+	sink := accountSink{}
+	store = NewMockStore()
+	executor, err = NewExecutor(store, NewMockBalanceStore())
+	if err != nil {
+		t.Fatalf("NewExecutor failed: %v", err)
+	}
+	executor.SetAccountStore(sink)
+	if _, err := executor.Execute([]Effect{e}); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if sink["alice"] == nil || store.Has([]byte("alice")) {
+		t.Fatalf("expected account in account store only, got %v", sink)
+	}
+}
@@ -0,0 +1,198 @@
+// Package errors assigns stable ABCI codes to SDK errors and provides the
+// wrapping helpers used on consensus paths.
+//
+// Every error that can reach a transaction or query result is registered
+// under a codespace and code. Results carry the codespace and code, so
+// clients map them back to the registered error (see client.DecodeTxResult)
+// instead of parsing log strings. Codes are part of the wire protocol: once
+// released, a codespace/code pair must keep its meaning.
+//
+// Import as sdkerrors to avoid shadowing the standard library package.
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// CodespaceSDK is the codespace of the SDK's own errors
+const CodespaceSDK = "sdk"
+
+const (
+	// CodeOK is the result code of a successful transaction or query
+	CodeOK uint32 = 0
+
+	// CodeInternal is the result code of ErrInternal and of unregistered errors
+	CodeInternal uint32 = 1
+)
+
+var (
+	// ErrInternal replaces unregistered errors in consensus results (see Redact)
+	ErrInternal = errors.New("internal error")
+
+	// ErrDuplicateCode indicates a codespace/code pair or error registered twice
+	ErrDuplicateCode = errors.New("duplicate error code")
+)
+
+// Code identifies an error in a transaction or query result.
+// Codes are only unique within a codespace.
+type Code struct {
+	Codespace string `json:"codespace"`
+	Code      uint32 `json:"code"`
+
+	// Message is the registered error's message
+	Message string `json:"message"`
+}
+
+// key identifies a registered code
+type key struct {
+	codespace string
+	code      uint32
+}
+
+// registry is the process-wide error code registry.
+// INVARIANT: byCode and byError are inverse mappings of each other.
+var registry = struct {
+	mu      sync.RWMutex
+	byCode  map[key]error
+	byError map[error]key
+}{
+	byCode:  map[key]error{{CodespaceSDK, CodeInternal}: ErrInternal},
+	byError: map[error]key{ErrInternal: {CodespaceSDK, CodeInternal}},
+}
+
+// Register assigns codespace/code to the sentinel err so results can carry it
+// across the ABCI boundary and clients can map it back with FromCode. Packages
+// register their errors under their own codespace from an init function.
+//
+// PRECONDITION: err is a comparable sentinel (e.g. from errors.New)
+// PRECONDITION: code is neither CodeOK nor CodeInternal
+// POSTCONDITION: Returns ErrDuplicateCode if the pair or err is taken
+func Register(codespace string, code uint32, err error) error {
+	if codespace == "" {
+		return fmt.Errorf("codespace cannot be empty")
+	}
+	if code == CodeOK || code == CodeInternal {
+		return fmt.Errorf("code %d is reserved", code)
+	}
+	if err == nil {
+		return fmt.Errorf("error cannot be nil")
+	}
+	if !reflect.TypeOf(err).Comparable() {
+		return fmt.Errorf("error type %T is not comparable", err)
+	}
+
+	k := key{codespace: codespace, code: code}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if existing, ok := registry.byCode[k]; ok {
+		return fmt.Errorf("%w: %s/%d already assigned to %q", ErrDuplicateCode, codespace, code, existing)
+	}
+	if existing, ok := registry.byError[err]; ok {
+		return fmt.Errorf("%w: %q already registered as %s/%d", ErrDuplicateCode, err, existing.codespace, existing.code)
+	}
+
+	registry.byCode[k] = err
+	registry.byError[err] = k
+	return nil
+}
+
+// MustRegister is Register for init functions; it panics on error.
+//
+// INVARIANT: Codes are part of the wire protocol; never renumber or reuse
+// them. A removed error's code stays retired.
+func MustRegister(codespace string, code uint32, err error) {
+	if regErr := Register(codespace, code, err); regErr != nil {
+		panic(regErr)
+	}
+}
+
+// ABCICode returns the codespace and code of err: those of the outermost
+// registered or WithCode error in its wrap chain, or CodespaceSDK/CodeInternal
+// if there is none. A nil err yields CodeOK.
+//
+// The chain is walked depth first, so for errors joining several causes the
+// first coded cause wins.
+func ABCICode(err error) (string, uint32) {
+	if err == nil {
+		return "", CodeOK
+	}
+
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	if k, ok := lookupLocked(err); ok {
+		return k.codespace, k.code
+	}
+	return CodespaceSDK, CodeInternal
+}
+
+// IsRegistered returns true if err has a code other than CodeInternal
+func IsRegistered(err error) bool {
+	_, code := ABCICode(err)
+	return code != CodeOK && code != CodeInternal
+}
+
+// lookupLocked walks the wrap chain of err for a coded error.
+// PRECONDITION: registry.mu is held.
+func lookupLocked(err error) (key, bool) {
+	if err == nil {
+		return key{}, false
+	}
+
+	if coded, ok := err.(*codedError); ok {
+		return key{codespace: coded.codespace, code: coded.code}, true
+	}
+
+	// Map lookups on non-comparable keys panic; registered errors are
+	// always comparable so such links can be skipped
+	if reflect.TypeOf(err).Comparable() {
+		if k, ok := registry.byError[err]; ok {
+			return k, true
+		}
+	}
+
+	switch wrapped := err.(type) {
+	case interface{ Unwrap() error }:
+		return lookupLocked(wrapped.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, inner := range wrapped.Unwrap() {
+			if k, ok := lookupLocked(inner); ok {
+				return k, true
+			}
+		}
+	}
+	return key{}, false
+}
+
+// FromCode returns the error registered for codespace/code, or nil if the
+// pair is unknown.
+func FromCode(codespace string, code uint32) error {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	return registry.byCode[key{codespace: codespace, code: code}]
+}
+
+// Codes returns every registered code ordered by codespace, then code
+func Codes() []Code {
+	registry.mu.RLock()
+	codes := make([]Code, 0, len(registry.byCode))
+	for k, err := range registry.byCode {
+		codes = append(codes, Code{Codespace: k.codespace, Code: k.code, Message: err.Error()})
+	}
+	registry.mu.RUnlock()
+
+	sort.Slice(codes, func(i, j int) bool {
+		if codes[i].Codespace != codes[j].Codespace {
+			return codes[i].Codespace < codes[j].Codespace
+		}
+		return codes[i].Code < codes[j].Code
+	})
+	return codes
+}
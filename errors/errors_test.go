@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errFirst  = errors.New("first")
+	errSecond = errors.New("second")
+)
+
+func init() {
+	MustRegister("errors-test", 2, errFirst)
+	MustRegister("errors-test", 3, errSecond)
+}
+
+func TestABCICode(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantCodespace string
+		wantCode      uint32
+	}{
+		{"nil", nil, "", CodeOK},
+		{"unregistered", errors.New("boom"), CodespaceSDK, CodeInternal},
+		{"internal", ErrInternal, CodespaceSDK, CodeInternal},
+		{"sentinel", errFirst, "errors-test", 2},
+		{"wrapped", Wrap(errFirst, "context"), "errors-test", 2},
+		{"outermost wins", fmt.Errorf("%w: %w", errSecond, errFirst), "errors-test", 3},
+		{"wrapped detail", fmt.Errorf("%w: %v", errSecond, errFirst), "errors-test", 3},
+		{"joined", errors.Join(errors.New("other"), errFirst), "errors-test", 2},
+		{"with code", WithCode(errors.New("io"), "errors-test", 9), "errors-test", 9},
+		{"with code outermost", WithCode(errFirst, "errors-test", 9), "errors-test", 9},
+		{"wrapped with code", Wrap(WithCode(errors.New("io"), "errors-test", 9), "context"), "errors-test", 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codespace, code := ABCICode(tt.err)
+			assert.Equal(t, tt.wantCodespace, codespace)
+			assert.Equal(t, tt.wantCode, code)
+		})
+	}
+}
+
+func TestFromCode(t *testing.T) {
+	assert.Equal(t, errFirst, FromCode("errors-test", 2))
+	assert.Equal(t, ErrInternal, FromCode(CodespaceSDK, CodeInternal))
+	assert.Nil(t, FromCode("errors-test", 9999))
+	assert.Nil(t, FromCode("unknown", 2))
+}
+
+func TestCodes(t *testing.T) {
+	codes := Codes()
+	require.Contains(t, codes, Code{Codespace: CodespaceSDK, Code: CodeInternal, Message: "internal error"})
+	require.Contains(t, codes, Code{Codespace: "errors-test", Code: 2, Message: "first"})
+
+	for i := 1; i < len(codes); i++ {
+		prev, cur := codes[i-1], codes[i]
+		require.True(t, prev.Codespace < cur.Codespace || (prev.Codespace == cur.Codespace && prev.Code < cur.Code),
+			"codes not sorted at %d", i)
+	}
+}
+
+// uncomparableError cannot be used as a map key
+type uncomparableError struct {
+	causes []error
+}
+
+func (e uncomparableError) Error() string { return "uncomparable" }
+
+func (e uncomparableError) Unwrap() []error { return e.causes }
+
+func TestRegister(t *testing.T) {
+	errA := errors.New("a")
+	errB := errors.New("b")
+
+	require.NoError(t, Register("register-test", 2, errA))
+	require.ErrorIs(t, Register("register-test", 2, errB), ErrDuplicateCode)
+	require.ErrorIs(t, Register("register-test", 3, errA), ErrDuplicateCode)
+	require.NoError(t, Register("other-space", 2, errB))
+
+	require.Error(t, Register("", 3, errors.New("c")))
+	require.Error(t, Register("register-test", CodeOK, errors.New("c")))
+	require.Error(t, Register("register-test", CodeInternal, errors.New("c")))
+	require.Error(t, Register("register-test", 4, nil))
+	require.Error(t, Register("register-test", 4, uncomparableError{}))
+
+	// Non-comparable links in a chain are skipped, not looked up
+	codespace, code := ABCICode(uncomparableError{causes: []error{errA}})
+	assert.Equal(t, "register-test", codespace)
+	assert.Equal(t, uint32(2), code)
+
+	assert.Panics(t, func() { MustRegister("register-test", 2, errors.New("d")) })
+}
+
+func TestWrap(t *testing.T) {
+	assert.Nil(t, Wrap(nil, "context"))
+	assert.Nil(t, Wrapf(nil, "context %d", 1))
+	assert.Nil(t, WithCode(nil, "errors-test", 9))
+
+	err := Wrapf(errFirst, "account %s", "alice")
+	assert.ErrorIs(t, err, errFirst)
+	assert.EqualError(t, err, "first: account alice")
+
+	coded := WithCode(errSecond, "errors-test", 9)
+	assert.ErrorIs(t, coded, errSecond)
+	assert.EqualError(t, coded, "second")
+}
+
+func TestRedact(t *testing.T) {
+	assert.Nil(t, Redact(nil))
+
+	assert.Equal(t, errFirst, Redact(Wrap(errFirst, "detail")))
+
+	// Unregistered text wrapped in a registered error is dropped
+	nondeterministic := fmt.Errorf("%w: %v", errFirst, fmt.Errorf("open /var/data/%p: too many open files", &struct{}{}))
+	assert.Equal(t, errFirst, Redact(nondeterministic))
+	assert.EqualError(t, Redact(nondeterministic), "first")
+
+	coded := WithCode(errors.New("io: read 0xc000123"), "errors-test", 9)
+	redacted := Redact(coded)
+	assert.EqualError(t, redacted, "errors-test error 9")
+	codespace, code := ABCICode(redacted)
+	assert.Equal(t, "errors-test", codespace)
+	assert.Equal(t, uint32(9), code)
+	assert.Equal(t, errSecond, Redact(WithCode(errors.New("io"), "errors-test", 3)))
+
+	assert.Equal(t, ErrInternal, Redact(fmt.Errorf("read /tmp/x: %w", errors.New("permission denied"))))
+}
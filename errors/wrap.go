@@ -0,0 +1,74 @@
+package errors
+
+import "fmt"
+
+// Wrap annotates err with description, keeping err in the chain.
+// The message reads "<err>: <description>". A nil err yields nil.
+//
+// Consensus results carry only the registered error of the chain (see
+// Redact), so description is free to hold node-local detail.
+func Wrap(err error, description string) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", err, description)
+}
+
+// Wrapf is Wrap with a formatted description
+func Wrapf(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return Wrap(err, fmt.Sprintf(format, args...))
+}
+
+// codedError carries an explicit codespace and code
+type codedError struct {
+	err       error
+	codespace string
+	code      uint32
+}
+
+// Error implements error
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+// WithCode returns err reporting codespace/code from ABCICode, for errors from
+// packages that do not register their own. The code should be registered (to
+// a sentinel describing the failure) so clients can decode it; ABCICode
+// reports it either way. A nil err yields nil.
+func WithCode(err error, codespace string, code uint32) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{err: err, codespace: codespace, code: code}
+}
+
+// Redact returns the registered error identifying err: the sentinel of its
+// code (see ABCICode), or ErrInternal if it has none. The rest of err's text,
+// including any wrapped description, is dropped. A nil err yields nil.
+//
+// Text outside the registered messages may come from outside the SDK's
+// control (I/O, encoding libraries, panics), even when wrapped in a
+// registered error, and may differ between nodes. Consensus results must not
+// include it; the original should be logged locally instead.
+func Redact(err error) error {
+	if err == nil {
+		return nil
+	}
+	codespace, code := ABCICode(err)
+	if code == CodeInternal {
+		return ErrInternal
+	}
+	if sentinel := FromCode(codespace, code); sentinel != nil {
+		return sentinel
+	}
+	// A WithCode code without a registered sentinel
+	return WithCode(fmt.Errorf("%s error %d", codespace, code), codespace, code)
+}
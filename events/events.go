@@ -0,0 +1,105 @@
+// Package events decodes and filters the events of transaction results.
+//
+// Standard events emitted by the SDK modules decode into typed values
+// (DecodeTransfer, DecodeAccountCreated, DecodeDelegate) through the
+// attribute helpers Value, String and Uint64; FromEffect converts the events
+// of an effect execution result. Filters compose with And, Or and Not, so
+// the same predicate can select events in an indexer and in a client
+// following a chain.
+package events
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+var (
+	// ErrUnexpectedType is returned when decoding an event of another type
+	ErrUnexpectedType = errors.New("unexpected event type")
+
+	// ErrMissingAttribute is returned when a required attribute is absent
+	ErrMissingAttribute = errors.New("missing event attribute")
+
+	// ErrInvalidAttribute is returned when an attribute cannot be decoded
+	ErrInvalidAttribute = errors.New("invalid event attribute")
+)
+
+// AttributeHeight is the block height attribute of the standard events
+const AttributeHeight = "height"
+
+// FromEffect converts an event of an effect execution result to the
+// transaction result form. Attributes are sorted by key so results do not
+// depend on map iteration order.
+func FromEffect(event effects.Event) types.Event {
+	keys := make([]string, 0, len(event.Attributes))
+	for key := range event.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	txEvent := types.NewEvent(event.Type)
+	for _, key := range keys {
+		txEvent.AddAttribute(key, event.Attributes[key])
+	}
+	return txEvent
+}
+
+// Value returns the value of the first attribute of event named key
+func Value(event types.Event, key string) ([]byte, bool) {
+	for _, attr := range event.Attributes {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+	return nil, false
+}
+
+// String returns the value of the attribute key as a string, or
+// ErrMissingAttribute
+func String(event types.Event, key string) (string, error) {
+	value, ok := Value(event, key)
+	if !ok {
+		return "", fmt.Errorf("%w: %s.%s", ErrMissingAttribute, event.Type, key)
+	}
+	return string(value), nil
+}
+
+// Uint64 returns the value of the attribute key parsed as a decimal
+// uint64, or ErrMissingAttribute or ErrInvalidAttribute
+func Uint64(event types.Event, key string) (uint64, error) {
+	s, err := String(event, key)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s.%s: %v", ErrInvalidAttribute, event.Type, key, err)
+	}
+	return n, nil
+}
+
+// account returns the value of the attribute key as a valid account name
+func account(event types.Event, key string) (types.AccountName, error) {
+	s, err := String(event, key)
+	if err != nil {
+		return "", err
+	}
+	name := types.AccountName(s)
+	if !name.IsValid() {
+		return "", fmt.Errorf("%w: %s.%s: invalid account name %q", ErrInvalidAttribute, event.Type, key, s)
+	}
+	return name, nil
+}
+
+// checkType returns ErrUnexpectedType unless event is of type typ
+func checkType(event types.Event, typ string) error {
+	if event.Type != typ {
+		return fmt.Errorf("%w: %s, want %s", ErrUnexpectedType, event.Type, typ)
+	}
+	return nil
+}
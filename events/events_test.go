@@ -0,0 +1,106 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// newEvent builds an event with string attributes in the given order
+func newEvent(typ string, kv ...string) types.Event {
+	event := types.NewEvent(typ)
+	for i := 0; i+1 < len(kv); i += 2 {
+		event.AddAttribute(kv[i], []byte(kv[i+1]))
+	}
+	return event
+}
+
+func transferEvent(from, to string) types.Event {
+	return newEvent(TypeTransfer, "amount", "250", "denom", "stake", "from", from, "height", "7", "to", to)
+}
+
+func TestFromEffect(t *testing.T) {
+	event := FromEffect(effects.Event{
+		Type:       TypeAccountCreated,
+		Attributes: map[string][]byte{"height": []byte("3"), "account": []byte("alice")},
+	})
+
+	assert.Equal(t, newEvent(TypeAccountCreated, "account", "alice", "height", "3"), event)
+}
+
+func TestAttributeHelpers(t *testing.T) {
+	event := newEvent("test", "name", "alice", "count", "42", "count", "43", "bad", "-1")
+
+	value, ok := Value(event, "name")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("alice"), value)
+	_, ok = Value(event, "absent")
+	assert.False(t, ok)
+
+	s, err := String(event, "name")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", s)
+	_, err = String(event, "absent")
+	assert.ErrorIs(t, err, ErrMissingAttribute)
+
+	// The first attribute with a key wins
+	n, err := Uint64(event, "count")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), n)
+	_, err = Uint64(event, "bad")
+	assert.ErrorIs(t, err, ErrInvalidAttribute)
+	_, err = Uint64(event, "absent")
+	assert.ErrorIs(t, err, ErrMissingAttribute)
+}
+
+func TestDecodeTransfer(t *testing.T) {
+	transfer, err := DecodeTransfer(transferEvent("alice", "bob"))
+	require.NoError(t, err)
+	assert.Equal(t, Transfer{From: "alice", To: "bob", Amount: types.NewCoin("stake", 250), Height: 7}, transfer)
+
+	tests := []struct {
+		name    string
+		event   types.Event
+		wantErr error
+	}{
+		{"wrong type", newEvent(TypeDelegate), ErrUnexpectedType},
+		{"missing to", newEvent(TypeTransfer, "from", "alice"), ErrMissingAttribute},
+		{"invalid account", transferEvent("alice", "Not Valid!"), ErrInvalidAttribute},
+		{"invalid amount", newEvent(TypeTransfer, "from", "alice", "to", "bob", "denom", "stake", "amount", "x"), ErrInvalidAttribute},
+		{"empty denom", newEvent(TypeTransfer, "from", "alice", "to", "bob", "denom", "", "amount", "1"), ErrInvalidAttribute},
+		{"missing height", newEvent(TypeTransfer, "from", "alice", "to", "bob", "denom", "stake", "amount", "1"), ErrMissingAttribute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeTransfer(tt.event)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestDecodeAccountCreated(t *testing.T) {
+	created, err := DecodeAccountCreated(newEvent(TypeAccountCreated, "account", "carol", "height", "12"))
+	require.NoError(t, err)
+	assert.Equal(t, AccountCreated{Account: "carol", Height: 12}, created)
+
+	_, err = DecodeAccountCreated(transferEvent("alice", "bob"))
+	assert.ErrorIs(t, err, ErrUnexpectedType)
+	_, err = DecodeAccountCreated(newEvent(TypeAccountCreated, "height", "12"))
+	assert.ErrorIs(t, err, ErrMissingAttribute)
+}
+
+func TestDecodeDelegate(t *testing.T) {
+	event := newEvent(TypeDelegate, "amount", "100", "delegator", "alice", "denom", "stake", "height", "5", "validator", "0a0b")
+	delegate, err := DecodeDelegate(event)
+	require.NoError(t, err)
+	assert.Equal(t, Delegate{Delegator: "alice", Validator: []byte{0x0a, 0x0b}, Amount: types.NewCoin("stake", 100), Height: 5}, delegate)
+
+	for _, validator := range []string{"zz", ""} {
+		_, err = DecodeDelegate(newEvent(TypeDelegate, "delegator", "alice", "validator", validator))
+		assert.ErrorIs(t, err, ErrInvalidAttribute, "validator %q", validator)
+	}
+}
@@ -0,0 +1,107 @@
+package events
+
+import (
+	"bytes"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Filter reports whether an event matches. Filters are pure functions of the
+// event, so the same filter selects the same events in an indexer and in a
+// subscriber.
+//
+// A nil Filter matches every event.
+type Filter func(event types.Event) bool
+
+// Match reports whether event matches f
+func (f Filter) Match(event types.Event) bool {
+	return f == nil || f(event)
+}
+
+// Select returns the events matching f, in order
+func (f Filter) Select(events []types.Event) []types.Event {
+	selected := make([]types.Event, 0, len(events))
+	for _, event := range events {
+		if f.Match(event) {
+			selected = append(selected, event)
+		}
+	}
+	return selected
+}
+
+// Any matches every event
+func Any() Filter {
+	return func(types.Event) bool { return true }
+}
+
+// Type matches events of any of the given types
+func Type(eventTypes ...string) Filter {
+	set := make(map[string]bool, len(eventTypes))
+	for _, typ := range eventTypes {
+		set[typ] = true
+	}
+	return func(event types.Event) bool {
+		return set[event.Type]
+	}
+}
+
+// HasAttribute matches events with an attribute named key
+func HasAttribute(key string) Filter {
+	return func(event types.Event) bool {
+		_, ok := Value(event, key)
+		return ok
+	}
+}
+
+// AttributeEquals matches events whose attribute key has value
+func AttributeEquals(key string, value []byte) Filter {
+	value = bytes.Clone(value)
+	return func(event types.Event) bool {
+		v, ok := Value(event, key)
+		return ok && bytes.Equal(v, value)
+	}
+}
+
+// Involves matches the standard events that name account: as the sender or
+// recipient of a transfer, the created account or the delegator
+func Involves(account types.AccountName) Filter {
+	name := []byte(account)
+	return Or(
+		And(Type(TypeTransfer), Or(AttributeEquals(AttributeFrom, name), AttributeEquals(AttributeTo, name))),
+		And(Type(TypeAccountCreated), AttributeEquals(AttributeAccount, name)),
+		And(Type(TypeDelegate), AttributeEquals(AttributeDelegator, name)),
+	)
+}
+
+// And matches events matching all filters; with no filters it matches every
+// event
+func And(filters ...Filter) Filter {
+	return func(event types.Event) bool {
+		for _, f := range filters {
+			if !f.Match(event) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches events matching any of filters; with no filters it matches no
+// event
+func Or(filters ...Filter) Filter {
+	return func(event types.Event) bool {
+		for _, f := range filters {
+			if f.Match(event) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches events not matching f
+func Not(f Filter) Filter {
+	return func(event types.Event) bool {
+		return !f.Match(event)
+	}
+}
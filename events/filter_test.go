@@ -0,0 +1,48 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+func TestFilter_Match(t *testing.T) {
+	send := transferEvent("alice", "bob")
+	created := newEvent(TypeAccountCreated, "account", "bob", "height", "1")
+	delegated := newEvent(TypeDelegate, "delegator", "carol", "validator", "0a")
+	other := newEvent("nft.minted", "owner", "alice")
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []types.Event
+	}{
+		{"nil", nil, []types.Event{send, created, delegated, other}},
+		{"any", Any(), []types.Event{send, created, delegated, other}},
+		{"type", Type(TypeTransfer, TypeDelegate), []types.Event{send, delegated}},
+		{"has attribute", HasAttribute("owner"), []types.Event{other}},
+		{"attribute equals", AttributeEquals("account", []byte("bob")), []types.Event{created}},
+		{"involves bob", Involves("bob"), []types.Event{send, created}},
+		{"involves carol", Involves("carol"), []types.Event{delegated}},
+		{"and", And(Type(TypeTransfer), AttributeEquals(AttributeTo, []byte("alice"))), []types.Event{}},
+		{"empty and", And(), []types.Event{send, created, delegated, other}},
+		{"or", Or(Type(TypeAccountCreated), HasAttribute("owner")), []types.Event{created, other}},
+		{"empty or", Or(), []types.Event{}},
+		{"not", Not(Type(TypeTransfer, TypeAccountCreated)), []types.Event{delegated, other}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Select([]types.Event{send, created, delegated, other}))
+		})
+	}
+}
+
+func TestAttributeEquals_CopiesValue(t *testing.T) {
+	value := []byte("bob")
+	filter := AttributeEquals(AttributeTo, value)
+	value[0] = 'x'
+
+	assert.True(t, filter.Match(transferEvent("alice", "bob")))
+}
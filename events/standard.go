@@ -0,0 +1,144 @@
+package events
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Standard event types emitted by the SDK modules
+const (
+	// TypeTransfer is emitted by the bank module for a MsgSend
+	TypeTransfer = "bank.send"
+
+	// TypeAccountCreated is emitted by the auth module for a new account
+	TypeAccountCreated = "account.created"
+
+	// TypeDelegate is emitted by the staking module for a MsgDelegate
+	TypeDelegate = "staking.delegated"
+)
+
+// Attribute keys of the standard events
+const (
+	AttributeFrom      = "from"
+	AttributeTo        = "to"
+	AttributeDenom     = "denom"
+	AttributeAmount    = "amount"
+	AttributeAccount   = "account"
+	AttributeDelegator = "delegator"
+	AttributeValidator = "validator"
+)
+
+// Transfer is a decoded TypeTransfer event
+type Transfer struct {
+	From   types.AccountName
+	To     types.AccountName
+	Amount types.Coin
+	Height uint64
+}
+
+// DecodeTransfer decodes a TypeTransfer event
+func DecodeTransfer(event types.Event) (Transfer, error) {
+	if err := checkType(event, TypeTransfer); err != nil {
+		return Transfer{}, err
+	}
+
+	from, err := account(event, AttributeFrom)
+	if err != nil {
+		return Transfer{}, err
+	}
+	to, err := account(event, AttributeTo)
+	if err != nil {
+		return Transfer{}, err
+	}
+	amount, err := coin(event)
+	if err != nil {
+		return Transfer{}, err
+	}
+	height, err := Uint64(event, AttributeHeight)
+	if err != nil {
+		return Transfer{}, err
+	}
+	return Transfer{From: from, To: to, Amount: amount, Height: height}, nil
+}
+
+// AccountCreated is a decoded TypeAccountCreated event
+type AccountCreated struct {
+	Account types.AccountName
+	Height  uint64
+}
+
+// DecodeAccountCreated decodes a TypeAccountCreated event
+func DecodeAccountCreated(event types.Event) (AccountCreated, error) {
+	if err := checkType(event, TypeAccountCreated); err != nil {
+		return AccountCreated{}, err
+	}
+
+	name, err := account(event, AttributeAccount)
+	if err != nil {
+		return AccountCreated{}, err
+	}
+	height, err := Uint64(event, AttributeHeight)
+	if err != nil {
+		return AccountCreated{}, err
+	}
+	return AccountCreated{Account: name, Height: height}, nil
+}
+
+// Delegate is a decoded TypeDelegate event
+type Delegate struct {
+	Delegator types.AccountName
+
+	// Validator is the validator's public key
+	Validator []byte
+
+	Amount types.Coin
+	Height uint64
+}
+
+// DecodeDelegate decodes a TypeDelegate event
+func DecodeDelegate(event types.Event) (Delegate, error) {
+	if err := checkType(event, TypeDelegate); err != nil {
+		return Delegate{}, err
+	}
+
+	delegator, err := account(event, AttributeDelegator)
+	if err != nil {
+		return Delegate{}, err
+	}
+	validatorHex, err := String(event, AttributeValidator)
+	if err != nil {
+		return Delegate{}, err
+	}
+	validator, err := hex.DecodeString(validatorHex)
+	if err != nil || len(validator) == 0 {
+		return Delegate{}, fmt.Errorf("%w: %s.%s: invalid validator key %q", ErrInvalidAttribute, event.Type, AttributeValidator, validatorHex)
+	}
+	amount, err := coin(event)
+	if err != nil {
+		return Delegate{}, err
+	}
+	height, err := Uint64(event, AttributeHeight)
+	if err != nil {
+		return Delegate{}, err
+	}
+	return Delegate{Delegator: delegator, Validator: validator, Amount: amount, Height: height}, nil
+}
+
+// coin decodes the denom and amount attributes of event
+func coin(event types.Event) (types.Coin, error) {
+	denom, err := String(event, AttributeDenom)
+	if err != nil {
+		return types.Coin{}, err
+	}
+	amount, err := Uint64(event, AttributeAmount)
+	if err != nil {
+		return types.Coin{}, err
+	}
+	c := types.NewCoin(denom, amount)
+	if !c.IsValid() {
+		return types.Coin{}, fmt.Errorf("%w: %s: invalid coin %s", ErrInvalidAttribute, event.Type, c)
+	}
+	return c, nil
+}
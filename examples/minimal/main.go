@@ -6,6 +6,7 @@ import (
 	"log"
 
 	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/module"
 	"github.com/blockberries/punnet-sdk/modules/auth"
 	"github.com/blockberries/punnet-sdk/modules/bank"
 	"github.com/blockberries/punnet-sdk/store"
@@ -21,44 +22,58 @@ func main() {
 	fmt.Println("1. Creating memory backing store...")
 	backing := store.NewMemoryStore()
 
-	// Step 2: Create capability manager
-	fmt.Println("2. Creating capability manager...")
+	// Step 2: Create capability manager and module manager
+	fmt.Println("2. Creating capability and module managers...")
 	capManager := capability.NewCapabilityManager(backing)
+	mm := module.NewModuleManager(capManager)
 
-	// Step 3: Register modules
+	// Step 3: Register modules with their dependencies and capabilities
 	fmt.Println("3. Registering modules...")
-	if err := capManager.RegisterModule("auth"); err != nil {
+	if err := mm.Register(auth.Spec()); err != nil {
 		log.Fatalf("Failed to register auth module: %v", err)
 	}
-	if err := capManager.RegisterModule("bank"); err != nil {
+	if err := mm.Register(bank.Spec()); err != nil {
 		log.Fatalf("Failed to register bank module: %v", err)
 	}
 
-	// Step 4: Grant capabilities
-	fmt.Println("4. Granting capabilities...")
-	accountCap, err := capManager.GrantAccountCapability("auth")
+	// Step 4: Build: resolve ordering, grant capabilities, create modules
+	fmt.Println("4. Building modules (granting capabilities)...")
+	if err := mm.Build(); err != nil {
+		log.Fatalf("Failed to build modules: %v", err)
+	}
+	fmt.Printf("   - Initialization order: %v\n", mm.InitOrder())
+
+	// Step 5: Look up modules and their capabilities
+	fmt.Println("5. Looking up modules...")
+	authMod, err := mm.Module(auth.ModuleName)
 	if err != nil {
-		log.Fatalf("Failed to grant account capability: %v", err)
+		log.Fatalf("Failed to get auth module: %v", err)
 	}
+	fmt.Printf("   - Auth module: %s\n", authMod.Name())
 
-	balanceCap, err := capManager.GrantBalanceCapability("bank")
+	bankMod, err := mm.Module(bank.ModuleName)
 	if err != nil {
-		log.Fatalf("Failed to grant balance capability: %v", err)
+		log.Fatalf("Failed to get bank module: %v", err)
 	}
+	fmt.Printf("   - Bank module: %s\n", bankMod.Name())
 
-	// Step 5: Create modules
-	fmt.Println("5. Creating modules...")
-	authMod, err := auth.CreateModule(accountCap)
+	authCaps, err := mm.Capabilities(auth.ModuleName)
 	if err != nil {
-		log.Fatalf("Failed to create auth module: %v", err)
+		log.Fatalf("Failed to get auth capabilities: %v", err)
+	}
+	accountCap, err := authCaps.Account()
+	if err != nil {
+		log.Fatalf("Failed to get account capability: %v", err)
 	}
-	fmt.Printf("   - Auth module: %s\n", authMod.Name())
 
-	bankMod, err := bank.CreateModule(balanceCap)
+	bankCaps, err := mm.Capabilities(bank.ModuleName)
 	if err != nil {
-		log.Fatalf("Failed to create bank module: %v", err)
+		log.Fatalf("Failed to get bank capabilities: %v", err)
+	}
+	balanceCap, err := bankCaps.Balance()
+	if err != nil {
+		log.Fatalf("Failed to get balance capability: %v", err)
 	}
-	fmt.Printf("   - Bank module: %s\n", bankMod.Name())
 
 	// Step 6: Demonstrate account creation
 	fmt.Println()
@@ -160,9 +175,9 @@ func main() {
 	fmt.Println("=== Summary ===")
 	fmt.Println("This example demonstrated:")
 	fmt.Println("  1. Creating a backing store")
-	fmt.Println("  2. Setting up the capability manager")
+	fmt.Println("  2. Setting up the capability and module managers")
 	fmt.Println("  3. Registering modules (auth, bank)")
-	fmt.Println("  4. Granting capabilities to modules")
+	fmt.Println("  4. Building modules in dependency order with declared capabilities")
 	fmt.Println("  5. Creating accounts")
 	fmt.Println("  6. Managing balances")
 	fmt.Println("  7. Transferring tokens")
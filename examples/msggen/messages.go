@@ -0,0 +1,55 @@
+// Package msggen shows messages whose SignDocSerializable implementation is
+// generated by punnet-gen msg (see messages_msggen.go).
+package msggen
+
+import "github.com/blockberries/punnet-sdk/types"
+
+//go:generate go run ../../cmd/punnet-gen msg -type MsgTransfer,MsgSetProfile -typeprefix /punnet.example.v1.
+
+// Tag is a free-form profile label
+type Tag string
+
+// MsgTransfer transfers coins with an optional memo
+type MsgTransfer struct {
+	// From is the sender account
+	From types.AccountName `json:"from" punnet:"signer"`
+
+	// To is the recipient account
+	To types.AccountName `json:"to"`
+
+	// Amount is the amount to transfer
+	Amount types.Coins `json:"amount" punnet:"required"`
+
+	// Memo is an optional note
+	Memo string `json:"memo"`
+
+	// Sequence orders transfers between the same accounts
+	Sequence uint64 `json:"sequence"`
+}
+
+// MsgSetProfile sets an account's profile, approved by its guardians
+type MsgSetProfile struct {
+	// Account is the profile owner
+	Account types.AccountName `json:"account" punnet:"signer"`
+
+	// Guardians must co-sign profile changes
+	Guardians []types.AccountName `json:"guardians" punnet:"signer,required"`
+
+	// DisplayName is the profile name
+	DisplayName string `json:"display_name" punnet:"required"`
+
+	// Tags are profile labels
+	Tags []Tag `json:"tags"`
+
+	// Avatar is the profile image
+	Avatar []byte `json:"avatar"`
+
+	// Public makes the profile visible to queries
+	Public bool `json:"public"`
+
+	// Priority orders profiles in listings
+	Priority int32 `json:"priority"`
+
+	// Referrer is the account that referred the owner, if any
+	Referrer types.AccountName `json:"referrer" punnet:"optional"`
+}
@@ -0,0 +1,354 @@
+package light
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// DefaultMaxTrustedBlocks is the default number of verified light blocks a
+// Client keeps
+const DefaultMaxTrustedBlocks = 1000
+
+// Provider serves light blocks, typically from a full node's RPC
+type Provider interface {
+	// LightBlock returns the light block at height; height 0 means the
+	// latest block
+	LightBlock(ctx context.Context, height uint64) (*LightBlock, error)
+}
+
+// TrustOptions is the trust root of a Client, obtained out of band (e.g.
+// from a block explorer or a trusted friend)
+type TrustOptions struct {
+	// Period is the trusting period: how long after its time a verified
+	// header can serve as a trust root. It must be shorter than the period in
+	// which misbehaving validators can still be punished.
+	Period time.Duration
+
+	// Height is the height of the trusted header
+	Height uint64
+
+	// Hash is the hash of the trusted header
+	Hash []byte
+}
+
+// ValidateBasic performs stateless validation
+func (o TrustOptions) ValidateBasic() error {
+	if o.Period <= 0 {
+		return fmt.Errorf("trusting period must be positive")
+	}
+	if o.Height == 0 {
+		return fmt.Errorf("trusted height cannot be zero")
+	}
+	if len(o.Hash) != HashSize {
+		return fmt.Errorf("trusted hash must be %d bytes", HashSize)
+	}
+	return nil
+}
+
+// Client verifies light blocks served by a Provider against a trust root.
+//
+// Thread-safe: All methods are safe for concurrent use; verifications are
+// serialized.
+type Client struct {
+	provider   Provider
+	verifier   verifier
+	now        func() time.Time
+	maxTrusted int
+
+	mu      sync.Mutex
+	trusted map[uint64]*LightBlock
+}
+
+// ClientOption configures a Client
+type ClientOption func(*Client)
+
+// WithTrustLevel sets the trust level for non-adjacent headers, within
+// [1/3, 1] (default DefaultTrustLevel)
+func WithTrustLevel(level types.Ratio) ClientOption {
+	return func(c *Client) {
+		c.verifier.trustLevel = level
+	}
+}
+
+// WithMaxClockDrift sets how far header times may be ahead of the local clock
+// (default DefaultMaxClockDrift)
+func WithMaxClockDrift(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.verifier.maxClockDrift = d
+	}
+}
+
+// WithAggregateVerifier sets the verifier of aggregated (e.g. BLS) commit
+// signatures
+func WithAggregateVerifier(agg AggregateVerifier) ClientOption {
+	return func(c *Client) {
+		c.verifier.aggregate = agg
+	}
+}
+
+// WithMaxTrustedBlocks sets how many verified light blocks are kept (default
+// DefaultMaxTrustedBlocks). The lowest heights are dropped first; the latest
+// block is always kept.
+func WithMaxTrustedBlocks(n int) ClientOption {
+	return func(c *Client) {
+		c.maxTrusted = n
+	}
+}
+
+// WithClock replaces the clock used for trusting period and clock drift
+// checks
+func WithClock(now func() time.Time) ClientOption {
+	return func(c *Client) {
+		c.now = now
+	}
+}
+
+// NewClient creates a client for chainID. It fetches the light block at
+// trust.Height, checks it against trust.Hash and verifies its commit.
+//
+// Returns ErrOldHeaderExpired if the trusted header is already outside the
+// trusting period.
+func NewClient(ctx context.Context, chainID string, trust TrustOptions, provider Provider, opts ...ClientOption) (*Client, error) {
+	if chainID == "" {
+		return nil, fmt.Errorf("chain ID cannot be empty")
+	}
+	if err := trust.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid trust options: %w", err)
+	}
+	if provider == nil {
+		return nil, fmt.Errorf("provider cannot be nil")
+	}
+
+	c := &Client{
+		provider: provider,
+		verifier: verifier{
+			chainID:        chainID,
+			trustingPeriod: trust.Period,
+			trustLevel:     DefaultTrustLevel,
+			maxClockDrift:  DefaultMaxClockDrift,
+		},
+		now:        time.Now,
+		maxTrusted: DefaultMaxTrustedBlocks,
+		trusted:    make(map[uint64]*LightBlock),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if err := validateTrustLevel(c.verifier.trustLevel); err != nil {
+		return nil, err
+	}
+	if c.verifier.maxClockDrift < 0 {
+		return nil, fmt.Errorf("max clock drift cannot be negative")
+	}
+	if c.maxTrusted < 1 {
+		return nil, fmt.Errorf("max trusted blocks must be positive")
+	}
+	if c.now == nil {
+		return nil, fmt.Errorf("clock cannot be nil")
+	}
+
+	lb, err := c.fetch(ctx, trust.Height)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(lb.Header.Hash(), trust.Hash) {
+		return nil, fmt.Errorf("%w: header at height %d does not match the trusted hash", ErrHeaderMismatch, trust.Height)
+	}
+	if err := c.verifier.checkTrusted(lb, c.now()); err != nil {
+		return nil, err
+	}
+	if err := VerifyCommit(lb.Validators, lb.Header, lb.Commit, c.verifier.aggregate); err != nil {
+		return nil, err
+	}
+
+	c.store(lb)
+	return c, nil
+}
+
+// ChainID returns the chain the client verifies
+func (c *Client) ChainID() string {
+	return c.verifier.chainID
+}
+
+// LatestTrusted returns the highest verified light block
+func (c *Client) LatestTrusted() *LightBlock {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.trusted[c.heights()[len(c.trusted)-1]]
+}
+
+// TrustedLightBlock returns the verified light block at height, if any
+func (c *Client) TrustedLightBlock(height uint64) (*LightBlock, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	lb, ok := c.trusted[height]
+	return lb, ok
+}
+
+// Update verifies the provider's latest light block and returns it, or the
+// latest trusted block if the provider has nothing newer
+func (c *Client) Update(ctx context.Context) (*LightBlock, error) {
+	latest, err := c.fetch(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	trusted := c.trusted[c.heights()[len(c.trusted)-1]]
+	if latest.Height() <= trusted.Height() {
+		return trusted, nil
+	}
+	if err := c.verifySkipping(ctx, trusted, latest); err != nil {
+		return nil, err
+	}
+	return latest, nil
+}
+
+// VerifyLightBlockAtHeight returns the verified light block at height.
+//
+// Heights above a trusted block are verified forward from the closest one
+// below, bisecting as needed; heights below every trusted block are verified
+// backwards through the header hash chain.
+func (c *Client) VerifyLightBlockAtHeight(ctx context.Context, height uint64) (*LightBlock, error) {
+	if height == 0 {
+		return nil, fmt.Errorf("height cannot be zero")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if lb, ok := c.trusted[height]; ok {
+		return lb, nil
+	}
+
+	heights := c.heights()
+	i := sort.Search(len(heights), func(i int) bool { return heights[i] > height })
+	if i == 0 {
+		return c.verifyBackwards(ctx, c.trusted[heights[0]], height)
+	}
+
+	target, err := c.fetch(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.verifySkipping(ctx, c.trusted[heights[i-1]], target); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// verifySkipping verifies target from trusted, bisecting the height range
+// whenever a header cannot be trusted directly. Verified blocks are stored.
+//
+// PRECONDITION: c.mu is held.
+//
+// Complexity: O(log(h)) light block fetches and verifications for a height
+// difference h while validator sets change gradually; O(h) if the whole
+// validator set is replaced every block.
+func (c *Client) verifySkipping(ctx context.Context, trusted, target *LightBlock) error {
+	verified := trusted
+	pending := []*LightBlock{target}
+	for {
+		next := pending[len(pending)-1]
+		err := c.verifier.verify(verified, next, c.now())
+		switch {
+		case err == nil:
+			c.store(next)
+			if next == target {
+				return nil
+			}
+			verified = next
+			pending = pending[:len(pending)-1]
+
+		case errors.Is(err, ErrNewValSetCantBeTrusted):
+			pivot, err := c.fetch(ctx, verified.Height()+(next.Height()-verified.Height())/2)
+			if err != nil {
+				return err
+			}
+			pending = append(pending, pivot)
+
+		default:
+			return fmt.Errorf("failed to verify height %d from trusted height %d: %w", next.Height(), verified.Height(), err)
+		}
+	}
+}
+
+// verifyBackwards verifies the light block at height, below every trusted
+// block, by walking the header hash chain down from trusted. Only the block
+// at height is stored.
+//
+// PRECONDITION: c.mu is held.
+func (c *Client) verifyBackwards(ctx context.Context, trusted *LightBlock, height uint64) (*LightBlock, error) {
+	if err := c.verifier.checkTrusted(trusted, c.now()); err != nil {
+		return nil, err
+	}
+
+	verified := trusted
+	for verified.Height() > height {
+		lb, err := c.fetch(ctx, verified.Height()-1)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.verifier.verifyBackwards(verified, lb); err != nil {
+			return nil, fmt.Errorf("failed to verify height %d backwards: %w", lb.Height(), err)
+		}
+		verified = lb
+	}
+
+	c.store(verified)
+	return verified, nil
+}
+
+// fetch fetches the light block at height (0 for the latest) and checks it
+// is well-formed and at the requested height
+func (c *Client) fetch(ctx context.Context, height uint64) (*LightBlock, error) {
+	lb, err := c.provider.LightBlock(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch light block at height %d: %w", height, err)
+	}
+	if err := lb.ValidateBasic(c.verifier.chainID); err != nil {
+		return nil, err
+	}
+	if height != 0 && lb.Height() != height {
+		return nil, fmt.Errorf("%w: provider returned height %d for height %d", ErrHeaderMismatch, lb.Height(), height)
+	}
+	return lb, nil
+}
+
+// store adds a verified light block, dropping the lowest heights beyond
+// maxTrusted.
+//
+// PRECONDITION: c.mu is held, or the client is not yet shared.
+func (c *Client) store(lb *LightBlock) {
+	c.trusted[lb.Height()] = lb
+	if len(c.trusted) <= c.maxTrusted {
+		return
+	}
+	heights := c.heights()
+	for _, h := range heights[:len(heights)-c.maxTrusted] {
+		delete(c.trusted, h)
+	}
+}
+
+// heights returns the trusted heights in ascending order.
+//
+// PRECONDITION: c.mu is held.
+func (c *Client) heights() []uint64 {
+	heights := make([]uint64, 0, len(c.trusted))
+	for h := range c.trusted {
+		heights = append(heights, h)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights
+}
@@ -0,0 +1,182 @@
+package light
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// staticSet returns a validator set function with the same signers at every
+// height
+func staticSet(signers []testSigner) func(uint64) []testSigner {
+	return func(uint64) []testSigner { return signers }
+}
+
+// slidingSet returns a validator set function where the set at height h is
+// pool[h-1 : h+3]: one of four validators is replaced every block, so a set
+// shares more than 1/3 of its power only with sets up to two blocks away
+func slidingSet(pool []testSigner) func(uint64) []testSigner {
+	return func(h uint64) []testSigner { return pool[h-1 : h+3] }
+}
+
+func newTestClient(t *testing.T, p *testProvider, height uint64, now func() time.Time, opts ...ClientOption) *Client {
+	t.Helper()
+	trust := TrustOptions{Period: 24 * time.Hour, Height: height, Hash: p.chain.at(height).Header.Hash()}
+	c, err := NewClient(context.Background(), testChainID, trust, p, append([]ClientOption{WithClock(now)}, opts...)...)
+	require.NoError(t, err)
+	return c
+}
+
+func TestNewClient(t *testing.T) {
+	chain := newTestChain(t, 3, staticSet(newTestSigners(t, 4, 10)))
+	ctx := context.Background()
+	trust := TrustOptions{Period: time.Hour, Height: 2, Hash: chain.at(2).Header.Hash()}
+
+	c, err := NewClient(ctx, testChainID, trust, newTestProvider(chain), WithClock(fixedClock(3, 0)))
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), c.LatestTrusted().Height())
+	require.Equal(t, testChainID, c.ChainID())
+
+	wrongHash := trust
+	wrongHash.Hash = chain.at(1).Header.Hash()
+	_, err = NewClient(ctx, testChainID, wrongHash, newTestProvider(chain), WithClock(fixedClock(3, 0)))
+	require.ErrorIs(t, err, ErrHeaderMismatch)
+
+	_, err = NewClient(ctx, testChainID, trust, newTestProvider(chain), WithClock(fixedClock(2, time.Hour)))
+	require.ErrorIs(t, err, ErrOldHeaderExpired)
+
+	_, err = NewClient(ctx, "other-chain", trust, newTestProvider(chain), WithClock(fixedClock(3, 0)))
+	require.ErrorIs(t, err, ErrHeaderMismatch)
+
+	_, err = NewClient(ctx, testChainID, trust, newTestProvider(chain), WithTrustLevel(types.Ratio{Numerator: 1, Denominator: 4}))
+	require.Error(t, err)
+
+	_, err = NewClient(ctx, testChainID, TrustOptions{Height: 2, Hash: trust.Hash}, newTestProvider(chain))
+	require.Error(t, err)
+}
+
+func TestClientSkipping(t *testing.T) {
+	chain := newTestChain(t, 50, staticSet(newTestSigners(t, 4, 10)))
+	p := newTestProvider(chain)
+	c := newTestClient(t, p, 1, fixedClock(50, 0))
+
+	lb, err := c.VerifyLightBlockAtHeight(context.Background(), 50)
+	require.NoError(t, err)
+	require.Equal(t, chain.at(50), lb)
+
+	// An unchanged validator set is trusted directly: no bisection
+	require.Equal(t, 2, p.fetchCount())
+
+	lb, err = c.Update(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(50), lb.Height())
+}
+
+func TestClientBisection(t *testing.T) {
+	chain := newTestChain(t, 32, slidingSet(newTestSigners(t, 36, 10)))
+	p := newTestProvider(chain)
+	c := newTestClient(t, p, 1, fixedClock(32, 0))
+
+	lb, err := c.VerifyLightBlockAtHeight(context.Background(), 32)
+	require.NoError(t, err)
+	require.Equal(t, chain.at(32), lb)
+	require.Greater(t, p.fetchCount(), 2, "validator set changes require bisection")
+
+	// Every pivot that was verified is trusted
+	for _, h := range p.fetched[1:] {
+		_, ok := c.TrustedLightBlock(h)
+		require.True(t, ok, "height %d", h)
+	}
+
+	// Heights between trusted blocks verify from the closest one below
+	_, err = c.VerifyLightBlockAtHeight(context.Background(), 17)
+	require.NoError(t, err)
+}
+
+func TestClientRejectsForgedHeader(t *testing.T) {
+	honest := newTestSigners(t, 4, 10)
+	chain := newTestChain(t, 20, staticSet(honest))
+
+	// A fork signed by validators the client never trusted
+	forged := newTestChain(t, 20, staticSet(newTestSigners(t, 4, 10)))
+	p := newTestProvider(chain)
+	p.replace[20] = forged.at(20)
+	c := newTestClient(t, p, 1, fixedClock(20, 0))
+
+	_, err := c.VerifyLightBlockAtHeight(context.Background(), 20)
+	require.ErrorIs(t, err, ErrHeaderMismatch)
+	_, ok := c.TrustedLightBlock(20)
+	require.False(t, ok)
+
+	// A header signed by too little of its own validator set
+	weak := *chain.at(20)
+	weak.Commit = signCommit(t, weak.Header, honest, 0, 1)
+	p.replace[20] = &weak
+	_, err = c.VerifyLightBlockAtHeight(context.Background(), 20)
+	require.ErrorIs(t, err, ErrNotEnoughVotingPower)
+}
+
+func TestClientTimeChecks(t *testing.T) {
+	chain := newTestChain(t, 10, staticSet(newTestSigners(t, 4, 10)))
+	ctx := context.Background()
+
+	c := newTestClient(t, newTestProvider(chain), 1, fixedClock(1, 0))
+
+	// Headers ahead of the local clock are rejected
+	_, err := c.VerifyLightBlockAtHeight(ctx, 5)
+	require.ErrorIs(t, err, ErrHeaderMismatch)
+
+	// Clock drift tolerance
+	c = newTestClient(t, newTestProvider(chain), 1, fixedClock(1, 0), WithMaxClockDrift(time.Hour))
+	_, err = c.VerifyLightBlockAtHeight(ctx, 5)
+	require.NoError(t, err)
+
+	// Trust roots expire
+	c = newTestClient(t, newTestProvider(chain), 1, fixedClock(1, 0))
+	c.now = fixedClock(1, 25*time.Hour)
+	_, err = c.VerifyLightBlockAtHeight(ctx, 5)
+	require.ErrorIs(t, err, ErrOldHeaderExpired)
+}
+
+func TestClientBackwards(t *testing.T) {
+	chain := newTestChain(t, 10, slidingSet(newTestSigners(t, 14, 10)))
+	p := newTestProvider(chain)
+	c := newTestClient(t, p, 10, fixedClock(10, 0))
+
+	lb, err := c.VerifyLightBlockAtHeight(context.Background(), 5)
+	require.NoError(t, err)
+	require.Equal(t, chain.at(5), lb)
+	require.Equal(t, []uint64{10, 9, 8, 7, 6, 5}, p.fetched)
+
+	// A tampered header breaks the hash chain
+	tampered := *chain.at(3)
+	header := *tampered.Header
+	header.AppHash = chain.at(2).Header.AppHash
+	tampered.Header = &header
+	commit := *tampered.Commit
+	commit.BlockHash = header.Hash()
+	tampered.Commit = &commit
+	p.replace[3] = &tampered
+	_, err = c.VerifyLightBlockAtHeight(context.Background(), 2)
+	require.ErrorIs(t, err, ErrHeaderMismatch)
+}
+
+func TestClientMaxTrustedBlocks(t *testing.T) {
+	chain := newTestChain(t, 10, staticSet(newTestSigners(t, 4, 10)))
+	c := newTestClient(t, newTestProvider(chain), 1, fixedClock(10, 0), WithMaxTrustedBlocks(2))
+
+	for h := uint64(2); h <= 10; h++ {
+		_, err := c.VerifyLightBlockAtHeight(context.Background(), h)
+		require.NoError(t, err)
+	}
+
+	_, ok := c.TrustedLightBlock(1)
+	require.False(t, ok)
+	_, ok = c.TrustedLightBlock(9)
+	require.True(t, ok)
+	require.Equal(t, uint64(10), c.LatestTrusted().Height())
+}
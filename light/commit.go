@@ -0,0 +1,169 @@
+package light
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+)
+
+// CommitSig is one validator's entry in a commit
+type CommitSig struct {
+	// Signature is the validator's signature of VoteSignBytes; empty if the
+	// validator did not sign or its signature is aggregated
+	Signature []byte `json:"signature,omitempty"`
+
+	// Aggregated marks a validator whose signature is part of the commit's
+	// AggregateSignature
+	Aggregated bool `json:"aggregated,omitempty"`
+}
+
+// Commit is the set of validator signatures of a header
+type Commit struct {
+	// Height is the height of the signed header
+	Height uint64 `json:"height"`
+
+	// BlockHash is the hash of the signed header
+	BlockHash []byte `json:"block_hash"`
+
+	// Signatures has one entry per validator, in validator set order
+	Signatures []CommitSig `json:"signatures"`
+
+	// AggregateSignature is the aggregate signature of the Aggregated
+	// validators; empty if there are none
+	AggregateSignature []byte `json:"aggregate_signature,omitempty"`
+}
+
+// ValidateBasic performs stateless validation
+func (c *Commit) ValidateBasic() error {
+	if c == nil {
+		return fmt.Errorf("%w: commit is nil", ErrInvalidCommit)
+	}
+
+	if c.Height == 0 {
+		return fmt.Errorf("%w: height cannot be zero", ErrInvalidCommit)
+	}
+
+	if len(c.BlockHash) != HashSize {
+		return fmt.Errorf("%w: block hash must be %d bytes", ErrInvalidCommit, HashSize)
+	}
+
+	if len(c.Signatures) == 0 {
+		return fmt.Errorf("%w: no signatures", ErrInvalidCommit)
+	}
+	if len(c.Signatures) > MaxValidators {
+		return fmt.Errorf("%w: too many signatures: %d > %d", ErrInvalidCommit, len(c.Signatures), MaxValidators)
+	}
+
+	aggregated := false
+	for i, sig := range c.Signatures {
+		if sig.Aggregated && len(sig.Signature) > 0 {
+			return fmt.Errorf("%w: signature %d is both individual and aggregated", ErrInvalidCommit, i)
+		}
+		aggregated = aggregated || sig.Aggregated
+	}
+	if aggregated != (len(c.AggregateSignature) > 0) {
+		return fmt.Errorf("%w: aggregate signature must be present exactly when signatures are aggregated", ErrInvalidCommit)
+	}
+
+	return nil
+}
+
+// verifySignatures verifies every signature of commit, which must sign
+// its block hash, against vals and reports which validators signed.
+//
+// SECURITY: All signatures are verified, not just enough to reach a quorum,
+// so a commit carrying any invalid signature is rejected as a whole.
+//
+// Complexity: O(n) signature verifications for n validators, plus one
+// aggregate verification.
+func verifySignatures(chainID string, vals *ValidatorSet, commit *Commit, agg AggregateVerifier) ([]bool, error) {
+	if len(commit.Signatures) != vals.Size() {
+		return nil, fmt.Errorf("%w: %d signatures for %d validators", ErrInvalidCommit, len(commit.Signatures), vals.Size())
+	}
+
+	signBytes := VoteSignBytes(chainID, commit.Height, commit.BlockHash)
+	signed := make([]bool, vals.Size())
+
+	var aggKeys [][]byte
+	for i, sig := range commit.Signatures {
+		v := vals.validators[i]
+		switch {
+		case sig.Aggregated:
+			if agg == nil || agg.Algorithm() != v.Algorithm {
+				return nil, fmt.Errorf("%w: no aggregate verifier for validator %d (%s)", ErrInvalidCommit, i, v.Algorithm)
+			}
+			aggKeys = append(aggKeys, v.PubKey)
+			signed[i] = true
+
+		case len(sig.Signature) > 0:
+			pubKey, err := crypto.PublicKeyFromBytes(v.Algorithm, v.PubKey)
+			if err != nil {
+				return nil, fmt.Errorf("%w: validator %d: %v", ErrInvalidCommit, i, err)
+			}
+			if !pubKey.Verify(signBytes, sig.Signature) {
+				return nil, fmt.Errorf("%w: invalid signature of validator %d", ErrInvalidCommit, i)
+			}
+			signed[i] = true
+		}
+	}
+
+	if len(aggKeys) > 0 && !agg.VerifyAggregate(aggKeys, signBytes, commit.AggregateSignature) {
+		return nil, fmt.Errorf("%w: invalid aggregate signature", ErrInvalidCommit)
+	}
+	return signed, nil
+}
+
+// VerifyCommit verifies that commit is a commit of header by more than 2/3
+// of the voting power of vals, the validator set header commits to. agg
+// verifies aggregated signatures and may be nil if there are none.
+func VerifyCommit(vals *ValidatorSet, header *Header, commit *Commit, agg AggregateVerifier) error {
+	if err := checkCommitFor(vals, header, commit); err != nil {
+		return err
+	}
+
+	signed, err := verifySignatures(header.ChainID, vals, commit, agg)
+	if err != nil {
+		return err
+	}
+	return checkQuorum(vals, signed)
+}
+
+// checkCommitFor checks that commit and vals belong to header
+func checkCommitFor(vals *ValidatorSet, header *Header, commit *Commit) error {
+	if vals == nil {
+		return fmt.Errorf("%w: validator set is nil", ErrInvalidValidatorSet)
+	}
+	if err := header.ValidateBasic(); err != nil {
+		return err
+	}
+	if err := commit.ValidateBasic(); err != nil {
+		return err
+	}
+
+	if commit.Height != header.Height {
+		return fmt.Errorf("%w: commit height %d for header height %d", ErrInvalidCommit, commit.Height, header.Height)
+	}
+	if !bytes.Equal(commit.BlockHash, header.Hash()) {
+		return fmt.Errorf("%w: commit signs a different header", ErrInvalidCommit)
+	}
+	if !bytes.Equal(vals.Hash(), header.ValidatorsHash) {
+		return fmt.Errorf("%w: validator set does not match header validators hash", ErrHeaderMismatch)
+	}
+	return nil
+}
+
+// checkQuorum checks that more than 2/3 of vals' voting power signed
+func checkQuorum(vals *ValidatorSet, signed []bool) error {
+	var power int64
+	for i, ok := range signed {
+		if ok {
+			power += vals.validators[i].Power
+		}
+	}
+	// No overflow: the total power is at most MaxTotalVotingPower
+	if power*3 <= vals.TotalPower()*2 {
+		return fmt.Errorf("%w: %d of %d", ErrNotEnoughVotingPower, power, vals.TotalPower())
+	}
+	return nil
+}
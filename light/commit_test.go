@@ -0,0 +1,278 @@
+package light
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+)
+
+const testChainID = "light-test"
+
+// testGenesisTime is the time of the header at height 1
+var testGenesisTime = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// testAggAlgorithm is the key algorithm of testAggregator
+const testAggAlgorithm crypto.Algorithm = "test-aggregate"
+
+// testAggregator is an insecure aggregate scheme for tests: the aggregate
+// signature is the hash of the message and the signer keys
+type testAggregator struct{}
+
+func (testAggregator) Algorithm() crypto.Algorithm { return testAggAlgorithm }
+
+func (testAggregator) VerifyAggregate(pubKeys [][]byte, msg, sig []byte) bool {
+	return bytes.Equal(sig, testAggregate(pubKeys, msg))
+}
+
+func testAggregate(pubKeys [][]byte, msg []byte) []byte {
+	h := sha256.New()
+	h.Write(msg)
+	for _, pk := range pubKeys {
+		h.Write(pk)
+	}
+	return h.Sum(nil)
+}
+
+// testSigner is a validator with its private key; priv is nil for
+// aggregate-only validators
+type testSigner struct {
+	priv crypto.PrivateKey
+	val  Validator
+}
+
+func newTestSigners(t *testing.T, n int, power int64) []testSigner {
+	t.Helper()
+	signers := make([]testSigner, n)
+	for i := range signers {
+		priv, err := crypto.GeneratePrivateKey(crypto.AlgorithmEd25519)
+		require.NoError(t, err)
+		signers[i] = testSigner{
+			priv: priv,
+			val:  Validator{Algorithm: crypto.AlgorithmEd25519, PubKey: priv.PublicKey().Bytes(), Power: power},
+		}
+	}
+	return signers
+}
+
+func newTestAggSigners(n int, power int64) []testSigner {
+	signers := make([]testSigner, n)
+	for i := range signers {
+		signers[i] = testSigner{val: Validator{Algorithm: testAggAlgorithm, PubKey: []byte(fmt.Sprintf("agg-%d", i)), Power: power}}
+	}
+	return signers
+}
+
+func testValidatorSet(t *testing.T, signers []testSigner) *ValidatorSet {
+	t.Helper()
+	vals := make([]Validator, len(signers))
+	for i, s := range signers {
+		vals[i] = s.val
+	}
+	vs, err := NewValidatorSet(vals)
+	require.NoError(t, err)
+	return vs
+}
+
+// signCommit signs header by the signers at the indexes in signing
+func signCommit(t *testing.T, header *Header, signers []testSigner, signing ...int) *Commit {
+	t.Helper()
+	commit := &Commit{Height: header.Height, BlockHash: header.Hash(), Signatures: make([]CommitSig, len(signers))}
+	msg := VoteSignBytes(header.ChainID, header.Height, commit.BlockHash)
+
+	var aggKeys [][]byte
+	for _, i := range signing {
+		if signers[i].priv == nil {
+			commit.Signatures[i].Aggregated = true
+			aggKeys = append(aggKeys, signers[i].val.PubKey)
+			continue
+		}
+		sig, err := signers[i].priv.Sign(msg)
+		require.NoError(t, err)
+		commit.Signatures[i].Signature = sig
+	}
+	if len(aggKeys) > 0 {
+		commit.AggregateSignature = testAggregate(aggKeys, msg)
+	}
+	return commit
+}
+
+func allIndexes(n int) []int {
+	out := make([]int, n)
+	for i := range out {
+		out[i] = i
+	}
+	return out
+}
+
+// testChain is a chain of light blocks with a validator set per height
+type testChain struct {
+	blocks []*LightBlock // blocks[h-1] is the block at height h
+}
+
+// newTestChain builds a chain of n blocks. setAt returns the signers of the
+// header at a height; every validator of a set signs.
+func newTestChain(t *testing.T, n int, setAt func(height uint64) []testSigner) *testChain {
+	t.Helper()
+	c := &testChain{}
+	var lastHash []byte
+	for h := uint64(1); h <= uint64(n); h++ {
+		signers := setAt(h)
+		vals := testValidatorSet(t, signers)
+		appHash := sha256.Sum256([]byte(fmt.Sprintf("app-%d", h)))
+		header := &Header{
+			ChainID:            testChainID,
+			Height:             h,
+			Time:               testGenesisTime.Add(time.Duration(h) * time.Minute),
+			LastBlockHash:      lastHash,
+			AppHash:            appHash[:],
+			ValidatorsHash:     vals.Hash(),
+			NextValidatorsHash: testValidatorSet(t, setAt(h+1)).Hash(),
+		}
+		c.blocks = append(c.blocks, &LightBlock{
+			Header:     header,
+			Commit:     signCommit(t, header, signers, allIndexes(len(signers))...),
+			Validators: vals,
+		})
+		lastHash = header.Hash()
+	}
+	return c
+}
+
+func (c *testChain) at(height uint64) *LightBlock {
+	return c.blocks[height-1]
+}
+
+// testProvider serves a testChain and records fetched heights
+type testProvider struct {
+	chain *testChain
+
+	mu      sync.Mutex
+	fetched []uint64
+	replace map[uint64]*LightBlock
+}
+
+func newTestProvider(chain *testChain) *testProvider {
+	return &testProvider{chain: chain, replace: make(map[uint64]*LightBlock)}
+}
+
+func (p *testProvider) LightBlock(_ context.Context, height uint64) (*LightBlock, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if height == 0 {
+		height = uint64(len(p.chain.blocks))
+	}
+	p.fetched = append(p.fetched, height)
+	if lb, ok := p.replace[height]; ok {
+		return lb, nil
+	}
+	if height > uint64(len(p.chain.blocks)) {
+		return nil, fmt.Errorf("height %d not available", height)
+	}
+	return p.chain.at(height), nil
+}
+
+func (p *testProvider) fetchCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.fetched)
+}
+
+// fixedClock returns a clock at the time of height h plus d
+func fixedClock(h uint64, d time.Duration) func() time.Time {
+	return func() time.Time {
+		return testGenesisTime.Add(time.Duration(h)*time.Minute + d)
+	}
+}
+
+func TestVerifyCommit(t *testing.T) {
+	signers := newTestSigners(t, 4, 10)
+	vals := testValidatorSet(t, signers)
+	appHash := sha256.Sum256([]byte("app"))
+	header := &Header{
+		ChainID:            testChainID,
+		Height:             1,
+		Time:               testGenesisTime,
+		AppHash:            appHash[:],
+		ValidatorsHash:     vals.Hash(),
+		NextValidatorsHash: vals.Hash(),
+	}
+
+	otherHeader := *header
+	otherHeader.Time = header.Time.Add(time.Second)
+
+	badSig := signCommit(t, header, signers, 0, 1, 2)
+	badSig.Signatures[1].Signature = bytes.Repeat([]byte{1}, 64)
+
+	tests := []struct {
+		name    string
+		commit  *Commit
+		wantErr error
+	}{
+		{name: "all signed", commit: signCommit(t, header, signers, 0, 1, 2, 3)},
+		{name: "3 of 4 signed", commit: signCommit(t, header, signers, 0, 1, 3)},
+		{name: "2 of 4 signed", commit: signCommit(t, header, signers, 0, 1), wantErr: ErrNotEnoughVotingPower},
+		{name: "invalid signature", commit: badSig, wantErr: ErrInvalidCommit},
+		{name: "other header", commit: signCommit(t, &otherHeader, signers, 0, 1, 2, 3), wantErr: ErrInvalidCommit},
+		{name: "nil commit", commit: nil, wantErr: ErrInvalidCommit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyCommit(vals, header, tt.commit, nil)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	// A signature from a different chain does not verify
+	foreign := *header
+	foreign.ChainID = "other-chain"
+	commit := signCommit(t, &foreign, signers, 0, 1, 2, 3)
+	commit.BlockHash = header.Hash()
+	require.ErrorIs(t, VerifyCommit(vals, header, commit, nil), ErrInvalidCommit)
+
+	// The validator set must be the one the header commits to
+	require.ErrorIs(t, VerifyCommit(testValidatorSet(t, signers[:3]), header, signCommit(t, header, signers[:3], 0, 1, 2), nil), ErrHeaderMismatch)
+}
+
+func TestVerifyCommitAggregate(t *testing.T) {
+	signers := append(newTestSigners(t, 1, 10), newTestAggSigners(3, 10)...)
+	vals := testValidatorSet(t, signers)
+	appHash := sha256.Sum256([]byte("app"))
+	header := &Header{
+		ChainID:            testChainID,
+		Height:             1,
+		Time:               testGenesisTime,
+		AppHash:            appHash[:],
+		ValidatorsHash:     vals.Hash(),
+		NextValidatorsHash: vals.Hash(),
+	}
+
+	commit := signCommit(t, header, signers, 0, 1, 2, 3)
+	require.NoError(t, VerifyCommit(vals, header, commit, testAggregator{}))
+
+	// Aggregated signatures need a verifier for their algorithm
+	require.ErrorIs(t, VerifyCommit(vals, header, commit, nil), ErrInvalidCommit)
+
+	// The aggregate covers exactly the flagged validators
+	commit = signCommit(t, header, signers, 0, 1, 2, 3)
+	commit.Signatures[3].Aggregated = false
+	require.ErrorIs(t, VerifyCommit(vals, header, commit, testAggregator{}), ErrInvalidCommit)
+
+	// Aggregated signatures without an aggregate are malformed
+	commit = signCommit(t, header, signers, 0, 1, 2, 3)
+	commit.AggregateSignature = nil
+	require.ErrorIs(t, VerifyCommit(vals, header, commit, testAggregator{}), ErrInvalidCommit)
+}
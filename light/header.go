@@ -0,0 +1,125 @@
+package light
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Domain separators of the hashes and sign bytes defined by this package.
+// SECURITY: Distinct prefixes keep a signature or hash of one structure from
+// being valid for another.
+const (
+	headerHashDomain    = "punnet/light/header/v1"
+	validatorHashDomain = "punnet/light/validators/v1"
+	voteSignDomain      = "punnet/light/vote/v1"
+)
+
+// HashSize is the size of header, validator set and app hashes
+const HashSize = sha256.Size
+
+// Header is the block header signed by the validators
+type Header struct {
+	// ChainID is the blockchain identifier
+	ChainID string `json:"chain_id"`
+
+	// Height is the block height
+	Height uint64 `json:"height"`
+
+	// Time is the block timestamp
+	Time time.Time `json:"time"`
+
+	// LastBlockHash is the hash of the header at Height-1; empty at height 1
+	LastBlockHash []byte `json:"last_block_hash"`
+
+	// AppHash is the app hash after this block was committed, which commits
+	// to the state store root (see types.CommitInfo)
+	AppHash []byte `json:"app_hash"`
+
+	// ValidatorsHash is the hash of the validator set that signs this header
+	ValidatorsHash []byte `json:"validators_hash"`
+
+	// NextValidatorsHash is the hash of the validator set that signs the
+	// header at Height+1
+	NextValidatorsHash []byte `json:"next_validators_hash"`
+}
+
+// ValidateBasic performs stateless validation
+func (h *Header) ValidateBasic() error {
+	if h == nil {
+		return fmt.Errorf("%w: header is nil", ErrInvalidHeader)
+	}
+
+	if h.ChainID == "" {
+		return fmt.Errorf("%w: chain ID cannot be empty", ErrInvalidHeader)
+	}
+
+	if h.Height == 0 {
+		return fmt.Errorf("%w: height cannot be zero", ErrInvalidHeader)
+	}
+
+	if h.Time.IsZero() {
+		return fmt.Errorf("%w: time cannot be zero", ErrInvalidHeader)
+	}
+
+	if h.Height == 1 && len(h.LastBlockHash) != 0 {
+		return fmt.Errorf("%w: last block hash must be empty at height 1", ErrInvalidHeader)
+	}
+	if h.Height > 1 && len(h.LastBlockHash) != HashSize {
+		return fmt.Errorf("%w: last block hash must be %d bytes", ErrInvalidHeader, HashSize)
+	}
+
+	if len(h.AppHash) != HashSize {
+		return fmt.Errorf("%w: app hash must be %d bytes", ErrInvalidHeader, HashSize)
+	}
+
+	if len(h.ValidatorsHash) != HashSize || len(h.NextValidatorsHash) != HashSize {
+		return fmt.Errorf("%w: validator set hashes must be %d bytes", ErrInvalidHeader, HashSize)
+	}
+
+	return nil
+}
+
+// Hash returns the header hash, which commits sign.
+//
+// INVARIANT: Every field is length-prefixed, so distinct headers never
+// encode to the same bytes.
+func (h *Header) Hash() []byte {
+	hasher := sha256.New()
+	hasher.Write([]byte(headerHashDomain))
+	writeBytes(hasher, []byte(h.ChainID))
+	writeUint64(hasher, h.Height)
+	writeUint64(hasher, uint64(h.Time.UnixNano()))
+	writeBytes(hasher, h.LastBlockHash)
+	writeBytes(hasher, h.AppHash)
+	writeBytes(hasher, h.ValidatorsHash)
+	writeBytes(hasher, h.NextValidatorsHash)
+	return hasher.Sum(nil)
+}
+
+// VoteSignBytes returns the bytes a validator signs to commit the block with
+// hash blockHash at height
+func VoteSignBytes(chainID string, height uint64, blockHash []byte) []byte {
+	out := make([]byte, 0, len(voteSignDomain)+8+len(chainID)+8+8+len(blockHash))
+	out = append(out, voteSignDomain...)
+	out = binary.BigEndian.AppendUint64(out, uint64(len(chainID)))
+	out = append(out, chainID...)
+	out = binary.BigEndian.AppendUint64(out, height)
+	out = binary.BigEndian.AppendUint64(out, uint64(len(blockHash)))
+	return append(out, blockHash...)
+}
+
+// writeBytes writes a length-prefixed byte string
+func writeBytes(w io.Writer, b []byte) {
+	writeUint64(w, uint64(len(b)))
+	w.Write(b)
+}
+
+// writeUint64 writes a big-endian uint64
+func writeUint64(w io.Writer, v uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	w.Write(buf[:])
+}
@@ -0,0 +1,72 @@
+package light
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderValidateBasic(t *testing.T) {
+	hash := bytes.Repeat([]byte{1}, HashSize)
+	valid := func() *Header {
+		return &Header{
+			ChainID:            testChainID,
+			Height:             2,
+			Time:               testGenesisTime,
+			LastBlockHash:      hash,
+			AppHash:            hash,
+			ValidatorsHash:     hash,
+			NextValidatorsHash: hash,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(h *Header)
+		wantErr bool
+	}{
+		{name: "valid", mutate: func(h *Header) {}},
+		{name: "first block", mutate: func(h *Header) { h.Height, h.LastBlockHash = 1, nil }},
+		{name: "empty chain ID", mutate: func(h *Header) { h.ChainID = "" }, wantErr: true},
+		{name: "zero height", mutate: func(h *Header) { h.Height = 0 }, wantErr: true},
+		{name: "zero time", mutate: func(h *Header) { h.Time = time.Time{} }, wantErr: true},
+		{name: "missing last block hash", mutate: func(h *Header) { h.LastBlockHash = nil }, wantErr: true},
+		{name: "last block hash at height 1", mutate: func(h *Header) { h.Height = 1 }, wantErr: true},
+		{name: "short app hash", mutate: func(h *Header) { h.AppHash = hash[:8] }, wantErr: true},
+		{name: "missing validators hash", mutate: func(h *Header) { h.ValidatorsHash = nil }, wantErr: true},
+		{name: "missing next validators hash", mutate: func(h *Header) { h.NextValidatorsHash = nil }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := valid()
+			tt.mutate(h)
+			err := h.ValidateBasic()
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidHeader)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestHeaderHash(t *testing.T) {
+	hash := bytes.Repeat([]byte{1}, HashSize)
+	h := &Header{ChainID: testChainID, Height: 1, Time: testGenesisTime, AppHash: hash, ValidatorsHash: hash, NextValidatorsHash: hash}
+	require.Len(t, h.Hash(), HashSize)
+	require.Equal(t, h.Hash(), h.Hash())
+
+	// Bytes cannot move between adjacent fields
+	a := *h
+	a.LastBlockHash, a.AppHash = []byte{1, 2}, []byte{3}
+	b := *h
+	b.LastBlockHash, b.AppHash = []byte{1}, []byte{2, 3}
+	require.NotEqual(t, a.Hash(), b.Hash())
+
+	moved := *h
+	moved.Time = h.Time.Add(time.Nanosecond)
+	require.NotEqual(t, h.Hash(), moved.Hash())
+}
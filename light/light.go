@@ -0,0 +1,52 @@
+// Package light verifies block headers and query proofs without running a
+// full node.
+//
+// A light block is a header, the commit of the validators that signed it and
+// their validator set. Starting from a header trusted out of band
+// (TrustOptions), the Client verifies later headers either sequentially, when
+// the validator set hands over through NextValidatorsHash, or by skipping,
+// when validators holding at least the trust level of the trusted voting
+// power also signed the new header. When a skip cannot be trusted the client
+// bisects the height range.
+//
+// Header.AppHash at height H commits to the state store root after the block
+// at H was committed (see types.CommitInfo), which is the root query proofs at
+// height H are computed against (see query.StoreKeyPath), so VerifyQueryResult
+// binds a query result to a verified header.
+//
+// SECURITY: The light client assumes that fewer than 1/3 of the voting power
+// of a trusted validator set acts maliciously within the trusting period.
+// Headers older than the trusting period are never used as a trust root.
+package light
+
+import "errors"
+
+var (
+	// ErrInvalidHeader is returned for malformed headers
+	ErrInvalidHeader = errors.New("invalid header")
+
+	// ErrInvalidValidatorSet is returned for malformed validator sets
+	ErrInvalidValidatorSet = errors.New("invalid validator set")
+
+	// ErrInvalidCommit is returned for malformed commits or invalid signatures
+	ErrInvalidCommit = errors.New("invalid commit")
+
+	// ErrNotEnoughVotingPower is returned when a commit is signed by at most
+	// 2/3 of its validator set's voting power
+	ErrNotEnoughVotingPower = errors.New("not enough voting power signed")
+
+	// ErrNewValSetCantBeTrusted is returned when too little of the trusted
+	// voting power signed a non-adjacent header; the client bisects
+	ErrNewValSetCantBeTrusted = errors.New("new validator set cannot be trusted")
+
+	// ErrOldHeaderExpired is returned when a trusted header is outside the
+	// trusting period
+	ErrOldHeaderExpired = errors.New("trusted header expired")
+
+	// ErrHeaderMismatch is returned when a header does not match the trusted
+	// chain (wrong hash, validator set hash or non-monotonic height/time)
+	ErrHeaderMismatch = errors.New("header mismatch")
+
+	// ErrInvalidProof is returned when a Merkle proof does not verify
+	ErrInvalidProof = errors.New("invalid proof")
+)
@@ -0,0 +1,100 @@
+package light
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	ics23 "github.com/cosmos/ics23/go"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// decodeProof decodes a protobuf-encoded ics23 proof
+func decodeProof(proof []byte) (*ics23.CommitmentProof, error) {
+	if len(proof) == 0 {
+		return nil, fmt.Errorf("%w: proof is empty", ErrInvalidProof)
+	}
+	var p ics23.CommitmentProof
+	if err := p.Unmarshal(proof); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	return &p, nil
+}
+
+// VerifyMembership verifies a protobuf-encoded ics23 proof that the state
+// store with root stateRoot maps key to value
+func VerifyMembership(stateRoot, proof, key, value []byte) error {
+	p, err := decodeProof(proof)
+	if err != nil {
+		return err
+	}
+	if !ics23.VerifyMembership(ics23.IavlSpec, stateRoot, p, key, value) {
+		return fmt.Errorf("%w: membership of key %x", ErrInvalidProof, key)
+	}
+	return nil
+}
+
+// VerifyNonMembership verifies a protobuf-encoded ics23 proof that the state
+// store with root stateRoot has no value for key
+func VerifyNonMembership(stateRoot, proof, key []byte) error {
+	p, err := decodeProof(proof)
+	if err != nil {
+		return err
+	}
+	if !ics23.VerifyNonMembership(ics23.IavlSpec, stateRoot, p, key) {
+		return fmt.Errorf("%w: non-membership of key %x", ErrInvalidProof, key)
+	}
+	return nil
+}
+
+// provenStateRoot returns the state store root proof is computed against,
+// if it is the root appHash commits to (see types.StateAppHash)
+func provenStateRoot(appHash, proof []byte) ([]byte, error) {
+	p, err := decodeProof(proof)
+	if err != nil {
+		return nil, err
+	}
+	root, err := p.Calculate()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	want, err := types.StateAppHash(root)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	if !bytes.Equal(want, appHash) {
+		return nil, fmt.Errorf("%w: proof root %x is not committed by app hash %x", ErrInvalidProof, root, appHash)
+	}
+	return root, nil
+}
+
+// VerifyQueryResult verifies the result of a proof query for key at
+// query.StoreKeyPath against the verified header at the result's height: a
+// result with data proves key maps to it, a result without data proves key is
+// absent.
+//
+// The proof is verified against the state store root it is computed from,
+// after checking the header's app hash commits to that root.
+func (c *Client) VerifyQueryResult(ctx context.Context, key []byte, result *types.QueryResult) error {
+	if result == nil {
+		return fmt.Errorf("%w: query result is nil", ErrInvalidProof)
+	}
+	if !result.IsOK() {
+		return fmt.Errorf("%w: query failed with code %d", ErrInvalidProof, result.Code)
+	}
+
+	lb, err := c.VerifyLightBlockAtHeight(ctx, result.Height)
+	if err != nil {
+		return err
+	}
+
+	root, err := provenStateRoot(lb.Header.AppHash, result.Proof)
+	if err != nil {
+		return err
+	}
+	if result.Data == nil {
+		return VerifyNonMembership(root, result.Proof, key)
+	}
+	return VerifyMembership(root, result.Proof, key, result.Data)
+}
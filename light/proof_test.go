@@ -0,0 +1,76 @@
+package light
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/query"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+func TestVerifyQueryResult(t *testing.T) {
+	s, err := store.NewIAVLStore(store.NewMemDB(), 0)
+	require.NoError(t, err)
+	require.NoError(t, s.Set([]byte("a"), []byte("1")))
+	require.NoError(t, s.Set([]byte("c"), []byte("3")))
+	root, version, err := s.SaveVersion()
+	require.NoError(t, err)
+
+	server, err := query.NewServer(s)
+	require.NoError(t, err)
+	ctx := context.Background()
+	queryKey := func(key string) *types.QueryResult {
+		resp, err := server.Query(ctx, query.Request{Path: query.StoreKeyPath, Data: []byte(key), Prove: true})
+		require.NoError(t, err)
+		proof, err := resp.Proof.Marshal()
+		require.NoError(t, err)
+		return &types.QueryResult{Data: resp.Value, Height: uint64(resp.Height), Proof: proof}
+	}
+
+	// A chain whose header at the store version commits to the store root
+	appHash, err := types.StateAppHash(root)
+	require.NoError(t, err)
+	signers := newTestSigners(t, 4, 10)
+	vals := testValidatorSet(t, signers)
+	header := &Header{
+		ChainID:            testChainID,
+		Height:             uint64(version),
+		Time:               testGenesisTime,
+		AppHash:            appHash,
+		ValidatorsHash:     vals.Hash(),
+		NextValidatorsHash: vals.Hash(),
+	}
+	chain := &testChain{blocks: []*LightBlock{{Header: header, Commit: signCommit(t, header, signers, 0, 1, 2, 3), Validators: vals}}}
+	c := newTestClient(t, newTestProvider(chain), 1, fixedClock(1, time.Minute))
+
+	require.NoError(t, c.VerifyQueryResult(ctx, []byte("a"), queryKey("a")))
+	require.NoError(t, c.VerifyQueryResult(ctx, []byte("b"), queryKey("b")))
+
+	// A tampered value does not verify
+	tampered := queryKey("a")
+	tampered.Data = []byte("2")
+	require.ErrorIs(t, c.VerifyQueryResult(ctx, []byte("a"), tampered), ErrInvalidProof)
+
+	// Nor does hiding an existing value
+	hidden := queryKey("a")
+	hidden.Data = nil
+	require.ErrorIs(t, c.VerifyQueryResult(ctx, []byte("a"), hidden), ErrInvalidProof)
+
+	// Nor a proof for another key
+	require.ErrorIs(t, c.VerifyQueryResult(ctx, []byte("a"), queryKey("c")), ErrInvalidProof)
+
+	require.ErrorIs(t, c.VerifyQueryResult(ctx, []byte("a"), &types.QueryResult{Height: 1, Data: []byte("1")}), ErrInvalidProof)
+	require.ErrorIs(t, c.VerifyQueryResult(ctx, []byte("a"), &types.QueryResult{Code: 1}), ErrInvalidProof)
+
+	// A proof against the state root itself rather than the app hash
+	header.AppHash = root
+	chain = &testChain{blocks: []*LightBlock{{Header: header, Commit: signCommit(t, header, signers, 0, 1, 2, 3), Validators: vals}}}
+	c = newTestClient(t, newTestProvider(chain), 1, fixedClock(1, time.Minute))
+	require.ErrorIs(t, c.VerifyQueryResult(ctx, []byte("a"), queryKey("a")), ErrInvalidProof)
+
+	require.ErrorIs(t, VerifyMembership(root, []byte{0xff}, []byte("a"), []byte("1")), ErrInvalidProof)
+}
@@ -0,0 +1,166 @@
+package light
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+)
+
+// AlgorithmBLS12381 identifies BLS12-381 validator keys. Their signatures are
+// only verified in aggregate, through an AggregateVerifier.
+const AlgorithmBLS12381 crypto.Algorithm = "bls12_381"
+
+// MaxTotalVotingPower bounds the total voting power of a validator set so
+// that power sums and the 2/3 and trust level products cannot overflow.
+const MaxTotalVotingPower = math.MaxInt64 / 8
+
+// MaxValidators limits the size of a validator set.
+// SECURITY: Bounds the signature verification work a single commit can cause.
+const MaxValidators = 10000
+
+// AggregateVerifier verifies aggregate signatures, e.g. BLS12-381 with
+// proof-of-possession keys.
+//
+// SECURITY: Implementations must reject rogue-key attacks, either by
+// requiring proofs of possession at validator registration or by using an
+// aggregation scheme that is safe without them.
+type AggregateVerifier interface {
+	// Algorithm returns the key algorithm the verifier handles
+	Algorithm() crypto.Algorithm
+
+	// VerifyAggregate reports whether sig is a valid aggregate signature of
+	// msg by all of pubKeys
+	VerifyAggregate(pubKeys [][]byte, msg, sig []byte) bool
+}
+
+// Validator is a member of a validator set
+type Validator struct {
+	// Algorithm is the algorithm of PubKey
+	Algorithm crypto.Algorithm `json:"algorithm"`
+
+	// PubKey is the validator's public key
+	PubKey []byte `json:"pub_key"`
+
+	// Power is the validator's voting power
+	Power int64 `json:"power"`
+}
+
+// ValidatorSet is an ordered validator set. Commit signatures are matched to
+// validators by position.
+type ValidatorSet struct {
+	validators []Validator
+	totalPower int64
+	index      map[string]int
+}
+
+// NewValidatorSet creates a validator set, preserving the order of validators.
+//
+// Returns an error if the set is empty or too large, a validator has no key
+// or a non-positive power, a key appears twice, or the total power exceeds
+// MaxTotalVotingPower.
+func NewValidatorSet(validators []Validator) (*ValidatorSet, error) {
+	if len(validators) == 0 {
+		return nil, fmt.Errorf("%w: validator set cannot be empty", ErrInvalidValidatorSet)
+	}
+	if len(validators) > MaxValidators {
+		return nil, fmt.Errorf("%w: too many validators: %d > %d", ErrInvalidValidatorSet, len(validators), MaxValidators)
+	}
+
+	vs := &ValidatorSet{
+		validators: make([]Validator, len(validators)),
+		index:      make(map[string]int, len(validators)),
+	}
+	for i, v := range validators {
+		if v.Algorithm == "" || len(v.PubKey) == 0 {
+			return nil, fmt.Errorf("%w: validator %d has no key", ErrInvalidValidatorSet, i)
+		}
+		if v.Power <= 0 {
+			return nil, fmt.Errorf("%w: validator %d has non-positive power %d", ErrInvalidValidatorSet, i, v.Power)
+		}
+		if v.Power > MaxTotalVotingPower-vs.totalPower {
+			return nil, fmt.Errorf("%w: total voting power exceeds %d", ErrInvalidValidatorSet, int64(MaxTotalVotingPower))
+		}
+
+		key := validatorKey(v)
+		if _, exists := vs.index[key]; exists {
+			return nil, fmt.Errorf("%w: duplicate validator %d", ErrInvalidValidatorSet, i)
+		}
+		vs.index[key] = i
+
+		// Defensive copy of the key
+		v.PubKey = append([]byte(nil), v.PubKey...)
+		vs.validators[i] = v
+		vs.totalPower += v.Power
+	}
+	return vs, nil
+}
+
+// validatorKey returns the index key of a validator
+func validatorKey(v Validator) string {
+	return string(v.Algorithm) + "/" + string(v.PubKey)
+}
+
+// Size returns the number of validators
+func (vs *ValidatorSet) Size() int {
+	return len(vs.validators)
+}
+
+// Validator returns the validator at position i
+func (vs *ValidatorSet) Validator(i int) Validator {
+	v := vs.validators[i]
+	v.PubKey = append([]byte(nil), v.PubKey...)
+	return v
+}
+
+// TotalPower returns the total voting power
+func (vs *ValidatorSet) TotalPower() int64 {
+	return vs.totalPower
+}
+
+// powerOf returns the power of the validator with v's key, or 0
+func (vs *ValidatorSet) powerOf(v Validator) int64 {
+	i, ok := vs.index[validatorKey(v)]
+	if !ok {
+		return 0
+	}
+	return vs.validators[i].Power
+}
+
+// Hash returns the hash headers commit to in ValidatorsHash.
+//
+// INVARIANT: The hash covers every validator's algorithm, key and power in
+// order.
+func (vs *ValidatorSet) Hash() []byte {
+	hasher := sha256.New()
+	hasher.Write([]byte(validatorHashDomain))
+	writeUint64(hasher, uint64(len(vs.validators)))
+	for _, v := range vs.validators {
+		writeBytes(hasher, []byte(v.Algorithm))
+		writeBytes(hasher, v.PubKey)
+		writeUint64(hasher, uint64(v.Power))
+	}
+	return hasher.Sum(nil)
+}
+
+// MarshalJSON encodes the set as its list of validators
+func (vs *ValidatorSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(vs.validators)
+}
+
+// UnmarshalJSON decodes a list of validators, validated as by
+// NewValidatorSet
+func (vs *ValidatorSet) UnmarshalJSON(data []byte) error {
+	var validators []Validator
+	if err := json.Unmarshal(data, &validators); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidValidatorSet, err)
+	}
+	decoded, err := NewValidatorSet(validators)
+	if err != nil {
+		return err
+	}
+	*vs = *decoded
+	return nil
+}
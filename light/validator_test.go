@@ -0,0 +1,102 @@
+package light
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+)
+
+func TestNewValidatorSet(t *testing.T) {
+	key := func(b byte) []byte { return []byte{b} }
+
+	tests := []struct {
+		name    string
+		vals    []Validator
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			vals: []Validator{
+				{Algorithm: crypto.AlgorithmEd25519, PubKey: key(1), Power: 1},
+				{Algorithm: crypto.AlgorithmEd25519, PubKey: key(2), Power: 2},
+			},
+		},
+		{name: "empty", vals: nil, wantErr: true},
+		{name: "no key", vals: []Validator{{Algorithm: crypto.AlgorithmEd25519, Power: 1}}, wantErr: true},
+		{name: "no algorithm", vals: []Validator{{PubKey: key(1), Power: 1}}, wantErr: true},
+		{name: "zero power", vals: []Validator{{Algorithm: crypto.AlgorithmEd25519, PubKey: key(1)}}, wantErr: true},
+		{name: "negative power", vals: []Validator{{Algorithm: crypto.AlgorithmEd25519, PubKey: key(1), Power: -1}}, wantErr: true},
+		{
+			name: "duplicate key",
+			vals: []Validator{
+				{Algorithm: crypto.AlgorithmEd25519, PubKey: key(1), Power: 1},
+				{Algorithm: crypto.AlgorithmEd25519, PubKey: key(1), Power: 1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "total power overflow",
+			vals: []Validator{
+				{Algorithm: crypto.AlgorithmEd25519, PubKey: key(1), Power: MaxTotalVotingPower},
+				{Algorithm: crypto.AlgorithmEd25519, PubKey: key(2), Power: 1},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vs, err := NewValidatorSet(tt.vals)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidValidatorSet)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, len(tt.vals), vs.Size())
+			require.Equal(t, int64(3), vs.TotalPower())
+		})
+	}
+}
+
+func TestValidatorSetHash(t *testing.T) {
+	a := Validator{Algorithm: crypto.AlgorithmEd25519, PubKey: []byte{1}, Power: 1}
+	b := Validator{Algorithm: crypto.AlgorithmEd25519, PubKey: []byte{2}, Power: 1}
+
+	ab, err := NewValidatorSet([]Validator{a, b})
+	require.NoError(t, err)
+	ba, err := NewValidatorSet([]Validator{b, a})
+	require.NoError(t, err)
+	require.NotEqual(t, ab.Hash(), ba.Hash(), "hash must cover the order")
+
+	b.Power = 2
+	ab2, err := NewValidatorSet([]Validator{a, b})
+	require.NoError(t, err)
+	require.NotEqual(t, ab.Hash(), ab2.Hash(), "hash must cover the power")
+
+	// The set keeps its own copy of keys
+	vals := []Validator{a}
+	vs, err := NewValidatorSet(vals)
+	require.NoError(t, err)
+	before := vs.Hash()
+	vals[0].PubKey[0] = 9
+	require.Equal(t, before, vs.Hash())
+}
+
+func TestLightBlockJSON(t *testing.T) {
+	chain := newTestChain(t, 2, staticSet(newTestSigners(t, 3, 10)))
+
+	data, err := json.Marshal(chain.at(2))
+	require.NoError(t, err)
+
+	var decoded LightBlock
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.NoError(t, decoded.ValidateBasic(testChainID))
+	require.Equal(t, chain.at(2).Header.Hash(), decoded.Header.Hash())
+	require.Equal(t, chain.at(2).Validators.Hash(), decoded.Validators.Hash())
+
+	var vs ValidatorSet
+	require.ErrorIs(t, json.Unmarshal([]byte(`[]`), &vs), ErrInvalidValidatorSet)
+}
@@ -0,0 +1,174 @@
+package light
+
+import (
+	"bytes"
+	"fmt"
+	"math/bits"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// DefaultTrustLevel is the minimum fraction of a trusted validator set's
+// voting power that must sign a non-adjacent header: more than 1/3 guarantees
+// at least one correct validator under the light client's assumption.
+var DefaultTrustLevel = types.Ratio{Numerator: 1, Denominator: 3}
+
+// DefaultMaxClockDrift is the default tolerance for header times ahead of the
+// local clock
+const DefaultMaxClockDrift = 10 * time.Second
+
+// LightBlock is a header with the commit and validator set that signed it
+type LightBlock struct {
+	// Header is the signed header
+	Header *Header `json:"header"`
+
+	// Commit is the commit of Header
+	Commit *Commit `json:"commit"`
+
+	// Validators is the validator set of Header (Header.ValidatorsHash)
+	Validators *ValidatorSet `json:"validators"`
+}
+
+// Height returns the header height
+func (lb *LightBlock) Height() uint64 {
+	return lb.Header.Height
+}
+
+// ValidateBasic performs stateless validation: the header belongs to
+// chainID, and the commit and validator set match the header. Signatures are
+// not verified.
+func (lb *LightBlock) ValidateBasic(chainID string) error {
+	if lb == nil {
+		return fmt.Errorf("%w: light block is nil", ErrInvalidHeader)
+	}
+	if err := checkCommitFor(lb.Validators, lb.Header, lb.Commit); err != nil {
+		return err
+	}
+	if lb.Header.ChainID != chainID {
+		return fmt.Errorf("%w: header is for chain %q, expected %q", ErrHeaderMismatch, lb.Header.ChainID, chainID)
+	}
+	return nil
+}
+
+// validateTrustLevel checks that 1/3 <= level <= 1
+func validateTrustLevel(level types.Ratio) error {
+	if level.Denominator == 0 {
+		return fmt.Errorf("trust level denominator cannot be zero")
+	}
+	if level.Numerator > level.Denominator || mulLess(level.Numerator, 3, level.Denominator, 1) {
+		return fmt.Errorf("trust level must be within [1/3, 1], got %d/%d", level.Numerator, level.Denominator)
+	}
+	return nil
+}
+
+// mulLess reports whether a*b < c*d without overflow
+func mulLess(a, b, c, d uint64) bool {
+	hi1, lo1 := bits.Mul64(a, b)
+	hi2, lo2 := bits.Mul64(c, d)
+	return hi1 < hi2 || (hi1 == hi2 && lo1 < lo2)
+}
+
+// verifier holds the verification parameters of a Client
+type verifier struct {
+	chainID        string
+	trustingPeriod time.Duration
+	trustLevel     types.Ratio
+	maxClockDrift  time.Duration
+	aggregate      AggregateVerifier
+}
+
+// checkTrusted checks that trusted can still serve as a trust root at now
+func (v *verifier) checkTrusted(trusted *LightBlock, now time.Time) error {
+	expires := trusted.Header.Time.Add(v.trustingPeriod)
+	if !expires.After(now) {
+		return fmt.Errorf("%w: header at height %d expired at %s", ErrOldHeaderExpired, trusted.Height(), expires)
+	}
+	return nil
+}
+
+// verify verifies untrusted, a light block above trusted, at time now.
+//
+// An adjacent header must be signed by the validator set trusted handed over
+// to (NextValidatorsHash). A non-adjacent header must be signed by validators
+// holding more than the trust level of trusted's voting power; otherwise
+// ErrNewValSetCantBeTrusted is returned and the caller may bisect. Either way
+// more than 2/3 of untrusted's own validator set must have signed.
+func (v *verifier) verify(trusted, untrusted *LightBlock, now time.Time) error {
+	if err := v.checkTrusted(trusted, now); err != nil {
+		return err
+	}
+	if err := untrusted.ValidateBasic(v.chainID); err != nil {
+		return err
+	}
+
+	uh, th := untrusted.Header, trusted.Header
+	if uh.Height <= th.Height {
+		return fmt.Errorf("%w: height %d is not above trusted height %d", ErrHeaderMismatch, uh.Height, th.Height)
+	}
+	if !uh.Time.After(th.Time) {
+		return fmt.Errorf("%w: time %s is not after trusted time %s", ErrHeaderMismatch, uh.Time, th.Time)
+	}
+	if uh.Time.After(now.Add(v.maxClockDrift)) {
+		return fmt.Errorf("%w: time %s is in the future", ErrHeaderMismatch, uh.Time)
+	}
+
+	signed, err := verifySignatures(v.chainID, untrusted.Validators, untrusted.Commit, v.aggregate)
+	if err != nil {
+		return err
+	}
+
+	if uh.Height == th.Height+1 {
+		if !bytes.Equal(uh.ValidatorsHash, th.NextValidatorsHash) {
+			return fmt.Errorf("%w: validator set at height %d is not the trusted next validator set", ErrHeaderMismatch, uh.Height)
+		}
+		if !bytes.Equal(uh.LastBlockHash, th.Hash()) {
+			return fmt.Errorf("%w: header at height %d does not link to the trusted header", ErrHeaderMismatch, uh.Height)
+		}
+	} else if err := v.checkTrusting(trusted.Validators, untrusted.Validators, signed); err != nil {
+		return err
+	}
+
+	return checkQuorum(untrusted.Validators, signed)
+}
+
+// checkTrusting checks that the validators of untrustedVals that signed hold
+// more than the trust level of trustedVals' voting power
+func (v *verifier) checkTrusting(trustedVals, untrustedVals *ValidatorSet, signed []bool) error {
+	var power int64
+	for i, ok := range signed {
+		if ok {
+			power += trustedVals.powerOf(untrustedVals.validators[i])
+		}
+	}
+
+	// power/total > numerator/denominator
+	if !mulLess(uint64(trustedVals.TotalPower()), v.trustLevel.Numerator, uint64(power), v.trustLevel.Denominator) {
+		return fmt.Errorf("%w: %d of trusted %d signed, need more than %d/%d",
+			ErrNewValSetCantBeTrusted, power, trustedVals.TotalPower(), v.trustLevel.Numerator, v.trustLevel.Denominator)
+	}
+	return nil
+}
+
+// verifyBackwards verifies untrusted, the light block directly below
+// trusted, through the header hash chain
+func (v *verifier) verifyBackwards(trusted, untrusted *LightBlock) error {
+	if err := untrusted.ValidateBasic(v.chainID); err != nil {
+		return err
+	}
+
+	uh, th := untrusted.Header, trusted.Header
+	if uh.Height+1 != th.Height {
+		return fmt.Errorf("%w: height %d is not directly below trusted height %d", ErrHeaderMismatch, uh.Height, th.Height)
+	}
+	if !uh.Time.Before(th.Time) {
+		return fmt.Errorf("%w: time %s is not before trusted time %s", ErrHeaderMismatch, uh.Time, th.Time)
+	}
+	if !bytes.Equal(th.LastBlockHash, uh.Hash()) {
+		return fmt.Errorf("%w: header at height %d is not the trusted header's parent", ErrHeaderMismatch, uh.Height)
+	}
+	if !bytes.Equal(uh.NextValidatorsHash, th.ValidatorsHash) {
+		return fmt.Errorf("%w: next validator set at height %d does not match", ErrHeaderMismatch, uh.Height)
+	}
+	return nil
+}
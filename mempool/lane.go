@@ -0,0 +1,250 @@
+package mempool
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Ordering is the order in which a lane's transactions are included in blocks
+type Ordering uint8
+
+const (
+	// OrderFIFO includes transactions in arrival order. A full lane rejects
+	// new transactions.
+	OrderFIFO Ordering = iota
+
+	// OrderPriority includes transactions by descending priority (see
+	// Config.Priority), then arrival order. A full lane evicts its
+	// lowest-priority transactions for a higher-priority one.
+	OrderPriority
+)
+
+// String returns the ordering name
+func (o Ordering) String() string {
+	switch o {
+	case OrderFIFO:
+		return "fifo"
+	case OrderPriority:
+		return "priority"
+	default:
+		return fmt.Sprintf("Ordering(%d)", uint8(o))
+	}
+}
+
+// Lane is a class of transactions with its own limits and ordering
+type Lane struct {
+	// Name identifies the lane
+	Name string
+
+	// Priority orders lanes: blocks are filled from the highest-priority lane
+	// first. Lanes with equal priority keep their configuration order.
+	Priority uint32
+
+	// MsgTypes are the message types of the lane. A transaction belongs to
+	// the lane if all of its messages have one of these types. Exactly one
+	// lane, the default lane, has no message types; it holds every
+	// transaction no other lane matches.
+	//
+	// SECURITY: Requiring every message to match keeps transactions from
+	// reaching a lane by bundling one of its messages with others.
+	MsgTypes []string
+
+	// MaxTxs is the maximum number of transactions in the lane
+	MaxTxs int
+
+	// MaxBytes is the maximum total size of the lane's transactions
+	MaxBytes int64
+
+	// MaxBlockTxs optionally limits how many of the lane's transactions a
+	// block includes (0 means no limit), so a flooded high-priority lane
+	// cannot starve the lanes below it
+	MaxBlockTxs int
+
+	// Ordering is the order of the lane's transactions
+	Ordering Ordering
+}
+
+// ValidateBasic performs stateless validation
+func (l Lane) ValidateBasic() error {
+	if l.Name == "" {
+		return fmt.Errorf("lane name cannot be empty")
+	}
+	if l.MaxTxs <= 0 {
+		return fmt.Errorf("lane %s: max txs must be positive", l.Name)
+	}
+	if l.MaxBytes <= 0 {
+		return fmt.Errorf("lane %s: max bytes must be positive", l.Name)
+	}
+	if l.MaxBlockTxs < 0 {
+		return fmt.Errorf("lane %s: max block txs cannot be negative", l.Name)
+	}
+	if l.Ordering > OrderPriority {
+		return fmt.Errorf("lane %s: unknown ordering %d", l.Name, uint8(l.Ordering))
+	}
+	for _, msgType := range l.MsgTypes {
+		if msgType == "" {
+			return fmt.Errorf("lane %s: message type cannot be empty", l.Name)
+		}
+	}
+	return nil
+}
+
+// PriorityFunc ranks a transaction within an OrderPriority lane; higher is
+// included first
+type PriorityFunc func(tx *types.Transaction) uint64
+
+// GasPriceScale scales the fee per unit of gas computed by GasPricePriority,
+// so that fees below one unit per gas still rank apart
+const GasPriceScale = 1_000_000
+
+// GasPricePriority returns a PriorityFunc ranking transactions by the fee in
+// denom they offer per unit of gas limit, times GasPriceScale.
+//
+// SECURITY: Transactions without a gas limit rank lowest; otherwise a small
+// fee could buy priority for unbounded execution.
+func GasPricePriority(denom string) PriorityFunc {
+	return func(tx *types.Transaction) uint64 {
+		return perGas(tx.Fee.Amount.AmountOf(denom), tx.Fee.GasLimit)
+	}
+}
+
+// TipPriority returns a PriorityFunc ranking transactions by the priority
+// tip in denom (types.Transaction.Tip) they offer per unit of gas limit,
+// times GasPriceScale. The base fee is ignored: every admitted transaction
+// pays it, so only the tip distinguishes them. Transactions without a tip
+// in denom rank lowest.
+//
+// SECURITY: As for GasPricePriority, transactions without a gas limit rank
+// lowest. The fee ante handler must charge the tip, or ranking by it is free.
+func TipPriority(denom string) PriorityFunc {
+	return func(tx *types.Transaction) uint64 {
+		if tx.Tip == nil || tx.Tip.Denom != denom {
+			return 0
+		}
+		return perGas(tx.Tip.Amount, tx.Fee.GasLimit)
+	}
+}
+
+// perGas returns amount × GasPriceScale / gasLimit, saturating at
+// math.MaxUint64; zero if gasLimit is zero.
+func perGas(amount, gasLimit uint64) uint64 {
+	if gasLimit == 0 {
+		return 0
+	}
+	hi, lo := bits.Mul64(amount, GasPriceScale)
+	if hi >= gasLimit {
+		return math.MaxUint64
+	}
+	price, _ := bits.Div64(hi, lo, gasLimit)
+	return price
+}
+
+// entry is a transaction in the mempool
+type entry struct {
+	hash     string
+	txBytes  []byte
+	account  types.AccountName
+	nonce    uint64
+	priority uint64
+	seq      uint64
+	lane     *lane
+}
+
+// lane is the state of a configured Lane
+type lane struct {
+	Lane
+	msgTypes map[string]struct{}
+	txs      map[string]*entry
+	bytes    int64
+}
+
+// matches reports whether every message of tx has one of the lane's types
+func (l *lane) matches(tx *types.Transaction) bool {
+	if len(l.msgTypes) == 0 || len(tx.Messages) == 0 {
+		return false
+	}
+	for _, msg := range tx.Messages {
+		if _, ok := l.msgTypes[msg.Type()]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// before reports whether a is included before b
+func (l *lane) before(a, b *entry) bool {
+	if l.Ordering == OrderPriority && a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	return a.seq < b.seq
+}
+
+// fits reports whether the lane can take n more transactions of size bytes
+func (l *lane) fits(n int, size int64) bool {
+	return len(l.txs)+n <= l.MaxTxs && l.bytes+size <= l.MaxBytes
+}
+
+// victims returns the entries to evict so that e fits, lowest priority and
+// newest first, or false if e cannot be admitted.
+//
+// Complexity: O(n log n) for a lane of n transactions; only evaluated when
+// the lane is full.
+func (l *lane) victims(e *entry) ([]*entry, bool) {
+	if l.fits(1, int64(len(e.txBytes))) {
+		return nil, true
+	}
+	if l.Ordering != OrderPriority {
+		return nil, false
+	}
+
+	candidates := &entryHeap{lane: l, worstFirst: true}
+	for _, c := range l.txs {
+		if c.priority < e.priority {
+			candidates.entries = append(candidates.entries, c)
+		}
+	}
+	heap.Init(candidates)
+
+	var evicted []*entry
+	var freed int64
+	for !l.fits(1-len(evicted), int64(len(e.txBytes))-freed) {
+		if candidates.Len() == 0 {
+			return nil, false
+		}
+		victim := heap.Pop(candidates).(*entry)
+		evicted = append(evicted, victim)
+		freed += int64(len(victim.txBytes))
+	}
+	return evicted, true
+}
+
+// entryHeap orders entries of a lane by inclusion order, or the reverse if
+// worstFirst is set
+type entryHeap struct {
+	lane       *lane
+	entries    []*entry
+	worstFirst bool
+}
+
+func (h *entryHeap) Len() int { return len(h.entries) }
+
+func (h *entryHeap) Less(i, j int) bool {
+	if h.worstFirst {
+		return h.lane.before(h.entries[j], h.entries[i])
+	}
+	return h.lane.before(h.entries[i], h.entries[j])
+}
+
+func (h *entryHeap) Swap(i, j int) { h.entries[i], h.entries[j] = h.entries[j], h.entries[i] }
+
+func (h *entryHeap) Push(x any) { h.entries = append(h.entries, x.(*entry)) }
+
+func (h *entryHeap) Pop() any {
+	last := h.entries[len(h.entries)-1]
+	h.entries = h.entries[:len(h.entries)-1]
+	return last
+}
@@ -0,0 +1,43 @@
+package mempool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+func TestGasPricePriority(t *testing.T) {
+	priority := GasPricePriority("stake")
+
+	tx, _ := testTx("alice", 0, 5, "bank.send")
+	assert.Equal(t, uint64(5*GasPriceScale/1000), priority(tx))
+
+	tx.Fee.Amount = types.NewCoins(types.NewCoin("other", 5))
+	assert.Equal(t, uint64(0), priority(tx))
+
+	tx.Fee = types.Fee{Amount: types.NewCoins(types.NewCoin("stake", 5))}
+	assert.Equal(t, uint64(0), priority(tx), "no gas limit ranks lowest")
+
+	tx.Fee = types.Fee{Amount: types.NewCoins(types.NewCoin("stake", ^uint64(0))), GasLimit: 1}
+	assert.Equal(t, ^uint64(0), priority(tx), "saturates instead of overflowing")
+}
+
+func TestTipPriority(t *testing.T) {
+	priority := TipPriority("stake")
+
+	tx, _ := testTx("alice", 0, 5, "bank.send")
+	assert.Equal(t, uint64(0), priority(tx), "no tip ranks lowest")
+
+	tip := types.NewCoin("stake", 2)
+	tx.Tip = &tip
+	assert.Equal(t, uint64(2*GasPriceScale/1000), priority(tx), "the fee does not count")
+
+	tx.Tip = &types.Coin{Denom: "other", Amount: 2}
+	assert.Equal(t, uint64(0), priority(tx))
+
+	tx.Tip = &tip
+	tx.Fee.GasLimit = 0
+	assert.Equal(t, uint64(0), priority(tx), "no gas limit ranks lowest")
+}
@@ -0,0 +1,344 @@
+// Package mempool provides a transaction pool with priority lanes.
+//
+// Transactions are classified into lanes by their message types, so that
+// liveness-critical messages (e.g. oracle votes or relayer packets) are not
+// crowded out by regular transfers. Each lane has its own size limits and
+// ordering; blocks are filled from the highest-priority lane first, subject
+// to each lane's per-block limit.
+//
+// The mempool does not validate transactions: callers admit only
+// transactions that passed the application's CheckTx, remove included ones
+// after each block, then call ReCheck to evict transactions the new state
+// invalidated.
+//
+// INVARIANT: An account's transactions are reaped in nonce order, whatever
+// their lanes, so a prioritized transaction never runs ahead of a lower
+// nonce of the same account.
+package mempool
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+var (
+	// ErrTxInMempool is returned when a transaction is already in the mempool
+	ErrTxInMempool = errors.New("transaction already in mempool")
+
+	// ErrNonceConflict is returned when the mempool already holds a
+	// transaction of the same account and nonce
+	ErrNonceConflict = errors.New("nonce already used by a transaction in mempool")
+
+	// ErrLaneFull is returned when a transaction's lane is full and it
+	// cannot evict enough lower-priority transactions
+	ErrLaneFull = errors.New("mempool lane is full")
+
+	// ErrTxTooLarge is returned when a transaction exceeds its lane's byte
+	// limit on its own
+	ErrTxTooLarge = errors.New("transaction too large for mempool lane")
+)
+
+// Config configures a Mempool
+type Config struct {
+	// Lanes are the mempool lanes; exactly one must have no message types
+	Lanes []Lane
+
+	// Priority ranks transactions of OrderPriority lanes; required if any
+	// lane uses OrderPriority. See GasPricePriority and TipPriority.
+	Priority PriorityFunc
+
+	// OnEvict is optionally called for every evicted transaction, e.g. to
+	// record eviction reasons. It is called without the mempool lock held.
+	OnEvict EvictFunc
+}
+
+// ValidateBasic performs stateless validation
+func (c Config) ValidateBasic() error {
+	if len(c.Lanes) == 0 {
+		return fmt.Errorf("at least one lane is required")
+	}
+
+	names := make(map[string]struct{}, len(c.Lanes))
+	msgTypes := make(map[string]string)
+	defaults := 0
+	for _, l := range c.Lanes {
+		if err := l.ValidateBasic(); err != nil {
+			return err
+		}
+		if _, exists := names[l.Name]; exists {
+			return fmt.Errorf("duplicate lane %s", l.Name)
+		}
+		names[l.Name] = struct{}{}
+
+		if len(l.MsgTypes) == 0 {
+			defaults++
+		}
+		for _, msgType := range l.MsgTypes {
+			if other, exists := msgTypes[msgType]; exists {
+				return fmt.Errorf("message type %s is in lanes %s and %s", msgType, other, l.Name)
+			}
+			msgTypes[msgType] = l.Name
+		}
+
+		if l.Ordering == OrderPriority && c.Priority == nil {
+			return fmt.Errorf("lane %s: priority ordering requires a priority function", l.Name)
+		}
+	}
+	if defaults != 1 {
+		return fmt.Errorf("exactly one lane must have no message types, got %d", defaults)
+	}
+	return nil
+}
+
+// Mempool holds pending transactions in lanes.
+//
+// Thread-safe: All methods are safe for concurrent use.
+type Mempool struct {
+	priority PriorityFunc
+	onEvict  EvictFunc
+
+	mu        sync.Mutex
+	lanes     []*lane // in priority order
+	byHash    map[string]*entry
+	byAccount map[types.AccountName]map[uint64]*entry
+	seq       uint64
+}
+
+// New creates an empty mempool
+func New(config Config) (*Mempool, error) {
+	if err := config.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	m := &Mempool{
+		priority:  config.Priority,
+		onEvict:   config.OnEvict,
+		lanes:     make([]*lane, len(config.Lanes)),
+		byHash:    make(map[string]*entry),
+		byAccount: make(map[types.AccountName]map[uint64]*entry),
+	}
+	for i, l := range config.Lanes {
+		l.MsgTypes = append([]string(nil), l.MsgTypes...)
+		m.lanes[i] = &lane{
+			Lane:     l,
+			msgTypes: make(map[string]struct{}, len(l.MsgTypes)),
+			txs:      make(map[string]*entry),
+		}
+		for _, msgType := range l.MsgTypes {
+			m.lanes[i].msgTypes[msgType] = struct{}{}
+		}
+	}
+	sort.SliceStable(m.lanes, func(i, j int) bool {
+		return m.lanes[i].Priority > m.lanes[j].Priority
+	})
+	return m, nil
+}
+
+// LaneOf returns the name of the lane tx belongs to
+func (m *Mempool) LaneOf(tx *types.Transaction) string {
+	return m.classify(tx).Name
+}
+
+// classify returns the lane tx belongs to
+func (m *Mempool) classify(tx *types.Transaction) *lane {
+	var fallback *lane
+	for _, l := range m.lanes {
+		if len(l.msgTypes) == 0 {
+			fallback = l
+			continue
+		}
+		if l.matches(tx) {
+			return l
+		}
+	}
+	return fallback
+}
+
+// Insert adds tx, encoded as txBytes, to its lane. A full OrderPriority lane
+// evicts lower-priority transactions to make room (EvictionCapacity).
+//
+// PRECONDITION: tx passed the application's CheckTx and txBytes is its
+// encoding.
+func (m *Mempool) Insert(tx *types.Transaction, txBytes []byte) error {
+	if tx == nil {
+		return fmt.Errorf("transaction cannot be nil")
+	}
+	if len(txBytes) == 0 {
+		return fmt.Errorf("transaction bytes cannot be empty")
+	}
+
+	l := m.classify(tx)
+	e := &entry{
+		hash:    string(types.TxHash(txBytes)),
+		txBytes: append([]byte(nil), txBytes...),
+		account: tx.Account,
+		nonce:   tx.Nonce,
+		lane:    l,
+	}
+	if l.Ordering == OrderPriority {
+		e.priority = m.priority(tx)
+	}
+	if int64(len(e.txBytes)) > l.MaxBytes {
+		return fmt.Errorf("%w: %d bytes exceeds lane %s limit of %d", ErrTxTooLarge, len(e.txBytes), l.Name, l.MaxBytes)
+	}
+
+	evicted, err := m.insert(e)
+	if err != nil {
+		return err
+	}
+	m.notify(evicted)
+	return nil
+}
+
+// insert adds e to its lane, evicting lower-priority transactions as needed,
+// and returns the evictions
+func (m *Mempool) insert(e *entry) ([]Eviction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.byHash[e.hash]; exists {
+		return nil, ErrTxInMempool
+	}
+	if _, exists := m.byAccount[e.account][e.nonce]; exists {
+		return nil, fmt.Errorf("%w: account %s nonce %d", ErrNonceConflict, e.account, e.nonce)
+	}
+
+	victims, ok := e.lane.victims(e)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrLaneFull, e.lane.Name)
+	}
+	evicted := make([]Eviction, len(victims))
+	for i, victim := range victims {
+		m.remove(victim)
+		evicted[i] = victim.eviction(EvictionCapacity, nil)
+	}
+
+	m.seq++
+	e.seq = m.seq
+	e.lane.txs[e.hash] = e
+	e.lane.bytes += int64(len(e.txBytes))
+	m.byHash[e.hash] = e
+	if m.byAccount[e.account] == nil {
+		m.byAccount[e.account] = make(map[uint64]*entry)
+	}
+	m.byAccount[e.account][e.nonce] = e
+	return evicted, nil
+}
+
+// Remove removes the transaction encoded as txBytes, e.g. after it was
+// included in a block, and reports whether it was in the mempool
+func (m *Mempool) Remove(txBytes []byte) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.byHash[string(types.TxHash(txBytes))]
+	if !ok {
+		return false
+	}
+	m.remove(e)
+	return true
+}
+
+// remove removes e.
+//
+// PRECONDITION: m.mu is held and e is in the mempool.
+func (m *Mempool) remove(e *entry) {
+	delete(e.lane.txs, e.hash)
+	e.lane.bytes -= int64(len(e.txBytes))
+	delete(m.byHash, e.hash)
+	delete(m.byAccount[e.account], e.nonce)
+	if len(m.byAccount[e.account]) == 0 {
+		delete(m.byAccount, e.account)
+	}
+}
+
+// Len returns the number of transactions in the mempool
+func (m *Mempool) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.byHash)
+}
+
+// LaneLen returns the number of transactions in the named lane
+func (m *Mempool) LaneLen(name string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, l := range m.lanes {
+		if l.Name == name {
+			return len(l.txs)
+		}
+	}
+	return 0
+}
+
+// Reap returns transactions for a block proposal, without removing them: at
+// most maxTxs transactions of at most maxBytes in total (non-positive means
+// no limit).
+//
+// Lanes are drained in priority order, each in its own ordering and up to its
+// MaxBlockTxs. An account's transaction is only eligible once its
+// next-lower nonce in the mempool was reaped, so a transaction following a
+// nonce gap, or a nonce that did not fit, is left out.
+//
+// Complexity: O(n log n) for n transactions in the mempool.
+func (m *Mempool) Reap(maxBytes int64, maxTxs int) [][]byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Each lane's eligible transactions: initially every account's lowest
+	// nonce
+	eligible := make(map[*lane]*entryHeap, len(m.lanes))
+	for _, l := range m.lanes {
+		eligible[l] = &entryHeap{lane: l}
+	}
+	for _, txs := range m.byAccount {
+		var first *entry
+		for _, e := range txs {
+			if first == nil || e.nonce < first.nonce {
+				first = e
+			}
+		}
+		eligible[first.lane].entries = append(eligible[first.lane].entries, first)
+	}
+	for _, h := range eligible {
+		heap.Init(h)
+	}
+
+	var reaped [][]byte
+	var size int64
+	included := make(map[*lane]int, len(m.lanes))
+	for maxTxs <= 0 || len(reaped) < maxTxs {
+		var next *entry
+		for _, l := range m.lanes {
+			if eligible[l].Len() > 0 && (l.MaxBlockTxs == 0 || included[l] < l.MaxBlockTxs) {
+				next = heap.Pop(eligible[l]).(*entry)
+				break
+			}
+		}
+		if next == nil {
+			break
+		}
+		if maxBytes > 0 && size+int64(len(next.txBytes)) > maxBytes {
+			continue
+		}
+
+		reaped = append(reaped, append([]byte(nil), next.txBytes...))
+		size += int64(len(next.txBytes))
+		included[next.lane]++
+		if next.nonce == math.MaxUint64 {
+			continue
+		}
+		if following, ok := m.byAccount[next.account][next.nonce+1]; ok {
+			heap.Push(eligible[following.lane], following)
+		}
+	}
+	return reaped
+}
@@ -0,0 +1,214 @@
+package mempool
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// testMsg is a message of a given type
+type testMsg struct {
+	msgType string
+}
+
+func (m testMsg) Type() string                    { return m.msgType }
+func (m testMsg) ValidateBasic() error            { return nil }
+func (m testMsg) GetSigners() []types.AccountName { return nil }
+
+// testTx returns a transaction and a distinct encoding for it
+func testTx(account types.AccountName, nonce uint64, fee uint64, msgTypes ...string) (*types.Transaction, []byte) {
+	msgs := make([]types.Message, len(msgTypes))
+	for i, msgType := range msgTypes {
+		msgs[i] = testMsg{msgType: msgType}
+	}
+	tx := types.NewTransaction(account, nonce, msgs, types.NewAuthorization())
+	tx.Fee = types.Fee{Amount: types.NewCoins(types.NewCoin("stake", fee)), GasLimit: 1000}
+	return tx, []byte(fmt.Sprintf("%s/%d/%d/%v", account, nonce, fee, msgTypes))
+}
+
+func testConfig() Config {
+	return Config{
+		Lanes: []Lane{
+			{Name: "default", Priority: 0, MaxTxs: 3, MaxBytes: 1 << 20, Ordering: OrderPriority},
+			{Name: "oracle", Priority: 10, MsgTypes: []string{"oracle.vote"}, MaxTxs: 10, MaxBytes: 1 << 20, MaxBlockTxs: 2},
+		},
+		Priority: GasPricePriority("stake"),
+	}
+}
+
+func TestConfig_ValidateBasic(t *testing.T) {
+	valid := testConfig()
+	require.NoError(t, valid.ValidateBasic())
+
+	tests := []struct {
+		name   string
+		mutate func(c *Config)
+	}{
+		{"no lanes", func(c *Config) { c.Lanes = nil }},
+		{"duplicate lane", func(c *Config) { c.Lanes[1].Name = "default" }},
+		{"no default lane", func(c *Config) { c.Lanes[0].MsgTypes = []string{"bank.send"} }},
+		{"two default lanes", func(c *Config) { c.Lanes[1].MsgTypes = nil }},
+		{"message type in two lanes", func(c *Config) {
+			c.Lanes = append(c.Lanes, Lane{Name: "votes", MsgTypes: []string{"oracle.vote"}, MaxTxs: 1, MaxBytes: 1})
+		}},
+		{"priority without function", func(c *Config) { c.Priority = nil }},
+		{"zero max txs", func(c *Config) { c.Lanes[0].MaxTxs = 0 }},
+		{"zero max bytes", func(c *Config) { c.Lanes[1].MaxBytes = 0 }},
+		{"negative max block txs", func(c *Config) { c.Lanes[1].MaxBlockTxs = -1 }},
+		{"unknown ordering", func(c *Config) { c.Lanes[1].Ordering = OrderPriority + 1 }},
+		{"empty lane name", func(c *Config) { c.Lanes[1].Name = "" }},
+		{"empty message type", func(c *Config) { c.Lanes[1].MsgTypes = []string{""} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig()
+			tt.mutate(&config)
+			assert.Error(t, config.ValidateBasic())
+			_, err := New(config)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMempool_LaneOf(t *testing.T) {
+	m, err := New(testConfig())
+	require.NoError(t, err)
+
+	vote, _ := testTx("alice", 0, 1, "oracle.vote")
+	votes, _ := testTx("alice", 0, 1, "oracle.vote", "oracle.vote")
+	send, _ := testTx("alice", 0, 1, "bank.send")
+	bundled, _ := testTx("alice", 0, 1, "oracle.vote", "bank.send")
+
+	assert.Equal(t, "oracle", m.LaneOf(vote))
+	assert.Equal(t, "oracle", m.LaneOf(votes))
+	assert.Equal(t, "default", m.LaneOf(send))
+	// A regular message cannot ride along into the oracle lane
+	assert.Equal(t, "default", m.LaneOf(bundled))
+}
+
+func TestMempool_InsertRemove(t *testing.T) {
+	m, err := New(testConfig())
+	require.NoError(t, err)
+
+	tx, bz := testTx("alice", 0, 1, "bank.send")
+	require.NoError(t, m.Insert(tx, bz))
+	assert.ErrorIs(t, m.Insert(tx, bz), ErrTxInMempool)
+
+	conflicting, conflictingBz := testTx("alice", 0, 2, "bank.send")
+	assert.ErrorIs(t, m.Insert(conflicting, conflictingBz), ErrNonceConflict)
+
+	vote, voteBz := testTx("bob", 0, 1, "oracle.vote")
+	require.NoError(t, m.Insert(vote, voteBz))
+	assert.Equal(t, 2, m.Len())
+	assert.Equal(t, 1, m.LaneLen("default"))
+	assert.Equal(t, 1, m.LaneLen("oracle"))
+	assert.Equal(t, 0, m.LaneLen("missing"))
+
+	assert.True(t, m.Remove(bz))
+	assert.False(t, m.Remove(bz))
+	assert.Equal(t, 1, m.Len())
+	assert.Equal(t, 0, m.LaneLen("default"))
+
+	// The nonce is free again
+	require.NoError(t, m.Insert(conflicting, conflictingBz))
+}
+
+func TestMempool_LaneLimits(t *testing.T) {
+	t.Run("fifo lane rejects when full", func(t *testing.T) {
+		config := testConfig()
+		config.Lanes[1].MaxTxs = 1
+		m, err := New(config)
+		require.NoError(t, err)
+
+		tx, bz := testTx("alice", 0, 1, "oracle.vote")
+		require.NoError(t, m.Insert(tx, bz))
+		tx, bz = testTx("bob", 0, 100, "oracle.vote")
+		assert.ErrorIs(t, m.Insert(tx, bz), ErrLaneFull)
+
+		// Other lanes are unaffected
+		tx, bz = testTx("bob", 0, 1, "bank.send")
+		assert.NoError(t, m.Insert(tx, bz))
+	})
+
+	t.Run("priority lane evicts lower priority", func(t *testing.T) {
+		m, err := New(testConfig())
+		require.NoError(t, err)
+
+		var encoded [][]byte
+		for i, fee := range []uint64{5, 1, 5} {
+			tx, bz := testTx(types.AccountName(fmt.Sprintf("acct%d", i)), 0, fee, "bank.send")
+			require.NoError(t, m.Insert(tx, bz))
+			encoded = append(encoded, bz)
+		}
+
+		// Not strictly higher than the lowest priority
+		tx, bz := testTx("late", 0, 1, "bank.send")
+		assert.ErrorIs(t, m.Insert(tx, bz), ErrLaneFull)
+
+		tx, bz = testTx("late", 0, 3, "bank.send")
+		require.NoError(t, m.Insert(tx, bz))
+		assert.Equal(t, 3, m.LaneLen("default"))
+		assert.False(t, m.Remove(encoded[1]), "lowest fee transaction should have been evicted")
+	})
+
+	t.Run("byte limit", func(t *testing.T) {
+		config := testConfig()
+		config.Lanes[1].MaxBytes = 40
+		m, err := New(config)
+		require.NoError(t, err)
+
+		tx, _ := testTx("alice", 0, 1, "oracle.vote")
+		assert.ErrorIs(t, m.Insert(tx, make([]byte, 41)), ErrTxTooLarge)
+		require.NoError(t, m.Insert(tx, make([]byte, 30)))
+		tx, _ = testTx("bob", 0, 1, "oracle.vote")
+		assert.ErrorIs(t, m.Insert(tx, make([]byte, 11)), ErrLaneFull)
+	})
+}
+
+func TestMempool_Reap(t *testing.T) {
+	config := testConfig()
+	config.Lanes[0].MaxTxs = 10
+	m, err := New(config)
+	require.NoError(t, err)
+
+	insert := func(account types.AccountName, nonce, fee uint64, msgTypes ...string) []byte {
+		tx, bz := testTx(account, nonce, fee, msgTypes...)
+		require.NoError(t, m.Insert(tx, bz))
+		return bz
+	}
+
+	send1 := insert("alice", 0, 1, "bank.send")
+	send9 := insert("bob", 0, 9, "bank.send")
+	vote1 := insert("carol", 0, 1, "oracle.vote")
+	vote2 := insert("dave", 0, 1, "oracle.vote")
+	vote3 := insert("erin", 0, 1, "oracle.vote")
+
+	t.Run("lanes in priority order", func(t *testing.T) {
+		// The oracle lane is capped at 2 per block, in arrival order; the
+		// default lane follows by fee
+		assert.Equal(t, [][]byte{vote1, vote2, send9, send1}, m.Reap(0, 0))
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		assert.Equal(t, [][]byte{vote1, vote2}, m.Reap(0, 2))
+		assert.Equal(t, [][]byte{vote1}, m.Reap(int64(len(vote1)), 0))
+	})
+
+	t.Run("nonce order across lanes", func(t *testing.T) {
+		// alice's vote follows her pending transfer, so it waits for it
+		vote := insert("alice", 1, 1, "oracle.vote")
+		// frank's nonce 2 follows a gap and is never reaped
+		frank := insert("frank", 0, 2, "bank.send")
+		insert("frank", 2, 100, "bank.send")
+
+		assert.Equal(t, [][]byte{vote1, vote2, send9, frank, send1}, m.Reap(0, 0))
+
+		require.True(t, m.Remove(vote1))
+		require.True(t, m.Remove(vote2))
+		assert.Equal(t, [][]byte{vote3, send9, frank, send1, vote}, m.Reap(0, 0))
+	})
+}
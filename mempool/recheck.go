@@ -0,0 +1,163 @@
+package mempool
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// EvictionReason is why a transaction was evicted
+type EvictionReason uint8
+
+const (
+	// EvictionCapacity: a higher-priority transaction needed room in a full
+	// lane
+	EvictionCapacity EvictionReason = iota
+
+	// EvictionReCheck: the transaction failed ReCheck against the state after
+	// a block; Eviction.Err holds the failure
+	EvictionReCheck
+
+	// EvictionNonceGap: a lower nonce of the same account failed ReCheck, so
+	// the transaction can no longer execute
+	EvictionNonceGap
+)
+
+// String returns the reason name
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictionCapacity:
+		return "capacity"
+	case EvictionReCheck:
+		return "recheck"
+	case EvictionNonceGap:
+		return "nonce_gap"
+	default:
+		return fmt.Sprintf("EvictionReason(%d)", uint8(r))
+	}
+}
+
+// Eviction describes an evicted transaction
+type Eviction struct {
+	// TxHash is the hash of the transaction encoding (see types.TxHash)
+	TxHash []byte
+
+	// Account and Nonce identify the transaction
+	Account types.AccountName
+	Nonce   uint64
+
+	// Lane is the name of the transaction's lane
+	Lane string
+
+	// Reason is why the transaction was evicted
+	Reason EvictionReason
+
+	// Err is the ReCheck failure for EvictionReCheck, nil otherwise. Callers
+	// can classify it with errors.Is, e.g. against types.ErrSequenceMismatch
+	// or types.ErrInsufficientFunds.
+	Err error
+}
+
+// EvictFunc is notified of an evicted transaction
+type EvictFunc func(Eviction)
+
+// CheckFunc validates an encoded transaction against the latest state, e.g.
+// runtime.Application.ReCheckTx
+type CheckFunc func(txBytes []byte) error
+
+// eviction describes the eviction of e
+func (e *entry) eviction(reason EvictionReason, err error) Eviction {
+	return Eviction{
+		TxHash:  []byte(e.hash),
+		Account: e.account,
+		Nonce:   e.nonce,
+		Lane:    e.lane.Name,
+		Reason:  reason,
+		Err:     err,
+	}
+}
+
+// ReCheck re-validates every transaction with check, typically after a block
+// was committed and its transactions removed, and evicts those that fail. It
+// returns the evictions, which are also passed to Config.OnEvict.
+//
+// Each account's transactions are checked in nonce order. A failure that is
+// not types.ErrSequenceMismatch (a nonce the chain already used) leaves a
+// nonce gap, so the account's later transactions are evicted unchecked with
+// EvictionNonceGap.
+//
+// check runs without the mempool lock held, so transactions may be inserted
+// concurrently; they are not checked by this pass.
+//
+// Complexity: O(n log n) plus n calls to check for n transactions.
+func (m *Mempool) ReCheck(check CheckFunc) []Eviction {
+	if check == nil {
+		return nil
+	}
+
+	// Snapshot each account's transactions in nonce order
+	m.mu.Lock()
+	accounts := make([][]*entry, 0, len(m.byAccount))
+	for _, txs := range m.byAccount {
+		pending := make([]*entry, 0, len(txs))
+		for _, e := range txs {
+			pending = append(pending, e)
+		}
+		sort.Slice(pending, func(i, j int) bool { return pending[i].nonce < pending[j].nonce })
+		accounts = append(accounts, pending)
+	}
+	m.mu.Unlock()
+
+	// Deterministic eviction order for OnEvict
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i][0].account < accounts[j][0].account })
+
+	var failed []Eviction
+	var victims []*entry
+	for _, pending := range accounts {
+		for i, e := range pending {
+			err := check(e.txBytes)
+			if err == nil {
+				continue
+			}
+			failed = append(failed, e.eviction(EvictionReCheck, err))
+			victims = append(victims, e)
+			if errors.Is(err, types.ErrSequenceMismatch) {
+				continue
+			}
+			for _, following := range pending[i+1:] {
+				failed = append(failed, following.eviction(EvictionNonceGap, nil))
+				victims = append(victims, following)
+			}
+			break
+		}
+	}
+
+	// Transactions removed while checking are not reported
+	m.mu.Lock()
+	evicted := make([]Eviction, 0, len(victims))
+	for i, e := range victims {
+		if m.byHash[e.hash] != e {
+			continue
+		}
+		m.remove(e)
+		evicted = append(evicted, failed[i])
+	}
+	m.mu.Unlock()
+
+	m.notify(evicted)
+	return evicted
+}
+
+// notify passes evictions to the eviction hook.
+//
+// PRECONDITION: m.mu is not held.
+func (m *Mempool) notify(evicted []Eviction) {
+	if m.onEvict == nil {
+		return
+	}
+	for _, eviction := range evicted {
+		m.onEvict(eviction)
+	}
+}
@@ -0,0 +1,88 @@
+package mempool
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+func TestMempool_ReCheck(t *testing.T) {
+	var hooked []Eviction
+	config := testConfig()
+	config.Lanes[0].MaxTxs = 10
+	config.OnEvict = func(e Eviction) { hooked = append(hooked, e) }
+	m, err := New(config)
+	require.NoError(t, err)
+
+	insert := func(account types.AccountName, nonce uint64) []byte {
+		tx, bz := testTx(account, nonce, 1, "bank.send")
+		require.NoError(t, m.Insert(tx, bz))
+		return bz
+	}
+	aliceStale := insert("alice", 0)
+	aliceNext := insert("alice", 1)
+	bobBroke := insert("bob", 3)
+	insert("bob", 4)
+	insert("bob", 5)
+	carol := insert("carol", 0)
+
+	failures := map[string]error{
+		string(aliceStale): fmt.Errorf("%w: nonce 0 already used", types.ErrSequenceMismatch),
+		string(bobBroke):   fmt.Errorf("%w: fee", types.ErrInsufficientFunds),
+	}
+	var checked [][]byte
+	evicted := m.ReCheck(func(txBytes []byte) error {
+		checked = append(checked, txBytes)
+		return failures[string(txBytes)]
+	})
+
+	// bob's later nonces are evicted without being checked
+	assert.Len(t, checked, 4)
+	require.Len(t, evicted, 4)
+	assert.Equal(t, evicted, hooked)
+
+	assert.Equal(t, types.AccountName("alice"), evicted[0].Account)
+	assert.Equal(t, EvictionReCheck, evicted[0].Reason)
+	assert.ErrorIs(t, evicted[0].Err, types.ErrSequenceMismatch)
+	assert.Equal(t, types.TxHash(aliceStale), evicted[0].TxHash)
+
+	assert.Equal(t, EvictionReCheck, evicted[1].Reason)
+	assert.ErrorIs(t, evicted[1].Err, types.ErrInsufficientFunds)
+	for _, e := range evicted[2:] {
+		assert.Equal(t, types.AccountName("bob"), e.Account)
+		assert.Equal(t, EvictionNonceGap, e.Reason)
+		assert.NoError(t, e.Err)
+		assert.Equal(t, "default", e.Lane)
+	}
+
+	assert.Equal(t, [][]byte{aliceNext, carol}, m.Reap(0, 0))
+	assert.Equal(t, 2, m.Len())
+
+	// Nothing left to evict
+	assert.Empty(t, m.ReCheck(func([]byte) error { return nil }))
+	assert.Nil(t, m.ReCheck(nil))
+}
+
+func TestMempool_OnEvictCapacity(t *testing.T) {
+	var hooked []Eviction
+	config := testConfig()
+	config.OnEvict = func(e Eviction) { hooked = append(hooked, e) }
+	m, err := New(config)
+	require.NoError(t, err)
+
+	for i, fee := range []uint64{5, 1, 5} {
+		tx, bz := testTx(types.AccountName(fmt.Sprintf("acct%d", i)), 0, fee, "bank.send")
+		require.NoError(t, m.Insert(tx, bz))
+	}
+	tx, bz := testTx("late", 0, 3, "bank.send")
+	require.NoError(t, m.Insert(tx, bz))
+
+	require.Len(t, hooked, 1)
+	assert.Equal(t, types.AccountName("acct1"), hooked[0].Account)
+	assert.Equal(t, EvictionCapacity, hooked[0].Reason)
+	assert.Equal(t, "capacity", hooked[0].Reason.String())
+}
@@ -2,6 +2,8 @@ package module
 
 import (
 	"fmt"
+
+	"github.com/blockberries/punnet-sdk/query"
 )
 
 // ModuleBuilder provides a fluent API for building modules
@@ -63,6 +65,60 @@ func (b *ModuleBuilder) WithDependency(moduleName string) *ModuleBuilder {
 	return b
 }
 
+// WithConsensusVersion sets the module's consensus (state layout) version.
+// Modules default to version 1.
+func (b *ModuleBuilder) WithConsensusVersion(version uint64) *ModuleBuilder {
+	if b == nil {
+		return nil
+	}
+
+	if b.err != nil {
+		return b
+	}
+
+	if version == 0 {
+		b.err = fmt.Errorf("consensus version must be positive")
+		return b
+	}
+
+	b.module.consensusVersion = version
+	return b
+}
+
+// WithInvariant registers an invariant under route "<module>/<name>"
+func (b *ModuleBuilder) WithInvariant(name string, inv Invariant) *ModuleBuilder {
+	if b == nil {
+		return nil
+	}
+
+	if b.err != nil {
+		return b
+	}
+
+	if name == "" {
+		b.err = fmt.Errorf("invariant name cannot be empty")
+		return b
+	}
+
+	if inv == nil {
+		b.err = fmt.Errorf("invariant cannot be nil for name %s", name)
+		return b
+	}
+
+	if b.module.invariants == nil {
+		b.module.invariants = make(map[string]Invariant)
+	}
+
+	// Check for duplicate
+	if _, exists := b.module.invariants[name]; exists {
+		b.err = fmt.Errorf("duplicate invariant: %s", name)
+		return b
+	}
+
+	b.module.invariants[name] = inv
+	return b
+}
+
 // WithDependencies adds multiple dependencies
 func (b *ModuleBuilder) WithDependencies(moduleNames ...string) *ModuleBuilder {
 	if b == nil {
@@ -187,6 +243,76 @@ func (b *ModuleBuilder) WithQueryHandlers(handlers map[string]QueryHandler) *Mod
 	return b
 }
 
+// WithQueryService registers a query.Handler, served by the application's
+// query.Server with height-pinned store reads
+func (b *ModuleBuilder) WithQueryService(path string, handler query.Handler) *ModuleBuilder {
+	if b == nil {
+		return nil
+	}
+
+	if b.err != nil {
+		return b
+	}
+
+	if path == "" {
+		b.err = fmt.Errorf("query service path cannot be empty")
+		return b
+	}
+
+	if handler == nil {
+		b.err = fmt.Errorf("handler cannot be nil for query service path %s", path)
+		return b
+	}
+
+	if b.module.queryServices == nil {
+		b.module.queryServices = make(map[string]query.Handler)
+	}
+
+	// Check for duplicate
+	if _, exists := b.module.queryServices[path]; exists {
+		b.err = fmt.Errorf("duplicate query service for path: %s", path)
+		return b
+	}
+
+	b.module.queryServices[path] = handler
+	return b
+}
+
+// WithAuthenticator registers an authenticator that accounts select as
+// runtime.AuthenticatorRoute(<module>, name)
+func (b *ModuleBuilder) WithAuthenticator(name string, authenticator Authenticator) *ModuleBuilder {
+	if b == nil {
+		return nil
+	}
+
+	if b.err != nil {
+		return b
+	}
+
+	if name == "" {
+		b.err = fmt.Errorf("authenticator name cannot be empty")
+		return b
+	}
+
+	if authenticator == nil {
+		b.err = fmt.Errorf("authenticator cannot be nil for name %s", name)
+		return b
+	}
+
+	if b.module.authenticators == nil {
+		b.module.authenticators = make(map[string]Authenticator)
+	}
+
+	// Check for duplicate
+	if _, exists := b.module.authenticators[name]; exists {
+		b.err = fmt.Errorf("duplicate authenticator: %s", name)
+		return b
+	}
+
+	b.module.authenticators[name] = authenticator
+	return b
+}
+
 // WithBeginBlocker sets the begin block handler
 func (b *ModuleBuilder) WithBeginBlocker(handler BeginBlocker) *ModuleBuilder {
 	if b == nil {
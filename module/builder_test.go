@@ -5,7 +5,9 @@ import (
 	"testing"
 
 	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/query"
 	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
 	"github.com/blockberries/punnet-sdk/types"
 	"github.com/stretchr/testify/require"
 )
@@ -36,6 +38,19 @@ func TestModuleBuilder_WithDependency(t *testing.T) {
 	require.Equal(t, "dep2", builder.module.dependencies[1])
 }
 
+func TestModuleBuilder_WithConsensusVersion(t *testing.T) {
+	mod, err := NewModuleBuilder("test").WithConsensusVersion(3).Build()
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), ConsensusVersionOf(mod))
+
+	mod, err = NewModuleBuilder("test").Build()
+	require.NoError(t, err)
+	require.Equal(t, DefaultConsensusVersion, ConsensusVersionOf(mod))
+
+	builder := NewModuleBuilder("test").WithConsensusVersion(0)
+	require.Error(t, builder.err)
+}
+
 func TestModuleBuilder_WithDependency_Empty(t *testing.T) {
 	builder := NewModuleBuilder("test").
 		WithDependency("")
@@ -465,3 +480,61 @@ func TestModuleBuilder_ErrorPropagation(t *testing.T) {
 	require.Error(t, builder.err)
 	require.Empty(t, builder.module.msgHandlers) // Handler should not have been added
 }
+
+func TestModuleBuilder_WithQueryService(t *testing.T) {
+	handler := func(ctx *query.Context, data []byte) ([]byte, error) { return data, nil }
+
+	mod, err := NewModuleBuilder("test").
+		WithQueryService("/test/b", handler).
+		WithQueryService("/test/a", handler).
+		Build()
+	require.NoError(t, err)
+
+	registrar, ok := mod.(query.Registrar)
+	require.True(t, ok)
+
+	server, err := query.NewServer(store.NewMemoryStore())
+	require.NoError(t, err)
+	require.NoError(t, registrar.RegisterQueryServices(server))
+	require.Equal(t, []string{"/test/a", "/test/b"}, server.Paths())
+
+	_, err = NewModuleBuilder("test").WithQueryService("", handler).Build()
+	require.Error(t, err)
+
+	_, err = NewModuleBuilder("test").WithQueryService("/test", nil).Build()
+	require.Error(t, err)
+
+	_, err = NewModuleBuilder("test").
+		WithQueryService("/test", handler).
+		WithQueryService("/test", handler).
+		Build()
+	require.Error(t, err)
+}
+
+func TestModuleBuilder_WithAuthenticator(t *testing.T) {
+	authenticator := func(ctx *runtime.Context, req *runtime.AuthRequest) ([]effects.Effect, error) {
+		return nil, req.VerifyAuthority()
+	}
+
+	mod, err := NewModuleBuilder("test").
+		WithAuthenticator("totp", authenticator).
+		Build()
+	require.NoError(t, err)
+
+	registrar, ok := mod.(runtime.AuthenticatorRegistrar)
+	require.True(t, ok)
+	require.Len(t, registrar.RegisterAuthenticators(), 1)
+	require.NotNil(t, registrar.RegisterAuthenticators()["totp"])
+
+	_, err = NewModuleBuilder("test").WithAuthenticator("", authenticator).Build()
+	require.Error(t, err)
+
+	_, err = NewModuleBuilder("test").WithAuthenticator("totp", nil).Build()
+	require.Error(t, err)
+
+	_, err = NewModuleBuilder("test").
+		WithAuthenticator("totp", authenticator).
+		WithAuthenticator("totp", authenticator).
+		Build()
+	require.Error(t, err)
+}
@@ -0,0 +1,14 @@
+package module
+
+import sdkerrors "github.com/blockberries/punnet-sdk/errors"
+
+// CodespaceModule is the result codespace of module framework errors
+const CodespaceModule = "module"
+
+// Module framework error codes.
+func init() {
+	sdkerrors.MustRegister(CodespaceModule, 2, ErrHandlerNotFound)
+	sdkerrors.MustRegister(CodespaceModule, 3, ErrQueryNotFound)
+	sdkerrors.MustRegister(CodespaceModule, 4, ErrInvariantBroken)
+	sdkerrors.MustRegister(CodespaceModule, 5, ErrUnknownInvariant)
+}
@@ -25,4 +25,8 @@ type (
 
 	// ExportGenesis exports the module's state for genesis
 	ExportGenesis = runtime.ExportGenesis
+
+	// Authenticator authorizes the transactions of accounts delegating
+	// authentication to it
+	Authenticator = runtime.Authenticator
 )
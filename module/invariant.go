@@ -0,0 +1,184 @@
+package module
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrInvariantBroken is returned when one or more invariants are violated
+	ErrInvariantBroken = errors.New("invariant broken")
+
+	// ErrDuplicateInvariant is returned when an invariant route is registered twice
+	ErrDuplicateInvariant = errors.New("invariant already registered")
+
+	// ErrUnknownInvariant is returned for unregistered invariant routes
+	ErrUnknownInvariant = errors.New("unknown invariant")
+)
+
+// Invariant checks a property that must always hold in committed state
+// (e.g. total supply equals the sum of balances). It returns a description
+// of the check and whether the invariant is broken.
+//
+// Invariants must be read-only and deterministic.
+type Invariant func(ctx context.Context) (msg string, broken bool)
+
+// HasInvariants is implemented by modules that contribute invariants
+type HasInvariants interface {
+	RegisterInvariants(registry *InvariantRegistry) error
+}
+
+// Violation describes a broken invariant
+type Violation struct {
+	// Route is the invariant route ("<module>/<name>")
+	Route string `json:"route"`
+
+	// Message is the description returned by the invariant
+	Message string `json:"message"`
+}
+
+// InvariantRegistry collects invariants contributed by modules.
+//
+// Routes are "<module>/<name>", e.g. "bank/total-supply".
+// Safe for concurrent use.
+type InvariantRegistry struct {
+	mu         sync.RWMutex
+	invariants map[string]Invariant
+}
+
+// NewInvariantRegistry creates an empty registry
+func NewInvariantRegistry() *InvariantRegistry {
+	return &InvariantRegistry{
+		invariants: make(map[string]Invariant),
+	}
+}
+
+// InvariantRoute returns the route of a module's invariant
+func InvariantRoute(moduleName, name string) string {
+	return moduleName + "/" + name
+}
+
+// Register registers an invariant of moduleName under name
+func (r *InvariantRegistry) Register(moduleName, name string, inv Invariant) error {
+	if r == nil {
+		return fmt.Errorf("invariant registry is nil")
+	}
+	if moduleName == "" {
+		return ErrModuleNameEmpty
+	}
+	if name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("invalid invariant name %q", name)
+	}
+	if inv == nil {
+		return fmt.Errorf("invariant %s cannot be nil", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	route := InvariantRoute(moduleName, name)
+	if _, exists := r.invariants[route]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateInvariant, route)
+	}
+	r.invariants[route] = inv
+	return nil
+}
+
+// RegisterModules registers the invariants of every module implementing HasInvariants
+func (r *InvariantRegistry) RegisterModules(modules []Module) error {
+	for _, m := range modules {
+		hasInvariants, ok := m.(HasInvariants)
+		if !ok {
+			continue
+		}
+		if err := hasInvariants.RegisterInvariants(r); err != nil {
+			return fmt.Errorf("failed to register invariants of module %s: %w", m.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Routes returns all registered routes in sorted order
+func (r *InvariantRegistry) Routes() []string {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	routes := make([]string, 0, len(r.invariants))
+	for route := range r.invariants {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+// Check runs the invariant registered under route
+func (r *InvariantRegistry) Check(ctx context.Context, route string) (*Violation, error) {
+	if r == nil {
+		return nil, fmt.Errorf("invariant registry is nil")
+	}
+
+	r.mu.RLock()
+	inv, exists := r.invariants[route]
+	r.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownInvariant, route)
+	}
+
+	msg, broken := inv(ctx)
+	if !broken {
+		return nil, nil
+	}
+	return &Violation{Route: route, Message: msg}, nil
+}
+
+// CheckAll runs every invariant in route order and returns the violations.
+// All invariants run even if an earlier one is broken, so the report is complete.
+func (r *InvariantRegistry) CheckAll(ctx context.Context) ([]Violation, error) {
+	violations := make([]Violation, 0)
+	for _, route := range r.Routes() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		violation, err := r.Check(ctx, route)
+		if err != nil {
+			return nil, err
+		}
+		if violation != nil {
+			violations = append(violations, *violation)
+		}
+	}
+	return violations, nil
+}
+
+// AssertAll runs every invariant and returns ErrInvariantBroken (wrapped,
+// listing each violation) if any is broken
+func (r *InvariantRegistry) AssertAll(ctx context.Context) error {
+	violations, err := r.CheckAll(ctx)
+	if err != nil {
+		return err
+	}
+	return ViolationsError(violations)
+}
+
+// ViolationsError returns an ErrInvariantBroken error describing violations,
+// or nil if there are none
+func ViolationsError(violations []Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	parts := make([]string, len(violations))
+	for i, v := range violations {
+		parts[i] = fmt.Sprintf("%s: %s", v.Route, v.Message)
+	}
+	return fmt.Errorf("%w: %s", ErrInvariantBroken, strings.Join(parts, "; "))
+}
@@ -0,0 +1,91 @@
+package module
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func constInvariant(msg string, broken bool) Invariant {
+	return func(context.Context) (string, bool) { return msg, broken }
+}
+
+func TestInvariantRegistry_Register(t *testing.T) {
+	r := NewInvariantRegistry()
+
+	require.NoError(t, r.Register("bank", "supply", constInvariant("ok", false)))
+	require.ErrorIs(t, r.Register("bank", "supply", constInvariant("ok", false)), ErrDuplicateInvariant)
+	require.ErrorIs(t, r.Register("", "supply", constInvariant("ok", false)), ErrModuleNameEmpty)
+	require.Error(t, r.Register("bank", "", constInvariant("ok", false)))
+	require.Error(t, r.Register("bank", "a/b", constInvariant("ok", false)))
+	require.Error(t, r.Register("bank", "nil", nil))
+
+	require.NoError(t, r.Register("auth", "accounts", constInvariant("ok", false)))
+	require.Equal(t, []string{"auth/accounts", "bank/supply"}, r.Routes())
+}
+
+func TestInvariantRegistry_Check(t *testing.T) {
+	r := NewInvariantRegistry()
+	require.NoError(t, r.Register("bank", "supply", constInvariant("supply mismatch", true)))
+	require.NoError(t, r.Register("auth", "accounts", constInvariant("ok", false)))
+	require.NoError(t, r.Register("staking", "power", constInvariant("negative power", true)))
+	ctx := context.Background()
+
+	violation, err := r.Check(ctx, "auth/accounts")
+	require.NoError(t, err)
+	require.Nil(t, violation)
+
+	violation, err = r.Check(ctx, "bank/supply")
+	require.NoError(t, err)
+	require.Equal(t, &Violation{Route: "bank/supply", Message: "supply mismatch"}, violation)
+
+	_, err = r.Check(ctx, "bank/unknown")
+	require.ErrorIs(t, err, ErrUnknownInvariant)
+
+	// All invariants run and violations are reported in route order
+	violations, err := r.CheckAll(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Violation{
+		{Route: "bank/supply", Message: "supply mismatch"},
+		{Route: "staking/power", Message: "negative power"},
+	}, violations)
+
+	err = r.AssertAll(ctx)
+	require.ErrorIs(t, err, ErrInvariantBroken)
+	require.ErrorContains(t, err, "bank/supply: supply mismatch")
+	require.ErrorContains(t, err, "staking/power: negative power")
+
+	require.NoError(t, NewInvariantRegistry().AssertAll(ctx))
+}
+
+func TestInvariantRegistry_RegisterModules(t *testing.T) {
+	bank, err := NewModuleBuilder("bank").
+		WithInvariant("supply", constInvariant("ok", false)).
+		WithInvariant("nonnegative", constInvariant("ok", false)).
+		Build()
+	require.NoError(t, err)
+	empty, err := NewModuleBuilder("empty").Build()
+	require.NoError(t, err)
+
+	r := NewInvariantRegistry()
+	require.NoError(t, r.RegisterModules([]Module{bank, empty}))
+	require.Equal(t, []string{"bank/nonnegative", "bank/supply"}, r.Routes())
+
+	// Registering the same modules again fails on the duplicate routes
+	require.ErrorIs(t, r.RegisterModules([]Module{bank}), ErrDuplicateInvariant)
+}
+
+func TestModuleBuilder_WithInvariant(t *testing.T) {
+	_, err := NewModuleBuilder("bank").WithInvariant("", constInvariant("ok", false)).Build()
+	require.Error(t, err)
+
+	_, err = NewModuleBuilder("bank").WithInvariant("supply", nil).Build()
+	require.Error(t, err)
+
+	_, err = NewModuleBuilder("bank").
+		WithInvariant("supply", constInvariant("ok", false)).
+		WithInvariant("supply", constInvariant("ok", false)).
+		Build()
+	require.Error(t, err)
+}
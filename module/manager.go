@@ -0,0 +1,509 @@
+package module
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+)
+
+var (
+	// ErrCapabilityNotGranted is returned when a module uses a capability it
+	// did not declare in its ModuleSpec
+	ErrCapabilityNotGranted = errors.New("capability not granted")
+
+	// ErrUnknownCapability is returned when a ModuleSpec declares an unknown capability
+	ErrUnknownCapability = errors.New("unknown capability")
+
+	// ErrManagerBuilt is returned when modifying a ModuleManager after Build
+	ErrManagerBuilt = errors.New("module manager already built")
+
+	// ErrInvalidOrder is returned when a lifecycle order is not a permutation of the modules
+	ErrInvalidOrder = errors.New("invalid module order")
+)
+
+// CapabilityKind names a capability a module can be granted
+type CapabilityKind string
+
+// Capability kinds
+const (
+	CapabilityAccount   CapabilityKind = "account"
+	CapabilityBalance   CapabilityKind = "balance"
+	CapabilityValidator CapabilityKind = "validator"
+
+	// CapabilityStore grants the module its own state namespace (see
+	// capability.StoreCapability)
+	CapabilityStore CapabilityKind = "store"
+
+	// CapabilityDispatch lets the module's message handlers invoke the
+	// message handlers of the modules listed in ModuleSpec.DispatchTargets
+	CapabilityDispatch CapabilityKind = "dispatch"
+)
+
+// Capabilities holds the capabilities granted to one module.
+// Accessors fail with ErrCapabilityNotGranted for capabilities the module did
+// not declare, so undeclared use is caught when the manager is built.
+type Capabilities struct {
+	module    string
+	account   capability.AccountCapability
+	balance   capability.BalanceCapability
+	validator capability.ValidatorCapability
+	store     capability.StoreCapability
+	dispatch  *runtime.MsgDispatcher
+}
+
+// Account returns the module's account capability
+func (c Capabilities) Account() (capability.AccountCapability, error) {
+	if c.account == nil {
+		return nil, fmt.Errorf("%w: module %s did not declare %s", ErrCapabilityNotGranted, c.module, CapabilityAccount)
+	}
+	return c.account, nil
+}
+
+// Balance returns the module's balance capability
+func (c Capabilities) Balance() (capability.BalanceCapability, error) {
+	if c.balance == nil {
+		return nil, fmt.Errorf("%w: module %s did not declare %s", ErrCapabilityNotGranted, c.module, CapabilityBalance)
+	}
+	return c.balance, nil
+}
+
+// Validator returns the module's validator capability
+func (c Capabilities) Validator() (capability.ValidatorCapability, error) {
+	if c.validator == nil {
+		return nil, fmt.Errorf("%w: module %s did not declare %s", ErrCapabilityNotGranted, c.module, CapabilityValidator)
+	}
+	return c.validator, nil
+}
+
+// Store returns the module's store capability
+func (c Capabilities) Store() (capability.StoreCapability, error) {
+	if c.store == nil {
+		return nil, fmt.Errorf("%w: module %s did not declare %s", ErrCapabilityNotGranted, c.module, CapabilityStore)
+	}
+	return c.store, nil
+}
+
+// Dispatcher returns the module's message dispatcher
+func (c Capabilities) Dispatcher() (*runtime.MsgDispatcher, error) {
+	if c.dispatch == nil {
+		return nil, fmt.Errorf("%w: module %s did not declare %s", ErrCapabilityNotGranted, c.module, CapabilityDispatch)
+	}
+	return c.dispatch, nil
+}
+
+// ModuleSpec describes a module for a ModuleManager
+type ModuleSpec struct {
+	// Name is the module name; the created module must report the same name
+	Name string
+
+	// Dependencies are the modules that must be initialized before this one
+	Dependencies []string
+
+	// Capabilities are the capabilities granted to the module
+	Capabilities []CapabilityKind
+
+	// DispatchTargets are the modules whose message handlers this module may
+	// invoke; requires CapabilityDispatch
+	DispatchTargets []string
+
+	// Create builds the module from its granted capabilities
+	Create func(caps Capabilities) (Module, error)
+}
+
+// ModuleManager wires an application's modules: it grants declared
+// capabilities, creates modules in dependency order, and computes the
+// InitGenesis/BeginBlock/EndBlock ordering.
+//
+// Usage:
+//
+//	mm := module.NewModuleManager(capability.NewCapabilityManager(backing))
+//	mm.Register(module.ModuleSpec{Name: "auth", Capabilities: ..., Create: ...})
+//	mm.Register(module.ModuleSpec{Name: "bank", Dependencies: []string{"auth"}, ...})
+//	if err := mm.Build(); err != nil { ... }
+//	app, err := runtime.NewApplication(mm.ApplicationConfig(chainID, stateStore))
+//
+// Registration order does not matter; Build resolves it. All configuration
+// errors (missing or cyclic dependencies, unknown or undeclared capabilities,
+// bad lifecycle orders) surface from Build, at startup.
+type ModuleManager struct {
+	mu         sync.RWMutex
+	capManager *capability.CapabilityManager
+	specs      map[string]ModuleSpec
+
+	// beginBlockOrder and endBlockOrder override the dependency order if set
+	beginBlockOrder []string
+	endBlockOrder   []string
+
+	// Set by Build
+	built    bool
+	registry *Registry
+	order    []string
+	caps     map[string]Capabilities
+}
+
+// NewModuleManager creates a module manager granting capabilities from capManager
+func NewModuleManager(capManager *capability.CapabilityManager) *ModuleManager {
+	return &ModuleManager{
+		capManager: capManager,
+		specs:      make(map[string]ModuleSpec),
+		caps:       make(map[string]Capabilities),
+	}
+}
+
+// Register adds a module spec
+func (mm *ModuleManager) Register(spec ModuleSpec) error {
+	if mm == nil {
+		return fmt.Errorf("module manager is nil")
+	}
+	if spec.Name == "" {
+		return ErrModuleNameEmpty
+	}
+	if spec.Create == nil {
+		return fmt.Errorf("module %s: create function cannot be nil", spec.Name)
+	}
+
+	seen := make(map[string]bool, len(spec.Dependencies))
+	for _, dep := range spec.Dependencies {
+		if dep == "" || dep == spec.Name || seen[dep] {
+			return fmt.Errorf("%w: module %s: dependency %q", ErrInvalidDependency, spec.Name, dep)
+		}
+		seen[dep] = true
+	}
+
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+
+	if mm.built {
+		return ErrManagerBuilt
+	}
+	if _, exists := mm.specs[spec.Name]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateModule, spec.Name)
+	}
+
+	// Store defensive copies
+	spec.Dependencies = append([]string(nil), spec.Dependencies...)
+	spec.Capabilities = append([]CapabilityKind(nil), spec.Capabilities...)
+	spec.DispatchTargets = append([]string(nil), spec.DispatchTargets...)
+	mm.specs[spec.Name] = spec
+	return nil
+}
+
+// SetBeginBlockOrder overrides the BeginBlock order (default: dependency order).
+// names must list every registered module exactly once; this is checked by Build.
+func (mm *ModuleManager) SetBeginBlockOrder(names ...string) {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	mm.beginBlockOrder = append([]string(nil), names...)
+}
+
+// SetEndBlockOrder overrides the EndBlock order (default: dependency order).
+// names must list every registered module exactly once; this is checked by Build.
+func (mm *ModuleManager) SetEndBlockOrder(names ...string) {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	mm.endBlockOrder = append([]string(nil), names...)
+}
+
+// Build resolves dependencies, grants capabilities, and creates all modules
+// in dependency order.
+//
+// POSTCONDITION: On success, every module is created and registered, and each
+// module's capabilities are granted exactly as declared.
+func (mm *ModuleManager) Build() error {
+	if mm == nil {
+		return fmt.Errorf("module manager is nil")
+	}
+
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+
+	if mm.built {
+		return ErrManagerBuilt
+	}
+	if mm.capManager == nil {
+		return fmt.Errorf("capability manager cannot be nil")
+	}
+	if len(mm.specs) == 0 {
+		return fmt.Errorf("no modules registered")
+	}
+
+	// Resolve initialization order
+	deps := make(map[string][]string, len(mm.specs))
+	for name, spec := range mm.specs {
+		for _, dep := range spec.Dependencies {
+			if _, exists := mm.specs[dep]; !exists {
+				return fmt.Errorf("%w: module %s requires %s", ErrMissingDependency, name, dep)
+			}
+		}
+		deps[name] = spec.Dependencies
+
+		if err := validateDispatchTargets(spec, mm.specs); err != nil {
+			return err
+		}
+	}
+	order, err := sortByDependencies(deps)
+	if err != nil {
+		return err
+	}
+
+	for _, custom := range [][]string{mm.beginBlockOrder, mm.endBlockOrder} {
+		if err := validateOrder(custom, mm.specs); err != nil {
+			return err
+		}
+	}
+
+	// Grant capabilities and create modules
+	registry := NewRegistry()
+	caps := make(map[string]Capabilities, len(order))
+	for _, name := range order {
+		spec := mm.specs[name]
+
+		granted, err := mm.grant(spec)
+		if err != nil {
+			return err
+		}
+
+		mod, err := spec.Create(granted)
+		if err != nil {
+			return fmt.Errorf("failed to create module %s: %w", name, err)
+		}
+		if mod == nil {
+			return fmt.Errorf("module %s: %w", name, ErrModuleNil)
+		}
+		if mod.Name() != name {
+			return fmt.Errorf("module %s: created module reports name %q", name, mod.Name())
+		}
+		for _, dep := range mod.Dependencies() {
+			if !containsString(spec.Dependencies, dep) {
+				return fmt.Errorf("%w: module %s depends on %s, which its spec does not declare",
+					ErrMissingDependency, name, dep)
+			}
+		}
+
+		if err := registry.Register(mod); err != nil {
+			return fmt.Errorf("failed to register module %s: %w", name, err)
+		}
+		caps[name] = granted
+	}
+	if err := registry.Build(); err != nil {
+		return err
+	}
+
+	mm.registry = registry
+	mm.order = order
+	mm.caps = caps
+	mm.built = true
+	return nil
+}
+
+// grant registers spec's module with the capability manager and grants its
+// declared capabilities.
+func (mm *ModuleManager) grant(spec ModuleSpec) (Capabilities, error) {
+	caps := Capabilities{module: spec.Name}
+
+	if !mm.capManager.IsModuleRegistered(spec.Name) {
+		if err := mm.capManager.RegisterModule(spec.Name); err != nil {
+			return caps, fmt.Errorf("failed to register module %s with capability manager: %w", spec.Name, err)
+		}
+	}
+
+	var err error
+	for _, kind := range spec.Capabilities {
+		switch kind {
+		case CapabilityAccount:
+			caps.account, err = mm.capManager.GrantAccountCapability(spec.Name)
+		case CapabilityBalance:
+			caps.balance, err = mm.capManager.GrantBalanceCapability(spec.Name)
+		case CapabilityValidator:
+			caps.validator, err = mm.capManager.GrantValidatorCapability(spec.Name)
+		case CapabilityStore:
+			caps.store, err = mm.capManager.GrantStoreCapability(spec.Name)
+		case CapabilityDispatch:
+			caps.dispatch, err = runtime.NewMsgDispatcher(spec.Name, spec.DispatchTargets...)
+		default:
+			return caps, fmt.Errorf("%w: module %s: %q", ErrUnknownCapability, spec.Name, kind)
+		}
+		if err != nil {
+			return caps, fmt.Errorf("failed to grant %s capability to module %s: %w", kind, spec.Name, err)
+		}
+	}
+	return caps, nil
+}
+
+// validateDispatchTargets checks that spec declares CapabilityDispatch exactly
+// when it lists dispatch targets, and that every target is a registered module
+func validateDispatchTargets(spec ModuleSpec, specs map[string]ModuleSpec) error {
+	declared := false
+	for _, kind := range spec.Capabilities {
+		if kind == CapabilityDispatch {
+			declared = true
+		}
+	}
+
+	if !declared {
+		if len(spec.DispatchTargets) > 0 {
+			return fmt.Errorf("%w: module %s lists dispatch targets without declaring %s",
+				ErrCapabilityNotGranted, spec.Name, CapabilityDispatch)
+		}
+		return nil
+	}
+	if len(spec.DispatchTargets) == 0 {
+		return fmt.Errorf("module %s: %s capability requires dispatch targets", spec.Name, CapabilityDispatch)
+	}
+
+	for _, target := range spec.DispatchTargets {
+		if target == spec.Name {
+			return fmt.Errorf("module %s: cannot dispatch to itself", spec.Name)
+		}
+		if _, exists := specs[target]; !exists {
+			return fmt.Errorf("%w: module %s dispatch target %s", ErrModuleNotFound, spec.Name, target)
+		}
+	}
+	return nil
+}
+
+// Modules returns the modules in initialization (dependency) order
+func (mm *ModuleManager) Modules() ([]Module, error) {
+	if mm == nil {
+		return nil, fmt.Errorf("module manager is nil")
+	}
+
+	mm.mu.RLock()
+	defer mm.mu.RUnlock()
+
+	if !mm.built {
+		return nil, fmt.Errorf("module manager not built: call Build() first")
+	}
+	return mm.registry.Modules()
+}
+
+// Module returns a built module by name
+func (mm *ModuleManager) Module(name string) (Module, error) {
+	if mm == nil {
+		return nil, fmt.Errorf("module manager is nil")
+	}
+
+	mm.mu.RLock()
+	defer mm.mu.RUnlock()
+
+	if !mm.built {
+		return nil, fmt.Errorf("module manager not built: call Build() first")
+	}
+	return mm.registry.Get(name)
+}
+
+// Capabilities returns the capabilities granted to a module
+func (mm *ModuleManager) Capabilities(name string) (Capabilities, error) {
+	if mm == nil {
+		return Capabilities{}, fmt.Errorf("module manager is nil")
+	}
+
+	mm.mu.RLock()
+	defer mm.mu.RUnlock()
+
+	caps, exists := mm.caps[name]
+	if !exists {
+		return Capabilities{}, fmt.Errorf("%w: %s", ErrModuleNotFound, name)
+	}
+	return caps, nil
+}
+
+// InitOrder returns module names in initialization (dependency) order
+func (mm *ModuleManager) InitOrder() []string {
+	if mm == nil {
+		return nil
+	}
+
+	mm.mu.RLock()
+	defer mm.mu.RUnlock()
+	return append([]string(nil), mm.order...)
+}
+
+// BeginBlockOrder returns module names in BeginBlock order
+func (mm *ModuleManager) BeginBlockOrder() []string {
+	if mm == nil {
+		return nil
+	}
+
+	mm.mu.RLock()
+	defer mm.mu.RUnlock()
+	if len(mm.beginBlockOrder) > 0 {
+		return append([]string(nil), mm.beginBlockOrder...)
+	}
+	return append([]string(nil), mm.order...)
+}
+
+// EndBlockOrder returns module names in EndBlock order
+func (mm *ModuleManager) EndBlockOrder() []string {
+	if mm == nil {
+		return nil
+	}
+
+	mm.mu.RLock()
+	defer mm.mu.RUnlock()
+	if len(mm.endBlockOrder) > 0 {
+		return append([]string(nil), mm.endBlockOrder...)
+	}
+	return append([]string(nil), mm.order...)
+}
+
+// ApplicationConfig returns a runtime.ApplicationConfig with the built
+// modules and their lifecycle orders.
+//
+// PRECONDITION: Build has succeeded.
+func (mm *ModuleManager) ApplicationConfig(chainID string, stateStore *store.IAVLStore) (runtime.ApplicationConfig, error) {
+	modules, err := mm.Modules()
+	if err != nil {
+		return runtime.ApplicationConfig{}, err
+	}
+
+	runtimeModules := make([]runtime.Module, len(modules))
+	for i, mod := range modules {
+		runtimeModules[i] = mod
+	}
+
+	return runtime.ApplicationConfig{
+		ChainID:          chainID,
+		StateStore:       stateStore,
+		Modules:          runtimeModules,
+		InitGenesisOrder: mm.InitOrder(),
+		BeginBlockOrder:  mm.BeginBlockOrder(),
+		EndBlockOrder:    mm.EndBlockOrder(),
+	}, nil
+}
+
+// validateOrder checks that order is empty or lists every spec exactly once
+func validateOrder(order []string, specs map[string]ModuleSpec) error {
+	if len(order) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(order))
+	for _, name := range order {
+		if _, exists := specs[name]; !exists {
+			return fmt.Errorf("%w: unknown module %s", ErrInvalidOrder, name)
+		}
+		if seen[name] {
+			return fmt.Errorf("%w: duplicate module %s", ErrInvalidOrder, name)
+		}
+		seen[name] = true
+	}
+	if len(seen) != len(specs) {
+		return fmt.Errorf("%w: lists %d of %d modules", ErrInvalidOrder, len(seen), len(specs))
+	}
+	return nil
+}
+
+// containsString reports whether s contains v
+func containsString(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
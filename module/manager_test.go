@@ -0,0 +1,203 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/stretchr/testify/require"
+)
+
+// simpleSpec returns a spec that builds an empty module with the given dependencies
+func simpleSpec(name string, deps ...string) ModuleSpec {
+	return ModuleSpec{
+		Name:         name,
+		Dependencies: deps,
+		Create: func(Capabilities) (Module, error) {
+			return NewModuleBuilder(name).WithDependencies(deps...).Build()
+		},
+	}
+}
+
+func newTestManager() *ModuleManager {
+	return NewModuleManager(capability.NewCapabilityManager(store.NewMemoryStore()))
+}
+
+func TestModuleManager_DependencyOrder(t *testing.T) {
+	mm := newTestManager()
+
+	// Registered out of dependency order
+	require.NoError(t, mm.Register(simpleSpec("staking", "bank", "auth")))
+	require.NoError(t, mm.Register(simpleSpec("bank", "auth")))
+	require.NoError(t, mm.Register(simpleSpec("auth")))
+	require.NoError(t, mm.Build())
+
+	require.Equal(t, []string{"auth", "bank", "staking"}, mm.InitOrder())
+	require.Equal(t, mm.InitOrder(), mm.BeginBlockOrder())
+	require.Equal(t, mm.InitOrder(), mm.EndBlockOrder())
+
+	modules, err := mm.Modules()
+	require.NoError(t, err)
+	require.Len(t, modules, 3)
+	require.Equal(t, "auth", modules[0].Name())
+
+	require.ErrorIs(t, mm.Register(simpleSpec("late")), ErrManagerBuilt)
+}
+
+func TestModuleManager_CustomLifecycleOrder(t *testing.T) {
+	mm := newTestManager()
+	require.NoError(t, mm.Register(simpleSpec("auth")))
+	require.NoError(t, mm.Register(simpleSpec("bank", "auth")))
+	mm.SetEndBlockOrder("bank", "auth")
+	require.NoError(t, mm.Build())
+
+	require.Equal(t, []string{"auth", "bank"}, mm.BeginBlockOrder())
+	require.Equal(t, []string{"bank", "auth"}, mm.EndBlockOrder())
+
+	bad := newTestManager()
+	require.NoError(t, bad.Register(simpleSpec("auth")))
+	require.NoError(t, bad.Register(simpleSpec("bank", "auth")))
+	bad.SetBeginBlockOrder("bank")
+	require.ErrorIs(t, bad.Build(), ErrInvalidOrder)
+}
+
+func TestModuleManager_DependencyErrors(t *testing.T) {
+	missing := newTestManager()
+	require.NoError(t, missing.Register(simpleSpec("bank", "auth")))
+	require.ErrorIs(t, missing.Build(), ErrMissingDependency)
+
+	cyclic := newTestManager()
+	require.NoError(t, cyclic.Register(simpleSpec("a", "b")))
+	require.NoError(t, cyclic.Register(simpleSpec("b", "a")))
+	require.ErrorIs(t, cyclic.Build(), ErrCyclicDependency)
+
+	// A module's own dependencies must be declared in its spec
+	undeclared := newTestManager()
+	require.NoError(t, undeclared.Register(simpleSpec("auth")))
+	require.NoError(t, undeclared.Register(ModuleSpec{
+		Name: "bank",
+		Create: func(Capabilities) (Module, error) {
+			return NewModuleBuilder("bank").WithDependency("auth").Build()
+		},
+	}))
+	require.ErrorIs(t, undeclared.Build(), ErrMissingDependency)
+
+	dup := newTestManager()
+	require.NoError(t, dup.Register(simpleSpec("auth")))
+	require.ErrorIs(t, dup.Register(simpleSpec("auth")), ErrDuplicateModule)
+}
+
+func TestModuleManager_Capabilities(t *testing.T) {
+	mm := newTestManager()
+	require.NoError(t, mm.Register(ModuleSpec{
+		Name:         "bank",
+		Capabilities: []CapabilityKind{CapabilityBalance, CapabilityStore},
+		Create: func(caps Capabilities) (Module, error) {
+			if _, err := caps.Balance(); err != nil {
+				return nil, err
+			}
+			return NewModuleBuilder("bank").Build()
+		},
+	}))
+	require.NoError(t, mm.Build())
+
+	caps, err := mm.Capabilities("bank")
+	require.NoError(t, err)
+	balance, err := caps.Balance()
+	require.NoError(t, err)
+	require.Equal(t, "bank", balance.ModuleName())
+	storeCap, err := caps.Store()
+	require.NoError(t, err)
+	require.Equal(t, "bank", storeCap.ModuleName())
+	_, err = caps.Account()
+	require.ErrorIs(t, err, ErrCapabilityNotGranted)
+}
+
+func TestModuleManager_UndeclaredCapabilityFailsAtBuild(t *testing.T) {
+	mm := newTestManager()
+	require.NoError(t, mm.Register(ModuleSpec{
+		Name: "auth",
+		Create: func(caps Capabilities) (Module, error) {
+			if _, err := caps.Account(); err != nil {
+				return nil, err
+			}
+			return NewModuleBuilder("auth").Build()
+		},
+	}))
+	require.ErrorIs(t, mm.Build(), ErrCapabilityNotGranted)
+
+	unknown := newTestManager()
+	require.NoError(t, unknown.Register(ModuleSpec{
+		Name:         "auth",
+		Capabilities: []CapabilityKind{"keys"},
+		Create:       simpleSpec("auth").Create,
+	}))
+	require.ErrorIs(t, unknown.Build(), ErrUnknownCapability)
+}
+
+func TestModuleManager_DispatchCapability(t *testing.T) {
+	mm := newTestManager()
+	require.NoError(t, mm.Register(simpleSpec("bank")))
+	require.NoError(t, mm.Register(ModuleSpec{
+		Name:            "gov",
+		Capabilities:    []CapabilityKind{CapabilityDispatch},
+		DispatchTargets: []string{"bank"},
+		Create:          simpleSpec("gov").Create,
+	}))
+	require.NoError(t, mm.Build())
+
+	caps, err := mm.Capabilities("gov")
+	require.NoError(t, err)
+	dispatcher, err := caps.Dispatcher()
+	require.NoError(t, err)
+	require.Equal(t, "gov", dispatcher.Caller())
+	require.Equal(t, []string{"bank"}, dispatcher.Targets())
+
+	bankCaps, err := mm.Capabilities("bank")
+	require.NoError(t, err)
+	_, err = bankCaps.Dispatcher()
+	require.ErrorIs(t, err, ErrCapabilityNotGranted)
+
+	tests := []struct {
+		name    string
+		spec    ModuleSpec
+		wantErr error
+	}{
+		{
+			name:    "targets without capability",
+			spec:    ModuleSpec{Name: "gov", DispatchTargets: []string{"bank"}, Create: simpleSpec("gov").Create},
+			wantErr: ErrCapabilityNotGranted,
+		},
+		{
+			name: "unknown target",
+			spec: ModuleSpec{
+				Name:            "gov",
+				Capabilities:    []CapabilityKind{CapabilityDispatch},
+				DispatchTargets: []string{"staking"},
+				Create:          simpleSpec("gov").Create,
+			},
+			wantErr: ErrModuleNotFound,
+		},
+		{
+			name: "capability without targets",
+			spec: ModuleSpec{
+				Name:         "gov",
+				Capabilities: []CapabilityKind{CapabilityDispatch},
+				Create:       simpleSpec("gov").Create,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bad := newTestManager()
+			require.NoError(t, bad.Register(simpleSpec("bank")))
+			require.NoError(t, bad.Register(tt.spec))
+			err := bad.Build()
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
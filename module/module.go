@@ -3,6 +3,9 @@ package module
 import (
 	"errors"
 	"fmt"
+	"sort"
+
+	"github.com/blockberries/punnet-sdk/query"
 )
 
 var (
@@ -63,6 +66,27 @@ type Module interface {
 	ExportGenesis() ExportGenesis
 }
 
+// DefaultConsensusVersion is the consensus version of modules that do not
+// implement HasConsensusVersion
+const DefaultConsensusVersion uint64 = 1
+
+// HasConsensusVersion is implemented by modules that version their state
+// layout. Increment the version whenever the layout changes and register a
+// migration from the previous version with an upgrade.Migrator.
+type HasConsensusVersion interface {
+	// ConsensusVersion returns the module's current state layout version (>= 1)
+	ConsensusVersion() uint64
+}
+
+// ConsensusVersionOf returns m's consensus version, or
+// DefaultConsensusVersion if m does not implement HasConsensusVersion
+func ConsensusVersionOf(m Module) uint64 {
+	if v, ok := m.(HasConsensusVersion); ok && v.ConsensusVersion() > 0 {
+		return v.ConsensusVersion()
+	}
+	return DefaultConsensusVersion
+}
+
 // ValidateModule performs validation on a module
 func ValidateModule(m Module) error {
 	if m == nil {
@@ -128,6 +152,10 @@ type baseModule struct {
 	endBlock     EndBlocker
 	initGenesis  InitGenesis
 	exportGenesis ExportGenesis
+	consensusVersion uint64
+	invariants       map[string]Invariant
+	queryServices    map[string]query.Handler
+	authenticators   map[string]Authenticator
 }
 
 // Name returns the module name
@@ -138,6 +166,68 @@ func (m *baseModule) Name() string {
 	return m.name
 }
 
+// ConsensusVersion returns the module's consensus version
+func (m *baseModule) ConsensusVersion() uint64 {
+	if m == nil || m.consensusVersion == 0 {
+		return DefaultConsensusVersion
+	}
+	return m.consensusVersion
+}
+
+// RegisterInvariants registers the module's invariants in name order
+func (m *baseModule) RegisterInvariants(registry *InvariantRegistry) error {
+	if m == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(m.invariants))
+	for name := range m.invariants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := registry.Register(m.name, name, m.invariants[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterQueryServices registers the module's query services in path order
+func (m *baseModule) RegisterQueryServices(server *query.Server) error {
+	if m == nil {
+		return nil
+	}
+
+	paths := make([]string, 0, len(m.queryServices))
+	for path := range m.queryServices {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if err := server.RegisterHandler(path, m.queryServices[path]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterAuthenticators returns the module's authenticators
+func (m *baseModule) RegisterAuthenticators() map[string]Authenticator {
+	if m == nil || m.authenticators == nil {
+		return nil
+	}
+
+	// Return defensive copy
+	authenticators := make(map[string]Authenticator, len(m.authenticators))
+	for k, v := range m.authenticators {
+		authenticators[k] = v
+	}
+	return authenticators
+}
+
 // Dependencies returns the module dependencies
 func (m *baseModule) Dependencies() []string {
 	if m == nil || m.dependencies == nil {
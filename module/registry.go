@@ -95,19 +95,33 @@ func (r *Registry) Build() error {
 // topologicalSort performs topological sort using Kahn's algorithm
 // Returns the modules in dependency order (dependencies before dependents)
 func (r *Registry) topologicalSort() ([]string, error) {
+	deps := make(map[string][]string, len(r.modules))
+	for name, module := range r.modules {
+		deps[name] = module.Dependencies()
+	}
+	return sortByDependencies(deps)
+}
+
+// sortByDependencies orders the keys of deps so every name follows its
+// dependencies, using Kahn's algorithm. Ties are broken by name so the result
+// is deterministic.
+//
+// PRECONDITION: every dependency is itself a key of deps.
+// Complexity: O(V log V + E log E) for V names and E dependency edges.
+func sortByDependencies(deps map[string][]string) ([]string, error) {
 	// Build adjacency list and in-degree map
 	inDegree := make(map[string]int)
 	adjList := make(map[string][]string)
 
 	// Initialize all modules with in-degree 0
-	for name := range r.modules {
+	for name := range deps {
 		inDegree[name] = 0
 		adjList[name] = make([]string, 0)
 	}
 
 	// Build the graph
-	for name, module := range r.modules {
-		for _, dep := range module.Dependencies() {
+	for name, nameDeps := range deps {
+		for _, dep := range nameDeps {
 			// dep -> name (name depends on dep)
 			adjList[dep] = append(adjList[dep], name)
 			inDegree[name]++
@@ -126,7 +140,7 @@ func (r *Registry) topologicalSort() ([]string, error) {
 	sort.Strings(queue)
 
 	// Process nodes
-	result := make([]string, 0, len(r.modules))
+	result := make([]string, 0, len(deps))
 
 	for len(queue) > 0 {
 		// Pop from queue
@@ -152,14 +166,14 @@ func (r *Registry) topologicalSort() ([]string, error) {
 	}
 
 	// Check for cycles
-	if len(result) != len(r.modules) {
+	if len(result) != len(deps) {
 		// Find nodes not in result (part of cycle)
 		missing := make([]string, 0)
 		resultSet := make(map[string]bool)
 		for _, name := range result {
 			resultSet[name] = true
 		}
-		for name := range r.modules {
+		for name := range deps {
 			if !resultSet[name] {
 				missing = append(missing, name)
 			}
@@ -11,6 +11,7 @@ const (
 	TypeMsgCreateAccount    = "/punnet.auth.v1.MsgCreateAccount"
 	TypeMsgUpdateAuthority  = "/punnet.auth.v1.MsgUpdateAuthority"
 	TypeMsgDeleteAccount    = "/punnet.auth.v1.MsgDeleteAccount"
+	TypeMsgSetAuthenticator = "/punnet.auth.v1.MsgSetAuthenticator"
 )
 
 // MsgCreateAccount creates a new account
@@ -132,3 +133,45 @@ func (m *MsgDeleteAccount) GetSigners() []types.AccountName {
 	// The account being deleted must sign
 	return []types.AccountName{m.Name}
 }
+
+// MsgSetAuthenticator selects the authenticator that authorizes an account's
+// transactions instead of its authority (see types.Account.Authenticator)
+type MsgSetAuthenticator struct {
+	// Name is the account to update
+	Name types.AccountName `json:"name"`
+
+	// Authenticator is the route of a registered authenticator (see
+	// runtime.AuthenticatorRoute); empty restores the authority check
+	Authenticator string `json:"authenticator"`
+}
+
+// Type returns the message type
+func (m *MsgSetAuthenticator) Type() string {
+	return TypeMsgSetAuthenticator
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgSetAuthenticator) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Name.IsValid() {
+		return fmt.Errorf("%w: invalid account name %s", types.ErrInvalidAccount, m.Name)
+	}
+
+	if m.Authenticator != "" && !types.IsValidAuthenticatorName(m.Authenticator) {
+		return fmt.Errorf("%w: invalid authenticator name %q", types.ErrInvalidAccount, m.Authenticator)
+	}
+
+	return nil
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgSetAuthenticator) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	// The account being updated must sign
+	return []types.AccountName{m.Name}
+}
@@ -283,3 +283,58 @@ func TestMsgDeleteAccount_GetSigners_Nil(t *testing.T) {
 		t.Errorf("GetSigners() on nil message = %v, want nil", signers)
 	}
 }
+
+func TestMsgSetAuthenticator_ValidateBasic(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     *MsgSetAuthenticator
+		wantErr bool
+	}{
+		{
+			name:    "valid message",
+			msg:     &MsgSetAuthenticator{Name: "alice", Authenticator: "twofactor/totp"},
+			wantErr: false,
+		},
+		{
+			name:    "clear authenticator",
+			msg:     &MsgSetAuthenticator{Name: "alice"},
+			wantErr: false,
+		},
+		{
+			name:    "nil message",
+			msg:     nil,
+			wantErr: true,
+		},
+		{
+			name:    "invalid account name",
+			msg:     &MsgSetAuthenticator{Name: "ALICE", Authenticator: "twofactor/totp"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid authenticator name",
+			msg:     &MsgSetAuthenticator{Name: "alice", Authenticator: "Two Factor"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.msg.ValidateBasic()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateBasic() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMsgSetAuthenticator_GetSigners(t *testing.T) {
+	msg := &MsgSetAuthenticator{Name: "alice"}
+	if got := msg.Type(); got != TypeMsgSetAuthenticator {
+		t.Errorf("Type() = %v, want %v", got, TypeMsgSetAuthenticator)
+	}
+
+	signers := msg.GetSigners()
+	if len(signers) != 1 || signers[0] != "alice" {
+		t.Errorf("GetSigners() = %v, want [alice]", signers)
+	}
+}
@@ -2,12 +2,16 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/blockberries/punnet-sdk/capability"
 	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/events"
 	"github.com/blockberries/punnet-sdk/module"
 	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
 	"github.com/blockberries/punnet-sdk/types"
 )
 
@@ -45,11 +49,28 @@ func CreateModule(accountCap capability.AccountCapability) (module.Module, error
 		WithMsgHandler(TypeMsgCreateAccount, authMod.handleCreateAccount).
 		WithMsgHandler(TypeMsgUpdateAuthority, authMod.handleUpdateAuthority).
 		WithMsgHandler(TypeMsgDeleteAccount, authMod.handleDeleteAccount).
+		WithMsgHandler(TypeMsgSetAuthenticator, authMod.handleSetAuthenticator).
 		WithQueryHandler("/account", authMod.handleQueryAccount).
 		WithQueryHandler("/nonce", authMod.handleQueryNonce).
+		WithQueryHandler("/delegation_graph", authMod.handleQueryDelegationGraph).
 		Build()
 }
 
+// Spec returns the auth module's ModuleSpec for a module.ModuleManager
+func Spec() module.ModuleSpec {
+	return module.ModuleSpec{
+		Name:         ModuleName,
+		Capabilities: []module.CapabilityKind{module.CapabilityAccount},
+		Create: func(caps module.Capabilities) (module.Module, error) {
+			accountCap, err := caps.Account()
+			if err != nil {
+				return nil, err
+			}
+			return CreateModule(accountCap)
+		},
+	}
+}
+
 // handleCreateAccount handles MsgCreateAccount
 func (m *AuthModule) handleCreateAccount(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
 	if m == nil || m.accountCap == nil {
@@ -103,7 +124,7 @@ func (m *AuthModule) handleCreateAccount(ctx *runtime.Context, msg types.Message
 			StoreKey: []byte(createMsg.Name),
 			Value:    account,
 		},
-		effects.NewEventEffect("account.created", map[string][]byte{
+		effects.NewEventEffect(events.TypeAccountCreated, map[string][]byte{
 			"account": []byte(createMsg.Name),
 			"height":  []byte(fmt.Sprintf("%d", ctx.BlockHeight())),
 		}),
@@ -158,6 +179,62 @@ func (m *AuthModule) handleUpdateAuthority(ctx *runtime.Context, msg types.Messa
 	}, nil
 }
 
+// handleSetAuthenticator handles MsgSetAuthenticator
+//
+// SECURITY: Only registered authenticators can be selected, so an account
+// cannot lock itself out by naming one that does not exist. The transaction
+// selecting an authenticator is itself authorized by the account's current
+// scheme.
+func (m *AuthModule) handleSetAuthenticator(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil || m.accountCap == nil {
+		return nil, fmt.Errorf("module or capability is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	setMsg, ok := msg.(*MsgSetAuthenticator)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgSetAuthenticator")
+	}
+
+	// Verify the account being updated is the transaction signer
+	if setMsg.Name != ctx.Account() {
+		return nil, fmt.Errorf("account name must match transaction account")
+	}
+
+	if setMsg.Authenticator != "" && !ctx.HasAuthenticator(setMsg.Authenticator) {
+		return nil, fmt.Errorf("%w: %s", runtime.ErrAuthenticatorNotFound, setMsg.Authenticator)
+	}
+
+	// Get the existing account
+	account, err := m.accountCap.GetAccount(ctx.Context(), setMsg.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	account.Authenticator = setMsg.Authenticator
+	account.UpdatedAt = ctx.BlockTime()
+
+	// Validate the updated account
+	if err := account.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid account: %w", err)
+	}
+
+	return []effects.Effect{
+		effects.WriteEffect[*types.Account]{
+			Store:    "account",
+			StoreKey: []byte(setMsg.Name),
+			Value:    account,
+		},
+		effects.NewEventEffect("account.authenticator_set", map[string][]byte{
+			"account":       []byte(setMsg.Name),
+			"authenticator": []byte(setMsg.Authenticator),
+			"height":        []byte(fmt.Sprintf("%d", ctx.BlockHeight())),
+		}),
+	}, nil
+}
+
 // handleDeleteAccount handles MsgDeleteAccount
 func (m *AuthModule) handleDeleteAccount(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
 	if m == nil || m.accountCap == nil {
@@ -265,3 +342,48 @@ func (m *AuthModule) handleQueryNonce(ctx context.Context, path string, data []b
 	// TODO: Proper serialization
 	return []byte(fmt.Sprintf("%d", nonce)), nil
 }
+
+// QueryDelegationGraphResponse is the response for delegation graph query
+type QueryDelegationGraphResponse struct {
+	Graph *types.DelegationGraph `json:"graph"`
+
+	// Satisfiable reports whether the account's threshold can be met
+	Satisfiable bool `json:"satisfiable"`
+}
+
+// handleQueryDelegationGraph returns the resolved delegation graph of an
+// account as JSON, for wallets displaying who can sign for it
+func (m *AuthModule) handleQueryDelegationGraph(ctx context.Context, path string, data []byte) ([]byte, error) {
+	if m == nil || m.accountCap == nil {
+		return nil, fmt.Errorf("module or capability is nil")
+	}
+
+	// For now, treat data as account name
+	name := types.AccountName(data)
+	if !name.IsValid() {
+		return nil, fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
+	}
+
+	graph, err := types.BuildDelegationGraph(name, &accountGetter{ctx: ctx, accountCap: m.accountCap})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build delegation graph: %w", err)
+	}
+
+	return json.Marshal(QueryDelegationGraphResponse{Graph: graph, Satisfiable: graph.Satisfiable()})
+}
+
+// accountGetter adapts the account capability to types.AccountGetter
+type accountGetter struct {
+	ctx        context.Context
+	accountCap capability.AccountCapability
+}
+
+// GetAccount retrieves an account by name, reporting missing accounts as
+// types.ErrNotFound
+func (g *accountGetter) GetAccount(name types.AccountName) (*types.Account, error) {
+	account, err := g.accountCap.GetAccount(g.ctx, name)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, fmt.Errorf("%w: account %s", types.ErrNotFound, name)
+	}
+	return account, err
+}
@@ -2,6 +2,8 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
@@ -284,6 +286,60 @@ func TestAuthModule_HandleUpdateAuthority_NonExistent(t *testing.T) {
 	}
 }
 
+func TestAuthModule_HandleSetAuthenticator(t *testing.T) {
+	authMod, accountCap := setupTestAuthModule(t)
+
+	// Create an account first
+	_, err := accountCap.CreateAccount(context.Background(), "alice", []byte("test-pubkey"))
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		msg     *MsgSetAuthenticator
+		account types.AccountName
+		wantErr bool
+		errIs   error
+	}{
+		{
+			name:    "clear authenticator",
+			msg:     &MsgSetAuthenticator{Name: "alice"},
+			account: "alice",
+		},
+		{
+			name:    "unregistered authenticator",
+			msg:     &MsgSetAuthenticator{Name: "alice", Authenticator: "twofactor/totp"},
+			account: "alice",
+			wantErr: true,
+			errIs:   runtime.ErrAuthenticatorNotFound,
+		},
+		{
+			name:    "account name mismatch",
+			msg:     &MsgSetAuthenticator{Name: "alice"},
+			account: "bob",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := setupTestContext(t, tt.account)
+			effs, err := authMod.handleSetAuthenticator(ctx, tt.msg)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("handleSetAuthenticator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.errIs != nil && !errors.Is(err, tt.errIs) {
+				t.Fatalf("handleSetAuthenticator() error = %v, want %v", err, tt.errIs)
+			}
+			if !tt.wantErr && len(effs) == 0 {
+				t.Fatal("handleSetAuthenticator() returned no effects")
+			}
+		})
+	}
+}
+
 func TestAuthModule_HandleDeleteAccount(t *testing.T) {
 	authMod, accountCap := setupTestAuthModule(t)
 
@@ -419,3 +475,45 @@ func TestAuthModule_HandleQueryNonce(t *testing.T) {
 		}
 	})
 }
+
+func TestAuthModule_HandleQueryDelegationGraph(t *testing.T) {
+	authMod, accountCap := setupTestAuthModule(t)
+	ctx := context.Background()
+
+	if _, err := accountCap.CreateAccount(ctx, "bob", []byte("bob-pubkey")); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	alice, err := accountCap.CreateAccount(ctx, "alice", []byte("alice-pubkey"))
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	alice.Authority.Threshold = 2
+	alice.Authority.AccountWeights = map[types.AccountName]uint64{"bob": 1, "ghost": 1}
+	if err := accountCap.UpdateAccount(ctx, alice); err != nil {
+		t.Fatalf("failed to update account: %v", err)
+	}
+
+	result, err := authMod.handleQueryDelegationGraph(ctx, "/delegation_graph", []byte("alice"))
+	if err != nil {
+		t.Fatalf("handleQueryDelegationGraph() error = %v", err)
+	}
+	var resp QueryDelegationGraphResponse
+	if err := json.Unmarshal(result, &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !resp.Satisfiable || resp.Graph.Depth != 1 || resp.Graph.Nodes != 2 {
+		t.Fatalf("unexpected graph: satisfiable=%v depth=%d nodes=%d",
+			resp.Satisfiable, resp.Graph.Depth, resp.Graph.Nodes)
+	}
+	delegates := resp.Graph.Root.Delegates
+	if len(delegates) != 2 || delegates[0].Status != types.DelegationResolved || delegates[1].Status != types.DelegationMissing {
+		t.Fatalf("unexpected delegates: %+v", delegates)
+	}
+
+	if _, err := authMod.handleQueryDelegationGraph(ctx, "/delegation_graph", []byte("ALICE")); !errors.Is(err, types.ErrInvalidAccount) {
+		t.Fatalf("expected ErrInvalidAccount, got %v", err)
+	}
+	if _, err := authMod.handleQueryDelegationGraph(ctx, "/delegation_graph", []byte("nobody")); !errors.Is(err, types.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	authv1 "github.com/blockberries/punnet-sdk/api/punnet/auth/v1"
+	"github.com/blockberries/punnet-sdk/protocodec"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// RegisterProtoMessages registers the protobuf converters of the auth
+// messages
+func RegisterProtoMessages(r *protocodec.Registry) error {
+	if err := protocodec.RegisterMessage(r, msgCreateAccountToProto, msgCreateAccountFromProto); err != nil {
+		return err
+	}
+	if err := protocodec.RegisterMessage(r, msgUpdateAuthorityToProto, msgUpdateAuthorityFromProto); err != nil {
+		return err
+	}
+	if err := protocodec.RegisterMessage(r, msgDeleteAccountToProto, msgDeleteAccountFromProto); err != nil {
+		return err
+	}
+	return protocodec.RegisterMessage(r, msgSetAuthenticatorToProto, msgSetAuthenticatorFromProto)
+}
+
+func msgCreateAccountToProto(m *MsgCreateAccount) (*authv1.MsgCreateAccount, error) {
+	return &authv1.MsgCreateAccount{
+		Name:      string(m.Name),
+		PubKey:    m.PubKey,
+		Authority: protocodec.AuthorityToProto(m.Authority),
+	}, nil
+}
+
+func msgCreateAccountFromProto(p *authv1.MsgCreateAccount) (*MsgCreateAccount, error) {
+	return &MsgCreateAccount{
+		Name:      types.AccountName(p.GetName()),
+		PubKey:    p.GetPubKey(),
+		Authority: protocodec.AuthorityFromProto(p.GetAuthority()),
+	}, nil
+}
+
+func msgUpdateAuthorityToProto(m *MsgUpdateAuthority) (*authv1.MsgUpdateAuthority, error) {
+	return &authv1.MsgUpdateAuthority{
+		Name:         string(m.Name),
+		NewAuthority: protocodec.AuthorityToProto(m.NewAuthority),
+	}, nil
+}
+
+func msgUpdateAuthorityFromProto(p *authv1.MsgUpdateAuthority) (*MsgUpdateAuthority, error) {
+	return &MsgUpdateAuthority{
+		Name:         types.AccountName(p.GetName()),
+		NewAuthority: protocodec.AuthorityFromProto(p.GetNewAuthority()),
+	}, nil
+}
+
+func msgDeleteAccountToProto(m *MsgDeleteAccount) (*authv1.MsgDeleteAccount, error) {
+	return &authv1.MsgDeleteAccount{Name: string(m.Name)}, nil
+}
+
+func msgDeleteAccountFromProto(p *authv1.MsgDeleteAccount) (*MsgDeleteAccount, error) {
+	return &MsgDeleteAccount{Name: types.AccountName(p.GetName())}, nil
+}
+
+func msgSetAuthenticatorToProto(m *MsgSetAuthenticator) (*authv1.MsgSetAuthenticator, error) {
+	return &authv1.MsgSetAuthenticator{Name: string(m.Name), Authenticator: m.Authenticator}, nil
+}
+
+func msgSetAuthenticatorFromProto(p *authv1.MsgSetAuthenticator) (*MsgSetAuthenticator, error) {
+	return &MsgSetAuthenticator{Name: types.AccountName(p.GetName()), Authenticator: p.GetAuthenticator()}, nil
+}
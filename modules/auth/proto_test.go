@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blockberries/punnet-sdk/protocodec"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+func TestRegisterProtoMessages(t *testing.T) {
+	r := protocodec.NewRegistry()
+	if err := RegisterProtoMessages(r); err != nil {
+		t.Fatalf("RegisterProtoMessages: %v", err)
+	}
+
+	want := []string{TypeMsgCreateAccount, TypeMsgDeleteAccount, TypeMsgSetAuthenticator, TypeMsgUpdateAuthority}
+	if got := r.MessageTypes(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("MessageTypes() = %v, want %v", got, want)
+	}
+
+	authority := types.Authority{
+		Threshold:      2,
+		KeyWeights:     map[string]uint64{"key": 1},
+		AccountWeights: map[types.AccountName]uint64{"bob": 1},
+	}
+	msgs := []types.Message{
+		&MsgCreateAccount{Name: "alice", PubKey: []byte{1, 2, 3}, Authority: authority},
+		&MsgUpdateAuthority{Name: "alice", NewAuthority: authority},
+		&MsgDeleteAccount{Name: "alice"},
+		&MsgSetAuthenticator{Name: "alice", Authenticator: "twofactor/totp"},
+	}
+	for _, msg := range msgs {
+		t.Run(msg.Type(), func(t *testing.T) {
+			a, err := r.PackAny(msg)
+			if err != nil {
+				t.Fatalf("PackAny: %v", err)
+			}
+			got, err := r.UnpackAny(a)
+			if err != nil {
+				t.Fatalf("UnpackAny: %v", err)
+			}
+			if !reflect.DeepEqual(got, msg) {
+				t.Fatalf("round trip = %+v, want %+v", got, msg)
+			}
+		})
+	}
+}
@@ -21,11 +21,11 @@ type TransactionValidator struct {
 
 // NewTransactionValidator creates a new validator bound to the specified chain.
 //
-// PRECONDITION: chainID is non-empty
+// PRECONDITION: chainID is valid (see types.ValidateChainID)
 // POSTCONDITION: Returned validator will reject all transactions with different chain IDs
 func NewTransactionValidator(chainID string) (*TransactionValidator, error) {
-	if chainID == "" {
-		return nil, fmt.Errorf("chainID cannot be empty")
+	if err := types.ValidateChainID(chainID); err != nil {
+		return nil, err
 	}
 	return &TransactionValidator{chainID: chainID}, nil
 }
@@ -49,7 +49,7 @@ func ValidateSignDocVersion(version string) error {
 // expected account sequence for replay protection.
 //
 // PRECONDITION: tx is not nil
-// PRECONDITION: chainID is non-empty
+// PRECONDITION: chainID is valid (see types.ValidateChainID)
 // PRECONDITION: expectedSequence is the current nonce for the signing account
 //
 // POSTCONDITION: Returns nil if transaction nonce matches expectedSequence
@@ -69,8 +69,8 @@ func ValidateReplayProtection(tx *types.Transaction, chainID string, expectedSeq
 		return fmt.Errorf("%w: transaction is nil", types.ErrInvalidTransaction)
 	}
 
-	if chainID == "" {
-		return fmt.Errorf("%w: chainID cannot be empty", types.ErrInvalidTransaction)
+	if err := types.ValidateChainID(chainID); err != nil {
+		return fmt.Errorf("%w: %w", types.ErrInvalidTransaction, err)
 	}
 
 	// Verify account sequence
@@ -252,6 +252,22 @@ func (v *TransactionValidator) ValidateForBlockProposal(
 	return nil
 }
 
+// ValidateForBlockProposalAtHeight is ValidateForBlockProposal for a block at
+// height. Unlike ValidateForBlockProposal, it accepts session-key
+// authorizations valid at that height.
+func (v *TransactionValidator) ValidateForBlockProposalAtHeight(
+	tx *types.Transaction,
+	account *types.Account,
+	getter types.AccountGetter,
+	height uint64,
+) error {
+	if err := v.ValidateTransaction(tx, account); err != nil {
+		return err
+	}
+
+	return tx.VerifyAuthorizationAtHeight(v.chainID, account, getter, height)
+}
+
 // ChainID returns the chain ID this validator is bound to.
 func (v *TransactionValidator) ChainID() string {
 	return v.chainID
@@ -28,8 +28,13 @@ func TestValidateSignDocVersion(t *testing.T) {
 			errContains: "unsupported",
 		},
 		{
-			name:        "invalid version 2",
-			version:     "2",
+			name:    "valid version 2",
+			version: "2",
+			wantErr: false,
+		},
+		{
+			name:        "invalid version 3",
+			version:     "3",
 			wantErr:     true,
 			errContains: "unsupported",
 		},
@@ -185,7 +190,10 @@ func TestValidateSignDoc(t *testing.T) {
 		{
 			name: "valid SignDoc",
 			signDoc: func() *types.SignDoc {
-				sd := types.NewSignDoc("test-chain", 1, "alice", 1, "")
+				sd, err := types.NewSignDoc("test-chain", 1, "alice", 1, "")
+				if err != nil {
+					t.Fatalf("NewSignDoc failed: %v", err)
+				}
 				sd.AddMessage("/test.v1.Msg", []byte(`{"foo":"bar"}`))
 				return sd
 			}(),
@@ -199,7 +207,10 @@ func TestValidateSignDoc(t *testing.T) {
 		{
 			name: "invalid version",
 			signDoc: func() *types.SignDoc {
-				sd := types.NewSignDoc("test-chain", 1, "alice", 1, "")
+				sd, err := types.NewSignDoc("test-chain", 1, "alice", 1, "")
+				if err != nil {
+					t.Fatalf("NewSignDoc failed: %v", err)
+				}
 				sd.Version = "99"
 				sd.AddMessage("/test.v1.Msg", []byte(`{"foo":"bar"}`))
 				return sd
@@ -209,7 +220,11 @@ func TestValidateSignDoc(t *testing.T) {
 		{
 			name: "empty chain ID",
 			signDoc: func() *types.SignDoc {
-				sd := types.NewSignDoc("", 1, "alice", 1, "")
+				sd, err := types.NewSignDoc("test-chain", 1, "alice", 1, "")
+				if err != nil {
+					t.Fatalf("NewSignDoc failed: %v", err)
+				}
+				sd.ChainID = ""
 				sd.AddMessage("/test.v1.Msg", []byte(`{"foo":"bar"}`))
 				return sd
 			}(),
@@ -218,7 +233,10 @@ func TestValidateSignDoc(t *testing.T) {
 		{
 			name: "empty account",
 			signDoc: func() *types.SignDoc {
-				sd := types.NewSignDoc("test-chain", 1, "", 1, "")
+				sd, err := types.NewSignDoc("test-chain", 1, "", 1, "")
+				if err != nil {
+					t.Fatalf("NewSignDoc failed: %v", err)
+				}
 				sd.AddMessage("/test.v1.Msg", []byte(`{"foo":"bar"}`))
 				return sd
 			}(),
@@ -227,7 +245,11 @@ func TestValidateSignDoc(t *testing.T) {
 		{
 			name: "no messages",
 			signDoc: func() *types.SignDoc {
-				return types.NewSignDoc("test-chain", 1, "alice", 1, "")
+				sd, err := types.NewSignDoc("test-chain", 1, "alice", 1, "")
+				if err != nil {
+					t.Fatalf("NewSignDoc failed: %v", err)
+				}
+				return sd
 			}(),
 			wantErr: true,
 		},
@@ -0,0 +1,88 @@
+package bank
+
+import (
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/runtime"
+)
+
+// SendHooks lets a chain enforce its own transfer policy (compliance
+// checks, per-account limits, ...) without forking the bank module.
+//
+// Hooks see every MsgSend and MsgMultiSend as inputs and outputs; a MsgSend
+// is one input and one output of the same coins.
+type SendHooks interface {
+	// BeforeSend is called after the Params checks and before balances are
+	// checked. Returning an error rejects the message.
+	BeforeSend(ctx *runtime.Context, inputs []Input, outputs []Output) error
+
+	// AfterSend is called once the transfer effects are built. Its effects
+	// are applied after the transfers, with the message's effects; returning
+	// an error fails the message.
+	//
+	// Transfers emitted as effects.TransferEffect bypass the Params checks
+	// and the hooks unless their module checks them through a
+	// SendRestriction; a MsgSend dispatched through a runtime.MsgDispatcher
+	// is checked and runs the hooks again.
+	AfterSend(ctx *runtime.Context, inputs []Input, outputs []Output) ([]effects.Effect, error)
+}
+
+// SendRestriction holds transfers that other modules emit as
+// effects.TransferEffect to the bank's transfer policy, as if they were
+// MsgMultiSends. *BankModule implements it.
+type SendRestriction interface {
+	// CheckSend applies the Params transfer policy and the BeforeSend hook.
+	// Returning an error rejects the transfer.
+	CheckSend(ctx *runtime.Context, inputs []Input, outputs []Output) error
+
+	// AfterSend runs the AfterSend hook, if any. Its effects must be
+	// applied after the transfers.
+	AfterSend(ctx *runtime.Context, inputs []Input, outputs []Output) ([]effects.Effect, error)
+}
+
+var _ SendRestriction = (*BankModule)(nil)
+
+// CheckSend applies the Params transfer policy and the BeforeSend hook
+func (m *BankModule) CheckSend(ctx *runtime.Context, inputs []Input, outputs []Output) error {
+	params, err := m.Params()
+	if err != nil {
+		return fmt.Errorf("failed to get params: %w", err)
+	}
+
+	for _, input := range inputs {
+		if err := params.CheckAccount(input.Address); err != nil {
+			return err
+		}
+		for _, coin := range input.Coins {
+			if !params.IsSendEnabled(coin.Denom) {
+				return fmt.Errorf("%w: %s", ErrSendDisabled, coin.Denom)
+			}
+		}
+	}
+	for _, output := range outputs {
+		if err := params.CheckAccount(output.Address); err != nil {
+			return err
+		}
+	}
+
+	if m.hooks == nil {
+		return nil
+	}
+	if err := m.hooks.BeforeSend(ctx, inputs, outputs); err != nil {
+		return fmt.Errorf("send rejected: %w", err)
+	}
+	return nil
+}
+
+// AfterSend runs the AfterSend hook, if any
+func (m *BankModule) AfterSend(ctx *runtime.Context, inputs []Input, outputs []Output) ([]effects.Effect, error) {
+	if m.hooks == nil {
+		return nil, nil
+	}
+	hookEffects, err := m.hooks.AfterSend(ctx, inputs, outputs)
+	if err != nil {
+		return nil, fmt.Errorf("after send hook failed: %w", err)
+	}
+	return hookEffects, nil
+}
@@ -0,0 +1,81 @@
+package bank
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// recordingHooks records hook calls and optionally rejects sends
+type recordingHooks struct {
+	reject error
+	before int
+	after  int
+}
+
+func (h *recordingHooks) BeforeSend(ctx *runtime.Context, inputs []Input, outputs []Output) error {
+	h.before++
+	return h.reject
+}
+
+func (h *recordingHooks) AfterSend(ctx *runtime.Context, inputs []Input, outputs []Output) ([]effects.Effect, error) {
+	h.after++
+	return []effects.Effect{
+		effects.NewEventEffect("compliance.recorded", map[string][]byte{"from": []byte(inputs[0].Address)}),
+	}, nil
+}
+
+func TestBankModule_SendHooks(t *testing.T) {
+	hooks := &recordingHooks{}
+	bankMod, _ := setupPolicyModule(t, DefaultParams(), hooks)
+	ctx := setupTestContext(t, "alice")
+
+	effs, err := bankMod.handleSend(ctx, &MsgSend{From: "alice", To: "bob", Amount: types.NewCoin("stake", 10)})
+	if err != nil {
+		t.Fatalf("handleSend() error = %v", err)
+	}
+	if hooks.before != 1 || hooks.after != 1 {
+		t.Fatalf("hooks called before=%d after=%d, want 1 and 1", hooks.before, hooks.after)
+	}
+	// Transfer, send event, then the hook's effect
+	if len(effs) != 3 {
+		t.Fatalf("expected 3 effects, got %d", len(effs))
+	}
+	if _, ok := effs[0].(effects.TransferEffect); !ok {
+		t.Fatalf("first effect = %T, want TransferEffect", effs[0])
+	}
+
+	_, err = bankMod.handleMultiSend(ctx, &MsgMultiSend{
+		Inputs:  []Input{{Address: "alice", Coins: types.NewCoins(types.NewCoin("stake", 10))}},
+		Outputs: []Output{{Address: "bob", Coins: types.NewCoins(types.NewCoin("stake", 10))}},
+	})
+	if err != nil {
+		t.Fatalf("handleMultiSend() error = %v", err)
+	}
+	if hooks.before != 2 || hooks.after != 2 {
+		t.Fatalf("hooks called before=%d after=%d, want 2 and 2", hooks.before, hooks.after)
+	}
+
+	errRejected := errors.New("sanctioned")
+	hooks.reject = errRejected
+	_, err = bankMod.handleSend(ctx, &MsgSend{From: "alice", To: "bob", Amount: types.NewCoin("stake", 10)})
+	if !errors.Is(err, errRejected) {
+		t.Fatalf("handleSend() error = %v, want %v", err, errRejected)
+	}
+	if hooks.after != 2 {
+		t.Fatalf("AfterSend called for a rejected send")
+	}
+
+	// Policy checks run before the hooks
+	bankMod, _ = setupPolicyModule(t, Params{DefaultSendEnabled: true, Blocklist: []types.AccountName{"bob"}}, hooks)
+	hooks.before = 0
+	if _, err := bankMod.handleSend(ctx, &MsgSend{From: "alice", To: "bob", Amount: types.NewCoin("stake", 10)}); !errors.Is(err, ErrAccountBlocked) {
+		t.Fatalf("handleSend() error = %v, want ErrAccountBlocked", err)
+	}
+	if hooks.before != 0 {
+		t.Fatal("BeforeSend called for a blocked send")
+	}
+}
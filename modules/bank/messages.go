@@ -8,8 +8,10 @@ import (
 
 // Message type identifiers
 const (
-	TypeMsgSend      = "/punnet.bank.v1.MsgSend"
-	TypeMsgMultiSend = "/punnet.bank.v1.MsgMultiSend"
+	TypeMsgSend             = "/punnet.bank.v1.MsgSend"
+	TypeMsgMultiSend        = "/punnet.bank.v1.MsgMultiSend"
+	TypeMsgSetDenomMetadata = "/punnet.bank.v1.MsgSetDenomMetadata"
+	TypeMsgUpdateParams     = "/punnet.bank.v1.MsgUpdateParams"
 )
 
 // MsgSend transfers coins from one account to another
@@ -126,9 +128,14 @@ func (o *Output) ValidateBasic() error {
 	return nil
 }
 
-// MsgMultiSend transfers coins from multiple accounts to multiple accounts
+// MsgMultiSend transfers coins to multiple accounts in one message, e.g. a
+// payout batch. The outputs are paid atomically: all of them or none.
+//
+// INVARIANT: The inputs and outputs total the same coins (see ValidateBasic).
 type MsgMultiSend struct {
-	// Inputs are the sender accounts and amounts
+	// Inputs are the amounts debited from the senders. Every input's
+	// account must sign the transaction, as its account or a co-signer; an
+	// input may be repeated.
 	Inputs []Input `json:"inputs"`
 
 	// Outputs are the recipient accounts and amounts
@@ -231,3 +238,73 @@ func (m *MsgMultiSend) GetSigners() []types.AccountName {
 
 	return signers
 }
+
+// MsgSetDenomMetadata sets (or replaces) the display metadata of a denomination
+type MsgSetDenomMetadata struct {
+	// Authority is the bank authority (see WithAuthority)
+	Authority types.AccountName `json:"authority"`
+
+	// Metadata is the new metadata of Metadata.Base
+	Metadata types.DenomMetadata `json:"metadata"`
+}
+
+// Type returns the message type
+func (m *MsgSetDenomMetadata) Type() string {
+	return TypeMsgSetDenomMetadata
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgSetDenomMetadata) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Authority.IsValid() {
+		return fmt.Errorf("%w: invalid authority account %s", types.ErrInvalidAccount, m.Authority)
+	}
+
+	return m.Metadata.ValidateBasic()
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgSetDenomMetadata) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Authority}
+}
+
+// MsgUpdateParams replaces the bank params
+type MsgUpdateParams struct {
+	// Authority is the bank authority (see WithAuthority)
+	Authority types.AccountName `json:"authority"`
+
+	// Params are the new params
+	Params Params `json:"params"`
+}
+
+// Type returns the message type
+func (m *MsgUpdateParams) Type() string {
+	return TypeMsgUpdateParams
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgUpdateParams) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Authority.IsValid() {
+		return fmt.Errorf("%w: invalid authority account %s", types.ErrInvalidAccount, m.Authority)
+	}
+
+	return m.Params.ValidateBasic()
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgUpdateParams) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Authority}
+}
@@ -0,0 +1,177 @@
+package bank
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/query"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// DenomMetadataNamespace is the state namespace holding denom metadata,
+// keyed by base denomination ("module/bank.metadata/<base>").
+//
+// RATIONALE: Every key of the bank namespace is a balance (see
+// capability.BalanceCapability.IterateBalances), so metadata lives beside
+// it rather than in it.
+const DenomMetadataNamespace = ModuleName + ".metadata"
+
+// Denom metadata query service paths
+const (
+	// QueryServiceDenomMetadata answers a JSON QueryDenomMetadataRequest with
+	// a JSON QueryDenomMetadataResponse
+	QueryServiceDenomMetadata = "/bank/denom_metadata"
+
+	// QueryServiceAllDenomMetadata answers any request with a JSON
+	// QueryAllDenomMetadataResponse
+	QueryServiceAllDenomMetadata = "/bank/all_denom_metadata"
+)
+
+// EventTypeSetDenomMetadata is emitted when MsgSetDenomMetadata succeeds
+const EventTypeSetDenomMetadata = "bank.set_denom_metadata"
+
+// DenomMetadataStore returns the DenomMetadataNamespace view of the state store s
+func DenomMetadataStore(s store.BackingStore) store.BackingStore {
+	return capability.ModuleStore(s, DenomMetadataNamespace)
+}
+
+// GetDenomMetadata returns the metadata of base from the state store s, if any
+func GetDenomMetadata(s store.BackingStore, base string) (types.DenomMetadata, bool, error) {
+	if s == nil {
+		return types.DenomMetadata{}, false, fmt.Errorf("state store cannot be nil")
+	}
+	if base == "" {
+		return types.DenomMetadata{}, false, fmt.Errorf("denom cannot be empty")
+	}
+
+	data, err := DenomMetadataStore(s).Get([]byte(base))
+	if errors.Is(err, store.ErrNotFound) || (err == nil && data == nil) {
+		return types.DenomMetadata{}, false, nil
+	}
+	if err != nil {
+		return types.DenomMetadata{}, false, fmt.Errorf("failed to read denom metadata: %w", err)
+	}
+
+	var metadata types.DenomMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return types.DenomMetadata{}, false, fmt.Errorf("failed to decode metadata of %s: %w", base, err)
+	}
+	return metadata, true, nil
+}
+
+// AllDenomMetadata returns all denom metadata in the state store s, ordered
+// by base denomination
+func AllDenomMetadata(s store.BackingStore) ([]types.DenomMetadata, error) {
+	if s == nil {
+		return nil, fmt.Errorf("state store cannot be nil")
+	}
+
+	iter, err := DenomMetadataStore(s).Iterator(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate denom metadata: %w", err)
+	}
+	defer iter.Close()
+
+	var all []types.DenomMetadata
+	for ; iter.Valid(); iter.Next() {
+		var metadata types.DenomMetadata
+		if err := json.Unmarshal(iter.Value(), &metadata); err != nil {
+			return nil, fmt.Errorf("failed to decode metadata of %s: %w", iter.Key(), err)
+		}
+		all = append(all, metadata)
+	}
+	return all, nil
+}
+
+// handleSetDenomMetadata handles MsgSetDenomMetadata
+func (m *BankModule) handleSetDenomMetadata(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("module is nil")
+	}
+
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	setMsg, ok := msg.(*MsgSetDenomMetadata)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgSetDenomMetadata")
+	}
+
+	if setMsg.Authority != ctx.Account() {
+		return nil, fmt.Errorf("%w: %s must be transaction account", types.ErrUnauthorized, setMsg.Authority)
+	}
+
+	if err := m.checkAuthority(setMsg.Authority); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(setMsg.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode denom metadata: %w", err)
+	}
+
+	return []effects.Effect{
+		effects.NewStateWriteEffect(DenomMetadataNamespace, []byte(setMsg.Metadata.Base), data),
+		effects.NewEventEffect(EventTypeSetDenomMetadata, map[string][]byte{
+			"base":     []byte(setMsg.Metadata.Base),
+			"display":  []byte(setMsg.Metadata.Display),
+			"exponent": []byte(strconv.FormatUint(uint64(setMsg.Metadata.Exponent), 10)),
+			"height":   []byte(strconv.FormatUint(ctx.BlockHeight(), 10)),
+		}),
+	}, nil
+}
+
+// QueryDenomMetadataRequest is the request for QueryServiceDenomMetadata
+type QueryDenomMetadataRequest struct {
+	Denom string `json:"denom"`
+}
+
+// QueryDenomMetadataResponse is the response for QueryServiceDenomMetadata
+type QueryDenomMetadataResponse struct {
+	Metadata types.DenomMetadata `json:"metadata"`
+}
+
+// QueryAllDenomMetadataResponse is the response for QueryServiceAllDenomMetadata
+type QueryAllDenomMetadataResponse struct {
+	Metadata []types.DenomMetadata `json:"metadata"`
+}
+
+// handleQueryDenomMetadata serves QueryServiceDenomMetadata from the state
+// pinned at the query height. A denom without metadata is ErrNotFound.
+func handleQueryDenomMetadata(ctx *query.Context, data []byte) ([]byte, error) {
+	var req QueryDenomMetadataRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("invalid denom metadata query: %w", err)
+	}
+
+	metadata, ok, err := GetDenomMetadata(ctx.Store(), req.Denom)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: no metadata for denom %s", types.ErrNotFound, req.Denom)
+	}
+
+	return json.Marshal(QueryDenomMetadataResponse{Metadata: metadata})
+}
+
+// handleQueryAllDenomMetadata serves QueryServiceAllDenomMetadata from the
+// state pinned at the query height
+func handleQueryAllDenomMetadata(ctx *query.Context, _ []byte) ([]byte, error) {
+	all, err := AllDenomMetadata(ctx.Store())
+	if err != nil {
+		return nil, err
+	}
+	if all == nil {
+		all = []types.DenomMetadata{}
+	}
+
+	return json.Marshal(QueryAllDenomMetadataResponse{Metadata: all})
+}
@@ -0,0 +1,187 @@
+package bank
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/blockberries/punnet-sdk/query"
+	"github.com/blockberries/punnet-sdk/store"
+	punnettesting "github.com/blockberries/punnet-sdk/testing"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+var testPun = types.DenomMetadata{Base: "upun", Display: "PUN", Exponent: 6, Description: "Punnet token"}
+
+// setupMetadataModule returns a bank module with authority "gov" whose
+// effects are applied through env
+func setupMetadataModule(t *testing.T) (*BankModule, *punnettesting.EffectEnv) {
+	t.Helper()
+
+	env := punnettesting.NewEffectEnv(t)
+	capMgr := env.CapabilityManager()
+	if err := capMgr.RegisterModule(ModuleName); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	balanceCap, err := capMgr.GrantBalanceCapability(ModuleName)
+	if err != nil {
+		t.Fatalf("failed to grant balance capability: %v", err)
+	}
+	env.UseBalances(balanceCap)
+
+	bankMod, err := NewBankModule(balanceCap, WithAuthority("gov"))
+	if err != nil {
+		t.Fatalf("failed to create bank module: %v", err)
+	}
+	return bankMod, env
+}
+
+func TestMsgSetDenomMetadata_ValidateBasic(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     *MsgSetDenomMetadata
+		wantErr bool
+	}{
+		{"valid", &MsgSetDenomMetadata{Authority: "gov", Metadata: testPun}, false},
+		{"nil", nil, true},
+		{"invalid authority", &MsgSetDenomMetadata{Authority: "Gov", Metadata: testPun}, true},
+		{"invalid metadata", &MsgSetDenomMetadata{Authority: "gov", Metadata: types.DenomMetadata{Base: "upun"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.msg.ValidateBasic()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateBasic() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	msg := &MsgSetDenomMetadata{Authority: "gov", Metadata: testPun}
+	if signers := msg.GetSigners(); len(signers) != 1 || signers[0] != "gov" {
+		t.Fatalf("GetSigners() = %v, want [gov]", signers)
+	}
+}
+
+func TestNewBankModule_InvalidAuthority(t *testing.T) {
+	_, balanceCap := setupTestBankModule(t)
+
+	if _, err := NewBankModule(balanceCap, WithAuthority("Not Valid")); !errors.Is(err, types.ErrInvalidAccount) {
+		t.Fatalf("NewBankModule() error = %v, want ErrInvalidAccount", err)
+	}
+}
+
+func TestBankModule_HandleSetDenomMetadata(t *testing.T) {
+	bankMod, env := setupMetadataModule(t)
+
+	tests := []struct {
+		name    string
+		account types.AccountName
+		msg     *MsgSetDenomMetadata
+	}{
+		{"not transaction account", "alice", &MsgSetDenomMetadata{Authority: "gov", Metadata: testPun}},
+		{"not authority", "alice", &MsgSetDenomMetadata{Authority: "alice", Metadata: testPun}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := bankMod.handleSetDenomMetadata(setupTestContext(t, tt.account), tt.msg)
+			if !errors.Is(err, types.ErrUnauthorized) {
+				t.Fatalf("handleSetDenomMetadata() error = %v, want ErrUnauthorized", err)
+			}
+		})
+	}
+
+	ctx := setupTestContext(t, "gov")
+	effs, err := bankMod.handleSetDenomMetadata(ctx, &MsgSetDenomMetadata{Authority: "gov", Metadata: testPun})
+	if err != nil {
+		t.Fatalf("handleSetDenomMetadata() error = %v", err)
+	}
+	if len(effs) != 2 {
+		t.Fatalf("expected 2 effects, got %d", len(effs))
+	}
+	env.Apply(t, ctx, effs)
+
+	got, ok, err := GetDenomMetadata(env.Store(), "upun")
+	if err != nil || !ok {
+		t.Fatalf("GetDenomMetadata() = %v, %v, want metadata", ok, err)
+	}
+	if got != testPun {
+		t.Fatalf("GetDenomMetadata() = %+v, want %+v", got, testPun)
+	}
+
+	// Metadata is not a balance
+	count := 0
+	if err := bankMod.balanceCap.IterateBalances(context.Background(), func(store.Balance) error {
+		count++
+		return nil
+	}); err != nil {
+		t.Fatalf("IterateBalances() error = %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("IterateBalances() saw %d balances, want 0", count)
+	}
+}
+
+func TestBankModule_HandleSetDenomMetadata_NoAuthority(t *testing.T) {
+	bankMod, _ := setupTestBankModule(t)
+
+	_, err := bankMod.handleSetDenomMetadata(setupTestContext(t, "gov"), &MsgSetDenomMetadata{Authority: "gov", Metadata: testPun})
+	if !errors.Is(err, types.ErrUnauthorized) {
+		t.Fatalf("handleSetDenomMetadata() error = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestDenomMetadataQueries(t *testing.T) {
+	bankMod, env := setupMetadataModule(t)
+
+	atom := types.DenomMetadata{Base: "uatom", Display: "ATOM", Exponent: 6}
+	for _, metadata := range []types.DenomMetadata{testPun, atom} {
+		txCtx := setupTestContext(t, "gov")
+		effs, err := bankMod.handleSetDenomMetadata(txCtx, &MsgSetDenomMetadata{Authority: "gov", Metadata: metadata})
+		if err != nil {
+			t.Fatalf("handleSetDenomMetadata() error = %v", err)
+		}
+		env.Apply(t, txCtx, effs)
+	}
+
+	server, err := query.NewServer(env.Store())
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	if err := server.RegisterHandler(QueryServiceDenomMetadata, handleQueryDenomMetadata); err != nil {
+		t.Fatalf("RegisterHandler() error = %v", err)
+	}
+	if err := server.RegisterHandler(QueryServiceAllDenomMetadata, handleQueryAllDenomMetadata); err != nil {
+		t.Fatalf("RegisterHandler() error = %v", err)
+	}
+	ctx := context.Background()
+
+	resp, err := server.Query(ctx, query.Request{Path: QueryServiceDenomMetadata, Data: []byte(`{"denom":"upun"}`)})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	var one QueryDenomMetadataResponse
+	if err := json.Unmarshal(resp.Value, &one); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if one.Metadata != testPun {
+		t.Fatalf("metadata = %+v, want %+v", one.Metadata, testPun)
+	}
+
+	_, err = server.Query(ctx, query.Request{Path: QueryServiceDenomMetadata, Data: []byte(`{"denom":"uother"}`)})
+	if !errors.Is(err, types.ErrNotFound) {
+		t.Fatalf("Query() error = %v, want ErrNotFound", err)
+	}
+
+	resp, err = server.Query(ctx, query.Request{Path: QueryServiceAllDenomMetadata})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	var all QueryAllDenomMetadataResponse
+	if err := json.Unmarshal(resp.Value, &all); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(all.Metadata) != 2 || all.Metadata[0] != atom || all.Metadata[1] != testPun {
+		t.Fatalf("all metadata = %+v, want [uatom upun]", all.Metadata)
+	}
+}
@@ -2,41 +2,151 @@ package bank
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 
 	"github.com/blockberries/punnet-sdk/capability"
 	"github.com/blockberries/punnet-sdk/effects"
+	sdkerrors "github.com/blockberries/punnet-sdk/errors"
+	"github.com/blockberries/punnet-sdk/events"
 	"github.com/blockberries/punnet-sdk/module"
+	"github.com/blockberries/punnet-sdk/query"
 	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
 	"github.com/blockberries/punnet-sdk/types"
 )
 
 // Module name
 const ModuleName = "bank"
 
+// InvariantTotalSupply is the name of the bank total supply invariant
+const InvariantTotalSupply = "total-supply"
+
+// QueryServiceBalance is the height-aware balance query service path
+const QueryServiceBalance = "/bank/balance"
+
+var (
+	// ErrSendDisabled is returned when sending a denom that Params disables
+	ErrSendDisabled = errors.New("send disabled for denom")
+
+	// ErrAccountBlocked is returned when a blocklisted account sends or receives
+	ErrAccountBlocked = errors.New("account blocked")
+
+	// ErrAccountNotAllowed is returned when an account missing from a
+	// non-empty allowlist sends or receives
+	ErrAccountNotAllowed = errors.New("account not allowed")
+)
+
+// Bank module error codes, in the ModuleName codespace.
+func init() {
+	sdkerrors.MustRegister(ModuleName, 2, ErrSendDisabled)
+	sdkerrors.MustRegister(ModuleName, 3, ErrAccountBlocked)
+	sdkerrors.MustRegister(ModuleName, 4, ErrAccountNotAllowed)
+}
+
 // BankModule provides token transfer functionality
 type BankModule struct {
 	balanceCap capability.BalanceCapability
+
+	// authority may set denom metadata and params ("" if no account may)
+	authority types.AccountName
+
+	// paramsStore holds the transfer policy (nil means DefaultParams)
+	paramsStore *ParamsStore
+
+	// hooks are notified of sends (may be nil)
+	hooks SendHooks
+
+	// supplyStore tracks the supply changed by Mint and Burn (nil: untracked)
+	supplyStore *SupplyStore
+}
+
+// Option configures a BankModule
+type Option func(*BankModule)
+
+// WithAuthority allows account (typically governance) to set denom metadata
+// with MsgSetDenomMetadata and params with MsgUpdateParams. Without it, both
+// messages always fail.
+func WithAuthority(account types.AccountName) Option {
+	return func(m *BankModule) {
+		m.authority = account
+	}
+}
+
+// WithParamsStore checks sends against the params in paramsStore. Without
+// it, sends are checked against DefaultParams, which allow everything.
+func WithParamsStore(paramsStore *ParamsStore) Option {
+	return func(m *BankModule) {
+		m.paramsStore = paramsStore
+	}
+}
+
+// WithSendHooks calls hooks for every MsgSend and MsgMultiSend
+func WithSendHooks(hooks SendHooks) Option {
+	return func(m *BankModule) {
+		m.hooks = hooks
+	}
+}
+
+// WithSupplyStore tracks the supply of each denom in supplyStore, which
+// enables Mint and Burn and makes the total supply invariant compare the
+// sum of balances with it. Without it, the invariant can only check that
+// the balances are valid.
+func WithSupplyStore(supplyStore *SupplyStore) Option {
+	return func(m *BankModule) {
+		m.supplyStore = supplyStore
+	}
 }
 
 // NewBankModule creates a new bank module with the given capability
-func NewBankModule(balanceCap capability.BalanceCapability) (*BankModule, error) {
+func NewBankModule(balanceCap capability.BalanceCapability, opts ...Option) (*BankModule, error) {
 	if balanceCap == nil {
 		return nil, fmt.Errorf("balance capability cannot be nil")
 	}
 
-	return &BankModule{
+	m := &BankModule{
 		balanceCap: balanceCap,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if m.authority != "" && !m.authority.IsValid() {
+		return nil, fmt.Errorf("%w: invalid authority account %s", types.ErrInvalidAccount, m.authority)
+	}
+
+	return m, nil
+}
+
+// Authority returns the account allowed to set denom metadata and params,
+// or "" if there is none
+func (m *BankModule) Authority() types.AccountName {
+	if m == nil {
+		return ""
+	}
+	return m.authority
+}
+
+// checkAuthority returns ErrUnauthorized unless account is the authority
+func (m *BankModule) checkAuthority(account types.AccountName) error {
+	if m.authority == "" {
+		return fmt.Errorf("%w: bank module has no authority", types.ErrUnauthorized)
+	}
+	if account != m.authority {
+		return fmt.Errorf("%w: %s is not the bank authority", types.ErrUnauthorized, account)
+	}
+	return nil
 }
 
 // CreateModule creates the bank module using the module builder
-func CreateModule(balanceCap capability.BalanceCapability) (module.Module, error) {
+func CreateModule(balanceCap capability.BalanceCapability, opts ...Option) (module.Module, error) {
 	if balanceCap == nil {
 		return nil, fmt.Errorf("balance capability cannot be nil")
 	}
 
-	bankMod, err := NewBankModule(balanceCap)
+	bankMod, err := NewBankModule(balanceCap, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create bank module: %w", err)
 	}
@@ -44,11 +154,33 @@ func CreateModule(balanceCap capability.BalanceCapability) (module.Module, error
 	return module.NewModuleBuilder(ModuleName).
 		WithMsgHandler(TypeMsgSend, bankMod.handleSend).
 		WithMsgHandler(TypeMsgMultiSend, bankMod.handleMultiSend).
+		WithMsgHandler(TypeMsgSetDenomMetadata, bankMod.handleSetDenomMetadata).
+		WithMsgHandler(TypeMsgUpdateParams, bankMod.handleUpdateParams).
 		WithQueryHandler("/balance", bankMod.handleQueryBalance).
 		WithQueryHandler("/all_balances", bankMod.handleQueryAllBalances).
+		WithQueryService(QueryServiceBalance, handleQueryBalanceAtHeight).
+		WithQueryService(QueryServiceDenomMetadata, handleQueryDenomMetadata).
+		WithQueryService(QueryServiceAllDenomMetadata, handleQueryAllDenomMetadata).
+		WithQueryService(QueryServiceParams, handleQueryParams).
+		WithInvariant(InvariantTotalSupply, bankMod.totalSupplyInvariant).
 		Build()
 }
 
+// Spec returns the bank module's ModuleSpec for a module.ModuleManager
+func Spec(opts ...Option) module.ModuleSpec {
+	return module.ModuleSpec{
+		Name:         ModuleName,
+		Capabilities: []module.CapabilityKind{module.CapabilityBalance},
+		Create: func(caps module.Capabilities) (module.Module, error) {
+			balanceCap, err := caps.Balance()
+			if err != nil {
+				return nil, err
+			}
+			return CreateModule(balanceCap, opts...)
+		},
+	}
+}
+
 // handleSend handles MsgSend
 func (m *BankModule) handleSend(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
 	if m == nil || m.balanceCap == nil {
@@ -68,6 +200,13 @@ func (m *BankModule) handleSend(ctx *runtime.Context, msg types.Message) ([]effe
 		return nil, fmt.Errorf("sender must be transaction account")
 	}
 
+	coins := types.NewCoins(sendMsg.Amount)
+	inputs := []Input{{Address: sendMsg.From, Coins: coins}}
+	outputs := []Output{{Address: sendMsg.To, Coins: coins}}
+	if err := m.CheckSend(ctx, inputs, outputs); err != nil {
+		return nil, err
+	}
+
 	// Check sender has sufficient balance
 	balance, err := m.balanceCap.GetBalance(ctx.Context(), sendMsg.From, sendMsg.Amount.Denom)
 	if err != nil {
@@ -79,23 +218,43 @@ func (m *BankModule) handleSend(ctx *runtime.Context, msg types.Message) ([]effe
 	}
 
 	// Return transfer effect
-	return []effects.Effect{
+	sendEffects := []effects.Effect{
 		effects.TransferEffect{
 			From:   sendMsg.From,
 			To:     sendMsg.To,
-			Amount: types.NewCoins(sendMsg.Amount),
+			Amount: coins,
 		},
-		effects.NewEventEffect("bank.send", map[string][]byte{
+		effects.NewEventEffect(events.TypeTransfer, map[string][]byte{
 			"from":   []byte(sendMsg.From),
 			"to":     []byte(sendMsg.To),
 			"denom":  []byte(sendMsg.Amount.Denom),
 			"amount": []byte(fmt.Sprintf("%d", sendMsg.Amount.Amount)),
 			"height": []byte(fmt.Sprintf("%d", ctx.BlockHeight())),
 		}),
-	}, nil
+	}
+
+	hookEffects, err := m.AfterSend(ctx, inputs, outputs)
+	if err != nil {
+		return nil, err
+	}
+	return append(sendEffects, hookEffects...), nil
 }
 
-// handleMultiSend handles MsgMultiSend
+// handleMultiSend handles MsgMultiSend: a batch of transfers from one or
+// more senders, e.g. an exchange's payouts, in one message.
+//
+// Every input's account must have signed the transaction. Each output is
+// paid by transfers drawn from the senders in input order (one transfer per
+// output when there is a single sender). Balances are checked against state
+// from before the transaction's earlier messages, so a transfer can still
+// fail when applied; the runtime applies a message's effects in one branch
+// under either runtime.MsgFailurePolicy, so then no output is paid.
+//
+// SECURITY: Inputs are authorized against the transaction's verified signers
+// (see runtime.Context.IsTxSigner), so an input from an account that did not
+// sign is rejected rather than debited.
+//
+// Complexity: O(inputs + outputs*senders) balance reads and effects
 func (m *BankModule) handleMultiSend(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
 	if m == nil || m.balanceCap == nil {
 		return nil, fmt.Errorf("module or capability is nil")
@@ -109,91 +268,158 @@ func (m *BankModule) handleMultiSend(ctx *runtime.Context, msg types.Message) ([
 		return nil, fmt.Errorf("invalid message type: expected *MsgMultiSend")
 	}
 
-	// Verify all senders are authorized
-	signers := multiSendMsg.GetSigners()
-	found := false
-	for _, signer := range signers {
-		if signer == ctx.Account() {
-			found = true
-			break
+	// SECURITY: Every input must have authorized the transaction, not only
+	// the signer the message executes as
+	for i, input := range multiSendMsg.Inputs {
+		if !ctx.IsTxSigner(input.Address) {
+			return nil, fmt.Errorf("%w: input %d is from %s, which did not sign the transaction",
+				types.ErrUnauthorized, i, input.Address)
 		}
 	}
-	if !found {
-		return nil, fmt.Errorf("transaction account must be one of the senders")
+
+	if err := m.CheckSend(ctx, multiSendMsg.Inputs, multiSendMsg.Outputs); err != nil {
+		return nil, err
 	}
 
-	// Check all senders have sufficient balances
-	for _, input := range multiSendMsg.Inputs {
-		for _, coin := range input.Coins {
-			balance, err := m.balanceCap.GetBalance(ctx.Context(), input.Address, coin.Denom)
+	// Check each sender can cover its total of each denom across all of its
+	// inputs. ValidateBasic guarantees the totals do not overflow and equal
+	// the output totals.
+	senders := sumInputs(multiSendMsg.Inputs)
+	var total types.Coins
+	for _, sender := range senders {
+		total = total.Add(sender.Coins)
+		for _, coin := range sender.Coins {
+			balance, err := m.balanceCap.GetBalance(ctx.Context(), sender.Address, coin.Denom)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get balance for %s: %w", input.Address, err)
+				return nil, fmt.Errorf("failed to get balance for %s: %w", sender.Address, err)
 			}
 
 			if balance < coin.Amount {
 				return nil, fmt.Errorf("%w: insufficient balance for %s denom %s",
-					types.ErrInsufficientFunds, input.Address, coin.Denom)
+					types.ErrInsufficientFunds, sender.Address, coin.Denom)
 			}
 		}
 	}
 
-	// Create transfer effects for each input->output combination
-	// Note: This is a simplified implementation. A full implementation would need to
-	// properly map inputs to outputs, but for now we just transfer from each input
-	// to the first output that needs those coins.
-	var transferEffects []effects.Effect
-
-	// Build a map of total outputs needed per account and denom
-	outputNeeds := make(map[string]map[string]uint64) // account -> denom -> amount
+	transferEffects := make([]effects.Effect, 0, len(multiSendMsg.Outputs)+1)
 	for _, output := range multiSendMsg.Outputs {
-		if outputNeeds[string(output.Address)] == nil {
-			outputNeeds[string(output.Address)] = make(map[string]uint64)
-		}
-		for _, coin := range output.Coins {
-			outputNeeds[string(output.Address)][coin.Denom] += coin.Amount
-		}
-	}
-
-	// Create transfers from inputs to outputs
-	for _, input := range multiSendMsg.Inputs {
-		for _, coin := range input.Coins {
-			// Find outputs that need this denomination and transfer to them
-			remaining := coin.Amount
-			for acct, denoms := range outputNeeds {
-				if needed, ok := denoms[coin.Denom]; ok && needed > 0 {
-					// Transfer what we can
-					toTransfer := remaining
-					if toTransfer > needed {
-						toTransfer = needed
-					}
-
-					transferEffects = append(transferEffects, effects.TransferEffect{
-						From:   input.Address,
-						To:     types.AccountName(acct),
-						Amount: types.Coins{{Denom: coin.Denom, Amount: toTransfer}},
-					})
-
-					outputNeeds[acct][coin.Denom] -= toTransfer
-					remaining -= toTransfer
-
-					if remaining == 0 {
-						break
-					}
+		// Draw the output's coins from the senders in input order
+		need := output.Coins
+		for i := range senders {
+			var drawn types.Coins
+			for _, coin := range need {
+				amount := min(coin.Amount, senders[i].Coins.AmountOf(coin.Denom))
+				if amount > 0 {
+					drawn = append(drawn, types.NewCoin(coin.Denom, amount))
 				}
 			}
+			if len(drawn) == 0 {
+				continue
+			}
+
+			var err error
+			if need, err = need.Sub(drawn); err != nil {
+				return nil, err
+			}
+			if senders[i].Coins, err = senders[i].Coins.Sub(drawn); err != nil {
+				return nil, err
+			}
+			transferEffects = append(transferEffects, effects.TransferEffect{
+				From:   senders[i].Address,
+				To:     output.Address,
+				Amount: types.NewCoins(drawn...),
+			})
 		}
 	}
 
-	// Add event effect
 	transferEffects = append(transferEffects,
 		effects.NewEventEffect("bank.multi_send", map[string][]byte{
+			"sender":      []byte(ctx.Account()),
+			"amount":      []byte(total.String()),
 			"num_inputs":  []byte(fmt.Sprintf("%d", len(multiSendMsg.Inputs))),
 			"num_outputs": []byte(fmt.Sprintf("%d", len(multiSendMsg.Outputs))),
 			"height":      []byte(fmt.Sprintf("%d", ctx.BlockHeight())),
 		}),
 	)
 
-	return transferEffects, nil
+	hookEffects, err := m.AfterSend(ctx, multiSendMsg.Inputs, multiSendMsg.Outputs)
+	if err != nil {
+		return nil, err
+	}
+	return append(transferEffects, hookEffects...), nil
+}
+
+// sumInputs sums inputs per address, in order of first appearance
+func sumInputs(inputs []Input) []Input {
+	senders := make([]Input, 0, len(inputs))
+	index := make(map[types.AccountName]int, len(inputs))
+	for _, input := range inputs {
+		i, ok := index[input.Address]
+		if !ok {
+			index[input.Address] = len(senders)
+			senders = append(senders, Input{Address: input.Address})
+			i = len(senders) - 1
+		}
+		senders[i].Coins = senders[i].Coins.Add(input.Coins)
+	}
+	return senders
+}
+
+// TotalSupply returns the total supply, the sum of all balances per denom.
+// Returns an error if a stored balance is invalid or a denom's sum overflows.
+// Like IterateBalances, it reads flushed (committed) balances.
+// Complexity: O(n) for n stored balances
+func (m *BankModule) TotalSupply(ctx context.Context) (types.Coins, error) {
+	if m == nil || m.balanceCap == nil {
+		return nil, fmt.Errorf("module or capability is nil")
+	}
+
+	totals := make(map[string]uint64)
+	err := m.balanceCap.IterateBalances(ctx, func(balance store.Balance) error {
+		if !types.NewCoin(balance.Denom, balance.Amount).IsValid() {
+			return fmt.Errorf("%w: account %s holds invalid denom %q", types.ErrInvalidCoin, balance.Account, balance.Denom)
+		}
+		total := totals[balance.Denom]
+		if total > math.MaxUint64-balance.Amount {
+			return fmt.Errorf("%w: total supply of %s overflows", types.ErrInvalidCoin, balance.Denom)
+		}
+		totals[balance.Denom] = total + balance.Amount
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	supply := make(types.Coins, 0, len(totals))
+	for denom, amount := range totals {
+		if amount > 0 {
+			supply = append(supply, types.NewCoin(denom, amount))
+		}
+	}
+	return supply.Sort(), nil
+}
+
+// totalSupplyInvariant checks that every balance is valid, that the sum of
+// balances per denom is representable and, with a supply store, that it
+// equals the tracked supply. Transfers conserve the sum and only Mint and
+// Burn change it, so a failure indicates state corruption.
+func (m *BankModule) totalSupplyInvariant(ctx context.Context) (string, bool) {
+	supply, err := m.TotalSupply(ctx)
+	if err != nil {
+		return fmt.Sprintf("total supply is not the sum of valid balances: %v", err), true
+	}
+	if m.supplyStore == nil {
+		return fmt.Sprintf("total supply: %s", supply), false
+	}
+
+	tracked, err := m.supplyStore.Supply()
+	if err != nil {
+		return fmt.Sprintf("failed to read tracked supply: %v", err), true
+	}
+	if mismatch := supplyMismatch(supply, tracked); mismatch != "" {
+		return fmt.Sprintf("total supply does not match tracked supply: %s", mismatch), true
+	}
+	return fmt.Sprintf("total supply: %s", supply), false
 }
 
 // QueryBalanceRequest is the request for balance query
@@ -240,6 +466,32 @@ func (m *BankModule) handleQueryBalance(ctx context.Context, path string, data [
 	return []byte(fmt.Sprintf("%d", balance)), nil
 }
 
+// handleQueryBalanceAtHeight serves QueryServiceBalance: a JSON
+// QueryBalanceRequest answered with a JSON QueryBalanceResponse, read from
+// the state pinned at the query height
+func handleQueryBalanceAtHeight(ctx *query.Context, data []byte) ([]byte, error) {
+	var req QueryBalanceRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("invalid balance query: %w", err)
+	}
+
+	if !req.Account.IsValid() {
+		return nil, fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
+	}
+
+	if req.Denom == "" {
+		return nil, fmt.Errorf("denom cannot be empty")
+	}
+
+	balances := store.NewBalanceStore(capability.ModuleStore(ctx.Store(), ModuleName))
+	balance, err := balances.Get(ctx.Context(), req.Account, req.Denom)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+
+	return json.Marshal(QueryBalanceResponse{Balance: balance.Amount})
+}
+
 // QueryAllBalancesRequest is the request for all balances query
 type QueryAllBalancesRequest struct {
 	Account types.AccountName `json:"account"`
@@ -2,10 +2,14 @@ package bank
 
 import (
 	"context"
+	"errors"
+	"math"
 	"testing"
 	"time"
 
 	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/module"
 	"github.com/blockberries/punnet-sdk/runtime"
 	"github.com/blockberries/punnet-sdk/store"
 	"github.com/blockberries/punnet-sdk/types"
@@ -217,6 +221,7 @@ func TestBankModule_HandleMultiSend(t *testing.T) {
 		name    string
 		msg     *MsgMultiSend
 		account types.AccountName
+		signers []types.AccountName
 		wantErr bool
 	}{
 		{
@@ -257,6 +262,76 @@ func TestBankModule_HandleMultiSend(t *testing.T) {
 			account: "bob",
 			wantErr: true,
 		},
+		{
+			name: "input from another account",
+			msg: &MsgMultiSend{
+				Inputs: []Input{
+					{
+						Address: "alice",
+						Coins:   types.NewCoins(types.NewCoin("token", 100)),
+					},
+					{
+						Address: "bob",
+						Coins:   types.NewCoins(types.NewCoin("token", 100)),
+					},
+				},
+				Outputs: []Output{
+					{
+						Address: "charlie",
+						Coins:   types.NewCoins(types.NewCoin("token", 200)),
+					},
+				},
+			},
+			account: "alice",
+			wantErr: true,
+		},
+		{
+			name: "input from a co-signer",
+			msg: &MsgMultiSend{
+				Inputs: []Input{
+					{
+						Address: "alice",
+						Coins:   types.NewCoins(types.NewCoin("token", 100)),
+					},
+					{
+						Address: "bob",
+						Coins:   types.NewCoins(types.NewCoin("token", 100)),
+					},
+				},
+				Outputs: []Output{
+					{
+						Address: "charlie",
+						Coins:   types.NewCoins(types.NewCoin("token", 200)),
+					},
+				},
+			},
+			account: "alice",
+			signers: []types.AccountName{"alice", "bob"},
+			wantErr: false,
+		},
+		{
+			name: "repeated inputs exceed balance",
+			msg: &MsgMultiSend{
+				Inputs: []Input{
+					{
+						Address: "alice",
+						Coins:   types.NewCoins(types.NewCoin("token", 600)),
+					},
+					{
+						Address: "alice",
+						Coins:   types.NewCoins(types.NewCoin("token", 600)),
+					},
+				},
+				Outputs: []Output{
+					{
+						Address: "charlie",
+						Coins:   types.NewCoins(types.NewCoin("token", 1200)),
+					},
+				},
+			},
+			account: "alice",
+			wantErr: true,
+		},
 		{
 			name: "insufficient balance",
 			msg: &MsgMultiSend{
@@ -280,7 +355,7 @@ func TestBankModule_HandleMultiSend(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx := setupTestContext(t, tt.account)
+			ctx := setupTestContext(t, tt.account).WithTxSigners(tt.signers...)
 			effects, err := bankMod.handleMultiSend(ctx, tt.msg)
 
 			if (err != nil) != tt.wantErr {
@@ -296,6 +371,109 @@ func TestBankModule_HandleMultiSend(t *testing.T) {
 	}
 }
 
+// TestBankModule_HandleMultiSend_Batch checks a payout batch becomes one
+// transfer per output, in output order
+func TestBankModule_HandleMultiSend_Batch(t *testing.T) {
+	bankMod, balanceCap := setupTestBankModule(t)
+	for _, denom := range []string{"atom", "token"} {
+		if err := balanceCap.SetBalance(context.Background(), "exchange", denom, 1000); err != nil {
+			t.Fatalf("failed to set balance: %v", err)
+		}
+	}
+
+	msg := &MsgMultiSend{
+		Inputs: []Input{
+			{Address: "exchange", Coins: types.NewCoins(types.NewCoin("atom", 5), types.NewCoin("token", 300))},
+		},
+		Outputs: []Output{
+			{Address: "zed", Coins: types.NewCoins(types.NewCoin("token", 100))},
+			{Address: "amy", Coins: types.NewCoins(types.NewCoin("atom", 5), types.NewCoin("token", 150))},
+			{Address: "zed", Coins: types.NewCoins(types.NewCoin("token", 50))},
+		},
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		t.Fatalf("ValidateBasic() error = %v", err)
+	}
+
+	for run := 0; run < 5; run++ {
+		effs, err := bankMod.handleMultiSend(setupTestContext(t, "exchange"), msg)
+		if err != nil {
+			t.Fatalf("handleMultiSend() error = %v", err)
+		}
+		if len(effs) != len(msg.Outputs)+1 {
+			t.Fatalf("expected %d effects, got %d", len(msg.Outputs)+1, len(effs))
+		}
+		for i, output := range msg.Outputs {
+			transfer, ok := effs[i].(effects.TransferEffect)
+			if !ok {
+				t.Fatalf("effect %d = %T, want TransferEffect", i, effs[i])
+			}
+			if transfer.From != "exchange" || transfer.To != output.Address || transfer.Amount.String() != output.Coins.String() {
+				t.Fatalf("effect %d = %+v, want exchange -> %s %s", i, transfer, output.Address, output.Coins)
+			}
+		}
+	}
+}
+
+// TestBankModule_HandleMultiSend_TwoInputs checks each output is drawn from
+// the senders in input order and the transfers apply
+func TestBankModule_HandleMultiSend_TwoInputs(t *testing.T) {
+	bankMod, env := setupPolicyModule(t, DefaultParams(), nil)
+	if err := bankMod.balanceCap.SetBalance(context.Background(), "bob", "stake", 1000); err != nil {
+		t.Fatalf("failed to set balance: %v", err)
+	}
+
+	msg := &MsgMultiSend{
+		Inputs: []Input{
+			{Address: "alice", Coins: types.NewCoins(types.NewCoin("stake", 100))},
+			{Address: "bob", Coins: types.NewCoins(types.NewCoin("stake", 200))},
+		},
+		Outputs: []Output{
+			{Address: "carol", Coins: types.NewCoins(types.NewCoin("stake", 150))},
+			{Address: "dave", Coins: types.NewCoins(types.NewCoin("stake", 150))},
+		},
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		t.Fatalf("ValidateBasic() error = %v", err)
+	}
+
+	// bob's input is rejected unless bob signed the transaction
+	if _, err := bankMod.handleMultiSend(setupTestContext(t, "alice"), msg); !errors.Is(err, types.ErrUnauthorized) {
+		t.Fatalf("handleMultiSend() error = %v, want ErrUnauthorized", err)
+	}
+
+	ctx := setupTestContext(t, "alice").WithTxSigners("alice", "bob")
+	effs, err := bankMod.handleMultiSend(ctx, msg)
+	if err != nil {
+		t.Fatalf("handleMultiSend() error = %v", err)
+	}
+	want := []effects.TransferEffect{
+		{From: "alice", To: "carol", Amount: types.NewCoins(types.NewCoin("stake", 100))},
+		{From: "bob", To: "carol", Amount: types.NewCoins(types.NewCoin("stake", 50))},
+		{From: "bob", To: "dave", Amount: types.NewCoins(types.NewCoin("stake", 150))},
+	}
+	if len(effs) != len(want)+1 {
+		t.Fatalf("expected %d effects, got %d", len(want)+1, len(effs))
+	}
+	for i, w := range want {
+		transfer, ok := effs[i].(effects.TransferEffect)
+		if !ok || transfer.From != w.From || transfer.To != w.To || transfer.Amount.String() != w.Amount.String() {
+			t.Fatalf("effect %d = %+v, want %+v", i, effs[i], w)
+		}
+	}
+	env.Apply(t, ctx, effs)
+
+	for account, want := range map[types.AccountName]uint64{"alice": 900, "bob": 800, "carol": 150, "dave": 150} {
+		balance, err := bankMod.balanceCap.GetBalance(context.Background(), account, "stake")
+		if err != nil {
+			t.Fatalf("GetBalance(%s) error = %v", account, err)
+		}
+		if balance != want {
+			t.Fatalf("balance of %s = %d, want %d", account, balance, want)
+		}
+	}
+}
+
 func TestBankModule_HandleQueryBalance(t *testing.T) {
 	bankMod, balanceCap := setupTestBankModule(t)
 
@@ -427,3 +605,72 @@ func TestSplitOnce(t *testing.T) {
 		})
 	}
 }
+
+// flushBalances writes cached balances through so iteration sees them
+func flushBalances(t *testing.T, balanceCap capability.BalanceCapability) {
+	t.Helper()
+
+	flusher, ok := balanceCap.(interface{ Flush(context.Context) error })
+	if !ok {
+		t.Fatal("balance capability cannot be flushed")
+	}
+	if err := flusher.Flush(context.Background()); err != nil {
+		t.Fatalf("failed to flush balances: %v", err)
+	}
+}
+
+func TestTotalSupplyInvariant(t *testing.T) {
+	bankMod, balanceCap := setupTestBankModule(t)
+	ctx := context.Background()
+
+	if err := balanceCap.SetBalance(ctx, "alice", "uatom", 100); err != nil {
+		t.Fatalf("failed to set balance: %v", err)
+	}
+	if err := balanceCap.SetBalance(ctx, "bob", "uatom", 50); err != nil {
+		t.Fatalf("failed to set balance: %v", err)
+	}
+	if err := balanceCap.SetBalance(ctx, "bob", "stake", 7); err != nil {
+		t.Fatalf("failed to set balance: %v", err)
+	}
+
+	flushBalances(t, balanceCap)
+
+	supply, err := bankMod.TotalSupply(ctx)
+	if err != nil {
+		t.Fatalf("TotalSupply failed: %v", err)
+	}
+	if supply.AmountOf("uatom") != 150 || supply.AmountOf("stake") != 7 {
+		t.Fatalf("unexpected supply %s", supply)
+	}
+
+	if msg, broken := bankMod.totalSupplyInvariant(ctx); broken {
+		t.Fatalf("invariant unexpectedly broken: %s", msg)
+	}
+
+	// A corrupted balance that overflows the supply breaks the invariant
+	if err := balanceCap.SetBalance(ctx, "carol", "uatom", math.MaxUint64); err != nil {
+		t.Fatalf("failed to set balance: %v", err)
+	}
+	flushBalances(t, balanceCap)
+	if _, broken := bankMod.totalSupplyInvariant(ctx); !broken {
+		t.Fatal("expected invariant to be broken")
+	}
+}
+
+func TestCreateModule_RegistersInvariants(t *testing.T) {
+	_, balanceCap := setupTestBankModule(t)
+
+	mod, err := CreateModule(balanceCap)
+	if err != nil {
+		t.Fatalf("CreateModule failed: %v", err)
+	}
+
+	registry := module.NewInvariantRegistry()
+	if err := registry.RegisterModules([]module.Module{mod}); err != nil {
+		t.Fatalf("RegisterModules failed: %v", err)
+	}
+	routes := registry.Routes()
+	if len(routes) != 1 || routes[0] != module.InvariantRoute(ModuleName, InvariantTotalSupply) {
+		t.Fatalf("unexpected routes %v", routes)
+	}
+}
@@ -0,0 +1,266 @@
+package bank
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/query"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// ParamsNamespace is the state namespace holding the bank params
+// ("module/bank.params/params"). Like DenomMetadataNamespace it lives beside
+// the bank namespace, whose every key is a balance.
+const ParamsNamespace = ModuleName + ".params"
+
+// QueryServiceParams answers any request with a JSON QueryParamsResponse
+const QueryServiceParams = "/bank/params"
+
+// EventTypeUpdateParams is emitted when MsgUpdateParams succeeds
+const EventTypeUpdateParams = "bank.update_params"
+
+// paramsKey is the key of the params within ParamsNamespace
+var paramsKey = []byte("params")
+
+// SendEnabled overrides Params.DefaultSendEnabled for one denomination
+type SendEnabled struct {
+	Denom   string `json:"denom"`
+	Enabled bool   `json:"enabled"`
+}
+
+// Params are the bank transfer policy, checked for every MsgSend and
+// MsgMultiSend.
+//
+// SECURITY: The policy covers the bank's own messages only. Transfers that
+// other modules make with effects.TransferEffect (fees, staking, ...) are not
+// checked.
+type Params struct {
+	// DefaultSendEnabled applies to denominations without a SendEnabled entry
+	DefaultSendEnabled bool `json:"default_send_enabled"`
+
+	// SendEnabled are per-denomination overrides.
+	// INVARIANT: Sorted by denom, without duplicates.
+	SendEnabled []SendEnabled `json:"send_enabled,omitempty"`
+
+	// Blocklist are accounts that may neither send nor receive.
+	// INVARIANT: Sorted, without duplicates.
+	Blocklist []types.AccountName `json:"blocklist,omitempty"`
+
+	// Allowlist, if not empty, are the only accounts that may send or receive.
+	// INVARIANT: Sorted, without duplicates.
+	Allowlist []types.AccountName `json:"allowlist,omitempty"`
+}
+
+// DefaultParams returns params allowing every transfer
+func DefaultParams() Params {
+	return Params{DefaultSendEnabled: true}
+}
+
+// ValidateBasic performs stateless validation
+func (p Params) ValidateBasic() error {
+	for i, s := range p.SendEnabled {
+		if !(types.Coin{Denom: s.Denom}).IsValid() {
+			return fmt.Errorf("%w: invalid send enabled denom %q", types.ErrInvalidCoin, s.Denom)
+		}
+		if i > 0 && p.SendEnabled[i-1].Denom >= s.Denom {
+			return fmt.Errorf("send enabled entries must be sorted by denom without duplicates: %s", s.Denom)
+		}
+	}
+
+	if err := validateAccountList("blocklist", p.Blocklist); err != nil {
+		return err
+	}
+	if err := validateAccountList("allowlist", p.Allowlist); err != nil {
+		return err
+	}
+
+	for _, account := range p.Blocklist {
+		if _, found := slices.BinarySearch(p.Allowlist, account); found {
+			return fmt.Errorf("account %s is both blocked and allowed", account)
+		}
+	}
+	return nil
+}
+
+// validateAccountList checks that accounts are valid, sorted and unique
+func validateAccountList(name string, accounts []types.AccountName) error {
+	for i, account := range accounts {
+		if !account.IsValid() {
+			return fmt.Errorf("%w: invalid %s account %s", types.ErrInvalidAccount, name, account)
+		}
+		if i > 0 && accounts[i-1] >= account {
+			return fmt.Errorf("%s must be sorted without duplicates: %s", name, account)
+		}
+	}
+	return nil
+}
+
+// IsSendEnabled reports whether denom may be transferred
+//
+// Complexity: O(log n) in len(SendEnabled)
+func (p Params) IsSendEnabled(denom string) bool {
+	i, found := slices.BinarySearchFunc(p.SendEnabled, denom, func(s SendEnabled, denom string) int {
+		switch {
+		case s.Denom < denom:
+			return -1
+		case s.Denom > denom:
+			return 1
+		}
+		return 0
+	})
+	if found {
+		return p.SendEnabled[i].Enabled
+	}
+	return p.DefaultSendEnabled
+}
+
+// CheckAccount returns ErrAccountBlocked or ErrAccountNotAllowed if account
+// may not send or receive
+//
+// Complexity: O(log n) in the list lengths
+func (p Params) CheckAccount(account types.AccountName) error {
+	if _, found := slices.BinarySearch(p.Blocklist, account); found {
+		return fmt.Errorf("%w: %s", ErrAccountBlocked, account)
+	}
+	if len(p.Allowlist) > 0 {
+		if _, found := slices.BinarySearch(p.Allowlist, account); !found {
+			return fmt.Errorf("%w: %s", ErrAccountNotAllowed, account)
+		}
+	}
+	return nil
+}
+
+// ParamsStore reads and writes the bank params in the state store.
+// Unset params read as DefaultParams.
+type ParamsStore struct {
+	// store is the ParamsNamespace view of the state store
+	store store.BackingStore
+}
+
+// NewParamsStore creates a params store over the application state store
+func NewParamsStore(stateStore store.BackingStore) (*ParamsStore, error) {
+	if stateStore == nil {
+		return nil, fmt.Errorf("state store cannot be nil")
+	}
+	return &ParamsStore{store: capability.ModuleStore(stateStore, ParamsNamespace)}, nil
+}
+
+// Get returns the current params
+func (s *ParamsStore) Get() (Params, error) {
+	if s == nil {
+		return Params{}, fmt.Errorf("params store is nil")
+	}
+
+	data, err := s.store.Get(paramsKey)
+	if errors.Is(err, store.ErrNotFound) || (err == nil && data == nil) {
+		return DefaultParams(), nil
+	}
+	if err != nil {
+		return Params{}, fmt.Errorf("failed to read params: %w", err)
+	}
+
+	var params Params
+	if err := json.Unmarshal(data, &params); err != nil {
+		return Params{}, fmt.Errorf("failed to decode params: %w", err)
+	}
+	return params, nil
+}
+
+// Set writes params directly to the store, e.g. at genesis. In transactions
+// params change through MsgUpdateParams.
+func (s *ParamsStore) Set(params Params) error {
+	if s == nil {
+		return fmt.Errorf("params store is nil")
+	}
+	if err := params.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid params: %w", err)
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to encode params: %w", err)
+	}
+	return s.store.Set(paramsKey, data)
+}
+
+// Params returns the current params: those of the params store (see
+// WithParamsStore), or DefaultParams without one
+func (m *BankModule) Params() (Params, error) {
+	if m == nil {
+		return Params{}, fmt.Errorf("bank module is nil")
+	}
+	if m.paramsStore == nil {
+		return DefaultParams(), nil
+	}
+	return m.paramsStore.Get()
+}
+
+// handleUpdateParams handles MsgUpdateParams
+func (m *BankModule) handleUpdateParams(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("module is nil")
+	}
+
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	updateMsg, ok := msg.(*MsgUpdateParams)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgUpdateParams")
+	}
+
+	if updateMsg.Authority != ctx.Account() {
+		return nil, fmt.Errorf("%w: %s must be transaction account", types.ErrUnauthorized, updateMsg.Authority)
+	}
+
+	if err := m.checkAuthority(updateMsg.Authority); err != nil {
+		return nil, err
+	}
+
+	// Without a params store the update could never be read back
+	if m.paramsStore == nil {
+		return nil, fmt.Errorf("bank module has no params store")
+	}
+
+	data, err := json.Marshal(updateMsg.Params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode params: %w", err)
+	}
+
+	return []effects.Effect{
+		effects.NewStateWriteEffect(ParamsNamespace, paramsKey, data),
+		effects.NewEventEffect(EventTypeUpdateParams, map[string][]byte{
+			"authority": []byte(updateMsg.Authority),
+			"height":    []byte(strconv.FormatUint(ctx.BlockHeight(), 10)),
+		}),
+	}, nil
+}
+
+// QueryParamsResponse is the response for QueryServiceParams
+type QueryParamsResponse struct {
+	Params Params `json:"params"`
+}
+
+// handleQueryParams serves QueryServiceParams from the state pinned at the
+// query height
+func handleQueryParams(ctx *query.Context, _ []byte) ([]byte, error) {
+	paramsStore, err := NewParamsStore(ctx.Store())
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := paramsStore.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(QueryParamsResponse{Params: params})
+}
@@ -0,0 +1,235 @@
+package bank
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/blockberries/punnet-sdk/query"
+	punnettesting "github.com/blockberries/punnet-sdk/testing"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// setupPolicyModule returns a bank module with authority "gov", a params
+// store and hooks over a fresh store, with 1000stake and 1000ufrozen for alice
+func setupPolicyModule(t *testing.T, params Params, hooks SendHooks) (*BankModule, *punnettesting.EffectEnv) {
+	t.Helper()
+
+	env := punnettesting.NewEffectEnv(t)
+	capMgr := env.CapabilityManager()
+	if err := capMgr.RegisterModule(ModuleName); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	balanceCap, err := capMgr.GrantBalanceCapability(ModuleName)
+	if err != nil {
+		t.Fatalf("failed to grant balance capability: %v", err)
+	}
+	env.UseBalances(balanceCap)
+	for _, denom := range []string{"stake", "ufrozen"} {
+		if err := balanceCap.AddBalance(context.Background(), "alice", denom, 1000); err != nil {
+			t.Fatalf("failed to fund alice: %v", err)
+		}
+	}
+
+	paramsStore, err := NewParamsStore(env.Store())
+	if err != nil {
+		t.Fatalf("NewParamsStore() error = %v", err)
+	}
+	if err := paramsStore.Set(params); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	opts := []Option{WithAuthority("gov"), WithParamsStore(paramsStore)}
+	if hooks != nil {
+		opts = append(opts, WithSendHooks(hooks))
+	}
+	bankMod, err := NewBankModule(balanceCap, opts...)
+	if err != nil {
+		t.Fatalf("failed to create bank module: %v", err)
+	}
+	return bankMod, env
+}
+
+func TestParams_ValidateBasic(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  Params
+		wantErr bool
+	}{
+		{"default", DefaultParams(), false},
+		{"full", Params{
+			SendEnabled: []SendEnabled{{Denom: "a", Enabled: true}, {Denom: "b"}},
+			Blocklist:   []types.AccountName{"eve", "mallory"},
+			Allowlist:   []types.AccountName{"alice", "bob"},
+		}, false},
+		{"unsorted send enabled", Params{SendEnabled: []SendEnabled{{Denom: "b"}, {Denom: "a"}}}, true},
+		{"duplicate send enabled", Params{SendEnabled: []SendEnabled{{Denom: "a"}, {Denom: "a"}}}, true},
+		{"empty denom", Params{SendEnabled: []SendEnabled{{Denom: ""}}}, true},
+		{"unsorted blocklist", Params{Blocklist: []types.AccountName{"mallory", "eve"}}, true},
+		{"invalid allowlist account", Params{Allowlist: []types.AccountName{"Alice"}}, true},
+		{"blocked and allowed", Params{Blocklist: []types.AccountName{"bob"}, Allowlist: []types.AccountName{"alice", "bob"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.ValidateBasic()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateBasic() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBankModule_SendPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  Params
+		msg     types.Message
+		wantErr error
+	}{
+		{
+			name:   "default params",
+			params: DefaultParams(),
+			msg:    &MsgSend{From: "alice", To: "bob", Amount: types.NewCoin("stake", 10)},
+		},
+		{
+			name:    "denom disabled",
+			params:  Params{DefaultSendEnabled: true, SendEnabled: []SendEnabled{{Denom: "ufrozen"}}},
+			msg:     &MsgSend{From: "alice", To: "bob", Amount: types.NewCoin("ufrozen", 10)},
+			wantErr: ErrSendDisabled,
+		},
+		{
+			name:   "other denom enabled",
+			params: Params{DefaultSendEnabled: true, SendEnabled: []SendEnabled{{Denom: "ufrozen"}}},
+			msg:    &MsgSend{From: "alice", To: "bob", Amount: types.NewCoin("stake", 10)},
+		},
+		{
+			name:   "denom enabled by override",
+			params: Params{SendEnabled: []SendEnabled{{Denom: "stake", Enabled: true}}},
+			msg:    &MsgSend{From: "alice", To: "bob", Amount: types.NewCoin("stake", 10)},
+		},
+		{
+			name:    "sends disabled by default",
+			params:  Params{SendEnabled: []SendEnabled{{Denom: "stake", Enabled: true}}},
+			msg:     &MsgSend{From: "alice", To: "bob", Amount: types.NewCoin("ufrozen", 10)},
+			wantErr: ErrSendDisabled,
+		},
+		{
+			name:    "blocked recipient",
+			params:  Params{DefaultSendEnabled: true, Blocklist: []types.AccountName{"bob"}},
+			msg:     &MsgSend{From: "alice", To: "bob", Amount: types.NewCoin("stake", 10)},
+			wantErr: ErrAccountBlocked,
+		},
+		{
+			name:    "blocked sender",
+			params:  Params{DefaultSendEnabled: true, Blocklist: []types.AccountName{"alice"}},
+			msg:     &MsgSend{From: "alice", To: "bob", Amount: types.NewCoin("stake", 10)},
+			wantErr: ErrAccountBlocked,
+		},
+		{
+			name:    "recipient not allowed",
+			params:  Params{DefaultSendEnabled: true, Allowlist: []types.AccountName{"alice"}},
+			msg:     &MsgSend{From: "alice", To: "bob", Amount: types.NewCoin("stake", 10)},
+			wantErr: ErrAccountNotAllowed,
+		},
+		{
+			name:   "both allowed",
+			params: Params{DefaultSendEnabled: true, Allowlist: []types.AccountName{"alice", "bob"}},
+			msg:    &MsgSend{From: "alice", To: "bob", Amount: types.NewCoin("stake", 10)},
+		},
+		{
+			name:   "multi send blocked output",
+			params: Params{DefaultSendEnabled: true, Blocklist: []types.AccountName{"carol"}},
+			msg: &MsgMultiSend{
+				Inputs: []Input{{Address: "alice", Coins: types.NewCoins(types.NewCoin("stake", 20))}},
+				Outputs: []Output{
+					{Address: "bob", Coins: types.NewCoins(types.NewCoin("stake", 10))},
+					{Address: "carol", Coins: types.NewCoins(types.NewCoin("stake", 10))},
+				},
+			},
+			wantErr: ErrAccountBlocked,
+		},
+		{
+			name:   "multi send denom disabled",
+			params: Params{DefaultSendEnabled: true, SendEnabled: []SendEnabled{{Denom: "ufrozen"}}},
+			msg: &MsgMultiSend{
+				Inputs:  []Input{{Address: "alice", Coins: types.NewCoins(types.NewCoin("ufrozen", 10))}},
+				Outputs: []Output{{Address: "bob", Coins: types.NewCoins(types.NewCoin("ufrozen", 10))}},
+			},
+			wantErr: ErrSendDisabled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bankMod, _ := setupPolicyModule(t, tt.params, nil)
+			ctx := setupTestContext(t, "alice")
+
+			var err error
+			if _, ok := tt.msg.(*MsgSend); ok {
+				_, err = bankMod.handleSend(ctx, tt.msg)
+			} else {
+				_, err = bankMod.handleMultiSend(ctx, tt.msg)
+			}
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("handler error = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("handler error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBankModule_HandleUpdateParams(t *testing.T) {
+	bankMod, env := setupPolicyModule(t, DefaultParams(), nil)
+	newParams := Params{DefaultSendEnabled: true, Blocklist: []types.AccountName{"bob"}}
+
+	_, err := bankMod.handleUpdateParams(setupTestContext(t, "alice"), &MsgUpdateParams{Authority: "alice", Params: newParams})
+	if !errors.Is(err, types.ErrUnauthorized) {
+		t.Fatalf("handleUpdateParams() error = %v, want ErrUnauthorized", err)
+	}
+
+	ctx := setupTestContext(t, "gov")
+	effs, err := bankMod.handleUpdateParams(ctx, &MsgUpdateParams{Authority: "gov", Params: newParams})
+	if err != nil {
+		t.Fatalf("handleUpdateParams() error = %v", err)
+	}
+	env.Apply(t, ctx, effs)
+
+	// The new params apply to the next send
+	_, err = bankMod.handleSend(setupTestContext(t, "alice"), &MsgSend{From: "alice", To: "bob", Amount: types.NewCoin("stake", 1)})
+	if !errors.Is(err, ErrAccountBlocked) {
+		t.Fatalf("handleSend() error = %v, want ErrAccountBlocked", err)
+	}
+
+	server, err := query.NewServer(env.Store())
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	if err := server.RegisterHandler(QueryServiceParams, handleQueryParams); err != nil {
+		t.Fatalf("RegisterHandler() error = %v", err)
+	}
+	resp, err := server.Query(context.Background(), query.Request{Path: QueryServiceParams})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	var got QueryParamsResponse
+	if err := json.Unmarshal(resp.Value, &got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(got.Params.Blocklist) != 1 || got.Params.Blocklist[0] != "bob" || !got.Params.DefaultSendEnabled {
+		t.Fatalf("params = %+v, want %+v", got.Params, newParams)
+	}
+}
+
+func TestBankModule_HandleUpdateParams_NoParamsStore(t *testing.T) {
+	_, balanceCap := setupTestBankModule(t)
+	bankMod, err := NewBankModule(balanceCap, WithAuthority("gov"))
+	if err != nil {
+		t.Fatalf("failed to create bank module: %v", err)
+	}
+
+	if _, err := bankMod.handleUpdateParams(setupTestContext(t, "gov"), &MsgUpdateParams{Authority: "gov", Params: DefaultParams()}); err == nil {
+		t.Fatal("handleUpdateParams() without params store succeeded")
+	}
+}
@@ -0,0 +1,60 @@
+package bank
+
+import (
+	bankv1 "github.com/blockberries/punnet-sdk/api/punnet/bank/v1"
+	"github.com/blockberries/punnet-sdk/protocodec"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// RegisterProtoMessages registers the protobuf converters of MsgSend and
+// MsgMultiSend
+func RegisterProtoMessages(r *protocodec.Registry) error {
+	if err := protocodec.RegisterMessage(r, msgSendToProto, msgSendFromProto); err != nil {
+		return err
+	}
+	return protocodec.RegisterMessage(r, msgMultiSendToProto, msgMultiSendFromProto)
+}
+
+func msgSendToProto(m *MsgSend) (*bankv1.MsgSend, error) {
+	return &bankv1.MsgSend{
+		From:   string(m.From),
+		To:     string(m.To),
+		Amount: protocodec.CoinToProto(m.Amount),
+	}, nil
+}
+
+func msgSendFromProto(p *bankv1.MsgSend) (*MsgSend, error) {
+	return &MsgSend{
+		From:   types.AccountName(p.GetFrom()),
+		To:     types.AccountName(p.GetTo()),
+		Amount: protocodec.CoinFromProto(p.GetAmount()),
+	}, nil
+}
+
+func msgMultiSendToProto(m *MsgMultiSend) (*bankv1.MsgMultiSend, error) {
+	p := &bankv1.MsgMultiSend{
+		Inputs:  make([]*bankv1.Input, len(m.Inputs)),
+		Outputs: make([]*bankv1.Output, len(m.Outputs)),
+	}
+	for i, in := range m.Inputs {
+		p.Inputs[i] = &bankv1.Input{Address: string(in.Address), Coins: protocodec.CoinsToProto(in.Coins)}
+	}
+	for i, out := range m.Outputs {
+		p.Outputs[i] = &bankv1.Output{Address: string(out.Address), Coins: protocodec.CoinsToProto(out.Coins)}
+	}
+	return p, nil
+}
+
+func msgMultiSendFromProto(p *bankv1.MsgMultiSend) (*MsgMultiSend, error) {
+	m := &MsgMultiSend{
+		Inputs:  make([]Input, len(p.GetInputs())),
+		Outputs: make([]Output, len(p.GetOutputs())),
+	}
+	for i, in := range p.GetInputs() {
+		m.Inputs[i] = Input{Address: types.AccountName(in.GetAddress()), Coins: protocodec.CoinsFromProto(in.GetCoins())}
+	}
+	for i, out := range p.GetOutputs() {
+		m.Outputs[i] = Output{Address: types.AccountName(out.GetAddress()), Coins: protocodec.CoinsFromProto(out.GetCoins())}
+	}
+	return m, nil
+}
@@ -0,0 +1,47 @@
+package bank
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blockberries/punnet-sdk/protocodec"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+func TestRegisterProtoMessages(t *testing.T) {
+	r := protocodec.NewRegistry()
+	if err := RegisterProtoMessages(r); err != nil {
+		t.Fatalf("RegisterProtoMessages: %v", err)
+	}
+	if err := RegisterProtoMessages(r); err == nil {
+		t.Fatal("expected error for duplicate registration")
+	}
+
+	want := []string{TypeMsgMultiSend, TypeMsgSend}
+	if got := r.MessageTypes(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("MessageTypes() = %v, want %v", got, want)
+	}
+
+	msgs := []types.Message{
+		&MsgSend{From: "alice", To: "bob", Amount: types.NewCoin("token", 100)},
+		&MsgMultiSend{
+			Inputs:  []Input{{Address: "alice", Coins: types.NewCoins(types.NewCoin("token", 30))}},
+			Outputs: []Output{{Address: "bob", Coins: types.NewCoins(types.NewCoin("token", 10))}, {Address: "carol", Coins: types.NewCoins(types.NewCoin("token", 20))}},
+		},
+	}
+	for _, msg := range msgs {
+		t.Run(msg.Type(), func(t *testing.T) {
+			a, err := r.PackAny(msg)
+			if err != nil {
+				t.Fatalf("PackAny: %v", err)
+			}
+			got, err := r.UnpackAny(a)
+			if err != nil {
+				t.Fatalf("UnpackAny: %v", err)
+			}
+			if !reflect.DeepEqual(got, msg) {
+				t.Fatalf("round trip = %+v, want %+v", got, msg)
+			}
+		})
+	}
+}
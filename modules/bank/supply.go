@@ -0,0 +1,194 @@
+package bank
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// SupplyNamespace is the state namespace holding the tracked supply of each
+// denom ("module/bank.supply/<denom>", 8-byte big-endian). Like
+// ParamsNamespace it lives beside the bank namespace, whose every key is a
+// balance.
+const SupplyNamespace = ModuleName + ".supply"
+
+// SupplyStore tracks the total supply of each denom. It is set at genesis
+// and changed only by BankModule.Mint and BankModule.Burn; transfers
+// conserve it. The total supply invariant checks that it matches the sum of
+// the balances.
+type SupplyStore struct {
+	// store is the SupplyNamespace view of the state store
+	store store.BackingStore
+}
+
+// NewSupplyStore creates a supply store over the application state store
+func NewSupplyStore(stateStore store.BackingStore) (*SupplyStore, error) {
+	if stateStore == nil {
+		return nil, fmt.Errorf("state store cannot be nil")
+	}
+	return &SupplyStore{store: capability.ModuleStore(stateStore, SupplyNamespace)}, nil
+}
+
+// Get returns the tracked supply of denom (0 if untracked)
+func (s *SupplyStore) Get(denom string) (uint64, error) {
+	if s == nil {
+		return 0, fmt.Errorf("supply store is nil")
+	}
+
+	data, err := s.store.Get([]byte(denom))
+	if errors.Is(err, store.ErrNotFound) || (err == nil && data == nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read supply of %s: %w", denom, err)
+	}
+	if len(data) != 8 {
+		return 0, fmt.Errorf("corrupt supply of %s: %d bytes", denom, len(data))
+	}
+	return binary.BigEndian.Uint64(data), nil
+}
+
+// Set writes the supply of denom directly to the store, e.g. at genesis,
+// where it must equal the sum of the genesis balances of denom. After
+// genesis the supply changes through Mint and Burn.
+func (s *SupplyStore) Set(denom string, amount uint64) error {
+	if s == nil {
+		return fmt.Errorf("supply store is nil")
+	}
+	if !(types.Coin{Denom: denom, Amount: 1}).IsValid() {
+		return fmt.Errorf("%w: invalid denom %q", types.ErrInvalidCoin, denom)
+	}
+
+	if amount == 0 {
+		return s.store.Delete([]byte(denom))
+	}
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, amount)
+	return s.store.Set([]byte(denom), data)
+}
+
+// Supply returns the tracked supply of every denom, sorted by denom
+func (s *SupplyStore) Supply() (types.Coins, error) {
+	if s == nil {
+		return nil, fmt.Errorf("supply store is nil")
+	}
+
+	iter, err := s.store.Iterator(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate supply: %w", err)
+	}
+	defer iter.Close()
+
+	var supply types.Coins
+	for ; iter.Valid(); iter.Next() {
+		value := iter.Value()
+		if len(value) != 8 {
+			return nil, fmt.Errorf("corrupt supply of %s: %d bytes", iter.Key(), len(value))
+		}
+		supply = append(supply, types.NewCoin(string(iter.Key()), binary.BigEndian.Uint64(value)))
+	}
+	return supply.Sort(), nil
+}
+
+// Mint creates coins in account's balance and adds them to the tracked
+// supply. It writes state directly, so call it outside transaction
+// execution, e.g. at genesis or in an upgrade; requires WithSupplyStore.
+func (m *BankModule) Mint(ctx context.Context, account types.AccountName, coins types.Coins) error {
+	if m == nil || m.balanceCap == nil {
+		return fmt.Errorf("module or capability is nil")
+	}
+	if m.supplyStore == nil {
+		return fmt.Errorf("minting requires a supply store")
+	}
+	if !coins.IsValid() || !coins.IsAllPositive() {
+		return fmt.Errorf("%w: cannot mint %s", types.ErrInvalidCoin, coins)
+	}
+
+	// Check every denom before writing, so a failure mints nothing
+	supplies := make([]uint64, len(coins))
+	for i, coin := range coins {
+		supply, err := m.supplyStore.Get(coin.Denom)
+		if err != nil {
+			return err
+		}
+		if supply > math.MaxUint64-coin.Amount {
+			return fmt.Errorf("%w: supply of %s overflows", types.ErrInvalidCoin, coin.Denom)
+		}
+		supplies[i] = supply + coin.Amount
+	}
+
+	for i, coin := range coins {
+		if err := m.balanceCap.AddBalance(ctx, account, coin.Denom, coin.Amount); err != nil {
+			return fmt.Errorf("failed to mint %s: %w", coin, err)
+		}
+		if err := m.supplyStore.Set(coin.Denom, supplies[i]); err != nil {
+			return fmt.Errorf("failed to update supply of %s: %w", coin.Denom, err)
+		}
+	}
+	return nil
+}
+
+// Burn destroys coins from account's balance and removes them from the
+// tracked supply. Like Mint it writes state directly and requires
+// WithSupplyStore.
+func (m *BankModule) Burn(ctx context.Context, account types.AccountName, coins types.Coins) error {
+	if m == nil || m.balanceCap == nil {
+		return fmt.Errorf("module or capability is nil")
+	}
+	if m.supplyStore == nil {
+		return fmt.Errorf("burning requires a supply store")
+	}
+	if !coins.IsValid() || !coins.IsAllPositive() {
+		return fmt.Errorf("%w: cannot burn %s", types.ErrInvalidCoin, coins)
+	}
+
+	// Check every denom before writing, so a failure burns nothing
+	supplies := make([]uint64, len(coins))
+	for i, coin := range coins {
+		balance, err := m.balanceCap.GetBalance(ctx, account, coin.Denom)
+		if err != nil {
+			return fmt.Errorf("failed to get balance: %w", err)
+		}
+		if balance < coin.Amount {
+			return fmt.Errorf("%w: insufficient balance for %s", types.ErrInsufficientFunds, coin.Denom)
+		}
+		supply, err := m.supplyStore.Get(coin.Denom)
+		if err != nil {
+			return err
+		}
+		// The balance is part of the supply, so this means corrupt state
+		if supply < coin.Amount {
+			return fmt.Errorf("supply of %s is %d, less than the %d burned", coin.Denom, supply, coin.Amount)
+		}
+		supplies[i] = supply - coin.Amount
+	}
+
+	for i, coin := range coins {
+		if err := m.balanceCap.SubBalance(ctx, account, coin.Denom, coin.Amount); err != nil {
+			return fmt.Errorf("failed to burn %s: %w", coin, err)
+		}
+		if err := m.supplyStore.Set(coin.Denom, supplies[i]); err != nil {
+			return fmt.Errorf("failed to update supply of %s: %w", coin.Denom, err)
+		}
+	}
+	return nil
+}
+
+// supplyMismatch returns a description of the first denom, in denom order,
+// whose balances do not sum to its tracked supply, or "" if all match
+func supplyMismatch(balances, tracked types.Coins) string {
+	denoms := make(types.Coins, 0, len(balances)+len(tracked))
+	denoms = append(append(denoms, balances...), tracked...)
+	for _, coin := range denoms.Sort() {
+		if sum, supply := balances.AmountOf(coin.Denom), tracked.AmountOf(coin.Denom); sum != supply {
+			return fmt.Sprintf("balances of %s sum to %d, tracked supply is %d", coin.Denom, sum, supply)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,192 @@
+package bank
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// setupSupplyModule returns a bank module tracking its supply in a supply
+// store over the same fresh store as its balances
+func setupSupplyModule(t *testing.T) (*BankModule, capability.BalanceCapability, *SupplyStore) {
+	t.Helper()
+
+	memStore := store.NewMemoryStore()
+	capMgr := capability.NewCapabilityManager(memStore)
+	if err := capMgr.RegisterModule(ModuleName); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	balanceCap, err := capMgr.GrantBalanceCapability(ModuleName)
+	if err != nil {
+		t.Fatalf("failed to grant balance capability: %v", err)
+	}
+
+	supplyStore, err := NewSupplyStore(memStore)
+	if err != nil {
+		t.Fatalf("NewSupplyStore() error = %v", err)
+	}
+	bankMod, err := NewBankModule(balanceCap, WithSupplyStore(supplyStore))
+	if err != nil {
+		t.Fatalf("failed to create bank module: %v", err)
+	}
+	return bankMod, balanceCap, supplyStore
+}
+
+func TestSupplyStore_SetGet(t *testing.T) {
+	supplyStore, err := NewSupplyStore(store.NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewSupplyStore() error = %v", err)
+	}
+
+	if amount, err := supplyStore.Get("uatom"); err != nil || amount != 0 {
+		t.Fatalf("expected untracked supply 0, got %d, %v", amount, err)
+	}
+	if err := supplyStore.Set("uatom", 150); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := supplyStore.Set("stake", 7); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if amount, err := supplyStore.Get("uatom"); err != nil || amount != 150 {
+		t.Fatalf("expected supply 150, got %d, %v", amount, err)
+	}
+
+	supply, err := supplyStore.Supply()
+	if err != nil {
+		t.Fatalf("Supply() error = %v", err)
+	}
+	if supply.String() != types.NewCoins(types.NewCoin("stake", 7), types.NewCoin("uatom", 150)).String() {
+		t.Fatalf("unexpected supply %s", supply)
+	}
+
+	// A zero supply is untracked
+	if err := supplyStore.Set("stake", 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if supply, err := supplyStore.Supply(); err != nil || len(supply) != 1 {
+		t.Fatalf("expected only uatom, got %s, %v", supply, err)
+	}
+
+	if err := supplyStore.Set("", 1); !errors.Is(err, types.ErrInvalidCoin) {
+		t.Fatalf("expected ErrInvalidCoin for an empty denom, got %v", err)
+	}
+	if _, err := NewSupplyStore(nil); err == nil {
+		t.Fatal("expected nil state store to fail")
+	}
+}
+
+func TestBankModule_MintBurn(t *testing.T) {
+	bankMod, balanceCap, supplyStore := setupSupplyModule(t)
+	ctx := context.Background()
+
+	if err := bankMod.Mint(ctx, "alice", types.NewCoins(types.NewCoin("uatom", 100))); err != nil {
+		t.Fatalf("Mint() error = %v", err)
+	}
+	if err := bankMod.Mint(ctx, "bob", types.NewCoins(types.NewCoin("stake", 7), types.NewCoin("uatom", 50))); err != nil {
+		t.Fatalf("Mint() error = %v", err)
+	}
+	if err := bankMod.Burn(ctx, "alice", types.NewCoins(types.NewCoin("uatom", 30))); err != nil {
+		t.Fatalf("Burn() error = %v", err)
+	}
+
+	if balance, _ := balanceCap.GetBalance(ctx, "alice", "uatom"); balance != 70 {
+		t.Fatalf("expected alice to hold 70uatom, got %d", balance)
+	}
+	if amount, _ := supplyStore.Get("uatom"); amount != 120 {
+		t.Fatalf("expected uatom supply 120, got %d", amount)
+	}
+	if amount, _ := supplyStore.Get("stake"); amount != 7 {
+		t.Fatalf("expected stake supply 7, got %d", amount)
+	}
+
+	// Burning more than the balance burns nothing
+	err := bankMod.Burn(ctx, "bob", types.NewCoins(types.NewCoin("stake", 1), types.NewCoin("uatom", 51)))
+	if !errors.Is(err, types.ErrInsufficientFunds) {
+		t.Fatalf("expected ErrInsufficientFunds, got %v", err)
+	}
+	if balance, _ := balanceCap.GetBalance(ctx, "bob", "stake"); balance != 7 {
+		t.Fatalf("expected bob to keep 7stake, got %d", balance)
+	}
+
+	// Minting past the maximum supply mints nothing
+	err = bankMod.Mint(ctx, "carol", types.NewCoins(types.NewCoin("stake", 1), types.NewCoin("uatom", math.MaxUint64)))
+	if !errors.Is(err, types.ErrInvalidCoin) {
+		t.Fatalf("expected ErrInvalidCoin, got %v", err)
+	}
+	if amount, _ := supplyStore.Get("stake"); amount != 7 {
+		t.Fatalf("expected stake supply to stay 7, got %d", amount)
+	}
+
+	flushBalances(t, balanceCap)
+	if msg, broken := bankMod.totalSupplyInvariant(ctx); broken {
+		t.Fatalf("invariant unexpectedly broken: %s", msg)
+	}
+}
+
+func TestBankModule_MintRequiresSupplyStore(t *testing.T) {
+	bankMod, _ := setupTestBankModule(t)
+	coins := types.NewCoins(types.NewCoin("uatom", 1))
+
+	if err := bankMod.Mint(context.Background(), "alice", coins); err == nil {
+		t.Fatal("expected Mint without a supply store to fail")
+	}
+	if err := bankMod.Burn(context.Background(), "alice", coins); err == nil {
+		t.Fatal("expected Burn without a supply store to fail")
+	}
+}
+
+func TestTotalSupplyInvariant_TrackedSupply(t *testing.T) {
+	bankMod, balanceCap, supplyStore := setupSupplyModule(t)
+	ctx := context.Background()
+
+	// Genesis sets the balances and their supply directly
+	if err := balanceCap.SetBalance(ctx, "alice", "uatom", 100); err != nil {
+		t.Fatalf("failed to set balance: %v", err)
+	}
+	if err := balanceCap.SetBalance(ctx, "bob", "uatom", 50); err != nil {
+		t.Fatalf("failed to set balance: %v", err)
+	}
+	if err := supplyStore.Set("uatom", 150); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	flushBalances(t, balanceCap)
+
+	if msg, broken := bankMod.totalSupplyInvariant(ctx); broken {
+		t.Fatalf("invariant unexpectedly broken: %s", msg)
+	}
+
+	// A corrupted balance is valid and representable, but no longer sums
+	// to the tracked supply
+	if err := balanceCap.SetBalance(ctx, "bob", "uatom", 51); err != nil {
+		t.Fatalf("failed to set balance: %v", err)
+	}
+	flushBalances(t, balanceCap)
+
+	msg, broken := bankMod.totalSupplyInvariant(ctx)
+	if !broken {
+		t.Fatal("expected invariant to be broken")
+	}
+	if !strings.Contains(msg, "balances of uatom sum to 151, tracked supply is 150") {
+		t.Fatalf("unexpected message %q", msg)
+	}
+
+	// So does a balance in a denom that was never minted
+	if err := balanceCap.SetBalance(ctx, "bob", "uatom", 50); err != nil {
+		t.Fatalf("failed to set balance: %v", err)
+	}
+	if err := balanceCap.SetBalance(ctx, "carol", "stake", 1); err != nil {
+		t.Fatalf("failed to set balance: %v", err)
+	}
+	flushBalances(t, balanceCap)
+
+	msg, broken = bankMod.totalSupplyInvariant(ctx)
+	if !broken || !strings.Contains(msg, "balances of stake sum to 1, tracked supply is 0") {
+		t.Fatalf("expected the stake mismatch, got %q, %v", msg, broken)
+	}
+}
@@ -0,0 +1,99 @@
+package circuit
+
+import (
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Message type identifiers
+const (
+	TypeMsgTripCircuitBreaker  = "/punnet.circuit.v1.MsgTripCircuitBreaker"
+	TypeMsgResetCircuitBreaker = "/punnet.circuit.v1.MsgResetCircuitBreaker"
+)
+
+// MaxMsgTypes bounds the message types one message trips or resets
+const MaxMsgTypes = 100
+
+// MsgTripCircuitBreaker disables message types
+type MsgTripCircuitBreaker struct {
+	// Authority is the account allowed to trip the circuit breaker
+	Authority types.AccountName `json:"authority"`
+
+	// MsgTypes are the message types to disable
+	MsgTypes []string `json:"msg_types"`
+}
+
+// Type returns the message type
+func (m *MsgTripCircuitBreaker) Type() string {
+	return TypeMsgTripCircuitBreaker
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgTripCircuitBreaker) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+	return validateMsg(m.Authority, m.MsgTypes)
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgTripCircuitBreaker) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Authority}
+}
+
+// MsgResetCircuitBreaker re-enables message types
+type MsgResetCircuitBreaker struct {
+	// Authority is the account allowed to reset the circuit breaker
+	Authority types.AccountName `json:"authority"`
+
+	// MsgTypes are the message types to re-enable
+	MsgTypes []string `json:"msg_types"`
+}
+
+// Type returns the message type
+func (m *MsgResetCircuitBreaker) Type() string {
+	return TypeMsgResetCircuitBreaker
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgResetCircuitBreaker) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+	return validateMsg(m.Authority, m.MsgTypes)
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgResetCircuitBreaker) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Authority}
+}
+
+// validateMsg validates the fields shared by both messages
+func validateMsg(authority types.AccountName, msgTypes []string) error {
+	if !authority.IsValid() {
+		return fmt.Errorf("%w: invalid authority account %s", types.ErrInvalidAccount, authority)
+	}
+
+	if len(msgTypes) == 0 || len(msgTypes) > MaxMsgTypes {
+		return fmt.Errorf("must name 1 to %d message types, got %d", MaxMsgTypes, len(msgTypes))
+	}
+	seen := make(map[string]bool, len(msgTypes))
+	for _, msgType := range msgTypes {
+		if msgType == "" {
+			return fmt.Errorf("message type cannot be empty")
+		}
+		if seen[msgType] {
+			return fmt.Errorf("duplicate message type %s", msgType)
+		}
+		seen[msgType] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,282 @@
+// Package circuit provides a circuit breaker that disables message types.
+//
+// An authority account (typically governance, or an operator multisig for
+// emergencies) trips the breaker for message types with
+// MsgTripCircuitBreaker and resets it with MsgResetCircuitBreaker. The
+// module implements runtime.CircuitBreaker; set it as the application's
+// ApplicationConfig.CircuitBreaker so the router rejects disabled messages
+// before dispatch, in CheckTx, in blocks and in module-to-module dispatch.
+//
+// The breaker's own message types can never be disabled, so a tripped
+// breaker can always be reset.
+package circuit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/effects"
+	sdkerrors "github.com/blockberries/punnet-sdk/errors"
+	"github.com/blockberries/punnet-sdk/module"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Module name
+const ModuleName = "circuit"
+
+// ErrProtectedMsgType is returned when tripping the breaker for one of its
+// own message types
+var ErrProtectedMsgType = errors.New("message type cannot be disabled")
+
+// Circuit module error codes, in the ModuleName codespace.
+func init() {
+	sdkerrors.MustRegister(ModuleName, 2, ErrProtectedMsgType)
+}
+
+// Event types
+const (
+	EventTypeTripped = "circuit.tripped"
+	EventTypeReset   = "circuit.reset"
+)
+
+// disabledPrefix prefixes "<msg type>" in the module namespace, present
+// while the message type is disabled
+const disabledPrefix = "disabled/"
+
+// disabledKey returns the state key marking msgType disabled
+func disabledKey(msgType string) []byte {
+	return []byte(disabledPrefix + msgType)
+}
+
+// CircuitModule records disabled message types and implements
+// runtime.CircuitBreaker
+type CircuitModule struct {
+	// moduleStore is the "module/circuit/" view of the state store
+	moduleStore store.BackingStore
+
+	// disabledStore is the "disabled/" view of moduleStore
+	disabledStore store.BackingStore
+
+	// authority is the only account allowed to trip or reset the breaker
+	authority types.AccountName
+}
+
+var _ runtime.CircuitBreaker = (*CircuitModule)(nil)
+
+// NewCircuitModule creates a circuit module over its store capability,
+// controlled by authority
+func NewCircuitModule(storeCap capability.StoreCapability, authority types.AccountName) (*CircuitModule, error) {
+	if err := capability.CheckStoreCapability(storeCap, ModuleName); err != nil {
+		return nil, err
+	}
+
+	if !authority.IsValid() {
+		return nil, fmt.Errorf("%w: invalid authority account %s", types.ErrInvalidAccount, authority)
+	}
+
+	return &CircuitModule{
+		moduleStore:   storeCap,
+		disabledStore: store.NewPrefixStore(storeCap, []byte(disabledPrefix)),
+		authority:     authority,
+	}, nil
+}
+
+// CreateModule creates the circuit module using the module builder
+//
+// Usage:
+//
+//	storeCap, _ := capManager.GrantStoreCapability(circuit.ModuleName)
+//	circuitMod, _ := circuit.NewCircuitModule(storeCap, "gov")
+//	mod, _ := circuit.CreateModule(circuitMod)
+//	app, _ := runtime.NewApplication(runtime.ApplicationConfig{
+//		Modules:        append(modules, mod),
+//		CircuitBreaker: circuitMod,
+//		...
+//	})
+func CreateModule(circuitMod *CircuitModule) (module.Module, error) {
+	if circuitMod == nil {
+		return nil, fmt.Errorf("circuit module cannot be nil")
+	}
+
+	return module.NewModuleBuilder(ModuleName).
+		WithMsgHandler(TypeMsgTripCircuitBreaker, circuitMod.handleTrip).
+		WithMsgHandler(TypeMsgResetCircuitBreaker, circuitMod.handleReset).
+		WithQueryHandler("/disabled", circuitMod.handleQueryDisabled).
+		WithQueryHandler("/authority", circuitMod.handleQueryAuthority).
+		Build()
+}
+
+// Authority returns the account allowed to trip and reset the breaker
+func (m *CircuitModule) Authority() types.AccountName {
+	if m == nil {
+		return ""
+	}
+	return m.authority
+}
+
+// IsProtected reports whether msgType is one of the breaker's own message
+// types, which cannot be disabled
+func IsProtected(msgType string) bool {
+	return msgType == TypeMsgTripCircuitBreaker || msgType == TypeMsgResetCircuitBreaker
+}
+
+// IsDisabled reports whether msgType is disabled
+func (m *CircuitModule) IsDisabled(msgType string) (bool, error) {
+	if m == nil {
+		return false, fmt.Errorf("circuit module is nil")
+	}
+	if IsProtected(msgType) {
+		return false, nil
+	}
+
+	data, err := m.moduleStore.Get(disabledKey(msgType))
+	if errors.Is(err, store.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read circuit state: %w", err)
+	}
+	return data != nil, nil
+}
+
+// IsAllowed implements runtime.CircuitBreaker
+func (m *CircuitModule) IsAllowed(ctx *runtime.Context, msgType string) (bool, error) {
+	disabled, err := m.IsDisabled(msgType)
+	if err != nil {
+		return false, err
+	}
+	return !disabled, nil
+}
+
+// Disabled returns the disabled message types in sorted order
+func (m *CircuitModule) Disabled() ([]string, error) {
+	if m == nil {
+		return nil, fmt.Errorf("circuit module is nil")
+	}
+
+	iter, err := m.disabledStore.Iterator(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate disabled message types: %w", err)
+	}
+	defer iter.Close()
+
+	disabled := make([]string, 0)
+	for ; iter.Valid(); iter.Next() {
+		disabled = append(disabled, string(iter.Key()))
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to iterate disabled message types: %w", err)
+	}
+	return disabled, nil
+}
+
+// checkAuthority verifies that the message is from the authority and signed
+// by the transaction account
+func (m *CircuitModule) checkAuthority(ctx *runtime.Context, authority types.AccountName) error {
+	if authority != m.authority {
+		return fmt.Errorf("%w: only %s may trip or reset the circuit breaker", types.ErrUnauthorized, m.authority)
+	}
+	if authority != ctx.Account() {
+		return fmt.Errorf("%w: %s must be transaction account", types.ErrUnauthorized, authority)
+	}
+	return nil
+}
+
+// handleTrip handles MsgTripCircuitBreaker
+func (m *CircuitModule) handleTrip(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("circuit module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	tripMsg, ok := msg.(*MsgTripCircuitBreaker)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgTripCircuitBreaker")
+	}
+
+	if err := m.checkAuthority(ctx, tripMsg.Authority); err != nil {
+		return nil, err
+	}
+
+	effs := make([]effects.Effect, 0, len(tripMsg.MsgTypes)+1)
+	for _, msgType := range tripMsg.MsgTypes {
+		if IsProtected(msgType) {
+			return nil, fmt.Errorf("%w: %s", ErrProtectedMsgType, msgType)
+		}
+		effs = append(effs, effects.NewStateWriteEffect(ModuleName, disabledKey(msgType), []byte{1}))
+	}
+	effs = append(effs, effects.NewEventEffect(EventTypeTripped, map[string][]byte{
+		"authority": []byte(tripMsg.Authority),
+		"msg_types": []byte(strings.Join(sortedCopy(tripMsg.MsgTypes), ",")),
+	}))
+	return effs, nil
+}
+
+// handleReset handles MsgResetCircuitBreaker. Resetting a message type that
+// is not disabled is a no-op.
+func (m *CircuitModule) handleReset(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("circuit module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	resetMsg, ok := msg.(*MsgResetCircuitBreaker)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgResetCircuitBreaker")
+	}
+
+	if err := m.checkAuthority(ctx, resetMsg.Authority); err != nil {
+		return nil, err
+	}
+
+	effs := make([]effects.Effect, 0, len(resetMsg.MsgTypes)+1)
+	for _, msgType := range resetMsg.MsgTypes {
+		disabled, err := m.IsDisabled(msgType)
+		if err != nil {
+			return nil, err
+		}
+		if disabled {
+			effs = append(effs, effects.NewStateDeleteEffect(ModuleName, disabledKey(msgType)))
+		}
+	}
+	effs = append(effs, effects.NewEventEffect(EventTypeReset, map[string][]byte{
+		"authority": []byte(resetMsg.Authority),
+		"msg_types": []byte(strings.Join(sortedCopy(resetMsg.MsgTypes), ",")),
+	}))
+	return effs, nil
+}
+
+// handleQueryDisabled returns the disabled message types as a JSON array
+func (m *CircuitModule) handleQueryDisabled(ctx context.Context, path string, data []byte) ([]byte, error) {
+	disabled, err := m.Disabled()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(disabled)
+}
+
+// handleQueryAuthority returns the authority account as JSON
+func (m *CircuitModule) handleQueryAuthority(ctx context.Context, path string, data []byte) ([]byte, error) {
+	if m == nil {
+		return nil, fmt.Errorf("circuit module is nil")
+	}
+	return json.Marshal(m.authority)
+}
+
+// sortedCopy returns a sorted copy of s
+func sortedCopy(s []string) []string {
+	sorted := append([]string(nil), s...)
+	sort.Strings(sorted)
+	return sorted
+}
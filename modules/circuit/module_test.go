@@ -0,0 +1,278 @@
+package circuit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/module"
+	"github.com/blockberries/punnet-sdk/runtime"
+	punnettesting "github.com/blockberries/punnet-sdk/testing"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+const testMsgType = "/punnet.bank.v1.MsgSend"
+
+// testMessage is a message of any type
+type testMessage struct {
+	msgType string
+	signer  types.AccountName
+}
+
+func (m *testMessage) Type() string                    { return m.msgType }
+func (m *testMessage) ValidateBasic() error            { return nil }
+func (m *testMessage) GetSigners() []types.AccountName { return []types.AccountName{m.signer} }
+
+type testEnv struct {
+	*punnettesting.EffectEnv
+	mod *CircuitModule
+}
+
+func setupTestCircuitModule(t *testing.T) *testEnv {
+	t.Helper()
+
+	env := punnettesting.NewEffectEnv(t)
+	circuitMod, err := NewCircuitModule(env.GrantStore(t, ModuleName), "gov")
+	if err != nil {
+		t.Fatalf("failed to create circuit module: %v", err)
+	}
+	return &testEnv{EffectEnv: env, mod: circuitMod}
+}
+
+func setupTestContext(t *testing.T, account types.AccountName) *runtime.Context {
+	t.Helper()
+
+	header := runtime.NewBlockHeader(1, time.Now(), "test-chain", []byte("proposer"))
+	ctx, err := runtime.NewContext(context.Background(), header, account)
+	if err != nil {
+		t.Fatalf("failed to create context: %v", err)
+	}
+	return ctx
+}
+
+// run validates and handles msg as account and applies its effects
+func (env *testEnv) run(t *testing.T, account types.AccountName, msg types.Message) error {
+	t.Helper()
+
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+	var (
+		effs []effects.Effect
+		err  error
+	)
+	ctx := setupTestContext(t, account)
+	switch msg.(type) {
+	case *MsgTripCircuitBreaker:
+		effs, err = env.mod.handleTrip(ctx, msg)
+	case *MsgResetCircuitBreaker:
+		effs, err = env.mod.handleReset(ctx, msg)
+	default:
+		t.Fatalf("unexpected message %T", msg)
+	}
+	if err != nil {
+		return err
+	}
+	env.Apply(t, ctx, effs)
+	return nil
+}
+
+func (env *testEnv) disabled(t *testing.T) []string {
+	t.Helper()
+
+	data, err := env.mod.handleQueryDisabled(context.Background(), "/disabled", nil)
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	var disabled []string
+	if err := json.Unmarshal(data, &disabled); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return disabled
+}
+
+func TestTripAndReset(t *testing.T) {
+	env := setupTestCircuitModule(t)
+	ctx := setupTestContext(t, "alice")
+
+	if allowed, err := env.mod.IsAllowed(ctx, testMsgType); err != nil || !allowed {
+		t.Fatalf("expected %s allowed before tripping, got %v, %v", testMsgType, allowed, err)
+	}
+
+	trip := &MsgTripCircuitBreaker{Authority: "gov", MsgTypes: []string{testMsgType, "/punnet.nft.v1.MsgMint"}}
+	if err := env.run(t, "gov", trip); err != nil {
+		t.Fatalf("trip failed: %v", err)
+	}
+	if allowed, err := env.mod.IsAllowed(ctx, testMsgType); err != nil || allowed {
+		t.Fatalf("expected %s disabled, got %v, %v", testMsgType, allowed, err)
+	}
+	if got := env.disabled(t); !slices.Equal(got, []string{"/punnet.bank.v1.MsgSend", "/punnet.nft.v1.MsgMint"}) {
+		t.Fatalf("unexpected disabled message types: %v", got)
+	}
+
+	// Tripping again is idempotent
+	if err := env.run(t, "gov", trip); err != nil {
+		t.Fatalf("second trip failed: %v", err)
+	}
+
+	reset := &MsgResetCircuitBreaker{Authority: "gov", MsgTypes: []string{testMsgType, "/never/disabled"}}
+	if err := env.run(t, "gov", reset); err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+	if allowed, err := env.mod.IsAllowed(ctx, testMsgType); err != nil || !allowed {
+		t.Fatalf("expected %s allowed after reset, got %v, %v", testMsgType, allowed, err)
+	}
+	if got := env.disabled(t); !slices.Equal(got, []string{"/punnet.nft.v1.MsgMint"}) {
+		t.Fatalf("unexpected disabled message types: %v", got)
+	}
+}
+
+func TestTrip_Rejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		account types.AccountName
+		msg     types.Message
+		wantErr error
+	}{
+		{
+			name:    "not the authority",
+			account: "mallory",
+			msg:     &MsgTripCircuitBreaker{Authority: "mallory", MsgTypes: []string{testMsgType}},
+			wantErr: types.ErrUnauthorized,
+		},
+		{
+			name:    "authority is not the transaction account",
+			account: "mallory",
+			msg:     &MsgResetCircuitBreaker{Authority: "gov", MsgTypes: []string{testMsgType}},
+			wantErr: types.ErrUnauthorized,
+		},
+		{
+			name:    "own message type",
+			account: "gov",
+			msg:     &MsgTripCircuitBreaker{Authority: "gov", MsgTypes: []string{testMsgType, TypeMsgResetCircuitBreaker}},
+			wantErr: ErrProtectedMsgType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := setupTestCircuitModule(t)
+			if err := env.run(t, tt.account, tt.msg); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if got := env.disabled(t); len(got) != 0 {
+				t.Fatalf("expected nothing disabled, got %v", got)
+			}
+		})
+	}
+}
+
+func TestMsgValidateBasic(t *testing.T) {
+	tooMany := make([]string, MaxMsgTypes+1)
+	for i := range tooMany {
+		tooMany[i] = "/type/" + string(rune('a'+i%26)) + string(rune('a'+i/26))
+	}
+
+	tests := []struct {
+		name     string
+		msgTypes []string
+		valid    bool
+	}{
+		{name: "valid", msgTypes: []string{testMsgType}, valid: true},
+		{name: "none", msgTypes: nil},
+		{name: "too many", msgTypes: tooMany},
+		{name: "empty type", msgTypes: []string{""}},
+		{name: "duplicate type", msgTypes: []string{testMsgType, testMsgType}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, msg := range []types.Message{
+				&MsgTripCircuitBreaker{Authority: "gov", MsgTypes: tt.msgTypes},
+				&MsgResetCircuitBreaker{Authority: "gov", MsgTypes: tt.msgTypes},
+			} {
+				if err := msg.ValidateBasic(); (err == nil) != tt.valid {
+					t.Errorf("%s ValidateBasic() error = %v, want valid %v", msg.Type(), err, tt.valid)
+				}
+			}
+		})
+	}
+
+	if err := (&MsgTripCircuitBreaker{Authority: "", MsgTypes: []string{testMsgType}}).ValidateBasic(); !errors.Is(err, types.ErrInvalidAccount) {
+		t.Errorf("expected ErrInvalidAccount, got %v", err)
+	}
+}
+
+func TestRouterIntegration(t *testing.T) {
+	env := setupTestCircuitModule(t)
+	mod, err := CreateModule(env.mod)
+	if err != nil {
+		t.Fatalf("CreateModule() error = %v", err)
+	}
+
+	bankCalls := 0
+	bank, err := module.NewModuleBuilder("bank").
+		WithMsgHandler(testMsgType, func(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+			bankCalls++
+			return nil, nil
+		}).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build bank module: %v", err)
+	}
+
+	router := runtime.NewRouter()
+	for _, m := range []module.Module{mod, bank} {
+		if err := router.RegisterModule(m); err != nil {
+			t.Fatalf("RegisterModule() error = %v", err)
+		}
+	}
+	router.SetCircuitBreaker(env.mod)
+
+	// The breaker's own messages cannot be disabled, even by writing state
+	if err := env.Store().Set(append([]byte("module/circuit/"), disabledKey(TypeMsgResetCircuitBreaker)...), []byte{1}); err != nil {
+		t.Fatalf("failed to write state: %v", err)
+	}
+	reset := &MsgResetCircuitBreaker{Authority: "gov", MsgTypes: []string{TypeMsgResetCircuitBreaker}}
+	if _, err := router.RouteMsg(setupTestContext(t, "gov"), reset); err != nil {
+		t.Fatalf("expected reset to be routed, got %v", err)
+	}
+
+	// Tripped message types are rejected before dispatch
+	if err := env.run(t, "gov", &MsgTripCircuitBreaker{Authority: "gov", MsgTypes: []string{testMsgType}}); err != nil {
+		t.Fatalf("trip failed: %v", err)
+	}
+	send := &testMessage{msgType: testMsgType, signer: "alice"}
+	if _, err := router.RouteMsg(setupTestContext(t, "alice"), send); !errors.Is(err, runtime.ErrCircuitBreakerTripped) {
+		t.Fatalf("expected ErrCircuitBreakerTripped, got %v", err)
+	}
+	if bankCalls != 0 {
+		t.Fatalf("expected the handler not to run, got %d calls", bankCalls)
+	}
+
+	if err := env.run(t, "gov", &MsgResetCircuitBreaker{Authority: "gov", MsgTypes: []string{testMsgType}}); err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+	if _, err := router.RouteMsg(setupTestContext(t, "alice"), send); err != nil {
+		t.Fatalf("expected message routed after reset, got %v", err)
+	}
+	if bankCalls != 1 {
+		t.Fatalf("expected one handler call, got %d", bankCalls)
+	}
+}
+
+func TestNewCircuitModule(t *testing.T) {
+	if _, err := NewCircuitModule(nil, "gov"); err == nil {
+		t.Error("expected error for nil store capability")
+	}
+	if _, err := NewCircuitModule(punnettesting.NewEffectEnv(t).GrantStore(t, ModuleName), ""); !errors.Is(err, types.ErrInvalidAccount) {
+		t.Errorf("expected ErrInvalidAccount, got %v", err)
+	}
+	if _, err := CreateModule(nil); err == nil {
+		t.Error("expected error for nil module")
+	}
+}
@@ -0,0 +1,47 @@
+package crisis
+
+import (
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Message type identifiers
+const (
+	TypeMsgVerifyInvariant = "/punnet.crisis.v1.MsgVerifyInvariant"
+)
+
+// MsgVerifyInvariant runs one invariant, or all invariants if InvariantRoute is empty
+type MsgVerifyInvariant struct {
+	// Sender is the account requesting the check
+	Sender types.AccountName `json:"sender"`
+
+	// InvariantRoute is the route of the invariant to check ("<module>/<name>")
+	InvariantRoute string `json:"invariant_route,omitempty"`
+}
+
+// Type returns the message type
+func (m *MsgVerifyInvariant) Type() string {
+	return TypeMsgVerifyInvariant
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgVerifyInvariant) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Sender.IsValid() {
+		return fmt.Errorf("%w: invalid sender account %s", types.ErrInvalidAccount, m.Sender)
+	}
+
+	return nil
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgVerifyInvariant) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Sender}
+}
@@ -0,0 +1,270 @@
+// Package crisis runs module invariants and halts the chain (or reports)
+// when one is broken.
+//
+// Modules contribute invariants with module.ModuleBuilder.WithInvariant; the
+// application collects them in a module.InvariantRegistry. Invariants run
+// when an account sends MsgVerifyInvariant, every CheckPeriod blocks in
+// EndBlock, or when the operator calls AssertInvariants (e.g. at startup).
+package crisis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/module"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Module name
+const ModuleName = "crisis"
+
+// DefaultInvariantGas is the gas charged per invariant run by MsgVerifyInvariant
+const DefaultInvariantGas = uint64(100_000)
+
+// EventTypeInvariantBroken is emitted for each violation found by MsgVerifyInvariant
+const EventTypeInvariantBroken = "invariant_broken"
+
+// CrisisModule checks invariants and halts the chain on violation.
+//
+// INVARIANT: Once a violation is recorded in halt mode, every BeginBlock
+// fails until the process restarts with fixed state or code.
+type CrisisModule struct {
+	mu sync.RWMutex
+
+	registry *module.InvariantRegistry
+
+	// reportOnly disables halting; violations are only reported
+	reportOnly bool
+
+	// checkPeriod runs all invariants every checkPeriod blocks (0 disables)
+	checkPeriod uint64
+
+	// invariantGas is charged per invariant run by MsgVerifyInvariant
+	invariantGas uint64
+
+	// violations are the violations that halted the chain
+	violations []module.Violation
+}
+
+// Option configures a CrisisModule
+type Option func(*CrisisModule)
+
+// WithReportOnly reports violations (events, tx results, logs) without halting
+func WithReportOnly() Option {
+	return func(m *CrisisModule) {
+		m.reportOnly = true
+	}
+}
+
+// WithCheckPeriod runs all invariants in EndBlock every period blocks.
+// Place the crisis module last in the EndBlock order so it sees the block's
+// final state.
+func WithCheckPeriod(period uint64) Option {
+	return func(m *CrisisModule) {
+		m.checkPeriod = period
+	}
+}
+
+// WithInvariantGas sets the gas charged per invariant run by MsgVerifyInvariant
+func WithInvariantGas(gas uint64) Option {
+	return func(m *CrisisModule) {
+		m.invariantGas = gas
+	}
+}
+
+// NewCrisisModule creates a crisis module checking the invariants in registry
+func NewCrisisModule(registry *module.InvariantRegistry, opts ...Option) (*CrisisModule, error) {
+	if registry == nil {
+		return nil, fmt.Errorf("invariant registry cannot be nil")
+	}
+
+	m := &CrisisModule{
+		registry:     registry,
+		invariantGas: DefaultInvariantGas,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m, nil
+}
+
+// CreateModule creates the crisis module using the module builder
+//
+// Usage:
+//
+//	registry := module.NewInvariantRegistry()
+//	registry.RegisterModules(modules)
+//	crisisMod, _ := crisis.NewCrisisModule(registry, crisis.WithCheckPeriod(1000))
+//	mod, _ := crisis.CreateModule(crisisMod)
+func CreateModule(crisisMod *CrisisModule) (module.Module, error) {
+	if crisisMod == nil {
+		return nil, fmt.Errorf("crisis module cannot be nil")
+	}
+
+	return module.NewModuleBuilder(ModuleName).
+		WithMsgHandler(TypeMsgVerifyInvariant, crisisMod.handleVerifyInvariant).
+		WithQueryHandler("/routes", crisisMod.handleQueryRoutes).
+		WithQueryHandler("/violations", crisisMod.handleQueryViolations).
+		WithBeginBlocker(crisisMod.beginBlock).
+		WithEndBlocker(crisisMod.endBlock).
+		Build()
+}
+
+// AssertInvariants runs all invariants. In halt mode a violation is recorded
+// and halts the chain at the next BeginBlock. Returns ErrInvariantBroken
+// (wrapped) listing every violation.
+func (m *CrisisModule) AssertInvariants(ctx context.Context) error {
+	if m == nil {
+		return fmt.Errorf("crisis module is nil")
+	}
+
+	violations, err := m.registry.CheckAll(ctx)
+	if err != nil {
+		return err
+	}
+	m.record(violations)
+	return module.ViolationsError(violations)
+}
+
+// Halted reports whether a violation has halted the chain
+func (m *CrisisModule) Halted() bool {
+	return len(m.Violations()) > 0
+}
+
+// Violations returns the violations that halted the chain
+func (m *CrisisModule) Violations() []module.Violation {
+	if m == nil {
+		return nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]module.Violation, len(m.violations))
+	copy(result, m.violations)
+	return result
+}
+
+// record stores violations that halt the chain
+func (m *CrisisModule) record(violations []module.Violation) {
+	if m.reportOnly || len(violations) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.violations = append(m.violations, violations...)
+}
+
+// handleVerifyInvariant handles MsgVerifyInvariant
+func (m *CrisisModule) handleVerifyInvariant(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("crisis module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	verifyMsg, ok := msg.(*MsgVerifyInvariant)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgVerifyInvariant")
+	}
+
+	if verifyMsg.Sender != ctx.Account() {
+		return nil, fmt.Errorf("sender must be transaction account")
+	}
+
+	routes := m.registry.Routes()
+	if verifyMsg.InvariantRoute != "" {
+		if !containsRoute(routes, verifyMsg.InvariantRoute) {
+			return nil, fmt.Errorf("%w: %s", module.ErrUnknownInvariant, verifyMsg.InvariantRoute)
+		}
+		routes = []string{verifyMsg.InvariantRoute}
+	}
+
+	// Invariants can be expensive; CheckTx only validates the request
+	if ctx.IsReadOnly() {
+		return nil, nil
+	}
+
+	if err := ctx.ConsumeGas(m.invariantGas * uint64(len(routes))); err != nil {
+		return nil, err
+	}
+
+	violations := make([]module.Violation, 0)
+	for _, route := range routes {
+		violation, err := m.registry.Check(ctx.Context(), route)
+		if err != nil {
+			return nil, err
+		}
+		if violation != nil {
+			violations = append(violations, *violation)
+		}
+	}
+
+	for _, v := range violations {
+		event := types.NewEvent(EventTypeInvariantBroken)
+		event.AddAttribute("route", []byte(v.Route))
+		event.AddAttribute("message", []byte(v.Message))
+		ctx.EventManager().EmitEvent(event)
+	}
+
+	if m.reportOnly {
+		return nil, nil
+	}
+
+	m.record(violations)
+	return nil, module.ViolationsError(violations)
+}
+
+// beginBlock halts the chain once a violation has been recorded
+func (m *CrisisModule) beginBlock(ctx *runtime.Context) ([]effects.Effect, error) {
+	violations := m.Violations()
+	if len(violations) == 0 {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("halting at height %d: %w", ctx.BlockHeight(), module.ViolationsError(violations))
+}
+
+// endBlock runs all invariants every checkPeriod blocks
+func (m *CrisisModule) endBlock(ctx *runtime.Context) ([]effects.Effect, []types.ValidatorUpdate, error) {
+	if m.checkPeriod == 0 || ctx.BlockHeight()%m.checkPeriod != 0 {
+		return nil, nil, nil
+	}
+
+	err := m.AssertInvariants(ctx.Context())
+	if err != nil && !m.reportOnly {
+		return nil, nil, fmt.Errorf("halting at height %d: %w", ctx.BlockHeight(), err)
+	}
+	return nil, nil, nil
+}
+
+// handleQueryRoutes returns the registered invariant routes as JSON
+func (m *CrisisModule) handleQueryRoutes(ctx context.Context, path string, data []byte) ([]byte, error) {
+	if m == nil {
+		return nil, fmt.Errorf("crisis module is nil")
+	}
+	return json.Marshal(m.registry.Routes())
+}
+
+// handleQueryViolations returns the violations that halted the chain as JSON
+func (m *CrisisModule) handleQueryViolations(ctx context.Context, path string, data []byte) ([]byte, error) {
+	if m == nil {
+		return nil, fmt.Errorf("crisis module is nil")
+	}
+	return json.Marshal(m.Violations())
+}
+
+// containsRoute reports whether routes contains route
+func containsRoute(routes []string, route string) bool {
+	for _, r := range routes {
+		if r == route {
+			return true
+		}
+	}
+	return false
+}
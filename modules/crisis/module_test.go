@@ -0,0 +1,228 @@
+package crisis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/module"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// toggleInvariant is an invariant whose result the test controls
+type toggleInvariant struct {
+	broken bool
+	runs   int
+}
+
+func (ti *toggleInvariant) check(context.Context) (string, bool) {
+	ti.runs++
+	return "supply mismatch", ti.broken
+}
+
+func setupTestCrisisModule(t *testing.T, opts ...Option) (*CrisisModule, *toggleInvariant) {
+	t.Helper()
+
+	inv := &toggleInvariant{}
+	registry := module.NewInvariantRegistry()
+	if err := registry.Register("bank", "total-supply", inv.check); err != nil {
+		t.Fatalf("failed to register invariant: %v", err)
+	}
+	if err := registry.Register("auth", "accounts", func(context.Context) (string, bool) { return "ok", false }); err != nil {
+		t.Fatalf("failed to register invariant: %v", err)
+	}
+
+	crisisMod, err := NewCrisisModule(registry, opts...)
+	if err != nil {
+		t.Fatalf("failed to create crisis module: %v", err)
+	}
+	return crisisMod, inv
+}
+
+func setupTestContext(t *testing.T, height uint64, account types.AccountName, readOnly bool) *runtime.Context {
+	t.Helper()
+
+	header := runtime.NewBlockHeader(height, time.Now(), "test-chain", []byte("proposer"))
+	newCtx := runtime.NewContext
+	if readOnly {
+		newCtx = runtime.NewReadOnlyContext
+	}
+	ctx, err := newCtx(context.Background(), header, account)
+	if err != nil {
+		t.Fatalf("failed to create context: %v", err)
+	}
+	return ctx
+}
+
+func TestNewCrisisModule(t *testing.T) {
+	if _, err := NewCrisisModule(nil); err == nil {
+		t.Fatal("expected error for nil registry")
+	}
+
+	crisisMod, _ := setupTestCrisisModule(t)
+	mod, err := CreateModule(crisisMod)
+	if err != nil {
+		t.Fatalf("CreateModule failed: %v", err)
+	}
+	if mod.Name() != ModuleName {
+		t.Fatalf("expected module name %s, got %s", ModuleName, mod.Name())
+	}
+}
+
+func TestMsgVerifyInvariant_ValidateBasic(t *testing.T) {
+	if err := (&MsgVerifyInvariant{Sender: "alice"}).ValidateBasic(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := (&MsgVerifyInvariant{}).ValidateBasic(); !errors.Is(err, types.ErrInvalidAccount) {
+		t.Fatalf("expected ErrInvalidAccount, got %v", err)
+	}
+	var nilMsg *MsgVerifyInvariant
+	if err := nilMsg.ValidateBasic(); err == nil {
+		t.Fatal("expected error for nil message")
+	}
+}
+
+func TestHandleVerifyInvariant(t *testing.T) {
+	t.Run("holding invariants", func(t *testing.T) {
+		crisisMod, inv := setupTestCrisisModule(t)
+		ctx := setupTestContext(t, 1, "alice", false)
+
+		if _, err := crisisMod.handleVerifyInvariant(ctx, &MsgVerifyInvariant{Sender: "alice"}); err != nil {
+			t.Fatalf("handleVerifyInvariant failed: %v", err)
+		}
+		if inv.runs != 1 {
+			t.Fatalf("expected invariant to run once, ran %d times", inv.runs)
+		}
+		if ctx.GasUsed() != 2*DefaultInvariantGas {
+			t.Fatalf("expected gas %d, got %d", 2*DefaultInvariantGas, ctx.GasUsed())
+		}
+	})
+
+	t.Run("unknown route", func(t *testing.T) {
+		crisisMod, _ := setupTestCrisisModule(t)
+		ctx := setupTestContext(t, 1, "alice", false)
+		_, err := crisisMod.handleVerifyInvariant(ctx, &MsgVerifyInvariant{Sender: "alice", InvariantRoute: "bank/unknown"})
+		if !errors.Is(err, module.ErrUnknownInvariant) {
+			t.Fatalf("expected ErrUnknownInvariant, got %v", err)
+		}
+	})
+
+	t.Run("sender must be transaction account", func(t *testing.T) {
+		crisisMod, _ := setupTestCrisisModule(t)
+		ctx := setupTestContext(t, 1, "bob", false)
+		if _, err := crisisMod.handleVerifyInvariant(ctx, &MsgVerifyInvariant{Sender: "alice"}); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("read-only context does not run invariants", func(t *testing.T) {
+		crisisMod, inv := setupTestCrisisModule(t)
+		inv.broken = true
+		ctx := setupTestContext(t, 1, "alice", true)
+		if _, err := crisisMod.handleVerifyInvariant(ctx, &MsgVerifyInvariant{Sender: "alice"}); err != nil {
+			t.Fatalf("handleVerifyInvariant failed: %v", err)
+		}
+		if inv.runs != 0 {
+			t.Fatalf("invariant ran in read-only context")
+		}
+	})
+
+	t.Run("violation halts", func(t *testing.T) {
+		crisisMod, inv := setupTestCrisisModule(t)
+		inv.broken = true
+		ctx := setupTestContext(t, 1, "alice", false)
+
+		_, err := crisisMod.handleVerifyInvariant(ctx, &MsgVerifyInvariant{Sender: "alice", InvariantRoute: "bank/total-supply"})
+		if !errors.Is(err, module.ErrInvariantBroken) {
+			t.Fatalf("expected ErrInvariantBroken, got %v", err)
+		}
+		if events := ctx.EventManager().Events(); len(events) != 1 || events[0].Type != EventTypeInvariantBroken {
+			t.Fatalf("unexpected events %+v", events)
+		}
+		if !crisisMod.Halted() {
+			t.Fatal("expected module to be halted")
+		}
+
+		_, err = crisisMod.beginBlock(setupTestContext(t, 2, "system", false))
+		if !errors.Is(err, module.ErrInvariantBroken) {
+			t.Fatalf("expected BeginBlock to halt, got %v", err)
+		}
+	})
+
+	t.Run("violation reported only", func(t *testing.T) {
+		crisisMod, inv := setupTestCrisisModule(t, WithReportOnly())
+		inv.broken = true
+		ctx := setupTestContext(t, 1, "alice", false)
+
+		if _, err := crisisMod.handleVerifyInvariant(ctx, &MsgVerifyInvariant{Sender: "alice"}); err != nil {
+			t.Fatalf("handleVerifyInvariant failed: %v", err)
+		}
+		if len(ctx.EventManager().Events()) != 1 {
+			t.Fatal("expected violation event")
+		}
+		if crisisMod.Halted() {
+			t.Fatal("report-only module must not halt")
+		}
+	})
+}
+
+func TestEndBlock_CheckPeriod(t *testing.T) {
+	crisisMod, inv := setupTestCrisisModule(t, WithCheckPeriod(10))
+
+	if _, _, err := crisisMod.endBlock(setupTestContext(t, 5, "system", false)); err != nil {
+		t.Fatalf("endBlock failed: %v", err)
+	}
+	if inv.runs != 0 {
+		t.Fatal("invariants ran outside the check period")
+	}
+
+	if _, _, err := crisisMod.endBlock(setupTestContext(t, 10, "system", false)); err != nil {
+		t.Fatalf("endBlock failed: %v", err)
+	}
+	if inv.runs != 1 {
+		t.Fatalf("expected invariants to run at height 10, ran %d times", inv.runs)
+	}
+
+	inv.broken = true
+	_, _, err := crisisMod.endBlock(setupTestContext(t, 20, "system", false))
+	if !errors.Is(err, module.ErrInvariantBroken) {
+		t.Fatalf("expected ErrInvariantBroken, got %v", err)
+	}
+}
+
+func TestQueries(t *testing.T) {
+	crisisMod, inv := setupTestCrisisModule(t)
+	ctx := context.Background()
+
+	data, err := crisisMod.handleQueryRoutes(ctx, "/routes", nil)
+	if err != nil {
+		t.Fatalf("routes query failed: %v", err)
+	}
+	var routes []string
+	if err := json.Unmarshal(data, &routes); err != nil {
+		t.Fatalf("failed to decode routes: %v", err)
+	}
+	if len(routes) != 2 || routes[0] != "auth/accounts" || routes[1] != "bank/total-supply" {
+		t.Fatalf("unexpected routes %v", routes)
+	}
+
+	inv.broken = true
+	if err := crisisMod.AssertInvariants(ctx); !errors.Is(err, module.ErrInvariantBroken) {
+		t.Fatalf("expected ErrInvariantBroken, got %v", err)
+	}
+
+	data, err = crisisMod.handleQueryViolations(ctx, "/violations", nil)
+	if err != nil {
+		t.Fatalf("violations query failed: %v", err)
+	}
+	var violations []module.Violation
+	if err := json.Unmarshal(data, &violations); err != nil {
+		t.Fatalf("failed to decode violations: %v", err)
+	}
+	if len(violations) != 1 || violations[0].Route != "bank/total-supply" {
+		t.Fatalf("unexpected violations %+v", violations)
+	}
+}
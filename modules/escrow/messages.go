@@ -0,0 +1,172 @@
+package escrow
+
+import (
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Message type identifiers
+const (
+	TypeMsgCreateHTLC = "/punnet.escrow.v1.MsgCreateHTLC"
+	TypeMsgClaimHTLC  = "/punnet.escrow.v1.MsgClaimHTLC"
+	TypeMsgRefundHTLC = "/punnet.escrow.v1.MsgRefundHTLC"
+)
+
+// MsgCreateHTLC locks coins of the sender for the receiver until the preimage
+// of HashLock is revealed or the timeout is reached. The HTLC ID is
+// HTLCID(Sender, Receiver, HashLock).
+type MsgCreateHTLC struct {
+	// Sender is the account locking the coins
+	Sender types.AccountName `json:"sender"`
+
+	// Receiver is the account that receives the coins on claim
+	Receiver types.AccountName `json:"receiver"`
+
+	// Amount is the coins to lock
+	Amount types.Coins `json:"amount"`
+
+	// HashLock is the lowercase hex SHA-256 of the secret preimage
+	HashLock string `json:"hash_lock"`
+
+	// TimeoutHeight, if not 0, is the block height from which the HTLC can
+	// no longer be claimed and can be refunded
+	TimeoutHeight uint64 `json:"timeout_height,omitempty"`
+
+	// TimeoutTime, if not 0, is the block time (Unix seconds) from which the
+	// HTLC can no longer be claimed and can be refunded
+	TimeoutTime int64 `json:"timeout_time,omitempty"`
+}
+
+// Type returns the message type
+func (m *MsgCreateHTLC) Type() string {
+	return TypeMsgCreateHTLC
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgCreateHTLC) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Sender.IsValid() {
+		return fmt.Errorf("%w: invalid sender account %s", types.ErrInvalidAccount, m.Sender)
+	}
+
+	if !m.Receiver.IsValid() {
+		return fmt.Errorf("%w: invalid receiver account %s", types.ErrInvalidAccount, m.Receiver)
+	}
+
+	if !m.Amount.IsValid() {
+		return fmt.Errorf("%w: invalid amount", types.ErrInvalidCoin)
+	}
+
+	if !m.Amount.IsAllPositive() {
+		return fmt.Errorf("%w: all coin amounts must be positive", types.ErrInvalidCoin)
+	}
+
+	if err := ValidateHashLock(m.HashLock); err != nil {
+		return err
+	}
+
+	if m.TimeoutHeight == 0 && m.TimeoutTime == 0 {
+		return fmt.Errorf("%w: timeout height or time is required", ErrInvalidTimeout)
+	}
+
+	if m.TimeoutTime < 0 {
+		return fmt.Errorf("%w: negative timeout time %d", ErrInvalidTimeout, m.TimeoutTime)
+	}
+
+	return nil
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgCreateHTLC) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Sender}
+}
+
+// MsgClaimHTLC pays an HTLC to its receiver by revealing the preimage of its
+// hash lock before the timeout
+type MsgClaimHTLC struct {
+	// Claimer is the account submitting the claim; it need not be the receiver
+	Claimer types.AccountName `json:"claimer"`
+
+	// ID is the HTLC ID
+	ID string `json:"id"`
+
+	// Preimage is the hex secret whose SHA-256 is the hash lock
+	Preimage string `json:"preimage"`
+}
+
+// Type returns the message type
+func (m *MsgClaimHTLC) Type() string {
+	return TypeMsgClaimHTLC
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgClaimHTLC) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Claimer.IsValid() {
+		return fmt.Errorf("%w: invalid claimer account %s", types.ErrInvalidAccount, m.Claimer)
+	}
+
+	if m.ID == "" {
+		return fmt.Errorf("htlc id cannot be empty")
+	}
+
+	_, err := HashPreimage(m.Preimage)
+	return err
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgClaimHTLC) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Claimer}
+}
+
+// MsgRefundHTLC returns the coins of an expired HTLC to its sender
+type MsgRefundHTLC struct {
+	// Sender is the sender of the HTLC
+	Sender types.AccountName `json:"sender"`
+
+	// ID is the HTLC ID
+	ID string `json:"id"`
+}
+
+// Type returns the message type
+func (m *MsgRefundHTLC) Type() string {
+	return TypeMsgRefundHTLC
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgRefundHTLC) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Sender.IsValid() {
+		return fmt.Errorf("%w: invalid sender account %s", types.ErrInvalidAccount, m.Sender)
+	}
+
+	if m.ID == "" {
+		return fmt.Errorf("htlc id cannot be empty")
+	}
+
+	return nil
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgRefundHTLC) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Sender}
+}
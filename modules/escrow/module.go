@@ -0,0 +1,521 @@
+// Package escrow provides hash-time-locked contracts (HTLCs).
+//
+// A sender locks coins for a receiver under the SHA-256 hash of a secret
+// preimage and a timeout. Before the timeout anyone holding the preimage
+// can claim the coins for the receiver; once the timeout is reached only a
+// refund to the sender is possible. Claiming reveals the preimage in an
+// event, which is what makes atomic swaps with other chains work: the party
+// that claims on one chain discloses the secret the other party needs to
+// claim on the other.
+//
+// Locked coins are held by an escrow account (DefaultEscrowAccount unless
+// configured otherwise) until claimed or refunded.
+package escrow
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/effects"
+	sdkerrors "github.com/blockberries/punnet-sdk/errors"
+	"github.com/blockberries/punnet-sdk/module"
+	"github.com/blockberries/punnet-sdk/modules/bank"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Module name
+const ModuleName = "escrow"
+
+// DefaultEscrowAccount holds the coins of open HTLCs. No keys should
+// control it.
+const DefaultEscrowAccount = types.AccountName("escrow.vault")
+
+// MaxPreimageLength bounds the length of an HTLC preimage in bytes
+const MaxPreimageLength = 64
+
+var (
+	// ErrHTLCNotFound is returned when claiming or refunding an HTLC that
+	// does not exist or was already settled
+	ErrHTLCNotFound = errors.New("htlc not found")
+
+	// ErrHTLCExists is returned when creating an HTLC whose ID is taken
+	ErrHTLCExists = errors.New("htlc already exists")
+
+	// ErrInvalidPreimage is returned when a preimage does not hash to the hash lock
+	ErrInvalidPreimage = errors.New("invalid preimage")
+
+	// ErrHTLCExpired is returned when claiming an HTLC after its timeout
+	ErrHTLCExpired = errors.New("htlc expired")
+
+	// ErrHTLCNotExpired is returned when refunding an HTLC before its timeout
+	ErrHTLCNotExpired = errors.New("htlc not expired")
+
+	// ErrInvalidTimeout is returned when an HTLC timeout is missing or not in the future
+	ErrInvalidTimeout = errors.New("invalid htlc timeout")
+)
+
+// Escrow module error codes, in the ModuleName codespace.
+func init() {
+	sdkerrors.MustRegister(ModuleName, 2, ErrHTLCNotFound)
+	sdkerrors.MustRegister(ModuleName, 3, ErrHTLCExists)
+	sdkerrors.MustRegister(ModuleName, 4, ErrInvalidPreimage)
+	sdkerrors.MustRegister(ModuleName, 5, ErrHTLCExpired)
+	sdkerrors.MustRegister(ModuleName, 6, ErrHTLCNotExpired)
+	sdkerrors.MustRegister(ModuleName, 7, ErrInvalidTimeout)
+}
+
+// Event types
+const (
+	EventTypeCreateHTLC = "escrow.htlc_created"
+	EventTypeClaimHTLC  = "escrow.htlc_claimed"
+	EventTypeRefundHTLC = "escrow.htlc_refunded"
+)
+
+// htlcKey is the key of an HTLC within the module namespace
+func htlcKey(id string) []byte {
+	return []byte("htlc/" + id)
+}
+
+// HTLCID returns the ID of the HTLC from sender to receiver under hashLock:
+// the hex SHA-256 of "sender/receiver/hashLock".
+//
+// RATIONALE: The ID binds the sender, so nobody can take the ID of an HTLC
+// the counterparty of a swap is about to create by creating one with the
+// same hash lock first. Both parties can compute it before the HTLC exists.
+func HTLCID(sender, receiver types.AccountName, hashLock string) string {
+	sum := sha256.Sum256([]byte(string(sender) + "/" + string(receiver) + "/" + hashLock))
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidateHashLock checks that hashLock is a lowercase hex SHA-256 digest.
+// Lowercase only, so that each hash lock has a single HTLC ID.
+func ValidateHashLock(hashLock string) error {
+	b, err := hex.DecodeString(hashLock)
+	if err != nil || len(b) != sha256.Size || hex.EncodeToString(b) != hashLock {
+		return fmt.Errorf("hash lock must be %d lowercase hex characters: %q", 2*sha256.Size, hashLock)
+	}
+	return nil
+}
+
+// HashPreimage returns the hash lock of a hex preimage of 1 to
+// MaxPreimageLength bytes
+func HashPreimage(preimage string) (string, error) {
+	b, err := hex.DecodeString(preimage)
+	if err != nil {
+		return "", fmt.Errorf("%w: preimage must be hex: %v", ErrInvalidPreimage, err)
+	}
+	if len(b) == 0 || len(b) > MaxPreimageLength {
+		return "", fmt.Errorf("%w: preimage must be 1 to %d bytes, got %d", ErrInvalidPreimage, MaxPreimageLength, len(b))
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// HTLC is an open hash-time-locked contract
+type HTLC struct {
+	// ID is HTLCID(Sender, Receiver, HashLock)
+	ID string `json:"id"`
+
+	// Sender locked the coins and receives them back on refund
+	Sender types.AccountName `json:"sender"`
+
+	// Receiver receives the coins on claim
+	Receiver types.AccountName `json:"receiver"`
+
+	// Amount is the locked coins
+	Amount types.Coins `json:"amount"`
+
+	// HashLock is the lowercase hex SHA-256 of the preimage
+	HashLock string `json:"hash_lock"`
+
+	// TimeoutHeight, if not 0, is the block height from which the HTLC is expired
+	TimeoutHeight uint64 `json:"timeout_height,omitempty"`
+
+	// TimeoutTime, if not 0, is the block time (Unix seconds) from which the
+	// HTLC is expired
+	TimeoutTime int64 `json:"timeout_time,omitempty"`
+
+	// CreatedHeight is the block height of creation
+	CreatedHeight uint64 `json:"created_height"`
+}
+
+// IsExpired reports whether the HTLC timeout has been reached at the block of
+// ctx: at TimeoutHeight or TimeoutTime, whichever is set and comes first.
+//
+// INVARIANT: Claim and refund are mutually exclusive; an HTLC is claimable
+// exactly when it is not expired.
+func (h *HTLC) IsExpired(ctx *runtime.Context) bool {
+	if h.TimeoutHeight != 0 && ctx.BlockHeight() >= h.TimeoutHeight {
+		return true
+	}
+	if h.TimeoutTime != 0 && ctx.BlockTime().Unix() >= h.TimeoutTime {
+		return true
+	}
+	return false
+}
+
+// EscrowModule manages HTLCs.
+type EscrowModule struct {
+	// moduleStore is the "module/escrow/" view of the state store
+	moduleStore store.BackingStore
+
+	// balanceCap reads sender balances
+	balanceCap capability.BalanceCapability
+
+	// sends holds the transfers into and out of escrowAccount to the bank's
+	// transfer policy
+	sends bank.SendRestriction
+
+	// escrowAccount holds the coins of open HTLCs
+	escrowAccount types.AccountName
+}
+
+// NewEscrowModule creates an escrow module over its store capability.
+// Locked coins are held by escrowAccount.
+//
+// PRECONDITION: balanceCap must be the capability over account balances
+// (typically the bank module's), not one granted to ModuleName, and sends
+// the bank module's send restriction (a *bank.BankModule with the chain's
+// params store and send hooks).
+//
+// SECURITY: Locking, claiming and refunding are transfers checked by sends,
+// so escrow cannot move coins a MsgSend could not: a blocklisted account
+// can neither lock nor receive coins, and a denom whose sends are disabled
+// can neither be locked nor paid out.
+//
+// SECURITY: Nobody may control escrowAccount: its balance is the sum of the
+// open HTLCs, and any other withdrawal would leave HTLCs unbacked.
+func NewEscrowModule(storeCap capability.StoreCapability, balanceCap capability.BalanceCapability, sends bank.SendRestriction, escrowAccount types.AccountName) (*EscrowModule, error) {
+	if err := capability.CheckStoreCapability(storeCap, ModuleName); err != nil {
+		return nil, err
+	}
+
+	if balanceCap == nil {
+		return nil, fmt.Errorf("balance capability cannot be nil")
+	}
+
+	if sends == nil {
+		return nil, fmt.Errorf("send restriction cannot be nil")
+	}
+
+	if !escrowAccount.IsValid() {
+		return nil, fmt.Errorf("%w: invalid escrow account %s", types.ErrInvalidAccount, escrowAccount)
+	}
+
+	return &EscrowModule{
+		moduleStore:   storeCap,
+		balanceCap:    balanceCap,
+		sends:         sends,
+		escrowAccount: escrowAccount,
+	}, nil
+}
+
+// CreateModule creates the escrow module using the module builder
+//
+// Usage:
+//
+//	storeCap, _ := capManager.GrantStoreCapability(escrow.ModuleName)
+//	escrowMod, _ := escrow.NewEscrowModule(storeCap, balanceCap, bankMod, escrow.DefaultEscrowAccount)
+//	mod, _ := escrow.CreateModule(escrowMod)
+func CreateModule(escrowMod *EscrowModule) (module.Module, error) {
+	if escrowMod == nil {
+		return nil, fmt.Errorf("escrow module cannot be nil")
+	}
+
+	return module.NewModuleBuilder(ModuleName).
+		WithMsgHandler(TypeMsgCreateHTLC, escrowMod.handleCreateHTLC).
+		WithMsgHandler(TypeMsgClaimHTLC, escrowMod.handleClaimHTLC).
+		WithMsgHandler(TypeMsgRefundHTLC, escrowMod.handleRefundHTLC).
+		WithQueryHandler("/htlc", escrowMod.handleQueryHTLC).
+		Build()
+}
+
+// EscrowAccount returns the account holding the coins of open HTLCs
+func (m *EscrowModule) EscrowAccount() types.AccountName {
+	return m.escrowAccount
+}
+
+// HTLC returns the open HTLC with the given ID, if any
+func (m *EscrowModule) HTLC(id string) (*HTLC, bool, error) {
+	if m == nil {
+		return nil, false, fmt.Errorf("escrow module is nil")
+	}
+
+	data, err := m.moduleStore.Get(htlcKey(id))
+	if errors.Is(err, store.ErrNotFound) || (err == nil && data == nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read htlc: %w", err)
+	}
+
+	var htlc HTLC
+	if err := json.Unmarshal(data, &htlc); err != nil {
+		return nil, false, fmt.Errorf("failed to decode htlc %s: %w", id, err)
+	}
+	return &htlc, true, nil
+}
+
+// htlcOf returns the open HTLC with the given ID, failing with
+// ErrHTLCNotFound if there is none
+func (m *EscrowModule) htlcOf(id string) (*HTLC, error) {
+	htlc, ok, err := m.HTLC(id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrHTLCNotFound, id)
+	}
+	return htlc, nil
+}
+
+// htlcEvent returns the event effect for an action on htlc
+func htlcEvent(ctx *runtime.Context, eventType string, htlc *HTLC, attrs ...string) effects.Effect {
+	attributes := map[string][]byte{
+		"id":        []byte(htlc.ID),
+		"sender":    []byte(htlc.Sender),
+		"receiver":  []byte(htlc.Receiver),
+		"amount":    []byte(htlc.Amount.String()),
+		"hash_lock": []byte(htlc.HashLock),
+		"height":    []byte(strconv.FormatUint(ctx.BlockHeight(), 10)),
+	}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		attributes[attrs[i]] = []byte(attrs[i+1])
+	}
+	return effects.NewEventEffect(eventType, attributes)
+}
+
+// checkSigner verifies that signer is the transaction account
+func checkSigner(ctx *runtime.Context, signer types.AccountName) error {
+	if signer != ctx.Account() {
+		return fmt.Errorf("%w: %s must be transaction account", types.ErrUnauthorized, signer)
+	}
+	return nil
+}
+
+// transfer returns the effects of a transfer of coins from one account to
+// another: the transfer and the AfterSend hook's effects, once the transfer
+// passes the bank's CheckSend
+func (m *EscrowModule) transfer(ctx *runtime.Context, from, to types.AccountName, coins types.Coins) ([]effects.Effect, error) {
+	inputs := []bank.Input{{Address: from, Coins: coins}}
+	outputs := []bank.Output{{Address: to, Coins: coins}}
+	if err := m.sends.CheckSend(ctx, inputs, outputs); err != nil {
+		return nil, err
+	}
+	hookEffects, err := m.sends.AfterSend(ctx, inputs, outputs)
+	if err != nil {
+		return nil, err
+	}
+	return append([]effects.Effect{effects.TransferEffect{From: from, To: to, Amount: coins}}, hookEffects...), nil
+}
+
+// claimSettlement claims the HTLC id for the transaction settling it.
+//
+// SECURITY: Handlers read the HTLC from the state before the transaction, so
+// a second claim or refund of it in the same transaction would pass the same
+// checks and pay the coins out of the escrow account twice.
+func claimSettlement(ctx *runtime.Context, id string) error {
+	if !ctx.ClaimTxKey(ModuleName, htlcKey(id)) {
+		return fmt.Errorf("%w: %s already settled in this transaction", ErrHTLCNotFound, id)
+	}
+	return nil
+}
+
+// handleCreateHTLC handles MsgCreateHTLC
+func (m *EscrowModule) handleCreateHTLC(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("escrow module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	createMsg, ok := msg.(*MsgCreateHTLC)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgCreateHTLC")
+	}
+
+	if err := checkSigner(ctx, createMsg.Sender); err != nil {
+		return nil, err
+	}
+
+	htlc := &HTLC{
+		ID:            HTLCID(createMsg.Sender, createMsg.Receiver, createMsg.HashLock),
+		Sender:        createMsg.Sender,
+		Receiver:      createMsg.Receiver,
+		Amount:        createMsg.Amount,
+		HashLock:      createMsg.HashLock,
+		TimeoutHeight: createMsg.TimeoutHeight,
+		TimeoutTime:   createMsg.TimeoutTime,
+		CreatedHeight: ctx.BlockHeight(),
+	}
+
+	// An HTLC born expired could never be claimed
+	if htlc.IsExpired(ctx) {
+		return nil, fmt.Errorf("%w: timeout already reached at height %d", ErrInvalidTimeout, ctx.BlockHeight())
+	}
+
+	_, exists, err := m.HTLC(htlc.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("%w: %s", ErrHTLCExists, htlc.ID)
+	}
+
+	for _, coin := range htlc.Amount {
+		balance, err := m.balanceCap.GetBalance(ctx.Context(), htlc.Sender, coin.Denom)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get balance: %w", err)
+		}
+		if balance < coin.Amount {
+			return nil, fmt.Errorf("%w: insufficient balance for %s", types.ErrInsufficientFunds, coin.Denom)
+		}
+	}
+
+	transferEffects, err := m.transfer(ctx, htlc.Sender, m.escrowAccount, htlc.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	// Claimed last, so a rejected create does not take the ID for the rest
+	// of the transaction
+	if !ctx.ClaimTxKey(ModuleName, htlcKey(htlc.ID)) {
+		return nil, fmt.Errorf("%w: %s", ErrHTLCExists, htlc.ID)
+	}
+
+	data, err := json.Marshal(htlc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode htlc: %w", err)
+	}
+
+	return append(transferEffects,
+		effects.NewStateWriteEffect(ModuleName, htlcKey(htlc.ID), data),
+		htlcEvent(ctx, EventTypeCreateHTLC, htlc,
+			"timeout_height", strconv.FormatUint(htlc.TimeoutHeight, 10),
+			"timeout_time", strconv.FormatInt(htlc.TimeoutTime, 10)),
+	), nil
+}
+
+// handleClaimHTLC handles MsgClaimHTLC.
+//
+// SECURITY: Anyone may claim, since the coins only ever go to the receiver;
+// the preimage is the authorization. It is emitted in the claim event so the
+// counterparty of a swap can claim on the other chain.
+func (m *EscrowModule) handleClaimHTLC(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("escrow module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	claimMsg, ok := msg.(*MsgClaimHTLC)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgClaimHTLC")
+	}
+
+	if err := checkSigner(ctx, claimMsg.Claimer); err != nil {
+		return nil, err
+	}
+
+	htlc, err := m.htlcOf(claimMsg.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if htlc.IsExpired(ctx) {
+		return nil, fmt.Errorf("%w: %s", ErrHTLCExpired, htlc.ID)
+	}
+
+	hashLock, err := HashPreimage(claimMsg.Preimage)
+	if err != nil {
+		return nil, err
+	}
+	if hashLock != htlc.HashLock {
+		return nil, fmt.Errorf("%w: preimage does not match hash lock of %s", ErrInvalidPreimage, htlc.ID)
+	}
+
+	transferEffects, err := m.transfer(ctx, m.escrowAccount, htlc.Receiver, htlc.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := claimSettlement(ctx, htlc.ID); err != nil {
+		return nil, err
+	}
+
+	return append(transferEffects,
+		effects.NewStateDeleteEffect(ModuleName, htlcKey(htlc.ID)),
+		htlcEvent(ctx, EventTypeClaimHTLC, htlc,
+			"claimer", string(claimMsg.Claimer),
+			"preimage", claimMsg.Preimage),
+	), nil
+}
+
+// handleRefundHTLC handles MsgRefundHTLC
+func (m *EscrowModule) handleRefundHTLC(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("escrow module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	refundMsg, ok := msg.(*MsgRefundHTLC)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgRefundHTLC")
+	}
+
+	if err := checkSigner(ctx, refundMsg.Sender); err != nil {
+		return nil, err
+	}
+
+	htlc, err := m.htlcOf(refundMsg.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if htlc.Sender != refundMsg.Sender {
+		return nil, fmt.Errorf("%w: %s is not the sender of %s", types.ErrUnauthorized, refundMsg.Sender, htlc.ID)
+	}
+
+	if !htlc.IsExpired(ctx) {
+		return nil, fmt.Errorf("%w: %s", ErrHTLCNotExpired, htlc.ID)
+	}
+
+	transferEffects, err := m.transfer(ctx, m.escrowAccount, htlc.Sender, htlc.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := claimSettlement(ctx, htlc.ID); err != nil {
+		return nil, err
+	}
+
+	return append(transferEffects,
+		effects.NewStateDeleteEffect(ModuleName, htlcKey(htlc.ID)),
+		htlcEvent(ctx, EventTypeRefundHTLC, htlc),
+	), nil
+}
+
+// handleQueryHTLC returns the open HTLC whose ID is data as JSON, or null
+// if none
+func (m *EscrowModule) handleQueryHTLC(ctx context.Context, path string, data []byte) ([]byte, error) {
+	htlc, ok, err := m.HTLC(string(data))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return json.Marshal(nil)
+	}
+	return json.Marshal(htlc)
+}
@@ -0,0 +1,519 @@
+package escrow
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/modules/bank"
+	"github.com/blockberries/punnet-sdk/runtime"
+	punnettesting "github.com/blockberries/punnet-sdk/testing"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// testPreimage and testHashLock are a secret and its hash lock
+var (
+	testPreimage = hex.EncodeToString([]byte("swap secret"))
+	testHashLock = func() string {
+		sum := sha256.Sum256([]byte("swap secret"))
+		return hex.EncodeToString(sum[:])
+	}()
+)
+
+// testGenesisTime is the block time at height 0 in tests; each block adds a second
+var testGenesisTime = time.Unix(1_700_000_000, 0)
+
+type testEnv struct {
+	*punnettesting.EffectEnv
+	mod        *EscrowModule
+	balanceCap capability.BalanceCapability
+	params     *bank.ParamsStore
+}
+
+func setupTestEscrowModule(t *testing.T) *testEnv {
+	t.Helper()
+
+	env := punnettesting.NewEffectEnv(t)
+	capMgr := env.CapabilityManager()
+	if err := capMgr.RegisterModule("bank"); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	balanceCap, err := capMgr.GrantBalanceCapability("bank")
+	if err != nil {
+		t.Fatalf("failed to grant balance capability: %v", err)
+	}
+	env.UseBalances(balanceCap)
+	if err := balanceCap.AddBalance(context.Background(), "alice", "stake", 1000); err != nil {
+		t.Fatalf("failed to fund alice: %v", err)
+	}
+
+	params, err := bank.NewParamsStore(env.Store())
+	if err != nil {
+		t.Fatalf("failed to create params store: %v", err)
+	}
+	bankMod, err := bank.NewBankModule(balanceCap, bank.WithParamsStore(params))
+	if err != nil {
+		t.Fatalf("failed to create bank module: %v", err)
+	}
+
+	escrowMod, err := NewEscrowModule(env.GrantStore(t, ModuleName), balanceCap, bankMod, DefaultEscrowAccount)
+	if err != nil {
+		t.Fatalf("failed to create escrow module: %v", err)
+	}
+	return &testEnv{EffectEnv: env, mod: escrowMod, balanceCap: balanceCap, params: params}
+}
+
+// setBankParams sets the bank params escrow transfers are checked against
+func (env *testEnv) setBankParams(t *testing.T, params bank.Params) {
+	t.Helper()
+
+	if err := env.params.Set(params); err != nil {
+		t.Fatalf("failed to set bank params: %v", err)
+	}
+}
+
+func setupTestContext(t *testing.T, height uint64, account types.AccountName) *runtime.Context {
+	t.Helper()
+
+	blockTime := testGenesisTime.Add(time.Duration(height) * time.Second)
+	header := runtime.NewBlockHeader(height, blockTime, "test-chain", []byte("proposer"))
+	ctx, err := runtime.NewContext(context.Background(), header, account)
+	if err != nil {
+		t.Fatalf("failed to create context: %v", err)
+	}
+	return ctx
+}
+
+// run invokes handler at height with account as the transaction account
+// and applies its effects on success
+func (env *testEnv) run(t *testing.T, height uint64, account types.AccountName, msg types.Message) ([]effects.Effect, error) {
+	t.Helper()
+
+	handlers := map[string]func(*runtime.Context, types.Message) ([]effects.Effect, error){
+		TypeMsgCreateHTLC: env.mod.handleCreateHTLC,
+		TypeMsgClaimHTLC:  env.mod.handleClaimHTLC,
+		TypeMsgRefundHTLC: env.mod.handleRefundHTLC,
+	}
+	ctx := setupTestContext(t, height, account)
+	effs, err := handlers[msg.Type()](ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	env.Apply(t, ctx, effs)
+	return effs, nil
+}
+
+// runTx invokes the handlers of msgs at height in one transaction of
+// account, returning the error of each message. The effects of the messages
+// that succeeded are applied.
+func (env *testEnv) runTx(t *testing.T, height uint64, account types.AccountName, msgs ...types.Message) []error {
+	t.Helper()
+
+	handlers := map[string]func(*runtime.Context, types.Message) ([]effects.Effect, error){
+		TypeMsgCreateHTLC: env.mod.handleCreateHTLC,
+		TypeMsgClaimHTLC:  env.mod.handleClaimHTLC,
+		TypeMsgRefundHTLC: env.mod.handleRefundHTLC,
+	}
+	ctx := setupTestContext(t, height, account).WithTxHash([]byte("tx"))
+	errs := make([]error, len(msgs))
+	var all []effects.Effect
+	for i, msg := range msgs {
+		effs, err := handlers[msg.Type()](ctx, msg)
+		errs[i] = err
+		all = append(all, effs...)
+	}
+	env.Apply(t, ctx, all)
+	return errs
+}
+
+func (env *testEnv) balance(t *testing.T, account types.AccountName) uint64 {
+	t.Helper()
+
+	balance, err := env.balanceCap.GetBalance(context.Background(), account, "stake")
+	if err != nil {
+		t.Fatalf("GetBalance() error = %v", err)
+	}
+	return balance
+}
+
+// testCreateMsg locks 100stake from alice for bob until height 10
+func testCreateMsg() *MsgCreateHTLC {
+	return &MsgCreateHTLC{
+		Sender:        "alice",
+		Receiver:      "bob",
+		Amount:        types.NewCoins(types.NewCoin("stake", 100)),
+		HashLock:      testHashLock,
+		TimeoutHeight: 10,
+	}
+}
+
+func TestMsgCreateHTLC_ValidateBasic(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*MsgCreateHTLC)
+		wantErr bool
+	}{
+		{"valid", func(*MsgCreateHTLC) {}, false},
+		{"time timeout", func(m *MsgCreateHTLC) { m.TimeoutHeight, m.TimeoutTime = 0, 1_700_000_100 }, false},
+		{"invalid sender", func(m *MsgCreateHTLC) { m.Sender = "Alice" }, true},
+		{"invalid receiver", func(m *MsgCreateHTLC) { m.Receiver = "" }, true},
+		{"empty amount", func(m *MsgCreateHTLC) { m.Amount = nil }, true},
+		{"zero amount", func(m *MsgCreateHTLC) { m.Amount = types.NewCoins(types.NewCoin("stake", 0)) }, true},
+		{"short hash lock", func(m *MsgCreateHTLC) { m.HashLock = testHashLock[:62] }, true},
+		{"uppercase hash lock", func(m *MsgCreateHTLC) { m.HashLock = "AB" + testHashLock[2:] }, true},
+		{"no timeout", func(m *MsgCreateHTLC) { m.TimeoutHeight = 0 }, true},
+		{"negative timeout time", func(m *MsgCreateHTLC) { m.TimeoutTime = -1 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := testCreateMsg()
+			tt.modify(msg)
+			err := msg.ValidateBasic()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateBasic() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMsgClaimHTLC_ValidateBasic(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     *MsgClaimHTLC
+		wantErr bool
+	}{
+		{"valid", &MsgClaimHTLC{Claimer: "bob", ID: "id", Preimage: testPreimage}, false},
+		{"nil", nil, true},
+		{"no id", &MsgClaimHTLC{Claimer: "bob", Preimage: testPreimage}, true},
+		{"empty preimage", &MsgClaimHTLC{Claimer: "bob", ID: "id"}, true},
+		{"non-hex preimage", &MsgClaimHTLC{Claimer: "bob", ID: "id", Preimage: "zz"}, true},
+		{"long preimage", &MsgClaimHTLC{Claimer: "bob", ID: "id", Preimage: hex.EncodeToString(make([]byte, MaxPreimageLength+1))}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.msg.ValidateBasic()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateBasic() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEscrowModule_CreateAndClaim(t *testing.T) {
+	env := setupTestEscrowModule(t)
+	id := HTLCID("alice", "bob", testHashLock)
+
+	if _, err := env.run(t, 1, "alice", testCreateMsg()); err != nil {
+		t.Fatalf("create error = %v", err)
+	}
+	if got := env.balance(t, "alice"); got != 900 {
+		t.Fatalf("alice balance = %d, want 900", got)
+	}
+	if got := env.balance(t, DefaultEscrowAccount); got != 100 {
+		t.Fatalf("escrow balance = %d, want 100", got)
+	}
+
+	htlc, ok, err := env.mod.HTLC(id)
+	if err != nil || !ok {
+		t.Fatalf("HTLC() = %v, %v, want htlc", ok, err)
+	}
+	if htlc.CreatedHeight != 1 || htlc.Receiver != "bob" {
+		t.Fatalf("htlc = %+v", htlc)
+	}
+
+	// The same HTLC cannot be created twice
+	if _, err := env.run(t, 2, "alice", testCreateMsg()); !errors.Is(err, ErrHTLCExists) {
+		t.Fatalf("create error = %v, want ErrHTLCExists", err)
+	}
+
+	// Refunding before the timeout fails
+	if _, err := env.run(t, 5, "alice", &MsgRefundHTLC{Sender: "alice", ID: id}); !errors.Is(err, ErrHTLCNotExpired) {
+		t.Fatalf("refund error = %v, want ErrHTLCNotExpired", err)
+	}
+
+	wrong := hex.EncodeToString([]byte("wrong secret"))
+	if _, err := env.run(t, 5, "carol", &MsgClaimHTLC{Claimer: "carol", ID: id, Preimage: wrong}); !errors.Is(err, ErrInvalidPreimage) {
+		t.Fatalf("claim error = %v, want ErrInvalidPreimage", err)
+	}
+
+	// Anyone with the preimage claims for the receiver, revealing it
+	effs, err := env.run(t, 9, "carol", &MsgClaimHTLC{Claimer: "carol", ID: id, Preimage: testPreimage})
+	if err != nil {
+		t.Fatalf("claim error = %v", err)
+	}
+	if got := env.balance(t, "bob"); got != 100 {
+		t.Fatalf("bob balance = %d, want 100", got)
+	}
+	if got := env.balance(t, DefaultEscrowAccount); got != 0 {
+		t.Fatalf("escrow balance = %d, want 0", got)
+	}
+	revealed := false
+	for _, eff := range effs {
+		if e, ok := eff.(effects.EventEffect); ok && e.EventType == EventTypeClaimHTLC {
+			revealed = string(e.Attributes["preimage"]) == testPreimage
+		}
+	}
+	if !revealed {
+		t.Fatal("claim event does not reveal the preimage")
+	}
+
+	// A settled HTLC can be neither claimed nor refunded
+	if _, err := env.run(t, 10, "bob", &MsgClaimHTLC{Claimer: "bob", ID: id, Preimage: testPreimage}); !errors.Is(err, ErrHTLCNotFound) {
+		t.Fatalf("claim error = %v, want ErrHTLCNotFound", err)
+	}
+	if _, err := env.run(t, 10, "alice", &MsgRefundHTLC{Sender: "alice", ID: id}); !errors.Is(err, ErrHTLCNotFound) {
+		t.Fatalf("refund error = %v, want ErrHTLCNotFound", err)
+	}
+}
+
+func TestEscrowModule_Refund(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*MsgCreateHTLC)
+		height uint64
+	}{
+		{"height timeout", func(*MsgCreateHTLC) {}, 10},
+		{"time timeout", func(m *MsgCreateHTLC) {
+			m.TimeoutHeight, m.TimeoutTime = 0, testGenesisTime.Add(20*time.Second).Unix()
+		}, 20},
+		{"earlier of both", func(m *MsgCreateHTLC) {
+			m.TimeoutHeight, m.TimeoutTime = 100, testGenesisTime.Add(5*time.Second).Unix()
+		}, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := setupTestEscrowModule(t)
+			msg := testCreateMsg()
+			tt.modify(msg)
+			id := HTLCID(msg.Sender, msg.Receiver, msg.HashLock)
+
+			if _, err := env.run(t, 1, "alice", msg); err != nil {
+				t.Fatalf("create error = %v", err)
+			}
+
+			if _, err := env.run(t, tt.height-1, "alice", &MsgRefundHTLC{Sender: "alice", ID: id}); !errors.Is(err, ErrHTLCNotExpired) {
+				t.Fatalf("refund error = %v, want ErrHTLCNotExpired", err)
+			}
+			if _, err := env.run(t, tt.height, "bob", &MsgClaimHTLC{Claimer: "bob", ID: id, Preimage: testPreimage}); !errors.Is(err, ErrHTLCExpired) {
+				t.Fatalf("claim error = %v, want ErrHTLCExpired", err)
+			}
+			if _, err := env.run(t, tt.height, "bob", &MsgRefundHTLC{Sender: "bob", ID: id}); !errors.Is(err, types.ErrUnauthorized) {
+				t.Fatalf("refund error = %v, want ErrUnauthorized", err)
+			}
+
+			if _, err := env.run(t, tt.height, "alice", &MsgRefundHTLC{Sender: "alice", ID: id}); err != nil {
+				t.Fatalf("refund error = %v", err)
+			}
+			if got := env.balance(t, "alice"); got != 1000 {
+				t.Fatalf("alice balance = %d, want 1000", got)
+			}
+			if _, ok, _ := env.mod.HTLC(id); ok {
+				t.Fatal("refunded htlc still exists")
+			}
+		})
+	}
+}
+
+func TestEscrowModule_SettleOncePerTx(t *testing.T) {
+	id := HTLCID("alice", "bob", testHashLock)
+
+	t.Run("two claims", func(t *testing.T) {
+		env := setupTestEscrowModule(t)
+		// Another HTLC's coins are in the escrow account as well
+		other := testCreateMsg()
+		other.Receiver = "carol"
+		if errs := env.runTx(t, 1, "alice", testCreateMsg(), other); errs[0] != nil || errs[1] != nil {
+			t.Fatalf("create errors = %v", errs)
+		}
+
+		claim := &MsgClaimHTLC{Claimer: "bob", ID: id, Preimage: testPreimage}
+		errs := env.runTx(t, 2, "bob", claim, claim)
+		if errs[0] != nil || !errors.Is(errs[1], ErrHTLCNotFound) {
+			t.Fatalf("claim errors = %v, want nil and ErrHTLCNotFound", errs)
+		}
+		if got := env.balance(t, "bob"); got != 100 {
+			t.Fatalf("bob balance = %d, want 100", got)
+		}
+		if got := env.balance(t, DefaultEscrowAccount); got != 100 {
+			t.Fatalf("escrow balance = %d, want 100", got)
+		}
+	})
+
+	t.Run("two refunds", func(t *testing.T) {
+		env := setupTestEscrowModule(t)
+		if _, err := env.run(t, 1, "alice", testCreateMsg()); err != nil {
+			t.Fatalf("create error = %v", err)
+		}
+
+		refund := &MsgRefundHTLC{Sender: "alice", ID: id}
+		errs := env.runTx(t, 10, "alice", refund, refund)
+		if errs[0] != nil || !errors.Is(errs[1], ErrHTLCNotFound) {
+			t.Fatalf("refund errors = %v, want nil and ErrHTLCNotFound", errs)
+		}
+		if got := env.balance(t, "alice"); got != 1000 {
+			t.Fatalf("alice balance = %d, want 1000", got)
+		}
+	})
+
+	t.Run("two creates", func(t *testing.T) {
+		env := setupTestEscrowModule(t)
+		errs := env.runTx(t, 1, "alice", testCreateMsg(), testCreateMsg())
+		if errs[0] != nil || !errors.Is(errs[1], ErrHTLCExists) {
+			t.Fatalf("create errors = %v, want nil and ErrHTLCExists", errs)
+		}
+		if got := env.balance(t, "alice"); got != 900 {
+			t.Fatalf("alice balance = %d, want 900", got)
+		}
+	})
+}
+
+func TestEscrowModule_CreateErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		account types.AccountName
+		modify  func(*MsgCreateHTLC)
+		wantErr error
+	}{
+		{"not transaction account", "bob", func(*MsgCreateHTLC) {}, types.ErrUnauthorized},
+		{"insufficient funds", "alice", func(m *MsgCreateHTLC) { m.Amount = types.NewCoins(types.NewCoin("stake", 1001)) }, types.ErrInsufficientFunds},
+		{"height timeout reached", "alice", func(m *MsgCreateHTLC) { m.TimeoutHeight = 5 }, ErrInvalidTimeout},
+		{"time timeout reached", "alice", func(m *MsgCreateHTLC) {
+			m.TimeoutHeight, m.TimeoutTime = 0, testGenesisTime.Unix()
+		}, ErrInvalidTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := setupTestEscrowModule(t)
+			msg := testCreateMsg()
+			tt.modify(msg)
+
+			if _, err := env.run(t, 5, tt.account, msg); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("create error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEscrowModule_SendRestriction(t *testing.T) {
+	id := HTLCID("alice", "bob", testHashLock)
+	blocked := func(accounts ...types.AccountName) bank.Params {
+		params := bank.DefaultParams()
+		params.Blocklist = accounts
+		return params
+	}
+	disabled := bank.Params{DefaultSendEnabled: true, SendEnabled: []bank.SendEnabled{{Denom: "stake", Enabled: false}}}
+
+	t.Run("blocklisted sender cannot lock", func(t *testing.T) {
+		env := setupTestEscrowModule(t)
+		env.setBankParams(t, blocked("alice"))
+
+		if _, err := env.run(t, 1, "alice", testCreateMsg()); !errors.Is(err, bank.ErrAccountBlocked) {
+			t.Fatalf("create error = %v, want ErrAccountBlocked", err)
+		}
+		if got := env.balance(t, "alice"); got != 1000 {
+			t.Fatalf("alice balance = %d, want 1000", got)
+		}
+
+		// The rejected create did not take the ID for the transaction
+		env.setBankParams(t, bank.DefaultParams())
+		if _, err := env.run(t, 1, "alice", testCreateMsg()); err != nil {
+			t.Fatalf("create error = %v", err)
+		}
+	})
+
+	t.Run("disabled denom cannot be locked", func(t *testing.T) {
+		env := setupTestEscrowModule(t)
+		env.setBankParams(t, disabled)
+
+		if _, err := env.run(t, 1, "alice", testCreateMsg()); !errors.Is(err, bank.ErrSendDisabled) {
+			t.Fatalf("create error = %v, want ErrSendDisabled", err)
+		}
+		if got := env.balance(t, DefaultEscrowAccount); got != 0 {
+			t.Fatalf("escrow balance = %d, want 0", got)
+		}
+	})
+
+	t.Run("disabled denom cannot be paid out", func(t *testing.T) {
+		env := setupTestEscrowModule(t)
+		if _, err := env.run(t, 1, "alice", testCreateMsg()); err != nil {
+			t.Fatalf("create error = %v", err)
+		}
+		env.setBankParams(t, disabled)
+
+		if _, err := env.run(t, 2, "bob", &MsgClaimHTLC{Claimer: "bob", ID: id, Preimage: testPreimage}); !errors.Is(err, bank.ErrSendDisabled) {
+			t.Fatalf("claim error = %v, want ErrSendDisabled", err)
+		}
+		if _, err := env.run(t, 10, "alice", &MsgRefundHTLC{Sender: "alice", ID: id}); !errors.Is(err, bank.ErrSendDisabled) {
+			t.Fatalf("refund error = %v, want ErrSendDisabled", err)
+		}
+		if got := env.balance(t, DefaultEscrowAccount); got != 100 {
+			t.Fatalf("escrow balance = %d, want 100", got)
+		}
+	})
+
+	t.Run("blocklisted receiver cannot claim", func(t *testing.T) {
+		env := setupTestEscrowModule(t)
+		if _, err := env.run(t, 1, "alice", testCreateMsg()); err != nil {
+			t.Fatalf("create error = %v", err)
+		}
+		env.setBankParams(t, blocked("bob"))
+
+		if _, err := env.run(t, 2, "bob", &MsgClaimHTLC{Claimer: "bob", ID: id, Preimage: testPreimage}); !errors.Is(err, bank.ErrAccountBlocked) {
+			t.Fatalf("claim error = %v, want ErrAccountBlocked", err)
+		}
+		if _, ok, _ := env.mod.HTLC(id); !ok {
+			t.Fatal("htlc settled by a rejected claim")
+		}
+	})
+}
+
+func TestEscrowModule_QueryHTLC(t *testing.T) {
+	env := setupTestEscrowModule(t)
+	id := HTLCID("alice", "bob", testHashLock)
+
+	data, err := env.mod.handleQueryHTLC(context.Background(), "/htlc", []byte(id))
+	if err != nil {
+		t.Fatalf("handleQueryHTLC() error = %v", err)
+	}
+	if string(data) != "null" {
+		t.Fatalf("handleQueryHTLC() = %s, want null", data)
+	}
+
+	if _, err := env.run(t, 1, "alice", testCreateMsg()); err != nil {
+		t.Fatalf("create error = %v", err)
+	}
+
+	data, err = env.mod.handleQueryHTLC(context.Background(), "/htlc", []byte(id))
+	if err != nil {
+		t.Fatalf("handleQueryHTLC() error = %v", err)
+	}
+	var htlc HTLC
+	if err := json.Unmarshal(data, &htlc); err != nil {
+		t.Fatalf("failed to decode htlc: %v", err)
+	}
+	if htlc.ID != id || htlc.HashLock != testHashLock || htlc.TimeoutHeight != 10 {
+		t.Fatalf("htlc = %+v", htlc)
+	}
+}
+
+func TestCreateModule(t *testing.T) {
+	env := setupTestEscrowModule(t)
+
+	mod, err := CreateModule(env.mod)
+	if err != nil {
+		t.Fatalf("CreateModule() error = %v", err)
+	}
+	if mod.Name() != ModuleName {
+		t.Fatalf("Name() = %s, want %s", mod.Name(), ModuleName)
+	}
+
+	if _, err := CreateModule(nil); err == nil {
+		t.Fatal("CreateModule(nil) succeeded")
+	}
+}
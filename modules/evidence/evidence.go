@@ -0,0 +1,134 @@
+package evidence
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+	"github.com/blockberries/punnet-sdk/light"
+)
+
+// MaxPubKeySize bounds the validator public key of evidence
+const MaxPubKeySize = 128
+
+// MaxSignatureSize bounds each vote signature of evidence
+const MaxSignatureSize = 256
+
+// Vote is a validator's signature of the block hash it voted for, over
+// light.VoteSignBytes
+type Vote struct {
+	// BlockHash is the hash of the header voted for
+	BlockHash []byte `json:"block_hash"`
+
+	// Signature is the validator's signature of the vote
+	Signature []byte `json:"signature"`
+}
+
+// DuplicateVoteEvidence proves that a validator equivocated: it signed votes
+// for two different blocks at the same height.
+//
+// INVARIANT: A validator equivocates at most once per height as far as this
+// module is concerned; evidence is deduplicated by (PubKey, Height), so
+// evidence for either order of the two votes, or for a third conflicting
+// vote, is a duplicate.
+type DuplicateVoteEvidence struct {
+	// Algorithm is the algorithm of PubKey
+	Algorithm crypto.Algorithm `json:"algorithm"`
+
+	// PubKey is the equivocating validator's public key
+	PubKey []byte `json:"pub_key"`
+
+	// Height is the height both votes were cast at
+	Height uint64 `json:"height"`
+
+	// VoteA and VoteB are the conflicting votes
+	VoteA Vote `json:"vote_a"`
+	VoteB Vote `json:"vote_b"`
+}
+
+// ValidateBasic performs stateless validation
+func (e *DuplicateVoteEvidence) ValidateBasic() error {
+	if e == nil {
+		return fmt.Errorf("%w: evidence is nil", ErrInvalidEvidence)
+	}
+
+	if !e.Algorithm.IsValid() {
+		return fmt.Errorf("%w: invalid algorithm %q", ErrInvalidEvidence, e.Algorithm)
+	}
+
+	if len(e.PubKey) == 0 || len(e.PubKey) > MaxPubKeySize {
+		return fmt.Errorf("%w: public key must be 1 to %d bytes", ErrInvalidEvidence, MaxPubKeySize)
+	}
+
+	if e.Height == 0 {
+		return fmt.Errorf("%w: height cannot be zero", ErrInvalidEvidence)
+	}
+
+	for _, v := range e.votes() {
+		if len(v.vote.BlockHash) != light.HashSize {
+			return fmt.Errorf("%w: %s block hash must be %d bytes", ErrInvalidEvidence, v.name, light.HashSize)
+		}
+		if len(v.vote.Signature) == 0 || len(v.vote.Signature) > MaxSignatureSize {
+			return fmt.Errorf("%w: %s signature must be 1 to %d bytes", ErrInvalidEvidence, v.name, MaxSignatureSize)
+		}
+	}
+
+	// Two votes for the same block are not misbehavior
+	if bytes.Equal(e.VoteA.BlockHash, e.VoteB.BlockHash) {
+		return fmt.Errorf("%w: votes are for the same block", ErrInvalidEvidence)
+	}
+
+	return nil
+}
+
+// namedVote is a vote of evidence with its name for error messages
+type namedVote struct {
+	name string
+	vote Vote
+}
+
+// votes returns both votes in order
+func (e *DuplicateVoteEvidence) votes() [2]namedVote {
+	return [2]namedVote{{"vote a", e.VoteA}, {"vote b", e.VoteB}}
+}
+
+// Verify verifies that both votes are signed by the validator on chainID.
+//
+// PRECONDITION: ValidateBasic passed.
+func (e *DuplicateVoteEvidence) Verify(chainID string) error {
+	pubKey, err := crypto.PublicKeyFromBytes(e.Algorithm, e.PubKey)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEvidence, err)
+	}
+
+	for _, v := range e.votes() {
+		if !pubKey.Verify(light.VoteSignBytes(chainID, e.Height, v.vote.BlockHash), v.vote.Signature) {
+			return fmt.Errorf("%w: invalid %s signature", ErrInvalidEvidence, v.name)
+		}
+	}
+	return nil
+}
+
+// Hash returns the hash identifying the equivocation: the SHA-256 of the
+// algorithm, public key and height, so evidence of the same equivocation
+// hashes alike whichever votes it carries
+func (e *DuplicateVoteEvidence) Hash() []byte {
+	h := sha256.New()
+	writeBytes(h, []byte(e.Algorithm))
+	writeBytes(h, e.PubKey)
+	var height [8]byte
+	binary.BigEndian.PutUint64(height[:], e.Height)
+	h.Write(height[:])
+	return h.Sum(nil)
+}
+
+// writeBytes writes a length-prefixed byte string
+func writeBytes(w io.Writer, b []byte) {
+	var n [8]byte
+	binary.BigEndian.PutUint64(n[:], uint64(len(b)))
+	w.Write(n[:])
+	w.Write(b)
+}
@@ -0,0 +1,49 @@
+package evidence
+
+import (
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Message type identifiers
+const (
+	TypeMsgSubmitEvidence = "/punnet.evidence.v1.MsgSubmitEvidence"
+)
+
+// MsgSubmitEvidence reports a validator's equivocation. Any account may
+// submit evidence; it is verified against the chain ID of the block it is
+// included in.
+type MsgSubmitEvidence struct {
+	// Submitter is the account reporting the evidence
+	Submitter types.AccountName `json:"submitter"`
+
+	// Evidence is the equivocation proof
+	Evidence DuplicateVoteEvidence `json:"evidence"`
+}
+
+// Type returns the message type
+func (m *MsgSubmitEvidence) Type() string {
+	return TypeMsgSubmitEvidence
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgSubmitEvidence) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Submitter.IsValid() {
+		return fmt.Errorf("%w: invalid submitter account %s", types.ErrInvalidAccount, m.Submitter)
+	}
+
+	return m.Evidence.ValidateBasic()
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgSubmitEvidence) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Submitter}
+}
@@ -0,0 +1,378 @@
+// Package evidence handles reports of validator misbehavior.
+//
+// Evidence that a validator signed votes for two different blocks at one
+// height (DuplicateVoteEvidence) is submitted with MsgSubmitEvidence, or by
+// the application for misbehavior reported by consensus (SubmitEvidence).
+// Accepted evidence is verified against the validator's key, deduplicated by
+// validator and height, stored, and passed to Hooks, e.g. for slashing.
+// Evidence older than Params.MaxAgeBlocks is rejected, and pruned from state
+// at end block.
+//
+// Stored evidence is queried with the query services in query.go.
+package evidence
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/effects"
+	sdkerrors "github.com/blockberries/punnet-sdk/errors"
+	"github.com/blockberries/punnet-sdk/module"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Module name
+const ModuleName = "evidence"
+
+var (
+	// ErrInvalidEvidence is returned for malformed or unverifiable evidence
+	ErrInvalidEvidence = errors.New("invalid evidence")
+
+	// ErrEvidenceExists is returned when the equivocation was already reported
+	ErrEvidenceExists = errors.New("evidence already exists")
+
+	// ErrEvidenceExpired is returned for evidence older than Params.MaxAgeBlocks
+	ErrEvidenceExpired = errors.New("evidence expired")
+
+	// ErrUnknownValidator is returned for evidence against a key that is not
+	// a validator
+	ErrUnknownValidator = errors.New("unknown validator")
+)
+
+// Evidence module error codes, in the ModuleName codespace.
+func init() {
+	sdkerrors.MustRegister(ModuleName, 2, ErrInvalidEvidence)
+	sdkerrors.MustRegister(ModuleName, 3, ErrEvidenceExists)
+	sdkerrors.MustRegister(ModuleName, 4, ErrEvidenceExpired)
+	sdkerrors.MustRegister(ModuleName, 5, ErrUnknownValidator)
+}
+
+// Event types
+const (
+	EventTypeEvidenceSubmitted = "evidence.submitted"
+	EventTypeEvidencePruned    = "evidence.pruned"
+)
+
+// State keys, relative to the module namespace
+const (
+	// evidencePrefix prefixes "<height><hash>" (height as 8 big-endian
+	// bytes), holding a Record; height order makes expired evidence a prefix
+	// of the iteration
+	evidencePrefix = "evidence/"
+
+	// validatorPrefix prefixes "<hex pub key>/<height><hash>", holding the
+	// evidence key of the record; an index for queries by validator
+	validatorPrefix = "validator/"
+)
+
+// recordKey returns the key of the evidence record with hash at height,
+// relative to evidencePrefix
+func recordKey(height uint64, hash []byte) []byte {
+	return append(binary.BigEndian.AppendUint64(nil, height), hash...)
+}
+
+// evidenceKey returns the state key of the evidence record with hash at height
+func evidenceKey(height uint64, hash []byte) []byte {
+	return append([]byte(evidencePrefix), recordKey(height, hash)...)
+}
+
+// validatorIndexPrefix returns the validator index prefix of pubKey
+func validatorIndexPrefix(pubKey []byte) []byte {
+	return []byte(validatorPrefix + hex.EncodeToString(pubKey) + "/")
+}
+
+// validatorIndexKey returns the validator index key of the evidence record
+// with hash at height
+func validatorIndexKey(pubKey []byte, height uint64, hash []byte) []byte {
+	return append(validatorIndexPrefix(pubKey), recordKey(height, hash)...)
+}
+
+// Record is accepted evidence
+type Record struct {
+	// Evidence is the equivocation proof
+	Evidence DuplicateVoteEvidence `json:"evidence"`
+
+	// Hash is Evidence.Hash()
+	Hash []byte `json:"hash"`
+
+	// Power is the validator's voting power when the evidence was accepted
+	Power int64 `json:"power"`
+
+	// Submitter is the account that submitted the evidence
+	Submitter types.AccountName `json:"submitter"`
+
+	// SubmittedHeight is the height the evidence was accepted at
+	SubmittedHeight uint64 `json:"submitted_height"`
+}
+
+// Hooks lets other modules react to misbehavior
+type Hooks interface {
+	// OnEquivocation is called once for each accepted equivocation. Its
+	// effects are applied with the evidence module's, e.g. to slash and jail
+	// the validator.
+	//
+	// PRECONDITION: Returning an error rejects the evidence, so hooks should
+	// only fail on internal errors.
+	OnEquivocation(ctx *runtime.Context, record Record) ([]effects.Effect, error)
+}
+
+// EvidenceModule verifies, stores and prunes misbehavior evidence
+type EvidenceModule struct {
+	// moduleStore is the "module/evidence/" view of the state store
+	moduleStore store.BackingStore
+
+	// evidenceStore is the "evidence/" view of moduleStore
+	evidenceStore store.BackingStore
+
+	// validators looks up the accused validators
+	validators capability.ValidatorCapability
+
+	// params are fixed at construction
+	params Params
+
+	// hooks are notified of accepted evidence (may be nil)
+	hooks Hooks
+}
+
+// NewEvidenceModule creates an evidence module over its store capability,
+// looking up accused validators through validators. hooks may be nil.
+func NewEvidenceModule(storeCap capability.StoreCapability, validators capability.ValidatorCapability, params Params, hooks Hooks) (*EvidenceModule, error) {
+	if err := capability.CheckStoreCapability(storeCap, ModuleName); err != nil {
+		return nil, err
+	}
+	if validators == nil {
+		return nil, fmt.Errorf("validator capability cannot be nil")
+	}
+
+	if err := params.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid params: %w", err)
+	}
+
+	return &EvidenceModule{
+		moduleStore:   storeCap,
+		evidenceStore: store.NewPrefixStore(storeCap, []byte(evidencePrefix)),
+		validators:    validators,
+		params:        params,
+		hooks:         hooks,
+	}, nil
+}
+
+// CreateModule creates the evidence module using the module builder
+//
+// Usage:
+//
+//	storeCap, _ := capManager.GrantStoreCapability(evidence.ModuleName)
+//	evidenceMod, _ := evidence.NewEvidenceModule(storeCap, validatorCap, evidence.DefaultParams(), slashingHooks)
+//	mod, _ := evidence.CreateModule(evidenceMod)
+func CreateModule(evidenceMod *EvidenceModule) (module.Module, error) {
+	if evidenceMod == nil {
+		return nil, fmt.Errorf("evidence module cannot be nil")
+	}
+
+	return module.NewModuleBuilder(ModuleName).
+		WithMsgHandler(TypeMsgSubmitEvidence, evidenceMod.handleSubmitEvidence).
+		WithEndBlocker(evidenceMod.endBlock).
+		WithQueryHandler("/params", evidenceMod.handleQueryParams).
+		WithQueryService(QueryServiceEvidence, handleQueryEvidence).
+		WithQueryService(QueryServiceAllEvidence, handleQueryAllEvidence).
+		WithQueryService(QueryServiceValidatorEvidence, handleQueryValidatorEvidence).
+		Build()
+}
+
+// Params returns the module parameters
+func (m *EvidenceModule) Params() Params {
+	return m.params
+}
+
+// Evidence returns the evidence record with hash at height, if any
+func (m *EvidenceModule) Evidence(height uint64, hash []byte) (Record, bool, error) {
+	if m == nil {
+		return Record{}, false, fmt.Errorf("evidence module is nil")
+	}
+	return getRecord(m.moduleStore, evidenceKey(height, hash))
+}
+
+// SubmitEvidence verifies ev and returns the effects recording it as
+// submitted by ctx's account, including those of Hooks.OnEquivocation.
+// Applications call it from a BeginBlocker for misbehavior reported by
+// consensus; MsgSubmitEvidence calls it for reports in transactions.
+//
+// SECURITY: Both votes must be signed by the validator for ctx's chain ID,
+// so evidence cannot be replayed from another chain, and the validator
+// must be known to the validator capability.
+func (m *EvidenceModule) SubmitEvidence(ctx *runtime.Context, ev DuplicateVoteEvidence) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("evidence module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	if err := ev.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	height := ctx.BlockHeight()
+	if ev.Height >= height {
+		return nil, fmt.Errorf("%w: evidence height %d is not before current height %d", ErrInvalidEvidence, ev.Height, height)
+	}
+	if m.params.IsExpired(ev.Height, height) {
+		return nil, fmt.Errorf("%w: evidence at height %d is older than %d blocks", ErrEvidenceExpired, ev.Height, m.params.MaxAgeBlocks)
+	}
+
+	hash := ev.Hash()
+	key := evidenceKey(ev.Height, hash)
+	if _, exists, err := getRecord(m.moduleStore, key); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, fmt.Errorf("%w: %x", ErrEvidenceExists, hash)
+	}
+
+	validator, err := m.validators.GetValidator(ctx.Context(), ev.PubKey)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %x", ErrUnknownValidator, ev.PubKey)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read validator: %w", err)
+	}
+
+	// Signatures are verified last: they are the most expensive check
+	if err := ev.Verify(ctx.ChainID()); err != nil {
+		return nil, err
+	}
+
+	record := Record{
+		Evidence:        ev,
+		Hash:            hash,
+		Power:           validator.Power,
+		Submitter:       ctx.Account(),
+		SubmittedHeight: height,
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode evidence: %w", err)
+	}
+
+	effs := []effects.Effect{
+		effects.NewStateWriteEffect(ModuleName, key, data),
+		effects.NewStateWriteEffect(ModuleName, validatorIndexKey(ev.PubKey, ev.Height, hash), key),
+		effects.NewEventEffect(EventTypeEvidenceSubmitted, map[string][]byte{
+			"hash":      []byte(hex.EncodeToString(hash)),
+			"validator": []byte(hex.EncodeToString(ev.PubKey)),
+			"height":    []byte(strconv.FormatUint(ev.Height, 10)),
+			"power":     []byte(strconv.FormatInt(validator.Power, 10)),
+			"submitter": []byte(ctx.Account()),
+		}),
+	}
+
+	if m.hooks != nil {
+		hookEffs, err := m.hooks.OnEquivocation(ctx, record)
+		if err != nil {
+			return nil, fmt.Errorf("equivocation hook failed for %x: %w", ev.PubKey, err)
+		}
+		effs = append(effs, hookEffs...)
+	}
+	return effs, nil
+}
+
+// handleSubmitEvidence handles MsgSubmitEvidence
+func (m *EvidenceModule) handleSubmitEvidence(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("evidence module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	submitMsg, ok := msg.(*MsgSubmitEvidence)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgSubmitEvidence")
+	}
+
+	if submitMsg.Submitter != ctx.Account() {
+		return nil, fmt.Errorf("%w: %s must be transaction account", types.ErrUnauthorized, submitMsg.Submitter)
+	}
+
+	return m.SubmitEvidence(ctx, submitMsg.Evidence)
+}
+
+// endBlock prunes the evidence that expires after this block: evidence at
+// heights up to the current height minus Params.MaxAgeBlocks.
+//
+// Complexity: O(k) for k pruned records; evidence is stored in height
+// order, so iteration stops at the first record that is kept.
+func (m *EvidenceModule) endBlock(ctx *runtime.Context) ([]effects.Effect, []types.ValidatorUpdate, error) {
+	if m == nil {
+		return nil, nil, fmt.Errorf("evidence module is nil")
+	}
+	if ctx == nil {
+		return nil, nil, fmt.Errorf("context is nil")
+	}
+
+	height := ctx.BlockHeight()
+	if height < m.params.MaxAgeBlocks {
+		return nil, nil, nil
+	}
+	// Records at heights up to height-MaxAgeBlocks expire after this block
+	end := binary.BigEndian.AppendUint64(nil, height-m.params.MaxAgeBlocks+1)
+
+	iter, err := m.evidenceStore.Iterator(nil, end)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to iterate evidence: %w", err)
+	}
+	defer iter.Close()
+
+	var effs []effects.Effect
+	for ; iter.Valid(); iter.Next() {
+		var record Record
+		if err := json.Unmarshal(iter.Value(), &record); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode evidence %x: %w", iter.Key(), err)
+		}
+		ev := record.Evidence
+		effs = append(effs,
+			effects.NewStateDeleteEffect(ModuleName, evidenceKey(ev.Height, record.Hash)),
+			effects.NewStateDeleteEffect(ModuleName, validatorIndexKey(ev.PubKey, ev.Height, record.Hash)),
+			effects.NewEventEffect(EventTypeEvidencePruned, map[string][]byte{
+				"hash":   []byte(hex.EncodeToString(record.Hash)),
+				"height": []byte(strconv.FormatUint(ev.Height, 10)),
+			}),
+		)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, nil, fmt.Errorf("failed to iterate evidence: %w", err)
+	}
+	return effs, nil, nil
+}
+
+// handleQueryParams returns the module parameters as JSON
+func (m *EvidenceModule) handleQueryParams(ctx context.Context, path string, data []byte) ([]byte, error) {
+	if m == nil {
+		return nil, fmt.Errorf("evidence module is nil")
+	}
+	return json.Marshal(m.Params())
+}
+
+// getRecord reads the evidence record at key of the module namespace view s
+func getRecord(s store.BackingStore, key []byte) (Record, bool, error) {
+	data, err := s.Get(key)
+	if errors.Is(err, store.ErrNotFound) || (err == nil && data == nil) {
+		return Record{}, false, nil
+	}
+	if err != nil {
+		return Record{}, false, fmt.Errorf("failed to read evidence: %w", err)
+	}
+
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return Record{}, false, fmt.Errorf("failed to decode evidence %x: %w", key, err)
+	}
+	return record, true, nil
+}
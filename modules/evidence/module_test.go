@@ -0,0 +1,418 @@
+package evidence
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/light"
+	"github.com/blockberries/punnet-sdk/query"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+	punnettesting "github.com/blockberries/punnet-sdk/testing"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+const testChainID = "test-chain"
+
+type testEnv struct {
+	*punnettesting.EffectEnv
+	mod       *EvidenceModule
+	hooks     *testHooks
+	validator crypto.PrivateKey
+}
+
+// testHooks records equivocations
+type testHooks struct {
+	records []Record
+	err     error
+}
+
+func (h *testHooks) OnEquivocation(ctx *runtime.Context, record Record) ([]effects.Effect, error) {
+	if h.err != nil {
+		return nil, h.err
+	}
+	h.records = append(h.records, record)
+	return []effects.Effect{effects.NewEventEffect("test.slash", map[string][]byte{"power": []byte("10")})}, nil
+}
+
+func testParams() Params {
+	return Params{MaxAgeBlocks: 10}
+}
+
+// setupTestEvidenceModule creates an evidence module with one validator of
+// power 10
+func setupTestEvidenceModule(t *testing.T) *testEnv {
+	t.Helper()
+
+	env := punnettesting.NewEffectEnv(t)
+	capMgr := env.CapabilityManager()
+	if err := capMgr.RegisterModule(ModuleName); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	validatorCap, err := capMgr.GrantValidatorCapability(ModuleName)
+	if err != nil {
+		t.Fatalf("failed to grant validator capability: %v", err)
+	}
+
+	priv, err := crypto.GeneratePrivateKey(crypto.AlgorithmEd25519)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	if err := validatorCap.SetValidator(context.Background(), store.NewValidator(priv.PublicKey().Bytes(), 10, "operator")); err != nil {
+		t.Fatalf("failed to set validator: %v", err)
+	}
+
+	hooks := &testHooks{}
+	evidenceMod, err := NewEvidenceModule(env.GrantStore(t, ModuleName), validatorCap, testParams(), hooks)
+	if err != nil {
+		t.Fatalf("failed to create evidence module: %v", err)
+	}
+	return &testEnv{EffectEnv: env, mod: evidenceMod, hooks: hooks, validator: priv}
+}
+
+func setupTestContext(t *testing.T, height uint64, account types.AccountName) *runtime.Context {
+	t.Helper()
+
+	header := runtime.NewBlockHeader(height, time.Now(), testChainID, []byte("proposer"))
+	ctx, err := runtime.NewContext(context.Background(), header, account)
+	if err != nil {
+		t.Fatalf("failed to create context: %v", err)
+	}
+	return ctx
+}
+
+// blockHash returns a distinct 32-byte block hash
+func blockHash(b byte) []byte {
+	return bytes.Repeat([]byte{b}, light.HashSize)
+}
+
+// signVote signs a vote for blockHash at height on chainID
+func signVote(t *testing.T, priv crypto.PrivateKey, chainID string, height uint64, blockHash []byte) Vote {
+	t.Helper()
+
+	sig, err := priv.Sign(light.VoteSignBytes(chainID, height, blockHash))
+	if err != nil {
+		t.Fatalf("failed to sign vote: %v", err)
+	}
+	return Vote{BlockHash: blockHash, Signature: sig}
+}
+
+// equivocation returns evidence that priv voted for two blocks at height
+func equivocation(t *testing.T, priv crypto.PrivateKey, height uint64) DuplicateVoteEvidence {
+	t.Helper()
+
+	return DuplicateVoteEvidence{
+		Algorithm: crypto.AlgorithmEd25519,
+		PubKey:    priv.PublicKey().Bytes(),
+		Height:    height,
+		VoteA:     signVote(t, priv, testChainID, height, blockHash(1)),
+		VoteB:     signVote(t, priv, testChainID, height, blockHash(2)),
+	}
+}
+
+// submit runs MsgSubmitEvidence at height and applies its effects
+func (env *testEnv) submit(t *testing.T, height uint64, ev DuplicateVoteEvidence) error {
+	t.Helper()
+
+	msg := &MsgSubmitEvidence{Submitter: "reporter", Evidence: ev}
+	if err := msg.ValidateBasic(); err != nil {
+		return err
+	}
+	ctx := setupTestContext(t, height, "reporter")
+	effs, err := env.mod.handleSubmitEvidence(ctx, msg)
+	if err != nil {
+		return err
+	}
+	env.Apply(t, ctx, effs)
+	return nil
+}
+
+// endBlock runs the end blocker at height and applies its effects
+func (env *testEnv) endBlock(t *testing.T, height uint64) []effects.Effect {
+	t.Helper()
+
+	ctx := setupTestContext(t, height, "system")
+	effs, updates, err := env.mod.endBlock(ctx)
+	if err != nil {
+		t.Fatalf("end block failed: %v", err)
+	}
+	if len(updates) != 0 {
+		t.Fatalf("expected no validator updates, got %d", len(updates))
+	}
+	env.Apply(t, ctx, effs)
+	return effs
+}
+
+// query runs a query service against the state store
+func (env *testEnv) query(t *testing.T, path string, handler query.Handler, req, resp any) error {
+	t.Helper()
+
+	server, err := query.NewServer(env.Store())
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	if err := server.RegisterHandler(path, handler); err != nil {
+		t.Fatalf("RegisterHandler() error = %v", err)
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("failed to encode request: %v", err)
+	}
+	result, err := server.Query(context.Background(), query.Request{Path: path, Data: data})
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(result.Value, resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return nil
+}
+
+func TestDuplicateVoteEvidence_ValidateBasic(t *testing.T) {
+	priv, err := crypto.GeneratePrivateKey(crypto.AlgorithmEd25519)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(ev *DuplicateVoteEvidence)
+	}{
+		{name: "unknown algorithm", modify: func(ev *DuplicateVoteEvidence) { ev.Algorithm = "rsa" }},
+		{name: "empty public key", modify: func(ev *DuplicateVoteEvidence) { ev.PubKey = nil }},
+		{name: "oversized public key", modify: func(ev *DuplicateVoteEvidence) { ev.PubKey = make([]byte, MaxPubKeySize+1) }},
+		{name: "zero height", modify: func(ev *DuplicateVoteEvidence) { ev.Height = 0 }},
+		{name: "short block hash", modify: func(ev *DuplicateVoteEvidence) { ev.VoteB.BlockHash = []byte{1} }},
+		{name: "missing signature", modify: func(ev *DuplicateVoteEvidence) { ev.VoteA.Signature = nil }},
+		{name: "oversized signature", modify: func(ev *DuplicateVoteEvidence) { ev.VoteA.Signature = make([]byte, MaxSignatureSize+1) }},
+		{name: "same block", modify: func(ev *DuplicateVoteEvidence) { ev.VoteB = ev.VoteA }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev := equivocation(t, priv, 5)
+			tt.modify(&ev)
+			if err := ev.ValidateBasic(); !errors.Is(err, ErrInvalidEvidence) {
+				t.Fatalf("expected ErrInvalidEvidence, got %v", err)
+			}
+		})
+	}
+
+	ev := equivocation(t, priv, 5)
+	if err := ev.ValidateBasic(); err != nil {
+		t.Fatalf("ValidateBasic() error = %v", err)
+	}
+	if err := ev.Verify(testChainID); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if err := ev.Verify("other-chain"); !errors.Is(err, ErrInvalidEvidence) {
+		t.Fatalf("expected votes of another chain to fail verification, got %v", err)
+	}
+
+	swapped := ev
+	swapped.VoteA, swapped.VoteB = ev.VoteB, ev.VoteA
+	if !bytes.Equal(ev.Hash(), swapped.Hash()) {
+		t.Error("expected the hash to be independent of vote order")
+	}
+	later := equivocation(t, priv, 6)
+	if bytes.Equal(ev.Hash(), later.Hash()) {
+		t.Error("expected equivocations at different heights to hash differently")
+	}
+}
+
+func TestSubmitEvidence(t *testing.T) {
+	env := setupTestEvidenceModule(t)
+	ev := equivocation(t, env.validator, 5)
+
+	if err := env.submit(t, 6, ev); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+
+	record, ok, err := env.mod.Evidence(5, ev.Hash())
+	if err != nil || !ok {
+		t.Fatalf("expected stored evidence, got ok=%v err=%v", ok, err)
+	}
+	if record.Power != 10 || record.Submitter != "reporter" || record.SubmittedHeight != 6 {
+		t.Fatalf("unexpected record: %+v", record)
+	}
+	if len(env.hooks.records) != 1 || !bytes.Equal(env.hooks.records[0].Hash, ev.Hash()) {
+		t.Fatalf("expected one equivocation hook call, got %d", len(env.hooks.records))
+	}
+
+	// The same equivocation is not accepted twice, whichever votes prove it
+	third := ev
+	third.VoteA, third.VoteB = ev.VoteB, signVote(t, env.validator, testChainID, 5, blockHash(3))
+	if err := env.submit(t, 7, third); !errors.Is(err, ErrEvidenceExists) {
+		t.Fatalf("expected ErrEvidenceExists, got %v", err)
+	}
+	if len(env.hooks.records) != 1 {
+		t.Fatal("expected duplicate evidence not to reach hooks")
+	}
+}
+
+func TestSubmitEvidence_Rejected(t *testing.T) {
+	env := setupTestEvidenceModule(t)
+	stranger, err := crypto.GeneratePrivateKey(crypto.AlgorithmEd25519)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	forged := equivocation(t, env.validator, 5)
+	forged.VoteB = signVote(t, stranger, testChainID, 5, blockHash(2))
+	otherChain := equivocation(t, env.validator, 5)
+	otherChain.VoteA = signVote(t, env.validator, "other-chain", 5, blockHash(1))
+
+	tests := []struct {
+		name    string
+		height  uint64
+		ev      DuplicateVoteEvidence
+		wantErr error
+	}{
+		{name: "forged vote", height: 6, ev: forged, wantErr: ErrInvalidEvidence},
+		{name: "vote of another chain", height: 6, ev: otherChain, wantErr: ErrInvalidEvidence},
+		{name: "current height", height: 5, ev: equivocation(t, env.validator, 5), wantErr: ErrInvalidEvidence},
+		{name: "expired", height: 16, ev: equivocation(t, env.validator, 5), wantErr: ErrEvidenceExpired},
+		{name: "unknown validator", height: 6, ev: equivocation(t, stranger, 5), wantErr: ErrUnknownValidator},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := env.submit(t, tt.height, tt.ev); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// The oldest evidence is still accepted
+	if err := env.submit(t, 15, equivocation(t, env.validator, 5)); err != nil {
+		t.Fatalf("expected evidence MaxAgeBlocks old to be accepted, got %v", err)
+	}
+
+	// Hook failures reject the evidence
+	env.hooks.err = errors.New("slashing failed")
+	if err := env.submit(t, 15, equivocation(t, env.validator, 6)); err == nil {
+		t.Fatal("expected hook failure to reject evidence")
+	}
+
+	// Only the transaction account may submit
+	msg := &MsgSubmitEvidence{Submitter: "reporter", Evidence: equivocation(t, env.validator, 7)}
+	if _, err := env.mod.handleSubmitEvidence(setupTestContext(t, 8, "mallory"), msg); !errors.Is(err, types.ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestEndBlock_Prune(t *testing.T) {
+	env := setupTestEvidenceModule(t)
+	for _, height := range []uint64{3, 4, 5} {
+		if err := env.submit(t, 6, equivocation(t, env.validator, height)); err != nil {
+			t.Fatalf("submit at %d failed: %v", height, err)
+		}
+	}
+
+	// Before evidence at height 3 reaches MaxAgeBlocks, nothing is pruned
+	if effs := env.endBlock(t, 12); len(effs) != 0 {
+		t.Fatalf("expected nothing pruned, got %d effects", len(effs))
+	}
+
+	// At height 14 evidence at heights 3 and 4 expires for the next block
+	env.endBlock(t, 14)
+	for height, want := range map[uint64]bool{3: false, 4: false, 5: true} {
+		ev := equivocation(t, env.validator, height)
+		_, ok, err := env.mod.Evidence(height, ev.Hash())
+		if err != nil {
+			t.Fatalf("Evidence() error = %v", err)
+		}
+		if ok != want {
+			t.Errorf("evidence at height %d stored = %v, want %v", height, ok, want)
+		}
+	}
+
+	// Pruned evidence cannot be resubmitted: it has expired
+	if err := env.submit(t, 15, equivocation(t, env.validator, 4)); !errors.Is(err, ErrEvidenceExpired) {
+		t.Fatalf("expected ErrEvidenceExpired, got %v", err)
+	}
+
+	var resp QueryEvidenceListResponse
+	if err := env.query(t, QueryServiceValidatorEvidence, handleQueryValidatorEvidence, QueryValidatorEvidenceRequest{PubKey: env.validator.PublicKey().Bytes()}, &resp); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(resp.Records) != 1 || resp.Records[0].Evidence.Height != 5 {
+		t.Fatalf("expected the validator index to be pruned too, got %d records", len(resp.Records))
+	}
+}
+
+func TestQueryEvidence(t *testing.T) {
+	env := setupTestEvidenceModule(t)
+	for _, height := range []uint64{5, 3, 4} {
+		if err := env.submit(t, 6, equivocation(t, env.validator, height)); err != nil {
+			t.Fatalf("submit at %d failed: %v", height, err)
+		}
+	}
+
+	ev := equivocation(t, env.validator, 4)
+	var record QueryEvidenceResponse
+	if err := env.query(t, QueryServiceEvidence, handleQueryEvidence, QueryEvidenceRequest{Height: 4, Hash: ev.Hash()}, &record); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if !bytes.Equal(record.Record.Hash, ev.Hash()) {
+		t.Fatalf("unexpected record: %+v", record.Record)
+	}
+	if err := env.query(t, QueryServiceEvidence, handleQueryEvidence, QueryEvidenceRequest{Height: 9, Hash: ev.Hash()}, &record); !errors.Is(err, types.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	// Evidence is listed in height order, page by page
+	var heights []uint64
+	req := QueryAllEvidenceRequest{Pagination: &query.PageRequest{Limit: 2}}
+	for {
+		var resp QueryEvidenceListResponse
+		if err := env.query(t, QueryServiceAllEvidence, handleQueryAllEvidence, req, &resp); err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		for _, r := range resp.Records {
+			heights = append(heights, r.Evidence.Height)
+		}
+		if len(resp.Pagination.NextKey) == 0 {
+			break
+		}
+		req.Pagination.Key = resp.Pagination.NextKey
+	}
+	if len(heights) != 3 || heights[0] != 3 || heights[1] != 4 || heights[2] != 5 {
+		t.Fatalf("expected evidence at heights [3 4 5], got %v", heights)
+	}
+
+	var resp QueryEvidenceListResponse
+	if err := env.query(t, QueryServiceValidatorEvidence, handleQueryValidatorEvidence, QueryValidatorEvidenceRequest{PubKey: []byte("nobody")}, &resp); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if len(resp.Records) != 0 {
+		t.Fatalf("expected no evidence against an unknown key, got %d", len(resp.Records))
+	}
+}
+
+func TestNewEvidenceModule(t *testing.T) {
+	env := setupTestEvidenceModule(t)
+	if _, err := NewEvidenceModule(nil, env.mod.validators, testParams(), nil); err == nil {
+		t.Error("expected error for nil store capability")
+	}
+	if _, err := NewEvidenceModule(env.GrantStore(t, ModuleName), nil, testParams(), nil); err == nil {
+		t.Error("expected error for nil validator capability")
+	}
+	if _, err := NewEvidenceModule(env.GrantStore(t, ModuleName), env.mod.validators, Params{}, nil); err == nil {
+		t.Error("expected error for zero max age")
+	}
+
+	mod, err := CreateModule(env.mod)
+	if err != nil {
+		t.Fatalf("CreateModule() error = %v", err)
+	}
+	if mod.Name() != ModuleName {
+		t.Errorf("Name() = %q, want %q", mod.Name(), ModuleName)
+	}
+}
@@ -0,0 +1,36 @@
+package evidence
+
+import "fmt"
+
+// DefaultMaxAgeBlocks is the default Params.MaxAgeBlocks
+const DefaultMaxAgeBlocks = uint64(100_000)
+
+// Params configures the evidence module
+type Params struct {
+	// MaxAgeBlocks is how many blocks after its height evidence is accepted
+	// and kept: evidence at height H is accepted up to height
+	// H+MaxAgeBlocks and pruned at the end of that block.
+	//
+	// RATIONALE: Stake that has finished unbonding can no longer be slashed,
+	// so MaxAgeBlocks should not exceed the unbonding period in blocks.
+	MaxAgeBlocks uint64 `json:"max_age_blocks"`
+}
+
+// DefaultParams returns the default parameters
+func DefaultParams() Params {
+	return Params{MaxAgeBlocks: DefaultMaxAgeBlocks}
+}
+
+// ValidateBasic performs stateless validation
+func (p Params) ValidateBasic() error {
+	if p.MaxAgeBlocks == 0 {
+		return fmt.Errorf("max age blocks must be positive")
+	}
+	return nil
+}
+
+// IsExpired reports whether evidence at height is no longer accepted at
+// currentHeight
+func (p Params) IsExpired(height, currentHeight uint64) bool {
+	return currentHeight > height && currentHeight-height > p.MaxAgeBlocks
+}
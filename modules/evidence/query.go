@@ -0,0 +1,137 @@
+package evidence
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/query"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Height-aware query service paths. Requests and responses are JSON; list
+// services page with query.PageRequest.
+const (
+	QueryServiceEvidence          = "/evidence/evidence"
+	QueryServiceAllEvidence       = "/evidence/all"
+	QueryServiceValidatorEvidence = "/evidence/validator"
+)
+
+// QueryEvidenceRequest is the request for QueryServiceEvidence
+type QueryEvidenceRequest struct {
+	Height uint64 `json:"height"`
+	Hash   []byte `json:"hash"`
+}
+
+// QueryEvidenceResponse is the response for QueryServiceEvidence
+type QueryEvidenceResponse struct {
+	Record Record `json:"record"`
+}
+
+// QueryAllEvidenceRequest is the request for QueryServiceAllEvidence
+type QueryAllEvidenceRequest struct {
+	Pagination *query.PageRequest `json:"pagination,omitempty"`
+}
+
+// QueryValidatorEvidenceRequest is the request for
+// QueryServiceValidatorEvidence
+type QueryValidatorEvidenceRequest struct {
+	PubKey     []byte             `json:"pub_key"`
+	Pagination *query.PageRequest `json:"pagination,omitempty"`
+}
+
+// QueryEvidenceListResponse is the response for QueryServiceAllEvidence and
+// QueryServiceValidatorEvidence
+type QueryEvidenceListResponse struct {
+	Records    []Record            `json:"records"`
+	Pagination *query.PageResponse `json:"pagination"`
+}
+
+// decodeRequest unmarshals a JSON query request
+func decodeRequest(data []byte, req any) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, req); err != nil {
+		return fmt.Errorf("invalid evidence query: %w", err)
+	}
+	return nil
+}
+
+// handleQueryEvidence serves QueryServiceEvidence from the state pinned at
+// the query height. Unknown or pruned evidence is ErrNotFound.
+func handleQueryEvidence(ctx *query.Context, data []byte) ([]byte, error) {
+	var req QueryEvidenceRequest
+	if err := decodeRequest(data, &req); err != nil {
+		return nil, err
+	}
+
+	record, ok, err := getRecord(capability.ModuleStore(ctx.Store(), ModuleName), evidenceKey(req.Height, req.Hash))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: evidence %x at height %d", types.ErrNotFound, req.Hash, req.Height)
+	}
+
+	return json.Marshal(QueryEvidenceResponse{Record: record})
+}
+
+// handleQueryAllEvidence serves QueryServiceAllEvidence, in evidence height
+// order
+func handleQueryAllEvidence(ctx *query.Context, data []byte) ([]byte, error) {
+	var req QueryAllEvidenceRequest
+	if err := decodeRequest(data, &req); err != nil {
+		return nil, err
+	}
+
+	s := store.NewPrefixStore(capability.ModuleStore(ctx.Store(), ModuleName), []byte(evidencePrefix))
+	resp := QueryEvidenceListResponse{Records: []Record{}}
+	page, err := query.Paginate(s, req.Pagination, func(key, value []byte) error {
+		var record Record
+		if err := json.Unmarshal(value, &record); err != nil {
+			return fmt.Errorf("failed to decode evidence %x: %w", key, err)
+		}
+		resp.Records = append(resp.Records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Pagination = page
+
+	return json.Marshal(resp)
+}
+
+// handleQueryValidatorEvidence serves QueryServiceValidatorEvidence: the
+// evidence against a validator in evidence height order
+func handleQueryValidatorEvidence(ctx *query.Context, data []byte) ([]byte, error) {
+	var req QueryValidatorEvidenceRequest
+	if err := decodeRequest(data, &req); err != nil {
+		return nil, err
+	}
+	if len(req.PubKey) == 0 || len(req.PubKey) > MaxPubKeySize {
+		return nil, fmt.Errorf("public key must be 1 to %d bytes", MaxPubKeySize)
+	}
+
+	moduleStore := capability.ModuleStore(ctx.Store(), ModuleName)
+	resp := QueryEvidenceListResponse{Records: []Record{}}
+	page, err := query.Paginate(store.NewPrefixStore(moduleStore, validatorIndexPrefix(req.PubKey)), req.Pagination, func(key, value []byte) error {
+		record, ok, err := getRecord(moduleStore, value)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("validator index entry %x has no evidence", key)
+		}
+		resp.Records = append(resp.Records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Pagination = page
+
+	return json.Marshal(resp)
+}
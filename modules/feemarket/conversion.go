@@ -0,0 +1,56 @@
+package feemarket
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// PriceOracle prices alternate fee denoms in Params.Denom.
+// The oracle module's PriceCapability implements it.
+type PriceOracle interface {
+	// ConversionRate returns the rate at which denom converts into the fee
+	// denom (rate.Numerator fee denom units per rate.Denominator units of
+	// denom) for a transaction executing in ctx's block, and the reference
+	// rate the transaction's FeeSlippage is measured against.
+	ConversionRate(ctx *runtime.Context, denom string) (rate, reference types.Ratio, err error)
+}
+
+// withinSlippage reports whether rate deviates from reference by at most
+// slippage × reference, in either direction:
+//
+//	|rate − reference| ≤ reference × slippage
+//
+// PRECONDITION: all denominators are non-zero
+//
+// Complexity: O(1); products are computed exactly with big integers
+func withinSlippage(rate, reference, slippage types.Ratio) bool {
+	// Multiplying both sides by rate.D × reference.D × slippage.D:
+	// |rate.N × reference.D − reference.N × rate.D| × slippage.D ≤ reference.N × rate.D × slippage.N
+	lhs := new(big.Int).Sub(mul(rate.Numerator, reference.Denominator), mul(reference.Numerator, rate.Denominator))
+	lhs.Abs(lhs)
+	lhs.Mul(lhs, new(big.Int).SetUint64(slippage.Denominator))
+
+	rhs := mul(reference.Numerator, rate.Denominator)
+	rhs.Mul(rhs, new(big.Int).SetUint64(slippage.Numerator))
+
+	return lhs.Cmp(rhs) <= 0
+}
+
+// convert returns amount × rate rounded down, saturating at math.MaxUint64
+// PRECONDITION: rate.Denominator > 0
+func convert(amount uint64, rate types.Ratio) uint64 {
+	q := mul(amount, rate.Numerator)
+	q.Quo(q, new(big.Int).SetUint64(rate.Denominator))
+	if !q.IsUint64() {
+		return math.MaxUint64
+	}
+	return q.Uint64()
+}
+
+// mul returns a × b as a big integer
+func mul(a, b uint64) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(a), new(big.Int).SetUint64(b))
+}
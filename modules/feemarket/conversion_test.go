@@ -0,0 +1,107 @@
+package feemarket
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+var errNoPrice = errors.New("no price")
+
+// testOracle prices denoms at fixed rates
+type testOracle map[string][2]types.Ratio
+
+func (o testOracle) ConversionRate(ctx *runtime.Context, denom string) (types.Ratio, types.Ratio, error) {
+	rates, ok := o[denom]
+	if !ok {
+		return types.Ratio{}, types.Ratio{}, errNoPrice
+	}
+	return rates[0], rates[1], nil
+}
+
+func ratio(n, d uint64) types.Ratio {
+	return types.Ratio{Numerator: n, Denominator: d}
+}
+
+func TestWithinSlippage(t *testing.T) {
+	tests := []struct {
+		name      string
+		rate      types.Ratio
+		reference types.Ratio
+		slippage  types.Ratio
+		want      bool
+	}{
+		{name: "equal, zero slippage", rate: ratio(2, 1), reference: ratio(4, 2), slippage: ratio(0, 1), want: true},
+		{name: "up, zero slippage", rate: ratio(201, 100), reference: ratio(2, 1), slippage: ratio(0, 1), want: false},
+		{name: "up at limit", rate: ratio(202, 100), reference: ratio(2, 1), slippage: ratio(1, 100), want: true},
+		{name: "up beyond limit", rate: ratio(203, 100), reference: ratio(2, 1), slippage: ratio(1, 100), want: false},
+		{name: "down at limit", rate: ratio(198, 100), reference: ratio(2, 1), slippage: ratio(1, 100), want: true},
+		{name: "down beyond limit", rate: ratio(197, 100), reference: ratio(2, 1), slippage: ratio(1, 100), want: false},
+		{name: "large values", rate: ratio(1<<63, 1), reference: ratio(1<<63, 1), slippage: ratio(0, 1<<63), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withinSlippage(tt.rate, tt.reference, tt.slippage); got != tt.want {
+				t.Fatalf("withinSlippage = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnteHandler_ConvertsFee(t *testing.T) {
+	oracle := testOracle{
+		// 1 usdc = 2.5 stake
+		"usdc": {ratio(5, 2), ratio(5, 2)},
+		// 1 atom = 10 stake, up from 9.9 in this block
+		"atom": {ratio(10, 1), ratio(99, 10)},
+	}
+	feeMod, _ := setupTestFeeMarket(t, testParams(), oracle)
+	ante := feeMod.AnteHandler()
+
+	slippageTx := func(fee types.Coin, slippage types.Ratio) *types.Transaction {
+		tx := testTx(10, types.NewCoins(fee))
+		tx.FeeSlippage = slippage
+		return tx
+	}
+
+	tests := []struct {
+		name          string
+		tx            *types.Transaction
+		wantErr       error
+		wantBurned    uint64
+		wantCollected uint64
+	}{
+		// 1000 stake required, half burned
+		{name: "exact conversion", tx: slippageTx(types.NewCoin("usdc", 400), ratio(0, 1)), wantBurned: 200, wantCollected: 200},
+		{name: "with tip", tx: slippageTx(types.NewCoin("usdc", 500), ratio(0, 1)), wantBurned: 200, wantCollected: 300},
+		{name: "insufficient after conversion", tx: slippageTx(types.NewCoin("usdc", 399), ratio(0, 1)), wantErr: ErrInsufficientFee},
+		{name: "within slippage", tx: slippageTx(types.NewCoin("atom", 100), ratio(2, 100)), wantBurned: 50, wantCollected: 50},
+		{name: "slippage exceeded", tx: slippageTx(types.NewCoin("atom", 100), ratio(1, 100)), wantErr: ErrFeeSlippageExceeded},
+		{name: "unpriced denom", tx: slippageTx(types.NewCoin("eth", 100), ratio(1, 1)), wantErr: errNoPrice},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			effs, err := ante(setupTestContext(t, false), tt.tx)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ante failed: %v", err)
+			}
+
+			denom := tt.tx.Fee.Amount[0].Denom
+			got := transfers(effs, denom)
+			if got[DefaultBurnAccount] != tt.wantBurned || got[DefaultFeeCollector] != tt.wantCollected {
+				t.Fatalf("expected %d%s burned and %d%s collected, got %d and %d",
+					tt.wantBurned, denom, tt.wantCollected, denom, got[DefaultBurnAccount], got[DefaultFeeCollector])
+			}
+		})
+	}
+}
@@ -0,0 +1,409 @@
+// Package feemarket provides a dynamic base fee in the style of EIP-1559.
+//
+// Every transaction must pay at least the current base fee per unit of gas
+// for its whole gas limit. The module's ante handler enforces this and
+// charges the fee: a configurable share of the base fee portion is sent to a
+// burn account and the rest, including any tip, to a fee collector. At the
+// end of each block the base fee moves toward the price at which blocks use
+// Params.TargetBlockGas.
+//
+// With a PriceOracle, fees may also be paid in other denoms, converted into
+// Params.Denom at the oracle rate. The transaction's signed FeeSlippage
+// bounds how far that rate may deviate from the oracle's reference rate.
+//
+// Usage:
+//
+//	priceCap, _ := oracleMod.GrantPriceCapability(feemarket.ModuleName)
+//	storeCap, _ := capManager.GrantStoreCapability(feemarket.ModuleName)
+//	feeMod, _ := feemarket.NewFeeMarketModule(storeCap, feemarket.DefaultParams(), priceCap)
+//	mod, _ := feemarket.CreateModule(feeMod)
+//	app, _ := runtime.NewApplication(runtime.ApplicationConfig{
+//		AnteHandler: feeMod.AnteHandler(),
+//		// ...
+//	})
+package feemarket
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/effects"
+	sdkerrors "github.com/blockberries/punnet-sdk/errors"
+	"github.com/blockberries/punnet-sdk/module"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Module name
+const ModuleName = "feemarket"
+
+var (
+	// ErrGasLimitRequired is returned for a transaction without a gas limit
+	ErrGasLimitRequired = errors.New("gas limit required")
+
+	// ErrInvalidFeeDenom is returned when a fee is not paid in a single
+	// denom that is Params.Denom or priced by the oracle
+	ErrInvalidFeeDenom = errors.New("invalid fee denom")
+
+	// ErrInsufficientFee is returned when a fee is below base fee × gas limit
+	ErrInsufficientFee = errors.New("insufficient fee")
+
+	// ErrFeeSlippageExceeded is returned when the oracle rate of a fee denom
+	// deviates from its reference rate by more than the transaction's FeeSlippage
+	ErrFeeSlippageExceeded = errors.New("fee conversion slippage exceeded")
+)
+
+// Fee market module error codes, in the ModuleName codespace.
+func init() {
+	sdkerrors.MustRegister(ModuleName, 2, ErrGasLimitRequired)
+	sdkerrors.MustRegister(ModuleName, 3, ErrInvalidFeeDenom)
+	sdkerrors.MustRegister(ModuleName, 4, ErrInsufficientFee)
+	sdkerrors.MustRegister(ModuleName, 5, ErrFeeSlippageExceeded)
+}
+
+// Event types
+const (
+	EventTypeFee     = "feemarket.fee"
+	EventTypeBaseFee = "feemarket.base_fee"
+)
+
+// State keys, relative to the module namespace
+var (
+	// baseFeeKey holds the current base fee (8-byte big-endian)
+	baseFeeKey = []byte("base_fee")
+
+	// blockGasKey holds the gas limits summed over the current block's
+	// charged transactions (8-byte big-endian); deleted at end block
+	blockGasKey = []byte("block_gas")
+)
+
+// FeeMarketModule tracks block gas and adjusts the base fee
+type FeeMarketModule struct {
+	// moduleStore is the "module/feemarket/" view of the state store
+	moduleStore store.BackingStore
+
+	// params are fixed at construction
+	params Params
+
+	// oracle prices alternate fee denoms (nil: fees are paid in params.Denom only)
+	oracle PriceOracle
+}
+
+// NewFeeMarketModule creates a fee market module over its store capability.
+// oracle may be nil, in which case fees must be paid in params.Denom.
+func NewFeeMarketModule(storeCap capability.StoreCapability, params Params, oracle PriceOracle) (*FeeMarketModule, error) {
+	if err := capability.CheckStoreCapability(storeCap, ModuleName); err != nil {
+		return nil, err
+	}
+
+	if err := params.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid params: %w", err)
+	}
+
+	return &FeeMarketModule{
+		moduleStore: storeCap,
+		params:      params,
+		oracle:      oracle,
+	}, nil
+}
+
+// CreateModule creates the fee market module using the module builder
+//
+// Usage:
+//
+//	storeCap, _ := capManager.GrantStoreCapability(feemarket.ModuleName)
+//	feeMod, _ := feemarket.NewFeeMarketModule(storeCap, feemarket.DefaultParams(), nil)
+//	mod, _ := feemarket.CreateModule(feeMod)
+func CreateModule(feeMod *FeeMarketModule) (module.Module, error) {
+	if feeMod == nil {
+		return nil, fmt.Errorf("fee market module cannot be nil")
+	}
+
+	return module.NewModuleBuilder(ModuleName).
+		WithEndBlocker(feeMod.endBlock).
+		WithQueryHandler("/base_fee", feeMod.handleQueryBaseFee).
+		WithQueryHandler("/params", feeMod.handleQueryParams).
+		Build()
+}
+
+// Params returns the module parameters
+func (m *FeeMarketModule) Params() Params {
+	return m.params
+}
+
+// BaseFee returns the current base fee per unit of gas
+func (m *FeeMarketModule) BaseFee() (uint64, error) {
+	if m == nil {
+		return 0, fmt.Errorf("fee market module is nil")
+	}
+
+	baseFee, ok, err := m.loadUint64(baseFeeKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read base fee: %w", err)
+	}
+	if !ok {
+		return m.params.InitialBaseFee, nil
+	}
+	return baseFee, nil
+}
+
+// BlockGas returns the gas charged so far in the current block
+func (m *FeeMarketModule) BlockGas() (uint64, error) {
+	if m == nil {
+		return 0, fmt.Errorf("fee market module is nil")
+	}
+
+	blockGas, _, err := m.loadUint64(blockGasKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read block gas: %w", err)
+	}
+	return blockGas, nil
+}
+
+// loadUint64 reads the 8-byte big-endian value at key, reporting whether it exists
+func (m *FeeMarketModule) loadUint64(key []byte) (uint64, bool, error) {
+	data, err := m.moduleStore.Get(key)
+	if errors.Is(err, store.ErrNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	if data == nil {
+		return 0, false, nil
+	}
+	if len(data) != 8 {
+		return 0, false, fmt.Errorf("invalid %s length %d", key, len(data))
+	}
+	return binary.BigEndian.Uint64(data), true, nil
+}
+
+// uint64Effect returns the effect storing v at key
+func uint64Effect(key []byte, v uint64) effects.Effect {
+	return effects.NewStateWriteEffect(ModuleName, key, binary.BigEndian.AppendUint64(nil, v))
+}
+
+// AnteHandler returns the runtime.AnteHandler enforcing and charging fees.
+//
+// A transaction must set a gas limit and pay its fee in a single denom:
+// Params.Denom, or a denom the oracle prices. An alternate denom is
+// converted into Params.Denom at the oracle rate, which must be within the
+// transaction's FeeSlippage of the oracle's reference rate. The (converted)
+// fee must be at least BaseFee() × GasLimit.
+//
+// The whole fee is charged to the transaction's fee source
+// (types.Transaction.FeeSource: its fee granter, fee payer or account), in
+// the denom it was paid in: the burn share of BaseFee() × GasLimit goes to
+// Params.BurnAccount and the remainder to Params.FeeCollector. A priority
+// tip (types.Transaction.Tip) is charged on top, in full, to
+// Params.FeeCollector; it does not count towards the required fee. The gas
+// limit is added to BlockGas().
+//
+// SECURITY: The gas limit, not the gas used, is charged and counted, so the
+// fee is known before execution and cannot be reduced by the messages.
+// Charging and counting are effects the runtime applies before the messages
+// run and keeps when a message fails, and the nonce advances with them, so a
+// transaction whose messages fail still pays and cannot be replayed to fill
+// blocks for free.
+//
+// SECURITY: FeeSlippage is part of the SignDoc, so the signer, not the block
+// proposer or the price feeders, bounds the rate the fee is converted at.
+func (m *FeeMarketModule) AnteHandler() runtime.AnteHandler {
+	return func(ctx *runtime.Context, tx *types.Transaction) ([]effects.Effect, error) {
+		if m == nil {
+			return nil, fmt.Errorf("fee market module is nil")
+		}
+		if ctx == nil {
+			return nil, fmt.Errorf("context cannot be nil")
+		}
+		if tx == nil {
+			return nil, fmt.Errorf("transaction cannot be nil")
+		}
+
+		gasLimit := tx.Fee.GasLimit
+		if gasLimit == 0 {
+			return nil, ErrGasLimitRequired
+		}
+
+		fee, err := m.feeCoin(tx.Fee.Amount)
+		if err != nil {
+			return nil, err
+		}
+
+		// value is the fee in Params.Denom
+		value := fee.Amount
+		if fee.Denom != m.params.Denom {
+			value, err = m.convertFee(ctx, fee, tx.FeeSlippage)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		baseFee, err := m.BaseFee()
+		if err != nil {
+			return nil, err
+		}
+
+		required, ok := requiredFee(baseFee, gasLimit)
+		if !ok || value < required {
+			return nil, fmt.Errorf("%w: paid %d%s worth %d%s, base fee %d × gas limit %d required",
+				ErrInsufficientFee, fee.Amount, fee.Denom, value, m.params.Denom, baseFee, gasLimit)
+		}
+
+		if ctx.IsReadOnly() {
+			return nil, nil
+		}
+
+		blockGas, err := m.BlockGas()
+		if err != nil {
+			return nil, err
+		}
+		newBlockGas := blockGas + gasLimit
+		if newBlockGas < blockGas {
+			newBlockGas = math.MaxUint64
+		}
+
+		// The burn share is a share of the fee's value, so it is charged in
+		// the paid denom in proportion: burned/fee.Amount = burn value/value
+		var burned uint64
+		if value > 0 {
+			burned = mulDiv(fee.Amount, burnAmount(required, m.params.BurnRatio), value)
+		}
+		collected := fee.Amount - burned
+
+		// The fee payer or granter sponsors the fee, if the transaction names one
+		payer := tx.FeeSource()
+
+		var effs []effects.Effect
+		if burned > 0 {
+			effs = append(effs, effects.TransferEffect{
+				From:   payer,
+				To:     m.params.BurnAccount,
+				Amount: types.NewCoins(types.NewCoin(fee.Denom, burned)),
+			})
+		}
+		if collected > 0 {
+			effs = append(effs, effects.TransferEffect{
+				From:   payer,
+				To:     m.params.FeeCollector,
+				Amount: types.NewCoins(types.NewCoin(fee.Denom, collected)),
+			})
+		}
+		tip := ""
+		if tx.Tip != nil {
+			effs = append(effs, effects.TransferEffect{
+				From:   payer,
+				To:     m.params.FeeCollector,
+				Amount: types.NewCoins(*tx.Tip),
+			})
+			tip = tx.Tip.String()
+		}
+
+		return append(effs,
+			uint64Effect(blockGasKey, newBlockGas),
+			effects.NewEventEffect(EventTypeFee, map[string][]byte{
+				"account":   []byte(tx.Account),
+				"payer":     []byte(payer),
+				"denom":     []byte(fee.Denom),
+				"value":     []byte(strconv.FormatUint(value, 10)),
+				"gas_limit": []byte(strconv.FormatUint(gasLimit, 10)),
+				"base_fee":  []byte(strconv.FormatUint(baseFee, 10)),
+				"burned":    []byte(strconv.FormatUint(burned, 10)),
+				"collected": []byte(strconv.FormatUint(collected, 10)),
+				"tip":       []byte(tip),
+			}),
+		), nil
+	}
+}
+
+// feeCoin returns the single coin a fee is paid in; an empty fee is zero Params.Denom
+func (m *FeeMarketModule) feeCoin(amount types.Coins) (types.Coin, error) {
+	switch len(amount) {
+	case 0:
+		return types.NewCoin(m.params.Denom, 0), nil
+	case 1:
+		return amount[0], nil
+	default:
+		return types.Coin{}, fmt.Errorf("%w: fee must be paid in a single denom, got %d", ErrInvalidFeeDenom, len(amount))
+	}
+}
+
+// convertFee returns the value of fee in Params.Denom at the oracle rate,
+// failing with ErrFeeSlippageExceeded if the rate deviates from the
+// reference rate by more than slippage
+func (m *FeeMarketModule) convertFee(ctx *runtime.Context, fee types.Coin, slippage types.Ratio) (uint64, error) {
+	if m.oracle == nil {
+		return 0, fmt.Errorf("%w: %s, expected %s", ErrInvalidFeeDenom, fee.Denom, m.params.Denom)
+	}
+
+	rate, reference, err := m.oracle.ConversionRate(ctx, fee.Denom)
+	if err != nil {
+		return 0, fmt.Errorf("failed to price fee denom %s: %w", fee.Denom, err)
+	}
+	if rate.Denominator == 0 || reference.Denominator == 0 || slippage.Denominator == 0 {
+		return 0, fmt.Errorf("%w: %s: zero denominator", ErrInvalidFeeDenom, fee.Denom)
+	}
+
+	if !withinSlippage(rate, reference, slippage) {
+		return 0, fmt.Errorf("%w: %s rate %d/%d, reference %d/%d, slippage %d/%d",
+			ErrFeeSlippageExceeded, fee.Denom, rate.Numerator, rate.Denominator,
+			reference.Numerator, reference.Denominator, slippage.Numerator, slippage.Denominator)
+	}
+
+	return convert(fee.Amount, rate), nil
+}
+
+// endBlock sets the next block's base fee from the block's gas and resets the gas counter
+func (m *FeeMarketModule) endBlock(ctx *runtime.Context) ([]effects.Effect, []types.ValidatorUpdate, error) {
+	if m == nil {
+		return nil, nil, fmt.Errorf("fee market module is nil")
+	}
+	if ctx == nil {
+		return nil, nil, fmt.Errorf("context cannot be nil")
+	}
+
+	baseFee, err := m.BaseFee()
+	if err != nil {
+		return nil, nil, err
+	}
+	blockGas, err := m.BlockGas()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	next := m.params.NextBaseFee(baseFee, blockGas)
+
+	return []effects.Effect{
+		uint64Effect(baseFeeKey, next),
+		effects.NewStateDeleteEffect(ModuleName, blockGasKey),
+		effects.NewEventEffect(EventTypeBaseFee, map[string][]byte{
+			"height":    []byte(strconv.FormatUint(ctx.BlockHeight(), 10)),
+			"block_gas": []byte(strconv.FormatUint(blockGas, 10)),
+			"base_fee":  []byte(strconv.FormatUint(next, 10)),
+		}),
+	}, nil, nil
+}
+
+// handleQueryBaseFee returns the current base fee as a decimal JSON string
+func (m *FeeMarketModule) handleQueryBaseFee(ctx context.Context, path string, data []byte) ([]byte, error) {
+	baseFee, err := m.BaseFee()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(strconv.FormatUint(baseFee, 10))
+}
+
+// handleQueryParams returns the module parameters as JSON
+func (m *FeeMarketModule) handleQueryParams(ctx context.Context, path string, data []byte) ([]byte, error) {
+	if m == nil {
+		return nil, fmt.Errorf("fee market module is nil")
+	}
+	return json.Marshal(m.params)
+}
@@ -0,0 +1,417 @@
+package feemarket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/runtime"
+	punnettesting "github.com/blockberries/punnet-sdk/testing"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+func testParams() Params {
+	params := DefaultParams()
+	params.InitialBaseFee = 100
+	params.MinBaseFee = 10
+	params.TargetBlockGas = 1000
+	params.BurnRatio = types.Ratio{Numerator: 1, Denominator: 2}
+	return params
+}
+
+// setupTestFeeMarket creates a fee market module whose effects env applies,
+// with alice funded to pay fees
+func setupTestFeeMarket(t *testing.T, params Params, oracle PriceOracle) (*FeeMarketModule, *punnettesting.EffectEnv) {
+	t.Helper()
+
+	env := punnettesting.NewEffectEnv(t)
+	capMgr := env.CapabilityManager()
+	if err := capMgr.RegisterModule("bank"); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	balanceCap, err := capMgr.GrantBalanceCapability("bank")
+	if err != nil {
+		t.Fatalf("failed to grant balance capability: %v", err)
+	}
+	if err := balanceCap.AddBalance(context.Background(), "alice", DefaultDenom, 1_000_000); err != nil {
+		t.Fatalf("failed to fund alice: %v", err)
+	}
+	env.UseBalances(balanceCap)
+
+	feeMod, err := NewFeeMarketModule(env.GrantStore(t, ModuleName), params, oracle)
+	if err != nil {
+		t.Fatalf("failed to create fee market module: %v", err)
+	}
+	return feeMod, env
+}
+
+func setupTestContext(t *testing.T, readOnly bool) *runtime.Context {
+	t.Helper()
+
+	header := runtime.NewBlockHeader(1, time.Now(), "test-chain", []byte("proposer"))
+	newContext := runtime.NewContext
+	if readOnly {
+		newContext = runtime.NewReadOnlyContext
+	}
+	ctx, err := newContext(context.Background(), header, "alice")
+	if err != nil {
+		t.Fatalf("failed to create context: %v", err)
+	}
+	return ctx
+}
+
+func testTx(gasLimit uint64, fee types.Coins) *types.Transaction {
+	tx := types.NewTransaction("alice", 0, nil, types.NewAuthorization())
+	tx.Fee = types.Fee{Amount: fee, GasLimit: gasLimit}
+	return tx
+}
+
+func transfers(effs []effects.Effect, denom string) map[types.AccountName]uint64 {
+	out := make(map[types.AccountName]uint64)
+	for _, eff := range effs {
+		if tr, ok := eff.(effects.TransferEffect); ok {
+			out[tr.To] += tr.Amount.AmountOf(denom)
+		}
+	}
+	return out
+}
+
+func TestParams_ValidateBasic(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Params)
+		wantErr bool
+	}{
+		{name: "default", modify: func(p *Params) {}},
+		{name: "no burn", modify: func(p *Params) { p.BurnRatio = types.Ratio{Numerator: 0, Denominator: 1} }},
+		{name: "empty denom", modify: func(p *Params) { p.Denom = "" }, wantErr: true},
+		{name: "initial below min", modify: func(p *Params) { p.InitialBaseFee = 0 }, wantErr: true},
+		{name: "zero target", modify: func(p *Params) { p.TargetBlockGas = 0 }, wantErr: true},
+		{name: "zero denominator", modify: func(p *Params) { p.MaxChangeDenominator = 0 }, wantErr: true},
+		{name: "zero burn denominator", modify: func(p *Params) { p.BurnRatio = types.Ratio{Numerator: 0, Denominator: 0} }, wantErr: true},
+		{name: "burn above one", modify: func(p *Params) { p.BurnRatio = types.Ratio{Numerator: 3, Denominator: 2} }, wantErr: true},
+		{name: "invalid burn account", modify: func(p *Params) { p.BurnAccount = "Burn" }, wantErr: true},
+		{name: "invalid collector", modify: func(p *Params) { p.FeeCollector = "" }, wantErr: true},
+		{name: "same accounts", modify: func(p *Params) { p.FeeCollector = p.BurnAccount }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := DefaultParams()
+			tt.modify(&params)
+			err := params.ValidateBasic()
+			if tt.wantErr && err == nil {
+				t.Fatal("expected error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestParams_NextBaseFee(t *testing.T) {
+	params := testParams()
+
+	tests := []struct {
+		name     string
+		baseFee  uint64
+		blockGas uint64
+		want     uint64
+	}{
+		{name: "at target", baseFee: 100, blockGas: 1000, want: 100},
+		{name: "full block", baseFee: 100, blockGas: 2000, want: 112},
+		{name: "over full block capped", baseFee: 100, blockGas: 1_000_000, want: 112},
+		{name: "half over target", baseFee: 100, blockGas: 1500, want: 106},
+		{name: "empty block", baseFee: 100, blockGas: 0, want: 88},
+		{name: "increase at least one", baseFee: 10, blockGas: 1001, want: 11},
+		{name: "floored at min", baseFee: 10, blockGas: 0, want: 10},
+		{name: "saturates", baseFee: math.MaxUint64, blockGas: 2000, want: math.MaxUint64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := params.NextBaseFee(tt.baseFee, tt.blockGas); got != tt.want {
+				t.Fatalf("NextBaseFee(%d, %d) = %d, want %d", tt.baseFee, tt.blockGas, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnteHandler_Rejects(t *testing.T) {
+	feeMod, _ := setupTestFeeMarket(t, testParams(), nil)
+	ante := feeMod.AnteHandler()
+
+	tests := []struct {
+		name    string
+		tx      *types.Transaction
+		wantErr error
+	}{
+		{name: "no gas limit", tx: testTx(0, types.NewCoins(types.NewCoin(DefaultDenom, 1000))), wantErr: ErrGasLimitRequired},
+		{name: "wrong denom", tx: testTx(10, types.NewCoins(types.NewCoin("atom", 1000))), wantErr: ErrInvalidFeeDenom},
+		{name: "extra denom", tx: testTx(10, types.NewCoins(types.NewCoin("atom", 1), types.NewCoin(DefaultDenom, 1000))), wantErr: ErrInvalidFeeDenom},
+		{name: "below base fee", tx: testTx(10, types.NewCoins(types.NewCoin(DefaultDenom, 999))), wantErr: ErrInsufficientFee},
+		{name: "no fee", tx: testTx(10, nil), wantErr: ErrInsufficientFee},
+		{name: "overflow", tx: testTx(math.MaxUint64, types.NewCoins(types.NewCoin(DefaultDenom, math.MaxUint64))), wantErr: ErrInsufficientFee},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, readOnly := range []bool{true, false} {
+				_, err := ante(setupTestContext(t, readOnly), tt.tx)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("readOnly=%v: expected %v, got %v", readOnly, tt.wantErr, err)
+				}
+			}
+		})
+	}
+}
+
+func TestAnteHandler_ChargesFee(t *testing.T) {
+	feeMod, env := setupTestFeeMarket(t, testParams(), nil)
+	ante := feeMod.AnteHandler()
+
+	// base fee 100 × gas 10 = 1000 required, 200 tip
+	tx := testTx(10, types.NewCoins(types.NewCoin(DefaultDenom, 1200)))
+
+	effs, err := ante(setupTestContext(t, true), tx)
+	if err != nil {
+		t.Fatalf("CheckTx ante failed: %v", err)
+	}
+	if len(effs) != 0 {
+		t.Fatalf("expected no effects in read-only mode, got %d", len(effs))
+	}
+
+	effs, err = ante(setupTestContext(t, false), tx)
+	if err != nil {
+		t.Fatalf("ante failed: %v", err)
+	}
+	got := transfers(effs, DefaultDenom)
+	if got[DefaultBurnAccount] != 500 {
+		t.Fatalf("expected 500 burned, got %d", got[DefaultBurnAccount])
+	}
+	if got[DefaultFeeCollector] != 700 {
+		t.Fatalf("expected 700 collected, got %d", got[DefaultFeeCollector])
+	}
+	for _, eff := range effs {
+		if tr, ok := eff.(effects.TransferEffect); ok && tr.From != "alice" {
+			t.Fatalf("expected fee charged to alice, got %s", tr.From)
+		}
+	}
+
+	env.Apply(t, setupTestContext(t, false), effs)
+	if blockGas, _ := feeMod.BlockGas(); blockGas != 10 {
+		t.Fatalf("expected block gas 10, got %d", blockGas)
+	}
+
+	effs, err = ante(setupTestContext(t, false), testTx(5, types.NewCoins(types.NewCoin(DefaultDenom, 500))))
+	if err != nil {
+		t.Fatalf("ante failed: %v", err)
+	}
+	env.Apply(t, setupTestContext(t, false), effs)
+	if blockGas, _ := feeMod.BlockGas(); blockGas != 15 {
+		t.Fatalf("expected block gas 15, got %d", blockGas)
+	}
+}
+
+func TestAnteHandler_ChargesFeeSource(t *testing.T) {
+	feeMod, _ := setupTestFeeMarket(t, testParams(), nil)
+	ante := feeMod.AnteHandler()
+
+	tests := []struct {
+		name    string
+		payer   types.AccountName
+		granter types.AccountName
+		want    types.AccountName
+	}{
+		{name: "payer", payer: "bob", want: "bob"},
+		{name: "granter", granter: "carol", want: "carol"},
+		{name: "payer and granter", payer: "bob", granter: "carol", want: "carol"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := testTx(10, types.NewCoins(types.NewCoin(DefaultDenom, 1000)))
+			tx.FeePayer = tt.payer
+			tx.FeeGranter = tt.granter
+
+			effs, err := ante(setupTestContext(t, false), tx)
+			if err != nil {
+				t.Fatalf("ante failed: %v", err)
+			}
+			charged := 0
+			for _, eff := range effs {
+				if tr, ok := eff.(effects.TransferEffect); ok {
+					if tr.From != tt.want {
+						t.Fatalf("expected fee charged to %s, got %s", tt.want, tr.From)
+					}
+					charged++
+				}
+			}
+			if charged == 0 {
+				t.Fatal("expected the fee to be charged")
+			}
+		})
+	}
+}
+
+func TestAnteHandler_ChargesTip(t *testing.T) {
+	feeMod, _ := setupTestFeeMarket(t, testParams(), nil)
+	ante := feeMod.AnteHandler()
+
+	// The tip does not count towards the required fee
+	tx := testTx(10, types.NewCoins(types.NewCoin(DefaultDenom, 999)))
+	tip := types.NewCoin(DefaultDenom, 300)
+	tx.Tip = &tip
+	if _, err := ante(setupTestContext(t, false), tx); !errors.Is(err, ErrInsufficientFee) {
+		t.Fatalf("expected ErrInsufficientFee, got %v", err)
+	}
+
+	tx.Fee.Amount = types.NewCoins(types.NewCoin(DefaultDenom, 1000))
+	effs, err := ante(setupTestContext(t, false), tx)
+	if err != nil {
+		t.Fatalf("ante failed: %v", err)
+	}
+	got := transfers(effs, DefaultDenom)
+	if got[DefaultBurnAccount] != 500 {
+		t.Fatalf("expected 500 burned, got %d", got[DefaultBurnAccount])
+	}
+	if got[DefaultFeeCollector] != 800 {
+		t.Fatalf("expected 500 collected plus 300 tip, got %d", got[DefaultFeeCollector])
+	}
+}
+
+func TestAnteHandler_BurnShare(t *testing.T) {
+	tests := []struct {
+		name          string
+		ratio         types.Ratio
+		wantBurned    uint64
+		wantCollected uint64
+	}{
+		{name: "burn all", ratio: types.Ratio{Numerator: 1, Denominator: 1}, wantBurned: 1000, wantCollected: 1},
+		{name: "burn none", ratio: types.Ratio{Numerator: 0, Denominator: 1}, wantBurned: 0, wantCollected: 1001},
+		{name: "rounds down", ratio: types.Ratio{Numerator: 1, Denominator: 3}, wantBurned: 333, wantCollected: 668},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := testParams()
+			params.BurnRatio = tt.ratio
+			feeMod, _ := setupTestFeeMarket(t, params, nil)
+
+			effs, err := feeMod.AnteHandler()(setupTestContext(t, false), testTx(10, types.NewCoins(types.NewCoin(DefaultDenom, 1001))))
+			if err != nil {
+				t.Fatalf("ante failed: %v", err)
+			}
+			got := transfers(effs, DefaultDenom)
+			if got[DefaultBurnAccount] != tt.wantBurned || got[DefaultFeeCollector] != tt.wantCollected {
+				t.Fatalf("expected %d burned and %d collected, got %d and %d",
+					tt.wantBurned, tt.wantCollected, got[DefaultBurnAccount], got[DefaultFeeCollector])
+			}
+			for _, eff := range effs {
+				if tr, ok := eff.(effects.TransferEffect); ok && tr.Amount.AmountOf(DefaultDenom) == 0 {
+					t.Fatal("expected no zero-amount transfers")
+				}
+			}
+		})
+	}
+}
+
+func TestEndBlock_AdjustsBaseFee(t *testing.T) {
+	feeMod, env := setupTestFeeMarket(t, testParams(), nil)
+	ctx := setupTestContext(t, false)
+
+	// A full block raises the base fee by 1/8
+	effs, err := feeMod.AnteHandler()(ctx, testTx(2000, types.NewCoins(types.NewCoin(DefaultDenom, 200_000))))
+	if err != nil {
+		t.Fatalf("ante failed: %v", err)
+	}
+	env.Apply(t, ctx, effs)
+
+	effs, updates, err := feeMod.endBlock(ctx)
+	if err != nil {
+		t.Fatalf("end block failed: %v", err)
+	}
+	if len(updates) != 0 {
+		t.Fatalf("expected no validator updates, got %d", len(updates))
+	}
+	env.Apply(t, ctx, effs)
+
+	if baseFee, _ := feeMod.BaseFee(); baseFee != 112 {
+		t.Fatalf("expected base fee 112, got %d", baseFee)
+	}
+	if blockGas, _ := feeMod.BlockGas(); blockGas != 0 {
+		t.Fatalf("expected block gas reset, got %d", blockGas)
+	}
+
+	// The old fee no longer suffices
+	_, err = feeMod.AnteHandler()(ctx, testTx(10, types.NewCoins(types.NewCoin(DefaultDenom, 1000))))
+	if !errors.Is(err, ErrInsufficientFee) {
+		t.Fatalf("expected ErrInsufficientFee, got %v", err)
+	}
+
+	// Empty blocks lower it to the floor
+	for i := 0; i < 50; i++ {
+		effs, _, err := feeMod.endBlock(ctx)
+		if err != nil {
+			t.Fatalf("end block failed: %v", err)
+		}
+		env.Apply(t, ctx, effs)
+	}
+	if baseFee, _ := feeMod.BaseFee(); baseFee != testParams().MinBaseFee {
+		t.Fatalf("expected base fee %d, got %d", testParams().MinBaseFee, baseFee)
+	}
+}
+
+func TestQueries(t *testing.T) {
+	feeMod, _ := setupTestFeeMarket(t, testParams(), nil)
+
+	data, err := feeMod.handleQueryBaseFee(context.Background(), "/base_fee", nil)
+	if err != nil {
+		t.Fatalf("base fee query failed: %v", err)
+	}
+	if string(data) != `"100"` {
+		t.Fatalf("expected \"100\", got %s", data)
+	}
+
+	data, err = feeMod.handleQueryParams(context.Background(), "/params", nil)
+	if err != nil {
+		t.Fatalf("params query failed: %v", err)
+	}
+	var params Params
+	if err := json.Unmarshal(data, &params); err != nil {
+		t.Fatalf("failed to decode params: %v", err)
+	}
+	if params != testParams() {
+		t.Fatalf("expected %+v, got %+v", testParams(), params)
+	}
+}
+
+func TestNewFeeMarketModule_Validation(t *testing.T) {
+	if _, err := NewFeeMarketModule(nil, DefaultParams(), nil); err == nil {
+		t.Fatal("expected error for nil store")
+	}
+
+	params := DefaultParams()
+	params.TargetBlockGas = 0
+	if _, err := NewFeeMarketModule(punnettesting.NewEffectEnv(t).GrantStore(t, ModuleName), params, nil); err == nil {
+		t.Fatal("expected error for invalid params")
+	}
+
+	if _, err := CreateModule(nil); err == nil {
+		t.Fatal("expected error for nil module")
+	}
+
+	feeMod, _ := setupTestFeeMarket(t, DefaultParams(), nil)
+	mod, err := CreateModule(feeMod)
+	if err != nil {
+		t.Fatalf("failed to create module: %v", err)
+	}
+	if mod.Name() != ModuleName {
+		t.Fatalf("expected module name %s, got %s", ModuleName, mod.Name())
+	}
+}
@@ -0,0 +1,160 @@
+package feemarket
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Default parameter values
+const (
+	DefaultDenom                = "stake"
+	DefaultInitialBaseFee       = 1
+	DefaultMinBaseFee           = 1
+	DefaultTargetBlockGas       = 10_000_000
+	DefaultMaxChangeDenominator = 8 // at most 12.5% change per block
+	DefaultFeeCollector         = types.AccountName("fee.collector")
+	DefaultBurnAccount          = types.AccountName("fee.burned")
+)
+
+// Params configures the fee market.
+//
+// The base fee is a price per unit of gas. Each block it moves toward the
+// price at which blocks use TargetBlockGas: up when blocks use more, down
+// when they use less, by at most 1/MaxChangeDenominator per block.
+type Params struct {
+	// Denom is the denomination fees are paid in
+	Denom string `json:"denom"`
+
+	// InitialBaseFee is the base fee before the first block adjusts it
+	InitialBaseFee uint64 `json:"initial_base_fee"`
+
+	// MinBaseFee is the floor of the base fee
+	MinBaseFee uint64 `json:"min_base_fee"`
+
+	// TargetBlockGas is the block gas (sum of transaction gas limits) at
+	// which the base fee stays constant
+	TargetBlockGas uint64 `json:"target_block_gas"`
+
+	// MaxChangeDenominator bounds the per-block change to BaseFee/MaxChangeDenominator
+	MaxChangeDenominator uint64 `json:"max_change_denominator"`
+
+	// BurnRatio is the share of the base fee portion (base fee × gas limit)
+	// sent to BurnAccount. The rest of the fee, including any tip above the
+	// base fee, goes to FeeCollector.
+	BurnRatio types.Ratio `json:"burn_ratio"`
+
+	// BurnAccount receives burned fees. No keys should control it.
+	BurnAccount types.AccountName `json:"burn_account"`
+
+	// FeeCollector receives the fees that are not burned
+	FeeCollector types.AccountName `json:"fee_collector"`
+}
+
+// DefaultParams returns parameters that burn the whole base fee, as in EIP-1559
+func DefaultParams() Params {
+	return Params{
+		Denom:                DefaultDenom,
+		InitialBaseFee:       DefaultInitialBaseFee,
+		MinBaseFee:           DefaultMinBaseFee,
+		TargetBlockGas:       DefaultTargetBlockGas,
+		MaxChangeDenominator: DefaultMaxChangeDenominator,
+		BurnRatio:            types.Ratio{Numerator: 1, Denominator: 1},
+		BurnAccount:          DefaultBurnAccount,
+		FeeCollector:         DefaultFeeCollector,
+	}
+}
+
+// ValidateBasic performs stateless validation
+func (p Params) ValidateBasic() error {
+	if !(types.Coin{Denom: p.Denom, Amount: 1}).IsValid() {
+		return fmt.Errorf("invalid denom %q", p.Denom)
+	}
+
+	if p.InitialBaseFee < p.MinBaseFee {
+		return fmt.Errorf("initial base fee %d below min base fee %d", p.InitialBaseFee, p.MinBaseFee)
+	}
+
+	if p.TargetBlockGas == 0 {
+		return fmt.Errorf("target block gas must be positive")
+	}
+
+	if p.MaxChangeDenominator == 0 {
+		return fmt.Errorf("max change denominator must be positive")
+	}
+
+	if err := p.BurnRatio.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid burn ratio: %w", err)
+	}
+	if p.BurnRatio.Numerator > p.BurnRatio.Denominator {
+		return fmt.Errorf("burn ratio %d/%d exceeds 1", p.BurnRatio.Numerator, p.BurnRatio.Denominator)
+	}
+
+	if !p.BurnAccount.IsValid() {
+		return fmt.Errorf("%w: invalid burn account %s", types.ErrInvalidAccount, p.BurnAccount)
+	}
+
+	if !p.FeeCollector.IsValid() {
+		return fmt.Errorf("%w: invalid fee collector %s", types.ErrInvalidAccount, p.FeeCollector)
+	}
+
+	if p.BurnAccount == p.FeeCollector {
+		return fmt.Errorf("burn account and fee collector must differ")
+	}
+
+	return nil
+}
+
+// NextBaseFee returns the base fee of the block after one that used
+// blockGas at baseFee:
+//
+//	next = baseFee ± baseFee × |blockGas − target| / target / MaxChangeDenominator
+//
+// blockGas is capped at twice the target, so the change never exceeds
+// baseFee/MaxChangeDenominator. An increase is at least 1, so a base fee
+// of 1 can grow. The result is at least MinBaseFee.
+//
+// PRECONDITION: p.ValidateBasic() == nil
+func (p Params) NextBaseFee(baseFee, blockGas uint64) uint64 {
+	target := p.TargetBlockGas
+	blockGas = min(blockGas, 2*min(target, math.MaxUint64/2))
+
+	var next uint64
+	switch {
+	case blockGas > target:
+		delta := max(mulDiv(baseFee, blockGas-target, target)/p.MaxChangeDenominator, 1)
+		next = baseFee + delta
+		if next < baseFee {
+			next = math.MaxUint64
+		}
+	case blockGas < target:
+		delta := mulDiv(baseFee, target-blockGas, target) / p.MaxChangeDenominator
+		next = baseFee - delta
+	default:
+		next = baseFee
+	}
+
+	return max(next, p.MinBaseFee)
+}
+
+// requiredFee returns baseFee × gasLimit, or false on overflow
+func requiredFee(baseFee, gasLimit uint64) (uint64, bool) {
+	hi, lo := bits.Mul64(baseFee, gasLimit)
+	return lo, hi == 0
+}
+
+// burnAmount returns the share of baseAmount burned under ratio
+// PRECONDITION: ratio.Numerator <= ratio.Denominator, ratio.Denominator > 0
+func burnAmount(baseAmount uint64, ratio types.Ratio) uint64 {
+	return mulDiv(baseAmount, ratio.Numerator, ratio.Denominator)
+}
+
+// mulDiv returns a × b / c rounded down, computed in 128 bits.
+// PRECONDITION: c > 0 and b <= c, so the result fits in a uint64
+func mulDiv(a, b, c uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	q, _ := bits.Div64(hi, lo, c)
+	return q
+}
@@ -0,0 +1,48 @@
+package gas
+
+import (
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Message type identifiers
+const (
+	TypeMsgUpdateGasSchedule = "/punnet.gas.v1.MsgUpdateGasSchedule"
+)
+
+// MsgUpdateGasSchedule replaces the gas schedule
+type MsgUpdateGasSchedule struct {
+	// Authority is the account allowed to update the gas schedule
+	Authority types.AccountName `json:"authority"`
+
+	// Schedule is the new gas schedule
+	Schedule runtime.GasSchedule `json:"schedule"`
+}
+
+// Type returns the message type
+func (m *MsgUpdateGasSchedule) Type() string {
+	return TypeMsgUpdateGasSchedule
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgUpdateGasSchedule) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Authority.IsValid() {
+		return fmt.Errorf("%w: invalid authority account %s", types.ErrInvalidAccount, m.Authority)
+	}
+
+	return m.Schedule.ValidateBasic()
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgUpdateGasSchedule) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Authority}
+}
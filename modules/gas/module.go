@@ -0,0 +1,239 @@
+// Package gas stores the runtime gas schedule (runtime.GasSchedule) in state
+// and lets an authority account (typically governance) update it with
+// MsgUpdateGasSchedule, so gas prices can be tuned without a software
+// upgrade.
+//
+// The module implements runtime.GasScheduleSource; set it as the
+// application's ApplicationConfig.GasSchedule. An update takes effect with
+// the first transaction after the one that made it.
+//
+// Until a schedule is set, runtime.DefaultGasSchedule applies. Fields absent
+// from a stored schedule, such as costs added by a later release, read as
+// their defaults; MigrateSchedule rewrites the stored schedule in an upgrade.
+package gas
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/module"
+	"github.com/blockberries/punnet-sdk/query"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+	"github.com/blockberries/punnet-sdk/upgrade"
+)
+
+// Module name
+const ModuleName = "gas"
+
+// ConsensusVersion is the module's state layout version
+const ConsensusVersion uint64 = 1
+
+// QueryServiceSchedule answers any request with a JSON QueryScheduleResponse
+const QueryServiceSchedule = "/gas/schedule"
+
+// EventTypeUpdateSchedule is emitted when MsgUpdateGasSchedule succeeds
+const EventTypeUpdateSchedule = "gas.update_schedule"
+
+// scheduleKey is the key of the schedule in the module namespace
+var scheduleKey = []byte("schedule")
+
+// GasModule stores the gas schedule and implements runtime.GasScheduleSource
+type GasModule struct {
+	// moduleStore is the "module/gas/" view of the state store
+	moduleStore store.BackingStore
+
+	// authority is the only account allowed to update the schedule
+	authority types.AccountName
+}
+
+var _ runtime.GasScheduleSource = (*GasModule)(nil)
+
+// NewGasModule creates a gas module over its store capability,
+// controlled by authority
+func NewGasModule(storeCap capability.StoreCapability, authority types.AccountName) (*GasModule, error) {
+	if err := capability.CheckStoreCapability(storeCap, ModuleName); err != nil {
+		return nil, err
+	}
+
+	if !authority.IsValid() {
+		return nil, fmt.Errorf("%w: invalid authority account %s", types.ErrInvalidAccount, authority)
+	}
+
+	return &GasModule{
+		moduleStore: storeCap,
+		authority:   authority,
+	}, nil
+}
+
+// CreateModule creates the gas module using the module builder
+//
+// Usage:
+//
+//	storeCap, _ := capManager.GrantStoreCapability(gas.ModuleName)
+//	gasMod, _ := gas.NewGasModule(storeCap, "gov")
+//	mod, _ := gas.CreateModule(gasMod)
+//	app, _ := runtime.NewApplication(runtime.ApplicationConfig{
+//		Modules:     append(modules, mod),
+//		GasSchedule: gasMod,
+//		...
+//	})
+func CreateModule(gasMod *GasModule) (module.Module, error) {
+	if gasMod == nil {
+		return nil, fmt.Errorf("gas module cannot be nil")
+	}
+
+	return module.NewModuleBuilder(ModuleName).
+		WithConsensusVersion(ConsensusVersion).
+		WithMsgHandler(TypeMsgUpdateGasSchedule, gasMod.handleUpdateSchedule).
+		WithQueryHandler("/authority", gasMod.handleQueryAuthority).
+		WithQueryService(QueryServiceSchedule, handleQuerySchedule).
+		Build()
+}
+
+// Authority returns the account allowed to update the schedule
+func (m *GasModule) Authority() types.AccountName {
+	if m == nil {
+		return ""
+	}
+	return m.authority
+}
+
+// GasSchedule implements runtime.GasScheduleSource
+func (m *GasModule) GasSchedule() (runtime.GasSchedule, error) {
+	if m == nil {
+		return runtime.GasSchedule{}, fmt.Errorf("gas module is nil")
+	}
+	return getSchedule(m.moduleStore)
+}
+
+// SetSchedule writes schedule directly to the store, e.g. at genesis. In
+// transactions the schedule changes through MsgUpdateGasSchedule.
+func (m *GasModule) SetSchedule(schedule runtime.GasSchedule) error {
+	if m == nil {
+		return fmt.Errorf("gas module is nil")
+	}
+	return setSchedule(m.moduleStore, schedule)
+}
+
+// getSchedule reads the schedule from the module namespace s. Unset
+// schedules, and fields absent from a stored one, read as the defaults.
+func getSchedule(s store.BackingStore) (runtime.GasSchedule, error) {
+	data, err := s.Get(scheduleKey)
+	if errors.Is(err, store.ErrNotFound) || (err == nil && data == nil) {
+		return runtime.DefaultGasSchedule(), nil
+	}
+	if err != nil {
+		return runtime.GasSchedule{}, fmt.Errorf("failed to read gas schedule: %w", err)
+	}
+
+	schedule := runtime.DefaultGasSchedule()
+	if err := json.Unmarshal(data, &schedule); err != nil {
+		return runtime.GasSchedule{}, fmt.Errorf("failed to decode gas schedule: %w", err)
+	}
+	return schedule, nil
+}
+
+// setSchedule validates schedule and writes it to the module namespace s
+func setSchedule(s store.BackingStore, schedule runtime.GasSchedule) error {
+	if err := schedule.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid gas schedule: %w", err)
+	}
+
+	data, err := json.Marshal(schedule)
+	if err != nil {
+		return fmt.Errorf("failed to encode gas schedule: %w", err)
+	}
+	return s.Set(scheduleKey, data)
+}
+
+// MigrateSchedule returns a migration that rewrites the stored schedule with
+// update applied to it. A release that adds a cost to runtime.GasSchedule or
+// changes its units raises ConsensusVersion and registers one for the step:
+//
+//	migrator.Register(gas.ModuleName, 1, gas.MigrateSchedule(func(s runtime.GasSchedule) runtime.GasSchedule {
+//		s.SigVerifyCost = 2_000
+//		return s
+//	}))
+func MigrateSchedule(update func(runtime.GasSchedule) runtime.GasSchedule) upgrade.MigrationFunc {
+	return func(ctx context.Context, s store.BackingStore) error {
+		if update == nil {
+			return fmt.Errorf("schedule update cannot be nil")
+		}
+
+		moduleStore := upgrade.ModuleStore(s, ModuleName)
+		schedule, err := getSchedule(moduleStore)
+		if err != nil {
+			return err
+		}
+		return setSchedule(moduleStore, update(schedule))
+	}
+}
+
+// handleUpdateSchedule handles MsgUpdateGasSchedule
+func (m *GasModule) handleUpdateSchedule(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("gas module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	updateMsg, ok := msg.(*MsgUpdateGasSchedule)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgUpdateGasSchedule")
+	}
+
+	if updateMsg.Authority != m.authority {
+		return nil, fmt.Errorf("%w: only %s may update the gas schedule", types.ErrUnauthorized, m.authority)
+	}
+	if updateMsg.Authority != ctx.Account() {
+		return nil, fmt.Errorf("%w: %s must be transaction account", types.ErrUnauthorized, updateMsg.Authority)
+	}
+
+	if err := updateMsg.Schedule.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid gas schedule: %w", err)
+	}
+
+	data, err := json.Marshal(updateMsg.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode gas schedule: %w", err)
+	}
+
+	return []effects.Effect{
+		effects.NewStateWriteEffect(ModuleName, scheduleKey, data),
+		effects.NewEventEffect(EventTypeUpdateSchedule, map[string][]byte{
+			"authority": []byte(updateMsg.Authority),
+			"height":    []byte(strconv.FormatUint(ctx.BlockHeight(), 10)),
+		}),
+	}, nil
+}
+
+// QueryScheduleResponse is the response for QueryServiceSchedule
+type QueryScheduleResponse struct {
+	Schedule runtime.GasSchedule `json:"schedule"`
+}
+
+// handleQuerySchedule serves QueryServiceSchedule from the state pinned at
+// the query height
+func handleQuerySchedule(ctx *query.Context, _ []byte) ([]byte, error) {
+	schedule, err := getSchedule(capability.ModuleStore(ctx.Store(), ModuleName))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(QueryScheduleResponse{Schedule: schedule})
+}
+
+// handleQueryAuthority returns the authority account as JSON
+func (m *GasModule) handleQueryAuthority(ctx context.Context, path string, data []byte) ([]byte, error) {
+	if m == nil {
+		return nil, fmt.Errorf("gas module is nil")
+	}
+	return json.Marshal(m.authority)
+}
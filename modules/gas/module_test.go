@@ -0,0 +1,274 @@
+package gas
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/query"
+	"github.com/blockberries/punnet-sdk/runtime"
+	punnettesting "github.com/blockberries/punnet-sdk/testing"
+	"github.com/blockberries/punnet-sdk/types"
+	"github.com/blockberries/punnet-sdk/upgrade"
+)
+
+type testEnv struct {
+	*punnettesting.EffectEnv
+	mod *GasModule
+}
+
+func setupTestGasModule(t *testing.T) *testEnv {
+	t.Helper()
+
+	env := punnettesting.NewEffectEnv(t)
+	gasMod, err := NewGasModule(env.GrantStore(t, ModuleName), "gov")
+	if err != nil {
+		t.Fatalf("failed to create gas module: %v", err)
+	}
+	return &testEnv{EffectEnv: env, mod: gasMod}
+}
+
+func setupTestContext(t *testing.T, account types.AccountName) *runtime.Context {
+	t.Helper()
+
+	header := runtime.NewBlockHeader(1, time.Now(), "test-chain", []byte("proposer"))
+	ctx, err := runtime.NewContext(context.Background(), header, account)
+	if err != nil {
+		t.Fatalf("failed to create context: %v", err)
+	}
+	return ctx
+}
+
+func TestNewGasModule(t *testing.T) {
+	if _, err := NewGasModule(nil, "gov"); err == nil {
+		t.Fatal("expected error for nil store capability")
+	}
+	if _, err := NewGasModule(punnettesting.NewEffectEnv(t).GrantStore(t, ModuleName), ""); !errors.Is(err, types.ErrInvalidAccount) {
+		t.Fatalf("expected ErrInvalidAccount, got %v", err)
+	}
+
+	env := setupTestGasModule(t)
+	if env.mod.Authority() != "gov" {
+		t.Fatalf("expected authority gov, got %s", env.mod.Authority())
+	}
+
+	mod, err := CreateModule(env.mod)
+	if err != nil {
+		t.Fatalf("CreateModule failed: %v", err)
+	}
+	if mod.Name() != ModuleName {
+		t.Fatalf("expected module name %s, got %s", ModuleName, mod.Name())
+	}
+}
+
+func TestGasModule_DefaultSchedule(t *testing.T) {
+	env := setupTestGasModule(t)
+
+	schedule, err := env.mod.GasSchedule()
+	if err != nil {
+		t.Fatalf("GasSchedule failed: %v", err)
+	}
+	if schedule != runtime.DefaultGasSchedule() {
+		t.Fatalf("expected default schedule, got %+v", schedule)
+	}
+
+	// Fields absent from a stored schedule read as their defaults
+	if err := env.Store().Set([]byte("module/gas/schedule"), []byte(`{"msg_base_cost":5}`)); err != nil {
+		t.Fatalf("failed to write schedule: %v", err)
+	}
+	schedule, err = env.mod.GasSchedule()
+	if err != nil {
+		t.Fatalf("GasSchedule failed: %v", err)
+	}
+	want := runtime.DefaultGasSchedule()
+	want.MsgBaseCost = 5
+	if schedule != want {
+		t.Fatalf("expected %+v, got %+v", want, schedule)
+	}
+}
+
+func TestGasModule_SetSchedule(t *testing.T) {
+	env := setupTestGasModule(t)
+
+	if err := env.mod.SetSchedule(runtime.GasSchedule{MsgBaseCost: runtime.MaxGasCost + 1}); err == nil {
+		t.Fatal("expected invalid schedule to be rejected")
+	}
+
+	// A zero cost is stored, not replaced by the default
+	want := runtime.GasSchedule{MsgBaseCost: 10, SigVerifyCost: 20, ReadCostPerByte: 0, WriteCostPerByte: 4}
+	if err := env.mod.SetSchedule(want); err != nil {
+		t.Fatalf("SetSchedule failed: %v", err)
+	}
+	got, err := env.mod.GasSchedule()
+	if err != nil {
+		t.Fatalf("GasSchedule failed: %v", err)
+	}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestGasModule_UpdateSchedule(t *testing.T) {
+	env := setupTestGasModule(t)
+	schedule := runtime.GasSchedule{MsgBaseCost: 1, SigVerifyCost: 2, ReadCostPerByte: 3, WriteCostPerByte: 4}
+
+	tests := []struct {
+		name    string
+		account types.AccountName
+		msg     *MsgUpdateGasSchedule
+		wantErr error
+	}{
+		{
+			name:    "not the authority",
+			account: "alice",
+			msg:     &MsgUpdateGasSchedule{Authority: "alice", Schedule: schedule},
+			wantErr: types.ErrUnauthorized,
+		},
+		{
+			name:    "authority not the transaction account",
+			account: "alice",
+			msg:     &MsgUpdateGasSchedule{Authority: "gov", Schedule: schedule},
+			wantErr: types.ErrUnauthorized,
+		},
+		{
+			name:    "invalid schedule",
+			account: "gov",
+			msg:     &MsgUpdateGasSchedule{Authority: "gov", Schedule: runtime.GasSchedule{WriteCostPerByte: runtime.MaxGasCost + 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := env.mod.handleUpdateSchedule(setupTestContext(t, tt.account), tt.msg)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	msg := &MsgUpdateGasSchedule{Authority: "gov", Schedule: schedule}
+	if err := msg.ValidateBasic(); err != nil {
+		t.Fatalf("ValidateBasic failed: %v", err)
+	}
+	ctx := setupTestContext(t, "gov")
+	effs, err := env.mod.handleUpdateSchedule(ctx, msg)
+	if err != nil {
+		t.Fatalf("handleUpdateSchedule failed: %v", err)
+	}
+	env.Apply(t, ctx, effs)
+
+	got, err := env.mod.GasSchedule()
+	if err != nil {
+		t.Fatalf("GasSchedule failed: %v", err)
+	}
+	if got != schedule {
+		t.Fatalf("expected %+v, got %+v", schedule, got)
+	}
+
+	var events int
+	for _, eff := range effs {
+		if e, ok := eff.(effects.EventEffect); ok && e.EventType == EventTypeUpdateSchedule {
+			events++
+		}
+	}
+	if events != 1 {
+		t.Fatalf("expected 1 %s event, got %d", EventTypeUpdateSchedule, events)
+	}
+}
+
+func TestMsgUpdateGasSchedule_ValidateBasic(t *testing.T) {
+	var nilMsg *MsgUpdateGasSchedule
+	if err := nilMsg.ValidateBasic(); err == nil {
+		t.Fatal("expected error for nil message")
+	}
+	if err := (&MsgUpdateGasSchedule{Authority: ""}).ValidateBasic(); !errors.Is(err, types.ErrInvalidAccount) {
+		t.Fatalf("expected ErrInvalidAccount, got %v", err)
+	}
+	if err := (&MsgUpdateGasSchedule{Authority: "gov", Schedule: runtime.GasSchedule{SigVerifyCost: runtime.MaxGasCost + 1}}).ValidateBasic(); err == nil {
+		t.Fatal("expected error for invalid schedule")
+	}
+
+	msg := &MsgUpdateGasSchedule{Authority: "gov"}
+	if signers := msg.GetSigners(); len(signers) != 1 || signers[0] != "gov" {
+		t.Fatalf("expected signer gov, got %v", signers)
+	}
+}
+
+func TestMigrateSchedule(t *testing.T) {
+	env := setupTestGasModule(t)
+	if err := env.mod.SetSchedule(runtime.GasSchedule{MsgBaseCost: 7}); err != nil {
+		t.Fatalf("SetSchedule failed: %v", err)
+	}
+
+	migrate := MigrateSchedule(func(s runtime.GasSchedule) runtime.GasSchedule {
+		s.SigVerifyCost = 2_000
+		return s
+	})
+	migrator := upgrade.NewMigrator()
+	if err := migrator.Register(ModuleName, 1, migrate); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	steps, err := migrator.Plan(upgrade.VersionMap{ModuleName: 1}, upgrade.VersionMap{ModuleName: 2})
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if len(steps) != 1 {
+		t.Fatalf("expected 1 migration step, got %d", len(steps))
+	}
+
+	if err := migrate(context.Background(), env.Store()); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	got, err := env.mod.GasSchedule()
+	if err != nil {
+		t.Fatalf("GasSchedule failed: %v", err)
+	}
+	if got != (runtime.GasSchedule{MsgBaseCost: 7, SigVerifyCost: 2_000}) {
+		t.Fatalf("unexpected migrated schedule %+v", got)
+	}
+
+	// An invalid result is rejected
+	invalid := MigrateSchedule(func(s runtime.GasSchedule) runtime.GasSchedule {
+		s.MsgBaseCost = runtime.MaxGasCost + 1
+		return s
+	})
+	if err := invalid(context.Background(), env.Store()); err == nil {
+		t.Fatal("expected invalid migrated schedule to fail")
+	}
+	if err := MigrateSchedule(nil)(context.Background(), env.Store()); err == nil {
+		t.Fatal("expected nil update to fail")
+	}
+}
+
+func TestQuerySchedule(t *testing.T) {
+	env := setupTestGasModule(t)
+	want := runtime.GasSchedule{MsgBaseCost: 9}
+	if err := env.mod.SetSchedule(want); err != nil {
+		t.Fatalf("SetSchedule failed: %v", err)
+	}
+
+	server, err := query.NewServer(env.Store())
+	if err != nil {
+		t.Fatalf("failed to create query server: %v", err)
+	}
+	if err := server.RegisterHandler(QueryServiceSchedule, handleQuerySchedule); err != nil {
+		t.Fatalf("failed to register query service: %v", err)
+	}
+	resp, err := server.Query(context.Background(), query.Request{Path: QueryServiceSchedule})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+
+	var decoded QueryScheduleResponse
+	if err := json.Unmarshal(resp.Value, &decoded); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if decoded.Schedule != want {
+		t.Fatalf("expected %+v, got %+v", want, decoded.Schedule)
+	}
+}
@@ -0,0 +1,223 @@
+package nft
+
+import (
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Message type identifiers
+const (
+	TypeMsgCreateCollection = "/punnet.nft.v1.MsgCreateCollection"
+	TypeMsgMint             = "/punnet.nft.v1.MsgMint"
+	TypeMsgTransfer         = "/punnet.nft.v1.MsgTransfer"
+	TypeMsgBurn             = "/punnet.nft.v1.MsgBurn"
+)
+
+// MsgCreateCollection creates a collection; the creator is its only minter
+type MsgCreateCollection struct {
+	// Creator is the account creating the collection
+	Creator types.AccountName `json:"creator"`
+
+	// ID is the collection ID (see ValidateCollectionID)
+	ID string `json:"id"`
+
+	// Name is the display name
+	Name string `json:"name"`
+
+	// Description describes the collection
+	Description string `json:"description,omitempty"`
+
+	// URI points to off-chain collection metadata
+	URI string `json:"uri,omitempty"`
+}
+
+// Type returns the message type
+func (m *MsgCreateCollection) Type() string {
+	return TypeMsgCreateCollection
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgCreateCollection) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Creator.IsValid() {
+		return fmt.Errorf("%w: invalid creator account %s", types.ErrInvalidAccount, m.Creator)
+	}
+
+	if err := ValidateCollectionID(m.ID); err != nil {
+		return err
+	}
+
+	if m.Name == "" || len(m.Name) > MaxNameLength {
+		return fmt.Errorf("name must be 1 to %d bytes, got %d", MaxNameLength, len(m.Name))
+	}
+
+	if len(m.Description) > MaxDescriptionLength {
+		return fmt.Errorf("description length %d exceeds maximum %d", len(m.Description), MaxDescriptionLength)
+	}
+
+	return validateURI(m.URI)
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgCreateCollection) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Creator}
+}
+
+// MsgMint mints a token of a collection to a recipient
+type MsgMint struct {
+	// Minter is the collection creator
+	Minter types.AccountName `json:"minter"`
+
+	// CollectionID is the collection of the token
+	CollectionID string `json:"collection_id"`
+
+	// TokenID is the new token's ID (see ValidateTokenID)
+	TokenID string `json:"token_id"`
+
+	// Recipient owns the minted token
+	Recipient types.AccountName `json:"recipient"`
+
+	// URI points to off-chain token metadata
+	URI string `json:"uri,omitempty"`
+}
+
+// Type returns the message type
+func (m *MsgMint) Type() string {
+	return TypeMsgMint
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgMint) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Minter.IsValid() {
+		return fmt.Errorf("%w: invalid minter account %s", types.ErrInvalidAccount, m.Minter)
+	}
+
+	if err := ValidateCollectionID(m.CollectionID); err != nil {
+		return err
+	}
+
+	if err := ValidateTokenID(m.TokenID); err != nil {
+		return err
+	}
+
+	if !m.Recipient.IsValid() {
+		return fmt.Errorf("%w: invalid recipient account %s", types.ErrInvalidAccount, m.Recipient)
+	}
+
+	return validateURI(m.URI)
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgMint) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Minter}
+}
+
+// MsgTransfer transfers a token to a new owner
+type MsgTransfer struct {
+	// Sender is the token's owner
+	Sender types.AccountName `json:"sender"`
+
+	// CollectionID is the collection of the token
+	CollectionID string `json:"collection_id"`
+
+	// TokenID is the token's ID
+	TokenID string `json:"token_id"`
+
+	// Recipient is the new owner
+	Recipient types.AccountName `json:"recipient"`
+}
+
+// Type returns the message type
+func (m *MsgTransfer) Type() string {
+	return TypeMsgTransfer
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgTransfer) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Sender.IsValid() {
+		return fmt.Errorf("%w: invalid sender account %s", types.ErrInvalidAccount, m.Sender)
+	}
+
+	if !m.Recipient.IsValid() {
+		return fmt.Errorf("%w: invalid recipient account %s", types.ErrInvalidAccount, m.Recipient)
+	}
+
+	// A self-transfer would change nothing
+	if m.Sender == m.Recipient {
+		return fmt.Errorf("cannot transfer to self")
+	}
+
+	if err := ValidateCollectionID(m.CollectionID); err != nil {
+		return err
+	}
+
+	return ValidateTokenID(m.TokenID)
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgTransfer) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Sender}
+}
+
+// MsgBurn destroys a token
+type MsgBurn struct {
+	// Owner is the token's owner
+	Owner types.AccountName `json:"owner"`
+
+	// CollectionID is the collection of the token
+	CollectionID string `json:"collection_id"`
+
+	// TokenID is the token's ID
+	TokenID string `json:"token_id"`
+}
+
+// Type returns the message type
+func (m *MsgBurn) Type() string {
+	return TypeMsgBurn
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgBurn) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Owner.IsValid() {
+		return fmt.Errorf("%w: invalid owner account %s", types.ErrInvalidAccount, m.Owner)
+	}
+
+	if err := ValidateCollectionID(m.CollectionID); err != nil {
+		return err
+	}
+
+	return ValidateTokenID(m.TokenID)
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgBurn) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Owner}
+}
@@ -0,0 +1,514 @@
+// Package nft provides non-fungible tokens grouped in collections.
+//
+// An account creates a collection and is its only minter. Each token has an
+// ID unique within its collection, an owner and an optional metadata URI.
+// Owners transfer and burn their tokens. Tokens are indexed by owner, so
+// wallets and indexers can enumerate an account's tokens with the paginated
+// query services.
+//
+// Handlers read the state committed before their transaction, so a
+// transaction touching the same token twice acts on the state before both
+// messages.
+package nft
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/effects"
+	sdkerrors "github.com/blockberries/punnet-sdk/errors"
+	"github.com/blockberries/punnet-sdk/module"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Module name
+const ModuleName = "nft"
+
+// Field length limits
+const (
+	MaxNameLength        = 128
+	MaxDescriptionLength = 512
+	MaxURILength         = 512
+)
+
+var (
+	// ErrCollectionNotFound is returned for an unknown collection
+	ErrCollectionNotFound = errors.New("collection not found")
+
+	// ErrCollectionExists is returned when creating a collection whose ID is taken
+	ErrCollectionExists = errors.New("collection already exists")
+
+	// ErrTokenNotFound is returned for an unknown or burned token
+	ErrTokenNotFound = errors.New("token not found")
+
+	// ErrTokenExists is returned when minting a token whose ID is taken
+	ErrTokenExists = errors.New("token already exists")
+
+	// ErrTokenChanged is returned when a token is transferred or burned by
+	// a transaction that already minted, transferred or burned it
+	ErrTokenChanged = errors.New("token already changed in this transaction")
+)
+
+// NFT module error codes, in the ModuleName codespace.
+func init() {
+	sdkerrors.MustRegister(ModuleName, 2, ErrCollectionNotFound)
+	sdkerrors.MustRegister(ModuleName, 3, ErrCollectionExists)
+	sdkerrors.MustRegister(ModuleName, 4, ErrTokenNotFound)
+	sdkerrors.MustRegister(ModuleName, 5, ErrTokenExists)
+	sdkerrors.MustRegister(ModuleName, 6, ErrTokenChanged)
+}
+
+// Event types
+const (
+	EventTypeCreateCollection = "nft.collection_created"
+	EventTypeMint             = "nft.minted"
+	EventTypeTransfer         = "nft.transferred"
+	EventTypeBurn             = "nft.burned"
+)
+
+var (
+	// collectionIDRegex matches collection IDs
+	collectionIDRegex = regexp.MustCompile(`^[a-z][a-z0-9.\-]{2,63}$`)
+
+	// tokenIDRegex matches token IDs
+	tokenIDRegex = regexp.MustCompile(`^[a-zA-Z0-9._\-]{1,64}$`)
+)
+
+// ValidateCollectionID checks that id is 3 to 64 characters of [a-z0-9.-]
+// starting with a letter
+func ValidateCollectionID(id string) error {
+	if !collectionIDRegex.MatchString(id) {
+		return fmt.Errorf("invalid collection id %q", id)
+	}
+	return nil
+}
+
+// ValidateTokenID checks that id is 1 to 64 characters of [a-zA-Z0-9._-]
+func ValidateTokenID(id string) error {
+	if !tokenIDRegex.MatchString(id) {
+		return fmt.Errorf("invalid token id %q", id)
+	}
+	return nil
+}
+
+// validateURI checks the length of a metadata URI; it may be empty
+func validateURI(uri string) error {
+	if len(uri) > MaxURILength {
+		return fmt.Errorf("uri length %d exceeds maximum %d", len(uri), MaxURILength)
+	}
+	return nil
+}
+
+// State keys within the module namespace. IDs cannot contain '/', so the
+// key of one ID is never a prefix of another's.
+//
+//	collection/<collection>                 → Collection
+//	token/<collection>/<token>              → NFT
+//	owner/<owner>/<collection>/<token>      → ownerIndexValue
+func collectionKey(collectionID string) []byte {
+	return []byte("collection/" + collectionID)
+}
+
+func tokenKey(collectionID, tokenID string) []byte {
+	return []byte("token/" + collectionID + "/" + tokenID)
+}
+
+func ownerKey(owner types.AccountName, collectionID, tokenID string) []byte {
+	return []byte("owner/" + string(owner) + "/" + collectionID + "/" + tokenID)
+}
+
+// collectionPrefix, tokenPrefix and ownerPrefix are the prefixes enumerated
+// by the query services
+var collectionPrefix = []byte("collection/")
+
+func tokenPrefix(collectionID string) []byte {
+	return []byte("token/" + collectionID + "/")
+}
+
+func ownerPrefix(owner types.AccountName) []byte {
+	return []byte("owner/" + string(owner) + "/")
+}
+
+// ownerIndexValue is the value of owner index entries; a StateWriteEffect
+// with a nil value is a delete
+var ownerIndexValue = []byte{1}
+
+// Collection is a named group of tokens minted by its creator
+type Collection struct {
+	// ID is the unique collection ID
+	ID string `json:"id"`
+
+	// Creator created the collection and is its only minter
+	Creator types.AccountName `json:"creator"`
+
+	// Name is the display name
+	Name string `json:"name"`
+
+	// Description describes the collection
+	Description string `json:"description,omitempty"`
+
+	// URI points to off-chain collection metadata
+	URI string `json:"uri,omitempty"`
+
+	// CreatedHeight is the block height of creation
+	CreatedHeight uint64 `json:"created_height"`
+}
+
+// NFT is a token of a collection
+type NFT struct {
+	// CollectionID is the ID of the token's collection
+	CollectionID string `json:"collection_id"`
+
+	// ID is the token ID, unique within the collection
+	ID string `json:"id"`
+
+	// Owner owns the token
+	Owner types.AccountName `json:"owner"`
+
+	// URI points to off-chain token metadata
+	URI string `json:"uri,omitempty"`
+}
+
+// NFTModule manages collections and tokens.
+type NFTModule struct {
+	// moduleStore is the "module/nft/" view of the state store
+	moduleStore store.BackingStore
+}
+
+// NewNFTModule creates an NFT module over its store capability
+func NewNFTModule(storeCap capability.StoreCapability) (*NFTModule, error) {
+	if err := capability.CheckStoreCapability(storeCap, ModuleName); err != nil {
+		return nil, err
+	}
+
+	return &NFTModule{
+		moduleStore: storeCap,
+	}, nil
+}
+
+// CreateModule creates the NFT module using the module builder
+//
+// Usage:
+//
+//	storeCap, _ := capManager.GrantStoreCapability(nft.ModuleName)
+//	nftMod, _ := nft.NewNFTModule(storeCap)
+//	mod, _ := nft.CreateModule(nftMod)
+func CreateModule(nftMod *NFTModule) (module.Module, error) {
+	if nftMod == nil {
+		return nil, fmt.Errorf("nft module cannot be nil")
+	}
+
+	return module.NewModuleBuilder(ModuleName).
+		WithMsgHandler(TypeMsgCreateCollection, nftMod.handleCreateCollection).
+		WithMsgHandler(TypeMsgMint, nftMod.handleMint).
+		WithMsgHandler(TypeMsgTransfer, nftMod.handleTransfer).
+		WithMsgHandler(TypeMsgBurn, nftMod.handleBurn).
+		WithQueryService(QueryServiceCollection, handleQueryCollection).
+		WithQueryService(QueryServiceCollections, handleQueryCollections).
+		WithQueryService(QueryServiceToken, handleQueryToken).
+		WithQueryService(QueryServiceTokens, handleQueryTokens).
+		WithQueryService(QueryServiceOwnerTokens, handleQueryOwnerTokens).
+		Build()
+}
+
+// Collection returns the collection with the given ID, if any
+func (m *NFTModule) Collection(collectionID string) (*Collection, bool, error) {
+	if m == nil {
+		return nil, false, fmt.Errorf("nft module is nil")
+	}
+	return getCollection(m.moduleStore, collectionID)
+}
+
+// NFT returns the token of a collection, if it exists
+func (m *NFTModule) NFT(collectionID, tokenID string) (*NFT, bool, error) {
+	if m == nil {
+		return nil, false, fmt.Errorf("nft module is nil")
+	}
+	return getNFT(m.moduleStore, collectionID, tokenID)
+}
+
+// getCollection reads a collection from the module namespace view s
+func getCollection(s store.BackingStore, collectionID string) (*Collection, bool, error) {
+	var collection Collection
+	ok, err := load(s, collectionKey(collectionID), &collection)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return &collection, true, nil
+}
+
+// getNFT reads a token from the module namespace view s
+func getNFT(s store.BackingStore, collectionID, tokenID string) (*NFT, bool, error) {
+	var nft NFT
+	ok, err := load(s, tokenKey(collectionID, tokenID), &nft)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return &nft, true, nil
+}
+
+// load decodes the JSON value at key of s into v, reporting whether it exists
+func load(s store.BackingStore, key []byte, v any) (bool, error) {
+	data, err := s.Get(key)
+	if errors.Is(err, store.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if data == nil {
+		return false, nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("failed to decode %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// writeEffect returns the effect storing v as JSON at key
+func writeEffect(key []byte, v any) (effects.Effect, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+	return effects.NewStateWriteEffect(ModuleName, key, data), nil
+}
+
+// nftEvent returns the event effect for an action on a token
+func nftEvent(ctx *runtime.Context, eventType string, collectionID, tokenID string, attrs ...string) effects.Effect {
+	attributes := map[string][]byte{
+		"collection_id": []byte(collectionID),
+		"height":        []byte(strconv.FormatUint(ctx.BlockHeight(), 10)),
+	}
+	if tokenID != "" {
+		attributes["token_id"] = []byte(tokenID)
+	}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		attributes[attrs[i]] = []byte(attrs[i+1])
+	}
+	return effects.NewEventEffect(eventType, attributes)
+}
+
+// checkSigner verifies that signer is the transaction account
+func checkSigner(ctx *runtime.Context, signer types.AccountName) error {
+	if signer != ctx.Account() {
+		return fmt.Errorf("%w: %s must be transaction account", types.ErrUnauthorized, signer)
+	}
+	return nil
+}
+
+// ownedToken returns the token, failing with ErrTokenNotFound if it does not
+// exist and ErrUnauthorized if owner does not own it
+func (m *NFTModule) ownedToken(owner types.AccountName, collectionID, tokenID string) (*NFT, error) {
+	nft, ok, err := m.NFT(collectionID, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", ErrTokenNotFound, collectionID, tokenID)
+	}
+	if nft.Owner != owner {
+		return nil, fmt.Errorf("%w: %s does not own %s/%s", types.ErrUnauthorized, owner, collectionID, tokenID)
+	}
+	return nft, nil
+}
+
+// claimToken claims the token for the transaction changing it, failing with
+// err otherwise.
+//
+// SECURITY: Handlers read tokens from the state before the transaction, so a
+// second message on the same token in the transaction would pass the same
+// ownership and existence checks, leaving stale owner index entries behind.
+func claimToken(ctx *runtime.Context, collectionID, tokenID string, err error) error {
+	if !ctx.ClaimTxKey(ModuleName, tokenKey(collectionID, tokenID)) {
+		return fmt.Errorf("%w: %s/%s", err, collectionID, tokenID)
+	}
+	return nil
+}
+
+// handleCreateCollection handles MsgCreateCollection
+func (m *NFTModule) handleCreateCollection(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("nft module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	createMsg, ok := msg.(*MsgCreateCollection)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgCreateCollection")
+	}
+
+	if err := checkSigner(ctx, createMsg.Creator); err != nil {
+		return nil, err
+	}
+
+	_, exists, err := m.Collection(createMsg.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read collection: %w", err)
+	}
+	if exists || !ctx.ClaimTxKey(ModuleName, collectionKey(createMsg.ID)) {
+		return nil, fmt.Errorf("%w: %s", ErrCollectionExists, createMsg.ID)
+	}
+
+	collection := &Collection{
+		ID:            createMsg.ID,
+		Creator:       createMsg.Creator,
+		Name:          createMsg.Name,
+		Description:   createMsg.Description,
+		URI:           createMsg.URI,
+		CreatedHeight: ctx.BlockHeight(),
+	}
+	write, err := writeEffect(collectionKey(collection.ID), collection)
+	if err != nil {
+		return nil, err
+	}
+
+	return []effects.Effect{
+		write,
+		nftEvent(ctx, EventTypeCreateCollection, collection.ID, "", "creator", string(collection.Creator)),
+	}, nil
+}
+
+// handleMint handles MsgMint
+func (m *NFTModule) handleMint(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("nft module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	mintMsg, ok := msg.(*MsgMint)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgMint")
+	}
+
+	if err := checkSigner(ctx, mintMsg.Minter); err != nil {
+		return nil, err
+	}
+
+	collection, ok, err := m.Collection(mintMsg.CollectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read collection: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCollectionNotFound, mintMsg.CollectionID)
+	}
+	if collection.Creator != mintMsg.Minter {
+		return nil, fmt.Errorf("%w: only %s can mint in %s", types.ErrUnauthorized, collection.Creator, collection.ID)
+	}
+
+	_, exists, err := m.NFT(mintMsg.CollectionID, mintMsg.TokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("%w: %s/%s", ErrTokenExists, mintMsg.CollectionID, mintMsg.TokenID)
+	}
+	if err := claimToken(ctx, mintMsg.CollectionID, mintMsg.TokenID, ErrTokenExists); err != nil {
+		return nil, err
+	}
+
+	nft := &NFT{
+		CollectionID: mintMsg.CollectionID,
+		ID:           mintMsg.TokenID,
+		Owner:        mintMsg.Recipient,
+		URI:          mintMsg.URI,
+	}
+	write, err := writeEffect(tokenKey(nft.CollectionID, nft.ID), nft)
+	if err != nil {
+		return nil, err
+	}
+
+	return []effects.Effect{
+		write,
+		effects.NewStateWriteEffect(ModuleName, ownerKey(nft.Owner, nft.CollectionID, nft.ID), ownerIndexValue),
+		nftEvent(ctx, EventTypeMint, nft.CollectionID, nft.ID,
+			"minter", string(mintMsg.Minter),
+			"owner", string(nft.Owner),
+			"uri", nft.URI),
+	}, nil
+}
+
+// handleTransfer handles MsgTransfer
+func (m *NFTModule) handleTransfer(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("nft module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	transferMsg, ok := msg.(*MsgTransfer)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgTransfer")
+	}
+
+	if err := checkSigner(ctx, transferMsg.Sender); err != nil {
+		return nil, err
+	}
+
+	nft, err := m.ownedToken(transferMsg.Sender, transferMsg.CollectionID, transferMsg.TokenID)
+	if err != nil {
+		return nil, err
+	}
+	if err := claimToken(ctx, nft.CollectionID, nft.ID, ErrTokenChanged); err != nil {
+		return nil, err
+	}
+
+	nft.Owner = transferMsg.Recipient
+	write, err := writeEffect(tokenKey(nft.CollectionID, nft.ID), nft)
+	if err != nil {
+		return nil, err
+	}
+
+	return []effects.Effect{
+		write,
+		effects.NewStateDeleteEffect(ModuleName, ownerKey(transferMsg.Sender, nft.CollectionID, nft.ID)),
+		effects.NewStateWriteEffect(ModuleName, ownerKey(nft.Owner, nft.CollectionID, nft.ID), ownerIndexValue),
+		nftEvent(ctx, EventTypeTransfer, nft.CollectionID, nft.ID,
+			"sender", string(transferMsg.Sender),
+			"recipient", string(nft.Owner)),
+	}, nil
+}
+
+// handleBurn handles MsgBurn
+func (m *NFTModule) handleBurn(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("nft module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	burnMsg, ok := msg.(*MsgBurn)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgBurn")
+	}
+
+	if err := checkSigner(ctx, burnMsg.Owner); err != nil {
+		return nil, err
+	}
+
+	nft, err := m.ownedToken(burnMsg.Owner, burnMsg.CollectionID, burnMsg.TokenID)
+	if err != nil {
+		return nil, err
+	}
+	if err := claimToken(ctx, nft.CollectionID, nft.ID, ErrTokenChanged); err != nil {
+		return nil, err
+	}
+
+	return []effects.Effect{
+		effects.NewStateDeleteEffect(ModuleName, tokenKey(nft.CollectionID, nft.ID)),
+		effects.NewStateDeleteEffect(ModuleName, ownerKey(nft.Owner, nft.CollectionID, nft.ID)),
+		nftEvent(ctx, EventTypeBurn, nft.CollectionID, nft.ID, "owner", string(nft.Owner)),
+	}, nil
+}
@@ -0,0 +1,456 @@
+package nft
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/query"
+	"github.com/blockberries/punnet-sdk/runtime"
+	punnettesting "github.com/blockberries/punnet-sdk/testing"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+type testEnv struct {
+	*punnettesting.EffectEnv
+	mod *NFTModule
+}
+
+func setupTestNFTModule(t *testing.T) *testEnv {
+	t.Helper()
+
+	env := punnettesting.NewEffectEnv(t)
+	nftMod, err := NewNFTModule(env.GrantStore(t, ModuleName))
+	if err != nil {
+		t.Fatalf("failed to create nft module: %v", err)
+	}
+	return &testEnv{EffectEnv: env, mod: nftMod}
+}
+
+func setupTestContext(t *testing.T, account types.AccountName) *runtime.Context {
+	t.Helper()
+
+	header := runtime.NewBlockHeader(1, time.Now(), "test-chain", []byte("proposer"))
+	ctx, err := runtime.NewContext(context.Background(), header, account)
+	if err != nil {
+		t.Fatalf("failed to create context: %v", err)
+	}
+	return ctx
+}
+
+// run invokes the message handler with account as the transaction account
+// and applies its effects on success
+func (env *testEnv) run(t *testing.T, account types.AccountName, msg types.Message) error {
+	t.Helper()
+
+	handlers := map[string]func(*runtime.Context, types.Message) ([]effects.Effect, error){
+		TypeMsgCreateCollection: env.mod.handleCreateCollection,
+		TypeMsgMint:             env.mod.handleMint,
+		TypeMsgTransfer:         env.mod.handleTransfer,
+		TypeMsgBurn:             env.mod.handleBurn,
+	}
+	ctx := setupTestContext(t, account)
+	effs, err := handlers[msg.Type()](ctx, msg)
+	if err != nil {
+		return err
+	}
+	env.Apply(t, ctx, effs)
+	return nil
+}
+
+// runTx invokes the handlers of msgs in one transaction of account,
+// returning the error of each message. The effects of the messages that
+// succeeded are applied.
+func (env *testEnv) runTx(t *testing.T, account types.AccountName, msgs ...types.Message) []error {
+	t.Helper()
+
+	handlers := map[string]func(*runtime.Context, types.Message) ([]effects.Effect, error){
+		TypeMsgCreateCollection: env.mod.handleCreateCollection,
+		TypeMsgMint:             env.mod.handleMint,
+		TypeMsgTransfer:         env.mod.handleTransfer,
+		TypeMsgBurn:             env.mod.handleBurn,
+	}
+	ctx := setupTestContext(t, account).WithTxHash([]byte("tx"))
+	errs := make([]error, len(msgs))
+	var all []effects.Effect
+	for i, msg := range msgs {
+		effs, err := handlers[msg.Type()](ctx, msg)
+		errs[i] = err
+		all = append(all, effs...)
+	}
+	env.Apply(t, ctx, all)
+	return errs
+}
+
+// mustRun runs msg and fails the test on error
+func (env *testEnv) mustRun(t *testing.T, account types.AccountName, msg types.Message) {
+	t.Helper()
+
+	if err := env.run(t, account, msg); err != nil {
+		t.Fatalf("%s error = %v", msg.Type(), err)
+	}
+}
+
+// query runs a query service against the state store
+func (env *testEnv) query(t *testing.T, path string, handler query.Handler, req, resp any) error {
+	t.Helper()
+
+	server, err := query.NewServer(env.Store())
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	if err := server.RegisterHandler(path, handler); err != nil {
+		t.Fatalf("RegisterHandler() error = %v", err)
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("failed to encode request: %v", err)
+	}
+	result, err := server.Query(context.Background(), query.Request{Path: path, Data: data})
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(result.Value, resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return nil
+}
+
+// setupPunks creates collection "punks" by alice with tokens 1 and 2 owned
+// by bob and token 3 owned by carol
+func setupPunks(t *testing.T) *testEnv {
+	t.Helper()
+
+	env := setupTestNFTModule(t)
+	env.mustRun(t, "alice", &MsgCreateCollection{Creator: "alice", ID: "punks", Name: "Punks"})
+	for _, mint := range []struct {
+		id        string
+		recipient types.AccountName
+	}{{"1", "bob"}, {"2", "bob"}, {"3", "carol"}} {
+		env.mustRun(t, "alice", &MsgMint{Minter: "alice", CollectionID: "punks", TokenID: mint.id, Recipient: mint.recipient, URI: "ipfs://punk/" + mint.id})
+	}
+	return env
+}
+
+// tokenIDs returns the "<collection>/<token>" names of nfts
+func tokenIDs(nfts []NFT) []string {
+	ids := make([]string, len(nfts))
+	for i, nft := range nfts {
+		ids[i] = nft.CollectionID + "/" + nft.ID
+	}
+	return ids
+}
+
+func equalIDs(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMessages_ValidateBasic(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     interface{ ValidateBasic() error }
+		wantErr bool
+	}{
+		{"create valid", &MsgCreateCollection{Creator: "alice", ID: "punks", Name: "Punks"}, false},
+		{"create short id", &MsgCreateCollection{Creator: "alice", ID: "pk", Name: "Punks"}, true},
+		{"create id with slash", &MsgCreateCollection{Creator: "alice", ID: "pu/nks", Name: "Punks"}, true},
+		{"create no name", &MsgCreateCollection{Creator: "alice", ID: "punks"}, true},
+		{"mint valid", &MsgMint{Minter: "alice", CollectionID: "punks", TokenID: "Token_1", Recipient: "bob"}, false},
+		{"mint invalid token id", &MsgMint{Minter: "alice", CollectionID: "punks", TokenID: "a/b", Recipient: "bob"}, true},
+		{"mint invalid recipient", &MsgMint{Minter: "alice", CollectionID: "punks", TokenID: "1", Recipient: "Bob"}, true},
+		{"mint long uri", &MsgMint{Minter: "alice", CollectionID: "punks", TokenID: "1", Recipient: "bob", URI: string(make([]byte, MaxURILength+1))}, true},
+		{"transfer valid", &MsgTransfer{Sender: "bob", CollectionID: "punks", TokenID: "1", Recipient: "carol"}, false},
+		{"transfer to self", &MsgTransfer{Sender: "bob", CollectionID: "punks", TokenID: "1", Recipient: "bob"}, true},
+		{"burn valid", &MsgBurn{Owner: "bob", CollectionID: "punks", TokenID: "1"}, false},
+		{"burn no token", &MsgBurn{Owner: "bob", CollectionID: "punks"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.msg.ValidateBasic()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateBasic() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNFTModule_CreateCollection(t *testing.T) {
+	env := setupTestNFTModule(t)
+	msg := &MsgCreateCollection{Creator: "alice", ID: "punks", Name: "Punks", URI: "ipfs://punks"}
+
+	if err := env.run(t, "bob", msg); !errors.Is(err, types.ErrUnauthorized) {
+		t.Fatalf("create error = %v, want ErrUnauthorized", err)
+	}
+	env.mustRun(t, "alice", msg)
+	if err := env.run(t, "alice", msg); !errors.Is(err, ErrCollectionExists) {
+		t.Fatalf("create error = %v, want ErrCollectionExists", err)
+	}
+
+	collection, ok, err := env.mod.Collection("punks")
+	if err != nil || !ok {
+		t.Fatalf("Collection() = %v, %v, want collection", ok, err)
+	}
+	if collection.Creator != "alice" || collection.URI != "ipfs://punks" || collection.CreatedHeight != 1 {
+		t.Fatalf("collection = %+v", collection)
+	}
+}
+
+func TestNFTModule_Mint(t *testing.T) {
+	env := setupPunks(t)
+
+	tests := []struct {
+		name    string
+		account types.AccountName
+		msg     *MsgMint
+		wantErr error
+	}{
+		{"not creator", "bob", &MsgMint{Minter: "bob", CollectionID: "punks", TokenID: "9", Recipient: "bob"}, types.ErrUnauthorized},
+		{"not transaction account", "bob", &MsgMint{Minter: "alice", CollectionID: "punks", TokenID: "9", Recipient: "bob"}, types.ErrUnauthorized},
+		{"unknown collection", "alice", &MsgMint{Minter: "alice", CollectionID: "apes", TokenID: "9", Recipient: "bob"}, ErrCollectionNotFound},
+		{"existing token", "alice", &MsgMint{Minter: "alice", CollectionID: "punks", TokenID: "1", Recipient: "carol"}, ErrTokenExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := env.run(t, tt.account, tt.msg); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("mint error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	nft, ok, err := env.mod.NFT("punks", "3")
+	if err != nil || !ok {
+		t.Fatalf("NFT() = %v, %v, want token", ok, err)
+	}
+	if nft.Owner != "carol" || nft.URI != "ipfs://punk/3" {
+		t.Fatalf("nft = %+v", nft)
+	}
+}
+
+func TestNFTModule_TransferAndBurn(t *testing.T) {
+	env := setupPunks(t)
+
+	transfer := &MsgTransfer{Sender: "carol", CollectionID: "punks", TokenID: "1", Recipient: "dave"}
+	if err := env.run(t, "carol", transfer); !errors.Is(err, types.ErrUnauthorized) {
+		t.Fatalf("transfer error = %v, want ErrUnauthorized", err)
+	}
+	transfer.TokenID = "9"
+	if err := env.run(t, "carol", transfer); !errors.Is(err, ErrTokenNotFound) {
+		t.Fatalf("transfer error = %v, want ErrTokenNotFound", err)
+	}
+
+	env.mustRun(t, "bob", &MsgTransfer{Sender: "bob", CollectionID: "punks", TokenID: "1", Recipient: "dave"})
+	if nft, _, _ := env.mod.NFT("punks", "1"); nft == nil || nft.Owner != "dave" {
+		t.Fatalf("nft = %+v, want owner dave", nft)
+	}
+
+	burn := &MsgBurn{Owner: "bob", CollectionID: "punks", TokenID: "1"}
+	if err := env.run(t, "bob", burn); !errors.Is(err, types.ErrUnauthorized) {
+		t.Fatalf("burn error = %v, want ErrUnauthorized", err)
+	}
+	env.mustRun(t, "dave", &MsgBurn{Owner: "dave", CollectionID: "punks", TokenID: "1"})
+	if _, ok, _ := env.mod.NFT("punks", "1"); ok {
+		t.Fatal("burned token still exists")
+	}
+
+	// A burned token ID can be minted again
+	env.mustRun(t, "alice", &MsgMint{Minter: "alice", CollectionID: "punks", TokenID: "1", Recipient: "alice"})
+
+	var resp QueryTokensResponse
+	if err := env.query(t, QueryServiceOwnerTokens, handleQueryOwnerTokens, QueryOwnerTokensRequest{Owner: "dave"}, &resp); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(resp.NFTs) != 0 {
+		t.Fatalf("dave owns %v, want nothing", tokenIDs(resp.NFTs))
+	}
+}
+
+func TestNFTModule_OncePerTx(t *testing.T) {
+	ownerTokens := func(t *testing.T, env *testEnv, owner types.AccountName) []string {
+		t.Helper()
+		var resp QueryTokensResponse
+		if err := env.query(t, QueryServiceOwnerTokens, handleQueryOwnerTokens, QueryOwnerTokensRequest{Owner: owner}, &resp); err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+		return tokenIDs(resp.NFTs)
+	}
+
+	t.Run("two transfers", func(t *testing.T) {
+		env := setupPunks(t)
+		errs := env.runTx(t, "bob",
+			&MsgTransfer{Sender: "bob", CollectionID: "punks", TokenID: "1", Recipient: "dave"},
+			&MsgTransfer{Sender: "bob", CollectionID: "punks", TokenID: "1", Recipient: "carol"})
+		if errs[0] != nil || !errors.Is(errs[1], ErrTokenChanged) {
+			t.Fatalf("transfer errors = %v, want nil and ErrTokenChanged", errs)
+		}
+		if nft, _, _ := env.mod.NFT("punks", "1"); nft == nil || nft.Owner != "dave" {
+			t.Fatalf("nft = %+v, want owner dave", nft)
+		}
+		if got := ownerTokens(t, env, "carol"); !equalIDs(got, []string{"punks/3"}) {
+			t.Fatalf("carol owns %v, want [punks/3]", got)
+		}
+	})
+
+	t.Run("transfer then burn", func(t *testing.T) {
+		env := setupPunks(t)
+		errs := env.runTx(t, "bob",
+			&MsgTransfer{Sender: "bob", CollectionID: "punks", TokenID: "1", Recipient: "dave"},
+			&MsgBurn{Owner: "bob", CollectionID: "punks", TokenID: "1"})
+		if errs[0] != nil || !errors.Is(errs[1], ErrTokenChanged) {
+			t.Fatalf("burn errors = %v, want nil and ErrTokenChanged", errs)
+		}
+		if got := ownerTokens(t, env, "dave"); !equalIDs(got, []string{"punks/1"}) {
+			t.Fatalf("dave owns %v, want [punks/1]", got)
+		}
+	})
+
+	t.Run("two mints", func(t *testing.T) {
+		env := setupPunks(t)
+		errs := env.runTx(t, "alice",
+			&MsgMint{Minter: "alice", CollectionID: "punks", TokenID: "4", Recipient: "bob"},
+			&MsgMint{Minter: "alice", CollectionID: "punks", TokenID: "4", Recipient: "dave"})
+		if errs[0] != nil || !errors.Is(errs[1], ErrTokenExists) {
+			t.Fatalf("mint errors = %v, want nil and ErrTokenExists", errs)
+		}
+		if got := ownerTokens(t, env, "dave"); len(got) != 0 {
+			t.Fatalf("dave owns %v, want nothing", got)
+		}
+	})
+
+	t.Run("two collections", func(t *testing.T) {
+		env := setupTestNFTModule(t)
+		errs := env.runTx(t, "alice",
+			&MsgCreateCollection{Creator: "alice", ID: "apes", Name: "Apes"},
+			&MsgCreateCollection{Creator: "alice", ID: "apes", Name: "Other"})
+		if errs[0] != nil || !errors.Is(errs[1], ErrCollectionExists) {
+			t.Fatalf("create errors = %v, want nil and ErrCollectionExists", errs)
+		}
+	})
+}
+
+func TestQueryOwnerTokens(t *testing.T) {
+	env := setupPunks(t)
+	env.mustRun(t, "carol", &MsgCreateCollection{Creator: "carol", ID: "apes", Name: "Apes"})
+	env.mustRun(t, "carol", &MsgMint{Minter: "carol", CollectionID: "apes", TokenID: "a", Recipient: "bob"})
+	env.mustRun(t, "bob", &MsgTransfer{Sender: "bob", CollectionID: "punks", TokenID: "2", Recipient: "carol"})
+
+	tests := []struct {
+		name string
+		req  QueryOwnerTokensRequest
+		want []string
+	}{
+		{"all collections", QueryOwnerTokensRequest{Owner: "bob"}, []string{"apes/a", "punks/1"}},
+		{"one collection", QueryOwnerTokensRequest{Owner: "bob", CollectionID: "punks"}, []string{"punks/1"}},
+		{"receiver", QueryOwnerTokensRequest{Owner: "carol"}, []string{"punks/2", "punks/3"}},
+		{"no tokens", QueryOwnerTokensRequest{Owner: "dave"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp QueryTokensResponse
+			if err := env.query(t, QueryServiceOwnerTokens, handleQueryOwnerTokens, tt.req, &resp); err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if got := tokenIDs(resp.NFTs); !equalIDs(got, tt.want) {
+				t.Fatalf("tokens = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Paging through bob's tokens one at a time
+	var got []string
+	req := QueryOwnerTokensRequest{Owner: "bob", Pagination: &query.PageRequest{Limit: 1}}
+	for {
+		var resp QueryTokensResponse
+		if err := env.query(t, QueryServiceOwnerTokens, handleQueryOwnerTokens, req, &resp); err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+		got = append(got, tokenIDs(resp.NFTs)...)
+		if resp.Pagination == nil || resp.Pagination.NextKey == nil {
+			break
+		}
+		req.Pagination.Key = resp.Pagination.NextKey
+	}
+	if !equalIDs(got, []string{"apes/a", "punks/1"}) {
+		t.Fatalf("paged tokens = %v", got)
+	}
+}
+
+func TestQueryCollectionsAndTokens(t *testing.T) {
+	env := setupPunks(t)
+	env.mustRun(t, "carol", &MsgCreateCollection{Creator: "carol", ID: "apes", Name: "Apes"})
+
+	var collection QueryCollectionResponse
+	if err := env.query(t, QueryServiceCollection, handleQueryCollection, QueryCollectionRequest{CollectionID: "punks"}, &collection); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if collection.Collection.Creator != "alice" {
+		t.Fatalf("collection = %+v", collection.Collection)
+	}
+	if err := env.query(t, QueryServiceCollection, handleQueryCollection, QueryCollectionRequest{CollectionID: "cats"}, &collection); !errors.Is(err, types.ErrNotFound) {
+		t.Fatalf("Query() error = %v, want ErrNotFound", err)
+	}
+
+	var collections QueryCollectionsResponse
+	if err := env.query(t, QueryServiceCollections, handleQueryCollections, QueryCollectionsRequest{}, &collections); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(collections.Collections) != 2 || collections.Collections[0].ID != "apes" || collections.Collections[1].ID != "punks" {
+		t.Fatalf("collections = %+v", collections.Collections)
+	}
+
+	var token QueryTokenResponse
+	if err := env.query(t, QueryServiceToken, handleQueryToken, QueryTokenRequest{CollectionID: "punks", TokenID: "2"}, &token); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if token.NFT.Owner != "bob" || token.NFT.URI != "ipfs://punk/2" {
+		t.Fatalf("token = %+v", token.NFT)
+	}
+
+	var tokens QueryTokensResponse
+	req := QueryTokensRequest{CollectionID: "punks", Pagination: &query.PageRequest{Limit: 2, CountTotal: true}}
+	if err := env.query(t, QueryServiceTokens, handleQueryTokens, req, &tokens); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if got := tokenIDs(tokens.NFTs); !equalIDs(got, []string{"punks/1", "punks/2"}) || tokens.Pagination.Total != 3 {
+		t.Fatalf("tokens = %v, total %d", got, tokens.Pagination.Total)
+	}
+}
+
+func TestNewNFTModule(t *testing.T) {
+	if _, err := NewNFTModule(nil); err == nil {
+		t.Fatal("expected error for nil store capability")
+	}
+
+	// The capability must be the one granted to the nft module, whose
+	// namespace its state effects write to
+	env := punnettesting.NewEffectEnv(t)
+	if _, err := NewNFTModule(env.GrantStore(t, "escrow")); err == nil {
+		t.Fatal("expected error for another module's store capability")
+	}
+}
+
+func TestCreateModule(t *testing.T) {
+	env := setupTestNFTModule(t)
+
+	mod, err := CreateModule(env.mod)
+	if err != nil {
+		t.Fatalf("CreateModule() error = %v", err)
+	}
+	if mod.Name() != ModuleName {
+		t.Fatalf("Name() = %s, want %s", mod.Name(), ModuleName)
+	}
+
+	if _, err := CreateModule(nil); err == nil {
+		t.Fatal("CreateModule(nil) succeeded")
+	}
+}
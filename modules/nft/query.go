@@ -0,0 +1,230 @@
+package nft
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/query"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Height-aware query service paths. Requests and responses are JSON; list
+// services page with query.PageRequest.
+const (
+	QueryServiceCollection  = "/nft/collection"
+	QueryServiceCollections = "/nft/collections"
+	QueryServiceToken       = "/nft/token"
+	QueryServiceTokens      = "/nft/tokens"
+	QueryServiceOwnerTokens = "/nft/owner_tokens"
+)
+
+// QueryCollectionRequest is the request for QueryServiceCollection
+type QueryCollectionRequest struct {
+	CollectionID string `json:"collection_id"`
+}
+
+// QueryCollectionResponse is the response for QueryServiceCollection
+type QueryCollectionResponse struct {
+	Collection Collection `json:"collection"`
+}
+
+// QueryCollectionsRequest is the request for QueryServiceCollections
+type QueryCollectionsRequest struct {
+	Pagination *query.PageRequest `json:"pagination,omitempty"`
+}
+
+// QueryCollectionsResponse is the response for QueryServiceCollections
+type QueryCollectionsResponse struct {
+	Collections []Collection        `json:"collections"`
+	Pagination  *query.PageResponse `json:"pagination"`
+}
+
+// QueryTokenRequest is the request for QueryServiceToken
+type QueryTokenRequest struct {
+	CollectionID string `json:"collection_id"`
+	TokenID      string `json:"token_id"`
+}
+
+// QueryTokenResponse is the response for QueryServiceToken
+type QueryTokenResponse struct {
+	NFT NFT `json:"nft"`
+}
+
+// QueryTokensRequest is the request for QueryServiceTokens
+type QueryTokensRequest struct {
+	CollectionID string             `json:"collection_id"`
+	Pagination   *query.PageRequest `json:"pagination,omitempty"`
+}
+
+// QueryTokensResponse is the response for QueryServiceTokens and
+// QueryServiceOwnerTokens
+type QueryTokensResponse struct {
+	NFTs       []NFT               `json:"nfts"`
+	Pagination *query.PageResponse `json:"pagination"`
+}
+
+// QueryOwnerTokensRequest is the request for QueryServiceOwnerTokens. An
+// empty CollectionID enumerates the owner's tokens of every collection.
+type QueryOwnerTokensRequest struct {
+	Owner        types.AccountName  `json:"owner"`
+	CollectionID string             `json:"collection_id,omitempty"`
+	Pagination   *query.PageRequest `json:"pagination,omitempty"`
+}
+
+// decodeRequest unmarshals a JSON query request
+func decodeRequest(data []byte, req any) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, req); err != nil {
+		return fmt.Errorf("invalid nft query: %w", err)
+	}
+	return nil
+}
+
+// handleQueryCollection serves QueryServiceCollection from the state pinned
+// at the query height. An unknown collection is ErrNotFound.
+func handleQueryCollection(ctx *query.Context, data []byte) ([]byte, error) {
+	var req QueryCollectionRequest
+	if err := decodeRequest(data, &req); err != nil {
+		return nil, err
+	}
+
+	collection, ok, err := getCollection(capability.ModuleStore(ctx.Store(), ModuleName), req.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: collection %s", types.ErrNotFound, req.CollectionID)
+	}
+
+	return json.Marshal(QueryCollectionResponse{Collection: *collection})
+}
+
+// handleQueryCollections serves QueryServiceCollections, in collection ID order
+func handleQueryCollections(ctx *query.Context, data []byte) ([]byte, error) {
+	var req QueryCollectionsRequest
+	if err := decodeRequest(data, &req); err != nil {
+		return nil, err
+	}
+
+	s := store.NewPrefixStore(capability.ModuleStore(ctx.Store(), ModuleName), collectionPrefix)
+	resp := QueryCollectionsResponse{Collections: []Collection{}}
+	page, err := query.Paginate(s, req.Pagination, func(key, value []byte) error {
+		var collection Collection
+		if err := json.Unmarshal(value, &collection); err != nil {
+			return fmt.Errorf("failed to decode collection %s: %w", key, err)
+		}
+		resp.Collections = append(resp.Collections, collection)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Pagination = page
+
+	return json.Marshal(resp)
+}
+
+// handleQueryToken serves QueryServiceToken from the state pinned at the
+// query height. An unknown or burned token is ErrNotFound.
+func handleQueryToken(ctx *query.Context, data []byte) ([]byte, error) {
+	var req QueryTokenRequest
+	if err := decodeRequest(data, &req); err != nil {
+		return nil, err
+	}
+
+	nft, ok, err := getNFT(capability.ModuleStore(ctx.Store(), ModuleName), req.CollectionID, req.TokenID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: token %s/%s", types.ErrNotFound, req.CollectionID, req.TokenID)
+	}
+
+	return json.Marshal(QueryTokenResponse{NFT: *nft})
+}
+
+// handleQueryTokens serves QueryServiceTokens: the tokens of a collection in
+// token ID order
+func handleQueryTokens(ctx *query.Context, data []byte) ([]byte, error) {
+	var req QueryTokensRequest
+	if err := decodeRequest(data, &req); err != nil {
+		return nil, err
+	}
+	if err := ValidateCollectionID(req.CollectionID); err != nil {
+		return nil, err
+	}
+
+	s := store.NewPrefixStore(capability.ModuleStore(ctx.Store(), ModuleName), tokenPrefix(req.CollectionID))
+	resp := QueryTokensResponse{NFTs: []NFT{}}
+	page, err := query.Paginate(s, req.Pagination, func(key, value []byte) error {
+		var nft NFT
+		if err := json.Unmarshal(value, &nft); err != nil {
+			return fmt.Errorf("failed to decode token %s: %w", key, err)
+		}
+		resp.NFTs = append(resp.NFTs, nft)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Pagination = page
+
+	return json.Marshal(resp)
+}
+
+// handleQueryOwnerTokens serves QueryServiceOwnerTokens: the tokens of an
+// owner in (collection ID, token ID) order.
+//
+// Index entries whose token has another owner are skipped, so a page may
+// hold fewer than its limit of tokens; see the package doc on transactions
+// touching a token twice.
+func handleQueryOwnerTokens(ctx *query.Context, data []byte) ([]byte, error) {
+	var req QueryOwnerTokensRequest
+	if err := decodeRequest(data, &req); err != nil {
+		return nil, err
+	}
+	if !req.Owner.IsValid() {
+		return nil, fmt.Errorf("%w: invalid owner %s", types.ErrInvalidAccount, req.Owner)
+	}
+
+	moduleStore := capability.ModuleStore(ctx.Store(), ModuleName)
+	prefix := ownerPrefix(req.Owner)
+	if req.CollectionID != "" {
+		if err := ValidateCollectionID(req.CollectionID); err != nil {
+			return nil, err
+		}
+		prefix = append(prefix, req.CollectionID+"/"...)
+	}
+
+	resp := QueryTokensResponse{NFTs: []NFT{}}
+	page, err := query.Paginate(store.NewPrefixStore(moduleStore, prefix), req.Pagination, func(key, _ []byte) error {
+		collectionID, tokenID := req.CollectionID, string(key)
+		if collectionID == "" {
+			var found bool
+			collectionID, tokenID, found = strings.Cut(string(key), "/")
+			if !found {
+				return fmt.Errorf("malformed owner index key %s", key)
+			}
+		}
+
+		nft, ok, err := getNFT(moduleStore, collectionID, tokenID)
+		if err != nil {
+			return err
+		}
+		if ok && nft.Owner == req.Owner {
+			resp.NFTs = append(resp.NFTs, *nft)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Pagination = page
+
+	return json.Marshal(resp)
+}
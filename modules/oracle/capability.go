@@ -0,0 +1,63 @@
+package oracle
+
+import (
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// PriceCapability provides read-only access to oracle prices.
+// It satisfies feemarket.PriceOracle.
+type PriceCapability interface {
+	// ModuleName returns the module this capability was granted to
+	ModuleName() string
+
+	// Price returns the aggregated price of denom, if any
+	Price(denom string) (PriceRecord, bool, error)
+
+	// ConversionRate returns the price of denom for a transaction executing
+	// in ctx's block and its reference rate (see OracleModule.ConversionRate)
+	ConversionRate(ctx *runtime.Context, denom string) (rate, reference types.Ratio, err error)
+}
+
+// priceCapability is the implementation of PriceCapability
+type priceCapability struct {
+	moduleName string
+	oracle     *OracleModule
+}
+
+// GrantPriceCapability grants read access to prices to moduleName
+func (m *OracleModule) GrantPriceCapability(moduleName string) (PriceCapability, error) {
+	if m == nil {
+		return nil, fmt.Errorf("oracle module is nil")
+	}
+	if moduleName == "" {
+		return nil, fmt.Errorf("module name cannot be empty")
+	}
+	return &priceCapability{moduleName: moduleName, oracle: m}, nil
+}
+
+// ModuleName returns the module this capability was granted to
+func (pc *priceCapability) ModuleName() string {
+	if pc == nil {
+		return ""
+	}
+	return pc.moduleName
+}
+
+// Price returns the aggregated price of denom, if any
+func (pc *priceCapability) Price(denom string) (PriceRecord, bool, error) {
+	if pc == nil {
+		return PriceRecord{}, false, fmt.Errorf("price capability is nil")
+	}
+	return pc.oracle.Price(denom)
+}
+
+// ConversionRate returns the price of denom and its reference rate
+func (pc *priceCapability) ConversionRate(ctx *runtime.Context, denom string) (types.Ratio, types.Ratio, error) {
+	if pc == nil {
+		return types.Ratio{}, types.Ratio{}, fmt.Errorf("price capability is nil")
+	}
+	return pc.oracle.ConversionRate(ctx, denom)
+}
@@ -0,0 +1,65 @@
+package oracle
+
+import (
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Message type identifiers
+const (
+	TypeMsgPriceVote = "/punnet.oracle.v1.MsgPriceVote"
+)
+
+// MsgPriceVote is a feeder's vote on the price of a denom in the base denom.
+// Votes are aggregated at the end of the block they are included in; a
+// later vote by the same feeder for the same denom in the block replaces
+// the earlier one.
+type MsgPriceVote struct {
+	// Feeder is the price feeder submitting the vote
+	Feeder types.AccountName `json:"feeder"`
+
+	// Denom is the priced denomination
+	Denom string `json:"denom"`
+
+	// Rate is the price: Rate.Numerator base denom units are worth
+	// Rate.Denominator units of Denom
+	Rate types.Ratio `json:"rate"`
+}
+
+// Type returns the message type
+func (m *MsgPriceVote) Type() string {
+	return TypeMsgPriceVote
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgPriceVote) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Feeder.IsValid() {
+		return fmt.Errorf("%w: invalid feeder name %s", types.ErrInvalidAccount, m.Feeder)
+	}
+
+	if !(types.Coin{Denom: m.Denom, Amount: 1}).IsValid() {
+		return fmt.Errorf("invalid denom %q", m.Denom)
+	}
+
+	if err := m.Rate.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid rate: %w", err)
+	}
+	if m.Rate.Numerator == 0 {
+		return fmt.Errorf("rate must be positive")
+	}
+
+	return nil
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgPriceVote) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Feeder}
+}
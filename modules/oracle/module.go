@@ -0,0 +1,437 @@
+// Package oracle aggregates externally reported prices of denominations in
+// a base denomination.
+//
+// Whitelisted feeder accounts vote on prices with MsgPriceVote. At the end
+// of each block the votes for each denom are aggregated: the median vote
+// becomes the denom's price, and votes far from the median are reported to
+// Hooks, e.g. for slashing. Other modules read prices through a
+// PriceCapability.
+//
+// Each price keeps a reference rate: the price before the latest update,
+// for transactions in the block right after the update, which were likely
+// signed against it. Consumers such as fee conversion compare the current
+// rate with the reference to bound slippage.
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/effects"
+	sdkerrors "github.com/blockberries/punnet-sdk/errors"
+	"github.com/blockberries/punnet-sdk/module"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Module name
+const ModuleName = "oracle"
+
+var (
+	// ErrNotFeeder is returned when a price is submitted by an account that is not a feeder
+	ErrNotFeeder = errors.New("not a price feeder")
+
+	// ErrPriceNotFound is returned when no price is recorded for a denom
+	ErrPriceNotFound = errors.New("price not found")
+)
+
+// Oracle module error codes, in the ModuleName codespace.
+func init() {
+	sdkerrors.MustRegister(ModuleName, 2, ErrNotFeeder)
+	sdkerrors.MustRegister(ModuleName, 3, ErrPriceNotFound)
+}
+
+// Event types
+const (
+	EventTypePriceVote    = "oracle.price_vote"
+	EventTypePriceUpdated = "oracle.price_updated"
+	EventTypeOutlierVote  = "oracle.outlier_vote"
+)
+
+// State keys, relative to the module namespace
+const (
+	// pricePrefix prefixes "<denom>", holding a PriceRecord
+	pricePrefix = "price/"
+
+	// votePrefix prefixes "<denom>/<feeder>", holding the Vote of the
+	// current block; all votes are deleted at end block
+	votePrefix = "vote/"
+)
+
+// priceKey returns the state key of the price of denom
+func priceKey(denom string) []byte {
+	return []byte(pricePrefix + denom)
+}
+
+// voteKey returns the state key of feeder's vote on denom
+func voteKey(denom string, feeder types.AccountName) []byte {
+	return []byte(votePrefix + denom + "/" + string(feeder))
+}
+
+// PriceRecord is the aggregated price of a denom in the base denom
+type PriceRecord struct {
+	// Rate is the latest price (see MsgPriceVote.Rate)
+	Rate types.Ratio `json:"rate"`
+
+	// Reference is the price before the update at UpdatedHeight
+	// (Rate itself for the first price of a denom)
+	Reference types.Ratio `json:"reference"`
+
+	// UpdatedHeight is the height whose votes set Rate
+	UpdatedHeight uint64 `json:"updated_height"`
+}
+
+// ReferenceAt returns the reference rate for a block at height: Reference
+// in the block after the update, Rate once the update has been committed
+// for a whole block
+func (r PriceRecord) ReferenceAt(height uint64) types.Ratio {
+	if r.UpdatedHeight+1 < height {
+		return r.Rate
+	}
+	return r.Reference
+}
+
+// Vote is a feeder's vote in the current block
+type Vote struct {
+	// Feeder is the voting feeder
+	Feeder types.AccountName `json:"feeder"`
+
+	// Rate is the voted price
+	Rate types.Ratio `json:"rate"`
+}
+
+// Hooks lets other modules react to price aggregation
+type Hooks interface {
+	// OnOutlierVote is called at end block for each vote deviating from the
+	// median by more than Params.OutlierThreshold. Its effects are applied
+	// with the oracle's end block effects, e.g. to slash feeder.
+	//
+	// PRECONDITION: Returning an error fails the end block, so hooks should
+	// only fail on internal errors.
+	OnOutlierVote(ctx *runtime.Context, feeder types.AccountName, denom string, vote, median types.Ratio) ([]effects.Effect, error)
+}
+
+// OracleModule records and aggregates price votes
+type OracleModule struct {
+	// moduleStore is the "module/oracle/" view of the state store
+	moduleStore store.BackingStore
+
+	// voteStore is the "vote/" view of moduleStore
+	voteStore store.BackingStore
+
+	// params are fixed at construction
+	params Params
+
+	// feeders is params.Feeders as a set
+	feeders map[types.AccountName]bool
+
+	// hooks are notified of outlier votes (may be nil)
+	hooks Hooks
+}
+
+// NewOracleModule creates an oracle module over its store capability.
+// hooks may be nil.
+func NewOracleModule(storeCap capability.StoreCapability, params Params, hooks Hooks) (*OracleModule, error) {
+	if err := capability.CheckStoreCapability(storeCap, ModuleName); err != nil {
+		return nil, err
+	}
+
+	if err := params.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid params: %w", err)
+	}
+
+	feeders := make(map[types.AccountName]bool, len(params.Feeders))
+	for _, feeder := range params.Feeders {
+		feeders[feeder] = true
+	}
+	params.Feeders = append([]types.AccountName(nil), params.Feeders...)
+
+	return &OracleModule{
+		moduleStore: storeCap,
+		voteStore:   store.NewPrefixStore(storeCap, []byte(votePrefix)),
+		params:      params,
+		feeders:     feeders,
+		hooks:       hooks,
+	}, nil
+}
+
+// CreateModule creates the oracle module using the module builder
+//
+// Usage:
+//
+//	params := oracle.DefaultParams()
+//	params.Feeders = feeders
+//	storeCap, _ := capManager.GrantStoreCapability(oracle.ModuleName)
+//	oracleMod, _ := oracle.NewOracleModule(storeCap, params, nil)
+//	mod, _ := oracle.CreateModule(oracleMod)
+func CreateModule(oracleMod *OracleModule) (module.Module, error) {
+	if oracleMod == nil {
+		return nil, fmt.Errorf("oracle module cannot be nil")
+	}
+
+	return module.NewModuleBuilder(ModuleName).
+		WithMsgHandler(TypeMsgPriceVote, oracleMod.handlePriceVote).
+		WithEndBlocker(oracleMod.endBlock).
+		WithQueryHandler("/price", oracleMod.handleQueryPrice).
+		WithQueryHandler("/votes", oracleMod.handleQueryVotes).
+		WithQueryHandler("/params", oracleMod.handleQueryParams).
+		Build()
+}
+
+// Params returns the module parameters
+func (m *OracleModule) Params() Params {
+	params := m.params
+	params.Feeders = append([]types.AccountName(nil), m.params.Feeders...)
+	return params
+}
+
+// IsFeeder reports whether account may vote on prices
+func (m *OracleModule) IsFeeder(account types.AccountName) bool {
+	return m.feeders[account]
+}
+
+// Price returns the aggregated price of denom, if any
+func (m *OracleModule) Price(denom string) (PriceRecord, bool, error) {
+	if m == nil {
+		return PriceRecord{}, false, fmt.Errorf("oracle module is nil")
+	}
+
+	data, err := m.moduleStore.Get(priceKey(denom))
+	if errors.Is(err, store.ErrNotFound) || (err == nil && data == nil) {
+		return PriceRecord{}, false, nil
+	}
+	if err != nil {
+		return PriceRecord{}, false, fmt.Errorf("failed to read price: %w", err)
+	}
+
+	var record PriceRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return PriceRecord{}, false, fmt.Errorf("failed to decode price of %s: %w", denom, err)
+	}
+	return record, true, nil
+}
+
+// ConversionRate returns the price of denom in the base denom for a
+// transaction executing in ctx's block, and the reference rate slippage is
+// measured against. Returns ErrPriceNotFound if denom has no price.
+func (m *OracleModule) ConversionRate(ctx *runtime.Context, denom string) (rate, reference types.Ratio, err error) {
+	if ctx == nil {
+		return types.Ratio{}, types.Ratio{}, fmt.Errorf("context is nil")
+	}
+
+	record, ok, err := m.Price(denom)
+	if err != nil {
+		return types.Ratio{}, types.Ratio{}, err
+	}
+	if !ok {
+		return types.Ratio{}, types.Ratio{}, fmt.Errorf("%w: %s", ErrPriceNotFound, denom)
+	}
+	return record.Rate, record.ReferenceAt(ctx.BlockHeight()), nil
+}
+
+// Votes returns the current block's votes, grouped by denom and ordered by feeder
+func (m *OracleModule) Votes() (map[string][]Vote, error) {
+	if m == nil {
+		return nil, fmt.Errorf("oracle module is nil")
+	}
+
+	iter, err := m.voteStore.Iterator(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate votes: %w", err)
+	}
+	defer iter.Close()
+
+	votes := make(map[string][]Vote)
+	for ; iter.Valid(); iter.Next() {
+		// Denoms may contain '/', feeder names may not
+		key := iter.Key()
+		sep := bytes.LastIndexByte(key, '/')
+		if sep < 0 {
+			return nil, fmt.Errorf("invalid vote key %q", key)
+		}
+
+		var vote Vote
+		if err := json.Unmarshal(iter.Value(), &vote); err != nil {
+			return nil, fmt.Errorf("failed to decode vote %s: %w", key, err)
+		}
+		denom := string(key[:sep])
+		votes[denom] = append(votes[denom], vote)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to iterate votes: %w", err)
+	}
+	return votes, nil
+}
+
+// handlePriceVote handles MsgPriceVote
+func (m *OracleModule) handlePriceVote(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("oracle module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	voteMsg, ok := msg.(*MsgPriceVote)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgPriceVote")
+	}
+
+	if voteMsg.Feeder != ctx.Account() {
+		return nil, fmt.Errorf("%w: %s must be transaction account", types.ErrUnauthorized, voteMsg.Feeder)
+	}
+
+	if !m.IsFeeder(voteMsg.Feeder) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFeeder, voteMsg.Feeder)
+	}
+
+	if voteMsg.Denom == m.params.BaseDenom {
+		return nil, fmt.Errorf("cannot price base denom %s", m.params.BaseDenom)
+	}
+
+	data, err := json.Marshal(Vote{Feeder: voteMsg.Feeder, Rate: voteMsg.Rate})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode vote: %w", err)
+	}
+
+	return []effects.Effect{
+		effects.NewStateWriteEffect(ModuleName, voteKey(voteMsg.Denom, voteMsg.Feeder), data),
+		effects.NewEventEffect(EventTypePriceVote, map[string][]byte{
+			"feeder": []byte(voteMsg.Feeder),
+			"denom":  []byte(voteMsg.Denom),
+			"rate":   []byte(ratioString(voteMsg.Rate)),
+			"height": []byte(strconv.FormatUint(ctx.BlockHeight(), 10)),
+		}),
+	}, nil
+}
+
+// endBlock aggregates the block's votes into prices and clears them.
+//
+// For each denom (in sorted order) with at least Params.MinVotes votes, the
+// median vote becomes the price and every vote deviating from it by more
+// than Params.OutlierThreshold is passed to Hooks.OnOutlierVote. Votes for
+// denoms short of MinVotes are discarded.
+func (m *OracleModule) endBlock(ctx *runtime.Context) ([]effects.Effect, []types.ValidatorUpdate, error) {
+	if m == nil {
+		return nil, nil, fmt.Errorf("oracle module is nil")
+	}
+	if ctx == nil {
+		return nil, nil, fmt.Errorf("context is nil")
+	}
+
+	votes, err := m.Votes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	denoms := make([]string, 0, len(votes))
+	for denom := range votes {
+		denoms = append(denoms, denom)
+	}
+	sort.Strings(denoms)
+
+	height := ctx.BlockHeight()
+	var effs []effects.Effect
+	for _, denom := range denoms {
+		denomVotes := votes[denom]
+		for _, vote := range denomVotes {
+			effs = append(effs, effects.NewStateDeleteEffect(ModuleName, voteKey(denom, vote.Feeder)))
+		}
+		if uint64(len(denomVotes)) < m.params.MinVotes {
+			continue
+		}
+
+		median := medianVote(denomVotes)
+		previous, exists, err := m.Price(denom)
+		if err != nil {
+			return nil, nil, err
+		}
+		record := PriceRecord{Rate: median, Reference: median, UpdatedHeight: height}
+		if exists {
+			record.Reference = previous.Rate
+		}
+		data, err := json.Marshal(record)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode price: %w", err)
+		}
+		effs = append(effs,
+			effects.NewStateWriteEffect(ModuleName, priceKey(denom), data),
+			effects.NewEventEffect(EventTypePriceUpdated, map[string][]byte{
+				"denom":  []byte(denom),
+				"rate":   []byte(ratioString(median)),
+				"votes":  []byte(strconv.Itoa(len(denomVotes))),
+				"height": []byte(strconv.FormatUint(height, 10)),
+			}),
+		)
+
+		for _, vote := range denomVotes {
+			if !isOutlier(vote.Rate, median, m.params.OutlierThreshold) {
+				continue
+			}
+			effs = append(effs, effects.NewEventEffect(EventTypeOutlierVote, map[string][]byte{
+				"feeder": []byte(vote.Feeder),
+				"denom":  []byte(denom),
+				"rate":   []byte(ratioString(vote.Rate)),
+				"median": []byte(ratioString(median)),
+				"height": []byte(strconv.FormatUint(height, 10)),
+			}))
+			if m.hooks == nil {
+				continue
+			}
+			hookEffs, err := m.hooks.OnOutlierVote(ctx, vote.Feeder, denom, vote.Rate, median)
+			if err != nil {
+				return nil, nil, fmt.Errorf("outlier hook failed for %s: %w", vote.Feeder, err)
+			}
+			effs = append(effs, hookEffs...)
+		}
+	}
+
+	return effs, nil, nil
+}
+
+// ratioString formats r as "numerator/denominator"
+func ratioString(r types.Ratio) string {
+	return strconv.FormatUint(r.Numerator, 10) + "/" + strconv.FormatUint(r.Denominator, 10)
+}
+
+// handleQueryPrice returns the price record of the denom named by data as
+// JSON, or null if none
+func (m *OracleModule) handleQueryPrice(ctx context.Context, path string, data []byte) ([]byte, error) {
+	record, ok, err := m.Price(string(data))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return json.Marshal(nil)
+	}
+	return json.Marshal(record)
+}
+
+// handleQueryVotes returns the current block's votes on the denom named by
+// data as a JSON array
+func (m *OracleModule) handleQueryVotes(ctx context.Context, path string, data []byte) ([]byte, error) {
+	votes, err := m.Votes()
+	if err != nil {
+		return nil, err
+	}
+	denomVotes := votes[string(data)]
+	if denomVotes == nil {
+		denomVotes = []Vote{}
+	}
+	return json.Marshal(denomVotes)
+}
+
+// handleQueryParams returns the module parameters as JSON
+func (m *OracleModule) handleQueryParams(ctx context.Context, path string, data []byte) ([]byte, error) {
+	if m == nil {
+		return nil, fmt.Errorf("oracle module is nil")
+	}
+	return json.Marshal(m.Params())
+}
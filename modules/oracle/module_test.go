@@ -0,0 +1,410 @@
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/runtime"
+	punnettesting "github.com/blockberries/punnet-sdk/testing"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+type testEnv struct {
+	*punnettesting.EffectEnv
+	mod   *OracleModule
+	hooks *testHooks
+}
+
+// testHooks records outlier votes
+type testHooks struct {
+	outliers []string
+	err      error
+}
+
+func (h *testHooks) OnOutlierVote(ctx *runtime.Context, feeder types.AccountName, denom string, vote, median types.Ratio) ([]effects.Effect, error) {
+	if h.err != nil {
+		return nil, h.err
+	}
+	h.outliers = append(h.outliers, string(feeder)+"/"+denom)
+	return []effects.Effect{effects.NewEventEffect("test.slash", map[string][]byte{"feeder": []byte(feeder)})}, nil
+}
+
+func testParams() Params {
+	params := DefaultParams()
+	params.Feeders = []types.AccountName{"feeder1", "feeder2", "feeder3", "feeder4"}
+	return params
+}
+
+func setupTestOracleModule(t *testing.T, params Params) *testEnv {
+	t.Helper()
+
+	env := punnettesting.NewEffectEnv(t)
+	hooks := &testHooks{}
+	oracleMod, err := NewOracleModule(env.GrantStore(t, ModuleName), params, hooks)
+	if err != nil {
+		t.Fatalf("failed to create oracle module: %v", err)
+	}
+	return &testEnv{EffectEnv: env, mod: oracleMod, hooks: hooks}
+}
+
+func setupTestContext(t *testing.T, height uint64, account types.AccountName) *runtime.Context {
+	t.Helper()
+
+	header := runtime.NewBlockHeader(height, time.Now(), "test-chain", []byte("proposer"))
+	ctx, err := runtime.NewContext(context.Background(), header, account)
+	if err != nil {
+		t.Fatalf("failed to create context: %v", err)
+	}
+	return ctx
+}
+
+// vote runs MsgPriceVote at height as feeder and applies its effects
+func (env *testEnv) vote(t *testing.T, height uint64, feeder types.AccountName, denom string, rate types.Ratio) error {
+	t.Helper()
+
+	msg := &MsgPriceVote{Feeder: feeder, Denom: denom, Rate: rate}
+	ctx := setupTestContext(t, height, feeder)
+	effs, err := env.mod.handlePriceVote(ctx, msg)
+	if err != nil {
+		return err
+	}
+	env.Apply(t, ctx, effs)
+	return nil
+}
+
+// endBlock runs the end blocker at height and applies its effects
+func (env *testEnv) endBlock(t *testing.T, height uint64) []effects.Effect {
+	t.Helper()
+
+	ctx := setupTestContext(t, height, "system")
+	effs, updates, err := env.mod.endBlock(ctx)
+	if err != nil {
+		t.Fatalf("end block failed: %v", err)
+	}
+	if len(updates) != 0 {
+		t.Fatalf("expected no validator updates, got %d", len(updates))
+	}
+	env.Apply(t, ctx, effs)
+	return effs
+}
+
+func (env *testEnv) mustVote(t *testing.T, height uint64, feeder types.AccountName, denom string, rate types.Ratio) {
+	t.Helper()
+
+	if err := env.vote(t, height, feeder, denom, rate); err != nil {
+		t.Fatalf("vote by %s failed: %v", feeder, err)
+	}
+}
+
+func ratio(n, d uint64) types.Ratio {
+	return types.Ratio{Numerator: n, Denominator: d}
+}
+
+func TestEndBlock_Median(t *testing.T) {
+	tests := []struct {
+		name  string
+		votes []types.Ratio
+		want  types.Ratio
+	}{
+		{name: "single", votes: []types.Ratio{ratio(2, 1)}, want: ratio(2, 1)},
+		{name: "odd", votes: []types.Ratio{ratio(3, 1), ratio(1, 1), ratio(2, 1)}, want: ratio(2, 1)},
+		{name: "even takes lower middle", votes: []types.Ratio{ratio(4, 1), ratio(1, 1), ratio(3, 1), ratio(2, 1)}, want: ratio(2, 1)},
+		{name: "equal values", votes: []types.Ratio{ratio(1, 2), ratio(2, 4), ratio(3, 6)}, want: ratio(1, 2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := setupTestOracleModule(t, testParams())
+			for i, rate := range tt.votes {
+				env.mustVote(t, 5, testParams().Feeders[i], "usdc", rate)
+			}
+			env.endBlock(t, 5)
+
+			record, ok, err := env.mod.Price("usdc")
+			if err != nil || !ok {
+				t.Fatalf("expected price, got %v, %v", ok, err)
+			}
+			if compareRatio(record.Rate, tt.want) != 0 || record.UpdatedHeight != 5 {
+				t.Fatalf("expected %v at height 5, got %+v", tt.want, record)
+			}
+
+			votes, err := env.mod.Votes()
+			if err != nil {
+				t.Fatalf("Votes failed: %v", err)
+			}
+			if len(votes) != 0 {
+				t.Fatalf("expected votes cleared, got %v", votes)
+			}
+		})
+	}
+}
+
+func TestEndBlock_Outliers(t *testing.T) {
+	env := setupTestOracleModule(t, testParams())
+
+	env.mustVote(t, 5, "feeder1", "usdc", ratio(100, 1))
+	env.mustVote(t, 5, "feeder2", "usdc", ratio(110, 1)) // 10% off: within threshold
+	env.mustVote(t, 5, "feeder3", "usdc", ratio(111, 1)) // 11% off: outlier
+	env.mustVote(t, 5, "feeder4", "usdc", ratio(10, 1))
+	effs := env.endBlock(t, 5)
+
+	if len(env.hooks.outliers) != 2 || env.hooks.outliers[0] != "feeder3/usdc" || env.hooks.outliers[1] != "feeder4/usdc" {
+		t.Fatalf("expected feeder3 and feeder4 reported, got %v", env.hooks.outliers)
+	}
+
+	var slashes, outlierEvents int
+	for _, eff := range effs {
+		if e, ok := eff.(effects.EventEffect); ok {
+			switch e.EventType {
+			case "test.slash":
+				slashes++
+			case EventTypeOutlierVote:
+				outlierEvents++
+			}
+		}
+	}
+	if slashes != 2 || outlierEvents != 2 {
+		t.Fatalf("expected 2 hook effects and 2 outlier events, got %d and %d", slashes, outlierEvents)
+	}
+
+	// A failing hook fails the end block
+	env.hooks.err = errors.New("slashing failed")
+	env.mustVote(t, 6, "feeder1", "usdc", ratio(100, 1))
+	env.mustVote(t, 6, "feeder2", "usdc", ratio(1, 1))
+	if _, _, err := env.mod.endBlock(setupTestContext(t, 6, "system")); err == nil {
+		t.Fatal("expected end block to fail")
+	}
+}
+
+func TestEndBlock_MinVotes(t *testing.T) {
+	params := testParams()
+	params.MinVotes = 2
+	env := setupTestOracleModule(t, params)
+
+	env.mustVote(t, 5, "feeder1", "usdc", ratio(2, 1))
+	env.mustVote(t, 5, "feeder1", "atom", ratio(10, 1))
+	env.mustVote(t, 5, "feeder2", "atom", ratio(12, 1))
+	env.endBlock(t, 5)
+
+	if _, ok, _ := env.mod.Price("usdc"); ok {
+		t.Fatal("expected no usdc price with a single vote")
+	}
+	if record, ok, _ := env.mod.Price("atom"); !ok || record.Rate != ratio(10, 1) {
+		t.Fatalf("expected atom price 10/1, got %+v", record)
+	}
+
+	// Votes short of the quorum do not carry over
+	env.mustVote(t, 6, "feeder2", "usdc", ratio(2, 1))
+	env.endBlock(t, 6)
+	if _, ok, _ := env.mod.Price("usdc"); ok {
+		t.Fatal("expected no usdc price")
+	}
+}
+
+func TestPriceVote_ReplacesWithinBlock(t *testing.T) {
+	env := setupTestOracleModule(t, testParams())
+
+	env.mustVote(t, 5, "feeder1", "ibc/usdc", ratio(2, 1))
+	env.mustVote(t, 5, "feeder1", "ibc/usdc", ratio(3, 1))
+
+	votes, err := env.mod.Votes()
+	if err != nil {
+		t.Fatalf("Votes failed: %v", err)
+	}
+	if len(votes["ibc/usdc"]) != 1 || votes["ibc/usdc"][0].Rate != ratio(3, 1) {
+		t.Fatalf("expected a single replaced vote, got %v", votes)
+	}
+}
+
+func TestConversionRate_Reference(t *testing.T) {
+	env := setupTestOracleModule(t, testParams())
+	priceCap, err := env.mod.GrantPriceCapability("feemarket")
+	if err != nil {
+		t.Fatalf("failed to grant capability: %v", err)
+	}
+	if priceCap.ModuleName() != "feemarket" {
+		t.Fatalf("expected module feemarket, got %s", priceCap.ModuleName())
+	}
+
+	conversionRate := func(height uint64) (types.Ratio, types.Ratio) {
+		t.Helper()
+		rate, reference, err := priceCap.ConversionRate(setupTestContext(t, height, "alice"), "usdc")
+		if err != nil {
+			t.Fatalf("ConversionRate failed: %v", err)
+		}
+		return rate, reference
+	}
+
+	if _, _, err := priceCap.ConversionRate(setupTestContext(t, 1, "alice"), "usdc"); !errors.Is(err, ErrPriceNotFound) {
+		t.Fatalf("expected ErrPriceNotFound, got %v", err)
+	}
+
+	// The first price is its own reference
+	env.mustVote(t, 10, "feeder1", "usdc", ratio(2, 1))
+	env.endBlock(t, 10)
+	if rate, reference := conversionRate(11); rate != ratio(2, 1) || reference != ratio(2, 1) {
+		t.Fatalf("unexpected rates %v, %v", rate, reference)
+	}
+
+	// In the block after an update the previous price is the reference
+	env.mustVote(t, 11, "feeder1", "usdc", ratio(4, 1))
+	env.endBlock(t, 11)
+	if rate, reference := conversionRate(12); rate != ratio(4, 1) || reference != ratio(2, 1) {
+		t.Fatalf("unexpected rates at height 12: %v, %v", rate, reference)
+	}
+
+	// Afterwards the latest price is the reference
+	if rate, reference := conversionRate(13); rate != ratio(4, 1) || reference != ratio(4, 1) {
+		t.Fatalf("unexpected rates at height 13: %v, %v", rate, reference)
+	}
+}
+
+func TestPriceVote_Rejects(t *testing.T) {
+	env := setupTestOracleModule(t, testParams())
+
+	if err := env.vote(t, 1, "alice", "usdc", ratio(1, 1)); !errors.Is(err, ErrNotFeeder) {
+		t.Fatalf("expected ErrNotFeeder, got %v", err)
+	}
+	if err := env.vote(t, 1, "feeder1", DefaultBaseDenom, ratio(1, 1)); err == nil {
+		t.Fatal("expected error pricing the base denom")
+	}
+
+	// The feeder must be the transaction account
+	msg := &MsgPriceVote{Feeder: "feeder1", Denom: "usdc", Rate: ratio(1, 1)}
+	if _, err := env.mod.handlePriceVote(setupTestContext(t, 1, "feeder2"), msg); !errors.Is(err, types.ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestQueries(t *testing.T) {
+	env := setupTestOracleModule(t, testParams())
+	ctx := context.Background()
+
+	data, err := env.mod.handleQueryPrice(ctx, "/price", []byte("usdc"))
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if string(data) != "null" {
+		t.Fatalf("expected null, got %s", data)
+	}
+
+	env.mustVote(t, 3, "feeder1", "usdc", ratio(2, 1))
+	data, err = env.mod.handleQueryVotes(ctx, "/votes", []byte("usdc"))
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	var votes []Vote
+	if err := json.Unmarshal(data, &votes); err != nil {
+		t.Fatalf("failed to decode votes: %v", err)
+	}
+	if len(votes) != 1 || votes[0].Feeder != "feeder1" {
+		t.Fatalf("unexpected votes %+v", votes)
+	}
+
+	env.endBlock(t, 3)
+	data, err = env.mod.handleQueryPrice(ctx, "/price", []byte("usdc"))
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	var record PriceRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatalf("failed to decode price: %v", err)
+	}
+	if record.Rate != ratio(2, 1) || record.UpdatedHeight != 3 {
+		t.Fatalf("unexpected record %+v", record)
+	}
+
+	data, err = env.mod.handleQueryVotes(ctx, "/votes", []byte("usdc"))
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if string(data) != "[]" {
+		t.Fatalf("expected [], got %s", data)
+	}
+}
+
+func TestParams_ValidateBasic(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Params)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(p *Params) {}},
+		{name: "no feeders", modify: func(p *Params) { p.Feeders = nil }, wantErr: true},
+		{name: "invalid denom", modify: func(p *Params) { p.BaseDenom = "" }, wantErr: true},
+		{name: "invalid feeder", modify: func(p *Params) { p.Feeders = []types.AccountName{"Feeder"} }, wantErr: true},
+		{name: "duplicate feeder", modify: func(p *Params) { p.Feeders = []types.AccountName{"feeder1", "feeder1"} }, wantErr: true},
+		{name: "zero min votes", modify: func(p *Params) { p.MinVotes = 0 }, wantErr: true},
+		{name: "min votes above feeders", modify: func(p *Params) { p.MinVotes = 5 }, wantErr: true},
+		{name: "zero threshold denominator", modify: func(p *Params) { p.OutlierThreshold = ratio(1, 0) }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := testParams()
+			tt.modify(&params)
+			err := params.ValidateBasic()
+			if tt.wantErr && err == nil {
+				t.Fatal("expected error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewOracleModule_Validation(t *testing.T) {
+	if _, err := NewOracleModule(nil, testParams(), nil); err == nil {
+		t.Fatal("expected error for nil store")
+	}
+	if _, err := NewOracleModule(punnettesting.NewEffectEnv(t).GrantStore(t, ModuleName), DefaultParams(), nil); err == nil {
+		t.Fatal("expected error for params without feeders")
+	}
+	if _, err := CreateModule(nil); err == nil {
+		t.Fatal("expected error for nil module")
+	}
+
+	env := setupTestOracleModule(t, testParams())
+	if _, err := env.mod.GrantPriceCapability(""); err == nil {
+		t.Fatal("expected error for empty module name")
+	}
+	mod, err := CreateModule(env.mod)
+	if err != nil {
+		t.Fatalf("failed to create module: %v", err)
+	}
+	if mod.Name() != ModuleName {
+		t.Fatalf("expected module name %s, got %s", ModuleName, mod.Name())
+	}
+}
+
+func TestMsgPriceVote_ValidateBasic(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     *MsgPriceVote
+		wantErr bool
+	}{
+		{name: "valid", msg: &MsgPriceVote{Feeder: "feeder1", Denom: "usdc", Rate: ratio(3, 2)}},
+		{name: "nil", msg: nil, wantErr: true},
+		{name: "invalid feeder", msg: &MsgPriceVote{Feeder: "", Denom: "usdc", Rate: ratio(3, 2)}, wantErr: true},
+		{name: "invalid denom", msg: &MsgPriceVote{Feeder: "feeder1", Denom: "", Rate: ratio(3, 2)}, wantErr: true},
+		{name: "zero denominator", msg: &MsgPriceVote{Feeder: "feeder1", Denom: "usdc", Rate: ratio(3, 0)}, wantErr: true},
+		{name: "zero rate", msg: &MsgPriceVote{Feeder: "feeder1", Denom: "usdc", Rate: ratio(0, 2)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.msg.ValidateBasic()
+			if tt.wantErr && err == nil {
+				t.Fatal("expected error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package oracle
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+	"sort"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Default parameter values
+const (
+	DefaultBaseDenom = "stake"
+	DefaultMinVotes  = 1
+)
+
+// DefaultOutlierThreshold flags votes more than 10% from the median
+var DefaultOutlierThreshold = types.Ratio{Numerator: 1, Denominator: 10}
+
+// Params configures the oracle
+type Params struct {
+	// BaseDenom is the denomination prices are expressed in
+	BaseDenom string `json:"base_denom"`
+
+	// Feeders are the accounts allowed to vote on prices
+	Feeders []types.AccountName `json:"feeders"`
+
+	// MinVotes is the number of votes a denom needs in a block for its
+	// price to be updated
+	MinVotes uint64 `json:"min_votes"`
+
+	// OutlierThreshold is the deviation from the median, relative to the
+	// median, beyond which a vote is reported to Hooks as an outlier
+	OutlierThreshold types.Ratio `json:"outlier_threshold"`
+}
+
+// DefaultParams returns the default parameters. Feeders must be set.
+func DefaultParams() Params {
+	return Params{
+		BaseDenom:        DefaultBaseDenom,
+		MinVotes:         DefaultMinVotes,
+		OutlierThreshold: DefaultOutlierThreshold,
+	}
+}
+
+// ValidateBasic performs stateless validation
+func (p Params) ValidateBasic() error {
+	if !(types.Coin{Denom: p.BaseDenom, Amount: 1}).IsValid() {
+		return fmt.Errorf("invalid base denom %q", p.BaseDenom)
+	}
+
+	if len(p.Feeders) == 0 {
+		return fmt.Errorf("at least one feeder is required")
+	}
+	seen := make(map[types.AccountName]bool, len(p.Feeders))
+	for _, feeder := range p.Feeders {
+		if !feeder.IsValid() {
+			return fmt.Errorf("%w: invalid feeder %s", types.ErrInvalidAccount, feeder)
+		}
+		if seen[feeder] {
+			return fmt.Errorf("duplicate feeder %s", feeder)
+		}
+		seen[feeder] = true
+	}
+
+	if p.MinVotes == 0 || p.MinVotes > uint64(len(p.Feeders)) {
+		return fmt.Errorf("min votes %d must be between 1 and the number of feeders %d", p.MinVotes, len(p.Feeders))
+	}
+
+	if err := p.OutlierThreshold.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid outlier threshold: %w", err)
+	}
+
+	return nil
+}
+
+// compareRatio returns -1, 0 or 1 as a is less than, equal to or greater than b
+// PRECONDITION: a.Denominator > 0 and b.Denominator > 0
+func compareRatio(a, b types.Ratio) int {
+	// a.N/a.D vs b.N/b.D  <=>  a.N × b.D vs b.N × a.D, in 128 bits
+	aHi, aLo := bits.Mul64(a.Numerator, b.Denominator)
+	bHi, bLo := bits.Mul64(b.Numerator, a.Denominator)
+	switch {
+	case aHi != bHi:
+		if aHi < bHi {
+			return -1
+		}
+		return 1
+	case aLo != bLo:
+		if aLo < bLo {
+			return -1
+		}
+		return 1
+	default:
+		return 0
+	}
+}
+
+// medianVote returns the median of votes by rate. For an even number of
+// votes it is the lower of the two middle votes, so the result is always a
+// submitted rate and needs no averaging.
+//
+// PRECONDITION: len(votes) > 0
+// Complexity: O(n log n)
+func medianVote(votes []Vote) types.Ratio {
+	sorted := make([]Vote, len(votes))
+	copy(sorted, votes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compareRatio(sorted[i].Rate, sorted[j].Rate) < 0
+	})
+	return sorted[(len(sorted)-1)/2].Rate
+}
+
+// isOutlier reports whether vote deviates from median by more than
+// threshold × median:
+//
+//	|vote − median| > median × threshold
+//
+// PRECONDITION: all denominators are non-zero
+func isOutlier(vote, median, threshold types.Ratio) bool {
+	// Multiplying both sides by vote.D × median.D × threshold.D
+	lhs := new(big.Int).Sub(mul(vote.Numerator, median.Denominator), mul(median.Numerator, vote.Denominator))
+	lhs.Abs(lhs)
+	lhs.Mul(lhs, new(big.Int).SetUint64(threshold.Denominator))
+
+	rhs := mul(median.Numerator, vote.Denominator)
+	rhs.Mul(rhs, new(big.Int).SetUint64(threshold.Numerator))
+
+	return lhs.Cmp(rhs) > 0
+}
+
+// mul returns a × b as a big integer
+func mul(a, b uint64) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(a), new(big.Int).SetUint64(b))
+}
@@ -0,0 +1,251 @@
+package recovery
+
+import (
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Message type identifiers
+const (
+	TypeMsgSetGuardians     = "/punnet.recovery.v1.MsgSetGuardians"
+	TypeMsgRemoveGuardians  = "/punnet.recovery.v1.MsgRemoveGuardians"
+	TypeMsgInitiateRecovery = "/punnet.recovery.v1.MsgInitiateRecovery"
+	TypeMsgApproveRecovery  = "/punnet.recovery.v1.MsgApproveRecovery"
+	TypeMsgVetoRecovery     = "/punnet.recovery.v1.MsgVetoRecovery"
+	TypeMsgExecuteRecovery  = "/punnet.recovery.v1.MsgExecuteRecovery"
+)
+
+// MsgSetGuardians designates the guardians that can recover an account,
+// replacing any previous guardians and cancelling any pending recovery
+type MsgSetGuardians struct {
+	// Account is the account being protected
+	Account types.AccountName `json:"account"`
+
+	// Guardians weights the guardian accounts (AccountWeights) and sets the
+	// approval weight required to recover (Threshold)
+	Guardians types.Authority `json:"guardians"`
+
+	// Timelock is the number of blocks between guardian approval and execution,
+	// during which the account can veto the recovery
+	Timelock uint64 `json:"timelock"`
+}
+
+// Type returns the message type
+func (m *MsgSetGuardians) Type() string {
+	return TypeMsgSetGuardians
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgSetGuardians) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Account.IsValid() {
+		return fmt.Errorf("%w: invalid account name %s", types.ErrInvalidAccount, m.Account)
+	}
+
+	config := GuardianConfig{Guardians: m.Guardians, Timelock: m.Timelock}
+	return config.Validate(m.Account)
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgSetGuardians) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Account}
+}
+
+// MsgRemoveGuardians removes an account's guardians and any pending recovery
+type MsgRemoveGuardians struct {
+	// Account is the protected account
+	Account types.AccountName `json:"account"`
+}
+
+// Type returns the message type
+func (m *MsgRemoveGuardians) Type() string {
+	return TypeMsgRemoveGuardians
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgRemoveGuardians) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Account.IsValid() {
+		return fmt.Errorf("%w: invalid account name %s", types.ErrInvalidAccount, m.Account)
+	}
+
+	return nil
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgRemoveGuardians) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Account}
+}
+
+// MsgInitiateRecovery proposes a new authority for an account. The initiating
+// guardian's approval is counted immediately.
+type MsgInitiateRecovery struct {
+	// Guardian is the guardian proposing the recovery
+	Guardian types.AccountName `json:"guardian"`
+
+	// Account is the account to recover
+	Account types.AccountName `json:"account"`
+
+	// NewAuthority replaces the account's authority when the recovery executes
+	NewAuthority types.Authority `json:"new_authority"`
+}
+
+// Type returns the message type
+func (m *MsgInitiateRecovery) Type() string {
+	return TypeMsgInitiateRecovery
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgInitiateRecovery) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Guardian.IsValid() {
+		return fmt.Errorf("%w: invalid guardian account %s", types.ErrInvalidAccount, m.Guardian)
+	}
+
+	if !m.Account.IsValid() {
+		return fmt.Errorf("%w: invalid account name %s", types.ErrInvalidAccount, m.Account)
+	}
+
+	if err := m.NewAuthority.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid new authority: %w", err)
+	}
+
+	return nil
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgInitiateRecovery) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Guardian}
+}
+
+// MsgApproveRecovery adds a guardian's approval to an account's pending recovery
+type MsgApproveRecovery struct {
+	// Guardian is the approving guardian
+	Guardian types.AccountName `json:"guardian"`
+
+	// Account is the account being recovered
+	Account types.AccountName `json:"account"`
+}
+
+// Type returns the message type
+func (m *MsgApproveRecovery) Type() string {
+	return TypeMsgApproveRecovery
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgApproveRecovery) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Guardian.IsValid() {
+		return fmt.Errorf("%w: invalid guardian account %s", types.ErrInvalidAccount, m.Guardian)
+	}
+
+	if !m.Account.IsValid() {
+		return fmt.Errorf("%w: invalid account name %s", types.ErrInvalidAccount, m.Account)
+	}
+
+	return nil
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgApproveRecovery) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Guardian}
+}
+
+// MsgVetoRecovery cancels an account's pending recovery. Only the account's
+// current authority can veto, so a recovery of an account whose keys are
+// still in its owner's hands can always be stopped during the timelock.
+type MsgVetoRecovery struct {
+	// Account is the account whose recovery is vetoed
+	Account types.AccountName `json:"account"`
+}
+
+// Type returns the message type
+func (m *MsgVetoRecovery) Type() string {
+	return TypeMsgVetoRecovery
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgVetoRecovery) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Account.IsValid() {
+		return fmt.Errorf("%w: invalid account name %s", types.ErrInvalidAccount, m.Account)
+	}
+
+	return nil
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgVetoRecovery) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Account}
+}
+
+// MsgExecuteRecovery rotates the account's authority once its recovery is
+// approved and the timelock has passed. Any account may submit it.
+type MsgExecuteRecovery struct {
+	// Executor is the account submitting the execution
+	Executor types.AccountName `json:"executor"`
+
+	// Account is the account being recovered
+	Account types.AccountName `json:"account"`
+}
+
+// Type returns the message type
+func (m *MsgExecuteRecovery) Type() string {
+	return TypeMsgExecuteRecovery
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgExecuteRecovery) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Executor.IsValid() {
+		return fmt.Errorf("%w: invalid executor account %s", types.ErrInvalidAccount, m.Executor)
+	}
+
+	if !m.Account.IsValid() {
+		return fmt.Errorf("%w: invalid account name %s", types.ErrInvalidAccount, m.Account)
+	}
+
+	return nil
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgExecuteRecovery) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Executor}
+}
@@ -0,0 +1,652 @@
+// Package recovery provides social recovery of accounts by guardians.
+//
+// An account designates guardian accounts, each with a weight, and an
+// approval threshold, using the same weighted delegation structure as an
+// account Authority (AccountWeights and Threshold). If the account loses its
+// keys, a guardian proposes a new authority and the other guardians approve
+// it. Once approvals reach the threshold a timelock starts; during it the
+// account's current authority can veto the recovery. After the timelock
+// anyone can execute the recovery, which replaces the account's authority.
+package recovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/effects"
+	sdkerrors "github.com/blockberries/punnet-sdk/errors"
+	"github.com/blockberries/punnet-sdk/module"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Module name
+const ModuleName = "recovery"
+
+// MaxGuardians bounds the number of guardians per account
+const MaxGuardians = 32
+
+var (
+	// ErrNoGuardians is returned when recovering an account without guardians
+	ErrNoGuardians = errors.New("no guardians configured")
+
+	// ErrNotGuardian is returned when a non-guardian initiates or approves a recovery
+	ErrNotGuardian = errors.New("not a guardian")
+
+	// ErrRecoveryPending is returned when initiating a recovery while another is pending
+	ErrRecoveryPending = errors.New("recovery already pending")
+
+	// ErrNoRecoveryPending is returned when no recovery is pending for the account
+	ErrNoRecoveryPending = errors.New("no recovery pending")
+
+	// ErrAlreadyApproved is returned when a guardian approves a recovery twice
+	ErrAlreadyApproved = errors.New("recovery already approved by guardian")
+
+	// ErrRecoveryNotApproved is returned when executing a recovery whose
+	// approvals have not reached the guardian threshold
+	ErrRecoveryNotApproved = errors.New("recovery not approved")
+
+	// ErrTimelockActive is returned when executing a recovery before its timelock expires
+	ErrTimelockActive = errors.New("recovery timelock active")
+)
+
+// Recovery module error codes, in the ModuleName codespace.
+func init() {
+	sdkerrors.MustRegister(ModuleName, 2, ErrNoGuardians)
+	sdkerrors.MustRegister(ModuleName, 3, ErrNotGuardian)
+	sdkerrors.MustRegister(ModuleName, 4, ErrRecoveryPending)
+	sdkerrors.MustRegister(ModuleName, 5, ErrNoRecoveryPending)
+	sdkerrors.MustRegister(ModuleName, 6, ErrAlreadyApproved)
+	sdkerrors.MustRegister(ModuleName, 7, ErrRecoveryNotApproved)
+	sdkerrors.MustRegister(ModuleName, 8, ErrTimelockActive)
+}
+
+// Event types
+const (
+	EventTypeSetGuardians     = "recovery.guardians_set"
+	EventTypeRemoveGuardians  = "recovery.guardians_removed"
+	EventTypeInitiateRecovery = "recovery.initiated"
+	EventTypeApproveRecovery  = "recovery.approved"
+	EventTypeVetoRecovery     = "recovery.vetoed"
+	EventTypeExecuteRecovery  = "recovery.executed"
+)
+
+// guardiansKey is the key of an account's GuardianConfig within the module namespace
+func guardiansKey(account types.AccountName) []byte {
+	return []byte("guardians/" + string(account))
+}
+
+// pendingKey is the key of an account's PendingRecovery within the module namespace
+func pendingKey(account types.AccountName) []byte {
+	return []byte("pending/" + string(account))
+}
+
+// GuardianConfig is an account's recovery configuration
+type GuardianConfig struct {
+	// Guardians holds the guardian accounts and their weights in
+	// AccountWeights, and the approval weight required in Threshold.
+	// INVARIANT: KeyWeights is empty; guardians are accounts.
+	Guardians types.Authority `json:"guardians"`
+
+	// Timelock is the number of blocks between approval and execution
+	Timelock uint64 `json:"timelock"`
+}
+
+// Validate performs stateless validation of the configuration of account
+func (c GuardianConfig) Validate(account types.AccountName) error {
+	if err := c.Guardians.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid guardians: %w", err)
+	}
+
+	if len(c.Guardians.KeyWeights) > 0 {
+		return fmt.Errorf("%w: guardians must be accounts, not keys", types.ErrInvalidAuthority)
+	}
+
+	if len(c.Guardians.AccountWeights) == 0 {
+		return fmt.Errorf("%w: at least one guardian is required", types.ErrInvalidAuthority)
+	}
+
+	if len(c.Guardians.AccountWeights) > MaxGuardians {
+		return fmt.Errorf("%w: %d guardians exceeds maximum %d", types.ErrInvalidAuthority, len(c.Guardians.AccountWeights), MaxGuardians)
+	}
+
+	// SECURITY: An account guarding itself would let its own (possibly
+	// compromised) authority approve a recovery without other guardians
+	if c.Guardians.HasAccount(account) {
+		return fmt.Errorf("%w: account cannot be its own guardian", types.ErrInvalidAuthority)
+	}
+
+	if c.Timelock == 0 {
+		return fmt.Errorf("%w: timelock must be positive", types.ErrInvalidAuthority)
+	}
+
+	return nil
+}
+
+// PendingRecovery is a proposed recovery awaiting approval or execution
+type PendingRecovery struct {
+	// NewAuthority replaces the account's authority on execution
+	NewAuthority types.Authority `json:"new_authority"`
+
+	// Initiator is the guardian that proposed the recovery
+	Initiator types.AccountName `json:"initiator"`
+
+	// InitiatedHeight is the block height of the proposal
+	InitiatedHeight uint64 `json:"initiated_height"`
+
+	// Approvals lists the approving guardians, sorted, including the initiator
+	Approvals []types.AccountName `json:"approvals"`
+
+	// ApprovedHeight is the height at which approvals reached the threshold,
+	// or 0 while they have not. The timelock runs from this height.
+	ApprovedHeight uint64 `json:"approved_height"`
+}
+
+// HasApproved reports whether guardian has approved the recovery
+func (p *PendingRecovery) HasApproved(guardian types.AccountName) bool {
+	i := sort.Search(len(p.Approvals), func(i int) bool { return p.Approvals[i] >= guardian })
+	return i < len(p.Approvals) && p.Approvals[i] == guardian
+}
+
+// ApprovalWeight returns the total guardian weight of the approvals
+func (p *PendingRecovery) ApprovalWeight(guardians types.Authority) uint64 {
+	var weight uint64
+	for _, g := range p.Approvals {
+		// Cannot overflow: Authority.ValidateBasic bounds the total weight
+		weight += guardians.GetAccountWeight(g)
+	}
+	return weight
+}
+
+// addApproval records guardian's approval, keeping Approvals sorted
+func (p *PendingRecovery) addApproval(guardian types.AccountName) {
+	i := sort.Search(len(p.Approvals), func(i int) bool { return p.Approvals[i] >= guardian })
+	p.Approvals = append(p.Approvals, "")
+	copy(p.Approvals[i+1:], p.Approvals[i:])
+	p.Approvals[i] = guardian
+}
+
+// AccountReader reads the accounts being recovered and their guardians.
+// capability.ReadOnlyAccountCapability implements it, as does an adapter over
+// the runtime's account store.
+type AccountReader interface {
+	// GetAccount retrieves an account by name
+	GetAccount(ctx context.Context, name types.AccountName) (*types.Account, error)
+
+	// HasAccount checks if an account exists
+	HasAccount(ctx context.Context, name types.AccountName) (bool, error)
+}
+
+// RecoveryModule manages guardians and recoveries.
+type RecoveryModule struct {
+	// moduleStore is the "module/recovery/" view of the state store
+	moduleStore store.BackingStore
+
+	// accounts reads the accounts being recovered and their guardians
+	accounts AccountReader
+}
+
+// NewRecoveryModule creates a recovery module over its store capability.
+//
+// PRECONDITION: accounts must read the accounts the runtime stores (e.g. the
+// auth module's capability), not ones granted to ModuleName: the recovery
+// namespace holds only guardian and pending recovery state. Executed
+// recoveries write the rotated account with an effects.AccountWriteEffect.
+func NewRecoveryModule(storeCap capability.StoreCapability, accounts AccountReader) (*RecoveryModule, error) {
+	if err := capability.CheckStoreCapability(storeCap, ModuleName); err != nil {
+		return nil, err
+	}
+
+	if accounts == nil {
+		return nil, fmt.Errorf("account reader cannot be nil")
+	}
+
+	return &RecoveryModule{
+		moduleStore: storeCap,
+		accounts:    accounts,
+	}, nil
+}
+
+// CreateModule creates the recovery module using the module builder
+//
+// Usage:
+//
+//	storeCap, _ := capManager.GrantStoreCapability(recovery.ModuleName)
+//	recoveryMod, _ := recovery.NewRecoveryModule(storeCap, accountCap)
+//	mod, _ := recovery.CreateModule(recoveryMod)
+func CreateModule(recoveryMod *RecoveryModule) (module.Module, error) {
+	if recoveryMod == nil {
+		return nil, fmt.Errorf("recovery module cannot be nil")
+	}
+
+	return module.NewModuleBuilder(ModuleName).
+		WithMsgHandler(TypeMsgSetGuardians, recoveryMod.handleSetGuardians).
+		WithMsgHandler(TypeMsgRemoveGuardians, recoveryMod.handleRemoveGuardians).
+		WithMsgHandler(TypeMsgInitiateRecovery, recoveryMod.handleInitiateRecovery).
+		WithMsgHandler(TypeMsgApproveRecovery, recoveryMod.handleApproveRecovery).
+		WithMsgHandler(TypeMsgVetoRecovery, recoveryMod.handleVetoRecovery).
+		WithMsgHandler(TypeMsgExecuteRecovery, recoveryMod.handleExecuteRecovery).
+		WithQueryHandler("/guardians", recoveryMod.handleQueryGuardians).
+		WithQueryHandler("/pending", recoveryMod.handleQueryPending).
+		Build()
+}
+
+// Guardians returns the guardian configuration of account, if any
+func (m *RecoveryModule) Guardians(account types.AccountName) (GuardianConfig, bool, error) {
+	if m == nil {
+		return GuardianConfig{}, false, fmt.Errorf("recovery module is nil")
+	}
+
+	var config GuardianConfig
+	ok, err := m.load(guardiansKey(account), &config)
+	if err != nil {
+		return GuardianConfig{}, false, fmt.Errorf("failed to read guardians: %w", err)
+	}
+	return config, ok, nil
+}
+
+// PendingRecovery returns the pending recovery of account, if any
+func (m *RecoveryModule) PendingRecovery(account types.AccountName) (*PendingRecovery, bool, error) {
+	if m == nil {
+		return nil, false, fmt.Errorf("recovery module is nil")
+	}
+
+	var pending PendingRecovery
+	ok, err := m.load(pendingKey(account), &pending)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read pending recovery: %w", err)
+	}
+	if !ok {
+		return nil, false, nil
+	}
+	return &pending, true, nil
+}
+
+// load decodes the JSON value at key into v, reporting whether it exists
+func (m *RecoveryModule) load(key []byte, v any) (bool, error) {
+	data, err := m.moduleStore.Get(key)
+	if errors.Is(err, store.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if data == nil {
+		return false, nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("failed to decode %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// writeEffect returns the effect storing v as JSON at key
+func writeEffect(key []byte, v any) (effects.Effect, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+	return effects.NewStateWriteEffect(ModuleName, key, data), nil
+}
+
+// recoveryEvent returns the event effect for an action on account
+func recoveryEvent(ctx *runtime.Context, eventType string, account types.AccountName, attrs ...string) effects.Effect {
+	attributes := map[string][]byte{
+		"account": []byte(account),
+		"height":  []byte(strconv.FormatUint(ctx.BlockHeight(), 10)),
+	}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		attributes[attrs[i]] = []byte(attrs[i+1])
+	}
+	return effects.NewEventEffect(eventType, attributes)
+}
+
+// checkSigner verifies that signer is the transaction account
+func checkSigner(ctx *runtime.Context, signer types.AccountName) error {
+	if signer != ctx.Account() {
+		return fmt.Errorf("%w: %s must be transaction account", types.ErrUnauthorized, signer)
+	}
+	return nil
+}
+
+// guardiansOf returns the guardian configuration of account, failing with
+// ErrNoGuardians if it has none
+func (m *RecoveryModule) guardiansOf(account types.AccountName) (GuardianConfig, error) {
+	config, ok, err := m.Guardians(account)
+	if err != nil {
+		return GuardianConfig{}, err
+	}
+	if !ok {
+		return GuardianConfig{}, fmt.Errorf("%w: %s", ErrNoGuardians, account)
+	}
+	return config, nil
+}
+
+// pendingOf returns the pending recovery of account, failing with
+// ErrNoRecoveryPending if there is none
+func (m *RecoveryModule) pendingOf(account types.AccountName) (*PendingRecovery, error) {
+	pending, ok, err := m.PendingRecovery(account)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoRecoveryPending, account)
+	}
+	return pending, nil
+}
+
+// handleSetGuardians handles MsgSetGuardians
+func (m *RecoveryModule) handleSetGuardians(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("recovery module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	setMsg, ok := msg.(*MsgSetGuardians)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgSetGuardians")
+	}
+
+	if err := checkSigner(ctx, setMsg.Account); err != nil {
+		return nil, err
+	}
+
+	for _, guardian := range setMsg.Guardians.SortedAccountWeights() {
+		exists, err := m.accounts.HasAccount(ctx.Context(), guardian.Account)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check guardian existence: %w", err)
+		}
+		if !exists {
+			return nil, fmt.Errorf("%w: guardian %s", types.ErrNotFound, guardian.Account)
+		}
+	}
+
+	config := GuardianConfig{Guardians: setMsg.Guardians, Timelock: setMsg.Timelock}
+	write, err := writeEffect(guardiansKey(setMsg.Account), config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Approvals were given under the old guardian set, so changing the set
+	// cancels any pending recovery
+	return []effects.Effect{
+		write,
+		effects.NewStateDeleteEffect(ModuleName, pendingKey(setMsg.Account)),
+		recoveryEvent(ctx, EventTypeSetGuardians, setMsg.Account,
+			"threshold", strconv.FormatUint(config.Guardians.Threshold, 10),
+			"timelock", strconv.FormatUint(config.Timelock, 10)),
+	}, nil
+}
+
+// handleRemoveGuardians handles MsgRemoveGuardians
+func (m *RecoveryModule) handleRemoveGuardians(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("recovery module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	removeMsg, ok := msg.(*MsgRemoveGuardians)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgRemoveGuardians")
+	}
+
+	if err := checkSigner(ctx, removeMsg.Account); err != nil {
+		return nil, err
+	}
+
+	if _, err := m.guardiansOf(removeMsg.Account); err != nil {
+		return nil, err
+	}
+
+	return []effects.Effect{
+		effects.NewStateDeleteEffect(ModuleName, guardiansKey(removeMsg.Account)),
+		effects.NewStateDeleteEffect(ModuleName, pendingKey(removeMsg.Account)),
+		recoveryEvent(ctx, EventTypeRemoveGuardians, removeMsg.Account),
+	}, nil
+}
+
+// handleInitiateRecovery handles MsgInitiateRecovery
+func (m *RecoveryModule) handleInitiateRecovery(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("recovery module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	initMsg, ok := msg.(*MsgInitiateRecovery)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgInitiateRecovery")
+	}
+
+	if err := checkSigner(ctx, initMsg.Guardian); err != nil {
+		return nil, err
+	}
+
+	exists, err := m.accounts.HasAccount(ctx.Context(), initMsg.Account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check account existence: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: account %s", types.ErrNotFound, initMsg.Account)
+	}
+
+	config, err := m.guardiansOf(initMsg.Account)
+	if err != nil {
+		return nil, err
+	}
+	if !config.Guardians.HasAccount(initMsg.Guardian) {
+		return nil, fmt.Errorf("%w: %s for account %s", ErrNotGuardian, initMsg.Guardian, initMsg.Account)
+	}
+
+	// SECURITY: One recovery at a time; a competing proposal would let a
+	// single guardian reset the timelock of an approved recovery
+	_, pending, err := m.PendingRecovery(initMsg.Account)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, fmt.Errorf("%w: %s", ErrRecoveryPending, initMsg.Account)
+	}
+
+	recovery := &PendingRecovery{
+		NewAuthority:    initMsg.NewAuthority,
+		Initiator:       initMsg.Guardian,
+		InitiatedHeight: ctx.BlockHeight(),
+	}
+	recovery.addApproval(initMsg.Guardian)
+	if recovery.ApprovalWeight(config.Guardians) >= config.Guardians.Threshold {
+		recovery.ApprovedHeight = ctx.BlockHeight()
+	}
+
+	write, err := writeEffect(pendingKey(initMsg.Account), recovery)
+	if err != nil {
+		return nil, err
+	}
+
+	return []effects.Effect{
+		write,
+		recoveryEvent(ctx, EventTypeInitiateRecovery, initMsg.Account, "guardian", string(initMsg.Guardian)),
+	}, nil
+}
+
+// handleApproveRecovery handles MsgApproveRecovery
+func (m *RecoveryModule) handleApproveRecovery(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("recovery module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	approveMsg, ok := msg.(*MsgApproveRecovery)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgApproveRecovery")
+	}
+
+	if err := checkSigner(ctx, approveMsg.Guardian); err != nil {
+		return nil, err
+	}
+
+	config, err := m.guardiansOf(approveMsg.Account)
+	if err != nil {
+		return nil, err
+	}
+	if !config.Guardians.HasAccount(approveMsg.Guardian) {
+		return nil, fmt.Errorf("%w: %s for account %s", ErrNotGuardian, approveMsg.Guardian, approveMsg.Account)
+	}
+
+	recovery, err := m.pendingOf(approveMsg.Account)
+	if err != nil {
+		return nil, err
+	}
+	if recovery.HasApproved(approveMsg.Guardian) {
+		return nil, fmt.Errorf("%w: %s", ErrAlreadyApproved, approveMsg.Guardian)
+	}
+
+	recovery.addApproval(approveMsg.Guardian)
+	// INVARIANT: ApprovedHeight is set once; later approvals do not restart the timelock
+	if recovery.ApprovedHeight == 0 && recovery.ApprovalWeight(config.Guardians) >= config.Guardians.Threshold {
+		recovery.ApprovedHeight = ctx.BlockHeight()
+	}
+
+	write, err := writeEffect(pendingKey(approveMsg.Account), recovery)
+	if err != nil {
+		return nil, err
+	}
+
+	return []effects.Effect{
+		write,
+		recoveryEvent(ctx, EventTypeApproveRecovery, approveMsg.Account,
+			"guardian", string(approveMsg.Guardian),
+			"weight", strconv.FormatUint(recovery.ApprovalWeight(config.Guardians), 10)),
+	}, nil
+}
+
+// handleVetoRecovery handles MsgVetoRecovery.
+//
+// SECURITY: The veto is signed by the account's current authority, so
+// guardians cannot take over an account whose owner still holds its keys
+// and watches for recoveries during the timelock.
+func (m *RecoveryModule) handleVetoRecovery(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("recovery module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	vetoMsg, ok := msg.(*MsgVetoRecovery)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgVetoRecovery")
+	}
+
+	if err := checkSigner(ctx, vetoMsg.Account); err != nil {
+		return nil, err
+	}
+
+	if _, err := m.pendingOf(vetoMsg.Account); err != nil {
+		return nil, err
+	}
+
+	return []effects.Effect{
+		effects.NewStateDeleteEffect(ModuleName, pendingKey(vetoMsg.Account)),
+		recoveryEvent(ctx, EventTypeVetoRecovery, vetoMsg.Account),
+	}, nil
+}
+
+// handleExecuteRecovery handles MsgExecuteRecovery
+func (m *RecoveryModule) handleExecuteRecovery(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("recovery module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	execMsg, ok := msg.(*MsgExecuteRecovery)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgExecuteRecovery")
+	}
+
+	if err := checkSigner(ctx, execMsg.Executor); err != nil {
+		return nil, err
+	}
+
+	config, err := m.guardiansOf(execMsg.Account)
+	if err != nil {
+		return nil, err
+	}
+
+	recovery, err := m.pendingOf(execMsg.Account)
+	if err != nil {
+		return nil, err
+	}
+	if recovery.ApprovedHeight == 0 {
+		return nil, fmt.Errorf("%w: approval weight %d of %d", ErrRecoveryNotApproved,
+			recovery.ApprovalWeight(config.Guardians), config.Guardians.Threshold)
+	}
+
+	// ApprovedHeight <= BlockHeight, so the subtraction cannot underflow
+	height := ctx.BlockHeight()
+	if height-recovery.ApprovedHeight < config.Timelock {
+		return nil, fmt.Errorf("%w: executable at height %d, current height %d", ErrTimelockActive,
+			recovery.ApprovedHeight+config.Timelock, height)
+	}
+
+	account, err := m.accounts.GetAccount(ctx.Context(), execMsg.Account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	// The stored account may be shared with a cache; rotate a copy
+	rotated := *account
+	rotated.Authority = recovery.NewAuthority
+	rotated.UpdatedAt = ctx.BlockTime()
+
+	if err := rotated.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid account: %w", err)
+	}
+
+	return []effects.Effect{
+		effects.NewAccountWriteEffect(&rotated),
+		effects.NewStateDeleteEffect(ModuleName, pendingKey(execMsg.Account)),
+		recoveryEvent(ctx, EventTypeExecuteRecovery, execMsg.Account, "executor", string(execMsg.Executor)),
+	}, nil
+}
+
+// handleQueryGuardians returns the guardian configuration of the account
+// named by data as JSON, or null if none
+func (m *RecoveryModule) handleQueryGuardians(ctx context.Context, path string, data []byte) ([]byte, error) {
+	config, ok, err := m.Guardians(types.AccountName(data))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return json.Marshal(nil)
+	}
+	return json.Marshal(config)
+}
+
+// handleQueryPending returns the pending recovery of the account named by
+// data as JSON, or null if none
+func (m *RecoveryModule) handleQueryPending(ctx context.Context, path string, data []byte) ([]byte, error) {
+	recovery, ok, err := m.PendingRecovery(types.AccountName(data))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return json.Marshal(nil)
+	}
+	return json.Marshal(recovery)
+}
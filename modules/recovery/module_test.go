@@ -0,0 +1,533 @@
+package recovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	dbm "github.com/cosmos/cosmos-db"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+	punnettesting "github.com/blockberries/punnet-sdk/testing"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+type testEnv struct {
+	*punnettesting.EffectEnv
+	mod        *RecoveryModule
+	accountCap capability.AccountCapability
+}
+
+func setupTestRecoveryModule(t *testing.T) *testEnv {
+	t.Helper()
+
+	env := punnettesting.NewEffectEnv(t)
+	capMgr := env.CapabilityManager()
+	if err := capMgr.RegisterModule("auth"); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	accountCap, err := capMgr.GrantAccountCapability("auth")
+	if err != nil {
+		t.Fatalf("failed to grant account capability: %v", err)
+	}
+	env.UseAccounts(accountCap)
+
+	for _, name := range []types.AccountName{"alice", "guardian1", "guardian2", "guardian3", "eve"} {
+		if _, err := accountCap.CreateAccount(context.Background(), name, []byte("pubkey-"+name)); err != nil {
+			t.Fatalf("failed to create account %s: %v", name, err)
+		}
+	}
+
+	recoveryMod, err := NewRecoveryModule(env.GrantStore(t, ModuleName), accountCap)
+	if err != nil {
+		t.Fatalf("failed to create recovery module: %v", err)
+	}
+	return &testEnv{EffectEnv: env, mod: recoveryMod, accountCap: accountCap}
+}
+
+func setupTestContext(t *testing.T, height uint64, account types.AccountName) *runtime.Context {
+	t.Helper()
+
+	header := runtime.NewBlockHeader(height, time.Now(), "test-chain", []byte("proposer"))
+	ctx, err := runtime.NewContext(context.Background(), header, account)
+	if err != nil {
+		t.Fatalf("failed to create context: %v", err)
+	}
+	return ctx
+}
+
+// run invokes handler at height with account as the transaction account
+// and applies its effects on success
+func (env *testEnv) run(t *testing.T, height uint64, account types.AccountName, msg types.Message) error {
+	t.Helper()
+
+	handlers := map[string]func(*runtime.Context, types.Message) ([]effects.Effect, error){
+		TypeMsgSetGuardians:     env.mod.handleSetGuardians,
+		TypeMsgRemoveGuardians:  env.mod.handleRemoveGuardians,
+		TypeMsgInitiateRecovery: env.mod.handleInitiateRecovery,
+		TypeMsgApproveRecovery:  env.mod.handleApproveRecovery,
+		TypeMsgVetoRecovery:     env.mod.handleVetoRecovery,
+		TypeMsgExecuteRecovery:  env.mod.handleExecuteRecovery,
+	}
+	ctx := setupTestContext(t, height, account)
+	effs, err := handlers[msg.Type()](ctx, msg)
+	if err != nil {
+		return err
+	}
+	env.Apply(t, ctx, effs)
+	return nil
+}
+
+func testGuardians(threshold uint64, weights map[types.AccountName]uint64) types.Authority {
+	return types.Authority{
+		Threshold:      threshold,
+		KeyWeights:     map[string]uint64{},
+		AccountWeights: weights,
+	}
+}
+
+func testNewAuthority() types.Authority {
+	return types.NewAuthority(1, []byte("recovered-pubkey"), 1)
+}
+
+// setTwoOfThree configures guardian1..3 with weight 1, threshold 2 and timelock 10
+func (env *testEnv) setTwoOfThree(t *testing.T) {
+	t.Helper()
+
+	msg := &MsgSetGuardians{
+		Account:   "alice",
+		Guardians: testGuardians(2, map[types.AccountName]uint64{"guardian1": 1, "guardian2": 1, "guardian3": 1}),
+		Timelock:  10,
+	}
+	if err := env.run(t, 1, "alice", msg); err != nil {
+		t.Fatalf("set guardians failed: %v", err)
+	}
+}
+
+func TestNewRecoveryModule(t *testing.T) {
+	env := setupTestRecoveryModule(t)
+
+	if _, err := NewRecoveryModule(nil, env.accountCap); err == nil {
+		t.Fatal("expected error for nil store capability")
+	}
+	if _, err := NewRecoveryModule(env.GrantStore(t, ModuleName), nil); err == nil {
+		t.Fatal("expected error for nil account reader")
+	}
+	if _, err := CreateModule(nil); err == nil {
+		t.Fatal("expected error for nil recovery module")
+	}
+
+	mod, err := CreateModule(env.mod)
+	if err != nil {
+		t.Fatalf("CreateModule failed: %v", err)
+	}
+	if mod.Name() != ModuleName {
+		t.Fatalf("expected module name %s, got %s", ModuleName, mod.Name())
+	}
+}
+
+func TestMsgSetGuardians_ValidateBasic(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     *MsgSetGuardians
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			msg: &MsgSetGuardians{
+				Account:   "alice",
+				Guardians: testGuardians(2, map[types.AccountName]uint64{"bob": 1, "carol": 1}),
+				Timelock:  10,
+			},
+		},
+		{
+			name:    "nil message",
+			msg:     nil,
+			wantErr: true,
+		},
+		{
+			name: "invalid account",
+			msg: &MsgSetGuardians{
+				Account:   "",
+				Guardians: testGuardians(1, map[types.AccountName]uint64{"bob": 1}),
+				Timelock:  10,
+			},
+			wantErr: true,
+		},
+		{
+			name: "no guardians",
+			msg: &MsgSetGuardians{
+				Account:   "alice",
+				Guardians: types.NewAuthority(1, []byte("key"), 1),
+				Timelock:  10,
+			},
+			wantErr: true,
+		},
+		{
+			name: "key guardian",
+			msg: &MsgSetGuardians{
+				Account: "alice",
+				Guardians: types.Authority{
+					Threshold:      1,
+					KeyWeights:     map[string]uint64{"key": 1},
+					AccountWeights: map[types.AccountName]uint64{"bob": 1},
+				},
+				Timelock: 10,
+			},
+			wantErr: true,
+		},
+		{
+			name: "self guardian",
+			msg: &MsgSetGuardians{
+				Account:   "alice",
+				Guardians: testGuardians(1, map[types.AccountName]uint64{"alice": 1, "bob": 1}),
+				Timelock:  10,
+			},
+			wantErr: true,
+		},
+		{
+			name: "unreachable threshold",
+			msg: &MsgSetGuardians{
+				Account:   "alice",
+				Guardians: testGuardians(3, map[types.AccountName]uint64{"bob": 1, "carol": 1}),
+				Timelock:  10,
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero timelock",
+			msg: &MsgSetGuardians{
+				Account:   "alice",
+				Guardians: testGuardians(1, map[types.AccountName]uint64{"bob": 1}),
+				Timelock:  0,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.msg.ValidateBasic()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateBasic() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecovery_FullFlow(t *testing.T) {
+	env := setupTestRecoveryModule(t)
+	env.setTwoOfThree(t)
+
+	initiate := &MsgInitiateRecovery{Guardian: "guardian1", Account: "alice", NewAuthority: testNewAuthority()}
+	if err := env.run(t, 5, "guardian1", initiate); err != nil {
+		t.Fatalf("initiate failed: %v", err)
+	}
+
+	execute := &MsgExecuteRecovery{Executor: "eve", Account: "alice"}
+	if err := env.run(t, 100, "eve", execute); !errors.Is(err, ErrRecoveryNotApproved) {
+		t.Fatalf("expected ErrRecoveryNotApproved, got %v", err)
+	}
+
+	if err := env.run(t, 6, "guardian1", &MsgApproveRecovery{Guardian: "guardian1", Account: "alice"}); !errors.Is(err, ErrAlreadyApproved) {
+		t.Fatalf("expected ErrAlreadyApproved, got %v", err)
+	}
+	if err := env.run(t, 6, "eve", &MsgApproveRecovery{Guardian: "eve", Account: "alice"}); !errors.Is(err, ErrNotGuardian) {
+		t.Fatalf("expected ErrNotGuardian, got %v", err)
+	}
+	if err := env.run(t, 6, "eve", &MsgApproveRecovery{Guardian: "guardian2", Account: "alice"}); !errors.Is(err, types.ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized for guardian impersonation, got %v", err)
+	}
+
+	if err := env.run(t, 6, "guardian2", &MsgApproveRecovery{Guardian: "guardian2", Account: "alice"}); err != nil {
+		t.Fatalf("approve failed: %v", err)
+	}
+	pending, ok, err := env.mod.PendingRecovery("alice")
+	if err != nil || !ok {
+		t.Fatalf("expected pending recovery, got ok=%v err=%v", ok, err)
+	}
+	if pending.ApprovedHeight != 6 {
+		t.Fatalf("expected approved height 6, got %d", pending.ApprovedHeight)
+	}
+
+	// A further approval does not restart the timelock
+	if err := env.run(t, 8, "guardian3", &MsgApproveRecovery{Guardian: "guardian3", Account: "alice"}); err != nil {
+		t.Fatalf("approve failed: %v", err)
+	}
+
+	if err := env.run(t, 15, "eve", execute); !errors.Is(err, ErrTimelockActive) {
+		t.Fatalf("expected ErrTimelockActive, got %v", err)
+	}
+	if err := env.run(t, 16, "eve", execute); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	account, err := env.accountCap.GetAccount(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("failed to get account: %v", err)
+	}
+	if !account.Authority.HasKey([]byte("recovered-pubkey")) || account.Authority.HasKey([]byte("pubkey-alice")) {
+		t.Fatalf("authority not rotated: %+v", account.Authority)
+	}
+	if _, ok, _ := env.mod.PendingRecovery("alice"); ok {
+		t.Fatal("pending recovery should be cleared after execution")
+	}
+
+	// Guardians remain configured for future recoveries
+	if _, ok, _ := env.mod.Guardians("alice"); !ok {
+		t.Fatal("guardians should remain after execution")
+	}
+}
+
+func TestRecovery_InitiatorMeetsThreshold(t *testing.T) {
+	env := setupTestRecoveryModule(t)
+
+	setMsg := &MsgSetGuardians{
+		Account:   "alice",
+		Guardians: testGuardians(2, map[types.AccountName]uint64{"guardian1": 2, "guardian2": 1}),
+		Timelock:  5,
+	}
+	if err := env.run(t, 1, "alice", setMsg); err != nil {
+		t.Fatalf("set guardians failed: %v", err)
+	}
+
+	initiate := &MsgInitiateRecovery{Guardian: "guardian1", Account: "alice", NewAuthority: testNewAuthority()}
+	if err := env.run(t, 3, "guardian1", initiate); err != nil {
+		t.Fatalf("initiate failed: %v", err)
+	}
+	if err := env.run(t, 8, "guardian2", &MsgExecuteRecovery{Executor: "guardian2", Account: "alice"}); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+}
+
+func TestRecovery_Veto(t *testing.T) {
+	env := setupTestRecoveryModule(t)
+	env.setTwoOfThree(t)
+
+	initiate := &MsgInitiateRecovery{Guardian: "guardian1", Account: "alice", NewAuthority: testNewAuthority()}
+	if err := env.run(t, 5, "guardian1", initiate); err != nil {
+		t.Fatalf("initiate failed: %v", err)
+	}
+	if err := env.run(t, 6, "guardian2", &MsgApproveRecovery{Guardian: "guardian2", Account: "alice"}); err != nil {
+		t.Fatalf("approve failed: %v", err)
+	}
+
+	veto := &MsgVetoRecovery{Account: "alice"}
+	if err := env.run(t, 7, "guardian1", veto); !errors.Is(err, types.ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized for guardian veto, got %v", err)
+	}
+	if err := env.run(t, 7, "alice", veto); err != nil {
+		t.Fatalf("veto failed: %v", err)
+	}
+
+	if err := env.run(t, 100, "eve", &MsgExecuteRecovery{Executor: "eve", Account: "alice"}); !errors.Is(err, ErrNoRecoveryPending) {
+		t.Fatalf("expected ErrNoRecoveryPending, got %v", err)
+	}
+	if err := env.run(t, 8, "alice", veto); !errors.Is(err, ErrNoRecoveryPending) {
+		t.Fatalf("expected ErrNoRecoveryPending, got %v", err)
+	}
+
+	// Guardians can start over after a veto
+	if err := env.run(t, 9, "guardian3", &MsgInitiateRecovery{Guardian: "guardian3", Account: "alice", NewAuthority: testNewAuthority()}); err != nil {
+		t.Fatalf("re-initiate failed: %v", err)
+	}
+}
+
+func TestRecovery_StatefulChecks(t *testing.T) {
+	env := setupTestRecoveryModule(t)
+
+	initiate := &MsgInitiateRecovery{Guardian: "guardian1", Account: "alice", NewAuthority: testNewAuthority()}
+	if err := env.run(t, 2, "guardian1", initiate); !errors.Is(err, ErrNoGuardians) {
+		t.Fatalf("expected ErrNoGuardians, got %v", err)
+	}
+
+	unknown := &MsgSetGuardians{
+		Account:   "alice",
+		Guardians: testGuardians(1, map[types.AccountName]uint64{"nobody": 1}),
+		Timelock:  10,
+	}
+	if err := env.run(t, 1, "alice", unknown); !errors.Is(err, types.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for unknown guardian, got %v", err)
+	}
+
+	env.setTwoOfThree(t)
+
+	if err := env.run(t, 2, "guardian1", initiate); err != nil {
+		t.Fatalf("initiate failed: %v", err)
+	}
+	second := &MsgInitiateRecovery{Guardian: "guardian2", Account: "alice", NewAuthority: testNewAuthority()}
+	if err := env.run(t, 3, "guardian2", second); !errors.Is(err, ErrRecoveryPending) {
+		t.Fatalf("expected ErrRecoveryPending, got %v", err)
+	}
+
+	// Changing the guardian set cancels the pending recovery
+	env.setTwoOfThree(t)
+	if _, ok, _ := env.mod.PendingRecovery("alice"); ok {
+		t.Fatal("setting guardians should cancel the pending recovery")
+	}
+
+	if err := env.run(t, 4, "alice", &MsgRemoveGuardians{Account: "alice"}); err != nil {
+		t.Fatalf("remove guardians failed: %v", err)
+	}
+	if err := env.run(t, 5, "alice", &MsgRemoveGuardians{Account: "alice"}); !errors.Is(err, ErrNoGuardians) {
+		t.Fatalf("expected ErrNoGuardians, got %v", err)
+	}
+
+	missing := &MsgInitiateRecovery{Guardian: "guardian1", Account: "ghost", NewAuthority: testNewAuthority()}
+	if err := env.run(t, 6, "guardian1", missing); !errors.Is(err, types.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for unknown account, got %v", err)
+	}
+}
+
+func TestQueries(t *testing.T) {
+	env := setupTestRecoveryModule(t)
+	ctx := context.Background()
+
+	data, err := env.mod.handleQueryGuardians(ctx, "/guardians", []byte("alice"))
+	if err != nil {
+		t.Fatalf("guardians query failed: %v", err)
+	}
+	if string(data) != "null" {
+		t.Fatalf("expected null guardians, got %s", data)
+	}
+
+	env.setTwoOfThree(t)
+	data, err = env.mod.handleQueryGuardians(ctx, "/guardians", []byte("alice"))
+	if err != nil {
+		t.Fatalf("guardians query failed: %v", err)
+	}
+	var config GuardianConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("failed to decode guardians: %v", err)
+	}
+	if config.Timelock != 10 || config.Guardians.Threshold != 2 || len(config.Guardians.AccountWeights) != 3 {
+		t.Fatalf("unexpected guardians %+v", config)
+	}
+
+	initiate := &MsgInitiateRecovery{Guardian: "guardian2", Account: "alice", NewAuthority: testNewAuthority()}
+	if err := env.run(t, 3, "guardian2", initiate); err != nil {
+		t.Fatalf("initiate failed: %v", err)
+	}
+	data, err = env.mod.handleQueryPending(ctx, "/pending", []byte("alice"))
+	if err != nil {
+		t.Fatalf("pending query failed: %v", err)
+	}
+	var pending PendingRecovery
+	if err := json.Unmarshal(data, &pending); err != nil {
+		t.Fatalf("failed to decode pending recovery: %v", err)
+	}
+	if pending.Initiator != "guardian2" || pending.InitiatedHeight != 3 || len(pending.Approvals) != 1 {
+		t.Fatalf("unexpected pending recovery %+v", pending)
+	}
+}
+
+// appAccounts reads the accounts of a runtime.Application, set once the
+// application is created
+type appAccounts struct {
+	app *runtime.Application
+}
+
+func (a *appAccounts) GetAccount(ctx context.Context, name types.AccountName) (*types.Account, error) {
+	return a.app.AccountStore().Get(ctx, []byte(name))
+}
+
+func (a *appAccounts) HasAccount(ctx context.Context, name types.AccountName) (bool, error) {
+	return a.app.AccountStore().Has(ctx, []byte(name))
+}
+
+// TestRecovery_Application executes a recovery through a runtime.Application
+// and checks the rotated authority reaches the account the runtime
+// authorizes transactions against, in the cache and in committed state.
+func TestRecovery_Application(t *testing.T) {
+	ctx := context.Background()
+
+	iavlStore, err := store.NewIAVLStore(dbm.NewMemDB(), 0)
+	if err != nil {
+		t.Fatalf("failed to create IAVL store: %v", err)
+	}
+	capManager := capability.NewCapabilityManager(iavlStore)
+	if err := capManager.RegisterModule(ModuleName); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	storeCap, err := capManager.GrantStoreCapability(ModuleName)
+	if err != nil {
+		t.Fatalf("failed to grant store capability: %v", err)
+	}
+	accounts := &appAccounts{}
+	recoveryMod, err := NewRecoveryModule(storeCap, accounts)
+	if err != nil {
+		t.Fatalf("failed to create recovery module: %v", err)
+	}
+	mod, err := CreateModule(recoveryMod)
+	if err != nil {
+		t.Fatalf("CreateModule failed: %v", err)
+	}
+	app, err := runtime.NewApplication(runtime.ApplicationConfig{
+		ChainID:    "test-chain",
+		StateStore: iavlStore,
+		Modules:    []runtime.Module{mod},
+	})
+	if err != nil {
+		t.Fatalf("failed to create application: %v", err)
+	}
+	accounts.app = app
+
+	for _, name := range []types.AccountName{"alice", "guardian1", "guardian2"} {
+		if err := app.AccountStore().Set(ctx, []byte(name), types.NewAccount(name, []byte("pubkey-"+name))); err != nil {
+			t.Fatalf("failed to create account %s: %v", name, err)
+		}
+	}
+
+	deliver := func(height uint64, account types.AccountName, msg types.Message) {
+		t.Helper()
+		execCtx := setupTestContext(t, height, account)
+		effs, err := app.Router().RouteMsg(execCtx, msg)
+		if err != nil {
+			t.Fatalf("%s failed: %v", msg.Type(), err)
+		}
+		if _, _, err := app.EffectApplier().Apply(execCtx, effs); err != nil {
+			t.Fatalf("failed to apply %s effects: %v", msg.Type(), err)
+		}
+	}
+	deliver(1, "alice", &MsgSetGuardians{
+		Account:   "alice",
+		Guardians: testGuardians(2, map[types.AccountName]uint64{"guardian1": 1, "guardian2": 1}),
+		Timelock:  5,
+	})
+	deliver(2, "guardian1", &MsgInitiateRecovery{Guardian: "guardian1", Account: "alice", NewAuthority: testNewAuthority()})
+	deliver(3, "guardian2", &MsgApproveRecovery{Guardian: "guardian2", Account: "alice"})
+	deliver(8, "guardian1", &MsgExecuteRecovery{Executor: "guardian1", Account: "alice"})
+
+	checkRotated := func(account *types.Account) {
+		t.Helper()
+		if !account.Authority.HasKey([]byte("recovered-pubkey")) || account.Authority.HasKey([]byte("pubkey-alice")) {
+			t.Fatalf("authority not rotated: %+v", account.Authority)
+		}
+	}
+	account, err := app.AccountStore().Get(ctx, []byte("alice"))
+	if err != nil {
+		t.Fatalf("failed to get account: %v", err)
+	}
+	checkRotated(account)
+
+	if err := app.BeginBlock(ctx, runtime.NewBlockHeader(8, time.Now(), "test-chain", nil)); err != nil {
+		t.Fatalf("BeginBlock failed: %v", err)
+	}
+	if _, err := app.Commit(ctx); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	raw, err := iavlStore.Get([]byte("alice"))
+	if err != nil {
+		t.Fatalf("failed to read committed account: %v", err)
+	}
+	committed, err := store.NewJSONSerializer[*types.Account]().Unmarshal(raw)
+	if err != nil {
+		t.Fatalf("failed to decode committed account %q: %v", raw, err)
+	}
+	checkRotated(committed)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/blockberries/punnet-sdk/capability"
 	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/events"
 	"github.com/blockberries/punnet-sdk/module"
 	"github.com/blockberries/punnet-sdk/runtime"
 	"github.com/blockberries/punnet-sdk/store"
@@ -62,6 +63,26 @@ func CreateModule(validatorCap capability.ValidatorCapability, balanceCap capabi
 		Build()
 }
 
+// Spec returns the staking module's ModuleSpec for a module.ModuleManager
+func Spec() module.ModuleSpec {
+	return module.ModuleSpec{
+		Name:         ModuleName,
+		Dependencies: []string{"bank"},
+		Capabilities: []module.CapabilityKind{module.CapabilityValidator, module.CapabilityBalance},
+		Create: func(caps module.Capabilities) (module.Module, error) {
+			validatorCap, err := caps.Validator()
+			if err != nil {
+				return nil, err
+			}
+			balanceCap, err := caps.Balance()
+			if err != nil {
+				return nil, err
+			}
+			return CreateModule(validatorCap, balanceCap)
+		},
+	}
+}
+
 // handleCreateValidator handles MsgCreateValidator
 func (m *StakingModule) handleCreateValidator(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
 	if m == nil || m.validatorCap == nil {
@@ -183,7 +204,7 @@ func (m *StakingModule) handleDelegate(ctx *runtime.Context, msg types.Message)
 			Value:    delegation,
 		},
 		// Emit event
-		effects.NewEventEffect("staking.delegated", map[string][]byte{
+		effects.NewEventEffect(events.TypeDelegate, map[string][]byte{
 			"delegator": []byte(delegateMsg.Delegator),
 			"validator": []byte(hex.EncodeToString(delegateMsg.Validator)),
 			"amount":    []byte(fmt.Sprintf("%d", delegateMsg.Amount.Amount)),
@@ -0,0 +1,86 @@
+package upgrade
+
+import (
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/types"
+	sdkupgrade "github.com/blockberries/punnet-sdk/upgrade"
+)
+
+// Message type identifiers
+const (
+	TypeMsgScheduleUpgrade = "/punnet.upgrade.v1.MsgScheduleUpgrade"
+	TypeMsgCancelUpgrade   = "/punnet.upgrade.v1.MsgCancelUpgrade"
+)
+
+// MsgScheduleUpgrade schedules a named upgrade at a future height,
+// replacing any plan scheduled earlier
+type MsgScheduleUpgrade struct {
+	// Authority is the governance account allowed to schedule upgrades
+	Authority types.AccountName `json:"authority"`
+
+	// Plan is the upgrade to schedule
+	Plan sdkupgrade.Plan `json:"plan"`
+}
+
+// Type returns the message type
+func (m *MsgScheduleUpgrade) Type() string {
+	return TypeMsgScheduleUpgrade
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgScheduleUpgrade) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Authority.IsValid() {
+		return fmt.Errorf("%w: invalid authority account %s", types.ErrInvalidAccount, m.Authority)
+	}
+
+	if err := m.Plan.ValidateBasic(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgScheduleUpgrade) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Authority}
+}
+
+// MsgCancelUpgrade cancels the scheduled upgrade
+type MsgCancelUpgrade struct {
+	// Authority is the governance account allowed to cancel upgrades
+	Authority types.AccountName `json:"authority"`
+}
+
+// Type returns the message type
+func (m *MsgCancelUpgrade) Type() string {
+	return TypeMsgCancelUpgrade
+}
+
+// ValidateBasic performs stateless validation
+func (m *MsgCancelUpgrade) ValidateBasic() error {
+	if m == nil {
+		return fmt.Errorf("message is nil")
+	}
+
+	if !m.Authority.IsValid() {
+		return fmt.Errorf("%w: invalid authority account %s", types.ErrInvalidAccount, m.Authority)
+	}
+
+	return nil
+}
+
+// GetSigners returns the accounts that must authorize this message
+func (m *MsgCancelUpgrade) GetSigners() []types.AccountName {
+	if m == nil {
+		return nil
+	}
+	return []types.AccountName{m.Authority}
+}
@@ -0,0 +1,430 @@
+// Package upgrade provides on-chain scheduling of named software upgrades.
+//
+// A governance authority schedules a plan (name, height, info). When the
+// chain reaches the plan height, a binary that does not register an upgrade
+// handler for the plan halts in BeginBlock with ErrUpgradeNeeded, so node
+// operators can swap in the new binary. The new binary registers the handler
+// (an upgrade.UpgradeHandler from the root upgrade package), which runs the
+// store migrations at that same height and clears the plan.
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	sdkerrors "github.com/blockberries/punnet-sdk/errors"
+	"github.com/blockberries/punnet-sdk/module"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+	sdkupgrade "github.com/blockberries/punnet-sdk/upgrade"
+)
+
+// Module name
+const ModuleName = "upgrade"
+
+var (
+	// ErrUpgradeNeeded halts the chain at a plan height the binary cannot handle
+	ErrUpgradeNeeded = errors.New("upgrade needed")
+
+	// ErrWrongBinary is returned when the binary handles a plan whose height
+	// has not been reached yet
+	ErrWrongBinary = errors.New("binary started before upgrade height")
+
+	// ErrPlanHeightPassed is returned when scheduling a plan at or below the current height
+	ErrPlanHeightPassed = errors.New("upgrade height already passed")
+
+	// ErrPlanAlreadyApplied is returned when scheduling a plan name that was already applied
+	ErrPlanAlreadyApplied = errors.New("upgrade already applied")
+
+	// ErrDuplicateHandler is returned when an upgrade handler is registered twice
+	ErrDuplicateHandler = errors.New("upgrade handler already registered")
+)
+
+// Upgrade module error codes, in the ModuleName codespace.
+func init() {
+	sdkerrors.MustRegister(ModuleName, 2, ErrUpgradeNeeded)
+	sdkerrors.MustRegister(ModuleName, 3, ErrWrongBinary)
+	sdkerrors.MustRegister(ModuleName, 4, ErrPlanHeightPassed)
+	sdkerrors.MustRegister(ModuleName, 5, ErrPlanAlreadyApplied)
+}
+
+// planKey is the key of the scheduled plan within the module namespace
+var planKey = []byte("plan")
+
+// Event types
+const (
+	EventTypeScheduleUpgrade = "schedule_upgrade"
+	EventTypeCancelUpgrade   = "cancel_upgrade"
+)
+
+// UpgradeModule schedules upgrades and halts or migrates at the plan height.
+//
+// Messages schedule and cancel the plan through effects. ScheduleUpgrade and
+// CancelUpgrade write the state store directly and are meant for genesis and
+// keeper use; beginBlock clears an applied plan directly, together with the
+// migrations it runs.
+type UpgradeModule struct {
+	mu sync.RWMutex
+
+	// stateStore is the full state store; migrations need all namespaces
+	stateStore store.BackingStore
+
+	// moduleStore is the "module/upgrade/" view of stateStore
+	moduleStore store.BackingStore
+
+	// authority is the only account allowed to schedule or cancel upgrades
+	authority types.AccountName
+
+	// handlers maps plan names to the handlers this binary provides
+	handlers map[string]*sdkupgrade.UpgradeHandler
+}
+
+// NewUpgradeModule creates an upgrade module over the application state store.
+// authority is typically the governance account.
+func NewUpgradeModule(stateStore store.BackingStore, authority types.AccountName) (*UpgradeModule, error) {
+	if stateStore == nil {
+		return nil, fmt.Errorf("state store cannot be nil")
+	}
+
+	if !authority.IsValid() {
+		return nil, fmt.Errorf("%w: invalid authority account %s", types.ErrInvalidAccount, authority)
+	}
+
+	return &UpgradeModule{
+		stateStore:  stateStore,
+		moduleStore: sdkupgrade.ModuleStore(stateStore, ModuleName),
+		authority:   authority,
+		handlers:    make(map[string]*sdkupgrade.UpgradeHandler),
+	}, nil
+}
+
+// CreateModule creates the upgrade module using the module builder.
+// Register upgrade handlers on upgradeMod before the chain reaches a plan height.
+//
+// Usage:
+//
+//	upgradeMod, _ := upgrade.NewUpgradeModule(stateStore, "gov")
+//	upgradeMod.SetUpgradeHandler("v2", sdkupgrade.NewUpgradeHandler(migrator, sdkupgrade.CurrentVersions(modules)))
+//	mod, _ := upgrade.CreateModule(upgradeMod)
+func CreateModule(upgradeMod *UpgradeModule) (module.Module, error) {
+	if upgradeMod == nil {
+		return nil, fmt.Errorf("upgrade module cannot be nil")
+	}
+
+	return module.NewModuleBuilder(ModuleName).
+		WithMsgHandler(TypeMsgScheduleUpgrade, upgradeMod.handleScheduleUpgrade).
+		WithMsgHandler(TypeMsgCancelUpgrade, upgradeMod.handleCancelUpgrade).
+		WithQueryHandler("/plan", upgradeMod.handleQueryPlan).
+		WithQueryHandler("/applied", upgradeMod.handleQueryApplied).
+		WithBeginBlocker(upgradeMod.beginBlock).
+		Build()
+}
+
+// Authority returns the account allowed to schedule upgrades
+func (m *UpgradeModule) Authority() types.AccountName {
+	if m == nil {
+		return ""
+	}
+	return m.authority
+}
+
+// SetUpgradeHandler registers the handler that performs the named upgrade.
+// A binary registers a handler only for the upgrades it implements.
+func (m *UpgradeModule) SetUpgradeHandler(planName string, handler *sdkupgrade.UpgradeHandler) error {
+	if m == nil {
+		return fmt.Errorf("upgrade module is nil")
+	}
+	if planName == "" {
+		return fmt.Errorf("%w: name cannot be empty", sdkupgrade.ErrInvalidPlan)
+	}
+	if handler == nil {
+		return fmt.Errorf("upgrade handler cannot be nil")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.handlers[planName]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateHandler, planName)
+	}
+	m.handlers[planName] = handler
+	return nil
+}
+
+// HasUpgradeHandler reports whether this binary handles the named upgrade
+func (m *UpgradeModule) HasUpgradeHandler(planName string) bool {
+	return m.upgradeHandler(planName) != nil
+}
+
+// Plan returns the scheduled plan, if any
+func (m *UpgradeModule) Plan() (sdkupgrade.Plan, bool, error) {
+	if m == nil {
+		return sdkupgrade.Plan{}, false, fmt.Errorf("upgrade module is nil")
+	}
+
+	data, err := m.moduleStore.Get(planKey)
+	if errors.Is(err, store.ErrNotFound) {
+		return sdkupgrade.Plan{}, false, nil
+	}
+	if err != nil {
+		return sdkupgrade.Plan{}, false, fmt.Errorf("failed to read upgrade plan: %w", err)
+	}
+
+	var plan sdkupgrade.Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return sdkupgrade.Plan{}, false, fmt.Errorf("failed to decode upgrade plan: %w", err)
+	}
+	return plan, true, nil
+}
+
+// ScheduleUpgrade validates plan against the current height and stores it,
+// replacing any previously scheduled plan
+func (m *UpgradeModule) ScheduleUpgrade(currentHeight uint64, plan sdkupgrade.Plan) error {
+	if err := m.checkPlan(currentHeight, plan); err != nil {
+		return err
+	}
+
+	data, err := encodePlan(plan)
+	if err != nil {
+		return err
+	}
+	if err := m.moduleStore.Set(planKey, data); err != nil {
+		return fmt.Errorf("failed to store upgrade plan: %w", err)
+	}
+	return nil
+}
+
+// CancelUpgrade removes the scheduled plan. Cancelling with no plan is a no-op.
+func (m *UpgradeModule) CancelUpgrade() error {
+	if m == nil {
+		return fmt.Errorf("upgrade module is nil")
+	}
+
+	if err := m.moduleStore.Delete(planKey); err != nil && !errors.Is(err, store.ErrNotFound) {
+		return fmt.Errorf("failed to delete upgrade plan: %w", err)
+	}
+	return nil
+}
+
+// encodePlan encodes plan as stored under planKey
+func encodePlan(plan sdkupgrade.Plan) ([]byte, error) {
+	data, err := json.Marshal(plan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode upgrade plan: %w", err)
+	}
+	return data, nil
+}
+
+// checkPlan performs the stateful validation of a plan to be scheduled
+func (m *UpgradeModule) checkPlan(currentHeight uint64, plan sdkupgrade.Plan) error {
+	if m == nil {
+		return fmt.Errorf("upgrade module is nil")
+	}
+
+	if err := plan.ValidateBasic(); err != nil {
+		return err
+	}
+
+	if plan.Height <= currentHeight {
+		return fmt.Errorf("%w: plan height %d, current height %d", ErrPlanHeightPassed, plan.Height, currentHeight)
+	}
+
+	applied, err := sdkupgrade.IsApplied(m.stateStore, plan.Name)
+	if err != nil {
+		return err
+	}
+	if applied {
+		return fmt.Errorf("%w: %s", ErrPlanAlreadyApplied, plan.Name)
+	}
+	return nil
+}
+
+// checkAuthority verifies that the message authority is the configured
+// authority and the transaction account
+func (m *UpgradeModule) checkAuthority(ctx *runtime.Context, authority types.AccountName) error {
+	if authority != m.authority {
+		return fmt.Errorf("%w: expected authority %s, got %s", types.ErrUnauthorized, m.authority, authority)
+	}
+	if authority != ctx.Account() {
+		return fmt.Errorf("authority must be transaction account")
+	}
+	return nil
+}
+
+// upgradeHandler returns the handler registered for planName, or nil
+func (m *UpgradeModule) upgradeHandler(planName string) *sdkupgrade.UpgradeHandler {
+	if m == nil {
+		return nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.handlers[planName]
+}
+
+// handleScheduleUpgrade handles MsgScheduleUpgrade
+func (m *UpgradeModule) handleScheduleUpgrade(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("upgrade module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	scheduleMsg, ok := msg.(*MsgScheduleUpgrade)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgScheduleUpgrade")
+	}
+
+	if err := m.checkAuthority(ctx, scheduleMsg.Authority); err != nil {
+		return nil, err
+	}
+
+	if err := m.checkPlan(ctx.BlockHeight(), scheduleMsg.Plan); err != nil {
+		return nil, err
+	}
+	data, err := encodePlan(scheduleMsg.Plan)
+	if err != nil {
+		return nil, err
+	}
+
+	event := types.NewEvent(EventTypeScheduleUpgrade)
+	event.AddAttribute("name", []byte(scheduleMsg.Plan.Name))
+	event.AddAttribute("height", []byte(strconv.FormatUint(scheduleMsg.Plan.Height, 10)))
+	ctx.EventManager().EmitEvent(event)
+
+	return []effects.Effect{effects.NewStateWriteEffect(ModuleName, planKey, data)}, nil
+}
+
+// handleCancelUpgrade handles MsgCancelUpgrade
+func (m *UpgradeModule) handleCancelUpgrade(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+	if m == nil {
+		return nil, fmt.Errorf("upgrade module is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context is nil")
+	}
+
+	cancelMsg, ok := msg.(*MsgCancelUpgrade)
+	if !ok {
+		return nil, fmt.Errorf("invalid message type: expected *MsgCancelUpgrade")
+	}
+
+	if err := m.checkAuthority(ctx, cancelMsg.Authority); err != nil {
+		return nil, err
+	}
+
+	plan, ok, err := m.Plan()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: no upgrade scheduled", types.ErrNotFound)
+	}
+
+	event := types.NewEvent(EventTypeCancelUpgrade)
+	event.AddAttribute("name", []byte(plan.Name))
+	ctx.EventManager().EmitEvent(event)
+
+	return []effects.Effect{effects.NewStateDeleteEffect(ModuleName, planKey)}, nil
+}
+
+// beginBlock halts at the plan height unless this binary handles the plan,
+// in which case it runs the plan's migrations and clears the plan.
+//
+// SECURITY: A binary that handles a plan refuses to run before the plan
+// height, so an early binary swap cannot apply new rules to old blocks.
+func (m *UpgradeModule) beginBlock(ctx *runtime.Context) ([]effects.Effect, error) {
+	plan, ok, err := m.Plan()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	height := ctx.BlockHeight()
+	handler := m.upgradeHandler(plan.Name)
+
+	if height < plan.Height {
+		if handler != nil {
+			return nil, fmt.Errorf("%w: binary handles upgrade %q scheduled at height %d, current height is %d; run the previous binary until the upgrade height",
+				ErrWrongBinary, plan.Name, plan.Height, height)
+		}
+		return nil, nil
+	}
+
+	if handler == nil {
+		return nil, fmt.Errorf("%w: UPGRADE %q NEEDED at height %d (current height %d)%s; halting: restart with a binary that registers an upgrade handler for %q",
+			ErrUpgradeNeeded, plan.Name, plan.Height, height, formatInfo(plan.Info), plan.Name)
+	}
+
+	// Run at the current height; it exceeds plan.Height only if the chain
+	// was stopped and restarted past the plan height
+	run := plan
+	run.Height = height
+	if err := handler.SchedulePlan(run); err != nil {
+		return nil, err
+	}
+	_, err = handler.Apply(ctx.Context(), m.stateStore, height)
+	handler.ClearPlan()
+	if err != nil {
+		return nil, fmt.Errorf("upgrade %q failed: %w", plan.Name, err)
+	}
+
+	if err := m.CancelUpgrade(); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// formatInfo renders plan info for halt diagnostics
+func formatInfo(info string) string {
+	if info == "" {
+		return ""
+	}
+	return fmt.Sprintf(" info: %s", info)
+}
+
+// handleQueryPlan returns the scheduled plan as JSON, or null if none
+func (m *UpgradeModule) handleQueryPlan(ctx context.Context, path string, data []byte) ([]byte, error) {
+	plan, ok, err := m.Plan()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return json.Marshal(nil)
+	}
+	return json.Marshal(plan)
+}
+
+// AppliedResponse is the response of the "/applied" query
+type AppliedResponse struct {
+	Name    string `json:"name"`
+	Applied bool   `json:"applied"`
+	Height  uint64 `json:"height,omitempty"`
+}
+
+// handleQueryApplied reports whether the plan named by data was applied, and at which height
+func (m *UpgradeModule) handleQueryApplied(ctx context.Context, path string, data []byte) ([]byte, error) {
+	if m == nil {
+		return nil, fmt.Errorf("upgrade module is nil")
+	}
+
+	name := string(data)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name cannot be empty", sdkupgrade.ErrInvalidPlan)
+	}
+
+	height, applied, err := sdkupgrade.AppliedHeight(m.stateStore, name)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(AppliedResponse{Name: name, Applied: applied, Height: height})
+}
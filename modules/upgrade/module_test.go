@@ -0,0 +1,315 @@
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+	punnettesting "github.com/blockberries/punnet-sdk/testing"
+	"github.com/blockberries/punnet-sdk/types"
+	sdkupgrade "github.com/blockberries/punnet-sdk/upgrade"
+)
+
+const testAuthority = types.AccountName("gov")
+
+func setupTestUpgradeModule(t *testing.T) (*UpgradeModule, *punnettesting.EffectEnv) {
+	t.Helper()
+
+	env := punnettesting.NewEffectEnv(t)
+	upgradeMod, err := NewUpgradeModule(env.Store(), testAuthority)
+	if err != nil {
+		t.Fatalf("failed to create upgrade module: %v", err)
+	}
+	return upgradeMod, env
+}
+
+func setupTestContext(t *testing.T, height uint64, account types.AccountName) *runtime.Context {
+	t.Helper()
+
+	header := runtime.NewBlockHeader(height, time.Now(), "test-chain", []byte("proposer"))
+	ctx, err := runtime.NewContext(context.Background(), header, account)
+	if err != nil {
+		t.Fatalf("failed to create context: %v", err)
+	}
+	return ctx
+}
+
+func TestNewUpgradeModule(t *testing.T) {
+	if _, err := NewUpgradeModule(nil, testAuthority); err == nil {
+		t.Fatal("expected error for nil store")
+	}
+	if _, err := NewUpgradeModule(store.NewMemoryStore(), ""); !errors.Is(err, types.ErrInvalidAccount) {
+		t.Fatalf("expected ErrInvalidAccount, got %v", err)
+	}
+
+	upgradeMod, _ := setupTestUpgradeModule(t)
+	mod, err := CreateModule(upgradeMod)
+	if err != nil {
+		t.Fatalf("CreateModule failed: %v", err)
+	}
+	if mod.Name() != ModuleName {
+		t.Fatalf("expected module name %s, got %s", ModuleName, mod.Name())
+	}
+	if upgradeMod.Authority() != testAuthority {
+		t.Fatalf("expected authority %s, got %s", testAuthority, upgradeMod.Authority())
+	}
+}
+
+func TestMsgScheduleUpgrade_ValidateBasic(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     *MsgScheduleUpgrade
+		wantErr bool
+	}{
+		{"valid", &MsgScheduleUpgrade{Authority: testAuthority, Plan: sdkupgrade.Plan{Name: "v2", Height: 10}}, false},
+		{"nil message", nil, true},
+		{"invalid authority", &MsgScheduleUpgrade{Plan: sdkupgrade.Plan{Name: "v2", Height: 10}}, true},
+		{"empty name", &MsgScheduleUpgrade{Authority: testAuthority, Plan: sdkupgrade.Plan{Height: 10}}, true},
+		{"zero height", &MsgScheduleUpgrade{Authority: testAuthority, Plan: sdkupgrade.Plan{Name: "v2"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.msg.ValidateBasic()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateBasic() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	msg := &MsgScheduleUpgrade{Authority: testAuthority}
+	if signers := msg.GetSigners(); len(signers) != 1 || signers[0] != testAuthority {
+		t.Fatalf("unexpected signers %v", signers)
+	}
+	if (&MsgCancelUpgrade{}).ValidateBasic() == nil {
+		t.Fatal("expected error for cancel without authority")
+	}
+}
+
+func TestHandleScheduleUpgrade(t *testing.T) {
+	plan := sdkupgrade.Plan{Name: "v2", Height: 10, Info: "https://example.com/v2"}
+
+	t.Run("wrong authority", func(t *testing.T) {
+		upgradeMod, _ := setupTestUpgradeModule(t)
+		ctx := setupTestContext(t, 1, "alice")
+		_, err := upgradeMod.handleScheduleUpgrade(ctx, &MsgScheduleUpgrade{Authority: "alice", Plan: plan})
+		if !errors.Is(err, types.ErrUnauthorized) {
+			t.Fatalf("expected ErrUnauthorized, got %v", err)
+		}
+	})
+
+	t.Run("authority is not transaction account", func(t *testing.T) {
+		upgradeMod, _ := setupTestUpgradeModule(t)
+		ctx := setupTestContext(t, 1, "alice")
+		if _, err := upgradeMod.handleScheduleUpgrade(ctx, &MsgScheduleUpgrade{Authority: testAuthority, Plan: plan}); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("height passed", func(t *testing.T) {
+		upgradeMod, _ := setupTestUpgradeModule(t)
+		ctx := setupTestContext(t, 10, testAuthority)
+		_, err := upgradeMod.handleScheduleUpgrade(ctx, &MsgScheduleUpgrade{Authority: testAuthority, Plan: plan})
+		if !errors.Is(err, ErrPlanHeightPassed) {
+			t.Fatalf("expected ErrPlanHeightPassed, got %v", err)
+		}
+	})
+
+	t.Run("handler does not write state", func(t *testing.T) {
+		upgradeMod, _ := setupTestUpgradeModule(t)
+		ctx := setupTestContext(t, 1, testAuthority)
+		effs, err := upgradeMod.handleScheduleUpgrade(ctx, &MsgScheduleUpgrade{Authority: testAuthority, Plan: plan})
+		if err != nil {
+			t.Fatalf("handleScheduleUpgrade failed: %v", err)
+		}
+		if len(effs) != 1 {
+			t.Fatalf("expected 1 effect, got %d", len(effs))
+		}
+		if _, ok, _ := upgradeMod.Plan(); ok {
+			t.Fatal("plan stored before its effects were applied")
+		}
+	})
+
+	t.Run("schedule and cancel", func(t *testing.T) {
+		upgradeMod, env := setupTestUpgradeModule(t)
+		ctx := setupTestContext(t, 1, testAuthority)
+		effs, err := upgradeMod.handleScheduleUpgrade(ctx, &MsgScheduleUpgrade{Authority: testAuthority, Plan: plan})
+		if err != nil {
+			t.Fatalf("handleScheduleUpgrade failed: %v", err)
+		}
+		env.Apply(t, ctx, effs)
+
+		got, ok, err := upgradeMod.Plan()
+		if err != nil || !ok {
+			t.Fatalf("expected scheduled plan, got ok=%v err=%v", ok, err)
+		}
+		if got != plan {
+			t.Fatalf("expected plan %+v, got %+v", plan, got)
+		}
+		if events := ctx.EventManager().Events(); len(events) != 1 || events[0].Type != EventTypeScheduleUpgrade {
+			t.Fatalf("unexpected events %+v", events)
+		}
+
+		effs, err = upgradeMod.handleCancelUpgrade(ctx, &MsgCancelUpgrade{Authority: testAuthority})
+		if err != nil {
+			t.Fatalf("handleCancelUpgrade failed: %v", err)
+		}
+		if _, ok, _ := upgradeMod.Plan(); !ok {
+			t.Fatal("plan cancelled before its effects were applied")
+		}
+		env.Apply(t, ctx, effs)
+		if _, ok, _ := upgradeMod.Plan(); ok {
+			t.Fatal("plan not cancelled")
+		}
+		if _, err := upgradeMod.handleCancelUpgrade(ctx, &MsgCancelUpgrade{Authority: testAuthority}); !errors.Is(err, types.ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+func TestBeginBlock_HaltsWithoutHandler(t *testing.T) {
+	upgradeMod, _ := setupTestUpgradeModule(t)
+	plan := sdkupgrade.Plan{Name: "v2", Height: 10, Info: "binary at https://example.com/v2"}
+	if err := upgradeMod.ScheduleUpgrade(1, plan); err != nil {
+		t.Fatalf("ScheduleUpgrade failed: %v", err)
+	}
+
+	if _, err := upgradeMod.beginBlock(setupTestContext(t, 9, "system")); err != nil {
+		t.Fatalf("unexpected error before upgrade height: %v", err)
+	}
+
+	_, err := upgradeMod.beginBlock(setupTestContext(t, 10, "system"))
+	if !errors.Is(err, ErrUpgradeNeeded) {
+		t.Fatalf("expected ErrUpgradeNeeded, got %v", err)
+	}
+	for _, want := range []string{`"v2"`, "height 10", plan.Info} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("halt error %q does not mention %q", err, want)
+		}
+	}
+
+	// The plan stays scheduled so the new binary can apply it
+	if _, ok, _ := upgradeMod.Plan(); !ok {
+		t.Fatal("plan cleared on halt")
+	}
+}
+
+func TestBeginBlock_AppliesWithHandler(t *testing.T) {
+	upgradeMod, env := setupTestUpgradeModule(t)
+	stateStore := env.Store()
+	if err := sdkupgrade.SaveVersions(stateStore, sdkupgrade.VersionMap{"bank": 1}); err != nil {
+		t.Fatalf("SaveVersions failed: %v", err)
+	}
+
+	migrated := false
+	migrator := sdkupgrade.NewMigrator()
+	err := migrator.Register("bank", 1, func(ctx context.Context, s store.BackingStore) error {
+		migrated = true
+		return sdkupgrade.ModuleStore(s, "bank").Set([]byte("migrated"), []byte{1})
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	handler := sdkupgrade.NewUpgradeHandler(migrator, sdkupgrade.VersionMap{"bank": 2})
+	if err := upgradeMod.SetUpgradeHandler("v2", handler); err != nil {
+		t.Fatalf("SetUpgradeHandler failed: %v", err)
+	}
+	if err := upgradeMod.SetUpgradeHandler("v2", handler); !errors.Is(err, ErrDuplicateHandler) {
+		t.Fatalf("expected ErrDuplicateHandler, got %v", err)
+	}
+	if !upgradeMod.HasUpgradeHandler("v2") {
+		t.Fatal("expected handler for v2")
+	}
+
+	if err := upgradeMod.ScheduleUpgrade(1, sdkupgrade.Plan{Name: "v2", Height: 10}); err != nil {
+		t.Fatalf("ScheduleUpgrade failed: %v", err)
+	}
+
+	// Running the new binary before the upgrade height is refused
+	if _, err := upgradeMod.beginBlock(setupTestContext(t, 9, "system")); !errors.Is(err, ErrWrongBinary) {
+		t.Fatalf("expected ErrWrongBinary, got %v", err)
+	}
+
+	if _, err := upgradeMod.beginBlock(setupTestContext(t, 10, "system")); err != nil {
+		t.Fatalf("beginBlock failed: %v", err)
+	}
+	if !migrated {
+		t.Fatal("migration did not run")
+	}
+	if _, ok, _ := upgradeMod.Plan(); ok {
+		t.Fatal("plan not cleared after upgrade")
+	}
+
+	versions, err := sdkupgrade.LoadVersions(stateStore)
+	if err != nil {
+		t.Fatalf("LoadVersions failed: %v", err)
+	}
+	if versions["bank"] != 2 {
+		t.Fatalf("expected bank at version 2, got %d", versions["bank"])
+	}
+
+	// An applied plan cannot be scheduled again
+	if err := upgradeMod.ScheduleUpgrade(10, sdkupgrade.Plan{Name: "v2", Height: 20}); !errors.Is(err, ErrPlanAlreadyApplied) {
+		t.Fatalf("expected ErrPlanAlreadyApplied, got %v", err)
+	}
+}
+
+func TestQueries(t *testing.T) {
+	upgradeMod, env := setupTestUpgradeModule(t)
+	stateStore := env.Store()
+	ctx := context.Background()
+
+	data, err := upgradeMod.handleQueryPlan(ctx, "/plan", nil)
+	if err != nil {
+		t.Fatalf("plan query failed: %v", err)
+	}
+	if string(data) != "null" {
+		t.Fatalf("expected null plan, got %s", data)
+	}
+
+	plan := sdkupgrade.Plan{Name: "v2", Height: 10}
+	if err := upgradeMod.ScheduleUpgrade(1, plan); err != nil {
+		t.Fatalf("ScheduleUpgrade failed: %v", err)
+	}
+	data, err = upgradeMod.handleQueryPlan(ctx, "/plan", nil)
+	if err != nil {
+		t.Fatalf("plan query failed: %v", err)
+	}
+	var got sdkupgrade.Plan
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("failed to decode plan: %v", err)
+	}
+	if got != plan {
+		t.Fatalf("expected plan %+v, got %+v", plan, got)
+	}
+
+	handler := sdkupgrade.NewUpgradeHandler(sdkupgrade.NewMigrator(), nil)
+	if err := handler.SchedulePlan(plan); err != nil {
+		t.Fatalf("SchedulePlan failed: %v", err)
+	}
+	if _, err := handler.Apply(ctx, stateStore, 10); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	data, err = upgradeMod.handleQueryApplied(ctx, "/applied", []byte("v2"))
+	if err != nil {
+		t.Fatalf("applied query failed: %v", err)
+	}
+	var applied AppliedResponse
+	if err := json.Unmarshal(data, &applied); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !applied.Applied || applied.Height != 10 {
+		t.Fatalf("unexpected applied response %+v", applied)
+	}
+
+	if _, err := upgradeMod.handleQueryApplied(ctx, "/applied", nil); err == nil {
+		t.Fatal("expected error for empty name")
+	}
+}
@@ -0,0 +1,264 @@
+package protocodec
+
+import (
+	typesv1 "github.com/blockberries/punnet-sdk/api/punnet/types/v1"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// CoinToProto converts a Coin to protobuf
+func CoinToProto(c types.Coin) *typesv1.Coin {
+	return &typesv1.Coin{Denom: c.Denom, Amount: c.Amount}
+}
+
+// CoinFromProto converts a protobuf Coin; nil converts to the zero coin
+func CoinFromProto(c *typesv1.Coin) types.Coin {
+	return types.Coin{Denom: c.GetDenom(), Amount: c.GetAmount()}
+}
+
+// CoinsToProto converts Coins to protobuf, preserving order
+func CoinsToProto(coins types.Coins) []*typesv1.Coin {
+	out := make([]*typesv1.Coin, len(coins))
+	for i, c := range coins {
+		out[i] = CoinToProto(c)
+	}
+	return out
+}
+
+// CoinsFromProto converts protobuf coins, preserving order.
+//
+// INVARIANT: The result is never nil (protobuf does not distinguish empty
+// from absent lists).
+func CoinsFromProto(coins []*typesv1.Coin) types.Coins {
+	out := make(types.Coins, len(coins))
+	for i, c := range coins {
+		out[i] = CoinFromProto(c)
+	}
+	return out
+}
+
+// AuthorityToProto converts an Authority to protobuf
+func AuthorityToProto(a types.Authority) *typesv1.Authority {
+	accountWeights := make(map[string]uint64, len(a.AccountWeights))
+	for name, weight := range a.AccountWeights {
+		accountWeights[string(name)] = weight
+	}
+	keyWeights := make(map[string]uint64, len(a.KeyWeights))
+	for keyID, weight := range a.KeyWeights {
+		keyWeights[keyID] = weight
+	}
+	return &typesv1.Authority{
+		Threshold:      a.Threshold,
+		KeyWeights:     keyWeights,
+		AccountWeights: accountWeights,
+	}
+}
+
+// AuthorityFromProto converts a protobuf Authority. The weight maps are
+// never nil.
+func AuthorityFromProto(a *typesv1.Authority) types.Authority {
+	accountWeights := make(map[types.AccountName]uint64, len(a.GetAccountWeights()))
+	for name, weight := range a.GetAccountWeights() {
+		accountWeights[types.AccountName(name)] = weight
+	}
+	keyWeights := make(map[string]uint64, len(a.GetKeyWeights()))
+	for keyID, weight := range a.GetKeyWeights() {
+		keyWeights[keyID] = weight
+	}
+	return types.Authority{
+		Threshold:      a.GetThreshold(),
+		KeyWeights:     keyWeights,
+		AccountWeights: accountWeights,
+	}
+}
+
+// feeToProto converts a Fee to protobuf
+func feeToProto(f types.Fee) *typesv1.Fee {
+	return &typesv1.Fee{Amount: CoinsToProto(f.Amount), GasLimit: f.GasLimit}
+}
+
+// tipToProto converts an optional tip to protobuf; nil stays nil
+func tipToProto(tip *types.Coin) *typesv1.Coin {
+	if tip == nil {
+		return nil
+	}
+	return CoinToProto(*tip)
+}
+
+// tipFromProto converts an optional protobuf tip; nil stays nil
+func tipFromProto(tip *typesv1.Coin) *types.Coin {
+	if tip == nil {
+		return nil
+	}
+	c := CoinFromProto(tip)
+	return &c
+}
+
+// coSignersToProto converts co-signers to protobuf; empty stays nil
+func coSignersToProto(coSigners []types.CoSigner) []*typesv1.CoSigner {
+	if len(coSigners) == 0 {
+		return nil
+	}
+	out := make([]*typesv1.CoSigner, len(coSigners))
+	for i, cs := range coSigners {
+		out[i] = &typesv1.CoSigner{
+			Account:       string(cs.Account),
+			Nonce:         cs.Nonce,
+			Authorization: AuthorizationToProto(cs.Authorization),
+		}
+	}
+	return out
+}
+
+// coSignersFromProto converts protobuf co-signers; empty stays nil
+func coSignersFromProto(coSigners []*typesv1.CoSigner) []types.CoSigner {
+	if len(coSigners) == 0 {
+		return nil
+	}
+	out := make([]types.CoSigner, len(coSigners))
+	for i, cs := range coSigners {
+		out[i] = types.CoSigner{
+			Account:       types.AccountName(cs.GetAccount()),
+			Nonce:         cs.GetNonce(),
+			Authorization: AuthorizationFromProto(cs.GetAuthorization()),
+		}
+	}
+	return out
+}
+
+// feeFromProto converts a protobuf Fee
+func feeFromProto(f *typesv1.Fee) types.Fee {
+	return types.Fee{Amount: CoinsFromProto(f.GetAmount()), GasLimit: f.GetGasLimit()}
+}
+
+// ratioToProto converts a Ratio to protobuf
+func ratioToProto(r types.Ratio) *typesv1.Ratio {
+	return &typesv1.Ratio{Numerator: r.Numerator, Denominator: r.Denominator}
+}
+
+// ratioFromProto converts a protobuf Ratio
+func ratioFromProto(r *typesv1.Ratio) types.Ratio {
+	return types.Ratio{Numerator: r.GetNumerator(), Denominator: r.GetDenominator()}
+}
+
+// boundToProto converts a validity bound to protobuf; nil stays nil
+func boundToProto(b *types.ValidityBound) *typesv1.ValidityBound {
+	if b == nil {
+		return nil
+	}
+	return &typesv1.ValidityBound{Height: b.Height.Uint64(), Time: b.Time.Uint64()}
+}
+
+// boundFromProto converts a protobuf validity bound; nil stays nil
+func boundFromProto(b *typesv1.ValidityBound) *types.ValidityBound {
+	if b == nil {
+		return nil
+	}
+	return &types.ValidityBound{Height: types.StringUint64(b.Height), Time: types.StringUint64(b.Time)}
+}
+
+// signatureToProto converts a Signature to protobuf
+func signatureToProto(s types.Signature) *typesv1.Signature {
+	out := &typesv1.Signature{
+		Algorithm: string(s.Algorithm),
+		PubKey:    s.PubKey,
+		Signature: s.Signature,
+	}
+	if s.WebAuthn != nil {
+		out.Webauthn = &typesv1.WebAuthnAssertion{
+			AuthenticatorData: s.WebAuthn.AuthenticatorData,
+			ClientDataJson:    s.WebAuthn.ClientDataJSON,
+		}
+	}
+	return out
+}
+
+// signatureFromProto converts a protobuf Signature
+func signatureFromProto(s *typesv1.Signature) types.Signature {
+	out := types.Signature{
+		Algorithm: types.Algorithm(s.GetAlgorithm()),
+		PubKey:    s.GetPubKey(),
+		Signature: s.GetSignature(),
+	}
+	if w := s.GetWebauthn(); w != nil {
+		out.WebAuthn = &types.WebAuthnAssertion{
+			AuthenticatorData: w.GetAuthenticatorData(),
+			ClientDataJSON:    w.GetClientDataJson(),
+		}
+	}
+	return out
+}
+
+// AuthorizationToProto converts an Authorization to protobuf; nil stays nil
+func AuthorizationToProto(a *types.Authorization) *typesv1.Authorization {
+	if a == nil {
+		return nil
+	}
+
+	out := &typesv1.Authorization{
+		Signatures: make([]*typesv1.Signature, len(a.Signatures)),
+		NotBefore:  boundToProto(a.NotBefore),
+		NotAfter:   boundToProto(a.NotAfter),
+	}
+	for i, sig := range a.Signatures {
+		out.Signatures[i] = signatureToProto(sig)
+	}
+	if len(a.AccountAuthorizations) > 0 {
+		out.AccountAuthorizations = make(map[string]*typesv1.Authorization, len(a.AccountAuthorizations))
+		for name, sub := range a.AccountAuthorizations {
+			out.AccountAuthorizations[string(name)] = AuthorizationToProto(sub)
+		}
+	}
+	if s := a.Session; s != nil {
+		out.Session = &typesv1.SessionAuthorization{
+			Grant: &typesv1.SessionGrant{
+				ChainId:             s.Grant.ChainID,
+				Account:             string(s.Grant.Account),
+				SessionAlgorithm:    string(s.Grant.SessionAlgorithm),
+				SessionPubKey:       s.Grant.SessionPubKey,
+				AllowedMessageTypes: s.Grant.AllowedMessageTypes,
+				ExpirationHeight:    s.Grant.ExpirationHeight.Uint64(),
+			},
+			GrantAuthorization: AuthorizationToProto(s.GrantAuthorization),
+			Signature:          signatureToProto(s.Signature),
+		}
+	}
+	return out
+}
+
+// AuthorizationFromProto converts a protobuf Authorization; nil stays nil
+func AuthorizationFromProto(a *typesv1.Authorization) *types.Authorization {
+	if a == nil {
+		return nil
+	}
+
+	out := &types.Authorization{
+		Signatures: make([]types.Signature, len(a.GetSignatures())),
+		NotBefore:  boundFromProto(a.GetNotBefore()),
+		NotAfter:   boundFromProto(a.GetNotAfter()),
+	}
+	for i, sig := range a.GetSignatures() {
+		out.Signatures[i] = signatureFromProto(sig)
+	}
+	if len(a.GetAccountAuthorizations()) > 0 {
+		out.AccountAuthorizations = make(map[types.AccountName]*types.Authorization, len(a.GetAccountAuthorizations()))
+		for name, sub := range a.GetAccountAuthorizations() {
+			out.AccountAuthorizations[types.AccountName(name)] = AuthorizationFromProto(sub)
+		}
+	}
+	if s := a.GetSession(); s != nil {
+		g := s.GetGrant()
+		out.Session = &types.SessionAuthorization{
+			Grant: types.SessionGrant{
+				ChainID:             g.GetChainId(),
+				Account:             types.AccountName(g.GetAccount()),
+				SessionAlgorithm:    types.Algorithm(g.GetSessionAlgorithm()),
+				SessionPubKey:       g.GetSessionPubKey(),
+				AllowedMessageTypes: append([]string{}, g.GetAllowedMessageTypes()...),
+				ExpirationHeight:    types.StringUint64(g.GetExpirationHeight()),
+			},
+			GrantAuthorization: AuthorizationFromProto(s.GetGrantAuthorization()),
+			Signature:          signatureFromProto(s.GetSignature()),
+		}
+	}
+	return out
+}
@@ -0,0 +1,184 @@
+// Package protocodec bridges the protobuf definitions in proto/ (generated
+// into api/) and the SDK types, for gRPC services and SDKs in other
+// languages.
+//
+// Messages travel packed in google.protobuf.Any, with the message type as the
+// type URL (e.g. "/punnet.bank.v1.MsgSend"), so a message's protobuf full
+// name must match its Type(). Modules register converters for their
+// messages with RegisterMessage.
+//
+// SECURITY: Signatures always sign the canonical JSON SignDoc. A protobuf
+// transaction is only a transport: convert it with TxFromProto and derive
+// the sign bytes from the SDK transaction (see Registry.SignBytes); never
+// verify signatures over protobuf bytes.
+package protocodec
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+var (
+	// ErrUnregisteredMessage is returned for message types without a converter
+	ErrUnregisteredMessage = errors.New("message type not registered")
+
+	// ErrInvalidMessage is returned when a message cannot be converted
+	ErrInvalidMessage = errors.New("invalid message")
+)
+
+// converter converts one message type between its SDK and protobuf forms
+type converter struct {
+	toProto   func(types.Message) (proto.Message, error)
+	fromProto func(proto.Message) (types.Message, error)
+	newProto  func() proto.Message
+}
+
+// Registry maps message type URLs to converters.
+//
+// Thread-safe: All methods are safe for concurrent use.
+type Registry struct {
+	mu         sync.RWMutex
+	converters map[string]*converter
+}
+
+// NewRegistry creates a registry with no message types
+func NewRegistry() *Registry {
+	return &Registry{converters: make(map[string]*converter)}
+}
+
+// RegisterMessage registers the converters of an SDK message type M and its
+// protobuf form P. The type URL is "/" followed by P's full name.
+//
+// PRECONDITION: M's Type() returns the type URL; PackAny and UnpackAny check
+// it for every message.
+//
+// Returns an error if a converter is nil or the type URL is already registered.
+func RegisterMessage[M types.Message, P proto.Message](r *Registry, toProto func(M) (P, error), fromProto func(P) (M, error)) error {
+	if r == nil {
+		return fmt.Errorf("registry is nil")
+	}
+	if toProto == nil || fromProto == nil {
+		return fmt.Errorf("converters cannot be nil")
+	}
+
+	var zero P
+	msgType := "/" + string(zero.ProtoReflect().Descriptor().FullName())
+	c := &converter{
+		toProto: func(msg types.Message) (proto.Message, error) {
+			m, ok := msg.(M)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s message has Go type %T", ErrInvalidMessage, msgType, msg)
+			}
+			return toProto(m)
+		},
+		fromProto: func(p proto.Message) (types.Message, error) {
+			return fromProto(p.(P))
+		},
+		newProto: func() proto.Message {
+			return zero.ProtoReflect().New().Interface()
+		},
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.converters[msgType]; exists {
+		return fmt.Errorf("message type %q already registered", msgType)
+	}
+	r.converters[msgType] = c
+	return nil
+}
+
+// MessageTypes returns the registered type URLs in sorted order
+func (r *Registry) MessageTypes() []string {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	msgTypes := make([]string, 0, len(r.converters))
+	for msgType := range r.converters {
+		msgTypes = append(msgTypes, msgType)
+	}
+	sort.Strings(msgTypes)
+	return msgTypes
+}
+
+// converter returns the converter of msgType
+func (r *Registry) converter(msgType string) (*converter, error) {
+	if r == nil {
+		return nil, fmt.Errorf("registry is nil")
+	}
+
+	r.mu.RLock()
+	c, ok := r.converters[msgType]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnregisteredMessage, msgType)
+	}
+	return c, nil
+}
+
+// PackAny converts msg to protobuf and packs it with its type URL.
+//
+// INVARIANT: The packed bytes are deterministic.
+func (r *Registry) PackAny(msg types.Message) (*anypb.Any, error) {
+	if msg == nil {
+		return nil, fmt.Errorf("%w: message is nil", ErrInvalidMessage)
+	}
+
+	msgType := msg.Type()
+	c, err := r.converter(msgType)
+	if err != nil {
+		return nil, err
+	}
+	p, err := c.toProto(msg)
+	if err != nil {
+		return nil, err
+	}
+	value, err := proto.MarshalOptions{Deterministic: true}.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidMessage, msgType, err)
+	}
+	return &anypb.Any{TypeUrl: msgType, Value: value}, nil
+}
+
+// UnpackAny unpacks and converts a message. Type URLs with a host prefix
+// (e.g. "type.googleapis.com/punnet.bank.v1.MsgSend") are accepted.
+func (r *Registry) UnpackAny(a *anypb.Any) (types.Message, error) {
+	if a == nil {
+		return nil, fmt.Errorf("%w: any is nil", ErrInvalidMessage)
+	}
+
+	msgType := a.GetTypeUrl()
+	if i := strings.LastIndexByte(msgType, '/'); i >= 0 {
+		msgType = msgType[i:]
+	}
+	c, err := r.converter(msgType)
+	if err != nil {
+		return nil, err
+	}
+
+	p := c.newProto()
+	if err := proto.Unmarshal(a.GetValue(), p); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidMessage, msgType, err)
+	}
+	msg, err := c.fromProto(p)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil || msg.Type() != msgType {
+		return nil, fmt.Errorf("%w: converter for %s returned a different message type", ErrInvalidMessage, msgType)
+	}
+	return msg, nil
+}
@@ -0,0 +1,194 @@
+package protocodec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	typesv1 "github.com/blockberries/punnet-sdk/api/punnet/types/v1"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// testCoinType is the type URL of testMsg, the full name of typesv1.Coin
+const testCoinType = "/punnet.types.v1.Coin"
+
+// testMsg is a message whose protobuf form is typesv1.Coin
+type testMsg struct {
+	msgType string
+	coin    types.Coin
+}
+
+func (m *testMsg) Type() string {
+	if m.msgType != "" {
+		return m.msgType
+	}
+	return testCoinType
+}
+
+func (m *testMsg) ValidateBasic() error { return nil }
+
+func (m *testMsg) GetSigners() []types.AccountName { return []types.AccountName{"alice"} }
+
+func newTestRegistry(t *testing.T) *Registry {
+	t.Helper()
+	r := NewRegistry()
+	err := RegisterMessage(r,
+		func(m *testMsg) (*typesv1.Coin, error) {
+			if m.coin.Denom == "" {
+				return nil, fmt.Errorf("%w: empty denom", ErrInvalidMessage)
+			}
+			return CoinToProto(m.coin), nil
+		},
+		func(p *typesv1.Coin) (*testMsg, error) {
+			return &testMsg{coin: CoinFromProto(p)}, nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("RegisterMessage: %v", err)
+	}
+	return r
+}
+
+func TestRegisterMessage(t *testing.T) {
+	r := newTestRegistry(t)
+
+	if got := r.MessageTypes(); len(got) != 1 || got[0] != testCoinType {
+		t.Fatalf("MessageTypes() = %v, want [%s]", got, testCoinType)
+	}
+
+	err := RegisterMessage(r,
+		func(m *testMsg) (*typesv1.Coin, error) { return nil, nil },
+		func(p *typesv1.Coin) (*testMsg, error) { return nil, nil },
+	)
+	if err == nil {
+		t.Fatal("expected error for duplicate registration")
+	}
+
+	if err := RegisterMessage[*testMsg, *typesv1.Coin](r, nil, nil); err == nil {
+		t.Fatal("expected error for nil converters")
+	}
+	if err := RegisterMessage[*testMsg, *typesv1.Coin](nil, nil, nil); err == nil {
+		t.Fatal("expected error for nil registry")
+	}
+}
+
+func TestPackUnpackAny(t *testing.T) {
+	r := newTestRegistry(t)
+	msg := &testMsg{coin: types.NewCoin("stake", 42)}
+
+	a, err := r.PackAny(msg)
+	if err != nil {
+		t.Fatalf("PackAny: %v", err)
+	}
+	if a.GetTypeUrl() != testCoinType {
+		t.Fatalf("TypeUrl = %q, want %q", a.GetTypeUrl(), testCoinType)
+	}
+	again, err := r.PackAny(msg)
+	if err != nil {
+		t.Fatalf("PackAny: %v", err)
+	}
+	if !bytes.Equal(a.GetValue(), again.GetValue()) {
+		t.Fatal("PackAny is not deterministic")
+	}
+
+	for _, typeURL := range []string{testCoinType, "type.googleapis.com" + testCoinType} {
+		got, err := r.UnpackAny(&anypb.Any{TypeUrl: typeURL, Value: a.GetValue()})
+		if err != nil {
+			t.Fatalf("UnpackAny(%q): %v", typeURL, err)
+		}
+		if got.(*testMsg).coin != msg.coin {
+			t.Fatalf("UnpackAny(%q) = %+v, want %+v", typeURL, got, msg)
+		}
+	}
+}
+
+func TestPackUnpackAnyErrors(t *testing.T) {
+	r := newTestRegistry(t)
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr error
+	}{
+		{
+			name: "pack nil message",
+			run: func() error {
+				_, err := r.PackAny(nil)
+				return err
+			},
+			wantErr: ErrInvalidMessage,
+		},
+		{
+			name: "pack unregistered type",
+			run: func() error {
+				_, err := r.PackAny(&testMsg{msgType: "/punnet.test.v1.Unknown", coin: types.NewCoin("stake", 1)})
+				return err
+			},
+			wantErr: ErrUnregisteredMessage,
+		},
+		{
+			name: "pack converter error",
+			run: func() error {
+				_, err := r.PackAny(&testMsg{})
+				return err
+			},
+			wantErr: ErrInvalidMessage,
+		},
+		{
+			name: "unpack nil any",
+			run: func() error {
+				_, err := r.UnpackAny(nil)
+				return err
+			},
+			wantErr: ErrInvalidMessage,
+		},
+		{
+			name: "unpack unregistered type",
+			run: func() error {
+				_, err := r.UnpackAny(&anypb.Any{TypeUrl: "/punnet.test.v1.Unknown"})
+				return err
+			},
+			wantErr: ErrUnregisteredMessage,
+		},
+		{
+			name: "unpack malformed value",
+			run: func() error {
+				_, err := r.UnpackAny(&anypb.Any{TypeUrl: testCoinType, Value: []byte{0xff}})
+				return err
+			},
+			wantErr: ErrInvalidMessage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnpackAnyTypeMismatch(t *testing.T) {
+	r := NewRegistry()
+	err := RegisterMessage(r,
+		func(m *testMsg) (*typesv1.Coin, error) { return CoinToProto(m.coin), nil },
+		func(p *typesv1.Coin) (*testMsg, error) { return &testMsg{msgType: "/other"}, nil },
+	)
+	if err != nil {
+		t.Fatalf("RegisterMessage: %v", err)
+	}
+
+	value, err := proto.Marshal(&typesv1.Coin{Denom: "stake", Amount: 1})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if _, err := r.UnpackAny(&anypb.Any{TypeUrl: testCoinType, Value: value}); !errors.Is(err, ErrInvalidMessage) {
+		t.Fatalf("error = %v, want %v", err, ErrInvalidMessage)
+	}
+}
@@ -0,0 +1,212 @@
+package protocodec
+
+import (
+	"fmt"
+	"strconv"
+
+	"google.golang.org/protobuf/types/known/anypb"
+
+	typesv1 "github.com/blockberries/punnet-sdk/api/punnet/types/v1"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// TxToProto converts a transaction to protobuf, packing each message with
+// PackAny
+func (r *Registry) TxToProto(tx *types.Transaction) (*typesv1.Transaction, error) {
+	if tx == nil {
+		return nil, fmt.Errorf("%w: transaction is nil", types.ErrInvalidTransaction)
+	}
+
+	msgs := make([]*anypb.Any, len(tx.Messages))
+	for i, msg := range tx.Messages {
+		a, err := r.PackAny(msg)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		msgs[i] = a
+	}
+
+	return &typesv1.Transaction{
+		Account:       string(tx.Account),
+		Messages:      msgs,
+		Authorization: AuthorizationToProto(tx.Authorization),
+		Nonce:         tx.Nonce,
+		Memo:          tx.Memo,
+		Fee:           feeToProto(tx.Fee),
+		FeeSlippage:   ratioToProto(tx.FeeSlippage),
+
+		FeePayer:              string(tx.FeePayer),
+		FeeGranter:            string(tx.FeeGranter),
+		FeePayerAuthorization: AuthorizationToProto(tx.FeePayerAuthorization),
+		Tip:                   tipToProto(tx.Tip),
+		CoSigners:             coSignersToProto(tx.CoSigners),
+	}, nil
+}
+
+// TxFromProto converts a protobuf transaction, unpacking each message with
+// UnpackAny.
+//
+// SECURITY: The result is not validated; run ValidateBasic and the
+// authorization checks on it as for a transaction from the TxDecoder.
+func (r *Registry) TxFromProto(pbTx *typesv1.Transaction) (*types.Transaction, error) {
+	if pbTx == nil {
+		return nil, fmt.Errorf("%w: transaction is nil", types.ErrInvalidTransaction)
+	}
+	if len(pbTx.GetMessages()) > types.MaxMessagesPerSignDoc {
+		return nil, fmt.Errorf("%w: too many messages: %d > %d", types.ErrInvalidTransaction, len(pbTx.GetMessages()), types.MaxMessagesPerSignDoc)
+	}
+
+	msgs := make([]types.Message, len(pbTx.GetMessages()))
+	for i, a := range pbTx.GetMessages() {
+		msg, err := r.UnpackAny(a)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i, err)
+		}
+		msgs[i] = msg
+	}
+
+	tx := types.NewTransaction(types.AccountName(pbTx.GetAccount()), pbTx.GetNonce(), msgs, AuthorizationFromProto(pbTx.GetAuthorization()))
+	tx.Memo = pbTx.GetMemo()
+	tx.Fee = feeFromProto(pbTx.GetFee())
+	tx.FeeSlippage = ratioFromProto(pbTx.GetFeeSlippage())
+	tx.FeePayer = types.AccountName(pbTx.GetFeePayer())
+	tx.FeeGranter = types.AccountName(pbTx.GetFeeGranter())
+	tx.FeePayerAuthorization = AuthorizationFromProto(pbTx.GetFeePayerAuthorization())
+	tx.Tip = tipFromProto(pbTx.GetTip())
+	tx.CoSigners = coSignersFromProto(pbTx.GetCoSigners())
+	return tx, nil
+}
+
+// SignBytes returns the bytes the signatures of a protobuf transaction must
+// cover: the hash of the canonical JSON SignDoc of the converted transaction.
+func (r *Registry) SignBytes(pbTx *typesv1.Transaction, chainID string, accountSequence uint64) ([]byte, error) {
+	tx, err := r.TxFromProto(pbTx)
+	if err != nil {
+		return nil, err
+	}
+	signDoc, err := tx.ToSignDoc(chainID, accountSequence)
+	if err != nil {
+		return nil, err
+	}
+	return signDoc.GetSignBytes()
+}
+
+// SignDocToProto converts a SignDoc to protobuf. Message data is carried as
+// its canonical JSON bytes.
+//
+// Returns an error if a decimal string field is malformed.
+func SignDocToProto(sd *types.SignDoc) (*typesv1.SignDoc, error) {
+	if sd == nil {
+		return nil, fmt.Errorf("%w: sign doc is nil", types.ErrInvalidTransaction)
+	}
+
+	fee := &typesv1.Fee{Amount: make([]*typesv1.Coin, len(sd.Fee.Amount))}
+	for i, c := range sd.Fee.Amount {
+		amount, err := parseUint("fee amount", c.Amount)
+		if err != nil {
+			return nil, err
+		}
+		fee.Amount[i] = &typesv1.Coin{Denom: c.Denom, Amount: amount}
+	}
+	gasLimit, err := parseUint("gas limit", sd.Fee.GasLimit)
+	if err != nil {
+		return nil, err
+	}
+	fee.GasLimit = gasLimit
+
+	numerator, err := parseUint("fee slippage numerator", sd.FeeSlippage.Numerator)
+	if err != nil {
+		return nil, err
+	}
+	denominator, err := parseUint("fee slippage denominator", sd.FeeSlippage.Denominator)
+	if err != nil {
+		return nil, err
+	}
+
+	var tip *typesv1.Coin
+	if sd.Tip != nil {
+		amount, err := parseUint("tip amount", sd.Tip.Amount)
+		if err != nil {
+			return nil, err
+		}
+		tip = &typesv1.Coin{Denom: sd.Tip.Denom, Amount: amount}
+	}
+
+	msgs := make([]*typesv1.SignDocMessage, len(sd.Messages))
+	for i, msg := range sd.Messages {
+		msgs[i] = &typesv1.SignDocMessage{Type: msg.Type, Data: append([]byte(nil), msg.Data...)}
+	}
+
+	return &typesv1.SignDoc{
+		Version:         sd.Version,
+		ChainId:         sd.ChainID,
+		Account:         sd.Account,
+		AccountSequence: sd.AccountSequence.Uint64(),
+		Messages:        msgs,
+		Nonce:           sd.Nonce.Uint64(),
+		Memo:            sd.Memo,
+		Fee:             fee,
+		FeeSlippage:     &typesv1.Ratio{Numerator: numerator, Denominator: denominator},
+		NotBefore:       boundToProto(sd.NotBefore),
+		NotAfter:        boundToProto(sd.NotAfter),
+		FeePayer:        sd.FeePayer,
+		FeeGranter:      sd.FeeGranter,
+		Tip:             tip,
+		Signers:         append([]string(nil), sd.Signers...),
+	}, nil
+}
+
+// SignDocFromProto converts a protobuf SignDoc.
+//
+// INVARIANT: SignDocFromProto(SignDocToProto(sd)) serializes to the same
+// canonical JSON as sd.
+func SignDocFromProto(pbDoc *typesv1.SignDoc) *types.SignDoc {
+	fee := types.SignDocFee{
+		Amount:   make([]types.SignDocCoin, len(pbDoc.GetFee().GetAmount())),
+		GasLimit: strconv.FormatUint(pbDoc.GetFee().GetGasLimit(), 10),
+	}
+	for i, c := range pbDoc.GetFee().GetAmount() {
+		fee.Amount[i] = types.SignDocCoin{Denom: c.GetDenom(), Amount: strconv.FormatUint(c.GetAmount(), 10)}
+	}
+	feeSlippage := types.SignDocRatio{
+		Numerator:   strconv.FormatUint(pbDoc.GetFeeSlippage().GetNumerator(), 10),
+		Denominator: strconv.FormatUint(pbDoc.GetFeeSlippage().GetDenominator(), 10),
+	}
+
+	// Built field by field rather than with NewSignDocWithFee: the chain ID
+	// is converted as received and checked by ValidateBasic
+	sd := &types.SignDoc{
+		Version:         pbDoc.GetVersion(),
+		ChainID:         pbDoc.GetChainId(),
+		AccountSequence: types.StringUint64(pbDoc.GetAccountSequence()),
+		Account:         pbDoc.GetAccount(),
+		Nonce:           types.StringUint64(pbDoc.GetNonce()),
+		Memo:            pbDoc.GetMemo(),
+		Messages:        make([]types.SignDocMessage, 0),
+		Fee:             fee,
+		FeeSlippage:     feeSlippage,
+	}
+	for _, msg := range pbDoc.GetMessages() {
+		sd.AddMessage(msg.GetType(), append([]byte(nil), msg.GetData()...))
+	}
+	sd.NotBefore = boundFromProto(pbDoc.GetNotBefore())
+	sd.NotAfter = boundFromProto(pbDoc.GetNotAfter())
+	sd.FeePayer = pbDoc.GetFeePayer()
+	sd.FeeGranter = pbDoc.GetFeeGranter()
+	if tip := pbDoc.GetTip(); tip != nil {
+		sd.Tip = &types.SignDocCoin{Denom: tip.GetDenom(), Amount: strconv.FormatUint(tip.GetAmount(), 10)}
+	}
+	if signers := pbDoc.GetSigners(); len(signers) > 0 {
+		sd.Signers = append([]string(nil), signers...)
+	}
+	return sd
+}
+
+// parseUint parses a SignDoc decimal string field
+func parseUint(field, s string) (uint64, error) {
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid %s %q", types.ErrInvalidTransaction, field, s)
+	}
+	return v, nil
+}
@@ -0,0 +1,224 @@
+package protocodec
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	typesv1 "github.com/blockberries/punnet-sdk/api/punnet/types/v1"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+func testAuthorization() *types.Authorization {
+	return &types.Authorization{
+		Signatures: []types.Signature{
+			{Algorithm: types.AlgorithmEd25519, PubKey: []byte{1, 2}, Signature: []byte{3, 4}},
+			{
+				Algorithm: types.AlgorithmWebAuthn,
+				PubKey:    []byte{5},
+				Signature: []byte{6},
+				WebAuthn: &types.WebAuthnAssertion{
+					AuthenticatorData: []byte{7},
+					ClientDataJSON:    []byte(`{"type":"webauthn.get"}`),
+				},
+			},
+		},
+		AccountAuthorizations: map[types.AccountName]*types.Authorization{
+			"bob": {Signatures: []types.Signature{{PubKey: []byte{8}, Signature: []byte{9}}}},
+		},
+		Session: &types.SessionAuthorization{
+			Grant: types.SessionGrant{
+				ChainID:             "test-chain",
+				Account:             "alice",
+				SessionAlgorithm:    types.AlgorithmEd25519,
+				SessionPubKey:       []byte{10},
+				AllowedMessageTypes: []string{testCoinType},
+				ExpirationHeight:    100,
+			},
+			GrantAuthorization: &types.Authorization{Signatures: []types.Signature{{PubKey: []byte{11}, Signature: []byte{12}}}},
+			Signature:          types.Signature{PubKey: []byte{13}, Signature: []byte{14}},
+		},
+		NotBefore: &types.ValidityBound{Height: 5},
+		NotAfter:  &types.ValidityBound{Time: 1700000000},
+	}
+}
+
+// wireRoundTrip marshals and unmarshals a protobuf message
+func wireRoundTrip[P proto.Message](t *testing.T, p P, out P) P {
+	t.Helper()
+	data, err := proto.Marshal(p)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if err := proto.Unmarshal(data, out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	return out
+}
+
+func TestAuthorizationRoundTrip(t *testing.T) {
+	auth := testAuthorization()
+
+	pb := wireRoundTrip(t, AuthorizationToProto(auth), &typesv1.Authorization{})
+	got := AuthorizationFromProto(pb)
+	if !reflect.DeepEqual(got, auth) {
+		t.Fatalf("round trip = %+v, want %+v", got, auth)
+	}
+
+	if AuthorizationToProto(nil) != nil || AuthorizationFromProto(nil) != nil {
+		t.Fatal("nil authorization must convert to nil")
+	}
+}
+
+func TestAuthorityRoundTrip(t *testing.T) {
+	authority := types.Authority{
+		Threshold:      2,
+		KeyWeights:     map[string]uint64{"key": 1},
+		AccountWeights: map[types.AccountName]uint64{"bob": 1},
+	}
+
+	pb := wireRoundTrip(t, AuthorityToProto(authority), &typesv1.Authority{})
+	if got := AuthorityFromProto(pb); !reflect.DeepEqual(got, authority) {
+		t.Fatalf("round trip = %+v, want %+v", got, authority)
+	}
+
+	empty := AuthorityFromProto(nil)
+	if empty.KeyWeights == nil || empty.AccountWeights == nil {
+		t.Fatal("weight maps must not be nil")
+	}
+}
+
+func TestTxRoundTrip(t *testing.T) {
+	r := newTestRegistry(t)
+	tx := types.NewTransaction("alice", 7, []types.Message{
+		&testMsg{coin: types.NewCoin("stake", 1)},
+		&testMsg{coin: types.NewCoin("atom", 2)},
+	}, testAuthorization())
+	tx.Memo = "memo"
+	tx.Fee = types.Fee{Amount: types.NewCoins(types.NewCoin("stake", 10)), GasLimit: 1000}
+	tx.FeeSlippage = types.Ratio{Numerator: 1, Denominator: 100}
+	tx.FeePayer = "bob"
+	tx.FeeGranter = "carol"
+	tx.FeePayerAuthorization = testAuthorization()
+	tip := types.NewCoin("stake", 3)
+	tx.Tip = &tip
+	tx.CoSigners = []types.CoSigner{{Account: "dave", Nonce: 4, Authorization: testAuthorization()}}
+
+	pbTx, err := r.TxToProto(tx)
+	if err != nil {
+		t.Fatalf("TxToProto: %v", err)
+	}
+	got, err := r.TxFromProto(wireRoundTrip(t, pbTx, &typesv1.Transaction{}))
+	if err != nil {
+		t.Fatalf("TxFromProto: %v", err)
+	}
+
+	if got.Account != tx.Account || got.Nonce != tx.Nonce || got.Memo != tx.Memo {
+		t.Fatalf("header = %s/%d/%q, want %s/%d/%q", got.Account, got.Nonce, got.Memo, tx.Account, tx.Nonce, tx.Memo)
+	}
+	if !reflect.DeepEqual(got.Fee, tx.Fee) || got.FeeSlippage != tx.FeeSlippage {
+		t.Fatalf("fee = %+v/%+v, want %+v/%+v", got.Fee, got.FeeSlippage, tx.Fee, tx.FeeSlippage)
+	}
+	if !reflect.DeepEqual(got.Authorization, tx.Authorization) {
+		t.Fatalf("authorization = %+v, want %+v", got.Authorization, tx.Authorization)
+	}
+	if got.Tip == nil || *got.Tip != *tx.Tip {
+		t.Fatalf("tip = %v, want %v", got.Tip, tx.Tip)
+	}
+	if got.FeePayer != tx.FeePayer || got.FeeGranter != tx.FeeGranter {
+		t.Fatalf("fee sponsors = %s/%s, want %s/%s", got.FeePayer, got.FeeGranter, tx.FeePayer, tx.FeeGranter)
+	}
+	if !reflect.DeepEqual(got.FeePayerAuthorization, tx.FeePayerAuthorization) {
+		t.Fatalf("fee payer authorization = %+v, want %+v", got.FeePayerAuthorization, tx.FeePayerAuthorization)
+	}
+	if !reflect.DeepEqual(got.CoSigners, tx.CoSigners) {
+		t.Fatalf("co-signers = %+v, want %+v", got.CoSigners, tx.CoSigners)
+	}
+	if len(got.Messages) != len(tx.Messages) {
+		t.Fatalf("got %d messages, want %d", len(got.Messages), len(tx.Messages))
+	}
+	for i := range tx.Messages {
+		if got.Messages[i].(*testMsg).coin != tx.Messages[i].(*testMsg).coin {
+			t.Fatalf("message %d = %+v, want %+v", i, got.Messages[i], tx.Messages[i])
+		}
+	}
+
+	// The protobuf transport must not change what the signatures cover
+	signDoc, err := tx.ToSignDoc("test-chain", 3)
+	if err != nil {
+		t.Fatalf("ToSignDoc: %v", err)
+	}
+	want, err := signDoc.GetSignBytes()
+	if err != nil {
+		t.Fatalf("GetSignBytes: %v", err)
+	}
+	signBytes, err := r.SignBytes(pbTx, "test-chain", 3)
+	if err != nil {
+		t.Fatalf("SignBytes: %v", err)
+	}
+	if !bytes.Equal(signBytes, want) {
+		t.Fatal("SignBytes differs from the SDK transaction's sign bytes")
+	}
+}
+
+func TestTxFromProtoErrors(t *testing.T) {
+	r := newTestRegistry(t)
+
+	if _, err := r.TxFromProto(nil); !errors.Is(err, types.ErrInvalidTransaction) {
+		t.Fatalf("error = %v, want %v", err, types.ErrInvalidTransaction)
+	}
+
+	pbTx := &typesv1.Transaction{Account: "alice"}
+	pbTx.Messages = append(pbTx.Messages, nil)
+	if _, err := r.TxFromProto(pbTx); !errors.Is(err, ErrInvalidMessage) {
+		t.Fatalf("error = %v, want %v", err, ErrInvalidMessage)
+	}
+
+	if _, err := r.TxToProto(types.NewTransaction("alice", 0, []types.Message{&testMsg{msgType: "/unknown"}}, nil)); !errors.Is(err, ErrUnregisteredMessage) {
+		t.Fatalf("error = %v, want %v", err, ErrUnregisteredMessage)
+	}
+}
+
+func TestSignDocRoundTrip(t *testing.T) {
+	fee := types.SignDocFee{
+		Amount:   []types.SignDocCoin{{Denom: "stake", Amount: "18446744073709551615"}},
+		GasLimit: "200000",
+	}
+	sd, err := types.NewSignDocWithFee("test-chain", 3, "alice", 7, "memo", fee, types.SignDocRatio{Numerator: "1", Denominator: "100"})
+	if err != nil {
+		t.Fatalf("NewSignDoc failed: %v", err)
+	}
+	sd.AddMessage(testCoinType, []byte(`{"signers":["alice"]}`))
+	sd.NotAfter = &types.ValidityBound{Height: 50}
+	sd.FeePayer = "bob"
+	sd.FeeGranter = "carol"
+	sd.Version = types.SignDocVersionTip
+	sd.Tip = &types.SignDocCoin{Denom: "stake", Amount: "5"}
+	sd.Signers = []string{"alice", "dave"}
+
+	pb, err := SignDocToProto(sd)
+	if err != nil {
+		t.Fatalf("SignDocToProto: %v", err)
+	}
+	got := SignDocFromProto(wireRoundTrip(t, pb, &typesv1.SignDoc{}))
+
+	want, err := sd.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON: %v", err)
+	}
+	gotJSON, err := got.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON: %v", err)
+	}
+	if !bytes.Equal(gotJSON, want) {
+		t.Fatalf("round trip JSON = %s, want %s", gotJSON, want)
+	}
+
+	sd.Fee.GasLimit = "-1"
+	if _, err := SignDocToProto(sd); !errors.Is(err, types.ErrInvalidTransaction) {
+		t.Fatalf("error = %v, want %v", err, types.ErrInvalidTransaction)
+	}
+}
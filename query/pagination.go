@@ -0,0 +1,149 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/store"
+)
+
+// Pagination limits
+const (
+	// DefaultLimit is used when a PageRequest has no limit
+	DefaultLimit = uint64(100)
+
+	// MaxLimit is the largest page a PageRequest may ask for
+	MaxLimit = uint64(1000)
+)
+
+// ErrInvalidPagination is returned for malformed page requests
+var ErrInvalidPagination = errors.New("invalid pagination")
+
+// PageRequest selects a page of a key range.
+//
+// A page starts either at Key (the NextKey of a previous page) or after
+// skipping Offset entries; Key and Offset are mutually exclusive. Key-based
+// paging is O(limit); offset-based paging is O(offset+limit).
+type PageRequest struct {
+	// Key is the first key of the page (relative to the paginated store)
+	Key []byte `json:"key,omitempty"`
+
+	// Offset is the number of entries to skip
+	Offset uint64 `json:"offset,omitempty"`
+
+	// Limit is the maximum number of entries (DefaultLimit if 0)
+	Limit uint64 `json:"limit,omitempty"`
+
+	// CountTotal requests the total number of entries (offset-based paging only)
+	CountTotal bool `json:"count_total,omitempty"`
+
+	// Reverse iterates in descending key order
+	Reverse bool `json:"reverse,omitempty"`
+}
+
+// ValidateBasic performs basic validation
+func (p *PageRequest) ValidateBasic() error {
+	if p == nil {
+		return nil
+	}
+	if len(p.Key) > 0 && p.Offset > 0 {
+		return fmt.Errorf("%w: key and offset cannot both be set", ErrInvalidPagination)
+	}
+	if len(p.Key) > 0 && p.CountTotal {
+		return fmt.Errorf("%w: count_total requires offset-based paging", ErrInvalidPagination)
+	}
+	if p.Limit > MaxLimit {
+		return fmt.Errorf("%w: limit %d exceeds maximum %d", ErrInvalidPagination, p.Limit, MaxLimit)
+	}
+	return nil
+}
+
+// limit returns the effective page size
+func (p *PageRequest) limit() uint64 {
+	if p == nil || p.Limit == 0 {
+		return DefaultLimit
+	}
+	return p.Limit
+}
+
+// PageResponse describes the position after a page
+type PageResponse struct {
+	// NextKey is the Key of the next page, or nil if this is the last page
+	NextKey []byte `json:"next_key,omitempty"`
+
+	// Total is the total number of entries, if CountTotal was requested
+	Total uint64 `json:"total,omitempty"`
+}
+
+// Paginate iterates one page of s and calls onResult for each entry in order.
+// Wrap s in a store.PrefixStore to paginate a single namespace. A nil req
+// returns the first DefaultLimit entries.
+//
+// Complexity: O(limit) with Key, O(offset+limit) with Offset, O(n) with CountTotal
+func Paginate(s store.BackingStore, req *PageRequest, onResult func(key, value []byte) error) (*PageResponse, error) {
+	if s == nil {
+		return nil, store.ErrStoreNil
+	}
+	if onResult == nil {
+		return nil, fmt.Errorf("callback cannot be nil")
+	}
+	if err := req.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	if req == nil {
+		req = &PageRequest{}
+	}
+
+	var (
+		iter store.RawIterator
+		err  error
+	)
+	switch {
+	case req.Reverse && len(req.Key) > 0:
+		// The end bound is exclusive; the smallest key after Key is Key+0x00
+		iter, err = s.ReverseIterator(nil, append(append([]byte{}, req.Key...), 0))
+	case req.Reverse:
+		iter, err = s.ReverseIterator(nil, nil)
+	default:
+		iter, err = s.Iterator(req.Key, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer iter.Close()
+
+	limit := req.limit()
+	resp := &PageResponse{}
+
+	// seen counts entries visited, including skipped ones
+	var seen, returned uint64
+	for ; iter.Valid(); iter.Next() {
+		seen++
+		if seen <= req.Offset {
+			continue
+		}
+
+		if returned == limit {
+			if resp.NextKey == nil {
+				resp.NextKey = append([]byte{}, iter.Key()...)
+			}
+			if !req.CountTotal {
+				break
+			}
+			continue
+		}
+
+		if err := onResult(iter.Key(), iter.Value()); err != nil {
+			return nil, err
+		}
+		returned++
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to iterate: %w", err)
+	}
+
+	if req.CountTotal {
+		resp.Total = seen
+	}
+	return resp, nil
+}
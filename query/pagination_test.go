@@ -0,0 +1,118 @@
+package query
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/stretchr/testify/require"
+)
+
+// newPagedStore returns a store with keys k00..k<n-1>
+func newPagedStore(t *testing.T, n int) store.BackingStore {
+	t.Helper()
+
+	s := store.NewMemoryStore()
+	for i := 0; i < n; i++ {
+		require.NoError(t, s.Set([]byte(fmt.Sprintf("k%02d", i)), []byte(fmt.Sprintf("v%02d", i))))
+	}
+	return s
+}
+
+// collect runs Paginate and returns the visited keys
+func collect(t *testing.T, s store.BackingStore, req *PageRequest) ([]string, *PageResponse) {
+	t.Helper()
+
+	keys := make([]string, 0)
+	resp, err := Paginate(s, req, func(key, value []byte) error {
+		keys = append(keys, string(key))
+		return nil
+	})
+	require.NoError(t, err)
+	return keys, resp
+}
+
+func TestPaginate_KeyBased(t *testing.T) {
+	s := newPagedStore(t, 5)
+
+	keys, resp := collect(t, s, &PageRequest{Limit: 2})
+	require.Equal(t, []string{"k00", "k01"}, keys)
+	require.Equal(t, []byte("k02"), resp.NextKey)
+
+	keys, resp = collect(t, s, &PageRequest{Key: resp.NextKey, Limit: 2})
+	require.Equal(t, []string{"k02", "k03"}, keys)
+
+	keys, resp = collect(t, s, &PageRequest{Key: resp.NextKey, Limit: 2})
+	require.Equal(t, []string{"k04"}, keys)
+	require.Nil(t, resp.NextKey)
+}
+
+func TestPaginate_Reverse(t *testing.T) {
+	s := newPagedStore(t, 5)
+
+	keys, resp := collect(t, s, &PageRequest{Limit: 2, Reverse: true})
+	require.Equal(t, []string{"k04", "k03"}, keys)
+	require.Equal(t, []byte("k02"), resp.NextKey)
+
+	keys, resp = collect(t, s, &PageRequest{Key: resp.NextKey, Limit: 2, Reverse: true})
+	require.Equal(t, []string{"k02", "k01"}, keys)
+
+	keys, resp = collect(t, s, &PageRequest{Key: resp.NextKey, Limit: 2, Reverse: true})
+	require.Equal(t, []string{"k00"}, keys)
+	require.Nil(t, resp.NextKey)
+}
+
+func TestPaginate_OffsetAndTotal(t *testing.T) {
+	s := newPagedStore(t, 5)
+
+	keys, resp := collect(t, s, &PageRequest{Offset: 3, Limit: 1, CountTotal: true})
+	require.Equal(t, []string{"k03"}, keys)
+	require.Equal(t, []byte("k04"), resp.NextKey)
+	require.Equal(t, uint64(5), resp.Total)
+
+	keys, resp = collect(t, s, &PageRequest{Offset: 10})
+	require.Empty(t, keys)
+	require.Nil(t, resp.NextKey)
+}
+
+func TestPaginate_DefaultLimit(t *testing.T) {
+	s := newPagedStore(t, int(DefaultLimit)+1)
+
+	keys, resp := collect(t, s, nil)
+	require.Len(t, keys, int(DefaultLimit))
+	require.NotNil(t, resp.NextKey)
+}
+
+func TestPaginate_PrefixStore(t *testing.T) {
+	s := newPagedStore(t, 3)
+	require.NoError(t, s.Set([]byte("other"), []byte("x")))
+
+	keys, _ := collect(t, store.NewPrefixStore(s, []byte("k")), &PageRequest{})
+	require.Equal(t, []string{"00", "01", "02"}, keys)
+}
+
+func TestPaginate_Invalid(t *testing.T) {
+	s := newPagedStore(t, 1)
+	noop := func(key, value []byte) error { return nil }
+
+	tests := []struct {
+		name string
+		req  *PageRequest
+	}{
+		{"key and offset", &PageRequest{Key: []byte("k00"), Offset: 1}},
+		{"key and count total", &PageRequest{Key: []byte("k00"), CountTotal: true}},
+		{"limit too large", &PageRequest{Limit: MaxLimit + 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Paginate(s, tt.req, noop)
+			require.ErrorIs(t, err, ErrInvalidPagination)
+		})
+	}
+
+	_, err := Paginate(nil, nil, noop)
+	require.ErrorIs(t, err, store.ErrStoreNil)
+
+	_, err = Paginate(s, nil, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,323 @@
+// Package query serves read-only queries against application state.
+//
+// Modules register Handlers by path with a Server. Each query runs against
+// a store view pinned to the requested height: the live store for the latest
+// height, or a historical view when the backing store implements
+// HistoricalStore. The built-in StoreKeyPath returns a raw value, with a
+// Merkle proof when requested and the store implements ProvableStore.
+// Handlers page through key ranges with Paginate.
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	ics23 "github.com/cosmos/ics23/go"
+
+	sdkerrors "github.com/blockberries/punnet-sdk/errors"
+	"github.com/blockberries/punnet-sdk/store"
+)
+
+var (
+	// ErrHandlerNotFound is returned when no handler is registered for a path
+	ErrHandlerNotFound = errors.New("query handler not found")
+
+	// ErrDuplicateHandler is returned when a path is registered twice
+	ErrDuplicateHandler = errors.New("query handler already registered")
+
+	// ErrHeightNotAvailable is returned when the requested height cannot be served
+	ErrHeightNotAvailable = errors.New("height not available")
+
+	// ErrProofNotSupported is returned when a proof is requested but cannot be produced
+	ErrProofNotSupported = errors.New("proof not supported")
+)
+
+// CodespaceQuery is the result codespace of query server errors
+const CodespaceQuery = "query"
+
+func init() {
+	sdkerrors.MustRegister(CodespaceQuery, 2, ErrHandlerNotFound)
+	sdkerrors.MustRegister(CodespaceQuery, 3, ErrHeightNotAvailable)
+	sdkerrors.MustRegister(CodespaceQuery, 4, ErrProofNotSupported)
+	sdkerrors.MustRegister(CodespaceQuery, 5, ErrInvalidPagination)
+}
+
+// StoreKeyPath is the built-in path for raw key lookups. The request data is
+// the key; the response value is the stored value (nil if absent).
+const StoreKeyPath = "/store/key"
+
+// VersionedStore is implemented by stores that know their latest committed version
+type VersionedStore interface {
+	Version() int64
+}
+
+// HistoricalStore is implemented by stores that can serve reads at past versions
+type HistoricalStore interface {
+	VersionedStore
+
+	// ReadAt returns a read-only view of the store at version
+	ReadAt(version int64) (store.BackingStore, error)
+}
+
+// ProvableStore is implemented by stores that produce Merkle proofs
+type ProvableStore interface {
+	// GetVersionedProof returns an existence or non-existence proof for key at version
+	GetVersionedProof(key []byte, version int64) (*ics23.CommitmentProof, error)
+}
+
+// Request is a query request
+type Request struct {
+	// Path selects the handler
+	Path string `json:"path"`
+
+	// Data is the handler-specific request payload
+	Data []byte `json:"data,omitempty"`
+
+	// Height pins the read to a committed height (0 means latest)
+	Height int64 `json:"height,omitempty"`
+
+	// Prove requests a Merkle proof (StoreKeyPath only)
+	Prove bool `json:"prove,omitempty"`
+}
+
+// Response is a query response
+type Response struct {
+	// Value is the handler result
+	Value []byte `json:"value,omitempty"`
+
+	// Height is the height the query was served at
+	Height int64 `json:"height"`
+
+	// Proof is the Merkle proof of Value, if requested
+	Proof *ics23.CommitmentProof `json:"proof,omitempty"`
+}
+
+// Context is passed to query handlers
+type Context struct {
+	ctx    context.Context
+	store  store.BackingStore
+	height int64
+}
+
+// Context returns the underlying Go context
+func (c *Context) Context() context.Context {
+	if c == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// Store returns the state store pinned at Height. Handlers must only read from it.
+func (c *Context) Store() store.BackingStore {
+	if c == nil {
+		return nil
+	}
+	return c.store
+}
+
+// Height returns the height the query is served at
+func (c *Context) Height() int64 {
+	if c == nil {
+		return 0
+	}
+	return c.height
+}
+
+// Handler handles a query against a pinned store view
+type Handler func(ctx *Context, data []byte) ([]byte, error)
+
+// Registrar is implemented by modules that register query handlers
+type Registrar interface {
+	RegisterQueryServices(server *Server) error
+}
+
+// Server routes queries to registered handlers.
+// Safe for concurrent use.
+type Server struct {
+	mu       sync.RWMutex
+	store    store.BackingStore
+	handlers map[string]Handler
+}
+
+// NewServer creates a query server reading from s
+func NewServer(s store.BackingStore) (*Server, error) {
+	if s == nil {
+		return nil, store.ErrStoreNil
+	}
+
+	return &Server{
+		store:    s,
+		handlers: make(map[string]Handler),
+	}, nil
+}
+
+// RegisterHandler registers a handler for path
+func (s *Server) RegisterHandler(path string, handler Handler) error {
+	if s == nil {
+		return fmt.Errorf("query server is nil")
+	}
+	if path == "" {
+		return fmt.Errorf("query path cannot be empty")
+	}
+	if handler == nil {
+		return fmt.Errorf("handler cannot be nil for query path %s", path)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.handlers[path]; exists || path == StoreKeyPath {
+		return fmt.Errorf("%w: %s", ErrDuplicateHandler, path)
+	}
+	s.handlers[path] = handler
+	return nil
+}
+
+// HasHandler reports whether path can be served
+func (s *Server) HasHandler(path string) bool {
+	if s == nil {
+		return false
+	}
+	if path == StoreKeyPath {
+		return true
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, exists := s.handlers[path]
+	return exists
+}
+
+// Paths returns the registered paths (sorted for determinism)
+func (s *Server) Paths() []string {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	paths := make([]string, 0, len(s.handlers))
+	for path := range s.handlers {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Query serves req
+func (s *Server) Query(ctx context.Context, req Request) (*Response, error) {
+	if s == nil {
+		return nil, fmt.Errorf("query server is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+	if req.Path == "" {
+		return nil, fmt.Errorf("query path cannot be empty")
+	}
+	if req.Height < 0 {
+		return nil, fmt.Errorf("%w: negative height %d", ErrHeightNotAvailable, req.Height)
+	}
+
+	view, height, err := s.storeAt(req.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Path == StoreKeyPath {
+		return s.queryKey(view, height, req)
+	}
+
+	s.mu.RLock()
+	handler, exists := s.handlers[req.Path]
+	s.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrHandlerNotFound, req.Path)
+	}
+	if req.Prove {
+		return nil, fmt.Errorf("%w: path %s", ErrProofNotSupported, req.Path)
+	}
+
+	value, err := handler(&Context{ctx: ctx, store: view, height: height}, req.Data)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{Value: value, Height: height}, nil
+}
+
+// storeAt returns the store view for height and the height it represents
+func (s *Server) storeAt(height int64) (store.BackingStore, int64, error) {
+	versioned, ok := s.store.(VersionedStore)
+	if !ok {
+		if height != 0 {
+			return nil, 0, fmt.Errorf("%w: store is not versioned", ErrHeightNotAvailable)
+		}
+		return s.store, 0, nil
+	}
+
+	latest := versioned.Version()
+	if height == 0 || height == latest {
+		return s.store, latest, nil
+	}
+	if height > latest {
+		return nil, 0, fmt.Errorf("%w: height %d is above latest height %d", ErrHeightNotAvailable, height, latest)
+	}
+
+	historical, ok := s.store.(HistoricalStore)
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: store does not support historical reads", ErrHeightNotAvailable)
+	}
+	view, err := historical.ReadAt(height)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", ErrHeightNotAvailable, err)
+	}
+	return view, height, nil
+}
+
+// queryKey serves StoreKeyPath
+func (s *Server) queryKey(view store.BackingStore, height int64, req Request) (*Response, error) {
+	if len(req.Data) == 0 {
+		return nil, fmt.Errorf("query key cannot be empty")
+	}
+
+	var provable ProvableStore
+	if req.Prove {
+		var ok bool
+		provable, ok = s.store.(ProvableStore)
+		if !ok {
+			return nil, fmt.Errorf("%w: store cannot produce proofs", ErrProofNotSupported)
+		}
+
+		// SECURITY: The proof is made against the saved version, so the
+		// value is read from it too. At the latest height the view is the
+		// working store, whose uncommitted writes the proof does not cover.
+		if historical, ok := s.store.(HistoricalStore); ok {
+			saved, err := historical.ReadAt(height)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrHeightNotAvailable, err)
+			}
+			view = saved
+		}
+	}
+
+	value, err := view.Get(req.Data)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return nil, err
+	}
+	resp := &Response{Value: value, Height: height}
+
+	if !req.Prove {
+		return resp, nil
+	}
+	proof, err := provable.GetVersionedProof(req.Data, height)
+	if err != nil {
+		return nil, err
+	}
+	resp.Proof = proof
+	return resp, nil
+}
@@ -0,0 +1,179 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	ics23 "github.com/cosmos/ics23/go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/store"
+)
+
+// historicalStore is a memory store that keeps a copy of each saved version
+type historicalStore struct {
+	*store.MemoryStore
+	versions map[int64]*store.MemoryStore
+	version  int64
+}
+
+func newHistoricalStore() *historicalStore {
+	return &historicalStore{
+		MemoryStore: store.NewMemoryStore(),
+		versions:    make(map[int64]*store.MemoryStore),
+	}
+}
+
+func (h *historicalStore) save(t *testing.T) {
+	t.Helper()
+
+	snapshot := store.NewMemoryStore()
+	iter, err := h.Iterator(nil, nil)
+	require.NoError(t, err)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		require.NoError(t, snapshot.Set(iter.Key(), iter.Value()))
+	}
+	h.version++
+	h.versions[h.version] = snapshot
+}
+
+func (h *historicalStore) Version() int64 {
+	return h.version
+}
+
+func (h *historicalStore) ReadAt(version int64) (store.BackingStore, error) {
+	snapshot, ok := h.versions[version]
+	if !ok {
+		return nil, fmt.Errorf("version %d pruned", version)
+	}
+	return snapshot, nil
+}
+
+// getHandler returns the value of the key in data
+func getHandler(ctx *Context, data []byte) ([]byte, error) {
+	return ctx.Store().Get(data)
+}
+
+func TestServer_RegisterHandler(t *testing.T) {
+	_, err := NewServer(nil)
+	require.ErrorIs(t, err, store.ErrStoreNil)
+
+	server, err := NewServer(store.NewMemoryStore())
+	require.NoError(t, err)
+
+	require.NoError(t, server.RegisterHandler("/bank/balance", getHandler))
+	require.ErrorIs(t, server.RegisterHandler("/bank/balance", getHandler), ErrDuplicateHandler)
+	require.ErrorIs(t, server.RegisterHandler(StoreKeyPath, getHandler), ErrDuplicateHandler)
+	require.Error(t, server.RegisterHandler("", getHandler))
+	require.Error(t, server.RegisterHandler("/nil", nil))
+
+	require.Equal(t, []string{"/bank/balance"}, server.Paths())
+	require.True(t, server.HasHandler("/bank/balance"))
+	require.True(t, server.HasHandler(StoreKeyPath))
+	require.False(t, server.HasHandler("/unknown"))
+}
+
+func TestServer_Query(t *testing.T) {
+	s := store.NewMemoryStore()
+	require.NoError(t, s.Set([]byte("a"), []byte("1")))
+
+	server, err := NewServer(s)
+	require.NoError(t, err)
+	require.NoError(t, server.RegisterHandler("/get", getHandler))
+	ctx := context.Background()
+
+	resp, err := server.Query(ctx, Request{Path: "/get", Data: []byte("a")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), resp.Value)
+
+	_, err = server.Query(ctx, Request{Path: "/unknown"})
+	require.ErrorIs(t, err, ErrHandlerNotFound)
+
+	// A store without versions only serves the latest height
+	_, err = server.Query(ctx, Request{Path: "/get", Data: []byte("a"), Height: 3})
+	require.ErrorIs(t, err, ErrHeightNotAvailable)
+
+	_, err = server.Query(ctx, Request{Path: "/get", Data: []byte("a"), Prove: true})
+	require.ErrorIs(t, err, ErrProofNotSupported)
+
+	resp, err = server.Query(ctx, Request{Path: StoreKeyPath, Data: []byte("missing")})
+	require.NoError(t, err)
+	require.Nil(t, resp.Value)
+}
+
+func TestServer_HeightPinned(t *testing.T) {
+	s := newHistoricalStore()
+	require.NoError(t, s.Set([]byte("a"), []byte("1")))
+	s.save(t)
+	require.NoError(t, s.Set([]byte("a"), []byte("2")))
+	s.save(t)
+
+	server, err := NewServer(s)
+	require.NoError(t, err)
+	require.NoError(t, server.RegisterHandler("/get", getHandler))
+	ctx := context.Background()
+
+	tests := []struct {
+		height     int64
+		wantValue  string
+		wantHeight int64
+	}{
+		{0, "2", 2},
+		{2, "2", 2},
+		{1, "1", 1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("height %d", tt.height), func(t *testing.T) {
+			resp, err := server.Query(ctx, Request{Path: "/get", Data: []byte("a"), Height: tt.height})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantValue, string(resp.Value))
+			require.Equal(t, tt.wantHeight, resp.Height)
+		})
+	}
+
+	_, err = server.Query(ctx, Request{Path: "/get", Data: []byte("a"), Height: 3})
+	require.ErrorIs(t, err, ErrHeightNotAvailable)
+
+	delete(s.versions, 1)
+	_, err = server.Query(ctx, Request{Path: "/get", Data: []byte("a"), Height: 1})
+	require.ErrorIs(t, err, ErrHeightNotAvailable)
+
+	_, err = server.Query(ctx, Request{Path: "/get", Height: -1})
+	require.ErrorIs(t, err, ErrHeightNotAvailable)
+}
+
+func TestServer_StoreKeyProof(t *testing.T) {
+	s, err := store.NewIAVLStore(store.NewMemDB(), 0)
+	require.NoError(t, err)
+	require.NoError(t, s.Set([]byte("a"), []byte("1")))
+	root, _, err := s.SaveVersion()
+	require.NoError(t, err)
+
+	server, err := NewServer(s)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	resp, err := server.Query(ctx, Request{Path: StoreKeyPath, Data: []byte("a"), Prove: true})
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), resp.Value)
+	require.Equal(t, int64(1), resp.Height)
+	require.NotNil(t, resp.Proof)
+	require.True(t, ics23.VerifyMembership(ics23.IavlSpec, root, resp.Proof, []byte("a"), []byte("1")))
+
+	resp, err = server.Query(ctx, Request{Path: StoreKeyPath, Data: []byte("b"), Prove: true})
+	require.NoError(t, err)
+	require.Nil(t, resp.Value)
+	require.True(t, ics23.VerifyNonMembership(ics23.IavlSpec, root, resp.Proof, []byte("b")))
+
+	// Uncommitted writes are not returned with a proof of the saved version
+	require.NoError(t, s.Set([]byte("a"), []byte("2")))
+	resp, err = server.Query(ctx, Request{Path: StoreKeyPath, Data: []byte("a"), Prove: true})
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), resp.Value)
+	require.True(t, ics23.VerifyMembership(ics23.IavlSpec, root, resp.Proof, []byte("a"), resp.Value))
+
+	_, err = server.Query(ctx, Request{Path: StoreKeyPath})
+	require.Error(t, err)
+}
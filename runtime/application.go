@@ -2,13 +2,18 @@ package runtime
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/blockberries/punnet-sdk/capability"
 	"github.com/blockberries/punnet-sdk/effects"
+	sdkerrors "github.com/blockberries/punnet-sdk/errors"
+	"github.com/blockberries/punnet-sdk/query"
 	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/streaming"
 	"github.com/blockberries/punnet-sdk/types"
 )
 
@@ -21,6 +26,10 @@ var (
 
 	// ErrInvalidHeight is returned when an invalid height is provided
 	ErrInvalidHeight = fmt.Errorf("invalid height")
+
+	// ErrInvalidModuleOrder is returned when a lifecycle order is not a
+	// permutation of the registered modules
+	ErrInvalidModuleOrder = fmt.Errorf("invalid module order")
 )
 
 // Application implements the Blockberry Application interface
@@ -37,6 +46,9 @@ type Application struct {
 	// effectExecutor executes effects against state stores
 	effectExecutor *effects.Executor
 
+	// effectApplier validates and executes handler effects
+	effectApplier *EffectApplier
+
 	// stateStore is the underlying IAVL state storage
 	stateStore *store.IAVLStore
 
@@ -52,16 +64,68 @@ type Application struct {
 	// chainID is the blockchain identifier
 	chainID string
 
-	// txSerializer handles transaction serialization
-	txSerializer *store.JSONSerializer[*types.Transaction]
+	// decodeTx decodes transaction bytes
+	decodeTx func(txBytes []byte) (*types.Transaction, error)
+
+	// txLimits bounds transaction size and authorization shape (defaults applied)
+	txLimits types.TxLimits
+
+	// anteHandler checks transactions before their messages run (may be nil)
+	anteHandler AnteHandler
+
+	// feeGrantHandler checks and consumes fee allowances (may be nil)
+	feeGrantHandler FeeGrantHandler
+
+	// msgFailurePolicy decides whether a failed message fails its transaction
+	msgFailurePolicy MsgFailurePolicy
+
+	// gasSchedule provides the gas schedule of every transaction (may be nil)
+	gasSchedule GasScheduleSource
+
+	// storageUsage counts module writes per block (may be nil)
+	storageUsage *capability.UsageTracker
+
+	// deadLetters receives the failed effect batches of transactions (may
+	// be nil)
+	deadLetters DeadLetterStore
+
+	// streamer streams committed state changes (nil without state sinks)
+	streamer *stateStreamer
 
 	// accountGetter adapts accountStore for authorization verification
 	accountGetter types.AccountGetter
+
+	// authenticators are the modules' authenticators by route (see
+	// AuthenticatorRoute)
+	authenticators map[string]Authenticator
+
+	// queryServer serves module query services with height-pinned reads
+	queryServer *query.Server
+
+	// initGenesisOrder, beginBlockOrder and endBlockOrder list module names
+	// in lifecycle execution order (nil means name order)
+	initGenesisOrder []string
+	beginBlockOrder  []string
+	endBlockOrder    []string
+
+	// lastCommitInfo describes the last committed state (nil before the
+	// first Commit)
+	lastCommitInfo *types.CommitInfo
+
+	// lastBlockTime is the time of the last committed block (zero before
+	// the first Commit)
+	lastBlockTime time.Time
 }
 
 // iavlStoreAdapter adapts IAVLStore to effects.Store interface
 type iavlStoreAdapter struct {
 	store *store.IAVLStore
+
+	// usage counts writes into module namespaces (may be nil)
+	usage *capability.UsageTracker
+
+	// capMgr keeps module indexes in sync with the writes (may be nil)
+	capMgr *capability.CapabilityManager
 }
 
 func (a *iavlStoreAdapter) Get(key []byte) ([]byte, error) {
@@ -69,13 +133,42 @@ func (a *iavlStoreAdapter) Get(key []byte) ([]byte, error) {
 }
 
 func (a *iavlStoreAdapter) Set(key []byte, value []byte) error {
+	if module, ok := capability.ModuleOfKey(key); ok {
+		if err := a.usage.RecordWrite(module, len(key)+len(value)); err != nil {
+			return err
+		}
+	}
+
+	indexed, moduleKey, err := a.indexedStoreOf(key)
+	if err != nil {
+		return err
+	}
+	if indexed != nil {
+		return indexed.Set(moduleKey, value)
+	}
 	return a.store.Set(key, value)
 }
 
 func (a *iavlStoreAdapter) Delete(key []byte) error {
+	indexed, moduleKey, err := a.indexedStoreOf(key)
+	if err != nil {
+		return err
+	}
+	if indexed != nil {
+		return indexed.Delete(moduleKey)
+	}
 	return a.store.Delete(key)
 }
 
+// indexedStoreOf returns the store keeping the indexes of key's module in
+// sync, or nil if key is not indexed; see CapabilityManager.IndexedStoreOf
+func (a *iavlStoreAdapter) indexedStoreOf(key []byte) (store.BackingStore, []byte, error) {
+	if a.capMgr == nil {
+		return nil, nil, nil
+	}
+	return a.capMgr.IndexedStoreOf(key)
+}
+
 func (a *iavlStoreAdapter) Has(key []byte) bool {
 	has, _ := a.store.Has(key)
 	return has
@@ -84,6 +177,9 @@ func (a *iavlStoreAdapter) Has(key []byte) bool {
 // balanceStoreAdapter adapts store.BalanceStore to effects.BalanceStore interface
 type balanceStoreAdapter struct {
 	store *store.BalanceStore
+
+	// streamer attributes the buffered balance writes (may be nil)
+	streamer *stateStreamer
 }
 
 func (a *balanceStoreAdapter) GetBalance(account types.AccountName, denom string) (uint64, error) {
@@ -96,15 +192,42 @@ func (a *balanceStoreAdapter) GetBalance(account types.AccountName, denom string
 
 func (a *balanceStoreAdapter) SetBalance(account types.AccountName, denom string, amount uint64) error {
 	balance := store.NewBalance(account, denom, amount)
-	return a.store.Set(context.Background(), balance)
+	return a.touched(account, denom, a.store.Set(context.Background(), balance))
 }
 
 func (a *balanceStoreAdapter) SubBalance(account types.AccountName, denom string, amount uint64) error {
-	return a.store.SubAmount(context.Background(), account, denom, amount)
+	return a.touched(account, denom, a.store.SubAmount(context.Background(), account, denom, amount))
 }
 
 func (a *balanceStoreAdapter) AddBalance(account types.AccountName, denom string, amount uint64) error {
-	return a.store.AddAmount(context.Background(), account, denom, amount)
+	return a.touched(account, denom, a.store.AddAmount(context.Background(), account, denom, amount))
+}
+
+// touched reports a successful write of the balance of account in denom to
+// the streamer and returns err
+func (a *balanceStoreAdapter) touched(account types.AccountName, denom string, err error) error {
+	if err == nil {
+		a.streamer.touch(store.BalanceKey(account, denom))
+	}
+	return err
+}
+
+// accountStoreAdapter adapts the account store to effects.AccountStore, so
+// account write effects update the cached accounts transactions read
+type accountStoreAdapter struct {
+	store store.ObjectStore[*types.Account]
+
+	// streamer attributes the buffered account writes (may be nil)
+	streamer *stateStreamer
+}
+
+func (a *accountStoreAdapter) SetAccount(account *types.Account) error {
+	key := []byte(account.Name)
+	if err := a.store.Set(context.Background(), key, account); err != nil {
+		return err
+	}
+	a.streamer.touch(key)
+	return nil
 }
 
 // accountGetterAdapter adapts ObjectStore to types.AccountGetter interface
@@ -125,14 +248,87 @@ type ApplicationConfig struct {
 	// StateStore is the backing IAVL store
 	StateStore *store.IAVLStore
 
+	// AccountStore and BalanceStore optionally replace the stores accounts
+	// and balances are kept in, which by default are cached object stores
+	// over StateStore. Pass the stores behind the capabilities of the
+	// modules owning accounts and balances (see capability.NewAccountCapability)
+	// so that their handlers and the runtime share one cache.
+	AccountStore store.ObjectStore[*types.Account]
+	BalanceStore *store.BalanceStore
+
+	// TxDecoder optionally decodes the transaction bytes of CheckTx,
+	// ReCheckTx and ExecuteTx, e.g. (*types.TxDecoder).Decode. By default
+	// transactions are decoded as JSON.
+	TxDecoder func(txBytes []byte) (*types.Transaction, error)
+
 	// Modules are the modules to register
 	Modules []Module
+
+	// InitGenesisOrder, BeginBlockOrder and EndBlockOrder optionally set the
+	// order in which module lifecycle hooks run. Each must list every module
+	// exactly once. If empty, modules run in name order.
+	// See module.ModuleManager, which computes them from dependencies.
+	InitGenesisOrder []string
+	BeginBlockOrder  []string
+	EndBlockOrder    []string
+
+	// TxLimits bounds transaction byte size, signature count and
+	// authorization depth, checked before signature verification.
+	// Zero fields use the defaults (see types.DefaultTxLimits).
+	TxLimits types.TxLimits
+
+	// AnteHandler optionally checks every transaction before its messages
+	// run, e.g. to enforce and charge fees. Combine several with
+	// ChainAnteHandlers.
+	AnteHandler AnteHandler
+
+	// FeeGrantHandler optionally lets transactions name a fee granter
+	// (types.Transaction.FeeGranter) whose allowance pays their fee.
+	// Without one, such transactions are rejected with
+	// ErrFeeGrantUnsupported.
+	FeeGrantHandler FeeGrantHandler
+
+	// MsgFailurePolicy decides whether a failed message fails its whole
+	// transaction (MsgFailureAtomic, the default) or only itself
+	// (MsgFailureContinue)
+	MsgFailurePolicy MsgFailurePolicy
+
+	// CircuitBreaker optionally disables message types; it is checked
+	// before every message dispatch (see Router.SetCircuitBreaker)
+	CircuitBreaker CircuitBreaker
+
+	// PanicLogger optionally reports recovered message handler panics;
+	// by default they are written to the standard logger
+	PanicLogger PanicLogger
+
+	// GasSchedule optionally prices messages, signatures and store access
+	// (see modules/gas); it is read for every transaction, so updates take
+	// effect with the next one. Without one no such gas is charged.
+	GasSchedule GasScheduleSource
+
+	// StorageUsage optionally counts the bytes each module writes per block,
+	// through effects and capabilities, and enforces its limits. Usage is
+	// reported at the end of every block.
+	StorageUsage *capability.UsageTracker
+
+	// DeadLetters optionally receives a DeadLetter for every effect batch of
+	// a transaction that fails to apply: the batch, its messages and the
+	// state it changed before failing. It is a debug mode for post-mortem
+	// analysis on non-validating nodes; it costs extra state reads per
+	// transaction but does not change results.
+	DeadLetters DeadLetterStore
+
+	// StateSinks optionally receive every committed block's key-value
+	// changes with their block and transaction context (see the streaming
+	// package), so off-chain indexers can mirror state. Sinks run after
+	// Commit and cannot affect consensus.
+	StateSinks []streaming.Sink
 }
 
 // NewApplication creates a new application
 func NewApplication(config ApplicationConfig) (*Application, error) {
-	if config.ChainID == "" {
-		return nil, fmt.Errorf("chain ID cannot be empty")
+	if err := types.ValidateChainID(config.ChainID); err != nil {
+		return nil, err
 	}
 
 	if config.StateStore == nil {
@@ -143,31 +339,60 @@ func NewApplication(config ApplicationConfig) (*Application, error) {
 		return nil, ErrNoModules
 	}
 
+	txLimits := config.TxLimits.WithDefaults()
+	if err := txLimits.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid tx limits: %w", err)
+	}
+
+	if err := config.MsgFailurePolicy.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
 	// Create router
 	router := NewRouter()
+	router.SetCircuitBreaker(config.CircuitBreaker)
+	router.SetPanicLogger(config.PanicLogger)
 
 	// Create account store with JSON serializer
 	// L1 cache: 1000 entries, L2 cache: 10000 entries
-	accountStore := store.NewCachedObjectStore[*types.Account](
-		config.StateStore,
-		store.NewJSONSerializer[*types.Account](),
-		1000,  // L1 cache size
-		10000, // L2 cache size
-	)
+	accountStore := config.AccountStore
+	if accountStore == nil {
+		accountStore = store.NewCachedObjectStore[*types.Account](
+			config.StateStore,
+			store.NewJSONSerializer[*types.Account](),
+			1000,  // L1 cache size
+			10000, // L2 cache size
+		)
+	}
 
 	// Create balance store
-	balanceStore := store.NewBalanceStore(config.StateStore)
+	balanceStore := config.BalanceStore
+	if balanceStore == nil {
+		balanceStore = store.NewBalanceStore(config.StateStore)
+	}
+
+	decodeTx := config.TxDecoder
+	if decodeTx == nil {
+		decodeTx = store.NewJSONSerializer[*types.Transaction]().Unmarshal
+	}
+
+	var streamer *stateStreamer
+	if len(config.StateSinks) > 0 {
+		streamer = newStateStreamer(config.StateStore, config.StateSinks)
+	}
 
 	// Create capability manager
 	capMgr := capability.NewCapabilityManager(config.StateStore)
+	capMgr.SetUsageTracker(config.StorageUsage)
 
 	// Create effect executor (wrapping IAVL store to match effects.Store interface)
-	storeAdapter := &iavlStoreAdapter{store: config.StateStore}
-	balanceStoreAdapter := &balanceStoreAdapter{store: balanceStore}
+	storeAdapter := &iavlStoreAdapter{store: config.StateStore, usage: config.StorageUsage, capMgr: capMgr}
+	balanceStoreAdapter := &balanceStoreAdapter{store: balanceStore, streamer: streamer}
 	executor, err := effects.NewExecutor(storeAdapter, balanceStoreAdapter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create effect executor: %w", err)
 	}
+	executor.SetAccountStore(&accountStoreAdapter{store: accountStore, streamer: streamer})
 
 	// Register all modules
 	for _, mod := range config.Modules {
@@ -176,6 +401,41 @@ func NewApplication(config ApplicationConfig) (*Application, error) {
 		}
 	}
 
+	// Register module query services
+	queryServer, err := query.NewServer(config.StateStore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create query server: %w", err)
+	}
+	if err := queryServer.RegisterHandler(QueryPathAccountNonce, queryAccountNonce); err != nil {
+		return nil, fmt.Errorf("failed to register runtime query services: %w", err)
+	}
+	for _, mod := range config.Modules {
+		registrar, ok := mod.(query.Registrar)
+		if !ok {
+			continue
+		}
+		if err := registrar.RegisterQueryServices(queryServer); err != nil {
+			return nil, fmt.Errorf("failed to register query services of module %s: %w", mod.Name(), err)
+		}
+	}
+
+	authenticators, err := registerAuthenticators(config.Modules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register authenticators: %w", err)
+	}
+
+	// Validate lifecycle orders
+	for _, order := range [][]string{config.InitGenesisOrder, config.BeginBlockOrder, config.EndBlockOrder} {
+		if err := validateModuleOrder(order, config.Modules); err != nil {
+			return nil, err
+		}
+	}
+
+	applier, err := NewEffectApplier(executor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create effect applier: %w", err)
+	}
+
 	// Create account getter adapter
 	accountGetter := &accountGetterAdapter{store: accountStore}
 
@@ -183,12 +443,26 @@ func NewApplication(config ApplicationConfig) (*Application, error) {
 		router:            router,
 		capabilityManager: capMgr,
 		effectExecutor:    executor,
+		effectApplier:     applier,
 		stateStore:        config.StateStore,
 		accountStore:      accountStore,
 		balanceStore:      balanceStore,
 		chainID:           config.ChainID,
-		txSerializer:      store.NewJSONSerializer[*types.Transaction](),
+		decodeTx:          decodeTx,
+		txLimits:          txLimits,
+		anteHandler:       config.AnteHandler,
+		feeGrantHandler:   config.FeeGrantHandler,
+		msgFailurePolicy:  config.MsgFailurePolicy,
+		gasSchedule:       config.GasSchedule,
+		storageUsage:      config.StorageUsage,
+		deadLetters:       config.DeadLetters,
+		streamer:          streamer,
 		accountGetter:     accountGetter,
+		authenticators:    authenticators,
+		queryServer:       queryServer,
+		initGenesisOrder:  copyStrings(config.InitGenesisOrder),
+		beginBlockOrder:   copyStrings(config.BeginBlockOrder),
+		endBlockOrder:     copyStrings(config.EndBlockOrder),
 	}
 
 	return app, nil
@@ -209,11 +483,26 @@ func (app *Application) CheckTx(ctx context.Context, txBytes []byte) error {
 		return fmt.Errorf("transaction bytes cannot be empty")
 	}
 
+	// SECURITY: Reject oversized transactions before decoding them
+	if err := app.txLimits.CheckTxSize(len(txBytes)); err != nil {
+		return fmt.Errorf("transaction validation failed: %w", err)
+	}
+
 	// Deserialize transaction
-	tx, err := app.txSerializer.Unmarshal(txBytes)
+	tx, err := app.decodeTx(txBytes)
 	if err != nil {
 		return fmt.Errorf("failed to deserialize transaction: %w", err)
 	}
+	return app.checkTx(ctx, tx, types.TxHash(txBytes))
+}
+
+// checkTx checks a decoded transaction, whose encoding hashes to txHash; see
+// CheckTx
+func (app *Application) checkTx(ctx context.Context, tx *types.Transaction, txHash []byte) error {
+	// SECURITY: Bound verification work before any signature is checked
+	if err := app.checkAuthorizationLimits(tx); err != nil {
+		return fmt.Errorf("transaction validation failed: %w", err)
+	}
 
 	// Basic validation
 	if err := tx.ValidateBasic(); err != nil {
@@ -230,39 +519,155 @@ func (app *Application) CheckTx(ctx context.Context, txBytes []byte) error {
 		return fmt.Errorf("failed to get account: %w", err)
 	}
 
-	// Verify authorization using SignDoc-based verification
-	// SECURITY: chainID binding prevents cross-chain replay attacks
-	if err := tx.VerifyAuthorization(app.chainID, account, app.accountGetter); err != nil {
-		return fmt.Errorf("authorization verification failed: %w", err)
-	}
-
-	// Create read-only context for message validation
-	app.mu.RLock()
-	header := app.currentHeader
-	app.mu.RUnlock()
+	header := app.checkHeader()
 
-	if header == nil {
-		// Use dummy header if not in block context
-		header = NewBlockHeader(1, time.Now(), app.chainID, nil)
+	// SECURITY: Reject transactions outside their signed validity window, so
+	// pre-signed transactions cannot enter the mempool early
+	if err := tx.Authorization.CheckValidity(header.Height, header.Time); err != nil {
+		return fmt.Errorf("transaction validation failed: %w", err)
 	}
 
+	// Create read-only context for authorization and message validation
 	readOnlyCtx, err := NewReadOnlyContext(ctx, header, tx.Account)
 	if err != nil {
 		return fmt.Errorf("failed to create read-only context: %w", err)
 	}
+	schedule, err := app.loadGasSchedule()
+	if err != nil {
+		return err
+	}
+	readOnlyCtx = readOnlyCtx.WithTxHash(txHash).WithTxSigners(tx.Signers()...).WithGasMeter(txGasMeter(tx)).WithGasSchedule(schedule).withAuthenticators(app.authenticators)
+
+	// Verify authorization using SignDoc-based verification, or the
+	// account's authenticator
+	if err := readOnlyCtx.consumeSigGas(tx); err != nil {
+		return fmt.Errorf("authorization verification failed: %w", err)
+	}
+	if _, err := app.authenticate(readOnlyCtx, tx, account); err != nil {
+		return fmt.Errorf("authorization verification failed: %w", err)
+	}
+	if _, err := app.verifyCoSigners(ctx, tx); err != nil {
+		return fmt.Errorf("co-signer verification failed: %w", err)
+	}
+	if _, err := app.verifyFeeSponsors(readOnlyCtx, tx, account); err != nil {
+		return fmt.Errorf("fee sponsor verification failed: %w", err)
+	}
+
+	if app.anteHandler != nil {
+		if _, err := app.anteHandler(readOnlyCtx, tx); err != nil {
+			return fmt.Errorf("ante handler failed: %w", err)
+		}
+	}
 
 	// Validate all messages by routing them (handlers should validate)
 	for _, msg := range tx.Messages {
-		// Route message to handler (read-only, no effects)
-		_, err := app.router.RouteMsg(readOnlyCtx, msg)
+		if err := readOnlyCtx.consumeMsgGas(); err != nil {
+			return fmt.Errorf("message validation failed: %w", err)
+		}
+
+		// Route message to handler (read-only, no effects), as its signer
+		msgEffects, err := app.router.RouteMsg(readOnlyCtx.withSigner(tx.MsgSigner(msg)), msg)
 		if err != nil {
 			return fmt.Errorf("message validation failed: %w", err)
 		}
+		if err := readOnlyCtx.consumeEffectGas(msgEffects); err != nil {
+			return fmt.Errorf("message validation failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ReCheckTx re-validates a transaction already admitted by CheckTx against
+// the latest state, e.g. for mempool.Mempool.ReCheck after a commit. It is
+// lighter than CheckTx: signatures are not re-verified and messages are not
+// routed; only the nonce, the validity window and the ante handler (fees and
+// balances) are checked.
+//
+// A nonce below the account's is stale and reported as
+// types.ErrSequenceMismatch; a higher nonce may still follow pending
+// transactions and passes.
+//
+// SECURITY: Skipping signature verification is safe because executeTx
+// verifies every transaction in full.
+func (app *Application) ReCheckTx(ctx context.Context, txBytes []byte) error {
+	if app == nil {
+		return ErrApplicationNil
+	}
+
+	if ctx == nil {
+		return fmt.Errorf("context cannot be nil")
+	}
+
+	if len(txBytes) == 0 {
+		return fmt.Errorf("transaction bytes cannot be empty")
+	}
+
+	tx, err := app.decodeTx(txBytes)
+	if err != nil {
+		return fmt.Errorf("failed to deserialize transaction: %w", err)
+	}
+	return app.recheckTx(ctx, tx, types.TxHash(txBytes))
+}
+
+// recheckTx re-validates a decoded transaction, whose encoding hashes to
+// txHash; see ReCheckTx
+func (app *Application) recheckTx(ctx context.Context, tx *types.Transaction, txHash []byte) error {
+	if err := tx.ValidateBasic(); err != nil {
+		return fmt.Errorf("transaction validation failed: %w", err)
+	}
+
+	account, err := app.accountStore.Get(ctx, []byte(tx.Account))
+	if err != nil {
+		if err == store.ErrNotFound {
+			return fmt.Errorf("%w: account %s", types.ErrNotFound, tx.Account)
+		}
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+	if tx.Nonce < account.Nonce {
+		return fmt.Errorf("%w: nonce %d already used, account is at %d", types.ErrSequenceMismatch, tx.Nonce, account.Nonce)
+	}
+	if err := app.checkCoSignerNonces(ctx, tx); err != nil {
+		return err
+	}
+
+	header := app.checkHeader()
+
+	if err := tx.Authorization.CheckValidity(header.Height, header.Time); err != nil {
+		return fmt.Errorf("transaction validation failed: %w", err)
 	}
 
+	if app.anteHandler == nil {
+		return nil
+	}
+	readOnlyCtx, err := NewReadOnlyContext(ctx, header, tx.Account)
+	if err != nil {
+		return fmt.Errorf("failed to create read-only context: %w", err)
+	}
+	readOnlyCtx = readOnlyCtx.WithTxHash(txHash).WithGasMeter(txGasMeter(tx))
+	if _, err := app.anteHandler(readOnlyCtx, tx); err != nil {
+		return fmt.Errorf("ante handler failed: %w", err)
+	}
 	return nil
 }
 
+// checkHeader returns the header CheckTx and ReCheckTx check transactions
+// against: the current block's, or between blocks the next block's height
+// with the last block's time (the current time before the first block)
+func (app *Application) checkHeader() *BlockHeader {
+	app.mu.RLock()
+	header, blockTime := app.currentHeader, app.lastBlockTime
+	app.mu.RUnlock()
+
+	if header != nil {
+		return header
+	}
+	if blockTime.IsZero() {
+		blockTime = time.Now()
+	}
+	return NewBlockHeader(uint64(app.stateStore.Version())+1, blockTime, app.chainID, nil)
+}
+
 // BeginBlock is called at the beginning of each block
 func (app *Application) BeginBlock(ctx context.Context, header *BlockHeader) error {
 	if app == nil {
@@ -285,6 +690,10 @@ func (app *Application) BeginBlock(ctx context.Context, header *BlockHeader) err
 	app.currentHeader = header
 	app.mu.Unlock()
 
+	// Writes outside blocks (e.g. at genesis) do not count against the
+	// block's storage limits
+	app.storageUsage.Reset()
+
 	// Call module BeginBlock hooks
 	return app.callBeginBlockers(ctx, header)
 }
@@ -303,17 +712,27 @@ func (app *Application) ExecuteTx(ctx context.Context, txBytes []byte) (*types.T
 		return nil, fmt.Errorf("transaction bytes cannot be empty")
 	}
 
+	app.streamer.beginTx()
+	result, err := app.deliverTx(ctx, txBytes)
+	app.streamer.endTx(txBytes, result)
+	return result, err
+}
+
+// deliverTx decodes and executes a transaction of the block
+func (app *Application) deliverTx(ctx context.Context, txBytes []byte) (*types.TxResult, error) {
+	// SECURITY: Reject oversized transactions before decoding them
+	if err := app.txLimits.CheckTxSize(len(txBytes)); err != nil {
+		return txErrorResult("transaction validation failed", err), nil
+	}
+
 	// Deserialize transaction
-	tx, err := app.txSerializer.Unmarshal(txBytes)
+	tx, err := app.decodeTx(txBytes)
 	if err != nil {
-		return &types.TxResult{
-			Code: 1,
-			Log:  fmt.Sprintf("failed to deserialize transaction: %v", err),
-		}, nil
+		return txErrorResult("failed to deserialize transaction", sdkerrors.Wrap(types.ErrInvalidTransaction, err.Error())), nil
 	}
 
 	// Execute transaction
-	return app.executeTx(ctx, tx)
+	return app.executeTx(ctx, tx, types.TxHash(txBytes))
 }
 
 // EndBlock is called at the end of each block
@@ -335,7 +754,13 @@ func (app *Application) EndBlock(ctx context.Context) (*types.EndBlockResult, er
 	}
 
 	// Call module EndBlock hooks
-	return app.callEndBlockers(ctx, header)
+	result, err := app.callEndBlockers(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	app.storageUsage.EndBlock(header.Height)
+	return result, nil
 }
 
 // Commit commits the current state and returns the app hash
@@ -366,24 +791,62 @@ func (app *Application) Commit(ctx context.Context) (*types.CommitResult, error)
 	}
 
 	// Commit IAVL state (save new version)
-	hash, version, err := app.stateStore.SaveVersion()
+	root, version, err := app.stateStore.SaveVersion()
 	if err != nil {
 		return nil, fmt.Errorf("failed to commit state: %w", err)
 	}
 
+	// Every module's state lives under its prefix in the state store, so its
+	// root is the only store root committed
+	info := types.NewCommitInfo(uint64(version), types.StoreRoot{Name: types.StateStoreName, Hash: root})
+	appHash, err := info.AppHash()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute app hash: %w", err)
+	}
+
 	// Clear current header
 	app.mu.Lock()
 	app.currentHeader = nil
+	app.lastCommitInfo = &info
+	app.lastBlockTime = header.Time
 	app.mu.Unlock()
 
+	app.streamer.commit(header, uint64(version), appHash)
+
 	return &types.CommitResult{
-		AppHash: hash,
-		Height:  uint64(version),
+		AppHash:    appHash,
+		Height:     uint64(version),
+		CommitInfo: &info,
 	}, nil
 }
 
-// Query handles query requests
+// LastCommitInfo returns the store roots of the last committed state, or
+// false before the first Commit
+func (app *Application) LastCommitInfo() (types.CommitInfo, bool) {
+	if app == nil {
+		return types.CommitInfo{}, false
+	}
+
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+
+	if app.lastCommitInfo == nil {
+		return types.CommitInfo{}, false
+	}
+	return types.NewCommitInfo(app.lastCommitInfo.Height, app.lastCommitInfo.Roots...), true
+}
+
+// Query handles query requests.
+// Paths registered as query services are served at height (0 means latest);
+// module query handlers only serve the latest height.
 func (app *Application) Query(ctx context.Context, path string, data []byte, height int64) (*types.QueryResult, error) {
+	return app.QueryRequest(ctx, query.Request{Path: path, Data: data, Height: height})
+}
+
+// QueryRequest handles a query request, including proof requests for
+// query.StoreKeyPath and batches at QueryPathBatch. The proof is returned
+// protobuf-encoded.
+func (app *Application) QueryRequest(ctx context.Context, req query.Request) (*types.QueryResult, error) {
 	if app == nil {
 		return nil, ErrApplicationNil
 	}
@@ -392,33 +855,104 @@ func (app *Application) Query(ctx context.Context, path string, data []byte, hei
 		return nil, fmt.Errorf("context cannot be nil")
 	}
 
-	if path == "" {
+	if req.Path == "" {
 		return nil, fmt.Errorf("query path cannot be empty")
 	}
 
-	if height < 0 {
+	if req.Height < 0 {
 		return nil, ErrInvalidHeight
 	}
 
-	// TODO: Support historical queries by loading specific IAVL version
-	// For now, only support queries at current height
+	if req.Path == QueryPathBatch {
+		return app.queryBatch(ctx, req)
+	}
+
+	if app.queryServer.HasHandler(req.Path) {
+		resp, err := app.queryServer.Query(ctx, req)
+		if err != nil {
+			return queryErrorResult(err, fmt.Sprintf("query failed: %v", err)), nil
+		}
+
+		result := &types.QueryResult{
+			Code:   0,
+			Data:   resp.Value,
+			Height: uint64(resp.Height),
+		}
+		if resp.Proof != nil {
+			proofBytes, err := resp.Proof.Marshal()
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode proof: %w", err)
+			}
+			result.Proof = proofBytes
+		}
+		return result, nil
+	}
+
+	latest := app.stateStore.Version()
+	if req.Height != 0 && req.Height != latest {
+		return queryErrorResult(query.ErrHeightNotAvailable,
+			fmt.Sprintf("query failed: %v: path %s only serves the latest height", query.ErrHeightNotAvailable, req.Path)), nil
+	}
+	if req.Prove {
+		return queryErrorResult(query.ErrProofNotSupported,
+			fmt.Sprintf("query failed: %v: path %s", query.ErrProofNotSupported, req.Path)), nil
+	}
 
 	// Route query to handler
-	result, err := app.router.RouteQuery(ctx, path, data)
+	result, err := app.router.RouteQuery(ctx, req.Path, req.Data)
 	if err != nil {
-		return &types.QueryResult{
-			Code: 1,
-			Log:  fmt.Sprintf("query failed: %v", err),
-		}, nil
+		return queryErrorResult(err, fmt.Sprintf("query failed: %v", err)), nil
 	}
 
 	return &types.QueryResult{
 		Code:   0,
 		Data:   result,
-		Height: uint64(app.stateStore.Version()),
+		Height: uint64(latest),
 	}, nil
 }
 
+// QueryPathAccountNonce is the runtime query service returning an account's
+// next expected transaction nonce. The request data is the account name; the
+// response is a JSON AccountNonceResponse.
+const QueryPathAccountNonce = "/runtime/account/nonce"
+
+// AccountNonceResponse is the response of QueryPathAccountNonce
+type AccountNonceResponse struct {
+	Account types.AccountName `json:"account"`
+	Nonce   uint64            `json:"nonce"`
+}
+
+// queryAccountNonce serves QueryPathAccountNonce from the pinned state
+func queryAccountNonce(ctx *query.Context, data []byte) ([]byte, error) {
+	name := types.AccountName(data)
+	if !name.IsValid() {
+		return nil, fmt.Errorf("%w: %s", types.ErrInvalidAccount, name)
+	}
+
+	// Accounts are stored under their raw name (see accountStore)
+	raw, err := ctx.Store().Get([]byte(name))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, fmt.Errorf("%w: account %s", types.ErrNotFound, name)
+		}
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	account, err := store.NewJSONSerializer[*types.Account]().Unmarshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode account: %w", err)
+	}
+	return json.Marshal(AccountNonceResponse{Account: name, Nonce: account.Nonce})
+}
+
+// QueryServer returns the server for module query services
+func (app *Application) QueryServer() *query.Server {
+	if app == nil {
+		return nil
+	}
+	return app.queryServer
+}
+
 // InitChain initializes the blockchain from genesis
 func (app *Application) InitChain(ctx context.Context, validators []types.ValidatorUpdate, appState []byte) error {
 	if app == nil {
@@ -465,6 +999,14 @@ func (app *Application) EffectExecutor() *effects.Executor {
 	return app.effectExecutor
 }
 
+// EffectApplier returns the effect applier
+func (app *Application) EffectApplier() *EffectApplier {
+	if app == nil {
+		return nil
+	}
+	return app.effectApplier
+}
+
 // StateStore returns the underlying IAVL store
 func (app *Application) StateStore() *store.IAVLStore {
 	if app == nil {
@@ -489,14 +1031,102 @@ func (app *Application) BalanceStore() *store.BalanceStore {
 	return app.balanceStore
 }
 
-// executeTx executes a transaction and returns the result
-func (app *Application) executeTx(ctx context.Context, tx *types.Transaction) (*types.TxResult, error) {
+// txErrorResult returns a failed transaction result for err, raised during stage.
+// The codespace and code identify err (see sdkerrors.ABCICode).
+//
+// SECURITY: Transaction results are part of consensus. The log is redacted
+// (see sdkerrors.Redact) so unregistered errors, whose text may differ
+// between nodes, cannot cause an app hash mismatch.
+func txErrorResult(stage string, err error) *types.TxResult {
+	redacted := sdkerrors.Redact(err)
+	codespace, code := sdkerrors.ABCICode(redacted)
+	return &types.TxResult{
+		Codespace: codespace,
+		Code:      code,
+		Log:       fmt.Sprintf("%s: %v", stage, redacted),
+	}
+}
+
+// msgErrorResult returns a failed message result, redacted as by
+// txErrorResult
+func msgErrorResult(msgType, stage string, err error, gasUsed uint64) types.MsgResult {
+	redacted := sdkerrors.Redact(err)
+	codespace, code := sdkerrors.ABCICode(redacted)
+	return types.MsgResult{
+		Type:      msgType,
+		Codespace: codespace,
+		Code:      code,
+		Log:       fmt.Sprintf("%s: %v", stage, redacted),
+		GasUsed:   gasUsed,
+	}
+}
+
+// queryErrorResult returns a failed query result whose codespace and code
+// identify err (see sdkerrors.ABCICode). Queries are not part of consensus, so
+// the log is not redacted.
+func queryErrorResult(err error, log string) *types.QueryResult {
+	codespace, code := sdkerrors.ABCICode(err)
+	return &types.QueryResult{
+		Codespace: codespace,
+		Code:      code,
+		Log:       log,
+	}
+}
+
+// txGasMeter returns the gas meter for tx: limited by its fee's gas limit,
+// or unlimited if the transaction sets none.
+func txGasMeter(tx *types.Transaction) GasMeter {
+	if tx.Fee.GasLimit == 0 {
+		return NewInfiniteGasMeter()
+	}
+	return NewGasMeter(tx.Fee.GasLimit)
+}
+
+// loadGasSchedule returns the gas schedule in force, or the zero schedule
+// without a GasScheduleSource
+func (app *Application) loadGasSchedule() (GasSchedule, error) {
+	if app.gasSchedule == nil {
+		return GasSchedule{}, nil
+	}
+
+	schedule, err := app.gasSchedule.GasSchedule()
+	if err != nil {
+		return GasSchedule{}, fmt.Errorf("failed to load gas schedule: %w", err)
+	}
+	if err := schedule.ValidateBasic(); err != nil {
+		return GasSchedule{}, fmt.Errorf("invalid gas schedule: %w", err)
+	}
+	return schedule, nil
+}
+
+// checkAuthorizationLimits checks every authorization of tx against the
+// transaction limits (see types.TxLimits.CheckAuthorization).
+func (app *Application) checkAuthorizationLimits(tx *types.Transaction) error {
+	if err := app.txLimits.CheckAuthorization(tx.Authorization); err != nil {
+		return err
+	}
+	if err := app.txLimits.CheckAuthorization(tx.FeePayerAuthorization); err != nil {
+		return err
+	}
+	for _, cs := range tx.CoSigners {
+		if err := app.txLimits.CheckAuthorization(cs.Authorization); err != nil {
+			return fmt.Errorf("co-signer %s: %w", cs.Account, err)
+		}
+	}
+	return nil
+}
+
+// executeTx executes a transaction, whose encoding hashes to txHash (see
+// types.TxHash), and returns the result
+func (app *Application) executeTx(ctx context.Context, tx *types.Transaction, txHash []byte) (*types.TxResult, error) {
+	// SECURITY: Bound verification work before any signature is checked
+	if err := app.checkAuthorizationLimits(tx); err != nil {
+		return txErrorResult("transaction validation failed", err), nil
+	}
+
 	// Validate transaction
 	if err := tx.ValidateBasic(); err != nil {
-		return &types.TxResult{
-			Code: 1,
-			Log:  fmt.Sprintf("transaction validation failed: %v", err),
-		}, nil
+		return txErrorResult("transaction validation failed", err), nil
 	}
 
 	// Get account
@@ -504,24 +1134,11 @@ func (app *Application) executeTx(ctx context.Context, tx *types.Transaction) (*
 	account, err := app.accountStore.Get(ctx, accountKey)
 	if err != nil {
 		if err == store.ErrNotFound {
-			return &types.TxResult{
-				Code: 1,
-				Log:  fmt.Sprintf("account not found: %s", tx.Account),
-			}, nil
+			return txErrorResult("account lookup failed", sdkerrors.Wrapf(types.ErrNotFound, "account %s", tx.Account)), nil
 		}
 		return nil, fmt.Errorf("failed to get account: %w", err)
 	}
 
-	// Verify authorization using SignDoc-based verification
-	// SECURITY: chainID binding prevents cross-chain replay attacks
-	if err := tx.VerifyAuthorization(app.chainID, account, app.accountGetter); err != nil {
-		return &types.TxResult{
-			Code: 1,
-			Log:  fmt.Sprintf("authorization verification failed: %v", err),
-		}, nil
-	}
-
-	// Create execution context
 	app.mu.RLock()
 	header := app.currentHeader
 	app.mu.RUnlock()
@@ -530,56 +1147,223 @@ func (app *Application) executeTx(ctx context.Context, tx *types.Transaction) (*
 		return nil, fmt.Errorf("no block in progress")
 	}
 
+	if err := tx.Authorization.CheckValidity(header.Height, header.Time); err != nil {
+		return txErrorResult("transaction validation failed", err), nil
+	}
+
+	// Create execution context
 	execCtx, err := NewContext(ctx, header, tx.Account)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create execution context: %w", err)
 	}
+	schedule, err := app.loadGasSchedule()
+	if err != nil {
+		return nil, err
+	}
+	execCtx = execCtx.WithTxHash(txHash).WithTxSigners(tx.Signers()...).WithGasMeter(txGasMeter(tx)).WithGasSchedule(schedule).withAuthenticators(app.authenticators)
+
+	// Verify authorization using SignDoc-based verification, or the
+	// account's authenticator
+	if err := execCtx.consumeSigGas(tx); err != nil {
+		return txErrorResult("authorization verification failed", err), nil
+	}
+	anteEffects, err := app.authenticate(execCtx, tx, account)
+	if err != nil {
+		return txErrorResult("authorization verification failed", err), nil
+	}
+	coSigners, err := app.verifyCoSigners(ctx, tx)
+	if err != nil {
+		return txErrorResult("co-signer verification failed", err), nil
+	}
+	sponsorEffects, err := app.verifyFeeSponsors(execCtx, tx, account)
+	if err != nil {
+		return txErrorResult("fee sponsor verification failed", err), nil
+	}
+	anteEffects = append(anteEffects, sponsorEffects...)
+
+	// Ante effects (e.g. fee payment) are applied in a branch of their own
+	// before any message runs
+	if app.anteHandler != nil {
+		effs, err := app.anteHandler(execCtx, tx)
+		if err != nil {
+			return txErrorResult("ante handler failed", err), nil
+		}
+		anteEffects = append(anteEffects, effs...)
+	}
+	anteEffects = append(anteEffects, execCtx.CollectEffects()...)
+	if err := app.effectApplier.Validate(anteEffects); err != nil {
+		return txErrorResult("effect execution failed", err), nil
+	}
+	if err := execCtx.consumeEffectGas(anteEffects); err != nil {
+		return txErrorResult("effect execution failed", err), nil
+	}
+
+	anteResult, err := app.applyEffects(execCtx, nil, anteEffects)
+	if err != nil {
+		return txErrorResult("effect execution failed", err), nil
+	}
+	anteEvents := toTxEvents(anteResult.Events)
+
+	var result *types.TxResult
+	if app.msgFailurePolicy == MsgFailureContinue {
+		result = app.executeMsgsContinue(execCtx, tx, anteEvents)
+	} else {
+		result = app.executeMsgsAtomic(execCtx, tx, anteEvents)
+	}
+
+	// SECURITY: Once the ante effects are applied the nonces advance, even
+	// if a message fails. The fee is paid and the gas counted, so the
+	// transaction cannot be replayed to fill blocks for free.
+	//
+	// The accounts are read again: account write effects may have replaced
+	// them.
+	if err := app.incrementNonce(ctx, tx.Account); err != nil {
+		return nil, fmt.Errorf("failed to update account nonce: %w", err)
+	}
+	for _, coSigner := range coSigners {
+		if err := app.incrementNonce(ctx, coSigner.Name); err != nil {
+			return nil, fmt.Errorf("failed to update co-signer nonce: %w", err)
+		}
+	}
+
+	result.GasUsed = execCtx.GasUsed()
+	return result, nil
+}
 
-	// Route all messages and collect effects
+// incrementNonce increments the nonce of the stored account name
+func (app *Application) incrementNonce(ctx context.Context, name types.AccountName) error {
+	key := []byte(name)
+	account, err := app.accountStore.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	updated := *account
+	updated.Nonce++
+	if err := app.accountStore.Set(ctx, key, &updated); err != nil {
+		return err
+	}
+	app.streamer.touch(key)
+	return nil
+}
+
+// executeMsgsAtomic routes every message, then applies all messages'
+// effects in one state branch. The first failure fails the transaction and
+// discards the branch; the already applied ante effects, whose events are
+// anteEvents, are kept.
+func (app *Application) executeMsgsAtomic(ctx *Context, tx *types.Transaction, anteEvents []types.Event) *types.TxResult {
+	msgs := tx.Messages
+	em := ctx.EventManager()
 	var allEffects []effects.Effect
-	for _, msg := range tx.Messages {
-		msgEffects, err := app.router.RouteMsg(execCtx, msg)
+	execEvents := len(anteEvents)
+	msgResults := make([]types.MsgResult, 0, len(msgs))
+	spans := make([]msgSpan, 0, len(msgs))
+
+	for i, msg := range msgs {
+		gasStart, eventStart := ctx.GasUsed(), em.len()
+		msgEffects, stage, err := app.routeMsg(ctx.withSigner(tx.MsgSigner(msg)), msg)
 		if err != nil {
-			return &types.TxResult{
-				Code: 1,
-				Log:  fmt.Sprintf("message execution failed: %v", err),
-			}, nil
+			result := txErrorResult(stage, err)
+			result.MsgResults = append(msgResults, msgErrorResult(msg.Type(), stage, err, ctx.GasUsed()-gasStart))
+			return result
 		}
+
 		allEffects = append(allEffects, msgEffects...)
+		msgResults = append(msgResults, types.MsgResult{Type: msg.Type(), GasUsed: ctx.GasUsed() - gasStart})
+		span := msgSpan{
+			msg:          i,
+			execStart:    execEvents,
+			execEnd:      execEvents + countEventEffects(msgEffects),
+			handlerStart: eventStart,
+			handlerEnd:   em.len(),
+		}
+		execEvents = span.execEnd
+		spans = append(spans, span)
 	}
 
-	// Add any context-emitted effects
-	allEffects = append(allEffects, execCtx.CollectEffects()...)
-
 	// Execute all effects
-	execResult, err := app.effectExecutor.Execute(allEffects)
+	execResult, err := app.applyEffects(ctx, msgs, allEffects)
 	if err != nil {
-		return &types.TxResult{
-			Code: 1,
-			Log:  fmt.Sprintf("effect execution failed: %v", err),
-		}, nil
+		return txErrorResult("effect execution failed", err)
 	}
 
-	// Increment account nonce
-	account.Nonce++
-	if err := app.accountStore.Set(ctx, accountKey, account); err != nil {
-		return nil, fmt.Errorf("failed to update account nonce: %w", err)
+	return &types.TxResult{
+		Code:       0,
+		Log:        "transaction executed successfully",
+		Events:     attachMsgEvents(msgResults, spans, append(anteEvents, toTxEvents(execResult.Events)...), em.Events()),
+		MsgResults: msgResults,
 	}
+}
 
-	// Convert execution events to transaction events
-	txEvents := make([]types.Event, len(execResult.Events))
-	for i, event := range execResult.Events {
-		txEvent := types.NewEvent(event.Type)
-		for key, value := range event.Attributes {
-			txEvent.AddAttribute(key, value)
+// executeMsgsContinue routes each message and applies its effects after
+// the ante effects, whose events are anteEvents. A failed message's effects
+// and events are discarded and the remaining messages still run.
+func (app *Application) executeMsgsContinue(ctx *Context, tx *types.Transaction, anteEvents []types.Event) *types.TxResult {
+	msgs := tx.Messages
+	em := ctx.EventManager()
+	execEvents := anteEvents
+	msgResults := make([]types.MsgResult, 0, len(msgs))
+	spans := make([]msgSpan, 0, len(msgs))
+	failed := 0
+
+	for i, msg := range msgs {
+		gasStart, eventStart := ctx.GasUsed(), em.len()
+		msgEffects, stage, err := app.routeMsg(ctx.withSigner(tx.MsgSigner(msg)), msg)
+		var execResult *effects.ExecutionResult
+		if err == nil {
+			execResult, err = app.applyEffects(ctx, []types.Message{msg}, msgEffects)
+			stage = "effect execution failed"
+		}
+		if err != nil {
+			em.truncate(eventStart)
+			msgResults = append(msgResults, msgErrorResult(msg.Type(), stage, err, ctx.GasUsed()-gasStart))
+			failed++
+			continue
 		}
-		txEvents[i] = txEvent
+
+		msgResults = append(msgResults, types.MsgResult{Type: msg.Type(), GasUsed: ctx.GasUsed() - gasStart})
+		spans = append(spans, msgSpan{
+			msg:          i,
+			execStart:    len(execEvents),
+			execEnd:      len(execEvents) + len(execResult.Events),
+			handlerStart: eventStart,
+			handlerEnd:   em.len(),
+		})
+		execEvents = append(execEvents, toTxEvents(execResult.Events)...)
 	}
 
+	log := "transaction executed successfully"
+	if failed > 0 {
+		log = fmt.Sprintf("transaction executed with %d of %d messages failed", failed, len(msgs))
+	}
 	return &types.TxResult{
-		Code:    0,
-		Log:     "transaction executed successfully",
-		Events:  txEvents,
-		GasUsed: execCtx.GasUsed(),
-	}, nil
+		Code:       0,
+		Log:        log,
+		Events:     attachMsgEvents(msgResults, spans, execEvents, em.Events()),
+		MsgResults: msgResults,
+	}
+}
+
+// routeMsg charges msg's base gas, routes it and validates its effects,
+// including those its handler emitted through ctx, charging for their
+// writes. On failure it also returns the stage that failed.
+func (app *Application) routeMsg(ctx *Context, msg types.Message) ([]effects.Effect, string, error) {
+	if err := ctx.consumeMsgGas(); err != nil {
+		return nil, "message execution failed", err
+	}
+
+	msgEffects, err := app.router.RouteMsg(ctx, msg)
+	emitted := ctx.CollectEffects()
+	if err != nil {
+		return nil, "message execution failed", err
+	}
+
+	all := append(msgEffects, emitted...)
+	if err := app.effectApplier.Validate(all); err != nil {
+		return nil, "effect execution failed", err
+	}
+	if err := ctx.consumeEffectGas(all); err != nil {
+		return nil, "effect execution failed", err
+	}
+	return all, "", nil
 }
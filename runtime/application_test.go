@@ -1,14 +1,24 @@
 package runtime
 
 import (
+	"bytes"
 	"context"
+	"crypto/ed25519"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	dbm "github.com/cosmos/cosmos-db"
 
+	"github.com/blockberries/punnet-sdk/capability"
 	"github.com/blockberries/punnet-sdk/effects"
+	sdkerrors "github.com/blockberries/punnet-sdk/errors"
+	"github.com/blockberries/punnet-sdk/query"
 	"github.com/blockberries/punnet-sdk/store"
 	"github.com/blockberries/punnet-sdk/types"
 )
@@ -70,6 +80,29 @@ func (m *testMessage) GetSigners() []types.AccountName {
 	return m.signers
 }
 
+// execTx executes tx as deliverTx does once it decoded it, with the hash
+// of its encoding
+func execTx(t *testing.T, app *Application, ctx context.Context, tx *types.Transaction) (*types.TxResult, error) {
+	t.Helper()
+	return app.executeTx(ctx, tx, testTxHash(t, app, tx))
+}
+
+// recheckTestTx rechecks tx as ReCheckTx does once it decoded it
+func recheckTestTx(t *testing.T, app *Application, ctx context.Context, tx *types.Transaction) error {
+	t.Helper()
+	return app.recheckTx(ctx, tx, testTxHash(t, app, tx))
+}
+
+// testTxHash returns the hash of the encoding of tx
+func testTxHash(t *testing.T, app *Application, tx *types.Transaction) []byte {
+	t.Helper()
+	txBytes, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+	return types.TxHash(txBytes)
+}
+
 // setupTestApp creates a test application with IAVL store
 func setupTestApp(t *testing.T) *Application {
 	t.Helper()
@@ -161,6 +194,21 @@ func TestNewApplication(t *testing.T) {
 			t.Fatal("expected error with no modules")
 		}
 	})
+
+	t.Run("unknown_msg_failure_policy", func(t *testing.T) {
+		db := dbm.NewMemDB()
+		iavlStore, _ := store.NewIAVLStore(db, 0)
+
+		_, err := NewApplication(ApplicationConfig{
+			ChainID:          "test",
+			StateStore:       iavlStore,
+			Modules:          []Module{&mockModule{name: "test"}},
+			MsgFailurePolicy: MsgFailureContinue + 1,
+		})
+		if err == nil {
+			t.Fatal("expected error with unknown message failure policy")
+		}
+	})
 }
 
 func TestApplication_BeginBlock(t *testing.T) {
@@ -282,6 +330,28 @@ func TestApplication_Commit(t *testing.T) {
 		t.Fatal("expected non-nil app hash")
 	}
 
+	// The app hash commits to the state store root
+	info, ok := app.LastCommitInfo()
+	if !ok {
+		t.Fatal("expected commit info after commit")
+	}
+	if info.Height != result.Height || len(info.Roots) != 1 || info.Roots[0].Name != types.StateStoreName {
+		t.Fatalf("unexpected commit info: %+v", info)
+	}
+	if !bytes.Equal(info.Roots[0].Hash, app.stateStore.Hash()) {
+		t.Error("expected commit info to hold the state store root")
+	}
+	appHash, err := info.AppHash()
+	if err != nil {
+		t.Fatalf("AppHash failed: %v", err)
+	}
+	if !bytes.Equal(appHash, result.AppHash) {
+		t.Error("expected app hash computed from commit info")
+	}
+	if result.CommitInfo == nil || result.CommitInfo.Height != info.Height {
+		t.Error("expected commit result to carry its commit info")
+	}
+
 	// Verify header is cleared
 	app.mu.RLock()
 	currentHeader := app.currentHeader
@@ -296,6 +366,10 @@ func TestApplication_Commit_NoBlockInProgress(t *testing.T) {
 	app := setupTestApp(t)
 	ctx := context.Background()
 
+	if _, ok := app.LastCommitInfo(); ok {
+		t.Error("expected no commit info before the first commit")
+	}
+
 	_, err := app.Commit(ctx)
 	if err == nil {
 		t.Fatal("expected error when no block in progress")
@@ -332,7 +406,7 @@ func TestApplication_CheckTx(t *testing.T) {
 	)
 
 	// Serialize transaction
-	txBytes, err := app.txSerializer.Marshal(tx)
+	txBytes, err := json.Marshal(tx)
 	if err != nil {
 		t.Fatalf("failed to serialize tx: %v", err)
 	}
@@ -396,7 +470,7 @@ func TestApplication_ExecuteTx(t *testing.T) {
 	)
 
 	// Serialize transaction
-	txBytes, err := app.txSerializer.Marshal(tx)
+	txBytes, err := json.Marshal(tx)
 	if err != nil {
 		t.Fatalf("failed to serialize tx: %v", err)
 	}
@@ -415,6 +489,67 @@ func TestApplication_ExecuteTx(t *testing.T) {
 	// but the execution path is tested
 }
 
+// TestApplication_StoresAndDecoder checks that configured account and
+// balance stores and transaction decoder replace the defaults
+func TestApplication_StoresAndDecoder(t *testing.T) {
+	ctx := context.Background()
+	iavlStore, err := store.NewIAVLStore(dbm.NewMemDB(), 0)
+	if err != nil {
+		t.Fatalf("failed to create IAVL store: %v", err)
+	}
+	accounts := store.NewCachedObjectStore[*types.Account](iavlStore, store.NewJSONSerializer[*types.Account](), 10, 10)
+	balances := store.NewBalanceStore(store.NewPrefixStore(iavlStore, []byte("balances/")))
+
+	tx := types.NewTransaction("alice", 0, []types.Message{&testMessage{msgType: "test.msg", signers: []types.AccountName{"alice"}}},
+		&types.Authorization{Signatures: []types.Signature{{Algorithm: types.AlgorithmEd25519, PubKey: make([]byte, 32), Signature: make([]byte, 64)}}})
+	tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+	decoded := map[string]*types.Transaction{"tx-1": tx}
+	app, err := NewApplication(ApplicationConfig{
+		ChainID:      "test-chain",
+		StateStore:   iavlStore,
+		Modules:      []Module{&mockModule{name: "test"}},
+		AccountStore: accounts,
+		BalanceStore: balances,
+		TxDecoder: func(txBytes []byte) (*types.Transaction, error) {
+			tx, ok := decoded[string(txBytes)]
+			if !ok {
+				return nil, fmt.Errorf("unknown transaction %q", txBytes)
+			}
+			return tx, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create application: %v", err)
+	}
+	if app.AccountStore() != accounts || app.BalanceStore() != balances {
+		t.Fatal("configured stores not used")
+	}
+
+	if err := app.BeginBlock(ctx, NewBlockHeader(1, time.Now(), "test-chain", nil)); err != nil {
+		t.Fatalf("BeginBlock failed: %v", err)
+	}
+
+	// The decoded transaction gets as far as the account lookup
+	result, err := app.ExecuteTx(ctx, []byte("tx-1"))
+	if err != nil {
+		t.Fatalf("ExecuteTx failed: %v", err)
+	}
+	if codespace, code := sdkerrors.ABCICode(types.ErrNotFound); result.Codespace != codespace || result.Code != code {
+		t.Fatalf("result = %s/%d (%s), want ErrNotFound", result.Codespace, result.Code, result.Log)
+	}
+
+	result, err = app.ExecuteTx(ctx, []byte("tx-2"))
+	if err != nil {
+		t.Fatalf("ExecuteTx failed: %v", err)
+	}
+	if codespace, code := sdkerrors.ABCICode(types.ErrInvalidTransaction); result.Codespace != codespace || result.Code != code {
+		t.Fatalf("result = %s/%d (%s), want ErrInvalidTransaction", result.Codespace, result.Code, result.Log)
+	}
+	if err := app.CheckTx(ctx, []byte("tx-2")); err == nil {
+		t.Fatal("CheckTx accepted undecodable transaction")
+	}
+}
+
 func TestApplication_ExecuteTx_NoBlockInProgress(t *testing.T) {
 	app := setupTestApp(t)
 	ctx := context.Background()
@@ -436,7 +571,7 @@ func TestApplication_ExecuteTx_NoBlockInProgress(t *testing.T) {
 		},
 	)
 
-	txBytes, _ := app.txSerializer.Marshal(tx)
+	txBytes, _ := json.Marshal(tx)
 
 	// Should return TxResult with error code (not fail) - ExecuteTx handles errors gracefully
 	result, err := app.ExecuteTx(ctx, txBytes)
@@ -474,6 +609,168 @@ func TestApplication_Query(t *testing.T) {
 	}
 }
 
+// queryServiceModule is a mockModule that also registers query services
+type queryServiceModule struct {
+	mockModule
+	services map[string]query.Handler
+}
+
+func (m *queryServiceModule) RegisterQueryServices(server *query.Server) error {
+	for path, handler := range m.services {
+		if err := server.RegisterHandler(path, handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestApplication_QueryServices(t *testing.T) {
+	iavlStore, err := store.NewIAVLStore(dbm.NewMemDB(), 0)
+	if err != nil {
+		t.Fatalf("failed to create IAVL store: %v", err)
+	}
+
+	mod := &queryServiceModule{
+		mockModule: mockModule{name: "svc"},
+		services: map[string]query.Handler{
+			"/svc/get": func(ctx *query.Context, data []byte) ([]byte, error) {
+				return ctx.Store().Get(data)
+			},
+		},
+	}
+	app, err := NewApplication(ApplicationConfig{
+		ChainID:    "test-chain",
+		StateStore: iavlStore,
+		Modules:    []Module{mod},
+	})
+	if err != nil {
+		t.Fatalf("failed to create application: %v", err)
+	}
+	if paths := app.QueryServer().Paths(); len(paths) != 2 || paths[0] != QueryPathAccountNonce || paths[1] != "/svc/get" {
+		t.Fatalf("unexpected query service paths %v", paths)
+	}
+
+	if err := iavlStore.Set([]byte("key"), []byte("value")); err != nil {
+		t.Fatalf("failed to set key: %v", err)
+	}
+	if _, _, err := iavlStore.SaveVersion(); err != nil {
+		t.Fatalf("failed to save version: %v", err)
+	}
+	ctx := context.Background()
+
+	result, err := app.Query(ctx, "/svc/get", []byte("key"), 0)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if result.Code != 0 || string(result.Data) != "value" || result.Height != 1 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	result, err = app.QueryRequest(ctx, query.Request{Path: query.StoreKeyPath, Data: []byte("key"), Prove: true})
+	if err != nil {
+		t.Fatalf("QueryRequest failed: %v", err)
+	}
+	if result.Code != 0 || string(result.Data) != "value" || len(result.Proof) == 0 {
+		t.Fatalf("expected value with proof, got %+v", result)
+	}
+
+	// Router handlers serve only the latest height and cannot prove
+	result, err = app.QueryRequest(ctx, query.Request{Path: "/svc/get", Data: []byte("key"), Height: 5})
+	if err != nil {
+		t.Fatalf("QueryRequest failed: %v", err)
+	}
+	if result.Code == 0 {
+		t.Fatal("expected failure for height above latest")
+	}
+}
+
+func TestApplication_QueryBatch(t *testing.T) {
+	iavlStore, err := store.NewIAVLStore(dbm.NewMemDB(), 0)
+	if err != nil {
+		t.Fatalf("failed to create IAVL store: %v", err)
+	}
+
+	mod := &queryServiceModule{
+		mockModule: mockModule{name: "svc"},
+		services: map[string]query.Handler{
+			"/svc/get": func(ctx *query.Context, data []byte) ([]byte, error) {
+				return ctx.Store().Get(data)
+			},
+		},
+	}
+	app, err := NewApplication(ApplicationConfig{
+		ChainID:    "test-chain",
+		StateStore: iavlStore,
+		Modules:    []Module{mod},
+	})
+	if err != nil {
+		t.Fatalf("failed to create application: %v", err)
+	}
+
+	for _, value := range []string{"v1", "v2"} {
+		if err := iavlStore.Set([]byte("key"), []byte(value)); err != nil {
+			t.Fatalf("failed to set key: %v", err)
+		}
+		if _, _, err := iavlStore.SaveVersion(); err != nil {
+			t.Fatalf("failed to save version: %v", err)
+		}
+	}
+	ctx := context.Background()
+
+	batch := func(height int64, reqs ...query.Request) (*types.QueryResult, []*types.QueryResult) {
+		t.Helper()
+		data, err := json.Marshal(QueryBatchRequest{Requests: reqs})
+		if err != nil {
+			t.Fatalf("failed to encode batch: %v", err)
+		}
+		result, err := app.QueryRequest(ctx, query.Request{Path: QueryPathBatch, Data: data, Height: height})
+		if err != nil {
+			t.Fatalf("QueryRequest failed: %v", err)
+		}
+		if result.Code != 0 {
+			return result, nil
+		}
+		var resp QueryBatchResponse
+		if err := json.Unmarshal(result.Data, &resp); err != nil {
+			t.Fatalf("failed to decode batch response: %v", err)
+		}
+		return result, resp.Results
+	}
+
+	result, results := batch(1,
+		query.Request{Path: "/svc/get", Data: []byte("key")},
+		query.Request{Path: "/svc/get", Data: []byte("key"), Height: 2},
+		query.Request{Path: "/unknown"},
+		query.Request{Path: QueryPathBatch},
+	)
+	if result.Height != 1 || len(results) != 4 {
+		t.Fatalf("unexpected batch result %+v with %d results", result, len(results))
+	}
+	if string(results[0].Data) != "v1" || results[0].Height != 1 {
+		t.Fatalf("expected v1 at the batch height, got %+v", results[0])
+	}
+	if string(results[1].Data) != "v2" || results[1].Height != 2 {
+		t.Fatalf("expected v2 at the request height, got %+v", results[1])
+	}
+	if results[2].Code == 0 {
+		t.Fatal("expected unknown path to fail without failing the batch")
+	}
+	if results[3].Code == 0 || !strings.Contains(results[3].Log, ErrInvalidQueryBatch.Error()) {
+		t.Fatalf("expected nested batch to fail, got %+v", results[3])
+	}
+
+	// Height 0 pins the latest height
+	result, results = batch(0, query.Request{Path: "/svc/get", Data: []byte("key")})
+	if result.Height != 2 || string(results[0].Data) != "v2" {
+		t.Fatalf("unexpected latest batch %+v: %+v", result, results[0])
+	}
+
+	result, _ = batch(0, make([]query.Request, MaxQueryBatchSize+1)...)
+	if result.Code == 0 || result.Codespace != CodespaceRuntime {
+		t.Fatalf("expected oversized batch to fail, got %+v", result)
+	}
+}
+
 func TestApplication_Query_EmptyPath(t *testing.T) {
 	app := setupTestApp(t)
 	ctx := context.Background()
@@ -498,6 +795,229 @@ func TestApplication_Query_NotFound(t *testing.T) {
 	}
 }
 
+func TestApplication_ResultCodes(t *testing.T) {
+	app := setupTestApp(t)
+	ctx := context.Background()
+
+	wantCode := func(t *testing.T, err error) (string, uint32) {
+		t.Helper()
+		codespace, code := sdkerrors.ABCICode(err)
+		if code == sdkerrors.CodeInternal {
+			t.Fatalf("no code registered for %v", err)
+		}
+		return codespace, code
+	}
+
+	result, err := app.ExecuteTx(ctx, []byte("not a transaction"))
+	if err != nil {
+		t.Fatalf("ExecuteTx failed: %v", err)
+	}
+	codespace, code := wantCode(t, types.ErrInvalidTransaction)
+	if result.Codespace != codespace || result.Code != code {
+		t.Fatalf("expected %s/%d for undecodable tx, got %s/%d", codespace, code, result.Codespace, result.Code)
+	}
+
+	tx := types.NewTransaction("nobody", 0, []types.Message{&testMessage{msgType: "test.msg", signers: []types.AccountName{"nobody"}}},
+		&types.Authorization{Signatures: []types.Signature{{Algorithm: types.AlgorithmEd25519, PubKey: make([]byte, 32), Signature: make([]byte, 64)}}})
+	tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+	result, err = execTx(t, app, ctx, tx)
+	if err != nil {
+		t.Fatalf("executeTx failed: %v", err)
+	}
+	codespace, code = wantCode(t, types.ErrNotFound)
+	if result.Codespace != codespace || result.Code != code {
+		t.Fatalf("expected %s/%d for unknown account, got %s/%d: %s", codespace, code, result.Codespace, result.Code, result.Log)
+	}
+
+	qresult, err := app.QueryRequest(ctx, query.Request{Path: "/test/query", Height: 5})
+	if err != nil {
+		t.Fatalf("QueryRequest failed: %v", err)
+	}
+	codespace, code = wantCode(t, query.ErrHeightNotAvailable)
+	if qresult.Codespace != codespace || qresult.Code != code {
+		t.Fatalf("expected %s/%d for unavailable height, got %s/%d", codespace, code, qresult.Codespace, qresult.Code)
+	}
+}
+
+func TestApplication_TxLimits(t *testing.T) {
+	ctx := context.Background()
+	iavlStore, err := store.NewIAVLStore(dbm.NewMemDB(), 0)
+	if err != nil {
+		t.Fatalf("failed to create IAVL store: %v", err)
+	}
+	app, err := NewApplication(ApplicationConfig{
+		ChainID:    "test-chain",
+		StateStore: iavlStore,
+		Modules:    []Module{&mockModule{name: "test"}},
+		TxLimits:   types.TxLimits{MaxTxBytes: 64, MaxSignatures: 2},
+	})
+	if err != nil {
+		t.Fatalf("failed to create application: %v", err)
+	}
+	if app.txLimits.MaxAuthorizationDepth != types.DefaultMaxAuthorizationDepth {
+		t.Fatalf("expected default depth limit, got %d", app.txLimits.MaxAuthorizationDepth)
+	}
+
+	oversized := make([]byte, 65)
+	if err := app.CheckTx(ctx, oversized); !errors.Is(err, types.ErrTxTooLarge) {
+		t.Fatalf("expected ErrTxTooLarge from CheckTx, got %v", err)
+	}
+	result, err := app.ExecuteTx(ctx, oversized)
+	if err != nil {
+		t.Fatalf("ExecuteTx failed: %v", err)
+	}
+	codespace, code := sdkerrors.ABCICode(types.ErrTxTooLarge)
+	if result.Codespace != codespace || result.Code != code {
+		t.Fatalf("expected %s/%d for oversized tx, got %s/%d", codespace, code, result.Codespace, result.Code)
+	}
+
+	// The signature limit is enforced before the account is even looked up
+	sig := types.Signature{Algorithm: types.AlgorithmEd25519, PubKey: make([]byte, 32), Signature: make([]byte, 64)}
+	tx := types.NewTransaction("nobody", 0, []types.Message{&testMessage{msgType: "test.msg", signers: []types.AccountName{"nobody"}}},
+		&types.Authorization{Signatures: []types.Signature{sig, sig, sig}})
+	tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+	result, err = execTx(t, app, ctx, tx)
+	if err != nil {
+		t.Fatalf("executeTx failed: %v", err)
+	}
+	codespace, code = sdkerrors.ABCICode(types.ErrTooManySignatures)
+	if result.Codespace != codespace || result.Code != code {
+		t.Fatalf("expected %s/%d for too many signatures, got %s/%d: %s", codespace, code, result.Codespace, result.Code, result.Log)
+	}
+
+	_, err = NewApplication(ApplicationConfig{
+		ChainID:    "test-chain",
+		StateStore: iavlStore,
+		Modules:    []Module{&mockModule{name: "test"}},
+		TxLimits:   types.TxLimits{MaxAuthorizationDepth: types.MaxRecursionDepth + 1},
+	})
+	if err == nil {
+		t.Fatal("expected error for depth limit above MaxRecursionDepth")
+	}
+}
+
+func TestApplication_ValidityWindow(t *testing.T) {
+	app := setupTestApp(t)
+	ctx := context.Background()
+
+	if err := app.BeginBlock(ctx, NewBlockHeader(5, time.Now(), "test-chain", nil)); err != nil {
+		t.Fatalf("BeginBlock failed: %v", err)
+	}
+	pubKey := make([]byte, 32)
+	if err := app.accountStore.Set(ctx, []byte("alice"), types.NewAccount("alice", pubKey)); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+
+	newTx := func(notBefore, notAfter *types.ValidityBound) *types.Transaction {
+		sig := types.Signature{Algorithm: types.AlgorithmEd25519, PubKey: pubKey, Signature: make([]byte, 64)}
+		tx := types.NewTransaction("alice", 0, []types.Message{&testMessage{msgType: "test.msg", signers: []types.AccountName{"alice"}}},
+			&types.Authorization{Signatures: []types.Signature{sig}, NotBefore: notBefore, NotAfter: notAfter})
+		tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+		return tx
+	}
+
+	tests := []struct {
+		name    string
+		tx      *types.Transaction
+		wantErr error
+	}{
+		{name: "not yet valid", tx: newTx(types.HeightBound(10), nil), wantErr: types.ErrTxNotYetValid},
+		{name: "expired", tx: newTx(nil, types.HeightBound(4)), wantErr: types.ErrTxExpired},
+		// Inside the window, verification proceeds to the (invalid) signature
+		{name: "within window", tx: newTx(types.HeightBound(5), types.HeightBound(5)), wantErr: types.ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := execTx(t, app, ctx, tt.tx)
+			if err != nil {
+				t.Fatalf("executeTx failed: %v", err)
+			}
+			codespace, code := sdkerrors.ABCICode(tt.wantErr)
+			if result.Codespace != codespace || result.Code != code {
+				t.Fatalf("expected %s/%d, got %s/%d: %s", codespace, code, result.Codespace, result.Code, result.Log)
+			}
+
+		})
+	}
+}
+
+func TestApplication_CheckTx_ValidityWindow(t *testing.T) {
+	app := setupTestApp(t)
+	ctx := context.Background()
+
+	// Commit three blocks; transactions are checked against the next one
+	blockTime := time.Unix(1_700_000_000, 0)
+	var next uint64
+	for i := 0; i < 3; i++ {
+		if err := app.BeginBlock(ctx, NewBlockHeader(next+1, blockTime, "test-chain", nil)); err != nil {
+			t.Fatalf("BeginBlock failed: %v", err)
+		}
+		commit, err := app.Commit(ctx)
+		if err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+		next = commit.Height + 1
+	}
+	pubKey := make([]byte, 32)
+	if err := app.accountStore.Set(ctx, []byte("alice"), types.NewAccount("alice", pubKey)); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+
+	newTx := func(notBefore, notAfter *types.ValidityBound) *types.Transaction {
+		sig := types.Signature{Algorithm: types.AlgorithmEd25519, PubKey: pubKey, Signature: make([]byte, 64)}
+		tx := types.NewTransaction("alice", 0, []types.Message{&testMessage{msgType: "test.msg", signers: []types.AccountName{"alice"}}},
+			&types.Authorization{Signatures: []types.Signature{sig}, NotBefore: notBefore, NotAfter: notAfter})
+		tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+		return tx
+	}
+
+	tests := []struct {
+		name    string
+		tx      *types.Transaction
+		wantErr error
+	}{
+		{name: "expired height", tx: newTx(nil, types.HeightBound(next-1)), wantErr: types.ErrTxExpired},
+		{name: "not yet valid height", tx: newTx(types.HeightBound(next+1), nil), wantErr: types.ErrTxNotYetValid},
+		{name: "expired time", tx: newTx(nil, types.TimeBound(blockTime.Add(-time.Second))), wantErr: types.ErrTxExpired},
+		// Inside the window, checking proceeds to the (invalid) signature
+		{name: "next block", tx: newTx(types.HeightBound(next), types.HeightBound(next)), wantErr: types.ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := app.checkTx(ctx, tt.tx, testTxHash(t, app, tt.tx))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestTxErrorResult_Redaction(t *testing.T) {
+	result := txErrorResult("message execution failed", sdkerrors.Wrapf(types.ErrInsufficientFunds, "need %d", 5))
+	if result.Codespace != sdkerrors.CodespaceSDK || result.Code == sdkerrors.CodeInternal {
+		t.Fatalf("expected registered code, got %s/%d", result.Codespace, result.Code)
+	}
+	if result.Log != "message execution failed: insufficient funds" {
+		t.Fatalf("unexpected log %q", result.Log)
+	}
+
+	// Unregistered text wrapped in a registered error is dropped as well
+	result = txErrorResult("message execution failed", fmt.Errorf("%w: %v", types.ErrInsufficientFunds, errors.New("read 0xc000123: i/o timeout")))
+	if result.Log != "message execution failed: insufficient funds" {
+		t.Fatalf("unexpected log %q", result.Log)
+	}
+
+	// Unregistered error text never reaches the result
+	result = txErrorResult("message execution failed", errors.New("open /var/data/0xc000123: too many open files"))
+	if result.Codespace != sdkerrors.CodespaceSDK || result.Code != sdkerrors.CodeInternal {
+		t.Fatalf("expected internal code, got %s/%d", result.Codespace, result.Code)
+	}
+	if result.Log != "message execution failed: internal error" {
+		t.Fatalf("unexpected log %q", result.Log)
+	}
+}
+
 func TestApplication_InitChain(t *testing.T) {
 	app := setupTestApp(t)
 	ctx := context.Background()
@@ -665,3 +1185,1313 @@ func TestApplication_Accessors(t *testing.T) {
 		t.Error("expected non-nil balance store")
 	}
 }
+
+func TestApplication_LifecycleOrder(t *testing.T) {
+	db := dbm.NewMemDB()
+	iavlStore, err := store.NewIAVLStore(db, 0)
+	if err != nil {
+		t.Fatalf("failed to create IAVL store: %v", err)
+	}
+
+	var calls []string
+	newModule := func(name string) *mockModule {
+		return &mockModule{
+			name: name,
+			beginBlocker: func(ctx *Context) ([]effects.Effect, error) {
+				calls = append(calls, "begin:"+name)
+				return nil, nil
+			},
+			endBlocker: func(ctx *Context) ([]effects.Effect, []types.ValidatorUpdate, error) {
+				calls = append(calls, "end:"+name)
+				return nil, nil, nil
+			},
+		}
+	}
+	modules := []Module{newModule("auth"), newModule("bank")}
+
+	t.Run("invalid order", func(t *testing.T) {
+		for _, order := range [][]string{{"bank"}, {"bank", "bank"}, {"bank", "auth", "staking"}} {
+			_, err := NewApplication(ApplicationConfig{
+				ChainID:         "test-chain",
+				StateStore:      iavlStore,
+				Modules:         modules,
+				BeginBlockOrder: order,
+			})
+			if !errors.Is(err, ErrInvalidModuleOrder) {
+				t.Errorf("order %v: expected ErrInvalidModuleOrder, got %v", order, err)
+			}
+		}
+	})
+
+	t.Run("custom order", func(t *testing.T) {
+		app, err := NewApplication(ApplicationConfig{
+			ChainID:         "test-chain",
+			StateStore:      iavlStore,
+			Modules:         modules,
+			BeginBlockOrder: []string{"bank", "auth"},
+		})
+		if err != nil {
+			t.Fatalf("failed to create application: %v", err)
+		}
+
+		header := NewBlockHeader(1, time.Now(), "test-chain", nil)
+		if err := app.callBeginBlockers(context.Background(), header); err != nil {
+			t.Fatalf("BeginBlock failed: %v", err)
+		}
+		if _, err := app.callEndBlockers(context.Background(), header); err != nil {
+			t.Fatalf("EndBlock failed: %v", err)
+		}
+
+		// EndBlock has no custom order and falls back to name order.
+		want := []string{"begin:bank", "begin:auth", "end:auth", "end:bank"}
+		if len(calls) != len(want) {
+			t.Fatalf("expected calls %v, got %v", want, calls)
+		}
+		for i := range want {
+			if calls[i] != want[i] {
+				t.Fatalf("expected calls %v, got %v", want, calls)
+			}
+		}
+	})
+}
+
+func TestApplication_AnteHandler(t *testing.T) {
+	app := setupTestApp(t)
+	ctx := context.Background()
+
+	if err := app.BeginBlock(ctx, NewBlockHeader(1, time.Now(), "test-chain", nil)); err != nil {
+		t.Fatalf("BeginBlock failed: %v", err)
+	}
+	priv := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	pubKey := priv.Public().(ed25519.PublicKey)
+	if err := app.accountStore.Set(ctx, []byte("alice"), types.NewAccount("alice", pubKey)); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+
+	newTx := func(nonce uint64) *types.Transaction {
+		tx := types.NewTransaction("alice", nonce, []types.Message{&testMessage{msgType: "test.msg", signers: []types.AccountName{"alice"}}},
+			types.NewAuthorization())
+		tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+		signDoc, err := tx.ToSignDoc("test-chain", nonce)
+		if err != nil {
+			t.Fatalf("failed to build sign doc: %v", err)
+		}
+		signBytes, err := signDoc.GetSignBytes()
+		if err != nil {
+			t.Fatalf("failed to get sign bytes: %v", err)
+		}
+		tx.Authorization.Signatures = []types.Signature{{Algorithm: types.AlgorithmEd25519, PubKey: pubKey, Signature: ed25519.Sign(priv, signBytes)}}
+		return tx
+	}
+
+	var calls []string
+	recordAnte := func(name string, err error) AnteHandler {
+		return func(ctx *Context, tx *types.Transaction) ([]effects.Effect, error) {
+			calls = append(calls, name)
+			if err != nil {
+				return nil, err
+			}
+			return []effects.Effect{effects.NewEventEffect("ante."+name, map[string][]byte{"account": []byte(tx.Account)})}, nil
+		}
+	}
+
+	// Chained handlers run in order and their effects are applied
+	app.anteHandler = ChainAnteHandlers(recordAnte("first", nil), nil, recordAnte("second", nil))
+	result, err := execTx(t, app, ctx, newTx(0))
+	if err != nil {
+		t.Fatalf("executeTx failed: %v", err)
+	}
+	if result.Code != 0 {
+		t.Fatalf("expected success, got %d: %s", result.Code, result.Log)
+	}
+	if len(calls) != 2 || calls[0] != "first" || calls[1] != "second" {
+		t.Fatalf("expected [first second], got %v", calls)
+	}
+	var eventTypes []string
+	for _, event := range result.Events {
+		eventTypes = append(eventTypes, event.Type)
+	}
+	if len(eventTypes) != 2 || eventTypes[0] != "ante.first" || eventTypes[1] != "ante.second" {
+		t.Fatalf("expected ante events, got %v", eventTypes)
+	}
+
+	// An ante error fails the transaction and stops the chain
+	calls = nil
+	app.anteHandler = ChainAnteHandlers(recordAnte("first", types.ErrInsufficientFunds), recordAnte("second", nil))
+	result, err = execTx(t, app, ctx, newTx(1))
+	if err != nil {
+		t.Fatalf("executeTx failed: %v", err)
+	}
+	codespace, code := sdkerrors.ABCICode(types.ErrInsufficientFunds)
+	if result.Codespace != codespace || result.Code != code {
+		t.Fatalf("expected %s/%d, got %s/%d: %s", codespace, code, result.Codespace, result.Code, result.Log)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("expected chain to stop after first handler, got %v", calls)
+	}
+
+	// The nonce did not advance, so the same nonce succeeds once the ante passes
+	account, err := app.accountStore.Get(ctx, []byte("alice"))
+	if err != nil {
+		t.Fatalf("failed to get account: %v", err)
+	}
+	if account.Nonce != 1 {
+		t.Fatalf("expected nonce 1, got %d", account.Nonce)
+	}
+}
+
+func TestApplication_TxRandomSeed(t *testing.T) {
+	app := setupTestApp(t)
+	ctx := context.Background()
+
+	var seeds [][32]byte
+	var hashes [][]byte
+	if err := app.router.RegisterModule(&mockModule{
+		name: "seed",
+		msgHandlers: map[string]MsgHandler{
+			"seed.record": func(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+				seeds = append(seeds, ctx.RandomSeed())
+				hashes = append(hashes, ctx.TxHash())
+				return nil, nil
+			},
+		},
+	}); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	if err := app.BeginBlock(ctx, NewBlockHeader(1, time.Now(), "test-chain", nil)); err != nil {
+		t.Fatalf("BeginBlock failed: %v", err)
+	}
+	priv := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	pubKey := priv.Public().(ed25519.PublicKey)
+	if err := app.accountStore.Set(ctx, []byte("alice"), types.NewAccount("alice", pubKey)); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+
+	// Two transactions of the same account and message types in one block
+	for nonce := uint64(0); nonce < 2; nonce++ {
+		tx := types.NewTransaction("alice", nonce, []types.Message{&testMessage{msgType: "seed.record", signers: []types.AccountName{"alice"}}},
+			types.NewAuthorization())
+		tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+		signDoc, err := tx.ToSignDoc("test-chain", nonce)
+		if err != nil {
+			t.Fatalf("failed to build sign doc: %v", err)
+		}
+		signBytes, err := signDoc.GetSignBytes()
+		if err != nil {
+			t.Fatalf("failed to get sign bytes: %v", err)
+		}
+		tx.Authorization.Signatures = []types.Signature{{Algorithm: types.AlgorithmEd25519, PubKey: pubKey, Signature: ed25519.Sign(priv, signBytes)}}
+
+		result, err := execTx(t, app, ctx, tx)
+		if err != nil || !result.IsOK() {
+			t.Fatalf("executeTx failed: %v %+v", err, result)
+		}
+		if !bytes.Equal(hashes[nonce], testTxHash(t, app, tx)) {
+			t.Fatalf("tx %d: context hash %x is not the hash of its encoding", nonce, hashes[nonce])
+		}
+	}
+	if seeds[0] == seeds[1] {
+		t.Fatal("expected distinct transactions to get distinct random seeds")
+	}
+}
+
+func TestApplication_MsgFailurePolicy(t *testing.T) {
+	priv := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	pubKey := priv.Public().(ed25519.PublicKey)
+
+	written := func(msgType string) []byte {
+		return effects.NewStateWriteEffect("policy", []byte(msgType), []byte("v")).Key()
+	}
+	policyModule := &mockModule{
+		name: "policy",
+		msgHandlers: map[string]MsgHandler{
+			// Writes a key named after the message type
+			"policy.write.a": policyWriteHandler,
+			"policy.write.b": policyWriteHandler,
+			// Fails in its handler
+			"policy.fail": func(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+				if err := ctx.ConsumeGas(5); err != nil {
+					return nil, err
+				}
+				ctx.EventManager().EmitEvent(types.NewEvent("policy.discarded"))
+				return nil, fmt.Errorf("%w: policy.fail", types.ErrInsufficientFunds)
+			},
+			// Fails while its effects are executed
+			"policy.overdraw": func(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+				ctx.EventManager().EmitEvent(types.NewEvent("policy.discarded"))
+				return []effects.Effect{effects.TransferEffect{From: "alice", To: "bob", Amount: types.NewCoins(types.NewCoin("token", 1))}}, nil
+			},
+			// Writes a key, then fails on the transfer after it
+			"policy.partial": func(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+				return []effects.Effect{
+					effects.NewStateWriteEffect("policy", []byte(msg.Type()), []byte("v")),
+					effects.TransferEffect{From: "alice", To: "bob", Amount: types.NewCoins(types.NewCoin("token", 1))},
+				}, nil
+			},
+		},
+	}
+
+	setup := func(t *testing.T, policy MsgFailurePolicy) (*Application, func(msgTypes ...string) *types.Transaction) {
+		t.Helper()
+		ctx := context.Background()
+
+		app := setupTestApp(t)
+		app.msgFailurePolicy = policy
+		if err := app.router.RegisterModule(policyModule); err != nil {
+			t.Fatalf("failed to register module: %v", err)
+		}
+		if err := app.BeginBlock(ctx, NewBlockHeader(1, time.Now(), "test-chain", nil)); err != nil {
+			t.Fatalf("BeginBlock failed: %v", err)
+		}
+		if err := app.accountStore.Set(ctx, []byte("alice"), types.NewAccount("alice", pubKey)); err != nil {
+			t.Fatalf("failed to create account: %v", err)
+		}
+
+		nonce := uint64(0)
+		newTx := func(msgTypes ...string) *types.Transaction {
+			msgs := make([]types.Message, len(msgTypes))
+			for i, msgType := range msgTypes {
+				msgs[i] = &testMessage{msgType: msgType, signers: []types.AccountName{"alice"}}
+			}
+			tx := types.NewTransaction("alice", nonce, msgs, types.NewAuthorization())
+			tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+			signDoc, err := tx.ToSignDoc("test-chain", nonce)
+			if err != nil {
+				t.Fatalf("failed to build sign doc: %v", err)
+			}
+			signBytes, err := signDoc.GetSignBytes()
+			if err != nil {
+				t.Fatalf("failed to get sign bytes: %v", err)
+			}
+			tx.Authorization.Signatures = []types.Signature{{Algorithm: types.AlgorithmEd25519, PubKey: pubKey, Signature: ed25519.Sign(priv, signBytes)}}
+			return tx
+		}
+		return app, func(msgTypes ...string) *types.Transaction {
+			tx := newTx(msgTypes...)
+			nonce++
+			return tx
+		}
+	}
+
+	execute := func(t *testing.T, app *Application, tx *types.Transaction) *types.TxResult {
+		t.Helper()
+		result, err := execTx(t, app, context.Background(), tx)
+		if err != nil {
+			t.Fatalf("executeTx failed: %v", err)
+		}
+		return result
+	}
+
+	checkWritten := func(t *testing.T, app *Application, msgType string, want bool) {
+		t.Helper()
+		has, err := app.stateStore.Has(written(msgType))
+		if err != nil {
+			t.Fatalf("failed to read state: %v", err)
+		}
+		if has != want {
+			t.Fatalf("expected %s written: %v, got %v", msgType, want, has)
+		}
+	}
+
+	checkNonce := func(t *testing.T, app *Application, want uint64) {
+		t.Helper()
+		account, err := app.accountStore.Get(context.Background(), []byte("alice"))
+		if err != nil {
+			t.Fatalf("failed to get account: %v", err)
+		}
+		if account.Nonce != want {
+			t.Fatalf("expected nonce %d, got %d", want, account.Nonce)
+		}
+	}
+
+	msgIndex := func(event types.Event) string {
+		for _, attr := range event.Attributes {
+			if attr.Key == AttributeKeyMsgIndex {
+				return string(attr.Value)
+			}
+		}
+		return ""
+	}
+
+	failCodespace, failCode := sdkerrors.ABCICode(types.ErrInsufficientFunds)
+
+	t.Run("success reports every message", func(t *testing.T) {
+		for _, policy := range []MsgFailurePolicy{MsgFailureAtomic, MsgFailureContinue} {
+			app, newTx := setup(t, policy)
+			result := execute(t, app, newTx("policy.write.a", "policy.write.b"))
+			if !result.IsOK() {
+				t.Fatalf("%s: expected success, got %d: %s", policy, result.Code, result.Log)
+			}
+			if len(result.MsgResults) != 2 {
+				t.Fatalf("%s: expected 2 message results, got %d", policy, len(result.MsgResults))
+			}
+
+			var gasUsed uint64
+			for i, msgResult := range result.MsgResults {
+				if !msgResult.IsOK() || msgResult.GasUsed != 10 {
+					t.Fatalf("%s: unexpected result of message %d: %+v", policy, i, msgResult)
+				}
+				gasUsed += msgResult.GasUsed
+				// One execution event and one handler event, both tagged
+				if len(msgResult.Events) != 2 {
+					t.Fatalf("%s: expected 2 events for message %d, got %d", policy, i, len(msgResult.Events))
+				}
+				for _, event := range msgResult.Events {
+					if msgIndex(event) != strconv.Itoa(i) {
+						t.Fatalf("%s: event %s of message %d has msg_index %q", policy, event.Type, i, msgIndex(event))
+					}
+				}
+			}
+			if result.GasUsed != gasUsed {
+				t.Fatalf("%s: expected tx gas %d, got %d", policy, gasUsed, result.GasUsed)
+			}
+			if len(result.Events) != 4 {
+				t.Fatalf("%s: expected 4 tx events, got %d", policy, len(result.Events))
+			}
+			checkWritten(t, app, "policy.write.a", true)
+			checkWritten(t, app, "policy.write.b", true)
+			checkNonce(t, app, 1)
+		}
+	})
+
+	t.Run("atomic fails the transaction", func(t *testing.T) {
+		app, newTx := setup(t, MsgFailureAtomic)
+		result := execute(t, app, newTx("policy.write.a", "policy.fail", "policy.write.b"))
+		if result.Codespace != failCodespace || result.Code != failCode {
+			t.Fatalf("expected %s/%d, got %s/%d: %s", failCodespace, failCode, result.Codespace, result.Code, result.Log)
+		}
+
+		// The failed message is reported; the one after it never ran
+		if len(result.MsgResults) != 2 {
+			t.Fatalf("expected 2 message results, got %d", len(result.MsgResults))
+		}
+		failed := result.MsgResults[1]
+		if failed.Type != "policy.fail" || failed.Codespace != failCodespace || failed.Code != failCode || failed.GasUsed != 5 {
+			t.Fatalf("unexpected failed message result: %+v", failed)
+		}
+
+		// The messages' writes are discarded, but the nonce advances
+		checkWritten(t, app, "policy.write.a", false)
+		checkWritten(t, app, "policy.write.b", false)
+		checkNonce(t, app, 1)
+
+		// Execution failures fail the transaction as well
+		result = execute(t, app, newTx("policy.overdraw"))
+		if result.IsOK() || !strings.Contains(result.Log, "insufficient funds") {
+			t.Fatalf("expected overdraw to fail the transaction, got %d: %s", result.Code, result.Log)
+		}
+		checkNonce(t, app, 2)
+
+		// A failure part-way through the effects discards the writes that
+		// ran before it, and the transaction cannot be replayed
+		app, newTx = setup(t, MsgFailureAtomic)
+		partial := newTx("policy.write.a", "policy.partial")
+		result = execute(t, app, partial)
+		if result.IsOK() || !strings.Contains(result.Log, "insufficient funds") {
+			t.Fatalf("expected the transfer to fail the transaction, got %d: %s", result.Code, result.Log)
+		}
+		checkWritten(t, app, "policy.write.a", false)
+		checkWritten(t, app, "policy.partial", false)
+		checkNonce(t, app, 1)
+
+		result = execute(t, app, partial)
+		if result.IsOK() || !strings.Contains(result.Log, "sequence mismatch") {
+			t.Fatalf("expected the replay to be rejected, got %d: %s", result.Code, result.Log)
+		}
+		checkNonce(t, app, 1)
+	})
+
+	t.Run("continue skips failed messages", func(t *testing.T) {
+		app, newTx := setup(t, MsgFailureContinue)
+		result := execute(t, app, newTx("policy.write.a", "policy.fail", "policy.overdraw", "policy.write.b"))
+		if !result.IsOK() {
+			t.Fatalf("expected success, got %d: %s", result.Code, result.Log)
+		}
+		if result.Log != "transaction executed with 2 of 4 messages failed" {
+			t.Fatalf("unexpected log: %s", result.Log)
+		}
+
+		if len(result.MsgResults) != 4 {
+			t.Fatalf("expected 4 message results, got %d", len(result.MsgResults))
+		}
+		for i, wantOK := range []bool{true, false, false, true} {
+			if result.MsgResults[i].IsOK() != wantOK {
+				t.Fatalf("expected message %d ok: %v, got %+v", i, wantOK, result.MsgResults[i])
+			}
+		}
+		if failed := result.MsgResults[1]; failed.Codespace != failCodespace || failed.Code != failCode || len(failed.Events) != 0 {
+			t.Fatalf("unexpected failed message result: %+v", failed)
+		}
+		if result.GasUsed != 25 {
+			t.Fatalf("expected gas of every message to be charged, got %d", result.GasUsed)
+		}
+
+		// Failed messages' events are discarded
+		for _, event := range result.Events {
+			if event.Type == "policy.discarded" {
+				t.Fatalf("unexpected event of a failed message: %+v", event)
+			}
+			if index := msgIndex(event); index != "0" && index != "3" {
+				t.Fatalf("unexpected msg_index %q on event %s", index, event.Type)
+			}
+		}
+
+		checkWritten(t, app, "policy.write.a", true)
+		checkWritten(t, app, "policy.write.b", true)
+		checkNonce(t, app, 1)
+
+		// Effects that ran before a failing one are discarded with it
+		result = execute(t, app, newTx("policy.partial", "policy.write.a"))
+		if !result.IsOK() || result.MsgResults[0].IsOK() || !result.MsgResults[1].IsOK() {
+			t.Fatalf("expected only policy.partial to fail, got %d: %s", result.Code, result.Log)
+		}
+		checkWritten(t, app, "policy.partial", false)
+		checkNonce(t, app, 2)
+	})
+}
+
+// policyWriteHandler consumes gas, emits a handler event and writes a key
+// named after the message type
+func policyWriteHandler(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+	if err := ctx.ConsumeGas(10); err != nil {
+		return nil, err
+	}
+	ctx.EventManager().EmitEvent(types.NewEvent("policy.handled"))
+	return []effects.Effect{
+		effects.NewStateWriteEffect("policy", []byte(msg.Type()), []byte("v")),
+		effects.NewEventEffect("policy.written", map[string][]byte{"type": []byte(msg.Type())}),
+	}, nil
+}
+
+// gasScheduleFunc adapts a function to GasScheduleSource
+type gasScheduleFunc func() (GasSchedule, error)
+
+func (f gasScheduleFunc) GasSchedule() (GasSchedule, error) { return f() }
+
+func TestApplication_GasSchedule(t *testing.T) {
+	priv := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	pubKey := priv.Public().(ed25519.PublicKey)
+	ctx := context.Background()
+
+	app := setupTestApp(t)
+	schedule := GasSchedule{MsgBaseCost: 100, SigVerifyCost: 7, WriteCostPerByte: 2}
+	app.gasSchedule = gasScheduleFunc(func() (GasSchedule, error) { return schedule, nil })
+	if err := app.router.RegisterModule(&mockModule{
+		name: "sched",
+		msgHandlers: map[string]MsgHandler{
+			// Writes 3 key and value bytes
+			"sched.write": func(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+				return []effects.Effect{effects.NewStateWriteEffect("sched", []byte("k"), []byte("vv"))}, nil
+			},
+		},
+	}); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	if err := app.BeginBlock(ctx, NewBlockHeader(1, time.Now(), "test-chain", nil)); err != nil {
+		t.Fatalf("BeginBlock failed: %v", err)
+	}
+	if err := app.accountStore.Set(ctx, []byte("alice"), types.NewAccount("alice", pubKey)); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+
+	nonce := uint64(0)
+	newTx := func(gasLimit uint64) *types.Transaction {
+		tx := types.NewTransaction("alice", nonce, []types.Message{&testMessage{msgType: "sched.write", signers: []types.AccountName{"alice"}}},
+			types.NewAuthorization())
+		tx.Fee.GasLimit = gasLimit
+		tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+		signDoc, err := tx.ToSignDoc("test-chain", nonce)
+		if err != nil {
+			t.Fatalf("failed to build sign doc: %v", err)
+		}
+		signBytes, err := signDoc.GetSignBytes()
+		if err != nil {
+			t.Fatalf("failed to get sign bytes: %v", err)
+		}
+		tx.Authorization.Signatures = []types.Signature{{Algorithm: types.AlgorithmEd25519, PubKey: pubKey, Signature: ed25519.Sign(priv, signBytes)}}
+		return tx
+	}
+
+	// One signature, one message and 3 written bytes
+	result, err := execTx(t, app, ctx, newTx(0))
+	if err != nil {
+		t.Fatalf("executeTx failed: %v", err)
+	}
+	if !result.IsOK() {
+		t.Fatalf("expected success, got %d: %s", result.Code, result.Log)
+	}
+	if result.GasUsed != 7+100+3*2 {
+		t.Fatalf("expected gas %d, got %d", 7+100+3*2, result.GasUsed)
+	}
+	if result.MsgResults[0].GasUsed != 100+3*2 {
+		t.Fatalf("expected message gas %d, got %d", 100+3*2, result.MsgResults[0].GasUsed)
+	}
+	nonce++
+
+	// The schedule is read for every transaction
+	schedule.MsgBaseCost = 200
+	result, err = execTx(t, app, ctx, newTx(0))
+	if err != nil {
+		t.Fatalf("executeTx failed: %v", err)
+	}
+	if result.GasUsed != 7+200+3*2 {
+		t.Fatalf("expected gas %d after update, got %d", 7+200+3*2, result.GasUsed)
+	}
+	nonce++
+
+	// A limit below the scheduled cost runs out of gas
+	result, err = execTx(t, app, ctx, newTx(7+200+3*2-1))
+	if err != nil {
+		t.Fatalf("executeTx failed: %v", err)
+	}
+	if codespace, code := sdkerrors.ABCICode(ErrOutOfGas); result.Codespace != codespace || result.Code != code {
+		t.Fatalf("expected out of gas, got %s/%d: %s", result.Codespace, result.Code, result.Log)
+	}
+
+	// An invalid schedule fails the transaction
+	schedule.SigVerifyCost = MaxGasCost + 1
+	if _, err := execTx(t, app, ctx, newTx(0)); err == nil {
+		t.Fatal("expected invalid gas schedule to fail")
+	}
+}
+
+func TestApplication_StorageUsage(t *testing.T) {
+	priv := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	pubKey := priv.Public().(ed25519.PublicKey)
+	ctx := context.Background()
+
+	iavlStore, err := store.NewIAVLStore(dbm.NewMemDB(), 0)
+	if err != nil {
+		t.Fatalf("failed to create IAVL store: %v", err)
+	}
+
+	var reported []capability.StorageUsage
+	// "module/usage/k" + "vv" is 16 bytes
+	tracker := capability.NewUsageTracker(capability.StorageLimits{Default: 40}, func(height uint64, usage []capability.StorageUsage) {
+		reported = usage
+	})
+	app, err := NewApplication(ApplicationConfig{
+		ChainID:    "test-chain",
+		StateStore: iavlStore,
+		Modules: []Module{&mockModule{
+			name: "usage",
+			msgHandlers: map[string]MsgHandler{
+				"usage.write": func(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+					return []effects.Effect{effects.NewStateWriteEffect("usage", []byte("k"), []byte("vv"))}, nil
+				},
+			},
+		}},
+		StorageUsage: tracker,
+	})
+	if err != nil {
+		t.Fatalf("failed to create application: %v", err)
+	}
+	if err := app.accountStore.Set(ctx, []byte("alice"), types.NewAccount("alice", pubKey)); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+
+	nonce := uint64(0)
+	execute := func() *types.TxResult {
+		t.Helper()
+		tx := types.NewTransaction("alice", nonce, []types.Message{&testMessage{msgType: "usage.write", signers: []types.AccountName{"alice"}}},
+			types.NewAuthorization())
+		tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+		signDoc, err := tx.ToSignDoc("test-chain", nonce)
+		if err != nil {
+			t.Fatalf("failed to build sign doc: %v", err)
+		}
+		signBytes, err := signDoc.GetSignBytes()
+		if err != nil {
+			t.Fatalf("failed to get sign bytes: %v", err)
+		}
+		tx.Authorization.Signatures = []types.Signature{{Algorithm: types.AlgorithmEd25519, PubKey: pubKey, Signature: ed25519.Sign(priv, signBytes)}}
+		result, err := execTx(t, app, ctx, tx)
+		if err != nil {
+			t.Fatalf("executeTx failed: %v", err)
+		}
+		// The nonce advances even when the write fails
+		nonce++
+		return result
+	}
+
+	for height := uint64(1); height <= 2; height++ {
+		if err := app.BeginBlock(ctx, NewBlockHeader(height, time.Now(), "test-chain", nil)); err != nil {
+			t.Fatalf("BeginBlock failed: %v", err)
+		}
+
+		// Two writes fit the limit; the third exceeds it
+		for i := 0; i < 2; i++ {
+			if result := execute(); !result.IsOK() {
+				t.Fatalf("height %d: write %d failed: %s", height, i, result.Log)
+			}
+		}
+		result := execute()
+		if codespace, code := sdkerrors.ABCICode(capability.ErrStorageLimitExceeded); result.Codespace != codespace || result.Code != code {
+			t.Fatalf("height %d: expected storage limit exceeded, got %s/%d: %s", height, result.Codespace, result.Code, result.Log)
+		}
+
+		if _, err := app.EndBlock(ctx); err != nil {
+			t.Fatalf("EndBlock failed: %v", err)
+		}
+		want := []capability.StorageUsage{{Module: "usage", BytesWritten: 32, Writes: 2}}
+		if !reflect.DeepEqual(reported, want) {
+			t.Fatalf("height %d: reported %+v, want %+v", height, reported, want)
+		}
+		if _, err := app.Commit(ctx); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+	}
+}
+
+func TestApplication_ReCheckTx(t *testing.T) {
+	app := setupTestApp(t)
+	ctx := context.Background()
+
+	account := types.NewAccount("alice", make([]byte, 32))
+	account.Nonce = 2
+	if err := app.accountStore.Set(ctx, []byte("alice"), account); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+
+	newTx := func(account types.AccountName, nonce uint64) *types.Transaction {
+		tx := types.NewTransaction(account, nonce, []types.Message{&testMessage{msgType: "test.msg", signers: []types.AccountName{account}}},
+			types.NewAuthorization())
+		tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+		tx.Authorization.Signatures = []types.Signature{{Algorithm: types.AlgorithmEd25519, PubKey: make([]byte, 32), Signature: make([]byte, 64)}}
+		return tx
+	}
+
+	// Signatures are not re-verified, so current and future nonces pass
+	for _, nonce := range []uint64{2, 3} {
+		if err := recheckTestTx(t, app, ctx, newTx("alice", nonce)); err != nil {
+			t.Fatalf("nonce %d: expected recheck to pass, got %v", nonce, err)
+		}
+	}
+
+	if err := recheckTestTx(t, app, ctx, newTx("alice", 1)); !errors.Is(err, types.ErrSequenceMismatch) {
+		t.Fatalf("expected ErrSequenceMismatch for a stale nonce, got %v", err)
+	}
+	if err := recheckTestTx(t, app, ctx, newTx("nobody", 0)); !errors.Is(err, types.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown account, got %v", err)
+	}
+
+	if err := app.ReCheckTx(ctx, []byte("not a transaction")); err == nil {
+		t.Fatal("expected undecodable transaction to fail recheck")
+	}
+
+	// Expired transactions are evicted
+	expired := newTx("alice", 2)
+	expired.Authorization.NotAfter = types.TimeBound(time.Now().Add(-time.Hour))
+	if err := recheckTestTx(t, app, ctx, expired); !errors.Is(err, types.ErrTxExpired) {
+		t.Fatalf("expected ErrTxExpired, got %v", err)
+	}
+
+	// The ante handler re-checks fees and balances
+	app.anteHandler = func(ctx *Context, tx *types.Transaction) ([]effects.Effect, error) {
+		return nil, types.ErrInsufficientFunds
+	}
+	if err := recheckTestTx(t, app, ctx, newTx("alice", 2)); !errors.Is(err, types.ErrInsufficientFunds) {
+		t.Fatalf("expected ErrInsufficientFunds, got %v", err)
+	}
+}
+
+// authenticatorModule is a mockModule that also provides authenticators
+type authenticatorModule struct {
+	mockModule
+	authenticators map[string]Authenticator
+}
+
+func (m *authenticatorModule) RegisterAuthenticators() map[string]Authenticator {
+	return m.authenticators
+}
+
+func TestApplication_Authenticator(t *testing.T) {
+	priv := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	pubKey := priv.Public().(ed25519.PublicKey)
+	usedKey := effects.NewStateWriteEffect("guard", []byte("used"), []byte("1")).Key()
+
+	guard := &authenticatorModule{
+		mockModule: mockModule{
+			name: "guard",
+			msgHandlers: map[string]MsgHandler{
+				// Fails unless handlers see the registered authenticators
+				"guard.check": func(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+					if !ctx.HasAuthenticator("guard/limit") || ctx.HasAuthenticator("guard/unknown") {
+						return nil, fmt.Errorf("unexpected authenticators")
+					}
+					return nil, nil
+				},
+			},
+		},
+		authenticators: map[string]Authenticator{
+			// Requires the authority check and allows one message per
+			// transaction, recording its use
+			"limit": func(ctx *Context, req *AuthRequest) ([]effects.Effect, error) {
+				if err := req.VerifyAuthority(); err != nil {
+					return nil, err
+				}
+				if len(req.Tx.Messages) > 1 {
+					return nil, fmt.Errorf("%w: one message per transaction", types.ErrUnauthorized)
+				}
+				return []effects.Effect{effects.NewStateWriteEffect("guard", []byte("used"), []byte("1"))}, nil
+			},
+			// Accepts any signature
+			"open": func(ctx *Context, req *AuthRequest) ([]effects.Effect, error) {
+				if len(req.SignBytes) == 0 {
+					return nil, fmt.Errorf("missing sign bytes")
+				}
+				return nil, nil
+			},
+		},
+	}
+
+	iavlStore, err := store.NewIAVLStore(dbm.NewMemDB(), 0)
+	if err != nil {
+		t.Fatalf("failed to create IAVL store: %v", err)
+	}
+	app, err := NewApplication(ApplicationConfig{
+		ChainID:    "test-chain",
+		StateStore: iavlStore,
+		Modules: []Module{guard, &mockModule{
+			name: "test",
+			msgHandlers: map[string]MsgHandler{
+				"test.msg": func(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+					return nil, nil
+				},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create application: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := app.BeginBlock(ctx, NewBlockHeader(1, time.Now(), "test-chain", nil)); err != nil {
+		t.Fatalf("BeginBlock failed: %v", err)
+	}
+
+	setAccount := func(t *testing.T, authenticator string) *types.Account {
+		t.Helper()
+		account := types.NewAccount("alice", pubKey)
+		account.Authenticator = authenticator
+		if err := app.accountStore.Set(ctx, []byte("alice"), account); err != nil {
+			t.Fatalf("failed to set account: %v", err)
+		}
+		return account
+	}
+
+	newTx := func(t *testing.T, nonce uint64, signer ed25519.PrivateKey, msgTypes ...string) *types.Transaction {
+		t.Helper()
+		msgs := make([]types.Message, len(msgTypes))
+		for i, msgType := range msgTypes {
+			msgs[i] = &testMessage{msgType: msgType, signers: []types.AccountName{"alice"}}
+		}
+		tx := types.NewTransaction("alice", nonce, msgs, types.NewAuthorization())
+		tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+		signDoc, err := tx.ToSignDoc("test-chain", nonce)
+		if err != nil {
+			t.Fatalf("failed to build sign doc: %v", err)
+		}
+		signBytes, err := signDoc.GetSignBytes()
+		if err != nil {
+			t.Fatalf("failed to get sign bytes: %v", err)
+		}
+		tx.Authorization.Signatures = []types.Signature{{Algorithm: types.AlgorithmEd25519, PubKey: signer.Public().(ed25519.PublicKey), Signature: ed25519.Sign(signer, signBytes)}}
+		return tx
+	}
+
+	execute := func(t *testing.T, tx *types.Transaction) *types.TxResult {
+		t.Helper()
+		result, err := execTx(t, app, ctx, tx)
+		if err != nil {
+			t.Fatalf("executeTx failed: %v", err)
+		}
+		return result
+	}
+
+	t.Run("authenticator effects apply with the transaction", func(t *testing.T) {
+		setAccount(t, "guard/limit")
+		result := execute(t, newTx(t, 0, priv, "guard.check"))
+		if !result.IsOK() {
+			t.Fatalf("expected success, got %s", result.Log)
+		}
+		has, err := app.stateStore.Has(usedKey)
+		if err != nil {
+			t.Fatalf("failed to read state: %v", err)
+		}
+		if !has {
+			t.Fatal("expected the authenticator's effect to be applied")
+		}
+	})
+
+	t.Run("authenticator rejects", func(t *testing.T) {
+		setAccount(t, "guard/limit")
+		result := execute(t, newTx(t, 0, priv, "test.msg", "test.msg"))
+		codespace, code := sdkerrors.ABCICode(types.ErrUnauthorized)
+		if result.Codespace != codespace || result.Code != code {
+			t.Fatalf("expected %s/%d, got %s/%d: %s", codespace, code, result.Codespace, result.Code, result.Log)
+		}
+	})
+
+	t.Run("authenticator replaces the authority check", func(t *testing.T) {
+		other := ed25519.NewKeyFromSeed([]byte("another-seed-of-32-bytes-length!"))
+
+		setAccount(t, "")
+		if result := execute(t, newTx(t, 0, other, "test.msg")); result.IsOK() {
+			t.Fatal("expected the authority check to reject a foreign key")
+		}
+
+		setAccount(t, "guard/open")
+		if result := execute(t, newTx(t, 0, other, "test.msg")); !result.IsOK() {
+			t.Fatalf("expected the authenticator to accept, got %s", result.Log)
+		}
+	})
+
+	t.Run("nonce is checked before the authenticator", func(t *testing.T) {
+		setAccount(t, "guard/open")
+		result := execute(t, newTx(t, 1, priv, "test.msg"))
+		codespace, code := sdkerrors.ABCICode(types.ErrSequenceMismatch)
+		if result.Codespace != codespace || result.Code != code {
+			t.Fatalf("expected %s/%d, got %s/%d: %s", codespace, code, result.Codespace, result.Code, result.Log)
+		}
+	})
+
+	t.Run("unregistered authenticator", func(t *testing.T) {
+		setAccount(t, "guard/unknown")
+		result := execute(t, newTx(t, 0, priv, "test.msg"))
+		codespace, code := sdkerrors.ABCICode(ErrAuthenticatorNotFound)
+		if result.Codespace != codespace || result.Code != code {
+			t.Fatalf("expected %s/%d, got %s/%d: %s", codespace, code, result.Codespace, result.Code, result.Log)
+		}
+	})
+
+	t.Run("invalid registrations", func(t *testing.T) {
+		for name, authenticators := range map[string]map[string]Authenticator{
+			"invalid_name":      {"Bad Name": guard.authenticators["open"]},
+			"nil_authenticator": {"nil": nil},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := NewApplication(ApplicationConfig{
+					ChainID:    "test-chain",
+					StateStore: iavlStore,
+					Modules:    []Module{&authenticatorModule{mockModule: mockModule{name: "bad"}, authenticators: authenticators}},
+				})
+				if err == nil {
+					t.Fatal("expected NewApplication to fail")
+				}
+			})
+		}
+	})
+}
+
+func TestApplication_FeePayer(t *testing.T) {
+	alicePriv := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	bobPriv := ed25519.NewKeyFromSeed([]byte("another-seed-of-32-bytes-length!"))
+	grantKey := effects.NewStateWriteEffect("grant", []byte("used"), []byte("1")).Key()
+
+	var granted []types.AccountName
+	iavlStore, err := store.NewIAVLStore(dbm.NewMemDB(), 0)
+	if err != nil {
+		t.Fatalf("failed to create IAVL store: %v", err)
+	}
+	app, err := NewApplication(ApplicationConfig{
+		ChainID:    "test-chain",
+		StateStore: iavlStore,
+		Modules: []Module{&mockModule{
+			name: "test",
+			msgHandlers: map[string]MsgHandler{
+				"test.msg": func(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+					return nil, nil
+				},
+			},
+		}},
+		// Only carol grants, and only to bob
+		FeeGrantHandler: func(ctx *Context, tx *types.Transaction) ([]effects.Effect, error) {
+			if tx.FeeGranter != "carol" || tx.FeeGrantee() != "bob" {
+				return nil, fmt.Errorf("%w: no allowance", types.ErrUnauthorized)
+			}
+			granted = append(granted, tx.FeeGrantee())
+			return []effects.Effect{effects.NewStateWriteEffect("grant", []byte("used"), []byte("1"))}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create application: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := app.BeginBlock(ctx, NewBlockHeader(1, time.Now(), "test-chain", nil)); err != nil {
+		t.Fatalf("BeginBlock failed: %v", err)
+	}
+	for name, priv := range map[types.AccountName]ed25519.PrivateKey{"alice": alicePriv, "bob": bobPriv} {
+		if err := app.accountStore.Set(ctx, []byte(name), types.NewAccount(name, priv.Public().(ed25519.PublicKey))); err != nil {
+			t.Fatalf("failed to set account: %v", err)
+		}
+	}
+
+	sign := func(t *testing.T, priv ed25519.PrivateKey, signBytes []byte) *types.Authorization {
+		t.Helper()
+		return types.NewAuthorization(types.Signature{Algorithm: types.AlgorithmEd25519, PubKey: priv.Public().(ed25519.PublicKey), Signature: ed25519.Sign(priv, signBytes)})
+	}
+
+	// newTx returns a transaction from alice paid by payer, signed by payerKey
+	newTx := func(t *testing.T, payer types.AccountName, payerKey ed25519.PrivateKey, granter types.AccountName) *types.Transaction {
+		t.Helper()
+		account, err := app.accountStore.Get(ctx, []byte("alice"))
+		if err != nil {
+			t.Fatalf("failed to get account: %v", err)
+		}
+		msgs := []types.Message{&testMessage{msgType: "test.msg", signers: []types.AccountName{"alice"}}}
+		tx := types.NewTransaction("alice", account.Nonce, msgs, nil)
+		tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+		tx.FeePayer = payer
+		tx.FeeGranter = granter
+		signDoc, err := tx.ToSignDoc("test-chain", account.Nonce)
+		if err != nil {
+			t.Fatalf("failed to build sign doc: %v", err)
+		}
+		signBytes, err := signDoc.GetSignBytes()
+		if err != nil {
+			t.Fatalf("failed to get sign bytes: %v", err)
+		}
+		tx.Authorization = sign(t, alicePriv, signBytes)
+		if payer != "" {
+			tx.FeePayerAuthorization = sign(t, payerKey, signBytes)
+		}
+		return tx
+	}
+
+	execute := func(t *testing.T, tx *types.Transaction) *types.TxResult {
+		t.Helper()
+		result, err := execTx(t, app, ctx, tx)
+		if err != nil {
+			t.Fatalf("executeTx failed: %v", err)
+		}
+		return result
+	}
+
+	expectCode := func(t *testing.T, result *types.TxResult, want error) {
+		t.Helper()
+		codespace, code := sdkerrors.ABCICode(want)
+		if result.Codespace != codespace || result.Code != code {
+			t.Fatalf("expected %s/%d, got %s/%d: %s", codespace, code, result.Codespace, result.Code, result.Log)
+		}
+	}
+
+	t.Run("payer signed", func(t *testing.T) {
+		if result := execute(t, newTx(t, "bob", bobPriv, "")); !result.IsOK() {
+			t.Fatalf("expected success, got %s", result.Log)
+		}
+	})
+
+	t.Run("payer signature forged", func(t *testing.T) {
+		result := execute(t, newTx(t, "bob", alicePriv, ""))
+		expectCode(t, result, types.ErrInsufficientWeight)
+	})
+
+	t.Run("payer account missing", func(t *testing.T) {
+		result := execute(t, newTx(t, "dave", bobPriv, ""))
+		expectCode(t, result, types.ErrNotFound)
+	})
+
+	t.Run("granter allowance", func(t *testing.T) {
+		granted = nil
+		if result := execute(t, newTx(t, "bob", bobPriv, "carol")); !result.IsOK() {
+			t.Fatalf("expected success, got %s", result.Log)
+		}
+		if len(granted) != 1 || granted[0] != "bob" {
+			t.Fatalf("expected one grant to bob, got %v", granted)
+		}
+		has, err := app.stateStore.Has(grantKey)
+		if err != nil {
+			t.Fatalf("failed to read state: %v", err)
+		}
+		if !has {
+			t.Fatal("expected the fee grant handler's effect to be applied")
+		}
+	})
+
+	t.Run("granter without allowance", func(t *testing.T) {
+		result := execute(t, newTx(t, "", nil, "carol"))
+		expectCode(t, result, types.ErrUnauthorized)
+	})
+
+	t.Run("granter without handler", func(t *testing.T) {
+		app.feeGrantHandler = nil
+		result := execute(t, newTx(t, "", nil, "carol"))
+		expectCode(t, result, ErrFeeGrantUnsupported)
+	})
+}
+
+func TestApplication_CoSigner(t *testing.T) {
+	alicePriv := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	bobPriv := ed25519.NewKeyFromSeed([]byte("another-seed-of-32-bytes-length!"))
+
+	var executedAs []types.AccountName
+	iavlStore, err := store.NewIAVLStore(dbm.NewMemDB(), 0)
+	if err != nil {
+		t.Fatalf("failed to create IAVL store: %v", err)
+	}
+	app, err := NewApplication(ApplicationConfig{
+		ChainID:    "test-chain",
+		StateStore: iavlStore,
+		Modules: []Module{&mockModule{
+			name: "test",
+			msgHandlers: map[string]MsgHandler{
+				"test.msg": func(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+					executedAs = append(executedAs, ctx.Account())
+					return nil, nil
+				},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create application: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := app.BeginBlock(ctx, NewBlockHeader(1, time.Now(), "test-chain", nil)); err != nil {
+		t.Fatalf("BeginBlock failed: %v", err)
+	}
+	for name, priv := range map[types.AccountName]ed25519.PrivateKey{"alice": alicePriv, "bob": bobPriv} {
+		if err := app.accountStore.Set(ctx, []byte(name), types.NewAccount(name, priv.Public().(ed25519.PublicKey))); err != nil {
+			t.Fatalf("failed to set account: %v", err)
+		}
+	}
+
+	nonce := func(t *testing.T, name types.AccountName) uint64 {
+		t.Helper()
+		account, err := app.accountStore.Get(ctx, []byte(name))
+		if err != nil {
+			t.Fatalf("failed to get account: %v", err)
+		}
+		return account.Nonce
+	}
+
+	// signFor returns priv's authorization of signer's SignDoc of tx
+	signFor := func(t *testing.T, tx *types.Transaction, signer types.AccountName, sequence uint64, priv ed25519.PrivateKey) *types.Authorization {
+		t.Helper()
+		signDoc, err := tx.ToSignerSignDoc("test-chain", signer, sequence)
+		if err != nil {
+			t.Fatalf("failed to build sign doc: %v", err)
+		}
+		signBytes, err := signDoc.GetSignBytes()
+		if err != nil {
+			t.Fatalf("failed to get sign bytes: %v", err)
+		}
+		return types.NewAuthorization(types.Signature{Algorithm: types.AlgorithmEd25519, PubKey: priv.Public().(ed25519.PublicKey), Signature: ed25519.Sign(priv, signBytes)})
+	}
+
+	// newTx returns a transaction from alice with a message signed by bob,
+	// co-signed with bobKey at bob's nonce plus bobNonceOffset
+	newTx := func(t *testing.T, bobKey ed25519.PrivateKey, bobNonceOffset uint64) *types.Transaction {
+		t.Helper()
+		msgs := []types.Message{
+			&testMessage{msgType: "test.msg", signers: []types.AccountName{"alice"}},
+			&testMessage{msgType: "test.msg", signers: []types.AccountName{"bob"}},
+		}
+		aliceNonce, bobNonce := nonce(t, "alice"), nonce(t, "bob")+bobNonceOffset
+		tx := types.NewTransaction("alice", aliceNonce, msgs, nil)
+		tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+		tx.CoSigners = []types.CoSigner{{Account: "bob", Nonce: bobNonce}}
+		tx.Authorization = signFor(t, tx, "alice", aliceNonce, alicePriv)
+		tx.CoSigners[0].Authorization = signFor(t, tx, "bob", bobNonce, bobKey)
+		return tx
+	}
+
+	execute := func(t *testing.T, tx *types.Transaction) *types.TxResult {
+		t.Helper()
+		result, err := execTx(t, app, ctx, tx)
+		if err != nil {
+			t.Fatalf("executeTx failed: %v", err)
+		}
+		return result
+	}
+
+	expectCode := func(t *testing.T, result *types.TxResult, want error) {
+		t.Helper()
+		codespace, code := sdkerrors.ABCICode(want)
+		if result.Codespace != codespace || result.Code != code {
+			t.Fatalf("expected %s/%d, got %s/%d: %s", codespace, code, result.Codespace, result.Code, result.Log)
+		}
+	}
+
+	t.Run("co-signed", func(t *testing.T) {
+		executedAs = nil
+		if result := execute(t, newTx(t, bobPriv, 0)); !result.IsOK() {
+			t.Fatalf("expected success, got %s", result.Log)
+		}
+		if len(executedAs) != 2 || executedAs[0] != "alice" || executedAs[1] != "bob" {
+			t.Fatalf("expected messages to execute as alice and bob, got %v", executedAs)
+		}
+		if nonce(t, "alice") != 1 || nonce(t, "bob") != 1 {
+			t.Fatalf("expected both nonces to be incremented, got %d and %d", nonce(t, "alice"), nonce(t, "bob"))
+		}
+	})
+
+	t.Run("co-signer signature forged", func(t *testing.T) {
+		result := execute(t, newTx(t, alicePriv, 0))
+		expectCode(t, result, types.ErrInsufficientWeight)
+	})
+
+	t.Run("co-signer nonce stale", func(t *testing.T) {
+		result := execute(t, newTx(t, bobPriv, 1))
+		expectCode(t, result, types.ErrSequenceMismatch)
+		if nonce(t, "alice") != 1 {
+			t.Fatalf("expected alice's nonce to be unchanged, got %d", nonce(t, "alice"))
+		}
+	})
+
+	t.Run("signers swapped", func(t *testing.T) {
+		// Bob's signature must not make him the primary signer paying the fee
+		tx := newTx(t, bobPriv, 0)
+		swapped := types.NewTransaction("bob", tx.CoSigners[0].Nonce, tx.Messages, tx.CoSigners[0].Authorization)
+		swapped.FeeSlippage = tx.FeeSlippage
+		swapped.CoSigners = []types.CoSigner{{Account: "alice", Nonce: tx.Nonce, Authorization: tx.Authorization}}
+		result := execute(t, swapped)
+		expectCode(t, result, types.ErrInvalidSignature)
+	})
+}
+
+func TestNewMemoAnteHandler(t *testing.T) {
+	if _, err := NewMemoAnteHandler(types.MemoPolicy{MaxBytes: types.MaxMemoBytes + 1}); err == nil {
+		t.Fatal("expected invalid policy to be rejected")
+	}
+
+	ante, err := NewMemoAnteHandler(types.MemoPolicy{MaxBytes: 8, RejectControlChars: true})
+	if err != nil {
+		t.Fatalf("NewMemoAnteHandler failed: %v", err)
+	}
+
+	for _, tt := range []struct {
+		memo    string
+		wantErr bool
+	}{
+		{memo: "", wantErr: false},
+		{memo: "invoice", wantErr: false},
+		{memo: "invoice 42", wantErr: true},
+		{memo: "a\rb", wantErr: true},
+	} {
+		tx := types.NewTransaction("alice", 0, []types.Message{&testMessage{msgType: "test.msg", signers: []types.AccountName{"alice"}}}, types.NewAuthorization())
+		tx.Memo = tt.memo
+		effs, err := ante(nil, tx)
+		if tt.wantErr {
+			if !errors.Is(err, types.ErrInvalidMemo) {
+				t.Fatalf("memo %q: expected ErrInvalidMemo, got %v", tt.memo, err)
+			}
+			continue
+		}
+		if err != nil || len(effs) != 0 {
+			t.Fatalf("memo %q: expected no error and no effects, got %v, %v", tt.memo, effs, err)
+		}
+	}
+}
+
+// dataMessage is a test message with the given SignDoc data
+type dataMessage struct {
+	testMessage
+	data string
+}
+
+func (m *dataMessage) SignDocData() (json.RawMessage, error) {
+	return json.RawMessage(m.data), nil
+}
+
+func TestNewNumberPolicyAnteHandler(t *testing.T) {
+	ante := NewNumberPolicyAnteHandler()
+
+	for _, tt := range []struct {
+		name    string
+		msg     types.Message
+		wantErr bool
+	}{
+		{name: "string numbers", msg: &dataMessage{data: `{"amount":"100","denom":"stake"}`}},
+		{name: "not serializable", msg: &testMessage{msgType: "test.msg"}},
+		{name: "bare integer", msg: &dataMessage{data: `{"amount":100,"denom":"stake"}`}, wantErr: true},
+		{name: "nested float", msg: &dataMessage{data: `{"coins":[{"amount":"1"},{"rate":0.5}]}`}, wantErr: true},
+	} {
+		tx := types.NewTransaction("alice", 0, []types.Message{tt.msg}, types.NewAuthorization())
+		effs, err := ante(nil, tx)
+		if tt.wantErr {
+			if !errors.Is(err, types.ErrInvalidTransaction) {
+				t.Fatalf("%s: expected ErrInvalidTransaction, got %v", tt.name, err)
+			}
+			continue
+		}
+		if err != nil || len(effs) != 0 {
+			t.Fatalf("%s: expected no error and no effects, got %v, %v", tt.name, effs, err)
+		}
+	}
+}
+
+func TestMemoRouter(t *testing.T) {
+	router := NewMemoRouter(false)
+	if err := router.Register("Bad Type", func(*Context, *types.Transaction, *types.StructuredMemo) ([]effects.Effect, error) { return nil, nil }); err == nil {
+		t.Fatal("expected invalid memo type to be rejected")
+	}
+	if err := router.Register("exchange.deposit", nil); err == nil {
+		t.Fatal("expected nil handler to be rejected")
+	}
+
+	var routed []string
+	handler := func(ctx *Context, tx *types.Transaction, memo *types.StructuredMemo) ([]effects.Effect, error) {
+		var body struct {
+			Tag string `json:"tag"`
+		}
+		if err := memo.Unmarshal(&body); err != nil {
+			return nil, err
+		}
+		if body.Tag == "" {
+			return nil, fmt.Errorf("%w: missing deposit tag", types.ErrInvalidMemo)
+		}
+		routed = append(routed, body.Tag)
+		return nil, nil
+	}
+	if err := router.Register("exchange.deposit", handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := router.Register("exchange.deposit", handler); err == nil {
+		t.Fatal("expected duplicate registration to be rejected")
+	}
+	if !router.HasHandler("exchange.deposit") || router.HasHandler("ibc.callback") {
+		t.Fatal("unexpected HasHandler result")
+	}
+
+	strict := NewMemoRouter(true)
+	if err := strict.Register("exchange.deposit", handler); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name       string
+		router     *MemoRouter
+		memo       string
+		wantErr    bool
+		wantRouted bool
+	}{
+		{"plain text", router, "invoice 42", false, false},
+		{"ics-20 style", router, `{"wasm":{}}`, false, false},
+		{"routed", router, `{"type":"exchange.deposit","tag":"12345"}`, false, true},
+		{"handler rejects", router, `{"type":"exchange.deposit"}`, true, false},
+		{"malformed", router, `{"type":"exchange.deposit","type":"x"}`, true, false},
+		{"unknown allowed", router, `{"type":"ibc.callback"}`, false, false},
+		{"unknown rejected", strict, `{"type":"ibc.callback"}`, true, false},
+		{"plain text strict", strict, "invoice 42", false, false},
+	} {
+		routed = nil
+		tx := types.NewTransaction("alice", 0, []types.Message{&testMessage{msgType: "test.msg", signers: []types.AccountName{"alice"}}}, types.NewAuthorization())
+		tx.Memo = tt.memo
+		_, err := tt.router.AnteHandler()(nil, tx)
+		if tt.wantErr {
+			if !errors.Is(err, types.ErrInvalidMemo) {
+				t.Fatalf("%s: expected ErrInvalidMemo, got %v", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if got := len(routed) == 1; got != tt.wantRouted {
+			t.Fatalf("%s: routed = %v, want %v", tt.name, routed, tt.wantRouted)
+		}
+	}
+}
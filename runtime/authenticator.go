@@ -0,0 +1,130 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// ErrAuthenticatorNotFound is returned when an account names an
+// authenticator that is not registered
+var ErrAuthenticatorNotFound = errors.New("authenticator not found")
+
+// Authenticator authorizes the transactions of accounts that delegate
+// authentication to it (see types.Account.Authenticator), replacing the
+// weighted Authority check. It inspects the request's SignDoc hash and the
+// transaction's signatures, and may call AuthRequest.VerifyAuthority to
+// build on the standard check, e.g. to require a second factor on top of it.
+//
+// It runs in CheckTx with a read-only context, and in ExecuteTx before the
+// ante handler. Its effects, e.g. recording spending against a limit, are
+// applied together with the transaction's other effects.
+//
+// SECURITY: The nonce is checked before an authenticator runs, so replay
+// protection does not depend on it. An authenticator must be deterministic
+// and must not trust anything in the transaction it has not verified.
+type Authenticator func(ctx *Context, req *AuthRequest) ([]effects.Effect, error)
+
+// AuthenticatorRegistrar is implemented by modules that provide
+// authenticators. Each is registered under AuthenticatorRoute(module, name).
+type AuthenticatorRegistrar interface {
+	// RegisterAuthenticators returns the module's authenticators by name
+	RegisterAuthenticators() map[string]Authenticator
+}
+
+// AuthenticatorRoute returns the name accounts use to select a module's
+// authenticator
+func AuthenticatorRoute(moduleName, name string) string {
+	return moduleName + "/" + name
+}
+
+// AuthRequest is the input of an Authenticator
+type AuthRequest struct {
+	// ChainID is the chain the transaction must be bound to
+	ChainID string
+
+	// Height is the height the transaction is authorized at
+	Height uint64
+
+	// Account is the transaction's account
+	Account *types.Account
+
+	// Tx is the transaction being authorized
+	Tx *types.Transaction
+
+	// SignBytes is the SignDoc hash the transaction's signatures must cover
+	SignBytes []byte
+
+	// getter resolves delegated accounts for VerifyAuthority
+	getter types.AccountGetter
+}
+
+// VerifyAuthority runs the standard weighted Authority check, including
+// session-key authorizations, on the request
+func (r *AuthRequest) VerifyAuthority() error {
+	return r.Tx.VerifyAuthorizationAtHeight(r.ChainID, r.Account, r.getter, r.Height)
+}
+
+// registerAuthenticators collects the authenticators of the modules
+// implementing AuthenticatorRegistrar
+func registerAuthenticators(modules []Module) (map[string]Authenticator, error) {
+	authenticators := make(map[string]Authenticator)
+	for _, mod := range modules {
+		registrar, ok := mod.(AuthenticatorRegistrar)
+		if !ok {
+			continue
+		}
+
+		provided := registrar.RegisterAuthenticators()
+		names := make([]string, 0, len(provided))
+		for name := range provided {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			route := AuthenticatorRoute(mod.Name(), name)
+			if !types.IsValidAuthenticatorName(route) {
+				return nil, fmt.Errorf("module %s: invalid authenticator name %q", mod.Name(), name)
+			}
+			if provided[name] == nil {
+				return nil, fmt.Errorf("module %s: authenticator %s is nil", mod.Name(), name)
+			}
+			authenticators[route] = provided[name]
+		}
+	}
+	return authenticators, nil
+}
+
+// authenticate authorizes tx for account at the context's height: by the
+// account's authenticator if it names one, otherwise by its Authority. It
+// returns the authenticator's effects.
+func (app *Application) authenticate(ctx *Context, tx *types.Transaction, account *types.Account) ([]effects.Effect, error) {
+	if account.Authenticator == "" {
+		// SECURITY: chainID binding prevents cross-chain replay attacks; the
+		// height bounds session-key authorizations
+		return nil, tx.VerifyAuthorizationAtHeight(app.chainID, account, app.accountGetter, ctx.BlockHeight())
+	}
+
+	authenticator, ok := app.authenticators[account.Authenticator]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s of account %s", ErrAuthenticatorNotFound, account.Authenticator, account.Name)
+	}
+
+	signBytes, err := tx.VerifiedSignBytes(app.chainID, account)
+	if err != nil {
+		return nil, err
+	}
+
+	return authenticator(ctx, &AuthRequest{
+		ChainID:   app.chainID,
+		Height:    ctx.BlockHeight(),
+		Account:   account,
+		Tx:        tx,
+		SignBytes: signBytes,
+		getter:    app.accountGetter,
+	})
+}
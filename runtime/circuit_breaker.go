@@ -0,0 +1,49 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrCircuitBreakerTripped is returned when a message type is disabled by
+// the circuit breaker
+var ErrCircuitBreakerTripped = errors.New("circuit breaker tripped")
+
+// CircuitBreaker decides whether messages of a type may be dispatched, so
+// operators can halt a misbehaving module's messages without a chain
+// upgrade (see modules/circuit).
+//
+// INVARIANT: The decision depends only on committed and in-block state, so
+// every node routes the same messages.
+type CircuitBreaker interface {
+	// IsAllowed reports whether messages of msgType may be dispatched in ctx
+	IsAllowed(ctx *Context, msgType string) (bool, error)
+}
+
+// SetCircuitBreaker sets the circuit breaker consulted before every message
+// dispatch, including module-to-module dispatch; nil disables the check
+func (r *Router) SetCircuitBreaker(breaker CircuitBreaker) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.breaker = breaker
+}
+
+// checkCircuit returns ErrCircuitBreakerTripped if breaker disables msgType
+func checkCircuit(ctx *Context, breaker CircuitBreaker, msgType string) error {
+	if breaker == nil {
+		return nil
+	}
+
+	allowed, err := breaker.IsAllowed(ctx, msgType)
+	if err != nil {
+		return fmt.Errorf("failed to check circuit breaker: %w", err)
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %s", ErrCircuitBreakerTripped, msgType)
+	}
+	return nil
+}
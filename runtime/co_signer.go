@@ -0,0 +1,57 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// verifyCoSigners verifies that every co-signer of the transaction signed its
+// SignDoc at its current nonce, and returns the co-signers' accounts in order.
+//
+// PRECONDITION: tx passed ValidateBasic.
+func (app *Application) verifyCoSigners(ctx context.Context, tx *types.Transaction) ([]*types.Account, error) {
+	accounts := make([]*types.Account, 0, len(tx.CoSigners))
+	for _, cs := range tx.CoSigners {
+		account, err := app.accountStore.Get(ctx, []byte(cs.Account))
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return nil, fmt.Errorf("%w: co-signer %s", types.ErrNotFound, cs.Account)
+			}
+			return nil, fmt.Errorf("failed to get co-signer: %w", err)
+		}
+		// RATIONALE: Authenticators receive the account's own SignDoc (see
+		// AuthRequest); co-signers sign with their authority directly
+		if account.Authenticator != "" {
+			return nil, fmt.Errorf("%w: co-signer %s uses authenticator %s",
+				types.ErrInvalidTransaction, account.Name, account.Authenticator)
+		}
+		if err := tx.VerifyCoSignerAuthorization(app.chainID, account, app.accountGetter); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+// checkCoSignerNonces rejects a transaction whose co-signer nonces are
+// already used; see ReCheckTx.
+func (app *Application) checkCoSignerNonces(ctx context.Context, tx *types.Transaction) error {
+	for _, cs := range tx.CoSigners {
+		account, err := app.accountStore.Get(ctx, []byte(cs.Account))
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return fmt.Errorf("%w: co-signer %s", types.ErrNotFound, cs.Account)
+			}
+			return fmt.Errorf("failed to get co-signer: %w", err)
+		}
+		if cs.Nonce < account.Nonce {
+			return fmt.Errorf("%w: co-signer %s nonce %d already used, account is at %d",
+				types.ErrSequenceMismatch, cs.Account, cs.Nonce, account.Nonce)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package runtime
+
+import sdkerrors "github.com/blockberries/punnet-sdk/errors"
+
+// CodespaceRuntime is the result codespace of runtime errors
+const CodespaceRuntime = "runtime"
+
+// Runtime error codes.
+func init() {
+	sdkerrors.MustRegister(CodespaceRuntime, 2, ErrOutOfGas)
+	sdkerrors.MustRegister(CodespaceRuntime, 3, ErrHandlerNotFound)
+	sdkerrors.MustRegister(CodespaceRuntime, 4, ErrQueryHandlerNotFound)
+	sdkerrors.MustRegister(CodespaceRuntime, 5, ErrDispatchNotAuthorized)
+	sdkerrors.MustRegister(CodespaceRuntime, 6, ErrReentrantDispatch)
+	sdkerrors.MustRegister(CodespaceRuntime, 7, ErrAuthenticatorNotFound)
+	sdkerrors.MustRegister(CodespaceRuntime, 8, ErrFeeGrantUnsupported)
+	sdkerrors.MustRegister(CodespaceRuntime, 9, ErrCircuitBreakerTripped)
+	sdkerrors.MustRegister(CodespaceRuntime, 10, ErrHandlerPanic)
+	sdkerrors.MustRegister(CodespaceRuntime, 11, ErrInvalidQueryBatch)
+}
@@ -2,7 +2,12 @@ package runtime
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
 	"time"
 
 	"github.com/blockberries/punnet-sdk/effects"
@@ -22,6 +27,10 @@ type BlockHeader struct {
 
 	// ProposerAddress is the address of the block proposer
 	ProposerAddress []byte
+
+	// Hash is the block hash from the consensus engine; empty if the engine
+	// does not provide it. It is mixed into the context random seed.
+	Hash []byte
 }
 
 // NewBlockHeader creates a new block header
@@ -38,6 +47,13 @@ func NewBlockHeader(height uint64, timestamp time.Time, chainID string, proposer
 	}
 }
 
+// WithHash returns a copy of the header with the given block hash
+func (h *BlockHeader) WithHash(hash []byte) *BlockHeader {
+	cp := *h
+	cp.Hash = append([]byte(nil), hash...)
+	return &cp
+}
+
 // ValidateBasic performs basic validation
 func (h *BlockHeader) ValidateBasic() error {
 	if h == nil {
@@ -59,8 +75,16 @@ func (h *BlockHeader) ValidateBasic() error {
 	return nil
 }
 
+// randomSeedDomain separates Context random seeds from other hashes.
+const randomSeedDomain = "punnet/runtime/random-seed/v1"
+
+// randomSeedBlockHashTag precedes the block hash in a random seed.
+const randomSeedBlockHashTag = "block-hash"
+
 // Context provides execution context for message handlers
-// It carries block information, transaction account, and effect collection
+// It carries block information, transaction account, and effect collection,
+// plus the transaction hash, gas meter, event manager, and deterministic
+// randomness.
 type Context struct {
 	// ctx is the underlying Go context
 	ctx context.Context
@@ -77,8 +101,45 @@ type Context struct {
 	// readOnly indicates if this is a read-only context (for CheckTx)
 	readOnly bool
 
-	// gasUsed tracks gas consumption (placeholder for future gas metering)
-	gasUsed uint64
+	// txHash is the hash of the executing transaction (nil outside a transaction)
+	txHash []byte
+
+	// txSigners are the accounts that authorized the executing transaction:
+	// its account and co-signers (nil outside a transaction)
+	txSigners []types.AccountName
+
+	// gasMeter tracks gas consumption against the transaction's limit
+	gasMeter GasMeter
+
+	// gasSchedule prices the work the runtime meters (zero outside
+	// transactions, charging nothing)
+	gasSchedule GasSchedule
+
+	// eventManager collects events emitted by handlers
+	eventManager *EventManager
+
+	// randomSeed is derived from block metadata and txHash
+	randomSeed [32]byte
+
+	// randomness hands out values derived from randomSeed; shared by the
+	// copies of a Context within one transaction
+	randomness *RandomnessProvider
+
+	// router is the router that invoked the current handler (nil outside
+	// message handlers); MsgDispatcher routes through it
+	router *Router
+
+	// callStack lists the modules whose message handlers are executing,
+	// outermost first
+	callStack []string
+
+	// authenticators are the application's registered authenticators (nil
+	// outside transactions)
+	authenticators map[string]Authenticator
+
+	// txKeys are the module keys claimed with ClaimTxKey; shared by the
+	// copies of a Context within one transaction
+	txKeys map[string]bool
 }
 
 // NewContext creates a new execution context
@@ -99,14 +160,19 @@ func NewContext(ctx context.Context, header *BlockHeader, account types.AccountN
 		return nil, fmt.Errorf("invalid account: %s", account)
 	}
 
-	return &Context{
-		ctx:       ctx,
-		header:    header,
-		account:   account,
-		collector: effects.NewCollector(),
-		readOnly:  false,
-		gasUsed:   0,
-	}, nil
+	c := &Context{
+		ctx:          ctx,
+		header:       header,
+		account:      account,
+		collector:    effects.NewCollector(),
+		readOnly:     false,
+		gasMeter:     NewInfiniteGasMeter(),
+		eventManager: NewEventManager(),
+		txKeys:       make(map[string]bool),
+	}
+	c.randomSeed = deriveRandomSeed(header, nil)
+	c.randomness = NewRandomnessProvider(c.randomSeed)
+	return c, nil
 }
 
 // NewReadOnlyContext creates a read-only context for CheckTx
@@ -164,6 +230,17 @@ func (c *Context) ProposerAddress() []byte {
 	return proposer
 }
 
+// BlockHash returns the block hash, or nil if the consensus engine did not
+// provide it
+func (c *Context) BlockHash() []byte {
+	if c == nil || c.header == nil || c.header.Hash == nil {
+		return nil
+	}
+
+	// Return defensive copy
+	return append([]byte(nil), c.header.Hash...)
+}
+
 // Account returns the account executing the current transaction
 func (c *Context) Account() types.AccountName {
 	if c == nil {
@@ -240,20 +317,117 @@ func (c *Context) ClearEffects() {
 	c.collector.Clear()
 }
 
-// GasUsed returns the amount of gas used (placeholder for future gas metering)
+// GasUsed returns the amount of gas used
 func (c *Context) GasUsed() uint64 {
-	if c == nil {
+	if c == nil || c.gasMeter == nil {
 		return 0
 	}
-	return c.gasUsed
+	return c.gasMeter.GasConsumed()
 }
 
-// ConsumeGas adds to the gas usage counter (placeholder for future gas metering)
-func (c *Context) ConsumeGas(amount uint64) {
+// ConsumeGas charges gas against the context's gas meter.
+// Returns an error wrapping ErrOutOfGas if the limit is exceeded.
+func (c *Context) ConsumeGas(amount uint64) error {
 	if c == nil {
-		return
+		return fmt.Errorf("context is nil")
+	}
+	if c.gasMeter == nil {
+		return fmt.Errorf("gas meter is nil")
+	}
+	return c.gasMeter.ConsumeGas(amount, "handler")
+}
+
+// GasMeter returns the context's gas meter
+func (c *Context) GasMeter() GasMeter {
+	if c == nil {
+		return nil
+	}
+	return c.gasMeter
+}
+
+// EventManager returns the context's event manager
+func (c *Context) EventManager() *EventManager {
+	if c == nil {
+		return nil
+	}
+	return c.eventManager
+}
+
+// TxHash returns the hash of the executing transaction, or nil outside a
+// transaction. It is types.TxHash of the transaction's encoding, the hash the
+// mempool, state streaming and clients identify the transaction by.
+func (c *Context) TxHash() []byte {
+	if c == nil || c.txHash == nil {
+		return nil
+	}
+
+	// Return defensive copy
+	hash := make([]byte, len(c.txHash))
+	copy(hash, c.txHash)
+	return hash
+}
+
+// RandomSeed returns a seed derived deterministically from the chain ID,
+// block height, block time, block hash (if any), and transaction hash.
+//
+// SECURITY: The seed is predictable by anyone who knows the block and the
+// transaction, and block proposers can influence it. Use it only where every
+// node must make the same pseudo-random choice, never for secrets or for
+// outcomes worth manipulating.
+func (c *Context) RandomSeed() [32]byte {
+	if c == nil {
+		return [32]byte{}
+	}
+	return c.randomSeed
+}
+
+// Rand returns a new deterministic PRNG seeded with RandomSeed.
+// Each call returns a generator starting from the same state; use Randomness
+// for values that differ between calls.
+func (c *Context) Rand() *rand.Rand {
+	return rand.New(rand.NewChaCha8(c.RandomSeed()))
+}
+
+// Randomness returns the context's randomness provider. Every call on it
+// yields a fresh value, also across the nested handlers of a transaction, and
+// every node yields the same sequence.
+//
+// SECURITY: See RandomSeed; the values are consensus-safe, not secret.
+func (c *Context) Randomness() *RandomnessProvider {
+	if c == nil {
+		return NewRandomnessProvider([32]byte{})
 	}
-	c.gasUsed += amount
+	return c.randomness
+}
+
+// deriveRandomSeed hashes the block metadata and txHash into a seed.
+//
+// INVARIANT: Deterministic - identical inputs yield identical seeds on every node.
+func deriveRandomSeed(header *BlockHeader, txHash []byte) [32]byte {
+	h := sha256.New()
+	h.Write([]byte(randomSeedDomain))
+
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(len(header.ChainID)))
+	h.Write(buf[:])
+	h.Write([]byte(header.ChainID))
+	binary.BigEndian.PutUint64(buf[:], header.Height)
+	h.Write(buf[:])
+	binary.BigEndian.PutUint64(buf[:], uint64(header.Time.UnixNano()))
+	h.Write(buf[:])
+	// The block hash is only mixed in when present, so seeds of headers
+	// without one are unchanged
+	if len(header.Hash) > 0 {
+		h.Write([]byte(randomSeedBlockHashTag))
+		binary.BigEndian.PutUint64(buf[:], uint64(len(header.Hash)))
+		h.Write(buf[:])
+		h.Write(header.Hash)
+	}
+	h.Write(txHash)
+
+	var seed [32]byte
+	copy(seed[:], h.Sum(nil))
+	return seed
 }
 
 // WithContext returns a new Context with the given Go context
@@ -266,14 +440,9 @@ func (c *Context) WithContext(ctx context.Context) *Context {
 		return c
 	}
 
-	return &Context{
-		ctx:       ctx,
-		header:    c.header,
-		account:   c.account,
-		collector: c.collector,
-		readOnly:  c.readOnly,
-		gasUsed:   c.gasUsed,
-	}
+	cp := *c
+	cp.ctx = ctx
+	return &cp
 }
 
 // WithAccount returns a new Context with the given account
@@ -286,12 +455,150 @@ func (c *Context) WithAccount(account types.AccountName) (*Context, error) {
 		return nil, fmt.Errorf("invalid account: %s", account)
 	}
 
-	return &Context{
-		ctx:       c.ctx,
-		header:    c.header,
-		account:   account,
-		collector: effects.NewCollector(), // New collector for new account
-		readOnly:  c.readOnly,
-		gasUsed:   0, // Reset gas for new account
-	}, nil
+	cp := *c
+	cp.account = account
+	cp.txSigners = nil
+	cp.collector = effects.NewCollector()   // New collector for new account
+	cp.eventManager = NewEventManager()     // New events for new account
+	cp.gasMeter = NewGasMeter(c.gasLimit()) // Reset gas for new account
+	return &cp, nil
+}
+
+// WithTxHash returns a new Context for the transaction with the given hash
+// (types.TxHash of its encoding).
+// The random seed is re-derived to include the hash, with a new randomness
+// provider, and no key is claimed (see ClaimTxKey).
+func (c *Context) WithTxHash(txHash []byte) *Context {
+	if c == nil {
+		return nil
+	}
+
+	cp := *c
+	cp.txHash = append([]byte(nil), txHash...)
+	cp.randomSeed = deriveRandomSeed(c.header, cp.txHash)
+	cp.randomness = NewRandomnessProvider(cp.randomSeed)
+	cp.txKeys = make(map[string]bool)
+	return &cp
+}
+
+// ClaimTxKey claims key of module for the executing transaction, reporting
+// false if a message of the transaction already claimed it.
+//
+// Handlers read the state from before the transaction: the effects of its
+// earlier messages are not visible to them. A handler that settles, moves or
+// creates the object under a key claims the key once its checks pass, so that
+// a second message acting on the same object in the transaction is rejected
+// instead of passing the same checks. A claim is kept even if the message
+// claiming it fails later.
+func (c *Context) ClaimTxKey(module string, key []byte) bool {
+	if c == nil {
+		return false
+	}
+	if c.txKeys == nil {
+		c.txKeys = make(map[string]bool)
+	}
+	id := module + "/" + string(key)
+	if c.txKeys[id] {
+		return false
+	}
+	c.txKeys[id] = true
+	return true
+}
+
+// WithTxSigners returns a new Context for a transaction authorized by
+// signers, its account and co-signers (see types.Transaction.Signers).
+//
+// PRECONDITION: The runtime verified the authorization of every signer
+func (c *Context) WithTxSigners(signers ...types.AccountName) *Context {
+	if c == nil {
+		return nil
+	}
+
+	cp := *c
+	cp.txSigners = append([]types.AccountName(nil), signers...)
+	return &cp
+}
+
+// IsTxSigner reports whether account authorized the executing transaction.
+// Handlers of messages with several signers (e.g. bank's MsgMultiSend) use it
+// to check each one, since Account is only the signer the message executes as.
+// Account is always a signer.
+func (c *Context) IsTxSigner(account types.AccountName) bool {
+	if c == nil || account == "" {
+		return false
+	}
+	return account == c.account || slices.Contains(c.txSigners, account)
+}
+
+// WithGasMeter returns a new Context using the given gas meter.
+// A nil meter is replaced by an infinite one.
+func (c *Context) WithGasMeter(meter GasMeter) *Context {
+	if c == nil {
+		return nil
+	}
+
+	if meter == nil {
+		meter = NewInfiniteGasMeter()
+	}
+
+	cp := *c
+	cp.gasMeter = meter
+	return &cp
+}
+
+// HasAuthenticator reports whether an authenticator is registered under name,
+// so that handlers can reject accounts selecting an unknown one
+func (c *Context) HasAuthenticator(name string) bool {
+	if c == nil {
+		return false
+	}
+	_, ok := c.authenticators[name]
+	return ok
+}
+
+// withAuthenticators returns a new Context knowing the given authenticators
+func (c *Context) withAuthenticators(authenticators map[string]Authenticator) *Context {
+	cp := *c
+	cp.authenticators = authenticators
+	return &cp
+}
+
+// CallStack returns the modules whose message handlers are executing, outermost
+// first; the last entry is the module handling the current message.
+func (c *Context) CallStack() []string {
+	if c == nil {
+		return nil
+	}
+	return append([]string(nil), c.callStack...)
+}
+
+// withCall returns a Context for a handler of module invoked through router.
+// Gas meter, effects and events stay shared with c.
+func (c *Context) withCall(router *Router, module string) *Context {
+	cp := *c
+	cp.router = router
+	cp.callStack = make([]string, len(c.callStack), len(c.callStack)+1)
+	copy(cp.callStack, c.callStack)
+	cp.callStack = append(cp.callStack, module)
+	return &cp
+}
+
+// withSigner returns a Context executing as account, the signer of a
+// message in a multi-signer transaction (see types.Transaction.MsgSigner).
+// Gas meter, effects and events stay shared with c.
+func (c *Context) withSigner(account types.AccountName) *Context {
+	if account == "" || account == c.account {
+		return c
+	}
+	cp := *c
+	cp.account = account
+	return &cp
+}
+
+// gasLimit returns the current meter's limit, or unlimited if there is no meter.
+func (c *Context) gasLimit() uint64 {
+	if c.gasMeter == nil {
+		return math.MaxUint64
+	}
+	return c.gasMeter.Limit()
 }
@@ -401,3 +401,109 @@ func TestContext_WithAccount_Nil(t *testing.T) {
 	_, err := rctx.WithAccount(types.AccountName("alice"))
 	require.Error(t, err)
 }
+
+func TestContext_TxSigners(t *testing.T) {
+	header := NewBlockHeader(100, time.Now(), "test-chain", nil)
+
+	rctx, err := NewContext(context.Background(), header, "alice")
+	require.NoError(t, err)
+
+	// The account is always a signer
+	require.True(t, rctx.IsTxSigner("alice"))
+	require.False(t, rctx.IsTxSigner("bob"))
+	require.False(t, rctx.IsTxSigner(""))
+
+	signed := rctx.WithTxSigners("alice", "bob")
+	require.True(t, signed.IsTxSigner("bob"))
+	require.False(t, signed.IsTxSigner("carol"))
+	require.False(t, rctx.IsTxSigner("bob"))
+
+	// A context for another account does not inherit the signers
+	other, err := signed.WithAccount("carol")
+	require.NoError(t, err)
+	require.True(t, other.IsTxSigner("carol"))
+	require.False(t, other.IsTxSigner("bob"))
+
+	var nilCtx *Context
+	require.False(t, nilCtx.IsTxSigner("alice"))
+	require.Nil(t, nilCtx.WithTxSigners("alice"))
+}
+
+func TestContext_ClaimTxKey(t *testing.T) {
+	header := NewBlockHeader(100, time.Now(), "test-chain", nil)
+
+	rctx, err := NewContext(context.Background(), header, "alice")
+	require.NoError(t, err)
+	txCtx := rctx.WithTxHash([]byte{1})
+
+	require.True(t, txCtx.ClaimTxKey("escrow", []byte("htlc/a")))
+	require.True(t, txCtx.ClaimTxKey("nft", []byte("htlc/a")))
+
+	// Copies within the transaction share the claims
+	signer := txCtx.withSigner("bob")
+	require.False(t, signer.ClaimTxKey("escrow", []byte("htlc/a")))
+
+	// The next transaction starts with none
+	require.True(t, txCtx.WithTxHash([]byte{2}).ClaimTxKey("escrow", []byte("htlc/a")))
+
+	var nilCtx *Context
+	require.False(t, nilCtx.ClaimTxKey("escrow", []byte("htlc/a")))
+}
+
+func TestContext_TxHashAndRandomSeed(t *testing.T) {
+	header := NewBlockHeader(100, time.Unix(1700000000, 0), "test-chain", nil)
+
+	rctx, err := NewContext(context.Background(), header, "alice")
+	require.NoError(t, err)
+	require.Nil(t, rctx.TxHash())
+
+	txCtx := rctx.WithTxHash([]byte{1, 2, 3})
+	require.Equal(t, []byte{1, 2, 3}, txCtx.TxHash())
+	require.NotEqual(t, rctx.RandomSeed(), txCtx.RandomSeed())
+
+	// Same inputs give the same seed and the same random stream.
+	other, err := NewContext(context.Background(), header, "bob")
+	require.NoError(t, err)
+	other = other.WithTxHash([]byte{1, 2, 3})
+	require.Equal(t, txCtx.RandomSeed(), other.RandomSeed())
+	require.Equal(t, txCtx.Rand().Uint64(), other.Rand().Uint64())
+
+	// Different blocks give different seeds.
+	next, err := NewContext(context.Background(), NewBlockHeader(101, header.Time, "test-chain", nil), "alice")
+	require.NoError(t, err)
+	require.NotEqual(t, txCtx.RandomSeed(), next.WithTxHash([]byte{1, 2, 3}).RandomSeed())
+}
+
+func TestContext_GasMeter(t *testing.T) {
+	rctx, err := NewContext(context.Background(), NewBlockHeader(1, time.Now(), "test-chain", nil), "alice")
+	require.NoError(t, err)
+
+	limited := rctx.WithGasMeter(NewGasMeter(100))
+	require.NoError(t, limited.ConsumeGas(100))
+	require.ErrorIs(t, limited.ConsumeGas(1), ErrOutOfGas)
+	require.Equal(t, uint64(100), limited.GasUsed())
+
+	// The original context keeps its own meter.
+	require.Equal(t, uint64(0), rctx.GasUsed())
+
+	// WithAccount resets consumption but keeps the limit.
+	bob, err := limited.WithAccount("bob")
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), bob.GasUsed())
+	require.Equal(t, uint64(100), bob.GasMeter().Limit())
+}
+
+func TestContext_EventManager(t *testing.T) {
+	rctx, err := NewContext(context.Background(), NewBlockHeader(1, time.Now(), "test-chain", nil), "alice")
+	require.NoError(t, err)
+
+	event := types.NewEvent("transfer")
+	event.AddAttribute("amount", []byte("100"))
+	rctx.EventManager().EmitEvent(event)
+
+	// Mutating the emitted event does not affect the stored copy.
+	event.Attributes[0].Value[0] = '9'
+	events := rctx.EventManager().Events()
+	require.Len(t, events, 1)
+	require.Equal(t, []byte("100"), events[0].Attributes[0].Value)
+}
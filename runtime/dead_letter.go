@@ -0,0 +1,437 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// DefaultDeadLetterCapacity is the number of dead letters a DeadLetterQueue
+// keeps when no capacity is given
+const DefaultDeadLetterCapacity = 1000
+
+// DeadLetter is the post-mortem record of an effect batch that failed to
+// apply during a transaction
+type DeadLetter struct {
+	// Height is the height of the block the transaction ran in
+	Height uint64 `json:"height"`
+
+	// Time is the time of the block the transaction ran in
+	Time time.Time `json:"time"`
+
+	// TxHash is the hash of the transaction (see types.TxHash)
+	TxHash []byte `json:"tx_hash,omitempty"`
+
+	// Account is the transaction's account
+	Account types.AccountName `json:"account"`
+
+	// Messages are the messages whose effects were in the batch: all of the
+	// transaction's under MsgFailureAtomic, the failed one under
+	// MsgFailureContinue, none for a batch of ante effects only
+	Messages []DeadLetterMsg `json:"messages,omitempty"`
+
+	// Effects is the failed batch, in application order
+	Effects []DeadLetterEffect `json:"effects"`
+
+	// Error is the application error
+	Error string `json:"error"`
+
+	// Changes are the state writes of the batch that executed before the
+	// failure; they were discarded with the batch
+	Changes []StateChange `json:"changes,omitempty"`
+
+	// BalanceChanges are the balance updates of the batch that executed
+	// before the failure; they were discarded with the batch
+	BalanceChanges []BalanceChange `json:"balance_changes,omitempty"`
+}
+
+// DeadLetterMsg is a message of a DeadLetter
+type DeadLetterMsg struct {
+	// Type is the message type
+	Type string `json:"type"`
+
+	// Data is the JSON encoding of the message (empty if it has none)
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// DeadLetterEffect is an effect of a DeadLetter
+type DeadLetterEffect struct {
+	// Type is the effect type
+	Type string `json:"type"`
+
+	// Key is the effect's key
+	Key []byte `json:"key"`
+
+	// Data is the JSON encoding of the effect (empty if it has none)
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// StateChange is the net change of a state key
+type StateChange struct {
+	// Key is the state key
+	Key []byte `json:"key"`
+
+	// Before is the value before the batch (nil if absent)
+	Before []byte `json:"before,omitempty"`
+
+	// After is the value after the failure (nil if absent)
+	After []byte `json:"after,omitempty"`
+}
+
+// BalanceChange is the net change of a balance
+type BalanceChange struct {
+	Account types.AccountName `json:"account"`
+	Denom   string            `json:"denom"`
+	Before  uint64            `json:"before"`
+	After   uint64            `json:"after"`
+}
+
+// DeadLetterStore receives the dead letters of failed effect batches (see
+// ApplicationConfig.DeadLetters)
+type DeadLetterStore interface {
+	// Record stores letter. Errors are logged and otherwise ignored.
+	Record(letter *DeadLetter) error
+}
+
+// DeadLetterQueue is a DeadLetterStore keeping the most recent dead letters,
+// as JSON, in a store of their own (e.g. a separate database on the node).
+//
+// INVARIANT: The backing store holds the letters with sequence numbers in
+// [first, next), keyed by their big-endian sequence number, and
+// next-first <= capacity.
+//
+// Safe for concurrent use.
+type DeadLetterQueue struct {
+	mu       sync.Mutex
+	backing  store.BackingStore
+	capacity uint64
+	first    uint64
+	next     uint64
+}
+
+// NewDeadLetterQueue creates a queue keeping up to capacity letters in
+// backing (DefaultDeadLetterCapacity if capacity is 0), resuming after the
+// letters backing already holds.
+//
+// PRECONDITION: backing is dedicated to the queue and never part of the
+// consensus state.
+func NewDeadLetterQueue(backing store.BackingStore, capacity int) (*DeadLetterQueue, error) {
+	if backing == nil {
+		return nil, fmt.Errorf("backing store cannot be nil")
+	}
+	if capacity < 0 {
+		return nil, fmt.Errorf("dead letter capacity cannot be negative: %d", capacity)
+	}
+	if capacity == 0 {
+		capacity = DefaultDeadLetterCapacity
+	}
+
+	q := &DeadLetterQueue{backing: backing, capacity: uint64(capacity)}
+	first, ok, err := q.edge(backing.Iterator)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		last, _, err := q.edge(backing.ReverseIterator)
+		if err != nil {
+			return nil, err
+		}
+		q.first, q.next = first, last+1
+	}
+	return q, nil
+}
+
+// Record appends letter, dropping the oldest letter if the queue is full
+func (q *DeadLetterQueue) Record(letter *DeadLetter) error {
+	if q == nil {
+		return fmt.Errorf("dead letter queue is nil")
+	}
+	if letter == nil {
+		return fmt.Errorf("dead letter cannot be nil")
+	}
+
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to encode dead letter: %w", err)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err := q.backing.Set(deadLetterKey(q.next), data); err != nil {
+		return fmt.Errorf("failed to store dead letter: %w", err)
+	}
+	q.next++
+	for q.next-q.first > q.capacity {
+		if err := q.backing.Delete(deadLetterKey(q.first)); err != nil {
+			return fmt.Errorf("failed to drop dead letter: %w", err)
+		}
+		q.first++
+	}
+	return q.backing.Flush()
+}
+
+// Len returns the number of letters in the queue
+func (q *DeadLetterQueue) Len() int {
+	if q == nil {
+		return 0
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return int(q.next - q.first)
+}
+
+// Letters returns the letters in the queue, oldest first
+//
+// Complexity: O(capacity)
+func (q *DeadLetterQueue) Letters() ([]*DeadLetter, error) {
+	if q == nil {
+		return nil, fmt.Errorf("dead letter queue is nil")
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	letters := make([]*DeadLetter, 0, q.next-q.first)
+	for seq := q.first; seq < q.next; seq++ {
+		data, err := q.backing.Get(deadLetterKey(seq))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dead letter %d: %w", seq, err)
+		}
+		var letter DeadLetter
+		if err := json.Unmarshal(data, &letter); err != nil {
+			return nil, fmt.Errorf("failed to decode dead letter %d: %w", seq, err)
+		}
+		letters = append(letters, &letter)
+	}
+	return letters, nil
+}
+
+// edge returns the sequence number of the first letter iterate yields
+func (q *DeadLetterQueue) edge(iterate func(start, end []byte) (store.RawIterator, error)) (uint64, bool, error) {
+	it, err := iterate(nil, nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to open dead letter store: %w", err)
+	}
+	defer it.Close()
+
+	if !it.Valid() {
+		return 0, false, it.Error()
+	}
+	if len(it.Key()) != 8 {
+		return 0, false, fmt.Errorf("unexpected key %x in dead letter store", it.Key())
+	}
+	return binary.BigEndian.Uint64(it.Key()), true, nil
+}
+
+// deadLetterKey returns the key of the letter with sequence number seq
+func deadLetterKey(seq uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, seq)
+}
+
+// applyEffects applies effs, the effects of msgs (and of the ante handler),
+// in a branch of the state that is committed only if every effect succeeds.
+// A failed batch therefore leaves no state behind, even when some of its
+// effects ran before the failure. The failure is recorded as a DeadLetter if
+// ApplicationConfig.DeadLetters is set.
+//
+// SECURITY: Recording reads state but never writes it and does not change
+// the result, so nodes with and without dead letters stay in consensus.
+func (app *Application) applyEffects(ctx *Context, msgs []types.Message, effs []effects.Effect) (*effects.ExecutionResult, error) {
+	if err := app.effectApplier.Validate(effs); err != nil {
+		app.recordDeadLetter(ctx, msgs, effs, nil, nil, err)
+		return nil, err
+	}
+	if len(effs) == 0 {
+		return effects.NewExecutionResult(), nil
+	}
+
+	var snapshot *stateSnapshot
+	if app.deadLetters != nil {
+		snapshot = app.snapshotState(effs)
+	}
+	branch, err := app.effectExecutor.Branch()
+	if err != nil {
+		return nil, err
+	}
+	result, err := branch.Execute(effs)
+	if err != nil {
+		app.recordDeadLetter(ctx, msgs, effs, snapshot, branch, err)
+		return nil, err
+	}
+	if err := branch.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// recordDeadLetter records the failure of effs to ApplicationConfig.DeadLetters,
+// if set. With a snapshot, the changes are read from branch, the discarded
+// branch the batch failed in.
+func (app *Application) recordDeadLetter(ctx *Context, msgs []types.Message, effs []effects.Effect, snapshot *stateSnapshot, branch *effects.Branch, applyErr error) {
+	if app.deadLetters == nil {
+		return
+	}
+	letter := &DeadLetter{
+		Height:   ctx.BlockHeight(),
+		Time:     ctx.BlockTime(),
+		TxHash:   ctx.TxHash(),
+		Account:  ctx.Account(),
+		Messages: make([]DeadLetterMsg, 0, len(msgs)),
+		Effects:  make([]DeadLetterEffect, 0, len(effs)),
+		Error:    applyErr.Error(),
+	}
+	for _, msg := range msgs {
+		letter.Messages = append(letter.Messages, DeadLetterMsg{Type: msg.Type(), Data: jsonOrNil(msg)})
+	}
+	for _, effect := range effs {
+		if effect == nil {
+			letter.Effects = append(letter.Effects, DeadLetterEffect{Type: "nil"})
+			continue
+		}
+		letter.Effects = append(letter.Effects, DeadLetterEffect{
+			Type: effect.Type().String(),
+			Key:  effect.Key(),
+			Data: jsonOrNil(effect),
+		})
+	}
+	if snapshot != nil && branch != nil {
+		letter.Changes, letter.BalanceChanges = diffState(snapshot, branch)
+	}
+
+	if err := app.deadLetters.Record(letter); err != nil {
+		log.Printf("failed to record dead letter of tx %x at height %d: %v", letter.TxHash, letter.Height, err)
+	}
+}
+
+// jsonOrNil returns the JSON encoding of v, or nil if it has none
+func jsonOrNil(v any) json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// stateSnapshot holds the state an effect batch may change, before it runs
+type stateSnapshot struct {
+	keys     [][]byte
+	values   [][]byte
+	balances []BalanceChange
+}
+
+// snapshotState reads the keys written or deleted by effs and the balances
+// they transfer. Keys that cannot be read are left out.
+//
+// Complexity: O(len(effs)) store reads
+func (app *Application) snapshotState(effs []effects.Effect) *stateSnapshot {
+	snapshot := &stateSnapshot{}
+	seenKeys := make(map[string]bool)
+	seenBalances := make(map[string]bool)
+
+	for _, effect := range effs {
+		if transfer, ok := effect.(effects.TransferEffect); ok {
+			for _, coin := range transfer.Amount {
+				for _, account := range []types.AccountName{transfer.From, transfer.To} {
+					id := string(store.BalanceKey(account, coin.Denom))
+					if seenBalances[id] {
+						continue
+					}
+					seenBalances[id] = true
+					if amount, ok := app.readBalance(account, coin.Denom); ok {
+						snapshot.balances = append(snapshot.balances, BalanceChange{Account: account, Denom: coin.Denom, Before: amount})
+					}
+				}
+			}
+			continue
+		}
+
+		if effect == nil || (effect.Type() != effects.EffectTypeWrite && effect.Type() != effects.EffectTypeDelete) {
+			continue
+		}
+		key := effect.Key()
+		if seenKeys[string(key)] {
+			continue
+		}
+		seenKeys[string(key)] = true
+		if value, ok := app.readState(key); ok {
+			snapshot.keys = append(snapshot.keys, key)
+			snapshot.values = append(snapshot.values, value)
+		}
+	}
+	return snapshot
+}
+
+// diffState returns the snapshotted keys and balances that branch changed
+func diffState(snapshot *stateSnapshot, branch *effects.Branch) ([]StateChange, []BalanceChange) {
+	var changes []StateChange
+	for i, key := range snapshot.keys {
+		var after []byte
+		if branch.Has(key) {
+			value, err := branch.Get(key)
+			if err != nil {
+				continue
+			}
+			after = value
+			if after == nil {
+				after = []byte{}
+			}
+		}
+		if sameValue(after, snapshot.values[i]) {
+			continue
+		}
+		changes = append(changes, StateChange{Key: key, Before: snapshot.values[i], After: after})
+	}
+
+	var balanceChanges []BalanceChange
+	for _, balance := range snapshot.balances {
+		after, err := branch.GetBalance(balance.Account, balance.Denom)
+		if err != nil || after == balance.Before {
+			continue
+		}
+		balance.After = after
+		balanceChanges = append(balanceChanges, balance)
+	}
+	return changes, balanceChanges
+}
+
+// sameValue reports whether two values read by readState are equal,
+// telling an absent key (nil) from an empty value
+func sameValue(a, b []byte) bool {
+	return (a == nil) == (b == nil) && bytes.Equal(a, b)
+}
+
+// readState returns the value of key (nil if absent); ok is false if it
+// cannot be read
+func (app *Application) readState(key []byte) (value []byte, ok bool) {
+	value, err := app.stateStore.Get(key)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, true
+	}
+	if err != nil {
+		return nil, false
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	return value, true
+}
+
+// readBalance returns the balance of account in denom; ok is false if it
+// cannot be read
+func (app *Application) readBalance(account types.AccountName, denom string) (uint64, bool) {
+	balance, err := app.balanceStore.Get(context.Background(), account, denom)
+	if err != nil {
+		return 0, false
+	}
+	return balance.Amount, true
+}
@@ -0,0 +1,197 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	dbm "github.com/cosmos/cosmos-db"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+func TestDeadLetterQueue(t *testing.T) {
+	backing := store.NewMemoryStore()
+	queue, err := NewDeadLetterQueue(backing, 2)
+	if err != nil {
+		t.Fatalf("NewDeadLetterQueue failed: %v", err)
+	}
+
+	for height := uint64(1); height <= 3; height++ {
+		if err := queue.Record(&DeadLetter{Height: height, Error: "failed"}); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+	checkHeights := func(q *DeadLetterQueue, want ...uint64) {
+		t.Helper()
+		letters, err := q.Letters()
+		if err != nil {
+			t.Fatalf("Letters failed: %v", err)
+		}
+		if q.Len() != len(want) || len(letters) != len(want) {
+			t.Fatalf("expected %d letters, got Len %d and %d letters", len(want), q.Len(), len(letters))
+		}
+		for i, letter := range letters {
+			if letter.Height != want[i] {
+				t.Fatalf("letter %d: expected height %d, got %d", i, want[i], letter.Height)
+			}
+		}
+	}
+
+	// The oldest letter was dropped
+	checkHeights(queue, 2, 3)
+
+	// A queue reopened over the same store resumes after its letters
+	reopened, err := NewDeadLetterQueue(backing, 2)
+	if err != nil {
+		t.Fatalf("NewDeadLetterQueue failed: %v", err)
+	}
+	checkHeights(reopened, 2, 3)
+	if err := reopened.Record(&DeadLetter{Height: 4}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	checkHeights(reopened, 3, 4)
+
+	if _, err := NewDeadLetterQueue(nil, 1); err == nil {
+		t.Fatal("expected error for nil backing store")
+	}
+	if _, err := NewDeadLetterQueue(backing, -1); err == nil {
+		t.Fatal("expected error for negative capacity")
+	}
+}
+
+func TestApplication_DeadLetters(t *testing.T) {
+	priv := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	pubKey := priv.Public().(ed25519.PublicKey)
+	ctx := context.Background()
+
+	iavlStore, err := store.NewIAVLStore(dbm.NewMemDB(), 0)
+	if err != nil {
+		t.Fatalf("failed to create IAVL store: %v", err)
+	}
+	queue, err := NewDeadLetterQueue(store.NewMemoryStore(), 0)
+	if err != nil {
+		t.Fatalf("NewDeadLetterQueue failed: %v", err)
+	}
+
+	written := effects.NewStateWriteEffect("dlq", []byte("k"), []byte("v"))
+	transfer := effects.TransferEffect{From: "alice", To: "bob", Amount: types.NewCoins(types.NewCoin("token", 3))}
+	app, err := NewApplication(ApplicationConfig{
+		ChainID:    "test-chain",
+		StateStore: iavlStore,
+		Modules: []Module{&mockModule{
+			name: "dlq",
+			msgHandlers: map[string]MsgHandler{
+				// The second transfer overdraws alice after the write and the
+				// first transfer were executed
+				"dlq.partial": func(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+					return []effects.Effect{written, transfer, transfer}, nil
+				},
+				"dlq.ok": func(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+					return nil, nil
+				},
+			},
+		}},
+		DeadLetters: queue,
+	})
+	if err != nil {
+		t.Fatalf("failed to create application: %v", err)
+	}
+	if err := app.BeginBlock(ctx, NewBlockHeader(7, time.Now(), "test-chain", nil)); err != nil {
+		t.Fatalf("BeginBlock failed: %v", err)
+	}
+	if err := app.accountStore.Set(ctx, []byte("alice"), types.NewAccount("alice", pubKey)); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	if err := app.balanceStore.Set(ctx, store.NewBalance("alice", "token", 5)); err != nil {
+		t.Fatalf("failed to fund account: %v", err)
+	}
+
+	var lastHash []byte
+	nonce := uint64(0)
+	execute := func(msgTypes ...string) *types.TxResult {
+		t.Helper()
+		msgs := make([]types.Message, len(msgTypes))
+		for i, msgType := range msgTypes {
+			msgs[i] = &testMessage{msgType: msgType, signers: []types.AccountName{"alice"}}
+		}
+		tx := types.NewTransaction("alice", nonce, msgs, types.NewAuthorization())
+		tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+		signDoc, err := tx.ToSignDoc("test-chain", nonce)
+		if err != nil {
+			t.Fatalf("failed to build sign doc: %v", err)
+		}
+		signBytes, err := signDoc.GetSignBytes()
+		if err != nil {
+			t.Fatalf("failed to get sign bytes: %v", err)
+		}
+		tx.Authorization.Signatures = []types.Signature{{Algorithm: types.AlgorithmEd25519, PubKey: pubKey, Signature: ed25519.Sign(priv, signBytes)}}
+		lastHash = testTxHash(t, app, tx)
+		result, err := execTx(t, app, ctx, tx)
+		if err != nil {
+			t.Fatalf("executeTx failed: %v", err)
+		}
+		// The nonce advances whether or not the messages fail
+		nonce++
+		return result
+	}
+
+	result := execute("dlq.ok", "dlq.partial")
+	if result.IsOK() {
+		t.Fatal("expected the overdraw to fail the transaction")
+	}
+
+	letters, err := queue.Letters()
+	if err != nil {
+		t.Fatalf("Letters failed: %v", err)
+	}
+	if len(letters) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(letters))
+	}
+	letter := letters[0]
+
+	if letter.Height != 7 || letter.Account != "alice" || !strings.Contains(letter.Error, "failed to subtract") {
+		t.Fatalf("unexpected dead letter header: %+v", letter)
+	}
+	// The letter joins the transaction by the hash of its encoding
+	if !bytes.Equal(letter.TxHash, lastHash) {
+		t.Fatalf("letter tx hash = %x, want %x", letter.TxHash, lastHash)
+	}
+	if len(letter.Messages) != 2 || letter.Messages[0].Type != "dlq.ok" || letter.Messages[1].Type != "dlq.partial" {
+		t.Fatalf("unexpected messages: %+v", letter.Messages)
+	}
+	if len(letter.Effects) != 3 || letter.Effects[0].Type != effects.EffectTypeWrite.String() || letter.Effects[2].Type != effects.EffectTypeTransfer.String() {
+		t.Fatalf("unexpected effects: %+v", letter.Effects)
+	}
+
+	wantChanges := []StateChange{{Key: written.Key(), After: []byte("v")}}
+	if !reflect.DeepEqual(letter.Changes, wantChanges) {
+		t.Fatalf("changes = %+v, want %+v", letter.Changes, wantChanges)
+	}
+	wantBalances := []BalanceChange{
+		{Account: "alice", Denom: "token", Before: 5, After: 2},
+		{Account: "bob", Denom: "token", Before: 0, After: 3},
+	}
+	if !reflect.DeepEqual(letter.BalanceChanges, wantBalances) {
+		t.Fatalf("balance changes = %+v, want %+v", letter.BalanceChanges, wantBalances)
+	}
+
+	// The changes were discarded with the failed batch
+	if has, err := app.stateStore.Has(written.Key()); err != nil || has {
+		t.Fatalf("expected the write to be discarded, got has=%v err=%v", has, err)
+	}
+
+	// Successful batches leave no letter
+	if result := execute("dlq.ok"); !result.IsOK() {
+		t.Fatalf("expected success, got %s", result.Log)
+	}
+	if queue.Len() != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", queue.Len())
+	}
+}
@@ -0,0 +1,159 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+var (
+	// ErrDispatchNotAuthorized is returned when a module dispatches a message
+	// to a module its dispatcher was not granted
+	ErrDispatchNotAuthorized = errors.New("module dispatch not authorized")
+
+	// ErrReentrantDispatch is returned when a dispatch would re-enter a module
+	// whose handler is already executing
+	ErrReentrantDispatch = errors.New("re-entrant module dispatch")
+)
+
+// DefaultMaxModuleCallDepth bounds how deeply dispatches may nest
+const DefaultMaxModuleCallDepth = 8
+
+// DispatchGasCost is the gas charged per dispatch, on top of the gas the
+// callee's handler consumes
+const DispatchGasCost uint64 = 1_000
+
+// MsgDispatcher is the capability that lets a module's message handlers
+// synchronously invoke other modules' message handlers within the same
+// transaction, e.g. gov executing a bank send. It is the only way one
+// module's handler invokes another's.
+//
+// A dispatcher is bound to its caller module and the target modules it was
+// granted (see module.CapabilityDispatch). The callee runs with the same gas
+// meter, effect collector and event manager; its effects are returned to the
+// caller, which must return them from its own handler. Like every handler,
+// the callee sees state as of the start of the transaction's effect
+// application, not effects returned earlier in the same transaction.
+//
+// SECURITY: The callee executes as the caller module's account, and every
+// signer of a dispatched message must be that account. A module can therefore
+// only move its own funds or act with its own authority, never the
+// transaction signer's.
+//
+// INVARIANT: A module appears at most once in a Context's call stack, so
+// dispatch chains cannot re-enter a module mid-execution.
+type MsgDispatcher struct {
+	caller  string
+	targets map[string]struct{}
+}
+
+// NewMsgDispatcher creates a dispatcher for caller allowed to invoke the
+// message handlers of targets
+func NewMsgDispatcher(caller string, targets ...string) (*MsgDispatcher, error) {
+	if !types.AccountName(caller).IsValid() {
+		return nil, fmt.Errorf("%w: caller module %q is not a valid account name", types.ErrInvalidAccount, caller)
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("dispatcher for %s needs at least one target module", caller)
+	}
+
+	allowed := make(map[string]struct{}, len(targets))
+	for _, target := range targets {
+		if target == "" || target == caller {
+			return nil, fmt.Errorf("dispatcher for %s: invalid target module %q", caller, target)
+		}
+		allowed[target] = struct{}{}
+	}
+
+	return &MsgDispatcher{caller: caller, targets: allowed}, nil
+}
+
+// Caller returns the module the dispatcher was granted to
+func (d *MsgDispatcher) Caller() string {
+	if d == nil {
+		return ""
+	}
+	return d.caller
+}
+
+// Targets returns the modules the dispatcher may invoke, sorted
+func (d *MsgDispatcher) Targets() []string {
+	if d == nil {
+		return nil
+	}
+	targets := make([]string, 0, len(d.targets))
+	for target := range d.targets {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// Dispatch invokes the handler for msg and returns its effects.
+//
+// PRECONDITION: ctx is the Context passed to one of the caller module's
+// message handlers
+// POSTCONDITION: Returns ErrDispatchNotAuthorized if the handling module was
+// not granted, ErrReentrantDispatch if it is already on the call stack, and
+// ErrOutOfGas (wrapped) if the dispatch cost exceeds the remaining gas
+func (d *MsgDispatcher) Dispatch(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+	if d == nil {
+		return nil, fmt.Errorf("dispatcher is nil")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("context cannot be nil")
+	}
+	if msg == nil {
+		return nil, fmt.Errorf("message cannot be nil")
+	}
+	if ctx.router == nil || len(ctx.callStack) == 0 {
+		return nil, fmt.Errorf("%w: dispatch is only available to message handlers", ErrDispatchNotAuthorized)
+	}
+
+	// SECURITY: The dispatcher only acts for its own module's handlers
+	if current := ctx.callStack[len(ctx.callStack)-1]; current != d.caller {
+		return nil, fmt.Errorf("%w: dispatcher of %s used by %s", ErrDispatchNotAuthorized, d.caller, current)
+	}
+
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("%w: %v", types.ErrInvalidMessage, err)
+	}
+
+	msgType := msg.Type()
+	target, exists := ctx.router.MsgHandlerModule(msgType)
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrHandlerNotFound, msgType)
+	}
+	if _, ok := d.targets[target]; !ok {
+		return nil, fmt.Errorf("%w: %s may not call %s (%s)", ErrDispatchNotAuthorized, d.caller, target, msgType)
+	}
+	if slices.Contains(ctx.callStack, target) {
+		return nil, fmt.Errorf("%w: %s is already executing (call stack %v)", ErrReentrantDispatch, target, ctx.callStack)
+	}
+	if len(ctx.callStack) >= DefaultMaxModuleCallDepth {
+		return nil, fmt.Errorf("%w: dispatch depth exceeds %d", types.ErrMaxRecursionDepth, DefaultMaxModuleCallDepth)
+	}
+
+	callerAccount := types.AccountName(d.caller)
+	for _, signer := range msg.GetSigners() {
+		if signer != callerAccount {
+			return nil, fmt.Errorf("%w: dispatched message signer %s is not module account %s",
+				types.ErrUnauthorized, signer, callerAccount)
+		}
+	}
+
+	if err := ctx.ConsumeGas(DispatchGasCost); err != nil {
+		return nil, fmt.Errorf("dispatch from %s to %s: %w", d.caller, target, err)
+	}
+
+	// SECURITY: The callee is authorized by the caller module alone, not by
+	// the transaction's signers
+	callCtx := *ctx
+	callCtx.account = callerAccount
+	callCtx.txSigners = nil
+	return ctx.router.RouteMsg(&callCtx, msg)
+}
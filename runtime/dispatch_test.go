@@ -0,0 +1,364 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+const bankSendGas = 500
+
+// dispatchFixture wires gov (may call bank) and bank (may call gov back) to a
+// router. The gov handler dispatches govNext with dispatcher; the bank callback
+// handler dispatches bankNext.
+type dispatchFixture struct {
+	router     *Router
+	govDisp    *MsgDispatcher
+	bankDisp   *MsgDispatcher
+	dispatcher *MsgDispatcher
+	govNext    types.Message
+	bankNext   types.Message
+
+	bankAccount   types.AccountName
+	bankCallStack []string
+
+	// bankSeesAlice records whether the bank handler saw alice as a
+	// transaction signer
+	bankSeesAlice bool
+}
+
+func newDispatchFixture(t *testing.T) *dispatchFixture {
+	t.Helper()
+
+	f := &dispatchFixture{router: NewRouter()}
+
+	var err error
+	if f.govDisp, err = NewMsgDispatcher("gov", "bank"); err != nil {
+		t.Fatalf("NewMsgDispatcher failed: %v", err)
+	}
+	if f.bankDisp, err = NewMsgDispatcher("bank", "gov"); err != nil {
+		t.Fatalf("NewMsgDispatcher failed: %v", err)
+	}
+	f.dispatcher = f.govDisp
+
+	modules := []*mockModule{
+		{name: "gov", msgHandlers: map[string]MsgHandler{
+			"/gov.exec": func(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+				return f.dispatcher.Dispatch(ctx, f.govNext)
+			},
+		}},
+		{name: "bank", msgHandlers: map[string]MsgHandler{
+			"/bank.send": func(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+				f.bankAccount = ctx.Account()
+				f.bankCallStack = ctx.CallStack()
+				f.bankSeesAlice = ctx.IsTxSigner("alice")
+				if err := ctx.ConsumeGas(bankSendGas); err != nil {
+					return nil, err
+				}
+				return []effects.Effect{effects.NewStateWriteEffect("bank", []byte("sent"), []byte("yes"))}, nil
+			},
+			"/bank.callback": func(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+				return f.bankDisp.Dispatch(ctx, f.bankNext)
+			},
+		}},
+		{name: "staking", msgHandlers: map[string]MsgHandler{
+			"/staking.delegate": func(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+				return nil, nil
+			},
+		}},
+	}
+	for _, mod := range modules {
+		if err := f.router.RegisterModule(mod); err != nil {
+			t.Fatalf("failed to register module: %v", err)
+		}
+	}
+	return f
+}
+
+func newDispatchContext(t *testing.T, gasLimit uint64) *Context {
+	t.Helper()
+
+	ctx, err := NewContext(context.Background(), NewBlockHeader(1, time.Now(), "test-chain", nil), "alice")
+	if err != nil {
+		t.Fatalf("failed to create context: %v", err)
+	}
+	return ctx.WithGasMeter(NewGasMeter(gasLimit))
+}
+
+func TestMsgDispatcher_Dispatch(t *testing.T) {
+	f := newDispatchFixture(t)
+	ctx := newDispatchContext(t, 1_000_000).WithTxSigners("alice")
+	f.govNext = &testMessage{msgType: "/bank.send", signers: []types.AccountName{"gov"}}
+
+	result, err := f.router.RouteMsg(ctx, &testMessage{msgType: "/gov.exec", signers: []types.AccountName{"alice"}})
+	if err != nil {
+		t.Fatalf("RouteMsg failed: %v", err)
+	}
+	if len(result) != 1 || result[0].Type() != effects.EffectTypeWrite {
+		t.Fatalf("expected the bank write effect, got %v", result)
+	}
+
+	// The callee runs as the calling module, below it on the call stack
+	if f.bankAccount != "gov" {
+		t.Errorf("expected callee account gov, got %s", f.bankAccount)
+	}
+	if !slices.Equal(f.bankCallStack, []string{"gov", "bank"}) {
+		t.Errorf("expected call stack [gov bank], got %v", f.bankCallStack)
+	}
+	// ...and is not authorized by the transaction's signers
+	if f.bankSeesAlice {
+		t.Error("callee sees the transaction signer as a signer")
+	}
+
+	// Dispatch and callee gas are charged to the shared meter
+	if got, want := ctx.GasUsed(), DispatchGasCost+bankSendGas; got != want {
+		t.Errorf("expected gas used %d, got %d", want, got)
+	}
+	if ctx.Account() != "alice" || len(ctx.CallStack()) != 0 {
+		t.Errorf("outer context modified: account %s, call stack %v", ctx.Account(), ctx.CallStack())
+	}
+}
+
+func TestMsgDispatcher_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(f *dispatchFixture)
+		gasLimit uint64
+		wantErr  error
+	}{
+		{
+			name: "target not granted",
+			setup: func(f *dispatchFixture) {
+				f.govNext = &testMessage{msgType: "/staking.delegate", signers: []types.AccountName{"gov"}}
+			},
+			wantErr: ErrDispatchNotAuthorized,
+		},
+		{
+			name: "unknown message type",
+			setup: func(f *dispatchFixture) {
+				f.govNext = &testMessage{msgType: "/unknown.msg", signers: []types.AccountName{"gov"}}
+			},
+			wantErr: ErrHandlerNotFound,
+		},
+		{
+			name: "signer is not the calling module",
+			setup: func(f *dispatchFixture) {
+				f.govNext = &testMessage{msgType: "/bank.send", signers: []types.AccountName{"alice"}}
+			},
+			wantErr: types.ErrUnauthorized,
+		},
+		{
+			name: "re-entrant call",
+			setup: func(f *dispatchFixture) {
+				f.govNext = &testMessage{msgType: "/bank.callback", signers: []types.AccountName{"gov"}}
+				f.bankNext = &testMessage{msgType: "/gov.exec", signers: []types.AccountName{"bank"}}
+			},
+			wantErr: ErrReentrantDispatch,
+		},
+		{
+			name: "dispatcher of another module",
+			setup: func(f *dispatchFixture) {
+				f.dispatcher = f.bankDisp
+				f.govNext = &testMessage{msgType: "/gov.exec", signers: []types.AccountName{"bank"}}
+			},
+			wantErr: ErrDispatchNotAuthorized,
+		},
+		{
+			name: "out of gas",
+			setup: func(f *dispatchFixture) {
+				f.govNext = &testMessage{msgType: "/bank.send", signers: []types.AccountName{"gov"}}
+			},
+			gasLimit: DispatchGasCost - 1,
+			wantErr:  ErrOutOfGas,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newDispatchFixture(t)
+			tt.setup(f)
+			gasLimit := tt.gasLimit
+			if gasLimit == 0 {
+				gasLimit = 1_000_000
+			}
+			ctx := newDispatchContext(t, gasLimit)
+
+			_, err := f.router.RouteMsg(ctx, &testMessage{msgType: "/gov.exec", signers: []types.AccountName{"alice"}})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMsgDispatcher_OutsideHandler(t *testing.T) {
+	f := newDispatchFixture(t)
+	ctx := newDispatchContext(t, 1_000_000)
+
+	_, err := f.govDisp.Dispatch(ctx, &testMessage{msgType: "/bank.send", signers: []types.AccountName{"gov"}})
+	if !errors.Is(err, ErrDispatchNotAuthorized) {
+		t.Fatalf("expected ErrDispatchNotAuthorized, got %v", err)
+	}
+	if ctx.GasUsed() != 0 {
+		t.Errorf("expected no gas charged, got %d", ctx.GasUsed())
+	}
+}
+
+func TestNewMsgDispatcher_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		caller  string
+		targets []string
+	}{
+		{"invalid caller", "Gov!", []string{"bank"}},
+		{"no targets", "gov", nil},
+		{"self target", "gov", []string{"gov"}},
+		{"empty target", "gov", []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewMsgDispatcher(tt.caller, tt.targets...); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+// circuitBreakerFunc adapts a function to CircuitBreaker
+type circuitBreakerFunc func(ctx *Context, msgType string) (bool, error)
+
+func (f circuitBreakerFunc) IsAllowed(ctx *Context, msgType string) (bool, error) {
+	return f(ctx, msgType)
+}
+
+func TestRouter_CircuitBreaker(t *testing.T) {
+	f := newDispatchFixture(t)
+	f.govNext = &testMessage{msgType: "/bank.send", signers: []types.AccountName{"gov"}}
+	send := &testMessage{msgType: "/bank.send", signers: []types.AccountName{"alice"}}
+	exec := &testMessage{msgType: "/gov.exec", signers: []types.AccountName{"alice"}}
+
+	f.router.SetCircuitBreaker(circuitBreakerFunc(func(ctx *Context, msgType string) (bool, error) {
+		return msgType != "/bank.send", nil
+	}))
+
+	if _, err := f.router.RouteMsg(newDispatchContext(t, 1_000_000), send); !errors.Is(err, ErrCircuitBreakerTripped) {
+		t.Fatalf("expected ErrCircuitBreakerTripped, got %v", err)
+	}
+
+	// Module-to-module dispatch is checked too
+	if _, err := f.router.RouteMsg(newDispatchContext(t, 1_000_000), exec); !errors.Is(err, ErrCircuitBreakerTripped) {
+		t.Fatalf("expected dispatched message to be rejected, got %v", err)
+	}
+
+	// Other message types are routed
+	delegate := &testMessage{msgType: "/staking.delegate", signers: []types.AccountName{"alice"}}
+	if _, err := f.router.RouteMsg(newDispatchContext(t, 1_000_000), delegate); err != nil {
+		t.Fatalf("RouteMsg failed: %v", err)
+	}
+
+	// Breaker failures reject the message
+	breakerErr := errors.New("state unavailable")
+	f.router.SetCircuitBreaker(circuitBreakerFunc(func(ctx *Context, msgType string) (bool, error) {
+		return false, breakerErr
+	}))
+	if _, err := f.router.RouteMsg(newDispatchContext(t, 1_000_000), send); !errors.Is(err, breakerErr) {
+		t.Fatalf("expected breaker error, got %v", err)
+	}
+
+	f.router.SetCircuitBreaker(nil)
+	if _, err := f.router.RouteMsg(newDispatchContext(t, 1_000_000), send); err != nil {
+		t.Fatalf("expected no check without a breaker, got %v", err)
+	}
+}
+
+func TestRouter_RecoversHandlerPanics(t *testing.T) {
+	var panicValue any
+	mod := &mockModule{name: "buggy", msgHandlers: map[string]MsgHandler{
+		"/buggy.panic": func(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+			panic(panicValue)
+		},
+	}}
+	router := NewRouter()
+	if err := router.RegisterModule(mod); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	var logged []PanicInfo
+	router.SetPanicLogger(func(info PanicInfo) { logged = append(logged, info) })
+	msg := &testMessage{msgType: "/buggy.panic", signers: []types.AccountName{"alice"}}
+
+	tests := []struct {
+		name    string
+		value   any
+		wantErr error
+	}{
+		{name: "string", value: "boom", wantErr: ErrHandlerPanic},
+		{name: "pointer", value: &struct{}{}, wantErr: ErrHandlerPanic},
+		{name: "error", value: errors.New("boom"), wantErr: ErrHandlerPanic},
+		{name: "out of gas", value: NewGasMeter(1).ConsumeGas(2, "test"), wantErr: ErrOutOfGas},
+	}
+
+	var messages []string
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			panicValue = tt.value
+			effs, err := router.RouteMsg(newDispatchContext(t, 1_000_000), msg)
+			if !errors.Is(err, tt.wantErr) || effs != nil {
+				t.Fatalf("expected %v and no effects, got %v, %v", tt.wantErr, effs, err)
+			}
+			if errors.Is(err, ErrHandlerPanic) {
+				messages = append(messages, err.Error())
+			}
+		})
+	}
+
+	// The error does not depend on the panic value, so all nodes agree on it
+	for _, m := range messages {
+		if m != messages[0] {
+			t.Errorf("expected identical error messages, got %q and %q", messages[0], m)
+		}
+	}
+
+	if len(logged) != len(tests) {
+		t.Fatalf("expected %d logged panics, got %d", len(tests), len(logged))
+	}
+	if info := logged[0]; info.Module != "buggy" || info.MsgType != "/buggy.panic" || info.Height != 1 || info.Value != "boom" || len(info.Stack) == 0 {
+		t.Errorf("unexpected panic info: %+v", info)
+	}
+}
+
+func TestRouter_RecoversDispatchedPanics(t *testing.T) {
+	f := newDispatchFixture(t)
+	var panicked []string
+	f.router.SetPanicLogger(func(info PanicInfo) { panicked = append(panicked, info.Module) })
+	f.govNext = &testMessage{msgType: "/bank.send", signers: []types.AccountName{"gov"}}
+
+	// Bank runs out of gas, and panics, after the dispatch is charged; the
+	// panic is an error for the dispatching handler
+	ctx := newDispatchContext(t, DispatchGasCost+1)
+	ctx = ctx.WithGasMeter(panickingGasMeter{ctx.GasMeter()})
+	_, err := f.router.RouteMsg(ctx, &testMessage{msgType: "/gov.exec", signers: []types.AccountName{"alice"}})
+	if !errors.Is(err, ErrOutOfGas) {
+		t.Fatalf("expected ErrOutOfGas, got %v", err)
+	}
+	if !slices.Equal(panicked, []string{"bank"}) {
+		t.Fatalf("expected the bank handler panic to be recovered, got %v", panicked)
+	}
+}
+
+// panickingGasMeter panics instead of returning out-of-gas errors
+type panickingGasMeter struct {
+	GasMeter
+}
+
+func (m panickingGasMeter) ConsumeGas(amount uint64, descriptor string) error {
+	if err := m.GasMeter.ConsumeGas(amount, descriptor); err != nil {
+		panic(err)
+	}
+	return nil
+}
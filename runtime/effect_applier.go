@@ -0,0 +1,75 @@
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/events"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// EffectApplier applies the effects returned by handlers.
+//
+// Application runs in two steps, none of which mutates state until the last:
+//
+//  1. Validation: every effect's Validate must pass (types.ErrInvalidEffect).
+//  2. Execution: the effect list runs through the effects.Executor.
+//
+// Modules invoke one another only through a MsgDispatcher, before their
+// handler returns; effects never carry messages to other modules.
+//
+// The list is returned as an effects.Journal, which can be fingerprinted to
+// check determinism or replayed against a copy of the starting state.
+type EffectApplier struct {
+	executor *effects.Executor
+}
+
+// NewEffectApplier creates an effect applier executing through executor
+func NewEffectApplier(executor *effects.Executor) (*EffectApplier, error) {
+	if executor == nil {
+		return nil, fmt.Errorf("executor cannot be nil")
+	}
+
+	return &EffectApplier{executor: executor}, nil
+}
+
+// Validate validates effs without applying anything
+func (a *EffectApplier) Validate(effs []effects.Effect) error {
+	if a == nil {
+		return fmt.Errorf("effect applier is nil")
+	}
+	if err := effects.ValidateEffects(effs); err != nil {
+		return fmt.Errorf("%w: %v", types.ErrInvalidEffect, err)
+	}
+	return nil
+}
+
+// Apply validates effs, then executes them.
+// On error no effect has been executed unless the executor itself failed.
+func (a *EffectApplier) Apply(ctx *Context, effs []effects.Effect) (*effects.ExecutionResult, *effects.Journal, error) {
+	if ctx == nil {
+		return nil, nil, fmt.Errorf("context cannot be nil")
+	}
+	if err := a.Validate(effs); err != nil {
+		return nil, nil, err
+	}
+	if len(effs) == 0 {
+		return effects.NewExecutionResult(), effects.NewJournal(nil), nil
+	}
+
+	result, err := a.executor.Execute(effs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, effects.NewJournal(effs), nil
+}
+
+// toTxEvents converts execution events to transaction events (see
+// events.FromEffect)
+func toTxEvents(execEvents []effects.Event) []types.Event {
+	txEvents := make([]types.Event, len(execEvents))
+	for i, event := range execEvents {
+		txEvents[i] = events.FromEffect(event)
+	}
+	return txEvents
+}
@@ -0,0 +1,196 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// setupApplier returns an effect applier over an IAVL store
+func setupApplier(t *testing.T) (*EffectApplier, *store.IAVLStore, *Context) {
+	t.Helper()
+
+	iavlStore, err := store.NewIAVLStore(store.NewMemDB(), 0)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	executor, err := effects.NewExecutor(&iavlStoreAdapter{store: iavlStore}, &balanceStoreAdapter{store: store.NewBalanceStore(iavlStore)})
+	if err != nil {
+		t.Fatalf("failed to create executor: %v", err)
+	}
+
+	applier, err := NewEffectApplier(executor)
+	if err != nil {
+		t.Fatalf("NewEffectApplier failed: %v", err)
+	}
+
+	ctx, err := NewContext(context.Background(), NewBlockHeader(1, time.Now(), "test-chain", nil), "alice")
+	if err != nil {
+		t.Fatalf("failed to create context: %v", err)
+	}
+	return applier, iavlStore, ctx
+}
+
+func TestEffectApplier_Apply(t *testing.T) {
+	applier, s, ctx := setupApplier(t)
+
+	effs := []effects.Effect{
+		effects.NewStateWriteEffect("gov", []byte("executed"), []byte("yes")),
+		effects.NewStateWriteEffect("bank", []byte("sent"), []byte("yes")),
+	}
+	_, journal, err := applier.Apply(ctx, effs)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	// The journal records the effects in order
+	entries := journal.Effects()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 journal entries, got %d", len(entries))
+	}
+	if string(entries[0].Key()) != "module/gov/executed" || string(entries[1].Key()) != "module/bank/sent" {
+		t.Fatalf("unexpected journal order: %q, %q", entries[0].Key(), entries[1].Key())
+	}
+
+	for _, key := range []string{"module/gov/executed", "module/bank/sent"} {
+		value, err := s.Get([]byte(key))
+		if err != nil || string(value) != "yes" {
+			t.Fatalf("expected %s to be written, got %q (%v)", key, value, err)
+		}
+	}
+
+	fromEffects, err := effects.Fingerprint(effs)
+	if err != nil {
+		t.Fatalf("Fingerprint failed: %v", err)
+	}
+	fromJournal, err := journal.Fingerprint()
+	if err != nil {
+		t.Fatalf("Fingerprint failed: %v", err)
+	}
+	if !bytes.Equal(fromEffects, fromJournal) {
+		t.Fatal("journal does not match the applied effects")
+	}
+}
+
+func TestEffectApplier_StateWritesUpdateIndexes(t *testing.T) {
+	iavlStore, err := store.NewIAVLStore(store.NewMemDB(), 0)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	capMgr := capability.NewCapabilityManager(iavlStore)
+	if err := capMgr.RegisterModule("bank"); err != nil {
+		t.Fatalf("failed to register module: %v", err)
+	}
+	byDenom, err := capMgr.RegisterIndex("bank", "by_denom", store.BalanceDenomIndex)
+	if err != nil {
+		t.Fatalf("failed to register index: %v", err)
+	}
+
+	executor, err := effects.NewExecutor(&iavlStoreAdapter{store: iavlStore, capMgr: capMgr}, &balanceStoreAdapter{store: store.NewBalanceStore(iavlStore)})
+	if err != nil {
+		t.Fatalf("failed to create executor: %v", err)
+	}
+	applier, err := NewEffectApplier(executor)
+	if err != nil {
+		t.Fatalf("NewEffectApplier failed: %v", err)
+	}
+	ctx, err := NewContext(context.Background(), NewBlockHeader(1, time.Now(), "test-chain", nil), "alice")
+	if err != nil {
+		t.Fatalf("failed to create context: %v", err)
+	}
+
+	key := store.BalanceKey("carol", "uatom")
+	if _, _, err := applier.Apply(ctx, []effects.Effect{
+		effects.NewStateWriteEffect("bank", key, []byte(`{"account":"carol","denom":"uatom","amount":4}`)),
+	}); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	holders, err := byDenom.Lookup([]byte("uatom"))
+	if err != nil {
+		t.Fatalf("failed to look up index: %v", err)
+	}
+	if len(holders) != 1 || string(holders[0]) != "carol/uatom" {
+		t.Fatalf("uatom holders = %q, want [carol/uatom]", holders)
+	}
+	if err := capMgr.CheckIndexes("bank"); err != nil {
+		t.Fatalf("index inconsistent after write: %v", err)
+	}
+
+	if _, _, err := applier.Apply(ctx, []effects.Effect{effects.NewStateDeleteEffect("bank", key)}); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if err := capMgr.CheckIndexes("bank"); err != nil {
+		t.Fatalf("index inconsistent after delete: %v", err)
+	}
+}
+
+func TestEffectApplier_Errors(t *testing.T) {
+	applier, s, ctx := setupApplier(t)
+
+	tests := []struct {
+		name    string
+		effects []effects.Effect
+		wantErr error
+	}{
+		{
+			name:    "invalid effect",
+			effects: []effects.Effect{effects.StateWriteEffect{Module: "bank"}},
+			wantErr: types.ErrInvalidEffect,
+		},
+		{
+			name:    "nil effect",
+			effects: []effects.Effect{nil},
+			wantErr: types.ErrInvalidEffect,
+		},
+		{
+			// Nothing is applied when a later effect fails validation
+			name: "atomic validation",
+			effects: []effects.Effect{
+				effects.NewStateWriteEffect("bank", []byte("partial"), []byte("yes")),
+				effects.StateWriteEffect{Module: "bank"},
+			},
+			wantErr: types.ErrInvalidEffect,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := applier.Apply(ctx, tt.effects)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if has, _ := s.Has([]byte("module/bank/partial")); has {
+		t.Fatal("effects were applied despite a validation failure")
+	}
+
+	if _, err := NewEffectApplier(nil); err == nil {
+		t.Fatal("expected error for nil executor")
+	}
+}
+
+func TestToTxEvents_SortedAttributes(t *testing.T) {
+	events := toTxEvents([]effects.Event{{
+		Type:       "transfer",
+		Attributes: map[string][]byte{"to": []byte("bob"), "amount": []byte("5"), "from": []byte("alice")},
+	}})
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+
+	var keys []string
+	for _, attr := range events[0].Attributes {
+		keys = append(keys, attr.Key)
+	}
+	if len(keys) != 3 || keys[0] != "amount" || keys[1] != "from" || keys[2] != "to" {
+		t.Fatalf("expected sorted attributes, got %v", keys)
+	}
+}
@@ -0,0 +1,78 @@
+package runtime
+
+import (
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// EventManager collects events emitted by handlers during execution.
+// Events are informational; state changes must still be expressed as effects.
+//
+// INVARIANT: Events are returned in emission order.
+//
+// Not safe for concurrent use; each Context owns its EventManager.
+type EventManager struct {
+	events []types.Event
+}
+
+// NewEventManager creates an empty event manager
+func NewEventManager() *EventManager {
+	return &EventManager{events: make([]types.Event, 0)}
+}
+
+// EmitEvent appends an event
+func (em *EventManager) EmitEvent(event types.Event) {
+	if em == nil {
+		return
+	}
+	em.events = append(em.events, cloneEvent(event))
+}
+
+// EmitEvents appends multiple events
+func (em *EventManager) EmitEvents(events []types.Event) {
+	for _, event := range events {
+		em.EmitEvent(event)
+	}
+}
+
+// Events returns a copy of the emitted events
+func (em *EventManager) Events() []types.Event {
+	if em == nil {
+		return nil
+	}
+	events := make([]types.Event, len(em.events))
+	for i, event := range em.events {
+		events[i] = cloneEvent(event)
+	}
+	return events
+}
+
+// len returns the number of emitted events
+func (em *EventManager) len() int {
+	if em == nil {
+		return 0
+	}
+	return len(em.events)
+}
+
+// truncate drops the events emitted after the first n
+func (em *EventManager) truncate(n int) {
+	if em == nil || n >= len(em.events) {
+		return
+	}
+	em.events = em.events[:n]
+}
+
+// cloneEvent returns a deep copy of event so callers cannot mutate stored events
+func cloneEvent(event types.Event) types.Event {
+	clone := types.Event{
+		Type:       event.Type,
+		Attributes: make([]types.EventAttribute, len(event.Attributes)),
+	}
+	for i, attr := range event.Attributes {
+		clone.Attributes[i] = types.EventAttribute{
+			Key:   attr.Key,
+			Value: append([]byte(nil), attr.Value...),
+		}
+	}
+	return clone
+}
@@ -0,0 +1,59 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// ErrFeeGrantUnsupported is returned for transactions naming a fee granter
+// on an application without a FeeGrantHandler
+var ErrFeeGrantUnsupported = errors.New("fee grants not supported")
+
+// FeeGrantHandler checks that tx.FeeGranter has granted tx.FeeGrantee() an
+// allowance covering tx.Fee and returns the effects consuming it. It runs
+// in CheckTx with a read-only context and in ExecuteTx after the
+// transaction's authorization is verified, before the ante handler; its
+// effects are applied together with the transaction's other effects.
+//
+// SECURITY: The granter does not sign the transaction; the allowance is its
+// only consent to pay, so the handler must reject a transaction it cannot
+// match to a grant.
+type FeeGrantHandler func(ctx *Context, tx *types.Transaction) ([]effects.Effect, error)
+
+// verifyFeeSponsors verifies that the transaction's fee payer, if any,
+// authorized it, and runs the fee grant handler if it names a fee granter.
+// It returns the fee grant handler's effects.
+//
+// PRECONDITION: tx passed ValidateBasic and account is tx.Account.
+func (app *Application) verifyFeeSponsors(ctx *Context, tx *types.Transaction, account *types.Account) ([]effects.Effect, error) {
+	if tx.FeePayer != "" {
+		payer, err := app.accountGetter.GetAccount(tx.FeePayer)
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return nil, fmt.Errorf("%w: fee payer %s", types.ErrNotFound, tx.FeePayer)
+			}
+			return nil, fmt.Errorf("failed to get fee payer: %w", err)
+		}
+		// RATIONALE: Authenticators authorize their account's own
+		// transactions; they are not asked to vouch for paying another's
+		if payer.Authenticator != "" {
+			return nil, fmt.Errorf("%w: fee payer %s uses authenticator %s",
+				types.ErrInvalidTransaction, payer.Name, payer.Authenticator)
+		}
+		if err := tx.VerifyFeePayerAuthorization(app.chainID, account, payer, app.accountGetter); err != nil {
+			return nil, err
+		}
+	}
+
+	if tx.FeeGranter == "" {
+		return nil, nil
+	}
+	if app.feeGrantHandler == nil {
+		return nil, fmt.Errorf("%w: fee granter %s", ErrFeeGrantUnsupported, tx.FeeGranter)
+	}
+	return app.feeGrantHandler(ctx, tx)
+}
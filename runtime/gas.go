@@ -0,0 +1,80 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrOutOfGas is returned when consuming gas would exceed a GasMeter's limit.
+var ErrOutOfGas = errors.New("out of gas")
+
+// GasMeter tracks gas consumption against a limit.
+//
+// INVARIANT: GasConsumed() <= Limit() after every successful ConsumeGas.
+type GasMeter interface {
+	// GasConsumed returns the gas consumed so far.
+	GasConsumed() uint64
+
+	// Limit returns the gas limit. Unlimited meters return math.MaxUint64.
+	Limit() uint64
+
+	// GasRemaining returns Limit() - GasConsumed().
+	GasRemaining() uint64
+
+	// ConsumeGas charges amount of gas, annotated with descriptor for error
+	// messages. Returns ErrOutOfGas (wrapped) if the limit would be exceeded;
+	// the meter is then left saturated at its limit.
+	ConsumeGas(amount uint64, descriptor string) error
+
+	// IsOutOfGas returns true once the limit has been reached.
+	IsOutOfGas() bool
+}
+
+// basicGasMeter is a GasMeter with a fixed limit.
+type basicGasMeter struct {
+	limit    uint64
+	consumed uint64
+}
+
+// NewGasMeter creates a GasMeter with the given limit.
+func NewGasMeter(limit uint64) GasMeter {
+	return &basicGasMeter{limit: limit}
+}
+
+// NewInfiniteGasMeter creates a GasMeter that tracks consumption but never
+// runs out. Used for block-level execution and transactions without a gas limit.
+func NewInfiniteGasMeter() GasMeter {
+	return &basicGasMeter{limit: math.MaxUint64}
+}
+
+// GasConsumed implements GasMeter.
+func (g *basicGasMeter) GasConsumed() uint64 {
+	return g.consumed
+}
+
+// Limit implements GasMeter.
+func (g *basicGasMeter) Limit() uint64 {
+	return g.limit
+}
+
+// GasRemaining implements GasMeter.
+func (g *basicGasMeter) GasRemaining() uint64 {
+	return g.limit - g.consumed
+}
+
+// ConsumeGas implements GasMeter.
+// SECURITY: Overflow-safe; a charge that would wrap uint64 is out of gas.
+func (g *basicGasMeter) ConsumeGas(amount uint64, descriptor string) error {
+	if amount > g.limit-g.consumed {
+		g.consumed = g.limit
+		return fmt.Errorf("%w: %s: limit %d, wanted %d more", ErrOutOfGas, descriptor, g.limit, amount)
+	}
+	g.consumed += amount
+	return nil
+}
+
+// IsOutOfGas implements GasMeter.
+func (g *basicGasMeter) IsOutOfGas() bool {
+	return g.consumed >= g.limit
+}
@@ -0,0 +1,300 @@
+package runtime
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Default gas schedule costs
+const (
+	DefaultMsgBaseCost      uint64 = 1_000
+	DefaultSigVerifyCost    uint64 = 1_000
+	DefaultReadCostPerByte  uint64 = 3
+	DefaultWriteCostPerByte uint64 = 30
+)
+
+// MaxGasCost bounds every cost of a GasSchedule, so a mistyped update
+// cannot price every transaction out of any gas limit
+const MaxGasCost uint64 = 1_000_000_000
+
+// GasSchedule prices the work the runtime meters for every transaction.
+//
+// The schedule lives in state (see modules/gas), so governance can retune
+// prices without a software upgrade. The zero schedule charges nothing.
+type GasSchedule struct {
+	// MsgBaseCost is charged once per message before it is routed
+	MsgBaseCost uint64 `json:"msg_base_cost"`
+
+	// SigVerifyCost is charged per signature in the transaction's
+	// authorizations, before they are verified
+	SigVerifyCost uint64 `json:"sig_verify_cost"`
+
+	// ReadCostPerByte is charged per key and value byte read through
+	// Context.GasStore
+	ReadCostPerByte uint64 `json:"read_cost_per_byte"`
+
+	// WriteCostPerByte is charged per key and value byte of every state
+	// write or delete effect
+	WriteCostPerByte uint64 `json:"write_cost_per_byte"`
+}
+
+// DefaultGasSchedule returns the default gas prices
+func DefaultGasSchedule() GasSchedule {
+	return GasSchedule{
+		MsgBaseCost:      DefaultMsgBaseCost,
+		SigVerifyCost:    DefaultSigVerifyCost,
+		ReadCostPerByte:  DefaultReadCostPerByte,
+		WriteCostPerByte: DefaultWriteCostPerByte,
+	}
+}
+
+// ValidateBasic performs stateless validation
+func (s GasSchedule) ValidateBasic() error {
+	costs := []struct {
+		name string
+		cost uint64
+	}{
+		{"msg base cost", s.MsgBaseCost},
+		{"signature verification cost", s.SigVerifyCost},
+		{"read cost per byte", s.ReadCostPerByte},
+		{"write cost per byte", s.WriteCostPerByte},
+	}
+	for _, c := range costs {
+		if c.cost > MaxGasCost {
+			return fmt.Errorf("%s %d exceeds max %d", c.name, c.cost, MaxGasCost)
+		}
+	}
+	return nil
+}
+
+// GasScheduleSource provides the gas schedule in force (see modules/gas).
+//
+// INVARIANT: The schedule depends only on committed and in-block state, so
+// every node charges the same gas.
+type GasScheduleSource interface {
+	// GasSchedule returns the current gas schedule
+	GasSchedule() (GasSchedule, error)
+}
+
+// mulGas returns a × b, saturating at math.MaxUint64 so an oversized charge
+// runs out of gas instead of wrapping
+func mulGas(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	if hi != 0 {
+		return ^uint64(0)
+	}
+	return lo
+}
+
+// GasSchedule returns the context's gas schedule
+func (c *Context) GasSchedule() GasSchedule {
+	if c == nil {
+		return GasSchedule{}
+	}
+	return c.gasSchedule
+}
+
+// WithGasSchedule returns a new Context charging gas by schedule.
+// The gas meter stays shared with c.
+func (c *Context) WithGasSchedule(schedule GasSchedule) *Context {
+	if c == nil {
+		return nil
+	}
+
+	cp := *c
+	cp.gasSchedule = schedule
+	return &cp
+}
+
+// consumeScheduled charges units × cost against the gas meter
+func (c *Context) consumeScheduled(units, cost uint64, descriptor string) error {
+	if c == nil {
+		return fmt.Errorf("context is nil")
+	}
+	if c.gasMeter == nil {
+		return fmt.Errorf("gas meter is nil")
+	}
+	return c.gasMeter.ConsumeGas(mulGas(units, cost), descriptor)
+}
+
+// ConsumeReadGas charges the schedule's read cost for n bytes
+func (c *Context) ConsumeReadGas(n int) error {
+	return c.consumeScheduled(uint64(n), c.GasSchedule().ReadCostPerByte, "store read")
+}
+
+// ConsumeWriteGas charges the schedule's write cost for n bytes
+func (c *Context) ConsumeWriteGas(n int) error {
+	return c.consumeScheduled(uint64(n), c.GasSchedule().WriteCostPerByte, "store write")
+}
+
+// consumeMsgGas charges the schedule's per-message base cost
+func (c *Context) consumeMsgGas() error {
+	return c.consumeScheduled(1, c.GasSchedule().MsgBaseCost, "message")
+}
+
+// consumeSigGas charges the schedule's verification cost for every
+// signature of tx, before any of them is verified
+func (c *Context) consumeSigGas(tx *types.Transaction) error {
+	n := countSignatures(tx.Authorization) + countSignatures(tx.FeePayerAuthorization)
+	for _, cs := range tx.CoSigners {
+		n += countSignatures(cs.Authorization)
+	}
+	return c.consumeScheduled(n, c.GasSchedule().SigVerifyCost, "signature verification")
+}
+
+// consumeEffectGas charges the schedule's write cost for the key and value
+// bytes of every state write and delete in effs
+func (c *Context) consumeEffectGas(effs []effects.Effect) error {
+	var n uint64
+	for _, eff := range effs {
+		if w, ok := eff.(effects.StateWriteEffect); ok {
+			n += uint64(len(w.StoreKey) + len(w.Value))
+		}
+	}
+	return c.consumeScheduled(n, c.GasSchedule().WriteCostPerByte, "store write")
+}
+
+// countSignatures counts the signatures of auth and its nested
+// authorizations, including session grants (as types.TxLimits does)
+//
+// Complexity: O(number of authorizations); iterative, so hostile nesting
+// cannot exhaust the stack. Callers bound the nesting with TxLimits first.
+func countSignatures(auth *types.Authorization) uint64 {
+	var n uint64
+	stack := []*types.Authorization{auth}
+	for len(stack) > 0 {
+		a := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if a == nil {
+			continue
+		}
+		n += uint64(len(a.Signatures))
+		if a.Session != nil {
+			n++
+			stack = append(stack, a.Session.GrantAuthorization)
+		}
+		for _, sub := range a.AccountAuthorizations {
+			stack = append(stack, sub)
+		}
+	}
+	return n
+}
+
+// GasStore returns a view of s that charges the schedule's read cost for
+// every key and value byte read, so handlers pay for the state they touch.
+// Writes pass through uncharged; they are charged as effects.
+func (c *Context) GasStore(s store.BackingStore) store.BackingStore {
+	return &gasStore{parent: s, ctx: c}
+}
+
+// gasStore is the view returned by Context.GasStore
+type gasStore struct {
+	parent store.BackingStore
+	ctx    *Context
+}
+
+// Get retrieves raw bytes by key, charging for the key and value
+func (s *gasStore) Get(key []byte) ([]byte, error) {
+	value, err := s.parent.Get(key)
+	if gasErr := s.ctx.ConsumeReadGas(len(key) + len(value)); gasErr != nil {
+		return nil, gasErr
+	}
+	return value, err
+}
+
+// Set stores raw bytes with the given key
+func (s *gasStore) Set(key []byte, value []byte) error {
+	return s.parent.Set(key, value)
+}
+
+// Delete removes a key
+func (s *gasStore) Delete(key []byte) error {
+	return s.parent.Delete(key)
+}
+
+// Has checks if a key exists, charging for the key
+func (s *gasStore) Has(key []byte) (bool, error) {
+	if err := s.ctx.ConsumeReadGas(len(key)); err != nil {
+		return false, err
+	}
+	return s.parent.Has(key)
+}
+
+// Iterator returns an iterator over a range of keys, charging for every
+// entry it visits
+func (s *gasStore) Iterator(start, end []byte) (store.RawIterator, error) {
+	iter, err := s.parent.Iterator(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return newGasIterator(iter, s.ctx), nil
+}
+
+// ReverseIterator returns a reverse iterator over a range of keys, charging
+// for every entry it visits
+func (s *gasStore) ReverseIterator(start, end []byte) (store.RawIterator, error) {
+	iter, err := s.parent.ReverseIterator(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return newGasIterator(iter, s.ctx), nil
+}
+
+// Flush writes pending changes
+func (s *gasStore) Flush() error {
+	return s.parent.Flush()
+}
+
+// Close is a no-op; the parent store is owned by the caller
+func (s *gasStore) Close() error {
+	return nil
+}
+
+// gasIterator charges for each entry as the iterator reaches it. Running
+// out of gas invalidates the iterator and is reported by Error.
+type gasIterator struct {
+	store.RawIterator
+	ctx *Context
+	err error
+}
+
+// newGasIterator wraps iter, charging for its first entry
+func newGasIterator(iter store.RawIterator, ctx *Context) *gasIterator {
+	it := &gasIterator{RawIterator: iter, ctx: ctx}
+	it.charge()
+	return it
+}
+
+// charge charges for the current entry
+func (it *gasIterator) charge() {
+	if it.err != nil || !it.RawIterator.Valid() {
+		return
+	}
+	it.err = it.ctx.ConsumeReadGas(len(it.RawIterator.Key()) + len(it.RawIterator.Value()))
+}
+
+// Valid returns true if positioned at a valid, paid-for entry
+func (it *gasIterator) Valid() bool {
+	return it.err == nil && it.RawIterator.Valid()
+}
+
+// Next advances to the next entry and charges for it
+func (it *gasIterator) Next() {
+	if it.err != nil {
+		return
+	}
+	it.RawIterator.Next()
+	it.charge()
+}
+
+// Error returns the out-of-gas error, or any error of the parent iterator
+func (it *gasIterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.RawIterator.Error()
+}
@@ -0,0 +1,161 @@
+package runtime
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+func TestGasSchedule_ValidateBasic(t *testing.T) {
+	require.NoError(t, DefaultGasSchedule().ValidateBasic())
+	require.NoError(t, GasSchedule{}.ValidateBasic())
+	require.NoError(t, GasSchedule{MsgBaseCost: MaxGasCost}.ValidateBasic())
+
+	tests := []struct {
+		name     string
+		schedule GasSchedule
+	}{
+		{"msg base cost", GasSchedule{MsgBaseCost: MaxGasCost + 1}},
+		{"signature verification cost", GasSchedule{SigVerifyCost: MaxGasCost + 1}},
+		{"read cost per byte", GasSchedule{ReadCostPerByte: MaxGasCost + 1}},
+		{"write cost per byte", GasSchedule{WriteCostPerByte: math.MaxUint64}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schedule.ValidateBasic()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.name)
+		})
+	}
+}
+
+func TestMulGas_Saturates(t *testing.T) {
+	require.Equal(t, uint64(6), mulGas(2, 3))
+	require.Equal(t, uint64(math.MaxUint64), mulGas(math.MaxUint64, 2))
+}
+
+// newGasTestContext returns a context with the given gas limit and schedule
+func newGasTestContext(t *testing.T, limit uint64, schedule GasSchedule) *Context {
+	t.Helper()
+	ctx, err := NewContext(context.Background(), NewBlockHeader(1, time.Now(), "test-chain", nil), "alice")
+	require.NoError(t, err)
+	return ctx.WithGasMeter(NewGasMeter(limit)).WithGasSchedule(schedule)
+}
+
+func TestContext_ScheduledGas(t *testing.T) {
+	schedule := GasSchedule{MsgBaseCost: 100, SigVerifyCost: 10, ReadCostPerByte: 1, WriteCostPerByte: 2}
+	ctx := newGasTestContext(t, 10_000, schedule)
+	require.Equal(t, schedule, ctx.GasSchedule())
+
+	require.NoError(t, ctx.consumeMsgGas())
+	require.Equal(t, uint64(100), ctx.GasUsed())
+
+	require.NoError(t, ctx.ConsumeReadGas(5))
+	require.Equal(t, uint64(105), ctx.GasUsed())
+
+	require.NoError(t, ctx.ConsumeWriteGas(5))
+	require.Equal(t, uint64(115), ctx.GasUsed())
+
+	// Only state writes and deletes are charged, by key and value bytes
+	require.NoError(t, ctx.consumeEffectGas([]effects.Effect{
+		effects.NewStateWriteEffect("m", []byte("key"), []byte("value")),
+		effects.NewStateDeleteEffect("m", []byte("gone")),
+		effects.NewEventEffect("ignored", nil),
+	}))
+	require.Equal(t, uint64(115+2*(3+5+4)), ctx.GasUsed())
+
+	// Every signature is charged, including nested and co-signer ones
+	nested := types.NewAuthorization()
+	nested.Signatures = make([]types.Signature, 2)
+	auth := types.NewAuthorization()
+	auth.Signatures = make([]types.Signature, 1)
+	auth.AccountAuthorizations = map[types.AccountName]*types.Authorization{"bob": nested}
+	coSigner := types.NewAuthorization()
+	coSigner.Signatures = make([]types.Signature, 1)
+	tx := &types.Transaction{
+		Authorization: auth,
+		CoSigners:     []types.CoSigner{{Account: "carol", Authorization: coSigner}},
+	}
+	before := ctx.GasUsed()
+	require.NoError(t, ctx.consumeSigGas(tx))
+	require.Equal(t, before+4*10, ctx.GasUsed())
+
+	// The zero schedule charges nothing
+	free := newGasTestContext(t, 0, GasSchedule{})
+	require.NoError(t, free.consumeMsgGas())
+	require.NoError(t, free.ConsumeWriteGas(1<<20))
+	require.Zero(t, free.GasUsed())
+
+	// Oversized charges run out of gas instead of wrapping
+	huge := newGasTestContext(t, math.MaxUint64, GasSchedule{WriteCostPerByte: MaxGasCost})
+	require.NoError(t, huge.ConsumeWriteGas(1))
+	require.ErrorIs(t, huge.ConsumeWriteGas(math.MaxInt), ErrOutOfGas)
+}
+
+func TestContext_GasStore(t *testing.T) {
+	parent := store.NewMemoryStore()
+	require.NoError(t, parent.Set([]byte("a"), []byte("11")))
+	require.NoError(t, parent.Set([]byte("b"), []byte("222")))
+
+	t.Run("reads are charged", func(t *testing.T) {
+		ctx := newGasTestContext(t, 1_000, GasSchedule{ReadCostPerByte: 10})
+		s := ctx.GasStore(parent)
+
+		value, err := s.Get([]byte("a"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("11"), value)
+		require.Equal(t, uint64(10*(1+2)), ctx.GasUsed())
+
+		has, err := s.Has([]byte("b"))
+		require.NoError(t, err)
+		require.True(t, has)
+		require.Equal(t, uint64(30+10), ctx.GasUsed())
+
+		// Writes are charged as effects, not through the store
+		require.NoError(t, s.Set([]byte("c"), []byte("3")))
+		require.Equal(t, uint64(40), ctx.GasUsed())
+	})
+
+	t.Run("iteration is charged per entry", func(t *testing.T) {
+		ctx := newGasTestContext(t, 1_000, GasSchedule{ReadCostPerByte: 1})
+		iter, err := ctx.GasStore(parent).Iterator([]byte("a"), []byte("c"))
+		require.NoError(t, err)
+		defer iter.Close()
+
+		var keys []string
+		for ; iter.Valid(); iter.Next() {
+			keys = append(keys, string(iter.Key()))
+		}
+		require.NoError(t, iter.Error())
+		require.Equal(t, []string{"a", "b"}, keys)
+		require.Equal(t, uint64((1+2)+(1+3)), ctx.GasUsed())
+	})
+
+	t.Run("running out of gas stops iteration", func(t *testing.T) {
+		ctx := newGasTestContext(t, 5, GasSchedule{ReadCostPerByte: 1})
+		iter, err := ctx.GasStore(parent).ReverseIterator(nil, nil)
+		require.NoError(t, err)
+		defer iter.Close()
+
+		// "c" (written above) fits; "b" does not
+		visited := 0
+		for ; iter.Valid(); iter.Next() {
+			visited++
+		}
+		require.Equal(t, 1, visited)
+		require.ErrorIs(t, iter.Error(), ErrOutOfGas)
+	})
+
+	t.Run("failed reads return out of gas", func(t *testing.T) {
+		ctx := newGasTestContext(t, 2, GasSchedule{ReadCostPerByte: 1})
+		_, err := ctx.GasStore(parent).Get([]byte("b"))
+		require.ErrorIs(t, err, ErrOutOfGas)
+	})
+}
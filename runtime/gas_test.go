@@ -0,0 +1,33 @@
+package runtime
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGasMeter(t *testing.T) {
+	meter := NewGasMeter(100)
+	require.Equal(t, uint64(100), meter.Limit())
+
+	require.NoError(t, meter.ConsumeGas(60, "first"))
+	require.Equal(t, uint64(60), meter.GasConsumed())
+	require.Equal(t, uint64(40), meter.GasRemaining())
+	require.False(t, meter.IsOutOfGas())
+
+	require.NoError(t, meter.ConsumeGas(40, "exact"))
+	require.True(t, meter.IsOutOfGas())
+
+	err := meter.ConsumeGas(1, "over")
+	require.ErrorIs(t, err, ErrOutOfGas)
+	require.Contains(t, err.Error(), "over")
+	require.Equal(t, uint64(100), meter.GasConsumed())
+}
+
+func TestGasMeter_Overflow(t *testing.T) {
+	meter := NewInfiniteGasMeter()
+	require.NoError(t, meter.ConsumeGas(math.MaxUint64-1, "big"))
+	require.ErrorIs(t, meter.ConsumeGas(2, "wrap"), ErrOutOfGas)
+	require.Equal(t, uint64(math.MaxUint64), meter.GasConsumed())
+}
@@ -35,8 +35,8 @@ func (g *GenesisState) ValidateBasic() error {
 		return fmt.Errorf("genesis state is nil")
 	}
 
-	if g.ChainID == "" {
-		return fmt.Errorf("chain ID cannot be empty")
+	if err := types.ValidateChainID(g.ChainID); err != nil {
+		return err
 	}
 
 	if g.GenesisTime.IsZero() {
@@ -120,13 +120,8 @@ func (app *Application) initGenesis(ctx context.Context, validators []types.Vali
 		return fmt.Errorf("failed to create context: %w", err)
 	}
 
-	// Get all modules and sort for deterministic initialization
-	modules := app.router.Modules()
-	sortedModules := make([]Module, len(modules))
-	copy(sortedModules, modules)
-	sort.Slice(sortedModules, func(i, j int) bool {
-		return sortedModules[i].Name() < sortedModules[j].Name()
-	})
+	// Get all modules and order them for deterministic initialization
+	sortedModules := orderModules(app.router.Modules(), app.initGenesisOrder)
 
 	// Initialize each module
 	for _, mod := range sortedModules {
@@ -2,6 +2,7 @@ package runtime
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/blockberries/punnet-sdk/effects"
 	"github.com/blockberries/punnet-sdk/types"
@@ -24,6 +25,80 @@ type BeginBlocker func(ctx *Context) ([]effects.Effect, error)
 // It can return effects and validator updates
 type EndBlocker func(ctx *Context) ([]effects.Effect, []types.ValidatorUpdate, error)
 
+// AnteHandler checks a transaction before its messages are routed.
+// It runs in CheckTx with a read-only context and in ExecuteTx after the
+// transaction's authorization is verified. Its effects are applied in a
+// state branch of their own before the messages run and are kept, and the
+// nonce advances, even if a message then fails. An AnteHandler error fails
+// the transaction before anything is applied.
+type AnteHandler func(ctx *Context, tx *types.Transaction) ([]effects.Effect, error)
+
+// ChainAnteHandlers returns an AnteHandler that runs handlers in order and
+// concatenates their effects, stopping at the first error. Nil handlers are
+// skipped.
+func ChainAnteHandlers(handlers ...AnteHandler) AnteHandler {
+	chain := make([]AnteHandler, 0, len(handlers))
+	for _, h := range handlers {
+		if h != nil {
+			chain = append(chain, h)
+		}
+	}
+
+	return func(ctx *Context, tx *types.Transaction) ([]effects.Effect, error) {
+		var all []effects.Effect
+		for _, h := range chain {
+			effs, err := h(ctx, tx)
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, effs...)
+		}
+		return all, nil
+	}
+}
+
+// NewMemoAnteHandler returns an AnteHandler rejecting transactions whose memo
+// violates policy with types.ErrInvalidMemo. It produces no effects.
+func NewMemoAnteHandler(policy types.MemoPolicy) (AnteHandler, error) {
+	if err := policy.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid memo policy: %w", err)
+	}
+
+	return func(ctx *Context, tx *types.Transaction) ([]effects.Effect, error) {
+		if err := policy.Check(tx.Memo); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}, nil
+}
+
+// NewNumberPolicyAnteHandler returns an AnteHandler rejecting transactions
+// with a message whose SignDoc data contains a bare JSON number (see
+// types.ValidateNoBareNumbers) with types.ErrInvalidTransaction. Messages
+// not implementing types.SignDocSerializable are not checked. It produces
+// no effects.
+//
+// Chains install it to keep numeric message fields string-encoded, as
+// JavaScript clients and other implementations require to sign them.
+func NewNumberPolicyAnteHandler() AnteHandler {
+	return func(ctx *Context, tx *types.Transaction) ([]effects.Effect, error) {
+		for i, msg := range tx.Messages {
+			serializable, ok := msg.(types.SignDocSerializable)
+			if !ok {
+				continue
+			}
+			data, err := serializable.SignDocData()
+			if err != nil {
+				return nil, fmt.Errorf("%w: message %d SignDocData failed: %v", types.ErrInvalidTransaction, i, err)
+			}
+			if err := types.ValidateNoBareNumbers(data); err != nil {
+				return nil, fmt.Errorf("%w: message %d (%s): %v", types.ErrInvalidTransaction, i, msg.Type(), err)
+			}
+		}
+		return nil, nil
+	}
+}
+
 // InitGenesis initializes the module's state from genesis data
 type InitGenesis func(ctx *Context, data []byte) error
 
@@ -29,12 +29,8 @@ func (app *Application) callBeginBlockers(ctx context.Context, header *BlockHead
 		return nil
 	}
 
-	// Sort modules by name for deterministic execution order
-	sortedModules := make([]Module, len(modules))
-	copy(sortedModules, modules)
-	sort.Slice(sortedModules, func(i, j int) bool {
-		return sortedModules[i].Name() < sortedModules[j].Name()
-	})
+	// Order modules deterministically
+	sortedModules := orderModules(modules, app.beginBlockOrder)
 
 	// Create execution context with empty account (system context)
 	execCtx, err := NewContext(ctx, header, "system")
@@ -65,7 +61,7 @@ func (app *Application) callBeginBlockers(ctx context.Context, header *BlockHead
 
 	// Execute all collected effects
 	if len(allEffects) > 0 {
-		_, err := app.effectExecutor.Execute(allEffects)
+		_, _, err := app.effectApplier.Apply(execCtx, allEffects)
 		if err != nil {
 			return fmt.Errorf("BeginBlock effect execution failed: %w", err)
 		}
@@ -94,12 +90,8 @@ func (app *Application) callEndBlockers(ctx context.Context, header *BlockHeader
 		return &types.EndBlockResult{}, nil
 	}
 
-	// Sort modules by name for deterministic execution order
-	sortedModules := make([]Module, len(modules))
-	copy(sortedModules, modules)
-	sort.Slice(sortedModules, func(i, j int) bool {
-		return sortedModules[i].Name() < sortedModules[j].Name()
-	})
+	// Order modules deterministically
+	sortedModules := orderModules(modules, app.endBlockOrder)
 
 	// Create execution context with empty account (system context)
 	execCtx, err := NewContext(ctx, header, "system")
@@ -137,19 +129,11 @@ func (app *Application) callEndBlockers(ctx context.Context, header *BlockHeader
 
 	// Execute all collected effects
 	if len(allEffects) > 0 {
-		execResult, err := app.effectExecutor.Execute(allEffects)
+		execResult, _, err := app.effectApplier.Apply(execCtx, allEffects)
 		if err != nil {
 			return nil, fmt.Errorf("EndBlock effect execution failed: %w", err)
 		}
-
-		// Convert execution events to types.Event
-		for _, event := range execResult.Events {
-			txEvent := types.NewEvent(event.Type)
-			for key, value := range event.Attributes {
-				txEvent.AddAttribute(key, value)
-			}
-			allEvents = append(allEvents, txEvent)
-		}
+		allEvents = append(allEvents, toTxEvents(execResult.Events)...)
 	}
 
 	// Deduplicate validator updates (last update wins)
@@ -188,3 +172,78 @@ func deduplicateValidatorUpdates(updates []types.ValidatorUpdate) []types.Valida
 
 	return result
 }
+
+// orderModules returns modules in the given order, or sorted by name if
+// order is empty.
+//
+// PRECONDITION: order is empty or a permutation of the module names
+// (enforced by validateModuleOrder in NewApplication).
+func orderModules(modules []Module, order []string) []Module {
+	sorted := make([]Module, len(modules))
+	copy(sorted, modules)
+
+	if len(order) == 0 {
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].Name() < sorted[j].Name()
+		})
+		return sorted
+	}
+
+	position := make(map[string]int, len(order))
+	for i, name := range order {
+		position[name] = i
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return position[sorted[i].Name()] < position[sorted[j].Name()]
+	})
+	return sorted
+}
+
+// validateModuleOrder checks that order is empty or lists every module
+// exactly once.
+func validateModuleOrder(order []string, modules []Module) error {
+	if len(order) == 0 {
+		return nil
+	}
+
+	registered := make(map[string]bool, len(modules))
+	for _, mod := range modules {
+		if mod != nil {
+			registered[mod.Name()] = true
+		}
+	}
+
+	seen := make(map[string]bool, len(order))
+	for _, name := range order {
+		if !registered[name] {
+			return fmt.Errorf("%w: unknown module %s", ErrInvalidModuleOrder, name)
+		}
+		if seen[name] {
+			return fmt.Errorf("%w: duplicate module %s", ErrInvalidModuleOrder, name)
+		}
+		seen[name] = true
+	}
+
+	if len(seen) != len(registered) {
+		missing := make([]string, 0)
+		for name := range registered {
+			if !seen[name] {
+				missing = append(missing, name)
+			}
+		}
+		sort.Strings(missing)
+		return fmt.Errorf("%w: missing modules %v", ErrInvalidModuleOrder, missing)
+	}
+
+	return nil
+}
+
+// copyStrings returns a copy of s, or nil if s is empty
+func copyStrings(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	out := make([]string, len(s))
+	copy(out, s)
+	return out
+}
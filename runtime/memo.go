@@ -0,0 +1,83 @@
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// MemoHandler validates a structured memo of the type it is registered for,
+// and may act on it, e.g. crediting an exchange deposit tag. Returning an
+// error rejects the transaction.
+type MemoHandler func(ctx *Context, tx *types.Transaction, memo *types.StructuredMemo) ([]effects.Effect, error)
+
+// MemoRouter routes structured memos (see types.ParseStructuredMemo) to the
+// handler registered for their type. Plain-text memos pass through untouched.
+//
+// INVARIANT: Handlers are only added before AnteHandler is called; the
+// router is not safe for concurrent registration.
+type MemoRouter struct {
+	handlers      map[string]MemoHandler
+	rejectUnknown bool
+}
+
+// NewMemoRouter creates an empty memo router. If rejectUnknown is set,
+// structured memos of unregistered types are rejected; otherwise they are
+// accepted unvalidated, so clients can adopt new memo types before the chain
+// does.
+func NewMemoRouter(rejectUnknown bool) *MemoRouter {
+	return &MemoRouter{
+		handlers:      make(map[string]MemoHandler),
+		rejectUnknown: rejectUnknown,
+	}
+}
+
+// Register registers handler for memos of memoType
+func (r *MemoRouter) Register(memoType string, handler MemoHandler) error {
+	if !types.IsValidMemoType(memoType) {
+		return fmt.Errorf("invalid memo type %q", memoType)
+	}
+	if handler == nil {
+		return fmt.Errorf("memo handler for %s is nil", memoType)
+	}
+	if _, exists := r.handlers[memoType]; exists {
+		return fmt.Errorf("memo handler for %s already registered", memoType)
+	}
+	r.handlers[memoType] = handler
+	return nil
+}
+
+// HasHandler reports whether a handler is registered for memoType
+func (r *MemoRouter) HasHandler(memoType string) bool {
+	_, ok := r.handlers[memoType]
+	return ok
+}
+
+// AnteHandler returns an AnteHandler that routes each transaction's
+// structured memo to its handler and returns the handler's effects.
+// Malformed structured memos and, if the router rejects them, memos of
+// unknown types fail with types.ErrInvalidMemo.
+//
+// Chain it after NewMemoAnteHandler so handlers only see memos within the
+// chain's memo policy.
+func (r *MemoRouter) AnteHandler() AnteHandler {
+	return func(ctx *Context, tx *types.Transaction) ([]effects.Effect, error) {
+		memo, ok, err := types.ParseStructuredMemo(tx.Memo)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, nil
+		}
+
+		handler, found := r.handlers[memo.Type]
+		if !found {
+			if r.rejectUnknown {
+				return nil, fmt.Errorf("%w: unknown structured memo type %s", types.ErrInvalidMemo, memo.Type)
+			}
+			return nil, nil
+		}
+		return handler(ctx, tx, memo)
+	}
+}
@@ -0,0 +1,99 @@
+package runtime
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// AttributeKeyMsgIndex is the event attribute holding the index of the
+// message that emitted an event, so indexers can attribute events of
+// multi-message transactions
+const AttributeKeyMsgIndex = "msg_index"
+
+// MsgFailurePolicy decides what happens to a transaction when one of its
+// messages fails
+type MsgFailurePolicy uint8
+
+const (
+	// MsgFailureAtomic fails the whole transaction on the first failed
+	// message: none of its messages' effects are applied. The ante effects
+	// (e.g. the fee) are kept and the nonce advances. Messages see the state
+	// from before the transaction, after its ante effects.
+	MsgFailureAtomic MsgFailurePolicy = iota
+
+	// MsgFailureContinue applies each message's effects as soon as the
+	// message succeeds and skips failed messages, whose effects are
+	// discarded. The transaction succeeds, and its nonce advances, as long as
+	// its ante handler succeeds; failures are reported in
+	// TxResult.MsgResults. Each message sees the effects of the messages
+	// before it.
+	//
+	// INVARIANT: Each message's effects run in an effects.Branch committed
+	// only if all of them succeed, so a message whose effects fail during
+	// execution (rather than in its handler) leaves no state behind.
+	MsgFailureContinue
+)
+
+// String returns the policy name
+func (p MsgFailurePolicy) String() string {
+	switch p {
+	case MsgFailureAtomic:
+		return "atomic"
+	case MsgFailureContinue:
+		return "continue"
+	default:
+		return fmt.Sprintf("MsgFailurePolicy(%d)", uint8(p))
+	}
+}
+
+// ValidateBasic checks that p is a known policy
+func (p MsgFailurePolicy) ValidateBasic() error {
+	switch p {
+	case MsgFailureAtomic, MsgFailureContinue:
+		return nil
+	default:
+		return fmt.Errorf("unknown message failure policy %d", uint8(p))
+	}
+}
+
+// msgSpan locates the events of a successful message among its
+// transaction's execution events and handler events, as [start, end) ranges
+type msgSpan struct {
+	msg                      int
+	execStart, execEnd       int
+	handlerStart, handlerEnd int
+}
+
+// countEventEffects returns the number of events executing effs produces
+func countEventEffects(effs []effects.Effect) int {
+	n := 0
+	for _, effect := range effs {
+		if effect.Type() == effects.EffectTypeEvent {
+			n++
+		}
+	}
+	return n
+}
+
+// attachMsgEvents tags each message's events with AttributeKeyMsgIndex and
+// copies them into its result. It returns the transaction's events: the
+// execution events followed by the handler events.
+//
+// PRECONDITION: msgResults[span.msg] is the result of message span.msg.
+func attachMsgEvents(msgResults []types.MsgResult, spans []msgSpan, execEvents, handlerEvents []types.Event) []types.Event {
+	for _, span := range spans {
+		index := strconv.Itoa(span.msg)
+		events := make([]types.Event, 0, span.execEnd-span.execStart+span.handlerEnd-span.handlerStart)
+		for _, tagged := range [][]types.Event{execEvents[span.execStart:span.execEnd], handlerEvents[span.handlerStart:span.handlerEnd]} {
+			for i := range tagged {
+				tagged[i].AddAttribute(AttributeKeyMsgIndex, []byte(index))
+				events = append(events, cloneEvent(tagged[i]))
+			}
+		}
+		msgResults[span.msg].Events = events
+	}
+	return append(execEvents, handlerEvents...)
+}
@@ -0,0 +1,89 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/query"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// ErrInvalidQueryBatch is returned for a malformed QueryPathBatch request
+var ErrInvalidQueryBatch = errors.New("invalid query batch")
+
+// QueryPathBatch serves several queries in one round trip. The request data
+// is a JSON QueryBatchRequest; the response is a JSON QueryBatchResponse.
+const QueryPathBatch = "/runtime/batch"
+
+// MaxQueryBatchSize is the maximum number of requests in a query batch
+const MaxQueryBatchSize = 64
+
+// QueryBatchRequest is the request of QueryPathBatch
+type QueryBatchRequest struct {
+	// Requests are the queries to serve. A request at height 0 is served at
+	// the height of the batch: the batch request's height, or the latest
+	// height if that is 0.
+	Requests []query.Request `json:"requests"`
+}
+
+// QueryBatchResponse is the response of QueryPathBatch
+type QueryBatchResponse struct {
+	// Results holds one result per request, in request order. A failed
+	// request has a nonzero Code; it does not fail the batch.
+	Results []*types.QueryResult `json:"results"`
+}
+
+// queryBatch serves QueryPathBatch.
+//
+// INVARIANT: Requests at height 0 are pinned to the batch height when the
+// batch starts, so they read the same state even if a block commits while
+// the batch is served (queries that only serve the latest height then fail
+// with query.ErrHeightNotAvailable rather than mixing heights).
+//
+// Complexity: O(MaxQueryBatchSize) queries.
+func (app *Application) queryBatch(ctx context.Context, req query.Request) (*types.QueryResult, error) {
+	if req.Prove {
+		return queryErrorResult(ErrInvalidQueryBatch,
+			fmt.Sprintf("query failed: %v: proofs are requested per request", ErrInvalidQueryBatch)), nil
+	}
+
+	var batch QueryBatchRequest
+	if err := json.Unmarshal(req.Data, &batch); err != nil {
+		return queryErrorResult(ErrInvalidQueryBatch, fmt.Sprintf("query failed: %v: %v", ErrInvalidQueryBatch, err)), nil
+	}
+	if len(batch.Requests) > MaxQueryBatchSize {
+		return queryErrorResult(ErrInvalidQueryBatch,
+			fmt.Sprintf("query failed: %v: %d requests exceed %d", ErrInvalidQueryBatch, len(batch.Requests), MaxQueryBatchSize)), nil
+	}
+
+	height := req.Height
+	if height == 0 {
+		height = app.stateStore.Version()
+	}
+
+	resp := QueryBatchResponse{Results: make([]*types.QueryResult, len(batch.Requests))}
+	for i, r := range batch.Requests {
+		if r.Path == QueryPathBatch {
+			resp.Results[i] = queryErrorResult(ErrInvalidQueryBatch,
+				fmt.Sprintf("query failed: %v: batches cannot nest", ErrInvalidQueryBatch))
+			continue
+		}
+		if r.Height == 0 {
+			r.Height = height
+		}
+
+		result, err := app.QueryRequest(ctx, r)
+		if err != nil {
+			result = queryErrorResult(err, fmt.Sprintf("query failed: %v", err))
+		}
+		resp.Results[i] = result
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode batch response: %w", err)
+	}
+	return &types.QueryResult{Code: 0, Data: data, Height: uint64(height)}, nil
+}
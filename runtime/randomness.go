@@ -0,0 +1,102 @@
+package runtime
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"sync/atomic"
+)
+
+// randomnessDomain separates randomness provider outputs from other hashes.
+const randomnessDomain = "punnet/runtime/randomness/v1"
+
+// RandomnessProvider is a deterministic pseudo-random beacon for handlers.
+// Output i is SHA-256(domain || seed || i), where i counts the values drawn so
+// far, so every node executing the same transactions in the same order draws
+// the same values.
+//
+// SECURITY: Values are predictable by anyone who knows the seed, and block
+// proposers can influence the seed (see Context.RandomSeed). Use them where
+// every node must make the same choice (lotteries with low stakes, sampling,
+// tie-breaking), never as secrets.
+//
+// Thread-safe: All methods are safe for concurrent use, but concurrent draws
+// interleave non-deterministically; draw from one goroutine in handlers.
+type RandomnessProvider struct {
+	seed    [32]byte
+	counter atomic.Uint64
+}
+
+// NewRandomnessProvider creates a provider drawing from seed, starting at
+// counter 0
+func NewRandomnessProvider(seed [32]byte) *RandomnessProvider {
+	return &RandomnessProvider{seed: seed}
+}
+
+// Seed returns the provider's seed
+func (p *RandomnessProvider) Seed() [32]byte {
+	return p.seed
+}
+
+// Counter returns the number of values drawn so far
+func (p *RandomnessProvider) Counter() uint64 {
+	return p.counter.Load()
+}
+
+// Bytes32 draws 32 pseudo-random bytes
+func (p *RandomnessProvider) Bytes32() [32]byte {
+	i := p.counter.Add(1) - 1
+
+	h := sha256.New()
+	h.Write([]byte(randomnessDomain))
+	h.Write(p.seed[:])
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], i)
+	h.Write(buf[:])
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// Uint64 draws a pseudo-random uint64
+func (p *RandomnessProvider) Uint64() uint64 {
+	b := p.Bytes32()
+	return binary.BigEndian.Uint64(b[:8])
+}
+
+// Uint64n draws a uniform pseudo-random value in [0, n).
+//
+// Returns an error if n is zero; nothing is drawn then.
+//
+// Complexity: O(1) expected draws; values in the biased tail of the 64-bit
+// range are rejected and redrawn.
+func (p *RandomnessProvider) Uint64n(n uint64) (uint64, error) {
+	if n == 0 {
+		return 0, fmt.Errorf("range cannot be empty")
+	}
+	return p.uint64n(n), nil
+}
+
+// uint64n draws a uniform value in [0, n) for n > 0
+func (p *RandomnessProvider) uint64n(n uint64) uint64 {
+	// Lemire's multiply-and-reject method
+	hi, lo := bits.Mul64(p.Uint64(), n)
+	if lo < n {
+		threshold := -n % n
+		for lo < threshold {
+			hi, lo = bits.Mul64(p.Uint64(), n)
+		}
+	}
+	return hi
+}
+
+// Shuffle pseudo-randomly permutes n elements with a Fisher-Yates shuffle;
+// swap swaps the elements with indexes i and j. For n <= 1 nothing is drawn.
+func (p *RandomnessProvider) Shuffle(n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		j := int(p.uint64n(uint64(i + 1)))
+		swap(i, j)
+	}
+}
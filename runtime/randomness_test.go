@@ -0,0 +1,111 @@
+package runtime
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomnessProvider_Deterministic(t *testing.T) {
+	seed := [32]byte{1, 2, 3}
+	a := NewRandomnessProvider(seed)
+	b := NewRandomnessProvider(seed)
+
+	for i := 0; i < 10; i++ {
+		require.Equal(t, a.Uint64(), b.Uint64())
+	}
+	require.Equal(t, uint64(10), a.Counter())
+
+	// Every draw differs
+	first := NewRandomnessProvider(seed).Bytes32()
+	require.NotEqual(t, first, a.Bytes32())
+
+	// Different seeds give different streams
+	require.NotEqual(t, NewRandomnessProvider(seed).Uint64(), NewRandomnessProvider([32]byte{4}).Uint64())
+}
+
+func TestRandomnessProvider_Uint64n(t *testing.T) {
+	p := NewRandomnessProvider([32]byte{7})
+
+	_, err := p.Uint64n(0)
+	require.Error(t, err)
+	require.Zero(t, p.Counter())
+
+	seen := make(map[uint64]bool)
+	for i := 0; i < 200; i++ {
+		v, err := p.Uint64n(6)
+		require.NoError(t, err)
+		require.Less(t, v, uint64(6))
+		seen[v] = true
+	}
+	require.Len(t, seen, 6)
+
+	v, err := p.Uint64n(1)
+	require.NoError(t, err)
+	require.Zero(t, v)
+}
+
+func TestRandomnessProvider_Shuffle(t *testing.T) {
+	shuffle := func() []int {
+		s := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+		NewRandomnessProvider([32]byte{9}).Shuffle(len(s), func(i, j int) { s[i], s[j] = s[j], s[i] })
+		return s
+	}
+
+	got := shuffle()
+	require.Equal(t, got, shuffle())
+	require.ElementsMatch(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, got)
+	require.NotEqual(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, got)
+
+	p := NewRandomnessProvider([32]byte{9})
+	p.Shuffle(1, func(i, j int) { t.Fatal("swap called for one element") })
+	p.Shuffle(-1, func(i, j int) { t.Fatal("swap called for negative n") })
+	require.Zero(t, p.Counter())
+}
+
+func TestContext_Randomness(t *testing.T) {
+	header := NewBlockHeader(100, time.Unix(1700000000, 0), "test-chain", nil).WithHash([]byte{0xaa})
+
+	rctx, err := NewContext(context.Background(), header, "alice")
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xaa}, rctx.BlockHash())
+	txCtx := rctx.WithTxHash([]byte{1, 2, 3})
+	require.Equal(t, txCtx.RandomSeed(), txCtx.Randomness().Seed())
+
+	// Copies of the context within a transaction share the counter, so nested
+	// handlers never see the same value twice
+	nested := txCtx.withCall(nil, "bank")
+	v1 := txCtx.Randomness().Uint64()
+	v2 := nested.Randomness().Uint64()
+	require.NotEqual(t, v1, v2)
+	require.Equal(t, uint64(2), txCtx.Randomness().Counter())
+
+	// Every node derives the same sequence
+	replay, err := NewContext(context.Background(), header, "alice")
+	require.NoError(t, err)
+	replayTx := replay.WithTxHash([]byte{1, 2, 3})
+	require.Equal(t, v1, replayTx.Randomness().Uint64())
+	require.Equal(t, v2, replayTx.Randomness().Uint64())
+
+	// A new transaction starts a new sequence
+	require.Zero(t, txCtx.WithTxHash([]byte{4}).Randomness().Counter())
+
+	// The block hash is part of the seed
+	other, err := NewContext(context.Background(), header.WithHash([]byte{0xbb}), "alice")
+	require.NoError(t, err)
+	require.NotEqual(t, txCtx.RandomSeed(), other.WithTxHash([]byte{1, 2, 3}).RandomSeed())
+
+	// Headers without a hash keep the seed they had before block hashes were
+	// mixed in
+	noHash, err := NewContext(context.Background(), NewBlockHeader(100, header.Time, "test-chain", nil), "alice")
+	require.NoError(t, err)
+	seed := noHash.RandomSeed()
+	require.Equal(t, "e9ffa367b5b8a7258a7810b3df96af674e420437044a386ecbd89b30e1081d70", hex.EncodeToString(seed[:]))
+	require.NotEqual(t, rctx.RandomSeed(), seed)
+
+	var nilCtx *Context
+	require.NotNil(t, nilCtx.Randomness())
+}
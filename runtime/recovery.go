@@ -0,0 +1,86 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// ErrHandlerPanic is returned when a message handler panics
+var ErrHandlerPanic = errors.New("message handler panicked")
+
+// PanicInfo describes a recovered message handler panic
+type PanicInfo struct {
+	// Module is the module whose handler panicked
+	Module string
+
+	// MsgType is the type of the message being handled
+	MsgType string
+
+	// Height is the height of the block (or CheckTx state) the handler ran in
+	Height uint64
+
+	// Value is the value passed to panic
+	Value any
+
+	// Stack is the goroutine stack at the panic
+	Stack []byte
+}
+
+// PanicLogger reports recovered handler panics off-consensus, e.g. to the
+// node's log. It must not affect state or results.
+type PanicLogger func(info PanicInfo)
+
+// logPanic is the default PanicLogger, writing to the standard logger
+func logPanic(info PanicInfo) {
+	log.Printf("recovered panic in %s handler for %s at height %d: %v\n%s",
+		info.Module, info.MsgType, info.Height, info.Value, info.Stack)
+}
+
+// SetPanicLogger sets the logger of recovered handler panics; nil restores
+// the default, which writes to the standard logger
+func (r *Router) SetPanicLogger(logger PanicLogger) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.panicLogger = logger
+}
+
+// callHandler runs handler, converting a panic into an error.
+//
+// SECURITY: A buggy handler must not crash the node. The error does not
+// include the panic value or stack, whose formatting may differ between
+// nodes (e.g. pointers), so every node records the same result; the details
+// go to the PanicLogger only. An out-of-gas error passed to panic is
+// returned as is, so it keeps its error code.
+func (r *Router) callHandler(ctx *Context, handler MsgHandler, module string, msg types.Message) (effs []effects.Effect, err error) {
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+
+		r.mu.RLock()
+		logger := r.panicLogger
+		r.mu.RUnlock()
+		if logger == nil {
+			logger = logPanic
+		}
+		logger(PanicInfo{Module: module, MsgType: msg.Type(), Height: ctx.BlockHeight(), Value: v, Stack: debug.Stack()})
+
+		if panicErr, ok := v.(error); ok && errors.Is(panicErr, ErrOutOfGas) {
+			effs, err = nil, panicErr
+			return
+		}
+		effs, err = nil, fmt.Errorf("%w: module %s, message %s", ErrHandlerPanic, module, msg.Type())
+	}()
+
+	return handler(ctx, msg)
+}
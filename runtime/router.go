@@ -54,17 +54,27 @@ type Router struct {
 	// msgHandlers maps message type to handler
 	msgHandlers map[string]MsgHandler
 
+	// msgModules maps message type to the name of the module handling it
+	msgModules map[string]string
+
 	// queryHandlers maps query path to handler
 	queryHandlers map[string]QueryHandler
 
 	// modules stores registered modules for lifecycle management
 	modules []Module
+
+	// breaker disables message types (may be nil)
+	breaker CircuitBreaker
+
+	// panicLogger reports recovered handler panics (nil means logPanic)
+	panicLogger PanicLogger
 }
 
 // NewRouter creates a new router
 func NewRouter() *Router {
 	return &Router{
 		msgHandlers:   make(map[string]MsgHandler),
+		msgModules:    make(map[string]string),
 		queryHandlers: make(map[string]QueryHandler),
 		modules:       make([]Module, 0),
 	}
@@ -99,6 +109,7 @@ func (r *Router) RegisterModule(m Module) error {
 		}
 
 		r.msgHandlers[msgType] = handler
+		r.msgModules[msgType] = m.Name()
 	}
 
 	// Register query handlers
@@ -125,7 +136,9 @@ func (r *Router) RegisterModule(m Module) error {
 	return nil
 }
 
-// RouteMsg routes a message to its handler and returns the effects
+// RouteMsg routes a message to its handler and returns the effects.
+// Messages whose type the circuit breaker disables are rejected with
+// ErrCircuitBreakerTripped; a handler panic is returned as ErrHandlerPanic.
 func (r *Router) RouteMsg(ctx *Context, msg types.Message) ([]effects.Effect, error) {
 	if r == nil {
 		return nil, ErrRouterNil
@@ -146,14 +159,20 @@ func (r *Router) RouteMsg(ctx *Context, msg types.Message) ([]effects.Effect, er
 
 	r.mu.RLock()
 	handler, exists := r.msgHandlers[msgType]
+	module := r.msgModules[msgType]
+	breaker := r.breaker
 	r.mu.RUnlock()
 
 	if !exists {
 		return nil, fmt.Errorf("%w: %s", ErrHandlerNotFound, msgType)
 	}
 
-	// Call the handler
-	return handler(ctx, msg)
+	if err := checkCircuit(ctx, breaker, msgType); err != nil {
+		return nil, err
+	}
+
+	// Call the handler with the module on the call stack
+	return r.callHandler(ctx.withCall(r, module), handler, module, msg)
 }
 
 // RouteQuery routes a query to its handler and returns the result
@@ -195,6 +214,19 @@ func (r *Router) HasMsgHandler(msgType string) bool {
 	return exists
 }
 
+// MsgHandlerModule returns the name of the module handling msgType
+func (r *Router) MsgHandlerModule(msgType string) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	name, exists := r.msgModules[msgType]
+	return name, exists
+}
+
 // HasQueryHandler checks if a handler exists for a query path
 func (r *Router) HasQueryHandler(path string) bool {
 	if r == nil {
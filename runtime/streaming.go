@@ -0,0 +1,175 @@
+package runtime
+
+import (
+	"log"
+	"sync"
+
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/streaming"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// stateStreamer collects the changes the state store commits, attributes
+// them to the block's transactions and hands each committed block to the
+// state sinks (see ApplicationConfig.StateSinks).
+//
+// A change is attributed to the transaction that was executing when it
+// reached the state store. The account and balance stores of the
+// application buffer their writes until Commit, so their keys are
+// attributed to the last transaction that wrote them instead.
+//
+// INVARIANT: Positions count the state store's writes since the last
+// streamed block: the committed changes, then the pending ones.
+type stateStreamer struct {
+	mu         sync.Mutex
+	sinks      []streaming.Sink
+	stateStore *store.IAVLStore
+
+	// committed holds the changes of the versions saved since the last
+	// streamed block
+	committed []store.KVChange
+
+	// txs and spans are the block's transactions and the positions of the
+	// writes made while each executed
+	txs   []streaming.Tx
+	spans []txSpan
+
+	// cached maps the keys written through the application's caches to
+	// the index of the last transaction writing them
+	cached map[string]int
+
+	// current is the index of the executing transaction, or streaming.NoTx
+	current   int
+	spanStart int
+}
+
+// txSpan is the range [start, end) of positions written by a transaction
+type txSpan struct {
+	start, end int
+}
+
+// newStateStreamer creates a streamer of the changes stateStore commits
+func newStateStreamer(stateStore *store.IAVLStore, sinks []streaming.Sink) *stateStreamer {
+	s := &stateStreamer{
+		sinks:      append([]streaming.Sink(nil), sinks...),
+		stateStore: stateStore,
+		cached:     make(map[string]int),
+		current:    streaming.NoTx,
+	}
+	stateStore.AddCommitHook(s.onCommit)
+	return s
+}
+
+// onCommit is the state store's commit hook
+func (s *stateStreamer) onCommit(version int64, changes []store.KVChange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.committed = append(s.committed, changes...)
+}
+
+// position returns the position of the next write
+func (s *stateStreamer) position() int {
+	// Read outside s.mu: the commit hook takes s.mu under the store's lock
+	pending := s.stateStore.PendingChanges()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.committed) + pending
+}
+
+// beginTx marks the start of the block's next transaction
+func (s *stateStreamer) beginTx() {
+	if s == nil {
+		return
+	}
+	start := s.position()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current = len(s.txs)
+	s.spanStart = start
+}
+
+// endTx records the transaction txBytes with its result. Without a result
+// (the block cannot be committed) its writes are left unattributed.
+func (s *stateStreamer) endTx(txBytes []byte, result *types.TxResult) {
+	if s == nil {
+		return
+	}
+	end := s.position()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if result != nil && s.current != streaming.NoTx {
+		s.txs = append(s.txs, streaming.Tx{Hash: types.TxHash(txBytes), Codespace: result.Codespace, Code: result.Code})
+		s.spans = append(s.spans, txSpan{start: s.spanStart, end: end})
+	}
+	s.current = streaming.NoTx
+}
+
+// touch records a write of key through the application's caches
+func (s *stateStreamer) touch(key []byte) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.current != streaming.NoTx {
+		s.cached[string(key)] = s.current
+	}
+}
+
+// commit streams the block committed at header with appHash to the sinks
+// and starts the next block. Sink errors are logged.
+//
+// Complexity: O(changes + txs)
+func (s *stateStreamer) commit(header *BlockHeader, height uint64, appHash []byte) {
+	if s == nil {
+		return
+	}
+	block := s.takeBlock(header, height, appHash)
+
+	for _, sink := range s.sinks {
+		if err := sink.WriteBlock(block); err != nil {
+			log.Printf("failed to stream state changes of block %d: %v", height, err)
+		}
+	}
+}
+
+// takeBlock builds the block of the collected changes and resets the
+// streamer for the next block
+func (s *stateStreamer) takeBlock(header *BlockHeader, height uint64, appHash []byte) *streaming.Block {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	block := &streaming.Block{
+		Height:  height,
+		Time:    header.Time,
+		AppHash: appHash,
+		Txs:     s.txs,
+		Changes: make([]streaming.Change, len(s.committed)),
+	}
+	if block.Txs == nil {
+		block.Txs = make([]streaming.Tx, 0)
+	}
+
+	span := 0
+	for pos, change := range s.committed {
+		for span < len(s.spans) && s.spans[span].end <= pos {
+			span++
+		}
+		txIndex := streaming.NoTx
+		if span < len(s.spans) && s.spans[span].start <= pos {
+			txIndex = span
+		} else if i, ok := s.cached[string(change.Key)]; ok {
+			txIndex = i
+		}
+		block.Changes[pos] = streaming.Change{TxIndex: txIndex, Key: change.Key, Value: change.Value, Delete: change.Delete}
+	}
+
+	s.committed = nil
+	s.txs = nil
+	s.spans = nil
+	s.cached = make(map[string]int)
+	return block
+}
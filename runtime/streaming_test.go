@@ -0,0 +1,159 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"testing"
+	"time"
+
+	dbm "github.com/cosmos/cosmos-db"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/streaming"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// sinkFunc adapts a function to streaming.Sink
+type sinkFunc func(block *streaming.Block) error
+
+func (f sinkFunc) WriteBlock(block *streaming.Block) error { return f(block) }
+
+func TestApplication_StateSinks(t *testing.T) {
+	priv := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	pubKey := priv.Public().(ed25519.PublicKey)
+	ctx := context.Background()
+
+	iavlStore, err := store.NewIAVLStore(dbm.NewMemDB(), 0)
+	if err != nil {
+		t.Fatalf("failed to create IAVL store: %v", err)
+	}
+
+	var blocks []*streaming.Block
+	written := effects.NewStateWriteEffect("stream", []byte("k"), []byte("v"))
+	app, err := NewApplication(ApplicationConfig{
+		ChainID:    "test-chain",
+		StateStore: iavlStore,
+		Modules: []Module{&mockModule{
+			name: "stream",
+			msgHandlers: map[string]MsgHandler{
+				"stream.write": func(ctx *Context, msg types.Message) ([]effects.Effect, error) {
+					return []effects.Effect{
+						written,
+						effects.TransferEffect{From: "alice", To: "bob", Amount: types.NewCoins(types.NewCoin("token", 2))},
+					}, nil
+				},
+			},
+		}},
+		StateSinks: []streaming.Sink{
+			sinkFunc(func(block *streaming.Block) error {
+				blocks = append(blocks, block)
+				return nil
+			}),
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create application: %v", err)
+	}
+
+	header := NewBlockHeader(1, time.Unix(100, 0), "test-chain", nil)
+	if err := app.BeginBlock(ctx, header); err != nil {
+		t.Fatalf("BeginBlock failed: %v", err)
+	}
+	if err := app.accountStore.Set(ctx, []byte("alice"), types.NewAccount("alice", pubKey)); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	if err := app.balanceStore.Set(ctx, store.NewBalance("alice", "token", 5)); err != nil {
+		t.Fatalf("failed to fund account: %v", err)
+	}
+	if err := app.stateStore.Set([]byte("outside"), []byte("1")); err != nil {
+		t.Fatalf("failed to write state: %v", err)
+	}
+
+	// Transaction 0 cannot be decoded
+	garbage := []byte("not a transaction")
+	result, err := app.ExecuteTx(ctx, garbage)
+	if err != nil {
+		t.Fatalf("ExecuteTx failed: %v", err)
+	}
+	if result.IsOK() {
+		t.Fatal("expected undecodable transaction to fail")
+	}
+
+	// Transaction 1 writes state, transfers and increments alice's nonce.
+	// Test messages do not survive encoding, so this runs the steps of
+	// ExecuteTx.
+	tx := types.NewTransaction("alice", 0, []types.Message{&testMessage{msgType: "stream.write", signers: []types.AccountName{"alice"}}},
+		types.NewAuthorization())
+	tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+	signDoc, err := tx.ToSignDoc("test-chain", 0)
+	if err != nil {
+		t.Fatalf("failed to build sign doc: %v", err)
+	}
+	signBytes, err := signDoc.GetSignBytes()
+	if err != nil {
+		t.Fatalf("failed to get sign bytes: %v", err)
+	}
+	tx.Authorization.Signatures = []types.Signature{{Algorithm: types.AlgorithmEd25519, PubKey: pubKey, Signature: ed25519.Sign(priv, signBytes)}}
+	txBytes := []byte("tx-1")
+	app.streamer.beginTx()
+	result, err = execTx(t, app, ctx, tx)
+	app.streamer.endTx(txBytes, result)
+	if err != nil || !result.IsOK() {
+		t.Fatalf("executeTx failed: %v %+v", err, result)
+	}
+
+	if _, err := app.EndBlock(ctx); err != nil {
+		t.Fatalf("EndBlock failed: %v", err)
+	}
+	commit, err := app.Commit(ctx)
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	if len(blocks) != 1 {
+		t.Fatalf("expected 1 streamed block, got %d", len(blocks))
+	}
+	block := blocks[0]
+	if block.Height != commit.Height || !block.Time.Equal(header.Time) || !bytes.Equal(block.AppHash, commit.AppHash) {
+		t.Fatalf("unexpected block context: height %d time %v app hash %x", block.Height, block.Time, block.AppHash)
+	}
+	if len(block.Txs) != 2 || !bytes.Equal(block.Txs[0].Hash, types.TxHash(garbage)) || block.Txs[0].Code == 0 ||
+		!bytes.Equal(block.Txs[1].Hash, types.TxHash(txBytes)) || block.Txs[1].Code != 0 {
+		t.Fatalf("unexpected txs: %+v", block.Txs)
+	}
+
+	wantTx := map[string]int{
+		"outside":             streaming.NoTx,
+		string(written.Key()): 1,
+		"alice":               1,
+		string(store.BalanceKey("alice", "token")): 1,
+		string(store.BalanceKey("bob", "token")):   1,
+	}
+	got := make(map[string]int)
+	for _, change := range block.Changes {
+		got[string(change.Key)] = change.TxIndex
+	}
+	for key, want := range wantTx {
+		txIndex, ok := got[key]
+		if !ok {
+			t.Fatalf("change of %q not streamed; got %v", key, got)
+		}
+		if txIndex != want {
+			t.Fatalf("change of %q attributed to tx %d, want %d", key, txIndex, want)
+		}
+	}
+
+	// The next block starts afresh
+	if err := app.BeginBlock(ctx, NewBlockHeader(2, time.Unix(101, 0), "test-chain", nil)); err != nil {
+		t.Fatalf("BeginBlock failed: %v", err)
+	}
+	commit, err = app.Commit(ctx)
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if len(blocks) != 2 || blocks[1].Height != commit.Height || len(blocks[1].Txs) != 0 || len(blocks[1].Changes) != 0 {
+		t.Fatalf("unexpected second block: %+v", blocks[len(blocks)-1])
+	}
+}
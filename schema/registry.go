@@ -0,0 +1,292 @@
+package schema
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Provider is implemented by messages whose SignDocData does not follow the
+// generated encoding (see cmd/punnet-gen) and that describe their data
+// schema by hand
+type Provider interface {
+	// SignDocSchema returns the schema of the message's SignDocData. Its
+	// $refs may use the shared definitions (DefUint, DefCoin, ...).
+	SignDocSchema() *Schema
+}
+
+// Registry maps message type URLs to message prototypes and derives their
+// schemas.
+//
+// A message's data schema is, in order of precedence:
+//   - its SignDocSchema, if it implements Provider
+//   - the schema of the generated SignDocData encoding of its struct fields,
+//     if it implements types.SignDocSerializable
+//   - the schema of the signers-only fallback, {"signers": [...]}, otherwise
+//
+// Thread-safe: All methods are safe for concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	messages map[string]*Schema
+}
+
+// NewRegistry creates a registry with no message types
+func NewRegistry() *Registry {
+	return &Registry{messages: make(map[string]*Schema)}
+}
+
+// Register registers message prototypes under their Type(). Register the
+// same messages as the chain's types.TxDecoder so that the SignDoc schema
+// accepts exactly the decodable message types.
+//
+// Returns ErrDuplicateMessage if a type is already registered and
+// ErrUnsupportedType if a data schema cannot be derived; no message is
+// registered on error.
+func (r *Registry) Register(msgs ...types.Message) error {
+	if r == nil {
+		return fmt.Errorf("registry is nil")
+	}
+
+	derived := make(map[string]*Schema, len(msgs))
+	for _, msg := range msgs {
+		if msg == nil {
+			return fmt.Errorf("message cannot be nil")
+		}
+		msgType := msg.Type()
+		if msgType == "" {
+			return fmt.Errorf("message %T has an empty type", msg)
+		}
+		if _, ok := commonDefs()[messageDefName(msgType)]; ok {
+			return fmt.Errorf("message type %q collides with a shared definition", msgType)
+		}
+		if _, ok := derived[msgType]; ok {
+			return fmt.Errorf("%w: %s", ErrDuplicateMessage, msgType)
+		}
+
+		s, err := dataSchema(msg)
+		if err != nil {
+			return fmt.Errorf("message %s: %w", msgType, err)
+		}
+		s.Title = msgType
+		derived[msgType] = s
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for msgType := range derived {
+		if _, exists := r.messages[msgType]; exists {
+			return fmt.Errorf("%w: %s", ErrDuplicateMessage, msgType)
+		}
+	}
+	for msgType, s := range derived {
+		r.messages[msgType] = s
+	}
+	return nil
+}
+
+// MessageTypes returns the registered type URLs in sorted order
+func (r *Registry) MessageTypes() []string {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	msgTypes := make([]string, 0, len(r.messages))
+	for msgType := range r.messages {
+		msgTypes = append(msgTypes, msgType)
+	}
+	sort.Strings(msgTypes)
+	return msgTypes
+}
+
+// MessageSchema returns the standalone document of a message type's SignDoc
+// data
+func (r *Registry) MessageSchema(msgType string) (*Schema, error) {
+	if r == nil {
+		return nil, fmt.Errorf("registry is nil")
+	}
+
+	r.mu.RLock()
+	s, ok := r.messages[msgType]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMessageNotFound, msgType)
+	}
+
+	doc := *s
+	doc.Schema = Draft
+	doc.Defs = commonDefs()
+	return &doc, nil
+}
+
+// SignDocSchema returns the SignDoc document. Its messages accept exactly the
+// registered types, each with its data schema; with no registered types,
+// any message type with object data is accepted.
+func (r *Registry) SignDocSchema() *Schema {
+	if r == nil {
+		return signDocSchema(nil, nil)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	msgTypes := make([]string, 0, len(r.messages))
+	data := make(map[string]*Schema, len(r.messages))
+	for msgType, s := range r.messages {
+		msgTypes = append(msgTypes, msgType)
+		data[msgType] = s
+	}
+	sort.Strings(msgTypes)
+	return signDocSchema(msgTypes, data)
+}
+
+// SignDocFile is the file name of the SignDoc document written by Export
+const SignDocFile = "signdoc.schema.json"
+
+// Export writes the SignDoc document to dir/SignDocFile and each message
+// document to dir/messages/<type>.schema.json, where <type> is the type URL
+// without the leading slash, and returns the written paths.
+func (r *Registry) Export(dir string) ([]string, error) {
+	if r == nil {
+		return nil, fmt.Errorf("registry is nil")
+	}
+
+	docs := map[string]*Schema{SignDocFile: r.SignDocSchema()}
+	for _, msgType := range r.MessageTypes() {
+		name := messageDefName(msgType)
+		if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+			return nil, fmt.Errorf("message type %q is not a valid file name", msgType)
+		}
+		doc, err := r.MessageSchema(msgType)
+		if err != nil {
+			return nil, err
+		}
+		docs[filepath.Join("messages", name+".schema.json")] = doc
+	}
+
+	paths := make([]string, 0, len(docs))
+	for name := range docs {
+		paths = append(paths, name)
+	}
+	sort.Strings(paths)
+
+	for i, name := range paths {
+		data, err := Marshal(docs[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %w", name, err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create directory for %s: %w", name, err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		paths[i] = path
+	}
+	return paths, nil
+}
+
+// Reflected types of the SDK types with dedicated schemas
+var (
+	accountNameType = reflect.TypeOf(types.AccountName(""))
+	coinType        = reflect.TypeOf(types.Coin{})
+)
+
+// dataSchema derives the data schema of a message prototype
+func dataSchema(msg types.Message) (*Schema, error) {
+	if p, ok := msg.(Provider); ok {
+		s := p.SignDocSchema()
+		if s == nil {
+			return nil, fmt.Errorf("SignDocSchema returned nil")
+		}
+		return s, nil
+	}
+
+	if _, ok := msg.(types.SignDocSerializable); !ok {
+		return withDescription(closedObject(map[string]*Schema{
+			"signers": {
+				OneOf: []*Schema{
+					{Type: "array", Items: ref(DefAccountName)},
+					{Type: "null"},
+				},
+			},
+		}), "Signers-only fallback data of a message without SignDocData"), nil
+	}
+
+	t := reflect.TypeOf(msg)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: message %s is not a struct", ErrUnsupportedType, t)
+	}
+
+	properties := make(map[string]*Schema)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			return nil, fmt.Errorf("%w: embedded field %s", ErrUnsupportedType, field.Name)
+		}
+		if !field.IsExported() || field.Tag.Get("punnet") == "-" {
+			continue
+		}
+
+		key := field.Name
+		if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name == "-" {
+			continue
+		} else if name != "" {
+			key = name
+		}
+
+		s, err := fieldSchema(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		properties[key] = s
+	}
+	return closedObject(properties), nil
+}
+
+// fieldSchema returns the schema of a field's generated SignDocData encoding
+func fieldSchema(t reflect.Type) (*Schema, error) {
+	switch {
+	case t == accountNameType:
+		return ref(DefAccountName), nil
+	case t == coinType:
+		return ref(DefCoin), nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string", Description: "NFC-normalized"}, nil
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return ref(DefInt), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return ref(DefUint), nil
+	case reflect.Slice:
+		elem := t.Elem()
+		if elem.Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", ContentEncoding: "base64"}, nil
+		}
+		if elem == accountNameType || elem == coinType || elem.Kind() == reflect.String {
+			items, err := fieldSchema(elem)
+			if err != nil {
+				return nil, err
+			}
+			return &Schema{Type: "array", Items: items}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+}
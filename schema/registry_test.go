@@ -0,0 +1,384 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// msgTransfer follows the punnet-gen encoding
+type msgTransfer struct {
+	From   types.AccountName `json:"from"`
+	Amount types.Coins       `json:"amount"`
+	Memo   string            `json:"memo"`
+	Count  int32             `json:"count"`
+	Data   []byte            `json:"data"`
+	Tags   []string          `json:"tags"`
+	Public bool              `json:"public"`
+	Cached string            `json:"cached" punnet:"-"`
+}
+
+func (m *msgTransfer) Type() string                    { return "/punnet.test.v1.MsgTransfer" }
+func (m *msgTransfer) ValidateBasic() error            { return nil }
+func (m *msgTransfer) GetSigners() []types.AccountName { return []types.AccountName{m.From} }
+
+func (m *msgTransfer) SignDocData() (json.RawMessage, error) {
+	return json.Marshal(struct {
+		Amount []types.SignDocCoin `json:"amount"`
+		Count  string              `json:"count"`
+		Data   string              `json:"data"`
+		From   string              `json:"from"`
+		Memo   string              `json:"memo"`
+		Public bool                `json:"public"`
+		Tags   []string            `json:"tags"`
+	}{
+		Amount: types.NewSignDocCoins(m.Amount),
+		Count:  strconv.FormatInt(int64(m.Count), 10),
+		Data:   base64.StdEncoding.EncodeToString(m.Data),
+		From:   types.SignDocString(m.From),
+		Memo:   types.SignDocString(m.Memo),
+		Public: m.Public,
+		Tags:   types.SignDocStrings(m.Tags),
+	})
+}
+
+// msgLegacy has no SignDocData and signs with the signers-only fallback
+type msgLegacy struct {
+	Owner types.AccountName `json:"owner"`
+}
+
+func (m *msgLegacy) Type() string                    { return "/punnet.test.v1.MsgLegacy" }
+func (m *msgLegacy) ValidateBasic() error            { return nil }
+func (m *msgLegacy) GetSigners() []types.AccountName { return []types.AccountName{m.Owner} }
+
+// msgCustom describes its own data schema
+type msgCustom struct {
+	msgLegacy
+}
+
+func (m *msgCustom) Type() string { return "/punnet.test.v1.MsgCustom" }
+
+func (m *msgCustom) SignDocSchema() *Schema {
+	return closedObject(map[string]*Schema{"weights": {Type: "object"}})
+}
+
+// msgMap has a field without a schema mapping
+type msgMap struct {
+	Weights map[string]uint64 `json:"weights"`
+}
+
+func (m *msgMap) Type() string                          { return "/punnet.test.v1.MsgMap" }
+func (m *msgMap) ValidateBasic() error                  { return nil }
+func (m *msgMap) GetSigners() []types.AccountName       { return nil }
+func (m *msgMap) SignDocData() (json.RawMessage, error) { return json.Marshal(m.Weights) }
+
+func newTestRegistry(t *testing.T) *Registry {
+	t.Helper()
+
+	r := NewRegistry()
+	if err := r.Register(&msgTransfer{}, &msgLegacy{}, &msgCustom{}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	return r
+}
+
+// signDocValue returns the decoded JSON of a SignDoc with the test messages
+func signDocValue(t *testing.T) map[string]any {
+	t.Helper()
+
+	tx := types.NewTransaction("alice", 7, []types.Message{
+		&msgTransfer{
+			From:   "alice",
+			Amount: types.Coins{{Denom: "stake", Amount: 100}},
+			Memo:   "rent",
+			Count:  -3,
+			Data:   []byte{1, 2, 3},
+			Tags:   []string{"a"},
+		},
+		&msgLegacy{Owner: "bob"},
+	}, nil)
+	sd, err := tx.ToSignDoc("test-chain", 1)
+	if err != nil {
+		t.Fatalf("ToSignDoc failed: %v", err)
+	}
+	sd.NotBefore = types.HeightBound(5)
+	sd.FeePayer = "bob"
+	sd.Signers = []string{"alice", "carol"}
+	data, err := sd.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+
+	var v map[string]any
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("failed to decode SignDoc JSON: %v", err)
+	}
+	return v
+}
+
+func TestRegistry_SignDocSchemaAcceptsSignDoc(t *testing.T) {
+	doc := newTestRegistry(t).SignDocSchema()
+	if err := validate(doc, doc, signDocValue(t)); err != nil {
+		t.Fatalf("SignDoc does not validate: %v", err)
+	}
+
+	// Without registered types any object data is accepted
+	generic := NewRegistry().SignDocSchema()
+	if err := validate(generic, generic, signDocValue(t)); err != nil {
+		t.Fatalf("SignDoc does not validate against the generic schema: %v", err)
+	}
+}
+
+func TestRegistry_SignDocSchemaRejects(t *testing.T) {
+	doc := newTestRegistry(t).SignDocSchema()
+
+	tests := []struct {
+		name   string
+		mutate func(v map[string]any)
+	}{
+		{"numeric nonce", func(v map[string]any) { v["nonce"] = 7.0 }},
+		{"leading zero", func(v map[string]any) { v["account_sequence"] = "01" }},
+		{"unknown property", func(v map[string]any) { v["extra"] = "x" }},
+		{"missing memo", func(v map[string]any) { delete(v, "memo") }},
+		{"invalid fee payer", func(v map[string]any) { v["fee_payer"] = "Bob" }},
+		{"unsupported version", func(v map[string]any) { v["version"] = "3" }},
+		{"single signer", func(v map[string]any) { v["signers"] = []any{"alice"} }},
+		{"invalid tip", func(v map[string]any) { v["tip"] = map[string]any{"denom": "stake", "amount": "-1"} }},
+		{"no messages", func(v map[string]any) { v["messages"] = []any{} }},
+		{"unregistered type", func(v map[string]any) {
+			v["messages"].([]any)[0].(map[string]any)["type"] = "/punnet.test.v1.MsgOther"
+		}},
+		{"numeric data field", func(v map[string]any) {
+			v["messages"].([]any)[0].(map[string]any)["data"].(map[string]any)["count"] = -3.0
+		}},
+		{"invalid signer", func(v map[string]any) {
+			v["messages"].([]any)[1].(map[string]any)["data"].(map[string]any)["signers"] = []any{"Bob"}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := signDocValue(t)
+			tt.mutate(v)
+			if err := validate(doc, doc, v); err == nil {
+				t.Fatal("expected validation error")
+			}
+		})
+	}
+}
+
+func TestRegistry_MessageSchema(t *testing.T) {
+	r := newTestRegistry(t)
+
+	if got, want := strings.Join(r.MessageTypes(), ","), "/punnet.test.v1.MsgCustom,/punnet.test.v1.MsgLegacy,/punnet.test.v1.MsgTransfer"; got != want {
+		t.Fatalf("MessageTypes = %s, want %s", got, want)
+	}
+
+	doc, err := r.MessageSchema("/punnet.test.v1.MsgTransfer")
+	if err != nil {
+		t.Fatalf("MessageSchema failed: %v", err)
+	}
+	if doc.Schema != Draft || doc.Title != "/punnet.test.v1.MsgTransfer" {
+		t.Errorf("unexpected header: %q %q", doc.Schema, doc.Title)
+	}
+	if _, ok := doc.Properties["cached"]; ok {
+		t.Error("excluded field has a schema")
+	}
+	if got := strings.Join(doc.Required, ","); got != "amount,count,data,from,memo,public,tags" {
+		t.Errorf("required = %s", got)
+	}
+
+	data, err := (&msgTransfer{From: "alice"}).SignDocData()
+	if err != nil {
+		t.Fatalf("SignDocData failed: %v", err)
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("failed to decode data: %v", err)
+	}
+	if err := validate(doc, doc, v); err != nil {
+		t.Fatalf("data does not validate: %v", err)
+	}
+
+	custom, err := r.MessageSchema("/punnet.test.v1.MsgCustom")
+	if err != nil {
+		t.Fatalf("MessageSchema failed: %v", err)
+	}
+	if _, ok := custom.Properties["weights"]; !ok {
+		t.Error("Provider schema not used")
+	}
+
+	if _, err := r.MessageSchema("/punnet.test.v1.MsgOther"); !errors.Is(err, ErrMessageNotFound) {
+		t.Errorf("expected ErrMessageNotFound, got %v", err)
+	}
+}
+
+func TestRegistry_RegisterErrors(t *testing.T) {
+	r := newTestRegistry(t)
+
+	if err := r.Register(&msgTransfer{}); !errors.Is(err, ErrDuplicateMessage) {
+		t.Errorf("expected ErrDuplicateMessage, got %v", err)
+	}
+	if err := r.Register(&msgMap{}); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType, got %v", err)
+	}
+	if err := NewRegistry().Register(&msgLegacy{}, &msgLegacy{}); !errors.Is(err, ErrDuplicateMessage) {
+		t.Errorf("expected ErrDuplicateMessage, got %v", err)
+	}
+	if err := r.Register(nil); err == nil {
+		t.Error("expected error for nil message")
+	}
+	if len(r.MessageTypes()) != 3 {
+		t.Errorf("failed registrations changed the registry: %v", r.MessageTypes())
+	}
+}
+
+func TestRegistry_Export(t *testing.T) {
+	r := newTestRegistry(t)
+	dir := t.TempDir()
+
+	paths, err := r.Export(dir)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if len(paths) != 4 {
+		t.Fatalf("expected 4 files, got %v", paths)
+	}
+
+	first, err := os.ReadFile(filepath.Join(dir, SignDocFile))
+	if err != nil {
+		t.Fatalf("failed to read SignDoc schema: %v", err)
+	}
+	var doc Schema
+	if err := json.Unmarshal(first, &doc); err != nil {
+		t.Fatalf("exported SignDoc schema is not valid JSON: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "messages", "punnet.test.v1.MsgTransfer.schema.json")); err != nil {
+		t.Fatalf("message schema not written: %v", err)
+	}
+
+	// Exports are deterministic
+	if _, err := r.Export(dir); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	second, err := os.ReadFile(filepath.Join(dir, SignDocFile))
+	if err != nil {
+		t.Fatalf("failed to read SignDoc schema: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Fatal("exports differ")
+	}
+}
+
+// validate checks v against s, resolving $refs in root. It implements the
+// keywords Schema supports.
+func validate(root, s *Schema, v any) error {
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/$defs/")
+		name = strings.ReplaceAll(strings.ReplaceAll(name, "~1", "/"), "~0", "~")
+		def, ok := root.Defs[name]
+		if !ok {
+			return fmt.Errorf("unresolved $ref %s", s.Ref)
+		}
+		return validate(root, def, v)
+	}
+
+	if s.OneOf != nil {
+		matched := 0
+		for _, sub := range s.OneOf {
+			if validate(root, sub, v) == nil {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return fmt.Errorf("%v matches %d oneOf schemas", v, matched)
+		}
+	}
+
+	switch s.Type {
+	case "":
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%v is not an object", v)
+		}
+		for _, key := range s.Required {
+			if _, ok := obj[key]; !ok {
+				return fmt.Errorf("missing property %s", key)
+			}
+		}
+		for key, value := range obj {
+			prop, ok := s.Properties[key]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("unknown property %s", key)
+				}
+				continue
+			}
+			if err := validate(root, prop, value); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%v is not an array", v)
+		}
+		if s.MinItems != nil && len(arr) < *s.MinItems || s.MaxItems != nil && len(arr) > *s.MaxItems {
+			return fmt.Errorf("array length %d out of range", len(arr))
+		}
+		for i, item := range arr {
+			if err := validate(root, s.Items, item); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%v is not a string", v)
+		}
+		n := utf8.RuneCountInString(str)
+		if s.MinLength != nil && n < *s.MinLength || s.MaxLength != nil && n > *s.MaxLength {
+			return fmt.Errorf("string %q length out of range", str)
+		}
+		if s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(str) {
+			return fmt.Errorf("string %q does not match %s", str, s.Pattern)
+		}
+		if s.Const != "" && str != s.Const {
+			return fmt.Errorf("string %q is not %q", str, s.Const)
+		}
+		if s.Enum != nil && !contains(s.Enum, str) {
+			return fmt.Errorf("string %q not in %v", str, s.Enum)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%v is not a boolean", v)
+		}
+	case "null":
+		if v != nil {
+			return fmt.Errorf("%v is not null", v)
+		}
+	default:
+		return fmt.Errorf("unknown type %s", s.Type)
+	}
+	return nil
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+// Package schema exports JSON Schema documents for the SignDoc and for
+// registered message types, so that wallets and clients in other languages can
+// validate signing payloads and generate typed bindings without reading Go
+// source.
+//
+// Documents use the JSON Schema 2020-12 dialect and describe the canonical
+// encodings: 64-bit numbers are decimal strings, byte strings are base64, and
+// objects reject unknown properties.
+//
+// The .cram files in this directory are the Cramberry schemas of the binary
+// encodings and are unrelated to this package.
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+)
+
+// Draft is the JSON Schema dialect of generated documents
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	// ErrUnsupportedType is returned for message fields without a schema mapping
+	ErrUnsupportedType = errors.New("unsupported field type")
+
+	// ErrDuplicateMessage is returned when a message type is registered twice
+	ErrDuplicateMessage = errors.New("message type already registered")
+
+	// ErrMessageNotFound is returned for message types that are not registered
+	ErrMessageNotFound = errors.New("message type not registered")
+)
+
+// Schema is a JSON Schema document or subschema. The fields are the subset of
+// the 2020-12 vocabulary the SDK's encodings need; the zero value accepts any
+// instance.
+//
+// INVARIANT: Marshaling is deterministic (map keys are sorted by
+// encoding/json), so exported documents can be committed and diffed.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Type  string   `json:"type,omitempty"`
+	Const string   `json:"const,omitempty"`
+	Enum  []string `json:"enum,omitempty"`
+
+	Pattern         string `json:"pattern,omitempty"`
+	MinLength       *int   `json:"minLength,omitempty"`
+	MaxLength       *int   `json:"maxLength,omitempty"`
+	ContentEncoding string `json:"contentEncoding,omitempty"`
+
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+
+	Items    *Schema `json:"items,omitempty"`
+	MinItems *int    `json:"minItems,omitempty"`
+	MaxItems *int    `json:"maxItems,omitempty"`
+
+	OneOf []*Schema `json:"oneOf,omitempty"`
+
+	Defs map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Marshal returns the indented JSON of s with a trailing newline, the form
+// of exported schema files
+func Marshal(s *Schema) ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// DefRef returns the $ref of the definition name in the enclosing document's
+// $defs, escaped as a JSON Pointer (RFC 6901)
+func DefRef(name string) string {
+	escaped := strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+	return "#/$defs/" + escaped
+}
+
+// ref returns a schema referencing the definition name
+func ref(name string) *Schema {
+	return &Schema{Ref: DefRef(name)}
+}
+
+// intPtr returns a pointer to n, for the optional numeric keywords
+func intPtr(n int) *int {
+	return &n
+}
+
+// closedObject returns an object schema that requires all properties and
+// rejects unknown ones
+func closedObject(properties map[string]*Schema) *Schema {
+	required := make([]string, 0, len(properties))
+	for key := range properties {
+		required = append(required, key)
+	}
+	sort.Strings(required)
+
+	closed := false
+	return &Schema{
+		Type:                 "object",
+		Properties:           properties,
+		Required:             required,
+		AdditionalProperties: &closed,
+	}
+}
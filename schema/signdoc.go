@@ -0,0 +1,179 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Names of the shared definitions in every exported document's $defs
+const (
+	DefUint           = "Uint64String"
+	DefInt            = "Int64String"
+	DefAccountName    = "AccountName"
+	DefCoin           = "SignDocCoin"
+	DefFee            = "SignDocFee"
+	DefRatio          = "SignDocRatio"
+	DefValidityBound  = "ValidityBound"
+	DefSignDocMessage = "SignDocMessage"
+)
+
+// Canonical decimal forms: no sign on unsigned values, no leading zeros
+const (
+	uintPattern = `^(0|[1-9][0-9]*)$`
+	intPattern  = `^(0|-?[1-9][0-9]*)$`
+)
+
+// commonDefs returns the definitions shared by SignDoc and message documents
+func commonDefs() map[string]*Schema {
+	return map[string]*Schema{
+		DefUint: {
+			Type:        "string",
+			Description: "Unsigned 64-bit integer as a canonical decimal string",
+			Pattern:     uintPattern,
+		},
+		DefInt: {
+			Type:        "string",
+			Description: "Signed 64-bit integer as a canonical decimal string",
+			Pattern:     intPattern,
+		},
+		DefAccountName: {
+			Type:        "string",
+			Description: "Account name",
+			Pattern:     `^[a-z0-9.]+$`,
+			MinLength:   intPtr(1),
+			MaxLength:   intPtr(64),
+		},
+		DefCoin: withDescription(closedObject(map[string]*Schema{
+			"denom": {
+				Type:        "string",
+				Description: "Denomination, NFC-normalized, at most 64 bytes",
+				MinLength:   intPtr(1),
+			},
+			"amount": ref(DefUint),
+		}), "Coin with a decimal string amount"),
+	}
+}
+
+// withDescription sets the description of s and returns it
+func withDescription(s *Schema, description string) *Schema {
+	s.Description = description
+	return s
+}
+
+// signDocSchema returns the SignDoc document. data maps the sorted registered
+// type URLs msgTypes to their data schemas; with none, message data may be
+// any object.
+func signDocSchema(msgTypes []string, data map[string]*Schema) *Schema {
+	defs := commonDefs()
+	defs[DefFee] = withDescription(closedObject(map[string]*Schema{
+		"amount": {
+			Type:     "array",
+			Items:    ref(DefCoin),
+			MaxItems: intPtr(types.MaxFeeCoins),
+		},
+		"gas_limit": ref(DefUint),
+	}), "Transaction fee")
+	defs[DefRatio] = withDescription(closedObject(map[string]*Schema{
+		"numerator":   ref(DefUint),
+		"denominator": ref(DefUint),
+	}), "Ratio of unsigned integers; the denominator must not be zero")
+	defs[DefValidityBound] = withDescription(closedObject(map[string]*Schema{
+		"height": ref(DefUint),
+		"time":   ref(DefUint),
+	}), "Validity bound by block height and Unix time in seconds; zero disables a component")
+
+	if len(msgTypes) == 0 {
+		defs[DefSignDocMessage] = withDescription(closedObject(map[string]*Schema{
+			"type": {Type: "string", MinLength: intPtr(1)},
+			"data": {Type: "object"},
+		}), "Message type URL and canonical data")
+	} else {
+		variants := make([]*Schema, len(msgTypes))
+		for i, msgType := range msgTypes {
+			variants[i] = closedObject(map[string]*Schema{
+				"type": {Type: "string", Const: msgType},
+				"data": ref(messageDefName(msgType)),
+			})
+		}
+		defs[DefSignDocMessage] = &Schema{
+			Description: "Message type URL and canonical data of a registered message type",
+			OneOf:       variants,
+		}
+	}
+
+	doc := closedObject(map[string]*Schema{
+		"version": {
+			Type:        "string",
+			Description: "SignDoc format version",
+			Enum:        append([]string(nil), types.SupportedSignDocVersions...),
+		},
+		"chain_id": {
+			Type:        "string",
+			Description: "Chain identifier (see types.ValidateChainID); an epoch suffix such as \"-1\" must not have leading zeros",
+			Pattern:     `^[a-z0-9]+([.-][a-z0-9]+)*$`,
+			MinLength:   intPtr(1),
+			MaxLength:   intPtr(types.MaxChainIDLength),
+		},
+		"account":          {Type: "string", Description: "Signing account, NFC-normalized", MinLength: intPtr(1)},
+		"account_sequence": ref(DefUint),
+		"nonce":            ref(DefUint),
+		"memo": {
+			Type:        "string",
+			Description: "Memo, NFC-normalized, at most 512 bytes of UTF-8 without control characters (see types.DefaultMemoPolicy)",
+			Pattern:     `^[^\x00-\x1f\x7f-\x9f]*$`,
+			MaxLength:   intPtr(types.MaxMemoBytes),
+		},
+		"messages": {
+			Type:     "array",
+			Items:    ref(DefSignDocMessage),
+			MinItems: intPtr(1),
+			MaxItems: intPtr(types.MaxMessagesPerSignDoc),
+		},
+		"fee":          ref(DefFee),
+		"fee_slippage": ref(DefRatio),
+		"tip":          ref(DefCoin),
+		"fee_payer":    ref(DefAccountName),
+		"fee_granter":  ref(DefAccountName),
+		"signers": {
+			Type:        "array",
+			Description: "Distinct signers of a multi-signer transaction, the transaction's account first; omitted for a single signer",
+			Items:       ref(DefAccountName),
+			MinItems:    intPtr(2),
+			MaxItems:    intPtr(types.MaxSigners),
+		},
+		"not_before": ref(DefValidityBound),
+		"not_after":  ref(DefValidityBound),
+	})
+	for _, optional := range []string{"tip", "fee_payer", "fee_granter", "signers", "not_before", "not_after"} {
+		doc.Required = removeString(doc.Required, optional)
+	}
+
+	doc.Schema = Draft
+	doc.Title = "SignDoc"
+	doc.Description = fmt.Sprintf("Punnet SDK SignDoc, versions %s: the document a transaction's signatures sign",
+		strings.Join(types.SupportedSignDocVersions, ", "))
+	for msgType, def := range data {
+		defs[messageDefName(msgType)] = def
+	}
+	doc.Defs = defs
+	return doc
+}
+
+// messageDefName returns the $defs name of a message type's data schema, its
+// type URL without the leading slash (e.g. "punnet.bank.v1.MsgSend")
+func messageDefName(msgType string) string {
+	return strings.TrimPrefix(msgType, "/")
+}
+
+// removeString returns s without the element v
+func removeString(s []string, v string) []string {
+	out := s[:0]
+	for _, e := range s {
+		if e != v {
+			out = append(out, e)
+		}
+	}
+	return out
+}
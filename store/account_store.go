@@ -22,6 +22,14 @@ func NewAccountStore(backing BackingStore) *AccountStore {
 	}
 }
 
+// NewAccountStoreOf creates an account store over objects, sharing its
+// cache, e.g. with the runtime that authorizes transactions against it
+func NewAccountStoreOf(objects ObjectStore[*types.Account]) *AccountStore {
+	return &AccountStore{
+		store: objects,
+	}
+}
+
 // Get retrieves an account by name
 func (as *AccountStore) Get(ctx context.Context, name types.AccountName) (*types.Account, error) {
 	if as == nil || as.store == nil {
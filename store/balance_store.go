@@ -1,6 +1,7 @@
 package store
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 
@@ -298,3 +299,13 @@ func (bs *BalanceStore) Close() error {
 
 	return bs.store.Close()
 }
+
+// BalanceDenomIndex is an IndexFunc indexing balance keys (see BalanceKey)
+// by denomination, e.g. to enumerate the holders of a denom
+func BalanceDenomIndex(key, _ []byte) ([][]byte, error) {
+	_, denom, found := bytes.Cut(key, []byte("/"))
+	if !found || len(denom) == 0 {
+		return nil, fmt.Errorf("%w: not a balance key: %q", ErrInvalidKey, key)
+	}
+	return [][]byte{denom}, nil
+}
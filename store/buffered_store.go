@@ -0,0 +1,279 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// BufferedStore wraps a BackingStore and buffers all writes in memory until
+// Write is called. Reads and iterators see the buffered writes layered over
+// the parent, so a sequence of operations can be staged, inspected, and then
+// applied or discarded as a unit.
+//
+// INVARIANT: The parent is not modified until Write is called.
+//
+// Iterators materialize the merged range in memory, so BufferedStore is meant
+// for bounded batches such as store migrations, not as a general cache.
+type BufferedStore struct {
+	mu     sync.RWMutex
+	parent BackingStore
+
+	// writes maps key to value; a nil value marks a deletion
+	writes map[string][]byte
+}
+
+// NewBufferedStore creates a buffered store over parent
+func NewBufferedStore(parent BackingStore) *BufferedStore {
+	if parent == nil {
+		panic("parent store cannot be nil")
+	}
+
+	return &BufferedStore{
+		parent: parent,
+		writes: make(map[string][]byte),
+	}
+}
+
+// Get retrieves raw bytes by key
+func (bs *BufferedStore) Get(key []byte) ([]byte, error) {
+	if bs == nil {
+		return nil, ErrStoreNil
+	}
+
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	bs.mu.RLock()
+	value, buffered := bs.writes[string(key)]
+	bs.mu.RUnlock()
+
+	if !buffered {
+		return bs.parent.Get(key)
+	}
+	if value == nil {
+		return nil, ErrNotFound
+	}
+
+	// Return defensive copy
+	result := make([]byte, len(value))
+	copy(result, value)
+	return result, nil
+}
+
+// Set buffers a write
+func (bs *BufferedStore) Set(key []byte, value []byte) error {
+	if bs == nil {
+		return ErrStoreNil
+	}
+
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	// Store defensive copy (non-nil, so it is not mistaken for a deletion)
+	valueCopy := make([]byte, len(value))
+	copy(valueCopy, value)
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	bs.writes[string(key)] = valueCopy
+	return nil
+}
+
+// Delete buffers a deletion
+func (bs *BufferedStore) Delete(key []byte) error {
+	if bs == nil {
+		return ErrStoreNil
+	}
+
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	bs.writes[string(key)] = nil
+	return nil
+}
+
+// Has checks if a key exists
+func (bs *BufferedStore) Has(key []byte) (bool, error) {
+	if bs == nil {
+		return false, ErrStoreNil
+	}
+
+	if err := validateKey(key); err != nil {
+		return false, err
+	}
+
+	bs.mu.RLock()
+	value, buffered := bs.writes[string(key)]
+	bs.mu.RUnlock()
+
+	if !buffered {
+		return bs.parent.Has(key)
+	}
+	return value != nil, nil
+}
+
+// Iterator returns an iterator over the merged view of a range of keys
+func (bs *BufferedStore) Iterator(start, end []byte) (RawIterator, error) {
+	return bs.iterator(start, end, false)
+}
+
+// ReverseIterator returns a reverse iterator over the merged view of a range of keys
+func (bs *BufferedStore) ReverseIterator(start, end []byte) (RawIterator, error) {
+	return bs.iterator(start, end, true)
+}
+
+// iterator merges the parent range with buffered writes
+// Complexity: O(n log n) time and O(n) memory for n keys in range
+func (bs *BufferedStore) iterator(start, end []byte, reverse bool) (RawIterator, error) {
+	if bs == nil {
+		return nil, ErrStoreNil
+	}
+
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+
+	merged := make(map[string][]byte)
+
+	parentIter, err := bs.parent.Iterator(start, end)
+	if err != nil {
+		return nil, err
+	}
+	for ; parentIter.Valid(); parentIter.Next() {
+		merged[string(parentIter.Key())] = parentIter.Value()
+	}
+	iterErr := parentIter.Error()
+	closeErr := parentIter.Close()
+	if iterErr != nil {
+		return nil, iterErr
+	}
+	if closeErr != nil {
+		return nil, closeErr
+	}
+
+	for key, value := range bs.writes {
+		keyBytes := []byte(key)
+		if start != nil && bytes.Compare(keyBytes, start) < 0 {
+			continue
+		}
+		if end != nil && bytes.Compare(keyBytes, end) >= 0 {
+			continue
+		}
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		valueCopy := make([]byte, len(value))
+		copy(valueCopy, value)
+		merged[key] = valueCopy
+	}
+
+	keys := make([]string, 0, len(merged))
+	for key := range merged {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if reverse {
+		for i, j := 0, len(keys)-1; i < j; i, j = i+1, j-1 {
+			keys[i], keys[j] = keys[j], keys[i]
+		}
+	}
+
+	items := make([]kvPair, len(keys))
+	for i, key := range keys {
+		items[i] = kvPair{key: []byte(key), value: merged[key]}
+	}
+
+	return &memoryIterator{items: items}, nil
+}
+
+// PendingWrites returns the number of buffered sets and deletions
+func (bs *BufferedStore) PendingWrites() (sets int, deletes int) {
+	if bs == nil {
+		return 0, 0
+	}
+
+	bs.mu.RLock()
+	defer bs.mu.RUnlock()
+
+	for _, value := range bs.writes {
+		if value == nil {
+			deletes++
+		} else {
+			sets++
+		}
+	}
+	return sets, deletes
+}
+
+// Write applies all buffered writes to the parent in key order and clears
+// the buffer. It does not flush the parent.
+//
+// If the parent rejects a write, Write stops and returns the error; writes
+// already applied are not rolled back, and the remaining writes stay buffered.
+func (bs *BufferedStore) Write() error {
+	if bs == nil {
+		return ErrStoreNil
+	}
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	// Apply in sorted order for deterministic behavior
+	keys := make([]string, 0, len(bs.writes))
+	for key := range bs.writes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := bs.writes[key]
+		var err error
+		if value == nil {
+			err = bs.parent.Delete([]byte(key))
+			if errors.Is(err, ErrNotFound) {
+				err = nil
+			}
+		} else {
+			err = bs.parent.Set([]byte(key), value)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write key %x: %w", key, err)
+		}
+		delete(bs.writes, key)
+	}
+
+	return nil
+}
+
+// Discard drops all buffered writes
+func (bs *BufferedStore) Discard() {
+	if bs == nil {
+		return
+	}
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	bs.writes = make(map[string][]byte)
+}
+
+// Flush applies buffered writes to the parent (see Write)
+func (bs *BufferedStore) Flush() error {
+	return bs.Write()
+}
+
+// Close discards buffered writes. The parent is not closed.
+func (bs *BufferedStore) Close() error {
+	bs.Discard()
+	return nil
+}
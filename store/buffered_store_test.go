@@ -0,0 +1,121 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestBufferedStore_IsolatesWritesUntilWrite(t *testing.T) {
+	parent := NewMemoryStore()
+	if err := parent.Set([]byte("a"), []byte("1")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := parent.Set([]byte("b"), []byte("2")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	bs := NewBufferedStore(parent)
+	if err := bs.Set([]byte("c"), []byte("3")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := bs.Delete([]byte("a")); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	// Buffered view
+	if _, err := bs.Get([]byte("a")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected deleted key to be not found, got %v", err)
+	}
+	if got, err := bs.Get([]byte("c")); err != nil || !bytes.Equal(got, []byte("3")) {
+		t.Errorf("expected buffered value 3, got %q (%v)", got, err)
+	}
+	if has, _ := bs.Has([]byte("b")); !has {
+		t.Error("expected parent key to be visible")
+	}
+
+	// Parent untouched
+	if has, _ := parent.Has([]byte("c")); has {
+		t.Error("parent modified before Write")
+	}
+	if has, _ := parent.Has([]byte("a")); !has {
+		t.Error("parent modified before Write")
+	}
+
+	if sets, deletes := bs.PendingWrites(); sets != 1 || deletes != 1 {
+		t.Errorf("expected 1 set and 1 delete, got %d and %d", sets, deletes)
+	}
+
+	if err := bs.Write(); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if has, _ := parent.Has([]byte("a")); has {
+		t.Error("deletion not applied")
+	}
+	if got, _ := parent.Get([]byte("c")); !bytes.Equal(got, []byte("3")) {
+		t.Errorf("set not applied, got %q", got)
+	}
+	if sets, deletes := bs.PendingWrites(); sets != 0 || deletes != 0 {
+		t.Error("buffer not cleared after Write")
+	}
+}
+
+func TestBufferedStore_Discard(t *testing.T) {
+	parent := NewMemoryStore()
+	bs := NewBufferedStore(parent)
+	if err := bs.Set([]byte("k"), []byte("v")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	bs.Discard()
+
+	if err := bs.Write(); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if has, _ := parent.Has([]byte("k")); has {
+		t.Error("discarded write reached parent")
+	}
+}
+
+func TestBufferedStore_Iterator(t *testing.T) {
+	parent := NewMemoryStore()
+	for _, k := range []string{"a", "b", "d"} {
+		if err := parent.Set([]byte(k), []byte("p"+k)); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+
+	bs := NewBufferedStore(parent)
+	_ = bs.Delete([]byte("b"))
+	_ = bs.Set([]byte("c"), []byte("bc"))
+	_ = bs.Set([]byte("d"), []byte("bd"))
+	_ = bs.Set([]byte("z"), []byte("out of range"))
+
+	collect := func(iter RawIterator, err error) []string {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("iterator failed: %v", err)
+		}
+		defer iter.Close()
+		var got []string
+		for ; iter.Valid(); iter.Next() {
+			got = append(got, string(iter.Key())+"="+string(iter.Value()))
+		}
+		return got
+	}
+
+	want := []string{"a=pa", "c=bc", "d=bd"}
+	got := collect(bs.Iterator([]byte("a"), []byte("e")))
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+
+	rev := collect(bs.ReverseIterator([]byte("a"), []byte("e")))
+	if len(rev) != 3 || rev[0] != "d=bd" || rev[2] != "a=pa" {
+		t.Errorf("unexpected reverse order: %v", rev)
+	}
+}
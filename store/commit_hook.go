@@ -0,0 +1,79 @@
+package store
+
+// KVChange is a write committed to an IAVLStore
+type KVChange struct {
+	// Key is the written key
+	Key []byte `json:"key"`
+
+	// Value is the written value (nil for a delete)
+	Value []byte `json:"value,omitempty"`
+
+	// Delete reports whether the key was deleted
+	Delete bool `json:"delete,omitempty"`
+}
+
+// CommitHook observes every version an IAVLStore saves, with the writes the
+// version committed in write order (a key written twice appears twice).
+//
+// Hooks run while the store is locked: they must return quickly and must
+// not call back into the store. changes is owned by the store and must not
+// be modified.
+type CommitHook func(version int64, changes []KVChange)
+
+// WithCommitHook registers hook to run after every saved version
+func WithCommitHook(hook CommitHook) IAVLOption {
+	return func(s *IAVLStore) {
+		if hook != nil {
+			s.commitHooks = append(s.commitHooks, hook)
+		}
+	}
+}
+
+// AddCommitHook registers hook to run after every saved version. Writes made
+// before the first hook (or WAL) was registered are not reported for the
+// version in progress.
+func (s *IAVLStore) AddCommitHook(hook CommitHook) {
+	if s == nil || hook == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commitHooks = append(s.commitHooks, hook)
+}
+
+// PendingChanges returns the number of writes since the last saved version.
+// Writes are only counted while the store has a WAL or commit hooks.
+func (s *IAVLStore) PendingChanges() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.pending)
+}
+
+// tracksPending reports whether writes must be recorded in s.pending.
+//
+// PRECONDITION: s.mu is held.
+func (s *IAVLStore) tracksPending() bool {
+	return s.wal != nil || len(s.commitHooks) > 0
+}
+
+// runCommitHooks reports the writes ops of version to the commit hooks.
+//
+// PRECONDITION: s.mu is held for writing.
+func (s *IAVLStore) runCommitHooks(version int64, ops []walOp) {
+	if len(s.commitHooks) == 0 {
+		return
+	}
+
+	changes := make([]KVChange, len(ops))
+	for i, op := range ops {
+		changes[i] = KVChange{Key: op.key, Value: op.value, Delete: op.value == nil}
+	}
+	for _, hook := range s.commitHooks {
+		hook(version, changes)
+	}
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIAVLStore_CommitHook(t *testing.T) {
+	type commit struct {
+		version int64
+		changes []KVChange
+	}
+	var commits []commit
+	record := func(version int64, changes []KVChange) {
+		commits = append(commits, commit{version, append([]KVChange(nil), changes...)})
+	}
+
+	s, err := NewIAVLStore(NewMemDB(), 0, WithCommitHook(record))
+	if err != nil {
+		t.Fatalf("NewIAVLStore failed: %v", err)
+	}
+	defer s.Close()
+
+	if err := s.Set([]byte("a"), []byte("1")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := s.Set([]byte("b"), []byte{}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := s.Delete([]byte("a")); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if n := s.PendingChanges(); n != 3 {
+		t.Fatalf("PendingChanges = %d, want 3", n)
+	}
+	if _, _, err := s.SaveVersion(); err != nil {
+		t.Fatalf("SaveVersion failed: %v", err)
+	}
+	if n := s.PendingChanges(); n != 0 {
+		t.Fatalf("PendingChanges after save = %d, want 0", n)
+	}
+
+	// Flush saves a version too, reported without writes
+	if err := s.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	want := []commit{
+		{1, []KVChange{
+			{Key: []byte("a"), Value: []byte("1")},
+			{Key: []byte("b"), Value: []byte{}},
+			{Key: []byte("a"), Delete: true},
+		}},
+		{2, nil},
+	}
+	if !reflect.DeepEqual(commits, want) {
+		t.Fatalf("commits = %+v, want %+v", commits, want)
+	}
+}
+
+func TestIAVLStore_AddCommitHook(t *testing.T) {
+	s, err := NewIAVLStore(NewMemDB(), 0)
+	if err != nil {
+		t.Fatalf("NewIAVLStore failed: %v", err)
+	}
+	defer s.Close()
+
+	// Writes are not tracked without hooks
+	if err := s.Set([]byte("untracked"), []byte("1")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if n := s.PendingChanges(); n != 0 {
+		t.Fatalf("PendingChanges without hooks = %d, want 0", n)
+	}
+
+	var versions []int64
+	var keys []string
+	s.AddCommitHook(func(version int64, changes []KVChange) {
+		versions = append(versions, version)
+		for _, c := range changes {
+			keys = append(keys, string(c.Key))
+		}
+	})
+	s.AddCommitHook(nil)
+
+	if err := s.Set([]byte("tracked"), []byte("1")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if _, _, err := s.SaveVersion(); err != nil {
+		t.Fatalf("SaveVersion failed: %v", err)
+	}
+	if !reflect.DeepEqual(versions, []int64{1}) || !reflect.DeepEqual(keys, []string{"tracked"}) {
+		t.Fatalf("hook saw versions %v keys %v, want [1] [tracked]", versions, keys)
+	}
+}
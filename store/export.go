@@ -0,0 +1,493 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// ErrInvalidExport is returned when importing a stream that is not a valid
+// state export
+var ErrInvalidExport = errors.New("invalid state export")
+
+// ExportFormatVersion is the version of the export stream format
+const ExportFormatVersion = 1
+
+// exportMagic starts every export stream
+const exportMagic = "PNTSTATE"
+
+// Export record types
+const (
+	exportRecordSection byte = 1
+	exportRecordEntry   byte = 2
+	exportRecordEnd     byte = 3
+)
+
+// Export size limits, bounding the allocations of a corrupt stream
+const (
+	maxExportKeyLength   = 1 << 16
+	maxExportValueLength = 1 << 26
+)
+
+// DefaultProgressInterval is the default number of entries between progress
+// callbacks
+const DefaultProgressInterval = 10000
+
+// moduleScopedNamespaces are the key namespaces holding one key space per
+// module ("<namespace>/<module>/..."), which are exported as one section per
+// module. They mirror the prefixes of the capability package.
+var moduleScopedNamespaces = map[string]bool{
+	"module":      true,
+	"account_ext": true,
+	"index":       true,
+}
+
+// SectionOf returns the export section of key: "<namespace>/<module>" for
+// module-scoped key spaces (e.g. "module/bank"), otherwise the namespace up
+// to the first "/" (e.g. "balance"). Keys without "/" have section "".
+func SectionOf(key []byte) string {
+	namespace, rest, ok := bytes.Cut(key, []byte("/"))
+	if !ok {
+		return ""
+	}
+	if !moduleScopedNamespaces[string(namespace)] {
+		return string(namespace)
+	}
+	module, _, ok := bytes.Cut(rest, []byte("/"))
+	if !ok {
+		return string(namespace)
+	}
+	return string(namespace) + "/" + string(module)
+}
+
+// StreamProgress reports the progress of an export or import
+type StreamProgress struct {
+	// Section is the section being streamed
+	Section string
+
+	// Entries is the number of entries streamed so far
+	Entries uint64
+
+	// Bytes is the number of stream bytes written or read so far
+	Bytes uint64
+}
+
+// ProgressFunc receives export and import progress
+type ProgressFunc func(StreamProgress)
+
+// StreamStats summarizes a completed export or import
+type StreamStats struct {
+	// Height is the block height recorded in the stream header
+	Height int64
+
+	// Sections is the number of sections
+	Sections int
+
+	// Entries is the number of entries
+	Entries uint64
+
+	// Bytes is the stream size
+	Bytes uint64
+
+	// Checksum is the SHA-256 of the stream before the checksum
+	Checksum []byte
+}
+
+// streamOptions configures Export and Import
+type streamOptions struct {
+	height           int64
+	progress         ProgressFunc
+	progressInterval uint64
+}
+
+// StreamOption configures Export and Import
+type StreamOption func(*streamOptions)
+
+// WithExportHeight records the block height of the exported state in the
+// stream header (default 0). Import ignores it.
+func WithExportHeight(height int64) StreamOption {
+	return func(o *streamOptions) {
+		o.height = height
+	}
+}
+
+// WithProgress calls fn every progress interval entries and when a section
+// ends
+func WithProgress(fn ProgressFunc) StreamOption {
+	return func(o *streamOptions) {
+		o.progress = fn
+	}
+}
+
+// WithProgressInterval sets the number of entries between progress
+// callbacks (default DefaultProgressInterval)
+func WithProgressInterval(n uint64) StreamOption {
+	return func(o *streamOptions) {
+		if n > 0 {
+			o.progressInterval = n
+		}
+	}
+}
+
+func newStreamOptions(opts []StreamOption) *streamOptions {
+	o := &streamOptions{progressInterval: DefaultProgressInterval}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// report calls the progress callback, if any
+func (o *streamOptions) report(section string, entries, n uint64) {
+	if o.progress != nil {
+		o.progress(StreamProgress{Section: section, Entries: entries, Bytes: n})
+	}
+}
+
+// exportWriter buffers and checksums the stream
+type exportWriter struct {
+	w   *bufio.Writer
+	h   hash.Hash
+	n   uint64
+	buf []byte
+}
+
+func (ew *exportWriter) write(b []byte) error {
+	ew.h.Write(b)
+	ew.n += uint64(len(b))
+	_, err := ew.w.Write(b)
+	return err
+}
+
+// writeBytes writes b prefixed by its uvarint length
+func (ew *exportWriter) writeBytes(b []byte) error {
+	ew.buf = binary.AppendUvarint(ew.buf[:0], uint64(len(b)))
+	if err := ew.write(ew.buf); err != nil {
+		return err
+	}
+	return ew.write(b)
+}
+
+// Export writes every entry of s to w in key order.
+//
+// The stream is the magic "PNTSTATE", the format version (1 byte) and the
+// height (8 bytes, big-endian), then for each section a section record
+// followed by its entries, then an end record:
+//
+//	section: 0x01 || uvarint len || name
+//	entry:   0x02 || uvarint len || key || uvarint len || value
+//	end:     0x03 || entry count (8 bytes, big-endian) || SHA-256
+//
+// The SHA-256 covers every byte before it. Sections group keys by SectionOf
+// and follow key order, so the same state always exports to the same bytes.
+//
+// PRECONDITION: s is not written during the export; export a saved version
+// (see IAVLStore.Export) of a live store.
+//
+// Complexity: O(n) time and O(1) memory for n entries
+func Export(s BackingStore, w io.Writer, opts ...StreamOption) (*StreamStats, error) {
+	if s == nil {
+		return nil, ErrStoreNil
+	}
+	if w == nil {
+		return nil, fmt.Errorf("export writer cannot be nil")
+	}
+	o := newStreamOptions(opts)
+
+	ew := &exportWriter{w: bufio.NewWriter(w), h: sha256.New()}
+	header := append([]byte(exportMagic), ExportFormatVersion)
+	header = binary.BigEndian.AppendUint64(header, uint64(o.height))
+	if err := ew.write(header); err != nil {
+		return nil, fmt.Errorf("failed to write export: %w", err)
+	}
+
+	iter, err := s.Iterator(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate store: %w", err)
+	}
+	defer iter.Close()
+
+	stats := &StreamStats{Height: o.height}
+	section, inSection := "", false
+	for ; iter.Valid(); iter.Next() {
+		key, value := iter.Key(), iter.Value()
+
+		if next := SectionOf(key); !inSection || next != section {
+			if inSection {
+				o.report(section, stats.Entries, ew.n)
+			}
+			section, inSection = next, true
+			stats.Sections++
+			if err := ew.write([]byte{exportRecordSection}); err != nil {
+				return nil, fmt.Errorf("failed to write export: %w", err)
+			}
+			if err := ew.writeBytes([]byte(section)); err != nil {
+				return nil, fmt.Errorf("failed to write export: %w", err)
+			}
+		}
+
+		if err := ew.write([]byte{exportRecordEntry}); err != nil {
+			return nil, fmt.Errorf("failed to write export: %w", err)
+		}
+		if err := ew.writeBytes(key); err != nil {
+			return nil, fmt.Errorf("failed to write export: %w", err)
+		}
+		if err := ew.writeBytes(value); err != nil {
+			return nil, fmt.Errorf("failed to write export: %w", err)
+		}
+
+		stats.Entries++
+		if stats.Entries%o.progressInterval == 0 {
+			o.report(section, stats.Entries, ew.n)
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to iterate store: %w", err)
+	}
+	if inSection {
+		o.report(section, stats.Entries, ew.n)
+	}
+
+	trailer := binary.BigEndian.AppendUint64([]byte{exportRecordEnd}, stats.Entries)
+	if err := ew.write(trailer); err != nil {
+		return nil, fmt.Errorf("failed to write export: %w", err)
+	}
+	stats.Checksum = ew.h.Sum(nil)
+	if _, err := ew.w.Write(stats.Checksum); err != nil {
+		return nil, fmt.Errorf("failed to write export: %w", err)
+	}
+	if err := ew.w.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to write export: %w", err)
+	}
+	stats.Bytes = ew.n + sha256.Size
+	return stats, nil
+}
+
+// exportReader checksums the stream as it is read
+type exportReader struct {
+	r *bufio.Reader
+	h hash.Hash
+	n uint64
+}
+
+// ReadByte implements io.ByteReader for binary.ReadUvarint
+func (er *exportReader) ReadByte() (byte, error) {
+	b, err := er.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	er.h.Write([]byte{b})
+	er.n++
+	return b, nil
+}
+
+func (er *exportReader) readFull(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(er.r, b); err != nil {
+		return nil, err
+	}
+	er.h.Write(b)
+	er.n += uint64(n)
+	return b, nil
+}
+
+// readBytes reads a uvarint length-prefixed byte string of at most max bytes
+func (er *exportReader) readBytes(max uint64) ([]byte, error) {
+	length, err := binary.ReadUvarint(er)
+	if err != nil {
+		return nil, err
+	}
+	if length > max {
+		return nil, fmt.Errorf("%w: length %d exceeds maximum %d", ErrInvalidExport, length, max)
+	}
+	return er.readFull(int(length))
+}
+
+// Import writes the entries of an export stream to s and verifies the
+// stream. Entries must be in strictly increasing key order within their
+// sections, and the entry count and checksum must match the end record.
+//
+// PRECONDITION: s is empty and is discarded if Import fails, since entries
+// are written as they are read (import into a fresh store, or a
+// BufferedStore flushed only on success).
+//
+// Complexity: O(n) time and O(1) memory for n entries
+func Import(s BackingStore, r io.Reader, opts ...StreamOption) (*StreamStats, error) {
+	if s == nil {
+		return nil, ErrStoreNil
+	}
+	if r == nil {
+		return nil, fmt.Errorf("import reader cannot be nil")
+	}
+	o := newStreamOptions(opts)
+
+	iter, err := s.Iterator(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate store: %w", err)
+	}
+	empty := !iter.Valid()
+	iter.Close()
+	if !empty {
+		return nil, fmt.Errorf("import target store is not empty")
+	}
+
+	stats, err := importStream(s, &exportReader{r: bufio.NewReader(r), h: sha256.New()}, o)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("%w: truncated stream", ErrInvalidExport)
+	}
+	return stats, err
+}
+
+// importStream reads and applies the stream after the target check
+func importStream(s BackingStore, er *exportReader, o *streamOptions) (*StreamStats, error) {
+	header, err := er.readFull(len(exportMagic) + 1 + 8)
+	if err != nil {
+		return nil, err
+	}
+	if string(header[:len(exportMagic)]) != exportMagic {
+		return nil, fmt.Errorf("%w: bad magic", ErrInvalidExport)
+	}
+	if version := header[len(exportMagic)]; version != ExportFormatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrInvalidExport, version)
+	}
+	stats := &StreamStats{Height: int64(binary.BigEndian.Uint64(header[len(exportMagic)+1:]))}
+
+	var section string
+	var sectionEntries uint64
+	var lastKey []byte
+	for {
+		recordType, err := er.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		switch recordType {
+		case exportRecordSection:
+			if stats.Sections > 0 {
+				if sectionEntries == 0 {
+					return nil, fmt.Errorf("%w: empty section %q", ErrInvalidExport, section)
+				}
+				o.report(section, stats.Entries, er.n)
+			}
+			name, err := er.readBytes(maxExportKeyLength)
+			if err != nil {
+				return nil, err
+			}
+			if stats.Sections > 0 && string(name) == section {
+				return nil, fmt.Errorf("%w: repeated section %q", ErrInvalidExport, name)
+			}
+			section, sectionEntries = string(name), 0
+			stats.Sections++
+
+		case exportRecordEntry:
+			if stats.Sections == 0 {
+				return nil, fmt.Errorf("%w: entry outside a section", ErrInvalidExport)
+			}
+			key, err := er.readBytes(maxExportKeyLength)
+			if err != nil {
+				return nil, err
+			}
+			value, err := er.readBytes(maxExportValueLength)
+			if err != nil {
+				return nil, err
+			}
+			if len(key) == 0 || (lastKey != nil && bytes.Compare(key, lastKey) <= 0) {
+				return nil, fmt.Errorf("%w: entry %d key %q out of order", ErrInvalidExport, stats.Entries, key)
+			}
+			if got := SectionOf(key); got != section {
+				return nil, fmt.Errorf("%w: key %q of section %q in section %q", ErrInvalidExport, key, got, section)
+			}
+			if err := s.Set(key, value); err != nil {
+				return nil, fmt.Errorf("failed to import key %q: %w", key, err)
+			}
+			lastKey = key
+			sectionEntries++
+			stats.Entries++
+			if stats.Entries%o.progressInterval == 0 {
+				o.report(section, stats.Entries, er.n)
+			}
+
+		case exportRecordEnd:
+			if stats.Sections > 0 {
+				if sectionEntries == 0 {
+					return nil, fmt.Errorf("%w: empty section %q", ErrInvalidExport, section)
+				}
+				o.report(section, stats.Entries, er.n)
+			}
+			count, err := er.readFull(8)
+			if err != nil {
+				return nil, err
+			}
+			if n := binary.BigEndian.Uint64(count); n != stats.Entries {
+				return nil, fmt.Errorf("%w: end record counts %d entries, read %d", ErrInvalidExport, n, stats.Entries)
+			}
+
+			stats.Checksum = er.h.Sum(nil)
+			checksum := make([]byte, sha256.Size)
+			if _, err := io.ReadFull(er.r, checksum); err != nil {
+				return nil, err
+			}
+			if !bytes.Equal(checksum, stats.Checksum) {
+				return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidExport)
+			}
+			if _, err := er.r.ReadByte(); err != io.EOF {
+				return nil, fmt.Errorf("%w: trailing data after end record", ErrInvalidExport)
+			}
+			stats.Bytes = er.n + sha256.Size
+			return stats, nil
+
+		default:
+			return nil, fmt.Errorf("%w: unknown record type %d", ErrInvalidExport, recordType)
+		}
+	}
+}
+
+// Export writes the latest saved version to w (see Export), recording its
+// version as the height
+func (s *IAVLStore) Export(w io.Writer, opts ...StreamOption) (*StreamStats, error) {
+	if s == nil {
+		return nil, ErrStoreNil
+	}
+
+	version := s.Version()
+	view, err := s.ReadAt(version)
+	if err != nil {
+		return nil, err
+	}
+	return Export(view, w, append(opts, WithExportHeight(version))...)
+}
+
+// Import imports an export stream (see Import) into the empty store and
+// saves it as the stream's height, so a chain can start from exported state.
+// A stream with height 0 is saved as version 1.
+//
+// PRECONDITION: The store has no saved version and no writes.
+func (s *IAVLStore) Import(r io.Reader, opts ...StreamOption) (*StreamStats, error) {
+	if s == nil {
+		return nil, ErrStoreNil
+	}
+	if version := s.Version(); version != 0 {
+		return nil, fmt.Errorf("import target store has saved version %d", version)
+	}
+
+	stats, err := Import(s, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stats.Height > 0 {
+		s.tree.SetInitialVersion(uint64(stats.Height))
+	}
+	if _, err := s.saveVersionLocked(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
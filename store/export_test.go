@@ -0,0 +1,200 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func exportTestStore(t *testing.T) BackingStore {
+	t.Helper()
+
+	s := NewMemoryStore()
+	entries := map[string]string{
+		"account/alice":             "a",
+		"account/bob":               "b",
+		"account_ext/auth/alice":    "ext",
+		"balance/alice/stake":       "100",
+		"module/bank/params":        "p",
+		"module/staking/validators": "v",
+		"module/staking/params":     "",
+		"version":                   "1",
+	}
+	for key, value := range entries {
+		if err := s.Set([]byte(key), []byte(value)); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+	return s
+}
+
+func TestSectionOf(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"account/alice", "account"},
+		{"balance/alice/stake", "balance"},
+		{"module/bank/params", "module/bank"},
+		{"account_ext/auth/alice", "account_ext/auth"},
+		{"index/nft/owner/x", "index/nft"},
+		{"module/bank", "module"},
+		{"version", ""},
+	}
+	for _, tt := range tests {
+		if got := SectionOf([]byte(tt.key)); got != tt.want {
+			t.Errorf("SectionOf(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	src := exportTestStore(t)
+
+	var progress []StreamProgress
+	var buf bytes.Buffer
+	exported, err := Export(src, &buf, WithExportHeight(42), WithProgress(func(p StreamProgress) {
+		progress = append(progress, p)
+	}))
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if exported.Entries != 8 || exported.Sections != 6 || exported.Height != 42 {
+		t.Errorf("export stats = %+v, want 8 entries in 6 sections at height 42", exported)
+	}
+	if exported.Bytes != uint64(buf.Len()) {
+		t.Errorf("export stats bytes = %d, stream is %d", exported.Bytes, buf.Len())
+	}
+	if len(progress) != 6 || progress[len(progress)-1].Entries != 8 {
+		t.Errorf("progress = %+v, want one report per section", progress)
+	}
+
+	// The same state exports to the same bytes
+	var again bytes.Buffer
+	if _, err := Export(src, &again, WithExportHeight(42)); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if !bytes.Equal(again.Bytes(), buf.Bytes()) {
+		t.Error("export is not deterministic")
+	}
+
+	dst := NewMemoryStore()
+	imported, err := Import(dst, bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if imported.Entries != exported.Entries || imported.Height != 42 || !bytes.Equal(imported.Checksum, exported.Checksum) {
+		t.Errorf("import stats = %+v, want %+v", imported, exported)
+	}
+
+	iter, err := src.Iterator(nil, nil)
+	if err != nil {
+		t.Fatalf("Iterator failed: %v", err)
+	}
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		value, err := dst.Get(iter.Key())
+		if err != nil || !bytes.Equal(value, iter.Value()) {
+			t.Errorf("imported %q = %q, %v, want %q", iter.Key(), value, err, iter.Value())
+		}
+	}
+}
+
+func TestExport_ProgressInterval(t *testing.T) {
+	s := NewMemoryStore()
+	for i := 0; i < 10; i++ {
+		s.Set([]byte(fmt.Sprintf("balance/acc%d/stake", i)), []byte("1"))
+	}
+
+	var reports int
+	var buf bytes.Buffer
+	if _, err := Export(s, &buf, WithProgressInterval(3), WithProgress(func(StreamProgress) { reports++ })); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	// Entries 3, 6, 9 and the end of the section
+	if reports != 4 {
+		t.Errorf("got %d progress reports, want 4", reports)
+	}
+}
+
+func TestImport_Invalid(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := Export(exportTestStore(t), &buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	stream := buf.Bytes()
+
+	tests := []struct {
+		name   string
+		stream func() []byte
+	}{
+		{"empty", func() []byte { return nil }},
+		{"bad magic", func() []byte { b := bytes.Clone(stream); b[0] = 'X'; return b }},
+		{"bad version", func() []byte { b := bytes.Clone(stream); b[8] = 9; return b }},
+		{"truncated", func() []byte { return stream[:len(stream)/2] }},
+		{"missing checksum", func() []byte { return stream[:len(stream)-32] }},
+		{"flipped value byte", func() []byte { b := bytes.Clone(stream); b[len(b)-50] ^= 0x01; return b }},
+		{"trailing data", func() []byte { return append(bytes.Clone(stream), 0) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Import(NewMemoryStore(), bytes.NewReader(tt.stream())); !errors.Is(err, ErrInvalidExport) {
+				t.Errorf("Import error = %v, want ErrInvalidExport", err)
+			}
+		})
+	}
+}
+
+func TestImport_NonEmptyTarget(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := Export(exportTestStore(t), &buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if _, err := Import(exportTestStore(t), &buf); err == nil {
+		t.Error("expected error importing into a non-empty store")
+	}
+}
+
+func TestIAVLStore_ExportImport(t *testing.T) {
+	src, err := NewIAVLStore(NewMemDB(), 0)
+	if err != nil {
+		t.Fatalf("NewIAVLStore failed: %v", err)
+	}
+	src.Set([]byte("module/bank/params"), []byte("p"))
+	src.SaveVersion()
+	src.Set([]byte("account/alice"), []byte("a"))
+	src.SaveVersion()
+	// Unsaved writes are not exported
+	src.Set([]byte("account/bob"), []byte("b"))
+
+	var buf bytes.Buffer
+	stats, err := src.Export(&buf)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if stats.Height != 2 || stats.Entries != 2 {
+		t.Errorf("export stats = %+v, want 2 entries at height 2", stats)
+	}
+
+	dst, err := NewIAVLStore(NewMemDB(), 0)
+	if err != nil {
+		t.Fatalf("NewIAVLStore failed: %v", err)
+	}
+	if _, err := dst.Import(&buf); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if v := dst.Version(); v != 2 {
+		t.Errorf("version after import = %d, want 2", v)
+	}
+	if value, err := dst.Get([]byte("account/alice")); err != nil || string(value) != "a" {
+		t.Errorf("Get(account/alice) = %q, %v, want a", value, err)
+	}
+	if has, _ := dst.Has([]byte("account/bob")); has {
+		t.Error("unsaved write was exported")
+	}
+
+	if _, err := dst.Import(bytes.NewReader(nil)); err == nil {
+		t.Error("expected error importing into a store with a saved version")
+	}
+}
@@ -16,15 +16,55 @@ import (
 // versioning, and merkle proof generation
 type IAVLStore struct {
 	mu      sync.RWMutex
+	db      *meteredDB
 	tree    *iavl.MutableTree
 	version int64
 	closed  bool
+
+	// pruning selects the versions retained for historical reads
+	pruning PruningOptions
+
+	// prunedTo is the highest version known to be deleted
+	prunedTo int64
+
+	// pruneStats accumulates the pruning done since the store was opened
+	pruneStats PruningStats
+
+	// pruneWake, pruneQuit and pruneDone drive the background pruner (nil
+	// without background pruning)
+	pruneWake chan struct{}
+	pruneQuit chan struct{}
+	pruneDone chan struct{}
+
+	// wal logs each changeset before its version is saved (nil disables it)
+	wal *WAL
+
+	// pending holds the writes since the last save, for the WAL and the
+	// commit hooks
+	pending []walOp
+
+	// commitHooks observe each saved version's writes
+	commitHooks []CommitHook
+}
+
+// IAVLOption configures an IAVLStore
+type IAVLOption func(*IAVLStore)
+
+// WithKeepRecent retains only the n most recent versions; older versions are
+// pruned when a new version is saved. n = 0 (the default) keeps every version.
+// It is shorthand for WithPruning with PruneKeepRecent.
+func WithKeepRecent(n int64) IAVLOption {
+	return func(s *IAVLStore) {
+		if n > 0 {
+			s.pruning = PruningOptions{Strategy: PruneKeepRecent, KeepRecent: n}
+		}
+	}
 }
 
 // NewIAVLStore creates a new IAVL-backed store
 // db is the underlying database (can be nil for in-memory)
 // cacheSize is the IAVL tree cache size (0 means no cache)
-func NewIAVLStore(db dbm.DB, cacheSize int) (*IAVLStore, error) {
+func NewIAVLStore(db dbm.DB, cacheSize int, opts ...IAVLOption) (*IAVLStore, error) {
 	if db == nil {
 		return nil, fmt.Errorf("database cannot be nil")
 	}
@@ -32,7 +72,9 @@ func NewIAVLStore(db dbm.DB, cacheSize int) (*IAVLStore, error) {
 	// Create a no-op logger
 	logger := log.NewNopLogger()
 
-	tree := iavl.NewMutableTree(db, cacheSize, false, logger)
+	// Metering deletes reports the space reclaimed by pruning
+	metered := &meteredDB{DB: db}
+	tree := iavl.NewMutableTree(metered, cacheSize, false, logger)
 
 	// Load latest version if exists
 	version, err := tree.Load()
@@ -40,11 +82,29 @@ func NewIAVLStore(db dbm.DB, cacheSize int) (*IAVLStore, error) {
 		return nil, fmt.Errorf("failed to load tree: %w", err)
 	}
 
-	return &IAVLStore{
+	s := &IAVLStore{
+		db:      metered,
 		tree:    tree,
 		version: version,
 		closed:  false,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if err := s.pruning.Validate(); err != nil {
+		return nil, err
+	}
+
+	if versions := tree.AvailableVersions(); version > 0 && len(versions) > 0 {
+		s.prunedTo = int64(versions[0]) - 1
+	}
+	if s.pruning.Background && s.pruning.Strategy != PruneKeepAll {
+		s.pruneWake = make(chan struct{}, 1)
+		s.pruneQuit = make(chan struct{})
+		s.pruneDone = make(chan struct{})
+		go s.runPruner()
+	}
+	return s, nil
 }
 
 // Get retrieves raw bytes by key
@@ -104,13 +164,7 @@ func (s *IAVLStore) Set(key []byte, value []byte) error {
 	valueCopy := make([]byte, len(value))
 	copy(valueCopy, value)
 
-	// Set in tree
-	_, err := s.tree.Set(keyCopy, valueCopy)
-	if err != nil {
-		return fmt.Errorf("failed to set key: %w", err)
-	}
-
-	return nil
+	return s.applyLocked(keyCopy, valueCopy)
 }
 
 // Delete removes a key
@@ -130,12 +184,29 @@ func (s *IAVLStore) Delete(key []byte) error {
 		return fmt.Errorf("store is closed")
 	}
 
-	// Delete from tree
-	_, _, err := s.tree.Remove(key)
-	if err != nil {
-		return fmt.Errorf("failed to delete key: %w", err)
+	keyCopy := make([]byte, len(key))
+	copy(keyCopy, key)
+
+	return s.applyLocked(keyCopy, nil)
+}
+
+// applyLocked writes value at key, or deletes key if value is nil, and
+// records the write for the WAL and the commit hooks.
+//
+// PRECONDITION: s.mu is held for writing and key and value are not shared
+// with the caller.
+func (s *IAVLStore) applyLocked(key, value []byte) error {
+	if value == nil {
+		if _, _, err := s.tree.Remove(key); err != nil {
+			return fmt.Errorf("failed to delete key: %w", err)
+		}
+	} else if _, err := s.tree.Set(key, value); err != nil {
+		return fmt.Errorf("failed to set key: %w", err)
 	}
 
+	if s.tracksPending() {
+		s.pending = append(s.pending, walOp{key: key, value: value})
+	}
 	return nil
 }
 
@@ -222,16 +293,9 @@ func (s *IAVLStore) Flush() error {
 		return fmt.Errorf("store is closed")
 	}
 
-	// Save new version
-	hash, version, err := s.tree.SaveVersion()
-	if err != nil {
-		return fmt.Errorf("failed to save version: %w", err)
-	}
-
-	s.version = version
-	_ = hash // Hash is available for merkle proofs
-
-	return nil
+	// Save new version (hash is available for merkle proofs)
+	_, err := s.saveVersionLocked()
+	return err
 }
 
 // Close releases resources
@@ -241,14 +305,24 @@ func (s *IAVLStore) Close() error {
 	}
 
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	if s.closed {
+		s.mu.Unlock()
 		return nil
 	}
-
 	s.closed = true
+	s.pending = nil
+	s.mu.Unlock()
+
+	// Wait for the background pruner outside the lock it takes
+	if s.pruneQuit != nil {
+		close(s.pruneQuit)
+		<-s.pruneDone
+	}
+
 	// IAVL tree doesn't have a Close method, but we mark store as closed
+	if s.wal != nil {
+		return s.wal.Close()
+	}
 	return nil
 }
 
@@ -266,12 +340,11 @@ func (s *IAVLStore) SaveVersion() ([]byte, int64, error) {
 		return nil, 0, fmt.Errorf("store is closed")
 	}
 
-	hash, version, err := s.tree.SaveVersion()
+	hash, err := s.saveVersionLocked()
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to save version: %w", err)
+		return nil, 0, err
 	}
-
-	s.version = version
+	version := s.version
 
 	// Return defensive copy of hash
 	hashCopy := make([]byte, len(hash))
@@ -280,6 +353,167 @@ func (s *IAVLStore) SaveVersion() ([]byte, int64, error) {
 	return hashCopy, version, nil
 }
 
+// saveVersionLocked saves a new version and prunes versions outside the
+// retention window, inline or in the background (see PruningOptions).
+// Pruning is best-effort: if it fails (e.g. a version still has active
+// readers) it is retried on the next save, since every save prunes all
+// versions below the window.
+//
+// With a WAL, the changeset is logged before the version is saved and the
+// log is truncated after. A failed truncation is ignored: replay skips
+// changesets whose versions are saved. The commit hooks run once the version
+// is saved.
+//
+// PRECONDITION: s.mu is held for writing.
+func (s *IAVLStore) saveVersionLocked() ([]byte, error) {
+	if s.wal != nil {
+		if err := s.wal.logChangeset(s.tree.WorkingVersion(), s.pending); err != nil {
+			return nil, fmt.Errorf("failed to log changeset: %w", err)
+		}
+	}
+
+	hash, version, err := s.tree.SaveVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to save version: %w", err)
+	}
+	s.version = version
+
+	committed := s.pending
+	s.pending = nil
+	if s.wal != nil {
+		_ = s.wal.reset()
+	}
+	s.runCommitHooks(version, committed)
+
+	s.schedulePrune()
+	return hash, nil
+}
+
+// EarliestVersion returns the oldest version readable with ReadAt
+// (0 if no version has been saved)
+func (s *IAVLStore) EarliestVersion() int64 {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.earliestVersionLocked()
+}
+
+// earliestVersionLocked returns the start of the retention window.
+// PRECONDITION: s.mu is held.
+func (s *IAVLStore) earliestVersionLocked() int64 {
+	if s.version == 0 {
+		return 0
+	}
+	return max(s.pruning.PruneTarget(s.version), s.prunedTo) + 1
+}
+
+// ReadAt returns a read-only view of the store at a saved version.
+// Returns ErrVersionNotAvailable (wrapped) if the version was never saved or
+// is outside the retention window. The view stays valid until the version
+// is pruned.
+func (s *IAVLStore) ReadAt(version int64) (BackingStore, error) {
+	if s == nil {
+		return nil, ErrStoreNil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return nil, fmt.Errorf("store is closed")
+	}
+
+	earliest := s.earliestVersionLocked()
+	if version < 1 || version > s.version || version < earliest {
+		return nil, fmt.Errorf("%w: version %d (available %d to %d)", ErrVersionNotAvailable, version, earliest, s.version)
+	}
+	if !s.tree.VersionExists(version) {
+		return nil, fmt.Errorf("%w: version %d was pruned", ErrVersionNotAvailable, version)
+	}
+
+	tree, err := s.tree.GetImmutable(version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load version %d: %w", version, err)
+	}
+	return &iavlVersionStore{tree: tree}, nil
+}
+
+// iavlVersionStore is a read-only BackingStore over a saved IAVL version
+type iavlVersionStore struct {
+	tree *iavl.ImmutableTree
+}
+
+// Get retrieves raw bytes by key
+func (s *iavlVersionStore) Get(key []byte) ([]byte, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	value, err := s.tree.Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key: %w", err)
+	}
+	if value == nil {
+		return nil, ErrNotFound
+	}
+	return value, nil
+}
+
+// Set is not supported
+func (s *iavlVersionStore) Set(key []byte, value []byte) error {
+	return ErrReadOnly
+}
+
+// Delete is not supported
+func (s *iavlVersionStore) Delete(key []byte) error {
+	return ErrReadOnly
+}
+
+// Has checks if a key exists
+func (s *iavlVersionStore) Has(key []byte) (bool, error) {
+	if err := validateKey(key); err != nil {
+		return false, err
+	}
+
+	has, err := s.tree.Has(key)
+	if err != nil {
+		return false, fmt.Errorf("failed to check key: %w", err)
+	}
+	return has, nil
+}
+
+// Iterator returns an iterator over a range of keys
+func (s *iavlVersionStore) Iterator(start, end []byte) (RawIterator, error) {
+	iter, err := s.tree.Iterator(start, end, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	return newIAVLIterator(iter, false), nil
+}
+
+// ReverseIterator returns a reverse iterator over a range of keys
+func (s *iavlVersionStore) ReverseIterator(start, end []byte) (RawIterator, error) {
+	iter, err := s.tree.Iterator(start, end, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	return newIAVLIterator(iter, true), nil
+}
+
+// Flush is a no-op; the view has no pending writes
+func (s *iavlVersionStore) Flush() error {
+	return nil
+}
+
+// Close is a no-op; the underlying tree is owned by the IAVLStore
+func (s *iavlVersionStore) Close() error {
+	return nil
+}
+
 // LoadVersion loads a specific version of the tree
 // Note: For MutableTree, this loads the version as a base, but the tree
 // remains mutable and will continue from the latest version when saved.
@@ -302,8 +536,9 @@ func (s *IAVLStore) LoadVersion(version int64) error {
 		return fmt.Errorf("failed to load version %d: %w", version, err)
 	}
 
-	// Update to the loaded version
+	// Update to the loaded version; unsaved writes were discarded
 	s.version = v
+	s.pending = nil
 	return nil
 }
 
@@ -333,6 +568,31 @@ func (s *IAVLStore) GetProof(key []byte) (*ics23.CommitmentProof, error) {
 	return proof, nil
 }
 
+// GetVersionedProof generates a merkle proof for a key at a saved version
+func (s *IAVLStore) GetVersionedProof(key []byte, version int64) (*ics23.CommitmentProof, error) {
+	if s == nil {
+		return nil, ErrStoreNil
+	}
+
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return nil, fmt.Errorf("store is closed")
+	}
+
+	proof, err := s.tree.GetVersionedProof(key, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proof at version %d: %w", version, err)
+	}
+
+	return proof, nil
+}
+
 // Version returns the current version number
 func (s *IAVLStore) Version() int64 {
 	if s == nil {
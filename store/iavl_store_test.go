@@ -946,3 +946,83 @@ func BenchmarkIAVLStoreSaveVersion(b *testing.B) {
 		_ = store.Set(key, value)
 	}
 }
+
+// TestIAVLStoreReadAt tests reads pinned to saved versions
+func TestIAVLStoreReadAt(t *testing.T) {
+	store, err := NewIAVLStore(NewMemDB(), 0)
+	require.NoError(t, err)
+
+	_, err = store.ReadAt(1)
+	require.ErrorIs(t, err, ErrVersionNotAvailable)
+
+	for v := 1; v <= 3; v++ {
+		require.NoError(t, store.Set([]byte("key"), []byte(fmt.Sprintf("v%d", v))))
+		require.NoError(t, store.Set([]byte(fmt.Sprintf("k%d", v)), []byte("x")))
+		_, _, err := store.SaveVersion()
+		require.NoError(t, err)
+	}
+
+	// Uncommitted writes are not visible in saved versions
+	require.NoError(t, store.Set([]byte("key"), []byte("pending")))
+
+	for v := int64(1); v <= 3; v++ {
+		view, err := store.ReadAt(v)
+		require.NoError(t, err)
+
+		value, err := view.Get([]byte("key"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte(fmt.Sprintf("v%d", v)), value)
+
+		iter, err := view.Iterator([]byte("k1"), []byte("k9"))
+		require.NoError(t, err)
+		count := int64(0)
+		for ; iter.Valid(); iter.Next() {
+			count++
+		}
+		require.NoError(t, iter.Close())
+		assert.Equal(t, v, count)
+
+		has, err := view.Has([]byte("k3"))
+		require.NoError(t, err)
+		assert.Equal(t, v == 3, has)
+
+		assert.ErrorIs(t, view.Set([]byte("key"), []byte("x")), ErrReadOnly)
+		assert.ErrorIs(t, view.Delete([]byte("key")), ErrReadOnly)
+	}
+
+	view, err := store.ReadAt(1)
+	require.NoError(t, err)
+	_, err = view.Get([]byte("k3"))
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	for _, v := range []int64{0, -1, 4} {
+		_, err := store.ReadAt(v)
+		assert.ErrorIs(t, err, ErrVersionNotAvailable, "version %d", v)
+	}
+}
+
+// TestIAVLStoreKeepRecent tests the retention window
+func TestIAVLStoreKeepRecent(t *testing.T) {
+	store, err := NewIAVLStore(NewMemDB(), 0, WithKeepRecent(2))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), store.EarliestVersion())
+
+	for v := 1; v <= 4; v++ {
+		require.NoError(t, store.Set([]byte("key"), []byte(fmt.Sprintf("v%d", v))))
+		require.NoError(t, store.Flush())
+	}
+
+	assert.Equal(t, int64(3), store.EarliestVersion())
+
+	for _, v := range []int64{1, 2} {
+		_, err := store.ReadAt(v)
+		assert.ErrorIs(t, err, ErrVersionNotAvailable, "version %d", v)
+	}
+	for _, v := range []int64{3, 4} {
+		view, err := store.ReadAt(v)
+		require.NoError(t, err)
+		value, err := view.Get([]byte("key"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte(fmt.Sprintf("v%d", v)), value)
+	}
+}
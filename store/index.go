@@ -0,0 +1,399 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// ErrIndexInconsistent is returned by CheckIndex when an index does not match
+// its primary key space
+var ErrIndexInconsistent = errors.New("index inconsistent")
+
+// IndexFunc derives the index keys of a primary entry. Returning no keys
+// leaves the entry unindexed; an error fails the primary write.
+//
+// INVARIANT: IndexFunc must be deterministic and depend only on its
+// arguments, or index entries of old values cannot be found and removed.
+type IndexFunc func(key, value []byte) ([][]byte, error)
+
+// Index is a secondary index of a primary key space: for each primary entry
+// it stores one entry per derived index key, in a separate key space.
+//
+// Entries are keyed len(indexKey) (2 bytes, big-endian) || indexKey ||
+// primaryKey and hold the primary key, so all primary keys of an index key
+// are adjacent and in primary key order. The length prefix keeps an index
+// key from matching the entries of a longer index key it is a prefix of.
+type Index struct {
+	// name identifies the index in errors
+	name string
+
+	// store is the index key space
+	store BackingStore
+
+	// fn derives index keys from primary entries
+	fn IndexFunc
+}
+
+// NewIndex creates an index named name whose entries live in indexStore.
+//
+// PRECONDITION: indexStore is used by this index only and does not overlap
+// the primary key space; IndexedStore or RebuildIndex is its only writer.
+func NewIndex(name string, indexStore BackingStore, fn IndexFunc) (*Index, error) {
+	if name == "" {
+		return nil, fmt.Errorf("index name cannot be empty")
+	}
+	if indexStore == nil {
+		return nil, ErrStoreNil
+	}
+	if fn == nil {
+		return nil, fmt.Errorf("index function cannot be nil")
+	}
+
+	return &Index{name: name, store: indexStore, fn: fn}, nil
+}
+
+// Name returns the index name
+func (idx *Index) Name() string {
+	return idx.name
+}
+
+// entryPrefix returns the prefix of the entries of indexKey
+func entryPrefix(indexKey []byte) ([]byte, error) {
+	if len(indexKey) == 0 || len(indexKey) > math.MaxUint16 {
+		return nil, fmt.Errorf("%w: index key length %d must be 1 to %d", ErrInvalidKey, len(indexKey), math.MaxUint16)
+	}
+	prefix := make([]byte, 2, 2+len(indexKey))
+	binary.BigEndian.PutUint16(prefix, uint16(len(indexKey)))
+	return append(prefix, indexKey...), nil
+}
+
+// entryKey returns the key of the entry of primaryKey under indexKey
+func entryKey(indexKey, primaryKey []byte) ([]byte, error) {
+	prefix, err := entryPrefix(indexKey)
+	if err != nil {
+		return nil, err
+	}
+	return append(prefix, primaryKey...), nil
+}
+
+// entryKeys returns the sorted, deduplicated entry keys of a primary entry
+func (idx *Index) entryKeys(key, value []byte) ([][]byte, error) {
+	indexKeys, err := idx.fn(key, value)
+	if err != nil {
+		return nil, fmt.Errorf("index %s: %w", idx.name, err)
+	}
+
+	entries := make([][]byte, 0, len(indexKeys))
+	for _, indexKey := range indexKeys {
+		entry, err := entryKey(indexKey, key)
+		if err != nil {
+			return nil, fmt.Errorf("index %s: %w", idx.name, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return bytes.Compare(entries[i], entries[j]) < 0 })
+	unique := entries[:0]
+	for i, entry := range entries {
+		if i == 0 || !bytes.Equal(entry, entries[i-1]) {
+			unique = append(unique, entry)
+		}
+	}
+	return unique, nil
+}
+
+// update replaces the entries of key for oldValue (nil if absent) with those
+// for newValue (nil if deleted). Entries common to both are left untouched.
+func (idx *Index) update(key, oldValue, newValue []byte) error {
+	var oldEntries, newEntries [][]byte
+	var err error
+	if oldValue != nil {
+		if oldEntries, err = idx.entryKeys(key, oldValue); err != nil {
+			return err
+		}
+	}
+	if newValue != nil {
+		if newEntries, err = idx.entryKeys(key, newValue); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range oldEntries {
+		if containsKey(newEntries, entry) {
+			continue
+		}
+		if err := idx.store.Delete(entry); err != nil && !errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("index %s: failed to delete entry: %w", idx.name, err)
+		}
+	}
+	for _, entry := range newEntries {
+		if containsKey(oldEntries, entry) {
+			continue
+		}
+		if err := idx.store.Set(entry, key); err != nil {
+			return fmt.Errorf("index %s: failed to write entry: %w", idx.name, err)
+		}
+	}
+	return nil
+}
+
+// containsKey reports whether sorted keys contains key
+func containsKey(keys [][]byte, key []byte) bool {
+	i := sort.Search(len(keys), func(i int) bool { return bytes.Compare(keys[i], key) >= 0 })
+	return i < len(keys) && bytes.Equal(keys[i], key)
+}
+
+// Iterate calls fn with each primary key indexed under indexKey, in primary
+// key order, until fn returns an error
+//
+// Complexity: O(log n + k) for k matching entries
+func (idx *Index) Iterate(indexKey []byte, fn func(primaryKey []byte) error) error {
+	prefix, err := entryPrefix(indexKey)
+	if err != nil {
+		return err
+	}
+
+	iter, err := NewPrefixStore(idx.store, prefix).Iterator(nil, nil)
+	if err != nil {
+		return fmt.Errorf("index %s: failed to iterate: %w", idx.name, err)
+	}
+	defer iter.Close()
+
+	for ; iter.Valid(); iter.Next() {
+		if err := fn(iter.Value()); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+// Lookup returns the primary keys indexed under indexKey, in primary key order
+func (idx *Index) Lookup(indexKey []byte) ([][]byte, error) {
+	var keys [][]byte
+	err := idx.Iterate(indexKey, func(primaryKey []byte) error {
+		keys = append(keys, append([]byte(nil), primaryKey...))
+		return nil
+	})
+	return keys, err
+}
+
+// IndexedStore is a BackingStore over a primary key space that keeps its
+// indexes in sync: each Set and Delete updates the index entries of the old
+// and new value before writing the primary key.
+//
+// SECURITY: A primary write and its index writes are separate store writes.
+// Wrap the parent of the primary and index stores in a BufferedStore (or
+// another transactional layer) so they commit together; use CheckIndex and
+// RebuildIndex to detect and repair an index after a partial write.
+type IndexedStore struct {
+	// mu serializes writes, whose read-modify-write of index entries must
+	// not interleave
+	mu      sync.Mutex
+	primary BackingStore
+	indexes []*Index
+}
+
+// NewIndexedStore returns primary with indexes kept in sync with its writes
+func NewIndexedStore(primary BackingStore, indexes ...*Index) (*IndexedStore, error) {
+	if primary == nil {
+		return nil, ErrStoreNil
+	}
+	for i, idx := range indexes {
+		if idx == nil {
+			return nil, fmt.Errorf("index %d is nil", i)
+		}
+	}
+
+	return &IndexedStore{primary: primary, indexes: indexes}, nil
+}
+
+// Indexes returns the maintained indexes
+func (s *IndexedStore) Indexes() []*Index {
+	return append([]*Index(nil), s.indexes...)
+}
+
+// current returns the primary value of key, or nil if absent
+func (s *IndexedStore) current(key []byte) ([]byte, error) {
+	value, err := s.primary.Get(key)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	return value, err
+}
+
+// Get retrieves raw bytes by key
+func (s *IndexedStore) Get(key []byte) ([]byte, error) {
+	return s.primary.Get(key)
+}
+
+// Set stores value at key and updates the index entries
+func (s *IndexedStore) Set(key []byte, value []byte) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	if value == nil {
+		value = []byte{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, err := s.current(key)
+	if err != nil {
+		return err
+	}
+	for _, idx := range s.indexes {
+		if err := idx.update(key, old, value); err != nil {
+			return err
+		}
+	}
+	return s.primary.Set(key, value)
+}
+
+// Delete removes key and its index entries
+func (s *IndexedStore) Delete(key []byte) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, err := s.current(key)
+	if err != nil {
+		return err
+	}
+	if old != nil {
+		for _, idx := range s.indexes {
+			if err := idx.update(key, old, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return s.primary.Delete(key)
+}
+
+// Has checks if a key exists
+func (s *IndexedStore) Has(key []byte) (bool, error) {
+	return s.primary.Has(key)
+}
+
+// Iterator returns an iterator over a range of primary keys
+func (s *IndexedStore) Iterator(start, end []byte) (RawIterator, error) {
+	return s.primary.Iterator(start, end)
+}
+
+// ReverseIterator returns a reverse iterator over a range of primary keys
+func (s *IndexedStore) ReverseIterator(start, end []byte) (RawIterator, error) {
+	return s.primary.ReverseIterator(start, end)
+}
+
+// Flush flushes the primary and index stores
+func (s *IndexedStore) Flush() error {
+	if err := s.primary.Flush(); err != nil {
+		return err
+	}
+	for _, idx := range s.indexes {
+		if err := idx.store.Flush(); err != nil {
+			return fmt.Errorf("index %s: %w", idx.name, err)
+		}
+	}
+	return nil
+}
+
+// Close closes the primary store. Index stores are views the caller owns.
+func (s *IndexedStore) Close() error {
+	return s.primary.Close()
+}
+
+// RebuildIndex deletes every entry of idx and re-derives them from primary
+//
+// Complexity: O(n + m) for n primary entries and m index entries
+func RebuildIndex(primary BackingStore, idx *Index) error {
+	if primary == nil || idx == nil {
+		return ErrStoreNil
+	}
+
+	// Collect before deleting: not every store supports writes during iteration
+	var stale [][]byte
+	if err := iterateRaw(idx.store, func(key, _ []byte) error {
+		stale = append(stale, append([]byte(nil), key...))
+		return nil
+	}); err != nil {
+		return fmt.Errorf("index %s: %w", idx.name, err)
+	}
+	for _, key := range stale {
+		if err := idx.store.Delete(key); err != nil && !errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("index %s: failed to delete entry: %w", idx.name, err)
+		}
+	}
+
+	return iterateRaw(primary, func(key, value []byte) error {
+		return idx.update(key, nil, value)
+	})
+}
+
+// CheckIndex reports whether the entries of idx are exactly those derived
+// from primary, returning ErrIndexInconsistent with the number of missing
+// and extra entries otherwise
+//
+// Complexity: O(n + m) time and O(m) memory for n primary entries and m
+// index entries
+func CheckIndex(primary BackingStore, idx *Index) error {
+	if primary == nil || idx == nil {
+		return ErrStoreNil
+	}
+
+	expected := make(map[string][]byte)
+	if err := iterateRaw(primary, func(key, value []byte) error {
+		entries, err := idx.entryKeys(key, value)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			expected[string(entry)] = append([]byte(nil), key...)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	var extra int
+	if err := iterateRaw(idx.store, func(key, value []byte) error {
+		primaryKey, ok := expected[string(key)]
+		if !ok || !bytes.Equal(primaryKey, value) {
+			extra++
+			return nil
+		}
+		delete(expected, string(key))
+		return nil
+	}); err != nil {
+		return fmt.Errorf("index %s: %w", idx.name, err)
+	}
+
+	if missing := len(expected); missing > 0 || extra > 0 {
+		return fmt.Errorf("%w: index %s has %d missing and %d extra entries", ErrIndexInconsistent, idx.name, missing, extra)
+	}
+	return nil
+}
+
+// iterateRaw calls fn with every entry of s in key order
+func iterateRaw(s BackingStore, fn func(key, value []byte) error) error {
+	iter, err := s.Iterator(nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to iterate: %w", err)
+	}
+	defer iter.Close()
+
+	for ; iter.Valid(); iter.Next() {
+		if err := fn(iter.Key(), iter.Value()); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
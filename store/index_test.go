@@ -0,0 +1,192 @@
+package store
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// tagIndex indexes "name" → "tag1,tag2" entries by each tag
+func tagIndex(_, value []byte) ([][]byte, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+	var keys [][]byte
+	for _, tag := range strings.Split(string(value), ",") {
+		keys = append(keys, []byte(tag))
+	}
+	return keys, nil
+}
+
+func setupIndexedStore(t *testing.T) (*IndexedStore, *Index, BackingStore) {
+	t.Helper()
+
+	backing := NewMemoryStore()
+	idx, err := NewIndex("tags", NewPrefixStore(backing, []byte("i/")), tagIndex)
+	if err != nil {
+		t.Fatalf("NewIndex failed: %v", err)
+	}
+	primary := NewPrefixStore(backing, []byte("p/"))
+	s, err := NewIndexedStore(primary, idx)
+	if err != nil {
+		t.Fatalf("NewIndexedStore failed: %v", err)
+	}
+	return s, idx, primary
+}
+
+func lookupStrings(t *testing.T, idx *Index, indexKey string) []string {
+	t.Helper()
+
+	keys, err := idx.Lookup([]byte(indexKey))
+	if err != nil {
+		t.Fatalf("Lookup(%s) failed: %v", indexKey, err)
+	}
+	result := make([]string, len(keys))
+	for i, key := range keys {
+		result[i] = string(key)
+	}
+	return result
+}
+
+func TestIndexedStore_SetAndDelete(t *testing.T) {
+	s, idx, _ := setupIndexedStore(t)
+
+	for key, tags := range map[string]string{"a": "red,blue", "b": "blue", "c": "green,green"} {
+		if err := s.Set([]byte(key), []byte(tags)); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"blue", "a,b"},
+		{"red", "a"},
+		{"green", "c"},
+		{"gree", ""},
+		{"yellow", ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(lookupStrings(t, idx, tt.tag), ","); got != tt.want {
+			t.Errorf("Lookup(%s) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+
+	// Overwriting moves entries from the old tags to the new ones
+	if err := s.Set([]byte("a"), []byte("blue,yellow")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if got := lookupStrings(t, idx, "red"); len(got) != 0 {
+		t.Errorf("Lookup(red) = %v, want none", got)
+	}
+	if got := strings.Join(lookupStrings(t, idx, "yellow"), ","); got != "a" {
+		t.Errorf("Lookup(yellow) = %q, want a", got)
+	}
+
+	if err := s.Delete([]byte("b")); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if got := strings.Join(lookupStrings(t, idx, "blue"), ","); got != "a" {
+		t.Errorf("Lookup(blue) = %q, want a", got)
+	}
+	if _, err := s.Get([]byte("b")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(b) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestIndexedStore_IndexFuncError(t *testing.T) {
+	backing := NewMemoryStore()
+	idx, err := NewIndex("failing", NewPrefixStore(backing, []byte("i/")), func(_, value []byte) ([][]byte, error) {
+		if string(value) == "bad" {
+			return nil, errors.New("bad value")
+		}
+		return [][]byte{value}, nil
+	})
+	if err != nil {
+		t.Fatalf("NewIndex failed: %v", err)
+	}
+	primary := NewPrefixStore(backing, []byte("p/"))
+	s, err := NewIndexedStore(primary, idx)
+	if err != nil {
+		t.Fatalf("NewIndexedStore failed: %v", err)
+	}
+
+	if err := s.Set([]byte("k"), []byte("bad")); err == nil {
+		t.Fatal("Set with failing index function succeeded")
+	}
+	if has, _ := primary.Has([]byte("k")); has {
+		t.Fatal("primary written despite index error")
+	}
+}
+
+func TestCheckAndRebuildIndex(t *testing.T) {
+	s, idx, primary := setupIndexedStore(t)
+
+	for key, tags := range map[string]string{"a": "red,blue", "b": "blue"} {
+		if err := s.Set([]byte(key), []byte(tags)); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+	if err := CheckIndex(primary, idx); err != nil {
+		t.Fatalf("CheckIndex failed: %v", err)
+	}
+
+	// Writes bypassing the indexed store leave the index stale
+	if err := primary.Set([]byte("c"), []byte("green")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := primary.Delete([]byte("a")); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	err := CheckIndex(primary, idx)
+	if !errors.Is(err, ErrIndexInconsistent) {
+		t.Fatalf("CheckIndex error = %v, want ErrIndexInconsistent", err)
+	}
+	if !strings.Contains(err.Error(), "1 missing and 2 extra") {
+		t.Errorf("CheckIndex error = %v, want 1 missing and 2 extra entries", err)
+	}
+
+	if err := RebuildIndex(primary, idx); err != nil {
+		t.Fatalf("RebuildIndex failed: %v", err)
+	}
+	if err := CheckIndex(primary, idx); err != nil {
+		t.Fatalf("CheckIndex after rebuild failed: %v", err)
+	}
+	if got := strings.Join(lookupStrings(t, idx, "blue"), ","); got != "b" {
+		t.Errorf("Lookup(blue) = %q, want b", got)
+	}
+	if got := strings.Join(lookupStrings(t, idx, "green"), ","); got != "c" {
+		t.Errorf("Lookup(green) = %q, want c", got)
+	}
+}
+
+func TestNewIndex_Invalid(t *testing.T) {
+	backing := NewMemoryStore()
+	if _, err := NewIndex("", backing, tagIndex); err == nil {
+		t.Error("expected error with empty name")
+	}
+	if _, err := NewIndex("tags", nil, tagIndex); err == nil {
+		t.Error("expected error with nil store")
+	}
+	if _, err := NewIndex("tags", backing, nil); err == nil {
+		t.Error("expected error with nil function")
+	}
+	if _, err := NewIndexedStore(backing, nil); err == nil {
+		t.Error("expected error with nil index")
+	}
+}
+
+func TestBalanceDenomIndex(t *testing.T) {
+	keys, err := BalanceDenomIndex(BalanceKey("alice", "ibc/27394FB0"), nil)
+	if err != nil {
+		t.Fatalf("BalanceDenomIndex failed: %v", err)
+	}
+	if len(keys) != 1 || string(keys[0]) != "ibc/27394FB0" {
+		t.Fatalf("BalanceDenomIndex = %q, want [ibc/27394FB0]", keys)
+	}
+
+	if _, err := BalanceDenomIndex([]byte("alice"), nil); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("BalanceDenomIndex error = %v, want ErrInvalidKey", err)
+	}
+}
@@ -2,22 +2,48 @@ package store
 
 import (
 	"bytes"
+	"hash/maphash"
 	"sort"
 	"sync"
 )
 
-// MemoryStore is a simple in-memory backing store
+// memoryStoreShards is the number of independently locked shards of a
+// MemoryStore (a power of two)
+const memoryStoreShards = 32
+
+// MemoryStore is a simple in-memory backing store.
+//
+// Keys are spread over memoryStoreShards shards by hash, each with its own
+// lock, so concurrent Get/Set on different keys rarely contend. Iterators
+// read-lock every shard (in index order) to take a consistent snapshot.
+//
 // TODO: Replace with IAVL store for production use
 type MemoryStore struct {
+	seed   maphash.Seed
+	shards [memoryStoreShards]memoryShard
+}
+
+// memoryShard is one lock-protected partition of a MemoryStore
+type memoryShard struct {
 	mu   sync.RWMutex
 	data map[string][]byte
+
+	// Pad to a cache line so neighbouring shard locks do not false-share
+	_ [32]byte
 }
 
 // NewMemoryStore creates a new in-memory store
 func NewMemoryStore() *MemoryStore {
-	return &MemoryStore{
-		data: make(map[string][]byte),
+	ms := &MemoryStore{seed: maphash.MakeSeed()}
+	for i := range ms.shards {
+		ms.shards[i].data = make(map[string][]byte)
 	}
+	return ms
+}
+
+// shard returns the shard holding key
+func (ms *MemoryStore) shard(key []byte) *memoryShard {
+	return &ms.shards[maphash.Bytes(ms.seed, key)&(memoryStoreShards-1)]
 }
 
 // Get retrieves raw bytes by key
@@ -30,10 +56,11 @@ func (ms *MemoryStore) Get(key []byte) ([]byte, error) {
 		return nil, err
 	}
 
-	ms.mu.RLock()
-	defer ms.mu.RUnlock()
+	sh := ms.shard(key)
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
 
-	value, ok := ms.data[string(key)]
+	value, ok := sh.data[string(key)]
 	if !ok {
 		return nil, ErrNotFound
 	}
@@ -54,17 +81,17 @@ func (ms *MemoryStore) Set(key []byte, value []byte) error {
 		return err
 	}
 
-	ms.mu.Lock()
-	defer ms.mu.Unlock()
-
-	// Store defensive copies
-	keyCopy := make([]byte, len(key))
-	copy(keyCopy, key)
-
+	// Store defensive copies; copying before locking keeps the critical
+	// section short
+	keyCopy := string(key)
 	valueCopy := make([]byte, len(value))
 	copy(valueCopy, value)
 
-	ms.data[string(keyCopy)] = valueCopy
+	sh := ms.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	sh.data[keyCopy] = valueCopy
 	return nil
 }
 
@@ -78,10 +105,11 @@ func (ms *MemoryStore) Delete(key []byte) error {
 		return err
 	}
 
-	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	sh := ms.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
 
-	delete(ms.data, string(key))
+	delete(sh.data, string(key))
 	return nil
 }
 
@@ -95,10 +123,11 @@ func (ms *MemoryStore) Has(key []byte) (bool, error) {
 		return false, err
 	}
 
-	ms.mu.RLock()
-	defer ms.mu.RUnlock()
+	sh := ms.shard(key)
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
 
-	_, ok := ms.data[string(key)]
+	_, ok := sh.data[string(key)]
 	return ok, nil
 }
 
@@ -108,8 +137,8 @@ func (ms *MemoryStore) Iterator(start, end []byte) (RawIterator, error) {
 		return nil, ErrStoreNil
 	}
 
-	ms.mu.RLock()
-	defer ms.mu.RUnlock()
+	ms.rlockAll()
+	defer ms.runlockAll()
 
 	return ms.iterator(start, end, false)
 }
@@ -120,50 +149,59 @@ func (ms *MemoryStore) ReverseIterator(start, end []byte) (RawIterator, error) {
 		return nil, ErrStoreNil
 	}
 
-	ms.mu.RLock()
-	defer ms.mu.RUnlock()
+	ms.rlockAll()
+	defer ms.runlockAll()
 
 	return ms.iterator(start, end, true)
 }
 
-// iterator creates an iterator (must be called with lock held)
+// rlockAll read-locks every shard in index order.
+// INVARIANT: Multi-shard locking always follows index order, so it cannot
+// deadlock with other multi-shard or single-shard lockers.
+func (ms *MemoryStore) rlockAll() {
+	for i := range ms.shards {
+		ms.shards[i].mu.RLock()
+	}
+}
+
+// runlockAll releases the locks taken by rlockAll
+func (ms *MemoryStore) runlockAll() {
+	for i := range ms.shards {
+		ms.shards[i].mu.RUnlock()
+	}
+}
+
+// iterator creates an iterator (must be called with all shards read-locked)
 func (ms *MemoryStore) iterator(start, end []byte, reverse bool) (RawIterator, error) {
-	// Collect and sort keys
-	keys := make([]string, 0, len(ms.data))
-	for key := range ms.data {
-		keyBytes := []byte(key)
-
-		// Check if key is in range
-		if start != nil && bytes.Compare(keyBytes, start) < 0 {
-			continue
+	// Collect in-range entries and sort them by key
+	var items []kvPair
+	for i := range ms.shards {
+		for key, value := range ms.shards[i].data {
+			keyBytes := []byte(key)
+
+			// Check if key is in range
+			if start != nil && bytes.Compare(keyBytes, start) < 0 {
+				continue
+			}
+			if end != nil && bytes.Compare(keyBytes, end) >= 0 {
+				continue
+			}
+
+			// Create defensive copy of value to prevent external mutation
+			valueCopy := make([]byte, len(value))
+			copy(valueCopy, value)
+			items = append(items, kvPair{key: keyBytes, value: valueCopy})
 		}
-		if end != nil && bytes.Compare(keyBytes, end) >= 0 {
-			continue
-		}
-
-		keys = append(keys, key)
 	}
 
-	// Sort keys
-	sort.Strings(keys)
+	sort.Slice(items, func(i, j int) bool {
+		return bytes.Compare(items[i].key, items[j].key) < 0
+	})
 
 	// Reverse if needed
 	if reverse {
-		for i, j := 0, len(keys)-1; i < j; i, j = i+1, j-1 {
-			keys[i], keys[j] = keys[j], keys[i]
-		}
-	}
-
-	// Copy data for iterator with defensive copies
-	items := make([]kvPair, len(keys))
-	for i, key := range keys {
-		value := ms.data[key]
-		// Create defensive copy of value to prevent external mutation
-		valueCopy := make([]byte, len(value))
-		copy(valueCopy, value)
-		items[i] = kvPair{
-			key:   []byte(key),
-			value: valueCopy,
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
 		}
 	}
 
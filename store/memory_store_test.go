@@ -2,6 +2,7 @@ package store
 
 import (
 	"bytes"
+	"fmt"
 	"sync"
 	"testing"
 )
@@ -452,3 +453,174 @@ func BenchmarkMemoryStore_Concurrent(b *testing.B) {
 		}
 	})
 }
+
+func TestMemoryStore_IteratorAcrossShards(t *testing.T) {
+	ms := NewMemoryStore()
+
+	const n = 500
+	for i := 0; i < n; i++ {
+		key := []byte(fmt.Sprintf("key%04d", i))
+		if err := ms.Set(key, key); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+
+	iter, err := ms.Iterator([]byte("key0100"), []byte("key0200"))
+	if err != nil {
+		t.Fatalf("Iterator failed: %v", err)
+	}
+	defer iter.Close()
+
+	count := 0
+	var prev []byte
+	for ; iter.Valid(); iter.Next() {
+		if prev != nil && bytes.Compare(prev, iter.Key()) >= 0 {
+			t.Fatalf("keys out of order: %s before %s", prev, iter.Key())
+		}
+		prev = iter.Key()
+		count++
+	}
+	if count != 100 {
+		t.Fatalf("expected 100 keys, got %d", count)
+	}
+}
+
+func TestMemoryStore_ConcurrentWritersAndIterators(t *testing.T) {
+	ms := NewMemoryStore()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				key := []byte(fmt.Sprintf("w%d/%03d", w, i))
+				if err := ms.Set(key, key); err != nil {
+					t.Errorf("Set failed: %v", err)
+					return
+				}
+			}
+		}(w)
+	}
+	for r := 0; r < 2; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				iter, err := ms.Iterator(nil, nil)
+				if err != nil {
+					t.Errorf("Iterator failed: %v", err)
+					return
+				}
+				for ; iter.Valid(); iter.Next() {
+					if !bytes.Equal(iter.Key(), iter.Value()) {
+						t.Errorf("value mismatch for %s", iter.Key())
+					}
+				}
+				iter.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	iter, err := ms.Iterator(nil, nil)
+	if err != nil {
+		t.Fatalf("Iterator failed: %v", err)
+	}
+	defer iter.Close()
+	count := 0
+	for ; iter.Valid(); iter.Next() {
+		count++
+	}
+	if count != 800 {
+		t.Fatalf("expected 800 keys, got %d", count)
+	}
+}
+
+// singleLockStore is the previous single-mutex MemoryStore layout, kept as a
+// baseline for the parallel benchmarks
+type singleLockStore struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+}
+
+func (s *singleLockStore) Get(key []byte) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.data[string(key)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	result := make([]byte, len(value))
+	copy(result, value)
+	return result, nil
+}
+
+func (s *singleLockStore) Set(key, value []byte) error {
+	valueCopy := make([]byte, len(value))
+	copy(valueCopy, value)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[string(key)] = valueCopy
+	return nil
+}
+
+// benchmarkParallelStore runs a parallel workload over 1024 keys where
+// writePercent of operations are Sets and the rest Gets
+func benchmarkParallelStore(b *testing.B, newStore func() interface {
+	Get([]byte) ([]byte, error)
+	Set([]byte, []byte) error
+}, writePercent int) {
+	s := newStore()
+	keys := make([][]byte, 1024)
+	for i := range keys {
+		keys[i] = []byte(fmt.Sprintf("account/%04d", i))
+		_ = s.Set(keys[i], keys[i])
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := keys[(i*7919)%len(keys)]
+			if i%100 < writePercent {
+				_ = s.Set(key, key)
+			} else {
+				_, _ = s.Get(key)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkMemoryStore_Parallel(b *testing.B) {
+	stores := []struct {
+		name     string
+		newStore func() interface {
+			Get([]byte) ([]byte, error)
+			Set([]byte, []byte) error
+		}
+	}{
+		{"Sharded", func() interface {
+			Get([]byte) ([]byte, error)
+			Set([]byte, []byte) error
+		} {
+			return NewMemoryStore()
+		}},
+		{"SingleLock", func() interface {
+			Get([]byte) ([]byte, error)
+			Set([]byte, []byte) error
+		} {
+			return &singleLockStore{data: make(map[string][]byte)}
+		}},
+	}
+
+	for _, writePercent := range []int{0, 10, 50} {
+		for _, st := range stores {
+			b.Run(fmt.Sprintf("%s/Writes%d%%", st.name, writePercent), func(b *testing.B) {
+				benchmarkParallelStore(b, st.newStore, writePercent)
+			})
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package store
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	dbm "github.com/cosmos/cosmos-db"
+)
+
+// PruningStrategy selects which saved versions a versioned store retains
+type PruningStrategy int
+
+const (
+	// PruneKeepAll retains every version
+	PruneKeepAll PruningStrategy = iota
+
+	// PruneKeepRecent retains the KeepRecent most recent versions
+	PruneKeepRecent
+
+	// PruneKeepEvery retains the KeepRecent most recent versions and every
+	// version since the last checkpoint (a multiple of KeepEvery) before
+	// them, so the oldest retained version is always a checkpoint height
+	// (e.g. for state sync snapshots taken every KeepEvery heights).
+	//
+	// RATIONALE: IAVL deletes versions only as a prefix of the version
+	// history, so older checkpoints cannot be kept individually.
+	PruneKeepEvery
+)
+
+// String returns the strategy name
+func (p PruningStrategy) String() string {
+	switch p {
+	case PruneKeepAll:
+		return "keep-all"
+	case PruneKeepRecent:
+		return "keep-recent"
+	case PruneKeepEvery:
+		return "keep-every"
+	default:
+		return fmt.Sprintf("PruningStrategy(%d)", int(p))
+	}
+}
+
+// DefaultPruneBatchSize is the default number of versions background pruning
+// deletes per step
+const DefaultPruneBatchSize = 100
+
+// PruningOptions configures version pruning
+type PruningOptions struct {
+	// Strategy selects the retained versions
+	Strategy PruningStrategy
+
+	// KeepRecent is the number of most recent versions retained
+	// (PruneKeepRecent and PruneKeepEvery)
+	KeepRecent int64
+
+	// KeepEvery is the checkpoint interval (PruneKeepEvery)
+	KeepEvery int64
+
+	// Background prunes in a goroutine after each save instead of inside
+	// it, so commits do not wait for deletions
+	Background bool
+
+	// BatchSize is the number of versions background pruning deletes per
+	// step; commits wait for at most one step (default DefaultPruneBatchSize)
+	BatchSize int64
+}
+
+// Validate checks the options
+func (o PruningOptions) Validate() error {
+	switch o.Strategy {
+	case PruneKeepAll:
+	case PruneKeepRecent:
+		if o.KeepRecent < 1 {
+			return fmt.Errorf("pruning %s: keep recent must be positive, got %d", o.Strategy, o.KeepRecent)
+		}
+	case PruneKeepEvery:
+		if o.KeepRecent < 1 {
+			return fmt.Errorf("pruning %s: keep recent must be positive, got %d", o.Strategy, o.KeepRecent)
+		}
+		if o.KeepEvery < 1 {
+			return fmt.Errorf("pruning %s: keep every must be positive, got %d", o.Strategy, o.KeepEvery)
+		}
+	default:
+		return fmt.Errorf("invalid pruning strategy %s", o.Strategy)
+	}
+
+	if o.BatchSize < 0 {
+		return fmt.Errorf("pruning batch size must not be negative, got %d", o.BatchSize)
+	}
+	return nil
+}
+
+// PruneTarget returns the highest version that may be deleted once latest is
+// saved, or 0 if none
+func (o PruningOptions) PruneTarget(latest int64) int64 {
+	switch o.Strategy {
+	case PruneKeepRecent:
+		return max(latest-o.KeepRecent, 0)
+	case PruneKeepEvery:
+		windowStart := latest - o.KeepRecent + 1
+		checkpoint := windowStart / o.KeepEvery * o.KeepEvery
+		return max(checkpoint-1, 0)
+	default:
+		return 0
+	}
+}
+
+// PruningStats reports the pruning done by a store since it was opened
+type PruningStats struct {
+	// PrunedVersions is the number of versions deleted
+	PrunedVersions uint64
+
+	// LastPrunedVersion is the highest version deleted
+	LastPrunedVersion int64
+
+	// DeletedEntries is the number of database entries deleted
+	DeletedEntries uint64
+
+	// ReclaimedBytes is the key and value size of the deleted entries. Disk
+	// space is released when the database compacts.
+	ReclaimedBytes uint64
+
+	// Failures is the number of failed prune attempts (e.g. a version had
+	// active readers); each is retried after the next save
+	Failures uint64
+
+	// LastError is the error of the last failed attempt
+	LastError string
+}
+
+// meteredDB counts the entries deleted through its batches while counting is
+// enabled, to report reclaimed space
+type meteredDB struct {
+	dbm.DB
+
+	counting       atomic.Bool
+	deletedEntries atomic.Uint64
+	deletedBytes   atomic.Uint64
+}
+
+// NewBatch creates a metered batch
+func (db *meteredDB) NewBatch() dbm.Batch {
+	return &meteredBatch{Batch: db.DB.NewBatch(), db: db}
+}
+
+// NewBatchWithSize creates a metered batch with a size hint
+func (db *meteredDB) NewBatchWithSize(size int) dbm.Batch {
+	return &meteredBatch{Batch: db.DB.NewBatchWithSize(size), db: db}
+}
+
+// startCounting resets the counters and enables counting
+func (db *meteredDB) startCounting() {
+	db.deletedEntries.Store(0)
+	db.deletedBytes.Store(0)
+	db.counting.Store(true)
+}
+
+// stopCounting disables counting and returns the counted entries and bytes
+func (db *meteredDB) stopCounting() (uint64, uint64) {
+	db.counting.Store(false)
+	return db.deletedEntries.Load(), db.deletedBytes.Load()
+}
+
+// meteredBatch is a batch of a meteredDB
+type meteredBatch struct {
+	dbm.Batch
+	db *meteredDB
+}
+
+// Delete counts the deleted entry's size before deleting it
+func (b *meteredBatch) Delete(key []byte) error {
+	if b.db.counting.Load() {
+		if value, err := b.db.Get(key); err == nil && value != nil {
+			b.db.deletedEntries.Add(1)
+			b.db.deletedBytes.Add(uint64(len(key) + len(value)))
+		}
+	}
+	return b.Batch.Delete(key)
+}
+
+// WithPruning sets the pruning options (default PruneKeepAll). NewIAVLStore
+// fails if they are invalid.
+func WithPruning(opts PruningOptions) IAVLOption {
+	return func(s *IAVLStore) {
+		s.pruning = opts
+	}
+}
+
+// Pruning returns the configured pruning options
+func (s *IAVLStore) Pruning() PruningOptions {
+	if s == nil {
+		return PruningOptions{}
+	}
+	return s.pruning
+}
+
+// PruningStats returns the pruning done since the store was opened
+func (s *IAVLStore) PruningStats() PruningStats {
+	if s == nil {
+		return PruningStats{}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.pruneStats
+}
+
+// pruneLocked deletes the versions below the retention window, at most limit
+// versions (0 for no limit), and reports whether none are left to delete.
+// Failures are recorded and retried after the next save.
+//
+// PRECONDITION: s.mu is held for writing.
+func (s *IAVLStore) pruneLocked(limit int64) bool {
+	target := s.pruning.PruneTarget(s.version)
+	if target <= s.prunedTo {
+		return true
+	}
+	to := target
+	if limit > 0 && to-s.prunedTo > limit {
+		to = s.prunedTo + limit
+	}
+
+	s.db.startCounting()
+	err := s.tree.DeleteVersionsTo(to)
+	entries, bytes := s.db.stopCounting()
+	if err != nil {
+		s.pruneStats.Failures++
+		s.pruneStats.LastError = err.Error()
+		return true
+	}
+
+	s.pruneStats.PrunedVersions += uint64(to - s.prunedTo)
+	s.pruneStats.LastPrunedVersion = to
+	s.pruneStats.DeletedEntries += entries
+	s.pruneStats.ReclaimedBytes += bytes
+	s.prunedTo = to
+	return to == target
+}
+
+// schedulePrune prunes after a save: inline, or by waking the background
+// pruner without waiting for it.
+//
+// PRECONDITION: s.mu is held for writing.
+func (s *IAVLStore) schedulePrune() {
+	if s.pruning.Strategy == PruneKeepAll {
+		return
+	}
+	if !s.pruning.Background {
+		s.pruneLocked(0)
+		return
+	}
+
+	select {
+	case s.pruneWake <- struct{}{}:
+	default:
+		// A wake-up is already pending
+	}
+}
+
+// runPruner prunes in steps of BatchSize versions, releasing s.mu between
+// steps so commits interleave, until the store closes
+func (s *IAVLStore) runPruner() {
+	defer close(s.pruneDone)
+
+	batch := s.pruning.BatchSize
+	if batch == 0 {
+		batch = DefaultPruneBatchSize
+	}
+
+	for {
+		select {
+		case <-s.pruneQuit:
+			return
+		case <-s.pruneWake:
+		}
+
+		for done := false; !done; {
+			s.mu.Lock()
+			if s.closed {
+				s.mu.Unlock()
+				return
+			}
+			done = s.pruneLocked(batch)
+			s.mu.Unlock()
+		}
+	}
+}
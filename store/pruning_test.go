@@ -0,0 +1,160 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func saveVersions(t *testing.T, s *IAVLStore, n int) {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		v := s.Version() + 1
+		if err := s.Set([]byte(fmt.Sprintf("key%d", v%3)), []byte(fmt.Sprintf("v%d", v))); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if _, _, err := s.SaveVersion(); err != nil {
+			t.Fatalf("SaveVersion failed: %v", err)
+		}
+	}
+}
+
+func TestPruningOptions_PruneTarget(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   PruningOptions
+		latest int64
+		want   int64
+	}{
+		{"keep all", PruningOptions{}, 100, 0},
+		{"keep recent", PruningOptions{Strategy: PruneKeepRecent, KeepRecent: 10}, 100, 90},
+		{"keep recent before window fills", PruningOptions{Strategy: PruneKeepRecent, KeepRecent: 10}, 5, 0},
+		{"keep every", PruningOptions{Strategy: PruneKeepEvery, KeepRecent: 2, KeepEvery: 4}, 10, 7},
+		{"keep every at checkpoint", PruningOptions{Strategy: PruneKeepEvery, KeepRecent: 2, KeepEvery: 4}, 9, 7},
+		{"keep every before checkpoint", PruningOptions{Strategy: PruneKeepEvery, KeepRecent: 2, KeepEvery: 4}, 8, 3},
+		{"keep every before first checkpoint", PruningOptions{Strategy: PruneKeepEvery, KeepRecent: 2, KeepEvery: 4}, 4, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.PruneTarget(tt.latest); got != tt.want {
+				t.Errorf("PruneTarget(%d) = %d, want %d", tt.latest, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPruningOptions_Validate(t *testing.T) {
+	invalid := []PruningOptions{
+		{Strategy: PruneKeepRecent},
+		{Strategy: PruneKeepEvery, KeepRecent: 1},
+		{Strategy: PruneKeepEvery, KeepEvery: 10},
+		{Strategy: PruningStrategy(7)},
+		{Strategy: PruneKeepRecent, KeepRecent: 1, BatchSize: -1},
+	}
+	for _, opts := range invalid {
+		if err := opts.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded", opts)
+		}
+		if _, err := NewIAVLStore(NewMemDB(), 0, WithPruning(opts)); err == nil {
+			t.Errorf("NewIAVLStore with %+v succeeded", opts)
+		}
+	}
+}
+
+func TestIAVLStore_PruneKeepRecent(t *testing.T) {
+	s, err := NewIAVLStore(NewMemDB(), 0, WithPruning(PruningOptions{Strategy: PruneKeepRecent, KeepRecent: 2}))
+	if err != nil {
+		t.Fatalf("NewIAVLStore failed: %v", err)
+	}
+	saveVersions(t, s, 5)
+
+	stats := s.PruningStats()
+	if stats.PrunedVersions != 3 || stats.LastPrunedVersion != 3 {
+		t.Errorf("stats = %+v, want versions 1 to 3 pruned", stats)
+	}
+	if stats.DeletedEntries == 0 || stats.ReclaimedBytes == 0 {
+		t.Errorf("stats = %+v, want reclaimed entries", stats)
+	}
+	if earliest := s.EarliestVersion(); earliest != 4 {
+		t.Errorf("EarliestVersion = %d, want 4", earliest)
+	}
+}
+
+func TestIAVLStore_PruneKeepEvery(t *testing.T) {
+	s, err := NewIAVLStore(NewMemDB(), 0, WithPruning(PruningOptions{Strategy: PruneKeepEvery, KeepRecent: 2, KeepEvery: 4}))
+	if err != nil {
+		t.Fatalf("NewIAVLStore failed: %v", err)
+	}
+	saveVersions(t, s, 10)
+
+	// The window of versions 9 and 10 extends back to checkpoint 8
+	if earliest := s.EarliestVersion(); earliest != 8 {
+		t.Errorf("EarliestVersion = %d, want 8", earliest)
+	}
+	for v := int64(1); v <= 10; v++ {
+		_, err := s.ReadAt(v)
+		switch {
+		case v < 8 && !errors.Is(err, ErrVersionNotAvailable):
+			t.Errorf("ReadAt(%d) error = %v, want ErrVersionNotAvailable", v, err)
+		case v >= 8 && err != nil:
+			t.Errorf("ReadAt(%d) failed: %v", v, err)
+		}
+	}
+	if stats := s.PruningStats(); stats.PrunedVersions != 7 {
+		t.Errorf("pruned %d versions, want 7", stats.PrunedVersions)
+	}
+}
+
+func TestIAVLStore_BackgroundPruning(t *testing.T) {
+	s, err := NewIAVLStore(NewMemDB(), 0, WithPruning(PruningOptions{
+		Strategy:   PruneKeepRecent,
+		KeepRecent: 3,
+		Background: true,
+		BatchSize:  2,
+	}))
+	if err != nil {
+		t.Fatalf("NewIAVLStore failed: %v", err)
+	}
+	saveVersions(t, s, 20)
+
+	// The retention window applies to reads before the pruner catches up
+	if earliest := s.EarliestVersion(); earliest != 18 {
+		t.Errorf("EarliestVersion = %d, want 18", earliest)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for s.PruningStats().PrunedVersions < 17 {
+		if time.Now().After(deadline) {
+			t.Fatalf("background pruning stalled: %+v", s.PruningStats())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if stats := s.PruningStats(); stats.LastPrunedVersion != 17 || stats.Failures != 0 {
+		t.Errorf("stats = %+v, want versions 1 to 17 pruned", stats)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+}
+
+func TestIAVLStore_PruningResumesAfterReopen(t *testing.T) {
+	db := NewMemDB()
+	s, err := NewIAVLStore(db, 0)
+	if err != nil {
+		t.Fatalf("NewIAVLStore failed: %v", err)
+	}
+	saveVersions(t, s, 6)
+	s.Close()
+
+	reopened, err := NewIAVLStore(db, 0, WithKeepRecent(2))
+	if err != nil {
+		t.Fatalf("NewIAVLStore failed: %v", err)
+	}
+	saveVersions(t, reopened, 1)
+	if stats := reopened.PruningStats(); stats.PrunedVersions != 5 || stats.LastPrunedVersion != 5 {
+		t.Errorf("stats = %+v, want versions 1 to 5 pruned", stats)
+	}
+}
@@ -0,0 +1,55 @@
+package store
+
+// ReadOnlyStore is a view of a BackingStore that rejects writes with
+// ErrReadOnly
+type ReadOnlyStore struct {
+	parent BackingStore
+}
+
+// NewReadOnlyStore returns a read-only view of parent
+func NewReadOnlyStore(parent BackingStore) *ReadOnlyStore {
+	if parent == nil {
+		panic("parent store cannot be nil")
+	}
+	return &ReadOnlyStore{parent: parent}
+}
+
+// Get retrieves raw bytes by key
+func (s *ReadOnlyStore) Get(key []byte) ([]byte, error) {
+	return s.parent.Get(key)
+}
+
+// Set is not supported
+func (s *ReadOnlyStore) Set(key []byte, value []byte) error {
+	return ErrReadOnly
+}
+
+// Delete is not supported
+func (s *ReadOnlyStore) Delete(key []byte) error {
+	return ErrReadOnly
+}
+
+// Has checks if a key exists
+func (s *ReadOnlyStore) Has(key []byte) (bool, error) {
+	return s.parent.Has(key)
+}
+
+// Iterator returns an iterator over a range of keys
+func (s *ReadOnlyStore) Iterator(start, end []byte) (RawIterator, error) {
+	return s.parent.Iterator(start, end)
+}
+
+// ReverseIterator returns a reverse iterator over a range of keys
+func (s *ReadOnlyStore) ReverseIterator(start, end []byte) (RawIterator, error) {
+	return s.parent.ReverseIterator(start, end)
+}
+
+// Flush is a no-op; the view has no pending writes
+func (s *ReadOnlyStore) Flush() error {
+	return nil
+}
+
+// Close is a no-op; the parent store is owned by the caller
+func (s *ReadOnlyStore) Close() error {
+	return nil
+}
@@ -20,6 +20,13 @@ var (
 
 	// ErrStoreNil is returned when a store is nil
 	ErrStoreNil = errors.New("store is nil")
+
+	// ErrReadOnly is returned when writing to a read-only store
+	ErrReadOnly = errors.New("store is read-only")
+
+	// ErrVersionNotAvailable is returned when reading a version that was
+	// never saved or is outside the retention window
+	ErrVersionNotAvailable = errors.New("version not available")
 )
 
 // ObjectStore is a typed key-value store interface with caching support
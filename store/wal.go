@@ -0,0 +1,385 @@
+package store
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sync"
+)
+
+// ErrWALMismatch is returned when replaying a WAL whose changesets do not
+// follow the store's saved version
+var ErrWALMismatch = errors.New("wal does not match store")
+
+// WALSyncMode selects when the WAL is fsynced, trading commit latency for
+// durability
+type WALSyncMode int
+
+const (
+	// WALSyncAlways fsyncs every changeset before its version is saved, so a
+	// committed block survives power loss and OS crashes
+	WALSyncAlways WALSyncMode = iota
+
+	// WALSyncInterval fsyncs every SyncInterval-th changeset. A power loss
+	// can lose the log of the last block; the consensus engine then replays it.
+	WALSyncInterval
+
+	// WALSyncNever leaves flushing to the OS. The WAL survives process
+	// crashes but not power loss or OS crashes.
+	WALSyncNever
+)
+
+// String returns the sync mode name
+func (m WALSyncMode) String() string {
+	switch m {
+	case WALSyncAlways:
+		return "always"
+	case WALSyncInterval:
+		return "interval"
+	case WALSyncNever:
+		return "never"
+	default:
+		return fmt.Sprintf("WALSyncMode(%d)", int(m))
+	}
+}
+
+// WAL record types
+const (
+	walRecordSet    byte = 1
+	walRecordDelete byte = 2
+	walRecordCommit byte = 3
+)
+
+// walCRCTable is the CRC-32C table used for record checksums
+var walCRCTable = crc32.MakeTable(crc32.Castagnoli)
+
+// walOp is a logged write; a nil value is a delete
+type walOp struct {
+	key   []byte
+	value []byte
+}
+
+// walChangeset is the writes of one version
+type walChangeset struct {
+	version int64
+	ops     []walOp
+}
+
+// WAL is a write-ahead log of the changesets committed to an IAVLStore.
+//
+// Before a version is saved, its writes and a commit record are appended
+// (and fsynced per the sync mode); once saved, the log is truncated. After a
+// crash between the two, ReplayWAL re-applies the logged changeset instead
+// of leaving the block half-committed.
+//
+// Each record is type (1 byte) || payload length (4 bytes, big-endian) ||
+// payload || CRC-32C of the preceding bytes (4 bytes). A truncated or
+// corrupt record ends the log: records after the last commit record belong
+// to a changeset that never started saving and are discarded.
+type WAL struct {
+	mu   sync.Mutex
+	file *os.File
+
+	syncMode     WALSyncMode
+	syncInterval int
+
+	// unsynced counts changesets written since the last fsync
+	unsynced int
+}
+
+// WALOption configures a WAL
+type WALOption func(*WAL)
+
+// WithWALSyncMode sets when the WAL is fsynced (default WALSyncAlways)
+func WithWALSyncMode(mode WALSyncMode) WALOption {
+	return func(w *WAL) {
+		w.syncMode = mode
+	}
+}
+
+// WithWALSyncInterval fsyncs every n-th changeset (WALSyncInterval)
+func WithWALSyncInterval(n int) WALOption {
+	return func(w *WAL) {
+		w.syncMode = WALSyncInterval
+		w.syncInterval = n
+	}
+}
+
+// OpenWAL opens or creates the WAL file at path
+func OpenWAL(path string, opts ...WALOption) (*WAL, error) {
+	w := &WAL{syncMode: WALSyncAlways}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	switch w.syncMode {
+	case WALSyncAlways, WALSyncNever:
+	case WALSyncInterval:
+		if w.syncInterval < 1 {
+			return nil, fmt.Errorf("wal sync interval must be positive, got %d", w.syncInterval)
+		}
+	default:
+		return nil, fmt.Errorf("invalid wal sync mode %s", w.syncMode)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open wal: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to open wal: %w", err)
+	}
+	w.file = file
+	return w, nil
+}
+
+// SyncMode returns the configured sync mode
+func (w *WAL) SyncMode() WALSyncMode {
+	return w.syncMode
+}
+
+// appendRecord encodes a record into buf
+func appendRecord(buf []byte, recordType byte, payload []byte) []byte {
+	start := len(buf)
+	buf = append(buf, recordType)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(payload)))
+	buf = append(buf, payload...)
+	return binary.BigEndian.AppendUint32(buf, crc32.Checksum(buf[start:], walCRCTable))
+}
+
+// logChangeset appends the writes of version and its commit record, then
+// fsyncs per the sync mode. The changeset is durable (for the sync mode)
+// when it returns.
+func (w *WAL) logChangeset(version int64, ops []walOp) error {
+	var buf []byte
+	for _, op := range ops {
+		if op.value == nil {
+			buf = appendRecord(buf, walRecordDelete, op.key)
+			continue
+		}
+		payload := binary.AppendUvarint(nil, uint64(len(op.key)))
+		payload = append(payload, op.key...)
+		payload = append(payload, op.value...)
+		buf = appendRecord(buf, walRecordSet, payload)
+	}
+	buf = appendRecord(buf, walRecordCommit, binary.BigEndian.AppendUint64(nil, uint64(version)))
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return fmt.Errorf("wal is closed")
+	}
+	// A single write keeps the changeset contiguous
+	if _, err := w.file.Write(buf); err != nil {
+		return fmt.Errorf("failed to write wal: %w", err)
+	}
+
+	w.unsynced++
+	switch {
+	case w.syncMode == WALSyncAlways,
+		w.syncMode == WALSyncInterval && w.unsynced >= w.syncInterval:
+		if err := w.file.Sync(); err != nil {
+			return fmt.Errorf("failed to sync wal: %w", err)
+		}
+		w.unsynced = 0
+	}
+	return nil
+}
+
+// reset truncates the log once its changesets are saved.
+//
+// The truncation is not fsynced: if it is lost, replay skips the changesets
+// whose versions are already saved.
+func (w *WAL) reset() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return fmt.Errorf("wal is closed")
+	}
+	if err := w.file.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate wal: %w", err)
+	}
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to truncate wal: %w", err)
+	}
+	return nil
+}
+
+// readChangesets returns the complete changesets of the log in order and the
+// number of writes after the last commit record
+func (w *WAL) readChangesets() ([]walChangeset, int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil, 0, fmt.Errorf("wal is closed")
+	}
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return nil, 0, fmt.Errorf("failed to read wal: %w", err)
+	}
+	// Leave the offset at the end for the next append
+	defer w.file.Seek(0, io.SeekEnd)
+
+	r := bufio.NewReader(w.file)
+	var changesets []walChangeset
+	var ops []walOp
+	for {
+		recordType, payload, ok := readRecord(r)
+		if !ok {
+			return changesets, len(ops), nil
+		}
+
+		switch recordType {
+		case walRecordSet:
+			keyLen, n := binary.Uvarint(payload)
+			if n <= 0 || keyLen == 0 || keyLen > uint64(len(payload)-n) {
+				return changesets, len(ops), nil
+			}
+			key := payload[n : n+int(keyLen)]
+			ops = append(ops, walOp{key: key, value: payload[n+int(keyLen):]})
+		case walRecordDelete:
+			ops = append(ops, walOp{key: payload})
+		case walRecordCommit:
+			if len(payload) != 8 {
+				return changesets, len(ops), nil
+			}
+			version := int64(binary.BigEndian.Uint64(payload))
+			changesets = append(changesets, walChangeset{version: version, ops: ops})
+			ops = nil
+		default:
+			return changesets, len(ops), nil
+		}
+	}
+}
+
+// readRecord reads one record, reporting false at the end of the log or at a
+// truncated or corrupt record
+func readRecord(r io.Reader) (byte, []byte, bool) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, false
+	}
+	length := binary.BigEndian.Uint32(header[1:])
+	// Bounds the allocation for a corrupt length
+	if length > 1<<30 {
+		return 0, nil, false
+	}
+
+	rest := make([]byte, int(length)+4)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return 0, nil, false
+	}
+	payload := rest[:length]
+
+	crc := crc32.Update(crc32.Checksum(header, walCRCTable), walCRCTable, payload)
+	if crc != binary.BigEndian.Uint32(rest[length:]) {
+		return 0, nil, false
+	}
+	return header[0], payload, true
+}
+
+// Close syncs and closes the WAL file
+func (w *WAL) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	syncErr := w.file.Sync()
+	closeErr := w.file.Close()
+	w.file = nil
+	if syncErr != nil {
+		return fmt.Errorf("failed to sync wal: %w", syncErr)
+	}
+	return closeErr
+}
+
+// WALReplayResult describes a ReplayWAL run
+type WALReplayResult struct {
+	// Replayed are the versions saved from the WAL, in order
+	Replayed []int64
+
+	// Skipped is the number of logged changesets already saved in the store
+	Skipped int
+
+	// Discarded is the number of logged writes without a commit record,
+	// whose block the consensus engine must replay
+	Discarded int
+}
+
+// WithWAL logs every changeset to w before its version is saved. The store
+// owns w and closes it on Close.
+//
+// PRECONDITION: Call ReplayWAL after opening the store and before writing to
+// it, so a changeset left by a crash is saved first.
+func WithWAL(w *WAL) IAVLOption {
+	return func(s *IAVLStore) {
+		s.wal = w
+	}
+}
+
+// ReplayWAL saves the logged changesets whose versions the store has not
+// saved, then truncates the log. It is a no-op without a WAL.
+//
+// Replay is deterministic: it re-applies exactly the writes of the block
+// that was committing when the process stopped, so the saved version has
+// the hash the block would have had.
+func (s *IAVLStore) ReplayWAL() (*WALReplayResult, error) {
+	if s == nil {
+		return nil, ErrStoreNil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, fmt.Errorf("store is closed")
+	}
+
+	result := &WALReplayResult{}
+	if s.wal == nil {
+		return result, nil
+	}
+	if len(s.pending) > 0 {
+		return nil, fmt.Errorf("%w: store has %d unsaved writes", ErrWALMismatch, len(s.pending))
+	}
+
+	changesets, discarded, err := s.wal.readChangesets()
+	if err != nil {
+		return nil, err
+	}
+	result.Discarded = discarded
+
+	for _, cs := range changesets {
+		if cs.version <= s.version {
+			result.Skipped++
+			continue
+		}
+		if working := s.tree.WorkingVersion(); cs.version != working {
+			return nil, fmt.Errorf("%w: logged version %d, next version %d", ErrWALMismatch, cs.version, working)
+		}
+
+		for _, op := range cs.ops {
+			if err := s.applyLocked(op.key, op.value); err != nil {
+				return nil, fmt.Errorf("failed to replay version %d: %w", cs.version, err)
+			}
+		}
+		if _, err := s.saveVersionLocked(); err != nil {
+			return nil, fmt.Errorf("failed to replay version %d: %w", cs.version, err)
+		}
+		result.Replayed = append(result.Replayed, cs.version)
+	}
+
+	if err := s.wal.reset(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,291 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func openTestWAL(t *testing.T, path string, opts ...WALOption) *WAL {
+	t.Helper()
+
+	w, err := OpenWAL(path, opts...)
+	if err != nil {
+		t.Fatalf("OpenWAL failed: %v", err)
+	}
+	return w
+}
+
+func openWALStore(t *testing.T, db *MemDB, path string) *IAVLStore {
+	t.Helper()
+
+	s, err := NewIAVLStore(db, 0, WithWAL(openTestWAL(t, path)))
+	if err != nil {
+		t.Fatalf("NewIAVLStore failed: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func walSize(t *testing.T, path string) int64 {
+	t.Helper()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	return info.Size()
+}
+
+func TestWAL_ReadChangesets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.wal")
+	w := openTestWAL(t, path)
+	defer w.Close()
+
+	ops := []walOp{
+		{key: []byte("a"), value: []byte("1")},
+		{key: []byte("b"), value: []byte{}},
+		{key: []byte("c")},
+	}
+	if err := w.logChangeset(7, ops); err != nil {
+		t.Fatalf("logChangeset failed: %v", err)
+	}
+
+	changesets, discarded, err := w.readChangesets()
+	if err != nil {
+		t.Fatalf("readChangesets failed: %v", err)
+	}
+	if discarded != 0 {
+		t.Errorf("discarded = %d, want 0", discarded)
+	}
+	if len(changesets) != 1 || changesets[0].version != 7 {
+		t.Fatalf("changesets = %+v, want one at version 7", changesets)
+	}
+	got := changesets[0].ops
+	if len(got) != len(ops) {
+		t.Fatalf("got %d ops, want %d", len(got), len(ops))
+	}
+	for i, op := range ops {
+		if !bytes.Equal(got[i].key, op.key) || !bytes.Equal(got[i].value, op.value) || (got[i].value == nil) != (op.value == nil) {
+			t.Errorf("op %d = %q=%q, want %q=%q", i, got[i].key, got[i].value, op.key, op.value)
+		}
+	}
+}
+
+func TestWAL_TornTail(t *testing.T) {
+	tests := []struct {
+		name string
+		// corrupt mutates the bytes of a log holding one changeset
+		corrupt       func([]byte) []byte
+		wantVersions  int
+		wantDiscarded int
+	}{
+		{"intact", func(b []byte) []byte { return b }, 1, 0},
+		{"truncated commit record", func(b []byte) []byte { return b[:len(b)-3] }, 0, 2},
+		{"corrupt commit checksum", func(b []byte) []byte { b[len(b)-1] ^= 0xff; return b }, 0, 2},
+		{"corrupt first record", func(b []byte) []byte { b[6] ^= 0xff; return b }, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "store.wal")
+			w := openTestWAL(t, path)
+			ops := []walOp{{key: []byte("a"), value: []byte("1")}, {key: []byte("b"), value: []byte("2")}}
+			if err := w.logChangeset(1, ops); err != nil {
+				t.Fatalf("logChangeset failed: %v", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("ReadFile failed: %v", err)
+			}
+			if err := os.WriteFile(path, tt.corrupt(data), 0o600); err != nil {
+				t.Fatalf("WriteFile failed: %v", err)
+			}
+
+			w = openTestWAL(t, path)
+			defer w.Close()
+			changesets, discarded, err := w.readChangesets()
+			if err != nil {
+				t.Fatalf("readChangesets failed: %v", err)
+			}
+			if len(changesets) != tt.wantVersions || discarded != tt.wantDiscarded {
+				t.Errorf("got %d changesets and %d discarded, want %d and %d",
+					len(changesets), discarded, tt.wantVersions, tt.wantDiscarded)
+			}
+		})
+	}
+}
+
+func TestOpenWAL_InvalidSyncInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.wal")
+	if _, err := OpenWAL(path, WithWALSyncInterval(0)); err == nil {
+		t.Error("expected error with zero sync interval")
+	}
+	if _, err := OpenWAL(path, WithWALSyncMode(WALSyncMode(9))); err == nil {
+		t.Error("expected error with unknown sync mode")
+	}
+
+	for _, opt := range []WALOption{WithWALSyncMode(WALSyncNever), WithWALSyncInterval(10)} {
+		w := openTestWAL(t, path, opt)
+		w.Close()
+	}
+}
+
+func TestIAVLStore_WALTruncatedAfterSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.wal")
+	s := openWALStore(t, NewMemDB(), path)
+
+	if err := s.Set([]byte("a"), []byte("1")); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if _, _, err := s.SaveVersion(); err != nil {
+		t.Fatalf("SaveVersion failed: %v", err)
+	}
+	if size := walSize(t, path); size != 0 {
+		t.Errorf("wal size after save = %d, want 0", size)
+	}
+}
+
+func TestIAVLStore_ReplayWAL(t *testing.T) {
+	db := NewMemDB()
+	path := filepath.Join(t.TempDir(), "store.wal")
+
+	// Reference: the hash version 2 has when saved normally
+	ref, err := NewIAVLStore(NewMemDB(), 0)
+	if err != nil {
+		t.Fatalf("NewIAVLStore failed: %v", err)
+	}
+	ref.Set([]byte("a"), []byte("1"))
+	ref.Set([]byte("b"), []byte("2"))
+	ref.SaveVersion()
+	ref.Set([]byte("c"), []byte("3"))
+	ref.Delete([]byte("a"))
+	wantHash, _, err := ref.SaveVersion()
+	if err != nil {
+		t.Fatalf("SaveVersion failed: %v", err)
+	}
+
+	s := openWALStore(t, db, path)
+	s.Set([]byte("a"), []byte("1"))
+	s.Set([]byte("b"), []byte("2"))
+	if _, _, err := s.SaveVersion(); err != nil {
+		t.Fatalf("SaveVersion failed: %v", err)
+	}
+
+	// Crash after logging version 2 but before saving it
+	s.Set([]byte("c"), []byte("3"))
+	s.Delete([]byte("a"))
+	if err := s.wal.logChangeset(2, s.pending); err != nil {
+		t.Fatalf("logChangeset failed: %v", err)
+	}
+	s.Close()
+
+	restarted := openWALStore(t, db, path)
+	if v := restarted.Version(); v != 1 {
+		t.Fatalf("version after restart = %d, want 1", v)
+	}
+
+	result, err := restarted.ReplayWAL()
+	if err != nil {
+		t.Fatalf("ReplayWAL failed: %v", err)
+	}
+	if len(result.Replayed) != 1 || result.Replayed[0] != 2 || result.Skipped != 0 || result.Discarded != 0 {
+		t.Errorf("result = %+v, want version 2 replayed", result)
+	}
+	if v := restarted.Version(); v != 2 {
+		t.Errorf("version after replay = %d, want 2", v)
+	}
+	if !bytes.Equal(restarted.Hash(), wantHash) {
+		t.Errorf("hash after replay = %X, want %X", restarted.Hash(), wantHash)
+	}
+	if _, err := restarted.Get([]byte("a")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(a) error = %v, want ErrNotFound", err)
+	}
+	if value, err := restarted.Get([]byte("c")); err != nil || string(value) != "3" {
+		t.Errorf("Get(c) = %q, %v, want 3", value, err)
+	}
+	if size := walSize(t, path); size != 0 {
+		t.Errorf("wal size after replay = %d, want 0", size)
+	}
+
+	// A second replay has nothing to do
+	result, err = restarted.ReplayWAL()
+	if err != nil {
+		t.Fatalf("ReplayWAL failed: %v", err)
+	}
+	if len(result.Replayed) != 0 {
+		t.Errorf("second replay saved %v", result.Replayed)
+	}
+}
+
+func TestIAVLStore_ReplayWAL_SkipsSavedVersions(t *testing.T) {
+	db := NewMemDB()
+	path := filepath.Join(t.TempDir(), "store.wal")
+
+	s := openWALStore(t, db, path)
+	s.Set([]byte("a"), []byte("1"))
+	ops := append([]walOp(nil), s.pending...)
+	if _, _, err := s.SaveVersion(); err != nil {
+		t.Fatalf("SaveVersion failed: %v", err)
+	}
+	// A lost truncation leaves the saved changeset in the log, followed by
+	// the writes of a block that crashed before its commit record
+	if err := s.wal.logChangeset(1, ops); err != nil {
+		t.Fatalf("logChangeset failed: %v", err)
+	}
+	s.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	torn := appendRecord(nil, walRecordDelete, []byte("a"))
+	if err := os.WriteFile(path, append(data, torn...), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	restarted := openWALStore(t, db, path)
+	result, err := restarted.ReplayWAL()
+	if err != nil {
+		t.Fatalf("ReplayWAL failed: %v", err)
+	}
+	if len(result.Replayed) != 0 || result.Skipped != 1 || result.Discarded != 1 {
+		t.Errorf("result = %+v, want 1 skipped and 1 discarded", result)
+	}
+	if value, err := restarted.Get([]byte("a")); err != nil || string(value) != "1" {
+		t.Errorf("Get(a) = %q, %v, want 1", value, err)
+	}
+}
+
+func TestIAVLStore_ReplayWAL_VersionGap(t *testing.T) {
+	db := NewMemDB()
+	path := filepath.Join(t.TempDir(), "store.wal")
+
+	s := openWALStore(t, db, path)
+	if err := s.wal.logChangeset(5, []walOp{{key: []byte("a"), value: []byte("1")}}); err != nil {
+		t.Fatalf("logChangeset failed: %v", err)
+	}
+	if _, err := s.ReplayWAL(); !errors.Is(err, ErrWALMismatch) {
+		t.Fatalf("ReplayWAL error = %v, want ErrWALMismatch", err)
+	}
+}
+
+func TestIAVLStore_ReplayWAL_WithoutWAL(t *testing.T) {
+	s, err := NewIAVLStore(NewMemDB(), 0)
+	if err != nil {
+		t.Fatalf("NewIAVLStore failed: %v", err)
+	}
+	result, err := s.ReplayWAL()
+	if err != nil {
+		t.Fatalf("ReplayWAL failed: %v", err)
+	}
+	if len(result.Replayed) != 0 || result.Skipped != 0 || result.Discarded != 0 {
+		t.Errorf("result = %+v, want empty", result)
+	}
+}
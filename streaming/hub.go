@@ -0,0 +1,186 @@
+package streaming
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultHubBuffer is the number of blocks a subscription buffers when no
+// buffer size is given
+const DefaultHubBuffer = 64
+
+var (
+	// ErrSlowSubscriber ends a subscription whose buffer was full when a
+	// block was committed
+	ErrSlowSubscriber = errors.New("subscriber fell behind")
+
+	// ErrHubClosed ends the subscriptions of a closed hub
+	ErrHubClosed = errors.New("stream hub closed")
+)
+
+// Hub is a Sink fanning blocks out to subscribers, e.g. the streams of a
+// gRPC firehose service: each server stream subscribes and forwards the
+// blocks it receives.
+//
+// SECURITY: WriteBlock never blocks on a subscriber. A subscriber that falls
+// more than its buffer behind is dropped with ErrSlowSubscriber and must
+// resubscribe and catch up from its own records, so a slow consumer cannot
+// stall block commits.
+//
+// Safe for concurrent use.
+type Hub struct {
+	mu     sync.Mutex
+	buffer int
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+// NewHub creates a hub whose subscriptions buffer up to buffer blocks
+// (DefaultHubBuffer if buffer is 0)
+func NewHub(buffer int) (*Hub, error) {
+	if buffer < 0 {
+		return nil, fmt.Errorf("hub buffer cannot be negative: %d", buffer)
+	}
+	if buffer == 0 {
+		buffer = DefaultHubBuffer
+	}
+	return &Hub{buffer: buffer, subs: make(map[*Subscription]struct{})}, nil
+}
+
+// Subscribe subscribes to the blocks committed from now on. With prefixes,
+// each block only carries the changes to keys under one of them; blocks
+// without such changes are still delivered, so subscribers see every
+// height.
+func (h *Hub) Subscribe(prefixes ...[]byte) (*Subscription, error) {
+	if h == nil {
+		return nil, fmt.Errorf("hub is nil")
+	}
+
+	sub := &Subscription{
+		hub:    h,
+		blocks: make(chan *Block, h.buffer),
+	}
+	for _, prefix := range prefixes {
+		sub.prefixes = append(sub.prefixes, bytes.Clone(prefix))
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, ErrHubClosed
+	}
+	h.subs[sub] = struct{}{}
+	return sub, nil
+}
+
+// WriteBlock delivers block to every subscriber
+//
+// Complexity: O(subscribers * changes) with prefix filters, O(subscribers)
+// otherwise
+func (h *Hub) WriteBlock(block *Block) error {
+	if h == nil {
+		return fmt.Errorf("hub is nil")
+	}
+	if block == nil {
+		return fmt.Errorf("block cannot be nil")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		select {
+		case sub.blocks <- sub.filter(block):
+		default:
+			h.endLocked(sub, ErrSlowSubscriber)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of subscribers
+func (h *Hub) Len() int {
+	if h == nil {
+		return 0
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+// Close ends every subscription with ErrHubClosed and refuses new ones
+func (h *Hub) Close() error {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for sub := range h.subs {
+		h.endLocked(sub, ErrHubClosed)
+	}
+	return nil
+}
+
+// endLocked removes sub, closing its channel with err.
+//
+// PRECONDITION: h.mu is held and sub is subscribed.
+func (h *Hub) endLocked(sub *Subscription, err error) {
+	delete(h.subs, sub)
+	sub.err = err
+	close(sub.blocks)
+}
+
+// Subscription is a Hub subscriber's stream of blocks
+type Subscription struct {
+	hub      *Hub
+	prefixes [][]byte
+	blocks   chan *Block
+
+	// err is why blocks was closed (guarded by hub.mu)
+	err error
+}
+
+// Blocks returns the channel of committed blocks. It is closed when the
+// subscription ends; Err then tells why. Blocks are shared with other
+// subscribers and must not be modified.
+func (s *Subscription) Blocks() <-chan *Block {
+	return s.blocks
+}
+
+// Err returns why the subscription ended: ErrSlowSubscriber, ErrHubClosed,
+// or nil if it was closed by Close or has not ended
+func (s *Subscription) Err() error {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	return s.err
+}
+
+// Close ends the subscription
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	if _, ok := s.hub.subs[s]; ok {
+		s.hub.endLocked(s, nil)
+	}
+}
+
+// filter returns block restricted to the subscription's prefixes
+func (s *Subscription) filter(block *Block) *Block {
+	if len(s.prefixes) == 0 {
+		return block
+	}
+
+	filtered := *block
+	filtered.Changes = make([]Change, 0)
+	for _, change := range block.Changes {
+		for _, prefix := range s.prefixes {
+			if bytes.HasPrefix(change.Key, prefix) {
+				filtered.Changes = append(filtered.Changes, change)
+				break
+			}
+		}
+	}
+	return &filtered
+}
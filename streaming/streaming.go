@@ -0,0 +1,185 @@
+// Package streaming delivers the state changes of committed blocks to
+// off-chain consumers, so indexers and analytics systems can mirror state
+// without polling queries.
+//
+// The application (see runtime.ApplicationConfig.StateSinks) hands every
+// committed block to its Sinks as a Block: the block context, the block's
+// transactions and every key-value change it committed, each attributed to
+// the transaction that wrote it. WriterSink and FileSink write blocks as
+// JSON lines, which ReadBlocks reads back; Hub fans blocks out to
+// subscribers, e.g. the streams of a gRPC firehose service. Other
+// transports, such as a Kafka producer, plug in by implementing Sink.
+package streaming
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// NoTx is the TxIndex of changes written outside transactions: by block
+// hooks, genesis or upgrades
+const NoTx = -1
+
+// Change is a committed key-value change
+type Change struct {
+	// TxIndex is the index in Block.Txs of the transaction that wrote the
+	// change, or NoTx
+	TxIndex int `json:"tx_index"`
+
+	// Key is the state store key
+	Key []byte `json:"key"`
+
+	// Value is the written value (nil for a delete)
+	Value []byte `json:"value,omitempty"`
+
+	// Delete reports whether the key was deleted
+	Delete bool `json:"delete,omitempty"`
+}
+
+// Tx is a transaction of a streamed block
+type Tx struct {
+	// Hash is the SHA-256 of the transaction bytes
+	Hash []byte `json:"hash"`
+
+	// Codespace and Code are the transaction's result code (0 on success)
+	Codespace string `json:"codespace,omitempty"`
+	Code      uint32 `json:"code"`
+}
+
+// Block is the state changes committed by a block
+//
+// INVARIANT: Changes are in the order they were written; a key written
+// twice appears twice, and the last change is the committed one.
+type Block struct {
+	// Height is the committed height: the state store version the block's
+	// changes were saved at, as in the block's commit result
+	Height uint64 `json:"height"`
+
+	// Time is the block time
+	Time time.Time `json:"time"`
+
+	// AppHash is the app hash committed by the block
+	AppHash []byte `json:"app_hash"`
+
+	// Txs are the block's transactions, in execution order
+	Txs []Tx `json:"txs"`
+
+	// Changes are the key-value changes the block committed
+	Changes []Change `json:"changes"`
+}
+
+// Sink receives every committed block.
+//
+// SECURITY: Sinks run after the block is committed and cannot affect
+// consensus; the application logs their errors and continues. Sinks that
+// must not miss blocks should persist them before returning, so consumers
+// can resume from the last Height they stored.
+//
+// WriteBlock is called from the commit path, one block at a time; it must
+// not modify block.
+type Sink interface {
+	WriteBlock(block *Block) error
+}
+
+// WriterSink writes each block as one line of JSON
+//
+// Safe for concurrent use.
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink creates a sink writing JSON lines to w
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// WriteBlock writes block as a line of JSON
+func (s *WriterSink) WriteBlock(block *Block) error {
+	if s == nil || s.w == nil {
+		return fmt.Errorf("writer sink is nil")
+	}
+	if block == nil {
+		return fmt.Errorf("block cannot be nil")
+	}
+
+	data, err := json.Marshal(block)
+	if err != nil {
+		return fmt.Errorf("failed to encode block %d: %w", block.Height, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write block %d: %w", block.Height, err)
+	}
+	return nil
+}
+
+// FileSink appends each block as a line of JSON to a file, syncing it to
+// disk before returning
+type FileSink struct {
+	file *os.File
+	sink *WriterSink
+}
+
+// NewFileSink opens (or creates) the file at path for appending
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stream file: %w", err)
+	}
+	return &FileSink{file: file, sink: NewWriterSink(file)}, nil
+}
+
+// WriteBlock appends block and syncs the file
+func (s *FileSink) WriteBlock(block *Block) error {
+	if s == nil {
+		return fmt.Errorf("file sink is nil")
+	}
+	if err := s.sink.WriteBlock(block); err != nil {
+		return err
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync stream file: %w", err)
+	}
+	return nil
+}
+
+// Close closes the file
+func (s *FileSink) Close() error {
+	if s == nil {
+		return nil
+	}
+	return s.file.Close()
+}
+
+// ReadBlocks reads JSON lines written by a WriterSink or FileSink, calling
+// fn with each block in order until fn returns an error. A torn last line
+// (e.g. from a crash mid-write) is ignored.
+func ReadBlocks(r io.Reader, fn func(block *Block) error) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read stream: %w", err)
+		}
+
+		var block Block
+		if err := json.Unmarshal(line, &block); err != nil {
+			return fmt.Errorf("failed to decode block: %w", err)
+		}
+		if err := fn(&block); err != nil {
+			return err
+		}
+	}
+}
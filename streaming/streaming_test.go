@@ -0,0 +1,133 @@
+package streaming
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBlock(height uint64) *Block {
+	return &Block{
+		Height:  height,
+		Time:    time.Unix(int64(height), 0).UTC(),
+		AppHash: []byte{byte(height)},
+		Txs:     []Tx{{Hash: []byte{0xaa}}, {Hash: []byte{0xbb}, Codespace: "sdk", Code: 5}},
+		Changes: []Change{
+			{TxIndex: 0, Key: []byte("module/bank/alice"), Value: []byte("1")},
+			{TxIndex: NoTx, Key: []byte("module/staking/x"), Delete: true},
+		},
+	}
+}
+
+func TestWriterSink_ReadBlocks(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewWriterSink(&buf)
+	require.NoError(t, sink.WriteBlock(testBlock(1)))
+	require.NoError(t, sink.WriteBlock(testBlock(2)))
+	assert.Error(t, sink.WriteBlock(nil))
+
+	// A torn last line is ignored
+	buf.WriteString(`{"height":3,"chan`)
+
+	var blocks []*Block
+	require.NoError(t, ReadBlocks(&buf, func(block *Block) error {
+		blocks = append(blocks, block)
+		return nil
+	}))
+	assert.Equal(t, []*Block{testBlock(1), testBlock(2)}, blocks)
+
+	stop := errors.New("stop")
+	var buf2 bytes.Buffer
+	require.NoError(t, NewWriterSink(&buf2).WriteBlock(testBlock(1)))
+	assert.ErrorIs(t, ReadBlocks(&buf2, func(*Block) error { return stop }), stop)
+	assert.Error(t, ReadBlocks(bytes.NewBufferString("not json\n"), func(*Block) error { return nil }))
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.jsonl")
+
+	sink, err := NewFileSink(path)
+	require.NoError(t, err)
+	require.NoError(t, sink.WriteBlock(testBlock(1)))
+	require.NoError(t, sink.Close())
+
+	// Reopening appends
+	sink, err = NewFileSink(path)
+	require.NoError(t, err)
+	require.NoError(t, sink.WriteBlock(testBlock(2)))
+	require.NoError(t, sink.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	var heights []uint64
+	require.NoError(t, ReadBlocks(file, func(block *Block) error {
+		heights = append(heights, block.Height)
+		return nil
+	}))
+	assert.Equal(t, []uint64{1, 2}, heights)
+}
+
+func TestHub(t *testing.T) {
+	hub, err := NewHub(2)
+	require.NoError(t, err)
+
+	all, err := hub.Subscribe()
+	require.NoError(t, err)
+	bank, err := hub.Subscribe([]byte("module/bank/"))
+	require.NoError(t, err)
+	slow, err := hub.Subscribe()
+	require.NoError(t, err)
+	assert.Equal(t, 3, hub.Len())
+
+	require.NoError(t, hub.WriteBlock(testBlock(1)))
+	assert.Equal(t, testBlock(1), <-all.Blocks())
+	<-slow.Blocks()
+
+	filtered := <-bank.Blocks()
+	assert.Equal(t, uint64(1), filtered.Height)
+	assert.Equal(t, testBlock(1).Txs, filtered.Txs)
+	assert.Equal(t, []Change{testBlock(1).Changes[0]}, filtered.Changes)
+
+	// Filtering does not alter the block other subscribers see
+	block := testBlock(2)
+	require.NoError(t, hub.WriteBlock(block))
+	assert.Len(t, block.Changes, 2)
+
+	// slow stops reading and is dropped once its buffer of 2 is full
+	<-all.Blocks()
+	<-bank.Blocks()
+	require.NoError(t, hub.WriteBlock(testBlock(3)))
+	<-all.Blocks()
+	<-bank.Blocks()
+	require.NoError(t, hub.WriteBlock(testBlock(4)))
+	assert.Equal(t, 2, hub.Len())
+
+	var buffered []uint64
+	for block := range slow.Blocks() {
+		buffered = append(buffered, block.Height)
+	}
+	assert.Equal(t, []uint64{2, 3}, buffered)
+	assert.ErrorIs(t, slow.Err(), ErrSlowSubscriber)
+	assert.Equal(t, uint64(4), (<-all.Blocks()).Height)
+
+	bank.Close()
+	bank.Close()
+	assert.NoError(t, bank.Err())
+
+	require.NoError(t, hub.Close())
+	for range all.Blocks() {
+	}
+	assert.ErrorIs(t, all.Err(), ErrHubClosed)
+	_, err = hub.Subscribe()
+	assert.ErrorIs(t, err, ErrHubClosed)
+
+	_, err = NewHub(-1)
+	assert.Error(t, err)
+}
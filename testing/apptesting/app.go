@@ -0,0 +1,479 @@
+// Package apptesting provides an in-memory application fixture for module tests.
+//
+// NewTestApp wires the auth and bank modules (plus any extra module specs)
+// through a module.ModuleManager over an in-memory store, creates and funds
+// the well-known genesis accounts, and signs transactions with their
+// deterministic keys:
+//
+//	app := apptesting.NewTestApp(t, apptesting.WithModules(mymodule.Spec()))
+//	app.FundAccount(t, apptesting.Alice, types.NewCoins(types.NewCoin("uatom", 500)))
+//	result := app.ExecMsg(t, &mymodule.MsgDoThing{Signer: apptesting.Alice})
+//	app.NextBlock(t)
+//
+// Transactions are delivered through a runtime.Application - validation,
+// authorization, message routing, effect application and nonce increment are
+// the runtime's - whose auth and bank modules share its account and balance
+// stores, so handlers read back what earlier messages wrote.
+package apptesting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	dbm "github.com/cosmos/cosmos-db"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/client"
+	"github.com/blockberries/punnet-sdk/crypto"
+	"github.com/blockberries/punnet-sdk/module"
+	"github.com/blockberries/punnet-sdk/modules/auth"
+	"github.com/blockberries/punnet-sdk/modules/bank"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+	punnettesting "github.com/blockberries/punnet-sdk/testing"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// DefaultDenom is the denomination of DefaultGenesisCoins.
+const DefaultDenom = "stake"
+
+// DefaultBlockInterval is how far NextBlock advances the block time.
+const DefaultBlockInterval = 5 * time.Second
+
+// DefaultGenesisCoins returns the balance of each default genesis account.
+func DefaultGenesisCoins() types.Coins {
+	return types.NewCoins(types.NewCoin(DefaultDenom, 1_000_000))
+}
+
+// GenesisAccount is an account created by NewTestApp, with its well-known
+// key (see WellKnownKey) and initial balance.
+type GenesisAccount struct {
+	Name  types.AccountName
+	Coins types.Coins
+}
+
+// DefaultGenesisAccounts returns WellKnownAccounts, each funded with
+// DefaultGenesisCoins.
+func DefaultGenesisAccounts() []GenesisAccount {
+	accounts := make([]GenesisAccount, len(WellKnownAccounts))
+	for i, name := range WellKnownAccounts {
+		accounts[i] = GenesisAccount{Name: name, Coins: DefaultGenesisCoins()}
+	}
+	return accounts
+}
+
+// config holds the settings applied by Options.
+type config struct {
+	chainID   string
+	height    uint64
+	blockTime time.Time
+	modules   []module.ModuleSpec
+	accounts  []GenesisAccount
+	ante      func(*TestApp) runtime.AnteHandler
+	fee       types.Fee
+}
+
+// Option configures NewTestApp.
+type Option func(*config)
+
+// WithChainID sets the chain ID transactions are signed for.
+func WithChainID(chainID string) Option {
+	return func(c *config) { c.chainID = chainID }
+}
+
+// WithBlockTime sets the time of the first block.
+func WithBlockTime(t time.Time) Option {
+	return func(c *config) { c.blockTime = t }
+}
+
+// WithModules registers module specs alongside auth and bank.
+func WithModules(specs ...module.ModuleSpec) Option {
+	return func(c *config) { c.modules = append(c.modules, specs...) }
+}
+
+// WithGenesisAccounts replaces DefaultGenesisAccounts.
+func WithGenesisAccounts(accounts ...GenesisAccount) Option {
+	return func(c *config) { c.accounts = accounts }
+}
+
+// WithAnteHandler sets the application's ante handler to the one newAnte
+// returns for the app, e.g. over a store capability from GrantStore.
+func WithAnteHandler(newAnte func(app *TestApp) runtime.AnteHandler) Option {
+	return func(c *config) { c.ante = newAnte }
+}
+
+// WithFee sets the fee SignTx attaches to every transaction.
+func WithFee(fee types.Fee) Option {
+	return func(c *config) { c.fee = fee }
+}
+
+// TestApp is an in-memory application for module tests.
+//
+// CONCURRENCY: Not safe for concurrent use; like a block, it executes one
+// transaction at a time.
+type TestApp struct {
+	app        *runtime.Application
+	state      *store.IAVLStore
+	modules    *module.ModuleManager
+	capMgr     *capability.CapabilityManager
+	keyring    crypto.Keyring
+	accounts   store.ObjectStore[*types.Account]
+	balances   *store.BalanceStore
+	accountCap capability.AccountCapability
+	balanceCap capability.BalanceCapability
+	header     *runtime.BlockHeader
+	fee        types.Fee
+
+	// pending is the transaction DeliverTx is executing, which decodeTx
+	// returns for its encoding
+	pending      *types.Transaction
+	pendingBytes []byte
+}
+
+// NewTestApp builds a TestApp and begins block punnettesting.DefaultHeight.
+//
+// Defaults: punnettesting.DefaultChainID, punnettesting.DefaultBlockTime and
+// DefaultGenesisAccounts. Auth and bank are always registered; WithModules
+// adds more. Every genesis account's well-known key is in Keyring.
+func NewTestApp(t testing.TB, opts ...Option) *TestApp {
+	t.Helper()
+
+	cfg := &config{
+		chainID:   punnettesting.DefaultChainID,
+		height:    punnettesting.DefaultHeight,
+		blockTime: punnettesting.DefaultBlockTime,
+		accounts:  DefaultGenesisAccounts(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	state, err := store.NewIAVLStore(dbm.NewMemDB(), 0)
+	require.NoError(t, err)
+
+	// Accounts are kept where the runtime keeps them by default, balances
+	// where a granted bank capability would. Auth and bank get capabilities
+	// over the application's own stores, so the accounts the runtime
+	// authorizes and the balances transfer effects move are the ones their
+	// handlers see.
+	accounts := store.NewCachedObjectStore[*types.Account](state, store.NewJSONSerializer[*types.Account](), 1000, 10000)
+	balances := store.NewBalanceStore(capability.ModuleStore(state, bank.ModuleName))
+	accountCap, err := capability.NewAccountCapability(store.NewAccountStoreOf(accounts), state, auth.ModuleName)
+	require.NoError(t, err)
+	balanceCap, err := capability.NewBalanceCapability(balances, bank.ModuleName)
+	require.NoError(t, err)
+
+	authSpec := auth.Spec()
+	authSpec.Create = func(module.Capabilities) (module.Module, error) {
+		return auth.CreateModule(accountCap)
+	}
+	bankSpec := bank.Spec()
+	bankSpec.Create = func(module.Capabilities) (module.Module, error) {
+		return bank.CreateModule(balanceCap)
+	}
+
+	capMgr := capability.NewCapabilityManager(state)
+	mm := module.NewModuleManager(capMgr)
+	for _, spec := range append([]module.ModuleSpec{authSpec, bankSpec}, cfg.modules...) {
+		require.NoError(t, mm.Register(spec), "failed to register module %s", spec.Name)
+	}
+	require.NoError(t, mm.Build(), "failed to build modules")
+
+	header := runtime.NewBlockHeader(cfg.height, cfg.blockTime, cfg.chainID, nil)
+	require.NoError(t, header.ValidateBasic(), "invalid test app configuration")
+
+	keyring := crypto.NewKeyring(crypto.NewMemoryStore())
+	t.Cleanup(func() { _ = keyring.Close() })
+
+	app := &TestApp{
+		state:      state,
+		modules:    mm,
+		capMgr:     capMgr,
+		keyring:    keyring,
+		accounts:   accounts,
+		balances:   balances,
+		accountCap: accountCap,
+		balanceCap: balanceCap,
+		header:     header,
+		fee:        cfg.fee,
+	}
+
+	appConfig, err := mm.ApplicationConfig(cfg.chainID, state)
+	require.NoError(t, err)
+	appConfig.AccountStore = accounts
+	appConfig.BalanceStore = balances
+	appConfig.TxDecoder = app.decodeTx
+	if cfg.ante != nil {
+		appConfig.AnteHandler = cfg.ante(app)
+	}
+	app.app, err = runtime.NewApplication(appConfig)
+	require.NoError(t, err, "failed to create application")
+
+	for _, acc := range cfg.accounts {
+		app.CreateAccount(t, acc.Name)
+		app.FundAccount(t, acc.Name, acc.Coins)
+	}
+	require.NoError(t, app.app.BeginBlock(context.Background(), header), "failed to begin block %d", header.Height)
+	return app
+}
+
+// CreateAccount creates name with a single-key authority over its well-known
+// key and adds the key to Keyring.
+func (app *TestApp) CreateAccount(t testing.TB, name types.AccountName) *types.Account {
+	t.Helper()
+
+	ctx := context.Background()
+	_, err := app.accountCap.CreateAccount(ctx, name, WellKnownPubKey(name))
+	require.NoError(t, err, "failed to create account %s", name)
+
+	// Pin the timestamps to the block time so account state is reproducible
+	account, err := app.accountCap.GetAccount(ctx, name)
+	require.NoError(t, err)
+	account.CreatedAt = app.header.Time
+	account.UpdatedAt = app.header.Time
+	require.NoError(t, app.accountCap.UpdateAccount(ctx, account))
+
+	require.NoError(t, importWellKnownKey(app.keyring, name), "failed to import key of %s", name)
+	return account
+}
+
+// FundAccount adds coins to name's bank balance.
+func (app *TestApp) FundAccount(t testing.TB, name types.AccountName, coins types.Coins) {
+	t.Helper()
+
+	for _, coin := range coins {
+		require.NoError(t, app.balanceCap.AddBalance(context.Background(), name, coin.Denom, coin.Amount),
+			"failed to fund %s with %d%s", name, coin.Amount, coin.Denom)
+	}
+}
+
+// Balance returns name's bank balance of denom.
+func (app *TestApp) Balance(t testing.TB, name types.AccountName, denom string) uint64 {
+	t.Helper()
+
+	balance, err := app.balanceCap.GetBalance(context.Background(), name, denom)
+	require.NoError(t, err)
+	return balance
+}
+
+// Account returns the stored account.
+func (app *TestApp) Account(t testing.TB, name types.AccountName) *types.Account {
+	t.Helper()
+
+	account, err := app.accountCap.GetAccount(context.Background(), name)
+	require.NoError(t, err, "failed to get account %s", name)
+	return account
+}
+
+// SignTx returns a transaction of msgs from signer at its current nonce,
+// with the fee set by WithFee, signed with the signer's key from Keyring.
+func (app *TestApp) SignTx(t testing.TB, signer types.AccountName, msgs ...types.Message) *types.Transaction {
+	t.Helper()
+
+	key, err := app.keyring.GetKey(string(signer))
+	require.NoError(t, err, "no key for %s in keyring", signer)
+
+	nonce := app.Account(t, signer).Nonce
+	tx := types.NewTransaction(signer, nonce, msgs, types.NewAuthorization())
+	tx.Fee = app.fee
+	tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+
+	signDoc, err := tx.ToSignDoc(app.header.ChainID, nonce)
+	require.NoError(t, err)
+	signBytes, err := signDoc.GetSignBytes()
+	require.NoError(t, err)
+	signature, err := key.Sign(signBytes)
+	require.NoError(t, err)
+
+	tx.Authorization.Signatures = []types.Signature{{
+		Algorithm: types.AlgorithmEd25519,
+		PubKey:    key.PublicKey().Bytes(),
+		Signature: signature,
+	}}
+	return tx
+}
+
+// DeliverTx executes tx in the current block with runtime.Application's
+// ExecuteTx. If the transaction fails, the error is the result's registered
+// error (see client.DecodeTxResult) and none of its messages' effects are
+// applied; its ante effects (e.g. the fee) are, and its nonce advances.
+//
+// Messages need no wire decoder: tx is encoded as JSON and its encoding
+// decoded back to tx itself.
+func (app *TestApp) DeliverTx(tx *types.Transaction) (*types.TxResult, error) {
+	if tx == nil {
+		return nil, fmt.Errorf("transaction cannot be nil")
+	}
+
+	txBytes, err := json.Marshal(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	app.pending, app.pendingBytes = tx, txBytes
+	defer func() { app.pending, app.pendingBytes = nil, nil }()
+
+	result, err := app.app.ExecuteTx(context.Background(), txBytes)
+	if err != nil {
+		return nil, err
+	}
+	return result, client.DecodeTxResult(result)
+}
+
+// decodeTx is the application's transaction decoder: it returns the
+// transaction DeliverTx is executing for its encoding
+func (app *TestApp) decodeTx(txBytes []byte) (*types.Transaction, error) {
+	if app.pending == nil || !bytes.Equal(txBytes, app.pendingBytes) {
+		return nil, fmt.Errorf("%w: not delivered by the test app", types.ErrInvalidTransaction)
+	}
+	return app.pending, nil
+}
+
+// DeliverMsg signs msg for its first signer and delivers it (see DeliverTx).
+// Use it to assert on handler errors; ExecMsg fails the test instead.
+func (app *TestApp) DeliverMsg(t testing.TB, msg types.Message) (*types.TxResult, error) {
+	t.Helper()
+
+	require.NotNil(t, msg, "message is nil")
+	signers := msg.GetSigners()
+	require.NotEmpty(t, signers, "message %s has no signers", msg.Type())
+	return app.DeliverTx(app.SignTx(t, signers[0], msg))
+}
+
+// ExecMsg delivers msg like DeliverMsg and fails t unless it succeeds.
+func (app *TestApp) ExecMsg(t testing.TB, msg types.Message) *types.TxResult {
+	t.Helper()
+
+	result, err := app.DeliverMsg(t, msg)
+	require.NoError(t, err, "message %s failed", msg.Type())
+	return result
+}
+
+// Query routes a module query to its handler.
+func (app *TestApp) Query(t testing.TB, path string, data []byte) ([]byte, error) {
+	t.Helper()
+	return app.app.Router().RouteQuery(context.Background(), path, data)
+}
+
+// NextBlock ends the current block, commits it and begins the next one,
+// with the height advanced by one and the time by DefaultBlockInterval.
+// Module BeginBlock and EndBlock hooks run as in runtime.Application.
+func (app *TestApp) NextBlock(t testing.TB) {
+	t.Helper()
+
+	ctx := context.Background()
+	_, err := app.app.EndBlock(ctx)
+	require.NoError(t, err, "failed to end block %d", app.header.Height)
+	app.Commit(t)
+	_, err = app.app.Commit(ctx)
+	require.NoError(t, err, "failed to commit block %d", app.header.Height)
+
+	app.header = runtime.NewBlockHeader(app.header.Height+1, app.header.Time.Add(DefaultBlockInterval),
+		app.header.ChainID, app.header.ProposerAddress)
+	require.NoError(t, app.app.BeginBlock(ctx, app.header), "failed to begin block %d", app.header.Height)
+}
+
+// flusher is implemented by the capabilities that cache writes
+type flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// Commit flushes the account and balance caches and every module's
+// capability caches to the state store, without ending the block. Reads
+// that see only flushed state, such as balance iteration and hence the bank
+// total supply invariant, observe the block's writes afterwards.
+func (app *TestApp) Commit(t testing.TB) {
+	t.Helper()
+
+	ctx := context.Background()
+	require.NoError(t, app.accounts.Flush(ctx), "failed to flush accounts")
+	require.NoError(t, app.balances.Flush(ctx), "failed to flush balances")
+	for _, name := range app.modules.InitOrder() {
+		caps, err := app.modules.Capabilities(name)
+		require.NoError(t, err)
+
+		var granted []any
+		if accountCap, err := caps.Account(); err == nil {
+			granted = append(granted, accountCap)
+		}
+		if balanceCap, err := caps.Balance(); err == nil {
+			granted = append(granted, balanceCap)
+		}
+		if validatorCap, err := caps.Validator(); err == nil {
+			granted = append(granted, validatorCap)
+		}
+		for _, c := range granted {
+			if f, ok := c.(flusher); ok {
+				require.NoError(t, f.Flush(ctx), "failed to flush capabilities of module %s", name)
+			}
+		}
+	}
+}
+
+// Context returns a context for the current block executing as account.
+func (app *TestApp) Context(t testing.TB, account types.AccountName) *runtime.Context {
+	t.Helper()
+
+	ctx, err := runtime.NewContext(context.Background(), app.header, account)
+	require.NoError(t, err)
+	return ctx
+}
+
+// Header returns the current block header.
+func (app *TestApp) Header() *runtime.BlockHeader {
+	return app.header
+}
+
+// Keyring returns the keyring holding the genesis accounts' keys.
+func (app *TestApp) Keyring() crypto.Keyring {
+	return app.keyring
+}
+
+// Application returns the runtime application transactions are delivered to.
+func (app *TestApp) Application() *runtime.Application {
+	return app.app
+}
+
+// ModuleManager returns the built module manager.
+func (app *TestApp) ModuleManager() *module.ModuleManager {
+	return app.modules
+}
+
+// Router returns the router of the built modules.
+func (app *TestApp) Router() *runtime.Router {
+	return app.app.Router()
+}
+
+// Store returns the in-memory state store. Read a module's state with
+// capability.ModuleStore(app.Store(), moduleName).
+func (app *TestApp) Store() store.BackingStore {
+	return app.state
+}
+
+// GrantStore grants moduleName its store capability over Store, e.g. for a
+// module wired outside the module manager such as an ante handler's. The
+// module is registered with the capability manager if it is not yet.
+func (app *TestApp) GrantStore(t testing.TB, moduleName string) capability.StoreCapability {
+	t.Helper()
+
+	if !app.capMgr.IsModuleRegistered(moduleName) {
+		require.NoError(t, app.capMgr.RegisterModule(moduleName))
+	}
+	storeCap, err := app.capMgr.GrantStoreCapability(moduleName)
+	require.NoError(t, err)
+	return storeCap
+}
+
+// AccountCapability returns the auth module's account capability.
+func (app *TestApp) AccountCapability() capability.AccountCapability {
+	return app.accountCap
+}
+
+// BalanceCapability returns the bank module's balance capability.
+func (app *TestApp) BalanceCapability() capability.BalanceCapability {
+	return app.balanceCap
+}
@@ -0,0 +1,182 @@
+package apptesting
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/events"
+	"github.com/blockberries/punnet-sdk/module"
+	"github.com/blockberries/punnet-sdk/modules/bank"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+func send(from, to types.AccountName, amount uint64) *bank.MsgSend {
+	return &bank.MsgSend{From: from, To: to, Amount: types.NewCoin(DefaultDenom, amount)}
+}
+
+func TestNewTestApp_Genesis(t *testing.T) {
+	app := NewTestApp(t)
+
+	for _, name := range WellKnownAccounts {
+		account := app.Account(t, name)
+		assert.Equal(t, uint64(0), account.Nonce)
+		assert.Equal(t, app.Header().Time, account.CreatedAt)
+		assert.Equal(t, uint64(1), account.Authority.KeyWeights[string(WellKnownPubKey(name))])
+		assert.Equal(t, uint64(1_000_000), app.Balance(t, name, DefaultDenom))
+
+		key, err := app.Keyring().GetKey(string(name))
+		require.NoError(t, err)
+		assert.Equal(t, WellKnownPubKey(name), key.PublicKey().Bytes())
+	}
+
+	custom := NewTestApp(t, WithGenesisAccounts(GenesisAccount{Name: "dave", Coins: types.NewCoins(types.NewCoin("uatom", 7))}))
+	assert.Equal(t, uint64(7), custom.Balance(t, "dave", "uatom"))
+	_, err := custom.AccountCapability().GetAccount(custom.Context(t, "dave").Context(), Alice)
+	assert.Error(t, err)
+}
+
+func TestWellKnownKey_Deterministic(t *testing.T) {
+	assert.Equal(t, WellKnownKey(Alice), WellKnownKey(Alice))
+	assert.NotEqual(t, WellKnownPubKey(Alice), WellKnownPubKey(Bob))
+
+	sig := ed25519.Sign(WellKnownKey(Bob), []byte("msg"))
+	assert.True(t, ed25519.Verify(WellKnownPubKey(Bob), []byte("msg"), sig))
+}
+
+func TestTestApp_ExecMsg(t *testing.T) {
+	app := NewTestApp(t)
+
+	result := app.ExecMsg(t, send(Alice, Bob, 250))
+	require.Len(t, result.Events, 1)
+	transfer, err := events.DecodeTransfer(result.Events[0])
+	require.NoError(t, err)
+	assert.Equal(t, events.Transfer{From: Alice, To: Bob, Amount: types.NewCoin(DefaultDenom, 250), Height: app.Header().Height}, transfer)
+
+	assert.Equal(t, uint64(999_750), app.Balance(t, Alice, DefaultDenom))
+	assert.Equal(t, uint64(1_000_250), app.Balance(t, Bob, DefaultDenom))
+	assert.Equal(t, uint64(1), app.Account(t, Alice).Nonce)
+
+	// The next message signs for the advanced nonce
+	app.ExecMsg(t, send(Alice, Carol, 50))
+	assert.Equal(t, uint64(2), app.Account(t, Alice).Nonce)
+
+	data, err := app.Query(t, "/balance", []byte("carol/"+DefaultDenom))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "1000050")
+}
+
+func TestTestApp_DeliverMsgErrors(t *testing.T) {
+	app := NewTestApp(t)
+
+	t.Run("handler error applies nothing but the nonce", func(t *testing.T) {
+		_, err := app.DeliverMsg(t, send(Alice, Bob, 2_000_000))
+		require.ErrorIs(t, err, types.ErrInsufficientFunds)
+		assert.Equal(t, uint64(1_000_000), app.Balance(t, Alice, DefaultDenom))
+		assert.Equal(t, uint64(1), app.Account(t, Alice).Nonce)
+	})
+
+	t.Run("stale nonce", func(t *testing.T) {
+		tx := app.SignTx(t, Alice, send(Alice, Bob, 1))
+		_, err := app.DeliverTx(tx)
+		require.NoError(t, err)
+		_, err = app.DeliverTx(tx)
+		require.Error(t, err)
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		tx := app.SignTx(t, Alice, send(Alice, Bob, 1))
+		tx.Account = Bob
+		tx.Messages = []types.Message{send(Bob, Alice, 1)}
+		_, err := app.DeliverTx(tx)
+		require.Error(t, err)
+		assert.Equal(t, uint64(0), app.Account(t, Bob).Nonce)
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		tx := app.SignTx(t, Alice, send(Alice, Bob, 1))
+		tx.Account = "dave"
+		tx.Messages = []types.Message{send("dave", Alice, 1)}
+		_, err := app.DeliverTx(tx)
+		require.ErrorIs(t, err, types.ErrNotFound)
+	})
+}
+
+func TestTestApp_FundAndCreateAccount(t *testing.T) {
+	app := NewTestApp(t, WithGenesisAccounts())
+
+	app.CreateAccount(t, "dave")
+	app.FundAccount(t, "dave", types.NewCoins(types.NewCoin(DefaultDenom, 10), types.NewCoin("uatom", 3)))
+	app.FundAccount(t, "dave", types.NewCoins(types.NewCoin(DefaultDenom, 5)))
+
+	assert.Equal(t, uint64(15), app.Balance(t, "dave", DefaultDenom))
+	assert.Equal(t, uint64(3), app.Balance(t, "dave", "uatom"))
+
+	app.CreateAccount(t, "erin")
+	app.ExecMsg(t, &bank.MsgSend{From: "dave", To: "erin", Amount: types.NewCoin("uatom", 2)})
+	assert.Equal(t, uint64(2), app.Balance(t, "erin", "uatom"))
+}
+
+func TestTestApp_NextBlock(t *testing.T) {
+	var heights []uint64
+	spec := module.ModuleSpec{
+		Name: "ticker",
+		Create: func(module.Capabilities) (module.Module, error) {
+			return module.NewModuleBuilder("ticker").
+				WithBeginBlocker(func(ctx *runtime.Context) ([]effects.Effect, error) {
+					heights = append(heights, ctx.BlockHeight())
+					return []effects.Effect{effects.NewStateWriteEffect("ticker", []byte("begin"), []byte{byte(ctx.BlockHeight())})}, nil
+				}).
+				WithEndBlocker(func(ctx *runtime.Context) ([]effects.Effect, []types.ValidatorUpdate, error) {
+					return []effects.Effect{effects.NewStateWriteEffect("ticker", []byte("end"), []byte{byte(ctx.BlockHeight())})}, nil, nil
+				}).
+				Build()
+		},
+	}
+
+	app := NewTestApp(t, WithModules(spec))
+	start := app.Header().Time
+
+	app.NextBlock(t)
+	app.NextBlock(t)
+
+	assert.Equal(t, []uint64{1, 2, 3}, heights)
+	assert.Equal(t, uint64(3), app.Header().Height)
+	_, committed := app.Application().LastCommitInfo()
+	assert.True(t, committed)
+	assert.Equal(t, start.Add(2*DefaultBlockInterval), app.Header().Time)
+
+	state := capability.ModuleStore(app.Store(), "ticker")
+	begin, err := state.Get([]byte("begin"))
+	require.NoError(t, err)
+	end, err := state.Get([]byte("end"))
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal([]byte{3}, begin))
+	assert.True(t, bytes.Equal([]byte{2}, end))
+}
+
+func TestTestApp_Commit(t *testing.T) {
+	app := NewTestApp(t)
+	app.ExecMsg(t, send(Alice, Bob, 5))
+
+	countBalances := func() int {
+		n := 0
+		require.NoError(t, app.BalanceCapability().IterateBalances(app.Context(t, Alice).Context(), func(store.Balance) error {
+			n++
+			return nil
+		}))
+		return n
+	}
+
+	// Iteration reads flushed balances only
+	assert.Zero(t, countBalances())
+	app.Commit(t)
+	assert.Equal(t, len(WellKnownAccounts), countBalances())
+}
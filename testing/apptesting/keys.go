@@ -0,0 +1,46 @@
+package apptesting
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Well-known test accounts, created and funded by NewTestApp by default.
+const (
+	Alice = types.AccountName("alice")
+	Bob   = types.AccountName("bob")
+	Carol = types.AccountName("carol")
+)
+
+// WellKnownAccounts lists the default genesis accounts.
+var WellKnownAccounts = []types.AccountName{Alice, Bob, Carol}
+
+// wellKnownKeyDomain separates well-known key seeds from other hashes
+const wellKnownKeyDomain = "punnet-sdk/apptesting/key/"
+
+// WellKnownKey returns the Ed25519 private key of the named test account.
+//
+// The key is derived from the name (seed = SHA-256 of a fixed domain and the
+// name), so every run, and every test outside a TestApp, sees the same keys
+// and signatures.
+//
+// SECURITY: Anyone can derive these keys. Use them only in tests.
+func WellKnownKey(name types.AccountName) ed25519.PrivateKey {
+	seed := sha256.Sum256([]byte(wellKnownKeyDomain + string(name)))
+	return ed25519.NewKeyFromSeed(seed[:])
+}
+
+// WellKnownPubKey returns the Ed25519 public key of the named test account.
+func WellKnownPubKey(name types.AccountName) []byte {
+	return WellKnownKey(name).Public().(ed25519.PublicKey)
+}
+
+// importWellKnownKey adds the named account's well-known key to kr under the
+// account name.
+func importWellKnownKey(kr crypto.Keyring, name types.AccountName) error {
+	_, err := kr.ImportKey(string(name), WellKnownKey(name), crypto.AlgorithmEd25519)
+	return err
+}
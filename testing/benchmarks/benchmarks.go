@@ -0,0 +1,232 @@
+// Package benchmarks compares key generation, signing, verification and
+// SignDoc hashing across the SDK's transaction signing algorithms.
+//
+// Cases returns one Case per (algorithm, operation, SignDoc size). Run
+// registers them as sub-benchmarks for go test -bench; Measure times them
+// without the testing package and returns a Report that can be written as
+// JSON and compared against a stored baseline with Compare.
+//
+// Signing and verification include hashing the SignDoc, as on the real
+// path: a client hashes and signs, a validator hashes and verifies. Hashing
+// alone is algorithm-independent and is reported once per size.
+//
+// Usage:
+//
+//	go test -bench . ./testing/benchmarks
+//	go run ./testing/benchmarks/cmd/benchreport -o bench.json
+//	go run ./testing/benchmarks/cmd/benchreport -baseline bench.json
+package benchmarks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Algorithms are the algorithms benchmarked by default.
+var Algorithms = []crypto.Algorithm{
+	crypto.AlgorithmEd25519,
+	crypto.AlgorithmSecp256k1,
+	crypto.AlgorithmSecp256r1,
+}
+
+// Operation is a benchmarked operation.
+type Operation string
+
+const (
+	// OpKeyGen generates a private key
+	OpKeyGen Operation = "keygen"
+
+	// OpSign hashes a SignDoc and signs the hash
+	OpSign Operation = "sign"
+
+	// OpVerify hashes a SignDoc and verifies a signature over the hash
+	OpVerify Operation = "verify"
+
+	// OpSignDocHash serializes a SignDoc to canonical JSON and hashes it
+	OpSignDocHash Operation = "signdoc_hash"
+)
+
+// SignDocSize describes the SignDocs built for a size class.
+type SignDocSize struct {
+	// Name identifies the size class in case names, e.g. "small"
+	Name string `json:"name"`
+
+	// Messages is the number of bank send messages in the SignDoc
+	Messages int `json:"messages"`
+
+	// MemoLength is the memo length in bytes
+	MemoLength int `json:"memo_length"`
+}
+
+// DefaultSizes are the SignDoc sizes benchmarked by default: a single
+// transfer, a small batch and a large batch with a long memo.
+var DefaultSizes = []SignDocSize{
+	{Name: "small", Messages: 1, MemoLength: 0},
+	{Name: "medium", Messages: 10, MemoLength: 64},
+	{Name: "large", Messages: 100, MemoLength: 256},
+}
+
+// Case is a single benchmark: one operation, for one algorithm and SignDoc
+// size where they apply.
+type Case struct {
+	// Algorithm is empty for OpSignDocHash
+	Algorithm crypto.Algorithm
+
+	Operation Operation
+
+	// Size is the zero value for OpKeyGen
+	Size SignDocSize
+
+	// SignDocBytes is the length of the SignDoc's canonical JSON, or zero
+	// for OpKeyGen
+	SignDocBytes int
+
+	// run performs the operation n times
+	run func(n int) error
+}
+
+// Name returns the case's name, e.g. "ed25519/sign/small". It is the
+// sub-benchmark name under Run and the key used by Compare.
+func (c Case) Name() string {
+	parts := make([]string, 0, 3)
+	if c.Algorithm != "" {
+		parts = append(parts, string(c.Algorithm))
+	}
+	parts = append(parts, string(c.Operation))
+	if c.Size.Name != "" {
+		parts = append(parts, c.Size.Name)
+	}
+	return strings.Join(parts, "/")
+}
+
+// Cases builds the cases for algos and sizes: per algorithm one keygen case
+// and a sign and a verify case per size, then one hash case per size.
+//
+// Keys, SignDocs and signatures are prepared here, so running a case
+// measures only its operation.
+func Cases(algos []crypto.Algorithm, sizes []SignDocSize) ([]Case, error) {
+	docs := make([]*types.SignDoc, len(sizes))
+	docBytes := make([]int, len(sizes))
+	for i, size := range sizes {
+		if size.Name == "" || size.Messages < 1 || size.MemoLength < 0 {
+			return nil, fmt.Errorf("invalid SignDoc size %+v", size)
+		}
+		doc, err := NewSignDoc(size)
+		if err != nil {
+			return nil, err
+		}
+		docs[i] = doc
+		data, err := docs[i].ToJSON()
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize %s SignDoc: %w", size.Name, err)
+		}
+		docBytes[i] = len(data)
+	}
+
+	var cases []Case
+	for _, algo := range algos {
+		key, err := crypto.GeneratePrivateKey(algo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate %s key: %w", algo, err)
+		}
+
+		cases = append(cases, Case{Algorithm: algo, Operation: OpKeyGen, run: keyGen(algo)})
+		for i, size := range sizes {
+			signBytes, err := docs[i].GetSignBytes()
+			if err != nil {
+				return nil, err
+			}
+			signature, err := key.Sign(signBytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to sign with %s: %w", algo, err)
+			}
+
+			cases = append(cases,
+				Case{Algorithm: algo, Operation: OpSign, Size: size, SignDocBytes: docBytes[i], run: sign(key, docs[i])},
+				Case{Algorithm: algo, Operation: OpVerify, Size: size, SignDocBytes: docBytes[i], run: verify(key.PublicKey(), docs[i], signature)},
+			)
+		}
+	}
+	for i, size := range sizes {
+		cases = append(cases, Case{Operation: OpSignDocHash, Size: size, SignDocBytes: docBytes[i], run: hash(docs[i])})
+	}
+	return cases, nil
+}
+
+// NewSignDoc builds a deterministic SignDoc of the given size: size.Messages
+// bank sends from alice and a memo of size.MemoLength bytes.
+func NewSignDoc(size SignDocSize) (*types.SignDoc, error) {
+	doc, err := types.NewSignDoc("bench-chain", 7, "alice", 7, strings.Repeat("m", size.MemoLength))
+	if err != nil {
+		return nil, err
+	}
+	for i := range size.Messages {
+		data, _ := json.Marshal(map[string]any{
+			"from":   "alice",
+			"to":     fmt.Sprintf("recipient%d", i),
+			"amount": []map[string]string{{"denom": "stake", "amount": fmt.Sprint(1000 + i)}},
+		})
+		doc.AddMessage("/punnet.bank.v1.MsgSend", data)
+	}
+	return doc, nil
+}
+
+// keyGen returns a run function generating algo keys
+func keyGen(algo crypto.Algorithm) func(int) error {
+	return func(n int) error {
+		for range n {
+			if _, err := crypto.GeneratePrivateKey(algo); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// sign returns a run function hashing doc and signing the hash with key
+func sign(key crypto.PrivateKey, doc *types.SignDoc) func(int) error {
+	return func(n int) error {
+		for range n {
+			signBytes, err := doc.GetSignBytes()
+			if err != nil {
+				return err
+			}
+			if _, err := key.Sign(signBytes); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// verify returns a run function hashing doc and verifying signature
+func verify(pubKey crypto.PublicKey, doc *types.SignDoc, signature []byte) func(int) error {
+	return func(n int) error {
+		for range n {
+			signBytes, err := doc.GetSignBytes()
+			if err != nil {
+				return err
+			}
+			if !pubKey.Verify(signBytes, signature) {
+				return fmt.Errorf("%s signature did not verify", pubKey.Algorithm())
+			}
+		}
+		return nil
+	}
+}
+
+// hash returns a run function computing doc's sign bytes
+func hash(doc *types.SignDoc) func(int) error {
+	return func(n int) error {
+		for range n {
+			if _, err := doc.GetSignBytes(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
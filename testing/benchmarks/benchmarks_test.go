@@ -0,0 +1,115 @@
+package benchmarks
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+)
+
+// BenchmarkAlgorithms compares all algorithms at all default sizes.
+// Filter with e.g. -bench 'Algorithms/secp256k1/verify'.
+func BenchmarkAlgorithms(b *testing.B) {
+	cases, err := Cases(Algorithms, DefaultSizes)
+	if err != nil {
+		b.Fatal(err)
+	}
+	Run(b, cases)
+}
+
+func TestCases(t *testing.T) {
+	cases, err := Cases(Algorithms, DefaultSizes)
+	require.NoError(t, err)
+
+	// Per algorithm: keygen plus sign and verify per size; then hash per size
+	require.Len(t, cases, len(Algorithms)*(1+2*len(DefaultSizes))+len(DefaultSizes))
+
+	names := make(map[string]bool)
+	for _, c := range cases {
+		assert.False(t, names[c.Name()], "duplicate case %s", c.Name())
+		names[c.Name()] = true
+
+		// Every case runs without error
+		require.NoError(t, c.run(2), c.Name())
+	}
+	assert.True(t, names["ed25519/keygen"])
+	assert.True(t, names["secp256r1/verify/large"])
+	assert.True(t, names["signdoc_hash/small"])
+
+	// Larger size classes produce larger SignDocs
+	var hashBytes []int
+	for _, c := range cases {
+		if c.Operation == OpSignDocHash {
+			hashBytes = append(hashBytes, c.SignDocBytes)
+		}
+	}
+	assert.IsIncreasing(t, hashBytes)
+
+	_, err = Cases(Algorithms, []SignDocSize{{Name: "empty"}})
+	require.Error(t, err)
+	_, err = Cases([]crypto.Algorithm{"unknown"}, DefaultSizes)
+	require.Error(t, err)
+}
+
+func TestNewSignDoc_Deterministic(t *testing.T) {
+	docA, err := NewSignDoc(DefaultSizes[1])
+	require.NoError(t, err)
+	a, err := docA.ToJSON()
+	require.NoError(t, err)
+	docB, err := NewSignDoc(DefaultSizes[1])
+	require.NoError(t, err)
+	b, err := docB.ToJSON()
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+}
+
+func TestMeasure(t *testing.T) {
+	cases, err := Cases([]crypto.Algorithm{crypto.AlgorithmEd25519}, DefaultSizes[:1])
+	require.NoError(t, err)
+
+	report, err := Measure(cases, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, ReportVersion, report.Version)
+	require.Len(t, report.Results, len(cases))
+	for i, r := range report.Results {
+		assert.Equal(t, cases[i].Name(), r.Name)
+		assert.Positive(t, r.Iterations)
+		assert.Positive(t, r.NsPerOp)
+	}
+	assert.Equal(t, "sign", report.Results[1].Operation)
+	assert.Equal(t, "small", report.Results[1].Size)
+
+	// The report round-trips through JSON
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJSON(&buf))
+	decoded, err := ReadReport(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, report, decoded)
+
+	_, err = ReadReport(bytes.NewReader([]byte(`{"version":"0"}`)))
+	require.Error(t, err)
+}
+
+func TestCompare(t *testing.T) {
+	baseline := &Report{Results: []Result{
+		{Name: "ed25519/sign/small", NsPerOp: 100},
+		{Name: "ed25519/verify/small", NsPerOp: 200},
+		{Name: "removed", NsPerOp: 50},
+	}}
+	current := &Report{Results: []Result{
+		{Name: "ed25519/sign/small", NsPerOp: 150},
+		{Name: "ed25519/verify/small", NsPerOp: 210},
+		{Name: "added", NsPerOp: 1000},
+	}}
+
+	regressions := Compare(baseline, current, 0.1)
+	require.Len(t, regressions, 1)
+	assert.Equal(t, "ed25519/sign/small", regressions[0].Name)
+	assert.InDelta(t, 1.5, regressions[0].Ratio, 1e-9)
+
+	assert.Empty(t, Compare(baseline, current, 0.5))
+}
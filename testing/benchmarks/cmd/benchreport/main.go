@@ -0,0 +1,108 @@
+// Command benchreport measures the signing algorithm benchmarks and writes
+// the report as JSON, optionally comparing it against a baseline report.
+//
+// Usage:
+//
+//	benchreport [-o <file>] [-time <duration>] [-baseline <file>] [-threshold <ratio>]
+//
+// The report is written to -o (stdout if omitted). With -baseline, cases
+// slower than the baseline by more than -threshold (default 0.2 = 20%) are
+// listed on stderr.
+//
+// Exit codes:
+//
+//	0 - report written, no regressions
+//	1 - at least one regression against the baseline
+//	2 - usage, benchmark or I/O error
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/blockberries/punnet-sdk/testing/benchmarks"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command and returns the process exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("benchreport", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("o", "", "output file (default stdout)")
+	minTime := fs.Duration("time", benchmarks.DefaultMinTime, "minimum run time per case")
+	baselinePath := fs.String("baseline", "", "baseline report to compare against")
+	threshold := fs.Float64("threshold", 0.2, "slowdown ratio reported as a regression")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	var baseline *benchmarks.Report
+	if *baselinePath != "" {
+		f, err := os.Open(*baselinePath)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 2
+		}
+		baseline, err = benchmarks.ReadReport(f)
+		f.Close()
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %s: %v\n", *baselinePath, err)
+			return 2
+		}
+	}
+
+	cases, err := benchmarks.Cases(benchmarks.Algorithms, benchmarks.DefaultSizes)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 2
+	}
+	report, err := benchmarks.Measure(cases, *minTime)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 2
+	}
+
+	if err := writeReport(report, *out, stdout); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 2
+	}
+
+	if baseline == nil {
+		return 0
+	}
+	regressions := benchmarks.Compare(baseline, report, *threshold)
+	for _, r := range regressions {
+		fmt.Fprintf(stderr, "REGRESSION %s: %.0f ns/op -> %.0f ns/op (x%.2f)\n",
+			r.Name, r.BaselineNsPerOp, r.CurrentNsPerOp, r.Ratio)
+	}
+	if len(regressions) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// writeReport writes report to path, or to stdout if path is empty.
+func writeReport(report *benchmarks.Report, path string, stdout io.Writer) error {
+	if path == "" {
+		return report.WriteJSON(stdout)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := report.WriteJSON(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
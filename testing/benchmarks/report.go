@@ -0,0 +1,203 @@
+package benchmarks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"testing"
+	"time"
+)
+
+// ReportVersion is the version of the Report JSON format.
+const ReportVersion = "1"
+
+// DefaultMinTime is how long Measure runs each case by default.
+const DefaultMinTime = 200 * time.Millisecond
+
+// maxIterations bounds the iterations of a single measurement
+const maxIterations = 1_000_000_000
+
+// Run registers every case as a sub-benchmark of b, named by Case.Name.
+//
+// Usage:
+//
+//	func BenchmarkAlgorithms(b *testing.B) {
+//		cases, err := benchmarks.Cases(benchmarks.Algorithms, benchmarks.DefaultSizes)
+//		if err != nil {
+//			b.Fatal(err)
+//		}
+//		benchmarks.Run(b, cases)
+//	}
+func Run(b *testing.B, cases []Case) {
+	for _, c := range cases {
+		b.Run(c.Name(), func(b *testing.B) {
+			b.ReportAllocs()
+			if c.SignDocBytes > 0 {
+				b.SetBytes(int64(c.SignDocBytes))
+			}
+			b.ResetTimer()
+			if err := c.run(b.N); err != nil {
+				b.Fatal(err)
+			}
+		})
+	}
+}
+
+// Report is the result of Measure, encoded as JSON for regression tracking.
+type Report struct {
+	// Version is ReportVersion
+	Version string `json:"version"`
+
+	// Environment the report was measured in; reports from different
+	// machines are not comparable
+	GoVersion string `json:"go_version"`
+	GOOS      string `json:"goos"`
+	GOARCH    string `json:"goarch"`
+	NumCPU    int    `json:"num_cpu"`
+
+	Results []Result `json:"results"`
+}
+
+// Result is the measurement of one Case.
+type Result struct {
+	// Name is Case.Name
+	Name         string `json:"name"`
+	Algorithm    string `json:"algorithm,omitempty"`
+	Operation    string `json:"operation"`
+	Size         string `json:"size,omitempty"`
+	SignDocBytes int    `json:"signdoc_bytes,omitempty"`
+
+	Iterations  int     `json:"iterations"`
+	NsPerOp     float64 `json:"ns_per_op"`
+	AllocsPerOp uint64  `json:"allocs_per_op"`
+	BytesPerOp  uint64  `json:"bytes_per_op"`
+}
+
+// Measure runs each case for at least minTime and returns the report.
+// A non-positive minTime means DefaultMinTime.
+//
+// Like go test -bench it grows the iteration count until a run lasts
+// minTime, and it counts allocations over the final run.
+func Measure(cases []Case, minTime time.Duration) (*Report, error) {
+	if minTime <= 0 {
+		minTime = DefaultMinTime
+	}
+
+	report := &Report{
+		Version:   ReportVersion,
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		NumCPU:    runtime.NumCPU(),
+		Results:   make([]Result, 0, len(cases)),
+	}
+	for _, c := range cases {
+		result, err := measure(c, minTime)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.Name(), err)
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// measure times a single case
+func measure(c Case, minTime time.Duration) (Result, error) {
+	n := 1
+	for {
+		runtime.GC()
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		if err := c.run(n); err != nil {
+			return Result{}, err
+		}
+		elapsed := time.Since(start)
+		runtime.ReadMemStats(&after)
+
+		if elapsed >= minTime || n >= maxIterations {
+			return Result{
+				Name:         c.Name(),
+				Algorithm:    string(c.Algorithm),
+				Operation:    string(c.Operation),
+				Size:         c.Size.Name,
+				SignDocBytes: c.SignDocBytes,
+				Iterations:   n,
+				NsPerOp:      float64(elapsed.Nanoseconds()) / float64(n),
+				AllocsPerOp:  (after.Mallocs - before.Mallocs) / uint64(n),
+				BytesPerOp:   (after.TotalAlloc - before.TotalAlloc) / uint64(n),
+			}, nil
+		}
+
+		// Aim 20% past minTime, growing at least 2x and at most 100x per round
+		next := n * 100
+		if elapsed > 0 {
+			next = int(float64(n) * 1.2 * float64(minTime) / float64(elapsed))
+		}
+		n = min(max(next, 2*n), 100*n, maxIterations)
+	}
+}
+
+// WriteJSON writes r as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// ReadReport decodes a report written by WriteJSON.
+func ReadReport(rd io.Reader) (*Report, error) {
+	var report Report
+	if err := json.NewDecoder(rd).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to decode benchmark report: %w", err)
+	}
+	if report.Version != ReportVersion {
+		return nil, fmt.Errorf("unsupported benchmark report version %q", report.Version)
+	}
+	return &report, nil
+}
+
+// Regression is a case that got slower between two reports.
+type Regression struct {
+	Name            string  `json:"name"`
+	BaselineNsPerOp float64 `json:"baseline_ns_per_op"`
+	CurrentNsPerOp  float64 `json:"current_ns_per_op"`
+
+	// Ratio is CurrentNsPerOp / BaselineNsPerOp
+	Ratio float64 `json:"ratio"`
+}
+
+// Compare returns the cases present in both reports whose time per
+// operation grew by more than threshold (0.1 = 10%), sorted by name.
+// Cases in only one report are ignored.
+func Compare(baseline, current *Report, threshold float64) []Regression {
+	base := make(map[string]float64, len(baseline.Results))
+	for _, r := range baseline.Results {
+		base[r.Name] = r.NsPerOp
+	}
+
+	var regressions []Regression
+	for _, r := range current.Results {
+		was, ok := base[r.Name]
+		if !ok || was <= 0 {
+			continue
+		}
+		if ratio := r.NsPerOp / was; ratio > 1+threshold {
+			regressions = append(regressions, Regression{
+				Name:            r.Name,
+				BaselineNsPerOp: was,
+				CurrentNsPerOp:  r.NsPerOp,
+				Ratio:           ratio,
+			})
+		}
+	}
+	sort.Slice(regressions, func(i, j int) bool {
+		return regressions[i].Name < regressions[j].Name
+	})
+	return regressions
+}
@@ -0,0 +1,133 @@
+package testing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+// Defaults used by NewContext.
+const (
+	DefaultChainID = "test-chain"
+	DefaultHeight  = uint64(1)
+	DefaultAccount = types.AccountName("alice")
+)
+
+// DefaultBlockTime is the fixed block time used by NewContext, so tests that
+// depend on block time or RandomSeed are reproducible.
+var DefaultBlockTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// contextConfig holds the settings applied by ContextOptions.
+type contextConfig struct {
+	ctx       context.Context
+	height    uint64
+	blockTime time.Time
+	chainID   string
+	proposer  []byte
+	blockHash []byte
+	account   types.AccountName
+	txHash    []byte
+	gasMeter  runtime.GasMeter
+	readOnly  bool
+}
+
+// ContextOption configures NewContext.
+type ContextOption func(*contextConfig)
+
+// WithHeight sets the block height.
+func WithHeight(height uint64) ContextOption {
+	return func(c *contextConfig) { c.height = height }
+}
+
+// WithBlockTime sets the block time.
+func WithBlockTime(t time.Time) ContextOption {
+	return func(c *contextConfig) { c.blockTime = t }
+}
+
+// WithChainID sets the chain ID.
+func WithChainID(chainID string) ContextOption {
+	return func(c *contextConfig) { c.chainID = chainID }
+}
+
+// WithProposer sets the block proposer address.
+func WithProposer(proposer []byte) ContextOption {
+	return func(c *contextConfig) { c.proposer = proposer }
+}
+
+// WithBlockHash sets the block hash (and thereby the random seed).
+func WithBlockHash(hash []byte) ContextOption {
+	return func(c *contextConfig) { c.blockHash = hash }
+}
+
+// WithAccount sets the executing account.
+func WithAccount(account types.AccountName) ContextOption {
+	return func(c *contextConfig) { c.account = account }
+}
+
+// WithTxHash sets the transaction hash (and thereby the random seed).
+func WithTxHash(txHash []byte) ContextOption {
+	return func(c *contextConfig) { c.txHash = txHash }
+}
+
+// WithGasLimit installs a gas meter with the given limit.
+func WithGasLimit(limit uint64) ContextOption {
+	return func(c *contextConfig) { c.gasMeter = runtime.NewGasMeter(limit) }
+}
+
+// WithGoContext sets the underlying Go context.
+func WithGoContext(ctx context.Context) ContextOption {
+	return func(c *contextConfig) { c.ctx = ctx }
+}
+
+// ReadOnly makes NewContext return a read-only (CheckTx) context.
+func ReadOnly() ContextOption {
+	return func(c *contextConfig) { c.readOnly = true }
+}
+
+// NewContext returns a runtime.Context for handler tests.
+//
+// Defaults: DefaultChainID, DefaultHeight, DefaultBlockTime, DefaultAccount,
+// no transaction hash, and an infinite gas meter. Everything is fixed, so
+// the context's RandomSeed is identical across runs.
+//
+// Usage:
+//
+//	ctx := punnettesting.NewContext(t, punnettesting.WithHeight(42), punnettesting.WithGasLimit(100_000))
+//	effects, err := handler(ctx, msg)
+func NewContext(t testing.TB, opts ...ContextOption) *runtime.Context {
+	t.Helper()
+
+	cfg := &contextConfig{
+		ctx:       context.Background(),
+		height:    DefaultHeight,
+		blockTime: DefaultBlockTime,
+		chainID:   DefaultChainID,
+		account:   DefaultAccount,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	header := runtime.NewBlockHeader(cfg.height, cfg.blockTime, cfg.chainID, cfg.proposer)
+	if cfg.blockHash != nil {
+		header = header.WithHash(cfg.blockHash)
+	}
+	newCtx := runtime.NewContext
+	if cfg.readOnly {
+		newCtx = runtime.NewReadOnlyContext
+	}
+	rctx, err := newCtx(cfg.ctx, header, cfg.account)
+	require.NoError(t, err, "invalid test context configuration")
+
+	if cfg.txHash != nil {
+		rctx = rctx.WithTxHash(cfg.txHash)
+	}
+	if cfg.gasMeter != nil {
+		rctx = rctx.WithGasMeter(cfg.gasMeter)
+	}
+	return rctx
+}
@@ -0,0 +1,55 @@
+package testing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewContext_Defaults(t *testing.T) {
+	ctx := NewContext(t)
+
+	assert.Equal(t, DefaultChainID, ctx.ChainID())
+	assert.Equal(t, DefaultHeight, ctx.BlockHeight())
+	assert.True(t, DefaultBlockTime.Equal(ctx.BlockTime()))
+	assert.Equal(t, DefaultAccount, ctx.Account())
+	assert.Nil(t, ctx.TxHash())
+	assert.False(t, ctx.IsReadOnly())
+	assert.False(t, ctx.GasMeter().IsOutOfGas())
+
+	// Fixed defaults give a reproducible seed.
+	assert.Equal(t, ctx.RandomSeed(), NewContext(t).RandomSeed())
+}
+
+func TestNewContext_Options(t *testing.T) {
+	blockTime := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	ctx := NewContext(t,
+		WithHeight(42),
+		WithBlockTime(blockTime),
+		WithChainID("other-chain"),
+		WithProposer([]byte("proposer")),
+		WithBlockHash([]byte("block")),
+		WithAccount("bob"),
+		WithTxHash([]byte{1, 2, 3}),
+		WithGasLimit(100),
+		ReadOnly(),
+	)
+
+	assert.Equal(t, uint64(42), ctx.BlockHeight())
+	assert.True(t, blockTime.Equal(ctx.BlockTime()))
+	assert.Equal(t, "other-chain", ctx.ChainID())
+	assert.Equal(t, []byte("proposer"), ctx.ProposerAddress())
+	assert.Equal(t, []byte("block"), ctx.BlockHash())
+	assert.Equal(t, "bob", string(ctx.Account()))
+	assert.Equal(t, []byte{1, 2, 3}, ctx.TxHash())
+	assert.True(t, ctx.IsReadOnly())
+	assert.Equal(t, uint64(100), ctx.GasMeter().Limit())
+
+	require.NoError(t, ctx.ConsumeGas(100))
+	assert.ErrorIs(t, ctx.ConsumeGas(1), runtime.ErrOutOfGas)
+
+	assert.NotEqual(t, NewContext(t).RandomSeed(), ctx.RandomSeed())
+}
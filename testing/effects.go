@@ -0,0 +1,219 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// EffectEnv applies the effects of module handlers called directly by unit
+// tests, through the runtime.EffectApplier and effects.Executor that
+// runtime.Application uses:
+//
+//	env := punnettesting.NewEffectEnv(t)
+//	mod, _ := mymodule.NewMyModule(env.Store())
+//	effs, err := mod.HandleThing(ctx, msg)
+//	env.Apply(t, ctx, effs)
+//
+// State effects write to Store, transfers move balances through the
+// capability given to UseBalances and account writes go through the one
+// given to UseAccounts. Apply fails the test on any effect the executor
+// would not persist: a typed write without an encoded value (which the
+// executor stores as a placeholder), or a transfer or account write without
+// a capability to apply it to.
+type EffectEnv struct {
+	backing  store.BackingStore
+	capMgr   *capability.CapabilityManager
+	applier  *runtime.EffectApplier
+	balances capability.BalanceCapability
+	accounts capability.AccountCapability
+}
+
+// NewEffectEnv creates an environment over an empty in-memory store
+func NewEffectEnv(t testing.TB) *EffectEnv {
+	t.Helper()
+
+	backing := store.NewMemoryStore()
+	env := &EffectEnv{
+		backing: backing,
+		capMgr:  capability.NewCapabilityManager(backing),
+	}
+
+	executor, err := effects.NewExecutor(&envStore{store: backing, capMgr: env.capMgr}, &envBalanceStore{env: env})
+	require.NoError(t, err)
+	executor.SetAccountStore(&envAccountStore{env: env})
+	env.applier, err = runtime.NewEffectApplier(executor)
+	require.NoError(t, err)
+	return env
+}
+
+// Store returns the state store effects are applied to
+func (e *EffectEnv) Store() store.BackingStore {
+	return e.backing
+}
+
+// CapabilityManager returns the capability manager over Store
+func (e *EffectEnv) CapabilityManager() *capability.CapabilityManager {
+	return e.capMgr
+}
+
+// GrantStore registers moduleName with CapabilityManager, if it is not yet
+// registered, and grants it its store capability
+func (e *EffectEnv) GrantStore(t testing.TB, moduleName string) capability.StoreCapability {
+	t.Helper()
+
+	if !e.capMgr.IsModuleRegistered(moduleName) {
+		require.NoError(t, e.capMgr.RegisterModule(moduleName))
+	}
+	storeCap, err := e.capMgr.GrantStoreCapability(moduleName)
+	require.NoError(t, err)
+	return storeCap
+}
+
+// UseBalances applies transfer effects to balanceCap
+func (e *EffectEnv) UseBalances(balanceCap capability.BalanceCapability) {
+	e.balances = balanceCap
+}
+
+// UseAccounts applies account write effects to accountCap
+func (e *EffectEnv) UseAccounts(accountCap capability.AccountCapability) {
+	e.accounts = accountCap
+}
+
+// Apply applies effs in ctx and fails t unless every effect was persisted
+func (e *EffectEnv) Apply(t testing.TB, ctx *runtime.Context, effs []effects.Effect) *effects.ExecutionResult {
+	t.Helper()
+
+	for i, eff := range effs {
+		require.NoError(t, e.checkPersisted(eff), "effect %d (%T)", i, eff)
+	}
+	result, _, err := e.applier.Apply(ctx, effs)
+	require.NoError(t, err, "failed to apply effects")
+	return result
+}
+
+// checkPersisted returns an error if the executor would not persist eff
+func (e *EffectEnv) checkPersisted(eff effects.Effect) error {
+	switch eff.(type) {
+	case effects.AccountWriteEffect:
+		if e.accounts == nil {
+			return fmt.Errorf("account write without UseAccounts")
+		}
+		return nil
+	case effects.TransferEffect:
+		if e.balances == nil {
+			return fmt.Errorf("transfer without UseBalances")
+		}
+		return nil
+	}
+
+	if eff.Type() == effects.EffectTypeWrite {
+		if _, ok := eff.(effects.ValueEncoder); !ok {
+			return fmt.Errorf("write of %q carries no encoded value", eff.Key())
+		}
+	}
+	return nil
+}
+
+// envStore adapts store.BackingStore to effects.Store, keeping the module
+// indexes registered with capMgr in sync as the runtime does
+type envStore struct {
+	store  store.BackingStore
+	capMgr *capability.CapabilityManager
+}
+
+func (a *envStore) Get(key []byte) ([]byte, error) {
+	return a.store.Get(key)
+}
+
+func (a *envStore) Set(key []byte, value []byte) error {
+	indexed, moduleKey, err := a.capMgr.IndexedStoreOf(key)
+	if err != nil {
+		return err
+	}
+	if indexed != nil {
+		return indexed.Set(moduleKey, value)
+	}
+	return a.store.Set(key, value)
+}
+
+func (a *envStore) Delete(key []byte) error {
+	indexed, moduleKey, err := a.capMgr.IndexedStoreOf(key)
+	if err != nil {
+		return err
+	}
+	if indexed != nil {
+		return indexed.Delete(moduleKey)
+	}
+	return a.store.Delete(key)
+}
+
+func (a *envStore) Has(key []byte) bool {
+	has, _ := a.store.Has(key)
+	return has
+}
+
+// envBalanceStore applies transfers to the environment's balance capability
+type envBalanceStore struct {
+	env *EffectEnv
+}
+
+func (a *envBalanceStore) capability() (capability.BalanceCapability, error) {
+	if a.env.balances == nil {
+		return nil, fmt.Errorf("no balance capability (see UseBalances)")
+	}
+	return a.env.balances, nil
+}
+
+func (a *envBalanceStore) GetBalance(account types.AccountName, denom string) (uint64, error) {
+	balanceCap, err := a.capability()
+	if err != nil {
+		return 0, err
+	}
+	return balanceCap.GetBalance(context.Background(), account, denom)
+}
+
+func (a *envBalanceStore) SetBalance(account types.AccountName, denom string, amount uint64) error {
+	balanceCap, err := a.capability()
+	if err != nil {
+		return err
+	}
+	return balanceCap.SetBalance(context.Background(), account, denom, amount)
+}
+
+func (a *envBalanceStore) SubBalance(account types.AccountName, denom string, amount uint64) error {
+	balanceCap, err := a.capability()
+	if err != nil {
+		return err
+	}
+	return balanceCap.SubBalance(context.Background(), account, denom, amount)
+}
+
+func (a *envBalanceStore) AddBalance(account types.AccountName, denom string, amount uint64) error {
+	balanceCap, err := a.capability()
+	if err != nil {
+		return err
+	}
+	return balanceCap.AddBalance(context.Background(), account, denom, amount)
+}
+
+// envAccountStore applies account writes to the environment's account
+// capability
+type envAccountStore struct {
+	env *EffectEnv
+}
+
+func (a *envAccountStore) SetAccount(account *types.Account) error {
+	if a.env.accounts == nil {
+		return fmt.Errorf("no account capability (see UseAccounts)")
+	}
+	return a.env.accounts.UpdateAccount(context.Background(), account)
+}
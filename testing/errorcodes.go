@@ -0,0 +1,80 @@
+package testing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdkerrors "github.com/blockberries/punnet-sdk/errors"
+)
+
+// UpdateErrorCodesEnv is the environment variable that makes
+// RequireStableErrorCodes rewrite its golden file instead of checking it.
+const UpdateErrorCodesEnv = "UPDATE_ERROR_CODES"
+
+// RequireStableErrorCodes fails t unless the registered error codes (see
+// sdkerrors.Codes) match the golden JSON file at filename.
+//
+// Codes are part of the wire protocol, so a released codespace/code must keep
+// its meaning: changing or removing a recorded code fails the check. New codes
+// also fail it until they are recorded by running the test with
+// UPDATE_ERROR_CODES=1, which makes every addition a reviewed golden-file diff.
+//
+// Only codes of packages linked into the test binary are registered; import
+// every package whose codes the golden file should cover.
+func RequireStableErrorCodes(t testing.TB, filename string) {
+	t.Helper()
+	requireStableCodes(t, filename, sdkerrors.Codes())
+}
+
+// requireStableCodes checks current against the golden file at filename
+func requireStableCodes(t testing.TB, filename string, current []sdkerrors.Code) {
+	t.Helper()
+
+	if os.Getenv(UpdateErrorCodesEnv) == "1" {
+		data, err := json.MarshalIndent(current, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filename, append(data, '\n'), 0o644))
+		t.Logf("wrote %d error codes to %s", len(current), filename)
+		return
+	}
+
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("%s not found - run with %s=1 to create it", filename, UpdateErrorCodesEnv)
+		return
+	}
+	require.NoError(t, err)
+
+	var golden []sdkerrors.Code
+	require.NoError(t, json.Unmarshal(data, &golden))
+
+	key := func(c sdkerrors.Code) string { return fmt.Sprintf("%s/%d", c.Codespace, c.Code) }
+	registered := make(map[string]sdkerrors.Code, len(current))
+	for _, c := range current {
+		registered[key(c)] = c
+	}
+
+	recorded := make(map[string]bool, len(golden))
+	for _, want := range golden {
+		recorded[key(want)] = true
+		got, ok := registered[key(want)]
+		if !ok {
+			t.Errorf("error code %s (%q) was removed; released codes must stay registered", key(want), want.Message)
+			continue
+		}
+		if got.Message != want.Message {
+			t.Errorf("error code %s changed from %q to %q; released codes must keep their meaning", key(want), want.Message, got.Message)
+		}
+	}
+
+	for _, c := range current {
+		if !recorded[key(c)] {
+			t.Errorf("error code %s (%q) is not recorded in %s; run with %s=1 to add it", key(c), c.Message, filename, UpdateErrorCodesEnv)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package testing
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdkerrors "github.com/blockberries/punnet-sdk/errors"
+
+	// Linked for their error code registrations
+	_ "github.com/blockberries/punnet-sdk/module"
+	_ "github.com/blockberries/punnet-sdk/modules/bank"
+	_ "github.com/blockberries/punnet-sdk/modules/circuit"
+	_ "github.com/blockberries/punnet-sdk/modules/escrow"
+	_ "github.com/blockberries/punnet-sdk/modules/evidence"
+	_ "github.com/blockberries/punnet-sdk/modules/feemarket"
+	_ "github.com/blockberries/punnet-sdk/modules/nft"
+	_ "github.com/blockberries/punnet-sdk/modules/oracle"
+	_ "github.com/blockberries/punnet-sdk/modules/recovery"
+	_ "github.com/blockberries/punnet-sdk/modules/upgrade"
+)
+
+func TestErrorCodesStable(t *testing.T) {
+	RequireStableErrorCodes(t, filepath.Join("..", "testdata", "error_codes.json"))
+}
+
+// recordingTB records failures instead of failing the test
+type recordingTB struct {
+	testing.TB
+	failed bool
+	errors []string
+}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failed = true
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...any) {
+	r.failed = true
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// FailNow records the failure and unwinds the helper with a panic
+func (r *recordingTB) FailNow() {
+	r.failed = true
+	panic("FailNow")
+}
+
+func TestRequireStableErrorCodes_Detects(t *testing.T) {
+	golden := filepath.Join(t.TempDir(), "codes.json")
+	codes := []sdkerrors.Code{
+		{Codespace: "test", Code: 2, Message: "first"},
+		{Codespace: "test", Code: 3, Message: "second"},
+	}
+	t.Setenv(UpdateErrorCodesEnv, "1")
+	requireStableCodes(t, golden, codes)
+	t.Setenv(UpdateErrorCodesEnv, "")
+
+	tests := []struct {
+		name       string
+		current    []sdkerrors.Code
+		wantFailed bool
+	}{
+		{"unchanged", codes, false},
+		{"added", append(codes[:2:2], sdkerrors.Code{Codespace: "test", Code: 4, Message: "third"}), true},
+		{"removed", codes[:1], true},
+		{"changed", []sdkerrors.Code{codes[0], {Codespace: "test", Code: 3, Message: "other"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recordingTB{TB: t}
+			requireStableCodes(rec, golden, tt.current)
+			require.Equal(t, tt.wantFailed, rec.failed)
+		})
+	}
+
+	rec := &recordingTB{TB: t}
+	requireStableCodes(rec, filepath.Join(t.TempDir(), "missing.json"), codes)
+	require.True(t, rec.failed)
+}
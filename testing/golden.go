@@ -0,0 +1,165 @@
+package testing
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// GoldenDir is the directory, relative to the test's package directory,
+// holding the files checked by AssertMatchesGolden.
+const GoldenDir = "testdata/golden"
+
+// UpdateGoldenEnv is the environment variable that, like the -update flag,
+// makes AssertMatchesGolden rewrite golden files instead of checking them.
+// It also works with `go test ./...`, where -update would reach packages
+// that do not define it.
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// updateGolden is the -update test flag
+var updateGolden = flag.Bool("update", false, "rewrite golden files checked by AssertMatchesGolden")
+
+// goldenDiffContext is the number of bytes shown on each side of the first
+// difference
+const goldenDiffContext = 40
+
+// maxGoldenDiffLines bounds the inputs of the line diff (quadratic in size)
+const maxGoldenDiffLines = 1000
+
+// AssertMatchesGolden fails t unless got equals the golden file
+// GoldenDir/<name>.golden, reporting a line diff and the first differing
+// byte. With -update (or UPDATE_GOLDEN=1) it writes got to the file instead.
+//
+// name may contain slashes to group files, e.g. "signdoc/basic_transfer".
+//
+// RATIONALE: Serialized forms such as SignDoc JSON are consensus-critical.
+// Keeping them in files makes every change a reviewed diff of the golden
+// file rather than an edited string literal.
+//
+// Usage:
+//
+//	data, err := signDoc.ToJSON()
+//	require.NoError(t, err)
+//	punnettesting.AssertMatchesGolden(t, "signdoc/basic_transfer", data)
+func AssertMatchesGolden(t testing.TB, name string, got []byte) {
+	t.Helper()
+
+	if name == "" || filepath.IsAbs(name) || strings.Contains(name, "..") {
+		t.Errorf("invalid golden file name %q", name)
+		return
+	}
+	path := filepath.Join(GoldenDir, filepath.FromSlash(name)+".golden")
+
+	if *updateGolden || os.Getenv(UpdateGoldenEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("failed to create golden directory: %v", err)
+			return
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Errorf("failed to write golden file: %v", err)
+			return
+		}
+		t.Logf("wrote golden file %s", path)
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Errorf("golden file %s not found - run with -update or %s=1 to create it", path, UpdateGoldenEnv)
+		return
+	}
+	if err != nil {
+		t.Errorf("failed to read golden file: %v", err)
+		return
+	}
+
+	if !bytes.Equal(want, got) {
+		t.Errorf("output does not match golden file %s (run with -update or %s=1 if the change is intended)\n%s",
+			path, UpdateGoldenEnv, goldenDiff(want, got))
+	}
+}
+
+// goldenDiff describes how got differs from want: the first differing byte
+// with surrounding context, then a line diff.
+func goldenDiff(want, got []byte) string {
+	var b strings.Builder
+
+	offset := 0
+	for offset < len(want) && offset < len(got) && want[offset] == got[offset] {
+		offset++
+	}
+	fmt.Fprintf(&b, "first difference at byte %d:\n", offset)
+	fmt.Fprintf(&b, "  want: %q\n", excerpt(want, offset))
+	fmt.Fprintf(&b, "  got:  %q\n", excerpt(got, offset))
+
+	wantLines := strings.SplitAfter(string(want), "\n")
+	gotLines := strings.SplitAfter(string(got), "\n")
+	if len(wantLines) > maxGoldenDiffLines || len(gotLines) > maxGoldenDiffLines {
+		return b.String()
+	}
+	b.WriteString("diff (-want +got):\n")
+	for _, line := range diffLines(wantLines, gotLines) {
+		b.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// excerpt returns data around offset
+func excerpt(data []byte, offset int) string {
+	start := max(offset-goldenDiffContext, 0)
+	end := min(offset+goldenDiffContext, len(data))
+	return string(data[start:end])
+}
+
+// diffLines returns a line diff of want and got: unchanged lines prefixed
+// with "  ", removed with "- " and added with "+ ".
+//
+// Complexity: O(n*m) time and space (longest common subsequence)
+func diffLines(want, got []string) []string {
+	n, m := len(want), len(got)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if want[i] == got[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case want[i] == got[j]:
+			out = append(out, "  "+want[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "- "+want[i])
+			i++
+		default:
+			out = append(out, "+ "+got[j])
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		out = append(out, "- "+want[i])
+	}
+	for ; j < m; j++ {
+		out = append(out, "+ "+got[j])
+	}
+	return out
+}
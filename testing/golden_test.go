@@ -0,0 +1,77 @@
+package testing
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssertMatchesGolden(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	t.Run("missing file fails", func(t *testing.T) {
+		rec := &recordingTB{TB: t}
+		AssertMatchesGolden(rec, "missing", []byte("x"))
+		require.True(t, rec.failed)
+		assert.Contains(t, rec.errors[0], "-update")
+	})
+
+	t.Run("update writes nested file", func(t *testing.T) {
+		t.Setenv(UpdateGoldenEnv, "1")
+		AssertMatchesGolden(t, "group/case", []byte("line 1\nline 2\n"))
+
+		data, err := os.ReadFile(filepath.Join(GoldenDir, "group", "case.golden"))
+		require.NoError(t, err)
+		assert.Equal(t, "line 1\nline 2\n", string(data))
+	})
+
+	t.Run("match passes", func(t *testing.T) {
+		rec := &recordingTB{TB: t}
+		AssertMatchesGolden(rec, "group/case", []byte("line 1\nline 2\n"))
+		assert.False(t, rec.failed)
+	})
+
+	t.Run("mismatch reports diff", func(t *testing.T) {
+		rec := &recordingTB{TB: t}
+		AssertMatchesGolden(rec, "group/case", []byte("line 1\nline two\n"))
+		require.True(t, rec.failed)
+
+		msg := rec.errors[0]
+		assert.Contains(t, msg, "first difference at byte 12")
+		assert.Contains(t, msg, "  line 1\n")
+		assert.Contains(t, msg, "- line 2\n")
+		assert.Contains(t, msg, "+ line two\n")
+	})
+
+	t.Run("invalid names rejected", func(t *testing.T) {
+		for _, name := range []string{"", "../escape", "/abs"} {
+			rec := &recordingTB{TB: t}
+			AssertMatchesGolden(rec, name, nil)
+			assert.True(t, rec.failed, name)
+		}
+	})
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		got  string
+		diff string
+	}{
+		{"equal", "a\nb\n", "a\nb\n", "  a\n|  b\n|  "},
+		{"added", "a\n", "a\nb\n", "  a\n|+ b\n|  "},
+		{"removed", "a\nb\n", "b\n", "- a\n|  b\n|  "},
+		{"single line", `{"x":1}`, `{"x":2}`, `- {"x":1}|+ {"x":2}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := diffLines(strings.SplitAfter(tt.want, "\n"), strings.SplitAfter(tt.got, "\n"))
+			assert.Equal(t, tt.diff, strings.Join(diff, "|"))
+		})
+	}
+}
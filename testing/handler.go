@@ -0,0 +1,33 @@
+package testing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// RequireDeterministicHandler runs handler on msg twice, each time with a fresh
+// context built from opts, and fails t unless both runs succeed with the same
+// effects (compared by effects.Fingerprint). It returns the effects so tests
+// can assert on them without applying them to a store.
+func RequireDeterministicHandler(t testing.TB, handler runtime.MsgHandler, msg types.Message, opts ...ContextOption) []effects.Effect {
+	t.Helper()
+	require.NotNil(t, handler, "handler is nil")
+
+	first, err := handler(NewContext(t, opts...), msg)
+	require.NoError(t, err)
+	second, err := handler(NewContext(t, opts...), msg)
+	require.NoError(t, err)
+
+	firstPrint, err := effects.Fingerprint(first)
+	require.NoError(t, err)
+	secondPrint, err := effects.Fingerprint(second)
+	require.NoError(t, err)
+	require.Equal(t, firstPrint, secondPrint, "handler returned different effects for the same input")
+
+	return first
+}
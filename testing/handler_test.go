@@ -0,0 +1,44 @@
+package testing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// handlerMessage is a minimal types.Message
+type handlerMessage struct{}
+
+func (handlerMessage) Type() string                    { return "/test.msg" }
+func (handlerMessage) ValidateBasic() error            { return nil }
+func (handlerMessage) GetSigners() []types.AccountName { return nil }
+
+func TestRequireDeterministicHandler(t *testing.T) {
+	handler := func(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+		return []effects.Effect{
+			effects.NewStateWriteEffect("test", []byte("height"), []byte{byte(ctx.BlockHeight())}),
+			effects.NewEventEffect("handled", map[string][]byte{"b": []byte("2"), "a": []byte("1")}),
+		}, nil
+	}
+
+	effs := RequireDeterministicHandler(t, handler, handlerMessage{}, WithHeight(7))
+	require.Len(t, effs, 2)
+	value, err := effs[0].(effects.StateWriteEffect).EncodedValue()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{7}, value)
+
+	// A handler whose output varies between runs is reported
+	calls := 0
+	flaky := func(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+		calls++
+		return []effects.Effect{effects.NewStateWriteEffect("test", []byte("calls"), []byte{byte(calls)})}, nil
+	}
+	rec := &recordingTB{TB: t}
+	assert.Panics(t, func() { RequireDeterministicHandler(rec, flaky, handlerMessage{}) })
+	assert.True(t, rec.failed)
+}
@@ -0,0 +1,436 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// MockAccountCapability is an in-memory capability.AccountCapability for
+// module unit tests. It validates arguments, stores accounts and extensions
+// and verifies authorizations like the real capability, and adds scripted
+// errors and latencies (FailNext, SetError, SetLatency), call recording
+// (Calls, CallsTo) and state snapshots (Snapshot, Restore). Scripting uses
+// the interface's method names, e.g. "GetAccount".
+//
+// Accounts are copied on the way in and out, so tests cannot change stored
+// state by mutating a returned account.
+//
+// CONCURRENCY: Safe for concurrent use. Iteration callbacks run without the
+// lock held and may call the mock.
+//
+// Usage:
+//
+//	auth := punnettesting.NewMockAccountCapability("auth")
+//	_, err := auth.CreateAccount(ctx, "alice", pubKey)
+//	require.NoError(t, err)
+//	auth.SetError("IncrementNonce", errors.New("store unavailable"))
+type MockAccountCapability struct {
+	*mockBehavior
+
+	moduleName string
+
+	mu       sync.RWMutex
+	accounts map[types.AccountName]*types.Account
+
+	// extensions holds encoded extensions by account and extension name
+	extensions map[types.AccountName]map[string][]byte
+}
+
+var _ capability.AccountCapability = (*MockAccountCapability)(nil)
+
+// MockAccountSnapshot is a copy of a MockAccountCapability's accounts and
+// extensions.
+type MockAccountSnapshot struct {
+	// Accounts holds every account, sorted by name
+	Accounts []*types.Account
+
+	// Extensions holds encoded extensions by account and extension name
+	Extensions map[types.AccountName]map[string][]byte
+}
+
+// NewMockAccountCapability creates an empty MockAccountCapability scoped to
+// moduleName.
+func NewMockAccountCapability(moduleName string) *MockAccountCapability {
+	return &MockAccountCapability{
+		mockBehavior: newMockBehavior(
+			"GetAccount", "CreateAccount", "UpdateAccount", "DeleteAccount", "HasAccount",
+			"VerifyAuthorization", "IncrementNonce", "GetNonce", "IterateAccounts",
+			"SetExtension", "GetExtension", "DeleteExtension",
+		),
+		moduleName: moduleName,
+		accounts:   make(map[types.AccountName]*types.Account),
+		extensions: make(map[types.AccountName]map[string][]byte),
+	}
+}
+
+// ModuleName returns the module this capability is scoped to
+func (m *MockAccountCapability) ModuleName() string {
+	return m.moduleName
+}
+
+// GetAccount retrieves an account by name.
+// Returns store.ErrNotFound if the account does not exist.
+func (m *MockAccountCapability) GetAccount(ctx context.Context, name types.AccountName) (_ *types.Account, err error) {
+	defer m.record("GetAccount", &err, name)()
+	if err = m.intercept(ctx, "GetAccount"); err != nil {
+		return nil, err
+	}
+	if !name.IsValid() {
+		return nil, fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
+	}
+
+	return m.get(name)
+}
+
+// CreateAccount creates a new account with the given name and public key
+func (m *MockAccountCapability) CreateAccount(ctx context.Context, name types.AccountName, pubKey []byte) (_ *types.Account, err error) {
+	defer m.record("CreateAccount", &err, name, cloneBytes(pubKey))()
+	if err = m.intercept(ctx, "CreateAccount"); err != nil {
+		return nil, err
+	}
+	if !name.IsValid() {
+		return nil, fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
+	}
+	if len(pubKey) == 0 {
+		return nil, fmt.Errorf("public key cannot be empty")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.accounts[name]; exists {
+		return nil, fmt.Errorf("account %s already exists", name)
+	}
+	account := types.NewAccount(name, cloneBytes(pubKey))
+	m.accounts[name] = cloneAccount(account)
+	return account, nil
+}
+
+// UpdateAccount updates an existing account
+func (m *MockAccountCapability) UpdateAccount(ctx context.Context, account *types.Account) (err error) {
+	defer m.record("UpdateAccount", &err, cloneAccount(account))()
+	if err = m.intercept(ctx, "UpdateAccount"); err != nil {
+		return err
+	}
+	if account == nil {
+		return fmt.Errorf("account cannot be nil")
+	}
+	if err = account.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid account: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.accounts[account.Name]; !exists {
+		return fmt.Errorf("%w: account %s not found", types.ErrNotFound, account.Name)
+	}
+	m.accounts[account.Name] = cloneAccount(account)
+	return nil
+}
+
+// DeleteAccount removes an account and its extensions
+func (m *MockAccountCapability) DeleteAccount(ctx context.Context, name types.AccountName) (err error) {
+	defer m.record("DeleteAccount", &err, name)()
+	if err = m.intercept(ctx, "DeleteAccount"); err != nil {
+		return err
+	}
+	if !name.IsValid() {
+		return fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.accounts, name)
+	delete(m.extensions, name)
+	return nil
+}
+
+// HasAccount checks if an account exists
+func (m *MockAccountCapability) HasAccount(ctx context.Context, name types.AccountName) (_ bool, err error) {
+	defer m.record("HasAccount", &err, name)()
+	if err = m.intercept(ctx, "HasAccount"); err != nil {
+		return false, err
+	}
+	if !name.IsValid() {
+		return false, fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, exists := m.accounts[name]
+	return exists, nil
+}
+
+// VerifyAuthorization verifies that an authorization meets the account's
+// authority threshold, resolving delegated accounts from the mock's state
+func (m *MockAccountCapability) VerifyAuthorization(ctx context.Context, account *types.Account, auth *types.Authorization, message []byte) (err error) {
+	var name types.AccountName
+	if account != nil {
+		name = account.Name
+	}
+	defer m.record("VerifyAuthorization", &err, name, cloneBytes(message))()
+	if err = m.intercept(ctx, "VerifyAuthorization"); err != nil {
+		return err
+	}
+	if account == nil {
+		return fmt.Errorf("account cannot be nil")
+	}
+	if auth == nil {
+		return fmt.Errorf("authorization cannot be nil")
+	}
+	if message == nil {
+		return fmt.Errorf("message cannot be nil")
+	}
+
+	return auth.VerifyAuthorization(account, message, mockAccountGetter{m})
+}
+
+// IncrementNonce increments an account's nonce
+func (m *MockAccountCapability) IncrementNonce(ctx context.Context, name types.AccountName) (err error) {
+	defer m.record("IncrementNonce", &err, name)()
+	if err = m.intercept(ctx, "IncrementNonce"); err != nil {
+		return err
+	}
+	if !name.IsValid() {
+		return fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	account, exists := m.accounts[name]
+	if !exists {
+		return fmt.Errorf("failed to get account: %w", store.ErrNotFound)
+	}
+	account.Nonce++
+	return nil
+}
+
+// GetNonce retrieves an account's current nonce
+func (m *MockAccountCapability) GetNonce(ctx context.Context, name types.AccountName) (_ uint64, err error) {
+	defer m.record("GetNonce", &err, name)()
+	if err = m.intercept(ctx, "GetNonce"); err != nil {
+		return 0, err
+	}
+	if !name.IsValid() {
+		return 0, fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
+	}
+
+	account, err := m.get(name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get account: %w", err)
+	}
+	return account.Nonce, nil
+}
+
+// IterateAccounts iterates over all accounts in name order
+func (m *MockAccountCapability) IterateAccounts(ctx context.Context, callback func(*types.Account) error) (err error) {
+	defer m.record("IterateAccounts", &err)()
+	if err = m.intercept(ctx, "IterateAccounts"); err != nil {
+		return err
+	}
+	if callback == nil {
+		return fmt.Errorf("callback cannot be nil")
+	}
+
+	for _, account := range m.Snapshot().Accounts {
+		if err = callback(account); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetExtension attaches ext to an existing account
+func (m *MockAccountCapability) SetExtension(ctx context.Context, name types.AccountName, ext types.AccountExtension) (err error) {
+	var extName string
+	if ext != nil {
+		extName = ext.ExtensionName()
+	}
+	defer m.record("SetExtension", &err, name, extName)()
+	if err = m.intercept(ctx, "SetExtension"); err != nil {
+		return err
+	}
+	if !name.IsValid() {
+		return fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
+	}
+
+	data, err := types.EncodeAccountExtension(ext)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.accounts[name]; !exists {
+		return fmt.Errorf("%w: account %s not found", types.ErrNotFound, name)
+	}
+	exts, ok := m.extensions[name]
+	if !ok {
+		exts = make(map[string][]byte)
+		m.extensions[name] = exts
+	}
+	exts[extName] = data
+	return nil
+}
+
+// GetExtension decodes an account's extension into ext
+func (m *MockAccountCapability) GetExtension(ctx context.Context, name types.AccountName, ext types.AccountExtension) (err error) {
+	var extName string
+	if ext != nil {
+		extName = ext.ExtensionName()
+	}
+	defer m.record("GetExtension", &err, name, extName)()
+	if err = m.intercept(ctx, "GetExtension"); err != nil {
+		return err
+	}
+	if !name.IsValid() {
+		return fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
+	}
+	if ext == nil {
+		return fmt.Errorf("account extension cannot be nil")
+	}
+	if err = types.ValidateExtensionName(extName); err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	data, ok := m.extensions[name][extName]
+	m.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: extension %s of account %s", types.ErrNotFound, extName, name)
+	}
+
+	return types.DecodeAccountExtension(data, ext)
+}
+
+// DeleteExtension removes an account's extension; removing a missing
+// extension is not an error
+func (m *MockAccountCapability) DeleteExtension(ctx context.Context, name types.AccountName, extName string) (err error) {
+	defer m.record("DeleteExtension", &err, name, extName)()
+	if err = m.intercept(ctx, "DeleteExtension"); err != nil {
+		return err
+	}
+	if !name.IsValid() {
+		return fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
+	}
+	if err = types.ValidateExtensionName(extName); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.extensions[name], extName)
+	if len(m.extensions[name]) == 0 {
+		delete(m.extensions, name)
+	}
+	return nil
+}
+
+// Snapshot returns a copy of the current accounts and extensions. It is not
+// recorded as a call and ignores scripted behaviors.
+func (m *MockAccountCapability) Snapshot() MockAccountSnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	accounts := make([]*types.Account, 0, len(m.accounts))
+	for _, account := range m.accounts {
+		accounts = append(accounts, cloneAccount(account))
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Name < accounts[j].Name
+	})
+
+	return MockAccountSnapshot{
+		Accounts:   accounts,
+		Extensions: cloneExtensions(m.extensions),
+	}
+}
+
+// Restore replaces the current accounts and extensions with those of
+// snapshot. Recorded calls and scripted behaviors are kept.
+func (m *MockAccountCapability) Restore(snapshot MockAccountSnapshot) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.accounts = make(map[types.AccountName]*types.Account, len(snapshot.Accounts))
+	for _, account := range snapshot.Accounts {
+		m.accounts[account.Name] = cloneAccount(account)
+	}
+	m.extensions = cloneExtensions(snapshot.Extensions)
+}
+
+// get returns a copy of the named account, or store.ErrNotFound
+func (m *MockAccountCapability) get(name types.AccountName) (*types.Account, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	account, exists := m.accounts[name]
+	if !exists {
+		return nil, store.ErrNotFound
+	}
+	return cloneAccount(account), nil
+}
+
+// mockAccountGetter resolves delegated accounts during authorization
+// verification without recording calls or applying scripted behaviors
+type mockAccountGetter struct {
+	mock *MockAccountCapability
+}
+
+// GetAccount retrieves an account by name (implements types.AccountGetter)
+func (g mockAccountGetter) GetAccount(name types.AccountName) (*types.Account, error) {
+	return g.mock.get(name)
+}
+
+// cloneAccount returns a deep copy of account
+func cloneAccount(account *types.Account) *types.Account {
+	if account == nil {
+		return nil
+	}
+
+	clone := *account
+	if account.Authority.KeyWeights != nil {
+		clone.Authority.KeyWeights = make(map[string]uint64, len(account.Authority.KeyWeights))
+		for keyID, weight := range account.Authority.KeyWeights {
+			clone.Authority.KeyWeights[keyID] = weight
+		}
+	}
+	if account.Authority.AccountWeights != nil {
+		clone.Authority.AccountWeights = make(map[types.AccountName]uint64, len(account.Authority.AccountWeights))
+		for name, weight := range account.Authority.AccountWeights {
+			clone.Authority.AccountWeights[name] = weight
+		}
+	}
+	return &clone
+}
+
+// cloneExtensions returns a deep copy of an extension map
+func cloneExtensions(extensions map[types.AccountName]map[string][]byte) map[types.AccountName]map[string][]byte {
+	clone := make(map[types.AccountName]map[string][]byte, len(extensions))
+	for name, exts := range extensions {
+		clone[name] = make(map[string][]byte, len(exts))
+		for extName, data := range exts {
+			clone[name][extName] = cloneBytes(data)
+		}
+	}
+	return clone
+}
+
+// cloneBytes returns a copy of b, keeping nil as nil
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
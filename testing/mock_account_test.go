@@ -0,0 +1,137 @@
+package testing
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// mockTestExtension is a minimal types.AccountExtension
+type mockTestExtension struct {
+	Level int `json:"level"`
+}
+
+func (*mockTestExtension) ExtensionName() string { return "test_level" }
+func (*mockTestExtension) SchemaVersion() uint32 { return 1 }
+
+func TestMockAccountCapability_Accounts(t *testing.T) {
+	ctx := context.Background()
+	auth := NewMockAccountCapability("auth")
+	assert.Equal(t, "auth", auth.ModuleName())
+
+	pub, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	account, err := auth.CreateAccount(ctx, "alice", pub)
+	require.NoError(t, err)
+	assert.True(t, account.Authority.HasKey(pub))
+	_, err = auth.CreateAccount(ctx, "alice", pub)
+	require.Error(t, err)
+	_, err = auth.CreateAccount(ctx, "bob", nil)
+	require.Error(t, err)
+
+	_, err = auth.GetAccount(ctx, "bob")
+	require.ErrorIs(t, err, store.ErrNotFound)
+	_, err = auth.GetAccount(ctx, "Not Valid")
+	require.ErrorIs(t, err, types.ErrInvalidAccount)
+
+	// Returned accounts are copies
+	got, err := auth.GetAccount(ctx, "alice")
+	require.NoError(t, err)
+	got.Nonce = 99
+	got.Authority.KeyWeights["other"] = 1
+
+	require.NoError(t, auth.IncrementNonce(ctx, "alice"))
+	nonce, err := auth.GetNonce(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), nonce)
+	got, err = auth.GetAccount(ctx, "alice")
+	require.NoError(t, err)
+	assert.Len(t, got.Authority.KeyWeights, 1)
+
+	got.Authority.Threshold = 2
+	require.Error(t, auth.UpdateAccount(ctx, got), "threshold exceeds total weight")
+	missing := types.NewAccount("carol", pub)
+	require.ErrorIs(t, auth.UpdateAccount(ctx, missing), types.ErrNotFound)
+
+	require.NoError(t, auth.SetExtension(ctx, "alice", &mockTestExtension{Level: 3}))
+	require.ErrorIs(t, auth.SetExtension(ctx, "carol", &mockTestExtension{}), types.ErrNotFound)
+	var ext mockTestExtension
+	require.NoError(t, auth.GetExtension(ctx, "alice", &ext))
+	assert.Equal(t, 3, ext.Level)
+
+	require.NoError(t, auth.DeleteAccount(ctx, "alice"))
+	has, err := auth.HasAccount(ctx, "alice")
+	require.NoError(t, err)
+	assert.False(t, has)
+	require.ErrorIs(t, auth.GetExtension(ctx, "alice", &ext), types.ErrNotFound)
+}
+
+func TestMockAccountCapability_VerifyAuthorization(t *testing.T) {
+	ctx := context.Background()
+	auth := NewMockAccountCapability("auth")
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	account, err := auth.CreateAccount(ctx, "alice", pub)
+	require.NoError(t, err)
+
+	message := []byte("sign me")
+	valid := types.NewAuthorization(types.Signature{PubKey: pub, Signature: ed25519.Sign(priv, message)})
+	require.NoError(t, auth.VerifyAuthorization(ctx, account, valid, message))
+
+	invalid := types.NewAuthorization(types.Signature{PubKey: pub, Signature: ed25519.Sign(priv, []byte("other"))})
+	require.Error(t, auth.VerifyAuthorization(ctx, account, invalid, message))
+
+	calls := auth.CallsTo("VerifyAuthorization")
+	require.Len(t, calls, 2)
+	assert.Equal(t, []any{types.AccountName("alice"), message}, calls[0].Args)
+	assert.NoError(t, calls[0].Err)
+	assert.Error(t, calls[1].Err)
+}
+
+func TestMockAccountCapability_ScriptedErrorsAndSnapshot(t *testing.T) {
+	ctx := context.Background()
+	auth := NewMockAccountCapability("auth")
+
+	pub, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	_, err = auth.CreateAccount(ctx, "alice", pub)
+	require.NoError(t, err)
+	require.NoError(t, auth.SetExtension(ctx, "alice", &mockTestExtension{Level: 1}))
+
+	snapshot := auth.Snapshot()
+	require.Len(t, snapshot.Accounts, 1)
+
+	errUnavailable := errors.New("store unavailable")
+	auth.FailNext("IncrementNonce", errUnavailable)
+	require.ErrorIs(t, auth.IncrementNonce(ctx, "alice"), errUnavailable)
+	require.NoError(t, auth.IncrementNonce(ctx, "alice"))
+	require.NoError(t, auth.DeleteExtension(ctx, "alice", "test_level"))
+	_, err = auth.CreateAccount(ctx, "bob", pub)
+	require.NoError(t, err)
+
+	auth.Restore(snapshot)
+	assert.Equal(t, snapshot, auth.Snapshot())
+	nonce, err := auth.GetNonce(ctx, "alice")
+	require.NoError(t, err)
+	assert.Zero(t, nonce)
+	var ext mockTestExtension
+	require.NoError(t, auth.GetExtension(ctx, "alice", &ext))
+	assert.Equal(t, 1, ext.Level)
+
+	var names []types.AccountName
+	require.NoError(t, auth.IterateAccounts(ctx, func(account *types.Account) error {
+		names = append(names, account.Name)
+		return nil
+	}))
+	assert.Equal(t, []types.AccountName{"alice"}, names)
+	assert.Equal(t, 2, auth.CallCount("IncrementNonce"))
+}
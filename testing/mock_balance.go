@@ -0,0 +1,349 @@
+package testing
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// MockBalanceCapability is an in-memory capability.BalanceCapability for
+// module unit tests. It validates arguments and moves funds like the real
+// capability, and adds scripted errors and latencies (FailNext, SetError,
+// SetLatency), call recording (Calls, CallsTo) and state snapshots
+// (Snapshot, Restore). Scripting uses the interface's method names, e.g.
+// "Transfer".
+//
+// Writes are visible immediately; there is no cache to flush.
+//
+// CONCURRENCY: Safe for concurrent use. Iteration callbacks run without the
+// lock held and may call the mock.
+//
+// Usage:
+//
+//	bank := punnettesting.NewMockBalanceCapability("bank")
+//	require.NoError(t, bank.SetBalance(ctx, "alice", "stake", 100))
+//	bank.FailNext("Transfer", types.ErrInsufficientFunds)
+//	// ... exercise the module ...
+//	require.Len(t, bank.CallsTo("Transfer"), 1)
+type MockBalanceCapability struct {
+	*mockBehavior
+
+	moduleName string
+
+	mu       sync.RWMutex
+	balances map[types.AccountName]map[string]uint64
+}
+
+var _ capability.BalanceCapability = (*MockBalanceCapability)(nil)
+
+// MockBalanceSnapshot is a copy of a MockBalanceCapability's balances.
+type MockBalanceSnapshot struct {
+	// Balances holds every stored balance, including zero ones, in key order
+	// (store.BalanceKey)
+	Balances []store.Balance
+}
+
+// NewMockBalanceCapability creates an empty MockBalanceCapability scoped to
+// moduleName.
+func NewMockBalanceCapability(moduleName string) *MockBalanceCapability {
+	return &MockBalanceCapability{
+		mockBehavior: newMockBehavior(
+			"GetBalance", "SetBalance", "AddBalance", "SubBalance", "Transfer",
+			"GetAccountBalances", "HasBalance", "IterateBalances", "IterateAccountBalances",
+		),
+		moduleName: moduleName,
+		balances:   make(map[types.AccountName]map[string]uint64),
+	}
+}
+
+// ModuleName returns the module this capability is scoped to
+func (m *MockBalanceCapability) ModuleName() string {
+	return m.moduleName
+}
+
+// GetBalance retrieves a balance by account and denomination
+func (m *MockBalanceCapability) GetBalance(ctx context.Context, account types.AccountName, denom string) (_ uint64, err error) {
+	defer m.record("GetBalance", &err, account, denom)()
+	if err = m.intercept(ctx, "GetBalance"); err != nil {
+		return 0, err
+	}
+	if err = validateBalanceArgs(account, denom); err != nil {
+		return 0, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.balances[account][denom], nil
+}
+
+// SetBalance sets a balance for an account and denomination
+func (m *MockBalanceCapability) SetBalance(ctx context.Context, account types.AccountName, denom string, amount uint64) (err error) {
+	defer m.record("SetBalance", &err, account, denom, amount)()
+	if err = m.intercept(ctx, "SetBalance"); err != nil {
+		return err
+	}
+	if err = validateBalanceArgs(account, denom); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.set(account, denom, amount)
+	return nil
+}
+
+// AddBalance adds to an account's balance
+func (m *MockBalanceCapability) AddBalance(ctx context.Context, account types.AccountName, denom string, amount uint64) (err error) {
+	defer m.record("AddBalance", &err, account, denom, amount)()
+	if err = m.intercept(ctx, "AddBalance"); err != nil {
+		return err
+	}
+	if err = validateBalanceArgs(account, denom); err != nil {
+		return err
+	}
+	if amount == 0 {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.add(account, denom, amount)
+}
+
+// SubBalance subtracts from an account's balance
+func (m *MockBalanceCapability) SubBalance(ctx context.Context, account types.AccountName, denom string, amount uint64) (err error) {
+	defer m.record("SubBalance", &err, account, denom, amount)()
+	if err = m.intercept(ctx, "SubBalance"); err != nil {
+		return err
+	}
+	if err = validateBalanceArgs(account, denom); err != nil {
+		return err
+	}
+	if amount == 0 {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sub(account, denom, amount)
+}
+
+// Transfer transfers tokens from one account to another.
+// Either both balances change or neither does.
+func (m *MockBalanceCapability) Transfer(ctx context.Context, from, to types.AccountName, denom string, amount uint64) (err error) {
+	defer m.record("Transfer", &err, from, to, denom, amount)()
+	if err = m.intercept(ctx, "Transfer"); err != nil {
+		return err
+	}
+	if !from.IsValid() {
+		return fmt.Errorf("%w: invalid sender account name", types.ErrInvalidAccount)
+	}
+	if !to.IsValid() {
+		return fmt.Errorf("%w: invalid receiver account name", types.ErrInvalidAccount)
+	}
+	if denom == "" {
+		return fmt.Errorf("denomination cannot be empty")
+	}
+	if amount == 0 {
+		return nil
+	}
+	if from == to {
+		return fmt.Errorf("cannot transfer to self")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Check both sides before changing either
+	fromAmount, toAmount := m.balances[from][denom], m.balances[to][denom]
+	if fromAmount < amount {
+		return fmt.Errorf("failed to transfer: %w: has %d, needs %d", types.ErrInsufficientFunds, fromAmount, amount)
+	}
+	if toAmount > ^uint64(0)-amount {
+		return fmt.Errorf("failed to transfer: balance overflow")
+	}
+	m.set(from, denom, fromAmount-amount)
+	m.set(to, denom, toAmount+amount)
+	return nil
+}
+
+// GetAccountBalances retrieves all non-zero balances for an account, sorted
+// by denomination
+func (m *MockBalanceCapability) GetAccountBalances(ctx context.Context, account types.AccountName) (_ types.Coins, err error) {
+	defer m.record("GetAccountBalances", &err, account)()
+	if err = m.intercept(ctx, "GetAccountBalances"); err != nil {
+		return nil, err
+	}
+	if !account.IsValid() {
+		return nil, fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
+	}
+
+	coins := make(types.Coins, 0)
+	for _, balance := range m.accountBalances(account) {
+		if balance.Amount > 0 {
+			coins = append(coins, types.Coin{Denom: balance.Denom, Amount: balance.Amount})
+		}
+	}
+	return coins, nil
+}
+
+// HasBalance checks if a balance exists for an account and denomination
+func (m *MockBalanceCapability) HasBalance(ctx context.Context, account types.AccountName, denom string) (_ bool, err error) {
+	defer m.record("HasBalance", &err, account, denom)()
+	if err = m.intercept(ctx, "HasBalance"); err != nil {
+		return false, err
+	}
+	if err = validateBalanceArgs(account, denom); err != nil {
+		return false, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.balances[account][denom]
+	return ok, nil
+}
+
+// IterateBalances iterates over all balances in key order
+func (m *MockBalanceCapability) IterateBalances(ctx context.Context, callback func(store.Balance) error) (err error) {
+	defer m.record("IterateBalances", &err)()
+	if err = m.intercept(ctx, "IterateBalances"); err != nil {
+		return err
+	}
+	if callback == nil {
+		return fmt.Errorf("callback cannot be nil")
+	}
+
+	for _, balance := range m.Snapshot().Balances {
+		if err = callback(balance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IterateAccountBalances iterates over all balances for a specific account in
+// denomination order
+func (m *MockBalanceCapability) IterateAccountBalances(ctx context.Context, account types.AccountName, callback func(store.Balance) error) (err error) {
+	defer m.record("IterateAccountBalances", &err, account)()
+	if err = m.intercept(ctx, "IterateAccountBalances"); err != nil {
+		return err
+	}
+	if !account.IsValid() {
+		return fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
+	}
+	if callback == nil {
+		return fmt.Errorf("callback cannot be nil")
+	}
+
+	for _, balance := range m.accountBalances(account) {
+		if err = callback(balance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Snapshot returns a copy of the current balances. It is not recorded as a
+// call and ignores scripted behaviors.
+func (m *MockBalanceCapability) Snapshot() MockBalanceSnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var balances []store.Balance
+	for account, denoms := range m.balances {
+		for denom, amount := range denoms {
+			balances = append(balances, store.NewBalance(account, denom, amount))
+		}
+	}
+	sortBalances(balances)
+	return MockBalanceSnapshot{Balances: balances}
+}
+
+// Restore replaces the current balances with those of snapshot. Recorded
+// calls and scripted behaviors are kept.
+func (m *MockBalanceCapability) Restore(snapshot MockBalanceSnapshot) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.balances = make(map[types.AccountName]map[string]uint64)
+	for _, balance := range snapshot.Balances {
+		m.set(balance.Account, balance.Denom, balance.Amount)
+	}
+}
+
+// accountBalances returns the balances of account sorted by denomination
+func (m *MockBalanceCapability) accountBalances(account types.AccountName) []store.Balance {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	balances := make([]store.Balance, 0, len(m.balances[account]))
+	for denom, amount := range m.balances[account] {
+		balances = append(balances, store.NewBalance(account, denom, amount))
+	}
+	sortBalances(balances)
+	return balances
+}
+
+// set stores a balance
+//
+// PRECONDITION: m.mu is held for writing
+func (m *MockBalanceCapability) set(account types.AccountName, denom string, amount uint64) {
+	denoms, ok := m.balances[account]
+	if !ok {
+		denoms = make(map[string]uint64)
+		m.balances[account] = denoms
+	}
+	denoms[denom] = amount
+}
+
+// add adds amount to a balance
+//
+// PRECONDITION: m.mu is held for writing
+func (m *MockBalanceCapability) add(account types.AccountName, denom string, amount uint64) error {
+	current := m.balances[account][denom]
+	if current > ^uint64(0)-amount {
+		return fmt.Errorf("failed to add balance: balance overflow")
+	}
+	m.set(account, denom, current+amount)
+	return nil
+}
+
+// sub subtracts amount from a balance
+//
+// PRECONDITION: m.mu is held for writing
+func (m *MockBalanceCapability) sub(account types.AccountName, denom string, amount uint64) error {
+	current := m.balances[account][denom]
+	if current < amount {
+		return fmt.Errorf("failed to subtract balance: %w", types.ErrInsufficientFunds)
+	}
+	m.set(account, denom, current-amount)
+	return nil
+}
+
+// validateBalanceArgs checks an account name and denomination the way the
+// real balance capability does
+func validateBalanceArgs(account types.AccountName, denom string) error {
+	if !account.IsValid() {
+		return fmt.Errorf("%w: invalid account name", types.ErrInvalidAccount)
+	}
+	if denom == "" {
+		return fmt.Errorf("denomination cannot be empty")
+	}
+	return nil
+}
+
+// sortBalances sorts balances by store.BalanceKey, the real store's order
+func sortBalances(balances []store.Balance) {
+	sort.Slice(balances, func(i, j int) bool {
+		return bytes.Compare(
+			store.BalanceKey(balances[i].Account, balances[i].Denom),
+			store.BalanceKey(balances[j].Account, balances[j].Denom),
+		) < 0
+	})
+}
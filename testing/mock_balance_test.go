@@ -0,0 +1,177 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+func TestMockBalanceCapability_Balances(t *testing.T) {
+	ctx := context.Background()
+	bank := NewMockBalanceCapability("bank")
+	assert.Equal(t, "bank", bank.ModuleName())
+
+	require.NoError(t, bank.SetBalance(ctx, "alice", "stake", 100))
+	require.NoError(t, bank.AddBalance(ctx, "alice", "atom", 5))
+	require.NoError(t, bank.Transfer(ctx, "alice", "bob", "stake", 40))
+
+	amount, err := bank.GetBalance(ctx, "alice", "stake")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(60), amount)
+	amount, err = bank.GetBalance(ctx, "carol", "stake")
+	require.NoError(t, err)
+	assert.Zero(t, amount)
+
+	coins, err := bank.GetAccountBalances(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, types.Coins{{Denom: "atom", Amount: 5}, {Denom: "stake", Amount: 60}}, coins)
+
+	// Failed transfers change neither side
+	err = bank.Transfer(ctx, "bob", "alice", "stake", 41)
+	require.ErrorIs(t, err, types.ErrInsufficientFunds)
+	err = bank.SubBalance(ctx, "bob", "stake", 41)
+	require.ErrorIs(t, err, types.ErrInsufficientFunds)
+	amount, err = bank.GetBalance(ctx, "bob", "stake")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(40), amount)
+
+	require.ErrorIs(t, bank.SetBalance(ctx, "Not Valid", "stake", 1), types.ErrInvalidAccount)
+	require.Error(t, bank.SetBalance(ctx, "alice", "", 1))
+	require.Error(t, bank.Transfer(ctx, "alice", "alice", "stake", 1))
+
+	// Zero balances exist but are left out of GetAccountBalances
+	require.NoError(t, bank.SubBalance(ctx, "alice", "atom", 5))
+	has, err := bank.HasBalance(ctx, "alice", "atom")
+	require.NoError(t, err)
+	assert.True(t, has)
+	coins, err = bank.GetAccountBalances(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, types.Coins{{Denom: "stake", Amount: 60}}, coins)
+
+	var visited []store.Balance
+	require.NoError(t, bank.IterateBalances(ctx, func(b store.Balance) error {
+		visited = append(visited, b)
+		return nil
+	}))
+	assert.Equal(t, []store.Balance{
+		store.NewBalance("alice", "atom", 0),
+		store.NewBalance("alice", "stake", 60),
+		store.NewBalance("bob", "stake", 40),
+	}, visited)
+}
+
+func TestMockBalanceCapability_ScriptedErrors(t *testing.T) {
+	ctx := context.Background()
+	bank := NewMockBalanceCapability("bank")
+	require.NoError(t, bank.SetBalance(ctx, "alice", "stake", 100))
+
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+	errAlways := errors.New("always")
+	bank.FailNext("Transfer", errFirst)
+	bank.FailNext("Transfer", errSecond)
+	bank.SetError("Transfer", errAlways)
+
+	require.ErrorIs(t, bank.Transfer(ctx, "alice", "bob", "stake", 1), errFirst)
+	require.ErrorIs(t, bank.Transfer(ctx, "alice", "bob", "stake", 1), errSecond)
+	require.ErrorIs(t, bank.Transfer(ctx, "alice", "bob", "stake", 1), errAlways)
+
+	// Scripted failures do not touch state
+	amount, err := bank.GetBalance(ctx, "alice", "stake")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), amount)
+
+	bank.SetError("Transfer", nil)
+	require.NoError(t, bank.Transfer(ctx, "alice", "bob", "stake", 1))
+
+	bank.SetError("GetBalance", errAlways)
+	bank.ClearBehaviors()
+	_, err = bank.GetBalance(ctx, "alice", "stake")
+	require.NoError(t, err)
+
+	assert.Panics(t, func() { bank.FailNext("Transfers", errFirst) })
+}
+
+func TestMockBalanceCapability_Latency(t *testing.T) {
+	bank := NewMockBalanceCapability("bank")
+	bank.SetLatency("GetBalance", 20*time.Millisecond)
+
+	start := time.Now()
+	_, err := bank.GetBalance(context.Background(), "alice", "stake")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	// A context that ends first cuts the wait short
+	bank.SetLatency("GetBalance", time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = bank.GetBalance(ctx, "alice", "stake")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	calls := bank.CallsTo("GetBalance")
+	require.Len(t, calls, 2)
+	assert.ErrorIs(t, calls[1].Err, context.DeadlineExceeded)
+}
+
+func TestMockBalanceCapability_Calls(t *testing.T) {
+	ctx := context.Background()
+	bank := NewMockBalanceCapability("bank")
+
+	require.NoError(t, bank.SetBalance(ctx, "alice", "stake", 10))
+	require.Error(t, bank.Transfer(ctx, "alice", "bob", "stake", 11))
+	_, err := bank.GetBalance(ctx, "bob", "stake")
+	require.NoError(t, err)
+
+	calls := bank.Calls()
+	require.Len(t, calls, 3)
+	assert.Equal(t, MockCall{Method: "SetBalance", Args: []any{types.AccountName("alice"), "stake", uint64(10)}}, calls[0])
+	assert.Equal(t, "Transfer", calls[1].Method)
+	assert.Equal(t, []any{types.AccountName("alice"), types.AccountName("bob"), "stake", uint64(11)}, calls[1].Args)
+	assert.ErrorIs(t, calls[1].Err, types.ErrInsufficientFunds)
+	assert.Equal(t, 1, bank.CallCount("GetBalance"))
+
+	bank.ResetCalls()
+	assert.Empty(t, bank.Calls())
+}
+
+func TestMockBalanceCapability_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	bank := NewMockBalanceCapability("bank")
+	require.NoError(t, bank.SetBalance(ctx, "alice", "stake", 10))
+
+	snapshot := bank.Snapshot()
+	require.NoError(t, bank.Transfer(ctx, "alice", "bob", "stake", 4))
+	assert.NotEqual(t, snapshot, bank.Snapshot())
+
+	bank.Restore(snapshot)
+	assert.Equal(t, snapshot, bank.Snapshot())
+	assert.Equal(t, []store.Balance{store.NewBalance("alice", "stake", 10)}, snapshot.Balances)
+}
+
+func TestMockBalanceCapability_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	bank := NewMockBalanceCapability("bank")
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, bank.AddBalance(ctx, "alice", "stake", 1))
+		}()
+	}
+	wg.Wait()
+
+	amount, err := bank.GetBalance(ctx, "alice", "stake")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(20), amount)
+	assert.Equal(t, 20, bank.CallCount("AddBalance"))
+}
@@ -0,0 +1,212 @@
+package testing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MockCall records one call to a mock capability.
+type MockCall struct {
+	// Method is the capability method name, e.g. "Transfer"
+	Method string
+
+	// Args are the call's arguments, excluding the context and callbacks
+	Args []any
+
+	// Err is the error the call returned
+	Err error
+}
+
+// mockBehavior holds the scripted errors and latencies of a mock capability
+// and records its calls. Its exported methods are promoted to the mocks.
+//
+// CONCURRENCY: Safe for concurrent use. The lock is not held while a call
+// waits out its latency.
+type mockBehavior struct {
+	mu sync.Mutex
+
+	// methods is the set of method names that may be scripted
+	methods map[string]bool
+
+	// nextErrs queues errors returned by upcoming calls, per method
+	nextErrs map[string][]error
+
+	// errs are returned by every call to a method, after nextErrs drains
+	errs map[string]error
+
+	latencies map[string]time.Duration
+	calls     []MockCall
+
+	// generation counts ResetCalls, so a call in flight across a reset does
+	// not store its result into the new log
+	generation uint64
+}
+
+// newMockBehavior creates a mockBehavior for the given method names
+func newMockBehavior(methods ...string) *mockBehavior {
+	set := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		set[method] = true
+	}
+	return &mockBehavior{
+		methods:   set,
+		nextErrs:  make(map[string][]error),
+		errs:      make(map[string]error),
+		latencies: make(map[string]time.Duration),
+	}
+}
+
+// FailNext makes the next call to method return err without touching state.
+// Repeated calls queue errors for successive calls.
+//
+// PRECONDITION: method names a capability method; otherwise FailNext panics.
+func (m *mockBehavior) FailNext(method string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.mustKnow(method)
+	m.nextErrs[method] = append(m.nextErrs[method], err)
+}
+
+// SetError makes every call to method return err without touching state,
+// once errors queued by FailNext are used up. A nil err clears it.
+//
+// PRECONDITION: method names a capability method; otherwise SetError panics.
+func (m *mockBehavior) SetError(method string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.mustKnow(method)
+	if err == nil {
+		delete(m.errs, method)
+		return
+	}
+	m.errs[method] = err
+}
+
+// SetLatency makes every call to method wait d before running. If the call's
+// context is done first, the call returns the context's error.
+// A zero d clears it.
+//
+// PRECONDITION: method names a capability method; otherwise SetLatency panics.
+func (m *mockBehavior) SetLatency(method string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.mustKnow(method)
+	if d <= 0 {
+		delete(m.latencies, method)
+		return
+	}
+	m.latencies[method] = d
+}
+
+// ClearBehaviors removes all scripted errors and latencies.
+func (m *mockBehavior) ClearBehaviors() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextErrs = make(map[string][]error)
+	m.errs = make(map[string]error)
+	m.latencies = make(map[string]time.Duration)
+}
+
+// Calls returns every recorded call, oldest first.
+func (m *mockBehavior) Calls() []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	calls := make([]MockCall, len(m.calls))
+	copy(calls, m.calls)
+	return calls
+}
+
+// CallsTo returns the recorded calls to method, oldest first.
+func (m *mockBehavior) CallsTo(method string) []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var calls []MockCall
+	for _, call := range m.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// CallCount returns the number of recorded calls to method.
+func (m *mockBehavior) CallCount(method string) int {
+	return len(m.CallsTo(method))
+}
+
+// ResetCalls clears the recorded calls.
+func (m *mockBehavior) ResetCalls() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = nil
+	m.generation++
+}
+
+// record appends a call and returns a function that stores its result.
+//
+// Usage:
+//
+//	defer m.record("GetBalance", &err, account, denom)()
+func (m *mockBehavior) record(method string, err *error, args ...any) func() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index, generation := len(m.calls), m.generation
+	m.calls = append(m.calls, MockCall{Method: method, Args: args})
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		if m.generation == generation {
+			m.calls[index].Err = *err
+		}
+	}
+}
+
+// intercept applies method's scripted latency and returns its scripted
+// error, if any
+func (m *mockBehavior) intercept(ctx context.Context, method string) error {
+	m.mu.Lock()
+	latency := m.latencies[method]
+	var err error
+	if queued := m.nextErrs[method]; len(queued) > 0 {
+		err = queued[0]
+		m.nextErrs[method] = queued[1:]
+	} else {
+		err = m.errs[method]
+	}
+	m.mu.Unlock()
+
+	if latency > 0 {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return err
+}
+
+// mustKnow panics if method is not a capability method, so a typo in a test
+// does not silently script nothing
+//
+// PRECONDITION: m.mu is held
+func (m *mockBehavior) mustKnow(method string) {
+	if !m.methods[method] {
+		panic(fmt.Sprintf("unknown capability method %q", method))
+	}
+}
@@ -96,6 +96,30 @@ func AssertSignDocDataValid(t *testing.T, msg types.SignDocSerializable) {
 	AssertSignDocDataDeterminism(t, msg, 100)
 }
 
+// AssertNoBareNumbers validates that SignDocData() encodes every numeric
+// value as a JSON string (see types.ValidateNoBareNumbers).
+//
+// SECURITY: A bare number such as {"amount":100} is parsed as a float64 by
+// JavaScript clients, so large integers and floats lose precision there and
+// re-serialized data stops matching the signed bytes. Encode amounts and
+// other numerics as strings (e.g. with the ",string" struct tag option or
+// types.StringUint64).
+//
+// Usage:
+//
+//	func TestMyMessage_NumberPolicy(t *testing.T) {
+//	    msg := &MyMessage{From: "alice", To: "bob", Amount: 100}
+//	    punnettesting.AssertNoBareNumbers(t, msg)
+//	}
+func AssertNoBareNumbers(t *testing.T, msg types.SignDocSerializable) {
+	t.Helper()
+
+	data, err := msg.SignDocData()
+	require.NoError(t, err, "SignDocData() returned error")
+	require.NoError(t, types.ValidateNoBareNumbers(data),
+		"SignDocData() must encode numbers as strings: %s", string(data))
+}
+
 // AssertSignDocDataDeterminismConcurrent validates that a SignDocSerializable
 // implementation produces deterministic output even when called concurrently
 // from multiple goroutines.
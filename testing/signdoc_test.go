@@ -189,6 +189,44 @@ func TestAssertSignDocDataValid_DetectsInvalidJSON(t *testing.T) {
 	assert.False(t, json.Valid(data), "invalidJSONMessage should return invalid JSON")
 }
 
+// =============================================================================
+// TESTS FOR AssertNoBareNumbers
+// =============================================================================
+
+// stringAmountMessage is a test message encoding its amount as a string
+type stringAmountMessage struct {
+	From   string `json:"from"`
+	Amount uint64 `json:"amount,string"`
+}
+
+func (m *stringAmountMessage) Type() string         { return "/test.StringAmountMsg" }
+func (m *stringAmountMessage) ValidateBasic() error { return nil }
+func (m *stringAmountMessage) GetSigners() []types.AccountName {
+	return []types.AccountName{types.AccountName(m.From)}
+}
+
+func (m *stringAmountMessage) SignDocData() (json.RawMessage, error) {
+	return json.Marshal(m)
+}
+
+func TestAssertNoBareNumbers_PassesForStringNumbers(t *testing.T) {
+	msg := &stringAmountMessage{From: "alice", Amount: 1 << 60}
+
+	// Should not panic or fail
+	AssertNoBareNumbers(t, msg)
+}
+
+func TestAssertNoBareNumbers_DetectsBareNumbers(t *testing.T) {
+	msg := &deterministicMessage{From: "alice", To: "bob", Amount: 100}
+
+	// Verify the helper would reject the bare amount
+	data, err := msg.SignDocData()
+	require.NoError(t, err)
+	err = types.ValidateNoBareNumbers(data)
+	require.ErrorIs(t, err, types.ErrSignDocMismatch)
+	assert.Contains(t, err.Error(), "at amount")
+}
+
 // =============================================================================
 // EDGE CASE TESTS
 // =============================================================================
@@ -0,0 +1,133 @@
+package testing
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// Stages of SignAndVerify, in order. A failure is reported with its stage.
+const (
+	StageSignDoc       = "sign doc"
+	StageKeyringSign   = "keyring sign"
+	StageAuthorization = "authorization"
+	StageVerification  = "verification"
+)
+
+// SignAndVerifyResult holds what SignAndVerify built along the way.
+type SignAndVerifyResult struct {
+	// SignDoc is the document that was signed
+	SignDoc *types.SignDoc
+
+	// SignBytes is the SHA-256 of SignDoc's canonical JSON
+	SignBytes []byte
+
+	// Signature is the keyring's signature, as placed in the transaction
+	Signature types.Signature
+
+	// Account is the single-key account the transaction was verified
+	// against: tx.Account at tx.Nonce, with keyName's key at threshold 1
+	Account *types.Account
+}
+
+// SignAndVerify signs tx with keyName from kr and verifies the result, going
+// through the whole client-to-chain path without a network or chain state:
+//
+//  1. sign doc: tx.ToSignDoc(chainID, tx.Nonce), its validation, JSON
+//     roundtrip and sign bytes
+//  2. keyring sign: kr.SignSignDoc, so signing policies and confirm hooks apply
+//  3. authorization: tx.Authorization is replaced with the single signature
+//     and tx.ValidateBasic must pass
+//  4. verification: tx.VerifyAuthorization against an account holding only
+//     keyName's public key
+//
+// It fails t naming the stage that failed; on success tx is signed and the
+// intermediate values are returned.
+//
+// Usage:
+//
+//	tx := types.NewTransaction("alice", 0, msgs, nil)
+//	tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+//	punnettesting.SignAndVerify(t, kr, "alice", tx, "test-chain")
+func SignAndVerify(t testing.TB, kr crypto.Keyring, keyName string, tx *types.Transaction, chainID string) *SignAndVerifyResult {
+	t.Helper()
+
+	result, stage, err := signAndVerify(kr, keyName, tx, chainID)
+	if err != nil {
+		t.Fatalf("SignAndVerify: %s stage failed: %v", stage, err)
+	}
+	return result
+}
+
+// signAndVerify runs the SignAndVerify stages, returning the failed stage
+// with the error
+func signAndVerify(kr crypto.Keyring, keyName string, tx *types.Transaction, chainID string) (*SignAndVerifyResult, string, error) {
+	if tx == nil {
+		return nil, StageSignDoc, fmt.Errorf("transaction cannot be nil")
+	}
+	if kr == nil {
+		return nil, StageKeyringSign, fmt.Errorf("keyring cannot be nil")
+	}
+
+	signDoc, err := tx.ToSignDoc(chainID, tx.Nonce)
+	if err != nil {
+		return nil, StageSignDoc, err
+	}
+	if err := signDoc.ValidateBasic(); err != nil {
+		return nil, StageSignDoc, err
+	}
+	if err := tx.ValidateSignDocRoundtrip(chainID, tx.Nonce); err != nil {
+		return nil, StageSignDoc, err
+	}
+	signBytes, err := signDoc.GetSignBytes()
+	if err != nil {
+		return nil, StageSignDoc, err
+	}
+
+	signer, err := kr.GetKey(keyName)
+	if err != nil {
+		return nil, StageKeyringSign, err
+	}
+	sigBytes, err := kr.SignSignDoc(keyName, signDoc)
+	if err != nil {
+		return nil, StageKeyringSign, err
+	}
+
+	pubKey := signer.PublicKey()
+	signature := types.Signature{
+		Algorithm: pubKey.Algorithm(),
+		PubKey:    pubKey.Bytes(),
+		Signature: sigBytes,
+	}
+	tx.Authorization = types.NewAuthorization(signature)
+	if err := tx.ValidateBasic(); err != nil {
+		return nil, StageAuthorization, err
+	}
+
+	account := &types.Account{
+		Name:      tx.Account,
+		Authority: types.NewAuthorityWithAlgorithm(1, pubKey.Algorithm(), pubKey.Bytes(), 1),
+		Nonce:     tx.Nonce,
+	}
+	if err := tx.VerifyAuthorization(chainID, account, noAccounts{}); err != nil {
+		return nil, StageVerification, err
+	}
+
+	return &SignAndVerifyResult{
+		SignDoc:   signDoc,
+		SignBytes: signBytes,
+		Signature: signature,
+		Account:   account,
+	}, "", nil
+}
+
+// noAccounts is a types.AccountGetter with no accounts, for verifying
+// authorizations without delegations
+type noAccounts struct{}
+
+// GetAccount always returns types.ErrNotFound
+func (noAccounts) GetAccount(name types.AccountName) (*types.Account, error) {
+	return nil, fmt.Errorf("%w: account %s", types.ErrNotFound, name)
+}
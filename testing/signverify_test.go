@@ -0,0 +1,97 @@
+package testing
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// signMessage is a minimal types.Message with a signer
+type signMessage struct {
+	Signer types.AccountName `json:"signer"`
+}
+
+func (signMessage) Type() string                            { return "/test.sign" }
+func (signMessage) ValidateBasic() error                    { return nil }
+func (m signMessage) GetSigners() []types.AccountName       { return []types.AccountName{m.Signer} }
+func (m signMessage) SignDocData() (json.RawMessage, error) { return json.Marshal(m) }
+
+// forgingKeyring signs with a different key than the one it reports
+type forgingKeyring struct {
+	crypto.Keyring
+	forger string
+}
+
+func (k forgingKeyring) SignSignDoc(_ string, doc crypto.PolicySignDoc) ([]byte, error) {
+	return k.Keyring.SignSignDoc(k.forger, doc)
+}
+
+func newSignTx(account types.AccountName, signer types.AccountName) *types.Transaction {
+	tx := types.NewTransaction(account, 3, []types.Message{signMessage{Signer: signer}}, nil)
+	tx.FeeSlippage = types.Ratio{Numerator: 0, Denominator: 1}
+	return tx
+}
+
+func TestSignAndVerify(t *testing.T) {
+	kr := crypto.NewKeyring(crypto.NewMemoryStore())
+	_, err := kr.NewKey("alice", crypto.AlgorithmEd25519)
+	require.NoError(t, err)
+
+	tx := newSignTx("alice", "alice")
+	result := SignAndVerify(t, kr, "alice", tx, "test-chain")
+
+	assert.Equal(t, "test-chain", result.SignDoc.ChainID)
+	assert.Equal(t, uint64(3), result.Account.Nonce)
+	assert.Equal(t, crypto.AlgorithmEd25519, result.Signature.Algorithm)
+	require.NotNil(t, tx.Authorization)
+	assert.Equal(t, []types.Signature{result.Signature}, tx.Authorization.Signatures)
+
+	// The signed transaction verifies on its own
+	require.NoError(t, tx.VerifyAuthorization("test-chain", result.Account, noAccounts{}))
+}
+
+func TestSignAndVerify_Stages(t *testing.T) {
+	kr := crypto.NewKeyring(crypto.NewMemoryStore())
+	_, err := kr.NewKey("alice", crypto.AlgorithmEd25519)
+	require.NoError(t, err)
+	_, err = kr.NewKey("mallory", crypto.AlgorithmEd25519)
+	require.NoError(t, err)
+	_, err = kr.NewKey("k1", crypto.AlgorithmSecp256k1)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		keyring crypto.Keyring
+		keyName string
+		tx      *types.Transaction
+		chainID string
+		stage   string
+	}{
+		{"nil transaction", kr, "alice", nil, "test-chain", StageSignDoc},
+		{"empty chain ID", kr, "alice", newSignTx("alice", "alice"), "", StageSignDoc},
+		{"missing key", kr, "bob", newSignTx("alice", "alice"), "test-chain", StageKeyringSign},
+		{"algorithm not accepted in transactions", kr, "k1", newSignTx("alice", "alice"), "test-chain", StageAuthorization},
+		{"account not a signer", kr, "alice", newSignTx("alice", "bob"), "test-chain", StageAuthorization},
+		{"wrong signing key", forgingKeyring{Keyring: kr, forger: "mallory"}, "alice", newSignTx("alice", "alice"), "test-chain", StageVerification},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, stage, err := signAndVerify(tc.keyring, tc.keyName, tc.tx, tc.chainID)
+			require.Error(t, err)
+			assert.Equal(t, tc.stage, stage)
+		})
+	}
+
+	// SignAndVerify reports the failed stage
+	rec := &recordingTB{TB: t}
+	assert.Nil(t, SignAndVerify(rec, kr, "bob", newSignTx("alice", "alice"), "test-chain"))
+	require.True(t, rec.failed)
+	require.Len(t, rec.errors, 1)
+	assert.Contains(t, rec.errors[0], StageKeyringSign)
+}
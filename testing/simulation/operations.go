@@ -0,0 +1,55 @@
+package simulation
+
+import (
+	"context"
+	"math/rand"
+
+	"github.com/blockberries/punnet-sdk/modules/bank"
+	"github.com/blockberries/punnet-sdk/testing/apptesting"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// RandomAccount returns one of accounts, chosen uniformly.
+//
+// PRECONDITION: accounts is not empty.
+func RandomAccount(r *rand.Rand, accounts []types.AccountName) types.AccountName {
+	return accounts[r.Intn(len(accounts))]
+}
+
+// RandomAmount returns an amount in [1, limit], or 0 if limit is 0.
+// Limits above 2^62 are capped there.
+func RandomAmount(r *rand.Rand, limit uint64) uint64 {
+	if limit == 0 {
+		return 0
+	}
+	return uint64(r.Int63n(int64(min(limit, uint64(1)<<62)))) + 1
+}
+
+// MsgSendOperation sends a random part of a random account's denom balance
+// to another random account. It skips when fewer than two accounts exist or
+// the sender holds none of denom.
+func MsgSendOperation(weight int, denom string) WeightedOperation {
+	return WeightedOperation{
+		Name:   "bank/send",
+		Weight: weight,
+		Op: func(r *rand.Rand, app *apptesting.TestApp, accounts []types.AccountName) (types.Message, error) {
+			if len(accounts) < 2 {
+				return nil, ErrSkip
+			}
+			from := RandomAccount(r, accounts)
+			to := RandomAccount(r, accounts)
+			for to == from {
+				to = RandomAccount(r, accounts)
+			}
+
+			balance, err := app.BalanceCapability().GetBalance(context.Background(), from, denom)
+			if err != nil {
+				return nil, err
+			}
+			if balance == 0 {
+				return nil, ErrSkip
+			}
+			return &bank.MsgSend{From: from, To: to, Amount: types.NewCoin(denom, RandomAmount(r, balance))}, nil
+		},
+	}
+}
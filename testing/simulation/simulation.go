@@ -0,0 +1,405 @@
+// Package simulation runs seeded random message sequences against modules
+// and checks their invariants after every block.
+//
+// A simulation builds an apptesting.TestApp, then for each block picks
+// operations by weight, lets each generate a message from the shared
+// *rand.Rand, delivers it, and ends the block. After every block all module
+// invariants (module.HasInvariants) run against the committed state. When one
+// is broken, the recorded steps are shrunk to a minimal sequence that still
+// breaks an invariant, so the failure report is short and replayable.
+//
+// INVARIANT: A run is a pure function of Config: the same seed, operations
+// and app options produce the same steps, results and state.
+//
+// Usage:
+//
+//	simulation.Run(t, simulation.Config{
+//		Seed:       simulation.SeedFromEnv(t, 42),
+//		Blocks:     50,
+//		Operations: []simulation.WeightedOperation{simulation.MsgSendOperation(10, "stake")},
+//	})
+package simulation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/module"
+	"github.com/blockberries/punnet-sdk/testing/apptesting"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// SeedEnv is the environment variable SeedFromEnv reads, so a failing seed
+// can be replayed without editing the test: SIM_SEED=1234 go test ./...
+const SeedEnv = "SIM_SEED"
+
+// Defaults applied to zero Config fields.
+const (
+	DefaultBlocks         = 20
+	DefaultOpsPerBlock    = 10
+	DefaultMaxShrinkRuns  = 200
+	defaultGenesisBalance = 1_000_000
+)
+
+// ErrSkip is returned by an Operation that cannot produce a valid message
+// in the current state (e.g. no account has funds). Skips are counted, not
+// failures.
+var ErrSkip = errors.New("operation skipped")
+
+// Operation generates one message from the current state.
+//
+// PRECONDITION: All randomness comes from r, so runs are reproducible.
+// Operations must not mutate app; the simulator delivers the message.
+type Operation func(r *rand.Rand, app *apptesting.TestApp, accounts []types.AccountName) (types.Message, error)
+
+// WeightedOperation is an operation and its relative selection weight.
+type WeightedOperation struct {
+	// Name identifies the operation in reports
+	Name string
+
+	// Weight is the relative frequency of the operation (must be positive)
+	Weight int
+
+	// Op generates the message
+	Op Operation
+}
+
+// Config configures a simulation.
+type Config struct {
+	// Seed seeds the random source
+	Seed int64
+
+	// Blocks is the number of blocks to run (DefaultBlocks if zero)
+	Blocks int
+
+	// OpsPerBlock is the number of operations per block (DefaultOpsPerBlock if zero)
+	OpsPerBlock int
+
+	// Operations are selected by weight; at least one is required
+	Operations []WeightedOperation
+
+	// Accounts are the genesis accounts operations draw from. If empty,
+	// apptesting.WellKnownAccounts are used. Each is funded with
+	// GenesisCoins.
+	Accounts []types.AccountName
+
+	// GenesisCoins fund each account (1,000,000 apptesting.DefaultDenom if empty)
+	GenesisCoins types.Coins
+
+	// AppOptions configure the TestApp, e.g. apptesting.WithModules.
+	// Genesis accounts come from Accounts and GenesisCoins.
+	AppOptions []apptesting.Option
+
+	// MaxShrinkRuns bounds the replays spent minimizing a failure
+	// (DefaultMaxShrinkRuns if zero, negative disables shrinking)
+	MaxShrinkRuns int
+}
+
+// withDefaults returns cfg with zero fields set to their defaults
+func (cfg Config) withDefaults() Config {
+	if cfg.Blocks == 0 {
+		cfg.Blocks = DefaultBlocks
+	}
+	if cfg.OpsPerBlock == 0 {
+		cfg.OpsPerBlock = DefaultOpsPerBlock
+	}
+	if len(cfg.Accounts) == 0 {
+		cfg.Accounts = append([]types.AccountName(nil), apptesting.WellKnownAccounts...)
+	}
+	if len(cfg.GenesisCoins) == 0 {
+		cfg.GenesisCoins = types.NewCoins(types.NewCoin(apptesting.DefaultDenom, defaultGenesisBalance))
+	}
+	if cfg.MaxShrinkRuns == 0 {
+		cfg.MaxShrinkRuns = DefaultMaxShrinkRuns
+	}
+	return cfg
+}
+
+// validate checks the configuration
+func (cfg Config) validate() error {
+	if cfg.Blocks < 0 || cfg.OpsPerBlock < 0 {
+		return fmt.Errorf("blocks and ops per block must not be negative")
+	}
+	if len(cfg.Operations) == 0 {
+		return fmt.Errorf("at least one operation is required")
+	}
+	for _, op := range cfg.Operations {
+		if op.Weight <= 0 {
+			return fmt.Errorf("operation %q: weight must be positive", op.Name)
+		}
+		if op.Op == nil {
+			return fmt.Errorf("operation %q: nil Op", op.Name)
+		}
+	}
+	return nil
+}
+
+// Step is one delivered message.
+type Step struct {
+	// Block is the 0-based block the message was delivered in
+	Block int
+
+	// Operation is the name of the generating operation
+	Operation string
+
+	// Msg is the delivered message
+	Msg types.Message
+
+	// Err is the delivery error, nil if the message succeeded
+	Err error
+}
+
+// String describes the step for reports
+func (s Step) String() string {
+	status := "ok"
+	if s.Err != nil {
+		status = "failed: " + s.Err.Error()
+	}
+	return fmt.Sprintf("block %d: %s %s (%s)", s.Block, s.Operation, s.Msg.Type(), status)
+}
+
+// OperationStats counts the outcomes of one operation.
+type OperationStats struct {
+	Succeeded int
+	Failed    int
+	Skipped   int
+}
+
+// Result summarizes a simulation.
+type Result struct {
+	// Seed is the seed the run used
+	Seed int64
+
+	// Steps are the delivered messages in order
+	Steps []Step
+
+	// Stats are per-operation outcome counts, keyed by operation name
+	Stats map[string]*OperationStats
+
+	// App is the final application state
+	App *apptesting.TestApp
+}
+
+// Failure describes a broken invariant.
+type Failure struct {
+	// Seed reproduces the failing run
+	Seed int64
+
+	// Block is the 0-based block after which the invariant broke
+	Block int
+
+	// Violations are the broken invariants
+	Violations []module.Violation
+
+	// Steps are the steps up to the failure
+	Steps []Step
+
+	// Minimized is a shrunk subsequence of Steps that still breaks an
+	// invariant when replayed (equal to Steps if shrinking is disabled).
+	// Each step keeps the Err of the original run.
+	Minimized []Step
+}
+
+// Error implements error
+func (f *Failure) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invariant broken after block %d (seed %d; rerun with %s=%d):\n", f.Block, f.Seed, SeedEnv, f.Seed)
+	for _, v := range f.Violations {
+		fmt.Fprintf(&b, "  %s: %s\n", v.Route, v.Message)
+	}
+	fmt.Fprintf(&b, "minimized to %d of %d steps:\n", len(f.Minimized), len(f.Steps))
+	for _, s := range f.Minimized {
+		fmt.Fprintf(&b, "  %s\n", s)
+	}
+	return b.String()
+}
+
+// SeedFromEnv returns the seed in SeedEnv, or def if it is unset.
+func SeedFromEnv(t testing.TB, def int64) int64 {
+	t.Helper()
+
+	value := os.Getenv(SeedEnv)
+	if value == "" {
+		return def
+	}
+	seed, err := strconv.ParseInt(value, 10, 64)
+	require.NoError(t, err, "invalid %s", SeedEnv)
+	return seed
+}
+
+// Run runs a simulation and fails t with a minimized report if an invariant
+// breaks.
+func Run(t testing.TB, cfg Config) *Result {
+	t.Helper()
+
+	result, failure := Simulate(t, cfg)
+	if failure != nil {
+		t.Fatal(failure.Error())
+	}
+	t.Logf("simulation seed %d: %d steps", result.Seed, len(result.Steps))
+	return result
+}
+
+// Simulate runs a simulation. It returns the Result, and a Failure (with a
+// minimized step sequence) if an invariant broke; the run stops at the first
+// broken block. Configuration and setup errors fail t.
+func Simulate(t testing.TB, cfg Config) (*Result, *Failure) {
+	t.Helper()
+
+	cfg = cfg.withDefaults()
+	require.NoError(t, cfg.validate(), "invalid simulation config")
+
+	r := rand.New(rand.NewSource(cfg.Seed))
+	app := newApp(t, cfg)
+	result := &Result{Seed: cfg.Seed, Stats: make(map[string]*OperationStats), App: app}
+	for _, op := range cfg.Operations {
+		result.Stats[op.Name] = &OperationStats{}
+	}
+
+	totalWeight := 0
+	for _, op := range cfg.Operations {
+		totalWeight += op.Weight
+	}
+
+	for block := 0; block < cfg.Blocks; block++ {
+		for i := 0; i < cfg.OpsPerBlock; i++ {
+			op := pickOperation(r, cfg.Operations, totalWeight)
+			stats := result.Stats[op.Name]
+
+			msg, err := op.Op(r, app, cfg.Accounts)
+			if errors.Is(err, ErrSkip) {
+				stats.Skipped++
+				continue
+			}
+			require.NoError(t, err, "operation %s failed to generate a message (seed %d)", op.Name, cfg.Seed)
+			require.NotNil(t, msg, "operation %s returned a nil message", op.Name)
+
+			step := Step{Block: block, Operation: op.Name, Msg: msg}
+			_, step.Err = app.DeliverMsg(t, msg)
+			if step.Err != nil {
+				stats.Failed++
+			} else {
+				stats.Succeeded++
+			}
+			result.Steps = append(result.Steps, step)
+		}
+		app.NextBlock(t)
+
+		violations := checkInvariants(t, app)
+		if len(violations) > 0 {
+			failure := &Failure{
+				Seed:       cfg.Seed,
+				Block:      block,
+				Violations: violations,
+				Steps:      result.Steps,
+			}
+			failure.Minimized = shrink(t, cfg, result.Steps)
+			return result, failure
+		}
+	}
+	return result, nil
+}
+
+// newApp builds the TestApp for cfg
+func newApp(t testing.TB, cfg Config) *apptesting.TestApp {
+	t.Helper()
+
+	genesis := make([]apptesting.GenesisAccount, len(cfg.Accounts))
+	for i, name := range cfg.Accounts {
+		genesis[i] = apptesting.GenesisAccount{Name: name, Coins: cfg.GenesisCoins}
+	}
+	opts := append([]apptesting.Option{apptesting.WithGenesisAccounts(genesis...)}, cfg.AppOptions...)
+	return apptesting.NewTestApp(t, opts...)
+}
+
+// pickOperation selects an operation with probability proportional to its weight
+func pickOperation(r *rand.Rand, ops []WeightedOperation, totalWeight int) WeightedOperation {
+	n := r.Intn(totalWeight)
+	for _, op := range ops {
+		if n < op.Weight {
+			return op
+		}
+		n -= op.Weight
+	}
+	return ops[len(ops)-1]
+}
+
+// checkInvariants runs every invariant of the app's modules
+func checkInvariants(t testing.TB, app *apptesting.TestApp) []module.Violation {
+	t.Helper()
+
+	mods, err := app.ModuleManager().Modules()
+	require.NoError(t, err)
+	registry := module.NewInvariantRegistry()
+	require.NoError(t, registry.RegisterModules(mods))
+	violations, err := registry.CheckAll(context.Background())
+	require.NoError(t, err)
+	return violations
+}
+
+// replay delivers steps on a fresh app, ending a block wherever the block
+// number changes and after the last step, and reports whether an invariant
+// broke at any block end.
+func replay(t testing.TB, cfg Config, steps []Step) bool {
+	t.Helper()
+
+	app := newApp(t, cfg)
+	for i, step := range steps {
+		_, _ = app.DeliverMsg(t, step.Msg)
+		if i+1 < len(steps) && steps[i+1].Block == step.Block {
+			continue
+		}
+		app.NextBlock(t)
+		if len(checkInvariants(t, app)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// shrink returns a small subsequence of steps that still breaks an
+// invariant, removing chunks of halving size (delta debugging) while
+// replays remain within cfg.MaxShrinkRuns.
+//
+// Complexity: O(MaxShrinkRuns) replays
+func shrink(t testing.TB, cfg Config, steps []Step) []Step {
+	t.Helper()
+
+	current := append([]Step(nil), steps...)
+	if cfg.MaxShrinkRuns < 0 {
+		return current
+	}
+
+	runs := 0
+	chunks := 2
+	for len(current) > 1 && runs < cfg.MaxShrinkRuns {
+		size := (len(current) + chunks - 1) / chunks
+		reduced := false
+		for start := 0; start < len(current) && runs < cfg.MaxShrinkRuns; start += size {
+			end := min(start+size, len(current))
+			candidate := append(append([]Step(nil), current[:start]...), current[end:]...)
+			runs++
+			if replay(t, cfg, candidate) {
+				current = candidate
+				chunks = max(chunks-1, 2)
+				reduced = true
+				break
+			}
+		}
+		if !reduced {
+			if size == 1 {
+				break
+			}
+			chunks = min(chunks*2, len(current))
+		}
+	}
+	return current
+}
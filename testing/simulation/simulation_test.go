@@ -0,0 +1,177 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/module"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/testing/apptesting"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+const typeMsgPoke = "/punnet.canary.v1.MsgPoke"
+
+// msgPoke records N with the canary module
+type msgPoke struct {
+	Signer types.AccountName
+	N      int
+}
+
+func (m *msgPoke) Type() string                    { return typeMsgPoke }
+func (m *msgPoke) ValidateBasic() error            { return nil }
+func (m *msgPoke) GetSigners() []types.AccountName { return []types.AccountName{m.Signer} }
+
+// canarySpec returns a module whose invariant breaks once a poke with
+// badValue has been delivered. Each app build gets fresh module state.
+func canarySpec(badValue int) module.ModuleSpec {
+	return module.ModuleSpec{
+		Name: "canary",
+		Create: func(module.Capabilities) (module.Module, error) {
+			poked := make(map[int]bool)
+			return module.NewModuleBuilder("canary").
+				WithMsgHandler(typeMsgPoke, func(ctx *runtime.Context, msg types.Message) ([]effects.Effect, error) {
+					poked[msg.(*msgPoke).N] = true
+					return nil, nil
+				}).
+				WithInvariant("bad-value", func(ctx context.Context) (string, bool) {
+					return fmt.Sprintf("poked %d", badValue), poked[badValue]
+				}).
+				Build()
+		},
+	}
+}
+
+func pokeOperation(values int) WeightedOperation {
+	return WeightedOperation{
+		Name:   "canary/poke",
+		Weight: 1,
+		Op: func(r *rand.Rand, app *apptesting.TestApp, accounts []types.AccountName) (types.Message, error) {
+			return &msgPoke{Signer: RandomAccount(r, accounts), N: r.Intn(values)}, nil
+		},
+	}
+}
+
+func sumBalances(t *testing.T, app *apptesting.TestApp, accounts []types.AccountName) uint64 {
+	t.Helper()
+	var total uint64
+	for _, name := range accounts {
+		total += app.Balance(t, name, apptesting.DefaultDenom)
+	}
+	return total
+}
+
+func TestRun_BankSend(t *testing.T) {
+	result := Run(t, Config{
+		Seed:       SeedFromEnv(t, 7),
+		Blocks:     10,
+		Operations: []WeightedOperation{MsgSendOperation(1, apptesting.DefaultDenom)},
+	})
+
+	stats := result.Stats["bank/send"]
+	require.NotNil(t, stats)
+	assert.Equal(t, 100, stats.Succeeded+stats.Failed+stats.Skipped)
+	assert.Positive(t, stats.Succeeded)
+	assert.Len(t, result.Steps, stats.Succeeded+stats.Failed)
+
+	// Transfers conserve the supply
+	assert.Equal(t, uint64(3*defaultGenesisBalance), sumBalances(t, result.App, apptesting.WellKnownAccounts))
+	assert.Equal(t, uint64(11), result.App.Header().Height)
+}
+
+func TestSimulate_Reproducible(t *testing.T) {
+	run := func(seed int64) ([]string, []uint64) {
+		result, failure := Simulate(t, Config{
+			Seed:       seed,
+			Blocks:     5,
+			Operations: []WeightedOperation{MsgSendOperation(3, apptesting.DefaultDenom), pokeOperation(5)},
+			AppOptions: []apptesting.Option{apptesting.WithModules(canarySpec(-1))},
+		})
+		require.Nil(t, failure)
+
+		var steps []string
+		for _, step := range result.Steps {
+			steps = append(steps, fmt.Sprintf("%s %+v", step, step.Msg))
+		}
+		var balances []uint64
+		for _, name := range apptesting.WellKnownAccounts {
+			balances = append(balances, result.App.Balance(t, name, apptesting.DefaultDenom))
+		}
+		return steps, balances
+	}
+
+	steps1, balances1 := run(42)
+	steps2, balances2 := run(42)
+	assert.Equal(t, steps1, steps2)
+	assert.Equal(t, balances1, balances2)
+
+	steps3, _ := run(43)
+	assert.NotEqual(t, steps1, steps3)
+}
+
+func TestSimulate_MinimizesFailure(t *testing.T) {
+	cfg := Config{
+		Seed:       1,
+		Blocks:     10,
+		Operations: []WeightedOperation{MsgSendOperation(3, apptesting.DefaultDenom), pokeOperation(20)},
+		AppOptions: []apptesting.Option{apptesting.WithModules(canarySpec(13))},
+	}
+
+	_, failure := Simulate(t, cfg)
+	require.NotNil(t, failure, "expected the canary invariant to break")
+	require.Len(t, failure.Violations, 1)
+	assert.Equal(t, "canary/bad-value", failure.Violations[0].Route)
+	assert.Greater(t, len(failure.Steps), 1)
+
+	require.Len(t, failure.Minimized, 1)
+	assert.Equal(t, &msgPoke{Signer: failure.Minimized[0].Msg.(*msgPoke).Signer, N: 13}, failure.Minimized[0].Msg)
+	assert.True(t, replay(t, cfg.withDefaults(), failure.Minimized))
+	assert.Contains(t, failure.Error(), "SIM_SEED=1")
+
+	// Without shrinking the full sequence is reported
+	cfg.MaxShrinkRuns = -1
+	_, failure = Simulate(t, cfg)
+	require.NotNil(t, failure)
+	assert.Equal(t, failure.Steps, failure.Minimized)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	op := MsgSendOperation(1, apptesting.DefaultDenom)
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"valid", Config{Operations: []WeightedOperation{op}}, false},
+		{"no operations", Config{}, true},
+		{"zero weight", Config{Operations: []WeightedOperation{{Name: "x", Op: op.Op}}}, true},
+		{"nil op", Config{Operations: []WeightedOperation{{Name: "x", Weight: 1}}}, true},
+		{"negative blocks", Config{Blocks: -1, Operations: []WeightedOperation{op}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.withDefaults().validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRandomAmount(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	assert.Zero(t, RandomAmount(r, 0))
+	for i := 0; i < 100; i++ {
+		amount := RandomAmount(r, 5)
+		assert.True(t, amount >= 1 && amount <= 5, amount)
+	}
+	assert.Positive(t, RandomAmount(r, ^uint64(0)))
+}
@@ -0,0 +1,162 @@
+// Command vectors generates Punnet SDK signing test vectors and verifies vectors
+// produced by other implementations (JS, Rust, Python, ...) against this SDK.
+//
+// Usage:
+//
+//	vectors verify [-q] <file>
+//	vectors generate [-o <file>]
+//
+// verify writes the machine-readable conformance report to stdout as JSON; a short
+// human-readable summary is written to stderr unless -q is given.
+//
+// generate writes the vector file to -o (stdout if omitted). Output is
+// deterministic, and an existing file is only rewritten if its content changes,
+// which makes the command suitable for go:generate.
+//
+// Exit codes:
+//
+//	0 - all vectors passed
+//	1 - at least one vector failed
+//	2 - usage or I/O error
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/blockberries/punnet-sdk/testing/vectors"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command and returns the process exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) < 1 {
+		usage(stderr)
+		return 2
+	}
+
+	switch args[0] {
+	case "verify":
+		return runVerify(args[1:], stdout, stderr)
+	case "generate":
+		return runGenerate(args[1:], stdout, stderr)
+	default:
+		usage(stderr)
+		return 2
+	}
+}
+
+// runGenerate implements the generate subcommand.
+func runGenerate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("o", "", "output file (default stdout)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		usage(stderr)
+		return 2
+	}
+
+	file, err := vectors.GenerateTestVectors()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 2
+	}
+
+	if *out == "" {
+		data, err := vectors.MarshalTestVectorFile(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 2
+		}
+		if _, err := stdout.Write(data); err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 2
+		}
+		return 0
+	}
+
+	changed, err := vectors.WriteTestVectorFile(*out, file)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 2
+	}
+	if changed {
+		fmt.Fprintf(stderr, "wrote %d vectors to %s (content hash %s)\n", len(file.Vectors), *out, file.ContentHash)
+	} else {
+		fmt.Fprintf(stderr, "%s is up to date\n", *out)
+	}
+	return 0
+}
+
+// runVerify implements the verify subcommand.
+func runVerify(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	quiet := fs.Bool("q", false, "suppress the human-readable summary on stderr")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		usage(stderr)
+		return 2
+	}
+
+	file, err := vectors.LoadTestVectorFile(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 2
+	}
+
+	report := vectors.RunConformance(file)
+
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		fmt.Fprintf(stderr, "error: failed to write report: %v\n", err)
+		return 2
+	}
+
+	if !*quiet {
+		printSummary(stderr, report)
+	}
+
+	if !report.OK() {
+		return 1
+	}
+	return 0
+}
+
+// printSummary writes failed checks and totals in human-readable form.
+func printSummary(w io.Writer, report *vectors.ConformanceReport) {
+	for _, result := range report.Results {
+		if result.Passed {
+			continue
+		}
+		fmt.Fprintf(w, "FAIL %s\n", result.Name)
+		for _, check := range result.Checks {
+			if check.Status != vectors.CheckFail {
+				continue
+			}
+			name := check.Check
+			if check.Algorithm != "" {
+				name += "/" + check.Algorithm
+			}
+			fmt.Fprintf(w, "  %s: %s\n", name, check.Detail)
+		}
+	}
+	fmt.Fprintf(w, "%d/%d vectors passed (format version %s)\n", report.Passed, report.Total, report.Version)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: vectors verify [-q] <file>")
+	fmt.Fprintln(w, "       vectors generate [-o <file>]")
+}
@@ -0,0 +1,421 @@
+package vectors
+
+import (
+	"bytes"
+	stdecdsa "crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+	"github.com/blockberries/punnet-sdk/types"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	secp256k1ecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// CheckStatus is the outcome of a single conformance check.
+type CheckStatus string
+
+const (
+	// CheckPass means the implementation under test agrees with this SDK.
+	CheckPass CheckStatus = "pass"
+
+	// CheckFail means the implementation under test disagrees with this SDK.
+	CheckFail CheckStatus = "fail"
+
+	// CheckSkip means the check does not apply (e.g. seed-only entries).
+	CheckSkip CheckStatus = "skip"
+)
+
+// Conformance check names used in reports.
+const (
+	CheckBuildSignDoc     = "build_sign_doc"
+	CheckSignDocJSON      = "sign_doc_json"
+	CheckSignBytes        = "sign_bytes"
+	CheckKeyDerivation    = "key_derivation"
+	CheckSignature        = "signature"
+	CheckDeterministicSig = "deterministic_signature"
+	CheckRejection        = "rejection"
+)
+
+// CheckResult records the outcome of a single check within a vector.
+type CheckResult struct {
+	// Check is the check name (one of the Check* constants).
+	Check string `json:"check"`
+
+	// Algorithm is set for per-algorithm checks.
+	Algorithm string `json:"algorithm,omitempty"`
+
+	// Status is the check outcome.
+	Status CheckStatus `json:"status"`
+
+	// Detail explains failures and skips.
+	Detail string `json:"detail,omitempty"`
+}
+
+// VectorResult records the outcome of all checks for one test vector.
+type VectorResult struct {
+	// Name is the vector name.
+	Name string `json:"name"`
+
+	// Category is the vector category.
+	Category string `json:"category"`
+
+	// Passed is true if no check failed.
+	Passed bool `json:"passed"`
+
+	// Checks lists every check performed, in execution order.
+	Checks []CheckResult `json:"checks"`
+}
+
+// ConformanceReport is the machine-readable result of verifying a test vector
+// file produced by another implementation.
+type ConformanceReport struct {
+	// Version is the version of the verified vector file.
+	Version string `json:"version"`
+
+	// Total is the number of vectors verified.
+	Total int `json:"total"`
+
+	// Passed is the number of vectors with no failed checks.
+	Passed int `json:"passed"`
+
+	// Failed is the number of vectors with at least one failed check.
+	Failed int `json:"failed"`
+
+	// Results holds the per-vector results, in file order.
+	Results []VectorResult `json:"results"`
+}
+
+// OK returns true if every vector passed.
+func (r *ConformanceReport) OK() bool {
+	return r != nil && r.Failed == 0
+}
+
+// LoadTestVectorFile reads and parses a test vector JSON file.
+func LoadTestVectorFile(path string) (*TestVectorFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read test vector file: %w", err)
+	}
+	return ParseTestVectorFile(data)
+}
+
+// ParseTestVectorFile parses test vector JSON.
+func ParseTestVectorFile(data []byte) (*TestVectorFile, error) {
+	var file TestVectorFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse test vector file: %w", err)
+	}
+	return &file, nil
+}
+
+// RunConformance verifies every vector in file against this SDK.
+//
+// For each vector the runner re-derives the SignDoc JSON and sign bytes from the
+// vector input and compares them byte-for-byte with the expected values, then
+// for each algorithm entry checks key derivation and signature validity.
+// Rejection vectors are instead checked to fail validation with the expected
+// error class.
+// Ed25519 signatures are additionally required to be byte-identical because
+// Ed25519 signing is deterministic; ECDSA signatures are only verified, since
+// implementations may legitimately use different nonce derivations.
+//
+// INVARIANT: RunConformance never panics on malformed vectors; problems are
+// reported as failed checks.
+func RunConformance(file *TestVectorFile) *ConformanceReport {
+	report := &ConformanceReport{Results: make([]VectorResult, 0)}
+	if file == nil {
+		return report
+	}
+	report.Version = file.Version
+
+	for _, vector := range file.Vectors {
+		result := verifyVectorConformance(vector)
+		report.Results = append(report.Results, result)
+		report.Total++
+		if result.Passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+	}
+	return report
+}
+
+// verifyVectorConformance runs all checks for a single vector.
+func verifyVectorConformance(vector TestVector) VectorResult {
+	result := VectorResult{Name: vector.Name, Category: vector.Category, Passed: true}
+	record := func(c CheckResult) {
+		if c.Status == CheckFail {
+			result.Passed = false
+		}
+		result.Checks = append(result.Checks, c)
+	}
+
+	if vector.Category == CategoryRejection || vector.Expected.ErrorClass != "" {
+		record(verifyRejectionConformance(vector))
+		return result
+	}
+
+	signDoc, err := SignDocFromInput(vector.Input)
+	if err != nil {
+		record(CheckResult{Check: CheckBuildSignDoc, Status: CheckFail, Detail: err.Error()})
+		return result
+	}
+	record(CheckResult{Check: CheckBuildSignDoc, Status: CheckPass})
+
+	signDocJSON, err := signDoc.ToJSON()
+	if err != nil {
+		record(CheckResult{Check: CheckSignDocJSON, Status: CheckFail, Detail: err.Error()})
+		return result
+	}
+	if mismatch := types.ExplainSignBytesMismatch([]byte(vector.Expected.SignDocJSON), signDocJSON); mismatch != nil {
+		record(CheckResult{Check: CheckSignDocJSON, Status: CheckFail, Detail: mismatch.String()})
+	} else {
+		record(CheckResult{Check: CheckSignDocJSON, Status: CheckPass})
+	}
+
+	signBytes, err := signDoc.GetSignBytes()
+	if err != nil {
+		record(CheckResult{Check: CheckSignBytes, Status: CheckFail, Detail: err.Error()})
+		return result
+	}
+	if got := hex.EncodeToString(signBytes); got != strings.ToLower(vector.Expected.SignBytesHex) {
+		record(CheckResult{Check: CheckSignBytes, Status: CheckFail,
+			Detail: fmt.Sprintf("expected %s, got %s", vector.Expected.SignBytesHex, got)})
+	} else {
+		record(CheckResult{Check: CheckSignBytes, Status: CheckPass})
+	}
+
+	// Iterate algorithms in sorted order so reports are reproducible.
+	algos := make([]string, 0, len(vector.Expected.Signatures))
+	for algo := range vector.Expected.Signatures {
+		algos = append(algos, algo)
+	}
+	sort.Strings(algos)
+
+	for _, algo := range algos {
+		for _, c := range verifySignatureConformance(algo, vector.Expected.Signatures[algo], signBytes) {
+			record(c)
+		}
+	}
+	return result
+}
+
+// verifyRejectionConformance checks that this SDK rejects a must-reject vector
+// with the expected error class.
+func verifyRejectionConformance(vector TestVector) CheckResult {
+	class, err := validateRejectionInput(vector.Input)
+	switch {
+	case err == nil:
+		return CheckResult{Check: CheckRejection, Status: CheckFail, Detail: "input was accepted but must be rejected"}
+	case vector.Expected.ErrorClass != "" && class != vector.Expected.ErrorClass:
+		return CheckResult{Check: CheckRejection, Status: CheckFail,
+			Detail: fmt.Sprintf("rejected with class %s, expected %s: %v", class, vector.Expected.ErrorClass, err)}
+	default:
+		return CheckResult{Check: CheckRejection, Status: CheckPass}
+	}
+}
+
+// verifySignatureConformance checks key derivation and signature validity for one algorithm entry.
+func verifySignatureConformance(algoName string, sig TestVectorSignature, signBytes []byte) []CheckResult {
+	if strings.HasSuffix(algoName, "_seed") || sig.SignatureHex == "" {
+		return []CheckResult{{Check: CheckSignature, Algorithm: algoName, Status: CheckSkip, Detail: "no signature to verify"}}
+	}
+
+	algo := crypto.Algorithm(algoName)
+	if !algo.IsValid() {
+		return []CheckResult{{Check: CheckSignature, Algorithm: algoName, Status: CheckFail, Detail: "unknown algorithm"}}
+	}
+
+	fail := func(check, format string, args ...interface{}) CheckResult {
+		return CheckResult{Check: check, Algorithm: algoName, Status: CheckFail, Detail: fmt.Sprintf(format, args...)}
+	}
+	pass := func(check string) CheckResult {
+		return CheckResult{Check: check, Algorithm: algoName, Status: CheckPass}
+	}
+
+	pubKey, err := hex.DecodeString(sig.PublicKeyHex)
+	if err != nil {
+		return []CheckResult{fail(CheckSignature, "invalid public_key_hex: %v", err)}
+	}
+	signature, err := hex.DecodeString(sig.SignatureHex)
+	if err != nil {
+		return []CheckResult{fail(CheckSignature, "invalid signature_hex: %v", err)}
+	}
+
+	var results []CheckResult
+
+	// Key derivation and deterministic signing are only checkable when the
+	// vector ships its (test-only) private key.
+	var privKey []byte
+	if sig.PrivateKeyHex != "" {
+		privKey, err = hex.DecodeString(sig.PrivateKeyHex)
+		if err != nil {
+			return []CheckResult{fail(CheckKeyDerivation, "invalid private_key_hex: %v", err)}
+		}
+		priv, err := crypto.PrivateKeyFromBytes(algo, privKey)
+		switch {
+		case err != nil:
+			results = append(results, fail(CheckKeyDerivation, "invalid private key: %v", err))
+		case !bytes.Equal(priv.PublicKey().Bytes(), pubKey):
+			results = append(results, fail(CheckKeyDerivation, "public key does not match private key: derived %x",
+				priv.PublicKey().Bytes()))
+		default:
+			results = append(results, pass(CheckKeyDerivation))
+		}
+	}
+
+	if err := verifyPrehashedSignature(algo, pubKey, signBytes, signature); err != nil {
+		results = append(results, fail(CheckSignature, "%v", err))
+	} else {
+		results = append(results, pass(CheckSignature))
+	}
+
+	if algo == crypto.AlgorithmEd25519 && len(privKey) == ed25519.PrivateKeySize {
+		expected := ed25519.Sign(ed25519.PrivateKey(privKey), signBytes)
+		if !bytes.Equal(expected, signature) {
+			results = append(results, fail(CheckDeterministicSig, "expected %x", expected))
+		} else {
+			results = append(results, pass(CheckDeterministicSig))
+		}
+	}
+
+	return results
+}
+
+// verifyPrehashedSignature verifies a signature over signBytes.
+//
+// Test vectors sign the 32-byte sign bytes directly: ECDSA signatures use them as
+// the message digest (no additional hashing), matching the vector generator.
+func verifyPrehashedSignature(algo crypto.Algorithm, pubKey, signBytes, signature []byte) error {
+	switch algo {
+	case crypto.AlgorithmEd25519:
+		if len(pubKey) != ed25519.PublicKeySize {
+			return fmt.Errorf("invalid ed25519 public key size %d", len(pubKey))
+		}
+		if len(signature) != ed25519.SignatureSize {
+			return fmt.Errorf("invalid ed25519 signature size %d", len(signature))
+		}
+		if !ed25519.Verify(ed25519.PublicKey(pubKey), signBytes, signature) {
+			return fmt.Errorf("ed25519 signature does not verify")
+		}
+		return nil
+
+	case crypto.AlgorithmSecp256k1:
+		if len(signature) != 64 {
+			return fmt.Errorf("invalid secp256k1 signature size %d (expected R || S)", len(signature))
+		}
+		key, err := secp256k1.ParsePubKey(pubKey)
+		if err != nil {
+			return fmt.Errorf("invalid secp256k1 public key: %w", err)
+		}
+		var r, s secp256k1.ModNScalar
+		if r.SetByteSlice(signature[:32]) || s.SetByteSlice(signature[32:]) {
+			return fmt.Errorf("secp256k1 signature scalar overflows curve order")
+		}
+		if !secp256k1ecdsa.NewSignature(&r, &s).Verify(signBytes, key) {
+			return fmt.Errorf("secp256k1 signature does not verify")
+		}
+		return nil
+
+	case crypto.AlgorithmSecp256r1:
+		if len(signature) != 64 {
+			return fmt.Errorf("invalid secp256r1 signature size %d (expected R || S)", len(signature))
+		}
+		x, y := elliptic.UnmarshalCompressed(elliptic.P256(), pubKey)
+		if x == nil {
+			return fmt.Errorf("invalid secp256r1 compressed public key")
+		}
+		key := &stdecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !stdecdsa.Verify(key, signBytes, r, s) {
+			return fmt.Errorf("secp256r1 signature does not verify")
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported algorithm %s", algo)
+	}
+}
+
+// newInputSignDoc returns the SignDoc of input without messages. It is built
+// field by field rather than with types.NewSignDocWithFee, so a malformed
+// chain ID reaches ValidateBasic like any other malformed field.
+func newInputSignDoc(input TestVectorInput, accountSequence, nonce uint64, fee types.SignDocFee, feeSlippage types.SignDocRatio) *types.SignDoc {
+	return &types.SignDoc{
+		Version:         types.SignDocVersion,
+		ChainID:         input.ChainID,
+		AccountSequence: types.StringUint64(accountSequence),
+		Account:         input.Account,
+		Nonce:           types.StringUint64(nonce),
+		Memo:            input.Memo,
+		Messages:        make([]types.SignDocMessage, 0),
+		Fee:             fee,
+		FeeSlippage:     feeSlippage,
+	}
+}
+
+// SignDocFromInput constructs a SignDoc from test vector input, returning an
+// error instead of panicking on malformed input. Null message data is preserved.
+//
+// Message data is compacted (whitespace removed) but otherwise used verbatim, so
+// key order in the vector file is significant, exactly as it is for signing.
+// Messages with RawData are used byte-for-byte without compaction.
+func SignDocFromInput(input TestVectorInput) (*types.SignDoc, error) {
+	accountSequence, err := strconv.ParseUint(input.AccountSequence, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid account_sequence %q: %w", input.AccountSequence, err)
+	}
+	nonce, err := strconv.ParseUint(input.Nonce, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce %q: %w", input.Nonce, err)
+	}
+
+	feeCoins := make([]types.SignDocCoin, len(input.Fee.Amount))
+	for i, coin := range input.Fee.Amount {
+		feeCoins[i] = types.SignDocCoin{Denom: coin.Denom, Amount: coin.Amount}
+	}
+
+	signDoc := newInputSignDoc(input, accountSequence, nonce,
+		types.SignDocFee{Amount: feeCoins, GasLimit: input.Fee.GasLimit},
+		types.SignDocRatio{Numerator: input.FeeSlippage.Numerator, Denominator: input.FeeSlippage.Denominator},
+	)
+	if input.Tip != nil {
+		signDoc.Version = types.SignDocVersionTip
+		signDoc.Tip = &types.SignDocCoin{Denom: input.Tip.Denom, Amount: input.Tip.Amount}
+	}
+	if len(input.Signers) > 0 {
+		signDoc.Signers = append([]string(nil), input.Signers...)
+	}
+	if input.Version != "" {
+		signDoc.Version = input.Version
+	}
+
+	for i, msg := range input.Messages {
+		if msg.RawData != "" {
+			signDoc.AddMessage(msg.Type, json.RawMessage(msg.RawData))
+			continue
+		}
+		if msg.Data == nil || string(msg.Data) == "null" {
+			signDoc.AddMessage(msg.Type, nil)
+			continue
+		}
+		var compactData bytes.Buffer
+		if err := json.Compact(&compactData, msg.Data); err != nil {
+			return nil, fmt.Errorf("message %d: invalid data: %w", i, err)
+		}
+		signDoc.AddMessage(msg.Type, json.RawMessage(compactData.Bytes()))
+	}
+
+	return signDoc, nil
+}
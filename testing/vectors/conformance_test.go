@@ -0,0 +1,138 @@
+package vectors
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// TestRunConformance_GeneratedVectors verifies our own vectors pass the conformance runner.
+func TestRunConformance_GeneratedVectors(t *testing.T) {
+	vectorFile, err := GenerateTestVectors()
+	require.NoError(t, err)
+
+	report := RunConformance(vectorFile)
+	for _, result := range report.Results {
+		assert.True(t, result.Passed, "vector %s failed: %+v", result.Name, result.Checks)
+	}
+	assert.True(t, report.OK())
+	assert.Equal(t, len(vectorFile.Vectors), report.Total)
+}
+
+// TestRunConformance_VectorsFile verifies the checked-in vectors file passes.
+func TestRunConformance_VectorsFile(t *testing.T) {
+	vectorFile, err := LoadTestVectorFile(filepath.Join("..", "..", "testdata", "signing_vectors.json"))
+	require.NoError(t, err)
+
+	report := RunConformance(vectorFile)
+	assert.True(t, report.OK(), "checked-in vectors must pass conformance")
+}
+
+// TestRunConformance_DetectsFailures verifies that tampered vectors are reported, not panicked on.
+func TestRunConformance_DetectsFailures(t *testing.T) {
+	base, err := GenerateTestVectors()
+	require.NoError(t, err)
+
+	findVector := func(name string) TestVector {
+		for _, v := range base.Vectors {
+			if v.Name == name {
+				return v
+			}
+		}
+		t.Fatalf("vector %s not found", name)
+		return TestVector{}
+	}
+
+	failedChecks := func(result VectorResult) map[string]bool {
+		failed := make(map[string]bool)
+		for _, c := range result.Checks {
+			if c.Status == CheckFail {
+				failed[c.Check] = true
+			}
+		}
+		return failed
+	}
+
+	t.Run("reordered sign doc json", func(t *testing.T) {
+		v := findVector("simple_send")
+		v.Expected.SignDocJSON = `{"chain_id":"x"}`
+		report := RunConformance(&TestVectorFile{Vectors: []TestVector{v}})
+		require.False(t, report.OK())
+		assert.True(t, failedChecks(report.Results[0])[CheckSignDocJSON])
+	})
+
+	t.Run("wrong sign bytes", func(t *testing.T) {
+		v := findVector("simple_send")
+		v.Expected.SignBytesHex = "00"
+		report := RunConformance(&TestVectorFile{Vectors: []TestVector{v}})
+		require.False(t, report.OK())
+		assert.True(t, failedChecks(report.Results[0])[CheckSignBytes])
+	})
+
+	t.Run("corrupted signature", func(t *testing.T) {
+		v := findVector("simple_send")
+		sig := v.Expected.Signatures["ed25519"]
+		sig.SignatureHex = "00" + sig.SignatureHex[2:]
+		v.Expected.Signatures = map[string]TestVectorSignature{"ed25519": sig}
+		report := RunConformance(&TestVectorFile{Vectors: []TestVector{v}})
+		require.False(t, report.OK())
+		failed := failedChecks(report.Results[0])
+		assert.True(t, failed[CheckSignature])
+		assert.True(t, failed[CheckDeterministicSig])
+	})
+
+	t.Run("malformed input", func(t *testing.T) {
+		v := findVector("simple_send")
+		v.Input.Nonce = "not-a-number"
+		report := RunConformance(&TestVectorFile{Vectors: []TestVector{v}})
+		require.False(t, report.OK())
+		assert.True(t, failedChecks(report.Results[0])[CheckBuildSignDoc])
+	})
+
+	t.Run("unknown algorithm", func(t *testing.T) {
+		v := findVector("simple_send")
+		v.Expected.Signatures = map[string]TestVectorSignature{"rsa": {SignatureHex: "00"}}
+		report := RunConformance(&TestVectorFile{Vectors: []TestVector{v}})
+		assert.False(t, report.OK())
+	})
+}
+
+// TestRunConformance_RejectionVectors verifies rejection vectors that are accepted,
+// or rejected for the wrong reason, are reported as failures.
+func TestRunConformance_RejectionVectors(t *testing.T) {
+	base := validRejectionBaseInput()
+
+	accepted := TestVector{Name: "accepted", Category: CategoryRejection, Input: base,
+		Expected: TestVectorExpected{ErrorClass: ErrorClassEmptyField}}
+	report := RunConformance(&TestVectorFile{Vectors: []TestVector{accepted}})
+	assert.False(t, report.OK(), "valid input in a rejection vector must fail conformance")
+
+	wrongClass := base
+	wrongClass.ChainID = ""
+	vector := TestVector{Name: "wrong_class", Category: CategoryRejection, Input: wrongClass,
+		Expected: TestVectorExpected{ErrorClass: ErrorClassNonNFC}}
+	report = RunConformance(&TestVectorFile{Vectors: []TestVector{vector}})
+	assert.False(t, report.OK(), "rejection for a different reason must fail conformance")
+
+	vector.Expected.ErrorClass = ErrorClassEmptyField
+	report = RunConformance(&TestVectorFile{Vectors: []TestVector{vector}})
+	assert.True(t, report.OK())
+}
+
+// TestClassifyValidationError verifies errors are classified by the sentinel
+// they wrap, not by their message.
+func TestClassifyValidationError(t *testing.T) {
+	assert.Equal(t, "", ClassifyValidationError(nil))
+	assert.Equal(t, ErrorClassNoMessages, ClassifyValidationError(fmt.Errorf("wrapped: %w", types.ErrNoMessages)))
+	assert.Equal(t, ErrorClassUnknown, ClassifyValidationError(errors.New("denom too long")))
+
+	// A malformed tip amount is an invalid number, not just an invalid tip
+	err := fmt.Errorf("%w: %w", types.ErrInvalidTip, types.ErrNotDecimal)
+	assert.Equal(t, ErrorClassInvalidNumber, ClassifyValidationError(err))
+}
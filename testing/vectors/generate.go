@@ -1,5 +1,7 @@
 package vectors
 
+//go:generate go run ./cmd/vectors generate -o ../../testdata/signing_vectors.json
+
 import (
 	"bytes"
 	stdecdsa "crypto/ecdsa"
@@ -8,6 +10,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"math/big"
 	"time"
 
@@ -148,7 +151,17 @@ func mustSignBytes(signDoc *types.SignDoc) []byte {
 	return bytes
 }
 
+// GeneratedEpoch is the fixed Generated timestamp of generated vector files.
+//
+// RATIONALE: Embedding the wall-clock time made every regeneration produce a diff
+// even when no vector changed. The file is identified by ContentHash instead.
+var GeneratedEpoch = time.Unix(0, 0).UTC()
+
 // GenerateTestVectors creates the complete test vector file.
+//
+// INVARIANT: Output is fully deterministic: Generated is GeneratedEpoch, vectors
+// appear in a fixed order (serialization, algorithm, edge_case, rejection; each in
+// generation order), and ContentHash commits to the vectors.
 func GenerateTestVectors() (*TestVectorFile, error) {
 	vectors := []TestVector{}
 
@@ -164,12 +177,31 @@ func GenerateTestVectors() (*TestVectorFile, error) {
 	edgeCaseVectors := generateEdgeCaseVectors()
 	vectors = append(vectors, edgeCaseVectors...)
 
-	return &TestVectorFile{
-		Version:     "1.0",
-		Generated:   time.Now().UTC(),
+	// Add rejection (must-reject) vectors
+	vectors = append(vectors, generateRejectionVectors()...)
+
+	seen := make(map[string]bool, len(vectors))
+	for _, v := range vectors {
+		if seen[v.Name] {
+			return nil, fmt.Errorf("duplicate test vector name %q", v.Name)
+		}
+		seen[v.Name] = true
+	}
+
+	file := &TestVectorFile{
+		Version:     "1.3",
+		Generated:   GeneratedEpoch,
 		Description: "Cross-implementation test vectors for Punnet SDK signing system",
 		Vectors:     vectors,
-	}, nil
+	}
+
+	hash, err := file.ComputeContentHash()
+	if err != nil {
+		return nil, err
+	}
+	file.ContentHash = hash
+
+	return file, nil
 }
 
 // generateSerializationVectors creates test vectors for JSON serialization.
@@ -191,6 +223,12 @@ func generateSerializationVectors() []TestVector {
 	// 5. Transaction with multiple fee coins
 	vectors = append(vectors, generateMultipleFeeCoinsVector())
 
+	// 6. Version 2 transaction with a priority tip
+	vectors = append(vectors, generatePriorityTipVector())
+
+	// 7. Co-signer's SignDoc of a multi-signer transaction
+	vectors = append(vectors, generateCoSignerVector())
+
 	return vectors
 }
 
@@ -490,6 +528,106 @@ func generateMultipleFeeCoinsVector() TestVector {
 	}
 }
 
+func generatePriorityTipVector() TestVector {
+	input := TestVectorInput{
+		ChainID:         "punnet-mainnet-1",
+		Account:         "alice",
+		AccountSequence: "21",
+		Nonce:           "21",
+		Memo:            "",
+		Messages: []TestVectorMessage{
+			{
+				Type: "/punnet.bank.v1.MsgSend",
+				Data: json.RawMessage(`{"from":"alice","to":"bob","amount":"1000"}`),
+			},
+		},
+		Fee: TestVectorFee{
+			Amount:   []TestVectorCoin{{Denom: "stake", Amount: "5000"}},
+			GasLimit: "200000",
+		},
+		FeeSlippage: TestVectorRatio{
+			Numerator:   "1",
+			Denominator: "100",
+		},
+		Tip: &TestVectorCoin{Denom: "stake", Amount: "2500"},
+	}
+
+	signDoc := buildSignDocFromInput(input)
+	signDocJSON := mustJSON(signDoc)
+	signBytes := mustSignBytes(signDoc)
+	ed25519Sig := ed25519.Sign(WellKnownTestKeys.Ed25519.PrivateKey, signBytes)
+
+	return TestVector{
+		Name:        "priority_tip",
+		Description: "Version 2 SignDoc with a priority tip after fee_slippage",
+		Category:    "serialization",
+		Input:       input,
+		Expected: TestVectorExpected{
+			SignDocJSON:  string(signDocJSON),
+			SignBytesHex: hex.EncodeToString(signBytes),
+			Signatures: map[string]TestVectorSignature{
+				"ed25519": {
+					PrivateKeyHex: hex.EncodeToString(WellKnownTestKeys.Ed25519.PrivateKey),
+					PublicKeyHex:  hex.EncodeToString(WellKnownTestKeys.Ed25519.PublicKey),
+					SignatureHex:  hex.EncodeToString(ed25519Sig),
+				},
+			},
+		},
+	}
+}
+
+func generateCoSignerVector() TestVector {
+	input := TestVectorInput{
+		ChainID:         "punnet-mainnet-1",
+		Account:         "bob",
+		AccountSequence: "4",
+		Nonce:           "4",
+		Memo:            "swap",
+		Messages: []TestVectorMessage{
+			{
+				Type: "/punnet.bank.v1.MsgSend",
+				Data: json.RawMessage(`{"from":"alice","to":"bob","amount":"1000"}`),
+			},
+			{
+				Type: "/punnet.bank.v1.MsgSend",
+				Data: json.RawMessage(`{"from":"bob","to":"alice","amount":"750"}`),
+			},
+		},
+		Fee: TestVectorFee{
+			Amount:   []TestVectorCoin{{Denom: "stake", Amount: "5000"}},
+			GasLimit: "200000",
+		},
+		FeeSlippage: TestVectorRatio{
+			Numerator:   "1",
+			Denominator: "100",
+		},
+		Signers: []string{"alice", "bob"},
+	}
+
+	signDoc := buildSignDocFromInput(input)
+	signDocJSON := mustJSON(signDoc)
+	signBytes := mustSignBytes(signDoc)
+	ed25519Sig := ed25519.Sign(WellKnownTestKeys.Ed25519.PrivateKey, signBytes)
+
+	return TestVector{
+		Name:        "co_signer",
+		Description: "Co-signer's SignDoc of a multi-signer transaction, with the signer list after fee_slippage",
+		Category:    "serialization",
+		Input:       input,
+		Expected: TestVectorExpected{
+			SignDocJSON:  string(signDocJSON),
+			SignBytesHex: hex.EncodeToString(signBytes),
+			Signatures: map[string]TestVectorSignature{
+				"ed25519": {
+					PrivateKeyHex: hex.EncodeToString(WellKnownTestKeys.Ed25519.PrivateKey),
+					PublicKeyHex:  hex.EncodeToString(WellKnownTestKeys.Ed25519.PublicKey),
+					SignatureHex:  hex.EncodeToString(ed25519Sig),
+				},
+			},
+		},
+	}
+}
+
 func generateEd25519KeyDerivationVector() TestVector {
 	// This vector tests that key derivation from seed produces expected results
 	input := TestVectorInput{
@@ -1208,15 +1346,7 @@ func buildSignDocFromInputWithNullData(input TestVectorInput) *types.SignDoc {
 		Denominator: input.FeeSlippage.Denominator,
 	}
 
-	signDoc := types.NewSignDocWithFee(
-		input.ChainID,
-		accountSequence,
-		input.Account,
-		nonce,
-		input.Memo,
-		fee,
-		slippage,
-	)
+	signDoc := newInputSignDoc(input, accountSequence, nonce, fee, slippage)
 
 	// Add messages - preserve null data as nil
 	for _, msg := range input.Messages {
@@ -1597,15 +1727,14 @@ func buildSignDocFromInput(input TestVectorInput) *types.SignDoc {
 		Denominator: input.FeeSlippage.Denominator,
 	}
 
-	signDoc := types.NewSignDocWithFee(
-		input.ChainID,
-		accountSequence,
-		input.Account,
-		nonce,
-		input.Memo,
-		fee,
-		slippage,
-	)
+	signDoc := newInputSignDoc(input, accountSequence, nonce, fee, slippage)
+	if input.Tip != nil {
+		signDoc.Version = types.SignDocVersionTip
+		signDoc.Tip = &types.SignDocCoin{Denom: input.Tip.Denom, Amount: input.Tip.Amount}
+	}
+	if len(input.Signers) > 0 {
+		signDoc.Signers = append([]string(nil), input.Signers...)
+	}
 
 	// Add messages
 	// NOTE: msg.Data from JSON file may have whitespace; compact it to ensure
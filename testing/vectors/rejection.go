@@ -0,0 +1,282 @@
+package vectors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// CategoryRejection is the category of must-reject vectors.
+//
+// Rejection vectors describe malformed SignDocs that every conforming validator
+// MUST reject. They carry no expected serialization or signatures; instead
+// Expected.ErrorClass names the class of validation failure.
+const CategoryRejection = "rejection"
+
+// Error classes for rejection vectors.
+//
+// These are implementation-neutral names: other implementations map their own
+// validation errors onto these classes rather than matching Go error strings.
+const (
+	// ErrorClassUnsupportedVersion: SignDoc version is not supported.
+	ErrorClassUnsupportedVersion = "unsupported_version"
+
+	// ErrorClassEmptyField: a required field (chain_id, account, message type, ...) is empty.
+	ErrorClassEmptyField = "empty_field"
+
+	// ErrorClassNonNFC: a string field is not Unicode NFC-normalized.
+	ErrorClassNonNFC = "non_nfc_string"
+
+	// ErrorClassZeroDenominator: a ratio has a zero denominator.
+	ErrorClassZeroDenominator = "zero_denominator"
+
+	// ErrorClassNoMessages: the SignDoc contains no messages.
+	ErrorClassNoMessages = "no_messages"
+
+	// ErrorClassTooManyMessages: the SignDoc exceeds MaxMessagesPerSignDoc.
+	ErrorClassTooManyMessages = "too_many_messages"
+
+	// ErrorClassTooManyFeeCoins: the fee exceeds MaxFeeCoins.
+	ErrorClassTooManyFeeCoins = "too_many_fee_coins"
+
+	// ErrorClassNonCompactData: message data contains whitespace outside strings.
+	ErrorClassNonCompactData = "non_compact_data"
+
+	// ErrorClassInvalidNumber: a numeric string field is not a non-negative decimal integer.
+	ErrorClassInvalidNumber = "invalid_number"
+
+	// ErrorClassDataTooLarge: message data exceeds MaxMessageDataSize.
+	ErrorClassDataTooLarge = "data_too_large"
+
+	// ErrorClassFieldTooLong: a bounded string field (e.g. denom) is too long.
+	ErrorClassFieldTooLong = "field_too_long"
+
+	// ErrorClassInvalidTip: the tip is zero, or present in a version other
+	// than 2, or absent from a version 2 SignDoc.
+	ErrorClassInvalidTip = "invalid_tip"
+
+	// ErrorClassInvalidSigners: the signer list has fewer than 2 or
+	// duplicate accounts, or omits the SignDoc's account.
+	ErrorClassInvalidSigners = "invalid_signers"
+
+	// ErrorClassUnknown: the error does not match any known class.
+	ErrorClassUnknown = "unknown"
+)
+
+// errorClasses maps types.SignDoc validation errors to error classes.
+// Order matters: an error may wrap several of these (e.g. a tip with a
+// malformed amount), and the first match wins.
+var errorClasses = []struct {
+	err   error
+	class string
+}{
+	{types.ErrUnsupportedVersion, ErrorClassUnsupportedVersion},
+	{types.ErrZeroDenominator, ErrorClassZeroDenominator},
+	{types.ErrNotNFC, ErrorClassNonNFC},
+	{types.ErrNoMessages, ErrorClassNoMessages},
+	{types.ErrTooManyMessages, ErrorClassTooManyMessages},
+	{types.ErrTooManyFeeCoins, ErrorClassTooManyFeeCoins},
+	{types.ErrNonCompactData, ErrorClassNonCompactData},
+	{types.ErrDataTooLarge, ErrorClassDataTooLarge},
+	{types.ErrFieldTooLong, ErrorClassFieldTooLong},
+	{types.ErrNotDecimal, ErrorClassInvalidNumber},
+	{types.ErrEmptyField, ErrorClassEmptyField},
+	{types.ErrInvalidTip, ErrorClassInvalidTip},
+	{types.ErrInvalidSigners, ErrorClassInvalidSigners},
+}
+
+// ClassifyValidationError maps a SignDoc validation error to an error class.
+//
+// Returns "" for a nil error and ErrorClassUnknown for unrecognized errors.
+func ClassifyValidationError(err error) string {
+	if err == nil {
+		return ""
+	}
+	for _, c := range errorClasses {
+		if errors.Is(err, c.err) {
+			return c.class
+		}
+	}
+	return ErrorClassUnknown
+}
+
+// validateRejectionInput builds a SignDoc from a rejection vector input and runs
+// stateless validation, returning the error class.
+func validateRejectionInput(input TestVectorInput) (string, error) {
+	signDoc, err := SignDocFromInput(input)
+	if err != nil {
+		return ErrorClassInvalidNumber, err
+	}
+	err = signDoc.ValidateBasic()
+	return ClassifyValidationError(err), err
+}
+
+// generateRejectionVectors creates must-reject vectors covering each validation rule.
+func generateRejectionVectors() []TestVector {
+	tooManyMessages := make([]TestVectorMessage, types.MaxMessagesPerSignDoc+1)
+	for i := range tooManyMessages {
+		tooManyMessages[i] = TestVectorMessage{Type: "/test.msg", Data: json.RawMessage(`{}`)}
+	}
+
+	tooManyCoins := make([]TestVectorCoin, types.MaxFeeCoins+1)
+	for i := range tooManyCoins {
+		tooManyCoins[i] = TestVectorCoin{Denom: fmt.Sprintf("denom%02d", i), Amount: "1"}
+	}
+
+	cases := []struct {
+		name        string
+		description string
+		class       string
+		mutate      func(*TestVectorInput)
+	}{
+		{
+			"reject_unsupported_version", "SignDoc version \"3\" is not supported",
+			ErrorClassUnsupportedVersion,
+			func(in *TestVectorInput) { in.Version = "3" },
+		},
+		{
+			"reject_empty_chain_id", "Empty chain_id would allow cross-chain replay",
+			ErrorClassEmptyField,
+			func(in *TestVectorInput) { in.ChainID = "" },
+		},
+		{
+			"reject_empty_account", "Empty account name",
+			ErrorClassEmptyField,
+			func(in *TestVectorInput) { in.Account = "" },
+		},
+		{
+			"reject_empty_message_type", "Message with empty type",
+			ErrorClassEmptyField,
+			func(in *TestVectorInput) { in.Messages[0].Type = "" },
+		},
+		{
+			"reject_non_nfc_memo", "Memo in decomposed (NFD) form: \"cafe\" + U+0301",
+			ErrorClassNonNFC,
+			func(in *TestVectorInput) { in.Memo = "cafe\u0301" },
+		},
+		{
+			"reject_non_nfc_denom", "Fee denom in decomposed (NFD) form",
+			ErrorClassNonNFC,
+			func(in *TestVectorInput) { in.Fee.Amount = []TestVectorCoin{{Denom: "e\u0301", Amount: "1"}} },
+		},
+		{
+			"reject_zero_denominator", "Fee slippage with zero denominator",
+			ErrorClassZeroDenominator,
+			func(in *TestVectorInput) { in.FeeSlippage.Denominator = "0" },
+		},
+		{
+			"reject_no_messages", "SignDoc without messages",
+			ErrorClassNoMessages,
+			func(in *TestVectorInput) { in.Messages = []TestVectorMessage{} },
+		},
+		{
+			"reject_too_many_messages", fmt.Sprintf("%d messages (limit %d)", types.MaxMessagesPerSignDoc+1, types.MaxMessagesPerSignDoc),
+			ErrorClassTooManyMessages,
+			func(in *TestVectorInput) { in.Messages = tooManyMessages },
+		},
+		{
+			"reject_too_many_fee_coins", fmt.Sprintf("%d fee coins (limit %d)", types.MaxFeeCoins+1, types.MaxFeeCoins),
+			ErrorClassTooManyFeeCoins,
+			func(in *TestVectorInput) { in.Fee.Amount = tooManyCoins },
+		},
+		{
+			"reject_whitespace_in_data", "Message data with whitespace outside strings",
+			ErrorClassNonCompactData,
+			func(in *TestVectorInput) {
+				in.Messages[0] = TestVectorMessage{
+					Type:    in.Messages[0].Type,
+					RawData: `{"from": "alice", "to": "bob"}`,
+				}
+			},
+		},
+		{
+			"reject_decimal_gas_limit", "Gas limit is not an integer",
+			ErrorClassInvalidNumber,
+			func(in *TestVectorInput) { in.Fee.GasLimit = "1.5" },
+		},
+		{
+			"reject_negative_fee_amount", "Fee amount is negative",
+			ErrorClassInvalidNumber,
+			func(in *TestVectorInput) { in.Fee.Amount = []TestVectorCoin{{Denom: "stake", Amount: "-1"}} },
+		},
+		{
+			"reject_tip_in_v1", "Version 1 SignDoc with a tip",
+			ErrorClassInvalidTip,
+			func(in *TestVectorInput) {
+				in.Version = types.SignDocVersion
+				in.Tip = &TestVectorCoin{Denom: "stake", Amount: "1"}
+			},
+		},
+		{
+			"reject_v2_without_tip", "Version 2 SignDoc without a tip",
+			ErrorClassInvalidTip,
+			func(in *TestVectorInput) { in.Version = types.SignDocVersionTip },
+		},
+		{
+			"reject_zero_tip", "Zero tip, which must be omitted instead",
+			ErrorClassInvalidTip,
+			func(in *TestVectorInput) { in.Tip = &TestVectorCoin{Denom: "stake", Amount: "0"} },
+		},
+		{
+			"reject_single_signer", "Signer list of one account, which must be omitted instead",
+			ErrorClassInvalidSigners,
+			func(in *TestVectorInput) { in.Signers = []string{"alice"} },
+		},
+		{
+			"reject_account_not_in_signers", "Signer list without the signing account",
+			ErrorClassInvalidSigners,
+			func(in *TestVectorInput) { in.Signers = []string{"bob", "carol"} },
+		},
+	}
+
+	vectors := make([]TestVector, 0, len(cases))
+	for _, c := range cases {
+		input := validRejectionBaseInput()
+		c.mutate(&input)
+
+		// Guard against generator drift: every rejection vector must actually be
+		// rejected by this SDK with the advertised class.
+		if class, err := validateRejectionInput(input); err == nil || class != c.class {
+			panic(fmt.Sprintf("rejection vector %s: expected class %s, got %q (err: %v)", c.name, c.class, class, err))
+		}
+
+		vectors = append(vectors, TestVector{
+			Name:        c.name,
+			Description: c.description,
+			Category:    CategoryRejection,
+			Input:       input,
+			Expected: TestVectorExpected{
+				Signatures: map[string]TestVectorSignature{},
+				ErrorClass: c.class,
+			},
+		})
+	}
+	return vectors
+}
+
+// validRejectionBaseInput returns a valid input that each rejection case breaks in exactly one way.
+func validRejectionBaseInput() TestVectorInput {
+	return TestVectorInput{
+		ChainID:         "punnet-mainnet-1",
+		Account:         "alice",
+		AccountSequence: "1",
+		Nonce:           "1",
+		Memo:            "",
+		Messages: []TestVectorMessage{
+			{
+				Type: "/punnet.bank.v1.MsgSend",
+				Data: json.RawMessage(`{"from":"alice","to":"bob","amount":"1"}`),
+			},
+		},
+		Fee: TestVectorFee{
+			Amount:   []TestVectorCoin{{Denom: "stake", Amount: "100"}},
+			GasLimit: "100000",
+		},
+		FeeSlippage: TestVectorRatio{
+			Numerator:   "1",
+			Denominator: "100",
+		},
+	}
+}
@@ -8,8 +8,13 @@
 package vectors
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
 	"time"
 )
 
@@ -19,8 +24,13 @@ type TestVectorFile struct {
 	Version string `json:"version"`
 
 	// Generated timestamp in RFC3339 format.
+	// Fixed to GeneratedEpoch by GenerateTestVectors so output is reproducible.
 	Generated time.Time `json:"generated"`
 
+	// ContentHash is the hex SHA-256 of the JSON-encoded Vectors array.
+	// It changes if and only if the vectors change.
+	ContentHash string `json:"content_hash,omitempty"`
+
 	// Description of this test vector file.
 	Description string `json:"description"`
 
@@ -36,7 +46,7 @@ type TestVector struct {
 	// Description explains what this test vector tests.
 	Description string `json:"description"`
 
-	// Category groups related test vectors (serialization, algorithm, edge_case).
+	// Category groups related test vectors (serialization, algorithm, edge_case, rejection).
 	Category string `json:"category"`
 
 	// Input contains the SignDoc input fields.
@@ -48,6 +58,10 @@ type TestVector struct {
 
 // TestVectorInput contains the input data for creating a SignDoc.
 type TestVectorInput struct {
+	// Version overrides the SignDoc version. Empty means the current version,
+	// or version 2 if Tip is set. Only rejection vectors set this.
+	Version string `json:"version,omitempty"`
+
 	// ChainID for replay protection.
 	ChainID string `json:"chain_id"`
 
@@ -71,6 +85,13 @@ type TestVectorInput struct {
 
 	// FeeSlippage is the fee slippage tolerance.
 	FeeSlippage TestVectorRatio `json:"fee_slippage"`
+
+	// Tip is the optional priority tip (version 2 SignDocs only).
+	Tip *TestVectorCoin `json:"tip,omitempty"`
+
+	// Signers lists the signers of a multi-signer transaction, the
+	// transaction's account first. Empty for a single signer.
+	Signers []string `json:"signers,omitempty"`
 }
 
 // TestVectorMessage represents a message in a test vector.
@@ -80,6 +101,11 @@ type TestVectorMessage struct {
 
 	// Data is the message data as a JSON object.
 	Data json.RawMessage `json:"data"`
+
+	// RawData, if set, is the exact message data bytes as a JSON string and
+	// takes precedence over Data. Unlike Data it survives re-formatting of the
+	// vector file, so rejection vectors use it to carry non-compact JSON.
+	RawData string `json:"raw_data,omitempty"`
 }
 
 // TestVectorFee represents fee information in a test vector.
@@ -119,6 +145,11 @@ type TestVectorExpected struct {
 
 	// Signatures contains expected signatures for different algorithms.
 	Signatures map[string]TestVectorSignature `json:"signatures"`
+
+	// ErrorClass is set only for rejection vectors: the input MUST fail
+	// validation, and the failure MUST belong to this class (see ErrorClass* constants).
+	// SignDocJSON, SignBytesHex and Signatures are empty for rejection vectors.
+	ErrorClass string `json:"error_class,omitempty"`
 }
 
 // TestVectorSignature contains key material and expected signature for an algorithm.
@@ -134,6 +165,69 @@ type TestVectorSignature struct {
 	SignatureHex string `json:"signature_hex"`
 }
 
+// ComputeContentHash returns the hex SHA-256 of the JSON encoding of f.Vectors.
+func (f *TestVectorFile) ComputeContentHash() (string, error) {
+	data, err := json.Marshal(f.Vectors)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode vectors: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// MarshalTestVectorFile encodes f in the canonical on-disk form: two-space
+// indentation with a trailing newline.
+func MarshalTestVectorFile(f *TestVectorFile) ([]byte, error) {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode test vector file: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// WriteTestVectorFile writes f to path only if the encoded content differs from
+// what is already there, so regenerating unchanged vectors leaves the file (and
+// its modification time) untouched. Writes go through a temporary file and
+// rename, so readers never observe a partially written file.
+//
+// Returns true if the file was written.
+func WriteTestVectorFile(path string, f *TestVectorFile) (bool, error) {
+	data, err := MarshalTestVectorFile(f)
+	if err != nil {
+		return false, err
+	}
+
+	existing, err := os.ReadFile(path)
+	if err == nil && bytes.Equal(existing, data) {
+		return false, nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".vectors-*.json")
+	if err != nil {
+		return false, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // no-op after successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return false, fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return false, fmt.Errorf("failed to close temporary file: %w", err)
+	}
+	if err := os.Chmod(tmpName, 0644); err != nil {
+		return false, fmt.Errorf("failed to set file mode: %w", err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return false, fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return true, nil
+}
+
 // HexBytes is a helper type for hex-encoded bytes in JSON.
 type HexBytes []byte
 
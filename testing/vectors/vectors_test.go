@@ -37,6 +37,14 @@ func TestGenerateAndVerifyVectors(t *testing.T) {
 func verifyVector(t *testing.T, vector TestVector) {
 	t.Helper()
 
+	// Rejection vectors must fail validation with the advertised error class
+	if vector.Category == CategoryRejection {
+		class, err := validateRejectionInput(vector.Input)
+		require.Error(t, err, "rejection vector must fail validation")
+		assert.Equal(t, vector.Expected.ErrorClass, class, "error class (err: %v)", err)
+		return
+	}
+
 	// 1. Build SignDoc from input
 	// Use the null-data-aware builder for vectors that test null message data
 	var signDoc *types.SignDoc
@@ -389,6 +397,9 @@ func TestSignDocRoundtrip(t *testing.T) {
 	require.NoError(t, err)
 
 	for _, vector := range vectors.Vectors {
+		if vector.Category == CategoryRejection {
+			continue
+		}
 		t.Run(vector.Name, func(t *testing.T) {
 			// Build SignDoc from input - use null-aware builder for null data tests
 			var original *types.SignDoc
@@ -432,13 +443,9 @@ func TestWriteVectorsFile(t *testing.T) {
 	vectors, err := GenerateTestVectors()
 	require.NoError(t, err)
 
-	// Marshal with indentation for readability
-	jsonBytes, err := json.MarshalIndent(vectors, "", "  ")
-	require.NoError(t, err)
-
-	// Write to testdata directory
+	// Write to testdata directory (only if content changed)
 	filename := filepath.Join("..", "..", "testdata", "signing_vectors.json")
-	err = os.WriteFile(filename, jsonBytes, 0644)
+	_, err = WriteTestVectorFile(filename, vectors)
 	require.NoError(t, err)
 
 	t.Logf("Wrote test vectors to %s", filename)
@@ -486,6 +493,7 @@ func TestVectorCategories(t *testing.T) {
 	assert.Greater(t, categories["serialization"], 0, "should have serialization vectors")
 	assert.Greater(t, categories["algorithm"], 0, "should have algorithm vectors")
 	assert.Greater(t, categories["edge_case"], 0, "should have edge case vectors")
+	assert.Greater(t, categories[CategoryRejection], 0, "should have rejection vectors")
 
 	t.Logf("Vector categories: %v", categories)
 }
@@ -578,3 +586,75 @@ func hasNullMessageData(input TestVectorInput) bool {
 	}
 	return false
 }
+
+// TestGenerateTestVectors_Reproducible verifies generation is byte-for-byte reproducible.
+func TestGenerateTestVectors_Reproducible(t *testing.T) {
+	v1, err := GenerateTestVectors()
+	require.NoError(t, err)
+	v2, err := GenerateTestVectors()
+	require.NoError(t, err)
+
+	b1, err := MarshalTestVectorFile(v1)
+	require.NoError(t, err)
+	b2, err := MarshalTestVectorFile(v2)
+	require.NoError(t, err)
+
+	assert.Equal(t, string(b1), string(b2), "generated file must be byte-identical")
+	assert.True(t, v1.Generated.Equal(GeneratedEpoch))
+	assert.NotEmpty(t, v1.ContentHash)
+}
+
+// TestContentHash verifies the content hash tracks the vectors.
+func TestContentHash(t *testing.T) {
+	file, err := GenerateTestVectors()
+	require.NoError(t, err)
+
+	hash, err := file.ComputeContentHash()
+	require.NoError(t, err)
+	assert.Equal(t, file.ContentHash, hash)
+
+	file.Vectors[0].Description += " (modified)"
+	modified, err := file.ComputeContentHash()
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, modified)
+}
+
+// TestVectorsFileUpToDate verifies the checked-in vectors file matches the generator.
+func TestVectorsFileUpToDate(t *testing.T) {
+	filename := filepath.Join("..", "..", "testdata", "signing_vectors.json")
+	existing, err := os.ReadFile(filename)
+	require.NoError(t, err)
+
+	file, err := GenerateTestVectors()
+	require.NoError(t, err)
+	expected, err := MarshalTestVectorFile(file)
+	require.NoError(t, err)
+
+	assert.True(t, string(existing) == string(expected),
+		"testdata/signing_vectors.json is stale; run go generate ./testing/vectors/...")
+}
+
+// TestWriteTestVectorFile verifies the writer only rewrites changed content.
+func TestWriteTestVectorFile(t *testing.T) {
+	file, err := GenerateTestVectors()
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "vectors.json")
+
+	changed, err := WriteTestVectorFile(path, file)
+	require.NoError(t, err)
+	assert.True(t, changed, "first write must create the file")
+
+	changed, err = WriteTestVectorFile(path, file)
+	require.NoError(t, err)
+	assert.False(t, changed, "unchanged content must not be rewritten")
+
+	file.Description = "changed"
+	changed, err = WriteTestVectorFile(path, file)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	loaded, err := LoadTestVectorFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "changed", loaded.Description)
+}
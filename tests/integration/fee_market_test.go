@@ -0,0 +1,72 @@
+package integration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/blockberries/punnet-sdk/modules/bank"
+	"github.com/blockberries/punnet-sdk/modules/feemarket"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/testing/apptesting"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// TestFeeMarket_FailedSendIsCharged delivers an overdrawing MsgSend under
+// the fee market: the send fails, but the fee is charged, the gas limit is
+// counted and the nonce advances, so the transaction cannot be replayed
+func TestFeeMarket_FailedSendIsCharged(t *testing.T) {
+	const gasLimit = 100_000
+	params := feemarket.DefaultParams()
+
+	var feeMod *feemarket.FeeMarketModule
+	app := apptesting.NewTestApp(t,
+		apptesting.WithAnteHandler(func(app *apptesting.TestApp) runtime.AnteHandler {
+			var err error
+			feeMod, err = feemarket.NewFeeMarketModule(app.GrantStore(t, feemarket.ModuleName), params, nil)
+			if err != nil {
+				t.Fatalf("NewFeeMarketModule() error = %v", err)
+			}
+			return feeMod.AnteHandler()
+		}),
+		apptesting.WithFee(types.Fee{
+			Amount:   types.NewCoins(types.NewCoin(apptesting.DefaultDenom, params.InitialBaseFee*gasLimit)),
+			GasLimit: gasLimit,
+		}),
+	)
+
+	tx := app.SignTx(t, apptesting.Alice,
+		&bank.MsgSend{From: apptesting.Alice, To: apptesting.Bob, Amount: types.NewCoin(apptesting.DefaultDenom, 2_000_000)})
+	if _, err := app.DeliverTx(tx); !errors.Is(err, types.ErrInsufficientFunds) {
+		t.Fatalf("DeliverTx() error = %v, want %v", err, types.ErrInsufficientFunds)
+	}
+
+	check := func() {
+		t.Helper()
+		if got, want := app.Balance(t, apptesting.Alice, apptesting.DefaultDenom), uint64(1_000_000-gasLimit); got != want {
+			t.Errorf("alice balance = %d, want %d", got, want)
+		}
+		if got := app.Balance(t, params.BurnAccount, apptesting.DefaultDenom); got != gasLimit {
+			t.Errorf("burned = %d, want %d", got, gasLimit)
+		}
+		if got := app.Balance(t, apptesting.Bob, apptesting.DefaultDenom); got != 1_000_000 {
+			t.Errorf("bob balance = %d, want 1000000", got)
+		}
+		blockGas, err := feeMod.BlockGas()
+		if err != nil {
+			t.Fatalf("BlockGas() error = %v", err)
+		}
+		if blockGas != gasLimit {
+			t.Errorf("block gas = %d, want %d", blockGas, gasLimit)
+		}
+		if got := app.Account(t, apptesting.Alice).Nonce; got != 1 {
+			t.Errorf("alice nonce = %d, want 1", got)
+		}
+	}
+	check()
+
+	// The replay is rejected before its fee is charged
+	if _, err := app.DeliverTx(tx); !errors.Is(err, types.ErrSequenceMismatch) {
+		t.Fatalf("replayed DeliverTx() error = %v, want %v", err, types.ErrSequenceMismatch)
+	}
+	check()
+}
@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/blockberries/punnet-sdk/modules/bank"
+	"github.com/blockberries/punnet-sdk/testing/apptesting"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// payoutBatch returns a MsgMultiSend paying amount from sender to each recipient
+func payoutBatch(sender types.AccountName, amount uint64, recipients ...types.AccountName) *bank.MsgMultiSend {
+	msg := &bank.MsgMultiSend{
+		Inputs: []bank.Input{{
+			Address: sender,
+			Coins:   types.NewCoins(types.NewCoin(apptesting.DefaultDenom, amount*uint64(len(recipients)))),
+		}},
+	}
+	for _, recipient := range recipients {
+		msg.Outputs = append(msg.Outputs, bank.Output{
+			Address: recipient,
+			Coins:   types.NewCoins(types.NewCoin(apptesting.DefaultDenom, amount)),
+		})
+	}
+	return msg
+}
+
+// TestMultiSend_PayoutBatch pays several recipients with one message
+func TestMultiSend_PayoutBatch(t *testing.T) {
+	app := apptesting.NewTestApp(t)
+
+	app.ExecMsg(t, payoutBatch(apptesting.Alice, 100, apptesting.Bob, apptesting.Carol, "dave", "erin"))
+
+	if got := app.Balance(t, apptesting.Alice, apptesting.DefaultDenom); got != 1_000_000-400 {
+		t.Errorf("alice balance = %d, want %d", got, 1_000_000-400)
+	}
+	if got := app.Balance(t, apptesting.Bob, apptesting.DefaultDenom); got != 1_000_100 {
+		t.Errorf("bob balance = %d, want 1000100", got)
+	}
+	for _, name := range []types.AccountName{"dave", "erin"} {
+		if got := app.Balance(t, name, apptesting.DefaultDenom); got != 100 {
+			t.Errorf("%s balance = %d, want 100", name, got)
+		}
+	}
+}
+
+// TestMultiSend_Atomic checks that a failing batch, or a failing message in
+// the same transaction, pays no output
+func TestMultiSend_Atomic(t *testing.T) {
+	app := apptesting.NewTestApp(t)
+
+	// The batch exceeds alice's balance
+	_, err := app.DeliverMsg(t, payoutBatch(apptesting.Alice, 400_000, apptesting.Bob, apptesting.Carol, "dave"))
+	if !errors.Is(err, types.ErrInsufficientFunds) {
+		t.Fatalf("DeliverMsg() error = %v, want ErrInsufficientFunds", err)
+	}
+
+	// The batch is valid but a later message of the transaction fails
+	tx := app.SignTx(t, apptesting.Alice,
+		payoutBatch(apptesting.Alice, 100, apptesting.Bob, apptesting.Carol),
+		&bank.MsgSend{From: apptesting.Alice, To: apptesting.Bob, Amount: types.NewCoin(apptesting.DefaultDenom, 2_000_000)},
+	)
+	if _, err := app.DeliverTx(tx); err == nil {
+		t.Fatal("DeliverTx() succeeded, want error")
+	}
+
+	for _, name := range []types.AccountName{apptesting.Alice, apptesting.Bob, apptesting.Carol} {
+		if got := app.Balance(t, name, apptesting.DefaultDenom); got != 1_000_000 {
+			t.Errorf("%s balance = %d, want 1000000", name, got)
+		}
+	}
+	if got := app.Balance(t, "dave", apptesting.DefaultDenom); got != 0 {
+		t.Errorf("dave balance = %d, want 0", got)
+	}
+
+	// Inputs from other accounts are never debited
+	steal := payoutBatch(apptesting.Bob, 100, apptesting.Alice)
+	steal.Inputs = append([]bank.Input{{Address: apptesting.Alice, Coins: types.NewCoins(types.NewCoin(apptesting.DefaultDenom, 1))}}, steal.Inputs...)
+	steal.Outputs[0].Coins = types.NewCoins(types.NewCoin(apptesting.DefaultDenom, 101))
+	_, err = app.DeliverTx(app.SignTx(t, apptesting.Alice, steal))
+	if !errors.Is(err, types.ErrUnauthorized) {
+		t.Fatalf("DeliverTx() error = %v, want ErrUnauthorized", err)
+	}
+}
+
+// TestMultiSend_AfterSpendingMessage checks that a batch whose senders were
+// drained by an earlier message of the transaction pays no output: its
+// balance check passes, but its transfers fail when applied
+func TestMultiSend_AfterSpendingMessage(t *testing.T) {
+	app := apptesting.NewTestApp(t)
+
+	tx := app.SignTx(t, apptesting.Alice,
+		&bank.MsgSend{From: apptesting.Alice, To: apptesting.Bob, Amount: types.NewCoin(apptesting.DefaultDenom, 700_000)},
+		payoutBatch(apptesting.Alice, 200_000, apptesting.Carol, "dave"),
+	)
+	if _, err := app.DeliverTx(tx); err == nil {
+		t.Fatal("DeliverTx() succeeded, want error")
+	}
+
+	for _, name := range []types.AccountName{apptesting.Alice, apptesting.Bob, apptesting.Carol} {
+		if got := app.Balance(t, name, apptesting.DefaultDenom); got != 1_000_000 {
+			t.Errorf("%s balance = %d, want 1000000", name, got)
+		}
+	}
+	if got := app.Balance(t, "dave", apptesting.DefaultDenom); got != 0 {
+		t.Errorf("dave balance = %d, want 0", got)
+	}
+	// The failed transaction still uses up its nonce
+	if got := app.Account(t, apptesting.Alice).Nonce; got != 1 {
+		t.Errorf("alice nonce = %d, want 1", got)
+	}
+}
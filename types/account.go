@@ -3,6 +3,7 @@ package types
 import (
 	"fmt"
 	"regexp"
+	"strings"
 	"time"
 )
 
@@ -40,6 +41,24 @@ type Account struct {
 
 	// UpdatedAt is when the account was last modified
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// Authenticator optionally names the registered authenticator that
+	// authorizes the account's transactions instead of the weighted Authority
+	// check, e.g. to add a second factor or spending limits. Empty means the
+	// Authority check.
+	Authenticator string `json:"authenticator,omitempty"`
+}
+
+// MaxAuthenticatorLength is the maximum length of an authenticator name
+const MaxAuthenticatorLength = 128
+
+// authenticatorNamePattern matches valid authenticator names
+var authenticatorNamePattern = regexp.MustCompile("^[a-z0-9._/-]+$")
+
+// IsValidAuthenticatorName reports whether name is a valid authenticator
+// name: 1 to MaxAuthenticatorLength characters of [a-z0-9._/-]
+func IsValidAuthenticatorName(name string) bool {
+	return len(name) > 0 && len(name) <= MaxAuthenticatorLength && authenticatorNamePattern.MatchString(name)
 }
 
 // NewAccount creates a new account with default authority
@@ -65,6 +84,9 @@ func (a *Account) ValidateBasic() error {
 	if err := a.Authority.ValidateBasic(); err != nil {
 		return err
 	}
+	if a.Authenticator != "" && !IsValidAuthenticatorName(a.Authenticator) {
+		return fmt.Errorf("%w: invalid authenticator name %q", ErrInvalidAccount, a.Authenticator)
+	}
 	return nil
 }
 
@@ -73,14 +95,61 @@ type Authority struct {
 	// Threshold is the minimum weight required for authorization
 	Threshold uint64 `json:"threshold"`
 
-	// KeyWeights maps public keys to their authorization weight
+	// KeyWeights maps key IDs to their authorization weight.
+	// A key ID identifies a public key together with its algorithm; see KeyID.
+	// Iterate it with SortedKeyWeights in consensus code.
 	KeyWeights map[string]uint64 `json:"key_weights"`
 
 	// AccountWeights maps account names to their delegation weight
 	// This enables hierarchical permissions where accounts can delegate authority
+	// Iterate it with SortedAccountWeights in consensus code.
 	AccountWeights map[AccountName]uint64 `json:"account_weights"`
 }
 
+// keyIDSeparator separates the algorithm prefix from the public key in a key ID.
+const keyIDSeparator = ":"
+
+// KeyID returns the KeyWeights key for a public key of the given algorithm.
+//
+// Ed25519 keys (and the empty algorithm) use the raw public key bytes, which
+// keeps existing authorities valid. Other algorithms are prefixed with
+// "<algorithm>:" so the same key bytes under different algorithms - e.g. a
+// P-256 key used raw and as a passkey - are distinct authority entries.
+//
+// INVARIANT: KeyID is injective over (algorithm, public key) pairs with valid
+// key sizes: a raw Ed25519 ID is 32 bytes, while every prefixed ID is longer.
+func KeyID(algo Algorithm, pubKey []byte) string {
+	if algo == "" || algo == AlgorithmEd25519 {
+		return string(pubKey)
+	}
+	return string(algo) + keyIDSeparator + string(pubKey)
+}
+
+// ParseKeyID splits a KeyWeights key into its algorithm and public key.
+//
+// Keys without a recognized "<algorithm>:" prefix are treated as raw Ed25519
+// public keys. The prefix is only recognized when the remainder has the
+// algorithm's public key size, so raw Ed25519 keys are never misparsed.
+func ParseKeyID(id string) (Algorithm, []byte) {
+	if algo, pubKey, ok := splitKeyIDPrefix(id); ok && len(pubKey) == algo.PublicKeySize() {
+		return algo, []byte(pubKey)
+	}
+	return AlgorithmEd25519, []byte(id)
+}
+
+// splitKeyIDPrefix splits a non-Ed25519 algorithm prefix from a key ID.
+func splitKeyIDPrefix(id string) (Algorithm, string, bool) {
+	prefix, rest, found := strings.Cut(id, keyIDSeparator)
+	if !found {
+		return "", "", false
+	}
+	algo := Algorithm(prefix)
+	if !algo.IsValid() || algo == AlgorithmEd25519 {
+		return "", "", false
+	}
+	return algo, rest, true
+}
+
 // NewAuthority creates a new authority with a single key
 func NewAuthority(threshold uint64, pubKey []byte, weight uint64) Authority {
 	return Authority{
@@ -90,6 +159,22 @@ func NewAuthority(threshold uint64, pubKey []byte, weight uint64) Authority {
 	}
 }
 
+// NewAuthorityWithAlgorithm creates a new authority with a single key of the
+// given algorithm.
+func NewAuthorityWithAlgorithm(threshold uint64, algo Algorithm, pubKey []byte, weight uint64) Authority {
+	return Authority{
+		Threshold:      threshold,
+		KeyWeights:     map[string]uint64{KeyID(algo, pubKey): weight},
+		AccountWeights: make(map[AccountName]uint64),
+	}
+}
+
+// NewWebAuthnAuthority creates a new authority with a single passkey.
+// pubKey is the credential's compressed P-256 public key (33 bytes).
+func NewWebAuthnAuthority(threshold uint64, pubKey []byte, weight uint64) Authority {
+	return NewAuthorityWithAlgorithm(threshold, AlgorithmWebAuthn, pubKey, weight)
+}
+
 // ValidateBasic performs basic validation
 func (a Authority) ValidateBasic() error {
 	if a.Threshold == 0 {
@@ -118,25 +203,53 @@ func (a Authority) ValidateBasic() error {
 		return fmt.Errorf("%w: threshold %d exceeds total weight %d", ErrInvalidAuthority, a.Threshold, totalWeight)
 	}
 
+	// Validate algorithm-prefixed key IDs. Unprefixed keys are legacy Ed25519
+	// entries and are not size-checked here.
+	for _, kw := range a.SortedKeyWeights() {
+		algo, pubKey, ok := splitKeyIDPrefix(kw.KeyID)
+		if !ok {
+			continue
+		}
+		if !IsValidAlgorithm(algo) {
+			return fmt.Errorf("%w: %v: %s", ErrInvalidAuthority, ErrUnsupportedAlgorithm, algo)
+		}
+		if len(pubKey) != algo.PublicKeySize() {
+			return fmt.Errorf("%w: %s key must be %d bytes, got %d",
+				ErrInvalidAuthority, algo, algo.PublicKeySize(), len(pubKey))
+		}
+	}
+
 	// Validate account names in delegations
-	for acct := range a.AccountWeights {
-		if !acct.IsValid() {
-			return fmt.Errorf("%w: invalid delegated account %s", ErrInvalidAuthority, acct)
+	for _, aw := range a.SortedAccountWeights() {
+		if !aw.Account.IsValid() {
+			return fmt.Errorf("%w: invalid delegated account %s", ErrInvalidAuthority, aw.Account)
 		}
 	}
 
 	return nil
 }
 
-// HasKey checks if a public key is in the authority
+// HasKey checks if an Ed25519 public key is in the authority.
+// Use HasAlgorithmKey for keys of other algorithms.
 func (a Authority) HasKey(pubKey []byte) bool {
-	_, ok := a.KeyWeights[string(pubKey)]
-	return ok
+	return a.HasAlgorithmKey(AlgorithmEd25519, pubKey)
 }
 
-// GetKeyWeight returns the weight of a public key
+// GetKeyWeight returns the weight of an Ed25519 public key.
+// Use GetAlgorithmKeyWeight for keys of other algorithms.
 func (a Authority) GetKeyWeight(pubKey []byte) uint64 {
-	return a.KeyWeights[string(pubKey)]
+	return a.GetAlgorithmKeyWeight(AlgorithmEd25519, pubKey)
+}
+
+// HasAlgorithmKey checks if a public key of the given algorithm is in the authority.
+func (a Authority) HasAlgorithmKey(algo Algorithm, pubKey []byte) bool {
+	_, ok := a.KeyWeights[KeyID(algo, pubKey)]
+	return ok
+}
+
+// GetAlgorithmKeyWeight returns the weight of a public key of the given algorithm.
+func (a Authority) GetAlgorithmKeyWeight(algo Algorithm, pubKey []byte) uint64 {
+	return a.KeyWeights[KeyID(algo, pubKey)]
 }
 
 // HasAccount checks if an account is delegated
@@ -0,0 +1,187 @@
+package types
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AccountEncodingVersion is the version tag of the canonical account encoding.
+// It changes whenever the encoding of an unchanged account would change.
+const AccountEncodingVersion = "1"
+
+// canonicalAccount is the canonical JSON form of Account.
+//
+// Fields are declared in ascending key order, so encoding/json emits every
+// object with sorted keys; CanonicalizeMessageData leaves the output as is.
+type canonicalAccount struct {
+	Authenticator string             `json:"authenticator,omitempty"`
+	Authority     canonicalAuthority `json:"authority"`
+	CreatedAt     string             `json:"created_at"`
+	Name          AccountName        `json:"name"`
+	Nonce         StringUint64       `json:"nonce"`
+	UpdatedAt     string             `json:"updated_at"`
+	Version       string             `json:"version"`
+}
+
+type canonicalAuthority struct {
+	AccountWeights []canonicalAccountWeight `json:"account_weights"`
+	KeyWeights     []canonicalKeyWeight     `json:"key_weights"`
+	Threshold      StringUint64             `json:"threshold"`
+}
+
+type canonicalAccountWeight struct {
+	Account AccountName  `json:"account"`
+	Weight  StringUint64 `json:"weight"`
+}
+
+type canonicalKeyWeight struct {
+	KeyID  []byte       `json:"key_id"`
+	Weight StringUint64 `json:"weight"`
+}
+
+// CanonicalJSON returns the canonical encoding of the account, the input to
+// StateHash:
+//
+//	{"authority":{"account_weights":[{"account":"bob","weight":"1"}],"key_weights":[{"key_id":"<base64>","weight":"1"}],"threshold":"1"},"created_at":"2024-01-01T00:00:00Z","name":"alice","nonce":"0","updated_at":"2024-01-01T00:00:00Z","version":"1"}
+//
+// The encoding is compact, with object keys in ascending byte order. Weight
+// arrays are sorted by key ID and account name and always present, so nil
+// and empty maps encode identically. Integers are decimal strings (JSON
+// numbers lose precision above 2^53 in many languages), key IDs are standard
+// base64 with padding, and times are RFC 3339 in UTC with trailing zero
+// fractional digits removed. The authenticator is omitted when empty, so
+// accounts without one keep their encoding.
+//
+// PRECONDITION: The account name, all delegated account names and the
+// authenticator name are valid, so every string is plain ASCII that needs no
+// escaping.
+// POSTCONDITION: Equal accounts - including times in different locations -
+// have byte-identical encodings.
+//
+// SECURITY: Nodes in different languages must agree on account state hashes;
+// testdata/account_vectors.json pins the encoding.
+func (a *Account) CanonicalJSON() ([]byte, error) {
+	if !a.Name.IsValid() {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAccount, a.Name)
+	}
+	if a.Authenticator != "" && !IsValidAuthenticatorName(a.Authenticator) {
+		return nil, fmt.Errorf("%w: authenticator %q", ErrInvalidAccount, a.Authenticator)
+	}
+	createdAt, err := canonicalTime(a.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("created_at: %w", err)
+	}
+	updatedAt, err := canonicalTime(a.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("updated_at: %w", err)
+	}
+
+	out := canonicalAccount{
+		Authenticator: a.Authenticator,
+		Authority: canonicalAuthority{
+			AccountWeights: make([]canonicalAccountWeight, 0, len(a.Authority.AccountWeights)),
+			KeyWeights:     make([]canonicalKeyWeight, 0, len(a.Authority.KeyWeights)),
+			Threshold:      StringUint64(a.Authority.Threshold),
+		},
+		CreatedAt: createdAt,
+		Name:      a.Name,
+		Nonce:     StringUint64(a.Nonce),
+		UpdatedAt: updatedAt,
+		Version:   AccountEncodingVersion,
+	}
+	for _, aw := range a.Authority.SortedAccountWeights() {
+		if !aw.Account.IsValid() {
+			return nil, fmt.Errorf("%w: delegated account %q", ErrInvalidAccount, aw.Account)
+		}
+		out.Authority.AccountWeights = append(out.Authority.AccountWeights,
+			canonicalAccountWeight{Account: aw.Account, Weight: StringUint64(aw.Weight)})
+	}
+	for _, kw := range a.Authority.SortedKeyWeights() {
+		out.Authority.KeyWeights = append(out.Authority.KeyWeights,
+			canonicalKeyWeight{KeyID: []byte(kw.KeyID), Weight: StringUint64(kw.Weight)})
+	}
+	return json.Marshal(out)
+}
+
+// StateHash returns the SHA-256 hash of the account's canonical encoding.
+func (a *Account) StateHash() ([32]byte, error) {
+	data, err := a.CanonicalJSON()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha256.Sum256(data), nil
+}
+
+// ParseCanonicalAccount decodes an account encoded by CanonicalJSON.
+//
+// SECURITY: Only the canonical encoding itself is accepted - unknown fields,
+// other versions, whitespace, reordered keys or entries and non-UTC times are
+// rejected with ErrNonCanonicalAccount - so every accepted input re-encodes to
+// the same bytes and hash.
+//
+// POSTCONDITION: On success, CanonicalJSON of the result equals data. Weight
+// maps are non-nil and times are in UTC.
+func ParseCanonicalAccount(data []byte) (*Account, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var in canonicalAccount
+	if err := dec.Decode(&in); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNonCanonicalAccount, err)
+	}
+	if in.Version != AccountEncodingVersion {
+		return nil, fmt.Errorf("%w: unsupported version %q (supported: %q)",
+			ErrNonCanonicalAccount, in.Version, AccountEncodingVersion)
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, in.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: created_at: %v", ErrNonCanonicalAccount, err)
+	}
+	updatedAt, err := time.Parse(time.RFC3339Nano, in.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: updated_at: %v", ErrNonCanonicalAccount, err)
+	}
+
+	acc := &Account{
+		Name: in.Name,
+		Authority: Authority{
+			Threshold:      in.Authority.Threshold.Uint64(),
+			KeyWeights:     make(map[string]uint64, len(in.Authority.KeyWeights)),
+			AccountWeights: make(map[AccountName]uint64, len(in.Authority.AccountWeights)),
+		},
+		Nonce:         in.Nonce.Uint64(),
+		CreatedAt:     createdAt.UTC(),
+		UpdatedAt:     updatedAt.UTC(),
+		Authenticator: in.Authenticator,
+	}
+	for _, kw := range in.Authority.KeyWeights {
+		acc.Authority.KeyWeights[string(kw.KeyID)] = kw.Weight.Uint64()
+	}
+	for _, aw := range in.Authority.AccountWeights {
+		acc.Authority.AccountWeights[aw.Account] = aw.Weight.Uint64()
+	}
+
+	// Re-encoding catches everything the decoder tolerates: key order,
+	// whitespace, unsorted or duplicate entries, time formatting.
+	canonical, err := acc.CanonicalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNonCanonicalAccount, err)
+	}
+	if !bytes.Equal(canonical, data) {
+		return nil, fmt.Errorf("%w: input is not in canonical form", ErrNonCanonicalAccount)
+	}
+	return acc, nil
+}
+
+// canonicalTime formats t as RFC 3339 in UTC.
+// Years outside [0, 9999] have no RFC 3339 representation and are rejected.
+func canonicalTime(t time.Time) (string, error) {
+	t = t.UTC()
+	if y := t.Year(); y < 0 || y > 9999 {
+		return "", fmt.Errorf("year %d outside [0, 9999]", y)
+	}
+	return t.Format(time.RFC3339Nano), nil
+}
@@ -0,0 +1,347 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// updateAccountVectorsEnv makes TestAccountVectors rewrite the vector file
+// instead of checking it.
+const updateAccountVectorsEnv = "UPDATE_ACCOUNT_VECTORS"
+
+var accountVectorsFile = filepath.Join("..", "testdata", "account_vectors.json")
+
+// accountVectorFile is the layout of testdata/account_vectors.json.
+type accountVectorFile struct {
+	Version         string                 `json:"version"`
+	EncodingVersion string                 `json:"encoding_version"`
+	Description     string                 `json:"description"`
+	Vectors         []accountVector        `json:"vectors"`
+	Rejections      []accountRejectionCase `json:"rejections"`
+}
+
+type accountVector struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Input       accountVectorInput `json:"input"`
+	Expected    struct {
+		CanonicalJSON string `json:"canonical_json"`
+		SHA256        string `json:"sha256"`
+	} `json:"expected"`
+}
+
+// accountVectorInput describes an account in language-neutral terms: key
+// IDs in hex, integers as strings, times in any RFC 3339 offset. Entries are
+// listed unsorted to exercise the canonical ordering.
+type accountVectorInput struct {
+	Name       string `json:"name"`
+	Threshold  string `json:"threshold"`
+	KeyWeights []struct {
+		KeyIDHex string `json:"key_id_hex"`
+		Weight   string `json:"weight"`
+	} `json:"key_weights"`
+	AccountWeights []struct {
+		Account string `json:"account"`
+		Weight  string `json:"weight"`
+	} `json:"account_weights"`
+	Nonce     string `json:"nonce"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type accountRejectionCase struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Encoded     string `json:"encoded"`
+}
+
+func (in accountVectorInput) account(t *testing.T) *Account {
+	t.Helper()
+	parseUint := func(s string) uint64 {
+		var v StringUint64
+		require.NoError(t, json.Unmarshal([]byte(`"`+s+`"`), &v))
+		return v.Uint64()
+	}
+	parseTime := func(s string) time.Time {
+		ts, err := time.Parse(time.RFC3339Nano, s)
+		require.NoError(t, err)
+		return ts
+	}
+
+	acc := &Account{
+		Name: AccountName(in.Name),
+		Authority: Authority{
+			Threshold:      parseUint(in.Threshold),
+			KeyWeights:     map[string]uint64{},
+			AccountWeights: map[AccountName]uint64{},
+		},
+		Nonce:     parseUint(in.Nonce),
+		CreatedAt: parseTime(in.CreatedAt),
+		UpdatedAt: parseTime(in.UpdatedAt),
+	}
+	for _, kw := range in.KeyWeights {
+		id, err := hex.DecodeString(kw.KeyIDHex)
+		require.NoError(t, err)
+		acc.Authority.KeyWeights[string(id)] = parseUint(kw.Weight)
+	}
+	for _, aw := range in.AccountWeights {
+		acc.Authority.AccountWeights[AccountName(aw.Account)] = parseUint(aw.Weight)
+	}
+	return acc
+}
+
+// accountVectorInputs are the source of truth for the vector file.
+const accountVectorInputs = `[
+  {"name": "single_key", "description": "Default single Ed25519 key account",
+   "input": {"name": "alice", "threshold": "1",
+     "key_weights": [{"key_id_hex": "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a", "weight": "1"}],
+     "account_weights": [], "nonce": "0",
+     "created_at": "2024-01-01T00:00:00Z", "updated_at": "2024-01-01T00:00:00Z"}},
+  {"name": "multisig_sorted", "description": "Key and account weights given unsorted; the encoding sorts them bytewise",
+   "input": {"name": "treasury.main", "threshold": "3",
+     "key_weights": [
+       {"key_id_hex": "ff00", "weight": "1"},
+       {"key_id_hex": "736563703235366b313a02a1633cafcc01ebfb6d78e39f687a1f0995c62fc95f51ead10a02ee0be551b5dc", "weight": "2"},
+       {"key_id_hex": "0102", "weight": "1"}],
+     "account_weights": [{"account": "carol", "weight": "1"}, {"account": "bob", "weight": "2"}],
+     "nonce": "42",
+     "created_at": "2024-03-10T12:30:45.123456789Z", "updated_at": "2024-03-11T08:00:00.5Z"}},
+  {"name": "delegation_only", "description": "Authority with account weights only; key_weights is an empty array",
+   "input": {"name": "dao", "threshold": "2",
+     "key_weights": [],
+     "account_weights": [{"account": "member.b", "weight": "1"}, {"account": "member.a", "weight": "1"}],
+     "nonce": "7",
+     "created_at": "2023-06-01T00:00:00Z", "updated_at": "2023-06-01T00:00:00Z"}},
+  {"name": "max_integers", "description": "Integers above 2^53 are decimal strings and keep full precision",
+   "input": {"name": "big", "threshold": "18446744073709551615",
+     "key_weights": [{"key_id_hex": "01", "weight": "18446744073709551615"}],
+     "account_weights": [], "nonce": "18446744073709551615",
+     "created_at": "2024-01-01T00:00:00Z", "updated_at": "2024-01-01T00:00:00Z"}},
+  {"name": "time_offset_normalized", "description": "Times with a UTC offset are normalized to UTC; trailing fractional zeros are dropped",
+   "input": {"name": "tz", "threshold": "1",
+     "key_weights": [{"key_id_hex": "aa", "weight": "1"}],
+     "account_weights": [], "nonce": "1",
+     "created_at": "2024-01-01T09:00:00.100+09:00", "updated_at": "2023-12-31T19:00:00-05:00"}}
+]`
+
+// accountRejections are encodings ParseCanonicalAccount must reject.
+var accountRejections = []accountRejectionCase{
+	{
+		Name:        "unknown_version",
+		Description: "Unsupported version tag",
+		Encoded:     `{"authority":{"account_weights":[],"key_weights":[{"key_id":"qg==","weight":"1"}],"threshold":"1"},"created_at":"2024-01-01T00:00:00Z","name":"tz","nonce":"1","updated_at":"2024-01-01T00:00:00Z","version":"2"}`,
+	},
+	{
+		Name:        "numeric_nonce",
+		Description: "Integers must be strings",
+		Encoded:     `{"authority":{"account_weights":[],"key_weights":[{"key_id":"qg==","weight":"1"}],"threshold":"1"},"created_at":"2024-01-01T00:00:00Z","name":"tz","nonce":1,"updated_at":"2024-01-01T00:00:00Z","version":"1"}`,
+	},
+	{
+		Name:        "leading_zero",
+		Description: "Integers have no leading zeros",
+		Encoded:     `{"authority":{"account_weights":[],"key_weights":[{"key_id":"qg==","weight":"01"}],"threshold":"1"},"created_at":"2024-01-01T00:00:00Z","name":"tz","nonce":"1","updated_at":"2024-01-01T00:00:00Z","version":"1"}`,
+	},
+	{
+		Name:        "unsorted_keys",
+		Description: "Object keys must be in ascending order",
+		Encoded:     `{"version":"1","authority":{"account_weights":[],"key_weights":[{"key_id":"qg==","weight":"1"}],"threshold":"1"},"created_at":"2024-01-01T00:00:00Z","name":"tz","nonce":"1","updated_at":"2024-01-01T00:00:00Z"}`,
+	},
+	{
+		Name:        "unsorted_entries",
+		Description: "Weight entries must be sorted",
+		Encoded:     `{"authority":{"account_weights":[{"account":"bob","weight":"1"},{"account":"alice","weight":"1"}],"key_weights":[],"threshold":"1"},"created_at":"2024-01-01T00:00:00Z","name":"tz","nonce":"1","updated_at":"2024-01-01T00:00:00Z","version":"1"}`,
+	},
+	{
+		Name:        "duplicate_entries",
+		Description: "Weight entries must be unique",
+		Encoded:     `{"authority":{"account_weights":[],"key_weights":[{"key_id":"qg==","weight":"1"},{"key_id":"qg==","weight":"1"}],"threshold":"1"},"created_at":"2024-01-01T00:00:00Z","name":"tz","nonce":"1","updated_at":"2024-01-01T00:00:00Z","version":"1"}`,
+	},
+	{
+		Name:        "non_utc_time",
+		Description: "Times must be in UTC",
+		Encoded:     `{"authority":{"account_weights":[],"key_weights":[{"key_id":"qg==","weight":"1"}],"threshold":"1"},"created_at":"2024-01-01T09:00:00+09:00","name":"tz","nonce":"1","updated_at":"2024-01-01T00:00:00Z","version":"1"}`,
+	},
+	{
+		Name:        "trailing_fraction_zeros",
+		Description: "Fractional seconds have no trailing zeros",
+		Encoded:     `{"authority":{"account_weights":[],"key_weights":[{"key_id":"qg==","weight":"1"}],"threshold":"1"},"created_at":"2024-01-01T00:00:00.100Z","name":"tz","nonce":"1","updated_at":"2024-01-01T00:00:00Z","version":"1"}`,
+	},
+	{
+		Name:        "whitespace",
+		Description: "The encoding is compact",
+		Encoded:     `{"authority": {"account_weights":[],"key_weights":[{"key_id":"qg==","weight":"1"}],"threshold":"1"},"created_at":"2024-01-01T00:00:00Z","name":"tz","nonce":"1","updated_at":"2024-01-01T00:00:00Z","version":"1"}`,
+	},
+	{
+		Name:        "unknown_field",
+		Description: "Unknown fields are rejected",
+		Encoded:     `{"authority":{"account_weights":[],"key_weights":[{"key_id":"qg==","weight":"1"}],"threshold":"1"},"created_at":"2024-01-01T00:00:00Z","extra":"x","name":"tz","nonce":"1","updated_at":"2024-01-01T00:00:00Z","version":"1"}`,
+	},
+}
+
+// generateAccountVectors computes the vector file from accountVectorInputs.
+func generateAccountVectors(t *testing.T) []byte {
+	t.Helper()
+
+	file := accountVectorFile{
+		Version:         "1.0",
+		EncodingVersion: AccountEncodingVersion,
+		Description:     "Canonical Account encoding (Account.CanonicalJSON) and state hashes (Account.StateHash)",
+		Rejections:      accountRejections,
+	}
+	require.NoError(t, json.Unmarshal([]byte(accountVectorInputs), &file.Vectors))
+	for i := range file.Vectors {
+		v := &file.Vectors[i]
+		acc := v.Input.account(t)
+		data, err := acc.CanonicalJSON()
+		require.NoError(t, err, v.Name)
+		hash, err := acc.StateHash()
+		require.NoError(t, err, v.Name)
+		v.Expected.CanonicalJSON = string(data)
+		v.Expected.SHA256 = hex.EncodeToString(hash[:])
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	require.NoError(t, err)
+	return append(data, '\n')
+}
+
+func TestAccountVectors(t *testing.T) {
+	generated := generateAccountVectors(t)
+	if os.Getenv(updateAccountVectorsEnv) == "1" {
+		require.NoError(t, os.WriteFile(accountVectorsFile, generated, 0o644))
+		t.Logf("wrote %s", accountVectorsFile)
+		return
+	}
+
+	golden, err := os.ReadFile(accountVectorsFile)
+	require.NoError(t, err, "run with %s=1 to create it", updateAccountVectorsEnv)
+	require.Equal(t, string(golden), string(generated),
+		"account encoding changed; bump AccountEncodingVersion or run with %s=1 if intended", updateAccountVectorsEnv)
+
+	var file accountVectorFile
+	require.NoError(t, json.Unmarshal(golden, &file))
+	assert.Equal(t, AccountEncodingVersion, file.EncodingVersion)
+
+	for _, v := range file.Vectors {
+		t.Run(v.Name, func(t *testing.T) {
+			acc, err := ParseCanonicalAccount([]byte(v.Expected.CanonicalJSON))
+			require.NoError(t, err)
+
+			again, err := acc.CanonicalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, v.Expected.CanonicalJSON, string(again))
+
+			sum := sha256.Sum256(again)
+			assert.Equal(t, v.Expected.SHA256, hex.EncodeToString(sum[:]))
+
+			// The encoding is already canonical JSON in the SignDoc sense
+			canonical, err := CanonicalizeMessageData(again)
+			require.NoError(t, err)
+			assert.Equal(t, string(again), string(canonical))
+		})
+	}
+
+	for _, r := range file.Rejections {
+		t.Run("reject_"+r.Name, func(t *testing.T) {
+			_, err := ParseCanonicalAccount([]byte(r.Encoded))
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrNonCanonicalAccount), err.Error())
+		})
+	}
+}
+
+func TestAccount_CanonicalJSON(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	acc := &Account{
+		Name:      "alice",
+		Authority: Authority{Threshold: 1, KeyWeights: map[string]uint64{"\x01": 1}},
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+
+	t.Run("nil and empty maps encode identically", func(t *testing.T) {
+		withEmpty := *acc
+		withEmpty.Authority.AccountWeights = map[AccountName]uint64{}
+
+		a, err := acc.CanonicalJSON()
+		require.NoError(t, err)
+		b, err := withEmpty.CanonicalJSON()
+		require.NoError(t, err)
+		assert.Equal(t, a, b)
+		assert.True(t, strings.Contains(string(a), `"account_weights":[]`))
+	})
+
+	t.Run("time location does not affect encoding", func(t *testing.T) {
+		local := *acc
+		local.CreatedAt = created.In(time.FixedZone("UTC+3", 3*3600))
+
+		a, err := acc.StateHash()
+		require.NoError(t, err)
+		b, err := local.StateHash()
+		require.NoError(t, err)
+		assert.Equal(t, a, b)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		data, err := acc.CanonicalJSON()
+		require.NoError(t, err)
+		parsed, err := ParseCanonicalAccount(data)
+		require.NoError(t, err)
+		assert.Equal(t, acc.Name, parsed.Name)
+		assert.Equal(t, acc.Authority.KeyWeights, parsed.Authority.KeyWeights)
+		assert.NotNil(t, parsed.Authority.AccountWeights)
+		assert.True(t, acc.CreatedAt.Equal(parsed.CreatedAt))
+	})
+
+	t.Run("invalid names rejected", func(t *testing.T) {
+		bad := *acc
+		bad.Name = "Alice"
+		_, err := bad.CanonicalJSON()
+		assert.ErrorIs(t, err, ErrInvalidAccount)
+
+		bad = *acc
+		bad.Authority.AccountWeights = map[AccountName]uint64{"<bob>": 1}
+		_, err = bad.CanonicalJSON()
+		assert.ErrorIs(t, err, ErrInvalidAccount)
+	})
+
+	t.Run("authenticator", func(t *testing.T) {
+		withAuth := *acc
+		withAuth.Authenticator = "twofactor/totp"
+
+		plain, err := acc.CanonicalJSON()
+		require.NoError(t, err)
+		assert.NotContains(t, string(plain), "authenticator")
+
+		data, err := withAuth.CanonicalJSON()
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(data), `{"authenticator":"twofactor/totp","authority":`))
+
+		parsed, err := ParseCanonicalAccount(data)
+		require.NoError(t, err)
+		assert.Equal(t, withAuth.Authenticator, parsed.Authenticator)
+
+		bad := withAuth
+		bad.Authenticator = "Two Factor"
+		_, err = bad.CanonicalJSON()
+		assert.ErrorIs(t, err, ErrInvalidAccount)
+		assert.ErrorIs(t, bad.ValidateBasic(), ErrInvalidAccount)
+	})
+
+	t.Run("unrepresentable time rejected", func(t *testing.T) {
+		bad := *acc
+		bad.UpdatedAt = time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)
+		_, err := bad.CanonicalJSON()
+		assert.Error(t, err)
+	})
+}
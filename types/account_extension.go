@@ -0,0 +1,134 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// extensionNamePattern matches valid account extension names
+var extensionNamePattern = regexp.MustCompile("^[a-z0-9_.]+$")
+
+// maxExtensionNameLength bounds extension names, like account names
+const maxExtensionNameLength = 64
+
+// AccountExtension is typed metadata a module attaches to an account, such as
+// a vesting schedule or a permission set. Extensions are stored next to the
+// account under AccountExtensionKey instead of inside Account, so modules do
+// not need parallel account tables and Account's encoding never changes.
+//
+// Implementations are JSON-encoded and should be pointers so they can be
+// decoded into.
+type AccountExtension interface {
+	// ExtensionName identifies the extension; it must satisfy
+	// ValidateExtensionName and be stable across schema versions
+	ExtensionName() string
+
+	// SchemaVersion is the version of the extension's encoding
+	SchemaVersion() uint32
+}
+
+// AccountExtensionMigrator is implemented by extensions that can decode data
+// written by an older schema version.
+type AccountExtensionMigrator interface {
+	AccountExtension
+
+	// MigrateFrom decodes data written with schema version into the receiver.
+	// PRECONDITION: version < SchemaVersion()
+	MigrateFrom(version uint32, data []byte) error
+}
+
+// accountExtensionRecord is the stored envelope of an extension
+type accountExtensionRecord struct {
+	Name    string          `json:"name"`
+	Version uint32          `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// ValidateExtensionName checks that name is a valid extension name:
+// 1-64 characters of [a-z0-9_.]
+func ValidateExtensionName(name string) error {
+	if len(name) == 0 || len(name) > maxExtensionNameLength || !extensionNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: invalid account extension name %q", ErrInvalidAccount, name)
+	}
+	return nil
+}
+
+// AccountExtensionKey returns the store key of extension name of account:
+// "<account>/<name>".
+//
+// INVARIANT: Account names cannot contain '/', so the keys of one account's
+// extensions never share the AccountExtensionPrefix of another account.
+func AccountExtensionKey(account AccountName, name string) []byte {
+	return []byte(string(account) + "/" + name)
+}
+
+// AccountExtensionPrefix returns the key prefix of all extensions of account
+func AccountExtensionPrefix(account AccountName) []byte {
+	return []byte(string(account) + "/")
+}
+
+// EncodeAccountExtension encodes ext together with its name and schema version
+func EncodeAccountExtension(ext AccountExtension) ([]byte, error) {
+	if ext == nil {
+		return nil, fmt.Errorf("account extension cannot be nil")
+	}
+	if err := ValidateExtensionName(ext.ExtensionName()); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(ext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode account extension %s: %w", ext.ExtensionName(), err)
+	}
+
+	return json.Marshal(accountExtensionRecord{
+		Name:    ext.ExtensionName(),
+		Version: ext.SchemaVersion(),
+		Data:    data,
+	})
+}
+
+// DecodeAccountExtension decodes data written by EncodeAccountExtension into ext.
+//
+// Data written with ext's schema version is decoded directly. Data written
+// with an older version is passed to MigrateFrom if ext implements
+// AccountExtensionMigrator. Any other version, including a newer one written
+// by an upgraded binary, returns ErrExtensionSchemaMismatch.
+func DecodeAccountExtension(data []byte, ext AccountExtension) error {
+	if ext == nil {
+		return fmt.Errorf("account extension cannot be nil")
+	}
+
+	var record accountExtensionRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return fmt.Errorf("failed to decode account extension record: %w", err)
+	}
+	if record.Name != ext.ExtensionName() {
+		return fmt.Errorf("%w: stored extension %q, decoding as %q",
+			ErrExtensionSchemaMismatch, record.Name, ext.ExtensionName())
+	}
+
+	current := ext.SchemaVersion()
+	switch {
+	case record.Version == current:
+		if err := json.Unmarshal(record.Data, ext); err != nil {
+			return fmt.Errorf("failed to decode account extension %s: %w", record.Name, err)
+		}
+		return nil
+	case record.Version < current:
+		migrator, ok := ext.(AccountExtensionMigrator)
+		if !ok {
+			return fmt.Errorf("%w: %s version %d cannot be read as version %d",
+				ErrExtensionSchemaMismatch, record.Name, record.Version, current)
+		}
+		if err := migrator.MigrateFrom(record.Version, record.Data); err != nil {
+			return fmt.Errorf("failed to migrate account extension %s from version %d: %w",
+				record.Name, record.Version, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %s version %d is newer than supported version %d",
+			ErrExtensionSchemaMismatch, record.Name, record.Version, current)
+	}
+}
@@ -0,0 +1,109 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// vestingV1 is the first schema of a test extension
+type vestingV1 struct {
+	Amount uint64 `json:"amount"`
+}
+
+func (v *vestingV1) ExtensionName() string { return "vesting" }
+func (v *vestingV1) SchemaVersion() uint32 { return 1 }
+
+// vestingV2 adds an end height and migrates from vestingV1
+type vestingV2 struct {
+	Amount    uint64 `json:"amount"`
+	EndHeight uint64 `json:"end_height"`
+}
+
+func (v *vestingV2) ExtensionName() string { return "vesting" }
+func (v *vestingV2) SchemaVersion() uint32 { return 2 }
+
+func (v *vestingV2) MigrateFrom(version uint32, data []byte) error {
+	if version != 1 {
+		return fmt.Errorf("unknown version %d", version)
+	}
+	var old vestingV1
+	if err := json.Unmarshal(data, &old); err != nil {
+		return err
+	}
+	*v = vestingV2{Amount: old.Amount}
+	return nil
+}
+
+// permissions has no migration path
+type permissions struct {
+	Version uint32   `json:"-"`
+	Allowed []string `json:"allowed"`
+}
+
+func (p *permissions) ExtensionName() string { return "permissions" }
+func (p *permissions) SchemaVersion() uint32 { return p.Version }
+
+func TestAccountExtension_RoundTrip(t *testing.T) {
+	data, err := EncodeAccountExtension(&vestingV2{Amount: 100, EndHeight: 50})
+	require.NoError(t, err)
+
+	var got vestingV2
+	require.NoError(t, DecodeAccountExtension(data, &got))
+	require.Equal(t, vestingV2{Amount: 100, EndHeight: 50}, got)
+}
+
+func TestAccountExtension_SchemaVersions(t *testing.T) {
+	v1, err := EncodeAccountExtension(&vestingV1{Amount: 7})
+	require.NoError(t, err)
+
+	// Older data is migrated
+	var migrated vestingV2
+	require.NoError(t, DecodeAccountExtension(v1, &migrated))
+	require.Equal(t, vestingV2{Amount: 7}, migrated)
+
+	// Newer data cannot be read by older code
+	v2, err := EncodeAccountExtension(&vestingV2{Amount: 7, EndHeight: 9})
+	require.NoError(t, err)
+	require.ErrorIs(t, DecodeAccountExtension(v2, &vestingV1{}), ErrExtensionSchemaMismatch)
+
+	// Older data without a migrator is rejected
+	old, err := EncodeAccountExtension(&permissions{Version: 1, Allowed: []string{"send"}})
+	require.NoError(t, err)
+	require.ErrorIs(t, DecodeAccountExtension(old, &permissions{Version: 2}), ErrExtensionSchemaMismatch)
+
+	// Data of another extension is rejected
+	require.ErrorIs(t, DecodeAccountExtension(old, &vestingV1{}), ErrExtensionSchemaMismatch)
+}
+
+func TestValidateExtensionName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"vesting", true},
+		{"bank.send_permissions", true},
+		{"", false},
+		{"Vesting", false},
+		{"a/b", false},
+		{string(make([]byte, 65)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExtensionName(tt.name)
+			if tt.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrInvalidAccount)
+			}
+		})
+	}
+}
+
+func TestAccountExtensionKey(t *testing.T) {
+	require.Equal(t, []byte("alice/vesting"), AccountExtensionKey("alice", "vesting"))
+	require.Equal(t, []byte("alice/"), AccountExtensionPrefix("alice"))
+}
@@ -0,0 +1,108 @@
+package types
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// AppHashVersion is the version of the app hash commitment format
+const AppHashVersion uint8 = 1
+
+// StateStoreName names the state store, which holds every module's state
+// under its prefix, in CommitInfo
+const StateStoreName = "state"
+
+// appHashDomain separates app hashes from other SHA-256 commitments
+const appHashDomain = "punnet/app_hash"
+
+// StoreRoot is the Merkle root of a named store
+type StoreRoot struct {
+	Name string `json:"name"`
+	Hash []byte `json:"hash"`
+}
+
+// CommitInfo describes the state committed at a height: the Merkle roots
+// of the application's stores, which AppHash combines into the app hash
+// reported to consensus.
+//
+// INVARIANT: Roots are sorted by name, so the app hash does not depend on
+// the order stores were registered or committed in.
+type CommitInfo struct {
+	// Version is the commitment format (AppHashVersion)
+	Version uint8 `json:"version"`
+
+	// Height is the committed block height
+	Height uint64 `json:"height"`
+
+	Roots []StoreRoot `json:"roots"`
+}
+
+// NewCommitInfo returns the current-version CommitInfo of roots at height,
+// sorting a copy of roots by name
+func NewCommitInfo(height uint64, roots ...StoreRoot) CommitInfo {
+	sorted := make([]StoreRoot, len(roots))
+	for i, root := range roots {
+		sorted[i] = StoreRoot{Name: root.Name, Hash: bytes.Clone(root.Hash)}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	return CommitInfo{Version: AppHashVersion, Height: height, Roots: sorted}
+}
+
+// ValidateBasic performs stateless validation
+func (ci CommitInfo) ValidateBasic() error {
+	if ci.Version != AppHashVersion {
+		return fmt.Errorf("unsupported app hash version %d", ci.Version)
+	}
+	if len(ci.Roots) == 0 {
+		return fmt.Errorf("commit info has no store roots")
+	}
+	for i, root := range ci.Roots {
+		if root.Name == "" {
+			return fmt.Errorf("store root %d has no name", i)
+		}
+		if len(root.Hash) != sha256.Size {
+			return fmt.Errorf("store %s root must be %d bytes, got %d", root.Name, sha256.Size, len(root.Hash))
+		}
+		if i > 0 && root.Name <= ci.Roots[i-1].Name {
+			return fmt.Errorf("store roots not sorted or duplicated at %s", root.Name)
+		}
+	}
+	return nil
+}
+
+// AppHash returns the app hash committing to the store roots: the SHA-256
+// of the domain string, the version, and the length-prefixed name and root
+// of each store in order.
+//
+// The height is not hashed: consensus binds the app hash to its height.
+//
+// INVARIANT: The app hash changes if any root, name or the version does.
+func (ci CommitInfo) AppHash() ([]byte, error) {
+	if err := ci.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	h.Write([]byte(appHashDomain))
+	h.Write([]byte{ci.Version})
+	var n [binary.MaxVarintLen64]byte
+	h.Write(n[:binary.PutUvarint(n[:], uint64(len(ci.Roots)))])
+	for _, root := range ci.Roots {
+		h.Write(n[:binary.PutUvarint(n[:], uint64(len(root.Name)))])
+		h.Write([]byte(root.Name))
+		h.Write(n[:binary.PutUvarint(n[:], uint64(len(root.Hash)))])
+		h.Write(root.Hash)
+	}
+	return h.Sum(nil), nil
+}
+
+// StateAppHash returns the app hash of an application whose only store is
+// the state store, with root stateRoot. Light clients use it to bind proofs
+// against the state store to a header's app hash.
+func StateAppHash(stateRoot []byte) ([]byte, error) {
+	return NewCommitInfo(0, StoreRoot{Name: StateStoreName, Hash: stateRoot}).AppHash()
+}
@@ -0,0 +1,92 @@
+package types
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRoot(name string, b byte) StoreRoot {
+	return StoreRoot{Name: name, Hash: bytes.Repeat([]byte{b}, sha256.Size)}
+}
+
+func TestCommitInfo_AppHash(t *testing.T) {
+	info := NewCommitInfo(7, testRoot("bank", 1), testRoot("auth", 2))
+	require.Equal(t, "auth", info.Roots[0].Name, "roots are sorted by name")
+
+	hash, err := info.AppHash()
+	require.NoError(t, err)
+	require.Len(t, hash, sha256.Size)
+
+	t.Run("pinned", func(t *testing.T) {
+		// Changing the commitment format changes every app hash and must bump
+		// AppHashVersion
+		assert.Equal(t, "a974084c0d5a291bc12a61e7ea0570506704129e0811e5503491380ef1ce4fd6", hex.EncodeToString(hash))
+	})
+
+	t.Run("independent of root order and height", func(t *testing.T) {
+		other, err := NewCommitInfo(8, testRoot("auth", 2), testRoot("bank", 1)).AppHash()
+		require.NoError(t, err)
+		assert.Equal(t, hash, other)
+	})
+
+	t.Run("commits to every field", func(t *testing.T) {
+		variants := map[string]CommitInfo{
+			"root":     NewCommitInfo(7, testRoot("auth", 2), testRoot("bank", 3)),
+			"name":     NewCommitInfo(7, testRoot("auth", 2), testRoot("bonk", 1)),
+			"extra":    NewCommitInfo(7, testRoot("auth", 2), testRoot("bank", 1), testRoot("gov", 0)),
+			"missing":  NewCommitInfo(7, testRoot("bank", 1)),
+			"swapped":  NewCommitInfo(7, testRoot("auth", 1), testRoot("bank", 2)),
+			"boundary": NewCommitInfo(7, testRoot("aut", 2), testRoot("hbank", 1)),
+		}
+		for name, v := range variants {
+			other, err := v.AppHash()
+			require.NoError(t, err, name)
+			assert.NotEqual(t, hash, other, name)
+		}
+	})
+
+	t.Run("copies roots", func(t *testing.T) {
+		root := testRoot("state", 1)
+		info := NewCommitInfo(1, root)
+		root.Hash[0] = 9
+		assert.Equal(t, byte(1), info.Roots[0].Hash[0])
+	})
+}
+
+func TestCommitInfo_ValidateBasic(t *testing.T) {
+	tests := []struct {
+		name string
+		info CommitInfo
+	}{
+		{name: "unknown version", info: CommitInfo{Version: AppHashVersion + 1, Roots: []StoreRoot{testRoot("state", 1)}}},
+		{name: "no roots", info: NewCommitInfo(1)},
+		{name: "unnamed root", info: NewCommitInfo(1, testRoot("", 1))},
+		{name: "short root", info: NewCommitInfo(1, StoreRoot{Name: "state", Hash: []byte{1}})},
+		{name: "duplicate name", info: NewCommitInfo(1, testRoot("state", 1), testRoot("state", 2))},
+		{name: "unsorted", info: CommitInfo{Version: AppHashVersion, Roots: []StoreRoot{testRoot("b", 1), testRoot("a", 1)}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Error(t, tt.info.ValidateBasic())
+			_, err := tt.info.AppHash()
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestStateAppHash(t *testing.T) {
+	root := bytes.Repeat([]byte{5}, sha256.Size)
+	hash, err := StateAppHash(root)
+	require.NoError(t, err)
+
+	want, err := NewCommitInfo(3, StoreRoot{Name: StateStoreName, Hash: root}).AppHash()
+	require.NoError(t, err)
+	assert.Equal(t, want, hash)
+	assert.NotEqual(t, root, hash, "the app hash is not the raw state root")
+}
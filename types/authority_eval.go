@@ -0,0 +1,132 @@
+package types
+
+import "fmt"
+
+// AuthorityKeyContribution is a key whose valid signature contributed weight.
+type AuthorityKeyContribution struct {
+	// Algorithm is the signature algorithm the key is listed under.
+	Algorithm Algorithm `json:"algorithm"`
+
+	// PubKey is the public key bytes.
+	PubKey []byte `json:"pub_key"`
+
+	// Weight is the key's weight in the authority.
+	Weight uint64 `json:"weight"`
+}
+
+// AuthorityAccountContribution is a delegated account whose authorization met
+// its own threshold and so contributed weight.
+type AuthorityAccountContribution struct {
+	// Account is the delegated account name.
+	Account AccountName `json:"account"`
+
+	// Weight is the account's weight in the authority.
+	Weight uint64 `json:"weight"`
+}
+
+// AuthorityEvaluation reports how far an authorization is from satisfying
+// an authority, for display in signing flows (e.g. "2 of 3 signatures
+// collected, 1 more needed").
+//
+// INVARIANT: Weight equals the sum of the Keys and Accounts weights.
+// INVARIANT: Satisfied == (Weight >= Threshold).
+type AuthorityEvaluation struct {
+	// Weight is the accumulated weight of valid signatures and satisfied delegations.
+	Weight uint64 `json:"weight"`
+
+	// Threshold is the authority's threshold.
+	Threshold uint64 `json:"threshold"`
+
+	// Satisfied reports whether Weight meets Threshold.
+	Satisfied bool `json:"satisfied"`
+
+	// Keys lists the keys that contributed, in signature order.
+	Keys []AuthorityKeyContribution `json:"keys"`
+
+	// Accounts lists the delegated accounts that contributed, sorted by name.
+	Accounts []AuthorityAccountContribution `json:"accounts"`
+}
+
+// MissingWeight returns the weight still needed to reach the threshold
+// (0 once satisfied).
+func (e *AuthorityEvaluation) MissingWeight() uint64 {
+	if e.Weight >= e.Threshold {
+		return 0
+	}
+	return e.Threshold - e.Weight
+}
+
+// String returns a short progress summary, e.g. "weight 2 of 3, 1 more needed".
+func (e *AuthorityEvaluation) String() string {
+	if e.Satisfied {
+		return fmt.Sprintf("weight %d of %d, satisfied", e.Weight, e.Threshold)
+	}
+	return fmt.Sprintf("weight %d of %d, %d more needed", e.Weight, e.Threshold, e.MissingWeight())
+}
+
+// Evaluate computes the weight auth contributes toward a, verifying each
+// signature against message and each delegated account's authorization
+// against that account's authority from getter.
+//
+// Unlike Authorization.VerifyAuthorization, Evaluate does not fail on
+// invalid or foreign signatures or on an unmet threshold: those simply do
+// not contribute. It is meant for partially collected authorizations.
+//
+// A nil getter skips delegated accounts, for callers without access to
+// chain state; their weight is then not counted.
+//
+// Returns ErrDuplicateSignature, ErrAuthorizationCycle or ErrMaxRecursionDepth
+// for authorizations that VerifyAuthorization would also reject, and
+// ErrInvalidSession for session authorizations.
+//
+// Complexity: Same as VerifyAuthorization.
+func (a Authority) Evaluate(auth *Authorization, message []byte, getter AccountGetter) (*AuthorityEvaluation, error) {
+	eval := &AuthorityEvaluation{
+		Threshold: a.Threshold,
+		Keys:      []AuthorityKeyContribution{},
+		Accounts:  []AuthorityAccountContribution{},
+	}
+	if auth == nil {
+		return eval, nil
+	}
+	if auth.Session != nil {
+		return nil, fmt.Errorf("%w: session authorization requires transaction context", ErrInvalidSession)
+	}
+
+	evaluated := auth
+	if getter == nil {
+		evaluated = &Authorization{Signatures: auth.Signatures}
+	}
+
+	// The evaluated authority has no account of its own; the empty name is
+	// never a valid account, so it cannot collide in cycle detection.
+	cache := newVerificationCache(message, getter)
+	result, err := evaluated.calculateWeight("", a, cache, make(map[AccountName]bool), 0, eval)
+	if err != nil {
+		return nil, err
+	}
+
+	eval.Weight = result.weight
+	eval.Satisfied = eval.Weight >= eval.Threshold
+	return eval, nil
+}
+
+// CanAuthorize reports whether auth meets a's threshold.
+// See Evaluate for the meaning of message and getter.
+func (a Authority) CanAuthorize(auth *Authorization, message []byte, getter AccountGetter) (bool, error) {
+	eval, err := a.Evaluate(auth, message, getter)
+	if err != nil {
+		return false, err
+	}
+	return eval.Satisfied, nil
+}
+
+// MissingWeight returns the weight auth still lacks to meet a's threshold.
+// See Evaluate for the meaning of message and getter.
+func (a Authority) MissingWeight(auth *Authorization, message []byte, getter AccountGetter) (uint64, error) {
+	eval, err := a.Evaluate(auth, message, getter)
+	if err != nil {
+		return 0, err
+	}
+	return eval.MissingWeight(), nil
+}
@@ -0,0 +1,122 @@
+package types
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type evalTestKey struct {
+	pub  ed25519.PublicKey
+	priv ed25519.PrivateKey
+}
+
+func newEvalTestKeys(t *testing.T, n int) []evalTestKey {
+	t.Helper()
+	keys := make([]evalTestKey, n)
+	for i := range keys {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+		keys[i] = evalTestKey{pub: pub, priv: priv}
+	}
+	return keys
+}
+
+func (k evalTestKey) sign(message []byte) Signature {
+	return Signature{Algorithm: AlgorithmEd25519, PubKey: k.pub, Signature: ed25519.Sign(k.priv, message)}
+}
+
+func TestAuthorityEvaluate_PartialMultisig(t *testing.T) {
+	message := []byte("sign bytes")
+	keys := newEvalTestKeys(t, 4)
+	authority := Authority{
+		Threshold:      3,
+		KeyWeights:     map[string]uint64{string(keys[0].pub): 1, string(keys[1].pub): 1, string(keys[2].pub): 1},
+		AccountWeights: map[AccountName]uint64{},
+	}
+
+	invalid := keys[2].sign([]byte("other message"))
+	auth := NewAuthorization(
+		keys[0].sign(message),
+		keys[1].sign(message),
+		invalid,               // does not verify
+		keys[3].sign(message), // not in the authority
+	)
+
+	eval, err := authority.Evaluate(auth, message, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), eval.Weight)
+	assert.Equal(t, uint64(3), eval.Threshold)
+	assert.False(t, eval.Satisfied)
+	assert.Equal(t, uint64(1), eval.MissingWeight())
+	assert.Equal(t, "weight 2 of 3, 1 more needed", eval.String())
+	require.Len(t, eval.Keys, 2)
+	assert.Equal(t, []byte(keys[0].pub), eval.Keys[0].PubKey)
+	assert.Equal(t, AlgorithmEd25519, eval.Keys[1].Algorithm)
+	assert.Empty(t, eval.Accounts)
+
+	ok, err := authority.CanAuthorize(auth, message, nil)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	auth.Signatures[2] = keys[2].sign(message)
+	missing, err := authority.MissingWeight(auth, message, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), missing)
+	ok, err = authority.CanAuthorize(auth, message, nil)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// Evaluate agrees with VerifyAuthorization once the foreign key is dropped
+	auth.Signatures = auth.Signatures[:3]
+	account := &Account{Name: "treasury", Authority: authority}
+	require.NoError(t, auth.VerifyAuthorization(account, message, newMockAccountGetter()))
+}
+
+func TestAuthorityEvaluate_Delegation(t *testing.T) {
+	message := []byte("sign bytes")
+	keys := newEvalTestKeys(t, 2)
+
+	getter := newMockAccountGetter()
+	getter.setAccount(NewAccount("bob", keys[1].pub))
+	authority := Authority{
+		Threshold:      2,
+		KeyWeights:     map[string]uint64{string(keys[0].pub): 1},
+		AccountWeights: map[AccountName]uint64{"bob": 1, "carol": 1},
+	}
+
+	auth := NewAuthorization(keys[0].sign(message))
+	auth.AccountAuthorizations["bob"] = NewAuthorization(keys[1].sign(message))
+
+	eval, err := authority.Evaluate(auth, message, getter)
+	require.NoError(t, err)
+	assert.True(t, eval.Satisfied)
+	assert.Equal(t, uint64(2), eval.Weight)
+	assert.Equal(t, []AuthorityAccountContribution{{Account: "bob", Weight: 1}}, eval.Accounts)
+	assert.Equal(t, "weight 2 of 2, satisfied", eval.String())
+
+	// Without a getter, delegations are not counted
+	eval, err = authority.Evaluate(auth, message, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), eval.Weight)
+	assert.Empty(t, eval.Accounts)
+}
+
+func TestAuthorityEvaluate_Errors(t *testing.T) {
+	message := []byte("sign bytes")
+	keys := newEvalTestKeys(t, 1)
+	authority := NewAuthority(2, keys[0].pub, 1)
+
+	eval, err := authority.Evaluate(nil, message, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), eval.MissingWeight())
+
+	sig := keys[0].sign(message)
+	_, err = authority.Evaluate(NewAuthorization(sig, sig), message, nil)
+	assert.ErrorIs(t, err, ErrDuplicateSignature)
+
+	_, err = authority.Evaluate(&Authorization{Session: &SessionAuthorization{}}, message, nil)
+	assert.ErrorIs(t, err, ErrInvalidSession)
+}
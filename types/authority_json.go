@@ -0,0 +1,204 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// KeyWeight is a KeyWeights entry
+type KeyWeight struct {
+	// KeyID identifies the key (see KeyID)
+	KeyID string
+
+	// Weight is the key's authorization weight
+	Weight uint64
+}
+
+// AccountWeight is an AccountWeights entry
+type AccountWeight struct {
+	// Account is the delegated account
+	Account AccountName
+
+	// Weight is the account's delegation weight
+	Weight uint64
+}
+
+// SortedKeyWeights returns the KeyWeights entries in ascending bytewise key
+// ID order.
+//
+// INVARIANT: Consensus code iterates KeyWeights through this method (or
+// sorts the keys itself) so results and error messages never depend on Go's
+// randomized map iteration order.
+//
+// Complexity: O(n log n)
+func (a Authority) SortedKeyWeights() []KeyWeight {
+	entries := make([]KeyWeight, 0, len(a.KeyWeights))
+	for id, weight := range a.KeyWeights {
+		entries = append(entries, KeyWeight{KeyID: id, Weight: weight})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].KeyID < entries[j].KeyID })
+	return entries
+}
+
+// SortedAccountWeights returns the AccountWeights entries in ascending
+// account name order.
+//
+// INVARIANT: As for SortedKeyWeights.
+//
+// Complexity: O(n log n)
+func (a Authority) SortedAccountWeights() []AccountWeight {
+	entries := make([]AccountWeight, 0, len(a.AccountWeights))
+	for account, weight := range a.AccountWeights {
+		entries = append(entries, AccountWeight{Account: account, Weight: weight})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Account < entries[j].Account })
+	return entries
+}
+
+// authorityJSON is the canonical JSON form of Authority.
+//
+// Key IDs are raw bytes (an Ed25519 key ID is the public key itself), which
+// JSON object keys cannot carry: invalid UTF-8 is replaced by U+FFFD, so two
+// keys could encode identically and decode to a different key. The
+// canonical form lists the weights as arrays sorted by key ID and account
+// name, with key IDs base64-encoded ([]byte).
+type authorityJSON struct {
+	Threshold      uint64              `json:"threshold"`
+	KeyWeights     []keyWeightJSON     `json:"key_weights"`
+	AccountWeights []accountWeightJSON `json:"account_weights"`
+}
+
+type keyWeightJSON struct {
+	KeyID  []byte `json:"key_id"`
+	Weight uint64 `json:"weight"`
+}
+
+type accountWeightJSON struct {
+	Account AccountName `json:"account"`
+	Weight  uint64      `json:"weight"`
+}
+
+// MarshalJSON encodes the authority in canonical form:
+//
+//	{"threshold":2,"key_weights":[{"key_id":"<base64>","weight":1},...],"account_weights":[{"account":"bob","weight":1},...]}
+//
+// Both arrays are sorted (see SortedKeyWeights and SortedAccountWeights) and
+// always present, so equal authorities have identical encodings.
+func (a Authority) MarshalJSON() ([]byte, error) {
+	out := authorityJSON{
+		Threshold:      a.Threshold,
+		KeyWeights:     make([]keyWeightJSON, 0, len(a.KeyWeights)),
+		AccountWeights: make([]accountWeightJSON, 0, len(a.AccountWeights)),
+	}
+	for _, kw := range a.SortedKeyWeights() {
+		out.KeyWeights = append(out.KeyWeights, keyWeightJSON{KeyID: []byte(kw.KeyID), Weight: kw.Weight})
+	}
+	for _, aw := range a.SortedAccountWeights() {
+		out.AccountWeights = append(out.AccountWeights, accountWeightJSON{Account: aw.Account, Weight: aw.Weight})
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes the canonical form written by MarshalJSON.
+//
+// The legacy form, with key_weights and account_weights as JSON objects,
+// is still accepted so existing state decodes.
+//
+// SECURITY: Canonical arrays must be strictly sorted, which also rejects
+// duplicate entries, so every authority has exactly one canonical encoding.
+func (a *Authority) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Threshold      uint64          `json:"threshold"`
+		KeyWeights     json.RawMessage `json:"key_weights"`
+		AccountWeights json.RawMessage `json:"account_weights"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	keyWeights, err := decodeKeyWeights(raw.KeyWeights)
+	if err != nil {
+		return fmt.Errorf("key_weights: %w", err)
+	}
+	accountWeights, err := decodeAccountWeights(raw.AccountWeights)
+	if err != nil {
+		return fmt.Errorf("account_weights: %w", err)
+	}
+
+	*a = Authority{
+		Threshold:      raw.Threshold,
+		KeyWeights:     keyWeights,
+		AccountWeights: accountWeights,
+	}
+	return nil
+}
+
+// isJSONObject reports whether data is a JSON object (the legacy map form)
+func isJSONObject(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+// isJSONNull reports whether data is absent or null
+func isJSONNull(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null"))
+}
+
+// decodeKeyWeights decodes a canonical or legacy key_weights value.
+// Absent or null yields a nil map, as before the canonical form.
+func decodeKeyWeights(data []byte) (map[string]uint64, error) {
+	if isJSONNull(data) {
+		return nil, nil
+	}
+	if isJSONObject(data) {
+		var legacy map[string]uint64
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return nil, err
+		}
+		return legacy, nil
+	}
+
+	var entries []keyWeightJSON
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	weights := make(map[string]uint64, len(entries))
+	for i, entry := range entries {
+		if i > 0 && bytes.Compare(entries[i-1].KeyID, entry.KeyID) >= 0 {
+			return nil, fmt.Errorf("%w: entries must be sorted by key_id without duplicates", ErrInvalidAuthority)
+		}
+		weights[string(entry.KeyID)] = entry.Weight
+	}
+	return weights, nil
+}
+
+// decodeAccountWeights decodes a canonical or legacy account_weights value.
+// Absent or null yields a nil map, as before the canonical form.
+func decodeAccountWeights(data []byte) (map[AccountName]uint64, error) {
+	if isJSONNull(data) {
+		return nil, nil
+	}
+	if isJSONObject(data) {
+		var legacy map[AccountName]uint64
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return nil, err
+		}
+		return legacy, nil
+	}
+
+	var entries []accountWeightJSON
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	weights := make(map[AccountName]uint64, len(entries))
+	for i, entry := range entries {
+		if i > 0 && entries[i-1].Account >= entry.Account {
+			return nil, fmt.Errorf("%w: entries must be sorted by account without duplicates", ErrInvalidAuthority)
+		}
+		weights[entry.Account] = entry.Weight
+	}
+	return weights, nil
+}
@@ -0,0 +1,107 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthority_SortedWeights(t *testing.T) {
+	auth := Authority{
+		Threshold:      1,
+		KeyWeights:     map[string]uint64{"\xff": 3, "\x01": 1, "b": 2},
+		AccountWeights: map[AccountName]uint64{"carol": 3, "alice": 1, "bob": 2},
+	}
+
+	assert.Equal(t, []KeyWeight{{KeyID: "\x01", Weight: 1}, {KeyID: "b", Weight: 2}, {KeyID: "\xff", Weight: 3}}, auth.SortedKeyWeights())
+	assert.Equal(t, []AccountWeight{{Account: "alice", Weight: 1}, {Account: "bob", Weight: 2}, {Account: "carol", Weight: 3}}, auth.SortedAccountWeights())
+	assert.Empty(t, Authority{}.SortedKeyWeights())
+	assert.Empty(t, Authority{}.SortedAccountWeights())
+}
+
+func TestAuthority_JSONCanonical(t *testing.T) {
+	auth := Authority{
+		Threshold:      2,
+		KeyWeights:     map[string]uint64{"\xff\xfe": 1, "\x01\x02": 1},
+		AccountWeights: map[AccountName]uint64{"bob": 1, "alice": 2},
+	}
+
+	data, err := json.Marshal(auth)
+	require.NoError(t, err)
+	assert.Equal(t, `{"threshold":2,"key_weights":[{"key_id":"AQI=","weight":1},{"key_id":"//4=","weight":1}],`+
+		`"account_weights":[{"account":"alice","weight":2},{"account":"bob","weight":1}]}`, string(data))
+
+	// Encoding does not depend on map iteration order
+	for i := 0; i < 20; i++ {
+		again, err := json.Marshal(auth)
+		require.NoError(t, err)
+		require.Equal(t, data, again)
+	}
+
+	empty, err := json.Marshal(Authority{Threshold: 1})
+	require.NoError(t, err)
+	assert.Equal(t, `{"threshold":1,"key_weights":[],"account_weights":[]}`, string(empty))
+}
+
+func TestAuthority_JSONRoundTripPreservesKeyBytes(t *testing.T) {
+	// Raw Ed25519 key IDs are arbitrary bytes; a JSON object key would
+	// replace invalid UTF-8 and corrupt them
+	pubKey := []byte{0xff, 0xfe, 0x00, 0x80, 0xc3}
+	account := NewAccount("alice", pubKey)
+	account.Authority.AccountWeights["bob"] = 1
+	account.Authority.KeyWeights[KeyID(AlgorithmSecp256k1, make([]byte, 33))] = 1
+
+	data, err := json.Marshal(account)
+	require.NoError(t, err)
+
+	var decoded Account
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, account.Authority, decoded.Authority)
+	assert.True(t, decoded.Authority.HasKey(pubKey))
+}
+
+func TestAuthority_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Authority
+		wantErr bool
+	}{
+		{
+			name: "legacy objects",
+			data: `{"threshold":1,"key_weights":{"key":1},"account_weights":{"bob":2}}`,
+			want: Authority{Threshold: 1, KeyWeights: map[string]uint64{"key": 1}, AccountWeights: map[AccountName]uint64{"bob": 2}},
+		},
+		{
+			name: "null and absent",
+			data: `{"threshold":1,"key_weights":null}`,
+			want: Authority{Threshold: 1},
+		},
+		{
+			name: "empty arrays",
+			data: `{"threshold":1,"key_weights":[],"account_weights":[]}`,
+			want: Authority{Threshold: 1, KeyWeights: map[string]uint64{}, AccountWeights: map[AccountName]uint64{}},
+		},
+		{name: "unsorted keys", data: `{"threshold":1,"key_weights":[{"key_id":"Ag==","weight":1},{"key_id":"AQ==","weight":1}]}`, wantErr: true},
+		{name: "duplicate keys", data: `{"threshold":1,"key_weights":[{"key_id":"AQ==","weight":1},{"key_id":"AQ==","weight":2}]}`, wantErr: true},
+		{name: "unsorted accounts", data: `{"threshold":1,"account_weights":[{"account":"bob","weight":1},{"account":"alice","weight":1}]}`, wantErr: true},
+		{name: "duplicate accounts", data: `{"threshold":1,"account_weights":[{"account":"bob","weight":1},{"account":"bob","weight":1}]}`, wantErr: true},
+		{name: "invalid base64", data: `{"threshold":1,"key_weights":[{"key_id":"!","weight":1}]}`, wantErr: true},
+		{name: "wrong type", data: `{"threshold":1,"key_weights":"key"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var auth Authority
+			err := json.Unmarshal([]byte(tt.data), &auth)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, auth)
+		})
+	}
+}
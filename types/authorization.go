@@ -1,12 +1,10 @@
 package types
 
 import (
-	"crypto/ecdsa"
 	"crypto/ed25519"
-	"crypto/elliptic"
 	"crypto/subtle"
 	"fmt"
-	"math/big"
+	"sort"
 
 	"github.com/blockberries/punnet-sdk/crypto"
 )
@@ -29,6 +27,7 @@ const (
 	AlgorithmEd25519   = crypto.AlgorithmEd25519
 	AlgorithmSecp256k1 = crypto.AlgorithmSecp256k1
 	AlgorithmSecp256r1 = crypto.AlgorithmSecp256r1
+	AlgorithmWebAuthn  = crypto.AlgorithmWebAuthn
 )
 
 // ValidAlgorithms returns the list of production-ready algorithms.
@@ -38,9 +37,12 @@ const (
 // implemented and tested. See Issue #XX for tracking.
 //
 // REVISIT WHEN: We have a concrete use case requiring secp256k1 (Ethereum key
-// compatibility) or secp256r1 (WebAuthn/passkeys).
+// compatibility) or raw secp256r1 (HSM keys).
+//
+// WebAuthn (passkey) P-256 assertions are production-ready: they are verified
+// through the WebAuthn envelope (see webauthn.go), not as raw secp256r1.
 func ValidAlgorithms() []Algorithm {
-	return []Algorithm{AlgorithmEd25519}
+	return []Algorithm{AlgorithmEd25519, AlgorithmWebAuthn}
 }
 
 // IsValidAlgorithm checks if the algorithm is production-ready.
@@ -48,8 +50,8 @@ func ValidAlgorithms() []Algorithm {
 // INVARIANT: Only algorithms with complete, tested implementations return true.
 // BACKWARDS COMPATIBILITY: Empty string is treated as Ed25519.
 func IsValidAlgorithm(algo Algorithm) bool {
-	// Only Ed25519 is production-ready. Empty defaults to Ed25519 for backwards compat.
-	return algo == AlgorithmEd25519 || algo == ""
+	// Empty defaults to Ed25519 for backwards compat.
+	return algo == AlgorithmEd25519 || algo == AlgorithmWebAuthn || algo == ""
 }
 
 // Signature represents a single signature with public key and algorithm.
@@ -62,12 +64,18 @@ type Signature struct {
 	Algorithm Algorithm `json:"algorithm,omitempty"`
 
 	// PubKey is the public key bytes.
-	// Size depends on algorithm: Ed25519=32, secp256k1=33, secp256r1=33
+	// Size depends on algorithm: Ed25519=32, secp256k1=33, secp256r1=33, webauthn=33
 	PubKey []byte `json:"pub_key"`
 
 	// Signature is the signature bytes.
-	// Size: Ed25519=64, secp256k1=64, secp256r1=64
+	// Size: Ed25519=64, secp256k1=64, secp256r1=64, webauthn=64 (R || S)
 	Signature []byte `json:"signature"`
+
+	// WebAuthn carries the authenticator data and client data for a passkey
+	// assertion.
+	//
+	// INVARIANT: Non-nil if and only if Algorithm is AlgorithmWebAuthn.
+	WebAuthn *WebAuthnAssertion `json:"webauthn,omitempty"`
 }
 
 // GetAlgorithm returns the algorithm, defaulting to Ed25519 if not specified.
@@ -79,6 +87,22 @@ func (s *Signature) GetAlgorithm() Algorithm {
 	return s.Algorithm
 }
 
+// clone returns a deep copy of the signature, including any WebAuthn data.
+func (s Signature) clone() Signature {
+	pubKeyCopy := make([]byte, len(s.PubKey))
+	copy(pubKeyCopy, s.PubKey)
+
+	sigCopy := make([]byte, len(s.Signature))
+	copy(sigCopy, s.Signature)
+
+	return Signature{
+		Algorithm: s.Algorithm,
+		PubKey:    pubKeyCopy,
+		Signature: sigCopy,
+		WebAuthn:  s.WebAuthn.clone(),
+	}
+}
+
 // ValidateBasic performs basic validation of the signature structure.
 //
 // INVARIANT: After successful validation, PubKey and Signature have correct sizes for the algorithm.
@@ -92,6 +116,13 @@ func (s *Signature) ValidateBasic() error {
 		return fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, algo)
 	}
 
+	// SECURITY: WebAuthn data on a non-WebAuthn signature is rejected rather
+	// than ignored, so a signature has exactly one interpretation.
+	if s.WebAuthn != nil && algo != AlgorithmWebAuthn {
+		return fmt.Errorf("%w: webauthn data is only valid with the %s algorithm",
+			ErrInvalidSignature, AlgorithmWebAuthn)
+	}
+
 	// Validate key and signature sizes for production-ready algorithms
 	switch algo {
 	case AlgorithmEd25519:
@@ -104,6 +135,19 @@ func (s *Signature) ValidateBasic() error {
 				ErrInvalidSignature, ed25519.SignatureSize, len(s.Signature))
 		}
 
+	case AlgorithmWebAuthn:
+		if len(s.PubKey) != AlgorithmWebAuthn.PublicKeySize() {
+			return fmt.Errorf("%w: webauthn public key must be %d bytes, got %d",
+				ErrInvalidPublicKey, AlgorithmWebAuthn.PublicKeySize(), len(s.PubKey))
+		}
+		if len(s.Signature) != AlgorithmWebAuthn.SignatureSize() {
+			return fmt.Errorf("%w: webauthn signature must be %d bytes, got %d",
+				ErrInvalidSignature, AlgorithmWebAuthn.SignatureSize(), len(s.Signature))
+		}
+		if err := s.WebAuthn.ValidateBasic(); err != nil {
+			return err
+		}
+
 	// NOTE: secp256k1 and secp256r1 cases are intentionally removed.
 	// IsValidAlgorithm() above already rejects these algorithms.
 	// When these algorithms become production-ready, add cases here with
@@ -140,6 +184,9 @@ func (s *Signature) Verify(message []byte) bool {
 	case AlgorithmSecp256r1:
 		return verifySecp256r1(s.PubKey, message, s.Signature)
 
+	case AlgorithmWebAuthn:
+		return verifyWebAuthn(s.PubKey, message, s.Signature, s.WebAuthn)
+
 	default:
 		return false
 	}
@@ -185,108 +232,42 @@ func verifySecp256r1(pubKey, message, signature []byte) bool {
 	// IsValidAlgorithm() rejects secp256r1, so ValidateBasic() will fail
 	// before Verify() is called.
 	//
-	// The implementation below is kept for documentation and future use,
-	// but will panic if reached to catch any validation bypass bugs.
-
-	// Decompress public key
-	if len(pubKey) != 33 {
-		return false
-	}
-
-	// Parse the compressed public key
-	x, y := decompressP256PublicKey(pubKey)
-	if x == nil || y == nil {
-		return false
-	}
-
-	curve := elliptic.P256()
-
-	// SECURITY FIX: Validate that the point is actually on the curve.
-	// A malicious actor could craft a compressed key where X is valid but
-	// produces a point not on the curve, leading to undefined behavior.
-	if !curve.IsOnCurve(x, y) {
-		return false
-	}
-
-	ecdsaPubKey := &ecdsa.PublicKey{
-		Curve: curve,
-		X:     x,
-		Y:     y,
-	}
-
-	// Parse R and S from signature
-	if len(signature) != 64 {
-		return false
-	}
-	r := new(big.Int).SetBytes(signature[:32])
-	s := new(big.Int).SetBytes(signature[32:])
-
+	// The implementation is shared with WebAuthn verification, which signs
+	// a digest derived from the message rather than the message itself.
+	//
 	// SECURITY FIX: The message parameter is already SHA-256(SignDoc JSON) from
 	// SignDoc.GetSignBytes(). Do NOT double-hash.
-	//
-	// ECDSA verification expects the hash of the message. Since our message
-	// is already the hash, we pass it directly.
-	return ecdsa.Verify(ecdsaPubKey, message, r, s)
+	return verifyP256Digest(pubKey, message, signature)
 }
 
-// decompressP256PublicKey decompresses a 33-byte compressed P-256 public key.
+// verifyP256Digest verifies a 64-byte R || S ECDSA signature over a 32-byte
+// digest using a 33-byte compressed P-256 public key.
 //
-// Format: 0x02 or 0x03 prefix (indicating Y coordinate parity) + 32 bytes X coordinate
+// SECURITY: Parsing goes through crypto.ParsePublicKeyCached, which rejects
+// points not on the curve; verification uses Go's standard library
+// crypto/ecdsa. The digest is passed to ECDSA as-is; callers are responsible
+// for hashing.
 //
-// SECURITY: This function validates that the decompressed point lies on the P-256 curve.
-// A malicious actor could craft a compressed key that produces invalid coordinates.
-func decompressP256PublicKey(compressed []byte) (*big.Int, *big.Int) {
-	if len(compressed) != 33 {
-		return nil, nil
-	}
-
-	prefix := compressed[0]
-	if prefix != 0x02 && prefix != 0x03 {
-		return nil, nil
+// SECURITY: High-S signatures are rejected. (r, s) and (r, n-s) both verify,
+// so accepting either would make signatures malleable; only the low-S form
+// (s <= n/2) is canonical.
+func verifyP256Digest(pubKey, digest, signature []byte) bool {
+	if len(pubKey) != 33 || len(signature) != 64 {
+		return false
 	}
-
-	x := new(big.Int).SetBytes(compressed[1:])
-	curve := elliptic.P256()
-
-	// Calculate Y from X using curve equation: y² = x³ - 3x + b (mod p)
-	// P-256 parameters
-	params := curve.Params()
-	p := params.P
-
-	// x³
-	x3 := new(big.Int).Mul(x, x)
-	x3.Mul(x3, x)
-	x3.Mod(x3, p)
-
-	// -3x
-	threeX := new(big.Int).Mul(x, big.NewInt(3))
-	threeX.Mod(threeX, p)
-
-	// x³ - 3x + b
-	y2 := new(big.Int).Sub(x3, threeX)
-	y2.Add(y2, params.B)
-	y2.Mod(y2, p)
-
-	// y = sqrt(y²) mod p
-	y := new(big.Int).ModSqrt(y2, p)
-	if y == nil {
-		return nil, nil
+	if !crypto.IsLowSForAlgorithm(signature, AlgorithmSecp256r1) {
+		return false
 	}
 
-	// Choose the correct Y based on prefix (even/odd)
-	if prefix == 0x02 && y.Bit(0) != 0 {
-		y.Sub(p, y)
-	} else if prefix == 0x03 && y.Bit(0) == 0 {
-		y.Sub(p, y)
+	key, err := crypto.ParsePublicKeyCached(AlgorithmSecp256r1, pubKey)
+	if err != nil {
+		return false
 	}
-
-	// SECURITY FIX: Verify point is actually on the curve.
-	// This is defense-in-depth; the caller should also verify.
-	if !curve.IsOnCurve(x, y) {
-		return nil, nil
+	verifier, ok := key.(crypto.DigestVerifier)
+	if !ok {
+		return false
 	}
-
-	return x, y
+	return verifier.VerifyDigest(digest, signature)
 }
 
 // Authorization represents proof of authority to perform an action
@@ -297,6 +278,25 @@ type Authorization struct {
 	// AccountAuthorizations maps delegated account names to their authorizations
 	// This enables recursive/hierarchical authorization
 	AccountAuthorizations map[AccountName]*Authorization `json:"account_authorizations,omitempty"`
+
+	// Session authorizes a transaction with a session key instead of the
+	// account's authority. When set, Signatures and AccountAuthorizations
+	// must be empty. Only valid on a transaction's top-level authorization;
+	// see Transaction.VerifyAuthorizationAtHeight.
+	Session *SessionAuthorization `json:"session,omitempty"`
+
+	// NotBefore is the first block (by height or time) at which the
+	// transaction may execute, so a pre-signed transaction cannot be
+	// broadcast early. Nil means no lower bound.
+	NotBefore *ValidityBound `json:"not_before,omitempty"`
+
+	// NotAfter is the last block (by height or time) at which the
+	// transaction may execute, so a signed transaction expires.
+	// Nil means no upper bound.
+	//
+	// NotBefore and NotAfter are included in the SignDoc. They are only
+	// valid on a transaction's top-level authorization; see CheckValidity.
+	NotAfter *ValidityBound `json:"not_after,omitempty"`
 }
 
 // NewAuthorization creates a new authorization with signatures.
@@ -305,18 +305,7 @@ func NewAuthorization(signatures ...Signature) *Authorization {
 	// Create defensive deep copy of signatures
 	sigsCopy := make([]Signature, len(signatures))
 	for i, sig := range signatures {
-		// Deep copy each signature's byte slices
-		pubKeyCopy := make([]byte, len(sig.PubKey))
-		copy(pubKeyCopy, sig.PubKey)
-
-		sigCopy := make([]byte, len(sig.Signature))
-		copy(sigCopy, sig.Signature)
-
-		sigsCopy[i] = Signature{
-			Algorithm: sig.Algorithm,
-			PubKey:    pubKeyCopy,
-			Signature: sigCopy,
-		}
+		sigsCopy[i] = sig.clone()
 	}
 
 	return &Authorization{
@@ -331,6 +320,17 @@ func (a *Authorization) ValidateBasic() error {
 		return fmt.Errorf("%w: authorization is nil", ErrInvalidAuthorization)
 	}
 
+	if err := validateValidityWindow(a.NotBefore, a.NotAfter); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAuthorization, err)
+	}
+
+	if a.Session != nil {
+		if len(a.Signatures) > 0 || len(a.AccountAuthorizations) > 0 {
+			return fmt.Errorf("%w: session authorization cannot carry signatures or account authorizations", ErrInvalidAuthorization)
+		}
+		return a.Session.ValidateBasic()
+	}
+
 	// Validate all signatures
 	for i, sig := range a.Signatures {
 		if err := sig.ValidateBasic(); err != nil {
@@ -346,6 +346,16 @@ func (a *Authorization) ValidateBasic() error {
 		if auth == nil {
 			return fmt.Errorf("%w: nil authorization for account %s", ErrInvalidAuthorization, acct)
 		}
+		// SECURITY: Session keys act only for the account that granted them,
+		// never through delegation.
+		if auth.Session != nil {
+			return fmt.Errorf("%w: account %s: %v: session authorization cannot be delegated", ErrInvalidAuthorization, acct, ErrInvalidSession)
+		}
+		// SECURITY: Only the top-level window is in the SignDoc; a nested
+		// window would look binding without being signed.
+		if auth.NotBefore != nil || auth.NotAfter != nil {
+			return fmt.Errorf("%w: account %s: validity window is only allowed on the top-level authorization", ErrInvalidAuthorization, acct)
+		}
 		if err := auth.ValidateBasic(); err != nil {
 			return fmt.Errorf("%w: account %s: %v", ErrInvalidAuthorization, acct, err)
 		}
@@ -383,21 +393,32 @@ func (a *Authorization) VerifyAuthorization(account *Account, message []byte, ge
 		return fmt.Errorf("%w: account getter is nil", ErrInvalidAuthorization)
 	}
 
+	// SECURITY: Session authorizations need the block height and message
+	// types, which only the transaction provides.
+	if a.Session != nil {
+		return fmt.Errorf("%w: session authorization requires transaction context", ErrInvalidSession)
+	}
+
+	// Shared sub-accounts and repeated signatures are verified once per call
+	cache := newVerificationCache(message, getter)
+
 	// Verify all direct signatures first
-	if err := a.VerifySignatures(message); err != nil {
-		return err
+	for i := range a.Signatures {
+		if !cache.verify(&a.Signatures[i]) {
+			return fmt.Errorf("%w: signature %d failed verification", ErrInvalidSignature, i)
+		}
 	}
 
 	// Calculate authorization weight with cycle detection
 	visited := make(map[AccountName]bool)
-	weight, err := a.calculateWeight(account.Name, account.Authority, message, getter, visited, 0)
+	result, err := a.calculateWeight(account.Name, account.Authority, cache, visited, 0, nil)
 	if err != nil {
 		return err
 	}
 
 	// Check if weight meets threshold
-	if weight < account.Authority.Threshold {
-		return fmt.Errorf("%w: weight %d < threshold %d", ErrInsufficientWeight, weight, account.Authority.Threshold)
+	if result.weight < account.Authority.Threshold {
+		return fmt.Errorf("%w: weight %d < threshold %d", ErrInsufficientWeight, result.weight, account.Authority.Threshold)
 	}
 
 	return nil
@@ -412,6 +433,15 @@ func (a *Authorization) VerifyAuthorization(account *Account, message []byte, ge
 //     affect the top-level account's authority during verification.
 //   - Delegated accounts: Uses LIVE semantics. getter.GetAccount() is called
 //     for each delegated account, so concurrent modifications ARE visible.
+//     Each delegated account is fetched at most once per verification (see
+//     verificationCache), so all branches see the same authority.
+//
+// MEMOIZATION: Signature checks and subtree weights are cached in cache.
+// A cached subtree weight is reused only when doing so cannot hide a cycle
+// or depth violation (see verificationCache.lookupWeight).
+//
+// If eval is non-nil, the keys and delegated accounts that contribute at this
+// level (not in nested delegations) are appended to it; see Authority.Evaluate.
 //
 // SECURITY IMPLICATION: The live semantics for delegated accounts creates a
 // potential TOCTOU (time-of-check-time-of-use) window. An attacker who can
@@ -425,19 +455,19 @@ func (a *Authorization) VerifyAuthorization(account *Account, message []byte, ge
 func (a *Authorization) calculateWeight(
 	accountName AccountName,
 	authority Authority,
-	message []byte,
-	getter AccountGetter,
+	cache *verificationCache,
 	visited map[AccountName]bool,
 	depth int,
-) (uint64, error) {
+	eval *AuthorityEvaluation,
+) (weightResult, error) {
 	// Check recursion depth
 	if depth > MaxRecursionDepth {
-		return 0, fmt.Errorf("%w: depth %d", ErrMaxRecursionDepth, depth)
+		return weightResult{}, fmt.Errorf("%w: depth %d", ErrMaxRecursionDepth, depth)
 	}
 
 	// Check for cycles
 	if visited[accountName] {
-		return 0, fmt.Errorf("%w: account %s appears multiple times in delegation chain", ErrAuthorizationCycle, accountName)
+		return weightResult{}, fmt.Errorf("%w: account %s appears multiple times in delegation chain", ErrAuthorizationCycle, accountName)
 	}
 
 	// Mark as visited
@@ -448,6 +478,8 @@ func (a *Authorization) calculateWeight(
 	}()
 
 	var totalWeight uint64
+	result := weightResult{accounts: []AccountName{accountName}}
+	inSubtree := map[AccountName]bool{accountName: true}
 
 	// SECURITY FIX (Issue #30): Track which public keys have already contributed weight.
 	// Without this, an attacker could submit multiple copies of the same signature
@@ -459,69 +491,123 @@ func (a *Authorization) calculateWeight(
 	seenPubKeys := make(map[string]bool)
 
 	// Calculate weight from direct key signatures
-	for _, sig := range a.Signatures {
-		pubKeyStr := string(sig.PubKey)
+	for i := range a.Signatures {
+		sig := &a.Signatures[i]
+		algo := sig.GetAlgorithm()
+		pubKeyStr := KeyID(algo, sig.PubKey)
 
 		// SECURITY: Check for duplicate signatures from same public key
 		if seenPubKeys[pubKeyStr] {
 			// Return error to make duplicate detection explicit
-			return 0, fmt.Errorf("%w: public key already provided a signature", ErrDuplicateSignature)
+			return weightResult{}, fmt.Errorf("%w: public key already provided a signature", ErrDuplicateSignature)
 		}
 
-		if authority.HasKey(sig.PubKey) {
-			if sig.Verify(message) {
+		// SECURITY: Keys are looked up by (algorithm, public key). A key only
+		// contributes weight when signed with the algorithm the authority lists
+		// it under, so e.g. a passkey's P-256 key cannot be satisfied by a raw
+		// secp256r1 signature.
+		if authority.HasAlgorithmKey(algo, sig.PubKey) {
+			if cache.verify(sig) {
 				// Mark this public key as having contributed
 				seenPubKeys[pubKeyStr] = true
 
-				keyWeight := authority.GetKeyWeight(sig.PubKey)
+				keyWeight := authority.GetAlgorithmKeyWeight(algo, sig.PubKey)
 				// Check for overflow
 				if totalWeight > ^uint64(0)-keyWeight {
-					return 0, fmt.Errorf("weight calculation overflow")
+					return weightResult{}, fmt.Errorf("weight calculation overflow")
 				}
 				totalWeight += keyWeight
+				if eval != nil {
+					eval.Keys = append(eval.Keys, AuthorityKeyContribution{
+						Algorithm: algo,
+						PubKey:    append([]byte(nil), sig.PubKey...),
+						Weight:    keyWeight,
+					})
+				}
 			}
 		}
 	}
 
-	// Calculate weight from delegated account authorizations (recursive)
-	for delegatedAcct, delegatedAuth := range a.AccountAuthorizations {
+	// Calculate weight from delegated account authorizations (recursive).
+	// Accounts are walked in name order: the first error decides the
+	// transaction's result code, which must not depend on map order.
+	for _, delegatedAcct := range a.delegatedAccounts() {
+		delegatedAuth := a.AccountAuthorizations[delegatedAcct]
 		// Check if this account is in the authority's delegation list
 		if !authority.HasAccount(delegatedAcct) {
 			continue
 		}
 
 		// Get the delegated account
-		delegatedAccount, err := getter.GetAccount(delegatedAcct)
+		delegatedAccount, err := cache.account(delegatedAcct)
 		if err != nil {
-			return 0, fmt.Errorf("failed to get delegated account %s: %w", delegatedAcct, err)
+			return weightResult{}, fmt.Errorf("failed to get delegated account %s: %w", delegatedAcct, err)
 		}
 
-		// Recursively calculate weight for delegated account
-		delegatedWeight, err := delegatedAuth.calculateWeight(
-			delegatedAcct,
-			delegatedAccount.Authority,
-			message,
-			getter,
-			visited,
-			depth+1,
-		)
-		if err != nil {
-			return 0, fmt.Errorf("delegated account %s: %w", delegatedAcct, err)
+		// Recursively calculate weight for delegated account, reusing the
+		// result if this subtree was already evaluated on another path
+		key := weightCacheKey{account: delegatedAcct, auth: delegatedAuth}
+		delegated, hit := cache.lookupWeight(key, visited, depth+1)
+		if !hit {
+			delegated, err = delegatedAuth.calculateWeight(
+				delegatedAcct,
+				delegatedAccount.Authority,
+				cache,
+				visited,
+				depth+1,
+				nil,
+			)
+			if err != nil {
+				return weightResult{}, fmt.Errorf("delegated account %s: %w", delegatedAcct, err)
+			}
+			cache.storeWeight(key, delegated)
+		}
+
+		if delegated.height+1 > result.height {
+			result.height = delegated.height + 1
+		}
+		for _, acct := range delegated.accounts {
+			if !inSubtree[acct] {
+				inSubtree[acct] = true
+				result.accounts = append(result.accounts, acct)
+			}
 		}
 
 		// If the delegated account's authorization is valid (meets its threshold),
 		// add the delegation weight to total
-		if delegatedWeight >= delegatedAccount.Authority.Threshold {
+		if delegated.weight >= delegatedAccount.Authority.Threshold {
 			accountWeight := authority.GetAccountWeight(delegatedAcct)
 			// Check for overflow
 			if totalWeight > ^uint64(0)-accountWeight {
-				return 0, fmt.Errorf("weight calculation overflow")
+				return weightResult{}, fmt.Errorf("weight calculation overflow")
 			}
 			totalWeight += accountWeight
+			if eval != nil {
+				eval.Accounts = append(eval.Accounts, AuthorityAccountContribution{
+					Account: delegatedAcct,
+					Weight:  accountWeight,
+				})
+			}
 		}
 	}
 
-	return totalWeight, nil
+	if eval != nil {
+		sort.Slice(eval.Accounts, func(i, j int) bool { return eval.Accounts[i].Account < eval.Accounts[j].Account })
+	}
+
+	result.weight = totalWeight
+	return result, nil
+}
+
+// delegatedAccounts returns the accounts of a.AccountAuthorizations in
+// sorted order
+func (a *Authorization) delegatedAccounts() []AccountName {
+	names := make([]AccountName, 0, len(a.AccountAuthorizations))
+	for name := range a.AccountAuthorizations {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
 }
 
 // GetSignedPubKeys returns a list of all public keys that have valid signatures
@@ -2,6 +2,7 @@ package types
 
 import (
 	"crypto/ed25519"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"testing"
@@ -536,6 +537,56 @@ func TestAuthorization_DuplicateSignatureDetection(t *testing.T) {
 		"Duplicate signatures should produce explicit error")
 }
 
+// TestAuthorization_DelegatedErrorDeterministic checks that an authorization
+// failing in several delegated subtrees always fails with the same error:
+// the error becomes the transaction's result code, so it must not depend on
+// map iteration order.
+func TestAuthorization_DelegatedErrorDeterministic(t *testing.T) {
+	pubAlice, privAlice, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	pubCarol, privCarol, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	message := []byte("test message")
+	alice := &Account{
+		Name: "alice",
+		Authority: Authority{
+			Threshold:      1,
+			KeyWeights:     map[string]uint64{string(pubAlice): 1},
+			AccountWeights: map[AccountName]uint64{"bob": 1, "carol": 1},
+		},
+	}
+	bob := &Account{
+		Name:      "bob",
+		Authority: Authority{Threshold: 1, AccountWeights: map[AccountName]uint64{"alice": 1}},
+	}
+	carol := &Account{
+		Name:      "carol",
+		Authority: Authority{Threshold: 1, KeyWeights: map[string]uint64{string(pubCarol): 1}},
+	}
+	getter := newMockAccountGetter()
+	getter.setAccount(alice)
+	getter.setAccount(bob)
+	getter.setAccount(carol)
+
+	// bob's subtree loops back to alice; carol's repeats a signature
+	carolSig := Signature{PubKey: pubCarol, Signature: ed25519.Sign(privCarol, message)}
+	auth := &Authorization{
+		Signatures: []Signature{{PubKey: pubAlice, Signature: ed25519.Sign(privAlice, message)}},
+		AccountAuthorizations: map[AccountName]*Authorization{
+			"bob":   {AccountAuthorizations: map[AccountName]*Authorization{}},
+			"carol": {Signatures: []Signature{carolSig, carolSig}},
+		},
+	}
+	auth.AccountAuthorizations["bob"].AccountAuthorizations["alice"] = auth
+
+	for i := 0; i < 200; i++ {
+		err := auth.VerifyAuthorization(alice, message, getter)
+		require.ErrorIs(t, err, ErrAuthorizationCycle, "run %d", i)
+		require.NotErrorIs(t, err, ErrDuplicateSignature, "run %d", i)
+	}
+}
+
 // TestAuthorization_MultipleValidKeysNotDuplicate verifies that different valid
 // signatures from different keys are counted correctly (not mistakenly deduplicated).
 func TestAuthorization_MultipleValidKeysNotDuplicate(t *testing.T) {
@@ -584,3 +635,93 @@ func TestAuthorization_MultipleValidKeysNotDuplicate(t *testing.T) {
 	err = auth.VerifyAuthorization(account, message, getter)
 	assert.NoError(t, err, "Three different valid signatures should meet threshold=3")
 }
+
+func TestKeyID(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	p256Key := append([]byte{0x02}, make([]byte, 32)...)
+
+	t.Run("ed25519 uses raw key bytes", func(t *testing.T) {
+		assert.Equal(t, string(pub), KeyID(AlgorithmEd25519, pub))
+		assert.Equal(t, string(pub), KeyID("", pub))
+	})
+
+	t.Run("same bytes under different algorithms are distinct", func(t *testing.T) {
+		assert.NotEqual(t, KeyID(AlgorithmSecp256r1, p256Key), KeyID(AlgorithmWebAuthn, p256Key))
+		assert.NotEqual(t, string(p256Key), KeyID(AlgorithmWebAuthn, p256Key))
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		tests := []struct {
+			algo   Algorithm
+			pubKey []byte
+		}{
+			{AlgorithmEd25519, pub},
+			{AlgorithmSecp256k1, p256Key},
+			{AlgorithmSecp256r1, p256Key},
+			{AlgorithmWebAuthn, p256Key},
+		}
+		for _, tt := range tests {
+			algo, pubKey := ParseKeyID(KeyID(tt.algo, tt.pubKey))
+			assert.Equal(t, tt.algo, algo)
+			assert.Equal(t, tt.pubKey, pubKey)
+		}
+	})
+
+	t.Run("unprefixed and malformed IDs parse as ed25519", func(t *testing.T) {
+		for _, id := range []string{"testkey", "webauthn:short", "unknown:" + string(p256Key)} {
+			algo, pubKey := ParseKeyID(id)
+			assert.Equal(t, AlgorithmEd25519, algo)
+			assert.Equal(t, []byte(id), pubKey)
+		}
+	})
+}
+
+func TestAuthority_AlgorithmScopedKeys(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	p256Key := append([]byte{0x03}, make([]byte, 32)...)
+
+	authority := Authority{
+		Threshold: 2,
+		KeyWeights: map[string]uint64{
+			KeyID(AlgorithmEd25519, pub):      1,
+			KeyID(AlgorithmWebAuthn, p256Key): 3,
+		},
+	}
+	require.NoError(t, authority.ValidateBasic())
+
+	assert.True(t, authority.HasKey(pub))
+	assert.True(t, authority.HasAlgorithmKey(AlgorithmEd25519, pub))
+	assert.Equal(t, uint64(1), authority.GetKeyWeight(pub))
+
+	assert.True(t, authority.HasAlgorithmKey(AlgorithmWebAuthn, p256Key))
+	assert.Equal(t, uint64(3), authority.GetAlgorithmKeyWeight(AlgorithmWebAuthn, p256Key))
+	assert.False(t, authority.HasKey(p256Key), "webauthn key must not match as ed25519")
+	assert.False(t, authority.HasAlgorithmKey(AlgorithmSecp256r1, p256Key), "webauthn key must not match as secp256r1")
+	assert.Zero(t, authority.GetAlgorithmKeyWeight(AlgorithmSecp256r1, p256Key))
+}
+
+func TestSignature_JSONIncludesAlgorithm(t *testing.T) {
+	sig := Signature{
+		Algorithm: AlgorithmWebAuthn,
+		PubKey:    []byte{0x02, 0x01},
+		Signature: []byte{0x03},
+		WebAuthn: &WebAuthnAssertion{
+			AuthenticatorData: []byte{0x04},
+			ClientDataJSON:    []byte(`{}`),
+		},
+	}
+
+	data, err := json.Marshal(sig)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"algorithm":"webauthn"`)
+
+	var decoded Signature
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, sig, decoded)
+
+	// Unknown algorithms are rejected at decode time.
+	err = json.Unmarshal([]byte(`{"algorithm":"rsa","pub_key":"","signature":""}`), &decoded)
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxChainIDLength is the maximum length of a chain ID in bytes
+const MaxChainIDLength = 64
+
+// ValidateChainID checks that chainID follows the chain ID grammar:
+//
+//	chain-id  = segment *( separator segment ) [ "-" epoch ]
+//	segment   = 1*( %x61-7A / DIGIT )        ; lowercase ASCII letters and digits
+//	separator = "-" / "."
+//	epoch     = "0" / %x31-39 *DIGIT         ; decimal uint64, no leading zeros
+//
+// of at most MaxChainIDLength bytes, e.g. "punnet-mainnet-1". A final
+// "-<digits>" segment is the epoch (see ChainIDEpoch), incremented when a
+// chain restarts from a new genesis.
+//
+// SECURITY: Chain IDs bind signatures to a chain. Restricting them to
+// lowercase ASCII without empty segments rules out IDs that render alike but
+// differ in bytes - homoglyphs, case variants, invisible or whitespace
+// characters, "chain--1" versus "chain-1", "chain-01" versus "chain-1" - so
+// a signer cannot be shown one chain while signing for another.
+func ValidateChainID(chainID string) error {
+	if chainID == "" {
+		return fmt.Errorf("%w: chain ID cannot be empty", ErrInvalidChainID)
+	}
+	if len(chainID) > MaxChainIDLength {
+		return fmt.Errorf("%w: chain ID exceeds %d bytes", ErrInvalidChainID, MaxChainIDLength)
+	}
+
+	// Complexity: O(n) for a chain ID of n bytes
+	segmentStart := 0
+	for i := 0; i <= len(chainID); i++ {
+		if i < len(chainID) {
+			c := chainID[i]
+			if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+				continue
+			}
+			if c != '-' && c != '.' {
+				r, _ := utf8.DecodeRuneInString(chainID[i:])
+				return fmt.Errorf("%w: chain ID %q has invalid character %q at %d (allowed: a-z, 0-9, '-', '.')", ErrInvalidChainID, chainID, r, i)
+			}
+		}
+		if i == segmentStart {
+			return fmt.Errorf("%w: chain ID %q has an empty segment at %d", ErrInvalidChainID, chainID, i)
+		}
+		segmentStart = i + 1
+	}
+
+	if epoch, ok := chainIDEpochSuffix(chainID); ok {
+		if len(epoch) > 1 && epoch[0] == '0' {
+			return fmt.Errorf("%w: chain ID %q has an epoch with leading zeros", ErrInvalidChainID, chainID)
+		}
+		if _, err := strconv.ParseUint(epoch, 10, 64); err != nil {
+			return fmt.Errorf("%w: chain ID %q has an epoch out of range", ErrInvalidChainID, chainID)
+		}
+	}
+	return nil
+}
+
+// NormalizeChainID converts user input to a chain ID: it trims surrounding
+// whitespace, applies Unicode NFC normalization and lowercases the result,
+// then validates it with ValidateChainID.
+//
+// Use it where people type chain IDs (CLI flags, configuration files), never
+// on chain IDs that were signed: those must already be valid as-is.
+func NormalizeChainID(input string) (string, error) {
+	chainID := strings.ToLower(norm.NFC.String(strings.TrimSpace(input)))
+	if err := ValidateChainID(chainID); err != nil {
+		return "", err
+	}
+	return chainID, nil
+}
+
+// ChainIDEpoch returns the epoch of a chain ID - its final "-<digits>"
+// segment - and whether it has one, e.g. 4 for "punnet-4".
+//
+// PRECONDITION: chainID is valid (see ValidateChainID).
+func ChainIDEpoch(chainID string) (uint64, bool) {
+	suffix, ok := chainIDEpochSuffix(chainID)
+	if !ok {
+		return 0, false
+	}
+	epoch, err := strconv.ParseUint(suffix, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return epoch, true
+}
+
+// chainIDEpochSuffix returns the digits after the last "-" of chainID if they
+// form its final segment
+func chainIDEpochSuffix(chainID string) (string, bool) {
+	i := strings.LastIndexByte(chainID, '-')
+	if i < 0 || i == len(chainID)-1 {
+		return "", false
+	}
+	suffix := chainID[i+1:]
+	for j := 0; j < len(suffix); j++ {
+		if suffix[j] < '0' || suffix[j] > '9' {
+			return "", false
+		}
+	}
+	return suffix, true
+}
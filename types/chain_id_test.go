@@ -0,0 +1,109 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateChainID(t *testing.T) {
+	tests := []struct {
+		name    string
+		chainID string
+		valid   bool
+	}{
+		{"single segment", "test", true},
+		{"with epoch", "punnet-mainnet-1", true},
+		{"epoch zero", "punnet-0", true},
+		{"dotted", "punnet.test-2", true},
+		{"digits only", "42", true},
+		{"max length", strings.Repeat("c", MaxChainIDLength), true},
+
+		{"empty", "", false},
+		{"too long", strings.Repeat("c", MaxChainIDLength+1), false},
+		{"uppercase", "Punnet-1", false},
+		{"underscore", "punnet_1", false},
+		{"whitespace", "punnet 1", false},
+		{"trailing newline", "punnet-1\n", false},
+		{"non-ascii homoglyph", "p\u0443nnet-1", false},
+		{"zero-width space", "punnet\u200b-1", false},
+		{"leading separator", "-punnet", false},
+		{"trailing separator", "punnet-", false},
+		{"double separator", "punnet--1", false},
+		{"epoch with leading zero", "punnet-01", false},
+		{"epoch overflow", "punnet-18446744073709551616", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateChainID(tt.chainID)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidChainID)
+			}
+		})
+	}
+}
+
+func TestNormalizeChainID(t *testing.T) {
+	chainID, err := NormalizeChainID("  Punnet-Mainnet-1\n")
+	require.NoError(t, err)
+	assert.Equal(t, "punnet-mainnet-1", chainID)
+
+	_, err = NormalizeChainID("punnet mainnet")
+	assert.ErrorIs(t, err, ErrInvalidChainID)
+}
+
+func TestChainIDEpoch(t *testing.T) {
+	tests := []struct {
+		chainID  string
+		epoch    uint64
+		hasEpoch bool
+	}{
+		{"punnet-mainnet-4", 4, true},
+		{"punnet-0", 0, true},
+		{"punnet", 0, false},
+		{"punnet-1.5", 0, false},
+		{"42", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.chainID, func(t *testing.T) {
+			epoch, ok := ChainIDEpoch(tt.chainID)
+			assert.Equal(t, tt.hasEpoch, ok)
+			assert.Equal(t, tt.epoch, epoch)
+		})
+	}
+}
+
+func TestNewSignDoc_ChainID(t *testing.T) {
+	for _, chainID := range []string{"", "Punnet-1", "punnet--1", "punnet-01", "punnet 1", "рunnet-1", strings.Repeat("a", MaxChainIDLength+1)} {
+		t.Run(chainID, func(t *testing.T) {
+			_, err := NewSignDoc(chainID, 0, "alice", 0, "")
+			assert.ErrorIs(t, err, ErrInvalidChainID)
+
+			_, err = NewSignDocWithFee(chainID, 0, "alice", 0, "", SignDocFee{GasLimit: "0"}, SignDocRatio{Numerator: "0", Denominator: "1"})
+			assert.ErrorIs(t, err, ErrInvalidChainID)
+		})
+	}
+
+	sd, err := NewSignDoc("punnet-1", 0, "alice", 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, "punnet-1", sd.ChainID)
+}
+
+func TestSignDoc_ValidateBasic_ChainID(t *testing.T) {
+	// A SignDoc decoded from the wire is not built by NewSignDoc
+	sd, err := NewSignDoc("punnet-1", 0, "alice", 0, "")
+	require.NoError(t, err)
+	sd.ChainID = "Punnet-1"
+	sd.AddMessage("/test.Msg", []byte(`{}`))
+	assert.ErrorIs(t, sd.ValidateBasic(), ErrInvalidChainID)
+	assert.ErrorIs(t, sd.ValidateBasic(), ErrSignDocMismatch)
+
+	sd.ChainID = "punnet-1"
+	assert.NoError(t, sd.ValidateBasic())
+}
@@ -0,0 +1,40 @@
+package types
+
+import sdkerrors "github.com/blockberries/punnet-sdk/errors"
+
+// SDK error codes.
+func init() {
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 2, ErrNotFound)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 3, ErrUnauthorized)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 4, ErrInvalidAccount)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 5, ErrInvalidAuthority)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 6, ErrInvalidAuthorization)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 7, ErrAuthorizationCycle)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 8, ErrInsufficientWeight)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 9, ErrInvalidSignature)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 10, ErrInvalidCoin)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 11, ErrInsufficientFunds)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 12, ErrInvalidMessage)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 13, ErrInvalidTransaction)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 14, ErrConflictingEffects)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 15, ErrInvalidEffect)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 16, ErrMaxRecursionDepth)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 17, ErrSignDocMismatch)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 18, ErrInvalidPublicKey)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 19, ErrUnsupportedAlgorithm)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 20, ErrDuplicateSignature)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 21, ErrChainIDMismatch)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 22, ErrSequenceMismatch)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 23, ErrUnsupportedVersion)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 24, ErrInvalidSession)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 25, ErrSessionExpired)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 26, ErrSessionMessageNotAllowed)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 27, ErrExtensionSchemaMismatch)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 28, ErrTxTooLarge)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 29, ErrTooManySignatures)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 30, ErrTxNotYetValid)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 31, ErrTxExpired)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 32, ErrNonCanonicalAccount)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 33, ErrInvalidChainID)
+	sdkerrors.MustRegister(sdkerrors.CodespaceSDK, 34, ErrInvalidMemo)
+}
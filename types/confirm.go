@@ -0,0 +1,78 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+)
+
+// Confirmation errors, re-exported from crypto so callers need only one import.
+var (
+	// ErrSigningDenied is returned when a ConfirmFunc rejects a signing request.
+	ErrSigningDenied = crypto.ErrSigningDenied
+
+	// ErrConfirmTimeout is returned when a ConfirmFunc does not answer in time.
+	ErrConfirmTimeout = crypto.ErrConfirmTimeout
+)
+
+// ConfirmFunc is called before a SignDoc is signed so a GUI or CLI can show
+// it to the user. Return nil to approve; any error denies signing. Returning
+// ErrSigningDenied (optionally wrapped) signals an explicit user denial.
+//
+// ctx is cancelled when the approval timeout expires; implementations should
+// dismiss their prompt when it is done.
+//
+// The SignDoc must not be modified.
+type ConfirmFunc func(ctx context.Context, signDoc *SignDoc) error
+
+// KeyringConfirmHook adapts fn for crypto.WithConfirmHook.
+//
+// Keyring.SignSignDoc requests are passed to fn as a *SignDoc. Raw
+// Keyring.Sign requests carry no SignDoc to show the user and are denied.
+func KeyringConfirmHook(fn ConfirmFunc) crypto.ConfirmHook {
+	return func(ctx context.Context, req crypto.ConfirmRequest) error {
+		if req.SignDoc == nil {
+			return fmt.Errorf("%w: raw signing of key %s cannot be confirmed", ErrSigningDenied, req.Key)
+		}
+		signDoc, ok := req.SignDoc.(*SignDoc)
+		if !ok {
+			docJSON, err := req.SignDoc.ToJSON()
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrSigningDenied, err)
+			}
+			if signDoc, err = ParseSignDoc(docJSON); err != nil {
+				return fmt.Errorf("%w: %w", ErrSigningDenied, err)
+			}
+		}
+		return fn(ctx, signDoc)
+	}
+}
+
+// SetConfirmFunc installs fn to be called before SignWithSigner signs.
+// timeout bounds how long fn may take; zero means no timeout. A nil fn
+// removes confirmation.
+//
+// Signatures added via AddSignature or ImportSignature were produced
+// elsewhere and are not confirmed.
+func (c *MultiSignCoordinator) SetConfirmFunc(fn ConfirmFunc, timeout time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.confirm = fn
+	c.confirmTimeout = timeout
+}
+
+// confirmSigning runs the coordinator's ConfirmFunc, if any.
+func (c *MultiSignCoordinator) confirmSigning(ctx context.Context) error {
+	c.mu.RLock()
+	fn, timeout := c.confirm, c.confirmTimeout
+	c.mu.RUnlock()
+
+	if fn == nil {
+		return nil
+	}
+	return crypto.RunConfirm(ctx, timeout, func(ctx context.Context) error {
+		return fn(ctx, c.signDoc)
+	})
+}
@@ -0,0 +1,121 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newConfirmTestSigner returns a fresh ed25519 signer.
+func newConfirmTestSigner(t *testing.T) crypto.Signer {
+	t.Helper()
+	priv, err := crypto.GeneratePrivateKey(crypto.AlgorithmEd25519)
+	require.NoError(t, err)
+	return crypto.NewSigner(priv)
+}
+
+func TestMultiSignCoordinator_ConfirmFunc(t *testing.T) {
+	signer := newConfirmTestSigner(t)
+	sd := testSignDoc()
+
+	coord, err := NewMultiSignCoordinator(sd)
+	require.NoError(t, err)
+
+	var shown *SignDoc
+	coord.SetConfirmFunc(func(_ context.Context, signDoc *SignDoc) error {
+		shown = signDoc
+		return ErrSigningDenied
+	}, time.Second)
+
+	err = coord.SignWithSigner(signer)
+	assert.ErrorIs(t, err, ErrSigningDenied)
+	assert.Equal(t, 0, coord.Count())
+	assert.Same(t, sd, shown)
+
+	coord.SetConfirmFunc(func(ctx context.Context, _ *SignDoc) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, 10*time.Millisecond)
+	err = coord.SignWithSigner(signer)
+	assert.ErrorIs(t, err, ErrConfirmTimeout)
+	assert.Equal(t, 0, coord.Count())
+
+	coord.SetConfirmFunc(func(context.Context, *SignDoc) error { return nil }, 0)
+	require.NoError(t, coord.SignWithSigner(signer))
+	assert.Equal(t, 1, coord.Count())
+}
+
+func TestMultiSignCoordinator_ConfirmContextCancelled(t *testing.T) {
+	coord, err := NewMultiSignCoordinator(testSignDoc())
+	require.NoError(t, err)
+	coord.SetConfirmFunc(func(ctx context.Context, _ *SignDoc) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = coord.SignWithSignerContext(ctx, newConfirmTestSigner(t))
+	assert.ErrorIs(t, err, ErrConfirmTimeout)
+}
+
+func TestKeyringConfirmHook(t *testing.T) {
+	var shown *SignDoc
+	denied := errors.New("rejected in wallet UI")
+	deny := false
+
+	kr := crypto.NewKeyring(crypto.NewMemoryStore(), crypto.WithConfirmHook(
+		KeyringConfirmHook(func(_ context.Context, signDoc *SignDoc) error {
+			shown = signDoc
+			if deny {
+				return denied
+			}
+			return nil
+		}), time.Second))
+	signer, err := kr.NewKey("alice", crypto.AlgorithmEd25519)
+	require.NoError(t, err)
+
+	sd := testSignDoc()
+	sig, err := kr.SignSignDoc("alice", sd)
+	require.NoError(t, err)
+	assert.Same(t, sd, shown)
+
+	signBytes, err := sd.GetSignBytes()
+	require.NoError(t, err)
+	assert.True(t, signer.PublicKey().Verify(signBytes, sig))
+
+	deny = true
+	_, err = kr.SignSignDoc("alice", sd)
+	assert.ErrorIs(t, err, ErrSigningDenied)
+	assert.ErrorIs(t, err, denied)
+
+	// Raw bytes cannot be shown to the user and are denied.
+	deny = false
+	_, err = kr.Sign("alice", signBytes)
+	assert.ErrorIs(t, err, ErrSigningDenied)
+}
+
+// jsonSignDoc is a crypto.PolicySignDoc that is not a *SignDoc.
+type jsonSignDoc struct{ sd *SignDoc }
+
+func (d jsonSignDoc) ToJSON() ([]byte, error)       { return d.sd.ToJSON() }
+func (d jsonSignDoc) GetSignBytes() ([]byte, error) { return d.sd.GetSignBytes() }
+
+func TestKeyringConfirmHook_ParsesForeignSignDoc(t *testing.T) {
+	var shown *SignDoc
+	hook := KeyringConfirmHook(func(_ context.Context, signDoc *SignDoc) error {
+		shown = signDoc
+		return nil
+	})
+
+	sd := testSignDoc()
+	require.NoError(t, hook(context.Background(), crypto.ConfirmRequest{Key: "alice", SignDoc: jsonSignDoc{sd}}))
+	require.NotNil(t, shown)
+	assert.Equal(t, sd.ChainID, shown.ChainID)
+	assert.Equal(t, sd.Messages, shown.Messages)
+}
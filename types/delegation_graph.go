@@ -0,0 +1,252 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxDelegationGraphNodes bounds the accounts a DelegationGraph resolves.
+// Delegations shared by several paths are expanded once per path, so a
+// hostile graph could otherwise grow exponentially with depth.
+const MaxDelegationGraphNodes = 1000
+
+// DelegationStatus describes whether a delegation can contribute weight
+type DelegationStatus string
+
+// Delegation statuses
+const (
+	// DelegationResolved delegations were resolved to their account
+	DelegationResolved DelegationStatus = "resolved"
+
+	// DelegationMissing delegations name an account that does not exist
+	DelegationMissing DelegationStatus = "missing"
+
+	// DelegationCycle delegations name an account already on the path from
+	// the root; authorizations through them are rejected
+	DelegationCycle DelegationStatus = "cycle"
+
+	// DelegationTooDeep delegations lie deeper than MaxRecursionDepth;
+	// authorizations through them are rejected
+	DelegationTooDeep DelegationStatus = "too_deep"
+
+	// DelegationTruncated delegations were not resolved because the graph
+	// reached MaxDelegationGraphNodes
+	DelegationTruncated DelegationStatus = "truncated"
+)
+
+// DelegationKey is a key of an authority
+type DelegationKey struct {
+	// Algorithm is the signature algorithm the key is listed under
+	Algorithm Algorithm `json:"algorithm"`
+
+	// PubKey is the public key bytes
+	PubKey []byte `json:"pub_key"`
+
+	// Weight is the key's weight in the authority
+	Weight uint64 `json:"weight"`
+}
+
+// DelegationEdge is a delegation from an authority to another account
+type DelegationEdge struct {
+	// Account is the delegated account name
+	Account AccountName `json:"account"`
+
+	// Weight is the account's weight in the delegating authority
+	Weight uint64 `json:"weight"`
+
+	// Status tells whether the delegation was resolved
+	Status DelegationStatus `json:"status"`
+
+	// Node is the delegated account's node (nil unless Status is
+	// DelegationResolved)
+	Node *DelegationNode `json:"node,omitempty"`
+}
+
+// DelegationNode is an account's authority within a DelegationGraph
+//
+// INVARIANT: MaxWeight is the sum of the Keys weights and of the weights of
+// the Delegates whose node is Satisfiable, saturating at math.MaxUint64.
+// INVARIANT: Satisfiable == (MaxWeight >= Threshold).
+type DelegationNode struct {
+	// Account is the account name
+	Account AccountName `json:"account"`
+
+	// Depth is the delegation depth of the node (0 for the root)
+	Depth int `json:"depth"`
+
+	// Threshold is the authority's threshold
+	Threshold uint64 `json:"threshold"`
+
+	// Authenticator is the account's authenticator, if any. It replaces the
+	// Authority check only for the account's own transactions; delegations
+	// to the account are always checked against its Authority.
+	Authenticator string `json:"authenticator,omitempty"`
+
+	// Keys are the authority's keys, sorted by key ID
+	Keys []DelegationKey `json:"keys"`
+
+	// Delegates are the authority's delegations, sorted by account name
+	Delegates []DelegationEdge `json:"delegates"`
+
+	// MaxWeight is the weight collected if every key and every satisfiable
+	// delegate signs
+	MaxWeight uint64 `json:"max_weight"`
+
+	// Satisfiable reports whether the authority's threshold can be met
+	Satisfiable bool `json:"satisfiable"`
+}
+
+// DelegationGraph is the resolved tree of authorities that can sign for an
+// account, for display in wallets ("who can sign for this account").
+//
+// An account delegated to along several paths appears once per path.
+type DelegationGraph struct {
+	// Root is the account's node
+	Root *DelegationNode `json:"root"`
+
+	// Depth is the depth of the deepest resolved node
+	Depth int `json:"depth"`
+
+	// Nodes is the number of resolved nodes
+	Nodes int `json:"nodes"`
+
+	// Truncated reports whether delegations were left unresolved because
+	// the graph reached MaxDelegationGraphNodes. Satisfiable is then a lower
+	// bound: truncated delegations count as unsatisfiable.
+	Truncated bool `json:"truncated"`
+}
+
+// Satisfiable reports whether the root account's threshold can be met
+func (g *DelegationGraph) Satisfiable() bool {
+	return g != nil && g.Root != nil && g.Root.Satisfiable
+}
+
+// BuildDelegationGraph resolves the delegation graph of account name from
+// getter, and for each authority whether its threshold can be met by the
+// keys and delegations that verification would accept.
+//
+// Delegates that getter reports as ErrNotFound (or nil) are
+// DelegationMissing. Returns the getter's error for the root account and
+// for any other lookup failure.
+//
+// Complexity: O(MaxDelegationGraphNodes) account lookups and nodes.
+func BuildDelegationGraph(name AccountName, getter AccountGetter) (*DelegationGraph, error) {
+	if getter == nil {
+		return nil, fmt.Errorf("account getter cannot be nil")
+	}
+	if !name.IsValid() {
+		return nil, fmt.Errorf("%w: invalid account name %s", ErrInvalidAccount, name)
+	}
+
+	account, err := getter.GetAccount(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account %s: %w", name, err)
+	}
+	if account == nil {
+		return nil, fmt.Errorf("%w: account %s", ErrNotFound, name)
+	}
+
+	b := &delegationGraphBuilder{
+		getter:  getter,
+		visited: make(map[AccountName]bool),
+		graph:   &DelegationGraph{},
+	}
+	root, err := b.node(account, 0)
+	if err != nil {
+		return nil, err
+	}
+	b.graph.Root = root
+	return b.graph, nil
+}
+
+// delegationGraphBuilder carries the state of BuildDelegationGraph
+type delegationGraphBuilder struct {
+	getter AccountGetter
+
+	// visited holds the accounts on the path from the root
+	visited map[AccountName]bool
+
+	graph *DelegationGraph
+}
+
+// node resolves the node of account at depth, and its delegates.
+// PRECONDITION: depth <= MaxRecursionDepth and the node budget allows it.
+func (b *delegationGraphBuilder) node(account *Account, depth int) (*DelegationNode, error) {
+	b.graph.Nodes++
+	b.graph.Depth = max(b.graph.Depth, depth)
+	b.visited[account.Name] = true
+	defer delete(b.visited, account.Name)
+
+	authority := account.Authority
+	n := &DelegationNode{
+		Account:       account.Name,
+		Depth:         depth,
+		Threshold:     authority.Threshold,
+		Authenticator: account.Authenticator,
+		Keys:          make([]DelegationKey, 0, len(authority.KeyWeights)),
+		Delegates:     make([]DelegationEdge, 0, len(authority.AccountWeights)),
+	}
+
+	for _, kw := range authority.SortedKeyWeights() {
+		algo, pubKey := ParseKeyID(kw.KeyID)
+		n.Keys = append(n.Keys, DelegationKey{Algorithm: algo, PubKey: pubKey, Weight: kw.Weight})
+		n.MaxWeight = addWeight(n.MaxWeight, kw.Weight)
+	}
+
+	for _, aw := range authority.SortedAccountWeights() {
+		edge, err := b.edge(aw, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if edge.Node != nil && edge.Node.Satisfiable {
+			n.MaxWeight = addWeight(n.MaxWeight, edge.Weight)
+		}
+		n.Delegates = append(n.Delegates, edge)
+	}
+
+	n.Satisfiable = n.MaxWeight >= n.Threshold
+	return n, nil
+}
+
+// edge resolves a delegation to a node at depth
+func (b *delegationGraphBuilder) edge(aw AccountWeight, depth int) (DelegationEdge, error) {
+	edge := DelegationEdge{Account: aw.Account, Weight: aw.Weight}
+
+	switch {
+	case b.visited[aw.Account]:
+		edge.Status = DelegationCycle
+		return edge, nil
+	case depth > MaxRecursionDepth:
+		edge.Status = DelegationTooDeep
+		return edge, nil
+	case b.graph.Nodes >= MaxDelegationGraphNodes:
+		edge.Status = DelegationTruncated
+		b.graph.Truncated = true
+		return edge, nil
+	}
+
+	delegate, err := b.getter.GetAccount(aw.Account)
+	if errors.Is(err, ErrNotFound) || (err == nil && delegate == nil) {
+		edge.Status = DelegationMissing
+		return edge, nil
+	}
+	if err != nil {
+		return DelegationEdge{}, fmt.Errorf("failed to get delegated account %s: %w", aw.Account, err)
+	}
+
+	node, err := b.node(delegate, depth)
+	if err != nil {
+		return DelegationEdge{}, err
+	}
+	edge.Status = DelegationResolved
+	edge.Node = node
+	return edge, nil
+}
+
+// addWeight returns a + b, saturating at math.MaxUint64
+func addWeight(a, b uint64) uint64 {
+	if a > ^uint64(0)-b {
+		return ^uint64(0)
+	}
+	return a + b
+}
@@ -0,0 +1,148 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDelegatingAccount returns an account with the given threshold, keys and
+// delegations
+func newDelegatingAccount(name AccountName, threshold uint64, keys map[string]uint64, delegates map[AccountName]uint64) *Account {
+	acc := NewAccount(name, nil)
+	acc.Authority = Authority{Threshold: threshold, KeyWeights: keys, AccountWeights: delegates}
+	return acc
+}
+
+func TestBuildDelegationGraph(t *testing.T) {
+	aliceKey := KeyID(AlgorithmEd25519, []byte("alice-key"))
+	getter := newMockAccountGetter()
+	getter.setAccount(newDelegatingAccount("alice", 2,
+		map[string]uint64{aliceKey: 1},
+		map[AccountName]uint64{"bob": 1, "carol": 1, "ghost": 5}))
+	getter.setAccount(newDelegatingAccount("bob", 1,
+		map[string]uint64{KeyID(AlgorithmEd25519, []byte("bob-key")): 1},
+		map[AccountName]uint64{"alice": 1}))
+	getter.setAccount(newDelegatingAccount("carol", 3,
+		map[string]uint64{KeyID(AlgorithmEd25519, []byte("carol-key")): 1},
+		nil))
+
+	graph, err := BuildDelegationGraph("alice", getter)
+	require.NoError(t, err)
+
+	root := graph.Root
+	require.Equal(t, AccountName("alice"), root.Account)
+	require.Equal(t, []DelegationKey{{Algorithm: AlgorithmEd25519, PubKey: []byte("alice-key"), Weight: 1}}, root.Keys)
+
+	// Delegates are sorted by account name
+	require.Len(t, root.Delegates, 3)
+	bob, carol, ghost := root.Delegates[0], root.Delegates[1], root.Delegates[2]
+
+	assert.Equal(t, DelegationResolved, bob.Status)
+	assert.True(t, bob.Node.Satisfiable)
+	assert.Equal(t, 1, bob.Node.Depth)
+	require.Len(t, bob.Node.Delegates, 1)
+	assert.Equal(t, DelegationCycle, bob.Node.Delegates[0].Status)
+	assert.Nil(t, bob.Node.Delegates[0].Node)
+
+	assert.Equal(t, DelegationResolved, carol.Status)
+	assert.False(t, carol.Node.Satisfiable)
+	assert.Equal(t, uint64(1), carol.Node.MaxWeight)
+
+	assert.Equal(t, DelegationMissing, ghost.Status)
+	assert.Nil(t, ghost.Node)
+
+	// Only alice's key and bob count: carol cannot sign, ghost does not exist
+	assert.Equal(t, uint64(2), root.MaxWeight)
+	assert.True(t, root.Satisfiable)
+	assert.True(t, graph.Satisfiable())
+	assert.Equal(t, 1, graph.Depth)
+	assert.Equal(t, 3, graph.Nodes)
+	assert.False(t, graph.Truncated)
+}
+
+func TestBuildDelegationGraph_TooDeep(t *testing.T) {
+	// d0 -> d1 -> ... -> d11, where only d11 holds a key
+	getter := newMockAccountGetter()
+	for i := 0; i <= MaxRecursionDepth+1; i++ {
+		name := AccountName(fmt.Sprintf("d%d", i))
+		if i == MaxRecursionDepth+1 {
+			getter.setAccount(newDelegatingAccount(name, 1, map[string]uint64{"key": 1}, nil))
+			continue
+		}
+		getter.setAccount(newDelegatingAccount(name, 1, nil,
+			map[AccountName]uint64{AccountName(fmt.Sprintf("d%d", i+1)): 1}))
+	}
+
+	graph, err := BuildDelegationGraph("d0", getter)
+	require.NoError(t, err)
+	assert.Equal(t, MaxRecursionDepth, graph.Depth)
+	assert.Equal(t, MaxRecursionDepth+1, graph.Nodes)
+
+	// Verification would not reach d11's key, so nothing is satisfiable
+	node := graph.Root
+	for node.Depth < MaxRecursionDepth {
+		assert.False(t, node.Satisfiable, "%s", node.Account)
+		node = node.Delegates[0].Node
+	}
+	require.Len(t, node.Delegates, 1)
+	assert.Equal(t, DelegationTooDeep, node.Delegates[0].Status)
+	assert.False(t, graph.Satisfiable())
+}
+
+func TestBuildDelegationGraph_Truncated(t *testing.T) {
+	// Two accounts per layer, each delegating to both accounts of the next
+	// layer, resolve to 2^(MaxRecursionDepth+1) nodes without a budget
+	getter := newMockAccountGetter()
+	for i := 0; i <= MaxRecursionDepth; i++ {
+		delegates := map[AccountName]uint64{}
+		if i < MaxRecursionDepth {
+			delegates[AccountName(fmt.Sprintf("a%d", i+1))] = 1
+			delegates[AccountName(fmt.Sprintf("b%d", i+1))] = 1
+		}
+		for _, prefix := range []string{"a", "b"} {
+			getter.setAccount(newDelegatingAccount(AccountName(fmt.Sprintf("%s%d", prefix, i)), 1,
+				map[string]uint64{"key": 1}, delegates))
+		}
+	}
+
+	graph, err := BuildDelegationGraph("a0", getter)
+	require.NoError(t, err)
+	assert.True(t, graph.Truncated)
+	assert.Equal(t, MaxDelegationGraphNodes, graph.Nodes)
+	assert.True(t, graph.Satisfiable())
+}
+
+func TestBuildDelegationGraph_Errors(t *testing.T) {
+	getter := newMockAccountGetter()
+
+	_, err := BuildDelegationGraph("alice", nil)
+	require.Error(t, err)
+
+	_, err = BuildDelegationGraph("ALICE", getter)
+	require.ErrorIs(t, err, ErrInvalidAccount)
+
+	_, err = BuildDelegationGraph("alice", getter)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// Lookup failures other than ErrNotFound are not reported as missing
+	errBroken := errors.New("broken store")
+	getter.setAccount(newDelegatingAccount("alice", 1, nil, map[AccountName]uint64{"bob": 1}))
+	_, err = BuildDelegationGraph("alice", accountGetterFunc(func(name AccountName) (*Account, error) {
+		if name == "bob" {
+			return nil, errBroken
+		}
+		return getter.GetAccount(name)
+	}))
+	require.ErrorIs(t, err, errBroken)
+}
+
+// accountGetterFunc adapts a function to AccountGetter
+type accountGetterFunc func(name AccountName) (*Account, error)
+
+func (f accountGetterFunc) GetAccount(name AccountName) (*Account, error) {
+	return f(name)
+}
@@ -0,0 +1,144 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MaxDenomExponent is the largest DenomMetadata.Exponent: 10^19 is the
+// largest power of ten that fits in a uint64
+const MaxDenomExponent = 19
+
+// MaxDenomDescriptionLength bounds DenomMetadata.Description
+const MaxDenomDescriptionLength = 256
+
+// DenomMetadata describes how a denomination is displayed to users.
+//
+// For example, base "upun" with display "PUN" and exponent 6 renders
+// 1500000upun as "1.5 PUN".
+type DenomMetadata struct {
+	// Base is the on-chain denomination, e.g. "upun"
+	Base string `json:"base"`
+
+	// Display is the user-facing unit, e.g. "PUN"
+	Display string `json:"display"`
+
+	// Exponent is the number of decimal places between Base and Display:
+	// 1 Display = 10^Exponent Base
+	Exponent uint32 `json:"exponent"`
+
+	// Description is an optional human-readable description
+	Description string `json:"description,omitempty"`
+}
+
+// ValidateBasic performs stateless validation
+func (m DenomMetadata) ValidateBasic() error {
+	if !(Coin{Denom: m.Base}).IsValid() {
+		return fmt.Errorf("%w: invalid base denomination %q", ErrInvalidCoin, m.Base)
+	}
+
+	// Display follows the amount after a space, so it must be a single word
+	if m.Display == "" || len(m.Display) > 64 || strings.IndexFunc(m.Display, unicode.IsSpace) >= 0 {
+		return fmt.Errorf("%w: denomination %s: invalid display name %q", ErrInvalidCoin, m.Base, m.Display)
+	}
+
+	if m.Exponent > MaxDenomExponent {
+		return fmt.Errorf("%w: denomination %s: exponent %d exceeds %d", ErrInvalidCoin, m.Base, m.Exponent, MaxDenomExponent)
+	}
+
+	if len(m.Description) > MaxDenomDescriptionLength {
+		return fmt.Errorf("%w: denomination %s: description exceeds %d bytes", ErrInvalidCoin, m.Base, MaxDenomDescriptionLength)
+	}
+
+	return nil
+}
+
+// FormatAmount renders an amount of Base in Display units without trailing
+// zeros, e.g. "1.5 PUN" for 1500000 with exponent 6.
+//
+// The conversion is exact: amounts are never rounded.
+func (m DenomMetadata) FormatAmount(amount uint64) string {
+	digits := strconv.FormatUint(amount, 10)
+	exp := int(m.Exponent)
+	if exp == 0 {
+		return digits + " " + m.Display
+	}
+
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-exp], strings.TrimRight(digits[len(digits)-exp:], "0")
+	if frac == "" {
+		return whole + " " + m.Display
+	}
+	return whole + "." + frac + " " + m.Display
+}
+
+// FormatCoin renders coin in Display units, or as coin.String() if coin is
+// not in Base
+func (m DenomMetadata) FormatCoin(coin Coin) string {
+	if coin.Denom != m.Base {
+		return coin.String()
+	}
+	return m.FormatAmount(coin.Amount)
+}
+
+// DenomMetadataLookup returns the metadata of a base denomination, if known.
+// client.ChainConfig.Denom is a DenomMetadataLookup.
+type DenomMetadataLookup func(base string) (DenomMetadata, bool)
+
+// NewDenomMetadataLookup returns a lookup over metadata. A later entry for
+// the same base replaces an earlier one.
+func NewDenomMetadataLookup(metadata ...DenomMetadata) DenomMetadataLookup {
+	byBase := make(map[string]DenomMetadata, len(metadata))
+	for _, m := range metadata {
+		byBase[m.Base] = m
+	}
+	return func(base string) (DenomMetadata, bool) {
+		m, ok := byBase[base]
+		return m, ok
+	}
+}
+
+// FormatCoin renders coin in display units if lookup knows its denomination,
+// otherwise as coin.String(). A nil lookup knows no denomination.
+func FormatCoin(coin Coin, lookup DenomMetadataLookup) string {
+	if lookup == nil {
+		return coin.String()
+	}
+	m, ok := lookup(coin.Denom)
+	if !ok {
+		return coin.String()
+	}
+	return m.FormatCoin(coin)
+}
+
+// FormatCoins renders coins with FormatCoin, separated by ", "
+func FormatCoins(coins Coins, lookup DenomMetadataLookup) string {
+	parts := make([]string, len(coins))
+	for i, coin := range coins {
+		parts[i] = FormatCoin(coin, lookup)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatSignDocCoins renders SignDoc coins (e.g. SignDoc.Fee.Amount) for a
+// signer to review, like FormatCoins.
+//
+// SECURITY: Display formatting is for humans only; the signer signs the
+// SignDoc's base amounts. Metadata from an untrusted source can make an
+// amount look smaller than it is, so lookups should come from chain state
+// or a trusted chain registry.
+func FormatSignDocCoins(coins []SignDocCoin, lookup DenomMetadataLookup) (string, error) {
+	parts := make([]string, len(coins))
+	for i, c := range coins {
+		amount, err := strconv.ParseUint(c.Amount, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("%w: invalid amount %q for %s", ErrInvalidCoin, c.Amount, c.Denom)
+		}
+		parts[i] = FormatCoin(NewCoin(c.Denom, amount), lookup)
+	}
+	return strings.Join(parts, ", "), nil
+}
@@ -0,0 +1,78 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPunMetadata = DenomMetadata{Base: "upun", Display: "PUN", Exponent: 6, Description: "The punnet staking token"}
+
+func TestDenomMetadata_ValidateBasic(t *testing.T) {
+	tests := []struct {
+		name  string
+		edit  func(m *DenomMetadata)
+		valid bool
+	}{
+		{"valid", func(m *DenomMetadata) {}, true},
+		{"zero exponent", func(m *DenomMetadata) { m.Exponent = 0 }, true},
+		{"max exponent", func(m *DenomMetadata) { m.Exponent = MaxDenomExponent }, true},
+		{"exponent too large", func(m *DenomMetadata) { m.Exponent = MaxDenomExponent + 1 }, false},
+		{"empty base", func(m *DenomMetadata) { m.Base = "" }, false},
+		{"empty display", func(m *DenomMetadata) { m.Display = "" }, false},
+		{"display with space", func(m *DenomMetadata) { m.Display = "PUN X" }, false},
+		{"long description", func(m *DenomMetadata) { m.Description = strings.Repeat("a", MaxDenomDescriptionLength+1) }, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := testPunMetadata
+			tc.edit(&m)
+			err := m.ValidateBasic()
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrInvalidCoin)
+			}
+		})
+	}
+}
+
+func TestDenomMetadata_FormatAmount(t *testing.T) {
+	tests := []struct {
+		exponent uint32
+		amount   uint64
+		want     string
+	}{
+		{6, 1500000, "1.5 PUN"},
+		{6, 1000000, "1 PUN"},
+		{6, 1, "0.000001 PUN"},
+		{6, 0, "0 PUN"},
+		{6, 123456789, "123.456789 PUN"},
+		{0, 1500000, "1500000 PUN"},
+		{MaxDenomExponent, ^uint64(0), "1.8446744073709551615 PUN"},
+	}
+	for _, tc := range tests {
+		m := testPunMetadata
+		m.Exponent = tc.exponent
+		assert.Equal(t, tc.want, m.FormatAmount(tc.amount))
+	}
+}
+
+func TestFormatCoins(t *testing.T) {
+	lookup := NewDenomMetadataLookup(testPunMetadata)
+
+	assert.Equal(t, "1.5 PUN", FormatCoin(NewCoin("upun", 1500000), lookup))
+	assert.Equal(t, "7uatom", FormatCoin(NewCoin("uatom", 7), lookup), "unknown denom")
+	assert.Equal(t, "1500000upun", FormatCoin(NewCoin("upun", 1500000), nil))
+	assert.Equal(t, "7uatom, 2.5 PUN", FormatCoins(NewCoins(NewCoin("uatom", 7), NewCoin("upun", 2500000)), lookup))
+	assert.Equal(t, "", FormatCoins(nil, lookup))
+
+	fee, err := FormatSignDocCoins([]SignDocCoin{{Denom: "upun", Amount: "5000"}}, lookup)
+	require.NoError(t, err)
+	assert.Equal(t, "0.005 PUN", fee)
+
+	_, err = FormatSignDocCoins([]SignDocCoin{{Denom: "upun", Amount: "-1"}}, lookup)
+	require.ErrorIs(t, err, ErrInvalidCoin)
+}
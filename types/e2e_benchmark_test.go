@@ -27,7 +27,10 @@ func BenchmarkFullSignFlow_Ed25519_Small(b *testing.B) {
 
 	for i := 0; i < b.N; i++ {
 		// Create SignDoc
-		sd := NewSignDoc("test-chain", uint64(i), "alice", uint64(i), "")
+		sd, err := NewSignDoc("test-chain", uint64(i), "alice", uint64(i), "")
+		if err != nil {
+			b.Fatalf("NewSignDoc failed: %v", err)
+		}
 		sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"from":"alice","to":"bob","amount":"100"}`))
 
 		// Get sign bytes (ToJSON + SHA256)
@@ -53,7 +56,10 @@ func BenchmarkFullSignFlow_Ed25519_Medium(b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		sd := NewSignDoc("mainnet-production", uint64(i), "cosmos1abc...xyz", uint64(i), "batch transfer")
+		sd, err := NewSignDoc("mainnet-production", uint64(i), "cosmos1abc...xyz", uint64(i), "batch transfer")
+		if err != nil {
+			b.Fatalf("NewSignDoc failed: %v", err)
+		}
 		sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"from":"cosmos1abc...xyz","to":"cosmos1def...uvw","amount":{"denom":"stake","amount":"1000000"}}`))
 		sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"from":"cosmos1abc...xyz","to":"cosmos1ghi...rst","amount":{"denom":"stake","amount":"500000"}}`))
 		sd.AddMessage("/punnet.staking.v1.MsgDelegate", json.RawMessage(`{"delegator":"cosmos1abc...xyz","validator":"cosmosvaloper1xyz...","amount":{"denom":"stake","amount":"2000000"}}`))
@@ -80,7 +86,10 @@ func BenchmarkFullSignFlow_Ed25519_Large(b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		sd := NewSignDoc("mainnet-production-chain-id", uint64(i), "cosmos1verylongaddress", uint64(i), memo)
+		sd, err := NewSignDoc("mainnet-production-chain-id", uint64(i), "cosmos1verylongaddress", uint64(i), memo)
+		if err != nil {
+			b.Fatalf("NewSignDoc failed: %v", err)
+		}
 		for j := 0; j < 10; j++ {
 			data := fmt.Sprintf(`{"from":"cosmos1sender%d","to":"cosmos1receiver%d","amount":{"denom":"ustake","amount":"%d"}}`, j, j, j*1000000)
 			sd.AddMessage(fmt.Sprintf("/punnet.bank.v1.MsgSend%d", j), json.RawMessage(data))
@@ -113,7 +122,10 @@ func BenchmarkFullVerifyFlow_Ed25519_Small(b *testing.B) {
 	}
 	pubKey := key.PublicKey()
 
-	sd := NewSignDoc("test-chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+	if err != nil {
+		b.Fatalf("NewSignDoc failed: %v", err)
+	}
 	sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"from":"alice","to":"bob","amount":"100"}`))
 
 	jsonBytes, err := sd.ToJSON()
@@ -164,7 +176,10 @@ func BenchmarkFullVerifyFlow_Ed25519_Medium(b *testing.B) {
 	}
 	pubKey := key.PublicKey()
 
-	sd := NewSignDoc("mainnet-production", 12345, "cosmos1abc...xyz", 12345, "batch transfer")
+	sd, err := NewSignDoc("mainnet-production", 12345, "cosmos1abc...xyz", 12345, "batch transfer")
+	if err != nil {
+		b.Fatalf("NewSignDoc failed: %v", err)
+	}
 	sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"from":"cosmos1abc...xyz","to":"cosmos1def...uvw","amount":{"denom":"stake","amount":"1000000"}}`))
 	sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"from":"cosmos1abc...xyz","to":"cosmos1ghi...rst","amount":{"denom":"stake","amount":"500000"}}`))
 	sd.AddMessage("/punnet.staking.v1.MsgDelegate", json.RawMessage(`{"delegator":"cosmos1abc...xyz","validator":"cosmosvaloper1xyz...","amount":{"denom":"stake","amount":"2000000"}}`))
@@ -214,7 +229,10 @@ func BenchmarkFullVerifyFlow_Ed25519_Large(b *testing.B) {
 	pubKey := key.PublicKey()
 
 	memo := strings.Repeat("x", 512)
-	sd := NewSignDoc("mainnet-production-chain-id", 999999999, "cosmos1verylongaddress", 999999999, memo)
+	sd, err := NewSignDoc("mainnet-production-chain-id", 999999999, "cosmos1verylongaddress", 999999999, memo)
+	if err != nil {
+		b.Fatalf("NewSignDoc failed: %v", err)
+	}
 	for j := 0; j < 10; j++ {
 		data := fmt.Sprintf(`{"from":"cosmos1sender%d","to":"cosmos1receiver%d","amount":{"denom":"ustake","amount":"%d"}}`, j, j, j*1000000)
 		sd.AddMessage(fmt.Sprintf("/punnet.bank.v1.MsgSend%d", j), json.RawMessage(data))
@@ -268,12 +286,17 @@ func BenchmarkIsolated_NewSignDoc(b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		_ = NewSignDoc("test-chain", uint64(i), "alice", uint64(i), "memo")
+		if _, err := NewSignDoc("test-chain", uint64(i), "alice", uint64(i), "memo"); err != nil {
+			b.Fatalf("NewSignDoc failed: %v", err)
+		}
 	}
 }
 
 func BenchmarkIsolated_AddMessage(b *testing.B) {
-	sd := NewSignDoc("test-chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+	if err != nil {
+		b.Fatalf("NewSignDoc failed: %v", err)
+	}
 	msg := json.RawMessage(`{"from":"alice","to":"bob","amount":"100"}`)
 	b.ReportAllocs()
 	b.ResetTimer()
@@ -439,7 +462,10 @@ func BenchmarkMemoryPressure_BatchVerification(b *testing.B) {
 		keys[i] = key
 		pubKeys[i] = key.PublicKey()
 
-		sd := NewSignDoc("test-chain", uint64(i), fmt.Sprintf("account%d", i), uint64(i), "")
+		sd, err := NewSignDoc("test-chain", uint64(i), fmt.Sprintf("account%d", i), uint64(i), "")
+		if err != nil {
+			b.Fatalf("NewSignDoc failed: %v", err)
+		}
 		sd.AddMessage("/msg", json.RawMessage(`{}`))
 		signBytes, err := sd.GetSignBytes()
 		if err != nil {
@@ -522,7 +548,10 @@ func BenchmarkMemoryPressure_BatchSigning(b *testing.B) {
 	signBytesArr := make([][]byte, batchSize)
 
 	for i := 0; i < batchSize; i++ {
-		sd := NewSignDoc("test-chain", uint64(i), fmt.Sprintf("account%d", i), uint64(i), "")
+		sd, err := NewSignDoc("test-chain", uint64(i), fmt.Sprintf("account%d", i), uint64(i), "")
+		if err != nil {
+			b.Fatalf("NewSignDoc failed: %v", err)
+		}
 		sd.AddMessage("/msg", json.RawMessage(`{}`))
 		signBytes, signBytesErr := sd.GetSignBytes()
 		if signBytesErr != nil {
@@ -77,4 +77,83 @@ var (
 	// SECURITY: Rejecting unknown versions prevents forward-compatibility attacks
 	// where nodes with different version support might interpret transactions differently.
 	ErrUnsupportedVersion = errors.New("unsupported SignDoc version")
+
+	// ErrInvalidSession indicates a malformed or unverifiable session key authorization.
+	ErrInvalidSession = errors.New("invalid session authorization")
+
+	// ErrSessionExpired indicates a session key used at or after its expiration height.
+	ErrSessionExpired = errors.New("session key expired")
+
+	// ErrSessionMessageNotAllowed indicates a transaction message outside the
+	// session grant's allowed message types.
+	ErrSessionMessageNotAllowed = errors.New("message type not allowed for session key")
+
+	// ErrExtensionSchemaMismatch indicates a stored account extension whose
+	// schema version the reading code cannot decode.
+	ErrExtensionSchemaMismatch = errors.New("account extension schema mismatch")
+
+	// ErrTxTooLarge indicates an encoded transaction exceeding the size limit
+	ErrTxTooLarge = errors.New("transaction too large")
+
+	// ErrTooManySignatures indicates an authorization exceeding the signature limit
+	ErrTooManySignatures = errors.New("too many signatures")
+
+	// ErrTxNotYetValid indicates a transaction executed before its authorization's NotBefore
+	ErrTxNotYetValid = errors.New("transaction not yet valid")
+
+	// ErrTxExpired indicates a transaction executed after its authorization's NotAfter
+	ErrTxExpired = errors.New("transaction expired")
+
+	// ErrNonCanonicalAccount indicates account bytes that are not the
+	// canonical encoding produced by Account.CanonicalJSON.
+	ErrNonCanonicalAccount = errors.New("non-canonical account encoding")
+
+	// ErrInvalidChainID indicates a chain ID outside the grammar of ValidateChainID
+	ErrInvalidChainID = errors.New("invalid chain ID")
+
+	// ErrInvalidMemo indicates a memo violating the memo policy (see MemoPolicy)
+	ErrInvalidMemo = errors.New("invalid memo")
+)
+
+// SignDoc validation errors. SignDoc.ValidateBasic wraps these together with
+// ErrSignDocMismatch so callers can tell the failed rule apart with errors.Is.
+var (
+	// ErrEmptyField indicates a required SignDoc field that is empty
+	ErrEmptyField = errors.New("required field is empty")
+
+	// ErrNotNFC indicates a SignDoc string that is not Unicode NFC-normalized
+	ErrNotNFC = errors.New("not Unicode NFC-normalized")
+
+	// ErrNotDecimal indicates a numeric SignDoc field that is not a
+	// non-negative decimal integer string
+	ErrNotDecimal = errors.New("must be a decimal string")
+
+	// ErrZeroDenominator indicates a SignDoc ratio with a zero denominator
+	ErrZeroDenominator = errors.New("denominator cannot be zero")
+
+	// ErrNoMessages indicates a SignDoc without messages
+	ErrNoMessages = errors.New("SignDoc must contain at least one message")
+
+	// ErrTooManyMessages indicates a SignDoc exceeding MaxMessagesPerSignDoc
+	ErrTooManyMessages = errors.New("too many messages")
+
+	// ErrTooManyFeeCoins indicates a fee exceeding MaxFeeCoins
+	ErrTooManyFeeCoins = errors.New("too many fee coins")
+
+	// ErrDataTooLarge indicates message data exceeding MaxMessageDataSize
+	ErrDataTooLarge = errors.New("data too large")
+
+	// ErrNonCompactData indicates message data with whitespace outside strings
+	ErrNonCompactData = errors.New("data is not compact JSON")
+
+	// ErrFieldTooLong indicates a bounded SignDoc string field (e.g. a denom)
+	// exceeding its length limit
+	ErrFieldTooLong = errors.New("field too long")
+
+	// ErrInvalidTip indicates a tip that is zero, malformed, or not matching
+	// the SignDoc version
+	ErrInvalidTip = errors.New("invalid tip")
+
+	// ErrInvalidSigners indicates a malformed SignDoc signer list
+	ErrInvalidSigners = errors.New("invalid signers")
 )
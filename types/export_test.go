@@ -0,0 +1,4 @@
+package types
+
+// Exported for the external tests in package types_test
+var SignDocFixtures = getSignDocFixtures
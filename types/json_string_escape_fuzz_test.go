@@ -288,7 +288,10 @@ func FuzzEscapeJSONString_SignDocContext(f *testing.F) {
 		}
 
 		// Create a SignDoc with the fuzzed memo
-		sd := NewSignDoc("chain-1", 1, "alice", 1, memo)
+		sd, err := NewSignDoc("chain-1", 1, "alice", 1, memo)
+		if err != nil {
+			t.Fatalf("NewSignDoc failed: %v", err)
+		}
 		sd.AddMessage("/msg.Test", json.RawMessage(`{}`))
 
 		// SECURITY INVARIANT: ToJSON must not panic
@@ -0,0 +1,158 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxMemoBytes is the hard limit on memo size, enforced by
+// Transaction.ValidateBasic whatever the memo policy
+const MaxMemoBytes = 512
+
+// MemoPolicy restricts transaction memos.
+//
+// SignDoc.ValidateBasic enforces DefaultMemoPolicy. Chains choose their own
+// policy for transactions with runtime.NewMemoAnteHandler.
+type MemoPolicy struct {
+	// MaxBytes is the maximum memo size in bytes, at most MaxMemoBytes.
+	// Zero means MaxMemoBytes.
+	MaxBytes int
+
+	// RequireUTF8 rejects memos that are not valid UTF-8
+	RequireUTF8 bool
+
+	// RejectControlChars rejects memos containing control characters
+	// (Unicode category Cc, including tab and line feed)
+	RejectControlChars bool
+}
+
+// DefaultMemoPolicy returns the policy SignDoc.ValidateBasic enforces:
+// memos of at most MaxMemoBytes bytes of UTF-8 text without control
+// characters.
+//
+// SECURITY: Control characters can rewrite what a terminal or wallet shows
+// around the memo (carriage returns, escape sequences), so a signer could be
+// shown a different transaction than the one signed.
+func DefaultMemoPolicy() MemoPolicy {
+	return MemoPolicy{
+		MaxBytes:           MaxMemoBytes,
+		RequireUTF8:        true,
+		RejectControlChars: true,
+	}
+}
+
+// ValidateBasic checks that the policy is usable
+func (p MemoPolicy) ValidateBasic() error {
+	if p.MaxBytes < 0 || p.MaxBytes > MaxMemoBytes {
+		return fmt.Errorf("memo max bytes must be in [0, %d], got %d", MaxMemoBytes, p.MaxBytes)
+	}
+	return nil
+}
+
+// Check returns ErrInvalidMemo if memo violates the policy.
+//
+// Complexity: O(n) for a memo of n bytes; oversized memos are rejected
+// before their content is inspected.
+func (p MemoPolicy) Check(memo string) error {
+	maxBytes := p.MaxBytes
+	if maxBytes == 0 {
+		maxBytes = MaxMemoBytes
+	}
+	if len(memo) > maxBytes {
+		return fmt.Errorf("%w: %d bytes (max %d)", ErrInvalidMemo, len(memo), maxBytes)
+	}
+
+	if p.RequireUTF8 && !utf8.ValidString(memo) {
+		return fmt.Errorf("%w: not valid UTF-8", ErrInvalidMemo)
+	}
+
+	if p.RejectControlChars {
+		for i, r := range memo {
+			if unicode.IsControl(r) {
+				return fmt.Errorf("%w: control character %U at byte %d", ErrInvalidMemo, r, i)
+			}
+		}
+	}
+	return nil
+}
+
+// MemoTypeKey is the JSON object member naming the type of a structured memo
+const MemoTypeKey = "type"
+
+// StructuredMemo is a machine-readable memo: a JSON object whose "type"
+// member names its format, e.g.
+//
+//	{"type":"exchange.deposit","tag":"12345"}
+type StructuredMemo struct {
+	// Type is the memo type (see IsValidMemoType)
+	Type string
+
+	// Raw is the whole JSON object, including the type member
+	Raw json.RawMessage
+}
+
+// Unmarshal decodes the memo's JSON object into v
+func (m *StructuredMemo) Unmarshal(v any) error {
+	return json.Unmarshal(m.Raw, v)
+}
+
+// IsValidMemoType reports whether memoType is a valid structured memo type:
+// 1 to 64 characters of [a-z0-9._/-]
+func IsValidMemoType(memoType string) bool {
+	return len(memoType) > 0 && len(memoType) <= 64 && authenticatorNamePattern.MatchString(memoType)
+}
+
+// ParseStructuredMemo parses memo as a structured memo. It returns false for
+// plain-text memos: anything that is not a JSON object with a string "type"
+// member, including other JSON such as ICS-20 style memos keyed by module.
+//
+// Returns ErrInvalidMemo for a JSON object whose type member is not a valid
+// memo type or that repeats a top-level member.
+//
+// SECURITY: JSON parsers disagree on which of several members with the same
+// name wins, so objects with duplicate top-level members are rejected rather
+// than routed by a type other tools may not see.
+func ParseStructuredMemo(memo string) (*StructuredMemo, bool, error) {
+	trimmed := strings.TrimSpace(memo)
+	if !strings.HasPrefix(trimmed, "{") || !json.Valid([]byte(trimmed)) {
+		return nil, false, nil
+	}
+
+	dec := json.NewDecoder(strings.NewReader(trimmed))
+	if _, err := dec.Token(); err != nil { // opening brace
+		return nil, false, nil
+	}
+	members := make(map[string]json.RawMessage)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, false, nil
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, false, nil
+		}
+		if _, exists := members[key]; exists {
+			return nil, false, fmt.Errorf("%w: duplicate member %q in structured memo", ErrInvalidMemo, key)
+		}
+		members[key] = value
+	}
+
+	rawType, ok := members[MemoTypeKey]
+	if !ok {
+		return nil, false, nil
+	}
+	var memoType string
+	if err := json.Unmarshal(rawType, &memoType); err != nil {
+		return nil, false, nil
+	}
+	if !IsValidMemoType(memoType) {
+		return nil, false, fmt.Errorf("%w: invalid structured memo type %q", ErrInvalidMemo, memoType)
+	}
+
+	return &StructuredMemo{Type: memoType, Raw: json.RawMessage(trimmed)}, true, nil
+}
@@ -0,0 +1,119 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoPolicy_Check(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy MemoPolicy
+		memo   string
+		valid  bool
+	}{
+		{"empty", DefaultMemoPolicy(), "", true},
+		{"text", DefaultMemoPolicy(), "invoice #42 – café 🚀", true},
+		{"at limit", DefaultMemoPolicy(), strings.Repeat("a", MaxMemoBytes), true},
+		{"over limit", DefaultMemoPolicy(), strings.Repeat("a", MaxMemoBytes+1), false},
+		{"newline", DefaultMemoPolicy(), "line\nbreak", false},
+		{"carriage return", DefaultMemoPolicy(), "pay bob\rpay eve", false},
+		{"escape sequence", DefaultMemoPolicy(), "\x1b[2J", false},
+		{"c1 control", DefaultMemoPolicy(), "a\u0085b", false},
+		{"invalid utf-8", DefaultMemoPolicy(), "a\xffb", false},
+
+		{"custom limit", MemoPolicy{MaxBytes: 4}, "abcde", false},
+		{"zero limit means max", MemoPolicy{}, strings.Repeat("a", MaxMemoBytes), true},
+		{"control chars allowed", MemoPolicy{}, "line\nbreak", true},
+		{"invalid utf-8 allowed", MemoPolicy{}, "a\xffb", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Check(tt.memo)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidMemo)
+			}
+		})
+	}
+}
+
+func TestMemoPolicy_ValidateBasic(t *testing.T) {
+	assert.NoError(t, DefaultMemoPolicy().ValidateBasic())
+	assert.NoError(t, MemoPolicy{}.ValidateBasic())
+	assert.Error(t, MemoPolicy{MaxBytes: -1}.ValidateBasic())
+	assert.Error(t, MemoPolicy{MaxBytes: MaxMemoBytes + 1}.ValidateBasic())
+}
+
+func TestSignDoc_ValidateBasic_Memo(t *testing.T) {
+	sd, err := NewSignDoc("punnet-1", 0, "alice", 0, "pay bob\rpay eve")
+	require.NoError(t, err)
+	sd.AddMessage("/test.Msg", []byte(`{}`))
+	err = sd.ValidateBasic()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidMemo)
+	assert.ErrorIs(t, err, ErrSignDocMismatch)
+
+	sd.Memo = strings.Repeat("a", MaxMemoBytes+1)
+	assert.ErrorIs(t, sd.ValidateBasic(), ErrInvalidMemo)
+
+	sd.Memo = "pay bob"
+	assert.NoError(t, sd.ValidateBasic())
+}
+
+func TestParseStructuredMemo(t *testing.T) {
+	tests := []struct {
+		name       string
+		memo       string
+		structured bool
+		wantType   string
+		wantErr    bool
+	}{
+		{"empty", "", false, "", false},
+		{"plain text", "invoice 42", false, "", false},
+		{"brace plain text", "{not json", false, "", false},
+		{"json array", `["type"]`, false, "", false},
+		{"ics-20 style", `{"wasm":{"contract":"c"}}`, false, "", false},
+		{"non-string type", `{"type":1}`, false, "", false},
+		{"typed", `{"type":"exchange.deposit","tag":"12345"}`, true, "exchange.deposit", false},
+		{"surrounding space", ` {"type":"ibc/callback"} `, true, "ibc/callback", false},
+		{"invalid type", `{"type":"Exchange Deposit"}`, false, "", true},
+		{"empty type", `{"type":""}`, false, "", true},
+		{"duplicate type", `{"type":"a","type":"b"}`, false, "", true},
+		{"duplicate member", `{"type":"a","tag":"1","tag":"2"}`, false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memo, ok, err := ParseStructuredMemo(tt.memo)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidMemo)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.structured, ok)
+			if ok {
+				assert.Equal(t, tt.wantType, memo.Type)
+			}
+		})
+	}
+}
+
+func TestStructuredMemo_Unmarshal(t *testing.T) {
+	memo, ok, err := ParseStructuredMemo(`{"type":"exchange.deposit","tag":"12345"}`)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	var deposit struct {
+		Type string `json:"type"`
+		Tag  string `json:"tag"`
+	}
+	require.NoError(t, memo.Unmarshal(&deposit))
+	assert.Equal(t, "exchange.deposit", deposit.Type)
+	assert.Equal(t, "12345", deposit.Tag)
+}
@@ -1,8 +1,10 @@
 package types
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/blockberries/punnet-sdk/crypto"
 )
@@ -22,6 +24,10 @@ type MultiSignCoordinator struct {
 	mu         sync.RWMutex
 	signDoc    *SignDoc
 	signatures []Signature
+
+	// confirm is called before SignWithSigner signs (see SetConfirmFunc)
+	confirm        ConfirmFunc
+	confirmTimeout time.Duration
 }
 
 // NewMultiSignCoordinator creates a new coordinator for collecting signatures
@@ -79,15 +85,7 @@ func (c *MultiSignCoordinator) AddSignature(sig Signature) error {
 	}
 
 	// Deep copy signature to prevent external mutation
-	sigCopy := Signature{
-		Algorithm: sig.Algorithm,
-		PubKey:    make([]byte, len(sig.PubKey)),
-		Signature: make([]byte, len(sig.Signature)),
-	}
-	copy(sigCopy.PubKey, sig.PubKey)
-	copy(sigCopy.Signature, sig.Signature)
-
-	c.signatures = append(c.signatures, sigCopy)
+	c.signatures = append(c.signatures, sig.clone())
 	return nil
 }
 
@@ -99,10 +97,24 @@ func (c *MultiSignCoordinator) AddSignature(sig Signature) error {
 //
 // Complexity: O(m + n) where m is SignDoc size and n is existing signature count
 func (c *MultiSignCoordinator) SignWithSigner(signer crypto.Signer) error {
+	return c.SignWithSignerContext(context.Background(), signer)
+}
+
+// SignWithSignerContext is SignWithSigner with a caller-supplied context,
+// which bounds the ConfirmFunc (if any) in addition to its timeout.
+//
+// Returns an error matching ErrSigningDenied or ErrConfirmTimeout if
+// confirmation fails; no signature is produced in that case.
+func (c *MultiSignCoordinator) SignWithSignerContext(ctx context.Context, signer crypto.Signer) error {
 	if signer == nil {
 		return fmt.Errorf("signer cannot be nil")
 	}
 
+	// Confirm before signing; never hold the lock while waiting on a user.
+	if err := c.confirmSigning(ctx); err != nil {
+		return err
+	}
+
 	// Get sign bytes outside the lock to avoid holding it during crypto ops
 	signBytes, err := c.signDoc.GetSignBytes()
 	if err != nil {
@@ -191,13 +203,7 @@ func (c *MultiSignCoordinator) Signatures() []Signature {
 	// Deep copy to prevent external mutation
 	result := make([]Signature, len(c.signatures))
 	for i, sig := range c.signatures {
-		result[i] = Signature{
-			Algorithm: sig.Algorithm,
-			PubKey:    make([]byte, len(sig.PubKey)),
-			Signature: make([]byte, len(sig.Signature)),
-		}
-		copy(result[i].PubKey, sig.PubKey)
-		copy(result[i].Signature, sig.Signature)
+		result[i] = sig.clone()
 	}
 	return result
 }
@@ -206,6 +212,7 @@ func (c *MultiSignCoordinator) Signatures() []Signature {
 // Does NOT verify that sufficient signatures have been collected.
 //
 // POSTCONDITION: Returned Authorization contains deep copies of all signatures
+// POSTCONDITION: Returned Authorization carries the SignDoc's validity window
 //
 // Complexity: O(n) where n is signature count (deep copy via NewAuthorization)
 func (c *MultiSignCoordinator) Complete() *Authorization {
@@ -213,7 +220,100 @@ func (c *MultiSignCoordinator) Complete() *Authorization {
 	defer c.mu.RUnlock()
 
 	// NewAuthorization creates defensive deep copies
-	return NewAuthorization(c.signatures...)
+	return c.withValidityWindow(NewAuthorization(c.signatures...))
+}
+
+// CompleteMultisig returns an Authorization satisfying the multisig described
+// by desc, built from the collected signatures.
+//
+// Every collected signature must come from a member of desc and verify
+// against the SignDoc. Signatures are ordered by member position, so the
+// result is independent of the order in which members signed.
+//
+// Returns ErrInvalidAuthority if desc is invalid.
+// Returns ErrUnauthorized if a signature is from a key outside desc.
+// Returns ErrInvalidSignature if a signature does not verify.
+// Returns ErrInsufficientWeight if the signers' weight is below the threshold.
+//
+// Complexity: O(n*k + n*v) where n is signature count, k is member count and
+// v is the cost of one signature verification
+func (c *MultiSignCoordinator) CompleteMultisig(desc *crypto.MultisigDescriptor) (*Authorization, error) {
+	if err := desc.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAuthority, err)
+	}
+
+	signBytes, err := c.signDoc.GetSignBytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sign bytes: %w", err)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	byMember := make([]*Signature, len(desc.Members))
+	for i := range c.signatures {
+		sig := &c.signatures[i]
+		idx := desc.MemberIndex(sig.GetAlgorithm(), sig.PubKey)
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: signature %d is not from a multisig member", ErrUnauthorized, i)
+		}
+		if !sig.Verify(signBytes) {
+			return nil, fmt.Errorf("%w: signature from member %d does not verify", ErrInvalidSignature, idx)
+		}
+		byMember[idx] = sig
+	}
+
+	var weight uint64
+	ordered := make([]Signature, 0, len(c.signatures))
+	for i, sig := range byMember {
+		if sig == nil {
+			continue
+		}
+		// Cannot overflow: ValidateBasic bounds the total member weight
+		weight += desc.Members[i].Weight
+		ordered = append(ordered, *sig)
+	}
+	if weight < desc.Threshold {
+		return nil, fmt.Errorf("%w: have %d, need %d", ErrInsufficientWeight, weight, desc.Threshold)
+	}
+
+	// NewAuthorization creates defensive deep copies
+	return c.withValidityWindow(NewAuthorization(ordered...)), nil
+}
+
+// withValidityWindow sets auth's validity window to the SignDoc's, which
+// the collected signatures cover.
+// PRECONDITION: c.mu is held
+func (c *MultiSignCoordinator) withValidityWindow(auth *Authorization) *Authorization {
+	auth.NotBefore = c.signDoc.NotBefore.clone()
+	auth.NotAfter = c.signDoc.NotAfter.clone()
+	return auth
+}
+
+// NewMultisigAuthority returns the Authority of an account controlled by the
+// multisig described by desc: each member key weighted as in desc, with
+// desc's threshold and no account delegations.
+//
+// Returns ErrInvalidAuthority if desc is invalid.
+//
+// Complexity: O(k) where k is member count
+func NewMultisigAuthority(desc *crypto.MultisigDescriptor) (Authority, error) {
+	if err := desc.ValidateBasic(); err != nil {
+		return Authority{}, fmt.Errorf("%w: %v", ErrInvalidAuthority, err)
+	}
+
+	authority := Authority{
+		Threshold:      desc.Threshold,
+		KeyWeights:     make(map[string]uint64, len(desc.Members)),
+		AccountWeights: make(map[AccountName]uint64),
+	}
+	for _, m := range desc.Members {
+		authority.KeyWeights[KeyID(m.Algorithm, m.PubKey)] = m.Weight
+	}
+	if err := authority.ValidateBasic(); err != nil {
+		return Authority{}, err
+	}
+	return authority, nil
 }
 
 // Reset clears all collected signatures, allowing the coordinator to be reused.
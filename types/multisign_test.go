@@ -11,7 +11,7 @@ import (
 
 // testSignDoc creates a valid SignDoc for testing.
 func testSignDoc() *SignDoc {
-	sd := NewSignDoc("test-chain", 1, "alice", 1, "test memo")
+	sd := mustNewSignDoc("test-chain", 1, "alice", 1, "test memo")
 	sd.AddMessage("/test.msg", []byte(`{"amount":"100"}`))
 	return sd
 }
@@ -653,3 +653,119 @@ func BenchmarkMultiSignCoordinator_Complete(b *testing.B) {
 		_ = coord.Complete()
 	}
 }
+
+// newTestMultisig returns a 2-of-3 descriptor (weights 2, 1, 1) and its
+// members' private keys
+func newTestMultisig(t *testing.T) (*crypto.MultisigDescriptor, []crypto.PrivateKey) {
+	t.Helper()
+	desc := &crypto.MultisigDescriptor{Threshold: 2}
+	var privs []crypto.PrivateKey
+	for i, weight := range []uint64{2, 1, 1} {
+		priv, err := crypto.GeneratePrivateKey(crypto.AlgorithmEd25519)
+		require.NoError(t, err)
+		privs = append(privs, priv)
+		desc.Members = append(desc.Members, crypto.MultisigMember{
+			Name:      string(rune('a' + i)),
+			Algorithm: crypto.AlgorithmEd25519,
+			PubKey:    priv.PublicKey().Bytes(),
+			Weight:    weight,
+		})
+	}
+	return desc, privs
+}
+
+func TestNewMultisigAuthority(t *testing.T) {
+	desc, privs := newTestMultisig(t)
+
+	authority, err := NewMultisigAuthority(desc)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), authority.Threshold)
+	assert.Len(t, authority.KeyWeights, 3)
+	assert.Equal(t, uint64(2), authority.KeyWeights[KeyID(AlgorithmEd25519, privs[0].PublicKey().Bytes())])
+	assert.Empty(t, authority.AccountWeights)
+
+	_, err = NewMultisigAuthority(&crypto.MultisigDescriptor{Threshold: 1})
+	assert.ErrorIs(t, err, ErrInvalidAuthority)
+
+	_, err = NewMultisigAuthority(nil)
+	assert.ErrorIs(t, err, ErrInvalidAuthority)
+}
+
+func TestMultiSignCoordinator_CompleteMultisig(t *testing.T) {
+	desc, privs := newTestMultisig(t)
+	authority, err := NewMultisigAuthority(desc)
+	require.NoError(t, err)
+	account := &Account{Name: "treasury", Authority: authority}
+	getter := newMockAccountGetter()
+
+	sd := testSignDoc()
+	signBytes, err := sd.GetSignBytes()
+	require.NoError(t, err)
+
+	t.Run("threshold met, ordered by member", func(t *testing.T) {
+		coord, err := NewMultiSignCoordinator(sd)
+		require.NoError(t, err)
+		// Members sign out of order
+		require.NoError(t, coord.SignWithSigner(crypto.NewSigner(privs[2])))
+		require.NoError(t, coord.SignWithSigner(crypto.NewSigner(privs[1])))
+
+		auth, err := coord.CompleteMultisig(desc)
+		require.NoError(t, err)
+		require.Len(t, auth.Signatures, 2)
+		assert.Equal(t, desc.Members[1].PubKey, auth.Signatures[0].PubKey)
+		assert.Equal(t, desc.Members[2].PubKey, auth.Signatures[1].PubKey)
+
+		require.NoError(t, auth.VerifyAuthorization(account, signBytes, getter))
+	})
+
+	t.Run("weighted member alone meets threshold", func(t *testing.T) {
+		coord, err := NewMultiSignCoordinator(sd)
+		require.NoError(t, err)
+		require.NoError(t, coord.SignWithSigner(crypto.NewSigner(privs[0])))
+
+		auth, err := coord.CompleteMultisig(desc)
+		require.NoError(t, err)
+		require.NoError(t, auth.VerifyAuthorization(account, signBytes, getter))
+	})
+
+	t.Run("insufficient weight", func(t *testing.T) {
+		coord, err := NewMultiSignCoordinator(sd)
+		require.NoError(t, err)
+		require.NoError(t, coord.SignWithSigner(crypto.NewSigner(privs[1])))
+
+		_, err = coord.CompleteMultisig(desc)
+		assert.ErrorIs(t, err, ErrInsufficientWeight)
+	})
+
+	t.Run("non-member signature", func(t *testing.T) {
+		outsider, err := crypto.GeneratePrivateKey(crypto.AlgorithmEd25519)
+		require.NoError(t, err)
+		coord, err := NewMultiSignCoordinator(sd)
+		require.NoError(t, err)
+		require.NoError(t, coord.SignWithSigner(crypto.NewSigner(privs[0])))
+		require.NoError(t, coord.SignWithSigner(crypto.NewSigner(outsider)))
+
+		_, err = coord.CompleteMultisig(desc)
+		assert.ErrorIs(t, err, ErrUnauthorized)
+	})
+
+	t.Run("invalid partial signature", func(t *testing.T) {
+		coord, err := NewMultiSignCoordinator(sd)
+		require.NoError(t, err)
+		require.NoError(t, coord.AddSignature(Signature{
+			Algorithm: crypto.AlgorithmEd25519,
+			PubKey:    desc.Members[0].PubKey,
+			Signature: make([]byte, 64),
+		}))
+
+		_, err = coord.CompleteMultisig(desc)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("invalid descriptor", func(t *testing.T) {
+		coord, err := NewMultiSignCoordinator(sd)
+		require.NoError(t, err)
+		_, err = coord.CompleteMultisig(&crypto.MultisigDescriptor{})
+		assert.ErrorIs(t, err, ErrInvalidAuthority)
+	})
+}
@@ -2,7 +2,10 @@ package types
 
 // TxResult represents the result of transaction execution
 type TxResult struct {
-	// Code is the response code (0 = success)
+	// Codespace namespaces Code; empty on success
+	Codespace string `json:"codespace,omitempty"`
+
+	// Code is the response code (0 = success), unique within Codespace
 	Code uint32 `json:"code"`
 
 	// Data is the response data
@@ -16,6 +19,12 @@ type TxResult struct {
 
 	// GasUsed is the amount of gas consumed
 	GasUsed uint64 `json:"gas_used"`
+
+	// MsgResults has one entry per message that ran, in message order; a
+	// failed message carries its own code. Messages after one that failed
+	// the transaction did not run. Empty if the transaction failed before
+	// its messages ran or while applying their effects.
+	MsgResults []MsgResult `json:"msg_results,omitempty"`
 }
 
 // IsOK returns true if the transaction succeeded
@@ -23,6 +32,33 @@ func (r *TxResult) IsOK() bool {
 	return r.Code == 0
 }
 
+// MsgResult represents the result of one message of a transaction
+type MsgResult struct {
+	// Type is the message type
+	Type string `json:"type"`
+
+	// Codespace namespaces Code; empty on success
+	Codespace string `json:"codespace,omitempty"`
+
+	// Code is the response code (0 = success), unique within Codespace
+	Code uint32 `json:"code"`
+
+	// Log is the execution log
+	Log string `json:"log,omitempty"`
+
+	// Events are the events emitted by the message; they are also part of
+	// the transaction's events
+	Events []Event `json:"events,omitempty"`
+
+	// GasUsed is the amount of gas the message consumed
+	GasUsed uint64 `json:"gas_used"`
+}
+
+// IsOK returns true if the message succeeded
+func (r *MsgResult) IsOK() bool {
+	return r.Code == 0
+}
+
 // Event represents a blockchain event
 type Event struct {
 	// Type is the event type
@@ -59,7 +95,10 @@ func (e *Event) AddAttribute(key string, value []byte) {
 
 // QueryResult represents the result of a query
 type QueryResult struct {
-	// Code is the response code (0 = success)
+	// Codespace namespaces Code; empty on success
+	Codespace string `json:"codespace,omitempty"`
+
+	// Code is the response code (0 = success), unique within Codespace
 	Code uint32 `json:"code"`
 
 	// Data is the query response data
@@ -70,6 +109,9 @@ type QueryResult struct {
 
 	// Height is the block height at which the query was executed
 	Height uint64 `json:"height"`
+
+	// Proof is the protobuf-encoded ics23 Merkle proof of Data, if requested
+	Proof []byte `json:"proof,omitempty"`
 }
 
 // IsOK returns true if the query succeeded
@@ -97,9 +139,12 @@ type EndBlockResult struct {
 
 // CommitResult represents the result of Commit
 type CommitResult struct {
-	// AppHash is the application state hash
+	// AppHash is the application state hash (CommitInfo.AppHash)
 	AppHash []byte `json:"app_hash"`
 
 	// Height is the committed block height
 	Height uint64 `json:"height"`
+
+	// CommitInfo lists the store roots AppHash commits to
+	CommitInfo *CommitInfo `json:"commit_info,omitempty"`
 }
@@ -286,11 +286,9 @@ func TestSignDoc_EmptyChainIDRejection(t *testing.T) {
 	assert.True(t, errors.Is(err, ErrSignDocMismatch),
 		"error should be wrapped with ErrSignDocMismatch")
 
-	// Also test via NewSignDoc constructor helper
-	sdWithEmpty := NewSignDoc("", 1, "alice", 1, "memo")
-	sdWithEmpty.AddMessage("/msg", []byte(`{}`))
-	err = sdWithEmpty.ValidateBasic()
-	require.Error(t, err, "SignDoc created with empty chain_id via NewSignDoc MUST fail validation")
+	// The NewSignDoc constructor rejects it up front
+	_, err = NewSignDoc("", 1, "alice", 1, "memo")
+	require.ErrorIs(t, err, ErrInvalidChainID, "NewSignDoc MUST reject an empty chain_id")
 
 	t.Log("✓ Empty chain_id is rejected to prevent cross-chain replay attacks")
 }
@@ -357,10 +355,12 @@ func TestSignDoc_WhitespaceOnlyChainIDRejection(t *testing.T) {
 // funds or causing unintended state changes on the target chain.
 func TestSignDoc_CrossChainReplayProtection(t *testing.T) {
 	// Create two SignDocs with identical content except for ChainID
-	mainnetSignDoc := NewSignDoc("mainnet", 1, "alice", 1, "transfer to bob")
+	mainnetSignDoc, err := NewSignDoc("mainnet", 1, "alice", 1, "transfer to bob")
+	require.NoError(t, err)
 	mainnetSignDoc.AddMessage("/punnet.bank.v1.MsgSend", []byte(`{"from":"alice","to":"bob","amount":"100"}`))
 
-	testnetSignDoc := NewSignDoc("testnet", 1, "alice", 1, "transfer to bob")
+	testnetSignDoc, err := NewSignDoc("testnet", 1, "alice", 1, "transfer to bob")
+	require.NoError(t, err)
 	testnetSignDoc.AddMessage("/punnet.bank.v1.MsgSend", []byte(`{"from":"alice","to":"bob","amount":"100"}`))
 
 	// Get the sign bytes for each chain
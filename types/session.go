@@ -0,0 +1,243 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+)
+
+// Session key limits.
+const (
+	// MaxSessionMessageTypes bounds the allowed message types in a SessionGrant.
+	MaxSessionMessageTypes = 64
+
+	// sessionGrantDomain separates session grant sign bytes from SignDoc
+	// sign bytes, so a signature over one can never be replayed as the other.
+	sessionGrantDomain = "punnet/session-grant/v1\n"
+)
+
+// SessionGrant authorizes a short-lived session key to sign a restricted set
+// of messages for an account until an expiration height.
+//
+// The account's authority approves the grant once (GrantAuthorization in
+// SessionAuthorization); afterwards the session key alone signs transactions,
+// so games and dApps can sign frequent low-value transactions without
+// prompting for the main key.
+//
+// SECURITY: The grant is re-verified against the account's current authority
+// for every transaction. Rotating the account's keys (MsgUpdateAuthority)
+// revokes all outstanding grants.
+type SessionGrant struct {
+	// ChainID binds the grant to one chain.
+	ChainID string `json:"chain_id"`
+
+	// Account is the account the session key acts for.
+	Account AccountName `json:"account"`
+
+	// SessionAlgorithm is the session key's signature algorithm.
+	SessionAlgorithm Algorithm `json:"session_algorithm"`
+
+	// SessionPubKey is the session key's public key.
+	SessionPubKey []byte `json:"session_pub_key"`
+
+	// AllowedMessageTypes lists the message types the session key may sign.
+	// Every message in a session-signed transaction must be listed.
+	AllowedMessageTypes []string `json:"allowed_message_types"`
+
+	// ExpirationHeight is the first block height at which the grant is no
+	// longer valid.
+	ExpirationHeight StringUint64 `json:"expiration_height"`
+}
+
+// NewSessionGrant creates a grant for sessionKey.
+// The returned grant must be approved by the account's authority; see SignSessionGrant.
+func NewSessionGrant(chainID string, account AccountName, sessionKey crypto.PublicKey, allowedMessageTypes []string, expirationHeight uint64) *SessionGrant {
+	return &SessionGrant{
+		ChainID:             chainID,
+		Account:             account,
+		SessionAlgorithm:    sessionKey.Algorithm(),
+		SessionPubKey:       append([]byte(nil), sessionKey.Bytes()...),
+		AllowedMessageTypes: append([]string(nil), allowedMessageTypes...),
+		ExpirationHeight:    StringUint64(expirationHeight),
+	}
+}
+
+// ValidateBasic performs stateless validation of the grant.
+func (g *SessionGrant) ValidateBasic() error {
+	if g == nil {
+		return fmt.Errorf("%w: grant is nil", ErrInvalidSession)
+	}
+	if err := ValidateChainID(g.ChainID); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSession, err)
+	}
+	if !g.Account.IsValid() {
+		return fmt.Errorf("%w: %v: %s", ErrInvalidSession, ErrInvalidAccount, g.Account)
+	}
+	if g.ExpirationHeight == 0 {
+		return fmt.Errorf("%w: expiration height cannot be zero", ErrInvalidSession)
+	}
+
+	// SECURITY: WebAuthn assertions sign a challenge derived from the message
+	// and need per-signature client data; session keys are plain signing keys.
+	if !IsValidAlgorithm(g.SessionAlgorithm) || g.SessionAlgorithm == AlgorithmWebAuthn {
+		return fmt.Errorf("%w: %v: %s", ErrInvalidSession, ErrUnsupportedAlgorithm, g.SessionAlgorithm)
+	}
+	if len(g.SessionPubKey) != g.SessionAlgorithm.PublicKeySize() {
+		return fmt.Errorf("%w: session public key must be %d bytes, got %d",
+			ErrInvalidSession, g.SessionAlgorithm.PublicKeySize(), len(g.SessionPubKey))
+	}
+
+	if len(g.AllowedMessageTypes) == 0 {
+		return fmt.Errorf("%w: at least one allowed message type required", ErrInvalidSession)
+	}
+	if len(g.AllowedMessageTypes) > MaxSessionMessageTypes {
+		return fmt.Errorf("%w: too many allowed message types: %d > %d",
+			ErrInvalidSession, len(g.AllowedMessageTypes), MaxSessionMessageTypes)
+	}
+	seen := make(map[string]bool, len(g.AllowedMessageTypes))
+	for _, t := range g.AllowedMessageTypes {
+		if t == "" {
+			return fmt.Errorf("%w: allowed message type cannot be empty", ErrInvalidSession)
+		}
+		if seen[t] {
+			return fmt.Errorf("%w: duplicate allowed message type %s", ErrInvalidSession, t)
+		}
+		seen[t] = true
+	}
+	return nil
+}
+
+// GetSignBytes returns the bytes the account's authority signs to approve
+// the grant: SHA-256 over a domain prefix and the grant's JSON encoding.
+//
+// INVARIANT: Deterministic - struct fields marshal in declaration order.
+func (g *SessionGrant) GetSignBytes() ([]byte, error) {
+	data, err := json.Marshal(g)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSession, err)
+	}
+	h := sha256.New()
+	h.Write([]byte(sessionGrantDomain))
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// allowsMessageType reports whether msgType is in the grant's allowed set.
+func (g *SessionGrant) allowsMessageType(msgType string) bool {
+	for _, t := range g.AllowedMessageTypes {
+		if t == msgType {
+			return true
+		}
+	}
+	return false
+}
+
+// SignSessionGrant signs the grant with signer, returning an Authorization
+// suitable for SessionAuthorization.GrantAuthorization. For multi-signature
+// accounts, collect signatures over GetSignBytes with a MultiSignCoordinator-
+// style flow and build the Authorization directly.
+func SignSessionGrant(grant *SessionGrant, signer crypto.Signer) (*Authorization, error) {
+	signBytes, err := grant.GetSignBytes()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := signer.Sign(signBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign session grant: %w", err)
+	}
+	return NewAuthorization(Signature{
+		Algorithm: signer.Algorithm(),
+		PubKey:    signer.PublicKey().Bytes(),
+		Signature: sig,
+	}), nil
+}
+
+// SessionAuthorization authorizes a transaction with a session key.
+// Carried in Authorization.Session.
+type SessionAuthorization struct {
+	// Grant defines the session key's scope.
+	Grant SessionGrant `json:"grant"`
+
+	// GrantAuthorization is the account authority's approval of Grant,
+	// i.e. signatures over Grant.GetSignBytes() meeting the account threshold.
+	GrantAuthorization *Authorization `json:"grant_authorization"`
+
+	// Signature is the session key's signature over the transaction's sign bytes.
+	Signature Signature `json:"signature"`
+}
+
+// NewSessionAuthorization wraps a session-signed transaction signature in an
+// Authorization.
+func NewSessionAuthorization(grant *SessionGrant, grantAuth *Authorization, sig Signature) *Authorization {
+	return &Authorization{
+		Signatures:            []Signature{},
+		AccountAuthorizations: make(map[AccountName]*Authorization),
+		Session: &SessionAuthorization{
+			Grant:              *grant,
+			GrantAuthorization: grantAuth,
+			Signature:          sig.clone(),
+		},
+	}
+}
+
+// ValidateBasic performs stateless validation of the session authorization.
+func (s *SessionAuthorization) ValidateBasic() error {
+	if err := s.Grant.ValidateBasic(); err != nil {
+		return err
+	}
+	if s.GrantAuthorization == nil {
+		return fmt.Errorf("%w: grant authorization cannot be nil", ErrInvalidSession)
+	}
+	// SECURITY: A session key cannot approve another session key.
+	if s.GrantAuthorization.Session != nil {
+		return fmt.Errorf("%w: grant cannot be authorized by a session key", ErrInvalidSession)
+	}
+	if err := s.GrantAuthorization.ValidateBasic(); err != nil {
+		return fmt.Errorf("%w: grant authorization: %v", ErrInvalidSession, err)
+	}
+	if err := s.Signature.ValidateBasic(); err != nil {
+		return fmt.Errorf("%w: session signature: %v", ErrInvalidSession, err)
+	}
+	if s.Signature.GetAlgorithm() != s.Grant.SessionAlgorithm ||
+		string(s.Signature.PubKey) != string(s.Grant.SessionPubKey) {
+		return fmt.Errorf("%w: signature is not from the granted session key", ErrInvalidSession)
+	}
+	return nil
+}
+
+// verify checks the session authorization for a transaction on account at
+// block height, with signBytes being the transaction's SignDoc sign bytes.
+//
+// Checks, in order: grant binding (chain, account), expiry, message types,
+// the session key's transaction signature, and finally the authority's
+// approval of the grant (the most expensive check, possibly recursive).
+func (s *SessionAuthorization) verify(chainID string, account *Account, msgTypes []string, signBytes []byte, height uint64, getter AccountGetter) error {
+	if s.Grant.ChainID != chainID {
+		return fmt.Errorf("%w: %v: grant for %q, expected %q", ErrInvalidSession, ErrChainIDMismatch, s.Grant.ChainID, chainID)
+	}
+	if s.Grant.Account != account.Name {
+		return fmt.Errorf("%w: grant for account %s, transaction from %s", ErrInvalidSession, s.Grant.Account, account.Name)
+	}
+	if height >= s.Grant.ExpirationHeight.Uint64() {
+		return fmt.Errorf("%w: expired at height %d, current height %d", ErrSessionExpired, s.Grant.ExpirationHeight, height)
+	}
+	for _, t := range msgTypes {
+		if !s.Grant.allowsMessageType(t) {
+			return fmt.Errorf("%w: %s", ErrSessionMessageNotAllowed, t)
+		}
+	}
+	if !s.Signature.Verify(signBytes) {
+		return fmt.Errorf("%w: session key signature failed verification", ErrInvalidSignature)
+	}
+
+	grantBytes, err := s.Grant.GetSignBytes()
+	if err != nil {
+		return err
+	}
+	if err := s.GrantAuthorization.VerifyAuthorization(account, grantBytes, getter); err != nil {
+		return fmt.Errorf("%w: grant not authorized: %w", ErrInvalidSession, err)
+	}
+	return nil
+}
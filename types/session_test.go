@@ -0,0 +1,229 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	sessionTestChain   = "session-chain"
+	sessionTestMsgType = "/game.v1.MsgMove"
+)
+
+// sessionFixture is an account whose main key grants a session key.
+type sessionFixture struct {
+	account    *Account
+	getter     *mockAccountGetter
+	mainKey    crypto.Signer
+	sessionKey crypto.Signer
+	grant      *SessionGrant
+	grantAuth  *Authorization
+}
+
+func newSessionFixture(t *testing.T, expiration uint64) *sessionFixture {
+	t.Helper()
+	mainKey := newConfirmTestSigner(t)
+	sessionKey := newConfirmTestSigner(t)
+
+	account := NewAccount("alice", mainKey.PublicKey().Bytes())
+	getter := newMockAccountGetter()
+	getter.setAccount(account)
+
+	grant := NewSessionGrant(sessionTestChain, "alice", sessionKey.PublicKey(), []string{sessionTestMsgType}, expiration)
+	require.NoError(t, grant.ValidateBasic())
+	grantAuth, err := SignSessionGrant(grant, mainKey)
+	require.NoError(t, err)
+
+	return &sessionFixture{
+		account:    account,
+		getter:     getter,
+		mainKey:    mainKey,
+		sessionKey: sessionKey,
+		grant:      grant,
+		grantAuth:  grantAuth,
+	}
+}
+
+// sessionTx builds a transaction with msgTypes signed by the session key.
+func (f *sessionFixture) sessionTx(t *testing.T, msgTypes ...string) *Transaction {
+	t.Helper()
+	msgs := make([]Message, len(msgTypes))
+	for i, typ := range msgTypes {
+		msgs[i] = &testMessage{MsgType: typ, Signers: []AccountName{"alice"}}
+	}
+	tx := NewTransaction("alice", f.account.Nonce, msgs, nil)
+	tx.FeeSlippage = Ratio{Numerator: 0, Denominator: 1}
+
+	signDoc, err := tx.ToSignDoc(sessionTestChain, f.account.Nonce)
+	require.NoError(t, err)
+	signBytes, err := signDoc.GetSignBytes()
+	require.NoError(t, err)
+	sig, err := f.sessionKey.Sign(signBytes)
+	require.NoError(t, err)
+
+	tx.Authorization = NewSessionAuthorization(f.grant, f.grantAuth, Signature{
+		Algorithm: f.sessionKey.Algorithm(),
+		PubKey:    f.sessionKey.PublicKey().Bytes(),
+		Signature: sig,
+	})
+	require.NoError(t, tx.ValidateBasic())
+	return tx
+}
+
+func TestSessionAuthorization_Verify(t *testing.T) {
+	f := newSessionFixture(t, 100)
+	tx := f.sessionTx(t, sessionTestMsgType, sessionTestMsgType)
+
+	require.NoError(t, tx.VerifyAuthorizationAtHeight(sessionTestChain, f.account, f.getter, 99))
+
+	err := tx.VerifyAuthorizationAtHeight(sessionTestChain, f.account, f.getter, 100)
+	assert.ErrorIs(t, err, ErrSessionExpired)
+
+	// Session authorizations need a height and are rejected without one.
+	err = tx.VerifyAuthorization(sessionTestChain, f.account, f.getter)
+	assert.ErrorIs(t, err, ErrInvalidSession)
+}
+
+func TestSessionAuthorization_Rejections(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(t *testing.T, f *sessionFixture) (*Transaction, string)
+		wantErr error
+	}{
+		{
+			name: "message type not allowed",
+			mutate: func(t *testing.T, f *sessionFixture) (*Transaction, string) {
+				return f.sessionTx(t, sessionTestMsgType, "/punnet.bank.v1.MsgSend"), sessionTestChain
+			},
+			wantErr: ErrSessionMessageNotAllowed,
+		},
+		{
+			name: "wrong chain",
+			mutate: func(t *testing.T, f *sessionFixture) (*Transaction, string) {
+				return f.sessionTx(t, sessionTestMsgType), "other-chain"
+			},
+			wantErr: ErrInvalidSession,
+		},
+		{
+			name: "grant for another account",
+			mutate: func(t *testing.T, f *sessionFixture) (*Transaction, string) {
+				f.grant.Account = "bob"
+				grantAuth, err := SignSessionGrant(f.grant, f.mainKey)
+				require.NoError(t, err)
+				f.grantAuth = grantAuth
+				return f.sessionTx(t, sessionTestMsgType), sessionTestChain
+			},
+			wantErr: ErrInvalidSession,
+		},
+		{
+			name: "grant signed by unrelated key",
+			mutate: func(t *testing.T, f *sessionFixture) (*Transaction, string) {
+				grantAuth, err := SignSessionGrant(f.grant, newConfirmTestSigner(t))
+				require.NoError(t, err)
+				f.grantAuth = grantAuth
+				return f.sessionTx(t, sessionTestMsgType), sessionTestChain
+			},
+			wantErr: ErrInsufficientWeight,
+		},
+		{
+			name: "grant widened after signing",
+			mutate: func(t *testing.T, f *sessionFixture) (*Transaction, string) {
+				tx := f.sessionTx(t, sessionTestMsgType)
+				tx.Authorization.Session.Grant.ExpirationHeight = 1000
+				return tx, sessionTestChain
+			},
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name: "tampered transaction",
+			mutate: func(t *testing.T, f *sessionFixture) (*Transaction, string) {
+				tx := f.sessionTx(t, sessionTestMsgType)
+				tx.Memo = "tampered"
+				return tx, sessionTestChain
+			},
+			wantErr: ErrInvalidSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newSessionFixture(t, 100)
+			tx, chainID := tt.mutate(t, f)
+			err := tx.VerifyAuthorizationAtHeight(chainID, f.account, f.getter, 10)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestSessionGrant_ValidateBasic(t *testing.T) {
+	sessionKey := newConfirmTestSigner(t).PublicKey()
+
+	tests := []struct {
+		name   string
+		mutate func(g *SessionGrant)
+	}{
+		{"empty chain ID", func(g *SessionGrant) { g.ChainID = "" }},
+		{"invalid account", func(g *SessionGrant) { g.Account = "" }},
+		{"zero expiration", func(g *SessionGrant) { g.ExpirationHeight = 0 }},
+		{"no message types", func(g *SessionGrant) { g.AllowedMessageTypes = nil }},
+		{"empty message type", func(g *SessionGrant) { g.AllowedMessageTypes = []string{""} }},
+		{"duplicate message type", func(g *SessionGrant) { g.AllowedMessageTypes = []string{"a", "a"} }},
+		{"webauthn key", func(g *SessionGrant) { g.SessionAlgorithm = AlgorithmWebAuthn }},
+		{"short public key", func(g *SessionGrant) { g.SessionPubKey = g.SessionPubKey[:8] }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewSessionGrant("chain", "alice", sessionKey, []string{sessionTestMsgType}, 10)
+			require.NoError(t, g.ValidateBasic())
+			tt.mutate(g)
+			assert.ErrorIs(t, g.ValidateBasic(), ErrInvalidSession)
+		})
+	}
+}
+
+func TestAuthorization_ValidateBasic_Session(t *testing.T) {
+	f := newSessionFixture(t, 100)
+	tx := f.sessionTx(t, sessionTestMsgType)
+	auth := tx.Authorization
+
+	// Session authorizations cannot be mixed with direct signatures.
+	mixed := *auth
+	mixed.Signatures = f.grantAuth.Signatures
+	assert.ErrorIs(t, mixed.ValidateBasic(), ErrInvalidAuthorization)
+
+	// The transaction must be signed by the granted session key.
+	wrongKey := *auth.Session
+	wrongKey.Signature.PubKey = f.mainKey.PublicKey().Bytes()
+	assert.ErrorIs(t, (&Authorization{Session: &wrongKey}).ValidateBasic(), ErrInvalidSession)
+
+	// A session key cannot grant another session key.
+	nested := *auth.Session
+	nested.GrantAuthorization = auth
+	assert.ErrorIs(t, (&Authorization{Session: &nested}).ValidateBasic(), ErrInvalidSession)
+
+	// Sessions cannot be used through delegation.
+	delegated := NewAuthorization()
+	delegated.AccountAuthorizations["alice"] = auth
+	err := delegated.ValidateBasic()
+	assert.ErrorIs(t, err, ErrInvalidAuthorization)
+	assert.ErrorContains(t, err, "cannot be delegated")
+}
+
+func TestSessionGrant_GetSignBytes(t *testing.T) {
+	f := newSessionFixture(t, 100)
+	b1, err := f.grant.GetSignBytes()
+	require.NoError(t, err)
+	b2, err := f.grant.GetSignBytes()
+	require.NoError(t, err)
+	assert.Equal(t, b1, b2)
+
+	// Any change to the grant changes what the authority approved.
+	f.grant.ExpirationHeight++
+	b3, err := f.grant.GetSignBytes()
+	require.NoError(t, err)
+	assert.NotEqual(t, b1, b3)
+}
@@ -0,0 +1,51 @@
+package types
+
+import "fmt"
+
+// NewPlaceholderSignature returns a signature for gas simulation: an
+// all-zero signature of algo's signature size over pubKey, or over an
+// all-zero public key of algo's size if pubKey is nil. It passes
+// ValidateBasic, so a transaction authorized by placeholders has the size
+// and layout of a signed one, without a private key being used.
+//
+// SECURITY: A placeholder never verifies; a transaction carrying one can
+// only be simulated, not executed.
+//
+// WebAuthn is not supported: its assertion data has no fixed size.
+func NewPlaceholderSignature(algo Algorithm, pubKey []byte) (Signature, error) {
+	if algo == "" {
+		algo = AlgorithmEd25519
+	}
+	if !IsValidAlgorithm(algo) || algo == AlgorithmWebAuthn {
+		return Signature{}, fmt.Errorf("%w: no placeholder signature for %s", ErrUnsupportedAlgorithm, algo)
+	}
+
+	if pubKey == nil {
+		pubKey = make([]byte, algo.PublicKeySize())
+	} else {
+		pubKey = append([]byte(nil), pubKey...)
+	}
+	sig := Signature{
+		Algorithm: algo,
+		PubKey:    pubKey,
+		Signature: make([]byte, algo.SignatureSize()),
+	}
+	if err := sig.ValidateBasic(); err != nil {
+		return Signature{}, err
+	}
+	return sig, nil
+}
+
+// IsPlaceholder reports whether the signature is all zero, as made by
+// NewPlaceholderSignature
+func (s *Signature) IsPlaceholder() bool {
+	if len(s.Signature) == 0 {
+		return false
+	}
+	for _, b := range s.Signature {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
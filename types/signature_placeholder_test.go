@@ -0,0 +1,57 @@
+package types
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPlaceholderSignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	t.Run("over a public key", func(t *testing.T) {
+		sig, err := NewPlaceholderSignature(AlgorithmEd25519, pub)
+		require.NoError(t, err)
+		require.NoError(t, sig.ValidateBasic())
+		assert.Equal(t, []byte(pub), sig.PubKey)
+		assert.Len(t, sig.Signature, ed25519.SignatureSize)
+		assert.True(t, sig.IsPlaceholder())
+		assert.False(t, sig.Verify([]byte("message")), "a placeholder must never verify")
+	})
+
+	t.Run("zero public key", func(t *testing.T) {
+		sig, err := NewPlaceholderSignature("", nil)
+		require.NoError(t, err)
+		assert.Equal(t, AlgorithmEd25519, sig.Algorithm)
+		assert.Equal(t, make([]byte, ed25519.PublicKeySize), sig.PubKey)
+	})
+
+	t.Run("same size as a real signature", func(t *testing.T) {
+		placeholder, err := NewPlaceholderSignature(AlgorithmEd25519, pub)
+		require.NoError(t, err)
+		real := Signature{Algorithm: AlgorithmEd25519, PubKey: pub, Signature: ed25519.Sign(priv, []byte("message"))}
+		assert.False(t, real.IsPlaceholder())
+
+		placeholderAuth, err := json.Marshal(NewAuthorization(placeholder))
+		require.NoError(t, err)
+		realAuth, err := json.Marshal(NewAuthorization(real))
+		require.NoError(t, err)
+		assert.Len(t, placeholderAuth, len(realAuth))
+	})
+
+	for _, algo := range []Algorithm{AlgorithmWebAuthn, AlgorithmSecp256k1, "rsa"} {
+		t.Run("unsupported "+string(algo), func(t *testing.T) {
+			_, err := NewPlaceholderSignature(algo, nil)
+			require.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+		})
+	}
+
+	t.Run("wrong public key size", func(t *testing.T) {
+		_, err := NewPlaceholderSignature(AlgorithmEd25519, make([]byte, 16))
+		require.ErrorIs(t, err, ErrInvalidPublicKey)
+	})
+}
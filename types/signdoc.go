@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"sync"
 
 	"github.com/blockberries/cramberry/pkg/cramberry"
 	"golang.org/x/text/unicode/norm"
@@ -16,13 +17,18 @@ import (
 // Changing this version invalidates all existing signatures.
 const SignDocVersion = "1"
 
+// SignDocVersionTip is the SignDoc version that carries a priority tip (see
+// SignDoc.Tip). A SignDoc has this version exactly when it has a tip, so
+// transactions without one keep version 1 and their existing signatures.
+const SignDocVersionTip = "2"
+
 // SupportedSignDocVersions is the list of SignDoc versions that this implementation
 // can validate and process. This is the authoritative source for version support.
 //
 // SECURITY: Nodes MUST reject transactions with unsupported versions to prevent
 // forward-compatibility attacks where different nodes interpret unknown versions
 // differently.
-var SupportedSignDocVersions = []string{"1"}
+var SupportedSignDocVersions = []string{SignDocVersion, SignDocVersionTip}
 
 // ValidateSignDocVersion checks if the given SignDoc version is supported.
 //
@@ -56,6 +62,11 @@ const MaxMessageDataSize = 64 * 1024 // 64KB
 // SECURITY: Prevents DoS attacks via iteration over large coin arrays.
 const MaxFeeCoins = 16
 
+// MaxSigners limits the number of signers of a multi-signer transaction,
+// including its account (see SignDoc.Signers).
+// SECURITY: Each signer costs a signature verification and an account lookup.
+const MaxSigners = 8
+
 // SignDocCoin represents a coin in the SignDoc with string-serialized amount.
 //
 // INVARIANT: Amount MUST be a valid decimal string representation of a non-negative integer.
@@ -173,6 +184,38 @@ type SignDoc struct {
 	// FeeSlippage is the maximum conversion rate slippage tolerance for fee payment.
 	// Expressed as a ratio (e.g., {numerator: "1", denominator: "100"} = 1% slippage).
 	FeeSlippage SignDocRatio `json:"fee_slippage"`
+
+	// Tip is the priority tip, paid on top of the fee to have the transaction
+	// included sooner (see Transaction.Tip). Present exactly in version 2
+	// SignDocs, so version 1 serialization is unchanged.
+	Tip *SignDocCoin `json:"tip,omitempty"`
+
+	// FeePayer is the account paying the fee, if not Account. It must sign
+	// this SignDoc too (see Transaction.FeePayerAuthorization).
+	// Omitted when empty, so SignDocs without a fee payer keep their
+	// existing serialization (and signatures).
+	FeePayer string `json:"fee_payer,omitempty"`
+
+	// FeeGranter is the account whose fee allowance pays the fee, if any.
+	// Omitted when empty, like FeePayer.
+	FeeGranter string `json:"fee_granter,omitempty"`
+
+	// Signers lists the accounts of a multi-signer transaction: the
+	// transaction's account first, then its co-signers in order (see
+	// Transaction.CoSigners). Each signer signs its own SignDoc, with its
+	// name as Account and its nonce as AccountSequence, and the same Signers.
+	// Omitted for single-signer transactions, which keep their existing
+	// serialization (and signatures).
+	Signers []string `json:"signers,omitempty"`
+
+	// NotBefore is the authorization's lower validity bound, if any.
+	// Omitted when nil, so SignDocs without a validity window keep their
+	// existing serialization (and signatures).
+	NotBefore *ValidityBound `json:"not_before,omitempty"`
+
+	// NotAfter is the authorization's upper validity bound, if any.
+	// Omitted when nil, like NotBefore.
+	NotAfter *ValidityBound `json:"not_after,omitempty"`
 }
 
 // SignDocMessage represents a message in canonical form for signing.
@@ -186,12 +229,17 @@ type SignDocMessage struct {
 }
 
 // NewSignDoc creates a new SignDoc with the current version.
+// Returns an error wrapping ErrInvalidChainID if chainID is empty or outside
+// the grammar of ValidateChainID.
 //
-// PRECONDITION: chainID is non-empty (cross-chain replay protection).
 // PRECONDITION: account is non-empty.
 // POSTCONDITION: Returned SignDoc has Version = SignDocVersion.
 // POSTCONDITION: Fee and FeeSlippage are zero-valued and must be set separately.
-func NewSignDoc(chainID string, accountSequence uint64, account string, nonce uint64, memo string) *SignDoc {
+func NewSignDoc(chainID string, accountSequence uint64, account string, nonce uint64, memo string) (*SignDoc, error) {
+	if err := ValidateChainID(chainID); err != nil {
+		return nil, err
+	}
+
 	return &SignDoc{
 		Version:         SignDocVersion,
 		ChainID:         chainID,
@@ -202,16 +250,21 @@ func NewSignDoc(chainID string, accountSequence uint64, account string, nonce ui
 		Messages:        make([]SignDocMessage, 0),
 		Fee:             SignDocFee{Amount: make([]SignDocCoin, 0), GasLimit: "0"},
 		FeeSlippage:     SignDocRatio{Numerator: "0", Denominator: "1"},
-	}
+	}, nil
 }
 
 // NewSignDocWithFee creates a new SignDoc with the current version and fee configuration.
+// Returns an error wrapping ErrInvalidChainID if chainID is empty or outside
+// the grammar of ValidateChainID.
 //
-// PRECONDITION: chainID is non-empty (cross-chain replay protection).
 // PRECONDITION: account is non-empty.
 // PRECONDITION: fee.GasLimit is a valid decimal string.
 // PRECONDITION: feeSlippage.Denominator is not "0".
-func NewSignDocWithFee(chainID string, accountSequence uint64, account string, nonce uint64, memo string, fee SignDocFee, feeSlippage SignDocRatio) *SignDoc {
+func NewSignDocWithFee(chainID string, accountSequence uint64, account string, nonce uint64, memo string, fee SignDocFee, feeSlippage SignDocRatio) (*SignDoc, error) {
+	if err := ValidateChainID(chainID); err != nil {
+		return nil, err
+	}
+
 	return &SignDoc{
 		Version:         SignDocVersion,
 		ChainID:         chainID,
@@ -222,7 +275,7 @@ func NewSignDocWithFee(chainID string, accountSequence uint64, account string, n
 		Messages:        make([]SignDocMessage, 0),
 		Fee:             fee,
 		FeeSlippage:     feeSlippage,
-	}
+	}, nil
 }
 
 // SetFee sets the fee on the SignDoc.
@@ -242,7 +295,7 @@ func (sd *SignDoc) SetFeeSlippage(slippage SignDocRatio) {
 //
 // CANONICAL JSON REQUIREMENTS:
 //   - Compact: No whitespace outside of string values (validated by ValidateBasic)
-//   - Ordered: Object keys in consistent order (NOT validated - caller's responsibility)
+//   - Ordered: Object keys in consistent order (validated only by ValidateBasicStrict)
 //   - Escaped: Proper JSON escaping for special characters
 //
 // RATIONALE: Re-canonicalizing the data would require parsing it as a generic map,
@@ -258,9 +311,11 @@ func (sd *SignDoc) SetFeeSlippage(slippage SignDocRatio) {
 //
 // RECOMMENDATION: Always construct message data using the SDK's message types and
 // their ToJSON methods, which guarantee canonical output. If constructing JSON
-// manually, use a deterministic JSON encoder with sorted keys.
+// manually, use a deterministic JSON encoder with sorted keys, or pass the data
+// through CanonicalizeMessageData first.
 //
-// See also: ValidateBasic() validates compactness but NOT key ordering.
+// See also: ValidateBasic() validates compactness but NOT key ordering;
+// ValidateBasicStrict() additionally rejects out-of-order keys.
 func (sd *SignDoc) AddMessage(msgType string, data json.RawMessage) {
 	sd.Messages = append(sd.Messages, SignDocMessage{
 		Type: msgType,
@@ -292,10 +347,18 @@ func (sd *SignDoc) AddMessage(msgType string, data json.RawMessage) {
 // allocation that occurs with strings.Builder.String() + []byte(...) conversion.
 // bytes.Buffer.Bytes() returns a slice of the internal buffer directly.
 func (sd *SignDoc) ToJSON() ([]byte, error) {
-	var b bytes.Buffer
+	return sd.AppendJSON(make([]byte, 0, sd.jsonSizeHint()))
+}
 
-	// Pre-allocate reasonable capacity to reduce allocations
-	b.Grow(256)
+// AppendJSON appends the canonical JSON of ToJSON to dst and returns the
+// extended slice, so callers can reuse a buffer across SignDocs.
+//
+// INVARIANT: AppendJSON(dst) == append(dst, ToJSON()...) for an unmodified SignDoc.
+//
+// Complexity: O(n) in the output size; no allocations beyond growing dst and
+// string escaping when dst has capacity for jsonSizeHint() more bytes.
+func (sd *SignDoc) AppendJSON(dst []byte) ([]byte, error) {
+	b := bytes.NewBuffer(dst)
 
 	b.WriteString(`{"version":`)
 	b.WriteString(cramberry.EscapeJSONString(sd.Version))
@@ -310,7 +373,7 @@ func (sd *SignDoc) ToJSON() ([]byte, error) {
 	b.WriteString(cramberry.EscapeJSONString(strconv.FormatUint(uint64(sd.AccountSequence), 10)))
 
 	b.WriteString(`,"messages":`)
-	if err := sd.writeMessagesJSON(&b); err != nil {
+	if err := sd.writeMessagesJSON(b); err != nil {
 		return nil, err
 	}
 
@@ -322,21 +385,77 @@ func (sd *SignDoc) ToJSON() ([]byte, error) {
 	b.WriteString(cramberry.EscapeJSONString(sd.Memo))
 
 	b.WriteString(`,"fee":`)
-	sd.Fee.writeJSON(&b)
+	sd.Fee.writeJSON(b)
 
 	b.WriteString(`,"fee_slippage":`)
-	sd.FeeSlippage.writeJSON(&b)
+	sd.FeeSlippage.writeJSON(b)
+
+	// The tip is written only when set (version 2)
+	if sd.Tip != nil {
+		b.WriteString(`,"tip":`)
+		sd.Tip.writeJSON(b)
+	}
+
+	// Fee sponsors are written only when set (omitempty behavior)
+	if sd.FeePayer != "" {
+		b.WriteString(`,"fee_payer":`)
+		b.WriteString(cramberry.EscapeJSONString(sd.FeePayer))
+	}
+	if sd.FeeGranter != "" {
+		b.WriteString(`,"fee_granter":`)
+		b.WriteString(cramberry.EscapeJSONString(sd.FeeGranter))
+	}
+
+	// Signers are written only for multi-signer transactions
+	if len(sd.Signers) > 0 {
+		b.WriteString(`,"signers":[`)
+		for i, signer := range sd.Signers {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(cramberry.EscapeJSONString(signer))
+		}
+		b.WriteByte(']')
+	}
+
+	// The validity window is written only when set (omitempty behavior)
+	if sd.NotBefore != nil {
+		b.WriteString(`,"not_before":`)
+		sd.NotBefore.writeJSON(b)
+	}
+	if sd.NotAfter != nil {
+		b.WriteString(`,"not_after":`)
+		sd.NotAfter.writeJSON(b)
+	}
 
 	b.WriteString(`}`)
 
-	// bytes.Buffer.Bytes() returns a slice of the internal buffer. Since this buffer
-	// is not reused after this function returns, returning the slice directly is safe.
-	// This avoids the double allocation that occurred with strings.Builder:
-	// Old: strings.Builder.String() (alloc 1) -> []byte(string) (alloc 2)
-	// New: bytes.Buffer.Bytes() returns internal buffer slice (no extra alloc)
+	// bytes.Buffer.Bytes() returns a slice of the internal buffer, which starts
+	// with dst. Since this buffer is not reused after this function returns,
+	// returning the slice directly is safe and avoids a copy.
 	return b.Bytes(), nil
 }
 
+// jsonSizeHint estimates the length of the SignDoc's canonical JSON, so the
+// output buffer is usually allocated once.
+func (sd *SignDoc) jsonSizeHint() int {
+	// Field names, punctuation and numeric fields, including a validity window
+	size := 256 + len(sd.ChainID) + len(sd.Account) + len(sd.Memo) + len(sd.FeePayer) + len(sd.FeeGranter)
+	for _, msg := range sd.Messages {
+		size += 24 + len(msg.Type) + len(msg.Data)
+	}
+	for _, coin := range sd.Fee.Amount {
+		size += 32 + len(coin.Denom) + len(coin.Amount)
+	}
+	if sd.Tip != nil {
+		size += 40 + len(sd.Tip.Denom) + len(sd.Tip.Amount)
+	}
+	for _, signer := range sd.Signers {
+		size += 4 + len(signer)
+	}
+	return size
+}
+
 // writeMessagesJSON writes the messages array to the buffer.
 func (sd *SignDoc) writeMessagesJSON(b *bytes.Buffer) error {
 	b.WriteString(`[`)
@@ -355,6 +474,7 @@ func (sd *SignDoc) writeMessagesJSON(b *bytes.Buffer) error {
 		// 1. Message data typically comes from our own serialization code which is consistent
 		// 2. Re-canonicalization would add significant overhead for large messages
 		// 3. ValidateBasic() catches the most common issue (pretty-printed JSON from files)
+		// 4. Callers needing the guarantee can use ValidateBasicStrict/CanonicalizeMessages
 		if msg.Data == nil {
 			b.WriteString(`null`)
 		} else {
@@ -369,15 +489,11 @@ func (sd *SignDoc) writeMessagesJSON(b *bytes.Buffer) error {
 // writeJSON writes the SignDocFee to the buffer in deterministic JSON format.
 func (f *SignDocFee) writeJSON(b *bytes.Buffer) {
 	b.WriteString(`{"amount":[`)
-	for i, coin := range f.Amount {
+	for i := range f.Amount {
 		if i > 0 {
 			b.WriteString(`,`)
 		}
-		b.WriteString(`{"denom":`)
-		b.WriteString(cramberry.EscapeJSONString(coin.Denom))
-		b.WriteString(`,"amount":`)
-		b.WriteString(cramberry.EscapeJSONString(coin.Amount))
-		b.WriteString(`}`)
+		f.Amount[i].writeJSON(b)
 	}
 	b.WriteString(`],"gas_limit":`)
 	b.WriteString(cramberry.EscapeJSONString(f.GasLimit))
@@ -385,6 +501,14 @@ func (f *SignDocFee) writeJSON(b *bytes.Buffer) {
 }
 
 // writeJSON writes the SignDocRatio to the buffer in deterministic JSON format.
+func (c *SignDocCoin) writeJSON(b *bytes.Buffer) {
+	b.WriteString(`{"denom":`)
+	b.WriteString(cramberry.EscapeJSONString(c.Denom))
+	b.WriteString(`,"amount":`)
+	b.WriteString(cramberry.EscapeJSONString(c.Amount))
+	b.WriteString(`}`)
+}
+
 func (r *SignDocRatio) writeJSON(b *bytes.Buffer) {
 	b.WriteString(`{"numerator":`)
 	b.WriteString(cramberry.EscapeJSONString(r.Numerator))
@@ -474,33 +598,74 @@ func isNFCNormalized(s string) bool {
 //
 // INVARIANT: GetSignBytes() returns the same result for equivalent SignDocs.
 func (sd *SignDoc) GetSignBytes() ([]byte, error) {
-	jsonBytes, err := sd.ToJSON()
+	buf := signDocBufferPool.Get().(*[]byte)
+	defer func() {
+		if cap(*buf) <= maxPooledSignDocBuffer {
+			signDocBufferPool.Put(buf)
+		}
+	}()
+
+	jsonBytes, err := sd.AppendJSON((*buf)[:0])
 	if err != nil {
 		return nil, fmt.Errorf("failed to serialize SignDoc: %w", err)
 	}
+	*buf = jsonBytes[:0]
 
 	hash := sha256.Sum256(jsonBytes)
 	return hash[:], nil
 }
 
+// maxPooledSignDocBuffer bounds the buffers kept by signDocBufferPool so one
+// large SignDoc does not pin memory
+const maxPooledSignDocBuffer = 64 * 1024
+
+// signDocBufferPool holds serialization buffers for GetSignBytes, whose JSON
+// output is hashed and discarded
+var signDocBufferPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, 0, 1024)
+		return &buf
+	},
+}
+
 // ValidateBasic performs stateless validation of the SignDoc.
 //
 // SECURITY: This validation includes bounds checking to prevent DoS attacks:
 // - Maximum message count: MaxMessagesPerSignDoc (256)
 // - Maximum message data size: MaxMessageDataSize (64KB)
 // - Maximum fee coin count: MaxFeeCoins (16)
+// - Maximum memo size: MaxMemoBytes (512), see DefaultMemoPolicy
+//
+// SECURITY: All strings must be valid UTF-8, and message data must be compact
+// JSON without duplicate object keys at any nesting level (see
+// ValidateNoDuplicateKeys) or unpaired surrogate escapes (see
+// ValidateMessageDataUTF8).
 func (sd *SignDoc) ValidateBasic() error {
-	if sd.Version != SignDocVersion {
-		return fmt.Errorf("%w: unsupported SignDoc version %q, expected %q",
-			ErrSignDocMismatch, sd.Version, SignDocVersion)
+	if err := ValidateSignDocVersion(sd.Version); err != nil {
+		return fmt.Errorf("%w: %w %q, expected one of %v",
+			ErrSignDocMismatch, ErrUnsupportedVersion, sd.Version, SupportedSignDocVersions)
 	}
 
 	if sd.ChainID == "" {
-		return fmt.Errorf("%w: chain_id cannot be empty", ErrSignDocMismatch)
+		return fmt.Errorf("%w: %w: chain_id cannot be empty", ErrSignDocMismatch, ErrEmptyField)
 	}
 
 	if sd.Account == "" {
-		return fmt.Errorf("%w: account cannot be empty", ErrSignDocMismatch)
+		return fmt.Errorf("%w: %w: account cannot be empty", ErrSignDocMismatch, ErrEmptyField)
+	}
+
+	// SECURITY: Reject malformed UTF-8 before anything else inspects the
+	// strings; its escaping differs across implementations.
+	for _, field := range []struct{ name, value string }{
+		{"chain_id", sd.ChainID},
+		{"account", sd.Account},
+		{"memo", sd.Memo},
+		{"fee_payer", sd.FeePayer},
+		{"fee_granter", sd.FeeGranter},
+	} {
+		if err := validateUTF8String(field.value); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrSignDocMismatch, field.name, err)
+		}
 	}
 
 	// SECURITY: Validate Unicode NFC normalization for all string fields.
@@ -508,65 +673,165 @@ func (sd *SignDoc) ValidateBasic() error {
 	// that normalize differently. Failing fast ensures consistent behavior.
 	// See: https://unicode.org/reports/tr15/
 	if !isNFCNormalized(sd.ChainID) {
-		return fmt.Errorf("%w: chain_id is not Unicode NFC-normalized (normalize with golang.org/x/text/unicode/norm.NFC.String before signing)", ErrSignDocMismatch)
+		return fmt.Errorf("%w: chain_id is %w (normalize with golang.org/x/text/unicode/norm.NFC.String before signing)", ErrSignDocMismatch, ErrNotNFC)
 	}
 	if !isNFCNormalized(sd.Account) {
-		return fmt.Errorf("%w: account is not Unicode NFC-normalized (normalize with golang.org/x/text/unicode/norm.NFC.String before signing)", ErrSignDocMismatch)
+		return fmt.Errorf("%w: account is %w (normalize with golang.org/x/text/unicode/norm.NFC.String before signing)", ErrSignDocMismatch, ErrNotNFC)
 	}
 	if !isNFCNormalized(sd.Memo) {
-		return fmt.Errorf("%w: memo is not Unicode NFC-normalized (normalize with golang.org/x/text/unicode/norm.NFC.String before signing)", ErrSignDocMismatch)
+		return fmt.Errorf("%w: memo is %w (normalize with golang.org/x/text/unicode/norm.NFC.String before signing)", ErrSignDocMismatch, ErrNotNFC)
+	}
+
+	// SECURITY: Reject malformed or visually confusable chain IDs
+	if err := ValidateChainID(sd.ChainID); err != nil {
+		return fmt.Errorf("%w: chain_id: %w", ErrSignDocMismatch, err)
+	}
+
+	// SECURITY: Bound the memo and reject control characters that could
+	// alter how it is displayed to signers
+	if err := DefaultMemoPolicy().Check(sd.Memo); err != nil {
+		return fmt.Errorf("%w: memo: %w", ErrSignDocMismatch, err)
 	}
 
 	if len(sd.Messages) == 0 {
-		return fmt.Errorf("%w: SignDoc must contain at least one message", ErrSignDocMismatch)
+		return fmt.Errorf("%w: %w", ErrSignDocMismatch, ErrNoMessages)
 	}
 
 	// SECURITY: Limit message count to prevent DoS via memory/CPU exhaustion
 	if len(sd.Messages) > MaxMessagesPerSignDoc {
-		return fmt.Errorf("%w: too many messages (%d > %d)",
-			ErrSignDocMismatch, len(sd.Messages), MaxMessagesPerSignDoc)
+		return fmt.Errorf("%w: %w (%d > %d)",
+			ErrSignDocMismatch, ErrTooManyMessages, len(sd.Messages), MaxMessagesPerSignDoc)
 	}
 
 	// Validate each message
 	for i, msg := range sd.Messages {
 		if msg.Type == "" {
-			return fmt.Errorf("%w: message %d has empty type", ErrSignDocMismatch, i)
+			return fmt.Errorf("%w: %w: message %d has empty type", ErrSignDocMismatch, ErrEmptyField, i)
+		}
+
+		if err := validateUTF8String(msg.Type); err != nil {
+			return fmt.Errorf("%w: message %d type: %v", ErrSignDocMismatch, i, err)
 		}
 
 		// SECURITY: Validate message type is NFC-normalized
 		if !isNFCNormalized(msg.Type) {
-			return fmt.Errorf("%w: message %d type is not Unicode NFC-normalized", ErrSignDocMismatch, i)
+			return fmt.Errorf("%w: message %d type is %w", ErrSignDocMismatch, i, ErrNotNFC)
 		}
 
 		// SECURITY: Limit message data size to prevent memory exhaustion
 		if len(msg.Data) > MaxMessageDataSize {
-			return fmt.Errorf("%w: message %d data too large (%d > %d)",
-				ErrSignDocMismatch, i, len(msg.Data), MaxMessageDataSize)
+			return fmt.Errorf("%w: message %d %w (%d > %d)",
+				ErrSignDocMismatch, i, ErrDataTooLarge, len(msg.Data), MaxMessageDataSize)
 		}
 
 		// SECURITY: Validate message data is compact JSON to ensure deterministic signing.
 		// Non-compact JSON (with whitespace outside strings) can cause signature mismatches
 		// across implementations. This catches the most common canonicalization issues.
-		// NOTE: This does NOT validate key ordering - that remains the caller's responsibility.
+		// NOTE: This does NOT validate key ordering - use ValidateBasicStrict for that.
 		if !isCompactJSON(msg.Data) {
-			return fmt.Errorf("%w: message %d data is not compact JSON (contains whitespace outside strings)",
-				ErrSignDocMismatch, i)
+			return fmt.Errorf("%w: message %d %w (contains whitespace outside strings)",
+				ErrSignDocMismatch, i, ErrNonCompactData)
+		}
+
+		// SECURITY: Reject malformed UTF-8 and unpaired surrogate escapes
+		if err := ValidateMessageDataUTF8(msg.Data); err != nil {
+			return fmt.Errorf("message %d: %w", i, err)
+		}
+
+		// SECURITY: Reject duplicate keys at any nesting level; parsers disagree on
+		// which duplicate wins, so the signed and executed values could differ.
+		if err := ValidateNoDuplicateKeys(msg.Data); err != nil {
+			return fmt.Errorf("message %d: %w", i, err)
 		}
 	}
 
 	// Validate fee
 	if err := sd.Fee.ValidateBasic(); err != nil {
-		return fmt.Errorf("%w: invalid fee: %v", ErrSignDocMismatch, err)
+		return fmt.Errorf("%w: invalid fee: %w", ErrSignDocMismatch, err)
 	}
 
 	// Validate fee slippage
 	if err := sd.FeeSlippage.ValidateBasic(); err != nil {
-		return fmt.Errorf("%w: invalid fee_slippage: %v", ErrSignDocMismatch, err)
+		return fmt.Errorf("%w: invalid fee_slippage: %w", ErrSignDocMismatch, err)
+	}
+
+	// Validate tip
+	if err := sd.validateTip(); err != nil {
+		return fmt.Errorf("%w: %w: %w", ErrSignDocMismatch, ErrInvalidTip, err)
+	}
+
+	// Validate validity window
+	if err := validateValidityWindow(sd.NotBefore, sd.NotAfter); err != nil {
+		return fmt.Errorf("%w: %v", ErrSignDocMismatch, err)
+	}
+
+	if err := validateFeeSponsors(AccountName(sd.Account), AccountName(sd.FeePayer), AccountName(sd.FeeGranter)); err != nil {
+		return fmt.Errorf("%w: %v", ErrSignDocMismatch, err)
+	}
+
+	if err := sd.validateSigners(); err != nil {
+		return fmt.Errorf("%w: %w: %w", ErrSignDocMismatch, ErrInvalidSigners, err)
 	}
 
 	return nil
 }
 
+// validateSigners checks that Signers, if set, lists between 2 and
+// MaxSigners distinct valid accounts including Account, none of which is a
+// fee sponsor.
+//
+// SECURITY: One encoding per meaning: a single-signer transaction omits
+// Signers, so it cannot be signed in two forms.
+func (sd *SignDoc) validateSigners() error {
+	if sd.Signers == nil {
+		return nil
+	}
+	if len(sd.Signers) < 2 {
+		return fmt.Errorf("must list at least 2 accounts (omit it for a single signer)")
+	}
+	if len(sd.Signers) > MaxSigners {
+		return fmt.Errorf("too many signers (%d > %d)", len(sd.Signers), MaxSigners)
+	}
+
+	names := make([]AccountName, len(sd.Signers))
+	for i, signer := range sd.Signers {
+		names[i] = AccountName(signer)
+	}
+	if err := validateSignerSet(names, AccountName(sd.FeePayer), AccountName(sd.FeeGranter)); err != nil {
+		return err
+	}
+	for _, signer := range sd.Signers {
+		if signer == sd.Account {
+			return nil
+		}
+	}
+	return fmt.Errorf("account %s is not a signer", sd.Account)
+}
+
+// validateTip checks that the tip is present exactly in version 2 SignDocs
+// and is a valid, non-zero coin.
+//
+// SECURITY: One encoding per meaning: a zero tip is expressed by omitting
+// it (version 1), so the same transaction cannot be signed in two forms.
+func (sd *SignDoc) validateTip() error {
+	if sd.Tip == nil {
+		if sd.Version == SignDocVersionTip {
+			return fmt.Errorf("version %s requires a tip", SignDocVersionTip)
+		}
+		return nil
+	}
+	if sd.Version != SignDocVersionTip {
+		return fmt.Errorf("tip requires version %s, got %q", SignDocVersionTip, sd.Version)
+	}
+	if err := sd.Tip.ValidateBasic(); err != nil {
+		return err
+	}
+	if amount, _ := strconv.ParseUint(sd.Tip.Amount, 10, 64); amount == 0 {
+		return fmt.Errorf("amount must be positive")
+	}
+	return nil
+}
+
 // ValidateBasic performs stateless validation of SignDocFee.
 //
 // INVARIANT: GasLimit MUST be a valid non-negative decimal string.
@@ -574,15 +839,15 @@ func (sd *SignDoc) ValidateBasic() error {
 func (f *SignDocFee) ValidateBasic() error {
 	// Validate gas limit is a valid uint64 string
 	if f.GasLimit == "" {
-		return fmt.Errorf("gas_limit cannot be empty")
+		return fmt.Errorf("%w: gas_limit cannot be empty", ErrEmptyField)
 	}
 	if _, err := strconv.ParseUint(f.GasLimit, 10, 64); err != nil {
-		return fmt.Errorf("invalid gas_limit %q: must be a decimal string", f.GasLimit)
+		return fmt.Errorf("invalid gas_limit %q: %w", f.GasLimit, ErrNotDecimal)
 	}
 
 	// SECURITY: Limit number of fee coins to prevent DoS
 	if len(f.Amount) > MaxFeeCoins {
-		return fmt.Errorf("too many fee coins (%d > %d)", len(f.Amount), MaxFeeCoins)
+		return fmt.Errorf("%w (%d > %d)", ErrTooManyFeeCoins, len(f.Amount), MaxFeeCoins)
 	}
 
 	// Validate each coin
@@ -601,21 +866,21 @@ func (f *SignDocFee) ValidateBasic() error {
 // INVARIANT: Denominator MUST NOT be "0".
 func (r *SignDocRatio) ValidateBasic() error {
 	if r.Numerator == "" {
-		return fmt.Errorf("numerator cannot be empty")
+		return fmt.Errorf("%w: numerator cannot be empty", ErrEmptyField)
 	}
 	if _, err := strconv.ParseUint(r.Numerator, 10, 64); err != nil {
-		return fmt.Errorf("invalid numerator %q: must be a decimal string", r.Numerator)
+		return fmt.Errorf("invalid numerator %q: %w", r.Numerator, ErrNotDecimal)
 	}
 
 	if r.Denominator == "" {
-		return fmt.Errorf("denominator cannot be empty")
+		return fmt.Errorf("%w: denominator cannot be empty", ErrEmptyField)
 	}
 	denom, err := strconv.ParseUint(r.Denominator, 10, 64)
 	if err != nil {
-		return fmt.Errorf("invalid denominator %q: must be a decimal string", r.Denominator)
+		return fmt.Errorf("invalid denominator %q: %w", r.Denominator, ErrNotDecimal)
 	}
 	if denom == 0 {
-		return fmt.Errorf("denominator cannot be zero")
+		return ErrZeroDenominator
 	}
 
 	return nil
@@ -628,21 +893,24 @@ func (r *SignDocRatio) ValidateBasic() error {
 // INVARIANT: Amount MUST be a valid non-negative decimal string.
 func (c *SignDocCoin) ValidateBasic() error {
 	if c.Denom == "" {
-		return fmt.Errorf("denom cannot be empty")
+		return fmt.Errorf("%w: denom cannot be empty", ErrEmptyField)
 	}
 	if len(c.Denom) > 64 {
-		return fmt.Errorf("denom too long (%d > 64)", len(c.Denom))
+		return fmt.Errorf("%w: denom too long (%d > 64)", ErrFieldTooLong, len(c.Denom))
+	}
+	if err := validateUTF8String(c.Denom); err != nil {
+		return fmt.Errorf("denom: %v", err)
 	}
 	// SECURITY: Validate denom is NFC-normalized to prevent signature mismatches
 	if !isNFCNormalized(c.Denom) {
-		return fmt.Errorf("denom is not Unicode NFC-normalized")
+		return fmt.Errorf("denom is %w", ErrNotNFC)
 	}
 
 	if c.Amount == "" {
-		return fmt.Errorf("amount cannot be empty")
+		return fmt.Errorf("%w: amount cannot be empty", ErrEmptyField)
 	}
 	if _, err := strconv.ParseUint(c.Amount, 10, 64); err != nil {
-		return fmt.Errorf("invalid amount %q: must be a decimal string", c.Amount)
+		return fmt.Errorf("invalid amount %q: %w", c.Amount, ErrNotDecimal)
 	}
 
 	return nil
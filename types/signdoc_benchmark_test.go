@@ -12,7 +12,7 @@ import (
 // createSmallSignDoc creates a SignDoc with 1 message and minimal fields.
 // Target: small transaction with single operation.
 func createSmallSignDoc() *SignDoc {
-	sd := NewSignDoc("test-chain", 1, "alice", 1, "")
+	sd := mustNewSignDoc("test-chain", 1, "alice", 1, "")
 	sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"from":"alice","to":"bob","amount":"100"}`))
 	return sd
 }
@@ -20,7 +20,7 @@ func createSmallSignDoc() *SignDoc {
 // createMediumSignDoc creates a SignDoc with 3 messages and typical fields.
 // Target: typical multi-message transaction.
 func createMediumSignDoc() *SignDoc {
-	sd := NewSignDoc("mainnet-production", 12345, "cosmos1abc...xyz", 12345, "batch transfer")
+	sd := mustNewSignDoc("mainnet-production", 12345, "cosmos1abc...xyz", 12345, "batch transfer")
 	sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"from":"cosmos1abc...xyz","to":"cosmos1def...uvw","amount":{"denom":"stake","amount":"1000000"}}`))
 	sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"from":"cosmos1abc...xyz","to":"cosmos1ghi...rst","amount":{"denom":"stake","amount":"500000"}}`))
 	sd.AddMessage("/punnet.staking.v1.MsgDelegate", json.RawMessage(`{"delegator":"cosmos1abc...xyz","validator":"cosmosvaloper1xyz...","amount":{"denom":"stake","amount":"2000000"}}`))
@@ -32,7 +32,7 @@ func createMediumSignDoc() *SignDoc {
 func createLargeSignDoc() *SignDoc {
 	// Max memo is 512 bytes
 	memo := strings.Repeat("x", 512)
-	sd := NewSignDoc("mainnet-production-chain-id-long-name", 999999999, "cosmos1verylongaddresshere000000000000000000abc", 999999999, memo)
+	sd := mustNewSignDoc("mainnet-production-chain-id-long-name", 999999999, "cosmos1verylongaddresshere000000000000000000abc", 999999999, memo)
 
 	// Add 10 messages with substantial data
 	for i := 0; i < 10; i++ {
@@ -266,7 +266,10 @@ func BenchmarkSignDocToJSON_MessageScaling(b *testing.B) {
 
 	for _, count := range messageCounts {
 		b.Run(fmt.Sprintf("Messages_%d", count), func(b *testing.B) {
-			sd := NewSignDoc("test-chain", 1, "alice", 1, "")
+			sd, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+			if err != nil {
+				b.Fatalf("NewSignDoc failed: %v", err)
+			}
 			for i := 0; i < count; i++ {
 				sd.AddMessage(fmt.Sprintf("/msg/%d", i), json.RawMessage(`{"key":"value"}`))
 			}
@@ -289,7 +292,10 @@ func BenchmarkSignDocGetSignBytes_MessageScaling(b *testing.B) {
 
 	for _, count := range messageCounts {
 		b.Run(fmt.Sprintf("Messages_%d", count), func(b *testing.B) {
-			sd := NewSignDoc("test-chain", 1, "alice", 1, "")
+			sd, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+			if err != nil {
+				b.Fatalf("NewSignDoc failed: %v", err)
+			}
 			for i := 0; i < count; i++ {
 				sd.AddMessage(fmt.Sprintf("/msg/%d", i), json.RawMessage(`{"key":"value"}`))
 			}
@@ -316,7 +322,10 @@ func BenchmarkSignDocToJSON_DataSizeScaling(b *testing.B) {
 
 	for _, size := range dataSizes {
 		b.Run(fmt.Sprintf("DataSize_%d", size), func(b *testing.B) {
-			sd := NewSignDoc("test-chain", 1, "alice", 1, "")
+			sd, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+			if err != nil {
+				b.Fatalf("NewSignDoc failed: %v", err)
+			}
 			data := fmt.Sprintf(`{"payload":"%s"}`, strings.Repeat("x", size-15))
 			sd.AddMessage("/msg/large", json.RawMessage(data))
 			b.ResetTimer()
@@ -331,3 +340,79 @@ func BenchmarkSignDocToJSON_DataSizeScaling(b *testing.B) {
 		})
 	}
 }
+
+// ============================================================================
+// Buffer Reuse and Transaction Caching Benchmarks
+// ============================================================================
+
+// BenchmarkSignDocAppendJSON_Medium measures serialization into a reused buffer.
+// Compare with BenchmarkSignDocToJSON_Medium.
+func BenchmarkSignDocAppendJSON_Medium(b *testing.B) {
+	sd := createMediumSignDoc()
+	buf := make([]byte, 0, 4096)
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		out, err := sd.AppendJSON(buf[:0])
+		if err != nil {
+			b.Fatal(err)
+		}
+		buf = out
+	}
+}
+
+// createBenchmarkTransaction creates a transaction with n SignDocSerializable messages.
+func createBenchmarkTransaction(n int) *Transaction {
+	msgs := make([]Message, n)
+	for i := range msgs {
+		msgs[i] = &serializableMessage{
+			MsgType: "/punnet.bank.v1.MsgSend",
+			Signers: []AccountName{"alice"},
+			From:    "alice",
+			To:      fmt.Sprintf("bob%d", i),
+			Amount:  uint64(i + 1),
+			Denom:   "stake",
+		}
+	}
+	tx := NewTransaction("alice", 1, msgs, nil)
+	tx.Fee = Fee{Amount: NewCoins(NewCoin("stake", 100)), GasLimit: 200000}
+	tx.FeeSlippage = Ratio{Numerator: 1, Denominator: 100}
+	return tx
+}
+
+// BenchmarkTransactionSignBytes compares computing sign bytes with a cold
+// message cache (every message re-serialized, the previous behavior) and a
+// warm one (sign then verify the same transaction).
+func BenchmarkTransactionSignBytes(b *testing.B) {
+	for _, n := range []int{1, 10} {
+		tx := createBenchmarkTransaction(n)
+
+		signBytes := func(b *testing.B) {
+			sd, err := tx.ToSignDoc("test-chain", 1)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := sd.GetSignBytes(); err != nil {
+				b.Fatal(err)
+			}
+		}
+
+		b.Run(fmt.Sprintf("Uncached_%dMsgs", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				tx.InvalidateSignDocCache()
+				signBytes(b)
+			}
+		})
+
+		b.Run(fmt.Sprintf("Cached_%dMsgs", n), func(b *testing.B) {
+			signBytes(b)
+			b.ResetTimer()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				signBytes(b)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package types
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingMessage counts SignDocData calls
+type countingMessage struct {
+	serializableMessage
+	calls atomic.Int64
+}
+
+func (m *countingMessage) SignDocData() (json.RawMessage, error) {
+	m.calls.Add(1)
+	return m.serializableMessage.SignDocData()
+}
+
+// valueMessage is a non-pointer message, which is never cached
+type valueMessage struct {
+	calls *atomic.Int64
+}
+
+func (m valueMessage) Type() string              { return "/test.value" }
+func (m valueMessage) ValidateBasic() error      { return nil }
+func (m valueMessage) GetSigners() []AccountName { return []AccountName{"alice"} }
+func (m valueMessage) SignDocData() (json.RawMessage, error) {
+	m.calls.Add(1)
+	return json.RawMessage(`{}`), nil
+}
+
+func newCountingMessage(amount uint64) *countingMessage {
+	return &countingMessage{serializableMessage: serializableMessage{
+		MsgType: "/punnet.bank.v1.MsgSend",
+		Signers: []AccountName{"alice"},
+		From:    "alice",
+		To:      "bob",
+		Amount:  amount,
+		Denom:   "stake",
+	}}
+}
+
+func TestTransaction_ToSignDocCachesMessages(t *testing.T) {
+	msg := newCountingMessage(100)
+	tx := NewTransaction("alice", 1, []Message{msg}, nil)
+
+	first, err := tx.ToSignDoc("test-chain", 1)
+	require.NoError(t, err)
+	second, err := tx.ToSignDoc("test-chain", 1)
+	require.NoError(t, err)
+
+	require.Equal(t, int64(1), msg.calls.Load())
+	require.True(t, first.Equals(second))
+
+	// Returned SignDocs do not share the cached message slice
+	first.Messages[0].Type = "/tampered"
+	third, err := tx.ToSignDoc("test-chain", 1)
+	require.NoError(t, err)
+	require.Equal(t, "/punnet.bank.v1.MsgSend", third.Messages[0].Type)
+}
+
+func TestTransaction_SignDocCacheInvalidation(t *testing.T) {
+	msg := newCountingMessage(100)
+	tx := NewTransaction("alice", 1, []Message{msg}, nil)
+
+	signBytes := func() []byte {
+		t.Helper()
+		sd, err := tx.ToSignDoc("test-chain", 1)
+		require.NoError(t, err)
+		bz, err := sd.GetSignBytes()
+		require.NoError(t, err)
+		return bz
+	}
+
+	original := signBytes()
+
+	// Replacing a message invalidates the cache
+	tx.Messages[0] = newCountingMessage(200)
+	replaced := signBytes()
+	require.NotEqual(t, original, replaced)
+
+	// Appending a message invalidates the cache
+	tx.Messages = append(tx.Messages, newCountingMessage(300))
+	sd, err := tx.ToSignDoc("test-chain", 1)
+	require.NoError(t, err)
+	require.Len(t, sd.Messages, 2)
+
+	// In-place mutation requires explicit invalidation
+	tx.Messages = []Message{msg}
+	before := signBytes()
+	msg.Amount = 999
+	require.Equal(t, before, signBytes())
+	tx.InvalidateSignDocCache()
+	require.NotEqual(t, before, signBytes())
+}
+
+func TestTransaction_VerifyIgnoresSignDocCache(t *testing.T) {
+	priv := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{3}, ed25519.SeedSize))
+	pub := priv.Public().(ed25519.PublicKey)
+	account := NewAccount("alice", pub)
+	account.Nonce = 1
+
+	msg := newCountingMessage(100)
+	tx := NewTransaction("alice", 1, []Message{msg}, NewAuthorization())
+	tx.FeeSlippage = Ratio{Numerator: 0, Denominator: 1}
+
+	signDoc, err := tx.ToSignDoc("test-chain", 1)
+	require.NoError(t, err)
+	signBytes, err := signDoc.GetSignBytes()
+	require.NoError(t, err)
+	tx.Authorization.Signatures = []Signature{{Algorithm: AlgorithmEd25519, PubKey: pub, Signature: ed25519.Sign(priv, signBytes)}}
+
+	getter := newMockAccountGetter()
+	require.NoError(t, tx.VerifyAuthorization("test-chain", account, getter))
+	verified, err := tx.VerifiedSignBytes("test-chain", account)
+	require.NoError(t, err)
+
+	// Mutating the message in place leaves the cached bytes stale, but
+	// verification covers the current content
+	msg.Amount = 999
+	cached, err := tx.ToSignDoc("test-chain", 1)
+	require.NoError(t, err)
+	cachedBytes, err := cached.GetSignBytes()
+	require.NoError(t, err)
+	require.Equal(t, signBytes, cachedBytes)
+	require.ErrorIs(t, tx.VerifyAuthorization("test-chain", account, getter), ErrInvalidSignature)
+
+	mutated, err := tx.VerifiedSignBytes("test-chain", account)
+	require.NoError(t, err)
+	require.NotEqual(t, verified, mutated)
+}
+
+func TestTransaction_SignDocCacheSkipsValueMessages(t *testing.T) {
+	calls := &atomic.Int64{}
+	tx := NewTransaction("alice", 1, []Message{valueMessage{calls: calls}}, nil)
+
+	for i := 0; i < 2; i++ {
+		_, err := tx.ToSignDoc("test-chain", 1)
+		require.NoError(t, err)
+	}
+	require.Equal(t, int64(2), calls.Load())
+}
+
+func TestTransaction_SignDocCacheErrorsNotCached(t *testing.T) {
+	tx := NewTransaction("alice", 1, []Message{&failingMessage{MsgType: "/fail"}}, nil)
+
+	_, err := tx.ToSignDoc("test-chain", 1)
+	require.Error(t, err)
+
+	tx.Messages = []Message{newCountingMessage(1)}
+	_, err = tx.ToSignDoc("test-chain", 1)
+	require.NoError(t, err)
+}
+
+func TestTransaction_SignDocCacheConcurrent(t *testing.T) {
+	tx := NewTransaction("alice", 1, []Message{newCountingMessage(1), newCountingMessage(2)}, nil)
+
+	want, err := tx.ToSignDoc("test-chain", 1)
+	require.NoError(t, err)
+	tx.InvalidateSignDocCache()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				sd, err := tx.ToSignDoc("test-chain", 1)
+				if err != nil || !sd.Equals(want) {
+					t.Errorf("concurrent ToSignDoc returned %v, %v", sd, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSignDoc_AppendJSON(t *testing.T) {
+	sd, err := NewSignDoc("test-chain", 1, "alice", 1, "memo")
+	require.NoError(t, err)
+	sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"amount":"100"}`))
+
+	want, err := sd.ToJSON()
+	require.NoError(t, err)
+
+	prefix := []byte("prefix:")
+	got, err := sd.AppendJSON(append([]byte(nil), prefix...))
+	require.NoError(t, err)
+	require.Equal(t, append(prefix, want...), got)
+
+	// Reusing a buffer with enough capacity does not reallocate
+	buf := make([]byte, 0, 4096)
+	out, err := sd.AppendJSON(buf)
+	require.NoError(t, err)
+	require.Equal(t, want, out)
+	require.Equal(t, &buf[:1][0], &out[:1][0])
+}
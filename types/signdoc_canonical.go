@@ -0,0 +1,471 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/blockberries/cramberry/pkg/cramberry"
+)
+
+// MaxMessageDataDepth limits the nesting depth of objects and arrays in message data
+// when it is canonicalized or checked for canonical key order.
+// SECURITY: Prevents stack exhaustion from deeply nested payloads such as [[[[...]]]].
+const MaxMessageDataDepth = 64
+
+// CanonicalizeMessageData rewrites message data into canonical JSON form.
+//
+// The canonical form is:
+//   - Compact: no whitespace outside of string values
+//   - Ordered: object keys sorted in ascending byte order (at every nesting level)
+//   - Escaped: strings re-escaped with the same rules as SignDoc.ToJSON
+//
+// Number literals are preserved verbatim (decoded as json.Number), so {"amount":100}
+// is never confused with {"amount":"100"} and no precision is lost on large integers.
+// Array element order is semantically significant and is NOT changed.
+//
+// PRECONDITION: data is a single valid JSON value.
+// POSTCONDITION: ValidateCanonicalKeyOrder(result) returns nil.
+// POSTCONDITION: CanonicalizeMessageData(result) returns bytes identical to result.
+//
+// SECURITY: Objects with duplicate keys are rejected rather than silently collapsed,
+// since different JSON parsers disagree on which duplicate wins.
+//
+// Empty input returns nil (serialized as "null" by SignDoc.ToJSON).
+func CanonicalizeMessageData(data json.RawMessage) (json.RawMessage, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if len(data) > MaxMessageDataSize {
+		return nil, fmt.Errorf("%w: message data too large (%d > %d)",
+			ErrSignDocMismatch, len(data), MaxMessageDataSize)
+	}
+
+	dec := newMessageDataDecoder(data)
+	var buf bytes.Buffer
+	buf.Grow(len(data))
+	if err := writeCanonicalValue(dec, &buf, 0); err != nil {
+		return nil, fmt.Errorf("%w: cannot canonicalize message data: %v", ErrSignDocMismatch, err)
+	}
+	if err := expectEOF(dec); err != nil {
+		return nil, fmt.Errorf("%w: cannot canonicalize message data: %v", ErrSignDocMismatch, err)
+	}
+
+	return json.RawMessage(buf.Bytes()), nil
+}
+
+// ValidateCanonicalKeyOrder checks that every JSON object in data has its keys in
+// strictly ascending byte order, without rewriting anything.
+//
+// Unlike comparing against CanonicalizeMessageData output, this check does not
+// constrain string escaping, so data produced by encoding/json (which escapes
+// '<', '>' and '&') passes as long as keys are sorted.
+//
+// POSTCONDITION: Returns nil for empty data, "null", and scalar values.
+// POSTCONDITION: Returns an error wrapping ErrSignDocMismatch on out-of-order or
+// duplicate keys, malformed JSON, or nesting deeper than MaxMessageDataDepth.
+func ValidateCanonicalKeyOrder(data json.RawMessage) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	dec := newMessageDataDecoder(data)
+	if err := checkKeyOrder(dec, 0); err != nil {
+		return fmt.Errorf("%w: message data is not in canonical key order: %v", ErrSignDocMismatch, err)
+	}
+	if err := expectEOF(dec); err != nil {
+		return fmt.Errorf("%w: message data is not in canonical key order: %v", ErrSignDocMismatch, err)
+	}
+	return nil
+}
+
+// ValidateNoDuplicateKeys checks that no JSON object in data, at any nesting
+// level, has two members with the same key.
+//
+// Keys are compared after unescaping, so {"a":1,"\u0061":2} is a duplicate.
+//
+// SECURITY: Parsers disagree on which of several duplicate members wins (the
+// first, the last, or an error), so a message carrying {"amount":1,"amount":2}
+// could be signed meaning one thing and executed meaning another.
+//
+// POSTCONDITION: Returns nil for empty data, "null", and scalar values.
+// POSTCONDITION: Returns an error wrapping ErrSignDocMismatch on duplicate keys,
+// malformed JSON, or nesting deeper than MaxMessageDataDepth.
+//
+// Complexity: O(n) for n bytes of data.
+func ValidateNoDuplicateKeys(data json.RawMessage) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	dec := newMessageDataDecoder(data)
+	if err := checkDuplicateKeys(dec, 0); err != nil {
+		return fmt.Errorf("%w: invalid message data: %v", ErrSignDocMismatch, err)
+	}
+	if err := expectEOF(dec); err != nil {
+		return fmt.Errorf("%w: invalid message data: %v", ErrSignDocMismatch, err)
+	}
+	return nil
+}
+
+// ValidateNoBareNumbers checks that data contains no JSON number literals:
+// every numeric value, at any nesting level, must be encoded as a string
+// (e.g. {"amount":"100"}, not {"amount":100}). The error names the path of
+// the first bare number found, such as amount.coins[0].
+//
+// SECURITY: JavaScript parses every JSON number as a float64, so an integer
+// above 2^53 or a float in message data is read differently by clients and
+// other implementations than by the chain, and re-serialized data no longer
+// hashes to the signed bytes. Strings round-trip exactly everywhere.
+//
+// POSTCONDITION: Returns nil for empty data and "null".
+// POSTCONDITION: Returns an error wrapping ErrSignDocMismatch on a bare
+// number, malformed JSON, or nesting deeper than MaxMessageDataDepth.
+//
+// Complexity: O(n) for n bytes of data.
+func ValidateNoBareNumbers(data json.RawMessage) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	dec := newMessageDataDecoder(data)
+	if err := checkBareNumbers(dec, "", 0); err != nil {
+		return fmt.Errorf("%w: invalid message data: %v", ErrSignDocMismatch, err)
+	}
+	if err := expectEOF(dec); err != nil {
+		return fmt.Errorf("%w: invalid message data: %v", ErrSignDocMismatch, err)
+	}
+	return nil
+}
+
+// ValidateBasicStrict performs ValidateBasic and additionally requires every
+// message's data to be in canonical key order.
+//
+// This closes the gap documented on AddMessage: ValidateBasic alone accepts
+// {"b":1,"a":2}, which signs differently from the semantically identical
+// {"a":2,"b":1}. Chains that want to reject such transactions outright should
+// call ValidateBasicStrict instead of ValidateBasic.
+//
+// Complexity: O(total message data size).
+func (sd *SignDoc) ValidateBasicStrict() error {
+	if err := sd.ValidateBasic(); err != nil {
+		return err
+	}
+
+	for i, msg := range sd.Messages {
+		if err := ValidateCanonicalKeyOrder(msg.Data); err != nil {
+			return fmt.Errorf("message %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// CanonicalizeMessages rewrites the data of every message in place using
+// CanonicalizeMessageData.
+//
+// POSTCONDITION: On success, ValidateBasicStrict's key-order check passes.
+// POSTCONDITION: On error, the SignDoc is left unmodified.
+func (sd *SignDoc) CanonicalizeMessages() error {
+	canonical := make([]json.RawMessage, len(sd.Messages))
+	for i, msg := range sd.Messages {
+		data, err := CanonicalizeMessageData(msg.Data)
+		if err != nil {
+			return fmt.Errorf("message %d: %w", i, err)
+		}
+		canonical[i] = data
+	}
+
+	for i := range sd.Messages {
+		sd.Messages[i].Data = canonical[i]
+	}
+	return nil
+}
+
+// newMessageDataDecoder returns a decoder that keeps number literals intact.
+func newMessageDataDecoder(data []byte) *json.Decoder {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec
+}
+
+// expectEOF returns an error if the decoder has any tokens left.
+func expectEOF(dec *json.Decoder) error {
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("unexpected data after top-level value")
+	}
+	return nil
+}
+
+// canonicalMember is a single decoded object member awaiting sorted output.
+type canonicalMember struct {
+	key   string
+	value []byte
+}
+
+// writeCanonicalValue reads one JSON value from dec and writes its canonical form to buf.
+func writeCanonicalValue(dec *json.Decoder, buf *bytes.Buffer, depth int) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		if depth >= MaxMessageDataDepth {
+			return fmt.Errorf("nesting depth exceeds %d", MaxMessageDataDepth)
+		}
+		switch t {
+		case '{':
+			return writeCanonicalObject(dec, buf, depth)
+		case '[':
+			return writeCanonicalArray(dec, buf, depth)
+		default:
+			return fmt.Errorf("unexpected delimiter %q", t)
+		}
+	case string:
+		buf.WriteString(cramberry.EscapeJSONString(t))
+	case json.Number:
+		buf.WriteString(t.String())
+	case bool:
+		if t {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+	case nil:
+		buf.WriteString("null")
+	default:
+		return fmt.Errorf("unexpected token %T", tok)
+	}
+	return nil
+}
+
+// writeCanonicalObject writes the remainder of an object (after '{') with sorted keys.
+func writeCanonicalObject(dec *json.Decoder, buf *bytes.Buffer, depth int) error {
+	var members []canonicalMember
+	seen := make(map[string]struct{})
+
+	for dec.More() {
+		keyTok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := keyTok.(string)
+		if !ok {
+			return fmt.Errorf("object key is not a string")
+		}
+		if _, dup := seen[key]; dup {
+			return fmt.Errorf("duplicate object key %q", key)
+		}
+		seen[key] = struct{}{}
+
+		var value bytes.Buffer
+		if err := writeCanonicalValue(dec, &value, depth+1); err != nil {
+			return err
+		}
+		members = append(members, canonicalMember{key: key, value: value.Bytes()})
+	}
+	if _, err := dec.Token(); err != nil { // consume '}'
+		return err
+	}
+
+	sort.Slice(members, func(i, j int) bool { return members[i].key < members[j].key })
+
+	buf.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(cramberry.EscapeJSONString(m.key))
+		buf.WriteByte(':')
+		buf.Write(m.value)
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// writeCanonicalArray writes the remainder of an array (after '[') preserving element order.
+func writeCanonicalArray(dec *json.Decoder, buf *bytes.Buffer, depth int) error {
+	buf.WriteByte('[')
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := writeCanonicalValue(dec, buf, depth+1); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil { // consume ']'
+		return err
+	}
+	buf.WriteByte(']')
+	return nil
+}
+
+// checkKeyOrder reads one JSON value from dec and verifies object key ordering within it.
+func checkKeyOrder(dec *json.Decoder, depth int) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil
+	}
+	if depth >= MaxMessageDataDepth {
+		return fmt.Errorf("nesting depth exceeds %d", MaxMessageDataDepth)
+	}
+
+	switch delim {
+	case '{':
+		first := true
+		var prev string
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key, ok := keyTok.(string)
+			if !ok {
+				return fmt.Errorf("object key is not a string")
+			}
+			if !first && key <= prev {
+				if key == prev {
+					return fmt.Errorf("duplicate object key %q", key)
+				}
+				return fmt.Errorf("key %q appears after %q but sorts before it", key, prev)
+			}
+			first = false
+			prev = key
+
+			if err := checkKeyOrder(dec, depth+1); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for dec.More() {
+			if err := checkKeyOrder(dec, depth+1); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unexpected delimiter %q", delim)
+	}
+
+	_, err = dec.Token() // consume closing delimiter
+	return err
+}
+
+// checkDuplicateKeys reads one JSON value from dec and verifies that no object
+// within it repeats a key.
+func checkDuplicateKeys(dec *json.Decoder, depth int) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil
+	}
+	if depth >= MaxMessageDataDepth {
+		return fmt.Errorf("nesting depth exceeds %d", MaxMessageDataDepth)
+	}
+
+	switch delim {
+	case '{':
+		seen := make(map[string]struct{})
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key, ok := keyTok.(string)
+			if !ok {
+				return fmt.Errorf("object key is not a string")
+			}
+			if _, dup := seen[key]; dup {
+				return fmt.Errorf("duplicate object key %q", key)
+			}
+			seen[key] = struct{}{}
+
+			if err := checkDuplicateKeys(dec, depth+1); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for dec.More() {
+			if err := checkDuplicateKeys(dec, depth+1); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unexpected delimiter %q", delim)
+	}
+
+	_, err = dec.Token() // consume closing delimiter
+	return err
+}
+
+// checkBareNumbers reads one JSON value from dec, found at path, and returns an
+// error if it is or contains a number literal.
+func checkBareNumbers(dec *json.Decoder, path string, depth int) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	var delim json.Delim
+	switch t := tok.(type) {
+	case json.Number:
+		if path == "" {
+			return fmt.Errorf("bare number %s: numbers must be encoded as strings", t)
+		}
+		return fmt.Errorf("bare number %s at %s: numbers must be encoded as strings", t, path)
+	case json.Delim:
+		delim = t
+	default:
+		return nil
+	}
+
+	if depth >= MaxMessageDataDepth {
+		return fmt.Errorf("nesting depth exceeds %d", MaxMessageDataDepth)
+	}
+
+	switch delim {
+	case '{':
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key, ok := keyTok.(string)
+			if !ok {
+				return fmt.Errorf("object key is not a string")
+			}
+
+			member := key
+			if path != "" {
+				member = path + "." + key
+			}
+			if err := checkBareNumbers(dec, member, depth+1); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for i := 0; dec.More(); i++ {
+			if err := checkBareNumbers(dec, fmt.Sprintf("%s[%d]", path, i), depth+1); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unexpected delimiter %q", delim)
+	}
+
+	_, err = dec.Token() // consume closing delimiter
+	return err
+}
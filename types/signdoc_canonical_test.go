@@ -0,0 +1,234 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalizeMessageData(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"already canonical", `{"a":1,"b":"x"}`, `{"a":1,"b":"x"}`},
+		{"reorders keys", `{"b":1,"a":2}`, `{"a":2,"b":1}`},
+		{"nested objects", `{"z":{"y":1,"x":2},"a":[{"d":1,"c":2}]}`, `{"a":[{"c":2,"d":1}],"z":{"x":2,"y":1}}`},
+		{"strips whitespace", "{ \"b\" : 1,\n \"a\" : [1, 2] }", `{"a":[1,2],"b":1}`},
+		{"preserves number literals", `{"big":18446744073709551615,"f":1.50,"e":1e3}`, `{"big":18446744073709551615,"e":1e3,"f":1.50}`},
+		{"preserves string vs number", `{"s":"100","n":100}`, `{"n":100,"s":"100"}`},
+		{"preserves array order", `[3,1,2]`, `[3,1,2]`},
+		{"scalars", `"hello"`, `"hello"`},
+		{"literals", `{"t":true,"f":false,"n":null}`, `{"f":false,"n":null,"t":true}`},
+		{"whitespace in strings kept", `{"k":"a b\tc"}`, `{"k":"a b\tc"}`},
+		{"unicode escape normalized", `{"k":"\u0041"}`, `{"k":"A"}`},
+		{"empty object", `{}`, `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CanonicalizeMessageData(json.RawMessage(tt.input))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(got))
+
+			// INVARIANT: Canonicalization is idempotent
+			again, err := CanonicalizeMessageData(got)
+			require.NoError(t, err)
+			assert.Equal(t, string(got), string(again))
+
+			assert.NoError(t, ValidateCanonicalKeyOrder(got))
+		})
+	}
+}
+
+func TestCanonicalizeMessageData_Empty(t *testing.T) {
+	got, err := CanonicalizeMessageData(nil)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestCanonicalizeMessageData_Rejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"duplicate keys", `{"a":1,"a":2}`},
+		{"nested duplicate keys", `{"x":{"a":1,"a":2}}`},
+		{"malformed", `{"a":`},
+		{"trailing data", `{"a":1}{"b":2}`},
+		{"too deep", strings.Repeat("[", MaxMessageDataDepth+1) + strings.Repeat("]", MaxMessageDataDepth+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CanonicalizeMessageData(json.RawMessage(tt.input))
+			require.Error(t, err)
+			assert.ErrorIs(t, err, ErrSignDocMismatch)
+		})
+	}
+}
+
+func TestValidateCanonicalKeyOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"empty", ``, false},
+		{"null", `null`, false},
+		{"sorted", `{"amount":"100","from":"alice","to":"bob"}`, false},
+		{"unsorted", `{"to":"bob","amount":"100"}`, true},
+		{"nested unsorted", `{"a":{"z":1,"b":2}}`, true},
+		{"unsorted inside array", `{"a":[{"z":1,"b":2}]}`, true},
+		{"duplicate", `{"a":1,"a":1}`, true},
+		{"html-escaped strings allowed", `{"a":"<b>"}`, false},
+		{"trailing data", `{"a":1} 1`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCanonicalKeyOrder(json.RawMessage(tt.input))
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrSignDocMismatch)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateNoDuplicateKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"empty", ``, false},
+		{"null", `null`, false},
+		{"scalar", `"a"`, false},
+		{"unsorted allowed", `{"to":"bob","amount":"100"}`, false},
+		{"same key in sibling objects", `[{"a":1},{"a":2}]`, false},
+		{"same key at different levels", `{"a":{"a":1}}`, false},
+		{"duplicate", `{"a":1,"a":2}`, true},
+		{"duplicate non-adjacent", `{"a":1,"b":2,"a":3}`, true},
+		{"nested duplicate", `{"a":{"b":1,"b":2}}`, true},
+		{"duplicate inside array", `{"a":[1,{"c":1,"c":1}]}`, true},
+		{"escaped duplicate", `{"a":1,"\u0061":2}`, true},
+		{"malformed", `{"a":`, true},
+		{"trailing data", `{"a":1} 1`, true},
+		{"too deep", strings.Repeat("[", MaxMessageDataDepth+1) + strings.Repeat("]", MaxMessageDataDepth+1), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNoDuplicateKeys(json.RawMessage(tt.input))
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrSignDocMismatch)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateNoBareNumbers(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "empty", input: ``},
+		{name: "null", input: `null`},
+		{name: "string numbers", input: `{"amount":"100","coins":[{"amount":"1","denom":"stake"}]}`},
+		{name: "booleans and nulls", input: `{"a":true,"b":null,"c":[false]}`},
+		{name: "number-like key", input: `{"1":"2"}`},
+		{name: "top-level number", input: `100`, wantErr: "bare number 100:"},
+		{name: "integer member", input: `{"to":"bob","amount":100}`, wantErr: "bare number 100 at amount"},
+		{name: "float", input: `{"rate":0.5}`, wantErr: "bare number 0.5 at rate"},
+		{name: "large integer", input: `{"n":18446744073709551615}`, wantErr: "at n"},
+		{name: "nested", input: `{"coins":[{"denom":"stake"},{"amount":1}]}`, wantErr: "at coins[1].amount"},
+		{name: "array element", input: `["1",2]`, wantErr: "at [1]"},
+		{name: "malformed", input: `{"a":`, wantErr: "invalid message data"},
+		{name: "trailing data", input: `{"a":"1"} "2"`, wantErr: "unexpected data"},
+		{name: "too deep", input: strings.Repeat("[", MaxMessageDataDepth+1) + strings.Repeat("]", MaxMessageDataDepth+1), wantErr: "nesting depth"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNoBareNumbers(json.RawMessage(tt.input))
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.ErrorIs(t, err, ErrSignDocMismatch)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSignDoc_ValidateBasic_DuplicateKeys(t *testing.T) {
+	sd, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+	require.NoError(t, err)
+	sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"amount":"1","to":"bob"}`))
+	sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"amount":"1","to":{"name":"bob","name":"eve"}}`))
+
+	err = sd.ValidateBasic()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrSignDocMismatch)
+	assert.Contains(t, err.Error(), "message 1")
+	assert.Contains(t, err.Error(), `duplicate object key "name"`)
+}
+
+func TestSignDoc_ValidateBasicStrict(t *testing.T) {
+	sd, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+	require.NoError(t, err)
+	sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"to":"bob","amount":"100"}`))
+
+	// Lenient validation accepts unsorted keys; strict validation does not.
+	require.NoError(t, sd.ValidateBasic())
+	err = sd.ValidateBasicStrict()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrSignDocMismatch)
+	assert.Contains(t, err.Error(), "message 0")
+
+	require.NoError(t, sd.CanonicalizeMessages())
+	assert.Equal(t, `{"amount":"100","to":"bob"}`, string(sd.Messages[0].Data))
+	assert.NoError(t, sd.ValidateBasicStrict())
+}
+
+func TestSignDoc_CanonicalizeMessages_SameSignBytes(t *testing.T) {
+	// SECURITY: Semantically identical messages must sign identically once canonicalized.
+	sd1, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+	require.NoError(t, err)
+	sd1.AddMessage("/msg", json.RawMessage(`{"b":1,"a":{"d":2,"c":3}}`))
+	sd2, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+	require.NoError(t, err)
+	sd2.AddMessage("/msg", json.RawMessage(`{"a":{"c":3,"d":2},"b":1}`))
+
+	require.False(t, sd1.Equals(sd2))
+	require.NoError(t, sd1.CanonicalizeMessages())
+	require.NoError(t, sd2.CanonicalizeMessages())
+
+	b1, err := sd1.GetSignBytes()
+	require.NoError(t, err)
+	b2, err := sd2.GetSignBytes()
+	require.NoError(t, err)
+	assert.Equal(t, b1, b2)
+}
+
+func TestSignDoc_CanonicalizeMessages_AtomicOnError(t *testing.T) {
+	sd, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+	require.NoError(t, err)
+	sd.AddMessage("/msg", json.RawMessage(`{"b":1,"a":2}`))
+	sd.AddMessage("/msg", json.RawMessage(`{"a":1,"a":2}`))
+
+	require.Error(t, sd.CanonicalizeMessages())
+	assert.Equal(t, `{"b":1,"a":2}`, string(sd.Messages[0].Data), "first message must be untouched on error")
+}
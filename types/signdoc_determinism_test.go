@@ -2,8 +2,6 @@ package types
 
 import (
 	"bytes"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
 	"math"
 	"strings"
@@ -21,7 +19,8 @@ import (
 func TestSignDocDeterminism_RepeatedSerialization(t *testing.T) {
 	// SECURITY: Repeated serialization of the same SignDoc MUST produce identical bytes.
 	// If this fails, signature verification becomes non-deterministic.
-	sd := NewSignDoc("punnet-mainnet-1", 42, "alice", 1, "test memo")
+	sd, err := NewSignDoc("punnet-mainnet-1", 42, "alice", 1, "test memo")
+	require.NoError(t, err)
 	sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"from":"alice","to":"bob","amount":"100"}`))
 
 	// Serialize 100 times and verify all are identical
@@ -41,7 +40,8 @@ func TestSignDocDeterminism_RepeatedSerialization(t *testing.T) {
 
 func TestSignDocDeterminism_HashConsistency(t *testing.T) {
 	// SECURITY: GetSignBytes() must produce identical hashes for the same SignDoc.
-	sd := NewSignDoc("test-chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 	sd.AddMessage("/msg.Type", json.RawMessage(`{"key":"value"}`))
 
 	var firstHash []byte
@@ -61,7 +61,8 @@ func TestSignDocDeterminism_HashConsistency(t *testing.T) {
 func TestSignDocDeterminism_EquivalentConstruction(t *testing.T) {
 	// Two SignDocs constructed with the same values must serialize identically.
 	createSignDoc := func() *SignDoc {
-		sd := NewSignDoc("chain-1", 10, "bob", 5, "hello")
+		sd, err := NewSignDoc("chain-1", 10, "bob", 5, "hello")
+		require.NoError(t, err)
 		sd.AddMessage("/type1", json.RawMessage(`{"a":1}`))
 		sd.AddMessage("/type2", json.RawMessage(`{"b":2}`))
 		return sd
@@ -81,7 +82,8 @@ func TestSignDocDeterminism_EquivalentConstruction(t *testing.T) {
 
 func TestSignDocDeterminism_FieldOrderIndependence(t *testing.T) {
 	// Verify that the struct's JSON field order is consistent (Go serializes in declaration order).
-	sd := NewSignDoc("chain", 1, "alice", 2, "memo")
+	sd, err := NewSignDoc("chain", 1, "alice", 2, "memo")
+	require.NoError(t, err)
 	sd.AddMessage("/msg", json.RawMessage(`{}`))
 
 	jsonBytes, err := sd.ToJSON()
@@ -106,7 +108,8 @@ func TestSignDocDeterminism_FieldOrderIndependence(t *testing.T) {
 func TestSignDocFieldValues_EmptyStringFields(t *testing.T) {
 	// Empty memo should serialize consistently.
 	// Per Cramberry determinism rules: all fields are always included, even if empty.
-	sd := NewSignDoc("chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 	sd.AddMessage("/msg", json.RawMessage(`{}`))
 
 	json1, err := sd.ToJSON()
@@ -125,7 +128,8 @@ func TestSignDocFieldValues_EmptyStringFields(t *testing.T) {
 
 func TestSignDocFieldValues_ZeroNumericFields(t *testing.T) {
 	// Zero values for uint64 fields must serialize consistently as strings.
-	sd := NewSignDoc("chain", 0, "alice", 0, "")
+	sd, err := NewSignDoc("chain", 0, "alice", 0, "")
+	require.NoError(t, err)
 	sd.AddMessage("/msg", json.RawMessage(`{}`))
 
 	jsonBytes, err := sd.ToJSON()
@@ -144,7 +148,8 @@ func TestSignDocFieldValues_ZeroNumericFields(t *testing.T) {
 
 func TestSignDocFieldValues_MaxUint64(t *testing.T) {
 	// Maximum uint64 values must serialize correctly as strings.
-	sd := NewSignDoc("chain", math.MaxUint64, "alice", math.MaxUint64, "")
+	sd, err := NewSignDoc("chain", math.MaxUint64, "alice", math.MaxUint64, "")
+	require.NoError(t, err)
 	sd.AddMessage("/msg", json.RawMessage(`{}`))
 
 	jsonBytes, err := sd.ToJSON()
@@ -179,7 +184,8 @@ func TestSignDocFieldValues_UnicodeStrings(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			sd := NewSignDoc("chain", 1, "alice", 1, tc.value)
+			sd, err := NewSignDoc("chain", 1, "alice", 1, tc.value)
+			require.NoError(t, err)
 			sd.AddMessage("/msg", json.RawMessage(`{}`))
 
 			jsonBytes, err := sd.ToJSON()
@@ -213,10 +219,12 @@ func TestSignDocFieldValues_UnicodeNormalizationBehavior(t *testing.T) {
 	// Visually identical but different byte sequences
 	require.NotEqual(t, nfcCafe, nfdCafe, "NFC and NFD should be different strings")
 
-	sdNFC := NewSignDoc("chain", 1, "alice", 1, nfcCafe)
+	sdNFC, err := NewSignDoc("chain", 1, "alice", 1, nfcCafe)
+	require.NoError(t, err)
 	sdNFC.AddMessage("/msg", json.RawMessage(`{}`))
 
-	sdNFD := NewSignDoc("chain", 1, "alice", 1, nfdCafe)
+	sdNFD, err := NewSignDoc("chain", 1, "alice", 1, nfdCafe)
+	require.NoError(t, err)
 	sdNFD.AddMessage("/msg", json.RawMessage(`{}`))
 
 	jsonNFC, err := sdNFC.ToJSON()
@@ -265,7 +273,8 @@ func TestSignDocFieldValues_SpecialCharactersInMemo(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			sd := NewSignDoc("chain", 1, "alice", 1, tc.memo)
+			sd, err := NewSignDoc("chain", 1, "alice", 1, tc.memo)
+			require.NoError(t, err)
 			sd.AddMessage("/msg", json.RawMessage(`{}`))
 
 			jsonBytes, err := sd.ToJSON()
@@ -313,7 +322,8 @@ func TestSignDocFieldValues_EmptyArray(t *testing.T) {
 
 func TestSignDocFieldValues_NilMessageData(t *testing.T) {
 	// Message with nil Data should serialize consistently.
-	sd := NewSignDoc("chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 	sd.Messages = append(sd.Messages, SignDocMessage{
 		Type: "/msg.Type",
 		Data: nil,
@@ -334,7 +344,8 @@ func TestSignDocFieldValues_NilMessageData(t *testing.T) {
 // Test serialization with various message configurations.
 
 func TestSignDocMessages_SingleMessage(t *testing.T) {
-	sd := NewSignDoc("chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 	sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"from":"alice","to":"bob","amount":"100"}`))
 
 	json1, err := sd.ToJSON()
@@ -351,7 +362,8 @@ func TestSignDocMessages_SingleMessage(t *testing.T) {
 }
 
 func TestSignDocMessages_MultipleMessages(t *testing.T) {
-	sd := NewSignDoc("chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 	sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"from":"alice","to":"bob","amount":"100"}`))
 	sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"from":"alice","to":"charlie","amount":"50"}`))
 	sd.AddMessage("/punnet.staking.v1.MsgDelegate", json.RawMessage(`{"delegator":"alice","validator":"val1"}`))
@@ -383,7 +395,8 @@ func TestSignDocMessages_DifferentMessageTypes(t *testing.T) {
 		"/ibc.applications.transfer.v1.MsgTransfer", // IBC-style
 	}
 
-	sd := NewSignDoc("chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 	for _, msgType := range messageTypes {
 		sd.AddMessage(msgType, json.RawMessage(`{}`))
 	}
@@ -421,7 +434,8 @@ func TestSignDocMessages_NestedMessageStructures(t *testing.T) {
 		]
 	}`)
 
-	sd := NewSignDoc("chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 	sd.AddMessage("/msg.Nested", nestedData)
 
 	json1, err := sd.ToJSON()
@@ -441,7 +455,8 @@ func TestSignDocMessages_NestedMessageStructures(t *testing.T) {
 func TestSignDocMessages_MessageOrderPreserved(t *testing.T) {
 	// CRITICAL: Message order must be preserved exactly as added.
 	// Reordering messages would change the hash and break signature verification.
-	sd := NewSignDoc("chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 	sd.AddMessage("/msg.First", json.RawMessage(`{"order":1}`))
 	sd.AddMessage("/msg.Second", json.RawMessage(`{"order":2}`))
 	sd.AddMessage("/msg.Third", json.RawMessage(`{"order":3}`))
@@ -468,7 +483,8 @@ func TestSignDocMessages_MessageOrderPreserved(t *testing.T) {
 
 func TestSignDocCoins_SingleCoin(t *testing.T) {
 	coinData := json.RawMessage(`{"amount":[{"denom":"uatom","amount":"1000"}]}`)
-	sd := NewSignDoc("chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 	sd.AddMessage("/msg.WithCoins", coinData)
 
 	json1, err := sd.ToJSON()
@@ -486,7 +502,8 @@ func TestSignDocCoins_MultipleCoinsSorted(t *testing.T) {
 	// Coins should be sorted lexicographically by denom in the message data.
 	// This tests that the message data preserves the exact order provided.
 	sortedCoins := json.RawMessage(`{"amount":[{"denom":"aaa","amount":"100"},{"denom":"bbb","amount":"200"},{"denom":"ccc","amount":"300"}]}`)
-	sd := NewSignDoc("chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 	sd.AddMessage("/msg.WithCoins", sortedCoins)
 
 	json1, err := sd.ToJSON()
@@ -506,7 +523,8 @@ func TestSignDocCoins_MultipleCoinsSorted(t *testing.T) {
 func TestSignDocCoins_SameDenomDifferentAmounts(t *testing.T) {
 	// When messages have the same denom but different amounts, they should serialize consistently.
 	coinData := json.RawMessage(`{"from":"alice","to":"bob","amount":[{"denom":"uatom","amount":"500"}]}`)
-	sd := NewSignDoc("chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 	sd.AddMessage("/punnet.bank.v1.MsgSend", coinData)
 
 	json1, err := sd.ToJSON()
@@ -532,7 +550,8 @@ func TestSignDocEdgeCases_MaximumLengthFields(t *testing.T) {
 	longChainID := strings.Repeat("c", 64)
 	longAccount := strings.Repeat("a", 128)
 
-	sd := NewSignDoc(longChainID, 1, longAccount, 1, longMemo)
+	sd, err := NewSignDoc(longChainID, 1, longAccount, 1, longMemo)
+	require.NoError(t, err)
 	sd.AddMessage("/msg", json.RawMessage(`{}`))
 
 	json1, err := sd.ToJSON()
@@ -552,7 +571,8 @@ func TestSignDocEdgeCases_MaximumLengthFields(t *testing.T) {
 
 func TestSignDocEdgeCases_MinimumValidSignDoc(t *testing.T) {
 	// Create the minimal valid SignDoc
-	sd := NewSignDoc("c", 0, "a", 0, "")
+	sd, err := NewSignDoc("c", 0, "a", 0, "")
+	require.NoError(t, err)
 	sd.AddMessage("/m", json.RawMessage(`{}`))
 
 	json1, err := sd.ToJSON()
@@ -621,7 +641,8 @@ func TestSignDocEdgeCases_AllFieldsPopulated(t *testing.T) {
 
 func TestSignDocEdgeCases_LargeMessageCount(t *testing.T) {
 	// Test with many messages (stress test)
-	sd := NewSignDoc("chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 
 	for i := 0; i < 100; i++ {
 		sd.AddMessage("/msg.Type", json.RawMessage(`{"index":`+string(rune('0'+i%10))+`}`))
@@ -647,7 +668,8 @@ func TestSignDocEdgeCases_LargeMessageData(t *testing.T) {
 	}
 
 	msgData := json.RawMessage(`{"data":"` + string(largeData) + `"}`)
-	sd := NewSignDoc("chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 	sd.AddMessage("/msg.Large", msgData)
 
 	json1, err := sd.ToJSON()
@@ -666,12 +688,11 @@ func TestSignDocEdgeCases_LargeMessageData(t *testing.T) {
 // =============================================================================
 // These provide known expected outputs for specific inputs.
 
-// SignDocFixture represents a test fixture with known expected output
+// SignDocFixture represents a test fixture whose expected output is the
+// golden file testdata/golden/signdoc/<Name>.golden (see signdoc_golden_test.go)
 type SignDocFixture struct {
-	Name         string
-	SignDoc      *SignDoc
-	ExpectedJSON string
-	ExpectedHash string // hex-encoded SHA-256
+	Name    string
+	SignDoc *SignDoc
 }
 
 func getSignDocFixtures() []SignDocFixture {
@@ -681,44 +702,40 @@ func getSignDocFixtures() []SignDocFixture {
 		{
 			Name: "basic_transfer",
 			SignDoc: func() *SignDoc {
-				sd := NewSignDoc("punnet-1", 1, "alice", 1, "")
+				sd := mustNewSignDoc("punnet-1", 1, "alice", 1, "")
 				sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"from":"alice","to":"bob","amount":"100"}`))
 				return sd
 			}(),
-			ExpectedJSON: `{"version":"1","chain_id":"punnet-1","account":"alice","account_sequence":"1","messages":[{"type":"/punnet.bank.v1.MsgSend","data":{"from":"alice","to":"bob","amount":"100"}}],"nonce":"1","memo":"","fee":{"amount":[],"gas_limit":"0"},"fee_slippage":{"numerator":"0","denominator":"1"}}`,
 		},
 		{
 			Name: "with_memo",
 			SignDoc: func() *SignDoc {
-				sd := NewSignDoc("test-chain", 42, "bob", 10, "hello world")
+				sd := mustNewSignDoc("test-chain", 42, "bob", 10, "hello world")
 				sd.AddMessage("/msg", json.RawMessage(`{}`))
 				return sd
 			}(),
-			ExpectedJSON: `{"version":"1","chain_id":"test-chain","account":"bob","account_sequence":"42","messages":[{"type":"/msg","data":{}}],"nonce":"10","memo":"hello world","fee":{"amount":[],"gas_limit":"0"},"fee_slippage":{"numerator":"0","denominator":"1"}}`,
 		},
 		{
 			Name: "zero_values",
 			SignDoc: func() *SignDoc {
-				sd := NewSignDoc("chain", 0, "user", 0, "")
+				sd := mustNewSignDoc("chain", 0, "user", 0, "")
 				sd.AddMessage("/m", json.RawMessage(`{}`))
 				return sd
 			}(),
-			ExpectedJSON: `{"version":"1","chain_id":"chain","account":"user","account_sequence":"0","messages":[{"type":"/m","data":{}}],"nonce":"0","memo":"","fee":{"amount":[],"gas_limit":"0"},"fee_slippage":{"numerator":"0","denominator":"1"}}`,
 		},
 		{
 			Name: "multiple_messages",
 			SignDoc: func() *SignDoc {
-				sd := NewSignDoc("chain", 1, "alice", 1, "")
+				sd := mustNewSignDoc("chain", 1, "alice", 1, "")
 				sd.AddMessage("/a", json.RawMessage(`{"x":1}`))
 				sd.AddMessage("/b", json.RawMessage(`{"y":2}`))
 				return sd
 			}(),
-			ExpectedJSON: `{"version":"1","chain_id":"chain","account":"alice","account_sequence":"1","messages":[{"type":"/a","data":{"x":1}},{"type":"/b","data":{"y":2}}],"nonce":"1","memo":"","fee":{"amount":[],"gas_limit":"0"},"fee_slippage":{"numerator":"0","denominator":"1"}}`,
 		},
 		{
 			Name: "with_fee",
 			SignDoc: func() *SignDoc {
-				sd := NewSignDocWithFee("punnet-1", 5, "alice", 5, "fee test",
+				sd := mustNewSignDocWithFee("punnet-1", 5, "alice", 5, "fee test",
 					SignDocFee{
 						Amount:   []SignDocCoin{{Denom: "uatom", Amount: "5000"}},
 						GasLimit: "200000",
@@ -728,27 +745,10 @@ func getSignDocFixtures() []SignDocFixture {
 				sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"to":"bob"}`))
 				return sd
 			}(),
-			ExpectedJSON: `{"version":"1","chain_id":"punnet-1","account":"alice","account_sequence":"5","messages":[{"type":"/punnet.bank.v1.MsgSend","data":{"to":"bob"}}],"nonce":"5","memo":"fee test","fee":{"amount":[{"denom":"uatom","amount":"5000"}],"gas_limit":"200000"},"fee_slippage":{"numerator":"5","denominator":"100"}}`,
 		},
 	}
 }
 
-func TestSignDocFixtures_JSONOutput(t *testing.T) {
-	// Verify that fixtures produce exactly the expected JSON output.
-	// This catches any changes to serialization behavior.
-	fixtures := getSignDocFixtures()
-
-	for _, fixture := range fixtures {
-		t.Run(fixture.Name, func(t *testing.T) {
-			jsonBytes, err := fixture.SignDoc.ToJSON()
-			require.NoError(t, err)
-
-			assert.Equal(t, fixture.ExpectedJSON, string(jsonBytes),
-				"fixture %q JSON output changed", fixture.Name)
-		})
-	}
-}
-
 func TestSignDocFixtures_Determinism(t *testing.T) {
 	// Verify fixtures serialize deterministically across multiple calls.
 	fixtures := getSignDocFixtures()
@@ -767,28 +767,6 @@ func TestSignDocFixtures_Determinism(t *testing.T) {
 	}
 }
 
-func TestSignDocFixtures_HashStability(t *testing.T) {
-	// Record and verify hash values for fixtures.
-	// If this test fails, it means serialization has changed (breaking change!).
-	fixtures := getSignDocFixtures()
-
-	for _, fixture := range fixtures {
-		t.Run(fixture.Name, func(t *testing.T) {
-			hash, err := fixture.SignDoc.GetSignBytes()
-			require.NoError(t, err)
-
-			// Compute expected hash from expected JSON
-			expectedHash := sha256.Sum256([]byte(fixture.ExpectedJSON))
-
-			assert.Equal(t, expectedHash[:], hash,
-				"hash mismatch for fixture %q - serialization may have changed", fixture.Name)
-
-			// Log the hash for documentation
-			t.Logf("Fixture %q hash: %s", fixture.Name, hex.EncodeToString(hash))
-		})
-	}
-}
-
 func TestSignDocFixtures_Roundtrip(t *testing.T) {
 	// Verify all fixtures roundtrip correctly.
 	fixtures := getSignDocFixtures()
@@ -833,7 +811,8 @@ func TestSignDocSecurity_NoExtraFields(t *testing.T) {
 func TestSignDocSecurity_DifferentSignDocsProduceDifferentHashes(t *testing.T) {
 	// Verify that any change to a SignDoc produces a different hash.
 	// This is critical for signature security.
-	base := NewSignDoc("chain", 1, "alice", 1, "memo")
+	base, err := NewSignDoc("chain", 1, "alice", 1, "memo")
+	require.NoError(t, err)
 	base.AddMessage("/msg", json.RawMessage(`{"key":"value"}`))
 
 	baseHash, err := base.GetSignBytes()
@@ -847,7 +826,8 @@ func TestSignDocSecurity_DifferentSignDocsProduceDifferentHashes(t *testing.T) {
 		{
 			name: "different chain_id",
 			modify: func() *SignDoc {
-				sd := NewSignDoc("chain2", 1, "alice", 1, "memo")
+				sd, err := NewSignDoc("chain2", 1, "alice", 1, "memo")
+				require.NoError(t, err)
 				sd.AddMessage("/msg", json.RawMessage(`{"key":"value"}`))
 				return sd
 			},
@@ -855,7 +835,8 @@ func TestSignDocSecurity_DifferentSignDocsProduceDifferentHashes(t *testing.T) {
 		{
 			name: "different account_sequence",
 			modify: func() *SignDoc {
-				sd := NewSignDoc("chain", 2, "alice", 1, "memo")
+				sd, err := NewSignDoc("chain", 2, "alice", 1, "memo")
+				require.NoError(t, err)
 				sd.AddMessage("/msg", json.RawMessage(`{"key":"value"}`))
 				return sd
 			},
@@ -863,7 +844,8 @@ func TestSignDocSecurity_DifferentSignDocsProduceDifferentHashes(t *testing.T) {
 		{
 			name: "different account",
 			modify: func() *SignDoc {
-				sd := NewSignDoc("chain", 1, "bob", 1, "memo")
+				sd, err := NewSignDoc("chain", 1, "bob", 1, "memo")
+				require.NoError(t, err)
 				sd.AddMessage("/msg", json.RawMessage(`{"key":"value"}`))
 				return sd
 			},
@@ -871,7 +853,8 @@ func TestSignDocSecurity_DifferentSignDocsProduceDifferentHashes(t *testing.T) {
 		{
 			name: "different nonce",
 			modify: func() *SignDoc {
-				sd := NewSignDoc("chain", 1, "alice", 2, "memo")
+				sd, err := NewSignDoc("chain", 1, "alice", 2, "memo")
+				require.NoError(t, err)
 				sd.AddMessage("/msg", json.RawMessage(`{"key":"value"}`))
 				return sd
 			},
@@ -879,7 +862,8 @@ func TestSignDocSecurity_DifferentSignDocsProduceDifferentHashes(t *testing.T) {
 		{
 			name: "different memo",
 			modify: func() *SignDoc {
-				sd := NewSignDoc("chain", 1, "alice", 1, "different memo")
+				sd, err := NewSignDoc("chain", 1, "alice", 1, "different memo")
+				require.NoError(t, err)
 				sd.AddMessage("/msg", json.RawMessage(`{"key":"value"}`))
 				return sd
 			},
@@ -887,7 +871,8 @@ func TestSignDocSecurity_DifferentSignDocsProduceDifferentHashes(t *testing.T) {
 		{
 			name: "different message type",
 			modify: func() *SignDoc {
-				sd := NewSignDoc("chain", 1, "alice", 1, "memo")
+				sd, err := NewSignDoc("chain", 1, "alice", 1, "memo")
+				require.NoError(t, err)
 				sd.AddMessage("/different.msg", json.RawMessage(`{"key":"value"}`))
 				return sd
 			},
@@ -895,7 +880,8 @@ func TestSignDocSecurity_DifferentSignDocsProduceDifferentHashes(t *testing.T) {
 		{
 			name: "different message data",
 			modify: func() *SignDoc {
-				sd := NewSignDoc("chain", 1, "alice", 1, "memo")
+				sd, err := NewSignDoc("chain", 1, "alice", 1, "memo")
+				require.NoError(t, err)
 				sd.AddMessage("/msg", json.RawMessage(`{"key":"different"}`))
 				return sd
 			},
@@ -903,7 +889,8 @@ func TestSignDocSecurity_DifferentSignDocsProduceDifferentHashes(t *testing.T) {
 		{
 			name: "additional message",
 			modify: func() *SignDoc {
-				sd := NewSignDoc("chain", 1, "alice", 1, "memo")
+				sd, err := NewSignDoc("chain", 1, "alice", 1, "memo")
+				require.NoError(t, err)
 				sd.AddMessage("/msg", json.RawMessage(`{"key":"value"}`))
 				sd.AddMessage("/msg2", json.RawMessage(`{}`))
 				return sd
@@ -926,7 +913,8 @@ func TestSignDocSecurity_DifferentSignDocsProduceDifferentHashes(t *testing.T) {
 func TestSignDocSecurity_CanonicalWhitespace(t *testing.T) {
 	// Verify that JSON output has no unnecessary whitespace.
 	// Extra whitespace could allow signature malleability.
-	sd := NewSignDoc("chain", 1, "alice", 1, "memo")
+	sd, err := NewSignDoc("chain", 1, "alice", 1, "memo")
+	require.NoError(t, err)
 	sd.AddMessage("/msg", json.RawMessage(`{"key":"value"}`))
 
 	jsonBytes, err := sd.ToJSON()
@@ -951,7 +939,8 @@ func TestSignDocSecurity_HashLength(t *testing.T) {
 		{
 			name: "minimal",
 			signDoc: func() *SignDoc {
-				sd := NewSignDoc("c", 0, "a", 0, "")
+				sd, err := NewSignDoc("c", 0, "a", 0, "")
+				require.NoError(t, err)
 				sd.AddMessage("/m", json.RawMessage(`{}`))
 				return sd
 			}(),
@@ -959,7 +948,8 @@ func TestSignDocSecurity_HashLength(t *testing.T) {
 		{
 			name: "large",
 			signDoc: func() *SignDoc {
-				sd := NewSignDoc(strings.Repeat("x", 100), math.MaxUint64, strings.Repeat("y", 100), math.MaxUint64, strings.Repeat("z", 500))
+				sd, err := NewSignDoc(strings.Repeat("x", MaxChainIDLength), math.MaxUint64, strings.Repeat("y", 100), math.MaxUint64, strings.Repeat("z", 500))
+				require.NoError(t, err)
 				for i := 0; i < 50; i++ {
 					sd.AddMessage("/msg", json.RawMessage(`{"data":"`+strings.Repeat("d", 1000)+`"}`))
 				}
@@ -984,7 +974,8 @@ func TestSignDocSecurity_HashLength(t *testing.T) {
 
 func TestSignDocEquals_ErrorPaths(t *testing.T) {
 	// Test Equals when ToJSON fails
-	sd1 := NewSignDoc("chain", 1, "alice", 1, "")
+	sd1, err := NewSignDoc("chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 	sd1.AddMessage("/msg", json.RawMessage(`{}`))
 
 	// Test with nil
@@ -996,10 +987,12 @@ func TestSignDocEquals_ErrorPaths(t *testing.T) {
 
 func TestSignDocEquals_BothValid(t *testing.T) {
 	// Test Equals with two valid SignDocs that should be equal
-	sd1 := NewSignDoc("chain", 1, "alice", 1, "memo")
+	sd1, err := NewSignDoc("chain", 1, "alice", 1, "memo")
+	require.NoError(t, err)
 	sd1.AddMessage("/msg", json.RawMessage(`{"key":"value"}`))
 
-	sd2 := NewSignDoc("chain", 1, "alice", 1, "memo")
+	sd2, err := NewSignDoc("chain", 1, "alice", 1, "memo")
+	require.NoError(t, err)
 	sd2.AddMessage("/msg", json.RawMessage(`{"key":"value"}`))
 
 	assert.True(t, sd1.Equals(sd2))
@@ -1007,10 +1000,12 @@ func TestSignDocEquals_BothValid(t *testing.T) {
 
 func TestSignDocEquals_Different(t *testing.T) {
 	// Test Equals with two different SignDocs
-	sd1 := NewSignDoc("chain1", 1, "alice", 1, "")
+	sd1, err := NewSignDoc("chain1", 1, "alice", 1, "")
+	require.NoError(t, err)
 	sd1.AddMessage("/msg", json.RawMessage(`{}`))
 
-	sd2 := NewSignDoc("chain2", 1, "alice", 1, "")
+	sd2, err := NewSignDoc("chain2", 1, "alice", 1, "")
+	require.NoError(t, err)
 	sd2.AddMessage("/msg", json.RawMessage(`{}`))
 
 	assert.False(t, sd1.Equals(sd2))
@@ -1112,7 +1107,8 @@ func TestSignDocConcurrency_MultipleGoroutines(t *testing.T) {
 	// Test that concurrent serialization of the same SignDoc is deterministic.
 	// This is important for production use where the same SignDoc might be
 	// serialized from multiple goroutines.
-	sd := NewSignDoc("chain", 1, "alice", 1, "memo")
+	sd, err := NewSignDoc("chain", 1, "alice", 1, "memo")
+	require.NoError(t, err)
 	sd.AddMessage("/msg", json.RawMessage(`{"key":"value"}`))
 
 	// Get the expected output
@@ -1148,7 +1144,8 @@ func TestSignDocConcurrency_MultipleGoroutines(t *testing.T) {
 
 func TestSignDocConcurrency_GetSignBytes(t *testing.T) {
 	// Test concurrent GetSignBytes calls
-	sd := NewSignDoc("chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 	sd.AddMessage("/msg", json.RawMessage(`{}`))
 
 	expected, err := sd.GetSignBytes()
@@ -1285,9 +1282,9 @@ func TestSortedJSONObject_MarshalError(t *testing.T) {
 func TestSignDocProperty_EqualsSelfReflexive(t *testing.T) {
 	// Property: Any SignDoc should equal itself
 	testSignDocs := []*SignDoc{
-		NewSignDoc("chain", 1, "alice", 1, ""),
-		NewSignDoc("chain", 0, "bob", 0, "memo"),
-		NewSignDoc(strings.Repeat("x", 100), math.MaxUint64, strings.Repeat("y", 100), math.MaxUint64, ""),
+		mustNewSignDoc("chain", 1, "alice", 1, ""),
+		mustNewSignDoc("chain", 0, "bob", 0, "memo"),
+		mustNewSignDoc(strings.Repeat("x", MaxChainIDLength), math.MaxUint64, strings.Repeat("y", 100), math.MaxUint64, ""),
 	}
 
 	for i, sd := range testSignDocs {
@@ -1298,10 +1295,12 @@ func TestSignDocProperty_EqualsSelfReflexive(t *testing.T) {
 
 func TestSignDocProperty_EqualsSymmetric(t *testing.T) {
 	// Property: If A equals B, then B equals A
-	sd1 := NewSignDoc("chain", 1, "alice", 1, "memo")
+	sd1, err := NewSignDoc("chain", 1, "alice", 1, "memo")
+	require.NoError(t, err)
 	sd1.AddMessage("/msg", json.RawMessage(`{"x":1}`))
 
-	sd2 := NewSignDoc("chain", 1, "alice", 1, "memo")
+	sd2, err := NewSignDoc("chain", 1, "alice", 1, "memo")
+	require.NoError(t, err)
 	sd2.AddMessage("/msg", json.RawMessage(`{"x":1}`))
 
 	eq1 := sd1.Equals(sd2)
@@ -1312,10 +1311,12 @@ func TestSignDocProperty_EqualsSymmetric(t *testing.T) {
 
 func TestSignDocProperty_HashPreservesEquality(t *testing.T) {
 	// Property: Equal SignDocs have equal hashes
-	sd1 := NewSignDoc("chain", 1, "alice", 1, "memo")
+	sd1, err := NewSignDoc("chain", 1, "alice", 1, "memo")
+	require.NoError(t, err)
 	sd1.AddMessage("/msg", json.RawMessage(`{"key":"value"}`))
 
-	sd2 := NewSignDoc("chain", 1, "alice", 1, "memo")
+	sd2, err := NewSignDoc("chain", 1, "alice", 1, "memo")
+	require.NoError(t, err)
 	sd2.AddMessage("/msg", json.RawMessage(`{"key":"value"}`))
 
 	assert.True(t, sd1.Equals(sd2), "SignDocs should be equal")
@@ -1338,7 +1339,8 @@ func TestSignDocProperty_RoundtripPreservesEquality(t *testing.T) {
 		{
 			name: "minimal",
 			signDoc: func() *SignDoc {
-				sd := NewSignDoc("c", 0, "a", 0, "")
+				sd, err := NewSignDoc("c", 0, "a", 0, "")
+				require.NoError(t, err)
 				sd.AddMessage("/m", json.RawMessage(`{}`))
 				return sd
 			}(),
@@ -1346,7 +1348,9 @@ func TestSignDocProperty_RoundtripPreservesEquality(t *testing.T) {
 		{
 			name: "with unicode",
 			signDoc: func() *SignDoc {
-				sd := NewSignDoc("chain-日本", 1, "alice", 1, "memo 🚀")
+				sd, err := NewSignDoc("chain", 1, "alice", 1, "memo 🚀")
+				require.NoError(t, err)
+				sd.ChainID = "chain-日本"
 				sd.AddMessage("/msg.送金", json.RawMessage(`{"to":"בוב"}`))
 				return sd
 			}(),
@@ -1354,7 +1358,8 @@ func TestSignDocProperty_RoundtripPreservesEquality(t *testing.T) {
 		{
 			name: "max values",
 			signDoc: func() *SignDoc {
-				sd := NewSignDoc("chain", math.MaxUint64, "alice", math.MaxUint64, "")
+				sd, err := NewSignDoc("chain", math.MaxUint64, "alice", math.MaxUint64, "")
+				require.NoError(t, err)
 				sd.AddMessage("/msg", json.RawMessage(`{}`))
 				return sd
 			}(),
@@ -0,0 +1,404 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MismatchCause classifies why two SignDoc serializations differ.
+type MismatchCause string
+
+const (
+	// MismatchCauseMalformed means at least one input is not valid JSON.
+	MismatchCauseMalformed MismatchCause = "malformed_json"
+
+	// MismatchCauseWhitespace means the documents differ only in whitespace
+	// outside of string values (e.g. pretty-printed vs compact JSON).
+	MismatchCauseWhitespace MismatchCause = "whitespace"
+
+	// MismatchCauseKeyOrder means the documents contain the same members but
+	// at least one object lists its keys in a different order.
+	MismatchCauseKeyOrder MismatchCause = "key_order"
+
+	// MismatchCauseUnicodeNormalization means two strings are equal after NFC
+	// normalization but differ byte-wise (composed vs decomposed characters).
+	MismatchCauseUnicodeNormalization MismatchCause = "unicode_normalization"
+
+	// MismatchCauseNumberFormat means two numbers are numerically equal but
+	// formatted differently (e.g. "100" vs 100, 1e2 vs 100, 1.0 vs 1).
+	MismatchCauseNumberFormat MismatchCause = "number_format"
+
+	// MismatchCauseStringEscaping means the decoded values are identical but
+	// strings are escaped differently (e.g. "\u0041" vs "A", "\u003c" vs "<").
+	MismatchCauseStringEscaping MismatchCause = "string_escaping"
+
+	// MismatchCauseValue means a field has a genuinely different value, or is
+	// missing from one side.
+	MismatchCauseValue MismatchCause = "value"
+)
+
+// maxSignDocJSONDepth bounds nesting when decoding a whole SignDoc for diffing:
+// message data sits three levels below the root (object, messages array, message).
+const maxSignDocJSONDepth = MaxMessageDataDepth + 3
+
+// mismatchSnippetRadius is the number of bytes shown on each side of the first
+// differing byte in SignBytesMismatch snippets.
+const mismatchSnippetRadius = 24
+
+// SignBytesMismatch describes the first difference between two SignDoc JSON
+// serializations and its most likely cause.
+//
+// It is a debugging aid for cross-implementation signature failures, where the
+// verifier otherwise only reports that a signature is invalid.
+type SignBytesMismatch struct {
+	// Offset is the index of the first differing byte.
+	// If one input is a prefix of the other, Offset is the length of the shorter input.
+	Offset int
+
+	// Path is the JSON path of the first differing field (e.g. "messages[0].data.amount").
+	// Empty when the difference cannot be attributed to a field (whitespace, malformed input).
+	Path string
+
+	// Cause is the classified reason for the mismatch.
+	Cause MismatchCause
+
+	// Expected is a short excerpt of the expected JSON around Offset.
+	Expected string
+
+	// Actual is a short excerpt of the actual JSON around Offset.
+	Actual string
+}
+
+// String returns a human-readable explanation of the mismatch.
+func (m *SignBytesMismatch) String() string {
+	if m == nil {
+		return "sign bytes match"
+	}
+
+	field := m.Path
+	if field == "" {
+		field = "(document)"
+	}
+	return fmt.Sprintf("sign bytes differ at byte %d, field %s: %s (%s)\n  expected: %s\n  actual:   %s",
+		m.Offset, field, m.Cause, m.Cause.hint(), m.Expected, m.Actual)
+}
+
+// hint returns a remediation hint for the cause.
+func (c MismatchCause) hint() string {
+	switch c {
+	case MismatchCauseMalformed:
+		return "input is not valid JSON"
+	case MismatchCauseWhitespace:
+		return "serialize without whitespace outside strings"
+	case MismatchCauseKeyOrder:
+		return "emit object keys in sorted order; see CanonicalizeMessageData"
+	case MismatchCauseUnicodeNormalization:
+		return "normalize strings with Unicode NFC before signing"
+	case MismatchCauseNumberFormat:
+		return "numeric fields must use the same literal form, SignDoc numerics are decimal strings"
+	case MismatchCauseStringEscaping:
+		return "escape strings exactly like SignDoc.ToJSON (no HTML or unnecessary \\u escapes)"
+	case MismatchCauseValue:
+		return "field values differ"
+	default:
+		return "unknown"
+	}
+}
+
+// DiffSignDocs compares the canonical JSON of two SignDocs.
+//
+// POSTCONDITION: Returns (nil, nil) if both SignDocs produce identical sign bytes.
+// POSTCONDITION: Returns an error only if either SignDoc is nil or cannot be serialized.
+func DiffSignDocs(a, b *SignDoc) (*SignBytesMismatch, error) {
+	if a == nil || b == nil {
+		return nil, fmt.Errorf("%w: cannot diff nil SignDoc", ErrSignDocMismatch)
+	}
+
+	aJSON, err := a.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize first SignDoc: %w", err)
+	}
+	bJSON, err := b.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize second SignDoc: %w", err)
+	}
+
+	return ExplainSignBytesMismatch(aJSON, bJSON), nil
+}
+
+// ExplainSignBytesMismatch locates the first difference between an expected and
+// an actual SignDoc JSON serialization (e.g. from this SDK and from a JS/Rust
+// client) and classifies its most likely cause.
+//
+// Classification precedence: a differing field value (including NFC and number
+// formatting differences) is reported before a key-order difference in the same
+// object, and structural causes (key order, escaping, whitespace) are reported
+// only when every decoded value is equal.
+//
+// POSTCONDITION: Returns nil if the inputs are byte-identical.
+func ExplainSignBytesMismatch(expectedJSON, actualJSON []byte) *SignBytesMismatch {
+	if bytes.Equal(expectedJSON, actualJSON) {
+		return nil
+	}
+
+	offset := firstDifferingByte(expectedJSON, actualJSON)
+	m := &SignBytesMismatch{
+		Offset:   offset,
+		Expected: snippetAround(expectedJSON, offset),
+		Actual:   snippetAround(actualJSON, offset),
+	}
+
+	expected, errE := parseOrderedJSON(expectedJSON)
+	actual, errA := parseOrderedJSON(actualJSON)
+	if errE != nil || errA != nil {
+		m.Cause = MismatchCauseMalformed
+		return m
+	}
+
+	if path, cause, found := diffOrderedJSON("", expected, actual); found {
+		m.Path = path
+		m.Cause = cause
+		return m
+	}
+
+	// Decoded values and key order are identical, so the difference is purely lexical.
+	var compactE, compactA bytes.Buffer
+	if json.Compact(&compactE, expectedJSON) == nil && json.Compact(&compactA, actualJSON) == nil &&
+		bytes.Equal(compactE.Bytes(), compactA.Bytes()) {
+		m.Cause = MismatchCauseWhitespace
+	} else {
+		m.Cause = MismatchCauseStringEscaping
+	}
+	return m
+}
+
+// firstDifferingByte returns the index of the first byte where a and b differ.
+func firstDifferingByte(a, b []byte) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
+// snippetAround returns a short excerpt of data centered on offset.
+func snippetAround(data []byte, offset int) string {
+	start := max(offset-mismatchSnippetRadius, 0)
+	end := min(offset+mismatchSnippetRadius, len(data))
+	if start > end {
+		start = end
+	}
+
+	s := string(data[start:end])
+	if start > 0 {
+		s = "…" + s
+	}
+	if end < len(data) {
+		s += "…"
+	}
+	return strconv.Quote(s)
+}
+
+// orderedJSONKind identifies the type of an orderedJSON node.
+type orderedJSONKind int
+
+const (
+	orderedJSONScalar orderedJSONKind = iota
+	orderedJSONObject
+	orderedJSONArray
+)
+
+// orderedJSON is a decoded JSON value that remembers object key order and
+// number literal text, both of which are lost when decoding into maps.
+type orderedJSON struct {
+	kind     orderedJSONKind
+	scalar   interface{} // string, json.Number, bool or nil
+	keys     []string    // object keys in document order
+	members  map[string]*orderedJSON
+	elements []*orderedJSON
+}
+
+// parseOrderedJSON decodes data into an orderedJSON tree.
+func parseOrderedJSON(data []byte) (*orderedJSON, error) {
+	dec := newMessageDataDecoder(data)
+	node, err := readOrderedJSON(dec, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := expectEOF(dec); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// readOrderedJSON reads one value from dec.
+func readOrderedJSON(dec *json.Decoder, depth int) (*orderedJSON, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return &orderedJSON{kind: orderedJSONScalar, scalar: tok}, nil
+	}
+	if depth >= maxSignDocJSONDepth {
+		return nil, fmt.Errorf("nesting depth exceeds %d", maxSignDocJSONDepth)
+	}
+
+	var node *orderedJSON
+	switch delim {
+	case '{':
+		node = &orderedJSON{kind: orderedJSONObject, members: make(map[string]*orderedJSON)}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, ok := keyTok.(string)
+			if !ok {
+				return nil, fmt.Errorf("object key is not a string")
+			}
+			if _, dup := node.members[key]; dup {
+				return nil, fmt.Errorf("duplicate object key %q", key)
+			}
+			child, err := readOrderedJSON(dec, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			node.keys = append(node.keys, key)
+			node.members[key] = child
+		}
+	case '[':
+		node = &orderedJSON{kind: orderedJSONArray}
+		for dec.More() {
+			child, err := readOrderedJSON(dec, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			node.elements = append(node.elements, child)
+		}
+	default:
+		return nil, fmt.Errorf("unexpected delimiter %q", delim)
+	}
+
+	if _, err := dec.Token(); err != nil { // consume closing delimiter
+		return nil, err
+	}
+	return node, nil
+}
+
+// diffOrderedJSON returns the path and cause of the first difference between a and b.
+// Object members are visited in sorted key order so the result does not depend on
+// which side's ordering is "wrong".
+func diffOrderedJSON(path string, a, b *orderedJSON) (string, MismatchCause, bool) {
+	if a.kind != b.kind {
+		return path, MismatchCauseValue, true
+	}
+
+	switch a.kind {
+	case orderedJSONScalar:
+		if cause, differ := diffScalars(a.scalar, b.scalar); differ {
+			return path, cause, true
+		}
+		return "", "", false
+
+	case orderedJSONArray:
+		if len(a.elements) != len(b.elements) {
+			return path, MismatchCauseValue, true
+		}
+		for i := range a.elements {
+			if p, c, found := diffOrderedJSON(fmt.Sprintf("%s[%d]", path, i), a.elements[i], b.elements[i]); found {
+				return p, c, true
+			}
+		}
+		return "", "", false
+
+	default: // object
+		keys := make([]string, 0, len(a.keys)+len(b.keys))
+		keys = append(keys, a.keys...)
+		for _, k := range b.keys {
+			if _, ok := a.members[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			childPath := k
+			if path != "" {
+				childPath = path + "." + k
+			}
+			av, inA := a.members[k]
+			bv, inB := b.members[k]
+			if !inA || !inB {
+				return childPath, MismatchCauseValue, true
+			}
+			if p, c, found := diffOrderedJSON(childPath, av, bv); found {
+				return p, c, true
+			}
+		}
+
+		for i := range a.keys {
+			if a.keys[i] != b.keys[i] {
+				return path, MismatchCauseKeyOrder, true
+			}
+		}
+		return "", "", false
+	}
+}
+
+// diffScalars compares two decoded scalar tokens.
+func diffScalars(a, b interface{}) (MismatchCause, bool) {
+	switch av := a.(type) {
+	case string:
+		switch bv := b.(type) {
+		case string:
+			if av == bv {
+				return "", false
+			}
+			if norm.NFC.String(av) == norm.NFC.String(bv) {
+				return MismatchCauseUnicodeNormalization, true
+			}
+			return MismatchCauseValue, true
+		case json.Number:
+			if numericallyEqual(av, bv.String()) {
+				return MismatchCauseNumberFormat, true
+			}
+		}
+	case json.Number:
+		switch bv := b.(type) {
+		case json.Number:
+			if av == bv {
+				return "", false
+			}
+			if numericallyEqual(av.String(), bv.String()) {
+				return MismatchCauseNumberFormat, true
+			}
+		case string:
+			if numericallyEqual(av.String(), bv) {
+				return MismatchCauseNumberFormat, true
+			}
+		}
+	default: // bool or nil
+		if a == b {
+			return "", false
+		}
+	}
+	return MismatchCauseValue, true
+}
+
+// numericallyEqual reports whether two decimal literals denote the same number.
+func numericallyEqual(a, b string) bool {
+	ra, okA := new(big.Rat).SetString(a)
+	rb, okB := new(big.Rat).SetString(b)
+	return okA && okB && ra.Cmp(rb) == 0
+}
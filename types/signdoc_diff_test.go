@@ -0,0 +1,128 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainSignBytesMismatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		expected  string
+		actual    string
+		wantCause MismatchCause
+		wantPath  string
+	}{
+		{
+			name:      "whitespace",
+			expected:  `{"a":"1","b":"2"}`,
+			actual:    `{"a": "1", "b": "2"}`,
+			wantCause: MismatchCauseWhitespace,
+		},
+		{
+			name:      "key order",
+			expected:  `{"messages":[{"data":{"amount":"1","to":"bob"}}]}`,
+			actual:    `{"messages":[{"data":{"to":"bob","amount":"1"}}]}`,
+			wantCause: MismatchCauseKeyOrder,
+			wantPath:  "messages[0].data",
+		},
+		{
+			name:      "nfc",
+			expected:  `{"memo":"café"}`,
+			actual:    "{\"memo\":\"cafe\u0301\"}",
+			wantCause: MismatchCauseUnicodeNormalization,
+			wantPath:  "memo",
+		},
+		{
+			name:      "number as string",
+			expected:  `{"fee":{"gas_limit":"100"}}`,
+			actual:    `{"fee":{"gas_limit":100}}`,
+			wantCause: MismatchCauseNumberFormat,
+			wantPath:  "fee.gas_limit",
+		},
+		{
+			name:      "number literal form",
+			expected:  `{"n":100}`,
+			actual:    `{"n":1e2}`,
+			wantCause: MismatchCauseNumberFormat,
+			wantPath:  "n",
+		},
+		{
+			name:      "string escaping",
+			expected:  `{"memo":"A<"}`,
+			actual:    `{"memo":"\u0041\u003c"}`,
+			wantCause: MismatchCauseStringEscaping,
+		},
+		{
+			name:      "different value",
+			expected:  `{"chain_id":"a","nonce":"1"}`,
+			actual:    `{"chain_id":"a","nonce":"2"}`,
+			wantCause: MismatchCauseValue,
+			wantPath:  "nonce",
+		},
+		{
+			name:      "missing field",
+			expected:  `{"a":"1","b":"2"}`,
+			actual:    `{"a":"1"}`,
+			wantCause: MismatchCauseValue,
+			wantPath:  "b",
+		},
+		{
+			name:      "value wins over key order",
+			expected:  `{"a":"1","b":"2"}`,
+			actual:    `{"b":"3","a":"1"}`,
+			wantCause: MismatchCauseValue,
+			wantPath:  "b",
+		},
+		{
+			name:      "malformed",
+			expected:  `{"a":"1"}`,
+			actual:    `{"a":`,
+			wantCause: MismatchCauseMalformed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := ExplainSignBytesMismatch([]byte(tt.expected), []byte(tt.actual))
+			require.NotNil(t, m)
+			assert.Equal(t, tt.wantCause, m.Cause)
+			assert.Equal(t, tt.wantPath, m.Path)
+			assert.Equal(t, firstDifferingByte([]byte(tt.expected), []byte(tt.actual)), m.Offset)
+			assert.Contains(t, m.String(), string(tt.wantCause))
+		})
+	}
+}
+
+func TestExplainSignBytesMismatch_Identical(t *testing.T) {
+	m := ExplainSignBytesMismatch([]byte(`{"a":1}`), []byte(`{"a":1}`))
+	assert.Nil(t, m)
+	assert.Equal(t, "sign bytes match", m.String())
+}
+
+func TestDiffSignDocs(t *testing.T) {
+	a, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+	require.NoError(t, err)
+	a.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"amount":"100","to":"bob"}`))
+
+	b, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+	require.NoError(t, err)
+	b.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"amount":"100","to":"bob"}`))
+
+	m, err := DiffSignDocs(a, b)
+	require.NoError(t, err)
+	assert.Nil(t, m)
+
+	b.ChainID = "other-chain"
+	m, err = DiffSignDocs(a, b)
+	require.NoError(t, err)
+	require.NotNil(t, m)
+	assert.Equal(t, "chain_id", m.Path)
+	assert.Equal(t, MismatchCauseValue, m.Cause)
+
+	_, err = DiffSignDocs(a, nil)
+	assert.ErrorIs(t, err, ErrSignDocMismatch)
+}
@@ -0,0 +1,176 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Canonical field encodings for SignDocSerializable implementations. Message
+// fields are encoded as strings (numbers in decimal) and strings are Unicode
+// NFC-normalized, so that clients in any language produce the same bytes for
+// the same message.
+
+// SignDocString returns s in canonical form: NFC-normalized
+func SignDocString[T ~string](s T) string {
+	return norm.NFC.String(string(s))
+}
+
+// SignDocStrings returns the canonical form of each element of s.
+//
+// INVARIANT: The result is never nil, so nil and empty slices encode alike.
+func SignDocStrings[T ~string](s []T) []string {
+	out := make([]string, len(s))
+	for i, v := range s {
+		out[i] = SignDocString(v)
+	}
+	return out
+}
+
+// NewSignDocCoin converts a Coin to SignDocCoin format with an NFC-normalized
+// denomination and a decimal string amount
+func NewSignDocCoin(coin Coin) SignDocCoin {
+	return SignDocCoin{
+		Denom:  SignDocString(coin.Denom),
+		Amount: strconv.FormatUint(coin.Amount, 10),
+	}
+}
+
+// NewSignDocCoins converts Coins to SignDocCoin format, preserving order.
+// Use CanonicalCoins for coin lists whose order is not already canonical.
+//
+// INVARIANT: The result is never nil, so nil and empty coins encode alike.
+func NewSignDocCoins(coins Coins) []SignDocCoin {
+	out := make([]SignDocCoin, len(coins))
+	for i, coin := range coins {
+		out[i] = NewSignDocCoin(coin)
+	}
+	return out
+}
+
+// CanonicalCoins is a coin list for message data that encodes in canonical
+// form whatever order it was built in: coins as SignDocCoin, with
+// NFC-normalized denominations in strictly ascending byte order. Encoding
+// fails with ErrInvalidCoin on an empty or duplicate denomination, and
+// decoding rejects non-canonical input rather than reordering it.
+//
+// SECURITY: Message data is signed byte for byte, so a coin list a client
+// orders differently from the chain signs differently, and a list naming a
+// denomination twice is read differently by different consumers.
+//
+// Usage in a SignDocData implementation:
+//
+//	Amount types.CanonicalCoins `json:"amount"`
+type CanonicalCoins Coins
+
+// SignDocCoins returns the coins in canonical order and SignDocCoin format.
+//
+// INVARIANT: The result is never nil, so nil and empty coins encode alike.
+func (c CanonicalCoins) SignDocCoins() ([]SignDocCoin, error) {
+	out := NewSignDocCoins(Coins(c))
+	sort.Slice(out, func(i, j int) bool { return out[i].Denom < out[j].Denom })
+
+	for i := range out {
+		if out[i].Denom == "" {
+			return nil, fmt.Errorf("%w: empty denom", ErrInvalidCoin)
+		}
+		if i > 0 && out[i].Denom == out[i-1].Denom {
+			return nil, fmt.Errorf("%w: duplicate denom %q", ErrInvalidCoin, out[i].Denom)
+		}
+	}
+	return out, nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c CanonicalCoins) MarshalJSON() ([]byte, error) {
+	coins, err := c.SignDocCoins()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(coins)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The input must be in the
+// canonical form MarshalJSON produces.
+func (c *CanonicalCoins) UnmarshalJSON(data []byte) error {
+	var coins []SignDocCoin
+	if err := json.Unmarshal(data, &coins); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCoin, err)
+	}
+
+	out := make(CanonicalCoins, len(coins))
+	for i, coin := range coins {
+		if err := coin.ValidateBasic(); err != nil {
+			return fmt.Errorf("%w: coin %d: %v", ErrInvalidCoin, i, err)
+		}
+		if i > 0 && coin.Denom <= coins[i-1].Denom {
+			return fmt.Errorf("%w: coin %d: denom %q is not in ascending order", ErrInvalidCoin, i, coin.Denom)
+		}
+		amount, err := strconv.ParseUint(coin.Amount, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: coin %d: %v", ErrInvalidCoin, i, err)
+		}
+		// SECURITY: "007" parses as 7 but signs differently; accept only
+		// the encoding MarshalJSON produces
+		if strconv.FormatUint(amount, 10) != coin.Amount {
+			return fmt.Errorf("%w: coin %d: amount %q is not canonical", ErrInvalidCoin, i, coin.Amount)
+		}
+		out[i] = Coin{Denom: coin.Denom, Amount: amount}
+	}
+
+	*c = out
+	return nil
+}
+
+// SortCoinsJSON returns data, a JSON array of coin objects, with its
+// elements in strictly ascending byte order of their NFC-normalized
+// "denom" member. Elements are otherwise copied unchanged. It fails with
+// ErrInvalidCoin if data is not such an array or names a denomination
+// twice.
+//
+// Use it on message data built elsewhere; messages built in Go should
+// use CanonicalCoins.
+//
+// Complexity: O(n log n) for n coins.
+func SortCoinsJSON(data json.RawMessage) (json.RawMessage, error) {
+	var elems []json.RawMessage
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return nil, fmt.Errorf("%w: coin list must be a JSON array: %v", ErrInvalidCoin, err)
+	}
+
+	denoms := make([]string, len(elems))
+	for i, elem := range elems {
+		var coin struct {
+			Denom *string `json:"denom"`
+		}
+		if err := json.Unmarshal(elem, &coin); err != nil || coin.Denom == nil || *coin.Denom == "" {
+			return nil, fmt.Errorf("%w: coin %d has no denom", ErrInvalidCoin, i)
+		}
+		denoms[i] = SignDocString(*coin.Denom)
+	}
+
+	order := make([]int, len(elems))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return denoms[order[i]] < denoms[order[j]] })
+
+	var buf bytes.Buffer
+	buf.Grow(len(data))
+	buf.WriteByte('[')
+	for i, idx := range order {
+		if i > 0 {
+			if denoms[idx] == denoms[order[i-1]] {
+				return nil, fmt.Errorf("%w: duplicate denom %q", ErrInvalidCoin, denoms[idx])
+			}
+			buf.WriteByte(',')
+		}
+		buf.Write(elems[idx])
+	}
+	buf.WriteByte(']')
+	return json.RawMessage(buf.Bytes()), nil
+}
@@ -0,0 +1,140 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignDocString_NFC(t *testing.T) {
+	assert.Equal(t, "caf\u00e9", SignDocString("cafe\u0301"))
+	assert.Equal(t, "alice", SignDocString(AccountName("alice")))
+}
+
+func TestSignDocStrings_NilAndEmptyEncodeAlike(t *testing.T) {
+	nilJSON, err := json.Marshal(SignDocStrings[AccountName](nil))
+	require.NoError(t, err)
+	emptyJSON, err := json.Marshal(SignDocStrings([]AccountName{}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "[]", string(nilJSON))
+	assert.Equal(t, nilJSON, emptyJSON)
+	assert.Equal(t, []string{"caf\u00e9", "bob"}, SignDocStrings([]string{"cafe\u0301", "bob"}))
+}
+
+func TestNewSignDocCoins(t *testing.T) {
+	coins := NewSignDocCoins(Coins{{Denom: "atom", Amount: 5}, {Denom: "stake", Amount: 18446744073709551615}})
+	assert.Equal(t, []SignDocCoin{
+		{Denom: "atom", Amount: "5"},
+		{Denom: "stake", Amount: "18446744073709551615"},
+	}, coins)
+
+	assert.NotNil(t, NewSignDocCoins(nil))
+	assert.Equal(t, SignDocCoin{Denom: "caf\u00e9", Amount: "0"}, NewSignDocCoin(Coin{Denom: "cafe\u0301"}))
+}
+
+func TestCanonicalCoins_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		coins   CanonicalCoins
+		want    string
+		wantErr bool
+	}{
+		{name: "nil", coins: nil, want: `[]`},
+		{name: "sorted", coins: CanonicalCoins{{Denom: "atom", Amount: 5}, {Denom: "stake", Amount: 1}}, want: `[{"denom":"atom","amount":"5"},{"denom":"stake","amount":"1"}]`},
+		{name: "unsorted", coins: CanonicalCoins{{Denom: "stake", Amount: 1}, {Denom: "atom", Amount: 5}}, want: `[{"denom":"atom","amount":"5"},{"denom":"stake","amount":"1"}]`},
+		{name: "byte order", coins: CanonicalCoins{{Denom: "b"}, {Denom: "B"}, {Denom: "a"}}, want: `[{"denom":"B","amount":"0"},{"denom":"a","amount":"0"},{"denom":"b","amount":"0"}]`},
+		{name: "duplicate denom", coins: CanonicalCoins{{Denom: "atom", Amount: 1}, {Denom: "stake"}, {Denom: "atom", Amount: 2}}, wantErr: true},
+		{name: "duplicate after normalization", coins: CanonicalCoins{{Denom: "caf\u00e9"}, {Denom: "cafe\u0301"}}, wantErr: true},
+		{name: "empty denom", coins: CanonicalCoins{{Amount: 1}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(struct {
+				Amount CanonicalCoins `json:"amount"`
+			}{tt.coins})
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidCoin)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, `{"amount":`+tt.want+`}`, string(data))
+		})
+	}
+
+	// Marshaling does not reorder the caller's coins
+	coins := CanonicalCoins{{Denom: "stake"}, {Denom: "atom"}}
+	_, err := coins.MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, "stake", coins[0].Denom)
+}
+
+func TestCanonicalCoins_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    CanonicalCoins
+		wantErr bool
+	}{
+		{name: "empty", input: `[]`, want: CanonicalCoins{}},
+		{name: "canonical", input: `[{"denom":"atom","amount":"5"},{"denom":"stake","amount":"18446744073709551615"}]`,
+			want: CanonicalCoins{{Denom: "atom", Amount: 5}, {Denom: "stake", Amount: 18446744073709551615}}},
+		{name: "unsorted", input: `[{"denom":"stake","amount":"1"},{"denom":"atom","amount":"5"}]`, wantErr: true},
+		{name: "duplicate", input: `[{"denom":"atom","amount":"1"},{"denom":"atom","amount":"1"}]`, wantErr: true},
+		{name: "leading zero", input: `[{"denom":"atom","amount":"05"}]`, wantErr: true},
+		{name: "bare amount", input: `[{"denom":"atom","amount":5}]`, wantErr: true},
+		{name: "not normalized", input: `[{"denom":"cafe\u0301","amount":"1"}]`, wantErr: true},
+		{name: "not an array", input: `{"denom":"atom","amount":"1"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var coins CanonicalCoins
+			err := json.Unmarshal([]byte(tt.input), &coins)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidCoin)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, coins)
+
+			data, err := json.Marshal(coins)
+			require.NoError(t, err)
+			assert.Equal(t, tt.input, string(data), "canonical input must round-trip")
+		})
+	}
+}
+
+func TestSortCoinsJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "empty", input: `[]`, want: `[]`},
+		{name: "sorted", input: `[{"denom":"atom","amount":"5"},{"denom":"stake","amount":"1"}]`, want: `[{"denom":"atom","amount":"5"},{"denom":"stake","amount":"1"}]`},
+		{name: "unsorted", input: `[{"denom":"stake","amount":"1"},{"amount":"5","denom":"atom"}]`, want: `[{"amount":"5","denom":"atom"},{"denom":"stake","amount":"1"}]`},
+		{name: "whitespace", input: ` [ {"denom":"b"} , {"denom":"a"} ] `, want: `[{"denom":"a"},{"denom":"b"}]`},
+		{name: "duplicate", input: `[{"denom":"atom","amount":"1"},{"denom":"stake"},{"denom":"atom","amount":"2"}]`, wantErr: true},
+		{name: "duplicate after normalization", input: `[{"denom":"caf\u00e9"},{"denom":"cafe\u0301"}]`, wantErr: true},
+		{name: "missing denom", input: `[{"amount":"1"}]`, wantErr: true},
+		{name: "non-object element", input: `["atom"]`, wantErr: true},
+		{name: "not an array", input: `{"denom":"atom"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SortCoinsJSON(json.RawMessage(tt.input))
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidCoin)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
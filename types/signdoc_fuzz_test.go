@@ -221,7 +221,10 @@ func FuzzSignDocMessageData(f *testing.F) {
 
 	f.Fuzz(func(t *testing.T, data []byte) {
 		// Test that message data can be used in a SignDoc without panicking
-		sd := NewSignDoc("chain", 1, "alice", 1, "")
+		sd, err := NewSignDoc("chain", 1, "alice", 1, "")
+		if err != nil {
+			t.Fatalf("NewSignDoc failed: %v", err)
+		}
 		sd.AddMessage("/test.msg", json.RawMessage(data))
 
 		// SECURITY INVARIANT: ToJSON must not panic on any message data
@@ -424,7 +427,10 @@ func FuzzSignDocUnicodeHandling(f *testing.F) {
 		// Test that we handle all string inputs gracefully
 
 		// Test in memo field
-		sd := NewSignDoc("chain", 1, "alice", 1, input)
+		sd, err := NewSignDoc("chain", 1, "alice", 1, input)
+		if err != nil {
+			t.Fatalf("NewSignDoc failed: %v", err)
+		}
 		sd.AddMessage("/msg", json.RawMessage(`{}`))
 
 		jsonBytes, err := sd.ToJSON()
@@ -584,7 +590,10 @@ func FuzzSignDocJSONInjection(f *testing.F) {
 
 	f.Fuzz(func(t *testing.T, payload string) {
 		// Try to inject the payload through the memo field
-		sd := NewSignDoc("chain", 1, "alice", 1, payload)
+		sd, err := NewSignDoc("chain", 1, "alice", 1, payload)
+		if err != nil {
+			t.Fatalf("NewSignDoc failed: %v", err)
+		}
 		sd.AddMessage("/msg", json.RawMessage(`{}`))
 
 		jsonBytes, err := sd.ToJSON()
@@ -679,7 +688,10 @@ func FuzzSignDocBoundaryValues(f *testing.F) {
 
 	f.Fuzz(func(t *testing.T, value uint64) {
 		// Test with the value in various fields
-		sd := NewSignDoc("chain", value, "alice", value, "")
+		sd, err := NewSignDoc("chain", value, "alice", value, "")
+		if err != nil {
+			t.Fatalf("NewSignDoc failed: %v", err)
+		}
 		sd.AddMessage("/msg", json.RawMessage(`{}`))
 
 		// SECURITY INVARIANT: Serialization must not panic
@@ -0,0 +1,31 @@
+package types_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	punnettesting "github.com/blockberries/punnet-sdk/testing"
+	"github.com/blockberries/punnet-sdk/types"
+)
+
+// TestSignDocFixtures_Golden pins the SignDoc fixtures' JSON and sign bytes
+// (SHA-256 of the JSON) to golden files under testdata/golden/signdoc.
+// Regenerate with: go test ./types -run TestSignDocFixtures_Golden -update
+func TestSignDocFixtures_Golden(t *testing.T) {
+	for _, fixture := range types.SignDocFixtures() {
+		t.Run(fixture.Name, func(t *testing.T) {
+			jsonBytes, err := fixture.SignDoc.ToJSON()
+			require.NoError(t, err)
+			punnettesting.AssertMatchesGolden(t, "signdoc/"+fixture.Name, jsonBytes)
+
+			signBytes, err := fixture.SignDoc.GetSignBytes()
+			require.NoError(t, err)
+			expected := sha256.Sum256(jsonBytes)
+			require.Equal(t, expected[:], signBytes, "sign bytes must be the SHA-256 of the JSON")
+			punnettesting.AssertMatchesGolden(t, "signdoc/"+fixture.Name+".sha256", []byte(hex.EncodeToString(signBytes)+"\n"))
+		})
+	}
+}
@@ -8,8 +8,29 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// mustNewSignDoc is NewSignDoc for fixtures built outside a test function.
+// It panics on an invalid chain ID.
+func mustNewSignDoc(chainID string, accountSequence uint64, account string, nonce uint64, memo string) *SignDoc {
+	sd, err := NewSignDoc(chainID, accountSequence, account, nonce, memo)
+	if err != nil {
+		panic(err)
+	}
+	return sd
+}
+
+// mustNewSignDocWithFee is NewSignDocWithFee for fixtures built outside a
+// test function. It panics on an invalid chain ID.
+func mustNewSignDocWithFee(chainID string, accountSequence uint64, account string, nonce uint64, memo string, fee SignDocFee, feeSlippage SignDocRatio) *SignDoc {
+	sd, err := NewSignDocWithFee(chainID, accountSequence, account, nonce, memo, fee, feeSlippage)
+	if err != nil {
+		panic(err)
+	}
+	return sd
+}
+
 func TestSignDoc_NewSignDoc(t *testing.T) {
-	sd := NewSignDoc("test-chain", 42, "alice", 1, "test memo")
+	sd, err := NewSignDoc("test-chain", 42, "alice", 1, "test memo")
+	require.NoError(t, err)
 
 	assert.Equal(t, SignDocVersion, sd.Version)
 	assert.Equal(t, "test-chain", sd.ChainID)
@@ -26,7 +47,8 @@ func TestSignDoc_NewSignDoc(t *testing.T) {
 }
 
 func TestSignDoc_AddMessage(t *testing.T) {
-	sd := NewSignDoc("test-chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 
 	msgData := json.RawMessage(`{"to":"bob","amount":100}`)
 	sd.AddMessage("/punnet.bank.v1.MsgSend", msgData)
@@ -38,7 +60,8 @@ func TestSignDoc_AddMessage(t *testing.T) {
 
 func TestSignDoc_ToJSON_Deterministic(t *testing.T) {
 	// INVARIANT: Two calls to ToJSON on the same SignDoc must produce identical bytes.
-	sd := NewSignDoc("test-chain", 1, "alice", 1, "test")
+	sd, err := NewSignDoc("test-chain", 1, "alice", 1, "test")
+	require.NoError(t, err)
 	sd.AddMessage("/msg.Type", json.RawMessage(`{"key":"value"}`))
 
 	json1, err1 := sd.ToJSON()
@@ -51,7 +74,8 @@ func TestSignDoc_ToJSON_Deterministic(t *testing.T) {
 }
 
 func TestSignDoc_GetSignBytes(t *testing.T) {
-	sd := NewSignDoc("test-chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 	sd.AddMessage("/msg.Type", json.RawMessage(`{"key":"value"}`))
 
 	signBytes, err := sd.GetSignBytes()
@@ -76,7 +100,8 @@ func TestSignDoc_ValidateBasic(t *testing.T) {
 		{
 			name: "valid SignDoc",
 			signDoc: func() *SignDoc {
-				sd := NewSignDoc("test-chain", 1, "alice", 1, "")
+				sd, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+				require.NoError(t, err)
 				sd.AddMessage("/msg.Type", json.RawMessage(`{}`))
 				return sd
 			}(),
@@ -260,13 +285,16 @@ func TestSignDoc_ValidateBasic(t *testing.T) {
 }
 
 func TestSignDoc_Equals(t *testing.T) {
-	sd1 := NewSignDoc("test-chain", 1, "alice", 1, "memo")
+	sd1, err := NewSignDoc("test-chain", 1, "alice", 1, "memo")
+	require.NoError(t, err)
 	sd1.AddMessage("/msg", json.RawMessage(`{"key":"value"}`))
 
-	sd2 := NewSignDoc("test-chain", 1, "alice", 1, "memo")
+	sd2, err := NewSignDoc("test-chain", 1, "alice", 1, "memo")
+	require.NoError(t, err)
 	sd2.AddMessage("/msg", json.RawMessage(`{"key":"value"}`))
 
-	sd3 := NewSignDoc("different-chain", 1, "alice", 1, "memo")
+	sd3, err := NewSignDoc("different-chain", 1, "alice", 1, "memo")
+	require.NoError(t, err)
 	sd3.AddMessage("/msg", json.RawMessage(`{"key":"value"}`))
 
 	assert.True(t, sd1.Equals(sd2), "identical SignDocs should be equal")
@@ -275,7 +303,8 @@ func TestSignDoc_Equals(t *testing.T) {
 }
 
 func TestSignDoc_ParseSignDoc(t *testing.T) {
-	original := NewSignDoc("test-chain", 42, "alice", 1, "memo")
+	original, err := NewSignDoc("test-chain", 42, "alice", 1, "memo")
+	require.NoError(t, err)
 	original.AddMessage("/msg", json.RawMessage(`{"key":"value"}`))
 
 	jsonBytes, err := original.ToJSON()
@@ -291,7 +320,8 @@ func TestSignDoc_Roundtrip(t *testing.T) {
 	// This tests the critical security property that serialization is deterministic.
 	// INVARIANT: JSON -> parse -> JSON must produce identical bytes.
 
-	original := NewSignDoc("test-chain", 1, "alice", 1, "")
+	original, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 	original.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"from":"alice","to":"bob","amount":"100"}`))
 	original.AddMessage("/punnet.stake.v1.MsgDelegate", json.RawMessage(`{"delegator":"alice","validator":"val1"}`))
 
@@ -681,7 +711,8 @@ func TestSignDoc_NewSignDocWithFee(t *testing.T) {
 	}
 	slippage := SignDocRatio{Numerator: "1", Denominator: "100"}
 
-	sd := NewSignDocWithFee("test-chain", 42, "alice", 1, "memo", fee, slippage)
+	sd, err := NewSignDocWithFee("test-chain", 42, "alice", 1, "memo", fee, slippage)
+	require.NoError(t, err)
 
 	assert.Equal(t, SignDocVersion, sd.Version)
 	assert.Equal(t, "test-chain", sd.ChainID)
@@ -694,7 +725,8 @@ func TestSignDoc_NewSignDocWithFee(t *testing.T) {
 }
 
 func TestSignDoc_SetFee(t *testing.T) {
-	sd := NewSignDoc("test-chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 
 	newFee := SignDocFee{
 		Amount:   []SignDocCoin{{Denom: "stake", Amount: "10000"}},
@@ -706,7 +738,8 @@ func TestSignDoc_SetFee(t *testing.T) {
 }
 
 func TestSignDoc_SetFeeSlippage(t *testing.T) {
-	sd := NewSignDoc("test-chain", 1, "alice", 1, "")
+	sd, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+	require.NoError(t, err)
 
 	newSlippage := SignDocRatio{Numerator: "10", Denominator: "100"}
 	sd.SetFeeSlippage(newSlippage)
@@ -724,10 +757,11 @@ func TestSignDoc_ValidateBasic_WithFee(t *testing.T) {
 		{
 			name: "valid SignDoc with fee",
 			signDoc: func() *SignDoc {
-				sd := NewSignDocWithFee("test-chain", 1, "alice", 1, "",
+				sd, err := NewSignDocWithFee("test-chain", 1, "alice", 1, "",
 					SignDocFee{Amount: []SignDocCoin{{Denom: "uatom", Amount: "1000"}}, GasLimit: "200000"},
 					SignDocRatio{Numerator: "1", Denominator: "100"},
 				)
+				require.NoError(t, err)
 				sd.AddMessage("/msg.Type", json.RawMessage(`{}`))
 				return sd
 			}(),
@@ -736,7 +770,8 @@ func TestSignDoc_ValidateBasic_WithFee(t *testing.T) {
 		{
 			name: "invalid fee - bad gas limit",
 			signDoc: func() *SignDoc {
-				sd := NewSignDoc("test-chain", 1, "alice", 1, "")
+				sd, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+				require.NoError(t, err)
 				sd.Fee.GasLimit = "invalid"
 				sd.AddMessage("/msg.Type", json.RawMessage(`{}`))
 				return sd
@@ -747,7 +782,8 @@ func TestSignDoc_ValidateBasic_WithFee(t *testing.T) {
 		{
 			name: "invalid fee slippage - zero denominator",
 			signDoc: func() *SignDoc {
-				sd := NewSignDoc("test-chain", 1, "alice", 1, "")
+				sd, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+				require.NoError(t, err)
 				sd.FeeSlippage = SignDocRatio{Numerator: "1", Denominator: "0"}
 				sd.AddMessage("/msg.Type", json.RawMessage(`{}`))
 				return sd
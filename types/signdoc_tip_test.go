@@ -0,0 +1,127 @@
+package types
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransaction_ToSignDoc_Tip(t *testing.T) {
+	tx := newCodecTx(t)
+
+	plain, err := tx.ToSignDoc("punnet-1", 7)
+	require.NoError(t, err)
+	assert.Equal(t, SignDocVersion, plain.Version)
+	assert.Nil(t, plain.Tip)
+
+	tip := NewCoin("stake", 250)
+	tx.Tip = &tip
+	tipped, err := tx.ToSignDoc("punnet-1", 7)
+	require.NoError(t, err)
+	assert.Equal(t, SignDocVersionTip, tipped.Version)
+	require.NotNil(t, tipped.Tip)
+	assert.Equal(t, SignDocCoin{Denom: "stake", Amount: "250"}, *tipped.Tip)
+	require.NoError(t, tipped.ValidateBasic())
+
+	data, err := tipped.ToJSON()
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data, []byte(`{"version":"2",`)))
+	assert.Contains(t, string(data), `"fee_slippage":{"numerator":"1","denominator":"100"},"tip":{"denom":"stake","amount":"250"}`)
+
+	parsed, err := ParseSignDoc(data)
+	require.NoError(t, err)
+	assert.True(t, parsed.Equals(tipped))
+
+	plainBytes, err := plain.GetSignBytes()
+	require.NoError(t, err)
+	tippedBytes, err := tipped.GetSignBytes()
+	require.NoError(t, err)
+	assert.NotEqual(t, plainBytes, tippedBytes, "the tip must be signed over")
+}
+
+func TestSignDoc_ValidateBasic_Tip(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		tip     *SignDocCoin
+		wantErr string
+	}{
+		{name: "v1 without tip", version: SignDocVersion},
+		{name: "v2 with tip", version: SignDocVersionTip, tip: &SignDocCoin{Denom: "stake", Amount: "1"}},
+		{name: "v1 with tip", version: SignDocVersion, tip: &SignDocCoin{Denom: "stake", Amount: "1"}, wantErr: "tip requires version 2"},
+		{name: "v2 without tip", version: SignDocVersionTip, wantErr: "version 2 requires a tip"},
+		{name: "zero tip", version: SignDocVersionTip, tip: &SignDocCoin{Denom: "stake", Amount: "0"}, wantErr: "must be positive"},
+		{name: "zero tip with leading zeros", version: SignDocVersionTip, tip: &SignDocCoin{Denom: "stake", Amount: "00"}, wantErr: "must be positive"},
+		{name: "empty denom", version: SignDocVersionTip, tip: &SignDocCoin{Amount: "1"}, wantErr: "denom cannot be empty"},
+		{name: "invalid amount", version: SignDocVersionTip, tip: &SignDocCoin{Denom: "stake", Amount: "-1"}, wantErr: "must be a decimal string"},
+		{name: "unsupported version", version: "3", wantErr: "unsupported SignDoc version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sd, err := NewSignDoc("punnet-1", 1, "alice", 1, "")
+			require.NoError(t, err)
+			sd.AddMessage(codecMsgType, []byte(`{"amount":"1"}`))
+			sd.Version = tt.version
+			sd.Tip = tt.tip
+			err = sd.ValidateBasic()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrSignDocMismatch)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestTransaction_ValidateBasic_Tip(t *testing.T) {
+	tests := []struct {
+		name    string
+		tip     *Coin
+		wantErr bool
+	}{
+		{name: "no tip"},
+		{name: "positive tip", tip: &Coin{Denom: "stake", Amount: 1}},
+		{name: "zero tip", tip: &Coin{Denom: "stake"}, wantErr: true},
+		{name: "empty denom", tip: &Coin{Amount: 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := newCodecTx(t)
+			tx.Tip = tt.tip
+			err := tx.ValidateBasic()
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidTransaction)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTransactionEncode_Tip(t *testing.T) {
+	tx := newCodecTx(t)
+	tip := NewCoin("stake", 250)
+	tx.Tip = &tip
+
+	bz, err := tx.Encode()
+	require.NoError(t, err)
+	require.NoError(t, ValidateCanonicalKeyOrder(bz))
+	assert.True(t, bytes.HasSuffix(bz, []byte(`,"nonce":"7","tip":{"amount":"250","denom":"stake"}}`)))
+
+	decoded, err := newCodecDecoder(t).Decode(bz)
+	require.NoError(t, err)
+	require.NotNil(t, decoded.Tip)
+	assert.Equal(t, tip, *decoded.Tip)
+
+	// A null tip is not canonical: an unset tip is omitted
+	plain, err := newCodecTx(t).Encode()
+	require.NoError(t, err)
+	malleated := append(bytes.TrimSuffix(plain, []byte("}")), []byte(`,"tip":null}`)...)
+	_, err = newCodecDecoder(t).Decode(malleated)
+	require.ErrorIs(t, err, ErrInvalidTransaction)
+}
@@ -177,11 +177,11 @@ func TestSignDoc_ValidateBasic_NFCValidation(t *testing.T) {
 		}
 	}
 
-	t.Run("NFC-normalized ChainID passes", func(t *testing.T) {
+	t.Run("NFC-normalized non-ASCII ChainID fails", func(t *testing.T) {
 		sd := validSignDoc()
 		sd.ChainID = "test-\u00e9" // NFC é
 		err := sd.ValidateBasic()
-		assert.NoError(t, err)
+		assert.ErrorIs(t, err, ErrInvalidChainID)
 	})
 
 	t.Run("Non-NFC ChainID fails", func(t *testing.T) {
@@ -247,7 +247,7 @@ func TestSignDoc_ValidateBasic_NFCValidation(t *testing.T) {
 				sd := validSignDoc()
 				sd.ChainID = tc.nfc
 				err := sd.ValidateBasic()
-				assert.NoError(t, err, "NFC form should pass for ChainID")
+				assert.ErrorIs(t, err, ErrInvalidChainID, "chain IDs are restricted to ASCII")
 			})
 			t.Run(tc.name+"-account", func(t *testing.T) {
 				sd := validSignDoc()
@@ -364,14 +364,16 @@ func TestNFC_AttackScenarios(t *testing.T) {
 		nfdMemo := "Payment to cafe\u0301"
 
 		// Both create valid-looking SignDocs
-		sdNFC := NewSignDoc("test", 1, "alice", 1, nfcMemo)
+		sdNFC, err := NewSignDoc("test", 1, "alice", 1, nfcMemo)
+		require.NoError(t, err)
 		sdNFC.AddMessage("/test", json.RawMessage(`{}`))
 
-		sdNFD := NewSignDoc("test", 1, "alice", 1, nfdMemo)
+		sdNFD, err := NewSignDoc("test", 1, "alice", 1, nfdMemo)
+		require.NoError(t, err)
 		sdNFD.AddMessage("/test", json.RawMessage(`{}`))
 
 		// NFC version should validate
-		err := sdNFC.ValidateBasic()
+		err = sdNFC.ValidateBasic()
 		assert.NoError(t, err, "NFC form should pass validation")
 
 		// NFD version should be rejected
@@ -389,11 +391,12 @@ func TestNFC_AttackScenarios(t *testing.T) {
 		// them, we should ensure they don't break our validation.
 
 		memo := "hello\u200Bworld" // Zero-width space
-		sd := NewSignDoc("test", 1, "alice", 1, memo)
+		sd, err := NewSignDoc("test", 1, "alice", 1, memo)
+		require.NoError(t, err)
 		sd.AddMessage("/test", json.RawMessage(`{}`))
 
 		// Should pass NFC validation (ZWSP is already in NFC form)
-		err := sd.ValidateBasic()
+		err = sd.ValidateBasic()
 		assert.NoError(t, err)
 	})
 }
@@ -476,16 +479,18 @@ func FuzzNFCSignDocRoundtrip(f *testing.F) {
 		account = norm.NFC.String(account)
 		memo = norm.NFC.String(memo)
 
-		// Skip if any field is empty (invalid for other reasons)
-		if chainID == "" || account == "" {
+		// Skip if any field is empty or the chain ID is outside its grammar
+		// (invalid for other reasons)
+		if chainID == "" || account == "" || ValidateChainID(chainID) != nil {
 			return
 		}
 
-		sd := NewSignDoc(chainID, 1, account, 1, memo)
+		sd, err := NewSignDoc(chainID, 1, account, 1, memo)
+		require.NoError(t, err)
 		sd.AddMessage("/test", json.RawMessage(`{}`))
 
 		// NFC-normalized input should always pass validation
-		err := sd.ValidateBasic()
+		err = sd.ValidateBasic()
 		if err != nil {
 			t.Errorf("NFC-normalized SignDoc failed validation: %v", err)
 			return
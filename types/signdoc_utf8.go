@@ -0,0 +1,120 @@
+package types
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// validateUTF8String checks that s is well-formed UTF-8.
+//
+// SECURITY: Go's UTF-8 decoder rejects overlong encodings and encoded
+// surrogates (U+D800-U+DFFF), so this check covers both. Implementations
+// disagree on how to escape invalid bytes - cramberry.EscapeJSONString
+// substitutes U+FFFD, others pass them through or fail - so a string that is
+// not valid UTF-8 has no portable canonical form and cannot be signed.
+func validateUTF8String(s string) error {
+	if utf8.ValidString(s) {
+		return nil
+	}
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size <= 1 {
+			return fmt.Errorf("invalid UTF-8 at byte %d", i)
+		}
+		i += size
+	}
+	return nil
+}
+
+// ValidateMessageDataUTF8 checks that message data is well-formed UTF-8 and
+// that every \u escape in its strings encodes a Unicode scalar value: a high
+// surrogate escape (\uD800-\uDBFF) must be immediately followed by a low
+// surrogate escape (\uDC00-\uDFFF), and a low surrogate escape must not appear
+// on its own.
+//
+// SECURITY: Unpaired surrogate escapes are valid JSON syntax but do not
+// denote any character. encoding/json silently decodes them to U+FFFD while
+// other parsers reject them or keep the lone surrogate, so the signed and
+// executed strings could differ.
+//
+// POSTCONDITION: Returns nil for empty data.
+// POSTCONDITION: Returns an error wrapping ErrSignDocMismatch on invalid
+// UTF-8 or an unpaired surrogate escape.
+//
+// Complexity: O(n) for n bytes of data.
+func ValidateMessageDataUTF8(data []byte) error {
+	if err := validateUTF8String(string(data)); err != nil {
+		return fmt.Errorf("%w: message data: %v", ErrSignDocMismatch, err)
+	}
+
+	inString := false
+	for i := 0; i < len(data); i++ {
+		b := data[i]
+		if !inString {
+			if b == '"' {
+				inString = true
+			}
+			continue
+		}
+
+		switch b {
+		case '"':
+			inString = false
+		case '\\':
+			if i+1 < len(data) && data[i+1] == 'u' {
+				n, err := checkSurrogateEscape(data, i)
+				if err != nil {
+					return fmt.Errorf("%w: message data: %v", ErrSignDocMismatch, err)
+				}
+				i += n - 1
+				continue
+			}
+			i++ // skip the escaped character
+		}
+	}
+	return nil
+}
+
+// checkSurrogateEscape checks the \u escape at data[i] and returns its length
+// in bytes, including the low surrogate escape of a surrogate pair. Escapes
+// with malformed hex digits are left to the JSON parser.
+func checkSurrogateEscape(data []byte, i int) (int, error) {
+	r, ok := parseUnicodeEscape(data, i)
+	if !ok {
+		return 2, nil
+	}
+
+	switch {
+	case r >= 0xD800 && r <= 0xDBFF:
+		low, ok := parseUnicodeEscape(data, i+6)
+		if !ok || low < 0xDC00 || low > 0xDFFF {
+			return 0, fmt.Errorf("unpaired high surrogate escape \\u%04X at byte %d", r, i)
+		}
+		return 12, nil
+	case r >= 0xDC00 && r <= 0xDFFF:
+		return 0, fmt.Errorf("unpaired low surrogate escape \\u%04X at byte %d", r, i)
+	}
+	return 6, nil
+}
+
+// parseUnicodeEscape decodes the \uXXXX escape at data[i]
+func parseUnicodeEscape(data []byte, i int) (rune, bool) {
+	if i+6 > len(data) || data[i] != '\\' || data[i+1] != 'u' {
+		return 0, false
+	}
+	var r rune
+	for _, c := range data[i+2 : i+6] {
+		r <<= 4
+		switch {
+		case c >= '0' && c <= '9':
+			r |= rune(c - '0')
+		case c >= 'a' && c <= 'f':
+			r |= rune(c-'a') + 10
+		case c >= 'A' && c <= 'F':
+			r |= rune(c-'A') + 10
+		default:
+			return 0, false
+		}
+	}
+	return r, true
+}
@@ -0,0 +1,83 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMessageDataUTF8(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"empty", ``, false},
+		{"ascii", `{"a":"b"}`, false},
+		{"multibyte", `{"name":"café 日本"}`, false},
+		{"bmp escape", `{"a":"\u00e9"}`, false},
+		{"surrogate pair", `{"a":"\ud83d\ude00"}`, false},
+		{"uppercase pair", `{"a":"\uD83D\uDE00"}`, false},
+		{"escaped backslash then u", `{"a":"\\ud800"}`, false},
+		{"surrogate escape in key", `{"\ud800":1}`, true},
+		{"lone high surrogate", `{"a":"\ud800"}`, true},
+		{"high surrogate at end of data", `"\ud800`, true},
+		{"high then non-surrogate", `{"a":"\ud800A"}`, true},
+		{"high then high", `{"a":"\ud800\ud800"}`, true},
+		{"lone low surrogate", `{"a":"\udc00"}`, true},
+		{"reversed pair", `{"a":"\ude00\ud83d"}`, true},
+		{"invalid byte", "{\"a\":\"\xff\"}", true},
+		{"overlong slash", "{\"a\":\"\xc0\xaf\"}", true},
+		{"encoded surrogate", "{\"a\":\"\xed\xa0\x80\"}", true},
+		{"truncated sequence", "{\"a\":\"\xe6\x97\"}", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMessageDataUTF8([]byte(tt.input))
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrSignDocMismatch)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSignDoc_ValidateBasic_UTF8(t *testing.T) {
+	newDoc := func() *SignDoc {
+		sd, err := NewSignDoc("test-chain", 1, "alice", 1, "")
+		require.NoError(t, err)
+		sd.AddMessage("/punnet.bank.v1.MsgSend", json.RawMessage(`{"amount":"1","to":"bob"}`))
+		return sd
+	}
+	require.NoError(t, newDoc().ValidateBasic())
+
+	tests := []struct {
+		name   string
+		mutate func(sd *SignDoc)
+		want   string
+	}{
+		{"account", func(sd *SignDoc) { sd.Account = "ali\xffce" }, "account"},
+		{"memo", func(sd *SignDoc) { sd.Memo = "\xc0\xaf" }, "memo"},
+		{"message type", func(sd *SignDoc) { sd.Messages[0].Type = "/msg\xed\xa0\x80" }, "message 0 type"},
+		{"message data", func(sd *SignDoc) { sd.Messages[0].Data = json.RawMessage(`{"to":"\udc00"}`) }, "unpaired low surrogate"},
+		{"fee denom", func(sd *SignDoc) {
+			sd.Fee.Amount = []SignDocCoin{{Denom: "st\xffake", Amount: "1"}}
+		}, "denom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sd := newDoc()
+			tt.mutate(sd)
+			err := sd.ValidateBasic()
+			require.Error(t, err)
+			assert.ErrorIs(t, err, ErrSignDocMismatch)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strconv"
+	"sync"
 )
 
 // Fee represents the transaction fee with gas limit and coin amounts.
@@ -94,6 +96,21 @@ func (r *Ratio) ValidateBasic() error {
 	return nil
 }
 
+// CoSigner is an additional signer of a multi-signer transaction (see
+// Transaction.CoSigners).
+type CoSigner struct {
+	// Account is the co-signing account
+	Account AccountName `json:"account"`
+
+	// Nonce is the co-signer's expected nonce, checked and incremented like
+	// the transaction's Nonce
+	Nonce uint64 `json:"nonce"`
+
+	// Authorization proves Account authorized the transaction: it signs the
+	// co-signer's SignDoc (see ToSignerSignDoc)
+	Authorization *Authorization `json:"authorization"`
+}
+
 // Transaction represents a signed transaction
 type Transaction struct {
 	// Account is the account executing this transaction
@@ -117,6 +134,32 @@ type Transaction struct {
 	// FeeSlippage is the maximum conversion rate slippage tolerance for fee payment.
 	// Expressed as a ratio (e.g., {Numerator: 1, Denominator: 100} = 1% slippage).
 	FeeSlippage Ratio `json:"fee_slippage"`
+
+	// Tip is an optional priority tip paid on top of Fee, which mempools may
+	// rank transactions by (see mempool.TipPriority). Signed in a version 2
+	// SignDoc (SignDocVersionTip); nil means no tip, and a set tip must be
+	// positive.
+	Tip *Coin `json:"tip,omitempty"`
+
+	// FeePayer is the account paying the fee, if not Account (see FeeSource)
+	FeePayer AccountName `json:"fee_payer,omitempty"`
+
+	// FeePayerAuthorization proves FeePayer authorized this transaction: it
+	// signs the same SignDoc as Authorization. Set exactly when FeePayer is.
+	FeePayerAuthorization *Authorization `json:"fee_payer_authorization,omitempty"`
+
+	// FeeGranter is the account whose fee allowance pays the fee, if any.
+	// It does not sign; the chain checks the allowance instead.
+	FeeGranter AccountName `json:"fee_granter,omitempty"`
+
+	// CoSigners are the other accounts signing the transaction, for messages
+	// whose signers do not include Account. Each signs its own SignDoc and
+	// has its nonce checked and incremented. Account remains the primary
+	// signer and pays the fee.
+	CoSigners []CoSigner `json:"co_signers,omitempty"`
+
+	// signDocMessages caches the SignDoc form of Messages; see ToSignDoc
+	signDocMessages signDocMessageCache
 }
 
 // NewTransaction creates a new transaction
@@ -157,29 +200,39 @@ func (tx *Transaction) ValidateBasic() error {
 		return fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
 	}
 
+	if err := tx.validateCoSigners(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+
 	// Validate all messages
+	usedSigners := make(map[AccountName]struct{}, len(tx.CoSigners))
 	for i, msg := range tx.Messages {
 		if err := msg.ValidateBasic(); err != nil {
 			return fmt.Errorf("%w: message %d: %v", ErrInvalidTransaction, i, err)
 		}
 
-		// Verify that the transaction account is authorized to send all messages
-		signers := msg.GetSigners()
-		validSigner := false
-		for _, signer := range signers {
-			if signer == tx.Account {
-				validSigner = true
-				break
+		// Verify that a transaction signer is authorized to send each message
+		signer := tx.MsgSigner(msg)
+		if signer == "" {
+			if len(tx.CoSigners) == 0 {
+				return fmt.Errorf("%w: transaction account %s not in message signers", ErrInvalidTransaction, tx.Account)
 			}
+			return fmt.Errorf("%w: message %d: no transaction signer in message signers", ErrInvalidTransaction, i)
 		}
-		if !validSigner {
-			return fmt.Errorf("%w: transaction account %s not in message signers", ErrInvalidTransaction, tx.Account)
+		usedSigners[signer] = struct{}{}
+	}
+
+	// One encoding per meaning: a co-signer that signs no message would only
+	// have its nonce consumed
+	for _, cs := range tx.CoSigners {
+		if _, ok := usedSigners[cs.Account]; !ok {
+			return fmt.Errorf("%w: co-signer %s signs no message", ErrInvalidTransaction, cs.Account)
 		}
 	}
 
 	// Memo size limit
-	if len(tx.Memo) > 512 {
-		return fmt.Errorf("%w: memo exceeds 512 bytes", ErrInvalidTransaction)
+	if len(tx.Memo) > MaxMemoBytes {
+		return fmt.Errorf("%w: memo exceeds %d bytes", ErrInvalidTransaction, MaxMemoBytes)
 	}
 
 	// Validate Fee
@@ -196,10 +249,231 @@ func (tx *Transaction) ValidateBasic() error {
 		return fmt.Errorf("%w: invalid fee_slippage: %v", ErrInvalidTransaction, err)
 	}
 
+	// SECURITY: A zero tip is expressed by omitting it, so each transaction
+	// has a single SignDoc version
+	if tx.Tip != nil && (!tx.Tip.IsValid() || !tx.Tip.IsPositive()) {
+		return fmt.Errorf("%w: invalid tip %s: must be a positive amount of a valid denom", ErrInvalidTransaction, tx.Tip)
+	}
+
+	if err := validateFeeSponsors(tx.Account, tx.FeePayer, tx.FeeGranter); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+	if len(tx.CoSigners) > 0 {
+		if err := validateSignerSet(tx.Signers(), tx.FeePayer, tx.FeeGranter); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+		}
+	}
+	if (tx.FeePayer == "") != (tx.FeePayerAuthorization == nil) {
+		return fmt.Errorf("%w: fee_payer_authorization must be set exactly when fee_payer is", ErrInvalidTransaction)
+	}
+	if tx.FeePayerAuthorization != nil {
+		if err := tx.FeePayerAuthorization.ValidateBasic(); err != nil {
+			return fmt.Errorf("%w: fee_payer_authorization: %v", ErrInvalidTransaction, err)
+		}
+		// SECURITY: The validity window is part of the SignDoc, which only
+		// Authorization sets; the payer's copy must not diverge from it
+		if tx.FeePayerAuthorization.NotBefore != nil || tx.FeePayerAuthorization.NotAfter != nil {
+			return fmt.Errorf("%w: fee_payer_authorization cannot set a validity window", ErrInvalidTransaction)
+		}
+	}
+
+	return nil
+}
+
+// validateCoSigners checks the number of co-signers and their
+// authorizations; the signer set is checked by validateSignerSet.
+func (tx *Transaction) validateCoSigners() error {
+	if len(tx.CoSigners) >= MaxSigners {
+		return fmt.Errorf("too many co-signers (%d > %d)", len(tx.CoSigners), MaxSigners-1)
+	}
+	for i, cs := range tx.CoSigners {
+		if cs.Authorization == nil {
+			return fmt.Errorf("co-signer %d: authorization cannot be nil", i)
+		}
+		if err := cs.Authorization.ValidateBasic(); err != nil {
+			return fmt.Errorf("co-signer %d: %v", i, err)
+		}
+		// SECURITY: The validity window is part of the SignDoc, which only
+		// Authorization sets, and session grants are bound to the account's
+		// own SignDoc; co-signers sign with their authority directly
+		if cs.Authorization.NotBefore != nil || cs.Authorization.NotAfter != nil {
+			return fmt.Errorf("co-signer %d: authorization cannot set a validity window", i)
+		}
+		if cs.Authorization.Session != nil {
+			return fmt.Errorf("co-signer %d: session authorizations are not supported", i)
+		}
+	}
+	return nil
+}
+
+// Signers returns the transaction's signers: Account, then the co-signers'
+// accounts in order.
+func (tx *Transaction) Signers() []AccountName {
+	signers := make([]AccountName, 0, 1+len(tx.CoSigners))
+	signers = append(signers, tx.Account)
+	for _, cs := range tx.CoSigners {
+		signers = append(signers, cs.Account)
+	}
+	return signers
+}
+
+// MsgSigner returns the transaction signer msg executes as: Account if it is
+// one of msg's signers, otherwise the first co-signer that is, in the order
+// of msg.GetSigners(). It returns "" if no transaction signer signs msg.
+func (tx *Transaction) MsgSigner(msg Message) AccountName {
+	signers := msg.GetSigners()
+	for _, signer := range signers {
+		if signer == tx.Account {
+			return signer
+		}
+	}
+	for _, signer := range signers {
+		if tx.coSigner(signer) != nil {
+			return signer
+		}
+	}
+	return ""
+}
+
+// coSigner returns the co-signer entry for account, or nil.
+func (tx *Transaction) coSigner(account AccountName) *CoSigner {
+	for i := range tx.CoSigners {
+		if tx.CoSigners[i].Account == account {
+			return &tx.CoSigners[i]
+		}
+	}
+	return nil
+}
+
+// FeeSource returns the account the fee is charged to: FeeGranter if set,
+// otherwise FeePayer if set, otherwise Account.
+func (tx *Transaction) FeeSource() AccountName {
+	switch {
+	case tx.FeeGranter != "":
+		return tx.FeeGranter
+	case tx.FeePayer != "":
+		return tx.FeePayer
+	default:
+		return tx.Account
+	}
+}
+
+// FeeGrantee returns the account a fee allowance must be granted to: the
+// fee payer if set, otherwise Account.
+func (tx *Transaction) FeeGrantee() AccountName {
+	if tx.FeePayer != "" {
+		return tx.FeePayer
+	}
+	return tx.Account
+}
+
+// VerifyFeePayerAuthorization verifies that FeePayerAuthorization signs the
+// transaction's SignDoc and meets payer's authority threshold. It returns
+// nil for transactions without a fee payer.
+//
+// PRECONDITION: account is the transaction's account (its nonce binds the
+// SignDoc), payer is the account named by FeePayer.
+//
+// SECURITY: Without this check anyone could name a wealthy account as fee
+// payer. The payer signs the same SignDoc as the account, so its signature
+// covers the messages, the fee and the account's nonce and cannot be
+// replayed on another transaction.
+func (tx *Transaction) VerifyFeePayerAuthorization(chainID string, account, payer *Account, getter AccountGetter) error {
+	if tx.FeePayer == "" {
+		return nil
+	}
+	if payer == nil || payer.Name != tx.FeePayer {
+		return fmt.Errorf("%w: fee payer account %s required", ErrInvalidTransaction, tx.FeePayer)
+	}
+
+	signBytes, err := tx.verifiedSignBytes(chainID, account)
+	if err != nil {
+		return err
+	}
+	if err := tx.FeePayerAuthorization.VerifyAuthorization(payer, signBytes, getter); err != nil {
+		return fmt.Errorf("fee payer %s: %w", tx.FeePayer, err)
+	}
+	return nil
+}
+
+// VerifyCoSignerAuthorization verifies that the co-signer entry for
+// coSigner signs its SignDoc (see ToSignerSignDoc) at coSigner's nonce and
+// meets its authority threshold.
+//
+// PRECONDITION: tx passed ValidateBasic.
+//
+// SECURITY: The co-signer's SignDoc carries its own account and sequence, so
+// its signature cannot be replayed once its nonce is incremented, and the
+// full signer list, so it cannot be moved to a transaction in which another
+// account is primary (and pays the fee).
+func (tx *Transaction) VerifyCoSignerAuthorization(chainID string, coSigner *Account, getter AccountGetter) error {
+	if coSigner == nil {
+		return fmt.Errorf("%w: co-signer account is nil", ErrInvalidTransaction)
+	}
+	cs := tx.coSigner(coSigner.Name)
+	if cs == nil {
+		return fmt.Errorf("%w: %s is not a co-signer", ErrInvalidTransaction, coSigner.Name)
+	}
+
+	signBytes, err := tx.verifiedSignerSignBytes(chainID, cs.Account, cs.Nonce, coSigner)
+	if err != nil {
+		return fmt.Errorf("co-signer %s: %w", cs.Account, err)
+	}
+	if err := cs.Authorization.VerifyAuthorization(coSigner, signBytes, getter); err != nil {
+		return fmt.Errorf("co-signer %s: %w", cs.Account, err)
+	}
+	return nil
+}
+
+// validateSignerSet checks that signers are distinct valid accounts and that
+// neither fee sponsor is one of them.
+func validateSignerSet(signers []AccountName, payer, granter AccountName) error {
+	seen := make(map[AccountName]struct{}, len(signers))
+	for _, signer := range signers {
+		if !signer.IsValid() {
+			return fmt.Errorf("%w: signer %s", ErrInvalidAccount, signer)
+		}
+		if _, dup := seen[signer]; dup {
+			return fmt.Errorf("duplicate signer %s", signer)
+		}
+		seen[signer] = struct{}{}
+		if signer == payer || signer == granter {
+			return fmt.Errorf("signer %s cannot also be a fee sponsor", signer)
+		}
+	}
+	return nil
+}
+
+// validateFeeSponsors checks the optional fee payer and granter of a
+// transaction by account.
+func validateFeeSponsors(account, payer, granter AccountName) error {
+	if payer != "" {
+		if !payer.IsValid() {
+			return fmt.Errorf("%w: fee_payer %s", ErrInvalidAccount, payer)
+		}
+		// One encoding per meaning: the account paying its own fee leaves
+		// fee_payer empty
+		if payer == account {
+			return fmt.Errorf("fee_payer must differ from account (leave it empty)")
+		}
+	}
+	if granter != "" {
+		if !granter.IsValid() {
+			return fmt.Errorf("%w: fee_granter %s", ErrInvalidAccount, granter)
+		}
+		if granter == account || granter == payer {
+			return fmt.Errorf("fee_granter must differ from account and fee_payer")
+		}
+	}
 	return nil
 }
 
-// Hash computes the transaction hash
+// Hash computes a legacy transaction identifier from the account and message types.
+//
+// Hash does not commit to message content, fee, memo or authorization, so
+// distinct transactions can share a Hash.
+//
+// Deprecated: Use TxHash, the hash transactions are identified by.
 func (tx *Transaction) Hash() []byte {
 	// TODO: Use proper serialization (Cramberry) for production
 	// For now, use a simple hash of concatenated fields
@@ -227,65 +501,128 @@ func (tx *Transaction) Hash() []byte {
 //
 // Performance: Optimized to perform single SignDoc construction and reuse serialized JSON
 // for both roundtrip validation and hash computation. See issue #36.
+//
+// Session-key authorizations are rejected; use VerifyAuthorizationAtHeight.
 func (tx *Transaction) VerifyAuthorization(chainID string, account *Account, getter AccountGetter) error {
+	signBytes, err := tx.verifiedSignBytes(chainID, account)
+	if err != nil {
+		return err
+	}
+
+	// 5. Verify all signatures against the hash
+	// First verify the signatures are valid, then check authorization weight
+	return tx.Authorization.VerifyAuthorization(account, signBytes, getter)
+}
+
+// VerifyAuthorizationAtHeight is VerifyAuthorization for a transaction
+// executing at block height, additionally accepting session-key
+// authorizations (Authorization.Session).
+//
+// For a session authorization it verifies that the grant is bound to chainID
+// and account, has not expired (height < ExpirationHeight), allows every
+// message type in the transaction, that the session key signed the SignDoc,
+// and that the grant itself meets the account's authority threshold.
+//
+// PRECONDITION: Same as VerifyAuthorization.
+// POSTCONDITION: If nil error returned, the transaction is authorized at height.
+func (tx *Transaction) VerifyAuthorizationAtHeight(chainID string, account *Account, getter AccountGetter, height uint64) error {
+	if tx.Authorization == nil || tx.Authorization.Session == nil {
+		return tx.VerifyAuthorization(chainID, account, getter)
+	}
+
+	signBytes, err := tx.verifiedSignBytes(chainID, account)
+	if err != nil {
+		return err
+	}
+
+	msgTypes := make([]string, len(tx.Messages))
+	for i, msg := range tx.Messages {
+		msgTypes[i] = msg.Type()
+	}
+	return tx.Authorization.Session.verify(chainID, account, msgTypes, signBytes, height, getter)
+}
+
+// VerifiedSignBytes checks the nonce, reconstructs the SignDoc, validates its
+// roundtrip and returns the hash the transaction's signatures must cover.
+// Authenticators that replace the weighted authority check (see
+// Account.Authenticator) verify signatures against it.
+//
+// SECURITY: The nonce check is part of it, so an authenticator verifying
+// signatures against these bytes keeps replay protection.
+func (tx *Transaction) VerifiedSignBytes(chainID string, account *Account) ([]byte, error) {
+	return tx.verifiedSignBytes(chainID, account)
+}
+
+// verifiedSignBytes checks the nonce, reconstructs the SignDoc, validates its
+// roundtrip and returns the hash the transaction's signatures must cover.
+func (tx *Transaction) verifiedSignBytes(chainID string, account *Account) ([]byte, error) {
+	return tx.verifiedSignerSignBytes(chainID, tx.Account, tx.Nonce, account)
+}
+
+// verifiedSignerSignBytes is verifiedSignBytes for the SignDoc of signer,
+// whose nonce in the transaction is nonce.
+func (tx *Transaction) verifiedSignerSignBytes(chainID string, signer AccountName, nonce uint64, account *Account) ([]byte, error) {
 	if account == nil {
-		return fmt.Errorf("%w: account is nil", ErrInvalidTransaction)
+		return nil, fmt.Errorf("%w: account is nil", ErrInvalidTransaction)
 	}
 
-	if chainID == "" {
-		return fmt.Errorf("%w: chainID cannot be empty", ErrInvalidTransaction)
+	if err := ValidateChainID(chainID); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTransaction, err)
 	}
 
 	// Check nonce
 	// SECURITY: Nonce verification prevents replay attacks
-	if tx.Nonce != account.Nonce {
-		return fmt.Errorf("%w: expected nonce %d, got %d", ErrInvalidTransaction, account.Nonce, tx.Nonce)
+	if nonce != account.Nonce {
+		return nil, fmt.Errorf("%w: expected nonce %d, got %d", ErrSequenceMismatch, account.Nonce, nonce)
 	}
 
 	// 1. Reconstruct SignDoc from transaction fields (single construction)
-	signDoc, err := tx.ToSignDoc(chainID, account.Nonce)
+	// SECURITY: The messages are converted afresh rather than read from the
+	// SignDoc cache, so a message mutated in place after signing is verified
+	// by its current content, never by stale cached bytes
+	messages, err := convertMessages(tx.Messages)
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+		return nil, fmt.Errorf("%w: failed to convert messages: %v", ErrInvalidTransaction, err)
 	}
+	signDoc := tx.buildSignDoc(chainID, signer, nonce, account.Nonce, messages)
 
 	// 2. Serialize to JSON (json1)
 	json1, err := signDoc.ToJSON()
 	if err != nil {
-		return fmt.Errorf("%w: initial serialization failed: %v", ErrSignDocMismatch, err)
+		return nil, fmt.Errorf("%w: initial serialization failed: %v", ErrSignDocMismatch, err)
 	}
 
 	// 3. Validate roundtrip: parse and re-serialize to verify determinism
 	// SECURITY: This catches non-deterministic serialization bugs and tampering
 	parsed, err := ParseSignDoc(json1)
 	if err != nil {
-		return fmt.Errorf("%w: parsing failed: %v", ErrSignDocMismatch, err)
+		return nil, fmt.Errorf("%w: parsing failed: %v", ErrSignDocMismatch, err)
 	}
 
 	json2, err := parsed.ToJSON()
 	if err != nil {
-		return fmt.Errorf("%w: re-serialization failed: %v", ErrSignDocMismatch, err)
+		return nil, fmt.Errorf("%w: re-serialization failed: %v", ErrSignDocMismatch, err)
 	}
 
 	if !bytes.Equal(json1, json2) {
-		return fmt.Errorf("%w: roundtrip produced different bytes (len %d vs %d)",
+		return nil, fmt.Errorf("%w: roundtrip produced different bytes (len %d vs %d)",
 			ErrSignDocMismatch, len(json1), len(json2))
 	}
 
 	// 4. Compute hash from json1 (reuse, no additional ToJSON call)
 	// Complexity: O(n) where n = len(json1)
 	hash := sha256.Sum256(json1)
-	signBytes := hash[:]
-
-	// 5. Verify all signatures against the hash
-	// First verify the signatures are valid, then check authorization weight
-	return tx.Authorization.VerifyAuthorization(account, signBytes, getter)
+	return hash[:], nil
 }
 
 // ToSignDoc converts the transaction to a SignDoc for signing.
 //
 // PRECONDITION: tx has at least one message
 // POSTCONDITION: returned SignDoc contains all signable transaction data
-// POSTCONDITION: Authorization field is NOT included (it contains the signatures being produced)
+// POSTCONDITION: Authorization field is NOT included (it contains the signatures being produced),
+// except for its validity window (NotBefore, NotAfter)
+// POSTCONDITION: Version is SignDocVersionTip if tx.Tip is set, SignDocVersion otherwise
+// POSTCONDITION: Signers lists tx.Signers() if tx has co-signers, and is nil otherwise
 //
 // INVARIANT: Two calls to ToSignDoc with same parameters return equal SignDocs.
 // PROOF SKETCH: All field conversions are pure functions of their inputs with no external
@@ -298,25 +635,157 @@ func (tx *Transaction) VerifyAuthorization(chainID string, account *Account, get
 // See SignDocSerializable interface for rationale and security implications.
 //
 // Returns an error if message serialization fails.
+//
+// CACHING: The SignDoc form of each message is cached on the transaction, so
+// signing and then verifying the same Transaction serializes its messages
+// once. The cache is keyed by message identity and is invalidated when
+// Messages is reassigned, resized or an element is replaced. Messages that
+// are not pointers are never cached. A message mutated in place through its
+// pointer is not detected: call InvalidateSignDocCache afterwards.
+//
+// SECURITY: Signature verification (VerifyAuthorization, VerifiedSignBytes
+// and the co-signer and fee payer checks) never reads the cache, so it always
+// covers the messages' current content.
 func (tx *Transaction) ToSignDoc(chainID string, accountSequence uint64) (*SignDoc, error) {
-	messages, err := convertMessages(tx.Messages)
+	return tx.signerSignDoc(chainID, tx.Account, tx.Nonce, accountSequence)
+}
+
+// ToSignerSignDoc returns the SignDoc signer signs: ToSignDoc for Account,
+// and for a co-signer the same document with the co-signer as Account and
+// its nonce as Nonce. Returns an error if signer does not sign tx.
+//
+// POSTCONDITION: Every signer's SignDoc has the same Signers, so each
+// signature binds the full signer list.
+func (tx *Transaction) ToSignerSignDoc(chainID string, signer AccountName, accountSequence uint64) (*SignDoc, error) {
+	if signer == tx.Account {
+		return tx.ToSignDoc(chainID, accountSequence)
+	}
+	cs := tx.coSigner(signer)
+	if cs == nil {
+		return nil, fmt.Errorf("%s is not a signer of the transaction", signer)
+	}
+	return tx.signerSignDoc(chainID, cs.Account, cs.Nonce, accountSequence)
+}
+
+// signerSignDoc builds the SignDoc of signer at nonce; see ToSignDoc.
+func (tx *Transaction) signerSignDoc(chainID string, signer AccountName, nonce, accountSequence uint64) (*SignDoc, error) {
+	messages, err := tx.signDocMessages.get(tx.Messages)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert messages: %w", err)
 	}
+	return tx.buildSignDoc(chainID, signer, nonce, accountSequence, messages), nil
+}
 
+// buildSignDoc builds the SignDoc of signer at nonce with the given SignDoc
+// form of the messages
+func (tx *Transaction) buildSignDoc(chainID string, signer AccountName, nonce, accountSequence uint64, messages []SignDocMessage) *SignDoc {
 	signDoc := &SignDoc{
 		Version:         SignDocVersion,
 		ChainID:         chainID,
-		Account:         string(tx.Account),
+		Account:         string(signer),
 		AccountSequence: StringUint64(accountSequence),
 		Messages:        messages,
-		Nonce:           StringUint64(tx.Nonce),
+		Nonce:           StringUint64(nonce),
 		Memo:            tx.Memo,
 		Fee:             convertFee(tx.Fee),
 		FeeSlippage:     convertRatio(tx.FeeSlippage),
+		FeePayer:        string(tx.FeePayer),
+		FeeGranter:      string(tx.FeeGranter),
+	}
+	if tx.Tip != nil {
+		signDoc.Version = SignDocVersionTip
+		signDoc.Tip = &SignDocCoin{Denom: tx.Tip.Denom, Amount: strconv.FormatUint(tx.Tip.Amount, 10)}
+	}
+	if len(tx.CoSigners) > 0 {
+		signDoc.Signers = make([]string, 0, 1+len(tx.CoSigners))
+		for _, s := range tx.Signers() {
+			signDoc.Signers = append(signDoc.Signers, string(s))
+		}
+	}
+	if tx.Authorization != nil {
+		signDoc.NotBefore = tx.Authorization.NotBefore.clone()
+		signDoc.NotAfter = tx.Authorization.NotAfter.clone()
 	}
 
-	return signDoc, nil
+	return signDoc
+}
+
+// InvalidateSignDocCache discards the cached SignDoc form of the messages.
+// Call it after mutating a message in place; see ToSignDoc.
+func (tx *Transaction) InvalidateSignDocCache() {
+	if tx == nil {
+		return
+	}
+	tx.signDocMessages.invalidate()
+}
+
+// signDocMessageCache caches convertMessages output for a transaction.
+// The zero value is an empty cache. Safe for concurrent use.
+type signDocMessageCache struct {
+	mu sync.Mutex
+
+	// msgs is a snapshot of the messages data was computed from
+	msgs []Message
+	data []SignDocMessage
+}
+
+// get returns the SignDoc form of msgs, converting them on a cache miss.
+//
+// POSTCONDITION: The returned slice is owned by the caller; the Data byte
+// slices are shared with the cache and must not be modified in place.
+func (c *signDocMessageCache) get(msgs []Message) ([]SignDocMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.data == nil || !sameMessages(c.msgs, msgs) {
+		data, err := convertMessages(msgs)
+		if err != nil {
+			c.msgs, c.data = nil, nil
+			return nil, err
+		}
+		if !cacheableMessages(msgs) {
+			c.msgs, c.data = nil, nil
+			return data, nil
+		}
+		c.msgs = append([]Message(nil), msgs...)
+		c.data = data
+	}
+
+	return append(make([]SignDocMessage, 0, len(c.data)), c.data...), nil
+}
+
+// invalidate empties the cache
+func (c *signDocMessageCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.msgs, c.data = nil, nil
+}
+
+// cacheableMessages reports whether every message is a non-nil pointer, so
+// that identity comparison in sameMessages is meaningful
+func cacheableMessages(msgs []Message) bool {
+	for _, msg := range msgs {
+		if msg == nil || reflect.TypeOf(msg).Kind() != reflect.Pointer {
+			return false
+		}
+	}
+	return true
+}
+
+// sameMessages reports whether a and b hold the same message pointers in the
+// same order.
+// PRECONDITION: cacheableMessages(a), so the comparison cannot panic on
+// uncomparable dynamic types
+func sameMessages(a, b []Message) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
 
 // convertMessages converts a slice of Message to SignDocMessage format.
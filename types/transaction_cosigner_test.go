@@ -0,0 +1,227 @@
+package types
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCoSignedTx returns a codec transaction from alice (nonce 7) with a
+// message from bob, co-signed by bob at nonce 3, on punnet-1.
+func newCoSignedTx(t *testing.T, alice, bob ed25519.PrivateKey) *Transaction {
+	t.Helper()
+	tx := NewTransaction("alice", 7, []Message{
+		&codecMessage{From: "alice", To: "bob", Amount: 10},
+		&codecMessage{From: "bob", To: "alice", Amount: 4},
+	}, nil)
+	tx.Fee = Fee{Amount: Coins{{Denom: "stake", Amount: 500}}, GasLimit: 200000}
+	tx.FeeSlippage = Ratio{Numerator: 0, Denominator: 1}
+	tx.CoSigners = []CoSigner{{Account: "bob", Nonce: 3}}
+
+	sign := func(priv ed25519.PrivateKey, signer AccountName, sequence uint64) *Authorization {
+		signDoc, err := tx.ToSignerSignDoc("punnet-1", signer, sequence)
+		require.NoError(t, err)
+		signBytes, err := signDoc.GetSignBytes()
+		require.NoError(t, err)
+		return NewAuthorization(Signature{
+			Algorithm: AlgorithmEd25519,
+			PubKey:    priv.Public().(ed25519.PublicKey),
+			Signature: ed25519.Sign(priv, signBytes),
+		})
+	}
+	tx.Authorization = sign(alice, "alice", 7)
+	tx.CoSigners[0].Authorization = sign(bob, "bob", 3)
+	return tx
+}
+
+func TestTransaction_ToSignerSignDoc(t *testing.T) {
+	alice, bob := sponsorKeys()
+	tx := newCoSignedTx(t, alice, bob)
+	assert.Equal(t, []AccountName{"alice", "bob"}, tx.Signers())
+
+	primary, err := tx.ToSignerSignDoc("punnet-1", "alice", 7)
+	require.NoError(t, err)
+	coSigner, err := tx.ToSignerSignDoc("punnet-1", "bob", 3)
+	require.NoError(t, err)
+	require.NoError(t, primary.ValidateBasic())
+	require.NoError(t, coSigner.ValidateBasic())
+
+	assert.Equal(t, "bob", coSigner.Account)
+	assert.Equal(t, StringUint64(3), coSigner.AccountSequence)
+	assert.Equal(t, StringUint64(3), coSigner.Nonce)
+	assert.Equal(t, []string{"alice", "bob"}, primary.Signers)
+	assert.Equal(t, primary.Signers, coSigner.Signers)
+
+	data, err := coSigner.ToJSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `,"signers":["alice","bob"]}`)
+	parsed, err := ParseSignDoc(data)
+	require.NoError(t, err)
+	assert.True(t, parsed.Equals(coSigner))
+
+	_, err = tx.ToSignerSignDoc("punnet-1", "carol", 0)
+	require.Error(t, err)
+
+	// Single-signer SignDocs keep their serialization
+	plain, err := newCodecTx(t).ToSignDoc("punnet-1", 7)
+	require.NoError(t, err)
+	assert.Nil(t, plain.Signers)
+}
+
+func TestSignDoc_ValidateBasic_Signers(t *testing.T) {
+	tests := []struct {
+		name    string
+		signers []string
+		payer   string
+		wantErr bool
+	}{
+		{name: "none"},
+		{name: "two signers", signers: []string{"alice", "bob"}},
+		{name: "account not first", signers: []string{"bob", "alice"}},
+		{name: "empty list", signers: []string{}, wantErr: true},
+		{name: "single signer", signers: []string{"alice"}, wantErr: true},
+		{name: "account missing", signers: []string{"bob", "carol"}, wantErr: true},
+		{name: "duplicate signer", signers: []string{"alice", "bob", "bob"}, wantErr: true},
+		{name: "invalid signer", signers: []string{"alice", "Bob"}, wantErr: true},
+		{name: "fee payer signs", signers: []string{"alice", "bob"}, payer: "bob", wantErr: true},
+		{name: "too many signers", signers: []string{"alice", "b", "c", "d", "e", "f", "g", "h", "i"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sd, err := NewSignDoc("punnet-1", 1, "alice", 1, "")
+			require.NoError(t, err)
+			sd.AddMessage(codecMsgType, []byte(`{"amount":"1"}`))
+			sd.Signers = tt.signers
+			sd.FeePayer = tt.payer
+			err = sd.ValidateBasic()
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrSignDocMismatch)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTransaction_ValidateBasic_CoSigners(t *testing.T) {
+	alice, bob := sponsorKeys()
+
+	tests := []struct {
+		name    string
+		modify  func(tx *Transaction)
+		wantErr bool
+	}{
+		{name: "co-signed", modify: func(tx *Transaction) {}},
+		{name: "message without a transaction signer", modify: func(tx *Transaction) {
+			tx.Messages = append(tx.Messages, &codecMessage{From: "carol"})
+		}, wantErr: true},
+		{name: "co-signer signs no message", modify: func(tx *Transaction) {
+			tx.Messages = tx.Messages[:1]
+		}, wantErr: true},
+		{name: "co-signer is account", modify: func(tx *Transaction) { tx.CoSigners[0].Account = "alice" }, wantErr: true},
+		{name: "duplicate co-signer", modify: func(tx *Transaction) {
+			tx.CoSigners = append(tx.CoSigners, tx.CoSigners[0])
+		}, wantErr: true},
+		{name: "co-signer pays the fee", modify: func(tx *Transaction) {
+			tx.FeePayer = "bob"
+			tx.FeePayerAuthorization = tx.CoSigners[0].Authorization
+		}, wantErr: true},
+		{name: "co-signer without authorization", modify: func(tx *Transaction) { tx.CoSigners[0].Authorization = nil }, wantErr: true},
+		{name: "co-signer authorization with validity window", modify: func(tx *Transaction) {
+			tx.CoSigners[0].Authorization.NotAfter = &ValidityBound{Height: 10}
+		}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := newCoSignedTx(t, alice, bob)
+			tt.modify(tx)
+			err := tx.ValidateBasic()
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidTransaction)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTransaction_MsgSigner(t *testing.T) {
+	alice, bob := sponsorKeys()
+	tx := newCoSignedTx(t, alice, bob)
+
+	assert.Equal(t, AccountName("alice"), tx.MsgSigner(tx.Messages[0]))
+	assert.Equal(t, AccountName("bob"), tx.MsgSigner(tx.Messages[1]))
+	assert.Equal(t, AccountName(""), tx.MsgSigner(&codecMessage{From: "carol"}))
+}
+
+func TestTransaction_VerifyCoSignerAuthorization(t *testing.T) {
+	alice, bob := sponsorKeys()
+	aliceAccount := NewAccount("alice", alice.Public().(ed25519.PublicKey))
+	aliceAccount.Nonce = 7
+	bobAccount := NewAccount("bob", bob.Public().(ed25519.PublicKey))
+	bobAccount.Nonce = 3
+	getter := newMockAccountGetter()
+
+	t.Run("valid", func(t *testing.T) {
+		tx := newCoSignedTx(t, alice, bob)
+		require.NoError(t, tx.VerifyAuthorization("punnet-1", aliceAccount, getter))
+		require.NoError(t, tx.VerifyCoSignerAuthorization("punnet-1", bobAccount, getter))
+	})
+
+	t.Run("not a co-signer", func(t *testing.T) {
+		tx := newCoSignedTx(t, alice, bob)
+		err := tx.VerifyCoSignerAuthorization("punnet-1", aliceAccount, getter)
+		require.ErrorIs(t, err, ErrInvalidTransaction)
+	})
+
+	t.Run("co-signer signed the primary SignDoc", func(t *testing.T) {
+		tx := newCoSignedTx(t, alice, bob)
+		tx.CoSigners[0].Authorization = tx.Authorization
+		err := tx.VerifyCoSignerAuthorization("punnet-1", bobAccount, getter)
+		require.Error(t, err)
+	})
+
+	t.Run("stale co-signer nonce", func(t *testing.T) {
+		tx := newCoSignedTx(t, alice, bob)
+		stale := *bobAccount
+		stale.Nonce = 4
+		err := tx.VerifyCoSignerAuthorization("punnet-1", &stale, getter)
+		require.ErrorIs(t, err, ErrSequenceMismatch)
+	})
+
+	t.Run("primary signature covers the signer list", func(t *testing.T) {
+		tx := newCoSignedTx(t, alice, bob)
+		tx.CoSigners = append(tx.CoSigners, CoSigner{Account: "carol"})
+		err := tx.VerifyAuthorization("punnet-1", aliceAccount, getter)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+	})
+}
+
+func TestTransactionEncode_CoSigners(t *testing.T) {
+	alice, bob := sponsorKeys()
+	tx := newCoSignedTx(t, alice, bob)
+
+	bz, err := tx.Encode()
+	require.NoError(t, err)
+	require.NoError(t, ValidateCanonicalKeyOrder(bz))
+	assert.Contains(t, string(bz), `,"co_signers":[{"account":"bob","authorization":`)
+
+	decoded, err := newCodecDecoder(t).Decode(bz)
+	require.NoError(t, err)
+	require.Len(t, decoded.CoSigners, 1)
+	assert.Equal(t, tx.CoSigners[0].Account, decoded.CoSigners[0].Account)
+	assert.Equal(t, tx.CoSigners[0].Nonce, decoded.CoSigners[0].Nonce)
+	assert.Equal(t, tx.CoSigners[0].Authorization.Signatures, decoded.CoSigners[0].Authorization.Signatures)
+
+	// An explicitly empty co-signer list is not canonical
+	plain, err := newCodecTx(t).Encode()
+	require.NoError(t, err)
+	malleated := bytes.Replace(plain, []byte(`,"fee":`), []byte(`,"co_signers":[],"fee":`), 1)
+	_, err = newCodecDecoder(t).Decode(malleated)
+	require.ErrorIs(t, err, ErrInvalidTransaction)
+}
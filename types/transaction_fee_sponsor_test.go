@@ -0,0 +1,227 @@
+package types
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSponsoredTx returns a codec transaction from alice (nonce 7) with bob
+// as fee payer, signed by both on punnet-1.
+func newSponsoredTx(t *testing.T, alice, bob ed25519.PrivateKey) *Transaction {
+	t.Helper()
+	tx := NewTransaction("alice", 7, []Message{
+		&codecMessage{From: "alice", To: "carol", Amount: 10},
+	}, nil)
+	tx.Fee = Fee{Amount: Coins{{Denom: "stake", Amount: 500}}, GasLimit: 200000}
+	tx.FeeSlippage = Ratio{Numerator: 0, Denominator: 1}
+	tx.FeePayer = "bob"
+
+	signDoc, err := tx.ToSignDoc("punnet-1", 7)
+	require.NoError(t, err)
+	signBytes, err := signDoc.GetSignBytes()
+	require.NoError(t, err)
+
+	tx.Authorization = NewAuthorization(Signature{
+		Algorithm: AlgorithmEd25519,
+		PubKey:    alice.Public().(ed25519.PublicKey),
+		Signature: ed25519.Sign(alice, signBytes),
+	})
+	tx.FeePayerAuthorization = NewAuthorization(Signature{
+		Algorithm: AlgorithmEd25519,
+		PubKey:    bob.Public().(ed25519.PublicKey),
+		Signature: ed25519.Sign(bob, signBytes),
+	})
+	return tx
+}
+
+func sponsorKeys() (alice, bob ed25519.PrivateKey) {
+	return ed25519.NewKeyFromSeed(bytes.Repeat([]byte{1}, ed25519.SeedSize)),
+		ed25519.NewKeyFromSeed(bytes.Repeat([]byte{2}, ed25519.SeedSize))
+}
+
+func TestSignDoc_FeeSponsorsSerialization(t *testing.T) {
+	sd, err := NewSignDoc("punnet-1", 1, "alice", 1, "")
+	require.NoError(t, err)
+	plain, err := sd.ToJSON()
+	require.NoError(t, err)
+	assert.NotContains(t, string(plain), "fee_payer")
+	assert.NotContains(t, string(plain), "fee_granter")
+
+	sd.FeePayer = "bob"
+	sd.FeeGranter = "carol"
+	sponsored, err := sd.ToJSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(sponsored), `,"fee_payer":"bob","fee_granter":"carol"`)
+
+	parsed, err := ParseSignDoc(sponsored)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", parsed.FeePayer)
+	assert.Equal(t, "carol", parsed.FeeGranter)
+
+	plainDoc, err := NewSignDoc("punnet-1", 1, "alice", 1, "")
+	require.NoError(t, err)
+	plainHash, err := plainDoc.GetSignBytes()
+	require.NoError(t, err)
+	sponsoredHash, err := sd.GetSignBytes()
+	require.NoError(t, err)
+	assert.NotEqual(t, plainHash, sponsoredHash, "fee sponsors must be signed over")
+}
+
+func TestSignDoc_ValidateBasic_FeeSponsors(t *testing.T) {
+	tests := []struct {
+		name    string
+		payer   string
+		granter string
+		wantErr bool
+	}{
+		{name: "none"},
+		{name: "payer", payer: "bob"},
+		{name: "granter", granter: "carol"},
+		{name: "payer and granter", payer: "bob", granter: "carol"},
+		{name: "payer is account", payer: "alice", wantErr: true},
+		{name: "invalid payer", payer: "Bob!", wantErr: true},
+		{name: "granter is account", granter: "alice", wantErr: true},
+		{name: "granter is payer", payer: "bob", granter: "bob", wantErr: true},
+		{name: "invalid granter", granter: strings.Repeat("c", 100), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sd, err := NewSignDoc("punnet-1", 1, "alice", 1, "")
+			require.NoError(t, err)
+			sd.AddMessage(codecMsgType, []byte(`{"amount":"1"}`))
+			sd.FeePayer = tt.payer
+			sd.FeeGranter = tt.granter
+			err = sd.ValidateBasic()
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrSignDocMismatch)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTransaction_ValidateBasic_FeeSponsors(t *testing.T) {
+	alice, bob := sponsorKeys()
+
+	tests := []struct {
+		name    string
+		modify  func(tx *Transaction)
+		wantErr bool
+	}{
+		{name: "payer with authorization", modify: func(tx *Transaction) {}},
+		{name: "payer and granter", modify: func(tx *Transaction) { tx.FeeGranter = "carol" }},
+		{name: "payer without authorization", modify: func(tx *Transaction) { tx.FeePayerAuthorization = nil }, wantErr: true},
+		{name: "authorization without payer", modify: func(tx *Transaction) { tx.FeePayer = "" }, wantErr: true},
+		{name: "payer is account", modify: func(tx *Transaction) { tx.FeePayer = "alice" }, wantErr: true},
+		{name: "granter is payer", modify: func(tx *Transaction) { tx.FeeGranter = "bob" }, wantErr: true},
+		{name: "granter is account", modify: func(tx *Transaction) { tx.FeeGranter = "alice" }, wantErr: true},
+		{name: "payer authorization with validity window", modify: func(tx *Transaction) {
+			tx.FeePayerAuthorization.NotAfter = &ValidityBound{Height: 10}
+		}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := newSponsoredTx(t, alice, bob)
+			tt.modify(tx)
+			err := tx.ValidateBasic()
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidTransaction)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTransaction_FeeSource(t *testing.T) {
+	tx := &Transaction{Account: "alice"}
+	assert.Equal(t, AccountName("alice"), tx.FeeSource())
+	assert.Equal(t, AccountName("alice"), tx.FeeGrantee())
+
+	tx.FeePayer = "bob"
+	assert.Equal(t, AccountName("bob"), tx.FeeSource())
+	assert.Equal(t, AccountName("bob"), tx.FeeGrantee())
+
+	tx.FeeGranter = "carol"
+	assert.Equal(t, AccountName("carol"), tx.FeeSource())
+	assert.Equal(t, AccountName("bob"), tx.FeeGrantee())
+}
+
+func TestTransaction_VerifyFeePayerAuthorization(t *testing.T) {
+	alice, bob := sponsorKeys()
+	aliceAccount := NewAccount("alice", alice.Public().(ed25519.PublicKey))
+	aliceAccount.Nonce = 7
+	bobAccount := NewAccount("bob", bob.Public().(ed25519.PublicKey))
+	getter := newMockAccountGetter()
+
+	t.Run("valid", func(t *testing.T) {
+		tx := newSponsoredTx(t, alice, bob)
+		require.NoError(t, tx.VerifyAuthorization("punnet-1", aliceAccount, getter))
+		require.NoError(t, tx.VerifyFeePayerAuthorization("punnet-1", aliceAccount, bobAccount, getter))
+	})
+
+	t.Run("no fee payer", func(t *testing.T) {
+		tx := newCodecTx(t)
+		require.NoError(t, tx.VerifyFeePayerAuthorization("punnet-1", aliceAccount, nil, getter))
+	})
+
+	t.Run("payer signed with another key", func(t *testing.T) {
+		tx := newSponsoredTx(t, alice, alice)
+		err := tx.VerifyFeePayerAuthorization("punnet-1", aliceAccount, bobAccount, getter)
+		require.Error(t, err)
+	})
+
+	t.Run("wrong payer account", func(t *testing.T) {
+		tx := newSponsoredTx(t, alice, bob)
+		err := tx.VerifyFeePayerAuthorization("punnet-1", aliceAccount, aliceAccount, getter)
+		require.ErrorIs(t, err, ErrInvalidTransaction)
+	})
+
+	t.Run("payer signature does not transfer to another transaction", func(t *testing.T) {
+		tx := newSponsoredTx(t, alice, bob)
+		tx.Fee.Amount = Coins{{Denom: "stake", Amount: 5000}}
+		tx.InvalidateSignDocCache()
+		err := tx.VerifyFeePayerAuthorization("punnet-1", aliceAccount, bobAccount, getter)
+		require.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("stale nonce", func(t *testing.T) {
+		tx := newSponsoredTx(t, alice, bob)
+		stale := *aliceAccount
+		stale.Nonce = 8
+		err := tx.VerifyFeePayerAuthorization("punnet-1", &stale, bobAccount, getter)
+		require.ErrorIs(t, err, ErrSequenceMismatch)
+	})
+}
+
+func TestTransactionEncode_FeeSponsors(t *testing.T) {
+	alice, bob := sponsorKeys()
+	tx := newSponsoredTx(t, alice, bob)
+	tx.FeeGranter = "carol"
+
+	bz, err := tx.Encode()
+	require.NoError(t, err)
+	require.NoError(t, ValidateCanonicalKeyOrder(bz))
+	assert.Contains(t, string(bz), `"fee_granter":"carol","fee_payer":"bob","fee_payer_authorization":`)
+
+	decoded, err := newCodecDecoder(t).Decode(bz)
+	require.NoError(t, err)
+	assert.Equal(t, tx.FeePayer, decoded.FeePayer)
+	assert.Equal(t, tx.FeeGranter, decoded.FeeGranter)
+	assert.Equal(t, tx.FeePayerAuthorization.Signatures, decoded.FeePayerAuthorization.Signatures)
+
+	// An explicitly empty sponsor is not canonical
+	plain, err := newCodecTx(t).Encode()
+	require.NoError(t, err)
+	malleated := bytes.Replace(plain, []byte(`,"fee_slippage"`), []byte(`,"fee_payer":"","fee_slippage"`), 1)
+	_, err = newCodecDecoder(t).Decode(malleated)
+	require.ErrorIs(t, err, ErrInvalidTransaction)
+}
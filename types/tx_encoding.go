@@ -0,0 +1,447 @@
+package types
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/blockberries/cramberry/pkg/cramberry"
+)
+
+// MaxTxSize is the maximum size of an encoded transaction in bytes.
+// SECURITY: Bounds decoding work for transactions from untrusted sources.
+const MaxTxSize = 1024 * 1024 // 1MB
+
+// Transaction wire encoding
+//
+// Encode produces the canonical JSON encoding of a transaction. It is the
+// single wire form of a transaction: every transaction has exactly one
+// encoding, and TxDecoder.Decode accepts nothing else. TxHash is the SHA-256
+// of these bytes, so any implementation that reproduces the encoding computes
+// the same hash.
+//
+// The encoding is a JSON object with keys in ascending byte order:
+//
+//	{"account":"alice",
+//	 "authorization":{...},
+//	 "fee":{"amount":[{"amount":"100","denom":"stake"}],"gas_limit":"200000"},
+//	 "fee_slippage":{"denominator":"100","numerator":"1"},
+//	 "memo":"",
+//	 "messages":[{"data":{...},"type":"/punnet.bank.v1.MsgSend"}],
+//	 "nonce":"7"}
+//
+// Rules:
+//   - Compact, with object keys sorted at every nesting level
+//   - Strings escaped as in SignDoc.ToJSON
+//   - uint64 amounts, gas limit and nonce as decimal strings (as in SignDoc)
+//   - Byte fields (public keys, signatures) as standard padded base64
+//   - Message data is the message's SignDocData in CanonicalizeMessageData form
+//   - Signature algorithms are always explicit ("ed25519", never omitted)
+//   - Signature lists are always arrays ([] rather than null)
+//   - Empty account_authorizations are omitted
+//   - Unset validity bounds (not_before, not_after) are omitted
+//   - Unset fee_granter, fee_payer and fee_payer_authorization are omitted
+//   - An unset tip is omitted; a set tip is {"amount":"<n>","denom":"<d>"}
+//   - Empty co_signers are omitted; each co-signer is
+//     {"account":"<a>","authorization":{...},"nonce":"<n>"}
+//   - memo is always present
+//
+// SECURITY: Signatures cover the SignDoc, not the wire bytes. Without a single
+// canonical encoding, anyone relaying a transaction could re-encode it (reorder
+// keys, add whitespace, drop a default algorithm) to change its hash without
+// invalidating its signatures. Decode rejects every such variant.
+
+// Encode returns the canonical encoding of the transaction.
+//
+// PRECONDITION: Every message implements SignDocSerializable, since the
+// encoding must carry the full message content.
+// POSTCONDITION: A TxDecoder that knows every message type decodes the result
+// to an equivalent transaction, and re-encoding it yields identical bytes.
+//
+// Complexity: O(total message data size + authorization size)
+func (tx *Transaction) Encode() ([]byte, error) {
+	if tx == nil {
+		return nil, fmt.Errorf("%w: transaction is nil", ErrInvalidTransaction)
+	}
+
+	for i, msg := range tx.Messages {
+		if _, ok := msg.(SignDocSerializable); !ok {
+			return nil, fmt.Errorf("%w: message %d (%T) does not implement SignDocSerializable",
+				ErrInvalidTransaction, i, msg)
+		}
+	}
+	msgs, err := tx.signDocMessages.get(tx.Messages)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"account":`)
+	buf.WriteString(cramberry.EscapeJSONString(string(tx.Account)))
+
+	buf.WriteString(`,"authorization":`)
+	if err := writeCanonicalJSON(&buf, normalizeAuthorization(tx.Authorization)); err != nil {
+		return nil, fmt.Errorf("%w: authorization: %v", ErrInvalidTransaction, err)
+	}
+
+	if len(tx.CoSigners) > 0 {
+		buf.WriteString(`,"co_signers":[`)
+		for i, cs := range tx.CoSigners {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(`{"account":`)
+			buf.WriteString(cramberry.EscapeJSONString(string(cs.Account)))
+			buf.WriteString(`,"authorization":`)
+			if err := writeCanonicalJSON(&buf, normalizeAuthorization(cs.Authorization)); err != nil {
+				return nil, fmt.Errorf("%w: co-signer %d authorization: %v", ErrInvalidTransaction, i, err)
+			}
+			buf.WriteString(`,"nonce":"`)
+			buf.WriteString(strconv.FormatUint(cs.Nonce, 10))
+			buf.WriteString(`"}`)
+		}
+		buf.WriteByte(']')
+	}
+
+	buf.WriteString(`,"fee":`)
+	if err := writeCanonicalJSON(&buf, convertFee(tx.Fee)); err != nil {
+		return nil, fmt.Errorf("%w: fee: %v", ErrInvalidTransaction, err)
+	}
+	if tx.FeeGranter != "" {
+		buf.WriteString(`,"fee_granter":`)
+		buf.WriteString(cramberry.EscapeJSONString(string(tx.FeeGranter)))
+	}
+	if tx.FeePayer != "" {
+		buf.WriteString(`,"fee_payer":`)
+		buf.WriteString(cramberry.EscapeJSONString(string(tx.FeePayer)))
+	}
+	if tx.FeePayerAuthorization != nil {
+		buf.WriteString(`,"fee_payer_authorization":`)
+		if err := writeCanonicalJSON(&buf, normalizeAuthorization(tx.FeePayerAuthorization)); err != nil {
+			return nil, fmt.Errorf("%w: fee_payer_authorization: %v", ErrInvalidTransaction, err)
+		}
+	}
+	buf.WriteString(`,"fee_slippage":`)
+	if err := writeCanonicalJSON(&buf, convertRatio(tx.FeeSlippage)); err != nil {
+		return nil, fmt.Errorf("%w: fee_slippage: %v", ErrInvalidTransaction, err)
+	}
+
+	buf.WriteString(`,"memo":`)
+	buf.WriteString(cramberry.EscapeJSONString(tx.Memo))
+
+	buf.WriteString(`,"messages":[`)
+	for i, msg := range msgs {
+		data, err := CanonicalizeMessageData(msg.Data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: message %d: %v", ErrInvalidTransaction, i, err)
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(`{"data":`)
+		if len(data) == 0 {
+			buf.WriteString("null")
+		} else {
+			buf.Write(data)
+		}
+		buf.WriteString(`,"type":`)
+		buf.WriteString(cramberry.EscapeJSONString(msg.Type))
+		buf.WriteByte('}')
+	}
+	buf.WriteString(`],"nonce":"`)
+	buf.WriteString(strconv.FormatUint(tx.Nonce, 10))
+	buf.WriteByte('"')
+
+	if tx.Tip != nil {
+		buf.WriteString(`,"tip":{"amount":"`)
+		buf.WriteString(strconv.FormatUint(tx.Tip.Amount, 10))
+		buf.WriteString(`","denom":`)
+		buf.WriteString(cramberry.EscapeJSONString(tx.Tip.Denom))
+		buf.WriteByte('}')
+	}
+	buf.WriteByte('}')
+
+	if buf.Len() > MaxTxSize {
+		return nil, fmt.Errorf("%w: encoded transaction too large (%d > %d)",
+			ErrInvalidTransaction, buf.Len(), MaxTxSize)
+	}
+	return buf.Bytes(), nil
+}
+
+// TxHash returns the transaction's hash: the SHA-256 of its canonical encoding.
+//
+// Unlike Hash, TxHash commits to the full transaction (messages, fee, memo
+// and authorization), so it is suitable as an identifier for indexers and
+// explorers.
+//
+// Complexity: O(encoded size)
+func (tx *Transaction) TxHash() ([]byte, error) {
+	bz, err := tx.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return TxHash(bz), nil
+}
+
+// TxHash returns the hash of an encoded transaction.
+//
+// PRECONDITION: txBytes is a canonical encoding (output of Transaction.Encode,
+// or input accepted by TxDecoder.Decode).
+func TxHash(txBytes []byte) []byte {
+	sum := sha256.Sum256(txBytes)
+	return sum[:]
+}
+
+// MessageDecoder reconstructs a message from its canonical data, i.e. the
+// inverse of the message's SignDocData.
+//
+// INVARIANT: The returned message's SignDocData must canonicalize to data,
+// otherwise TxDecoder.Decode rejects the transaction.
+type MessageDecoder func(data json.RawMessage) (Message, error)
+
+// TxDecoder decodes canonical transaction encodings.
+//
+// Message is an interface, so the decoder needs a MessageDecoder for every
+// message type it may encounter; see RegisterMessage.
+//
+// Thread-safe: All methods are safe for concurrent use.
+type TxDecoder struct {
+	mu       sync.RWMutex
+	decoders map[string]MessageDecoder
+}
+
+// NewTxDecoder creates a decoder with no registered message types.
+func NewTxDecoder() *TxDecoder {
+	return &TxDecoder{decoders: make(map[string]MessageDecoder)}
+}
+
+// RegisterMessage registers the decoder for msgType.
+//
+// Returns an error if msgType is empty, dec is nil, or msgType is already registered.
+func (d *TxDecoder) RegisterMessage(msgType string, dec MessageDecoder) error {
+	if msgType == "" {
+		return fmt.Errorf("message type cannot be empty")
+	}
+	if dec == nil {
+		return fmt.Errorf("message decoder cannot be nil")
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, exists := d.decoders[msgType]; exists {
+		return fmt.Errorf("message type %q already registered", msgType)
+	}
+	d.decoders[msgType] = dec
+	return nil
+}
+
+// txWire is the decoding target for the canonical encoding.
+type txWire struct {
+	Account       AccountName      `json:"account"`
+	Authorization *Authorization   `json:"authorization"`
+	CoSigners     []coSignerWire   `json:"co_signers"`
+	Fee           SignDocFee       `json:"fee"`
+	FeeGranter    AccountName      `json:"fee_granter"`
+	FeePayer      AccountName      `json:"fee_payer"`
+	FeePayerAuth  *Authorization   `json:"fee_payer_authorization"`
+	FeeSlippage   SignDocRatio     `json:"fee_slippage"`
+	Memo          string           `json:"memo"`
+	Messages      []SignDocMessage `json:"messages"`
+	Nonce         StringUint64     `json:"nonce"`
+	Tip           *SignDocCoin     `json:"tip"`
+}
+
+// coSignerWire is the decoding target for a co-signer.
+type coSignerWire struct {
+	Account       AccountName    `json:"account"`
+	Authorization *Authorization `json:"authorization"`
+	Nonce         StringUint64   `json:"nonce"`
+}
+
+// Decode decodes a canonical transaction encoding.
+//
+// POSTCONDITION: On success, tx.Encode() returns bytes identical to txBytes,
+// so TxHash(txBytes) is the transaction's hash.
+// POSTCONDITION: All errors wrap ErrInvalidTransaction.
+//
+// SECURITY: Any input that is not byte-for-byte canonical is rejected,
+// including reordered keys, whitespace, duplicate or unknown fields,
+// alternative number or base64 forms, and implicit defaults.
+//
+// Decode does not call ValidateBasic or verify signatures.
+//
+// Complexity: O(len(txBytes))
+func (d *TxDecoder) Decode(txBytes []byte) (*Transaction, error) {
+	if len(txBytes) == 0 {
+		return nil, fmt.Errorf("%w: empty transaction bytes", ErrInvalidTransaction)
+	}
+	if len(txBytes) > MaxTxSize {
+		return nil, fmt.Errorf("%w: transaction too large (%d > %d)",
+			ErrInvalidTransaction, len(txBytes), MaxTxSize)
+	}
+
+	var wire txWire
+	dec := json.NewDecoder(bytes.NewReader(txBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&wire); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+	if err := expectEOF(dec); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+
+	fee, err := parseSignDocFee(wire.Fee)
+	if err != nil {
+		return nil, fmt.Errorf("%w: fee: %v", ErrInvalidTransaction, err)
+	}
+	slippage, err := parseSignDocRatio(wire.FeeSlippage)
+	if err != nil {
+		return nil, fmt.Errorf("%w: fee_slippage: %v", ErrInvalidTransaction, err)
+	}
+
+	msgs := make([]Message, len(wire.Messages))
+	for i, m := range wire.Messages {
+		d.mu.RLock()
+		decodeMsg, ok := d.decoders[m.Type]
+		d.mu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("%w: message %d: unknown message type %q", ErrInvalidTransaction, i, m.Type)
+		}
+		msg, err := decodeMsg(m.Data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: message %d: %v", ErrInvalidTransaction, i, err)
+		}
+		if msg == nil || msg.Type() != m.Type {
+			return nil, fmt.Errorf("%w: message %d: decoder for %q returned a different message type",
+				ErrInvalidTransaction, i, m.Type)
+		}
+		msgs[i] = msg
+	}
+
+	tx := &Transaction{
+		Account:       wire.Account,
+		Messages:      msgs,
+		Authorization: wire.Authorization,
+		Nonce:         wire.Nonce.Uint64(),
+		Memo:          wire.Memo,
+		Fee:           fee,
+		FeeSlippage:   slippage,
+
+		FeePayer:              wire.FeePayer,
+		FeePayerAuthorization: wire.FeePayerAuth,
+		FeeGranter:            wire.FeeGranter,
+	}
+	if wire.CoSigners != nil {
+		tx.CoSigners = make([]CoSigner, len(wire.CoSigners))
+		for i, cs := range wire.CoSigners {
+			tx.CoSigners[i] = CoSigner{Account: cs.Account, Nonce: cs.Nonce.Uint64(), Authorization: cs.Authorization}
+		}
+	}
+	if wire.Tip != nil {
+		amount, err := strconv.ParseUint(wire.Tip.Amount, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: tip: invalid amount %q", ErrInvalidTransaction, wire.Tip.Amount)
+		}
+		tx.Tip = &Coin{Denom: wire.Tip.Denom, Amount: amount}
+	}
+
+	// The canonical encoding is unique, so the input is canonical exactly
+	// when re-encoding reproduces it.
+	reencoded, err := tx.Encode()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(reencoded, txBytes) {
+		return nil, fmt.Errorf("%w: transaction encoding is not canonical", ErrInvalidTransaction)
+	}
+	return tx, nil
+}
+
+// writeCanonicalJSON marshals v and writes it to buf in canonical form.
+func writeCanonicalJSON(buf *bytes.Buffer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	dec := newMessageDataDecoder(data)
+	if err := writeCanonicalValue(dec, buf, 0); err != nil {
+		return err
+	}
+	return expectEOF(dec)
+}
+
+// normalizeAuthorization returns a copy of auth with implicit defaults made
+// explicit, so that equivalent authorizations encode identically.
+func normalizeAuthorization(auth *Authorization) *Authorization {
+	if auth == nil {
+		return nil
+	}
+
+	normalized := &Authorization{
+		Signatures: make([]Signature, len(auth.Signatures)),
+		NotBefore:  auth.NotBefore.clone(),
+		NotAfter:   auth.NotAfter.clone(),
+	}
+	for i, sig := range auth.Signatures {
+		normalized.Signatures[i] = normalizeSignature(sig)
+	}
+	if len(auth.AccountAuthorizations) > 0 {
+		normalized.AccountAuthorizations = make(map[AccountName]*Authorization, len(auth.AccountAuthorizations))
+		for name, sub := range auth.AccountAuthorizations {
+			normalized.AccountAuthorizations[name] = normalizeAuthorization(sub)
+		}
+	}
+	if auth.Session != nil {
+		session := *auth.Session
+		if session.Grant.AllowedMessageTypes == nil {
+			session.Grant.AllowedMessageTypes = []string{}
+		}
+		session.GrantAuthorization = normalizeAuthorization(auth.Session.GrantAuthorization)
+		session.Signature = normalizeSignature(auth.Session.Signature)
+		normalized.Session = &session
+	}
+	return normalized
+}
+
+// normalizeSignature makes the default algorithm explicit.
+func normalizeSignature(sig Signature) Signature {
+	sig.Algorithm = sig.GetAlgorithm()
+	return sig
+}
+
+// parseSignDocFee is the inverse of convertFee.
+func parseSignDocFee(fee SignDocFee) (Fee, error) {
+	gasLimit, err := strconv.ParseUint(fee.GasLimit, 10, 64)
+	if err != nil {
+		return Fee{}, fmt.Errorf("invalid gas_limit %q", fee.GasLimit)
+	}
+
+	var coins Coins
+	if fee.Amount != nil {
+		coins = make(Coins, len(fee.Amount))
+	}
+	for i, c := range fee.Amount {
+		amount, err := strconv.ParseUint(c.Amount, 10, 64)
+		if err != nil {
+			return Fee{}, fmt.Errorf("coin %d: invalid amount %q", i, c.Amount)
+		}
+		coins[i] = Coin{Denom: c.Denom, Amount: amount}
+	}
+	return Fee{Amount: coins, GasLimit: gasLimit}, nil
+}
+
+// parseSignDocRatio is the inverse of convertRatio.
+func parseSignDocRatio(r SignDocRatio) (Ratio, error) {
+	num, err := strconv.ParseUint(r.Numerator, 10, 64)
+	if err != nil {
+		return Ratio{}, fmt.Errorf("invalid numerator %q", r.Numerator)
+	}
+	den, err := strconv.ParseUint(r.Denominator, 10, 64)
+	if err != nil {
+		return Ratio{}, fmt.Errorf("invalid denominator %q", r.Denominator)
+	}
+	return Ratio{Numerator: num, Denominator: den}, nil
+}
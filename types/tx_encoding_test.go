@@ -0,0 +1,319 @@
+package types
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const codecMsgType = "/punnet.bank.v1.MsgSend"
+
+// codecMessage is a test message whose SignDocData carries its full content
+type codecMessage struct {
+	From   AccountName
+	To     AccountName
+	Amount uint64
+}
+
+func (m *codecMessage) Type() string              { return codecMsgType }
+func (m *codecMessage) ValidateBasic() error      { return nil }
+func (m *codecMessage) GetSigners() []AccountName { return []AccountName{m.From} }
+
+func (m *codecMessage) SignDocData() (json.RawMessage, error) {
+	return json.Marshal(struct {
+		From   AccountName `json:"from"`
+		To     AccountName `json:"to"`
+		Amount uint64      `json:"amount"`
+	}{m.From, m.To, m.Amount})
+}
+
+func decodeCodecMessage(data json.RawMessage) (Message, error) {
+	var v struct {
+		From   AccountName `json:"from"`
+		To     AccountName `json:"to"`
+		Amount uint64      `json:"amount"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return &codecMessage{From: v.From, To: v.To, Amount: v.Amount}, nil
+}
+
+func newCodecDecoder(t *testing.T) *TxDecoder {
+	t.Helper()
+	d := NewTxDecoder()
+	require.NoError(t, d.RegisterMessage(codecMsgType, decodeCodecMessage))
+	return d
+}
+
+// newCodecTx returns a transaction from alice signed with a deterministic key
+func newCodecTx(t *testing.T) *Transaction {
+	t.Helper()
+	priv := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{1}, ed25519.SeedSize))
+
+	tx := NewTransaction("alice", 7, []Message{
+		&codecMessage{From: "alice", To: "bob", Amount: 1000},
+	}, nil)
+	tx.Memo = "rent <march>"
+	tx.Fee = Fee{Amount: Coins{{Denom: "stake", Amount: 500}}, GasLimit: 200000}
+	tx.FeeSlippage = Ratio{Numerator: 1, Denominator: 100}
+
+	signDoc, err := tx.ToSignDoc("punnet-1", 7)
+	require.NoError(t, err)
+	signBytes, err := signDoc.GetSignBytes()
+	require.NoError(t, err)
+
+	// Algorithm left empty: the encoding must make the default explicit
+	tx.Authorization = NewAuthorization(Signature{
+		PubKey:    priv.Public().(ed25519.PublicKey),
+		Signature: ed25519.Sign(priv, signBytes),
+	})
+	return tx
+}
+
+func TestTransactionEncode_Roundtrip(t *testing.T) {
+	tx := newCodecTx(t)
+
+	bz, err := tx.Encode()
+	require.NoError(t, err)
+	require.NoError(t, ValidateCanonicalKeyOrder(bz))
+
+	decoded, err := newCodecDecoder(t).Decode(bz)
+	require.NoError(t, err)
+
+	assert.Equal(t, tx.Account, decoded.Account)
+	assert.Equal(t, tx.Nonce, decoded.Nonce)
+	assert.Equal(t, tx.Memo, decoded.Memo)
+	assert.Equal(t, tx.Fee, decoded.Fee)
+	assert.Equal(t, tx.FeeSlippage, decoded.FeeSlippage)
+	assert.Equal(t, tx.Messages, decoded.Messages)
+	assert.Equal(t, AlgorithmEd25519, decoded.Authorization.Signatures[0].Algorithm)
+
+	reencoded, err := decoded.Encode()
+	require.NoError(t, err)
+	assert.Equal(t, bz, reencoded)
+
+	hash, err := tx.TxHash()
+	require.NoError(t, err)
+	decodedHash, err := decoded.TxHash()
+	require.NoError(t, err)
+	assert.Equal(t, TxHash(bz), hash)
+	assert.Equal(t, hash, decodedHash)
+
+	// The decoded transaction signs identically, so its signatures still verify
+	want, err := tx.ToSignDoc("punnet-1", 7)
+	require.NoError(t, err)
+	got, err := decoded.ToSignDoc("punnet-1", 7)
+	require.NoError(t, err)
+	wantBytes, err := want.GetSignBytes()
+	require.NoError(t, err)
+	gotBytes, err := got.GetSignBytes()
+	require.NoError(t, err)
+	assert.Equal(t, wantBytes, gotBytes)
+	assert.True(t, decoded.Authorization.Signatures[0].Verify(gotBytes))
+}
+
+// TestTransactionEncode_Golden pins the wire format. A change here breaks
+// every indexer and explorer that stored transaction hashes.
+func TestTransactionEncode_Golden(t *testing.T) {
+	tx := NewTransaction("alice", 7, []Message{
+		&codecMessage{From: "alice", To: "bob", Amount: 1000},
+	}, NewAuthorization(Signature{
+		PubKey:    bytes.Repeat([]byte{0x01}, 32),
+		Signature: bytes.Repeat([]byte{0x02}, 64),
+	}))
+	tx.Memo = "a<b"
+	tx.Fee = Fee{Amount: Coins{{Denom: "stake", Amount: 500}}, GasLimit: 200000}
+	tx.FeeSlippage = Ratio{Numerator: 1, Denominator: 100}
+
+	bz, err := tx.Encode()
+	require.NoError(t, err)
+
+	const want = `{"account":"alice",` +
+		`"authorization":{"signatures":[{"algorithm":"ed25519",` +
+		`"pub_key":"AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE=",` +
+		`"signature":"AgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAg=="}]},` +
+		`"fee":{"amount":[{"amount":"500","denom":"stake"}],"gas_limit":"200000"},` +
+		`"fee_slippage":{"denominator":"100","numerator":"1"},` +
+		`"memo":"a<b",` +
+		`"messages":[{"data":{"amount":1000,"from":"alice","to":"bob"},"type":"/punnet.bank.v1.MsgSend"}],` +
+		`"nonce":"7"}`
+	assert.Equal(t, want, string(bz))
+
+	sum := TxHash([]byte(want))
+	hash, err := tx.TxHash()
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(sum), hex.EncodeToString(hash))
+}
+
+func TestTxDecoder_RejectsMalleatedEncodings(t *testing.T) {
+	bz, err := newCodecTx(t).Encode()
+	require.NoError(t, err)
+	canonical := string(bz)
+	decoder := newCodecDecoder(t)
+
+	// Sanity: the canonical form decodes
+	_, err = decoder.Decode(bz)
+	require.NoError(t, err)
+
+	replace := func(old, new string) string {
+		require.Contains(t, canonical, old)
+		return strings.Replace(canonical, old, new, 1)
+	}
+
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{"leading whitespace", " " + canonical},
+		{"trailing newline", canonical + "\n"},
+		{"space after colon", replace(`"account":"alice"`, `"account": "alice"`)},
+		{"reordered top-level keys", `{"nonce":"7",` + strings.TrimPrefix(replace(`,"nonce":"7"`, ``), "{")},
+		{"reordered message data keys", replace(`{"amount":1000,"from":"alice","to":"bob"}`,
+			`{"from":"alice","amount":1000,"to":"bob"}`)},
+		{"duplicate key", replace(`{"account":"alice"`, `{"account":"mallory","account":"alice"`)},
+		{"unknown field", replace(`"memo":`, `"extra":1,"memo":`)},
+		{"case-folded key", replace(`"memo":`, `"Memo":`)},
+		{"nonce as number", replace(`"nonce":"7"`, `"nonce":7`)},
+		{"nonce with leading zero", replace(`"nonce":"7"`, `"nonce":"07"`)},
+		{"gas limit with plus sign", replace(`"gas_limit":"200000"`, `"gas_limit":"+200000"`)},
+		{"implicit algorithm", replace(`"algorithm":"ed25519",`, ``)},
+		{"null fee amount", replace(`"amount":[{"amount":"500","denom":"stake"}]`, `"amount":null`)},
+		{"empty account authorizations", replace(`"signatures":`, `"account_authorizations":{},"signatures":`)},
+		{"escaped character", replace(`"account":"alice"`, `"account":"\u0061lice"`)},
+		{"number with exponent", replace(`"amount":1000`, `"amount":1e3`)},
+		{"trailing value", canonical + "{}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NotEqual(t, canonical, tt.encoded)
+			_, err := decoder.Decode([]byte(tt.encoded))
+			assert.ErrorIs(t, err, ErrInvalidTransaction)
+		})
+	}
+}
+
+func TestTransactionTxHash_CommitsToContent(t *testing.T) {
+	base := newCodecTx(t)
+	baseHash, err := base.TxHash()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		mutate func(tx *Transaction)
+	}{
+		{"message content", func(tx *Transaction) { tx.Messages[0].(*codecMessage).Amount++ }},
+		{"memo", func(tx *Transaction) { tx.Memo += "!" }},
+		{"fee", func(tx *Transaction) { tx.Fee.Amount[0].Amount++ }},
+		{"fee slippage", func(tx *Transaction) { tx.FeeSlippage.Numerator++ }},
+		{"nonce", func(tx *Transaction) { tx.Nonce++ }},
+		{"signature", func(tx *Transaction) { tx.Authorization.Signatures[0].Signature[0] ^= 0xFF }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := newCodecTx(t)
+			tt.mutate(tx)
+			tx.InvalidateSignDocCache()
+
+			hash, err := tx.TxHash()
+			require.NoError(t, err)
+			assert.NotEqual(t, baseHash, hash)
+		})
+	}
+
+	// The legacy Hash ignores everything but account and message types
+	tx := newCodecTx(t)
+	tx.Memo = "different"
+	assert.Equal(t, base.Hash(), tx.Hash())
+}
+
+func TestTransactionEncode_SessionAuthorization(t *testing.T) {
+	tx := newCodecTx(t)
+	sessionPub, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	tx.Authorization = &Authorization{
+		Session: &SessionAuthorization{
+			Grant: SessionGrant{
+				ChainID:          "punnet-1",
+				Account:          "alice",
+				SessionAlgorithm: AlgorithmEd25519,
+				SessionPubKey:    sessionPub,
+				ExpirationHeight: 100,
+			},
+			GrantAuthorization: NewAuthorization(tx.Authorization.Signatures...),
+			Signature:          tx.Authorization.Signatures[0],
+		},
+	}
+
+	bz, err := tx.Encode()
+	require.NoError(t, err)
+	assert.Contains(t, string(bz), `"allowed_message_types":[]`)
+	assert.Contains(t, string(bz), `"signatures":[]`)
+
+	decoded, err := newCodecDecoder(t).Decode(bz)
+	require.NoError(t, err)
+	reencoded, err := decoded.Encode()
+	require.NoError(t, err)
+	assert.Equal(t, bz, reencoded)
+}
+
+func TestTxDecoder_Errors(t *testing.T) {
+	bz, err := newCodecTx(t).Encode()
+	require.NoError(t, err)
+
+	t.Run("unknown message type", func(t *testing.T) {
+		_, err := NewTxDecoder().Decode(bz)
+		assert.ErrorIs(t, err, ErrInvalidTransaction)
+		assert.Contains(t, err.Error(), "unknown message type")
+	})
+
+	t.Run("decoder returns wrong type", func(t *testing.T) {
+		d := NewTxDecoder()
+		require.NoError(t, d.RegisterMessage(codecMsgType, func(json.RawMessage) (Message, error) {
+			return &testMessage{MsgType: "/other", Signers: []AccountName{"alice"}}, nil
+		}))
+		_, err := d.Decode(bz)
+		assert.ErrorIs(t, err, ErrInvalidTransaction)
+	})
+
+	t.Run("lossy decoder", func(t *testing.T) {
+		d := NewTxDecoder()
+		require.NoError(t, d.RegisterMessage(codecMsgType, func(json.RawMessage) (Message, error) {
+			return &codecMessage{From: "alice", To: "bob"}, nil
+		}))
+		_, err := d.Decode(bz)
+		assert.ErrorIs(t, err, ErrInvalidTransaction)
+	})
+
+	t.Run("empty and oversized input", func(t *testing.T) {
+		d := newCodecDecoder(t)
+		_, err := d.Decode(nil)
+		assert.ErrorIs(t, err, ErrInvalidTransaction)
+		_, err = d.Decode(make([]byte, MaxTxSize+1))
+		assert.ErrorIs(t, err, ErrInvalidTransaction)
+	})
+
+	t.Run("register", func(t *testing.T) {
+		d := newCodecDecoder(t)
+		assert.Error(t, d.RegisterMessage(codecMsgType, decodeCodecMessage))
+		assert.Error(t, d.RegisterMessage("", decodeCodecMessage))
+		assert.Error(t, d.RegisterMessage("/x", nil))
+	})
+
+	t.Run("message without SignDocData", func(t *testing.T) {
+		tx := NewTransaction("alice", 1, []Message{
+			&testMessage{MsgType: codecMsgType, Signers: []AccountName{"alice"}},
+		}, NewAuthorization())
+		_, err := tx.Encode()
+		assert.ErrorIs(t, err, ErrInvalidTransaction)
+	})
+}
@@ -0,0 +1,141 @@
+package types
+
+import "fmt"
+
+// Default transaction limits.
+const (
+	// DefaultMaxTxBytes is the default maximum size of an encoded transaction.
+	DefaultMaxTxBytes = MaxTxSize
+
+	// DefaultMaxTxSignatures is the default maximum number of signatures in a
+	// transaction's authorization, counted across all delegation levels.
+	DefaultMaxTxSignatures = 64
+
+	// DefaultMaxAuthorizationDepth is the default maximum nesting depth of a
+	// transaction's authorization. The top-level authorization is depth 0.
+	DefaultMaxAuthorizationDepth = MaxRecursionDepth
+)
+
+// TxLimits bounds the resources a single transaction may consume before
+// signature verification begins.
+//
+// SECURITY: Signature verification and delegation traversal cost grows with
+// transaction size, signature count and nesting depth. Checking these limits
+// first means an oversized transaction is rejected in O(limits) work instead
+// of being fully verified.
+//
+// A zero field means the corresponding default; see WithDefaults.
+type TxLimits struct {
+	// MaxTxBytes is the maximum size of an encoded transaction in bytes.
+	MaxTxBytes int
+
+	// MaxSignatures is the maximum number of signatures in the authorization,
+	// including delegated, session grant and session key signatures.
+	MaxSignatures int
+
+	// MaxAuthorizationDepth is the maximum nesting depth of the authorization.
+	// A session's grant authorization is one level below the session.
+	MaxAuthorizationDepth int
+}
+
+// DefaultTxLimits returns the default transaction limits.
+func DefaultTxLimits() TxLimits {
+	return TxLimits{
+		MaxTxBytes:            DefaultMaxTxBytes,
+		MaxSignatures:         DefaultMaxTxSignatures,
+		MaxAuthorizationDepth: DefaultMaxAuthorizationDepth,
+	}
+}
+
+// WithDefaults returns l with every zero field replaced by its default.
+func (l TxLimits) WithDefaults() TxLimits {
+	defaults := DefaultTxLimits()
+	if l.MaxTxBytes == 0 {
+		l.MaxTxBytes = defaults.MaxTxBytes
+	}
+	if l.MaxSignatures == 0 {
+		l.MaxSignatures = defaults.MaxSignatures
+	}
+	if l.MaxAuthorizationDepth == 0 {
+		l.MaxAuthorizationDepth = defaults.MaxAuthorizationDepth
+	}
+	return l
+}
+
+// ValidateBasic checks that the limits are usable.
+//
+// INVARIANT: MaxAuthorizationDepth never exceeds MaxRecursionDepth, since
+// deeper authorizations fail verification regardless.
+func (l TxLimits) ValidateBasic() error {
+	if l.MaxTxBytes < 0 {
+		return fmt.Errorf("max tx bytes cannot be negative: %d", l.MaxTxBytes)
+	}
+	if l.MaxSignatures < 0 {
+		return fmt.Errorf("max signatures cannot be negative: %d", l.MaxSignatures)
+	}
+	if l.MaxAuthorizationDepth < 0 || l.MaxAuthorizationDepth > MaxRecursionDepth {
+		return fmt.Errorf("max authorization depth must be in [0, %d], got %d",
+			MaxRecursionDepth, l.MaxAuthorizationDepth)
+	}
+	return nil
+}
+
+// CheckTxSize returns ErrTxTooLarge if an encoded transaction of size bytes
+// exceeds l.MaxTxBytes.
+//
+// PRECONDITION: l has defaults applied (see WithDefaults).
+func (l TxLimits) CheckTxSize(size int) error {
+	if size > l.MaxTxBytes {
+		return fmt.Errorf("%w: %d bytes (max %d)", ErrTxTooLarge, size, l.MaxTxBytes)
+	}
+	return nil
+}
+
+// CheckAuthorization checks auth's signature count and nesting depth.
+//
+// Returns ErrTooManySignatures if auth holds more than l.MaxSignatures signatures.
+// Returns ErrMaxRecursionDepth if auth nests deeper than l.MaxAuthorizationDepth.
+// A nil auth passes (ValidateBasic rejects it).
+//
+// PRECONDITION: l has defaults applied (see WithDefaults).
+//
+// Complexity: O(number of authorizations), stopping at the first violation.
+// The traversal is iterative, so hostile nesting cannot exhaust the stack.
+func (l TxLimits) CheckAuthorization(auth *Authorization) error {
+	type node struct {
+		auth  *Authorization
+		depth int
+	}
+
+	signatures := 0
+	stack := []node{{auth: auth}}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if n.auth == nil {
+			continue
+		}
+		if n.depth > l.MaxAuthorizationDepth {
+			return fmt.Errorf("%w: authorization depth %d (max %d)",
+				ErrMaxRecursionDepth, n.depth, l.MaxAuthorizationDepth)
+		}
+
+		signatures += len(n.auth.Signatures)
+		if n.auth.Session != nil {
+			signatures++
+			stack = append(stack, node{auth: n.auth.Session.GrantAuthorization, depth: n.depth + 1})
+		}
+		if signatures > l.MaxSignatures {
+			return fmt.Errorf("%w: more than %d signatures", ErrTooManySignatures, l.MaxSignatures)
+		}
+
+		// Push in reverse name order so subtrees are visited in name order:
+		// which limit trips first decides the result code, which must not
+		// depend on map order
+		names := n.auth.delegatedAccounts()
+		for i := len(names) - 1; i >= 0; i-- {
+			stack = append(stack, node{auth: n.auth.AccountAuthorizations[names[i]], depth: n.depth + 1})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func limitsTestSig() Signature {
+	return Signature{Algorithm: AlgorithmEd25519, PubKey: make([]byte, 32), Signature: make([]byte, 64)}
+}
+
+// nestedAuthorization returns an authorization delegating depth levels deep,
+// with one signature at each level
+func nestedAuthorization(depth int) *Authorization {
+	auth := NewAuthorization(limitsTestSig())
+	for i := 0; i < depth; i++ {
+		parent := NewAuthorization(limitsTestSig())
+		parent.AccountAuthorizations["delegate"] = auth
+		auth = parent
+	}
+	return auth
+}
+
+func TestTxLimits_Defaults(t *testing.T) {
+	assert.Equal(t, DefaultTxLimits(), TxLimits{}.WithDefaults())
+
+	custom := TxLimits{MaxSignatures: 3}.WithDefaults()
+	assert.Equal(t, 3, custom.MaxSignatures)
+	assert.Equal(t, DefaultMaxTxBytes, custom.MaxTxBytes)
+	assert.Equal(t, DefaultMaxAuthorizationDepth, custom.MaxAuthorizationDepth)
+
+	require.NoError(t, DefaultTxLimits().ValidateBasic())
+	assert.Error(t, TxLimits{MaxTxBytes: -1}.ValidateBasic())
+	assert.Error(t, TxLimits{MaxSignatures: -1}.ValidateBasic())
+	assert.Error(t, TxLimits{MaxAuthorizationDepth: MaxRecursionDepth + 1}.ValidateBasic())
+}
+
+func TestTxLimits_CheckTxSize(t *testing.T) {
+	limits := TxLimits{MaxTxBytes: 100}.WithDefaults()
+	assert.NoError(t, limits.CheckTxSize(100))
+	assert.ErrorIs(t, limits.CheckTxSize(101), ErrTxTooLarge)
+}
+
+func TestTxLimits_CheckAuthorization(t *testing.T) {
+	limits := TxLimits{MaxSignatures: 4, MaxAuthorizationDepth: 2}.WithDefaults()
+
+	tests := []struct {
+		name    string
+		auth    *Authorization
+		wantErr error
+	}{
+		{"nil", nil, nil},
+		{"at signature limit", NewAuthorization(limitsTestSig(), limitsTestSig(), limitsTestSig(), limitsTestSig()), nil},
+		{"over signature limit", NewAuthorization(limitsTestSig(), limitsTestSig(), limitsTestSig(), limitsTestSig(), limitsTestSig()), ErrTooManySignatures},
+		{"at depth limit", nestedAuthorization(2), nil},
+		{"over depth limit", nestedAuthorization(3), ErrMaxRecursionDepth},
+		{"signatures counted across delegations", func() *Authorization {
+			auth := NewAuthorization(limitsTestSig(), limitsTestSig())
+			auth.AccountAuthorizations["bob"] = NewAuthorization(limitsTestSig(), limitsTestSig())
+			auth.AccountAuthorizations["carol"] = NewAuthorization(limitsTestSig())
+			return auth
+		}(), ErrTooManySignatures},
+		{"session counts grant and session signatures", &Authorization{
+			Session: &SessionAuthorization{
+				GrantAuthorization: NewAuthorization(limitsTestSig(), limitsTestSig(), limitsTestSig(), limitsTestSig()),
+				Signature:          limitsTestSig(),
+			},
+		}, ErrTooManySignatures},
+		{"session grant is one level deeper", &Authorization{
+			Session: &SessionAuthorization{
+				GrantAuthorization: nestedAuthorization(2),
+				Signature:          limitsTestSig(),
+			},
+		}, ErrMaxRecursionDepth},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := limits.CheckAuthorization(tt.auth)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestTxLimits_CheckAuthorization_Deterministic checks that an authorization
+// breaking both limits in different subtrees always trips the same one: the
+// error becomes the transaction's result code
+func TestTxLimits_CheckAuthorization_Deterministic(t *testing.T) {
+	limits := TxLimits{MaxSignatures: 4, MaxAuthorizationDepth: 2}.WithDefaults()
+
+	auth := NewAuthorization()
+	auth.AccountAuthorizations["bob"] = nestedAuthorization(3)
+	auth.AccountAuthorizations["carol"] = NewAuthorization(limitsTestSig(), limitsTestSig(), limitsTestSig(), limitsTestSig(), limitsTestSig())
+
+	// Subtrees are checked in name order, so bob's depth trips first
+	for i := 0; i < 200; i++ {
+		err := limits.CheckAuthorization(auth)
+		require.ErrorIs(t, err, ErrMaxRecursionDepth, "run %d", i)
+		require.NotErrorIs(t, err, ErrTooManySignatures, "run %d", i)
+	}
+}
@@ -0,0 +1,140 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ValidityBound is a block height or a block time bounding when a signed
+// transaction may execute (see Authorization.NotBefore and NotAfter).
+//
+// INVARIANT: Exactly one of Height and Time is non-zero (checked by ValidateBasic).
+// RATIONALE: Both fields serialize as decimal strings, like the other
+// SignDoc numbers, so the SignDoc and wire forms need no optional fields.
+type ValidityBound struct {
+	// Height is a block height, or 0 for a time bound
+	Height StringUint64 `json:"height"`
+
+	// Time is a block time in Unix seconds, or 0 for a height bound
+	Time StringUint64 `json:"time"`
+}
+
+// HeightBound returns a bound at block height
+func HeightBound(height uint64) *ValidityBound {
+	return &ValidityBound{Height: StringUint64(height)}
+}
+
+// TimeBound returns a bound at block time t, truncated to whole seconds.
+// Times at or before the Unix epoch yield an invalid bound.
+func TimeBound(t time.Time) *ValidityBound {
+	return &ValidityBound{Time: StringUint64(unixSeconds(t))}
+}
+
+// ValidateBasic performs stateless validation
+func (b *ValidityBound) ValidateBasic() error {
+	if b == nil {
+		return fmt.Errorf("validity bound is nil")
+	}
+	if (b.Height == 0) == (b.Time == 0) {
+		return fmt.Errorf("validity bound must set exactly one of height and time")
+	}
+	return nil
+}
+
+// IsHeight reports whether the bound is a block height
+func (b *ValidityBound) IsHeight() bool {
+	return b.Height != 0
+}
+
+// String returns "height N" or "time T" (RFC 3339, UTC)
+func (b *ValidityBound) String() string {
+	if b.IsHeight() {
+		return fmt.Sprintf("height %d", b.Height)
+	}
+	return fmt.Sprintf("time %s", time.Unix(int64(b.Time), 0).UTC().Format(time.RFC3339))
+}
+
+// clone returns a copy of b, or nil
+func (b *ValidityBound) clone() *ValidityBound {
+	if b == nil {
+		return nil
+	}
+	c := *b
+	return &c
+}
+
+// before reports whether the block at (height, blockTime) precedes b
+func (b *ValidityBound) before(height uint64, blockTime time.Time) bool {
+	if b.IsHeight() {
+		return height < b.Height.Uint64()
+	}
+	return unixSeconds(blockTime) < b.Time.Uint64()
+}
+
+// after reports whether the block at (height, blockTime) follows b
+func (b *ValidityBound) after(height uint64, blockTime time.Time) bool {
+	if b.IsHeight() {
+		return height > b.Height.Uint64()
+	}
+	return unixSeconds(blockTime) > b.Time.Uint64()
+}
+
+// writeJSON writes the bound to the buffer in deterministic JSON format.
+func (b *ValidityBound) writeJSON(buf *bytes.Buffer) {
+	buf.WriteString(`{"height":"`)
+	buf.WriteString(strconv.FormatUint(b.Height.Uint64(), 10))
+	buf.WriteString(`","time":"`)
+	buf.WriteString(strconv.FormatUint(b.Time.Uint64(), 10))
+	buf.WriteString(`"}`)
+}
+
+// unixSeconds returns t in Unix seconds, clamped at 0
+func unixSeconds(t time.Time) uint64 {
+	return uint64(max(t.Unix(), 0))
+}
+
+// validateValidityWindow checks the bounds of an authorization's validity window
+func validateValidityWindow(notBefore, notAfter *ValidityBound) error {
+	if notBefore != nil {
+		if err := notBefore.ValidateBasic(); err != nil {
+			return fmt.Errorf("not_before: %w", err)
+		}
+	}
+	if notAfter != nil {
+		if err := notAfter.ValidateBasic(); err != nil {
+			return fmt.Errorf("not_after: %w", err)
+		}
+	}
+
+	// Bounds of different kinds cannot be compared without chain history
+	if notBefore == nil || notAfter == nil || notBefore.IsHeight() != notAfter.IsHeight() {
+		return nil
+	}
+	if notBefore.Height > notAfter.Height || notBefore.Time > notAfter.Time {
+		return fmt.Errorf("validity window is empty: not_before %s is after not_after %s", notBefore, notAfter)
+	}
+	return nil
+}
+
+// CheckValidity verifies that a block at height and blockTime falls within
+// the authorization's validity window. Both bounds are inclusive.
+//
+// Returns ErrTxNotYetValid before NotBefore and ErrTxExpired after NotAfter.
+//
+// SECURITY: The window is part of the SignDoc, so it cannot be widened
+// without invalidating the signatures. Callers check it before verifying
+// signatures, both at mempool admission and at execution.
+func (a *Authorization) CheckValidity(height uint64, blockTime time.Time) error {
+	if a == nil {
+		return nil
+	}
+	if a.NotBefore != nil && a.NotBefore.before(height, blockTime) {
+		return fmt.Errorf("%w: valid from %s, current height %d", ErrTxNotYetValid, a.NotBefore, height)
+	}
+	if a.NotAfter != nil && a.NotAfter.after(height, blockTime) {
+		return fmt.Errorf("%w: valid until %s, current height %d", ErrTxExpired, a.NotAfter, height)
+	}
+	return nil
+}
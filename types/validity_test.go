@@ -0,0 +1,192 @@
+package types
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidityBound_ValidateBasic(t *testing.T) {
+	tests := []struct {
+		name    string
+		bound   *ValidityBound
+		wantErr bool
+	}{
+		{name: "height", bound: HeightBound(10)},
+		{name: "time", bound: TimeBound(time.Unix(1700000000, 0))},
+		{name: "nil", bound: nil, wantErr: true},
+		{name: "empty", bound: &ValidityBound{}, wantErr: true},
+		{name: "both", bound: &ValidityBound{Height: 10, Time: 1700000000}, wantErr: true},
+		{name: "epoch time", bound: TimeBound(time.Unix(0, 0)), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.bound.ValidateBasic()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuthorization_CheckValidity(t *testing.T) {
+	at := func(sec int64) time.Time { return time.Unix(sec, 0) }
+	window := &Authorization{NotBefore: HeightBound(10), NotAfter: HeightBound(20)}
+	timeWindow := &Authorization{NotBefore: TimeBound(at(1000)), NotAfter: TimeBound(at(2000))}
+
+	tests := []struct {
+		name    string
+		auth    *Authorization
+		height  uint64
+		time    time.Time
+		wantErr error
+	}{
+		{name: "no window", auth: NewAuthorization(), height: 1, time: at(1)},
+		{name: "nil authorization", auth: nil, height: 1, time: at(1)},
+		{name: "before height", auth: window, height: 9, time: at(1), wantErr: ErrTxNotYetValid},
+		{name: "first height", auth: window, height: 10, time: at(1)},
+		{name: "last height", auth: window, height: 20, time: at(1)},
+		{name: "after height", auth: window, height: 21, time: at(1), wantErr: ErrTxExpired},
+		{name: "before time", auth: timeWindow, height: 50, time: at(999), wantErr: ErrTxNotYetValid},
+		{name: "first time", auth: timeWindow, height: 50, time: at(1000)},
+		{name: "within time second", auth: timeWindow, height: 50, time: time.Unix(2000, 999)},
+		{name: "after time", auth: timeWindow, height: 50, time: at(2001), wantErr: ErrTxExpired},
+		{
+			name:    "mixed bounds",
+			auth:    &Authorization{NotBefore: HeightBound(10), NotAfter: TimeBound(at(2000))},
+			height:  10,
+			time:    at(2001),
+			wantErr: ErrTxExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.auth.CheckValidity(tt.height, tt.time)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuthorization_ValidateBasic_ValidityWindow(t *testing.T) {
+	auth := NewAuthorization()
+	auth.NotBefore = HeightBound(10)
+	auth.NotAfter = HeightBound(10)
+	require.NoError(t, auth.ValidateBasic())
+
+	auth.NotAfter = HeightBound(9)
+	assert.ErrorIs(t, auth.ValidateBasic(), ErrInvalidAuthorization)
+
+	auth.NotAfter = &ValidityBound{}
+	assert.ErrorIs(t, auth.ValidateBasic(), ErrInvalidAuthorization)
+
+	// Height and time bounds are not compared
+	auth.NotAfter = TimeBound(time.Unix(5, 0))
+	require.NoError(t, auth.ValidateBasic())
+
+	// The window is only signed at the top level
+	nested := NewAuthorization()
+	nested.NotAfter = HeightBound(20)
+	auth = NewAuthorization()
+	auth.AccountAuthorizations["bob"] = nested
+	assert.ErrorIs(t, auth.ValidateBasic(), ErrInvalidAuthorization)
+}
+
+func TestSignDoc_ValidityWindow(t *testing.T) {
+	tx := newCodecTx(t)
+	plain, err := tx.ToSignDoc("punnet-1", 7)
+	require.NoError(t, err)
+	plainJSON, err := plain.ToJSON()
+	require.NoError(t, err)
+	assert.NotContains(t, string(plainJSON), "not_before")
+	assert.NotContains(t, string(plainJSON), "not_after")
+
+	tx.Authorization.NotBefore = HeightBound(10)
+	tx.Authorization.NotAfter = TimeBound(time.Unix(1700000000, 0))
+	signDoc, err := tx.ToSignDoc("punnet-1", 7)
+	require.NoError(t, err)
+	require.NoError(t, signDoc.ValidateBasic())
+
+	data, err := signDoc.ToJSON()
+	require.NoError(t, err)
+	assert.True(t, bytes.HasSuffix(data, []byte(`,"not_before":{"height":"10","time":"0"},"not_after":{"height":"0","time":"1700000000"}}`)), string(data))
+
+	parsed, err := ParseSignDoc(data)
+	require.NoError(t, err)
+	assert.True(t, parsed.Equals(signDoc))
+
+	// The SignDoc holds a copy of the window
+	tx.Authorization.NotBefore.Height = 11
+	assert.Equal(t, StringUint64(10), signDoc.NotBefore.Height)
+
+	signDoc.NotAfter = &ValidityBound{}
+	assert.ErrorIs(t, signDoc.ValidateBasic(), ErrSignDocMismatch)
+}
+
+func TestTransaction_ValidityWindowIsSigned(t *testing.T) {
+	priv := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{2}, ed25519.SeedSize))
+	pub := priv.Public().(ed25519.PublicKey)
+	account := NewAccount("alice", pub)
+	account.Nonce = 7
+
+	tx := NewTransaction("alice", 7, []Message{&codecMessage{From: "alice", To: "bob", Amount: 1}}, &Authorization{
+		NotBefore: HeightBound(10),
+		NotAfter:  HeightBound(20),
+	})
+	tx.FeeSlippage = Ratio{Numerator: 0, Denominator: 1}
+
+	signDoc, err := tx.ToSignDoc("punnet-1", 7)
+	require.NoError(t, err)
+	signBytes, err := signDoc.GetSignBytes()
+	require.NoError(t, err)
+	tx.Authorization.Signatures = []Signature{{Algorithm: AlgorithmEd25519, PubKey: pub, Signature: ed25519.Sign(priv, signBytes)}}
+
+	getter := newMockAccountGetter()
+	require.NoError(t, tx.VerifyAuthorization("punnet-1", account, getter))
+
+	// Widening the window invalidates the signature
+	tx.Authorization.NotAfter = HeightBound(30)
+	assert.ErrorIs(t, tx.VerifyAuthorization("punnet-1", account, getter), ErrInvalidSignature)
+
+	tx.Authorization.NotAfter = nil
+	assert.ErrorIs(t, tx.VerifyAuthorization("punnet-1", account, getter), ErrInvalidSignature)
+}
+
+func TestTransactionEncode_ValidityWindow(t *testing.T) {
+	tx := newCodecTx(t)
+	tx.Authorization.NotBefore = HeightBound(10)
+	tx.Authorization.NotAfter = HeightBound(20)
+
+	encoded, err := tx.Encode()
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"not_after":{"height":"20","time":"0"},"not_before":{"height":"10","time":"0"}`)
+
+	decoded, err := newCodecDecoder(t).Decode(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, tx.Authorization.NotBefore, decoded.Authorization.NotBefore)
+	assert.Equal(t, tx.Authorization.NotAfter, decoded.Authorization.NotAfter)
+}
+
+func TestMultiSignCoordinator_CompleteCarriesValidityWindow(t *testing.T) {
+	tx := newCodecTx(t)
+	tx.Authorization.NotAfter = HeightBound(20)
+	signDoc, err := tx.ToSignDoc("punnet-1", 7)
+	require.NoError(t, err)
+
+	coord, err := NewMultiSignCoordinator(signDoc)
+	require.NoError(t, err)
+	auth := coord.Complete()
+	assert.Nil(t, auth.NotBefore)
+	assert.Equal(t, HeightBound(20), auth.NotAfter)
+}
@@ -0,0 +1,150 @@
+package types
+
+import "fmt"
+
+// maxVerificationCacheEntries bounds each map of a verificationCache.
+// SECURITY: Authorization trees are already bounded by MaxRecursionDepth and
+// the transaction limits; this cap keeps memory bounded even for callers that
+// verify authorizations outside a transaction.
+const maxVerificationCacheEntries = 1024
+
+// verificationCache memoizes work within a single VerifyAuthorization call.
+//
+// A delegation graph with shared sub-accounts (e.g. several co-signers that
+// all delegate to the same security council) repeats the same account in
+// many branches of the authorization tree. Without memoization each branch
+// re-fetches the account and re-verifies its signatures.
+//
+// INVARIANT: message is constant for the cache's lifetime, so a signature's
+// verification result depends only on the signature itself.
+// INVARIANT: Only successful results are cached; errors are recomputed.
+//
+// CONCURRENCY: Not safe for concurrent use. Each VerifyAuthorization call
+// owns its own cache.
+type verificationCache struct {
+	message []byte
+	getter  AccountGetter
+
+	// sigs maps a signature to whether it verifies against message
+	sigs map[sigCacheKey]bool
+
+	// accounts maps a delegated account name to the account fetched from
+	// getter. Within one call each delegated account is fetched once, so
+	// every branch sees the same authority.
+	accounts map[AccountName]*Account
+
+	// weights maps (account, authorization) to its computed weight
+	weights map[weightCacheKey]weightResult
+}
+
+// sigCacheKey identifies a signature by everything Signature.Verify reads.
+type sigCacheKey struct {
+	algorithm         Algorithm
+	pubKey            string
+	signature         string
+	authenticatorData string
+	clientDataJSON    string
+}
+
+// weightCacheKey identifies one account's authorization within the tree.
+// The authorization is compared by identity; identical content under a
+// different pointer is recomputed, but its signature checks hit sigs.
+type weightCacheKey struct {
+	account AccountName
+	auth    *Authorization
+}
+
+// weightResult is the outcome of calculateWeight for one subtree.
+type weightResult struct {
+	// weight is the subtree's authorization weight
+	weight uint64
+
+	// height is the subtree's delegation depth (0 for no delegations)
+	height int
+
+	// accounts lists every account in the subtree, including its root.
+	// A cached result is reused only if none of them is on the current
+	// path, so memoization never hides an authorization cycle.
+	accounts []AccountName
+}
+
+func newVerificationCache(message []byte, getter AccountGetter) *verificationCache {
+	return &verificationCache{
+		message:  message,
+		getter:   getter,
+		sigs:     make(map[sigCacheKey]bool),
+		accounts: make(map[AccountName]*Account),
+		weights:  make(map[weightCacheKey]weightResult),
+	}
+}
+
+// verify reports whether sig verifies against the cached message.
+// Complexity: O(1) on a hit, one signature verification on a miss.
+func (c *verificationCache) verify(sig *Signature) bool {
+	key := sigCacheKey{
+		algorithm: sig.GetAlgorithm(),
+		pubKey:    string(sig.PubKey),
+		signature: string(sig.Signature),
+	}
+	if sig.WebAuthn != nil {
+		key.authenticatorData = string(sig.WebAuthn.AuthenticatorData)
+		key.clientDataJSON = string(sig.WebAuthn.ClientDataJSON)
+	}
+
+	if ok, hit := c.sigs[key]; hit {
+		return ok
+	}
+	ok := sig.Verify(c.message)
+	if len(c.sigs) < maxVerificationCacheEntries {
+		c.sigs[key] = ok
+	}
+	return ok
+}
+
+// account returns the delegated account name, fetching it at most once.
+// Once maxVerificationCacheEntries accounts are cached, a new one fails with
+// ErrInvalidAuthorization: fetching it again for a later branch could yield
+// a different authority.
+func (c *verificationCache) account(name AccountName) (*Account, error) {
+	if acc, hit := c.accounts[name]; hit {
+		return acc, nil
+	}
+	if len(c.accounts) >= maxVerificationCacheEntries {
+		return nil, fmt.Errorf("%w: more than %d delegated accounts", ErrInvalidAuthorization, maxVerificationCacheEntries)
+	}
+	acc, err := c.getter.GetAccount(name)
+	if err != nil {
+		return nil, err
+	}
+	if acc == nil {
+		return nil, ErrNotFound
+	}
+	c.accounts[name] = acc
+	return acc, nil
+}
+
+// lookupWeight returns the cached weight of key's subtree if it can be
+// reused at depth with the accounts in visited on the current path.
+//
+// INVARIANT: A reused result is exactly what calculateWeight would return:
+// the subtree cannot reach a visited account (no new cycle) and fits within
+// MaxRecursionDepth at the new depth.
+func (c *verificationCache) lookupWeight(key weightCacheKey, visited map[AccountName]bool, depth int) (weightResult, bool) {
+	result, hit := c.weights[key]
+	if !hit || depth+result.height > MaxRecursionDepth {
+		return weightResult{}, false
+	}
+	for _, acct := range result.accounts {
+		if visited[acct] {
+			return weightResult{}, false
+		}
+	}
+	return result, true
+}
+
+// storeWeight caches a successful subtree result.
+func (c *verificationCache) storeWeight(key weightCacheKey, result weightResult) {
+	if len(c.weights) < maxVerificationCacheEntries {
+		c.weights[key] = result
+	}
+}
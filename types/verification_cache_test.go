@@ -0,0 +1,233 @@
+package types
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingAccountGetter counts GetAccount calls per account
+type countingAccountGetter struct {
+	*mockAccountGetter
+	calls map[AccountName]int
+}
+
+func newCountingAccountGetter() *countingAccountGetter {
+	return &countingAccountGetter{mockAccountGetter: newMockAccountGetter(), calls: make(map[AccountName]int)}
+}
+
+func (g *countingAccountGetter) GetAccount(name AccountName) (*Account, error) {
+	g.calls[name]++
+	return g.mockAccountGetter.GetAccount(name)
+}
+
+// delegatingAccount returns an account whose authority delegates to each of
+// delegates with weight 1
+func delegatingAccount(name AccountName, threshold uint64, delegates ...AccountName) *Account {
+	authority := Authority{
+		Threshold:      threshold,
+		KeyWeights:     make(map[string]uint64),
+		AccountWeights: make(map[AccountName]uint64),
+	}
+	for _, d := range delegates {
+		authority.AccountWeights[d] = 1
+	}
+	return &Account{Name: name, Authority: authority}
+}
+
+// fanOutFixture is a root account delegating to n members that all delegate
+// to one shared 2-of-3 council account
+type fanOutFixture struct {
+	getter  *countingAccountGetter
+	root    *Account
+	message []byte
+	council *Authorization
+	members []AccountName
+}
+
+func newFanOutFixture(tb testing.TB, n int) *fanOutFixture {
+	tb.Helper()
+	f := &fanOutFixture{getter: newCountingAccountGetter(), message: []byte("sign bytes")}
+
+	council := delegatingAccount("council", 2)
+	f.council = NewAuthorization()
+	for i := 0; i < 3; i++ {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		require.NoError(tb, err)
+		council.Authority.KeyWeights[string(pub)] = 1
+		if i < 2 {
+			f.council.Signatures = append(f.council.Signatures, Signature{
+				Algorithm: AlgorithmEd25519,
+				PubKey:    pub,
+				Signature: ed25519.Sign(priv, f.message),
+			})
+		}
+	}
+	f.getter.setAccount(council)
+
+	for i := 0; i < n; i++ {
+		name := AccountName(fmt.Sprintf("member%d", i))
+		f.members = append(f.members, name)
+		f.getter.setAccount(delegatingAccount(name, 1, "council"))
+	}
+	f.root = delegatingAccount("root", uint64(n), f.members...)
+	return f
+}
+
+// authorization returns the root authorization. With shared, every member
+// carries the same council authorization; otherwise each carries a copy.
+func (f *fanOutFixture) authorization(shared bool) *Authorization {
+	auth := NewAuthorization()
+	for _, m := range f.members {
+		council := f.council
+		if !shared {
+			council = NewAuthorization(f.council.Signatures...)
+		}
+		member := NewAuthorization()
+		member.AccountAuthorizations["council"] = council
+		auth.AccountAuthorizations[m] = member
+	}
+	return auth
+}
+
+func TestVerifyAuthorization_SharedSubAccountFetchedOnce(t *testing.T) {
+	for _, shared := range []bool{true, false} {
+		t.Run(fmt.Sprintf("shared=%v", shared), func(t *testing.T) {
+			f := newFanOutFixture(t, 8)
+			require.NoError(t, f.authorization(shared).VerifyAuthorization(f.root, f.message, f.getter))
+			assert.Equal(t, 1, f.getter.calls["council"])
+			for _, m := range f.members {
+				assert.Equal(t, 1, f.getter.calls[m])
+			}
+		})
+	}
+}
+
+func TestVerifyAuthorization_SharedSubAccountInsufficient(t *testing.T) {
+	f := newFanOutFixture(t, 4)
+	f.council.Signatures = f.council.Signatures[:1] // 1 of 2 required
+	err := f.authorization(true).VerifyAuthorization(f.root, f.message, f.getter)
+	assert.ErrorIs(t, err, ErrInsufficientWeight)
+}
+
+// TestVerifyAuthorization_CacheDoesNotHideCycle checks that a subtree cached
+// on one path is not reused on a path where it would close a cycle:
+// root -> a -> x -> b is acyclic, but root -> b -> x -> b is not.
+func TestVerifyAuthorization_CacheDoesNotHideCycle(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	message := []byte("sign bytes")
+	sig := Signature{Algorithm: AlgorithmEd25519, PubKey: pub, Signature: ed25519.Sign(priv, message)}
+
+	getter := newMockAccountGetter()
+	getter.setAccount(delegatingAccount("a", 1, "x"))
+	b := delegatingAccount("b", 1, "x")
+	b.Authority.KeyWeights[string(pub)] = 1
+	getter.setAccount(b)
+	getter.setAccount(delegatingAccount("x", 1, "b"))
+	root := delegatingAccount("root", 2, "a", "b")
+
+	authB := NewAuthorization(sig)
+	authX := NewAuthorization()
+	authX.AccountAuthorizations["b"] = authB
+	authA := NewAuthorization()
+	authA.AccountAuthorizations["x"] = authX
+	authBViaX := NewAuthorization()
+	authBViaX.AccountAuthorizations["x"] = authX
+
+	auth := NewAuthorization()
+	auth.AccountAuthorizations["a"] = authA
+	auth.AccountAuthorizations["b"] = authBViaX
+
+	// Map iteration order varies, so repeat to cover both traversal orders
+	for i := 0; i < 20; i++ {
+		err := auth.VerifyAuthorization(root, message, getter)
+		require.ErrorIs(t, err, ErrAuthorizationCycle)
+	}
+}
+
+// TestVerifyAuthorization_CacheDoesNotHideDepth checks that a subtree cached
+// at a shallow depth is not reused where it would exceed MaxRecursionDepth.
+func TestVerifyAuthorization_CacheDoesNotHideDepth(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	message := []byte("sign bytes")
+
+	getter := newMockAccountGetter()
+	leaf := NewAccount("leaf", pub)
+	getter.setAccount(leaf)
+	authLeaf := NewAuthorization(Signature{Algorithm: AlgorithmEd25519, PubKey: pub, Signature: ed25519.Sign(priv, message)})
+
+	// chain0 -> chain1 -> ... -> chainN -> leaf puts leaf at depth N+2
+	chainAuth := NewAuthorization()
+	chainAuth.AccountAuthorizations["leaf"] = authLeaf
+	next := AccountName("leaf")
+	for i := MaxRecursionDepth - 1; i >= 0; i-- {
+		name := AccountName(fmt.Sprintf("chain%d", i))
+		getter.setAccount(delegatingAccount(name, 1, next))
+		parent := NewAuthorization()
+		parent.AccountAuthorizations[name] = chainAuth
+		chainAuth, next = parent, name
+	}
+
+	root := delegatingAccount("root", 2, "leaf", "chain0")
+	auth := NewAuthorization()
+	auth.AccountAuthorizations["leaf"] = authLeaf
+	auth.AccountAuthorizations["chain0"] = chainAuth.AccountAuthorizations["chain0"]
+
+	for i := 0; i < 20; i++ {
+		err := auth.VerifyAuthorization(root, message, getter)
+		require.ErrorIs(t, err, ErrMaxRecursionDepth)
+	}
+}
+
+func TestVerificationCache_Bounded(t *testing.T) {
+	cache := newVerificationCache([]byte("msg"), newMockAccountGetter())
+	for i := 0; i < maxVerificationCacheEntries+10; i++ {
+		sig := Signature{Algorithm: AlgorithmEd25519, PubKey: []byte{byte(i), byte(i >> 8)}, Signature: []byte{1}}
+		assert.False(t, cache.verify(&sig))
+	}
+	assert.Len(t, cache.sigs, maxVerificationCacheEntries)
+
+	// A full account cache fails instead of fetching accounts it cannot
+	// keep, which a later branch would have to fetch again
+	getter := newMockAccountGetter()
+	accounts := newVerificationCache([]byte("msg"), getter)
+	for i := 0; i <= maxVerificationCacheEntries; i++ {
+		name := AccountName(fmt.Sprintf("acct%d", i))
+		getter.setAccount(delegatingAccount(name, 1))
+		_, err := accounts.account(name)
+		if i < maxVerificationCacheEntries {
+			require.NoError(t, err)
+		} else {
+			require.ErrorIs(t, err, ErrInvalidAuthorization)
+		}
+	}
+	// Cached accounts are still served
+	_, err := accounts.account("acct0")
+	require.NoError(t, err)
+}
+
+// BenchmarkVerifyAuthorization_FanOut measures verification of n members
+// delegating to one shared council, with the council authorization either
+// shared by pointer or copied per member (as after decoding a transaction).
+func BenchmarkVerifyAuthorization_FanOut(b *testing.B) {
+	for _, n := range []int{4, 16, 64} {
+		for _, shared := range []bool{true, false} {
+			b.Run(fmt.Sprintf("members=%d/shared=%v", n, shared), func(b *testing.B) {
+				f := newFanOutFixture(b, n)
+				auth := f.authorization(shared)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if err := auth.VerifyAuthorization(f.root, f.message, f.getter); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -464,8 +464,7 @@ func TestTransaction_VerifyAuthorization_EmptyChainID(t *testing.T) {
 
 	getter := newMockAccountGetter()
 	err = tx.VerifyAuthorization("", account, getter)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "chainID")
+	assert.ErrorIs(t, err, ErrInvalidChainID)
 }
 
 func TestTransaction_VerifyAuthorization_NilAccount(t *testing.T) {
@@ -627,6 +626,9 @@ func TestIsValidAlgorithm(t *testing.T) {
 	// Empty string defaults to Ed25519 for backwards compatibility
 	assert.True(t, IsValidAlgorithm(""))
 
+	// WebAuthn (passkey) assertions are production-ready
+	assert.True(t, IsValidAlgorithm(AlgorithmWebAuthn))
+
 	// secp256k1 and secp256r1 are NOT production-ready yet
 	// They are excluded from validation until properly implemented and tested
 	assert.False(t, IsValidAlgorithm(AlgorithmSecp256k1), "secp256k1 should not be valid until implemented")
@@ -638,9 +640,10 @@ func TestIsValidAlgorithm(t *testing.T) {
 
 func TestValidAlgorithms(t *testing.T) {
 	algos := ValidAlgorithms()
-	// Only Ed25519 is production-ready
-	assert.Len(t, algos, 1)
+	// Ed25519 and WebAuthn are production-ready
+	assert.Len(t, algos, 2)
 	assert.Contains(t, algos, AlgorithmEd25519)
+	assert.Contains(t, algos, AlgorithmWebAuthn)
 
 	// secp256k1 and secp256r1 should NOT be in the valid list until implemented
 	assert.NotContains(t, algos, AlgorithmSecp256k1, "secp256k1 should not be listed as valid")
@@ -0,0 +1,219 @@
+package types
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// WebAuthnTypeGet is the clientDataJSON type for authentication assertions.
+	// Registration ("webauthn.create") responses are never valid signatures.
+	WebAuthnTypeGet = "webauthn.get"
+
+	// MinAuthenticatorDataSize is the fixed-size prefix of authenticatorData:
+	// rpIdHash (32) || flags (1) || signCount (4).
+	MinAuthenticatorDataSize = 37
+
+	// MaxAuthenticatorDataSize bounds authenticatorData including extensions.
+	// SECURITY: Prevents oversized assertions from inflating verification cost.
+	MaxAuthenticatorDataSize = 1024
+
+	// MaxClientDataJSONSize bounds the clientDataJSON supplied with an assertion.
+	// SECURITY: Prevents oversized assertions from inflating verification cost.
+	MaxClientDataJSONSize = 2048
+)
+
+// Authenticator data flag bits (WebAuthn Level 2, section 6.1).
+const (
+	webAuthnFlagUserPresent  byte = 0x01
+	webAuthnFlagUserVerified byte = 0x04
+)
+
+// WebAuthnAssertion carries the authenticator output that accompanies a
+// WebAuthn (passkey) signature.
+//
+// The authenticator does not sign the SignDoc hash directly. It signs
+//
+//	authenticatorData || SHA-256(clientDataJSON)
+//
+// where clientDataJSON embeds the challenge supplied by the client. For a
+// Punnet transaction the challenge MUST be the SignDoc sign bytes
+// (SHA-256 of the canonical SignDoc JSON), base64url-encoded without padding.
+// See WebAuthnChallenge.
+type WebAuthnAssertion struct {
+	// AuthenticatorData is the raw authenticatorData returned by the authenticator.
+	AuthenticatorData []byte `json:"authenticator_data"`
+
+	// ClientDataJSON is the raw clientDataJSON returned by the browser/platform.
+	// It must be kept byte-for-byte: the signature covers its hash.
+	ClientDataJSON []byte `json:"client_data_json"`
+}
+
+// ValidateBasic performs stateless size validation of the assertion.
+func (w *WebAuthnAssertion) ValidateBasic() error {
+	if w == nil {
+		return fmt.Errorf("%w: webauthn assertion is required", ErrInvalidSignature)
+	}
+	if len(w.AuthenticatorData) < MinAuthenticatorDataSize {
+		return fmt.Errorf("%w: authenticator data must be at least %d bytes, got %d",
+			ErrInvalidSignature, MinAuthenticatorDataSize, len(w.AuthenticatorData))
+	}
+	if len(w.AuthenticatorData) > MaxAuthenticatorDataSize {
+		return fmt.Errorf("%w: authenticator data too large: %d bytes (max %d)",
+			ErrInvalidSignature, len(w.AuthenticatorData), MaxAuthenticatorDataSize)
+	}
+	if len(w.ClientDataJSON) == 0 {
+		return fmt.Errorf("%w: client data JSON cannot be empty", ErrInvalidSignature)
+	}
+	if len(w.ClientDataJSON) > MaxClientDataJSONSize {
+		return fmt.Errorf("%w: client data JSON too large: %d bytes (max %d)",
+			ErrInvalidSignature, len(w.ClientDataJSON), MaxClientDataJSONSize)
+	}
+	return nil
+}
+
+// AuthenticatorData is the parsed fixed-size prefix of WebAuthn authenticatorData.
+// Attested credential data and extensions, if present, are not interpreted.
+type AuthenticatorData struct {
+	// RPIDHash is SHA-256 of the relying party ID the credential is scoped to.
+	RPIDHash [32]byte
+
+	// Flags holds the UP/UV/AT/ED flag bits.
+	Flags byte
+
+	// SignCount is the authenticator's signature counter (0 if unsupported).
+	SignCount uint32
+}
+
+// UserPresent reports whether the UP (user present) flag is set.
+func (a AuthenticatorData) UserPresent() bool {
+	return a.Flags&webAuthnFlagUserPresent != 0
+}
+
+// UserVerified reports whether the UV (user verified) flag is set.
+func (a AuthenticatorData) UserVerified() bool {
+	return a.Flags&webAuthnFlagUserVerified != 0
+}
+
+// ParseAuthenticatorData parses the fixed-size prefix of authenticatorData.
+func ParseAuthenticatorData(data []byte) (AuthenticatorData, error) {
+	var ad AuthenticatorData
+	if len(data) < MinAuthenticatorDataSize {
+		return ad, fmt.Errorf("%w: authenticator data must be at least %d bytes, got %d",
+			ErrInvalidSignature, MinAuthenticatorDataSize, len(data))
+	}
+	copy(ad.RPIDHash[:], data[:32])
+	ad.Flags = data[32]
+	ad.SignCount = binary.BigEndian.Uint32(data[33:37])
+	return ad, nil
+}
+
+// CollectedClientData is the subset of WebAuthn clientDataJSON used for verification.
+type CollectedClientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin,omitempty"`
+}
+
+// ParseClientData decodes clientDataJSON.
+//
+// Unknown members (e.g. tokenBinding, or the "other_keys_can_be_added_here"
+// member some browsers inject) are ignored, as required by the WebAuthn spec.
+func ParseClientData(clientDataJSON []byte) (CollectedClientData, error) {
+	var cd CollectedClientData
+	if err := json.Unmarshal(clientDataJSON, &cd); err != nil {
+		return cd, fmt.Errorf("%w: malformed client data JSON: %v", ErrInvalidSignature, err)
+	}
+	return cd, nil
+}
+
+// WebAuthnChallenge returns the challenge a client must pass to
+// navigator.credentials.get() when signing the given sign bytes.
+//
+// PRECONDITION: signBytes is the output of SignDoc.GetSignBytes().
+// POSTCONDITION: Result is base64url without padding, as embedded in clientDataJSON.
+func WebAuthnChallenge(signBytes []byte) string {
+	return base64.RawURLEncoding.EncodeToString(signBytes)
+}
+
+// VerifyChallenge checks that clientDataJSON is an authentication assertion
+// whose challenge equals message.
+//
+// SECURITY: Binding the challenge to the SignDoc hash is what makes the
+// assertion a signature over the transaction. Without this check an assertion
+// produced for any other challenge (e.g. a website login) could be replayed.
+func (w *WebAuthnAssertion) VerifyChallenge(message []byte) error {
+	cd, err := ParseClientData(w.ClientDataJSON)
+	if err != nil {
+		return err
+	}
+	if cd.Type != WebAuthnTypeGet {
+		return fmt.Errorf("%w: client data type must be %q, got %q", ErrInvalidSignature, WebAuthnTypeGet, cd.Type)
+	}
+
+	// The spec mandates unpadded base64url; reject any other encoding so the
+	// challenge has exactly one valid representation.
+	challenge, err := base64.RawURLEncoding.Strict().DecodeString(cd.Challenge)
+	if err != nil {
+		return fmt.Errorf("%w: challenge is not unpadded base64url: %v", ErrInvalidSignature, err)
+	}
+	if subtle.ConstantTimeCompare(challenge, message) != 1 {
+		return fmt.Errorf("%w: challenge does not match sign bytes", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// SignedDigest returns the digest the authenticator signed:
+// SHA-256(authenticatorData || SHA-256(clientDataJSON)).
+func (w *WebAuthnAssertion) SignedDigest() []byte {
+	clientDataHash := sha256.Sum256(w.ClientDataJSON)
+	h := sha256.New()
+	h.Write(w.AuthenticatorData)
+	h.Write(clientDataHash[:])
+	return h.Sum(nil)
+}
+
+// clone returns a deep copy of the assertion. A nil receiver yields nil.
+func (w *WebAuthnAssertion) clone() *WebAuthnAssertion {
+	if w == nil {
+		return nil
+	}
+	return &WebAuthnAssertion{
+		AuthenticatorData: append([]byte(nil), w.AuthenticatorData...),
+		ClientDataJSON:    append([]byte(nil), w.ClientDataJSON...),
+	}
+}
+
+// verifyWebAuthn verifies a WebAuthn assertion against a message.
+//
+// Verification steps:
+//  1. clientDataJSON.type is "webauthn.get"
+//  2. clientDataJSON.challenge decodes to exactly message (the SignDoc hash)
+//  3. authenticatorData has the UP (user present) flag set
+//  4. signature is a valid P-256 ECDSA signature over
+//     SHA-256(authenticatorData || SHA-256(clientDataJSON))
+//
+// NOTE: The relying party ID hash and origin are not checked. A chain has no
+// single relying party; the challenge binding in step 2 is what ties the
+// assertion to the transaction. Sign counters are not tracked because an
+// assertion cannot be replayed once the account sequence advances.
+func verifyWebAuthn(pubKey, message, signature []byte, assertion *WebAuthnAssertion) bool {
+	if assertion.ValidateBasic() != nil {
+		return false
+	}
+	if assertion.VerifyChallenge(message) != nil {
+		return false
+	}
+
+	authData, err := ParseAuthenticatorData(assertion.AuthenticatorData)
+	if err != nil || !authData.UserPresent() {
+		return false
+	}
+
+	return verifyP256Digest(pubKey, assertion.SignedDigest(), signature)
+}
@@ -0,0 +1,374 @@
+package types
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/blockberries/punnet-sdk/crypto"
+)
+
+// testPasskey simulates a WebAuthn authenticator holding a P-256 credential.
+type testPasskey struct {
+	priv   *ecdsa.PrivateKey
+	pubKey []byte
+}
+
+func newTestPasskey(t *testing.T) *testPasskey {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &testPasskey{
+		priv:   priv,
+		pubKey: elliptic.MarshalCompressed(elliptic.P256(), priv.X, priv.Y),
+	}
+}
+
+// testAuthenticatorData builds authenticatorData for rp "example.com".
+func testAuthenticatorData(flags byte, signCount uint32) []byte {
+	rpIDHash := sha256.Sum256([]byte("example.com"))
+	data := make([]byte, MinAuthenticatorDataSize)
+	copy(data, rpIDHash[:])
+	data[32] = flags
+	binary.BigEndian.PutUint32(data[33:], signCount)
+	return data
+}
+
+func testClientDataJSON(typ, challenge string) []byte {
+	return []byte(fmt.Sprintf(`{"type":%q,"challenge":%q,"origin":"https://example.com","crossOrigin":false}`, typ, challenge))
+}
+
+// sign produces an assertion the way an authenticator does: ECDSA over
+// SHA-256(authenticatorData || SHA-256(clientDataJSON)), encoded as R || S.
+func (p *testPasskey) sign(t *testing.T, authData, clientDataJSON []byte) Signature {
+	t.Helper()
+	assertion := &WebAuthnAssertion{AuthenticatorData: authData, ClientDataJSON: clientDataJSON}
+	r, s, err := ecdsa.Sign(rand.Reader, p.priv, assertion.SignedDigest())
+	require.NoError(t, err)
+
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	// Authenticators may return either S; verification accepts only low-S.
+	sig = crypto.NormalizeSignature(sig, AlgorithmSecp256r1)
+
+	return Signature{
+		Algorithm: AlgorithmWebAuthn,
+		PubKey:    p.pubKey,
+		Signature: sig,
+		WebAuthn:  assertion,
+	}
+}
+
+func (p *testPasskey) signMessage(t *testing.T, message []byte) Signature {
+	t.Helper()
+	return p.sign(t, testAuthenticatorData(0x05, 1), testClientDataJSON(WebAuthnTypeGet, WebAuthnChallenge(message)))
+}
+
+func TestWebAuthnSignature_Verify(t *testing.T) {
+	passkey := newTestPasskey(t)
+	message := sha256.Sum256([]byte("sign doc"))
+	challenge := WebAuthnChallenge(message[:])
+
+	tests := []struct {
+		name  string
+		sig   func() Signature
+		valid bool
+	}{
+		{
+			name:  "valid assertion",
+			sig:   func() Signature { return passkey.signMessage(t, message[:]) },
+			valid: true,
+		},
+		{
+			name: "valid with zero sign count and no UV",
+			sig: func() Signature {
+				return passkey.sign(t, testAuthenticatorData(0x01, 0), testClientDataJSON(WebAuthnTypeGet, challenge))
+			},
+			valid: true,
+		},
+		{
+			name: "valid with extension bytes in authenticator data",
+			sig: func() Signature {
+				authData := append(testAuthenticatorData(0x85, 7), 0xa0)
+				return passkey.sign(t, authData, testClientDataJSON(WebAuthnTypeGet, challenge))
+			},
+			valid: true,
+		},
+		{
+			name: "challenge for a different message",
+			sig: func() Signature {
+				other := sha256.Sum256([]byte("other sign doc"))
+				return passkey.sign(t, testAuthenticatorData(0x05, 1), testClientDataJSON(WebAuthnTypeGet, WebAuthnChallenge(other[:])))
+			},
+		},
+		{
+			name: "padded base64 challenge",
+			sig: func() Signature {
+				return passkey.sign(t, testAuthenticatorData(0x05, 1), testClientDataJSON(WebAuthnTypeGet, base64.URLEncoding.EncodeToString(message[:])))
+			},
+		},
+		{
+			name: "registration ceremony type",
+			sig: func() Signature {
+				return passkey.sign(t, testAuthenticatorData(0x05, 1), testClientDataJSON("webauthn.create", challenge))
+			},
+		},
+		{
+			name: "user presence flag not set",
+			sig: func() Signature {
+				return passkey.sign(t, testAuthenticatorData(0x04, 1), testClientDataJSON(WebAuthnTypeGet, challenge))
+			},
+		},
+		{
+			name: "malformed client data JSON",
+			sig: func() Signature {
+				return passkey.sign(t, testAuthenticatorData(0x05, 1), []byte(`{"type":`))
+			},
+		},
+		{
+			name: "tampered authenticator data",
+			sig: func() Signature {
+				sig := passkey.signMessage(t, message[:])
+				sig.WebAuthn.AuthenticatorData[36]++
+				return sig
+			},
+		},
+		{
+			name: "tampered client data",
+			sig: func() Signature {
+				sig := passkey.signMessage(t, message[:])
+				sig.WebAuthn.ClientDataJSON = []byte(string(sig.WebAuthn.ClientDataJSON) + " ")
+				return sig
+			},
+		},
+		{
+			name: "signature from another key",
+			sig: func() Signature {
+				sig := newTestPasskey(t).signMessage(t, message[:])
+				sig.PubKey = passkey.pubKey
+				return sig
+			},
+		},
+		{
+			name: "raw P-256 signature over sign bytes",
+			sig: func() Signature {
+				sig := passkey.signMessage(t, message[:])
+				r, s, err := ecdsa.Sign(rand.Reader, passkey.priv, message[:])
+				require.NoError(t, err)
+				r.FillBytes(sig.Signature[:32])
+				s.FillBytes(sig.Signature[32:])
+				return sig
+			},
+		},
+		{
+			name: "high-S signature",
+			sig: func() Signature {
+				sig := passkey.signMessage(t, message[:])
+				n := elliptic.P256().Params().N
+				s := new(big.Int).SetBytes(sig.Signature[32:])
+				new(big.Int).Sub(n, s).FillBytes(sig.Signature[32:])
+				return sig
+			},
+		},
+		{
+			name: "missing assertion",
+			sig: func() Signature {
+				sig := passkey.signMessage(t, message[:])
+				sig.WebAuthn = nil
+				return sig
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig := tt.sig()
+			assert.Equal(t, tt.valid, sig.Verify(message[:]))
+		})
+	}
+}
+
+func TestWebAuthnSignature_ValidateBasic(t *testing.T) {
+	passkey := newTestPasskey(t)
+	message := sha256.Sum256([]byte("sign doc"))
+
+	tests := []struct {
+		name    string
+		mutate  func(*Signature)
+		wantErr error
+	}{
+		{name: "valid", mutate: func(*Signature) {}},
+		{
+			name:    "missing assertion",
+			mutate:  func(s *Signature) { s.WebAuthn = nil },
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "short public key",
+			mutate:  func(s *Signature) { s.PubKey = s.PubKey[:32] },
+			wantErr: ErrInvalidPublicKey,
+		},
+		{
+			name:    "short signature",
+			mutate:  func(s *Signature) { s.Signature = s.Signature[:63] },
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "short authenticator data",
+			mutate:  func(s *Signature) { s.WebAuthn.AuthenticatorData = s.WebAuthn.AuthenticatorData[:36] },
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "oversized authenticator data",
+			mutate:  func(s *Signature) { s.WebAuthn.AuthenticatorData = make([]byte, MaxAuthenticatorDataSize+1) },
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "empty client data",
+			mutate:  func(s *Signature) { s.WebAuthn.ClientDataJSON = nil },
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "oversized client data",
+			mutate:  func(s *Signature) { s.WebAuthn.ClientDataJSON = make([]byte, MaxClientDataJSONSize+1) },
+			wantErr: ErrInvalidSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig := passkey.signMessage(t, message[:])
+			tt.mutate(&sig)
+			err := sig.ValidateBasic()
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSignature_WebAuthnDataOnEd25519Rejected(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	message := []byte("test transaction")
+
+	sig := Signature{
+		PubKey:    pub,
+		Signature: ed25519.Sign(priv, message),
+		WebAuthn: &WebAuthnAssertion{
+			AuthenticatorData: testAuthenticatorData(0x05, 1),
+			ClientDataJSON:    testClientDataJSON(WebAuthnTypeGet, WebAuthnChallenge(message)),
+		},
+	}
+
+	assert.ErrorIs(t, sig.ValidateBasic(), ErrInvalidSignature)
+	assert.False(t, sig.Verify(message))
+}
+
+func TestParseAuthenticatorData(t *testing.T) {
+	ad, err := ParseAuthenticatorData(testAuthenticatorData(0x05, 42))
+	require.NoError(t, err)
+	assert.Equal(t, sha256.Sum256([]byte("example.com")), ad.RPIDHash)
+	assert.True(t, ad.UserPresent())
+	assert.True(t, ad.UserVerified())
+	assert.Equal(t, uint32(42), ad.SignCount)
+
+	_, err = ParseAuthenticatorData(make([]byte, MinAuthenticatorDataSize-1))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestAuthorization_WebAuthnAuthority(t *testing.T) {
+	passkey := newTestPasskey(t)
+	message := sha256.Sum256([]byte("sign doc"))
+	getter := newMockAccountGetter()
+
+	t.Run("passkey satisfies webauthn authority", func(t *testing.T) {
+		account := &Account{Name: "alice", Authority: NewWebAuthnAuthority(1, passkey.pubKey, 1)}
+		require.NoError(t, account.ValidateBasic())
+
+		auth := NewAuthorization(passkey.signMessage(t, message[:]))
+		require.NoError(t, auth.ValidateBasic())
+		assert.NoError(t, auth.VerifyAuthorization(account, message[:], getter))
+	})
+
+	t.Run("passkey does not satisfy undeclared key type", func(t *testing.T) {
+		account := &Account{Name: "alice", Authority: NewAuthority(1, passkey.pubKey, 1)}
+
+		auth := NewAuthorization(passkey.signMessage(t, message[:]))
+		err := auth.VerifyAuthorization(account, message[:], getter)
+		assert.ErrorIs(t, err, ErrInsufficientWeight)
+	})
+
+	t.Run("mixed ed25519 and passkey authority", func(t *testing.T) {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+
+		authority := NewWebAuthnAuthority(2, passkey.pubKey, 1)
+		authority.KeyWeights[string(pub)] = 1
+		account := &Account{Name: "alice", Authority: authority}
+		require.NoError(t, account.ValidateBasic())
+
+		auth := NewAuthorization(
+			passkey.signMessage(t, message[:]),
+			Signature{PubKey: pub, Signature: ed25519.Sign(priv, message[:])},
+		)
+		assert.NoError(t, auth.VerifyAuthorization(account, message[:], getter))
+	})
+}
+
+func TestNewAuthorization_CopiesWebAuthn(t *testing.T) {
+	passkey := newTestPasskey(t)
+	message := sha256.Sum256([]byte("sign doc"))
+	sig := passkey.signMessage(t, message[:])
+
+	auth := NewAuthorization(sig)
+	sig.WebAuthn.ClientDataJSON[0] = 'X'
+
+	assert.True(t, auth.Signatures[0].Verify(message[:]))
+}
+
+func TestAuthority_ValidateBasic_WebAuthnKeys(t *testing.T) {
+	passkey := newTestPasskey(t)
+	pub, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		keyID   string
+		wantErr error
+	}{
+		{name: "webauthn key", keyID: KeyID(AlgorithmWebAuthn, passkey.pubKey)},
+		{name: "raw ed25519 key", keyID: KeyID(AlgorithmEd25519, pub)},
+		{name: "webauthn key with ed25519 size", keyID: KeyID(AlgorithmWebAuthn, pub), wantErr: ErrInvalidAuthority},
+		{name: "unsupported algorithm", keyID: KeyID(AlgorithmSecp256r1, passkey.pubKey), wantErr: ErrUnsupportedAlgorithm},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authority := Authority{
+				Threshold:  1,
+				KeyWeights: map[string]uint64{tt.keyID: 1},
+			}
+			err := authority.ValidateBasic()
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidAuthority)
+				assert.ErrorContains(t, err, tt.wantErr.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package upgrade
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/blockberries/punnet-sdk/effects"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+)
+
+// Plan schedules an upgrade at a block height
+type Plan struct {
+	// Name identifies the upgrade; each name is applied at most once
+	Name string `json:"name"`
+
+	// Height is the block height whose BeginBlock runs the migrations
+	Height uint64 `json:"height"`
+
+	// Info is optional free-form metadata (e.g. release notes URL)
+	Info string `json:"info,omitempty"`
+}
+
+// ValidateBasic performs basic validation
+func (p Plan) ValidateBasic() error {
+	if p.Name == "" {
+		return fmt.Errorf("%w: name cannot be empty", ErrInvalidPlan)
+	}
+	if p.Height == 0 {
+		return fmt.Errorf("%w: height must be positive", ErrInvalidPlan)
+	}
+	return nil
+}
+
+// Progress reports the state of a running upgrade
+type Progress struct {
+	// Plan is the plan name ("" for dry runs without a plan)
+	Plan string
+
+	// Step is the step starting or finished
+	Step Step
+
+	// Index is the 0-based position of Step; Total is the number of steps
+	Index int
+	Total int
+
+	// Done is false when Step starts and true when it finishes
+	Done bool
+
+	// Err is set if Step failed
+	Err error
+}
+
+// ProgressFunc receives progress reports. It is called synchronously and
+// must not block for long.
+type ProgressFunc func(Progress)
+
+// StepReport describes one completed step
+type StepReport struct {
+	Module      string
+	FromVersion uint64
+	ToVersion   uint64
+
+	// KeysWritten is the number of distinct keys first set or deleted by this step
+	KeysWritten int
+}
+
+// Report summarizes an upgrade run
+type Report struct {
+	Plan   string
+	Height uint64
+	DryRun bool
+
+	// Steps are the executed steps in order
+	Steps []StepReport
+
+	// Versions are the module versions after the upgrade
+	Versions VersionMap
+
+	// Sets and Deletes count the staged key writes, including version bookkeeping
+	Sets    int
+	Deletes int
+}
+
+// UpgradeHandler runs pending migrations for a scheduled plan.
+//
+// Usage:
+//
+//	h := upgrade.NewUpgradeHandler(migrator, upgrade.CurrentVersions(modules))
+//	h.SchedulePlan(upgrade.Plan{Name: "v2", Height: 1000})
+//	upgradeModule, _ := module.NewModuleBuilder("upgrade").
+//		WithBeginBlocker(h.BeginBlocker(stateStore)).Build()
+type UpgradeHandler struct {
+	mu       sync.Mutex
+	migrator *Migrator
+	target   VersionMap
+	plan     *Plan
+	progress ProgressFunc
+}
+
+// HandlerOption configures an UpgradeHandler
+type HandlerOption func(*UpgradeHandler)
+
+// WithProgress installs a progress callback
+func WithProgress(fn ProgressFunc) HandlerOption {
+	return func(h *UpgradeHandler) {
+		h.progress = fn
+	}
+}
+
+// NewUpgradeHandler creates a handler migrating state to target versions
+// (typically CurrentVersions of the application's modules)
+func NewUpgradeHandler(migrator *Migrator, target VersionMap, opts ...HandlerOption) *UpgradeHandler {
+	targetCopy := make(VersionMap, len(target))
+	for name, v := range target {
+		targetCopy[name] = v
+	}
+
+	h := &UpgradeHandler{
+		migrator: migrator,
+		target:   targetCopy,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// SchedulePlan schedules plan, replacing any previously scheduled plan
+func (h *UpgradeHandler) SchedulePlan(plan Plan) error {
+	if err := plan.ValidateBasic(); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.plan = &plan
+	return nil
+}
+
+// ClearPlan cancels the scheduled plan
+func (h *UpgradeHandler) ClearPlan() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.plan = nil
+}
+
+// ScheduledPlan returns the scheduled plan, if any
+func (h *UpgradeHandler) ScheduledPlan() (Plan, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.plan == nil {
+		return Plan{}, false
+	}
+	return *h.plan, true
+}
+
+// Apply runs the scheduled plan's migrations against s if height is the
+// plan's height and the plan has not been applied yet. Returns a nil report
+// if there is nothing to do.
+//
+// POSTCONDITION: On error, s is unchanged.
+// POSTCONDITION: On success, all migrations, the new module versions, and the
+// plan's completion marker are written to s together.
+func (h *UpgradeHandler) Apply(ctx context.Context, s store.BackingStore, height uint64) (*Report, error) {
+	plan, ok := h.ScheduledPlan()
+	if !ok || plan.Height != height {
+		return nil, nil
+	}
+
+	done, err := IsApplied(s, plan.Name)
+	if err != nil {
+		return nil, err
+	}
+	if done {
+		return nil, nil
+	}
+
+	return h.run(ctx, s, plan.Name, height, false)
+}
+
+// DryRun runs all pending migrations against s without modifying it and
+// reports what would change.
+func (h *UpgradeHandler) DryRun(ctx context.Context, s store.BackingStore) (*Report, error) {
+	plan, _ := h.ScheduledPlan()
+	return h.run(ctx, s, plan.Name, plan.Height, true)
+}
+
+// BeginBlocker returns a BeginBlocker that applies the scheduled plan at its
+// height. Migrations write to s directly (not via effects) so they commit
+// with the upgrade block.
+func (h *UpgradeHandler) BeginBlocker(s store.BackingStore) runtime.BeginBlocker {
+	return func(ctx *runtime.Context) ([]effects.Effect, error) {
+		if _, err := h.Apply(ctx.Context(), s, ctx.BlockHeight()); err != nil {
+			return nil, fmt.Errorf("upgrade failed: %w", err)
+		}
+		return nil, nil
+	}
+}
+
+// run plans and executes pending migrations in a buffered store
+func (h *UpgradeHandler) run(ctx context.Context, s store.BackingStore, planName string, height uint64, dryRun bool) (*Report, error) {
+	if s == nil {
+		return nil, store.ErrStoreNil
+	}
+
+	stored, err := LoadVersions(s)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := h.migrator.Plan(stored, h.target)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := store.NewBufferedStore(s)
+	defer buf.Discard()
+
+	report := &Report{
+		Plan:   planName,
+		Height: height,
+		DryRun: dryRun,
+		Steps:  make([]StepReport, 0, len(steps)),
+	}
+
+	for i, step := range steps {
+		h.report(Progress{Plan: planName, Step: step, Index: i, Total: len(steps)})
+
+		before := pendingCount(buf)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := step.migrate(ctx, buf); err != nil {
+			err = fmt.Errorf("%w: %s from version %d to %d: %w", ErrMigrationFailed, step.Module, step.FromVersion, step.ToVersion, err)
+			h.report(Progress{Plan: planName, Step: step, Index: i, Total: len(steps), Done: true, Err: err})
+			return nil, err
+		}
+
+		report.Steps = append(report.Steps, StepReport{
+			Module:      step.Module,
+			FromVersion: step.FromVersion,
+			ToVersion:   step.ToVersion,
+			KeysWritten: pendingCount(buf) - before,
+		})
+		h.report(Progress{Plan: planName, Step: step, Index: i, Total: len(steps), Done: true})
+	}
+
+	// Record new versions: migrated and newly added modules move to target
+	versions := make(VersionMap, len(stored)+len(h.target))
+	for name, v := range stored {
+		versions[name] = v
+	}
+	for name, v := range h.target {
+		versions[name] = v
+	}
+	if err := SaveVersions(buf, versions); err != nil {
+		return nil, err
+	}
+	if planName != "" {
+		if err := markApplied(buf, planName, height); err != nil {
+			return nil, err
+		}
+	}
+
+	report.Versions = versions
+	report.Sets, report.Deletes = buf.PendingWrites()
+
+	if dryRun {
+		return report, nil
+	}
+	if err := buf.Write(); err != nil {
+		return nil, fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	return report, nil
+}
+
+// report calls the progress callback, if any
+func (h *UpgradeHandler) report(p Progress) {
+	if h.progress != nil {
+		h.progress(p)
+	}
+}
+
+// IsApplied reports whether the named plan has been applied to s
+func IsApplied(s store.BackingStore, planName string) (bool, error) {
+	has, err := s.Has([]byte(doneKeyPrefix + planName))
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return false, fmt.Errorf("failed to read upgrade state: %w", err)
+	}
+	return has, nil
+}
+
+// AppliedHeight returns the height at which the named plan was applied to s
+func AppliedHeight(s store.BackingStore, planName string) (uint64, bool, error) {
+	value, err := s.Get([]byte(doneKeyPrefix + planName))
+	if errors.Is(err, store.ErrNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read upgrade state: %w", err)
+	}
+	if len(value) != 8 {
+		return 0, false, fmt.Errorf("corrupt completion entry for plan %q", planName)
+	}
+	return binary.BigEndian.Uint64(value), true, nil
+}
+
+// markApplied records that planName was applied at height
+func markApplied(s store.BackingStore, planName string, height uint64) error {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], height)
+	if err := s.Set([]byte(doneKeyPrefix+planName), buf[:]); err != nil {
+		return fmt.Errorf("failed to record upgrade %s: %w", planName, err)
+	}
+	return nil
+}
+
+// pendingCount returns the number of distinct keys buffered in buf
+func pendingCount(buf *store.BufferedStore) int {
+	sets, deletes := buf.PendingWrites()
+	return sets + deletes
+}
@@ -0,0 +1,225 @@
+// Package upgrade runs in-place store migrations when module consensus
+// versions change.
+//
+// Modules declare a consensus version (module.HasConsensusVersion) and
+// register one MigrationFunc per version step with a Migrator. At an upgrade
+// height an UpgradeHandler compares the versions recorded in state with the
+// modules' current versions and runs every pending step. All migrations of an
+// upgrade are staged in a store.BufferedStore and applied together, so they
+// land in a single commit or not at all.
+package upgrade
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/blockberries/punnet-sdk/capability"
+	"github.com/blockberries/punnet-sdk/module"
+	"github.com/blockberries/punnet-sdk/store"
+)
+
+var (
+	// ErrMissingMigration is returned when a version step has no registered migration
+	ErrMissingMigration = errors.New("missing migration")
+
+	// ErrDuplicateMigration is returned when a migration is registered twice
+	ErrDuplicateMigration = errors.New("migration already registered")
+
+	// ErrVersionDowngrade is returned when state is ahead of a module's version
+	ErrVersionDowngrade = errors.New("module version downgrade")
+
+	// ErrMigrationFailed is returned when a migration returns an error
+	ErrMigrationFailed = errors.New("migration failed")
+
+	// ErrInvalidPlan is returned for malformed upgrade plans
+	ErrInvalidPlan = errors.New("invalid upgrade plan")
+)
+
+// Store key prefixes (outside the "module/<name>/" capability namespaces)
+const (
+	versionKeyPrefix = "upgrade/version/"
+	doneKeyPrefix    = "upgrade/done/"
+)
+
+// VersionMap maps module names to consensus versions
+type VersionMap map[string]uint64
+
+// CurrentVersions returns the consensus versions of modules
+func CurrentVersions(modules []module.Module) VersionMap {
+	vm := make(VersionMap, len(modules))
+	for _, m := range modules {
+		vm[m.Name()] = module.ConsensusVersionOf(m)
+	}
+	return vm
+}
+
+// MigrationFunc migrates state from one consensus version to the next.
+//
+// s is the full state store, buffered: writes become visible to later
+// migrations of the same upgrade but reach the underlying store only if every
+// migration succeeds. Use ModuleStore for a module's capability namespace.
+//
+// Migrations must be deterministic.
+type MigrationFunc func(ctx context.Context, s store.BackingStore) error
+
+// ModuleStore returns the view of s that capabilities granted to moduleName
+// use (keys prefixed with "module/<moduleName>/").
+func ModuleStore(s store.BackingStore, moduleName string) store.BackingStore {
+	return capability.ModuleStore(s, moduleName)
+}
+
+// migrationKey identifies a single version step
+type migrationKey struct {
+	module      string
+	fromVersion uint64
+}
+
+// Migrator holds registered migrations
+type Migrator struct {
+	mu         sync.RWMutex
+	migrations map[migrationKey]MigrationFunc
+}
+
+// NewMigrator creates an empty migrator
+func NewMigrator() *Migrator {
+	return &Migrator{
+		migrations: make(map[migrationKey]MigrationFunc),
+	}
+}
+
+// Register registers the migration of moduleName from fromVersion to fromVersion+1
+func (m *Migrator) Register(moduleName string, fromVersion uint64, fn MigrationFunc) error {
+	if m == nil {
+		return fmt.Errorf("migrator is nil")
+	}
+	if moduleName == "" {
+		return module.ErrModuleNameEmpty
+	}
+	if fromVersion == 0 {
+		return fmt.Errorf("from version must be positive")
+	}
+	if fn == nil {
+		return fmt.Errorf("migration function cannot be nil")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := migrationKey{module: moduleName, fromVersion: fromVersion}
+	if _, exists := m.migrations[key]; exists {
+		return fmt.Errorf("%w: %s from version %d", ErrDuplicateMigration, moduleName, fromVersion)
+	}
+	m.migrations[key] = fn
+	return nil
+}
+
+// Step is one pending version step
+type Step struct {
+	Module      string
+	FromVersion uint64
+	ToVersion   uint64
+
+	migrate MigrationFunc
+}
+
+// Plan returns the steps needed to bring stored versions up to target, in
+// deterministic order (by module name, then version).
+//
+// Modules absent from stored are new: they start at their target version and
+// need no migrations.
+//
+// Returns ErrVersionDowngrade or ErrMissingMigration (wrapped) if target
+// cannot be reached; nothing is run in that case.
+func (m *Migrator) Plan(stored, target VersionMap) ([]Step, error) {
+	if m == nil {
+		return nil, fmt.Errorf("migrator is nil")
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(target))
+	for name := range target {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	steps := make([]Step, 0)
+	for _, name := range names {
+		from, exists := stored[name]
+		to := target[name]
+		if !exists {
+			continue
+		}
+		if from > to {
+			return nil, fmt.Errorf("%w: %s stored at version %d, module is version %d", ErrVersionDowngrade, name, from, to)
+		}
+		for v := from; v < to; v++ {
+			fn, ok := m.migrations[migrationKey{module: name, fromVersion: v}]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s from version %d to %d", ErrMissingMigration, name, v, v+1)
+			}
+			steps = append(steps, Step{Module: name, FromVersion: v, ToVersion: v + 1, migrate: fn})
+		}
+	}
+	return steps, nil
+}
+
+// LoadVersions reads the module versions recorded in s
+func LoadVersions(s store.BackingStore) (VersionMap, error) {
+	iter, err := s.Iterator([]byte(versionKeyPrefix), prefixEnd([]byte(versionKeyPrefix)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate versions: %w", err)
+	}
+	defer iter.Close()
+
+	vm := make(VersionMap)
+	for ; iter.Valid(); iter.Next() {
+		value := iter.Value()
+		if len(value) != 8 {
+			return nil, fmt.Errorf("corrupt version entry for key %q", iter.Key())
+		}
+		name := string(iter.Key()[len(versionKeyPrefix):])
+		vm[name] = binary.BigEndian.Uint64(value)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, fmt.Errorf("failed to iterate versions: %w", err)
+	}
+	return vm, nil
+}
+
+// SaveVersions records module versions in s.
+// Call it at genesis so later upgrades know the starting versions.
+func SaveVersions(s store.BackingStore, vm VersionMap) error {
+	names := make([]string, 0, len(vm))
+	for name := range vm {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], vm[name])
+		if err := s.Set([]byte(versionKeyPrefix+name), buf[:]); err != nil {
+			return fmt.Errorf("failed to save version of %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// prefixEnd returns the exclusive end key for iterating prefix
+func prefixEnd(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blockberries/punnet-sdk/module"
+	"github.com/blockberries/punnet-sdk/runtime"
+	"github.com/blockberries/punnet-sdk/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// renameMigration moves key from to key to in moduleName's namespace
+func renameMigration(moduleName, from, to string) MigrationFunc {
+	return func(_ context.Context, s store.BackingStore) error {
+		ms := ModuleStore(s, moduleName)
+		value, err := ms.Get([]byte(from))
+		if err != nil {
+			return err
+		}
+		if err := ms.Delete([]byte(from)); err != nil {
+			return err
+		}
+		return ms.Set([]byte(to), value)
+	}
+}
+
+func newTestState(t *testing.T) store.BackingStore {
+	t.Helper()
+	s := store.NewMemoryStore()
+	require.NoError(t, SaveVersions(s, VersionMap{"bank": 1, "auth": 1}))
+	require.NoError(t, ModuleStore(s, "bank").Set([]byte("supply"), []byte("100")))
+	return s
+}
+
+func newTestMigrator(t *testing.T) *Migrator {
+	t.Helper()
+	m := NewMigrator()
+	require.NoError(t, m.Register("bank", 1, renameMigration("bank", "supply", "supply_v2")))
+	require.NoError(t, m.Register("bank", 2, renameMigration("bank", "supply_v2", "supply_v3")))
+	return m
+}
+
+func TestUpgradeHandler_AppliesAtPlanHeight(t *testing.T) {
+	s := newTestState(t)
+
+	var progress []Progress
+	h := NewUpgradeHandler(newTestMigrator(t), VersionMap{"bank": 3, "auth": 1, "staking": 1},
+		WithProgress(func(p Progress) { progress = append(progress, p) }))
+	require.NoError(t, h.SchedulePlan(Plan{Name: "v3", Height: 10}))
+
+	report, err := h.Apply(context.Background(), s, 9)
+	require.NoError(t, err)
+	assert.Nil(t, report, "nothing runs before the plan height")
+
+	report, err = h.Apply(context.Background(), s, 10)
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Len(t, report.Steps, 2)
+	assert.Equal(t, StepReport{Module: "bank", FromVersion: 1, ToVersion: 2, KeysWritten: 2}, report.Steps[0])
+	assert.Equal(t, VersionMap{"bank": 3, "auth": 1, "staking": 1}, report.Versions)
+	assert.Len(t, progress, 4)
+	assert.True(t, progress[3].Done)
+
+	got, err := ModuleStore(s, "bank").Get([]byte("supply_v3"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("100"), got)
+
+	versions, err := LoadVersions(s)
+	require.NoError(t, err)
+	assert.Equal(t, report.Versions, versions)
+
+	applied, err := IsApplied(s, "v3")
+	require.NoError(t, err)
+	assert.True(t, applied)
+
+	// A plan is applied only once.
+	report, err = h.Apply(context.Background(), s, 10)
+	require.NoError(t, err)
+	assert.Nil(t, report)
+}
+
+func TestUpgradeHandler_DryRunLeavesStateUntouched(t *testing.T) {
+	s := newTestState(t)
+	h := NewUpgradeHandler(newTestMigrator(t), VersionMap{"bank": 3, "auth": 1})
+
+	report, err := h.DryRun(context.Background(), s)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Len(t, report.Steps, 2)
+	assert.Positive(t, report.Sets)
+
+	has, err := ModuleStore(s, "bank").Has([]byte("supply"))
+	require.NoError(t, err)
+	assert.True(t, has)
+	versions, err := LoadVersions(s)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), versions["bank"])
+}
+
+func TestUpgradeHandler_FailedMigrationIsAtomic(t *testing.T) {
+	s := newTestState(t)
+	boom := errors.New("boom")
+
+	m := NewMigrator()
+	require.NoError(t, m.Register("bank", 1, renameMigration("bank", "supply", "supply_v2")))
+	require.NoError(t, m.Register("bank", 2, func(context.Context, store.BackingStore) error { return boom }))
+
+	var failed Progress
+	h := NewUpgradeHandler(m, VersionMap{"bank": 3}, WithProgress(func(p Progress) {
+		if p.Err != nil {
+			failed = p
+		}
+	}))
+	require.NoError(t, h.SchedulePlan(Plan{Name: "v3", Height: 5}))
+
+	_, err := h.Apply(context.Background(), s, 5)
+	require.ErrorIs(t, err, ErrMigrationFailed)
+	require.ErrorIs(t, err, boom)
+	assert.Equal(t, uint64(2), failed.Step.FromVersion)
+
+	// The first step's writes were not applied.
+	has, err := ModuleStore(s, "bank").Has([]byte("supply"))
+	require.NoError(t, err)
+	assert.True(t, has)
+	applied, err := IsApplied(s, "v3")
+	require.NoError(t, err)
+	assert.False(t, applied)
+}
+
+func TestMigrator_Plan(t *testing.T) {
+	m := newTestMigrator(t)
+
+	_, err := m.Plan(VersionMap{"bank": 1}, VersionMap{"bank": 4})
+	assert.ErrorIs(t, err, ErrMissingMigration)
+
+	_, err = m.Plan(VersionMap{"bank": 3}, VersionMap{"bank": 2})
+	assert.ErrorIs(t, err, ErrVersionDowngrade)
+
+	steps, err := m.Plan(VersionMap{"bank": 2}, VersionMap{"bank": 3, "new": 5})
+	require.NoError(t, err)
+	require.Len(t, steps, 1)
+	assert.Equal(t, uint64(2), steps[0].FromVersion)
+
+	assert.ErrorIs(t, m.Register("bank", 1, renameMigration("bank", "a", "b")), ErrDuplicateMigration)
+}
+
+func TestUpgradeHandler_BeginBlocker(t *testing.T) {
+	s := newTestState(t)
+	h := NewUpgradeHandler(newTestMigrator(t), VersionMap{"bank": 3})
+	require.NoError(t, h.SchedulePlan(Plan{Name: "v3", Height: 7}))
+
+	mod, err := module.NewModuleBuilder("upgrade").WithBeginBlocker(h.BeginBlocker(s)).Build()
+	require.NoError(t, err)
+
+	header := runtime.NewBlockHeader(7, time.Unix(1700000000, 0), "test-chain", nil)
+	rctx, err := runtime.NewContext(context.Background(), header, "system")
+	require.NoError(t, err)
+	_, err = mod.BeginBlock()(rctx)
+	require.NoError(t, err)
+
+	applied, err := IsApplied(s, "v3")
+	require.NoError(t, err)
+	assert.True(t, applied)
+}
+
+func TestCurrentVersions(t *testing.T) {
+	v2, err := module.NewModuleBuilder("bank").WithConsensusVersion(2).Build()
+	require.NoError(t, err)
+	v1, err := module.NewModuleBuilder("auth").Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, VersionMap{"bank": 2, "auth": 1}, CurrentVersions([]module.Module{v2, v1}))
+}